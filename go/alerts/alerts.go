@@ -0,0 +1,239 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Engine evaluates rules against parsed events and sends the alerts they raise to the
+// rules' notifiers
+type Engine struct {
+	config Config
+	now    func() time.Time
+
+	mu   sync.Mutex
+	last map[cooldownKey]time.Time // when each rule last alerted for a mint
+}
+
+// cooldownKey identifies the alerts a rule's cooldown applies to
+type cooldownKey struct {
+	rule string
+	mint solana.PublicKey
+}
+
+// New creates an engine, checking that rule names are unique and their notifiers exist
+func New(config Config) (*Engine, error) {
+	names := make(map[string]bool, len(config.Rules))
+	for _, rule := range config.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule name is required")
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule %q", rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.Side != "" && rule.Side != SideBuy && rule.Side != SideSell {
+			return nil, fmt.Errorf("rule %q has unknown side %q", rule.Name, rule.Side)
+		}
+		for _, name := range rule.Notifiers {
+			if config.Notifiers[name] == nil {
+				return nil, fmt.Errorf("rule %q references unknown notifier %q", rule.Name, name)
+			}
+		}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	config.Logger = logging.OrNop(config.Logger)
+
+	return &Engine{config: config, now: time.Now, last: make(map[cooldownKey]time.Time)}, nil
+}
+
+// Evaluate returns the alerts the events raise, one per matching rule and event unless
+// the rule is cooling down for the event's mint
+func (e *Engine) Evaluate(events []*sink.Event) []*Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	var alerts []*Alert
+	for _, event := range events {
+		for i := range e.config.Rules {
+			rule := &e.config.Rules[i]
+			if !rule.Matches(event) {
+				continue
+			}
+			if rule.Cooldown > 0 {
+				key := cooldownKey{rule.Name, event.Mint()}
+				if last, ok := e.last[key]; ok && now.Sub(last) < rule.Cooldown {
+					continue
+				}
+				e.last[key] = now
+			}
+			alerts = append(alerts, &Alert{Rule: rule.Name, Time: now, Event: event})
+		}
+	}
+	return alerts
+}
+
+// Notify sends each alert to its rule's notifiers in parallel, returning every failure
+func (e *Engine) Notify(ctx context.Context, alerts []*Alert) error {
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, alert := range alerts {
+		for _, name := range e.notifiers(alert.Rule) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(ctx, e.config.Timeout)
+				defer cancel()
+				if err := e.config.Notifiers[name].Notify(ctx, alert); err != nil {
+					e.config.Logger.ErrorContext(ctx, "alert notification failed", "rule", alert.Rule, "notifier", name, "signature", alert.Event.Signature, logging.ERROR_KEY, err)
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("failed to notify %s of %s: %w", name, alert.Rule, err))
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Process evaluates the rules against the events of a parsed transaction and sends the
+// alerts raised
+func (e *Engine) Process(ctx context.Context, tx *tx_parser.ParsedTransaction) error {
+	return e.Notify(ctx, e.Evaluate(sink.Events(tx)))
+}
+
+// notifiers returns the names of the notifiers of a rule
+func (e *Engine) notifiers(rule string) []string {
+	for _, r := range e.config.Rules {
+		if r.Name == rule && len(r.Notifiers) > 0 {
+			return r.Notifiers
+		}
+	}
+	names := make([]string, 0, len(e.config.Notifiers))
+	for name := range e.config.Notifiers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Matches reports whether the event satisfies the rule, ignoring its cooldown
+func (r *Rule) Matches(event *sink.Event) bool {
+	if len(r.Kinds) > 0 && !slices.Contains(r.Kinds, event.Kind) {
+		return false
+	}
+	if len(r.Mints) > 0 && !containsAny(r.Mints, mints(event)...) {
+		return false
+	}
+	if len(r.Wallets) > 0 && !containsAny(r.Wallets, wallets(event)...) {
+		return false
+	}
+	if len(r.Protocols) > 0 && !slices.Contains(r.Protocols, protocol(event)) {
+		return false
+	}
+	if r.Side != "" && (event.Swap == nil || side(event) != r.Side) {
+		return false
+	}
+	if r.MinUSD > 0 && usdValue(event) < r.MinUSD {
+		return false
+	}
+	if r.MinLiquiditySOL > 0 && liquiditySOL(event) < r.MinLiquiditySOL {
+		return false
+	}
+	return r.Match == nil || r.Match(event)
+}
+
+// side returns the direction of a swap relative to its token
+func side(event *sink.Event) Side {
+	if event.Swap.TokenIn.Mint.Equals(event.Mint()) {
+		return SideSell
+	}
+	return SideBuy
+}
+
+// mints returns every mint the event touches
+func mints(event *sink.Event) []solana.PublicKey {
+	switch {
+	case event.Swap != nil:
+		return []solana.PublicKey{event.Swap.TokenIn.Mint, event.Swap.TokenOut.Mint}
+	case event.PoolCreated != nil:
+		return []solana.PublicKey{event.PoolCreated.MintA, event.PoolCreated.MintB}
+	}
+	return []solana.PublicKey{event.Mint()}
+}
+
+// wallets returns the wallets involved in the event
+func wallets(event *sink.Event) []solana.PublicKey {
+	switch {
+	case event.Swap != nil:
+		return event.Swap.Signers
+	case event.Transfer != nil:
+		return []solana.PublicKey{event.Transfer.SourceOwner, event.Transfer.DestinationOwner}
+	}
+	return []solana.PublicKey{event.Wallet()}
+}
+
+// protocol returns the protocol of swaps and pool creations, empty for other events
+func protocol(event *sink.Event) tx_parser.SwapType {
+	switch {
+	case event.Swap != nil:
+		return event.Swap.Protocol
+	case event.PoolCreated != nil:
+		return event.PoolCreated.Protocol
+	}
+	return ""
+}
+
+// usdValue is the USD value of a priced swap or transfer, zero for everything else
+func usdValue(event *sink.Event) float64 {
+	switch {
+	case event.Swap != nil:
+		if event.Swap.AmountUSD > 0 {
+			return event.Swap.AmountUSD
+		}
+		if event.Swap.Price != nil {
+			return event.Swap.Price.VolumeUSD
+		}
+	case event.Transfer != nil:
+		return event.Transfer.AmountUSD
+	}
+	return 0
+}
+
+// liquiditySOL is the SOL a new pool was seeded with, zero for everything else
+func liquiditySOL(event *sink.Event) float64 {
+	created := event.PoolCreated
+	switch {
+	case created == nil:
+		return 0
+	case created.MintA.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		return float64(created.AmountA) / float64(solana.LAMPORTS_PER_SOL)
+	case created.MintB.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		return float64(created.AmountB) / float64(solana.LAMPORTS_PER_SOL)
+	}
+	return 0
+}
+
+func containsAny(set []solana.PublicKey, keys ...solana.PublicKey) bool {
+	for _, key := range keys {
+		if !key.IsZero() && solana.PublicKeySlice(set).Contains(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,306 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+	"github.com/soralabs/solana-toolkit/go/sink/webhook"
+)
+
+var (
+	whale = solana.NewWallet().PublicKey()
+	token = solana.NewWallet().PublicKey()
+	sol   = tx_parser.NATIVE_SOL_PROGRAM_ID
+)
+
+// testEvents are a whale selling the token for $60k, buying it back for $1k and a pool of
+// the token seeded with 150 SOL
+func testEvents() []*sink.Event {
+	return sink.Events(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{1},
+		Swaps: []*tx_parser.SwapInfo{
+			{
+				Protocol:  tx_parser.SwapTypeRaydium,
+				Signers:   []solana.PublicKey{whale},
+				TokenIn:   tx_parser.TokenInfo{Mint: token, Amount: 1_000_000},
+				TokenOut:  tx_parser.TokenInfo{Mint: sol, Amount: 400 * solana.LAMPORTS_PER_SOL, Decimals: 9},
+				AmountUSD: 60_000,
+			},
+			{
+				Protocol:  tx_parser.SwapTypeRaydium,
+				Signers:   []solana.PublicKey{whale},
+				TokenIn:   tx_parser.TokenInfo{Mint: sol, Amount: 7 * solana.LAMPORTS_PER_SOL, Decimals: 9},
+				TokenOut:  tx_parser.TokenInfo{Mint: token, Amount: 10_000},
+				AmountUSD: 1_000,
+			},
+		},
+		PoolCreations: []*tx_parser.PoolCreatedEvent{{
+			Protocol:  tx_parser.SwapTypeRaydium,
+			MintA:     token,
+			MintB:     sol,
+			DecimalsB: 9,
+			AmountB:   150 * solana.LAMPORTS_PER_SOL,
+		}},
+	})
+}
+
+// recorder is a notifier remembering the rules of its alerts
+type recorder struct {
+	mu    sync.Mutex
+	rules []string
+	err   error
+}
+
+func (r *recorder) Notify(ctx context.Context, alert *Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, alert.Rule)
+	return r.err
+}
+
+func TestRules(t *testing.T) {
+	events := testEvents()
+	cases := []struct {
+		rule    Rule
+		matches []int
+	}{
+		{Rule{Kinds: []sink.Kind{sink.KindSwap}, Mints: []solana.PublicKey{token}, MinUSD: 50_000}, []int{0}},
+		{Rule{Wallets: []solana.PublicKey{whale}, Side: SideSell}, []int{0}},
+		{Rule{Wallets: []solana.PublicKey{whale}, Side: SideBuy}, []int{1}},
+		{Rule{Kinds: []sink.Kind{sink.KindPoolCreated}, MinLiquiditySOL: 100}, []int{2}},
+		{Rule{MinLiquiditySOL: 200}, nil},
+		{Rule{Protocols: []tx_parser.SwapType{tx_parser.SwapTypeRaydium}}, []int{0, 1, 2}},
+		{Rule{Protocols: []tx_parser.SwapType{tx_parser.SwapTypeOrca}}, nil},
+		{Rule{Match: func(e *sink.Event) bool { return e.Index == 1 }}, []int{1}},
+	}
+	for i, c := range cases {
+		var matches []int
+		for j, event := range events {
+			if c.rule.Matches(event) {
+				matches = append(matches, j)
+			}
+		}
+		if !slices.Equal(matches, c.matches) {
+			t.Errorf("rule %d: expected matches %v, got %v", i, c.matches, matches)
+		}
+	}
+}
+
+func TestEngine(t *testing.T) {
+	chat, hook := &recorder{}, &recorder{err: errors.New("down")}
+	engine, err := New(Config{
+		Rules: []Rule{
+			{Name: "whale", Kinds: []sink.Kind{sink.KindSwap}, MinUSD: 50_000, Notifiers: []string{"chat"}},
+			{Name: "pools", Kinds: []sink.Kind{sink.KindPoolCreated}, Cooldown: time.Minute},
+		},
+		Notifiers: map[string]Notifier{"chat": chat, "hook": hook},
+	})
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	engine.now = func() time.Time { return now }
+
+	alerts := engine.Evaluate(testEvents())
+	if len(alerts) != 2 || alerts[0].Rule != "whale" || alerts[1].Rule != "pools" || !alerts[0].Time.Equal(now) {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+	if err := engine.Notify(context.Background(), alerts); err == nil {
+		t.Error("expected the failing notifier to be reported")
+	}
+	// the whale goes to chat only, the pool to both notifiers
+	if len(chat.rules) != 2 || len(hook.rules) != 1 || hook.rules[0] != "pools" {
+		t.Errorf("unexpected notifications chat=%v hook=%v", chat.rules, hook.rules)
+	}
+
+	// the pool rule cools down for the token
+	if alerts := engine.Evaluate(testEvents()); len(alerts) != 1 || alerts[0].Rule != "whale" {
+		t.Errorf("expected the pool alert suppressed, got %+v", alerts)
+	}
+	now = now.Add(time.Minute)
+	if alerts := engine.Evaluate(testEvents()); len(alerts) != 2 {
+		t.Errorf("expected the pool alert after the cooldown, got %+v", alerts)
+	}
+
+	for _, config := range []Config{
+		{Rules: []Rule{{}}},
+		{Rules: []Rule{{Name: "a"}, {Name: "a"}}},
+		{Rules: []Rule{{Name: "a", Notifiers: []string{"nope"}}}},
+		{Rules: []Rule{{Name: "a", Side: "hold"}}},
+	} {
+		if _, err := New(config); err == nil {
+			t.Errorf("expected %+v to be rejected", config.Rules)
+		}
+	}
+}
+
+func TestSink(t *testing.T) {
+	chat := &recorder{err: errors.New("down")}
+	engine, _ := New(Config{Rules: []Rule{{Name: "sells", Side: SideSell}}, Notifiers: map[string]Notifier{"chat": chat}})
+	out := NewSink(nil, engine)
+	if err := out.Write(context.Background(), testEvents()); err != nil {
+		t.Errorf("expected failed notifications not to fail the write: %v", err)
+	}
+	if len(chat.rules) != 1 {
+		t.Errorf("expected one alert, got %v", chat.rules)
+	}
+	if err := out.Close(); err != nil {
+		t.Errorf("failed to close: %v", err)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var received Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.TIMESTAMP_HEADER), 10, 64)
+		if !webhook.Verify("secret", timestamp, body, r.Header.Get(webhook.SIGNATURE_HEADER)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal(body, &received)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhook(WebhookConfig{URL: server.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+	event := testEvents()[0]
+	if err := notifier.Notify(context.Background(), &Alert{Rule: "whale", Event: event}); err != nil {
+		t.Fatalf("failed to notify: %v", err)
+	}
+	if received.Rule != "whale" || received.Event.Swap == nil || received.Event.Swap.AmountUSD != 60_000 {
+		t.Errorf("unexpected alert %+v", received)
+	}
+
+	notifier, _ = NewWebhook(WebhookConfig{URL: server.URL, Secret: "wrong"})
+	if err := notifier.Notify(context.Background(), &Alert{Rule: "whale", Event: event}); err == nil {
+		t.Error("expected a rejected delivery to fail")
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	formatter := NewFormatter(MessageConfig{Symbol: func(mint solana.PublicKey) string {
+		if mint.Equals(token) {
+			return "BONK"
+		}
+		return ""
+	}})
+	events := testEvents()
+
+	message := formatter.Alert(&Alert{Rule: "whale", Event: events[0]})
+	if message.Title != "whale: Sell BONK on Raydium" || message.Color != COLOR_SELL {
+		t.Errorf("unexpected title %q", message.Title)
+	}
+	fields := map[string]string{}
+	for _, field := range message.Fields {
+		fields[field.Name] = field.Value
+	}
+	if fields["Sold"] != "1000000 BONK" || fields["Bought"] != "400 SOL" || fields["Value"] != "$60000.00" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+	if len(message.Links) != 3 || message.Links[0].URL != DEFAULT_EXPLORER+"/tx/"+events[0].Signature.String() || message.Links[1].URL != DEFAULT_EXPLORER+"/token/"+token.String() {
+		t.Errorf("unexpected links %v", message.Links)
+	}
+
+	message = formatter.Event(events[2])
+	if message.Title != "New Raydium pool BONK/SOL" || message.Fields[1].Value != "0 BONK + 150 SOL" {
+		t.Errorf("unexpected pool message %+v", message)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	var requests []map[string]any
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottoken/sendMessage" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var request map[string]any
+		json.NewDecoder(r.Body).Decode(&request)
+		mu.Lock()
+		requests = append(requests, request)
+		first := len(requests) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"ok":false,"description":"Too Many Requests","parameters":{"retry_after":1}}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	telegram, err := NewTelegram(TelegramConfig{Token: "token", ChatID: "-100", APIURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+	if err := telegram.Notify(context.Background(), &Alert{Rule: "<whales>", Event: testEvents()[0]}); err != nil {
+		t.Fatalf("failed to notify: %v", err)
+	}
+	if len(requests) != 2 || requests[1]["chat_id"] != "-100" || requests[1]["parse_mode"] != "HTML" {
+		t.Fatalf("expected a retry after the rate limit, got %v", requests)
+	}
+	text := requests[1]["text"].(string)
+	if !strings.HasPrefix(text, "<b>&lt;whales&gt;: Sell ") || !strings.Contains(text, `<a href="https://solscan.io/tx/`) {
+		t.Errorf("unexpected text %q", text)
+	}
+
+	telegram, _ = NewTelegram(TelegramConfig{Token: "wrong", ChatID: "-100", APIURL: server.URL})
+	if err := telegram.SendEvent(context.Background(), testEvents()[2]); err == nil {
+		t.Error("expected an unknown bot to fail")
+	}
+}
+
+func TestDiscord(t *testing.T) {
+	var payload struct {
+		Username string         `json:"username"`
+		Embeds   []discordEmbed `json:"embeds"`
+	}
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	discord, err := NewDiscord(DiscordConfig{URL: server.URL, Message: MessageConfig{Explorer: "https://explorer.test/"}})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %v", err)
+	}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := discord.Notify(context.Background(), &Alert{Rule: "buys", Time: at, Event: testEvents()[1]}); err != nil {
+		t.Fatalf("failed to notify: %v", err)
+	}
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("expected one embed, got %+v", payload)
+	}
+	embed := payload.Embeds[0]
+	if embed.Color != COLOR_BUY || embed.Timestamp != "2024-05-01T12:00:00Z" || !strings.HasPrefix(embed.URL, "https://explorer.test/tx/") {
+		t.Errorf("unexpected embed %+v", embed)
+	}
+	if len(embed.Fields) != 4 || !strings.Contains(embed.Description, "[Wallet](https://explorer.test/account/"+whale.String()+")") {
+		t.Errorf("unexpected embed fields %+v", embed)
+	}
+
+	status = http.StatusBadRequest
+	if err := discord.SendEvent(context.Background(), testEvents()[0]); err == nil {
+		t.Error("expected a rejected message to fail")
+	}
+}
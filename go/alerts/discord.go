@@ -0,0 +1,129 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Discord posts messages as embeds to a Discord channel webhook
+type Discord struct {
+	config    DiscordConfig
+	formatter *Formatter
+}
+
+var _ Notifier = (*Discord)(nil)
+
+// discordEmbed is the subset of Discord's embed object messages use
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// NewDiscord creates a Discord notifier
+func NewDiscord(config DiscordConfig) (*Discord, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Discord{config: config, formatter: NewFormatter(config.Message)}, nil
+}
+
+// Notify posts the alert, timestamped with the time it was raised
+func (d *Discord) Notify(ctx context.Context, alert *Alert) error {
+	return d.send(ctx, d.formatter.Alert(alert), alert.Time)
+}
+
+// SendEvent posts a single event, for use without the alert engine
+func (d *Discord) SendEvent(ctx context.Context, event *sink.Event) error {
+	var at time.Time
+	if event.BlockTime != nil {
+		at = event.BlockTime.Time()
+	}
+	return d.send(ctx, d.formatter.Event(event), at)
+}
+
+// Send posts a message, waiting out rate limits until the context is done
+func (d *Discord) Send(ctx context.Context, message *Message) error {
+	return d.send(ctx, message, time.Time{})
+}
+
+func (d *Discord) send(ctx context.Context, message *Message, at time.Time) error {
+	body, err := json.Marshal(struct {
+		Username        string         `json:"username,omitempty"`
+		Embeds          []discordEmbed `json:"embeds"`
+		AllowedMentions map[string]any `json:"allowed_mentions"`
+	}{d.config.Username, []discordEmbed{d.render(message, at)}, map[string]any{"parse": []string{}}})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	for {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, d.config.URL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		response, err := d.config.HTTPClient.Do(request)
+		if err != nil {
+			// the error's URL holds the webhook token
+			return fmt.Errorf("failed to post message: %w", redact(err, d.config.URL))
+		}
+
+		var limit struct {
+			RetryAfter float64 `json:"retry_after"` // seconds
+		}
+		data, _ := io.ReadAll(io.LimitReader(response.Body, 1<<16))
+		response.Body.Close()
+		switch {
+		case response.StatusCode == http.StatusTooManyRequests && json.Unmarshal(data, &limit) == nil && limit.RetryAfter > 0:
+			if err := wait(ctx, time.Duration(limit.RetryAfter*float64(time.Second))); err != nil {
+				return fmt.Errorf("rate limited: %w", err)
+			}
+		case response.StatusCode < 200 || response.StatusCode >= 300:
+			return fmt.Errorf("discord returned %s: %s", response.Status, strings.TrimSpace(string(data)))
+		default:
+			return nil
+		}
+	}
+}
+
+// render builds an embed titled with the message and linking to its transaction, with
+// inline fields and the other links in the description
+func (d *Discord) render(message *Message, at time.Time) discordEmbed {
+	embed := discordEmbed{Title: message.Title, Color: message.Color}
+	for _, field := range message.Fields {
+		embed.Fields = append(embed.Fields, discordField{Name: field.Name, Value: field.Value, Inline: true})
+	}
+	var links []string
+	for i, link := range message.Links {
+		if i == 0 {
+			embed.URL = link.URL
+		}
+		links = append(links, "["+link.Text+"]("+link.URL+")")
+	}
+	embed.Description = strings.Join(links, " · ")
+	if !at.IsZero() {
+		embed.Timestamp = at.UTC().Format(time.RFC3339)
+	}
+	return embed
+}
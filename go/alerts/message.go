@@ -0,0 +1,178 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// DEFAULT_EXPLORER is the block explorer messages link to
+const DEFAULT_EXPLORER = "https://solscan.io"
+
+// Colors of messages on services that support them
+const (
+	COLOR_BUY   = 0x2ecc71
+	COLOR_SELL  = 0xe74c3c
+	COLOR_OTHER = 0x3498db
+)
+
+// Message is an event rendered for people, independent of the chat it is sent to
+type Message struct {
+	Title  string
+	Fields []Field
+	Links  []Link
+	Color  int // RGB
+}
+
+// Field is a labelled value of a message
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Link points to the explorer page of the transaction, token or wallet of a message
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Formatter renders events and alerts as messages
+type Formatter struct {
+	config MessageConfig
+}
+
+// NewFormatter creates a formatter
+func NewFormatter(config MessageConfig) *Formatter {
+	config.Explorer = strings.TrimSuffix(config.Explorer, "/")
+	if config.Explorer == "" {
+		config.Explorer = DEFAULT_EXPLORER
+	}
+	return &Formatter{config: config}
+}
+
+// Alert renders the alert's event titled with the rule that raised it
+func (f *Formatter) Alert(alert *Alert) *Message {
+	message := f.Event(alert.Event)
+	message.Title = alert.Rule + ": " + message.Title
+	return message
+}
+
+// Event renders a swap, new pool or transfer with its amounts, other events with their
+// wallet and mint. Every message links to its transaction.
+func (f *Formatter) Event(event *sink.Event) *Message {
+	message := &Message{Color: COLOR_OTHER}
+	mint, wallet := event.Mint(), event.Wallet()
+	switch {
+	case event.Swap != nil:
+		swap := event.Swap
+		if side(event) == SideSell {
+			message.Title = "Sell " + f.symbol(mint)
+			message.Color = COLOR_SELL
+		} else {
+			message.Title = "Buy " + f.symbol(mint)
+			message.Color = COLOR_BUY
+		}
+		message.Title += " on " + string(swap.Protocol)
+		message.Fields = append(message.Fields,
+			Field{"Wallet", short(wallet)},
+			Field{"Sold", f.amount(swap.TokenIn)},
+			Field{"Bought", f.amount(swap.TokenOut)},
+		)
+		if value := usdValue(event); value > 0 {
+			message.Fields = append(message.Fields, Field{"Value", usd(value)})
+		}
+		if swap.Price != nil && swap.Price.PriceUSD > 0 && swap.Price.BaseMint.Equals(mint) {
+			message.Fields = append(message.Fields, Field{"Price", usd(swap.Price.PriceUSD)})
+		}
+	case event.PoolCreated != nil:
+		created := event.PoolCreated
+		message.Title = "New " + string(created.Protocol) + " pool " + f.symbol(created.MintA) + "/" + f.symbol(created.MintB)
+		message.Fields = append(message.Fields,
+			Field{"Pool", short(created.Pool)},
+			Field{"Liquidity", f.amount(tx_parser.TokenInfo{Mint: created.MintA, Amount: created.AmountA, Decimals: created.DecimalsA}) +
+				" + " + f.amount(tx_parser.TokenInfo{Mint: created.MintB, Amount: created.AmountB, Decimals: created.DecimalsB})},
+			Field{"Creator", short(created.Creator)},
+		)
+		if mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+			mint = created.MintB
+		}
+	case event.Transfer != nil:
+		transfer := event.Transfer
+		if transfer.Type == tx_parser.TransferTypeSOL {
+			mint = tx_parser.NATIVE_SOL_PROGRAM_ID
+		}
+		message.Title = "Transfer " + f.symbol(mint)
+		message.Fields = append(message.Fields,
+			Field{"Amount", f.amount(tx_parser.TokenInfo{Mint: mint, Amount: transfer.Amount, Decimals: transfer.Decimals})},
+			Field{"From", short(transfer.SourceOwner)},
+			Field{"To", short(transfer.DestinationOwner)},
+		)
+		if transfer.AmountUSD > 0 {
+			message.Fields = append(message.Fields, Field{"Value", usd(transfer.AmountUSD)})
+		}
+	default:
+		message.Title = strings.ReplaceAll(string(event.Kind), "_", " ")
+		if !wallet.IsZero() {
+			message.Fields = append(message.Fields, Field{"Wallet", short(wallet)})
+		}
+		if !mint.IsZero() {
+			message.Fields = append(message.Fields, Field{"Mint", short(mint)})
+		}
+	}
+
+	message.Links = append(message.Links, Link{"Transaction", f.config.Explorer + "/tx/" + event.Signature.String()})
+	if !mint.IsZero() && !mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		message.Links = append(message.Links, Link{"Token", f.config.Explorer + "/token/" + mint.String()})
+	}
+	if !wallet.IsZero() {
+		message.Links = append(message.Links, Link{"Wallet", f.config.Explorer + "/account/" + wallet.String()})
+	}
+	return message
+}
+
+// symbol names a mint by its symbol, SOL for wrapped SOL and a shortened address otherwise
+func (f *Formatter) symbol(mint solana.PublicKey) string {
+	if mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		return "SOL"
+	}
+	if f.config.Symbol != nil {
+		if symbol := f.config.Symbol(mint); symbol != "" {
+			return symbol
+		}
+	}
+	return short(mint)
+}
+
+// amount formats a token amount in whole tokens with its symbol
+func (f *Formatter) amount(token tx_parser.TokenInfo) string {
+	digits := strconv.FormatUint(token.Amount, 10)
+	if token.Decimals > 0 {
+		if len(digits) <= int(token.Decimals) {
+			digits = strings.Repeat("0", int(token.Decimals)-len(digits)+1) + digits
+		}
+		split := len(digits) - int(token.Decimals)
+		digits = strings.TrimSuffix(strings.TrimRight(digits[:split]+"."+digits[split:], "0"), ".")
+	}
+	return digits + " " + f.symbol(token.Mint)
+}
+
+// short abbreviates an address to its first and last four characters
+func short(key solana.PublicKey) string {
+	s := key.String()
+	if len(s) <= 11 {
+		return s
+	}
+	return s[:4] + "…" + s[len(s)-4:]
+}
+
+func usd(value float64) string {
+	if value >= 1 {
+		return fmt.Sprintf("$%.2f", value)
+	}
+	return "$" + strconv.FormatFloat(value, 'g', 4, 64)
+}
@@ -0,0 +1,42 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Sink evaluates the engine's rules against the events written to another sink. Failed
+// notifications are logged by the engine and do not fail the write, so a broken notifier
+// does not stall the pipeline.
+type Sink struct {
+	next   sink.Sink
+	engine *Engine
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// NewSink wraps a sink, alerting on its events once it accepted them. A nil next only
+// alerts. Close closes next.
+func NewSink(next sink.Sink, engine *Engine) *Sink {
+	return &Sink{next: next, engine: engine}
+}
+
+// Write passes the events on and sends the alerts they raise
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	if s.next != nil {
+		if err := s.next.Write(ctx, events); err != nil {
+			return err
+		}
+	}
+	s.engine.Notify(ctx, s.engine.Evaluate(events))
+	return nil
+}
+
+// Close closes the wrapped sink
+func (s *Sink) Close() error {
+	if s.next == nil {
+		return nil
+	}
+	return s.next.Close()
+}
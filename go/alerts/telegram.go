@@ -0,0 +1,132 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Telegram sends messages through a Telegram bot
+type Telegram struct {
+	config    TelegramConfig
+	formatter *Formatter
+}
+
+var _ Notifier = (*Telegram)(nil)
+
+// NewTelegram creates a Telegram notifier
+func NewTelegram(config TelegramConfig) (*Telegram, error) {
+	if config.Token == "" || config.ChatID == "" {
+		return nil, fmt.Errorf("bot token and chat ID are required")
+	}
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+	if config.APIURL == "" {
+		config.APIURL = "https://api.telegram.org"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Telegram{config: config, formatter: NewFormatter(config.Message)}, nil
+}
+
+// Notify sends the alert
+func (t *Telegram) Notify(ctx context.Context, alert *Alert) error {
+	return t.Send(ctx, t.formatter.Alert(alert))
+}
+
+// SendEvent sends a single event, for use without the alert engine
+func (t *Telegram) SendEvent(ctx context.Context, event *sink.Event) error {
+	return t.Send(ctx, t.formatter.Event(event))
+}
+
+// Send sends a message as HTML, waiting out rate limits until the context is done
+func (t *Telegram) Send(ctx context.Context, message *Message) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  t.config.ChatID,
+		"text":                     t.render(message),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	for {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.APIURL+"/bot"+t.config.Token+"/sendMessage", bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		response, err := t.config.HTTPClient.Do(request)
+		if err != nil {
+			// the error's URL holds the bot token
+			return fmt.Errorf("failed to send message: %w", redact(err, t.config.Token))
+		}
+
+		var result struct {
+			OK          bool   `json:"ok"`
+			Description string `json:"description"`
+			Parameters  struct {
+				RetryAfter int `json:"retry_after"`
+			} `json:"parameters"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&result)
+		response.Body.Close()
+		switch {
+		case response.StatusCode == http.StatusTooManyRequests && result.Parameters.RetryAfter > 0:
+			if err := wait(ctx, time.Duration(result.Parameters.RetryAfter)*time.Second); err != nil {
+				return fmt.Errorf("rate limited: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("telegram returned %s", response.Status)
+		case !result.OK:
+			return fmt.Errorf("telegram returned %s: %s", response.Status, result.Description)
+		default:
+			return nil
+		}
+	}
+}
+
+// render formats the message as Telegram HTML, a bold title over one line per field and
+// a line of links
+func (t *Telegram) render(message *Message) string {
+	var b strings.Builder
+	b.WriteString("<b>" + html.EscapeString(message.Title) + "</b>\n")
+	for _, field := range message.Fields {
+		b.WriteString(html.EscapeString(field.Name) + ": " + html.EscapeString(field.Value) + "\n")
+	}
+	for i, link := range message.Links {
+		if i > 0 {
+			b.WriteString(" | ")
+		}
+		b.WriteString(`<a href="` + html.EscapeString(link.URL) + `">` + html.EscapeString(link.Text) + "</a>")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// redact removes a secret from an error's message
+func redact(err error, secret string) error {
+	if secret == "" || !strings.Contains(err.Error(), secret) {
+		return err
+	}
+	return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), secret, "<redacted>"))
+}
+
+// wait sleeps for the delay unless the context is done first
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,150 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Side is the direction of a swap relative to its token, see sink.Event.Mint
+type Side string
+
+const (
+	SideBuy  Side = "buy"  // the token is received
+	SideSell Side = "sell" // the token is given up
+)
+
+// Rule selects the events that raise an alert. An event must match every non-empty field
+// and matches a list when it matches any of its values.
+type Rule struct {
+	// Name identifies the rule in its alerts, must be unique
+	Name string
+
+	Kinds []sink.Kind
+
+	// Mints match either side of a swap, either mint of a new pool and the mint of any
+	// other event
+	Mints []solana.PublicKey
+
+	// Wallets match the signers of swaps, either owner of transfers and the wallet of any
+	// other event
+	Wallets []solana.PublicKey
+
+	// Protocols match swaps and pool creations of the given protocols
+	Protocols []tx_parser.SwapType
+
+	// Side matches swaps in one direction, only swaps match when set
+	Side Side
+
+	// MinUSD matches swaps and transfers worth at least as much, as priced by the pricing
+	// package. Events without a USD value do not match when set.
+	MinUSD float64
+
+	// MinLiquiditySOL matches pools created with at least as much SOL, only pools paired
+	// with SOL match when set
+	MinLiquiditySOL float64
+
+	// Match is an optional custom condition checked after the other fields
+	Match func(event *sink.Event) bool
+
+	// Cooldown suppresses alerts of the rule for the same mint until it has passed, zero
+	// alerts on every match
+	Cooldown time.Duration
+
+	// Notifiers names the notifiers the rule's alerts are sent to, all of them if empty
+	Notifiers []string
+}
+
+// Alert is a rule matching an event
+type Alert struct {
+	Rule  string      `json:"rule"`
+	Time  time.Time   `json:"time"`
+	Event *sink.Event `json:"event"`
+}
+
+// Notifier delivers alerts, e.g. to a chat or an HTTP endpoint
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
+
+// NotifierFunc adapts a function to a Notifier
+type NotifierFunc func(ctx context.Context, alert *Alert) error
+
+// Notify calls f
+func (f NotifierFunc) Notify(ctx context.Context, alert *Alert) error {
+	return f(ctx, alert)
+}
+
+// Config controls the rules and where their alerts go
+type Config struct {
+	Rules []Rule
+
+	// Notifiers by name, referenced by Rule.Notifiers
+	Notifiers map[string]Notifier
+
+	// Timeout bounds each notification, defaults to 10s
+	Timeout time.Duration
+
+	// Logger receives an error record per failed notification, defaults to a no-op logger
+	Logger logging.Logger
+}
+
+// WebhookConfig controls a webhook notifier
+type WebhookConfig struct {
+	URL string
+
+	// Secret signs every delivery like the webhook sink does, empty disables signing
+	Secret string
+
+	// HTTPClient defaults to a client with a 10s timeout
+	HTTPClient *http.Client
+}
+
+// MessageConfig controls how events are rendered as messages
+type MessageConfig struct {
+	// Explorer is the base URL of the block explorer linked to, defaults to DEFAULT_EXPLORER.
+	// Links follow solscan's /tx, /token and /account paths.
+	Explorer string
+
+	// Symbol names tokens, e.g. from tokenmeta, shortened addresses are used when it is
+	// nil or returns an empty string
+	Symbol func(mint solana.PublicKey) string
+}
+
+// TelegramConfig controls a Telegram bot notifier
+type TelegramConfig struct {
+	// Token of the bot, as issued by BotFather
+	Token string
+
+	// ChatID is the chat, group or channel messages are sent to, e.g. -1001234567890 or
+	// @channel
+	ChatID string
+
+	// APIURL defaults to https://api.telegram.org
+	APIURL string
+
+	// HTTPClient defaults to a client with a 10s timeout
+	HTTPClient *http.Client
+
+	Message MessageConfig
+}
+
+// DiscordConfig controls a Discord webhook notifier
+type DiscordConfig struct {
+	// URL of the channel webhook
+	URL string
+
+	// Username overrides the webhook's name, optional
+	Username string
+
+	// HTTPClient defaults to a client with a 10s timeout
+	HTTPClient *http.Client
+
+	Message MessageConfig
+}
@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/sink/webhook"
+)
+
+// Webhook POSTs each alert as a JSON object, signed with the webhook sink's headers so
+// receivers can verify it with webhook.Verify
+type Webhook struct {
+	config WebhookConfig
+}
+
+var _ Notifier = (*Webhook)(nil)
+
+// NewWebhook creates a webhook notifier
+func NewWebhook(config WebhookConfig) (*Webhook, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Webhook{config: config}, nil
+}
+
+// Notify posts the alert, failing unless the endpoint answers 2xx
+func (w *Webhook) Notify(ctx context.Context, alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(webhook.TIMESTAMP_HEADER, strconv.FormatInt(timestamp, 10))
+	if w.config.Secret != "" {
+		request.Header.Set(webhook.SIGNATURE_HEADER, webhook.Sign(w.config.Secret, timestamp, body))
+	}
+
+	response, err := w.config.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/klauspost/compress/zstd"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	payer     = solana.NewWallet().PublicKey()
+	recipient = solana.NewWallet().PublicKey()
+)
+
+// transfer returns a transaction sending lamports from the payer to the recipient
+func transfer(lamports uint64) *solana.Transaction {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data, 2)
+	binary.LittleEndian.PutUint64(data[4:], lamports)
+	return &solana.Transaction{
+		Signatures: []solana.Signature{{byte(lamports)}},
+		Message: solana.Message{
+			Header:       solana.MessageHeader{NumRequiredSignatures: 1},
+			AccountKeys:  []solana.PublicKey{payer, recipient, solana.SystemProgramID},
+			Instructions: []solana.CompiledInstruction{{ProgramIDIndex: 2, Accounts: []uint16{0, 1}, Data: data}},
+		},
+	}
+}
+
+func transferMeta(lamports uint64) *pb.TransactionStatusMeta {
+	return &pb.TransactionStatusMeta{
+		Fee:          5_000,
+		PreBalances:  []uint64{lamports + 5_000, 0, 1},
+		PostBalances: []uint64{0, lamports, 1},
+	}
+}
+
+// checkTransfer parses the block and checks it holds the transfer
+func checkTransfer(t *testing.T, slot uint64, block *rpc.GetBlockResult, lamports uint64) {
+	t.Helper()
+	parsed, err := tx_parser.ParseBlockWithOptions(block, tx_parser.BlockOptions{Slot: slot})
+	if err != nil {
+		t.Fatalf("failed to parse block %d: %v", slot, err)
+	}
+	if len(parsed.Transactions) != 1 || len(parsed.Errors) != 0 {
+		t.Fatalf("expected one transaction in block %d, got %+v", slot, parsed)
+	}
+	for _, tx := range parsed.Transactions {
+		if len(tx.Transfers) != 1 || tx.Transfers[0].Amount != lamports || tx.Fee != 5_000 {
+			t.Errorf("unexpected transaction %+v", tx)
+		}
+	}
+}
+
+// encodeCBOR encodes the values the decoder supports, with sorted map keys
+func encodeCBOR(value any) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n <= 0xff:
+			return []byte{major<<5 | 24, byte(n)}
+		case n <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+		case n <= 0xffffffff:
+			return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+		}
+		return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, n)
+	}
+	switch v := value.(type) {
+	case nil:
+		return []byte{0xf6}
+	case int:
+		return head(0, uint64(v))
+	case uint64:
+		return head(0, v)
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case cid:
+		return append([]byte{0xd8, 42}, encodeCBOR(append([]byte{0}, v...))...)
+	case []any:
+		out := head(4, uint64(len(v)))
+		for _, item := range v {
+			out = append(out, encodeCBOR(item)...)
+		}
+		return out
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out := head(5, uint64(len(v)))
+		for _, key := range keys {
+			out = append(out, encodeCBOR(key)...)
+			out = append(out, encodeCBOR(v[key])...)
+		}
+		return out
+	}
+	panic(fmt.Sprintf("unsupported %T", value))
+}
+
+// carWriter writes a CARv1 file of DAG-CBOR nodes
+type carWriter struct {
+	buffer bytes.Buffer
+}
+
+func (w *carWriter) section(data []byte) {
+	w.buffer.Write(binary.AppendUvarint(nil, uint64(len(data))))
+	w.buffer.Write(data)
+}
+
+// node writes a node and returns its CIDv1
+func (w *carWriter) node(node []any) cid {
+	data := encodeCBOR(node)
+	digest := sha256.Sum256(data)
+	id := cid(append([]byte{0x01, 0x71, 0x12, 0x20}, digest[:]...))
+	w.section(append([]byte(id), data...))
+	return id
+}
+
+// writeBlock writes a block of one transfer, with the transaction split across two frames
+func (w *carWriter) writeBlock(t *testing.T, slot, lamports uint64) {
+	raw, err := transfer(lamports).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	meta, _ := proto.Marshal(transferMeta(lamports))
+	encoder, _ := zstd.NewWriter(nil)
+	compressed := encoder.EncodeAll(meta, nil)
+
+	rest := w.node([]any{kindDataFrame, nil, 1, 2, raw[10:]})
+	tx := w.node([]any{kindTransaction,
+		[]any{kindDataFrame, nil, 0, 2, raw[:10], []any{rest}},
+		[]any{kindDataFrame, nil, nil, nil, compressed},
+		slot, 0,
+	})
+	entry := w.node([]any{kindEntry, 1, bytes.Repeat([]byte{byte(slot)}, 32), []any{tx}})
+	w.node([]any{kindBlock, slot, []any{}, []any{entry}, []any{slot - 1, 1_700_000_000 + slot, slot - 10}, nil})
+}
+
+func TestCAR(t *testing.T) {
+	w := &carWriter{}
+	w.section(encodeCBOR(map[string]any{"roots": []any{cid("root")}, "version": 1}))
+	for slot := uint64(11); slot <= 14; slot++ {
+		w.writeBlock(t, slot, slot*1_000)
+	}
+	path := filepath.Join(t.TempDir(), "epoch-0.car")
+	if err := os.WriteFile(path, w.buffer.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	var slots []uint64
+	err := NewCAR(CARConfig{Paths: []string{path}}).Walk(context.Background(), 12, 13, func(slot uint64, block *rpc.GetBlockResult) error {
+		slots = append(slots, slot)
+		checkTransfer(t, slot, block, slot*1_000)
+		if block.ParentSlot != slot-1 || block.BlockTime == nil || int64(*block.BlockTime) != int64(1_700_000_000+slot) || *block.BlockHeight != slot-10 {
+			t.Errorf("unexpected block meta %+v", block)
+		}
+		if block.Blockhash[0] != byte(slot) {
+			t.Errorf("expected the blockhash of the last entry, got %s", block.Blockhash)
+		}
+		return nil
+	})
+	if err != nil || !slices.Equal(slots, []uint64{12, 13}) {
+		t.Errorf("expected blocks 12 and 13, got %v: %v", slots, err)
+	}
+
+	stop := errors.New("stop")
+	err = NewCAR(CARConfig{Paths: []string{path}}).Walk(context.Background(), 0, 100, func(uint64, *rpc.GetBlockResult) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+}
+
+// rows is an in-memory blocks table
+type rows map[string]map[string][]byte
+
+func (r rows) ReadRow(ctx context.Context, table, key string) (map[string][]byte, error) {
+	return r[key], nil
+}
+
+func (r rows) RowKeys(ctx context.Context, table, start string, limit int) ([]string, error) {
+	var keys []string
+	for key := range r {
+		if key >= start {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys[:min(limit, len(keys))], nil
+}
+
+func TestBigtable(t *testing.T) {
+	table := rows{}
+	for _, slot := range []uint64{20, 21, 23, 30} {
+		tx := transfer(slot)
+		block := &pb.ConfirmedBlock{
+			Blockhash:         solana.Hash{byte(slot)}.String(),
+			PreviousBlockhash: solana.Hash{}.String(),
+			ParentSlot:        slot - 1,
+			BlockTime:         &pb.UnixTimestamp{Timestamp: int64(slot)},
+			Transactions: []*pb.ConfirmedTransaction{{
+				Transaction: &pb.Transaction{
+					Signatures: [][]byte{tx.Signatures[0][:]},
+					Message: &pb.Message{
+						Header:       &pb.MessageHeader{NumRequiredSignatures: 1},
+						AccountKeys:  [][]byte{payer[:], recipient[:], solana.SystemProgramID[:]},
+						Instructions: []*pb.CompiledInstruction{{ProgramIdIndex: 2, Accounts: []byte{0, 1}, Data: tx.Message.Instructions[0].Data}},
+					},
+				},
+				Meta: transferMeta(slot),
+			}},
+		}
+		data, _ := proto.Marshal(block)
+		cell := binary.LittleEndian.AppendUint32(nil, compressionNone)
+		if slot%2 == 1 {
+			encoder, _ := zstd.NewWriter(nil)
+			cell = binary.LittleEndian.AppendUint32(nil, compressionZstd)
+			data = encoder.EncodeAll(data, nil)
+		}
+		table[slotKey(slot)] = map[string][]byte{"proto": append(cell, data...)}
+	}
+	table[slotKey(40)] = map[string][]byte{"bin": {0, 0, 0, 0}}
+
+	reader := NewBigtable(table, BigtableConfig{PageSize: 2})
+	slots, err := reader.Slots(context.Background(), 21, 30)
+	if err != nil || !slices.Equal(slots, []uint64{21, 23, 30}) {
+		t.Errorf("expected slots 21, 23 and 30, got %v: %v", slots, err)
+	}
+
+	var walked []uint64
+	err = reader.Walk(context.Background(), 0, 25, func(slot uint64, block *rpc.GetBlockResult) error {
+		walked = append(walked, slot)
+		checkTransfer(t, slot, block, slot)
+		if block.Blockhash[0] != byte(slot) || *block.BlockTime != solana.UnixTimeSeconds(slot) {
+			t.Errorf("unexpected block %+v", block)
+		}
+		return nil
+	})
+	if err != nil || !slices.Equal(walked, []uint64{20, 21, 23}) {
+		t.Errorf("expected blocks 20, 21 and 23, got %v: %v", walked, err)
+	}
+
+	if _, err := reader.Block(context.Background(), 22); !errors.Is(err, ErrBlockNotFound) {
+		t.Errorf("expected a skipped slot to be missing, got %v", err)
+	}
+	if _, err := reader.Block(context.Background(), 40); err == nil {
+		t.Error("expected legacy bincode blocks to fail")
+	}
+}
@@ -0,0 +1,120 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrBlockNotFound is returned for slots without a block in the archive, e.g. skipped slots
+var ErrBlockNotFound = errors.New("block not found")
+
+// Bigtable reads blocks from the Bigtable layout of Solana's long-term ledger storage,
+// where the blocks table holds a compressed protobuf block per slot, keyed by the slot as
+// 16 hex digits
+type Bigtable struct {
+	rows   BigtableRows
+	config BigtableConfig
+}
+
+// NewBigtable creates a Bigtable archive reader
+func NewBigtable(rows BigtableRows, config BigtableConfig) *Bigtable {
+	if config.Table == "" {
+		config.Table = "blocks"
+	}
+	if config.PageSize <= 0 {
+		config.PageSize = 1000
+	}
+	return &Bigtable{rows: rows, config: config}
+}
+
+// Block reads the block at the slot
+func (b *Bigtable) Block(ctx context.Context, slot uint64) (*rpc.GetBlockResult, error) {
+	cells, err := b.rows.ReadRow(ctx, b.config.Table, slotKey(slot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block %d: %w", slot, err)
+	}
+	if cells == nil {
+		return nil, ErrBlockNotFound
+	}
+	cell, ok := cells["proto"]
+	if !ok {
+		if _, ok := cells["bin"]; ok {
+			return nil, fmt.Errorf("block %d is stored as legacy bincode, which is not supported", slot)
+		}
+		return nil, fmt.Errorf("block %d has no proto cell", slot)
+	}
+
+	data, err := decompress(cell)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress block %d: %w", slot, err)
+	}
+	var confirmed pb.ConfirmedBlock
+	if err := proto.Unmarshal(data, &confirmed); err != nil {
+		return nil, fmt.Errorf("failed to decode block %d: %w", slot, err)
+	}
+	block, err := convertBlock(&confirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert block %d: %w", slot, err)
+	}
+	return block, nil
+}
+
+// Slots lists the slots with a block from from to to, inclusive
+func (b *Bigtable) Slots(ctx context.Context, from, to uint64) ([]uint64, error) {
+	var slots []uint64
+	err := b.slots(ctx, from, to, func(slot uint64) error {
+		slots = append(slots, slot)
+		return nil
+	})
+	return slots, err
+}
+
+// Walk reads every block from from to to, inclusive
+func (b *Bigtable) Walk(ctx context.Context, from, to uint64, fn func(slot uint64, block *rpc.GetBlockResult) error) error {
+	return b.slots(ctx, from, to, func(slot uint64) error {
+		block, err := b.Block(ctx, slot)
+		if err != nil {
+			return err
+		}
+		return fn(slot, block)
+	})
+}
+
+// slots pages through the row keys of the range
+func (b *Bigtable) slots(ctx context.Context, from, to uint64, fn func(slot uint64) error) error {
+	start := slotKey(from)
+	for {
+		keys, err := b.rows.RowKeys(ctx, b.config.Table, start, b.config.PageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list blocks from %s: %w", start, err)
+		}
+		for _, key := range keys {
+			slot, err := strconv.ParseUint(key, 16, 64)
+			if err != nil {
+				return fmt.Errorf("invalid block key %q: %w", key, err)
+			}
+			if slot > to {
+				return nil
+			}
+			if err := fn(slot); err != nil {
+				return err
+			}
+		}
+		if len(keys) < b.config.PageSize {
+			return nil
+		}
+		last, _ := strconv.ParseUint(keys[len(keys)-1], 16, 64)
+		start = slotKey(last + 1)
+	}
+}
+
+// slotKey returns the row key of a slot, which sorts in slot order
+func slotKey(slot uint64) string {
+	return fmt.Sprintf("%016x", slot)
+}
@@ -0,0 +1,334 @@
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Kinds of the nodes in old-faithful CAR files
+const (
+	kindTransaction = 0
+	kindEntry       = 1
+	kindBlock       = 2
+	kindDataFrame   = 6
+)
+
+// CAR reads blocks from old-faithful epoch CAR files. Every node of a block precedes the
+// block node and blocks are in slot order, so files are streamed without an index.
+type CAR struct {
+	config CARConfig
+}
+
+// NewCAR creates an old-faithful CAR reader
+func NewCAR(config CARConfig) *CAR {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &CAR{config: config}
+}
+
+// carTransaction is a transaction node of a block in the walked range
+type carTransaction struct {
+	data, meta []any // data frames
+	index      uint64
+}
+
+// carEntry is an entry node, listing its transactions
+type carEntry struct {
+	hash         []byte
+	transactions []cid
+}
+
+// carReader assembles blocks from the nodes of one file
+type carReader struct {
+	from, to     uint64
+	transactions map[cid]*carTransaction
+	entries      map[cid]*carEntry
+	frames       map[cid][]any
+}
+
+// Walk streams the files in order and calls fn with every block from from to to. Reading a
+// file stops at the first block past to.
+func (c *CAR) Walk(ctx context.Context, from, to uint64, fn func(slot uint64, block *rpc.GetBlockResult) error) error {
+	for _, path := range c.config.Paths {
+		file, err := c.open(ctx, path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		err = c.read(ctx, file, from, to, fn)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// open opens a local file or starts downloading a remote one
+func (c *CAR) open(ctx context.Context, path string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		return os.Open(path)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.config.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+	return response.Body, nil
+}
+
+// read walks the sections of a CARv1 stream
+func (c *CAR) read(ctx context.Context, r io.Reader, from, to uint64, fn func(slot uint64, block *rpc.GetBlockResult) error) error {
+	buffered := bufio.NewReaderSize(r, 1<<20)
+	header, err := readSection(buffered)
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	decoded, err := decodeCBOR(header)
+	if err != nil {
+		return fmt.Errorf("failed to decode header: %w", err)
+	}
+	if fields, ok := decoded.(map[string]any); !ok || fields["version"] != uint64(1) {
+		return fmt.Errorf("unsupported CAR header %v", decoded)
+	}
+
+	reader := &carReader{from: from, to: to}
+	reader.reset()
+	for {
+		section, err := readSection(buffered)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		id, data, err := splitSection(section)
+		if err != nil {
+			return err
+		}
+		node, err := decodeCBOR(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode node: %w", err)
+		}
+
+		slot, block, err := reader.add(id, node)
+		if err != nil {
+			return err
+		}
+		if slot > to {
+			return nil
+		}
+		if block == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(slot, block); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *carReader) reset() {
+	r.transactions = make(map[cid]*carTransaction)
+	r.entries = make(map[cid]*carEntry)
+	r.frames = make(map[cid][]any)
+}
+
+// add records a node and returns the block it completes, if in range. Transactions are
+// kept only for blocks in range, and everything is dropped at every block node.
+func (r *carReader) add(id cid, node any) (uint64, *rpc.GetBlockResult, error) {
+	fields, ok := node.([]any)
+	if !ok || len(fields) == 0 {
+		return 0, nil, nil
+	}
+	kind, _ := fields[0].(uint64)
+	switch kind {
+	case kindTransaction:
+		if len(fields) < 4 {
+			return 0, nil, fmt.Errorf("transaction node has %d fields", len(fields))
+		}
+		slot, _ := fields[3].(uint64)
+		if slot < r.from || slot > r.to {
+			return 0, nil, nil
+		}
+		data, _ := fields[1].([]any)
+		meta, _ := fields[2].([]any)
+		tx := &carTransaction{data: data, meta: meta}
+		if len(fields) > 4 {
+			tx.index, _ = fields[4].(uint64)
+		}
+		r.transactions[id] = tx
+	case kindEntry:
+		if len(fields) < 4 {
+			return 0, nil, fmt.Errorf("entry node has %d fields", len(fields))
+		}
+		hash, _ := fields[2].([]byte)
+		links, _ := fields[3].([]any)
+		entry := &carEntry{hash: hash}
+		for _, link := range links {
+			if id, ok := link.(cid); ok {
+				entry.transactions = append(entry.transactions, id)
+			}
+		}
+		r.entries[id] = entry
+	case kindDataFrame:
+		r.frames[id] = fields
+	case kindBlock:
+		defer r.reset()
+		return r.block(fields)
+	}
+	return 0, nil, nil
+}
+
+// block assembles a block node's transactions in entry order
+func (r *carReader) block(fields []any) (uint64, *rpc.GetBlockResult, error) {
+	if len(fields) < 5 {
+		return 0, nil, fmt.Errorf("block node has %d fields", len(fields))
+	}
+	slot, _ := fields[1].(uint64)
+	if slot < r.from || slot > r.to {
+		return slot, nil, nil
+	}
+	block := &rpc.GetBlockResult{}
+	if meta, _ := fields[4].([]any); len(meta) >= 2 {
+		block.ParentSlot, _ = meta[0].(uint64)
+		if blockTime, ok := meta[1].(uint64); ok && blockTime > 0 {
+			seconds := solana.UnixTimeSeconds(blockTime)
+			block.BlockTime = &seconds
+		}
+		if len(meta) > 2 {
+			if height, ok := meta[2].(uint64); ok {
+				block.BlockHeight = &height
+			}
+		}
+	}
+
+	links, _ := fields[3].([]any)
+	for _, link := range links {
+		id, _ := link.(cid)
+		entry, ok := r.entries[id]
+		if !ok {
+			return 0, nil, fmt.Errorf("block %d references a missing entry", slot)
+		}
+		// the blockhash is the hash of the last entry
+		if len(entry.hash) == 32 {
+			block.Blockhash = solana.HashFromBytes(entry.hash)
+		}
+		for _, id := range entry.transactions {
+			tx, ok := r.transactions[id]
+			if !ok {
+				return 0, nil, fmt.Errorf("block %d references a missing transaction", slot)
+			}
+			withMeta, err := r.transaction(tx)
+			if err != nil {
+				return 0, nil, fmt.Errorf("failed to read transaction %d of block %d: %w", tx.index, slot, err)
+			}
+			block.Transactions = append(block.Transactions, withMeta)
+		}
+	}
+	return slot, block, nil
+}
+
+func (r *carReader) transaction(tx *carTransaction) (rpc.TransactionWithMeta, error) {
+	data, err := r.frameData(tx.data, 0)
+	if err != nil {
+		return rpc.TransactionWithMeta{}, fmt.Errorf("failed to read data: %w", err)
+	}
+	compressed, err := r.frameData(tx.meta, 0)
+	if err != nil {
+		return rpc.TransactionWithMeta{}, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	meta, err := convertMeta(compressed)
+	if err != nil {
+		return rpc.TransactionWithMeta{}, err
+	}
+	return rpc.TransactionWithMeta{Transaction: rpc.DataBytesOrJSONFromBytes(data), Meta: meta}, nil
+}
+
+// frameData returns the data of a data frame followed by the data of the frames it links,
+// depth first
+func (r *carReader) frameData(frame []any, depth int) ([]byte, error) {
+	if len(frame) < 5 {
+		return nil, fmt.Errorf("data frame has %d fields", len(frame))
+	}
+	if depth > 64 {
+		return nil, fmt.Errorf("data frames nested too deeply")
+	}
+	data, _ := frame[4].([]byte)
+	if len(frame) < 6 {
+		return data, nil
+	}
+	links, _ := frame[5].([]any)
+	for _, link := range links {
+		id, _ := link.(cid)
+		next, ok := r.frames[id]
+		if !ok {
+			return nil, fmt.Errorf("missing data frame")
+		}
+		more, err := r.frameData(next, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data[:len(data):len(data)], more...)
+	}
+	return data, nil
+}
+
+// readSection reads a varint length prefixed section
+func readSection(r *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if length > 1<<30 {
+		return nil, fmt.Errorf("section of %d bytes is too large", length)
+	}
+	section := make([]byte, length)
+	if _, err := io.ReadFull(r, section); err != nil {
+		return nil, fmt.Errorf("failed to read section: %w", io.ErrUnexpectedEOF)
+	}
+	return section, nil
+}
+
+// splitSection splits a section into its CID and node data
+func splitSection(section []byte) (cid, []byte, error) {
+	// CIDv0 is a bare sha2-256 multihash
+	if len(section) >= 34 && section[0] == 0x12 && section[1] == 0x20 {
+		return cid(section[:34]), section[34:], nil
+	}
+	pos := 0
+	// version, codec, multihash code and digest length
+	var length uint64
+	for range 4 {
+		value, n := binary.Uvarint(section[pos:])
+		if n <= 0 {
+			return "", nil, fmt.Errorf("invalid CID")
+		}
+		pos += n
+		length = value
+	}
+	if length > uint64(len(section)-pos) {
+		return "", nil, fmt.Errorf("invalid CID digest length %d", length)
+	}
+	pos += int(length)
+	return cid(section[:pos]), section[pos:], nil
+}
@@ -0,0 +1,162 @@
+package archive
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// cid is the raw binary form of a content identifier, comparable to key maps
+type cid string
+
+// cborDecoder decodes the DAG-CBOR subset old-faithful nodes are encoded with: integers,
+// byte and text strings, arrays, maps with text keys, CID links, booleans, null and floats
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// decodeCBOR decodes a single DAG-CBOR value
+func decodeCBOR(data []byte) (any, error) {
+	d := &cborDecoder{data: data}
+	value, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, fmt.Errorf("%d trailing bytes", len(data)-d.pos)
+	}
+	return value, nil
+}
+
+func (d *cborDecoder) value(depth int) (any, error) {
+	if depth > 32 {
+		return nil, fmt.Errorf("nested too deeply")
+	}
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	major, info := d.data[d.pos]>>5, d.data[d.pos]&0x1f
+	d.pos++
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		case 26:
+			bits, err := d.bytes(4)
+			if err != nil {
+				return nil, err
+			}
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(bits))), nil
+		case 27:
+			bits, err := d.bytes(8)
+			if err != nil {
+				return nil, err
+			}
+			return math.Float64frombits(binary.BigEndian.Uint64(bits)), nil
+		}
+		return nil, fmt.Errorf("unsupported simple value %d", info)
+	}
+
+	n, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case 0:
+		return n, nil
+	case 1:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("negative integer out of range")
+		}
+		return -1 - int64(n), nil
+	case 2:
+		return d.bytes(n)
+	case 3:
+		text, err := d.bytes(n)
+		return string(text), err
+	case 4:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("array of %d items exceeds the data", n)
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case 5:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("map of %d entries exceeds the data", n)
+		}
+		entries := make(map[string]any, n)
+		for range n {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			text, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key is %T, expected text", key)
+			}
+			if entries[text], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return entries, nil
+	case 6:
+		value, err := d.value(depth + 1)
+		if err != nil || n != 42 {
+			return value, err
+		}
+		raw, ok := value.([]byte)
+		if !ok || len(raw) == 0 || raw[0] != 0 {
+			return nil, fmt.Errorf("invalid link")
+		}
+		return cid(raw[1:]), nil
+	}
+	return nil, fmt.Errorf("unsupported major type %d", major)
+}
+
+// argument reads the integer argument of a data item
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, fmt.Errorf("unsupported additional information %d", info)
+	}
+	raw, err := d.bytes(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, b := range raw {
+		n = n<<8 | uint64(b)
+	}
+	return n, nil
+}
+
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	raw := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return raw, nil
+}
@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/klauspost/compress/zstd"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/soralabs/solana-toolkit/go/geyser"
+)
+
+// Compression methods prefixed to Bigtable cells, as a bincode u32 enum
+const (
+	compressionNone  = 0
+	compressionBzip2 = 1
+	compressionGzip  = 2
+	compressionZstd  = 3
+)
+
+// zstdDecoder decodes whole buffers and is safe for concurrent use
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+// decompress decodes a Bigtable cell, a compression method followed by the compressed data
+func decompress(cell []byte) ([]byte, error) {
+	if len(cell) < 4 {
+		return nil, fmt.Errorf("cell of %d bytes has no compression method", len(cell))
+	}
+	method, data := binary.LittleEndian.Uint32(cell), cell[4:]
+	switch method {
+	case compressionNone:
+		return data, nil
+	case compressionBzip2:
+		return io.ReadAll(bzip2.NewReader(bytes.NewReader(data)))
+	case compressionGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(reader)
+	case compressionZstd:
+		return zstdDecoder.DecodeAll(data, nil)
+	}
+	return nil, fmt.Errorf("unknown compression method %d", method)
+}
+
+// convertBlock converts a protobuf block, as stored in Bigtable, into the RPC representation
+func convertBlock(in *pb.ConfirmedBlock) (*rpc.GetBlockResult, error) {
+	block := &rpc.GetBlockResult{ParentSlot: in.GetParentSlot()}
+	var err error
+	if block.Blockhash, err = solana.HashFromBase58(in.GetBlockhash()); err != nil {
+		return nil, fmt.Errorf("invalid blockhash: %w", err)
+	}
+	if block.PreviousBlockhash, err = solana.HashFromBase58(in.GetPreviousBlockhash()); err != nil {
+		return nil, fmt.Errorf("invalid previous blockhash: %w", err)
+	}
+	if in.GetBlockTime() != nil {
+		blockTime := solana.UnixTimeSeconds(in.GetBlockTime().GetTimestamp())
+		block.BlockTime = &blockTime
+	}
+	if in.GetBlockHeight() != nil {
+		height := in.GetBlockHeight().GetBlockHeight()
+		block.BlockHeight = &height
+	}
+
+	for i, confirmed := range in.GetTransactions() {
+		tx, err := geyser.ConvertTransaction(confirmed.GetTransaction())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert transaction %d: %w", i, err)
+		}
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode transaction %d: %w", i, err)
+		}
+		withMeta := rpc.TransactionWithMeta{Transaction: rpc.DataBytesOrJSONFromBytes(raw)}
+		if confirmed.GetMeta() != nil {
+			if withMeta.Meta, err = geyser.ConvertMeta(confirmed.GetMeta()); err != nil {
+				return nil, fmt.Errorf("failed to convert metadata of transaction %d: %w", i, err)
+			}
+		}
+		block.Transactions = append(block.Transactions, withMeta)
+	}
+	return block, nil
+}
+
+// convertMeta decodes zstd compressed protobuf metadata, as stored by old-faithful. Epochs
+// whose metadata is in the legacy bincode layout are not supported.
+func convertMeta(compressed []byte) (*rpc.TransactionMeta, error) {
+	if len(compressed) == 0 {
+		return nil, nil
+	}
+	data, err := zstdDecoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress metadata: %w", err)
+	}
+	var meta pb.TransactionStatusMeta
+	if err := proto.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata, legacy bincode metadata is not supported: %w", err)
+	}
+	return geyser.ConvertMeta(&meta)
+}
@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Source walks the confirmed blocks of a slot range from a ledger archive. Blocks are in
+// the RPC representation with binary transactions, as returned by getBlock, so they parse
+// with tx_parser.ParseBlock.
+type Source interface {
+	// Walk calls fn with every block from from to to, inclusive, in slot order. An error
+	// returned by fn stops the walk and is returned.
+	Walk(ctx context.Context, from, to uint64, fn func(slot uint64, block *rpc.GetBlockResult) error) error
+}
+
+var (
+	_ Source = (*Bigtable)(nil)
+	_ Source = (*CAR)(nil)
+)
+
+// BigtableRows reads rows of the ledger tables Solana validators upload to Bigtable,
+// implemented by an adapter over a Bigtable client such as cloud.google.com/go/bigtable
+type BigtableRows interface {
+	// ReadRow returns the cells of a row by column qualifier, nil if the row does not exist
+	ReadRow(ctx context.Context, table, key string) (map[string][]byte, error)
+
+	// RowKeys returns up to limit row keys of the table, in order, from start inclusive
+	RowKeys(ctx context.Context, table, start string, limit int) ([]string, error)
+}
+
+// BigtableConfig controls the Bigtable reader
+type BigtableConfig struct {
+	// Table holding the blocks, defaults to blocks
+	Table string
+
+	// PageSize is the number of row keys listed at once while walking, defaults to 1000
+	PageSize int
+}
+
+// CARConfig controls the old-faithful CAR reader
+type CARConfig struct {
+	// Paths are the epoch CAR files to walk in order, local paths or http(s) URLs such as
+	// https://files.old-faithful.net/600/epoch-600.car
+	Paths []string
+
+	// HTTPClient downloads remote files, defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
@@ -0,0 +1,298 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// MAX_SIGNATURES_PAGE is the largest page getSignaturesForAddress returns
+const MAX_SIGNATURES_PAGE = 1000
+
+// blocksPerRequest is the slot range listed with a single getBlocks call
+const blocksPerRequest = 1000
+
+// Backfiller walks historical transactions and parses them
+type Backfiller struct {
+	rpcClient *rpc.Client
+	config    Config
+}
+
+// New creates a new backfiller
+func New(rpcClient *rpc.Client, config Config) *Backfiller {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 8
+	}
+	if config.PageSize <= 0 || config.PageSize > MAX_SIGNATURES_PAGE {
+		config.PageSize = MAX_SIGNATURES_PAGE
+	}
+	if config.Commitment == "" || config.Commitment == rpc.CommitmentProcessed {
+		config.Commitment = rpc.CommitmentFinalized
+	}
+
+	return &Backfiller{
+		rpcClient: rpcClient,
+		config:    config,
+	}
+}
+
+// Run walks the configured range, resuming from the saved checkpoint, and passes every
+// result to handle in order. Progress is checkpointed after each page of signatures or
+// window of blocks, so results of the page being handled when the process stops are
+// delivered again on resume. Run stops at the first error returned by handle.
+func (b *Backfiller) Run(ctx context.Context, handle func(result *Result) error) error {
+	if b.config.ToSlot > 0 && b.config.ToSlot < b.config.FromSlot {
+		return fmt.Errorf("to slot %d is before from slot %d", b.config.ToSlot, b.config.FromSlot)
+	}
+
+	checkpoint := &Checkpoint{}
+	if b.config.Checkpoints != nil {
+		saved, err := b.config.Checkpoints.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		if saved != nil {
+			checkpoint = saved
+		}
+	}
+	if checkpoint.Done {
+		return nil
+	}
+
+	if b.config.Address.IsZero() && b.config.Archive != nil {
+		return b.walkArchive(ctx, checkpoint, handle)
+	}
+	if b.config.Address.IsZero() {
+		return b.walkBlocks(ctx, checkpoint, handle)
+	}
+	return b.walkSignatures(ctx, checkpoint, handle)
+}
+
+// walkSignatures pages backwards through the signatures of the address
+func (b *Backfiller) walkSignatures(ctx context.Context, checkpoint *Checkpoint, handle func(result *Result) error) error {
+	for !checkpoint.Done {
+		limit := b.config.PageSize
+		page, err := b.rpcClient.GetSignaturesForAddressWithOpts(ctx, b.config.Address, &rpc.GetSignaturesForAddressOpts{
+			Limit:      &limit,
+			Before:     checkpoint.Before,
+			Commitment: b.config.Commitment,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get signatures: %w", err)
+		}
+
+		// A short page means the start of the account history was reached
+		reachedStart := len(page) < limit
+		var selected []*rpc.TransactionSignature
+		for _, signature := range page {
+			if signature.Slot < b.config.FromSlot {
+				reachedStart = true
+				break
+			}
+			if b.config.ToSlot > 0 && signature.Slot > b.config.ToSlot {
+				continue
+			}
+			if b.config.SkipFailed && signature.Err != nil {
+				continue
+			}
+			selected = append(selected, signature)
+		}
+
+		results := make([]*Result, len(selected))
+		b.parallel(len(selected), func(i int) {
+			results[i] = b.fetchTransaction(ctx, selected[i].Signature, selected[i].Slot)
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for _, result := range results {
+			if err := handle(result); err != nil {
+				return err
+			}
+		}
+
+		if len(page) > 0 {
+			checkpoint.Before = page[len(page)-1].Signature
+		}
+		checkpoint.Processed += uint64(len(results))
+		checkpoint.Done = reachedStart
+		if err := b.save(checkpoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkBlocks fetches every confirmed block in the slot range
+func (b *Backfiller) walkBlocks(ctx context.Context, checkpoint *Checkpoint, handle func(result *Result) error) error {
+	end := b.config.ToSlot
+	if end == 0 {
+		latest, err := b.rpcClient.GetSlot(ctx, b.config.Commitment)
+		if err != nil {
+			return fmt.Errorf("failed to get latest slot: %w", err)
+		}
+		end = latest
+	}
+
+	next := max(b.config.FromSlot, checkpoint.NextSlot)
+	for next <= end {
+		last := min(next+blocksPerRequest-1, end)
+		slots, err := b.rpcClient.GetBlocks(ctx, next, &last, b.config.Commitment)
+		if err != nil {
+			return fmt.Errorf("failed to get blocks %d-%d: %w", next, last, err)
+		}
+
+		for start := 0; start < len(slots); start += b.config.Concurrency {
+			window := slots[start:min(start+b.config.Concurrency, len(slots))]
+
+			blocks := make([][]*Result, len(window))
+			b.parallel(len(window), func(i int) {
+				blocks[i] = b.fetchBlock(ctx, window[i])
+			})
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			for _, results := range blocks {
+				for _, result := range results {
+					if err := handle(result); err != nil {
+						return err
+					}
+				}
+				checkpoint.Processed += uint64(len(results))
+			}
+
+			checkpoint.NextSlot = window[len(window)-1] + 1
+			if err := b.save(checkpoint); err != nil {
+				return err
+			}
+		}
+
+		next = last + 1
+		checkpoint.NextSlot = next
+	}
+
+	checkpoint.Done = true
+	return b.save(checkpoint)
+}
+
+// walkArchive reads every block in the slot range from the archive, checkpointing after
+// each block
+func (b *Backfiller) walkArchive(ctx context.Context, checkpoint *Checkpoint, handle func(result *Result) error) error {
+	end := b.config.ToSlot
+	if end == 0 {
+		end = math.MaxUint64
+	}
+
+	next := max(b.config.FromSlot, checkpoint.NextSlot)
+	err := b.config.Archive.Walk(ctx, next, end, func(slot uint64, block *rpc.GetBlockResult) error {
+		results := b.parseBlock(slot, block)
+		for _, result := range results {
+			if err := handle(result); err != nil {
+				return err
+			}
+		}
+		checkpoint.Processed += uint64(len(results))
+		checkpoint.NextSlot = slot + 1
+		return b.save(checkpoint)
+	})
+	if err != nil {
+		return err
+	}
+
+	checkpoint.Done = true
+	return b.save(checkpoint)
+}
+
+// fetchTransaction loads and parses a single transaction
+func (b *Backfiller) fetchTransaction(ctx context.Context, signature solana.Signature, slot uint64) *Result {
+	result := &Result{Signature: signature, Slot: slot}
+
+	maxVersion := uint64(0)
+	tx, err := b.rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     b.config.Commitment,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get transaction: %w", err)
+		return result
+	}
+
+	parser, err := tx_parser.NewWithOptions(tx, b.config.ParseOptions)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create parser: %w", err)
+		return result
+	}
+	result.Transaction, result.Err = parser.Parse()
+	return result
+}
+
+// fetchBlock loads and parses a block, returning one result per transaction
+func (b *Backfiller) fetchBlock(ctx context.Context, slot uint64) []*Result {
+	maxVersion := uint64(0)
+	rewards := false
+	block, err := b.rpcClient.GetBlockWithOpts(ctx, slot, &rpc.GetBlockOpts{
+		Encoding:                       solana.EncodingBase64,
+		TransactionDetails:             rpc.TransactionDetailsFull,
+		Rewards:                        &rewards,
+		Commitment:                     b.config.Commitment,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return []*Result{{Slot: slot, Err: fmt.Errorf("failed to get block %d: %w", slot, err)}}
+	}
+	return b.parseBlock(slot, block)
+}
+
+// parseBlock parses a block, returning one result per transaction
+func (b *Backfiller) parseBlock(slot uint64, block *rpc.GetBlockResult) []*Result {
+	parsed, err := tx_parser.ParseBlockWithOptions(block, tx_parser.BlockOptions{
+		ParseOptions: b.config.ParseOptions,
+		Slot:         slot,
+		SkipFailed:   b.config.SkipFailed,
+		SkipVotes:    true,
+	})
+	if err != nil {
+		return []*Result{{Slot: slot, Err: fmt.Errorf("failed to parse block %d: %w", slot, err)}}
+	}
+
+	results := make([]*Result, 0, len(parsed.Results))
+	for _, tx := range parsed.Results {
+		results = append(results, &Result{Signature: tx.Signature, Slot: slot, Transaction: tx.Transaction, Err: tx.Err})
+	}
+	return results
+}
+
+// parallel calls fn for every index with at most Concurrency calls in flight
+func (b *Backfiller) parallel(n int, fn func(i int)) {
+	semaphore := make(chan struct{}, b.config.Concurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// save persists the checkpoint if a store is configured
+func (b *Backfiller) save(checkpoint *Checkpoint) error {
+	if b.config.Checkpoints == nil {
+		return nil
+	}
+	if err := b.config.Checkpoints.Save(checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
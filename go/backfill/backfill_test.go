@@ -0,0 +1,288 @@
+package backfill
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// testChain serves a chain with one SOL transfer per slot in [first, last]
+type testChain struct {
+	first, last uint64
+	keys        []solana.PublicKey
+}
+
+func newTestChain(first, last uint64) *testChain {
+	return &testChain{
+		first: first,
+		last:  last,
+		keys:  []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.SystemProgramID},
+	}
+}
+
+func slotSignature(slot uint64) solana.Signature {
+	var signature solana.Signature
+	binary.LittleEndian.PutUint64(signature[:], slot)
+	return signature
+}
+
+// encodedTransaction returns the transaction of the slot, transferring slot lamports
+func (c *testChain) encodedTransaction(t *testing.T, slot uint64) string {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], 2)
+	binary.LittleEndian.PutUint64(data[4:12], slot)
+
+	tx := &solana.Transaction{
+		Signatures: []solana.Signature{slotSignature(slot)},
+		Message: solana.Message{
+			AccountKeys:  c.keys,
+			Header:       solana.MessageHeader{NumRequiredSignatures: 1},
+			Instructions: []solana.CompiledInstruction{{ProgramIDIndex: 2, Accounts: []uint16{0, 1}, Data: data}},
+		},
+	}
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	return encoded
+}
+
+func (c *testChain) serve(t *testing.T) *rpc.Client {
+	t.Helper()
+
+	const meta = `{"fee":5000,"preBalances":[],"postBalances":[]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			ID     any               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		var result string
+		switch request.Method {
+		case "getSlot":
+			result = fmt.Sprint(c.last)
+		case "getBlocks":
+			var start, end uint64
+			json.Unmarshal(request.Params[0], &start)
+			json.Unmarshal(request.Params[1], &end)
+			var slots []string
+			for slot := max(start, c.first); slot <= min(end, c.last); slot++ {
+				slots = append(slots, fmt.Sprint(slot))
+			}
+			result = "[" + strings.Join(slots, ",") + "]"
+		case "getBlock":
+			var slot uint64
+			json.Unmarshal(request.Params[0], &slot)
+			result = fmt.Sprintf(`{"parentSlot":%d,"transactions":[{"transaction":[%q,"base64"],"meta":%s}]}`, slot-1, c.encodedTransaction(t, slot), meta)
+		case "getSignaturesForAddress":
+			var opts struct {
+				Limit  int              `json:"limit"`
+				Before solana.Signature `json:"before"`
+			}
+			json.Unmarshal(request.Params[1], &opts)
+			slot := c.last
+			if !opts.Before.IsZero() {
+				slot = binary.LittleEndian.Uint64(opts.Before[:]) - 1
+			}
+			var signatures []string
+			for ; slot >= c.first && len(signatures) < opts.Limit; slot-- {
+				signatures = append(signatures, fmt.Sprintf(`{"signature":%q,"slot":%d,"err":null}`, slotSignature(slot), slot))
+			}
+			result = "[" + strings.Join(signatures, ",") + "]"
+		case "getTransaction":
+			var signature solana.Signature
+			json.Unmarshal(request.Params[0], &signature)
+			slot := binary.LittleEndian.Uint64(signature[:])
+			result = fmt.Sprintf(`{"slot":%d,"transaction":[%q,"base64"],"meta":%s}`, slot, c.encodedTransaction(t, slot), meta)
+		default:
+			t.Errorf("unexpected method %s", request.Method)
+		}
+
+		id, _ := json.Marshal(request.ID)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, id, result)
+	}))
+	t.Cleanup(server.Close)
+
+	return rpc.New(server.URL)
+}
+
+// collect runs the backfill and returns the slots of the handled results
+func collect(t *testing.T, backfiller *Backfiller) []uint64 {
+	t.Helper()
+
+	var slots []uint64
+	err := backfiller.Run(context.Background(), func(result *Result) error {
+		if result.Err != nil {
+			return result.Err
+		}
+		if len(result.Transaction.Transfers) != 1 || result.Transaction.Transfers[0].Amount != result.Slot {
+			t.Errorf("unexpected transaction at slot %d: %+v", result.Slot, result.Transaction)
+		}
+		slots = append(slots, result.Slot)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	return slots
+}
+
+func TestBackfillSignatures(t *testing.T) {
+	chain := newTestChain(1, 20)
+	backfiller := New(chain.serve(t), Config{
+		Address:  chain.keys[0],
+		FromSlot: 5,
+		ToSlot:   12,
+		PageSize: 3,
+	})
+
+	slots := collect(t, backfiller)
+	if fmt.Sprint(slots) != "[12 11 10 9 8 7 6 5]" {
+		t.Errorf("expected slots 12 down to 5, got %v", slots)
+	}
+}
+
+func TestBackfillBlocks(t *testing.T) {
+	chain := newTestChain(100, 110)
+	backfiller := New(chain.serve(t), Config{FromSlot: 103, Concurrency: 2})
+
+	slots := collect(t, backfiller)
+	if fmt.Sprint(slots) != "[103 104 105 106 107 108 109 110]" {
+		t.Errorf("expected slots 103 to 110, got %v", slots)
+	}
+}
+
+// testArchive serves the chain's blocks from memory
+type testArchive struct {
+	t     *testing.T
+	chain *testChain
+}
+
+func (a *testArchive) Walk(ctx context.Context, from, to uint64, fn func(slot uint64, block *rpc.GetBlockResult) error) error {
+	for slot := max(from, a.chain.first); slot <= min(to, a.chain.last); slot++ {
+		data, _ := base64.StdEncoding.DecodeString(a.chain.encodedTransaction(a.t, slot))
+		block := &rpc.GetBlockResult{Transactions: []rpc.TransactionWithMeta{{
+			Transaction: rpc.DataBytesOrJSONFromBytes(data),
+			Meta:        &rpc.TransactionMeta{PreBalances: []uint64{slot, 0, 1}, PostBalances: []uint64{0, slot, 1}},
+		}}}
+		if err := fn(slot, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestParseBlockKeepsOrder(t *testing.T) {
+	chain := newTestChain(1, 5)
+	block := &rpc.GetBlockResult{}
+	for amount := range uint64(5) {
+		data, _ := base64.StdEncoding.DecodeString(chain.encodedTransaction(t, amount+1))
+		if amount == 1 || amount == 2 {
+			data = []byte{1, 2, 3} // not a transaction, so without a signature
+		}
+		block.Transactions = append(block.Transactions, rpc.TransactionWithMeta{
+			Transaction: rpc.DataBytesOrJSONFromBytes(data),
+			Meta:        &rpc.TransactionMeta{PreBalances: []uint64{amount + 1, 0, 1}, PostBalances: []uint64{0, amount + 1, 1}},
+		})
+	}
+
+	backfiller := New(nil, Config{})
+	for range 10 {
+		var order []string
+		for _, result := range backfiller.parseBlock(7, block) {
+			if result.Err != nil {
+				order = append(order, "error")
+				continue
+			}
+			order = append(order, fmt.Sprint(result.Transaction.Transfers[0].Amount))
+		}
+		if fmt.Sprint(order) != "[1 error error 4 5]" {
+			t.Fatalf("expected the results in block order, got %v", order)
+		}
+	}
+}
+
+func TestBackfillArchive(t *testing.T) {
+	chain := newTestChain(100, 110)
+	store := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"))
+	backfiller := New(nil, Config{FromSlot: 105, Archive: &testArchive{t, chain}, Checkpoints: store})
+
+	slots := collect(t, backfiller)
+	if fmt.Sprint(slots) != "[105 106 107 108 109 110]" {
+		t.Errorf("expected slots 105 to 110 from the archive, got %v", slots)
+	}
+	if checkpoint, _ := store.Load(); !checkpoint.Done || checkpoint.NextSlot != 111 || checkpoint.Processed != 6 {
+		t.Errorf("unexpected checkpoint %+v", checkpoint)
+	}
+}
+
+func TestBackfillResumesFromCheckpoint(t *testing.T) {
+	chain := newTestChain(1, 10)
+	client := chain.serve(t)
+	store := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"))
+	config := Config{Address: chain.keys[0], PageSize: 2, Checkpoints: store}
+
+	// Crash while handling the second page
+	crash := errors.New("crash")
+	var handled []uint64
+	err := New(client, config).Run(context.Background(), func(result *Result) error {
+		if result.Slot == 7 {
+			return crash
+		}
+		handled = append(handled, result.Slot)
+		return nil
+	})
+	if !errors.Is(err, crash) {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+	if fmt.Sprint(handled) != "[10 9 8]" {
+		t.Fatalf("unexpected slots before the crash: %v", handled)
+	}
+
+	checkpoint, err := store.Load()
+	if err != nil || checkpoint == nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if checkpoint.Before != slotSignature(9) || checkpoint.Processed != 2 || checkpoint.Done {
+		t.Errorf("unexpected checkpoint: %+v", checkpoint)
+	}
+
+	// The interrupted page is delivered again, then the walk continues
+	slots := collect(t, New(client, config))
+	if fmt.Sprint(slots) != "[8 7 6 5 4 3 2 1]" {
+		t.Errorf("unexpected slots after resuming: %v", slots)
+	}
+
+	checkpoint, _ = store.Load()
+	if !checkpoint.Done || checkpoint.Processed != 10 {
+		t.Errorf("expected a finished checkpoint, got %+v", checkpoint)
+	}
+	if slots := collect(t, New(client, config)); len(slots) != 0 {
+		t.Errorf("expected a finished backfill to do nothing, got %v", slots)
+	}
+}
+
+func TestFileCheckpointMissing(t *testing.T) {
+	checkpoint, err := NewFileCheckpoint(filepath.Join(t.TempDir(), "missing.json")).Load()
+	if err != nil || checkpoint != nil {
+		t.Errorf("expected no checkpoint, got %+v, %v", checkpoint, err)
+	}
+}
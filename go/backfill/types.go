@@ -0,0 +1,74 @@
+package backfill
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/archive"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Config controls what the backfill walks
+type Config struct {
+	// Address walks the transactions of this account with getSignaturesForAddress, newest
+	// first. Leave it zero to walk every block between FromSlot and ToSlot instead.
+	Address solana.PublicKey
+
+	// FromSlot and ToSlot bound the walk, inclusive. ToSlot 0 means the latest slot.
+	FromSlot uint64
+	ToSlot   uint64
+
+	// Concurrency is the number of transactions or blocks fetched at once, defaults to 8
+	Concurrency int
+
+	// PageSize is the number of signatures requested per page, at most and by default 1000
+	PageSize int
+
+	// Commitment defaults to finalized, processed is not supported by the history methods
+	Commitment rpc.CommitmentType
+
+	// SkipFailed skips transactions that failed on chain
+	SkipFailed bool
+
+	// ParseOptions are passed to the transaction parser
+	ParseOptions tx_parser.ParseOptions
+
+	// Archive reads the blocks of a block walk from a ledger archive instead of getBlock,
+	// e.g. old-faithful CAR files for slots older than the RPC provider keeps. ToSlot 0
+	// walks to the end of the archive.
+	Archive archive.Source
+
+	// Checkpoints persists progress so an interrupted backfill resumes where it stopped, optional
+	Checkpoints CheckpointStore
+}
+
+// Result is a parsed transaction produced by the backfill
+type Result struct {
+	Signature   solana.Signature
+	Slot        uint64
+	Transaction *tx_parser.ParsedTransaction
+	Err         error // set if the transaction or its block could not be fetched or parsed
+}
+
+// Checkpoint records how far a backfill got. Everything before the cursor has been
+// passed to the handler.
+type Checkpoint struct {
+	// Before is the oldest signature handled when walking an address
+	Before solana.Signature `json:"before"`
+
+	// NextSlot is the next slot to fetch when walking blocks
+	NextSlot uint64 `json:"next_slot"`
+
+	// Processed is the number of results handled so far
+	Processed uint64 `json:"processed"`
+
+	// Done is set once the whole range has been walked
+	Done bool `json:"done"`
+}
+
+// CheckpointStore loads and saves backfill progress
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if the backfill has not started
+	Load() (*Checkpoint, error)
+	Save(checkpoint *Checkpoint) error
+}
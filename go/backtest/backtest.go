@@ -0,0 +1,318 @@
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/candles"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Backtest replays events through a strategy, filling its orders against a simulated
+// account. It is not safe for concurrent use.
+type Backtest struct {
+	strategy Strategy
+	config   Config
+
+	cash      float64
+	positions map[solana.PublicKey]*Position
+	fills     []*Fill
+	curve     []Point
+
+	realized float64
+	fees     float64
+	slippage float64
+	wins     int
+	losses   int
+
+	peak        float64
+	peakAt      time.Time
+	drawdown    float64
+	underwater  time.Duration
+	below       bool
+	start, last time.Time
+}
+
+// New creates a backtest of the strategy
+func New(strategy Strategy, config Config) (*Backtest, error) {
+	if strategy == nil {
+		return nil, fmt.Errorf("strategy is required")
+	}
+	if config.Capital <= 0 {
+		return nil, fmt.Errorf("capital must be positive")
+	}
+	if config.TradeFee < 0 {
+		return nil, fmt.Errorf("trade fee must not be negative")
+	}
+	if config.Fills == nil {
+		config.Fills = Fixed{}
+	}
+
+	return &Backtest{
+		strategy:  strategy,
+		config:    config,
+		cash:      config.Capital,
+		positions: make(map[solana.PublicKey]*Position),
+		peak:      config.Capital,
+	}, nil
+}
+
+// Run replays the events in time order and returns the result
+func (b *Backtest) Run(events []Event) (*Result, error) {
+	ordered := make([]*Event, len(events))
+	for i := range events {
+		ordered[i] = &events[i]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Time.Before(ordered[j].Time) })
+
+	for _, event := range ordered {
+		if _, err := b.Step(event); err != nil {
+			return nil, err
+		}
+	}
+	return b.Result(), nil
+}
+
+// Step marks the event's token at its price, fills the orders the strategy places on it
+// and returns the fills. Events without a price are ignored.
+func (b *Backtest) Step(event *Event) ([]*Fill, error) {
+	if event.Price <= 0 {
+		return nil, nil
+	}
+	if b.start.IsZero() {
+		b.start, b.peakAt = event.Time, event.Time
+	}
+	b.last = event.Time
+	b.position(event.Mint).Price = event.Price
+
+	var fills []*Fill
+	for _, order := range b.strategy.OnEvent(b, event) {
+		fill, err := b.fill(event.Time, order)
+		if err != nil {
+			return fills, fmt.Errorf("failed to fill %s of %s at %s: %w", order.Side, order.Mint, event.Time, err)
+		}
+		if fill != nil {
+			fills = append(fills, fill)
+		}
+	}
+
+	b.mark(event.Time)
+	return fills, nil
+}
+
+// Cash returns the cash held
+func (b *Backtest) Cash() float64 {
+	return b.cash
+}
+
+// Position returns the position in the token, nil if none was ever priced
+func (b *Backtest) Position(mint solana.PublicKey) *Position {
+	position, ok := b.positions[mint]
+	if !ok {
+		return nil
+	}
+	copied := *position
+	copied.Unrealized = copied.Tokens*copied.Price - copied.CostBasis
+	return &copied
+}
+
+// Price returns the latest price of the token, 0 if none was seen
+func (b *Backtest) Price(mint solana.PublicKey) float64 {
+	if position, ok := b.positions[mint]; ok {
+		return position.Price
+	}
+	return 0
+}
+
+// Equity returns the cash plus the positions at their latest price
+func (b *Backtest) Equity() float64 {
+	equity := b.cash
+	for _, position := range b.positions {
+		equity += position.Tokens * position.Price
+	}
+	return equity
+}
+
+// Result reports the performance so far
+func (b *Backtest) Result() *Result {
+	equity := b.Equity()
+	result := &Result{
+		Start:               b.start,
+		End:                 b.last,
+		Capital:             b.config.Capital,
+		Equity:              equity,
+		PnL:                 equity - b.config.Capital,
+		Return:              (equity - b.config.Capital) / b.config.Capital,
+		Realized:            b.realized,
+		Fees:                b.fees,
+		Slippage:            b.slippage,
+		Trades:              len(b.fills),
+		Wins:                b.wins,
+		Losses:              b.losses,
+		MaxDrawdown:         b.drawdown,
+		MaxDrawdownDuration: max(b.underwater, b.underwaterFor(b.last)),
+		Fills:               b.fills,
+		Curve:               b.curve,
+	}
+	for mint, position := range b.positions {
+		if position.Tokens == 0 {
+			continue
+		}
+		held := b.Position(mint)
+		result.Unrealized += held.Unrealized
+		result.Positions = append(result.Positions, held)
+	}
+	sort.Slice(result.Positions, func(i, j int) bool {
+		return result.Positions[i].Mint.String() < result.Positions[j].Mint.String()
+	})
+	return result
+}
+
+func (b *Backtest) fill(at time.Time, order Order) (*Fill, error) {
+	if order.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	position, ok := b.positions[order.Mint]
+	if !ok || position.Price == 0 {
+		return nil, fmt.Errorf("no price")
+	}
+	model := b.config.Fills
+	if override, ok := b.config.Models[order.Mint]; ok {
+		model = override
+	}
+	price, tradeFee := position.Price, b.config.TradeFee
+
+	fill := &Fill{Time: at, Mint: order.Mint, Side: order.Side}
+	switch order.Side {
+	case SideBuy:
+		amount := min(order.Amount, b.cash-tradeFee)
+		if amount <= 0 {
+			return nil, nil
+		}
+		tokens, cash, fee := model.Fill(SideBuy, price, amount)
+		fill.Tokens, fill.Cash, fill.Fee = tokens, cash+tradeFee, fee+tradeFee
+		fill.Slippage = cash - fee - tokens*price
+		b.cash -= fill.Cash
+		position.Tokens += tokens
+		position.CostBasis += fill.Cash
+	case SideSell:
+		amount := min(order.Amount, position.Tokens)
+		if amount <= 0 {
+			return nil, nil
+		}
+		tokens, cash, fee := model.Fill(SideSell, price, amount)
+		fill.Tokens, fill.Cash, fill.Fee = tokens, cash-tradeFee, fee+tradeFee
+		fill.Slippage = tokens*price - fee - cash
+		cost := position.CostBasis * tokens / position.Tokens
+		fill.Realized = fill.Cash - cost
+		b.cash += fill.Cash
+		position.Tokens -= tokens
+		position.CostBasis -= cost
+		if position.Tokens <= 0 {
+			position.Tokens, position.CostBasis = 0, 0
+		}
+		b.realized += fill.Realized
+		if fill.Realized > 0 {
+			b.wins++
+		} else if fill.Realized < 0 {
+			b.losses++
+		}
+	default:
+		return nil, fmt.Errorf("unknown side %q", order.Side)
+	}
+	if fill.Tokens > 0 {
+		fill.Price = fill.Cash / fill.Tokens
+	}
+	b.fees += fill.Fee
+	b.slippage += fill.Slippage
+	b.fills = append(b.fills, fill)
+	return fill, nil
+}
+
+// mark records the equity and updates the drawdown
+func (b *Backtest) mark(at time.Time) {
+	equity := b.Equity()
+	b.curve = append(b.curve, Point{Time: at, Equity: equity})
+	if equity >= b.peak {
+		b.underwater = max(b.underwater, b.underwaterFor(at))
+		b.peak, b.peakAt, b.below = equity, at, false
+		return
+	}
+	b.below = true
+	b.drawdown = max(b.drawdown, (b.peak-equity)/b.peak)
+}
+
+// underwaterFor returns how long the equity has been below its peak at the given time
+func (b *Backtest) underwaterFor(at time.Time) time.Duration {
+	if !b.below {
+		return 0
+	}
+	return at.Sub(b.peakAt)
+}
+
+func (b *Backtest) position(mint solana.PublicKey) *Position {
+	position, ok := b.positions[mint]
+	if !ok {
+		position = &Position{Mint: mint}
+		b.positions[mint] = position
+	}
+	return position
+}
+
+// CandleEvents returns an event at the close of every per-mint candle, priced at its
+// close. Per-pool candles are skipped as they are priced in the pool's quote token.
+func CandleEvents(closed []candles.Candle) []Event {
+	var events []Event
+	for i := range closed {
+		candle := &closed[i]
+		if candle.Mint.IsZero() {
+			continue
+		}
+		events = append(events, Event{
+			Time:   candle.Start.Add(candle.Interval),
+			Mint:   candle.Mint,
+			Price:  candle.Close,
+			Candle: candle,
+		})
+	}
+	return events
+}
+
+// SwapEvents returns an event for every swap priced by the pricing package, at the swap's
+// USD or SOL price of its base token. Swaps without a price or a time are skipped.
+func SwapEvents(txs []*tx_parser.ParsedTransaction, denomination candles.Denomination) []Event {
+	var events []Event
+	for _, tx := range txs {
+		var at time.Time
+		if tx.BlockTime != nil {
+			at = tx.BlockTime.Time()
+		}
+		for _, swap := range tx.Swaps {
+			if swap.Price == nil {
+				continue
+			}
+			swapAt := at
+			if !swap.Timestamp.IsZero() {
+				swapAt = swap.Timestamp
+			}
+			price := swap.Price.PriceUSD
+			if denomination == candles.DenominationSOL {
+				price = swap.Price.PriceSOL
+			}
+			if swapAt.IsZero() || price <= 0 {
+				continue
+			}
+			events = append(events, Event{
+				Time:  swapAt,
+				Slot:  tx.Slot,
+				Mint:  swap.Price.BaseMint,
+				Price: price,
+				Swap:  swap,
+			})
+		}
+	}
+	return events
+}
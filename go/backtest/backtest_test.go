@@ -0,0 +1,163 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/candles"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+var (
+	token = solana.NewWallet().PublicKey()
+	start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// events prices the token at 1, 2, 0.5 and 1.5 a minute apart, out of order
+func events() []Event {
+	prices := []float64{1, 2, 0.5, 1.5}
+	var events []Event
+	for i := len(prices) - 1; i >= 0; i-- {
+		events = append(events, Event{Time: start.Add(time.Duration(i) * time.Minute), Mint: token, Price: prices[i]})
+	}
+	return events
+}
+
+func TestRun(t *testing.T) {
+	// buys 500 on the first event and sells half once the price doubles
+	strategy := StrategyFunc(func(b *Backtest, event *Event) []Order {
+		switch position := b.Position(token); {
+		case position.Tokens == 0 && b.Cash() == 1_000:
+			return []Order{{Mint: token, Side: SideBuy, Amount: 500}}
+		case event.Price == 2:
+			return []Order{{Mint: token, Side: SideSell, Amount: position.Tokens / 2}}
+		}
+		return nil
+	})
+	b, err := New(strategy, Config{Capital: 1_000})
+	if err != nil {
+		t.Fatalf("failed to create backtest: %v", err)
+	}
+	result, err := b.Run(events())
+	if err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+
+	if result.Trades != 2 || result.Wins != 1 || result.Losses != 0 || len(result.Curve) != 4 {
+		t.Fatalf("unexpected trades %+v", result)
+	}
+	if !near(result.Equity, 1_375) || !near(result.PnL, 375) || !near(result.Return, 0.375) {
+		t.Errorf("unexpected equity %f, pnl %f, return %f", result.Equity, result.PnL, result.Return)
+	}
+	if !near(result.Realized, 250) || !near(result.Unrealized, 125) {
+		t.Errorf("unexpected realized %f and unrealized %f", result.Realized, result.Unrealized)
+	}
+	// the peak of 1500 fell to 1125 and had not recovered by the end
+	if !near(result.MaxDrawdown, 0.25) || result.MaxDrawdownDuration != 2*time.Minute {
+		t.Errorf("unexpected drawdown %f for %s", result.MaxDrawdown, result.MaxDrawdownDuration)
+	}
+	if len(result.Positions) != 1 || !near(result.Positions[0].Tokens, 250) || result.Positions[0].Price != 1.5 {
+		t.Errorf("unexpected positions %+v", result.Positions)
+	}
+	if !result.Start.Equal(start) || !result.End.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("unexpected range %s to %s", result.Start, result.End)
+	}
+}
+
+func TestOrders(t *testing.T) {
+	other := solana.NewWallet().PublicKey()
+	orders := [][]Order{
+		{{Mint: token, Side: SideBuy, Amount: 5_000}, {Mint: token, Side: SideSell, Amount: 5_000}},
+		{{Mint: token, Side: SideBuy}},
+		{{Mint: token, Side: "short", Amount: 1}},
+		{{Mint: other, Side: SideBuy, Amount: 1}},
+	}
+	for i, placed := range orders {
+		b, _ := New(StrategyFunc(func(*Backtest, *Event) []Order { return placed }), Config{Capital: 100, TradeFee: 1})
+		fills, err := b.Step(&Event{Time: start, Mint: token, Price: 2})
+		if i > 0 {
+			if err == nil {
+				t.Errorf("case %d: expected an invalid order to fail", i)
+			}
+			continue
+		}
+		// the buy spends what is left after the fee and the sell everything bought
+		if err != nil || len(fills) != 2 || !near(fills[0].Tokens, 49.5) || !near(fills[1].Cash, 98) || !near(b.Cash(), 98) {
+			t.Errorf("unexpected fills %+v: %v", fills, err)
+		}
+		if result := b.Result(); !near(result.Fees, 2) || result.Losses != 1 {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+}
+
+func TestModels(t *testing.T) {
+	tokens, cash, fee := Fixed{FeeRate: 0.01, SlippageBps: 100}.Fill(SideBuy, 1, 100)
+	if !near(tokens, 99/1.01) || cash != 100 || !near(fee, 1) {
+		t.Errorf("unexpected fixed buy %f, %f, %f", tokens, cash, fee)
+	}
+	if tokens, cash, fee = (Fixed{SlippageBps: 100}).Fill(SideSell, 2, 10); tokens != 10 || !near(cash, 19.8) || fee != 0 {
+		t.Errorf("unexpected fixed sell %f, %f, %f", tokens, cash, fee)
+	}
+
+	sol := tx_parser.NATIVE_SOL_PROGRAM_ID
+	pool := &pools.RaydiumAMM{CoinMint: token, PCMint: sol, CoinReserve: 1_000_000, PCReserve: 1_000_000_000_000, FeeNumerator: 25, FeeDenominator: 10_000}
+	model, err := PoolModel(pool, sol, 9, 1)
+	if err != nil || model.Liquidity != 1_000 || model.FeeRate != 0.0025 {
+		t.Fatalf("unexpected pool model %+v: %v", model, err)
+	}
+	if _, err := PoolModel(pool, solana.NewWallet().PublicKey(), 9, 1); err == nil {
+		t.Error("expected a quote outside the pool to fail")
+	}
+
+	// spending the whole reserve gets half the tokens, before fees
+	model.FeeRate = 0
+	if tokens, _, _ = model.Fill(SideBuy, 1, 1_000); !near(tokens, 500) {
+		t.Errorf("expected 500 tokens, got %f", tokens)
+	}
+	if _, cash, _ = model.Fill(SideSell, 1, 1_000); !near(cash, 500) {
+		t.Errorf("expected 500 cash, got %f", cash)
+	}
+
+	b, _ := New(StrategyFunc(func(*Backtest, *Event) []Order {
+		return []Order{{Mint: token, Side: SideBuy, Amount: 1_000}}
+	}), Config{Capital: 1_000, Models: map[solana.PublicKey]FillModel{token: model}})
+	fills, _ := b.Step(&Event{Time: start, Mint: token, Price: 1})
+	if len(fills) != 1 || !near(fills[0].Slippage, 500) || !near(fills[0].Price, 2) {
+		t.Errorf("expected the pool model's price impact, got %+v", fills)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	closed := []candles.Candle{
+		{Key: candles.Key{Mint: token, Interval: time.Minute}, Start: start, Close: 3},
+		{Key: candles.Key{Interval: time.Minute}, Start: start, Close: 4},
+	}
+	events := CandleEvents(closed)
+	if len(events) != 1 || events[0].Price != 3 || !events[0].Time.Equal(start.Add(time.Minute)) || events[0].Candle == nil {
+		t.Errorf("unexpected candle events %+v", events)
+	}
+
+	blockTime := solana.UnixTimeSeconds(start.Unix())
+	tx := &tx_parser.ParsedTransaction{
+		Slot:      7,
+		BlockTime: &blockTime,
+		Swaps: []*tx_parser.SwapInfo{
+			{Price: &tx_parser.PriceInfo{BaseMint: token, PriceUSD: 150, PriceSOL: 1}},
+			{Price: &tx_parser.PriceInfo{BaseMint: token}},
+			{},
+		},
+	}
+	events = SwapEvents([]*tx_parser.ParsedTransaction{tx}, candles.DenominationSOL)
+	if len(events) != 1 || events[0].Price != 1 || events[0].Slot != 7 || !events[0].Time.Equal(start) {
+		t.Errorf("unexpected swap events %+v", events)
+	}
+}
@@ -0,0 +1,80 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+var (
+	_ FillModel = Fixed{}
+	_ FillModel = ConstantProduct{}
+)
+
+// Fixed fills at the price moved against the order by SlippageBps, charging FeeRate of the
+// cash traded
+type Fixed struct {
+	FeeRate     float64
+	SlippageBps float64
+}
+
+func (m Fixed) Fill(side Side, price, amount float64) (float64, float64, float64) {
+	slippage := m.SlippageBps / 10_000
+	if side == SideBuy {
+		fee := amount * m.FeeRate
+		return (amount - fee) / (price * (1 + slippage)), amount, fee
+	}
+	gross := amount * price * (1 - slippage)
+	fee := gross * m.FeeRate
+	return amount, gross - fee, fee
+}
+
+// ConstantProduct fills against a constant product pool holding Liquidity of cash, with
+// the token reserve set by the event price. The pool is assumed to keep its depth in cash
+// as the price moves.
+type ConstantProduct struct {
+	Liquidity float64
+	FeeRate   float64
+}
+
+func (m ConstantProduct) Fill(side Side, price, amount float64) (float64, float64, float64) {
+	if m.Liquidity <= 0 {
+		return Fixed{FeeRate: m.FeeRate}.Fill(side, price, amount)
+	}
+	reserveCash, reserveTokens := m.Liquidity, m.Liquidity/price
+	if side == SideBuy {
+		fee := amount * m.FeeRate
+		in := amount - fee
+		return reserveTokens * in / (reserveCash + in), amount, fee
+	}
+	in := amount * (1 - m.FeeRate)
+	return amount, reserveCash * in / (reserveTokens + in), amount * m.FeeRate * price
+}
+
+// PoolModel derives a constant product model from a pool's state, using the pool's fee and
+// its reserve of quote as liquidity. quotePrice converts one whole quote token to the
+// backtest's denomination, e.g. 1 for SOL pools in a SOL backtest. Concentrated and DLMM
+// pools are approximated by their reserves.
+func PoolModel(pool pools.PoolState, quote solana.PublicKey, quoteDecimals uint8, quotePrice float64) (ConstantProduct, error) {
+	mintA, mintB := pool.Mints()
+	reserveA, reserveB := pool.Reserves()
+	var reserve uint64
+	switch {
+	case mintA.Equals(quote):
+		reserve = reserveA
+	case mintB.Equals(quote):
+		reserve = reserveB
+	default:
+		return ConstantProduct{}, fmt.Errorf("pool %s does not hold %s", pool.Address(), quote)
+	}
+	if quotePrice <= 0 {
+		return ConstantProduct{}, fmt.Errorf("invalid quote price %f", quotePrice)
+	}
+	return ConstantProduct{
+		Liquidity: float64(reserve) / math.Pow10(int(quoteDecimals)) * quotePrice,
+		FeeRate:   pool.FeeRate(),
+	}, nil
+}
@@ -0,0 +1,139 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/candles"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Side is the direction of an order
+type Side string
+
+const (
+	SideBuy  Side = "buy"  // cash is spent on the token
+	SideSell Side = "sell" // the token is sold for cash
+)
+
+// Event is one price update replayed through a strategy. Prices are in the backtest's
+// denomination per whole token.
+type Event struct {
+	Time  time.Time        `json:"time"`
+	Slot  uint64           `json:"slot,omitempty"`
+	Mint  solana.PublicKey `json:"mint"`
+	Price float64          `json:"price"`
+
+	// Candle is set on events replayed from closed candles
+	Candle *candles.Candle `json:"candle,omitempty"`
+
+	// Swap is set on events replayed from parsed swaps
+	Swap *tx_parser.SwapInfo `json:"swap,omitempty"`
+}
+
+// Order is a strategy's request to trade at the price of the event it was placed on.
+// Buys spend Amount of cash, sells sell Amount whole tokens. Orders beyond the cash or
+// position held are reduced to it.
+type Order struct {
+	Mint   solana.PublicKey `json:"mint"`
+	Side   Side             `json:"side"`
+	Amount float64          `json:"amount"`
+}
+
+// Strategy decides the orders to place on every event. The backtest is passed to read the
+// cash, positions and prices at the time of the event.
+type Strategy interface {
+	OnEvent(b *Backtest, event *Event) []Order
+}
+
+// StrategyFunc adapts a function to a Strategy
+type StrategyFunc func(b *Backtest, event *Event) []Order
+
+func (f StrategyFunc) OnEvent(b *Backtest, event *Event) []Order { return f(b, event) }
+
+// FillModel simulates the execution of an order at a price. Buys spend amount of cash for
+// tokens, sells sell amount tokens for cash. Fees are in cash and already accounted for in
+// the tokens or cash returned.
+type FillModel interface {
+	Fill(side Side, price, amount float64) (tokens, cash, fee float64)
+}
+
+// Fill is an executed order
+type Fill struct {
+	Time   time.Time        `json:"time"`
+	Mint   solana.PublicKey `json:"mint"`
+	Side   Side             `json:"side"`
+	Price  float64          `json:"price"`  // average execution price after slippage and fees
+	Tokens float64          `json:"tokens"` // whole tokens bought or sold
+	Cash   float64          `json:"cash"`   // cash spent or received, net of fees
+
+	// Fee is the pool fee and the fixed trade fee
+	Fee float64 `json:"fee"`
+
+	// Slippage is the value lost to price impact against the event price
+	Slippage float64 `json:"slippage"`
+
+	// Realized is the profit of sells against the average cost of the position
+	Realized float64 `json:"realized,omitempty"`
+}
+
+// Position is the holding of one token
+type Position struct {
+	Mint       solana.PublicKey `json:"mint"`
+	Tokens     float64          `json:"tokens"`
+	CostBasis  float64          `json:"cost_basis"` // cash spent on the tokens held, at average cost
+	Price      float64          `json:"price"`      // latest price, 0 if none was seen
+	Unrealized float64          `json:"unrealized"`
+}
+
+// Point is the equity at the time of an event
+type Point struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"`
+}
+
+// Result reports the performance of a backtest
+type Result struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Capital float64   `json:"capital"`
+	Equity  float64   `json:"equity"` // cash plus the positions at their latest price
+
+	PnL        float64 `json:"pnl"`    // equity - capital
+	Return     float64 `json:"return"` // PnL as a fraction of the capital
+	Realized   float64 `json:"realized"`
+	Unrealized float64 `json:"unrealized"`
+	Fees       float64 `json:"fees"`
+	Slippage   float64 `json:"slippage"`
+
+	Trades int `json:"trades"`
+	Wins   int `json:"wins"`   // sells at a profit
+	Losses int `json:"losses"` // sells at a loss
+
+	// MaxDrawdown is the largest fall of the equity from a previous peak, as a fraction of
+	// the peak
+	MaxDrawdown float64 `json:"max_drawdown"`
+
+	// MaxDrawdownDuration is the longest time the equity stayed below a previous peak
+	MaxDrawdownDuration time.Duration `json:"max_drawdown_duration"`
+
+	Positions []*Position `json:"positions"`
+	Fills     []*Fill     `json:"fills"`
+	Curve     []Point     `json:"curve"`
+}
+
+// Config controls the simulated account and its fills
+type Config struct {
+	// Capital is the starting cash in the denomination of the events, required
+	Capital float64
+
+	// Fills simulates fills, defaults to filling at the event price without fees
+	Fills FillModel
+
+	// Models overrides Fills for the given tokens, e.g. with pool models of their pools
+	Models map[solana.PublicKey]FillModel
+
+	// TradeFee is a fixed cash cost of every fill, e.g. priority fees and tips
+	TradeFee float64
+}
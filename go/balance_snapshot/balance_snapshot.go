@@ -0,0 +1,406 @@
+package balance_snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/pnl"
+)
+
+const (
+	// Token account layout offsets shared by both token programs
+	tokenAccountMintOffset   = 0
+	tokenAccountAmountOffset = 64
+	tokenAccountMinSize      = 72
+
+	// Mint layout offset of the decimals field
+	mintDecimalsOffset = 44
+
+	// getMultipleAccounts accepts at most 100 keys per request
+	maxAccountsPerRequest = 100
+)
+
+// Snapshotter periodically records SOL and token balances of tracked wallets
+type Snapshotter struct {
+	rpcClient *rpc.Client
+	store     Store
+	config    Config
+
+	// OnError is called for every wallet that fails to be snapshotted, and with the zero
+	// key when a scheduled snapshot fails as a whole. Snapshots keep going.
+	OnError func(wallet solana.PublicKey, err error)
+
+	epochMu    sync.Mutex
+	lastEpoch  uint64
+	epochKnown bool
+}
+
+// NewSnapshotter creates a new balance snapshotter writing into the given store, use
+// NewSinkStore to record the snapshots in an event sink
+func NewSnapshotter(rpcClient *rpc.Client, store Store, config Config) *Snapshotter {
+	if config.EpochPollInterval <= 0 {
+		config.EpochPollInterval = time.Minute
+	}
+	if config.Commitment == "" {
+		config.Commitment = rpc.CommitmentConfirmed
+	}
+
+	return &Snapshotter{
+		rpcClient: rpcClient,
+		store:     store,
+		config:    config,
+	}
+}
+
+// Run takes an initial snapshot of every wallet and keeps snapshotting on the
+// configured interval and epoch boundaries until the context is cancelled
+func (s *Snapshotter) Run(ctx context.Context) error {
+	if s.config.Interval <= 0 && !s.config.EpochBoundaries {
+		return fmt.Errorf("no snapshot schedule configured")
+	}
+
+	s.snapshotAll(ctx)
+
+	var intervalC, epochC <-chan time.Time
+	if s.config.Interval > 0 {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+		intervalC = ticker.C
+	}
+	if s.config.EpochBoundaries {
+		ticker := time.NewTicker(s.config.EpochPollInterval)
+		defer ticker.Stop()
+		epochC = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-intervalC:
+			s.snapshotAll(ctx)
+		case <-epochC:
+			changed, err := s.epochChanged(ctx)
+			if err != nil {
+				s.reportError(solana.PublicKey{}, err)
+				continue
+			}
+			if changed {
+				s.snapshotAll(ctx)
+			}
+		}
+	}
+}
+
+// SnapshotAll snapshots every tracked wallet once and saves the results. A wallet that
+// fails is reported to OnError and skipped, its error is joined into the returned error.
+func (s *Snapshotter) SnapshotAll(ctx context.Context) ([]*Snapshot, error) {
+	epoch, err := s.currentEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.snapshotWallets(ctx, epoch)
+}
+
+// TakeSnapshot fetches the current balances of a single wallet without storing them
+func (s *Snapshotter) TakeSnapshot(ctx context.Context, wallet solana.PublicKey) (*Snapshot, error) {
+	epoch, err := s.rpcClient.GetEpochInfo(ctx, s.config.Commitment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+
+	return s.takeSnapshot(ctx, wallet, epoch.Epoch)
+}
+
+// snapshotAll runs a scheduled snapshot, reporting errors instead of returning them
+func (s *Snapshotter) snapshotAll(ctx context.Context) {
+	epoch, err := s.currentEpoch(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.reportError(solana.PublicKey{}, err)
+		}
+		return
+	}
+	s.snapshotWallets(ctx, epoch)
+}
+
+// currentEpoch fetches the epoch snapshots are taken in and remembers it for epochChanged
+func (s *Snapshotter) currentEpoch(ctx context.Context) (uint64, error) {
+	epoch, err := s.rpcClient.GetEpochInfo(ctx, s.config.Commitment)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+	s.epochMu.Lock()
+	s.lastEpoch = epoch.Epoch
+	s.epochKnown = true
+	s.epochMu.Unlock()
+	return epoch.Epoch, nil
+}
+
+// snapshotWallets snapshots and saves each wallet independently, reporting the wallets
+// that fail
+func (s *Snapshotter) snapshotWallets(ctx context.Context, epoch uint64) ([]*Snapshot, error) {
+	snapshots := make([]*Snapshot, 0, len(s.config.Wallets))
+	var errs []error
+	for _, wallet := range s.config.Wallets {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		snapshot, err := s.snapshotWallet(ctx, wallet, epoch)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.reportError(wallet, err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, errors.Join(errs...)
+}
+
+// snapshotWallet snapshots and saves a single wallet
+func (s *Snapshotter) snapshotWallet(ctx context.Context, wallet solana.PublicKey, epoch uint64) (*Snapshot, error) {
+	snapshot, err := s.takeSnapshot(ctx, wallet, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot wallet %s: %w", wallet, err)
+	}
+	if err := s.store.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot for wallet %s: %w", wallet, err)
+	}
+	return snapshot, nil
+}
+
+// epochChanged checks whether a new epoch started since the last snapshot
+func (s *Snapshotter) epochChanged(ctx context.Context) (bool, error) {
+	epoch, err := s.rpcClient.GetEpochInfo(ctx, s.config.Commitment)
+	if err != nil {
+		return false, fmt.Errorf("failed to get epoch info: %w", err)
+	}
+
+	s.epochMu.Lock()
+	defer s.epochMu.Unlock()
+	return !s.epochKnown || epoch.Epoch != s.lastEpoch, nil
+}
+
+func (s *Snapshotter) reportError(wallet solana.PublicKey, err error) {
+	if s.OnError != nil {
+		s.OnError(wallet, err)
+	}
+}
+
+// takeSnapshot fetches the lamport balance and all token accounts of the wallet
+func (s *Snapshotter) takeSnapshot(ctx context.Context, wallet solana.PublicKey, epoch uint64) (*Snapshot, error) {
+	balance, err := s.rpcClient.GetBalance(ctx, wallet, s.config.Commitment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		Wallet:    wallet,
+		Slot:      balance.Context.Slot,
+		Epoch:     epoch,
+		Timestamp: time.Now().UTC(),
+		Lamports:  balance.Value,
+	}
+
+	for _, programID := range []solana.PublicKey{solana.TokenProgramID, solana.Token2022ProgramID} {
+		tokens, err := s.getTokenBalances(ctx, wallet, programID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Tokens = append(snapshot.Tokens, tokens...)
+	}
+
+	if err := s.resolveDecimals(ctx, snapshot.Tokens); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// getTokenBalances lists the wallet's token accounts owned by a token program
+func (s *Snapshotter) getTokenBalances(ctx context.Context, wallet, programID solana.PublicKey) ([]TokenBalance, error) {
+	accounts, err := s.rpcClient.GetTokenAccountsByOwner(
+		ctx,
+		wallet,
+		&rpc.GetTokenAccountsConfig{ProgramId: &programID},
+		&rpc.GetTokenAccountsOpts{
+			Commitment: s.config.Commitment,
+			Encoding:   solana.EncodingBase64,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts for program %s: %w", programID, err)
+	}
+
+	balances := make([]TokenBalance, 0, len(accounts.Value))
+	for _, account := range accounts.Value {
+		if account == nil || account.Account.Data == nil {
+			continue
+		}
+		balance, err := decodeTokenAccount(account.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		balance.Account = account.Pubkey
+		balance.Program = programID
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// resolveDecimals fills in token decimals by reading the mint accounts in batches
+func (s *Snapshotter) resolveDecimals(ctx context.Context, tokens []TokenBalance) error {
+	var mints []solana.PublicKey
+	seen := make(map[solana.PublicKey]bool)
+	for _, token := range tokens {
+		if !seen[token.Mint] {
+			seen[token.Mint] = true
+			mints = append(mints, token.Mint)
+		}
+	}
+
+	decimals := make(map[solana.PublicKey]uint8, len(mints))
+	for start := 0; start < len(mints); start += maxAccountsPerRequest {
+		end := min(start+maxAccountsPerRequest, len(mints))
+
+		result, err := s.rpcClient.GetMultipleAccountsWithOpts(ctx, mints[start:end], &rpc.GetMultipleAccountsOpts{
+			Commitment: s.config.Commitment,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get mint accounts: %w", err)
+		}
+
+		for i, account := range result.Value {
+			if account == nil || account.Data == nil {
+				continue
+			}
+			data := account.Data.GetBinary()
+			if len(data) > mintDecimalsOffset {
+				decimals[mints[start+i]] = data[mintDecimalsOffset]
+			}
+		}
+	}
+
+	for i := range tokens {
+		tokens[i].Decimals = decimals[tokens[i].Mint]
+	}
+
+	return nil
+}
+
+// decodeTokenAccount reads the mint and amount out of raw token account data
+func decodeTokenAccount(data []byte) (TokenBalance, error) {
+	if len(data) < tokenAccountMinSize {
+		return TokenBalance{}, fmt.Errorf("token account data too short")
+	}
+
+	return TokenBalance{
+		Mint:   solana.PublicKeyFromBytes(data[tokenAccountMintOffset : tokenAccountMintOffset+32]),
+		Amount: binary.LittleEndian.Uint64(data[tokenAccountAmountOffset : tokenAccountAmountOffset+8]),
+	}, nil
+}
+
+// Delta computes the net balance change between two snapshots of the same wallet.
+// The result can be reconciled against the change implied by parsed events over the same slot range.
+func Delta(from, to *Snapshot) (*BalanceDelta, error) {
+	if !from.Wallet.Equals(to.Wallet) {
+		return nil, fmt.Errorf("snapshots belong to different wallets")
+	}
+	if from.Slot > to.Slot {
+		return nil, fmt.Errorf("snapshots are out of order")
+	}
+
+	delta := &BalanceDelta{
+		Wallet:   from.Wallet,
+		FromSlot: from.Slot,
+		ToSlot:   to.Slot,
+		Lamports: new(big.Int).Sub(new(big.Int).SetUint64(to.Lamports), new(big.Int).SetUint64(from.Lamports)),
+		Tokens:   make(map[string]*big.Int),
+	}
+
+	// amounts are summed as big integers, u64 balances overflow int64 changes
+	change := func(mint solana.PublicKey) *big.Int {
+		if delta.Tokens[mint.String()] == nil {
+			delta.Tokens[mint.String()] = new(big.Int)
+		}
+		return delta.Tokens[mint.String()]
+	}
+	for _, token := range from.Tokens {
+		amount := change(token.Mint)
+		amount.Sub(amount, new(big.Int).SetUint64(token.Amount))
+	}
+	for _, token := range to.Tokens {
+		amount := change(token.Mint)
+		amount.Add(amount, new(big.Int).SetUint64(token.Amount))
+	}
+	for mint, amount := range delta.Tokens {
+		if amount.Sign() == 0 {
+			delete(delta.Tokens, mint)
+		}
+	}
+
+	return delta, nil
+}
+
+// Reconcile compares the token holdings of a snapshot with the positions of a PnL summary
+// built from the wallet's events up to the snapshot's slot, returning the mints whose
+// amounts differ ordered by mint. Mints in skip are left out, e.g. the tracker's quote
+// mints, which it does not hold positions in.
+func Reconcile(snapshot *Snapshot, summary *pnl.Summary, skip ...solana.PublicKey) ([]Mismatch, error) {
+	if !snapshot.Wallet.Equals(summary.Wallet) {
+		return nil, fmt.Errorf("snapshot of %s does not match the PnL of %s", snapshot.Wallet, summary.Wallet)
+	}
+
+	held := make(map[solana.PublicKey]*big.Int)
+	for _, token := range snapshot.Tokens {
+		if held[token.Mint] == nil {
+			held[token.Mint] = new(big.Int)
+		}
+		held[token.Mint].Add(held[token.Mint], new(big.Int).SetUint64(token.Amount))
+	}
+	derived := make(map[solana.PublicKey]*big.Int, len(summary.Positions))
+	for _, position := range summary.Positions {
+		derived[position.Mint] = new(big.Int).SetUint64(position.Amount)
+		if held[position.Mint] == nil {
+			held[position.Mint] = new(big.Int)
+		}
+	}
+
+	var mismatches []Mismatch
+	for mint, amount := range held {
+		if slices.Contains(skip, mint) {
+			continue
+		}
+		events := derived[mint]
+		if events == nil {
+			events = new(big.Int)
+		}
+		if amount.Cmp(events) != 0 {
+			mismatches = append(mismatches, Mismatch{
+				Mint:       mint,
+				Snapshot:   amount,
+				Events:     events,
+				Difference: new(big.Int).Sub(amount, events),
+			})
+		}
+	}
+	slices.SortFunc(mismatches, func(a, b Mismatch) int {
+		return bytes.Compare(a.Mint[:], b.Mint[:])
+	})
+	return mismatches, nil
+}
@@ -0,0 +1,309 @@
+package balance_snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/pnl"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func TestDecodeTokenAccount(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+
+	data := make([]byte, 165)
+	copy(data[0:32], mint.Bytes())
+	binary.LittleEndian.PutUint64(data[64:72], 123456)
+
+	balance, err := decodeTokenAccount(data)
+	if err != nil {
+		t.Fatalf("failed to decode token account: %v", err)
+	}
+	if !balance.Mint.Equals(mint) {
+		t.Errorf("expected mint %s, got %s", mint, balance.Mint)
+	}
+	if balance.Amount != 123456 {
+		t.Errorf("expected amount 123456, got %d", balance.Amount)
+	}
+
+	if _, err := decodeTokenAccount(data[:10]); err == nil {
+		t.Error("expected error for short account data")
+	}
+}
+
+func TestDelta(t *testing.T) {
+	wallet := solana.NewWallet().PublicKey()
+	mintA := solana.NewWallet().PublicKey()
+	mintB := solana.NewWallet().PublicKey()
+
+	from := &Snapshot{
+		Wallet:   wallet,
+		Slot:     100,
+		Lamports: 5_000_000_000,
+		Tokens: []TokenBalance{
+			{Mint: mintA, Amount: 1000},
+			{Mint: mintB, Amount: 50},
+		},
+	}
+	to := &Snapshot{
+		Wallet:   wallet,
+		Slot:     200,
+		Lamports: 4_000_000_000,
+		Tokens: []TokenBalance{
+			{Mint: mintA, Amount: 1500},
+			{Mint: mintB, Amount: 50},
+		},
+	}
+
+	delta, err := Delta(from, to)
+	if err != nil {
+		t.Fatalf("failed to compute delta: %v", err)
+	}
+	if delta.Lamports.Cmp(big.NewInt(-1_000_000_000)) != 0 {
+		t.Errorf("expected lamport delta -1000000000, got %s", delta.Lamports)
+	}
+	if delta.Tokens[mintA.String()].Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("expected mint A delta 500, got %s", delta.Tokens[mintA.String()])
+	}
+	if _, ok := delta.Tokens[mintB.String()]; ok {
+		t.Error("expected unchanged mint B to be omitted")
+	}
+
+	if _, err := Delta(to, from); err == nil {
+		t.Error("expected error for out of order snapshots")
+	}
+
+	// changes beyond int64 are kept exact
+	emptied := &Snapshot{Wallet: wallet, Slot: 300, Tokens: []TokenBalance{{Mint: mintA, Amount: 0}}}
+	full := &Snapshot{Wallet: wallet, Slot: 200, Tokens: []TokenBalance{{Mint: mintA, Amount: math.MaxUint64}}}
+	delta, err = Delta(full, emptied)
+	if err != nil {
+		t.Fatalf("failed to compute delta: %v", err)
+	}
+	expected := new(big.Int).Neg(new(big.Int).SetUint64(math.MaxUint64))
+	if delta.Tokens[mintA.String()].Cmp(expected) != 0 {
+		t.Errorf("expected mint A delta %s, got %s", expected, delta.Tokens[mintA.String()])
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	wallet := solana.NewWallet().PublicKey()
+	matched := solana.NewWallet().PublicKey()
+	short := solana.NewWallet().PublicKey()
+	unknown := solana.NewWallet().PublicKey()
+	sold := solana.NewWallet().PublicKey()
+
+	snapshot := &Snapshot{
+		Wallet: wallet,
+		Tokens: []TokenBalance{
+			{Mint: matched, Amount: 600},
+			{Mint: matched, Amount: 400}, // second account of the same mint
+			{Mint: short, Amount: 70},
+			{Mint: unknown, Amount: 5},
+			{Mint: solana.SolMint, Amount: 1_000_000},
+		},
+	}
+	summary := &pnl.Summary{
+		Wallet: wallet,
+		Positions: []*pnl.Position{
+			{Mint: matched, Amount: 1000},
+			{Mint: short, Amount: 100},
+			{Mint: sold, Amount: 20},
+		},
+	}
+
+	mismatches, err := Reconcile(snapshot, summary, solana.SolMint)
+	if err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	expected := map[solana.PublicKey]int64{short: -30, unknown: 5, sold: -20}
+	if len(mismatches) != len(expected) {
+		t.Fatalf("expected %d mismatches, got %+v", len(expected), mismatches)
+	}
+	for i, mismatch := range mismatches {
+		if i > 0 && bytes.Compare(mismatches[i-1].Mint[:], mismatch.Mint[:]) > 0 {
+			t.Errorf("expected mismatches ordered by mint")
+		}
+		if mismatch.Difference.Cmp(big.NewInt(expected[mismatch.Mint])) != 0 {
+			t.Errorf("expected difference %d for %s, got %s", expected[mismatch.Mint], mismatch.Mint, mismatch.Difference)
+		}
+	}
+
+	if _, err := Reconcile(snapshot, &pnl.Summary{Wallet: solana.NewWallet().PublicKey()}); err == nil {
+		t.Error("expected error for a summary of another wallet")
+	}
+}
+
+type recordingSink struct {
+	events []*sink.Event
+}
+
+func (r *recordingSink) Write(_ context.Context, events []*sink.Event) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *recordingSink) Close() error { return nil }
+
+func TestSinkStore(t *testing.T) {
+	wallet := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	recorder := &recordingSink{}
+
+	snapshot := &Snapshot{
+		Wallet:    wallet,
+		Slot:      1000,
+		Epoch:     700,
+		Timestamp: time.Unix(1700000000, 0),
+		Lamports:  5000,
+		Tokens:    []TokenBalance{{Mint: mint, Amount: 42, Decimals: 6}},
+	}
+	if err := NewSinkStore(recorder).SaveSnapshot(context.Background(), snapshot); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(recorder.events))
+	}
+	event := recorder.events[0]
+	if event.Kind != sink.KindBalance || event.Slot != 1000 || event.BlockTime == nil || *event.BlockTime != 1700000000 {
+		t.Errorf("unexpected balance event %+v", event)
+	}
+	if !event.Wallet().Equals(wallet) || event.Balance.Epoch != 700 || event.Balance.Lamports != 5000 {
+		t.Errorf("unexpected balance %+v", event.Balance)
+	}
+	if len(event.Balance.Tokens) != 1 || !event.Balance.Tokens[0].Mint.Equals(mint) || event.Balance.Tokens[0].Amount != 42 {
+		t.Errorf("unexpected token balances %+v", event.Balance.Tokens)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	wallet := solana.NewWallet().PublicKey()
+	ctx := context.Background()
+
+	for _, slot := range []uint64{30, 10, 20} {
+		if err := store.SaveSnapshot(ctx, &Snapshot{Wallet: wallet, Slot: slot}); err != nil {
+			t.Fatalf("failed to save snapshot: %v", err)
+		}
+	}
+
+	history := store.History(wallet)
+	if len(history) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(history))
+	}
+	for i, slot := range []uint64{10, 20, 30} {
+		if history[i].Slot != slot {
+			t.Errorf("expected slot %d at position %d, got %d", slot, i, history[i].Slot)
+		}
+	}
+	if latest := store.Latest(wallet); latest == nil || latest.Slot != 30 {
+		t.Errorf("expected latest snapshot at slot 30")
+	}
+}
+
+func TestSnapshotAllContinuesAfterFailure(t *testing.T) {
+	wallets := []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()}
+	failing := wallets[1]
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     any               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		id, _ := json.Marshal(request.ID)
+
+		var result string
+		switch request.Method {
+		case "getEpochInfo":
+			result = `{"epoch":700,"absoluteSlot":1000,"blockHeight":900,"slotIndex":10,"slotsInEpoch":432000}`
+		case "getBalance":
+			var wallet solana.PublicKey
+			json.Unmarshal(request.Params[0], &wallet)
+			if wallet.Equals(failing) {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32005,"message":"node is behind"}}`, id)
+				return
+			}
+			result = `{"context":{"slot":1000},"value":5000}`
+		case "getTokenAccountsByOwner":
+			result = `{"context":{"slot":1000},"value":[]}`
+		default:
+			t.Errorf("unexpected method %s", request.Method)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, id, result)
+	}))
+	defer server.Close()
+
+	store := NewMemoryStore()
+	snapshotter := NewSnapshotter(rpc.New(server.URL), store, Config{Wallets: wallets})
+	var reported []solana.PublicKey
+	snapshotter.OnError = func(wallet solana.PublicKey, err error) { reported = append(reported, wallet) }
+
+	snapshots, err := snapshotter.SnapshotAll(context.Background())
+	if err == nil || !strings.Contains(err.Error(), failing.String()) {
+		t.Errorf("expected the failing wallet in the error, got %v", err)
+	}
+	if len(snapshots) != 2 || !snapshots[0].Wallet.Equals(wallets[0]) || !snapshots[1].Wallet.Equals(wallets[2]) || snapshots[1].Epoch != 700 {
+		t.Errorf("expected the other wallets to be snapshotted, got %+v", snapshots)
+	}
+	if store.Latest(wallets[2]) == nil {
+		t.Error("expected the wallet after the failure to be saved")
+	}
+	if len(reported) != 1 || !reported[0].Equals(failing) {
+		t.Errorf("expected the failing wallet to be reported, got %v", reported)
+	}
+}
+
+func TestSnapshotAllConcurrently(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     any    `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		id, _ := json.Marshal(request.ID)
+
+		result := `{"context":{"slot":1000},"value":[]}`
+		switch request.Method {
+		case "getEpochInfo":
+			result = `{"epoch":700,"absoluteSlot":1000,"blockHeight":900,"slotIndex":10,"slotsInEpoch":432000}`
+		case "getBalance":
+			result = `{"context":{"slot":1000},"value":5000}`
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, id, result)
+	}))
+	defer server.Close()
+
+	snapshotter := NewSnapshotter(rpc.New(server.URL), NewMemoryStore(), Config{Wallets: []solana.PublicKey{solana.NewWallet().PublicKey()}})
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := snapshotter.SnapshotAll(context.Background()); err != nil {
+				t.Errorf("failed to snapshot: %v", err)
+			}
+			if _, err := snapshotter.epochChanged(context.Background()); err != nil {
+				t.Errorf("failed to check epoch: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package balance_snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Store persists snapshots taken by the Snapshotter
+type Store interface {
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+}
+
+// SinkStore writes snapshots to an event sink as balance events
+type SinkStore struct {
+	sink sink.Sink
+}
+
+// NewSinkStore creates a store writing snapshots to the given sink
+func NewSinkStore(s sink.Sink) *SinkStore {
+	return &SinkStore{sink: s}
+}
+
+// SaveSnapshot writes the snapshot to the sink
+func (s *SinkStore) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	if err := s.sink.Write(ctx, []*sink.Event{snapshot.Event()}); err != nil {
+		return fmt.Errorf("failed to write snapshot of %s: %w", snapshot.Wallet, err)
+	}
+	return nil
+}
+
+// MemoryStore keeps snapshots in memory, ordered by slot per wallet
+type MemoryStore struct {
+	mu        sync.RWMutex
+	snapshots map[solana.PublicKey][]*Snapshot
+}
+
+// NewMemoryStore creates an empty in-memory snapshot store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		snapshots: make(map[solana.PublicKey][]*Snapshot),
+	}
+}
+
+// SaveSnapshot stores the snapshot, keeping the wallet history sorted by slot
+func (s *MemoryStore) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := append(s.snapshots[snapshot.Wallet], snapshot)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Slot < history[j].Slot
+	})
+	s.snapshots[snapshot.Wallet] = history
+
+	return nil
+}
+
+// History returns all snapshots for a wallet ordered by slot
+func (s *MemoryStore) History(wallet solana.PublicKey) []*Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := s.snapshots[wallet]
+	out := make([]*Snapshot, len(history))
+	copy(out, history)
+	return out
+}
+
+// Latest returns the most recent snapshot for a wallet, or nil if none exists
+func (s *MemoryStore) Latest(wallet solana.PublicKey) *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := s.snapshots[wallet]
+	if len(history) == 0 {
+		return nil
+	}
+	return history[len(history)-1]
+}
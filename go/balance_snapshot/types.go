@@ -0,0 +1,79 @@
+package balance_snapshot
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Snapshot is the balance state of a single wallet at a point in time
+type Snapshot struct {
+	Wallet    solana.PublicKey `json:"wallet"`
+	Slot      uint64           `json:"slot"`
+	Epoch     uint64           `json:"epoch"`
+	Timestamp time.Time        `json:"timestamp"`
+	Lamports  uint64           `json:"lamports"`
+	Tokens    []TokenBalance   `json:"tokens"`
+}
+
+// Event converts the snapshot into a balance event for sinks
+func (s *Snapshot) Event() *sink.Event {
+	balance := &sink.Balance{
+		Wallet:   s.Wallet,
+		Epoch:    s.Epoch,
+		Lamports: s.Lamports,
+		Tokens:   make([]sink.TokenBalance, 0, len(s.Tokens)),
+	}
+	for _, token := range s.Tokens {
+		balance.Tokens = append(balance.Tokens, sink.TokenBalance(token))
+	}
+
+	blockTime := solana.UnixTimeSeconds(s.Timestamp.Unix())
+	return &sink.Event{Kind: sink.KindBalance, Slot: s.Slot, BlockTime: &blockTime, Balance: balance}
+}
+
+// TokenBalance is the balance of a single token account owned by the wallet
+type TokenBalance struct {
+	Account  solana.PublicKey `json:"account"`
+	Mint     solana.PublicKey `json:"mint"`
+	Program  solana.PublicKey `json:"program"`
+	Amount   uint64           `json:"amount"`
+	Decimals uint8            `json:"decimals"`
+}
+
+// BalanceDelta is the net change of a wallet's holdings between two snapshots
+type BalanceDelta struct {
+	Wallet   solana.PublicKey    `json:"wallet"`
+	FromSlot uint64              `json:"from_slot"`
+	ToSlot   uint64              `json:"to_slot"`
+	Lamports *big.Int            `json:"lamports"`
+	Tokens   map[string]*big.Int `json:"tokens"` // map[mint_address]raw_amount_change
+}
+
+// Mismatch is a mint whose snapshot balance differs from the amount derived from events
+type Mismatch struct {
+	Mint       solana.PublicKey `json:"mint"`
+	Snapshot   *big.Int         `json:"snapshot"`   // raw amount held across the wallet's token accounts
+	Events     *big.Int         `json:"events"`     // raw amount of the PnL position
+	Difference *big.Int         `json:"difference"` // Snapshot - Events
+}
+
+// Config controls which wallets are snapshotted and how often
+type Config struct {
+	Wallets []solana.PublicKey
+
+	// Interval between scheduled snapshots, zero disables the ticker
+	Interval time.Duration
+
+	// EpochBoundaries takes an additional snapshot whenever a new epoch is observed
+	EpochBoundaries bool
+
+	// EpochPollInterval controls how often the current epoch is checked, defaults to one minute
+	EpochPollInterval time.Duration
+
+	Commitment rpc.CommitmentType
+}
@@ -0,0 +1,259 @@
+package batcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// Batcher is a JSON-RPC client that coalesces getAccountInfo and getMultipleAccounts
+// lookups made within a short window into chunked getMultipleAccounts requests. Lookups
+// only share a request when their options match. Other methods pass through. It
+// implements rpc.JSONRPCClient, so resolvers and enrichers can share it through a
+// regular *rpc.Client: pass the same Client to decimals.NewResolver, owners.NewResolver,
+// tokenmeta.New or pools.New to coalesce their lookups.
+type Batcher struct {
+	next   rpc.JSONRPCClient
+	config Config
+
+	mu      sync.Mutex
+	batches map[string]*batch // open batch by options
+	stats   Stats
+}
+
+var _ rpc.JSONRPCClient = (*Batcher)(nil)
+
+// batch is a getMultipleAccounts request being collected
+type batch struct {
+	options  json.RawMessage
+	accounts []string
+	waiters  map[string][]chan<- result
+	timer    *time.Timer
+}
+
+// result is the outcome of a single account lookup
+type result struct {
+	context json.RawMessage
+	value   json.RawMessage
+	err     error
+}
+
+// New creates a batcher sending its requests through next, e.g. an rpcpool.Pool or
+// jsonrpc.NewClient
+func New(next rpc.JSONRPCClient, config Config) *Batcher {
+	if config.Window <= 0 {
+		config.Window = 5 * time.Millisecond
+	}
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = 100
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &Batcher{
+		next:    next,
+		config:  config,
+		batches: make(map[string]*batch),
+	}
+}
+
+// Client returns a Solana RPC client that sends every request through the batcher
+func (b *Batcher) Client() *rpc.Client {
+	return rpc.NewWithCustomRPCClient(b)
+}
+
+// Stats returns the lookups served so far
+func (b *Batcher) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// CallForInto coalesces account lookups and passes other requests through
+func (b *Batcher) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	switch method {
+	case "getAccountInfo":
+		return b.getAccountInfo(ctx, out, params)
+	case "getMultipleAccounts":
+		return b.getMultipleAccounts(ctx, out, params)
+	}
+	return b.next.CallForInto(ctx, out, method, params)
+}
+
+func (b *Batcher) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return b.next.CallWithCallback(ctx, method, params, callback)
+}
+
+func (b *Batcher) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	return b.next.CallBatch(ctx, requests)
+}
+
+// Close closes next if it can be closed
+func (b *Batcher) Close() error {
+	if closer, ok := b.next.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (b *Batcher) getAccountInfo(ctx context.Context, out interface{}, params []interface{}) error {
+	var account string
+	options, err := decodeParams(params, &account)
+	if err != nil {
+		return fmt.Errorf("failed to decode getAccountInfo params: %w", err)
+	}
+
+	results := b.lookup(options, []string{account})
+	lookup, err := wait(ctx, results[0])
+	if err != nil {
+		return err
+	}
+	return decodeResult(out, lookup.context, lookup.value)
+}
+
+func (b *Batcher) getMultipleAccounts(ctx context.Context, out interface{}, params []interface{}) error {
+	var accounts []string
+	options, err := decodeParams(params, &accounts)
+	if err != nil {
+		return fmt.Errorf("failed to decode getMultipleAccounts params: %w", err)
+	}
+
+	results := b.lookup(options, accounts)
+	values := make([]json.RawMessage, len(accounts))
+	var slotContext json.RawMessage
+	for i, results := range results {
+		lookup, err := wait(ctx, results)
+		if err != nil {
+			return err
+		}
+		if slotContext == nil {
+			slotContext = lookup.context
+		}
+		values[i] = lookup.value
+	}
+	return decodeResult(out, slotContext, values)
+}
+
+// lookup adds the accounts to the open batch of the options and returns the channel of
+// each account's result
+func (b *Batcher) lookup(options json.RawMessage, accounts []string) []chan result {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := string(options)
+	results := make([]chan result, len(accounts))
+	for i, account := range accounts {
+		results[i] = make(chan result, 1)
+		b.stats.Lookups++
+
+		open := b.batches[key]
+		if open == nil {
+			open = &batch{options: options, waiters: make(map[string][]chan<- result)}
+			open.timer = time.AfterFunc(b.config.Window, func() { b.flush(key, open) })
+			b.batches[key] = open
+		}
+		if _, ok := open.waiters[account]; !ok {
+			open.accounts = append(open.accounts, account)
+		}
+		open.waiters[account] = append(open.waiters[account], results[i])
+
+		// a full batch is closed, and sent here unless its window passed meanwhile
+		if len(open.accounts) >= b.config.MaxBatch {
+			delete(b.batches, key)
+			if open.timer.Stop() {
+				b.stats.Requests++
+				go b.send(open)
+			}
+		}
+	}
+	return results
+}
+
+// flush sends the batch once its window has passed
+func (b *Batcher) flush(key string, open *batch) {
+	b.mu.Lock()
+	if b.batches[key] == open {
+		delete(b.batches, key)
+	}
+	b.stats.Requests++
+	b.mu.Unlock()
+	b.send(open)
+}
+
+// send requests the accounts of the batch and hands each waiter its account
+func (b *Batcher) send(sent *batch) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.config.Timeout)
+	defer cancel()
+
+	params := []interface{}{sent.accounts}
+	if sent.options != nil {
+		params = append(params, sent.options)
+	}
+	var response struct {
+		Context json.RawMessage   `json:"context"`
+		Value   []json.RawMessage `json:"value"`
+	}
+	err := b.next.CallForInto(ctx, &response, "getMultipleAccounts", params)
+	if err == nil && len(response.Value) != len(sent.accounts) {
+		err = fmt.Errorf("expected %d accounts, got %d", len(sent.accounts), len(response.Value))
+	}
+
+	for i, account := range sent.accounts {
+		lookup := result{err: err}
+		if err == nil {
+			lookup = result{context: response.Context, value: response.Value[i]}
+		}
+		for _, waiter := range sent.waiters[account] {
+			waiter <- lookup
+		}
+	}
+}
+
+func wait(ctx context.Context, results <-chan result) (result, error) {
+	select {
+	case <-ctx.Done():
+		return result{}, ctx.Err()
+	case lookup := <-results:
+		return lookup, lookup.err
+	}
+}
+
+// decodeParams decodes the accounts param and returns the options param, nil if there is
+// none. Options are re-encoded so equal options share a batch.
+func decodeParams(params []interface{}, accounts any) (json.RawMessage, error) {
+	if len(params) == 0 || len(params) > 2 {
+		return nil, fmt.Errorf("expected 1 or 2 params, got %d", len(params))
+	}
+	encoded, err := json.Marshal(params[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, accounts); err != nil {
+		return nil, err
+	}
+	if len(params) == 1 {
+		return nil, nil
+	}
+	return json.Marshal(params[1])
+}
+
+// decodeResult decodes a context and value response into out
+func decodeResult(out interface{}, slotContext json.RawMessage, value any) error {
+	if out == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(struct {
+		Context json.RawMessage `json:"context,omitempty"`
+		Value   any             `json:"value"`
+	}{slotContext, value})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, out)
+}
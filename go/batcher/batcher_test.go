@@ -0,0 +1,132 @@
+package batcher
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// newServer answers getMultipleAccounts with the accounts' own key as data, leaving out
+// accounts in missing
+func newServer(t *testing.T, requests *atomic.Int32, missing map[string]bool) jsonrpc.RPCClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			ID     any               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil || request.Method != "getMultipleAccounts" {
+			t.Errorf("unexpected request %s", body)
+			return
+		}
+		requests.Add(1)
+		var accounts []string
+		json.Unmarshal(request.Params[0], &accounts)
+
+		values := make([]string, len(accounts))
+		for i, account := range accounts {
+			values[i] = "null"
+			if !missing[account] {
+				values[i] = fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+					solana.SystemProgramID, base64.StdEncoding.EncodeToString([]byte(account)))
+			}
+		}
+		id, _ := json.Marshal(request.ID)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"context":{"slot":7},"value":[%s]}}`, id, strings.Join(values, ","))
+	}))
+	t.Cleanup(server.Close)
+	return jsonrpc.NewClient(server.URL)
+}
+
+func accounts(n int) []solana.PublicKey {
+	keys := make([]solana.PublicKey, n)
+	for i := range keys {
+		keys[i] = solana.NewWallet().PublicKey()
+	}
+	return keys
+}
+
+func TestCoalescesLookups(t *testing.T) {
+	var requests atomic.Int32
+	keys := accounts(150)
+	missing := map[string]bool{keys[3].String(): true}
+	batcher := New(newServer(t, &requests, missing), Config{Window: 50 * time.Millisecond})
+	client := batcher.Client()
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			account, err := client.GetAccountInfoWithOpts(context.Background(), key, &rpc.GetAccountInfoOpts{Encoding: solana.EncodingBase64})
+			if i == 3 {
+				if !errors.Is(err, rpc.ErrNotFound) {
+					t.Errorf("expected the missing account not to be found, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("failed to get %s: %v", key, err)
+				return
+			}
+			if string(account.Value.Data.GetBinary()) != key.String() || account.Context.Slot != 7 {
+				t.Errorf("unexpected account for %s: %+v", key, account)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 150 lookups in 2 requests, got %d", got)
+	}
+	if stats := batcher.Stats(); stats.Lookups != 150 || stats.Requests != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestChunksMultipleAccounts(t *testing.T) {
+	var requests atomic.Int32
+	keys := accounts(250)
+	client := New(newServer(t, &requests, nil), Config{}).Client()
+
+	result, err := client.GetMultipleAccountsWithOpts(context.Background(), keys, &rpc.GetMultipleAccountsOpts{Encoding: solana.EncodingBase64})
+	if err != nil {
+		t.Fatalf("failed to get accounts: %v", err)
+	}
+	if len(result.Value) != len(keys) || requests.Load() != 3 {
+		t.Fatalf("expected 250 accounts in 3 requests, got %d in %d", len(result.Value), requests.Load())
+	}
+	for i, account := range result.Value {
+		if string(account.Data.GetBinary()) != keys[i].String() {
+			t.Fatalf("account %d out of order", i)
+		}
+	}
+}
+
+func TestPassesThrough(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":125}`)
+	}))
+	defer server.Close()
+
+	slot, err := New(jsonrpc.NewClient(server.URL), Config{}).Client().GetSlot(context.Background(), "")
+	if err != nil || slot != 125 {
+		t.Fatalf("expected slot 125, got %d: %v", slot, err)
+	}
+}
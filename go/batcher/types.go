@@ -0,0 +1,24 @@
+package batcher
+
+import "time"
+
+// Config controls the batcher
+type Config struct {
+	// Window is how long the first lookup of a batch waits for others to join it,
+	// defaults to 5ms
+	Window time.Duration
+
+	// MaxBatch is the most accounts in a single getMultipleAccounts request, defaults to
+	// the RPC limit of 100. Full batches are sent without waiting for the window.
+	MaxBatch int
+
+	// Timeout bounds each batch request, which outlives the lookups that joined it,
+	// defaults to 30s
+	Timeout time.Duration
+}
+
+// Stats counts the lookups served and the requests they were coalesced into
+type Stats struct {
+	Lookups  uint64 `json:"lookups"`  // accounts looked up
+	Requests uint64 `json:"requests"` // getMultipleAccounts requests sent
+}
@@ -0,0 +1,187 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/logging"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+const (
+	// Token program layouts, shared by both token programs. Token-2022 accounts with
+	// extensions are longer and record their type after the base token account layout.
+	mintSize                = 82
+	mintDecimalsOffset      = 44
+	tokenAccountSize        = 165
+	accountTypeOffset       = 165
+	accountTypeMint         = 1
+	accountTypeTokenAccount = 2
+)
+
+// Loader warms caches from account state read in bulk, so live processing starts without
+// a burst of RPC lookups
+type Loader struct {
+	config Config
+	mints  map[solana.PublicKey]struct{}
+	owners map[solana.PublicKey]struct{}
+	logger logging.Logger
+}
+
+// New creates a loader
+func New(config Config) *Loader {
+	l := &Loader{
+		config: config,
+		mints:  make(map[solana.PublicKey]struct{}),
+		owners: make(map[solana.PublicKey]struct{}),
+		logger: logging.OrNop(config.Logger),
+	}
+	for _, mint := range config.Mints {
+		l.mints[mint] = struct{}{}
+	}
+	for _, owner := range config.TokenAccountOwners {
+		l.owners[owner] = struct{}{}
+	}
+	return l
+}
+
+// LoadFile loads a snapshot archive, or an accounts export if the path ends in .json or
+// .jsonl
+func (l *Loader) LoadFile(ctx context.Context, path string) (*Stats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	read := ReadSnapshot
+	if strings.HasSuffix(path, ".json") || strings.HasSuffix(path, ".jsonl") {
+		read = ReadExport
+	}
+	return l.Load(ctx, func(fn func(account *Account) error) error {
+		return read(ctx, file, fn)
+	})
+}
+
+// Load reads the accounts passed by read, e.g. with ReadSnapshot or ReadExport, keeps the
+// latest version of each account the caches want and loads them
+func (l *Loader) Load(ctx context.Context, read func(fn func(account *Account) error) error) (*Stats, error) {
+	start := time.Now()
+	stats := &Stats{}
+	latest := make(map[solana.PublicKey]*Account)
+	// slots of dropped versions, so older versions read later are not kept. Versions that
+	// stopped being wanted without closing, e.g. token accounts given to another owner,
+	// are only known once a wanted version was read.
+	dropped := make(map[solana.PublicKey]uint64)
+	err := read(func(account *Account) error {
+		stats.Accounts++
+		if current, ok := latest[account.Pubkey]; ok && current.Slot > account.Slot {
+			return nil
+		}
+		if slot, ok := dropped[account.Pubkey]; ok && slot > account.Slot {
+			return nil
+		}
+		if account.Lamports == 0 || !l.wanted(account) {
+			if _, ok := latest[account.Pubkey]; ok || account.Lamports == 0 {
+				delete(latest, account.Pubkey)
+				dropped[account.Pubkey] = account.Slot
+			}
+			return nil
+		}
+		delete(dropped, account.Pubkey)
+		kept := *account
+		kept.Data = bytes.Clone(account.Data)
+		latest[account.Pubkey] = &kept
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounts: %w", err)
+	}
+
+	var found []pools.Pool
+	for _, account := range latest {
+		if pool, ok := l.pool(account); ok {
+			found = append(found, pool)
+			continue
+		}
+		if decimals, ok := l.mint(account); ok {
+			if err := l.config.Decimals.Add(account.Pubkey, decimals); err != nil {
+				return nil, fmt.Errorf("failed to add decimals of %s: %w", account.Pubkey, err)
+			}
+			stats.Mints++
+			continue
+		}
+		if owner, mint, ok := l.tokenAccount(account); ok {
+			l.config.TokenAccounts.Add(account.Pubkey, owner, mint)
+			stats.TokenAccounts++
+		}
+	}
+	if len(found) > 0 {
+		stats.Pools = l.config.Pools.Load(found)
+	}
+
+	l.logger.InfoContext(ctx, "bootstrapped account state", "accounts", stats.Accounts, "pools", stats.Pools,
+		"mints", stats.Mints, "token_accounts", stats.TokenAccounts, "duration", time.Since(start))
+	return stats, nil
+}
+
+// wanted reports whether a cache wants the account, so only those are kept in memory
+func (l *Loader) wanted(account *Account) bool {
+	if _, ok := l.pool(account); ok {
+		return true
+	}
+	if _, ok := l.mint(account); ok {
+		return true
+	}
+	_, _, ok := l.tokenAccount(account)
+	return ok
+}
+
+func (l *Loader) pool(account *Account) (pools.Pool, bool) {
+	if l.config.Pools == nil {
+		return pools.Pool{}, false
+	}
+	return pools.Identify(account.Pubkey, account.Owner, account.Data)
+}
+
+func (l *Loader) mint(account *Account) (uint8, bool) {
+	if l.config.Decimals == nil || !tokenProgram(account.Owner) || !hasType(account.Data, mintSize, accountTypeMint) {
+		return 0, false
+	}
+	if len(l.mints) > 0 {
+		if _, ok := l.mints[account.Pubkey]; !ok {
+			return 0, false
+		}
+	}
+	return account.Data[mintDecimalsOffset], true
+}
+
+func (l *Loader) tokenAccount(account *Account) (solana.PublicKey, solana.PublicKey, bool) {
+	if l.config.TokenAccounts == nil || !tokenProgram(account.Owner) || !hasType(account.Data, tokenAccountSize, accountTypeTokenAccount) {
+		return solana.PublicKey{}, solana.PublicKey{}, false
+	}
+	owner := solana.PublicKeyFromBytes(account.Data[32:64])
+	if _, ok := l.owners[owner]; !ok {
+		return solana.PublicKey{}, solana.PublicKey{}, false
+	}
+	return owner, solana.PublicKeyFromBytes(account.Data[:32]), true
+}
+
+func tokenProgram(owner solana.PublicKey) bool {
+	return owner.Equals(solana.TokenProgramID) || owner.Equals(solana.Token2022ProgramID)
+}
+
+// hasType reports whether token program data is of the base size, or longer and marked
+// with the account type
+func hasType(data []byte, size int, accountType byte) bool {
+	if len(data) == size {
+		return true
+	}
+	return len(data) > accountTypeOffset && data[accountTypeOffset] == accountType
+}
@@ -0,0 +1,180 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+var (
+	wallet = solana.NewWallet().PublicKey()
+	mint   = solana.NewWallet().PublicKey()
+	other  = solana.NewWallet().PublicKey()
+)
+
+// caches records what was loaded
+type caches struct {
+	decimals map[solana.PublicKey]uint8
+	owners   map[solana.PublicKey]solana.PublicKey
+	pools    []pools.Pool
+}
+
+func newCaches() *caches {
+	return &caches{decimals: map[solana.PublicKey]uint8{}, owners: map[solana.PublicKey]solana.PublicKey{}}
+}
+
+func (c *caches) Add(mint solana.PublicKey, decimals uint8) error {
+	c.decimals[mint] = decimals
+	return nil
+}
+
+func (c *caches) Load(pools []pools.Pool) int {
+	c.pools = append(c.pools, pools...)
+	return len(pools)
+}
+
+// tokenAccounts adapts the owner table to TokenAccounts, whose Add differs from Decimals
+type tokenAccounts struct{ *caches }
+
+func (t tokenAccounts) Add(account solana.PublicKey, owner, mint solana.PublicKey) {
+	t.owners[account] = owner
+}
+
+func mintData(decimals uint8) []byte {
+	data := make([]byte, mintSize)
+	data[mintDecimalsOffset] = decimals
+	return data
+}
+
+func tokenAccountData(owner solana.PublicKey) []byte {
+	data := make([]byte, tokenAccountSize)
+	copy(data, mint[:])
+	copy(data[32:], owner[:])
+	return data
+}
+
+func raydiumPool(mintA, mintB solana.PublicKey) []byte {
+	data := make([]byte, pools.RAYDIUM_AMM_ACCOUNT_LENGTH)
+	copy(data[400:], mintA[:])
+	copy(data[432:], mintB[:])
+	return data
+}
+
+// appendVec encodes accounts in the append vec layout, followed by zeroed capacity
+func appendVec(accounts ...*Account) []byte {
+	var out []byte
+	for _, account := range accounts {
+		header := make([]byte, storedAccountHeader)
+		binary.LittleEndian.PutUint64(header[8:], uint64(len(account.Data)))
+		copy(header[16:], account.Pubkey[:])
+		binary.LittleEndian.PutUint64(header[48:], account.Lamports)
+		copy(header[64:], account.Owner[:])
+		out = append(out, header...)
+		out = append(out, account.Data...)
+		out = append(out, make([]byte, (8-len(out)%8)%8)...)
+	}
+	return append(out, make([]byte, 256)...)
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	poolAddress, closedMint, ownedAccount, otherAccount := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	vecs := map[string][]byte{
+		// the older versions are read last
+		"accounts/200.1": appendVec(
+			&Account{Pubkey: mint, Owner: solana.TokenProgramID, Lamports: 1, Data: mintData(9)},
+			&Account{Pubkey: closedMint, Owner: solana.TokenProgramID},
+			&Account{Pubkey: poolAddress, Owner: tx_parser.RAYDIUM_V4_PROGRAM_ID, Lamports: 1, Data: raydiumPool(mint, tx_parser.NATIVE_SOL_PROGRAM_ID)},
+		),
+		"accounts/100.0": appendVec(
+			&Account{Pubkey: mint, Owner: solana.TokenProgramID, Lamports: 1, Data: mintData(6)},
+			&Account{Pubkey: closedMint, Owner: solana.Token2022ProgramID, Lamports: 1, Data: mintData(2)},
+			&Account{Pubkey: ownedAccount, Owner: solana.TokenProgramID, Lamports: 1, Data: tokenAccountData(wallet)},
+			&Account{Pubkey: otherAccount, Owner: solana.TokenProgramID, Lamports: 1, Data: tokenAccountData(other)},
+		),
+	}
+
+	var archive bytes.Buffer
+	encoder, _ := zstd.NewWriter(&archive)
+	writer := tar.NewWriter(encoder)
+	for _, name := range []string{"version", "accounts/200.1", "accounts/100.0"} {
+		data := append([]byte(nil), vecs[name]...)
+		if name == "version" {
+			data = []byte("1.2.0")
+		}
+		writer.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		writer.Write(data)
+	}
+	writer.Close()
+	encoder.Close()
+	path := filepath.Join(t.TempDir(), "snapshot-200-hash.tar.zst")
+	if err := os.WriteFile(path, archive.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+
+	loaded := newCaches()
+	loader := New(Config{Pools: loaded, Decimals: loaded, TokenAccounts: tokenAccounts{loaded}, TokenAccountOwners: []solana.PublicKey{wallet}})
+	stats, err := loader.LoadFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+	if stats.Accounts != 7 || stats.Pools != 1 || stats.Mints != 1 || stats.TokenAccounts != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if decimals, ok := loaded.decimals[mint]; !ok || decimals != 9 {
+		t.Errorf("expected the latest decimals of the mint, got %d", decimals)
+	}
+	if _, ok := loaded.decimals[closedMint]; ok {
+		t.Error("expected the closed mint to be dropped")
+	}
+	if owner := loaded.owners[ownedAccount]; !owner.Equals(wallet) || len(loaded.owners) != 1 {
+		t.Errorf("expected only the wallet's token account, got %v", loaded.owners)
+	}
+	if len(loaded.pools) != 1 || !loaded.pools[0].Address.Equals(poolAddress) || !loaded.pools[0].MintA.Equals(mint) {
+		t.Errorf("unexpected pools %+v", loaded.pools)
+	}
+}
+
+func TestLoadExport(t *testing.T) {
+	entry := func(pubkey, owner solana.PublicKey, data []byte) string {
+		return fmt.Sprintf(`{"pubkey":%q,"account":{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":18446744073709551615}}`,
+			pubkey, owner, base64.StdEncoding.EncodeToString(data))
+	}
+	token2022 := make([]byte, 200)
+	token2022[mintDecimalsOffset] = 4
+	token2022[accountTypeOffset] = accountTypeMint
+	accounts := []string{
+		entry(mint, solana.TokenProgramID, mintData(6)),
+		entry(other, solana.Token2022ProgramID, token2022),
+		entry(solana.NewWallet().PublicKey(), solana.SystemProgramID, nil),
+	}
+
+	for _, export := range []string{"[\n" + strings.Join(accounts, ",\n") + "\n]", strings.Join(accounts, "\n")} {
+		loaded := newCaches()
+		var read int
+		stats, err := New(Config{Decimals: loaded, Mints: []solana.PublicKey{other}}).Load(context.Background(), func(fn func(account *Account) error) error {
+			return ReadExport(context.Background(), strings.NewReader(export), func(account *Account) error {
+				read++
+				return fn(account)
+			})
+		})
+		if err != nil {
+			t.Fatalf("failed to load export: %v", err)
+		}
+		if read != 3 || stats.Mints != 1 || loaded.decimals[other] != 4 {
+			t.Errorf("expected only the listed Token-2022 mint, got %+v and %v", stats, loaded.decimals)
+		}
+	}
+}
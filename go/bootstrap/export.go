@@ -0,0 +1,80 @@
+package bootstrap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ReadExport reads an accounts export, a JSON array or a stream of JSON objects in the
+// keyed account format getProgramAccounts returns with base64 data, and calls fn with
+// every account
+func ReadExport(ctx context.Context, r io.Reader, fn func(account *Account) error) error {
+	buffered := bufio.NewReaderSize(r, 1<<20)
+	decoder := json.NewDecoder(buffered)
+
+	// an array is read element by element rather than decoded whole
+	array := false
+	if first, err := firstByte(buffered); err != nil {
+		return err
+	} else if first == '[' {
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("failed to read export: %w", err)
+		}
+		array = true
+	}
+
+	for i := 0; ; i++ {
+		if array && !decoder.More() {
+			return nil
+		}
+		var keyed rpc.KeyedAccount
+		err := decoder.Decode(&keyed)
+		if !array && errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode account %d: %w", i, err)
+		}
+		if keyed.Account == nil || keyed.Account.Data == nil {
+			return fmt.Errorf("account %d (%s) has no data", i, keyed.Pubkey)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		account := &Account{
+			Pubkey:     keyed.Pubkey,
+			Owner:      keyed.Account.Owner,
+			Lamports:   keyed.Account.Lamports,
+			Data:       keyed.Account.Data.GetBinary(),
+			Executable: keyed.Account.Executable,
+		}
+		if err := fn(account); err != nil {
+			return err
+		}
+	}
+}
+
+// firstByte peeks the first byte that is not whitespace
+func firstByte(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read export: %w", err)
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, r.UnreadByte()
+	}
+}
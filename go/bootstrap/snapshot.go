@@ -0,0 +1,131 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/klauspost/compress/zstd"
+)
+
+// storedAccountHeader is the size of the header preceding each account in an append vec:
+// write version, data length and pubkey, then lamports, rent epoch, owner and the
+// executable flag padded to 8 bytes, then the account hash
+const storedAccountHeader = 48 + 56 + 32
+
+var (
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+)
+
+// ReadSnapshot streams a validator snapshot archive, a tar file compressed with zstd,
+// gzip or bzip2 or uncompressed, and calls fn with every account stored in its append
+// vecs. Accounts are stored once per slot they changed in, so an account may be passed
+// several times in no particular order, the version with the highest Slot being current.
+// Accounts with zero lamports were closed at their slot. Data is only valid until fn
+// returns.
+func ReadSnapshot(ctx context.Context, r io.Reader, fn func(account *Account) error) error {
+	decompressed, err := decompress(r)
+	if err != nil {
+		return err
+	}
+	defer decompressed.Close()
+	archive := tar.NewReader(decompressed)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot archive: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// append vecs are stored as accounts/<slot>.<id>
+		dir, name := path.Split(header.Name)
+		if header.Typeflag != tar.TypeReg || strings.Trim(dir, "/") != "accounts" {
+			continue
+		}
+		slotText, _, _ := strings.Cut(name, ".")
+		slot, err := strconv.ParseUint(slotText, 10, 64)
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		if err := readAppendVec(data, slot, fn); err != nil {
+			return fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+	}
+}
+
+// decompress detects the compression of the archive by its magic bytes
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReaderSize(r, 1<<20)
+	magic, err := buffered.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read snapshot archive: %w", err)
+	}
+	switch {
+	case bytes.HasPrefix(magic, zstdMagic):
+		decoder, err := zstd.NewReader(buffered, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return decoder.IOReadCloser(), nil
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(buffered)
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return io.NopCloser(bzip2.NewReader(buffered)), nil
+	}
+	return io.NopCloser(buffered), nil
+}
+
+// readAppendVec decodes the stored accounts of an append vec, each 8 byte aligned. Append
+// vecs may be followed by zeroed capacity, where decoding stops.
+func readAppendVec(data []byte, slot uint64, fn func(account *Account) error) error {
+	for offset := 0; offset+storedAccountHeader <= len(data); {
+		dataLength := binary.LittleEndian.Uint64(data[offset+8:])
+		pubkey := solana.PublicKeyFromBytes(data[offset+16 : offset+48])
+		lamports := binary.LittleEndian.Uint64(data[offset+48:])
+		owner := solana.PublicKeyFromBytes(data[offset+64 : offset+96])
+		if pubkey.IsZero() && owner.IsZero() && lamports == 0 && dataLength == 0 {
+			return nil
+		}
+		start := offset + storedAccountHeader
+		if dataLength > uint64(len(data)-start) {
+			return fmt.Errorf("account %s at offset %d has %d bytes of data past the end", pubkey, offset, dataLength)
+		}
+		end := start + int(dataLength)
+
+		account := &Account{
+			Pubkey:     pubkey,
+			Owner:      owner,
+			Lamports:   lamports,
+			Data:       data[start:end:end],
+			Executable: data[offset+96] != 0,
+			Slot:       slot,
+		}
+		if err := fn(account); err != nil {
+			return err
+		}
+		offset = (end + 7) &^ 7
+	}
+	return nil
+}
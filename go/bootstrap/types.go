@@ -0,0 +1,76 @@
+package bootstrap
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/decimals"
+	"github.com/soralabs/solana-toolkit/go/internal/owners"
+	"github.com/soralabs/solana-toolkit/go/logging"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+// Account is an account read from a snapshot or an accounts export
+type Account struct {
+	Pubkey     solana.PublicKey
+	Owner      solana.PublicKey
+	Lamports   uint64
+	Data       []byte
+	Executable bool
+
+	// Slot the account was stored at in a snapshot, zero for exports
+	Slot uint64
+}
+
+// Decimals records mint decimals, implemented by decimals.Resolver
+type Decimals interface {
+	Add(mint solana.PublicKey, decimals uint8) error
+}
+
+// TokenAccounts records the owners and mints of token accounts, implemented by
+// owners.Resolver
+type TokenAccounts interface {
+	Add(account solana.PublicKey, owner, mint solana.PublicKey)
+}
+
+// Pools records pools and their pairs, implemented by pools.Registry
+type Pools interface {
+	Load(pools []pools.Pool) int
+}
+
+var (
+	_ Decimals      = (*decimals.Resolver)(nil)
+	_ TokenAccounts = (*owners.Resolver)(nil)
+	_ Pools         = (*pools.Registry)(nil)
+)
+
+// Config selects the caches to warm and the accounts loaded into them. A nil cache is
+// skipped.
+type Config struct {
+	// Pools is loaded with every Raydium AMM v4, Raydium CLMM, Orca Whirlpool and Meteora
+	// DLMM pool, so their pairs are served without querying
+	Pools Pools
+
+	// Decimals is loaded with the decimals of Mints
+	Decimals Decimals
+
+	// Mints limits the mints loaded into Decimals, every mint if empty
+	Mints []solana.PublicKey
+
+	// TokenAccounts is loaded with the token accounts of TokenAccountOwners
+	TokenAccounts TokenAccounts
+
+	// TokenAccountOwners are the wallets whose token accounts are loaded, e.g. tracked
+	// wallets. Snapshots hold hundreds of millions of token accounts, so none are loaded
+	// without it.
+	TokenAccountOwners []solana.PublicKey
+
+	Logger logging.Logger
+}
+
+// Stats counts what a load read and kept
+type Stats struct {
+	Accounts      int `json:"accounts"` // account versions read
+	Pools         int `json:"pools"`    // new pools
+	Mints         int `json:"mints"`
+	TokenAccounts int `json:"token_accounts"`
+}
@@ -0,0 +1,251 @@
+package candles
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+)
+
+// Aggregator builds OHLCV candles per mint and per pool from priced swaps. A candle closes
+// when a swap of its series falls into a later interval or when Flush passes its end.
+// It is safe for concurrent use.
+type Aggregator struct {
+	config Config
+
+	mu   sync.Mutex
+	open map[Key]*Candle
+}
+
+// New creates a candle aggregator
+func New(config Config) (*Aggregator, error) {
+	if len(config.Intervals) == 0 {
+		config.Intervals = []time.Duration{time.Minute}
+	}
+	for _, interval := range config.Intervals {
+		if interval < time.Second || interval > 24*time.Hour || (24*time.Hour)%interval != 0 {
+			return nil, fmt.Errorf("invalid interval %s, expected 1s to 1d dividing a day evenly", interval)
+		}
+	}
+	if config.Denomination == "" {
+		config.Denomination = DenominationUSD
+	}
+	if config.Denomination != DenominationUSD && config.Denomination != DenominationSOL {
+		return nil, fmt.Errorf("unknown denomination %q", config.Denomination)
+	}
+
+	return &Aggregator{
+		config: config,
+		open:   make(map[Key]*Candle),
+	}, nil
+}
+
+// AddTransaction adds every priced swap of the transaction and returns the candles that closed
+func (a *Aggregator) AddTransaction(tx *tx_parser.ParsedTransaction) []Candle {
+	at := time.Now()
+	if tx.BlockTime != nil {
+		at = tx.BlockTime.Time()
+	}
+
+	var closed []Candle
+	for _, swap := range tx.Swaps {
+		swapAt := at
+		if !swap.Timestamp.IsZero() {
+			swapAt = swap.Timestamp
+		}
+		closed = append(closed, a.Add(swap, swapAt)...)
+	}
+	return closed
+}
+
+// Add adds a swap priced by the pricing package and returns the candles that closed.
+// Swaps without a price, and swaps older than the open candle of a series, are ignored.
+func (a *Aggregator) Add(swap *tx_parser.SwapInfo, at time.Time) []Candle {
+	price := swap.Price
+	if price == nil {
+		return nil
+	}
+	volume := baseVolume(swap)
+
+	mintPrice := price.PriceUSD
+	if a.config.Denomination == DenominationSOL {
+		mintPrice = price.PriceSOL
+	}
+	market := pricing.Market{Protocol: swap.Protocol, BaseMint: price.BaseMint, QuoteMint: price.QuoteMint}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var closed []Candle
+	for _, interval := range a.config.Intervals {
+		if mintPrice > 0 {
+			closed = a.update(Key{Mint: price.BaseMint, Interval: interval}, at, mintPrice, volume, price.VolumeUSD, closed)
+		}
+		closed = a.update(Key{Market: market, Interval: interval}, at, price.Price, volume, price.VolumeUSD, closed)
+	}
+	return closed
+}
+
+// Flush closes and returns the candles whose interval ended at or before now, e.g. to
+// emit candles of series that stopped trading
+func (a *Aggregator) Flush(now time.Time) []Candle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var closed []Candle
+	for key, candle := range a.open {
+		if !candle.Start.Add(key.Interval).After(now) {
+			closed = append(closed, *candle)
+			delete(a.open, key)
+		}
+	}
+	sortCandles(closed)
+	return closed
+}
+
+// Current returns the open candle of a series
+func (a *Aggregator) Current(key Key) (Candle, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	candle, ok := a.open[key]
+	if !ok {
+		return Candle{}, false
+	}
+	return *candle, true
+}
+
+// Snapshot returns the open candles, to be restored after a restart
+func (a *Aggregator) Snapshot() []Candle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	candles := make([]Candle, 0, len(a.open))
+	for _, candle := range a.open {
+		candles = append(candles, *candle)
+	}
+	sortCandles(candles)
+	return candles
+}
+
+// Restore replaces the open candles with a snapshot
+func (a *Aggregator) Restore(candles []Candle) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.open = make(map[Key]*Candle, len(candles))
+	for _, candle := range candles {
+		a.open[candle.Key] = &candle
+	}
+}
+
+// update adds a trade to the open candle of a series, closing it first if the trade
+// belongs to a later interval
+func (a *Aggregator) update(key Key, at time.Time, price, volume, volumeUSD float64, closed []Candle) []Candle {
+	start := at.Truncate(key.Interval).UTC()
+
+	candle, ok := a.open[key]
+	if ok && start.Before(candle.Start) {
+		return closed
+	}
+	if ok && start.After(candle.Start) {
+		closed = append(closed, *candle)
+		ok = false
+	}
+	if !ok {
+		candle = &Candle{Key: key, Start: start, Open: price, High: price, Low: price}
+		a.open[key] = candle
+	}
+
+	candle.High = math.Max(candle.High, price)
+	candle.Low = math.Min(candle.Low, price)
+	candle.Close = price
+	candle.Volume += volume
+	candle.VolumeUSD += volumeUSD
+	candle.Trades++
+	return closed
+}
+
+// baseVolume returns the amount of the base token traded in whole tokens
+func baseVolume(swap *tx_parser.SwapInfo) float64 {
+	token := swap.TokenIn
+	if swap.TokenOut.Mint.Equals(swap.Price.BaseMint) {
+		token = swap.TokenOut
+	}
+	return float64(token.Amount) / math.Pow10(int(token.Decimals))
+}
+
+// sortCandles orders candles by start time, interval and series for deterministic output
+func sortCandles(candles []Candle) {
+	sort.Slice(candles, func(i, j int) bool {
+		if !candles[i].Start.Equal(candles[j].Start) {
+			return candles[i].Start.Before(candles[j].Start)
+		}
+		if candles[i].Interval != candles[j].Interval {
+			return candles[i].Interval < candles[j].Interval
+		}
+		a, b := candles[i].Key, candles[j].Key
+		if c := bytes.Compare(a.Mint[:], b.Mint[:]); c != 0 {
+			return c > 0 // per-mint series before per-pool series, whose mint is zero
+		}
+		if c := bytes.Compare(a.Market.BaseMint[:], b.Market.BaseMint[:]); c != 0 {
+			return c < 0
+		}
+		if c := bytes.Compare(a.Market.QuoteMint[:], b.Market.QuoteMint[:]); c != 0 {
+			return c < 0
+		}
+		return a.Market.Protocol < b.Market.Protocol
+	})
+}
+
+// SaveSnapshot atomically writes a snapshot to a JSON file
+func SaveSnapshot(path string, candles []Candle) error {
+	data, err := json.Marshal(candles)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot, returning nil if the file does not exist
+func LoadSnapshot(path string) ([]Candle, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var candles []Candle
+	if err := json.Unmarshal(data, &candles); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return candles, nil
+}
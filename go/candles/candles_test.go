@@ -0,0 +1,131 @@
+package candles
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var token = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+
+// pricedSwap returns a swap of amount tokens at price SOL each, with SOL at 100 USD
+func pricedSwap(price float64, amount uint64) *tx_parser.SwapInfo {
+	return &tx_parser.SwapInfo{
+		Protocol: tx_parser.SwapTypeRaydium,
+		TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Decimals: 9},
+		TokenOut: tx_parser.TokenInfo{Mint: token, Amount: amount * 1_000_000, Decimals: 6},
+		Price: &tx_parser.PriceInfo{
+			BaseMint:  token,
+			QuoteMint: tx_parser.NATIVE_SOL_PROGRAM_ID,
+			Price:     price,
+			PriceSOL:  price,
+			PriceUSD:  price * 100,
+			VolumeUSD: price * 100 * float64(amount),
+		},
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	a, err := New(Config{Intervals: []time.Duration{time.Minute, time.Hour}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, price := range []float64{2, 5, 1, 3} {
+		if closed := a.Add(pricedSwap(price, 10), start.Add(time.Duration(i)*10*time.Second)); len(closed) != 0 {
+			t.Fatalf("expected no closed candles within the first minute, got %+v", closed)
+		}
+	}
+	if closed := a.Add(pricedSwap(1, 1), start.Add(-time.Hour)); len(closed) != 0 {
+		t.Errorf("expected late swaps to be ignored, got %+v", closed)
+	}
+
+	closed := a.Add(pricedSwap(4, 1), start.Add(time.Minute))
+	if len(closed) != 2 {
+		t.Fatalf("expected the mint and pool minute candles to close, got %d", len(closed))
+	}
+	mintCandle, poolCandle := closed[0], closed[1]
+	if !mintCandle.Mint.Equals(token) || !poolCandle.Market.BaseMint.Equals(token) {
+		t.Fatalf("unexpected candle keys %+v and %+v", mintCandle.Key, poolCandle.Key)
+	}
+	if mintCandle.Open != 200 || mintCandle.High != 500 || mintCandle.Low != 100 || mintCandle.Close != 300 {
+		t.Errorf("unexpected USD candle %+v", mintCandle)
+	}
+	if poolCandle.Open != 2 || poolCandle.Close != 3 || poolCandle.Volume != 40 || poolCandle.Trades != 4 {
+		t.Errorf("unexpected pool candle %+v", poolCandle)
+	}
+	if !poolCandle.Start.Equal(start) {
+		t.Errorf("expected the candle to start at %s, got %s", start, poolCandle.Start)
+	}
+
+	hour, ok := a.Current(Key{Mint: token, Interval: time.Hour})
+	if !ok || hour.Trades != 5 || hour.High != 500 || hour.Close != 400 {
+		t.Errorf("unexpected hourly candle %+v", hour)
+	}
+
+	flushed := a.Flush(start.Add(2 * time.Minute))
+	if len(flushed) != 2 || flushed[0].Interval != time.Minute {
+		t.Errorf("expected only the minute candles to be flushed, got %+v", flushed)
+	}
+}
+
+func TestUnpricedSwapsIgnored(t *testing.T) {
+	a, _ := New(Config{})
+	swap := pricedSwap(1, 1)
+	swap.Price = nil
+	a.Add(swap, time.Now())
+	if len(a.Snapshot()) != 0 {
+		t.Error("expected unpriced swaps to be ignored")
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	a, _ := New(Config{Denomination: DenominationSOL})
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.Add(pricedSwap(2, 10), start)
+	a.Add(pricedSwap(3, 5), start.Add(time.Second))
+
+	path := filepath.Join(t.TempDir(), "candles.json")
+	if err := SaveSnapshot(path, a.Snapshot()); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+	candles, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+
+	restored, _ := New(Config{Denomination: DenominationSOL})
+	restored.Restore(candles)
+	if !reflect.DeepEqual(restored.Snapshot(), a.Snapshot()) {
+		t.Errorf("restored candles differ:\n got %+v\nwant %+v", restored.Snapshot(), a.Snapshot())
+	}
+
+	restored.Add(pricedSwap(1, 1), start.Add(2*time.Second))
+	mint, _ := restored.Current(Key{Mint: token, Interval: time.Minute})
+	if mint.Open != 2 || mint.Low != 1 || mint.Trades != 3 {
+		t.Errorf("expected the restored candle to continue, got %+v", mint)
+	}
+
+	if missing, err := LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err != nil || missing != nil {
+		t.Errorf("expected a missing snapshot to load as nil, got %v %v", missing, err)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, config := range []Config{
+		{Intervals: []time.Duration{time.Millisecond}},
+		{Intervals: []time.Duration{7 * time.Second}},
+		{Intervals: []time.Duration{48 * time.Hour}},
+		{Denomination: "eur"},
+	} {
+		if _, err := New(config); err == nil {
+			t.Errorf("expected %+v to be rejected", config)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package candles
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/pricing"
+)
+
+// Denomination selects the currency of per-mint candles
+type Denomination string
+
+const (
+	DenominationUSD Denomination = "usd"
+	DenominationSOL Denomination = "sol"
+)
+
+// Config controls the candle aggregator
+type Config struct {
+	// Intervals between 1s and 1d that divide a day evenly, defaults to 1m
+	Intervals []time.Duration
+
+	// Denomination of per-mint candles, defaults to USD. Pool candles are always in the
+	// quote token of the pool.
+	Denomination Denomination
+}
+
+// Key identifies a candle series. Per-mint series set Mint, per-pool series set Market.
+type Key struct {
+	Mint     solana.PublicKey `json:"mint,omitzero"`
+	Market   pricing.Market   `json:"market,omitzero"`
+	Interval time.Duration    `json:"interval"`
+}
+
+// Candle is the OHLCV summary of the swaps of one series in one interval
+type Candle struct {
+	Key
+	Start     time.Time `json:"start"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`               // base tokens traded
+	VolumeUSD float64   `json:"volume_usd,omitempty"` // 0 if the USD price was unknown
+	Trades    int       `json:"trades"`
+}
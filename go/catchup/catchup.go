@@ -0,0 +1,265 @@
+package catchup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/logging"
+)
+
+// Monitor tracks how far processing lags behind the chain tip. When the lag grows past
+// MaxLag it stops the stream and backfills the gap in batches, then resumes streaming
+// once the lag is under ResumeLag.
+type Monitor struct {
+	config Config
+	logger logging.Logger
+
+	processed atomic.Uint64
+
+	mu   sync.Mutex
+	mode Mode
+	lag  Lag
+
+	// set while the stream runs
+	stopStream context.CancelFunc
+	streamDone chan error
+}
+
+// New creates a monitor
+func New(config Config) *Monitor {
+	if config.Commitment == "" {
+		config.Commitment = rpc.CommitmentConfirmed
+	}
+	if config.Interval == 0 {
+		config.Interval = 2 * time.Second
+	}
+	if config.MaxLag == 0 {
+		config.MaxLag = 300
+	}
+	if config.ResumeLag == 0 {
+		config.ResumeLag = 32
+	}
+	if config.BatchSlots == 0 {
+		config.BatchSlots = 5000
+	}
+
+	m := &Monitor{
+		config: config,
+		logger: logging.OrNop(config.Logger),
+		mode:   ModeStreaming,
+	}
+	m.processed.Store(config.StartSlot)
+	return m
+}
+
+// Observe records a processed slot, slots lower than the highest one are ignored
+func (m *Monitor) Observe(slot uint64) {
+	for {
+		last := m.processed.Load()
+		if slot <= last || m.processed.CompareAndSwap(last, slot) {
+			return
+		}
+	}
+}
+
+// Processed returns the highest processed slot
+func (m *Monitor) Processed() uint64 {
+	return m.processed.Load()
+}
+
+// Mode returns whether the monitor is streaming or catching up
+func (m *Monitor) Mode() Mode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mode
+}
+
+// Lag returns the lag measured at the last poll
+func (m *Monitor) Lag() Lag {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lag
+}
+
+// Run starts the stream and polls the tip until the context is cancelled, catching up
+// whenever the lag grows past MaxLag. It fails if the stream returns.
+func (m *Monitor) Run(ctx context.Context) error {
+	if m.config.Tip == nil {
+		return fmt.Errorf("tip source is required")
+	}
+	if m.config.ResumeLag >= m.config.MaxLag {
+		return fmt.Errorf("resume lag %d must be below max lag %d", m.config.ResumeLag, m.config.MaxLag)
+	}
+	defer m.stop()
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.poll(ctx); err != nil {
+			return err
+		}
+		m.start(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-m.streamDone:
+			m.streamDone = nil
+			if err == nil {
+				err = errors.New("stream returned")
+			}
+			return fmt.Errorf("stream stopped: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll measures the lag and catches up if it grew past MaxLag
+func (m *Monitor) poll(ctx context.Context) error {
+	lag, err := m.measure(ctx)
+	if err != nil {
+		return err
+	}
+	if m.config.Backfill == nil || lag.Slots <= m.config.MaxLag {
+		return nil
+	}
+	return m.catchUp(ctx, lag)
+}
+
+// catchUp stops the stream and backfills in batches until the lag is under ResumeLag,
+// then restarts the stream and backfills the remaining slots, which the new stream starts
+// after
+func (m *Monitor) catchUp(ctx context.Context, lag Lag) error {
+	m.stop()
+	m.setMode(ctx, ModeCatchingUp, lag)
+
+	var err error
+	for lag.Slots > m.config.ResumeLag {
+		if err := m.backfill(ctx, lag.Processed+1, min(lag.Tip, lag.Processed+m.config.BatchSlots)); err != nil {
+			return err
+		}
+		if lag, err = m.measure(ctx); err != nil {
+			return err
+		}
+	}
+
+	m.start(ctx)
+	m.setMode(ctx, ModeStreaming, lag)
+	if lag.Slots > 0 {
+		return m.backfill(ctx, lag.Processed+1, lag.Tip)
+	}
+	return nil
+}
+
+// backfill processes the range, retrying after the interval until it succeeds or the
+// context is cancelled
+func (m *Monitor) backfill(ctx context.Context, from, to uint64) error {
+	for {
+		err := m.config.Backfill(ctx, from, to)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		m.logger.WarnContext(ctx, "catch-up backfill failed, retrying", "from", from, "to", to, logging.ERROR_KEY, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.config.Interval):
+		}
+	}
+
+	m.Observe(to)
+	if m.config.Metrics != nil {
+		m.config.Metrics.ObserveCatchupSlots(to - from + 1)
+	}
+	return nil
+}
+
+// measure polls the tip and records the lag. A failed poll reuses the last tip, only the
+// cancellation of the context is returned.
+func (m *Monitor) measure(ctx context.Context) (Lag, error) {
+	m.mu.Lock()
+	tip := m.lag.Tip
+	m.mu.Unlock()
+
+	latest, err := m.config.Tip.GetSlot(ctx, m.config.Commitment)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Lag{}, ctx.Err()
+		}
+		m.logger.WarnContext(ctx, "failed to get latest slot", logging.ERROR_KEY, err)
+	}
+	tip = max(tip, latest)
+
+	// without a start slot, processing starts from the tip
+	m.processed.CompareAndSwap(0, tip)
+	processed := m.processed.Load()
+
+	m.mu.Lock()
+	m.lag = Lag{Tip: tip, Processed: processed, Slots: max(tip, processed) - processed, Mode: m.mode}
+	lag := m.lag
+	m.mu.Unlock()
+
+	if m.config.Metrics != nil {
+		m.config.Metrics.ObserveSlotLag(lag.Slots)
+	}
+	if m.config.OnLag != nil {
+		m.config.OnLag(lag)
+	}
+	return lag, nil
+}
+
+func (m *Monitor) setMode(ctx context.Context, mode Mode, lag Lag) {
+	m.mu.Lock()
+	from := m.mode
+	m.mode = mode
+	m.lag.Mode = mode
+	m.mu.Unlock()
+	if from == mode {
+		return
+	}
+
+	m.logger.InfoContext(ctx, "switched processing mode", "mode", mode, "tip", lag.Tip, "processed", lag.Processed, "slot_lag", lag.Slots)
+	if m.config.Metrics != nil {
+		m.config.Metrics.ObserveCatchingUp(mode == ModeCatchingUp)
+	}
+	if m.config.OnModeChange != nil {
+		lag.Mode = mode
+		m.config.OnModeChange(from, mode, lag)
+	}
+}
+
+// start runs the stream in the background
+func (m *Monitor) start(ctx context.Context) {
+	if m.config.Stream == nil || m.stopStream != nil {
+		return
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- m.config.Stream(streamCtx)
+	}()
+	m.stopStream = cancel
+	m.streamDone = done
+}
+
+// stop cancels the stream and waits for it to return
+func (m *Monitor) stop() {
+	if m.stopStream == nil {
+		return
+	}
+	m.stopStream()
+	if m.streamDone != nil {
+		<-m.streamDone
+	}
+	m.stopStream = nil
+	m.streamDone = nil
+}
@@ -0,0 +1,108 @@
+package catchup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// tip advances by step slots on every poll
+type tip struct {
+	mu   sync.Mutex
+	slot uint64
+	step uint64
+}
+
+func (t *tip) GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slot := t.slot
+	t.slot += t.step
+	return slot, nil
+}
+
+func TestCatchUp(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		ranges   [][2]uint64
+		changes  []Mode
+		streams  int
+		streamed = make(chan struct{})
+	)
+	monitor := New(Config{
+		Tip:        &tip{slot: 2000, step: 10},
+		Interval:   time.Hour,
+		StartSlot:  1000,
+		BatchSlots: 600,
+		Stream: func(ctx context.Context) error {
+			mu.Lock()
+			streams++
+			mu.Unlock()
+			close(streamed)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Backfill: func(ctx context.Context, from, to uint64) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ranges = append(ranges, [2]uint64{from, to})
+			return nil
+		},
+		OnModeChange: func(from, to Mode, lag Lag) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, to)
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- monitor.Run(ctx) }()
+	<-streamed
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the run to be cancelled, got %v", err)
+	}
+
+	// batches until the lag is under ResumeLag, then the rest once streaming again
+	expected := [][2]uint64{{1001, 1600}, {1601, 2010}, {2011, 2020}}
+	if !slices.Equal(ranges, expected) {
+		t.Errorf("expected backfilled ranges %v, got %v", expected, ranges)
+	}
+	if !slices.Equal(changes, []Mode{ModeCatchingUp, ModeStreaming}) || streams != 1 {
+		t.Errorf("expected a single catch-up before streaming, got %v and %d streams", changes, streams)
+	}
+	if monitor.Processed() != 2020 || monitor.Mode() != ModeStreaming {
+		t.Errorf("expected streaming from slot 2020, got %d in %s", monitor.Processed(), monitor.Mode())
+	}
+}
+
+func TestMonitorOnly(t *testing.T) {
+	var lags []Lag
+	monitor := New(Config{
+		Tip:      &tip{slot: 500},
+		Interval: time.Millisecond,
+		OnLag:    func(lag Lag) { lags = append(lags, lag) },
+		Stream: func(ctx context.Context) error {
+			return errors.New("connection closed")
+		},
+	})
+	monitor.Observe(400)
+
+	err := monitor.Run(context.Background())
+	if err == nil || err.Error() != "stream stopped: connection closed" {
+		t.Fatalf("expected the stream error, got %v", err)
+	}
+	// without a backfill the lag is only reported
+	if len(lags) != 1 || lags[0].Slots != 100 || lags[0].Mode != ModeStreaming {
+		t.Errorf("unexpected lags %+v", lags)
+	}
+	if err := New(Config{Tip: &tip{}, MaxLag: 10, ResumeLag: 10}).Run(context.Background()); err == nil {
+		t.Error("expected a resume lag at max lag to be rejected")
+	}
+}
@@ -0,0 +1,93 @@
+package catchup
+
+import (
+	"context"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/logging"
+	"github.com/soralabs/solana-toolkit/go/metrics"
+)
+
+// Mode is how slots are being processed
+type Mode string
+
+const (
+	ModeStreaming  Mode = "streaming"
+	ModeCatchingUp Mode = "catching_up" // the stream is stopped while a backfill closes the gap
+)
+
+// Lag is the gap between the chain tip and the last processed slot at a poll
+type Lag struct {
+	Tip       uint64 `json:"tip"`
+	Processed uint64 `json:"processed"`
+	Slots     uint64 `json:"slots"`
+	Mode      Mode   `json:"mode"`
+}
+
+// TipSource reports the latest network slot, implemented by rpc.Client
+type TipSource interface {
+	GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
+}
+
+// Metrics records the lag and catch-ups, implemented by metrics.Metrics
+type Metrics interface {
+	ObserveSlotLag(lag uint64)
+	ObserveCatchingUp(catchingUp bool)
+	ObserveCatchupSlots(slots uint64)
+}
+
+var (
+	_ TipSource = (*rpc.Client)(nil)
+	_ Metrics   = (*metrics.Metrics)(nil)
+)
+
+// Config controls the monitor
+type Config struct {
+	// Tip is polled for the latest network slot, required
+	Tip TipSource
+
+	// Commitment of the chain tip, defaults to confirmed
+	Commitment rpc.CommitmentType
+
+	// Interval between polls of the tip, defaults to 2s
+	Interval time.Duration
+
+	// StartSlot is the last slot processed before the monitor started, e.g. from a
+	// checkpoint, so the slots missed while down are caught up. Zero starts from the tip.
+	StartSlot uint64
+
+	// MaxLag is the lag in slots past which the stream is stopped and the gap backfilled,
+	// defaults to 300
+	MaxLag uint64
+
+	// ResumeLag is the lag in slots under which streaming resumes after catching up,
+	// defaults to 32. The remaining slots are backfilled once the stream runs again.
+	ResumeLag uint64
+
+	// BatchSlots is the most slots a single Backfill call covers, so the lag is measured
+	// between batches, defaults to 5000
+	BatchSlots uint64
+
+	// Stream processes live slots until the context is cancelled, reporting them with
+	// Observe, e.g. geyser.Client.Run with a consumer of its results. It should reconnect
+	// on its own, the monitor fails once it returns. Optional.
+	Stream func(ctx context.Context) error
+
+	// Backfill processes every slot in the inclusive range, e.g. a backfill.Backfiller over
+	// blocks from FromSlot to ToSlot. Without it the lag is only monitored. Ranges may
+	// overlap the streamed slots by a few slots, so consumers should deduplicate.
+	Backfill func(ctx context.Context, from, to uint64) error
+
+	// OnLag is called with the lag after every poll
+	OnLag func(lag Lag)
+
+	// OnModeChange is called when the monitor switches between streaming and catching up
+	OnModeChange func(from, to Mode, lag Lag)
+
+	// Metrics records the lag and catch-ups, optional
+	Metrics Metrics
+
+	Logger logging.Logger
+}
@@ -0,0 +1,149 @@
+package cex
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// embeddedDataset is the exchange wallet list shipped with the package
+//
+//go:embed exchanges.json
+var embeddedDataset []byte
+
+// Classifier tags transfers to and from known exchange hot wallets. It is safe for
+// concurrent use, including while the dataset is updated.
+type Classifier struct {
+	overrides []Wallet
+
+	mu      sync.RWMutex
+	version string
+	wallets map[solana.PublicKey]Wallet
+}
+
+// New creates a classifier from the embedded dataset, or the file at DatasetPath
+func New(config Config) (*Classifier, error) {
+	c := &Classifier{overrides: config.Overrides}
+
+	dataset := io.Reader(bytes.NewReader(embeddedDataset))
+	if config.DatasetPath != "" {
+		file, err := os.Open(config.DatasetPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dataset: %w", err)
+		}
+		defer file.Close()
+		dataset = file
+	}
+
+	if err := c.Update(dataset); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Update replaces the dataset with a newer JSON document, keeping the overrides
+func (c *Classifier) Update(r io.Reader) error {
+	var dataset Dataset
+	if err := json.NewDecoder(r).Decode(&dataset); err != nil {
+		return fmt.Errorf("failed to decode dataset: %w", err)
+	}
+
+	wallets := make(map[solana.PublicKey]Wallet, len(dataset.Wallets)+len(c.overrides))
+	for _, wallet := range dataset.Wallets {
+		if wallet.Exchange == "" {
+			return fmt.Errorf("dataset wallet %s has no exchange", wallet.Address)
+		}
+		wallets[wallet.Address] = wallet
+	}
+	for _, wallet := range c.overrides {
+		if wallet.Exchange == "" {
+			delete(wallets, wallet.Address)
+			continue
+		}
+		wallets[wallet.Address] = wallet
+	}
+
+	c.mu.Lock()
+	c.version, c.wallets = dataset.Version, wallets
+	c.mu.Unlock()
+	return nil
+}
+
+// Version returns the version of the loaded dataset
+func (c *Classifier) Version() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.version
+}
+
+// Lookup returns the exchange wallet at the address
+func (c *Classifier) Lookup(address solana.PublicKey) (Wallet, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	wallet, ok := c.wallets[address]
+	return wallet, ok
+}
+
+// Wallets returns every known exchange wallet, overrides included
+func (c *Classifier) Wallets() []Wallet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	wallets := make([]Wallet, 0, len(c.wallets))
+	for _, wallet := range c.wallets {
+		wallets = append(wallets, wallet)
+	}
+	return wallets
+}
+
+// ClassifyTransfer tags a transfer by its owners, returning false when neither side is
+// an exchange wallet
+func (c *Classifier) ClassifyTransfer(transfer *tx_parser.TransferInfo) (*Classification, bool) {
+	source, destination := transfer.SourceOwner, transfer.DestinationOwner
+	if source.IsZero() {
+		source = transfer.Source
+	}
+	if destination.IsZero() {
+		destination = transfer.Destination
+	}
+
+	from, fromExchange := c.Lookup(source)
+	to, toExchange := c.Lookup(destination)
+	classification := &Classification{Transfer: transfer}
+	switch {
+	case fromExchange && toExchange:
+		classification.Flow = FlowInterExchange
+		if from.Exchange == to.Exchange {
+			classification.Flow = FlowInternal
+		}
+		classification.Exchange, classification.ExchangeWallet, classification.Wallet = from.Exchange, source, destination
+	case fromExchange:
+		classification.Flow = FlowWithdrawal
+		classification.Exchange, classification.ExchangeWallet, classification.Wallet = from.Exchange, source, destination
+	case toExchange:
+		classification.Flow = FlowDeposit
+		classification.Exchange, classification.ExchangeWallet, classification.Wallet = to.Exchange, destination, source
+	default:
+		return nil, false
+	}
+	return classification, true
+}
+
+// Classify tags every transfer of the transaction that touches an exchange wallet
+func (c *Classifier) Classify(tx *tx_parser.ParsedTransaction) []*Classification {
+	var classifications []*Classification
+	for i, transfer := range tx.Transfers {
+		if classification, ok := c.ClassifyTransfer(transfer); ok {
+			classification.Index = i
+			classifications = append(classifications, classification)
+		}
+	}
+	return classifications
+}
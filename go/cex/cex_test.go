@@ -0,0 +1,95 @@
+package cex
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	binance  = solana.MustPublicKeyFromBase58("5tzFkiKscXHK5ZXCGbXZxdw7gTjjD1mBwuoFbhUvuAi9")
+	binance2 = solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	coinbase = solana.MustPublicKeyFromBase58("H8sMJSCQxfKiFTCfDR3DUMLPwcRbM61LGFJ8N4dK3WjS")
+	kraken   = solana.MustPublicKeyFromBase58("FWznbcNXWQuHTawe9RxvQ2LdCENssh12dsznf4RiouN5")
+)
+
+func TestClassify(t *testing.T) {
+	user, desk := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	classifier, err := New(Config{Overrides: []Wallet{
+		{Address: desk, Exchange: "Desk"},
+		{Address: kraken}, // dropped from the dataset
+	}})
+	if err != nil {
+		t.Fatalf("failed to load the embedded dataset: %v", err)
+	}
+	if classifier.Version() == "" {
+		t.Error("expected the embedded dataset to be versioned")
+	}
+	if wallets := classifier.Wallets(); !slices.ContainsFunc(wallets, func(wallet Wallet) bool { return wallet.Address.Equals(desk) }) ||
+		slices.ContainsFunc(wallets, func(wallet Wallet) bool { return wallet.Address.Equals(kraken) }) {
+		t.Error("expected the wallets to include the overrides")
+	}
+
+	tx := &tx_parser.ParsedTransaction{Transfers: []*tx_parser.TransferInfo{
+		{SourceOwner: user, DestinationOwner: binance},
+		{SourceOwner: user, DestinationOwner: user},
+		// system transfers without owners fall back to the accounts
+		{Source: coinbase, Destination: user},
+		{SourceOwner: binance, DestinationOwner: binance2},
+		{SourceOwner: binance, DestinationOwner: coinbase},
+		{SourceOwner: desk, DestinationOwner: user},
+		{SourceOwner: kraken, DestinationOwner: user},
+	}}
+
+	classifications := classifier.Classify(tx)
+	if len(classifications) != 5 {
+		t.Fatalf("expected 5 classifications, got %d", len(classifications))
+	}
+
+	deposit := classifications[0]
+	if deposit.Flow != FlowDeposit || deposit.Exchange != "Binance" || !deposit.ExchangeWallet.Equals(binance) || !deposit.Wallet.Equals(user) || deposit.Index != 0 {
+		t.Errorf("unexpected deposit: %+v", deposit)
+	}
+	withdrawal := classifications[1]
+	if withdrawal.Flow != FlowWithdrawal || withdrawal.Exchange != "Coinbase" || !withdrawal.Wallet.Equals(user) || withdrawal.Index != 2 {
+		t.Errorf("unexpected withdrawal: %+v", withdrawal)
+	}
+	if classifications[2].Flow != FlowInternal || classifications[3].Flow != FlowInterExchange || classifications[3].Exchange != "Binance" {
+		t.Errorf("unexpected exchange to exchange flows: %+v, %+v", classifications[2], classifications[3])
+	}
+	if override := classifications[4]; override.Exchange != "Desk" || override.Flow != FlowWithdrawal {
+		t.Errorf("expected the override to classify, got %+v", override)
+	}
+
+	// Overrides are kept when the dataset is replaced
+	dataset := filepath.Join(t.TempDir(), "exchanges.json")
+	err = os.WriteFile(dataset, []byte(`{"version":"test","wallets":[{"address":"`+kraken.String()+`","exchange":"Kraken"}]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	classifier, err = New(Config{DatasetPath: dataset, Overrides: []Wallet{{Address: desk, Exchange: "Desk"}}})
+	if err != nil {
+		t.Fatalf("failed to load the dataset file: %v", err)
+	}
+	if _, ok := classifier.Lookup(binance); ok || classifier.Version() != "test" {
+		t.Error("expected the file to replace the embedded dataset")
+	}
+	if err := classifier.Update(strings.NewReader(`{"version":"next","wallets":[]}`)); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if _, ok := classifier.Lookup(kraken); ok {
+		t.Error("expected the update to replace the dataset")
+	}
+	if wallet, ok := classifier.Lookup(desk); !ok || wallet.Exchange != "Desk" {
+		t.Error("expected overrides to survive the update")
+	}
+	if err := classifier.Update(strings.NewReader(`{"wallets":[{"address":"` + user.String() + `"}]}`)); err == nil {
+		t.Error("expected a wallet without an exchange to be rejected")
+	}
+}
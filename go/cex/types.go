@@ -0,0 +1,49 @@
+package cex
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Flow is the direction funds move relative to an exchange
+type Flow string
+
+const (
+	FlowDeposit       Flow = "deposit"        // into an exchange wallet
+	FlowWithdrawal    Flow = "withdrawal"     // out of an exchange wallet
+	FlowInternal      Flow = "internal"       // between wallets of the same exchange
+	FlowInterExchange Flow = "inter_exchange" // from one exchange to another
+)
+
+// Wallet is a known exchange hot wallet
+type Wallet struct {
+	Address  solana.PublicKey `json:"address"`
+	Exchange string           `json:"exchange"`        // empty in an override to drop the address from the dataset
+	Label    string           `json:"label,omitempty"` // e.g. Hot Wallet 2
+}
+
+// Dataset is the JSON document listing exchange wallets, in the format of the embedded dataset
+type Dataset struct {
+	Version string   `json:"version"`
+	Wallets []Wallet `json:"wallets"`
+}
+
+// Config controls the exchange wallets the classifier knows
+type Config struct {
+	// DatasetPath replaces the embedded dataset with a JSON file in the same format
+	DatasetPath string
+
+	// Overrides add, relabel or drop dataset wallets and survive dataset updates
+	Overrides []Wallet
+}
+
+// Classification tags a transfer to or from an exchange wallet
+type Classification struct {
+	Flow           Flow                    `json:"flow"`
+	Exchange       string                  `json:"exchange"`        // the sending exchange for inter-exchange flows
+	ExchangeWallet solana.PublicKey        `json:"exchange_wallet"` // the exchange side of the transfer
+	Wallet         solana.PublicKey        `json:"wallet"`          // depositor, withdrawal recipient or receiving exchange wallet
+	Index          int                     `json:"index"`           // position of the transfer in the transaction
+	Transfer       *tx_parser.TransferInfo `json:"transfer"`
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/corpus"
+)
+
+// corpusCmd manages the golden-file parser corpus
+func corpusCmd(args []string) error {
+	if len(args) == 0 || args[0] != "record" {
+		return fmt.Errorf("expected corpus record")
+	}
+	return corpusRecord(args[1:])
+}
+
+// corpusRecord captures a transaction into the golden-file corpus
+func corpusRecord(args []string) error {
+	flags := flag.NewFlagSet("corpus record", flag.ContinueOnError)
+	rpcURL := rpcFlag(flags)
+	sig := flags.String("sig", "", "signature of the transaction to record")
+	protocol := flags.String("protocol", "", "protocol directory of the corpus, e.g. raydium")
+	dir := flags.String("dir", corpus.DEFAULT_DIR, "corpus directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *sig == "" || *protocol == "" {
+		return fmt.Errorf("--sig and --protocol are required")
+	}
+	signature, err := solana.SignatureFromBase58(*sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	fixture, err := corpus.Record(context.Background(), rpc.New(*rpcURL), signature, *dir, *protocol)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %s and %s\n", fixture.Path, fixture.GoldenPath)
+	fmt.Println("review the golden file before committing it")
+	return nil
+}
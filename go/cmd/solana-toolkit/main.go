@@ -0,0 +1,93 @@
+// Command solana-toolkit runs the toolkit's parsers and pipelines from the command line.
+//
+// Usage:
+//
+//	solana-toolkit parse tx <signature> [--rpc <url>] [--format text|json] [--strict]
+//	solana-toolkit stream --programs <ids> [--out json|kafka|postgres|clickhouse|parquet|redis|nats|csv|grpc|webhook] [--config <file.yaml>]
+//	solana-toolkit wallet <address> [--since <date>] [--format table|csv|json|form8949|turbotax|detailed]
+//	solana-toolkit token <mint> [--format text|json]
+//	solana-toolkit corpus record --sig <signature> --protocol <name> [--rpc <url>] [--dir <dir>]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+
+	"github.com/soralabs/solana-toolkit/go/batcher"
+)
+
+// RPC_URL_ENV overrides the default RPC endpoint of every command
+const RPC_URL_ENV = "SOLANA_RPC_URL"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "parse":
+		err = parseCmd(os.Args[2:])
+	case "stream":
+		err = streamCmd(os.Args[2:])
+	case "wallet":
+		err = walletCmd(os.Args[2:])
+	case "token":
+		err = tokenCmd(os.Args[2:])
+	case "corpus":
+		err = corpusCmd(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "solana-toolkit: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: solana-toolkit parse tx <signature> [--rpc <url>] [--format text|json] [--strict]")
+	fmt.Fprintln(os.Stderr, "       solana-toolkit stream --programs <ids> [--out json|kafka|postgres|clickhouse|parquet|redis|nats|csv|grpc|webhook] [--config <file.yaml>]")
+	fmt.Fprintln(os.Stderr, "       solana-toolkit wallet <address> [--since <date>] [--format table|csv|json|form8949|turbotax|detailed]")
+	fmt.Fprintln(os.Stderr, "       solana-toolkit token <mint> [--format text|json]")
+	fmt.Fprintln(os.Stderr, "       solana-toolkit corpus record --sig <signature> --protocol <name> [--rpc <url>] [--dir <dir>]")
+}
+
+// rpcFlag registers the --rpc flag, defaulting to $SOLANA_RPC_URL or mainnet
+func rpcFlag(flags *flag.FlagSet) *string {
+	url := os.Getenv(RPC_URL_ENV)
+	if url == "" {
+		url = rpc.MainNetBeta_RPC
+	}
+	return flags.String("rpc", url, "RPC endpoint, defaults to $"+RPC_URL_ENV+" or mainnet")
+}
+
+// batchedClient returns a client of the endpoint coalescing the account lookups of every
+// resolver sharing it
+func batchedClient(url string) *rpc.Client {
+	return batcher.New(jsonrpc.NewClient(url), batcher.Config{}).Client()
+}
+
+// parseFlags parses flags placed before or after positional arguments and returns the
+// positional ones
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
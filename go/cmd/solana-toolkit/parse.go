@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/decimals"
+	"github.com/soralabs/solana-toolkit/go/internal/owners"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// parseCmd dispatches the parse subcommands
+func parseCmd(args []string) error {
+	if len(args) == 0 || args[0] != "tx" {
+		return fmt.Errorf("unknown parse command, expected: parse tx <signature>")
+	}
+	return parseTx(args[1:])
+}
+
+// parseTx fetches a transaction and prints everything the parsers extract from it
+func parseTx(args []string) error {
+	flags := flag.NewFlagSet("parse tx", flag.ContinueOnError)
+	rpcURL := rpcFlag(flags)
+	format := flags.String("format", "text", "output format: text or json")
+	strict := flags.Bool("strict", false, "fail on the first instruction that cannot be parsed")
+	idls := flags.String("idl", "", "comma separated Anchor IDL files naming the custom errors of failed transactions")
+	showLogs := flags.Bool("logs", false, "print the program invocations reconstructed from the logs, text format only")
+	timeout := flags.Duration("timeout", 30*time.Second, "RPC timeout")
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one transaction signature")
+	}
+	signature, err := solana.SignatureFromBase58(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid signature %q: %w", positional[0], err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	rpcClient := batchedClient(*rpcURL)
+	maxVersion := uint64(0)
+	result, err := rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	programErrors := tx_parser.ProgramErrors{}
+	if *idls != "" {
+		for _, path := range strings.Split(*idls, ",") {
+			idl, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read IDL: %w", err)
+			}
+			if err := programErrors.LoadIDL(solana.PublicKey{}, idl); err != nil {
+				return fmt.Errorf("failed to load IDL %s: %w", path, err)
+			}
+		}
+	}
+
+	mints := decimals.NewResolver(rpcClient, decimals.Options{})
+	parser, err := tx_parser.NewWithOptions(result, tx_parser.ParseOptions{
+		Strict:                  *strict,
+		AnalyzeFailures:         true,
+		ProgramErrors:           programErrors,
+		DecimalsResolver:        mints,
+		TokenAccountResolver:    owners.NewResolver(rpcClient, owners.Options{}),
+		TokenExtensionsResolver: mints,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load transaction: %w", err)
+	}
+	parsed, err := parser.Parse()
+	if err != nil {
+		return fmt.Errorf("failed to parse transaction: %w", err)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(parsed)
+	case "text":
+		if err := writeParsed(os.Stdout, parsed); err != nil {
+			return err
+		}
+		if *showLogs {
+			return writeProgramLogs(os.Stdout, parser.ProgramLogs())
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// writeParsed prints a parsed transaction as readable text
+func writeParsed(out io.Writer, parsed *tx_parser.ParsedTransaction) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "signature\t%s\n", parsed.Signature)
+	fmt.Fprintf(w, "slot\t%d\n", parsed.Slot)
+	if parsed.BlockTime != nil {
+		fmt.Fprintf(w, "time\t%s\n", parsed.BlockTime.Time().UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, "fee payer\t%s\n", parsed.FeePayer)
+	fmt.Fprintf(w, "fee\t%s SOL\n", formatAmount(parsed.Fee, 9))
+	if parsed.ComputeBudget != nil && parsed.ComputeBudget.PriorityFee > 0 {
+		fmt.Fprintf(w, "priority fee\t%s SOL\n", formatAmount(parsed.ComputeBudget.PriorityFee, 9))
+	}
+	if parsed.JitoTip > 0 {
+		fmt.Fprintf(w, "jito tip\t%s SOL\n", formatAmount(parsed.JitoTip, 9))
+	}
+	if failure := parsed.Failure; failure != nil {
+		reason := failure.Error
+		if failure.InstructionError != "" {
+			reason = fmt.Sprintf("instruction %d: %s", failure.InstructionIndex, failure.InstructionError)
+		}
+		if failure.ErrorName != "" {
+			reason += " " + failure.ErrorName
+		}
+		fmt.Fprintf(w, "failed\t%s (%s)\n", reason, failure.Kind)
+		if !failure.Program.IsZero() {
+			fmt.Fprintf(w, "failed program\t%s\n", failure.Program)
+		}
+	}
+
+	if len(parsed.Swaps) > 0 {
+		fmt.Fprintf(w, "\nswaps\n")
+		for _, swap := range parsed.Swaps {
+			fmt.Fprintf(w, "  %s\t%s %s\t->\t%s %s\n", swap.Protocol,
+				formatAmount(swap.TokenIn.Amount, swap.TokenIn.Decimals), swap.TokenIn.Mint,
+				formatAmount(swap.TokenOut.Amount, swap.TokenOut.Decimals), swap.TokenOut.Mint)
+		}
+	}
+	if len(parsed.Transfers) > 0 {
+		fmt.Fprintf(w, "\ntransfers\n")
+		for _, transfer := range parsed.Transfers {
+			mint := "SOL"
+			if !transfer.Mint.IsZero() {
+				mint = transfer.Mint.String()
+			}
+			fmt.Fprintf(w, "  %s\t%s %s\t%s\t->\t%s\n", transfer.Type, formatAmount(transfer.Amount, transfer.Decimals), mint,
+				owner(transfer.SourceOwner, transfer.Source), owner(transfer.DestinationOwner, transfer.Destination))
+		}
+	}
+	for _, memo := range parsed.Memos {
+		fmt.Fprintf(w, "\nmemo\t%s\n", memo.Text)
+	}
+	if len(parsed.Errors) > 0 {
+		fmt.Fprintf(w, "\nparse errors\n")
+		for _, parseErr := range parsed.Errors {
+			fmt.Fprintf(w, "  %v\n", parseErr)
+		}
+	}
+	return w.Flush()
+}
+
+// writeProgramLogs prints each program invocation with what it logged, nested by stack height
+func writeProgramLogs(out io.Writer, invocations []*tx_parser.ProgramInvocation) error {
+	if _, err := fmt.Fprintf(out, "\nprogram logs\n"); err != nil {
+		return err
+	}
+	var write func(invocations []*tx_parser.ProgramInvocation) error
+	write = func(invocations []*tx_parser.ProgramInvocation) error {
+		for _, invocation := range invocations {
+			indent := strings.Repeat("  ", invocation.StackHeight)
+			status := string(invocation.Status)
+			if invocation.Error != "" {
+				status += ": " + invocation.Error
+			}
+			if _, err := fmt.Fprintf(out, "%s#%d %s (%s, %d CU)\n", indent, invocation.InstructionIndex, invocation.Program, status, invocation.ComputeUnits); err != nil {
+				return err
+			}
+			for _, line := range invocation.Logs {
+				if _, err := fmt.Fprintf(out, "%s  | %s\n", indent, line); err != nil {
+					return err
+				}
+			}
+			if err := write(invocation.Invocations); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return write(invocations)
+}
+
+// owner prefers the wallet owning a token account over the account itself
+func owner(owner, account solana.PublicKey) solana.PublicKey {
+	if owner.IsZero() {
+		return account
+	}
+	return owner
+}
+
+// formatAmount renders a raw token amount with its decimals
+func formatAmount(amount uint64, decimals uint8) string {
+	if decimals == 0 {
+		return strconv.FormatUint(amount, 10)
+	}
+	s := fmt.Sprintf("%0*d", int(decimals)+1, amount)
+	whole, fraction := s[:len(s)-int(decimals)], s[len(s)-int(decimals):]
+	for len(fraction) > 0 && fraction[len(fraction)-1] == '0' {
+		fraction = fraction[:len(fraction)-1]
+	}
+	if fraction == "" {
+		return whole
+	}
+	return whole + "." + fraction
+}
@@ -0,0 +1,749 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"gopkg.in/yaml.v3"
+
+	"github.com/soralabs/solana-toolkit/go/alerts"
+	"github.com/soralabs/solana-toolkit/go/enrich"
+	"github.com/soralabs/solana-toolkit/go/geyser"
+	"github.com/soralabs/solana-toolkit/go/grpc_server"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/metrics"
+	"github.com/soralabs/solana-toolkit/go/mev"
+	"github.com/soralabs/solana-toolkit/go/pb"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+	"github.com/soralabs/solana-toolkit/go/redis"
+	"github.com/soralabs/solana-toolkit/go/sink"
+	"github.com/soralabs/solana-toolkit/go/sink/avro"
+	"github.com/soralabs/solana-toolkit/go/sink/clickhouse"
+	"github.com/soralabs/solana-toolkit/go/sink/csv"
+	"github.com/soralabs/solana-toolkit/go/sink/dedupe"
+	"github.com/soralabs/solana-toolkit/go/sink/kafka"
+	"github.com/soralabs/solana-toolkit/go/sink/nats"
+	"github.com/soralabs/solana-toolkit/go/sink/parquet"
+	"github.com/soralabs/solana-toolkit/go/sink/postgres"
+	"github.com/soralabs/solana-toolkit/go/sink/webhook"
+	"github.com/soralabs/solana-toolkit/go/stream"
+)
+
+// SLOT_LAG_INTERVAL is how often the stream command polls the chain tip for the slot lag metric
+const SLOT_LAG_INTERVAL = 5 * time.Second
+
+// streamConfig is the YAML configuration of the stream command, flags override it
+type streamConfig struct {
+	Programs   []string `yaml:"programs"`
+	Source     string   `yaml:"source"` // websocket or geyser
+	RPC        string   `yaml:"rpc"`
+	WS         string   `yaml:"ws"`
+	Mode       string   `yaml:"mode"` // logs or blocks, websocket only
+	Commitment string   `yaml:"commitment"`
+	Geyser     struct {
+		Endpoint string `yaml:"endpoint"`
+		Token    string `yaml:"token"`
+		Insecure bool   `yaml:"insecure"`
+	} `yaml:"geyser"`
+	Out   string `yaml:"out"` // json, kafka, postgres, clickhouse, parquet, redis, nats, csv, grpc or webhook
+	Kafka struct {
+		Brokers     []string `yaml:"brokers"`
+		Topic       string   `yaml:"topic"`
+		PartitionBy string   `yaml:"partition_by"`
+		Encoding    string   `yaml:"encoding"` // json, protobuf or avro
+		Registry    struct {
+			URL      string `yaml:"url"`
+			Username string `yaml:"username"`
+			Password string `yaml:"password"`
+		} `yaml:"schema_registry"` // registers the avro schemas, bare avro without it
+	} `yaml:"kafka"`
+	Postgres struct {
+		DSN string `yaml:"dsn"`
+	} `yaml:"postgres"`
+	ClickHouse struct {
+		Addr        []string `yaml:"addr"`
+		Database    string   `yaml:"database"`
+		Username    string   `yaml:"username"`
+		Password    string   `yaml:"password"`
+		Table       string   `yaml:"table"`
+		CreateTable bool     `yaml:"create_table"`
+		AsyncInsert bool     `yaml:"async_insert"`
+	} `yaml:"clickhouse"`
+	Parquet struct {
+		Dir         string `yaml:"dir"`
+		PartitionBy string `yaml:"partition_by"` // date or slot
+		Compression string `yaml:"compression"`  // zstd, snappy, gzip or uncompressed
+	} `yaml:"parquet"`
+	GRPC struct {
+		Listen string `yaml:"listen"`
+	} `yaml:"grpc"`
+	Webhook struct {
+		URLs    []string `yaml:"urls"`
+		Secret  string   `yaml:"secret"`
+		Mints   []string `yaml:"mints"`
+		Wallets []string `yaml:"wallets"`
+		MinUSD  float64  `yaml:"min_usd"` // skips swaps worth less, requires the usd enrichment stage
+	} `yaml:"webhook"`
+	BatchSize     int           `yaml:"batch_size"`
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	Metrics       string        `yaml:"metrics"` // listen address of the /metrics endpoint, empty disables it
+	Enrich        []string      `yaml:"enrich"`  // enrichment stages in order: usd, mev
+	Redis         struct {
+		Addr   string `yaml:"addr"`
+		Mode   string `yaml:"mode"` // stream or pubsub
+		Key    string `yaml:"key"`  // stream or channel, {kind} is replaced by the event kind
+		MaxLen int64  `yaml:"max_len"`
+	} `yaml:"redis"`
+	NATS struct {
+		URL     string `yaml:"url"`
+		Stream  string `yaml:"stream"`  // JetStream stream created to capture the subjects
+		Prefix  string `yaml:"prefix"`  // prepended to every subject
+		Subject string `yaml:"subject"` // subject of kinds other than swaps and transfers
+	} `yaml:"nats"`
+	CSV struct {
+		Dir     string              `yaml:"dir"`
+		Locale  string              `yaml:"locale"`  // number format: en, or de, fr... for decimal commas
+		Columns map[string][]string `yaml:"columns"` // columns of the swaps and transfers files, all by default
+		MaxRows int                 `yaml:"max_rows"`
+	} `yaml:"csv"`
+	Dedupe struct {
+		Store string        `yaml:"store"` // memory or redis, empty disables deduplication
+		TTL   time.Duration `yaml:"ttl"`   // how long Redis remembers written events
+	} `yaml:"dedupe"`
+	Alerts struct {
+		Rules    []alertRule `yaml:"rules"`
+		Webhooks map[string]struct {
+			URL    string `yaml:"url"`
+			Secret string `yaml:"secret"`
+		} `yaml:"webhooks"` // notifiers by name
+		Telegram map[string]struct {
+			Token  string `yaml:"token"`
+			ChatID string `yaml:"chat_id"`
+		} `yaml:"telegram"`
+		Discord map[string]struct {
+			URL string `yaml:"url"`
+		} `yaml:"discord"`
+		Explorer string `yaml:"explorer"` // base URL of the links in chat messages
+	} `yaml:"alerts"`
+}
+
+// alertRule is the YAML form of alerts.Rule
+type alertRule struct {
+	Name            string        `yaml:"name"`
+	Kinds           []string      `yaml:"kinds"`
+	Mints           []string      `yaml:"mints"`
+	Wallets         []string      `yaml:"wallets"`
+	Protocols       []string      `yaml:"protocols"`
+	Side            string        `yaml:"side"` // buy or sell
+	MinUSD          float64       `yaml:"min_usd"`
+	MinLiquiditySOL float64       `yaml:"min_liquidity_sol"`
+	Cooldown        time.Duration `yaml:"cooldown"`
+	Notifiers       []string      `yaml:"notifiers"`
+}
+
+// streamCmd runs an indexer from the stream sources into a sink until interrupted
+func streamCmd(args []string) error {
+	flags := flag.NewFlagSet("stream", flag.ContinueOnError)
+	configPath := flags.String("config", "", "YAML config file, flags override its values")
+	rpcURL := rpcFlag(flags)
+	programs := flags.String("programs", "", "comma separated program IDs to stream")
+	source := flags.String("source", "websocket", "transaction source: websocket or geyser")
+	wsURL := flags.String("ws", "", "WebSocket endpoint, derived from --rpc by default")
+	mode := flags.String("mode", string(stream.ModeLogs), "WebSocket subscription: logs or blocks")
+	commitment := flags.String("commitment", string(rpc.CommitmentConfirmed), "commitment level")
+	geyserEndpoint := flags.String("geyser", "", "Geyser gRPC endpoint")
+	geyserToken := flags.String("geyser-token", "", "Geyser x-token")
+	output := flags.String("out", "json", "sink: json, kafka, postgres, clickhouse, parquet, redis, nats, csv, grpc or webhook")
+	brokers := flags.String("kafka-brokers", "", "comma separated Kafka brokers")
+	topic := flags.String("kafka-topic", "solana-events", "Kafka topic")
+	encoding := flags.String("kafka-encoding", "json", "Kafka message encoding: json, protobuf or avro")
+	registryURL := flags.String("schema-registry", "", "Confluent Schema Registry URL of the avro encoding")
+	dsn := flags.String("postgres-dsn", "", "Postgres connection string")
+	clickhouseAddr := flags.String("clickhouse-addr", "", "comma separated ClickHouse native protocol addresses")
+	parquetDir := flags.String("parquet-dir", "", "root directory of the parquet sink's files")
+	listen := flags.String("grpc-listen", grpc_server.DEFAULT_ADDR, "address the gRPC streaming service listens on")
+	webhookURLs := flags.String("webhook-urls", "", "comma separated webhook URLs")
+	webhookSecret := flags.String("webhook-secret", "", "secret signing webhook deliveries")
+	webhookMinUSD := flags.Float64("webhook-min-usd", 0, "skip swaps worth less in USD, requires --enrich usd")
+	metricsAddr := flags.String("metrics", "", "address serving Prometheus metrics on /metrics, e.g. :9100")
+	batchSize := flags.Int("batch", 100, "events written per batch")
+	flushInterval := flags.Duration("flush", time.Second, "maximum delay before a partial batch is written")
+	enrichStages := flags.String("enrich", "", "comma separated enrichment stages run in order: usd, mev")
+	dedupeStore := flags.String("dedupe", "", "drop events already written, remembered in memory or redis")
+	redisAddr := flags.String("redis", "", "Redis address of the redis sink and dedupe store")
+	redisMode := flags.String("redis-mode", string(redis.ModeStream), "redis sink delivery: stream or pubsub")
+	natsURL := flags.String("nats", nats.DEFAULT_URL, "NATS server URL")
+	csvDir := flags.String("csv-dir", "", "directory of the csv sink's files")
+	csvLocale := flags.String("csv-locale", "en", "number format of the csv sink: en, or de, fr... for decimal commas")
+	natsStream := flags.String("nats-stream", "", "JetStream stream created to capture the published subjects")
+	if _, err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	var config streamConfig
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to decode config: %w", err)
+		}
+	}
+
+	// flags given explicitly, or config values left unset, take the flag value
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	override := func(name string, target *string, value string) {
+		if set[name] || *target == "" {
+			*target = value
+		}
+	}
+	override("rpc", &config.RPC, *rpcURL)
+	override("source", &config.Source, *source)
+	override("ws", &config.WS, *wsURL)
+	override("mode", &config.Mode, *mode)
+	override("commitment", &config.Commitment, *commitment)
+	override("geyser", &config.Geyser.Endpoint, *geyserEndpoint)
+	override("geyser-token", &config.Geyser.Token, *geyserToken)
+	override("out", &config.Out, *output)
+	override("kafka-topic", &config.Kafka.Topic, *topic)
+	override("kafka-encoding", &config.Kafka.Encoding, *encoding)
+	override("schema-registry", &config.Kafka.Registry.URL, *registryURL)
+	override("postgres-dsn", &config.Postgres.DSN, *dsn)
+	override("parquet-dir", &config.Parquet.Dir, *parquetDir)
+	override("grpc-listen", &config.GRPC.Listen, *listen)
+	override("webhook-secret", &config.Webhook.Secret, *webhookSecret)
+	override("metrics", &config.Metrics, *metricsAddr)
+	override("dedupe", &config.Dedupe.Store, *dedupeStore)
+	override("redis", &config.Redis.Addr, *redisAddr)
+	override("redis-mode", &config.Redis.Mode, *redisMode)
+	override("nats", &config.NATS.URL, *natsURL)
+	override("nats-stream", &config.NATS.Stream, *natsStream)
+	override("csv-dir", &config.CSV.Dir, *csvDir)
+	override("csv-locale", &config.CSV.Locale, *csvLocale)
+	if set["programs"] || len(config.Programs) == 0 {
+		config.Programs = splitList(*programs)
+	}
+	if set["kafka-brokers"] || len(config.Kafka.Brokers) == 0 {
+		config.Kafka.Brokers = splitList(*brokers)
+	}
+	if set["clickhouse-addr"] || len(config.ClickHouse.Addr) == 0 {
+		config.ClickHouse.Addr = splitList(*clickhouseAddr)
+	}
+	if set["webhook-urls"] || len(config.Webhook.URLs) == 0 {
+		config.Webhook.URLs = splitList(*webhookURLs)
+	}
+	if set["webhook-min-usd"] {
+		config.Webhook.MinUSD = *webhookMinUSD
+	}
+	if set["batch"] || config.BatchSize == 0 {
+		config.BatchSize = *batchSize
+	}
+	if set["flush"] || config.FlushInterval == 0 {
+		config.FlushInterval = *flushInterval
+	}
+	if set["enrich"] || len(config.Enrich) == 0 {
+		config.Enrich = splitList(*enrichStages)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	out, err := openSink(ctx, config)
+	if err != nil {
+		return err
+	}
+	if out, err = alertSink(config, out); err != nil {
+		out.Close()
+		return err
+	}
+	if out, err = dedupeSink(config, out); err != nil {
+		out.Close()
+		return err
+	}
+	defer out.Close()
+
+	var monitor *metrics.Metrics
+	if config.Metrics != "" {
+		if monitor, err = serveMetrics(ctx, config); err != nil {
+			return err
+		}
+		out = monitor.InstrumentSink(config.Out, out)
+	}
+
+	results, run, err := openSource(config, monitor)
+	if err != nil {
+		return err
+	}
+	if results, err = enrichSource(ctx, config, results); err != nil {
+		return err
+	}
+	errs := make(chan error, 1)
+	go func() { errs <- run(ctx) }()
+
+	if err := pipe(ctx, results, out, config.BatchSize, config.FlushInterval); err != nil {
+		return err
+	}
+	if err := <-errs; err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// openSource builds the configured source, returning the channel parsed transactions
+// arrive on and the function that runs the stream. Transactions are recorded in the
+// metrics when enabled.
+func openSource(config streamConfig, monitor *metrics.Metrics) (<-chan *tx_parser.ParsedTransaction, func(context.Context) error, error) {
+	var programs []solana.PublicKey
+	for _, program := range config.Programs {
+		key, err := solana.PublicKeyFromBase58(program)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid program %q: %w", program, err)
+		}
+		programs = append(programs, key)
+	}
+	if len(programs) == 0 {
+		return nil, nil, fmt.Errorf("at least one program is required")
+	}
+	parsed := make(chan *tx_parser.ParsedTransaction, 1024)
+
+	switch config.Source {
+	case "websocket":
+		ws := config.WS
+		if ws == "" {
+			ws = strings.Replace(strings.Replace(config.RPC, "https://", "wss://", 1), "http://", "ws://", 1)
+		}
+		streamer := stream.New(rpc.New(config.RPC), stream.Config{
+			WSURL:      ws,
+			Programs:   programs,
+			Mode:       stream.Mode(config.Mode),
+			Commitment: rpc.CommitmentType(config.Commitment),
+			Logger:     slog.Default(),
+		})
+		return parsed, func(ctx context.Context) error {
+			go forward(streamer.Results(), parsed, monitor, config.Source, func(r *stream.Result) (*tx_parser.ParsedTransaction, error) { return r.Transaction, r.Err })
+			return streamer.Run(ctx)
+		}, nil
+	case "geyser":
+		if config.Geyser.Endpoint == "" {
+			return nil, nil, fmt.Errorf("a geyser endpoint is required")
+		}
+		client := geyser.New(geyser.Config{
+			Endpoint:   config.Geyser.Endpoint,
+			Token:      config.Geyser.Token,
+			Insecure:   config.Geyser.Insecure,
+			Programs:   programs,
+			Commitment: rpc.CommitmentType(config.Commitment),
+			Logger:     slog.Default(),
+		})
+		return parsed, func(ctx context.Context) error {
+			go forward(client.Results(), parsed, monitor, config.Source, func(r *geyser.Result) (*tx_parser.ParsedTransaction, error) { return r.Transaction, r.Err })
+			return client.Run(ctx)
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown source %q", config.Source)
+	}
+}
+
+// enrichSource runs the parsed transactions through the configured enrichment stages,
+// returning the channel enriched transactions arrive on
+func enrichSource(ctx context.Context, config streamConfig, parsed <-chan *tx_parser.ParsedTransaction) (<-chan *tx_parser.ParsedTransaction, error) {
+	if len(config.Enrich) == 0 {
+		return parsed, nil
+	}
+
+	pipeline := enrich.New(enrich.Config{})
+	for _, stage := range config.Enrich {
+		switch stage {
+		case "usd":
+			pipeline.Use(enrich.Valuation(pricing.New(pricing.Config{})))
+		case "mev":
+			pipeline.Use(enrich.MEV(mev.New(mev.Config{})))
+		default:
+			return nil, fmt.Errorf("unknown enrichment stage %q", stage)
+		}
+	}
+
+	enriched := make(chan *enrich.Transaction, 1024)
+	out := make(chan *tx_parser.ParsedTransaction, 1024)
+	go func() {
+		if err := pipeline.Run(ctx, parsed, enriched); err != nil && ctx.Err() == nil {
+			log.Printf("enrichment failed: %v", err)
+		}
+	}()
+	go func() {
+		defer close(out)
+		for tx := range enriched {
+			out <- tx.ParsedTransaction
+		}
+	}()
+	return out, nil
+}
+
+// forward copies parsed transactions from a source's results, logging failures and
+// recording both in the metrics when enabled, and closes out when the source is done
+func forward[T any](results <-chan T, out chan<- *tx_parser.ParsedTransaction, monitor *metrics.Metrics, source string, unwrap func(T) (*tx_parser.ParsedTransaction, error)) {
+	defer close(out)
+	for result := range results {
+		tx, err := unwrap(result)
+		if err != nil {
+			log.Printf("failed to parse transaction: %v", err)
+			if monitor != nil {
+				monitor.ObserveTransactionError()
+			}
+			continue
+		}
+		if tx == nil {
+			continue
+		}
+		if monitor != nil {
+			monitor.ObserveTransaction(source, tx)
+		}
+		out <- tx
+	}
+}
+
+// serveMetrics serves the Prometheus metrics on the configured address and tracks the
+// slot lag against the RPC node until the context is cancelled
+func serveMetrics(ctx context.Context, config streamConfig) (*metrics.Metrics, error) {
+	monitor, err := metrics.New(metrics.Config{Commitment: rpc.CommitmentType(config.Commitment)})
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", monitor.Handler())
+	server := &http.Server{Addr: config.Metrics, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("metrics server error: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	go monitor.TrackSlotLag(ctx, rpc.New(config.RPC), SLOT_LAG_INTERVAL)
+	return monitor, nil
+}
+
+// openSink connects the configured sink
+func openSink(ctx context.Context, config streamConfig) (sink.Sink, error) {
+	switch config.Out {
+	case "json":
+		return &jsonSink{out: os.Stdout}, nil
+	case "kafka":
+		encoder, err := kafkaEncoder(config)
+		if err != nil {
+			return nil, err
+		}
+		return kafka.New(kafka.Config{
+			Brokers:     config.Kafka.Brokers,
+			Topic:       config.Kafka.Topic,
+			PartitionBy: kafka.PartitionKey(config.Kafka.PartitionBy),
+			Encoder:     encoder,
+		})
+	case "postgres":
+		if config.Postgres.DSN == "" {
+			return nil, fmt.Errorf("a postgres DSN is required")
+		}
+		return postgres.Open(ctx, config.Postgres.DSN)
+	case "clickhouse":
+		return clickhouse.New(ctx, clickhouse.Config{
+			Addr:        config.ClickHouse.Addr,
+			Database:    config.ClickHouse.Database,
+			Username:    config.ClickHouse.Username,
+			Password:    config.ClickHouse.Password,
+			Table:       config.ClickHouse.Table,
+			CreateTable: config.ClickHouse.CreateTable,
+			AsyncInsert: config.ClickHouse.AsyncInsert,
+			OnError:     func(err error) { log.Printf("clickhouse flush error: %v", err) },
+		})
+	case "parquet":
+		exporter := parquet.Config{Dir: config.Parquet.Dir, PartitionBy: parquet.Partition(config.Parquet.PartitionBy)}
+		if config.Parquet.Compression != "" {
+			var compression compress.Compression
+			if err := compression.UnmarshalText([]byte(strings.ToUpper(config.Parquet.Compression))); err != nil {
+				return nil, fmt.Errorf("unknown parquet compression %q", config.Parquet.Compression)
+			}
+			exporter.Compression = &compression
+		}
+		return parquet.New(exporter)
+	case "redis":
+		if config.Redis.Addr == "" {
+			return nil, fmt.Errorf("a redis address is required")
+		}
+		return redis.OpenSink(ctx, redis.Config{Addr: config.Redis.Addr}, redis.SinkConfig{
+			Mode:   redis.Mode(config.Redis.Mode),
+			Key:    config.Redis.Key,
+			MaxLen: config.Redis.MaxLen,
+		})
+	case "nats":
+		return nats.Open(ctx, nats.Config{
+			URL:     config.NATS.URL,
+			Stream:  config.NATS.Stream,
+			Prefix:  config.NATS.Prefix,
+			Subject: config.NATS.Subject,
+		})
+	case "csv":
+		format, err := csv.Locale(config.CSV.Locale)
+		if err != nil {
+			return nil, err
+		}
+		columns := make(map[csv.Table][]string)
+		for table, names := range config.CSV.Columns {
+			columns[csv.Table(table)] = names
+		}
+		return csv.New(csv.Config{Dir: config.CSV.Dir, Columns: columns, Format: format, MaxRows: config.CSV.MaxRows})
+	case "grpc":
+		server := grpc_server.New(grpc_server.Config{Addr: config.GRPC.Listen})
+		go func() {
+			if err := server.ListenAndServe(ctx); err != nil {
+				log.Printf("grpc server error: %v", err)
+			}
+		}()
+		return server, nil
+	case "webhook":
+		return openWebhook(config)
+	default:
+		return nil, fmt.Errorf("unknown sink %q", config.Out)
+	}
+}
+
+// kafkaEncoder returns the encoder of the configured Kafka encoding
+func kafkaEncoder(config streamConfig) (sink.Encoder, error) {
+	switch config.Kafka.Encoding {
+	case "json":
+		return sink.JSONEncoder{}, nil
+	case "protobuf":
+		return pb.EventEncoder{}, nil
+	case "avro":
+		var registry *avro.Registry
+		if config.Kafka.Registry.URL != "" {
+			registry = avro.NewRegistry(avro.RegistryConfig{
+				URL:      config.Kafka.Registry.URL,
+				Username: config.Kafka.Registry.Username,
+				Password: config.Kafka.Registry.Password,
+			})
+		}
+		return avro.NewEncoder(registry, avro.Config{})
+	default:
+		return nil, fmt.Errorf("unknown kafka encoding %q", config.Kafka.Encoding)
+	}
+}
+
+// dedupeSink wraps the sink to drop events it already received when deduplication is
+// configured
+func dedupeSink(config streamConfig, out sink.Sink) (sink.Sink, error) {
+	switch config.Dedupe.Store {
+	case "":
+		return out, nil
+	case "memory":
+		return dedupe.New(out, dedupe.NewMemoryStore(0)), nil
+	case "redis":
+		if config.Redis.Addr == "" {
+			return out, fmt.Errorf("a redis address is required")
+		}
+		client := redis.New(redis.Config{Addr: config.Redis.Addr})
+		return &closingSink{Sink: dedupe.New(out, dedupe.NewRedisStore(client, dedupe.RedisConfig{TTL: config.Dedupe.TTL})), close: client.Close}, nil
+	default:
+		return out, fmt.Errorf("unknown dedupe store %q", config.Dedupe.Store)
+	}
+}
+
+// alertSink evaluates the configured alert rules against the events written to out
+func alertSink(config streamConfig, out sink.Sink) (sink.Sink, error) {
+	if len(config.Alerts.Rules) == 0 {
+		return out, nil
+	}
+	notifiers := make(map[string]alerts.Notifier)
+	for name, hook := range config.Alerts.Webhooks {
+		notifier, err := alerts.NewWebhook(alerts.WebhookConfig{URL: hook.URL, Secret: hook.Secret})
+		if err != nil {
+			return out, fmt.Errorf("invalid alert webhook %q: %w", name, err)
+		}
+		notifiers[name] = notifier
+	}
+	message := alerts.MessageConfig{Explorer: config.Alerts.Explorer}
+	for name, bot := range config.Alerts.Telegram {
+		notifier, err := alerts.NewTelegram(alerts.TelegramConfig{Token: bot.Token, ChatID: bot.ChatID, Message: message})
+		if err != nil {
+			return out, fmt.Errorf("invalid alert telegram %q: %w", name, err)
+		}
+		notifiers[name] = notifier
+	}
+	for name, hook := range config.Alerts.Discord {
+		notifier, err := alerts.NewDiscord(alerts.DiscordConfig{URL: hook.URL, Message: message})
+		if err != nil {
+			return out, fmt.Errorf("invalid alert discord %q: %w", name, err)
+		}
+		notifiers[name] = notifier
+	}
+
+	keys := func(values []string) ([]solana.PublicKey, error) {
+		var result []solana.PublicKey
+		for _, value := range values {
+			key, err := solana.PublicKeyFromBase58(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", value, err)
+			}
+			result = append(result, key)
+		}
+		return result, nil
+	}
+	var rules []alerts.Rule
+	for _, r := range config.Alerts.Rules {
+		rule := alerts.Rule{
+			Name:            r.Name,
+			Side:            alerts.Side(r.Side),
+			MinUSD:          r.MinUSD,
+			MinLiquiditySOL: r.MinLiquiditySOL,
+			Cooldown:        r.Cooldown,
+			Notifiers:       r.Notifiers,
+		}
+		for _, kind := range r.Kinds {
+			rule.Kinds = append(rule.Kinds, sink.Kind(kind))
+		}
+		for _, protocol := range r.Protocols {
+			rule.Protocols = append(rule.Protocols, tx_parser.SwapType(protocol))
+		}
+		var err error
+		if rule.Mints, err = keys(r.Mints); err != nil {
+			return out, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		if rule.Wallets, err = keys(r.Wallets); err != nil {
+			return out, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	engine, err := alerts.New(alerts.Config{Rules: rules, Notifiers: notifiers, Logger: slog.Default()})
+	if err != nil {
+		return out, err
+	}
+	return alerts.NewSink(out, engine), nil
+}
+
+// openWebhook builds a webhook sink posting to every configured URL with the same filter
+func openWebhook(config streamConfig) (sink.Sink, error) {
+	var filter webhook.Filter
+	for _, value := range config.Webhook.Mints {
+		mint, err := solana.PublicKeyFromBase58(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook mint %q: %w", value, err)
+		}
+		filter.Mints = append(filter.Mints, mint)
+	}
+	for _, value := range config.Webhook.Wallets {
+		wallet, err := solana.PublicKeyFromBase58(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook wallet %q: %w", value, err)
+		}
+		filter.Wallets = append(filter.Wallets, wallet)
+	}
+
+	filter.MinUSD = config.Webhook.MinUSD
+
+	var webhooks []webhook.Webhook
+	for _, url := range config.Webhook.URLs {
+		webhooks = append(webhooks, webhook.Webhook{URL: url, Secret: config.Webhook.Secret, Filter: filter})
+	}
+	return webhook.New(webhook.Config{Webhooks: webhooks, Logger: slog.Default()})
+}
+
+// pipe batches the events of parsed transactions into the sink until the source closes,
+// writing partial batches after the flush interval
+func pipe(ctx context.Context, parsed <-chan *tx_parser.ParsedTransaction, out sink.Sink, batchSize int, flushInterval time.Duration) error {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []*sink.Event
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		// the final flush runs after the context is cancelled
+		if err := out.Write(context.WithoutCancel(ctx), batch); err != nil {
+			return fmt.Errorf("failed to write events: %w", err)
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		select {
+		case tx, ok := <-parsed:
+			if !ok {
+				return flush()
+			}
+			batch = append(batch, sink.Events(tx)...)
+			if len(batch) >= batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// closingSink closes a resource the sink uses after closing the sink
+type closingSink struct {
+	sink.Sink
+	close func() error
+}
+
+// Close closes the sink, then the resource
+func (s *closingSink) Close() error {
+	return errors.Join(s.Sink.Close(), s.close())
+}
+
+// jsonSink writes events to a stream as JSON lines
+type jsonSink struct {
+	out io.Writer
+}
+
+// Write encodes each event on its own line
+func (s *jsonSink) Write(_ context.Context, events []*sink.Event) error {
+	encoder := json.NewEncoder(s.out)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close does nothing, the output stream is not owned by the sink
+func (s *jsonSink) Close() error {
+	return nil
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+	"github.com/soralabs/solana-toolkit/go/tokencheck"
+	"github.com/soralabs/solana-toolkit/go/tokenmeta"
+)
+
+// tokenInfo is the output of the token command
+type tokenInfo struct {
+	Metadata *tokenmeta.Metadata `json:"metadata"`
+	Check    *tokencheck.Report  `json:"check"`
+	Pools    []*poolInfo         `json:"pools"`
+	PriceSOL float64             `json:"price_sol,omitempty"`
+	PriceUSD float64             `json:"price_usd,omitempty"`
+}
+
+// poolInfo is a pool trading the token against SOL or USDC
+type poolInfo struct {
+	Address   solana.PublicKey   `json:"address"`
+	Protocol  tx_parser.SwapType `json:"protocol"`
+	QuoteMint solana.PublicKey   `json:"quote_mint"`
+	Reserve   uint64             `json:"reserve,string"`       // token held by the pool
+	Quote     uint64             `json:"quote_reserve,string"` // quote token held by the pool
+	Price     float64            `json:"price"`                // quote tokens per token
+}
+
+// tokenCmd prints everything the library knows about a mint
+func tokenCmd(args []string) error {
+	flags := flag.NewFlagSet("token", flag.ContinueOnError)
+	rpcURL := rpcFlag(flags)
+	format := flags.String("format", "text", "output format: text or json")
+	offChain := flags.Bool("offchain", true, "download the off-chain metadata JSON")
+	timeout := flags.Duration("timeout", time.Minute, "overall timeout")
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one mint address")
+	}
+	mint, err := solana.PublicKeyFromBase58(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid mint %q: %w", positional[0], err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	rpcClient := rpc.New(*rpcURL)
+	registry := pools.NewRegistry(rpcClient, pools.RegistryConfig{})
+
+	info := &tokenInfo{}
+	if info.Check, err = tokencheck.New(rpcClient, tokencheck.Config{Registry: registry}).Check(ctx, mint); err != nil {
+		return err
+	}
+	// tokens without metadata are still reported
+	info.Metadata, _ = tokenmeta.New(rpcClient, tokenmeta.Config{FetchOffChain: *offChain}).Get(ctx, mint)
+
+	sol, usdc := tx_parser.NATIVE_SOL_PROGRAM_ID, pricing.USDC_MINT
+	solPools, priceSOL, err := deepestPrice(ctx, registry, mint, info.Check.Decimals, sol, 9)
+	if err != nil {
+		return err
+	}
+	usdcPools, priceUSD, err := deepestPrice(ctx, registry, mint, info.Check.Decimals, usdc, 6)
+	if err != nil {
+		return err
+	}
+	info.Pools = append(solPools, usdcPools...)
+	info.PriceSOL, info.PriceUSD = priceSOL, priceUSD
+	if info.PriceUSD == 0 && info.PriceSOL > 0 {
+		// value through the deepest SOL/USDC pool
+		if _, solUSD, err := deepestPrice(ctx, registry, sol, 9, usdc, 6); err == nil {
+			info.PriceUSD = info.PriceSOL * solUSD
+		}
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	case "text":
+		return writeToken(os.Stdout, info)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// deepestPrice is the price of the deepest pool trading mint against quote, zero when
+// there is none
+func deepestPrice(ctx context.Context, registry *pools.Registry, mint solana.PublicKey, mintDecimals uint8, quote solana.PublicKey, quoteDecimals uint8) ([]*poolInfo, float64, error) {
+	prices, err := registry.Prices(ctx, mint, mintDecimals, quote, quoteDecimals)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to price pools: %w", err)
+	}
+	infos := make([]*poolInfo, 0, len(prices))
+	for _, price := range prices {
+		infos = append(infos, &poolInfo{
+			Address:   price.Pool,
+			Protocol:  price.Protocol,
+			QuoteMint: price.QuoteMint,
+			Reserve:   price.Reserve,
+			Quote:     price.QuoteReserve,
+			Price:     price.Price,
+		})
+	}
+	deepest, _ := pools.DeepestPrice(prices)
+	return infos, deepest.Price, nil
+}
+
+// writeToken prints the token report as readable text
+func writeToken(out io.Writer, info *tokenInfo) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	check := info.Check
+	fmt.Fprintf(w, "mint\t%s\n", check.Mint)
+	if meta := info.Metadata; meta != nil {
+		fmt.Fprintf(w, "name\t%s (%s)\n", meta.Name, meta.Symbol)
+		fmt.Fprintf(w, "uri\t%s\n", meta.URI)
+		fmt.Fprintf(w, "update authority\t%s (mutable: %t)\n", meta.UpdateAuthority, meta.IsMutable)
+		if meta.OffChain != nil && meta.OffChain.Description != "" {
+			fmt.Fprintf(w, "description\t%s\n", meta.OffChain.Description)
+		}
+	}
+	fmt.Fprintf(w, "program\t%s\n", check.Program)
+	fmt.Fprintf(w, "supply\t%s\n", formatAmount(check.Supply, check.Decimals))
+	fmt.Fprintf(w, "decimals\t%d\n", check.Decimals)
+	fmt.Fprintf(w, "mint authority\t%s\n", authority(check.MintAuthority))
+	fmt.Fprintf(w, "freeze authority\t%s\n", authority(check.FreezeAuthority))
+	if len(check.Extensions) > 0 {
+		fmt.Fprintf(w, "extensions\t%s\n", strings.Join(check.Extensions, ", "))
+	}
+	if info.PriceSOL > 0 {
+		fmt.Fprintf(w, "price\t%g SOL\n", info.PriceSOL)
+	}
+	if info.PriceUSD > 0 {
+		fmt.Fprintf(w, "price\t%g USD\n", info.PriceUSD)
+	}
+	fmt.Fprintf(w, "risk\t%s\n", check.Risk)
+
+	if len(check.TopHolders) > 0 {
+		fmt.Fprintf(w, "\ntop holders (%.2f%% of supply)\n", check.TopHoldersPercent)
+		for _, holder := range check.TopHolders {
+			fmt.Fprintf(w, "  %s\t%s\t%.2f%%\n", holder.Owner, formatAmount(holder.Amount, check.Decimals), holder.Percent)
+		}
+	}
+	if len(info.Pools) > 0 {
+		fmt.Fprintf(w, "\npools\n")
+		for _, pool := range info.Pools {
+			fmt.Fprintf(w, "  %s\t%s\t%s per token\n", pool.Protocol, pool.Address, formatPrice(pool.Price, pool.QuoteMint))
+		}
+	}
+	if len(check.Liquidity) > 0 {
+		fmt.Fprintf(w, "\nliquidity\n")
+		for _, liquidity := range check.Liquidity {
+			fmt.Fprintf(w, "  %s\t%s\t%.2f%% burned, %.2f%% locked\n", liquidity.Pool, liquidity.Status, liquidity.BurnedPercent, liquidity.LockedPercent)
+		}
+	}
+	if len(check.Findings) > 0 {
+		fmt.Fprintf(w, "\nfindings\n")
+		for _, finding := range check.Findings {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", finding.Level, finding.Check, finding.Message)
+		}
+	}
+	return w.Flush()
+}
+
+// authority renders an optional authority
+func authority(key *solana.PublicKey) string {
+	if key == nil {
+		return "none"
+	}
+	return key.String()
+}
+
+// formatPrice renders a price with the quote token's name when known
+func formatPrice(price float64, quote solana.PublicKey) string {
+	switch {
+	case quote.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		return fmt.Sprintf("%g SOL", price)
+	case quote.Equals(pricing.USDC_MINT):
+		return fmt.Sprintf("%g USDC", price)
+	}
+	return fmt.Sprintf("%g %s", price, quote)
+}
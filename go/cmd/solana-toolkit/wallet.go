@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/backfill"
+	"github.com/soralabs/solana-toolkit/go/internal/decimals"
+	"github.com/soralabs/solana-toolkit/go/internal/owners"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pnl"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+	"github.com/soralabs/solana-toolkit/go/tax"
+	"github.com/soralabs/solana-toolkit/go/walletwatch"
+)
+
+// errReachedSince stops the backfill once it walks past the start of the report
+var errReachedSince = errors.New("reached since")
+
+// walletReport is the output of the wallet command
+type walletReport struct {
+	Wallet       solana.PublicKey         `json:"wallet"`
+	Since        time.Time                `json:"since"`
+	Transactions int                      `json:"transactions"`
+	Activity     map[walletwatch.Kind]int `json:"activity"`
+	Summary      *pnl.Summary             `json:"summary"`
+	Tokens       []*tokenReport           `json:"tokens"`
+}
+
+// tokenReport is the wallet's activity and PnL in one token
+type tokenReport struct {
+	*pnl.Position
+	Swaps        int `json:"swaps"`
+	TransfersIn  int `json:"transfers_in"`
+	TransfersOut int `json:"transfers_out"`
+}
+
+// walletCmd backfills a wallet's history and reports its PnL and activity per token
+func walletCmd(args []string) error {
+	flags := flag.NewFlagSet("wallet", flag.ContinueOnError)
+	rpcURL := rpcFlag(flags)
+	sinceFlag := flags.String("since", "", "start of the report, YYYY-MM-DD or RFC 3339, defaults to 30 days ago")
+	format := flags.String("format", "table", "output format: table, csv, json, or a tax report: form8949, turbotax or detailed")
+	method := flags.String("method", string(pnl.MethodFIFO), "cost basis method: fifo or average")
+	concurrency := flags.Int("concurrency", 8, "transactions fetched at once")
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one wallet address")
+	}
+	wallet, err := solana.PublicKeyFromBase58(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid wallet %q: %w", positional[0], err)
+	}
+	since := time.Now().AddDate(0, 0, -30)
+	if *sinceFlag != "" {
+		if since, err = parseDate(*sinceFlag); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	rpcClient := batchedClient(*rpcURL)
+	mints := decimals.NewResolver(rpcClient, decimals.Options{})
+	backfiller := backfill.New(rpcClient, backfill.Config{
+		Address:     wallet,
+		Concurrency: *concurrency,
+		ParseOptions: tx_parser.ParseOptions{
+			DecimalsResolver:        mints,
+			TokenAccountResolver:    owners.NewResolver(rpcClient, owners.Options{}),
+			TokenExtensionsResolver: mints,
+		},
+	})
+
+	// signatures are walked newest first
+	var history []*tx_parser.ParsedTransaction
+	err = backfiller.Run(ctx, func(result *backfill.Result) error {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "skipping %s: %v\n", result.Signature, result.Err)
+			return nil
+		}
+		tx := result.Transaction
+		if tx.BlockTime != nil && tx.BlockTime.Time().Before(since) {
+			return errReachedSince
+		}
+		history = append(history, tx)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errReachedSince) {
+		return fmt.Errorf("failed to backfill wallet: %w", err)
+	}
+	slices.Reverse(history)
+
+	tracker := pnl.New(wallet, pricing.New(pricing.Config{}), pnl.Config{Method: pnl.Method(*method)})
+	watcher := walletwatch.New(nil, walletwatch.Config{Wallets: []solana.PublicKey{wallet}})
+	report := &walletReport{
+		Wallet:       wallet,
+		Since:        since,
+		Transactions: len(history),
+		Activity:     make(map[walletwatch.Kind]int),
+	}
+	tokens := make(map[solana.PublicKey]*tokenReport)
+	token := func(mint solana.PublicKey) *tokenReport {
+		if tokens[mint] == nil {
+			tokens[mint] = &tokenReport{Position: &pnl.Position{Mint: mint}}
+		}
+		return tokens[mint]
+	}
+	for _, tx := range history {
+		tracker.Add(tx)
+		for _, notification := range watcher.Classify(tx) {
+			report.Activity[notification.Kind]++
+			switch {
+			case notification.Swap != nil:
+				token(notification.Swap.TokenIn.Mint).Swaps++
+				token(notification.Swap.TokenOut.Mint).Swaps++
+			case notification.Transfer != nil:
+				mint := notification.Transfer.Mint
+				if notification.Transfer.Type == tx_parser.TransferTypeSOL {
+					mint = tx_parser.NATIVE_SOL_PROGRAM_ID
+				}
+				if notification.Direction == walletwatch.DirectionIn {
+					token(mint).TransfersIn++
+				} else {
+					token(mint).TransfersOut++
+				}
+			}
+		}
+	}
+
+	report.Summary = tracker.Summary()
+	for _, position := range report.Summary.Positions {
+		token(position.Mint).Position = position
+	}
+	for _, t := range tokens {
+		report.Tokens = append(report.Tokens, t)
+	}
+	slices.SortFunc(report.Tokens, func(a, b *tokenReport) int {
+		// largest realized PnL first
+		switch {
+		case a.RealizedUSD > b.RealizedUSD:
+			return -1
+		case a.RealizedUSD < b.RealizedUSD:
+			return 1
+		}
+		return b.Swaps - a.Swaps
+	})
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "csv":
+		return writeWalletCSV(os.Stdout, report)
+	case "table":
+		return writeWalletTable(os.Stdout, report)
+	case string(tax.FormatForm8949), string(tax.FormatTurboTax), string(tax.FormatDetailed):
+		// tokens bought before since have no acquisition date, report with an early since
+		return tax.Write(os.Stdout, tracker.Disposals(), tax.Config{Format: tax.Format(*format), From: since})
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// walletColumns are the per-token columns of the table and CSV reports
+var walletColumns = []string{"mint", "held", "swaps", "transfers_in", "transfers_out", "cost_basis_usd", "realized_usd", "realized_sol", "unrealized_usd", "unrealized_sol"}
+
+// walletRow renders one token's columns
+func walletRow(t *tokenReport) []string {
+	usd := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	sol := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	return []string{
+		t.Mint.String(), formatAmount(t.Amount, t.Decimals),
+		strconv.Itoa(t.Swaps), strconv.Itoa(t.TransfersIn), strconv.Itoa(t.TransfersOut),
+		usd(t.CostBasisUSD), usd(t.RealizedUSD), sol(t.RealizedSOL), usd(t.UnrealizedUSD), sol(t.UnrealizedSOL),
+	}
+}
+
+// writeWalletCSV writes one CSV row per token
+func writeWalletCSV(out io.Writer, report *walletReport) error {
+	w := csv.NewWriter(out)
+	w.Write(walletColumns)
+	for _, t := range report.Tokens {
+		w.Write(walletRow(t))
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeWalletTable prints the summary and a table of tokens
+func writeWalletTable(out io.Writer, report *walletReport) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	summary := report.Summary
+	fmt.Fprintf(w, "wallet\t%s\n", report.Wallet)
+	fmt.Fprintf(w, "since\t%s\n", report.Since.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "transactions\t%d\n", report.Transactions)
+	for _, kind := range []walletwatch.Kind{walletwatch.KindSwap, walletwatch.KindTransfer, walletwatch.KindLiquidity, walletwatch.KindStake} {
+		fmt.Fprintf(w, "%ss\t%d\n", kind, report.Activity[kind])
+	}
+	fmt.Fprintf(w, "realized\t%.2f USD\t%.4f SOL\n", summary.RealizedUSD, summary.RealizedSOL)
+	fmt.Fprintf(w, "unrealized\t%.2f USD\t%.4f SOL\n", summary.UnrealizedUSD, summary.UnrealizedSOL)
+	fmt.Fprintf(w, "fees and tips\t%.2f USD\t%.4f SOL\n", summary.FeesUSD+summary.TipsUSD, summary.FeesSOL+summary.TipsSOL)
+	fmt.Fprintf(w, "net\t%.2f USD\t%.4f SOL\n\n", summary.NetUSD, summary.NetSOL)
+
+	for i, column := range walletColumns {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, column)
+	}
+	fmt.Fprintln(w)
+	for _, t := range report.Tokens {
+		row := walletRow(t)
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(w, "\t")
+			}
+			fmt.Fprint(w, cell)
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
+
+// parseDate accepts a date or an RFC 3339 timestamp
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,114 @@
+// Command toolkit holds developer tooling for the solana toolkit.
+//
+// Usage:
+//
+//	toolkit gen parser --program <id> --idl <file> [--name <name>] [--out <dir>]
+//	toolkit stats landing --file <stats.json> [--format table|prometheus]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/soralabs/solana-toolkit/go/internal/parsergen"
+	"github.com/soralabs/solana-toolkit/go/landing_stats"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] + " " + os.Args[2] {
+	case "gen parser":
+		err = genParser(os.Args[3:])
+	case "stats landing":
+		err = landingReport(os.Args[3:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "toolkit: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: toolkit gen parser --program <id> --idl <file> [--name <name>] [--out <dir>]")
+	fmt.Fprintln(os.Stderr, "       toolkit stats landing --file <stats.json> [--format table|prometheus]")
+}
+
+// genParser scaffolds a parser package from an Anchor IDL
+func genParser(args []string) error {
+	flags := flag.NewFlagSet("gen parser", flag.ContinueOnError)
+	programID := flags.String("program", "", "program ID, defaults to the address in the IDL")
+	idlPath := flags.String("idl", "", "path to the Anchor IDL JSON file")
+	name := flags.String("name", "", "protocol name, defaults to the name in the IDL")
+	out := flags.String("out", "", "output directory, defaults to internal/parsers/<name>")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *idlPath == "" {
+		return fmt.Errorf("--idl is required")
+	}
+
+	idl, err := parsergen.LoadIDL(*idlPath)
+	if err != nil {
+		return err
+	}
+
+	opts := parsergen.Options{
+		ProgramID: *programID,
+		Name:      *name,
+	}
+
+	files, err := parsergen.Generate(idl, opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate parser: %w", err)
+	}
+
+	dir := *out
+	if dir == "" {
+		dir = filepath.Join("internal", "parsers", parsergen.PackageName(idl, opts))
+	}
+	if err := parsergen.WriteFiles(dir, files); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d files to %s\n", len(files), dir)
+	fmt.Printf("blank-import the package to register the parser, then record fixtures into %s\n", filepath.Join(dir, "testdata"))
+	return nil
+}
+
+// landingReport prints landing statistics saved by a landing_stats.Recorder
+func landingReport(args []string) error {
+	flags := flag.NewFlagSet("stats landing", flag.ContinueOnError)
+	file := flags.String("file", "", "path to the JSON file written by Recorder.SaveFile")
+	format := flags.String("format", "table", "output format: table or prometheus")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	stats, err := landing_stats.LoadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "table":
+		return landing_stats.WriteReport(os.Stdout, stats)
+	case "prometheus":
+		return landing_stats.WritePrometheus(os.Stdout, stats)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
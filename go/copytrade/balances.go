@@ -0,0 +1,63 @@
+package copytrade
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// offset of the amount in token account data, after the mint and owner
+const tokenAccountAmountOffset = 64
+
+// RPCBalances looks up balances over RPC, the native balance for wrapped SOL and the
+// sum of the owner's token accounts of any other mint
+type RPCBalances struct {
+	rpcClient  *rpc.Client
+	commitment rpc.CommitmentType
+}
+
+var _ Balances = (*RPCBalances)(nil)
+
+// NewRPCBalances creates an RPC balance source, commitment defaults to confirmed
+func NewRPCBalances(rpcClient *rpc.Client, commitment rpc.CommitmentType) *RPCBalances {
+	if commitment == "" {
+		commitment = rpc.CommitmentConfirmed
+	}
+	return &RPCBalances{rpcClient: rpcClient, commitment: commitment}
+}
+
+// Balance returns the owner's raw balance of the mint
+func (b *RPCBalances) Balance(ctx context.Context, owner, mint solana.PublicKey) (uint64, error) {
+	if mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		result, err := b.rpcClient.GetBalance(ctx, owner, b.commitment)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get SOL balance: %w", err)
+		}
+		return result.Value, nil
+	}
+
+	accounts, err := b.rpcClient.GetTokenAccountsByOwner(ctx, owner,
+		&rpc.GetTokenAccountsConfig{Mint: &mint},
+		&rpc.GetTokenAccountsOpts{Commitment: b.commitment, Encoding: solana.EncodingBase64},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token accounts: %w", err)
+	}
+	var total uint64
+	for _, account := range accounts.Value {
+		if account == nil || account.Account.Data == nil {
+			continue
+		}
+		data := account.Account.Data.GetBinary()
+		if len(data) < tokenAccountAmountOffset+8 {
+			continue
+		}
+		total += binary.LittleEndian.Uint64(data[tokenAccountAmountOffset:])
+	}
+	return total, nil
+}
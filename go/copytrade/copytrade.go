@@ -0,0 +1,243 @@
+package copytrade
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/txbuilder"
+)
+
+// Generator turns the swaps of leader wallets into signals and sizes, and optionally
+// builds, the follower's copies of them
+type Generator struct {
+	config Config
+}
+
+// New creates a signal generator
+func New(config Config) (*Generator, error) {
+	if len(config.Leaders) == 0 {
+		return nil, fmt.Errorf("at least one leader is required")
+	}
+	if len(config.Quotes) == 0 {
+		config.Quotes = []solana.PublicKey{tx_parser.NATIVE_SOL_PROGRAM_ID}
+	}
+	if config.Sizing.Mode == "" {
+		config.Sizing.Mode = SizingFixed
+	}
+	switch config.Sizing.Mode {
+	case SizingFixed:
+		if config.Sizing.Amount == 0 {
+			return nil, fmt.Errorf("fixed sizing requires an amount")
+		}
+	case SizingProportional:
+		if config.Balances == nil {
+			return nil, fmt.Errorf("proportional sizing requires balances")
+		}
+	case SizingMultiplier:
+		if config.Sizing.Multiplier <= 0 {
+			return nil, fmt.Errorf("multiplier sizing requires a positive multiplier")
+		}
+	default:
+		return nil, fmt.Errorf("unknown sizing mode %q", config.Sizing.Mode)
+	}
+	if config.Builder != nil && config.Pools == nil {
+		return nil, fmt.Errorf("a pool finder is required to build trades")
+	}
+	return &Generator{config: config}, nil
+}
+
+// Signals returns a signal per swap of a leader against a quote mint in the transaction.
+// With Balances configured the proportion is derived from the leader's balance after the
+// swap, so it should be called as the transaction arrives.
+func (g *Generator) Signals(ctx context.Context, tx *tx_parser.ParsedTransaction) ([]*Signal, error) {
+	var signals []*Signal
+	for _, swap := range tx.Swaps {
+		signal := g.signal(tx, swap)
+		if signal == nil {
+			continue
+		}
+		if g.config.Balances != nil {
+			mintIn := swap.TokenIn.Mint
+			balance, err := g.config.Balances.Balance(ctx, signal.Leader, mintIn)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get balance of leader %s: %w", signal.Leader, err)
+			}
+			signal.Proportion = float64(signal.AmountIn) / (float64(balance) + float64(signal.AmountIn))
+		}
+		signals = append(signals, signal)
+	}
+	return signals, nil
+}
+
+// Size turns a signal into the follower's order
+func (g *Generator) Size(ctx context.Context, signal *Signal) (*Order, error) {
+	order := &Order{Signal: signal, Owner: g.config.Follower, SlippageBps: g.config.SlippageBps}
+	if signal.Side == SideBuy {
+		order.MintIn, order.MintOut = signal.Quote, signal.Mint
+		switch g.config.Sizing.Mode {
+		case SizingFixed:
+			order.AmountIn = g.config.Sizing.Amount
+		case SizingMultiplier:
+			order.AmountIn = scale(signal.AmountIn, g.config.Sizing.Multiplier)
+		case SizingProportional:
+			if signal.Proportion == 0 {
+				return nil, fmt.Errorf("the leader's proportion is unknown")
+			}
+			balance, err := g.balance(ctx, signal.Quote)
+			if err != nil {
+				return nil, err
+			}
+			order.AmountIn = scale(balance, signal.Proportion)
+		}
+		if g.config.Sizing.MaxAmount > 0 {
+			order.AmountIn = min(order.AmountIn, g.config.Sizing.MaxAmount)
+		}
+	} else {
+		order.MintIn, order.MintOut = signal.Mint, signal.Quote
+		if g.config.Balances == nil {
+			return nil, fmt.Errorf("sells require balances")
+		}
+		balance, err := g.balance(ctx, signal.Mint)
+		if err != nil {
+			return nil, err
+		}
+		order.AmountIn = balance
+		if signal.Proportion > 0 && signal.Proportion < 1 {
+			order.AmountIn = scale(balance, signal.Proportion)
+		}
+	}
+
+	if order.AmountIn == 0 || order.AmountIn < g.config.Sizing.MinAmount {
+		return nil, fmt.Errorf("order of %d is below the minimum", order.AmountIn)
+	}
+	return order, nil
+}
+
+// Build builds the order's swap, on pump.fun for pump.fun signals and against a Raydium
+// pool of the pair otherwise
+func (g *Generator) Build(ctx context.Context, order *Order) (*txbuilder.Swap, error) {
+	if g.config.Builder == nil {
+		return nil, fmt.Errorf("no builder configured")
+	}
+	if order.Signal.Protocol == tx_parser.SwapTypePumpFun && order.Signal.Quote.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		return g.config.Builder.BuildPumpFunSwap(ctx, txbuilder.PumpFunRequest{
+			Owner:       order.Owner,
+			Mint:        order.Signal.Mint,
+			Buy:         order.Signal.Side == SideBuy,
+			Amount:      order.AmountIn,
+			SlippageBps: order.SlippageBps,
+		})
+	}
+
+	found, err := g.config.Pools.Discover(ctx, order.MintIn, order.MintOut)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pools: %w", err)
+	}
+	for _, pool := range found {
+		if pool.Protocol != tx_parser.SwapTypeRaydium {
+			continue
+		}
+		return g.config.Builder.BuildSwap(ctx, txbuilder.SwapRequest{
+			Owner:       order.Owner,
+			Pool:        pool.Address,
+			MintIn:      order.MintIn,
+			AmountIn:    order.AmountIn,
+			SlippageBps: order.SlippageBps,
+		})
+	}
+	return nil, fmt.Errorf("no supported pool trades %s against %s", order.Signal.Mint, order.Signal.Quote)
+}
+
+// Process copies every signal of the transaction, sizing each and building it when a
+// builder is configured. Failures are reported per trade.
+func (g *Generator) Process(ctx context.Context, tx *tx_parser.ParsedTransaction) ([]*Trade, error) {
+	signals, err := g.Signals(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	trades := make([]*Trade, 0, len(signals))
+	for _, signal := range signals {
+		trade := &Trade{Signal: signal}
+		trades = append(trades, trade)
+		if trade.Order, trade.Err = g.Size(ctx, signal); trade.Err != nil || g.config.Builder == nil {
+			continue
+		}
+		trade.Swap, trade.Err = g.Build(ctx, trade.Order)
+	}
+	return trades, nil
+}
+
+// signal reduces a swap to a signal, nil if it is not a leader's trade of a selected token
+func (g *Generator) signal(tx *tx_parser.ParsedTransaction, swap *tx_parser.SwapInfo) *Signal {
+	var leader solana.PublicKey
+	for _, signer := range swap.Signers {
+		if solana.PublicKeySlice(g.config.Leaders).Contains(signer) {
+			leader = signer
+			break
+		}
+	}
+	if leader.IsZero() {
+		return nil
+	}
+
+	quoteIn, quoteOut := g.isQuote(swap.TokenIn.Mint), g.isQuote(swap.TokenOut.Mint)
+	signal := &Signal{
+		Leader:    leader,
+		Signature: tx.Signature,
+		Slot:      tx.Slot,
+		Protocol:  swap.Protocol,
+		AmountIn:  swap.TokenIn.Amount,
+		AmountOut: swap.TokenOut.Amount,
+		AmountUSD: swap.AmountUSD,
+	}
+	switch {
+	case quoteIn && !quoteOut:
+		signal.Side, signal.Mint, signal.Quote = SideBuy, swap.TokenOut.Mint, swap.TokenIn.Mint
+	case quoteOut && !quoteIn:
+		signal.Side, signal.Mint, signal.Quote = SideSell, swap.TokenIn.Mint, swap.TokenOut.Mint
+	default:
+		return nil
+	}
+	if signal.AmountUSD == 0 && swap.Price != nil {
+		signal.AmountUSD = swap.Price.VolumeUSD
+	}
+
+	switch {
+	case len(g.config.Mints) > 0 && !solana.PublicKeySlice(g.config.Mints).Contains(signal.Mint):
+		return nil
+	case solana.PublicKeySlice(g.config.Ignore).Contains(signal.Mint):
+		return nil
+	case len(g.config.Sides) > 0 && !slices.Contains(g.config.Sides, signal.Side):
+		return nil
+	case g.config.MinUSD > 0 && signal.AmountUSD < g.config.MinUSD:
+		return nil
+	}
+	return signal
+}
+
+// balance returns the follower's balance of a mint
+func (g *Generator) balance(ctx context.Context, mint solana.PublicKey) (uint64, error) {
+	balance, err := g.config.Balances.Balance(ctx, g.config.Follower, mint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance of %s: %w", mint, err)
+	}
+	return balance, nil
+}
+
+func (g *Generator) isQuote(mint solana.PublicKey) bool {
+	return solana.PublicKeySlice(g.config.Quotes).Contains(mint)
+}
+
+// scale multiplies a raw amount, saturating at the largest amount
+func scale(amount uint64, factor float64) uint64 {
+	scaled := math.Floor(float64(amount) * factor)
+	if scaled >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(scaled)
+}
@@ -0,0 +1,246 @@
+package copytrade
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/txbuilder"
+)
+
+var (
+	leader   = solana.NewWallet().PublicKey()
+	follower = solana.NewWallet().PublicKey()
+	token    = solana.NewWallet().PublicKey()
+	pool     = solana.NewWallet().PublicKey()
+	sol      = tx_parser.NATIVE_SOL_PROGRAM_ID
+)
+
+// balances is a fixed balance table
+type balances map[[2]solana.PublicKey]uint64
+
+func (b balances) Balance(ctx context.Context, owner, mint solana.PublicKey) (uint64, error) {
+	return b[[2]solana.PublicKey{owner, mint}], nil
+}
+
+// builder records the requests it builds
+type builder struct {
+	swaps []txbuilder.SwapRequest
+	pumps []txbuilder.PumpFunRequest
+}
+
+func (b *builder) BuildSwap(ctx context.Context, request txbuilder.SwapRequest) (*txbuilder.Swap, error) {
+	b.swaps = append(b.swaps, request)
+	return &txbuilder.Swap{MintIn: request.MintIn, AmountIn: request.AmountIn}, nil
+}
+
+func (b *builder) BuildPumpFunSwap(ctx context.Context, request txbuilder.PumpFunRequest) (*txbuilder.Swap, error) {
+	b.pumps = append(b.pumps, request)
+	return &txbuilder.Swap{AmountIn: request.Amount}, nil
+}
+
+type poolFinder []pools.Pool
+
+func (p poolFinder) Discover(ctx context.Context, mintA, mintB solana.PublicKey) ([]pools.Pool, error) {
+	return p, nil
+}
+
+// testTransaction has the leader buying the token with 2 SOL on Raydium, selling a
+// quarter of it on pump.fun and someone else's swap
+func testTransaction() *tx_parser.ParsedTransaction {
+	return &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{7},
+		Slot:      100,
+		Swaps: []*tx_parser.SwapInfo{
+			{
+				Protocol: tx_parser.SwapTypeRaydium,
+				Signers:  []solana.PublicKey{leader},
+				TokenIn:  tx_parser.TokenInfo{Mint: sol, Amount: 2_000_000_000},
+				TokenOut: tx_parser.TokenInfo{Mint: token, Amount: 400},
+			},
+			{
+				Protocol:  tx_parser.SwapTypePumpFun,
+				Signers:   []solana.PublicKey{leader},
+				TokenIn:   tx_parser.TokenInfo{Mint: token, Amount: 100},
+				TokenOut:  tx_parser.TokenInfo{Mint: sol, Amount: 500_000_000},
+				AmountUSD: 75,
+			},
+			{
+				Protocol: tx_parser.SwapTypeRaydium,
+				Signers:  []solana.PublicKey{solana.NewWallet().PublicKey()},
+				TokenIn:  tx_parser.TokenInfo{Mint: sol, Amount: 1},
+				TokenOut: tx_parser.TokenInfo{Mint: token, Amount: 1},
+			},
+		},
+	}
+}
+
+// testBalances are the balances after the transaction: the leader kept 6 of 8 SOL and
+// 300 of 400 tokens
+func testBalances() balances {
+	return balances{
+		{leader, sol}:     6_000_000_000,
+		{leader, token}:   300,
+		{follower, sol}:   1_000_000_000,
+		{follower, token}: 1_000,
+	}
+}
+
+func TestSignals(t *testing.T) {
+	generator, err := New(Config{Leaders: []solana.PublicKey{leader}, Balances: testBalances(), Sizing: Sizing{Amount: 1}})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	signals, err := generator.Signals(context.Background(), testTransaction())
+	if err != nil {
+		t.Fatalf("failed to generate signals: %v", err)
+	}
+	if len(signals) != 2 {
+		t.Fatalf("expected the leader's two swaps, got %d", len(signals))
+	}
+	buy, sell := signals[0], signals[1]
+	if buy.Side != SideBuy || !buy.Mint.Equals(token) || !buy.Quote.Equals(sol) || buy.Proportion != 0.25 || buy.Slot != 100 {
+		t.Errorf("unexpected buy %+v", buy)
+	}
+	if sell.Side != SideSell || sell.Proportion != 0.25 || sell.AmountUSD != 75 || sell.Protocol != tx_parser.SwapTypePumpFun {
+		t.Errorf("unexpected sell %+v", sell)
+	}
+
+	filtered := []Config{
+		{Sides: []Side{SideSell}},
+		{MinUSD: 50},
+		{Mints: []solana.PublicKey{token}, Ignore: []solana.PublicKey{token}},
+	}
+	for i, config := range filtered {
+		config.Leaders, config.Sizing = []solana.PublicKey{leader}, Sizing{Amount: 1}
+		generator, _ := New(config)
+		signals, _ := generator.Signals(context.Background(), testTransaction())
+		expected := 1
+		if i == 2 {
+			expected = 0
+		}
+		if len(signals) != expected {
+			t.Errorf("filter %d: expected %d signals, got %d", i, expected, len(signals))
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	cases := []struct {
+		sizing   Sizing
+		buy      uint64
+		sell     uint64
+		buyError bool
+	}{
+		{sizing: Sizing{Mode: SizingFixed, Amount: 100_000_000}, buy: 100_000_000, sell: 250},
+		{sizing: Sizing{Mode: SizingProportional}, buy: 250_000_000, sell: 250},
+		{sizing: Sizing{Mode: SizingMultiplier, Multiplier: 0.1, MaxAmount: 150_000_000}, buy: 150_000_000, sell: 250},
+		{sizing: Sizing{Mode: SizingFixed, Amount: 100, MinAmount: 500}, buyError: true},
+	}
+	for i, c := range cases {
+		generator, err := New(Config{Leaders: []solana.PublicKey{leader}, Follower: follower, Balances: testBalances(), Sizing: c.sizing})
+		if err != nil {
+			t.Fatalf("case %d: failed to create generator: %v", i, err)
+		}
+		signals, _ := generator.Signals(context.Background(), testTransaction())
+		order, err := generator.Size(context.Background(), signals[0])
+		if c.buyError {
+			if err == nil {
+				t.Errorf("case %d: expected the buy to be below the minimum", i)
+			}
+			continue
+		}
+		if err != nil || order.AmountIn != c.buy || !order.MintIn.Equals(sol) || !order.Owner.Equals(follower) {
+			t.Errorf("case %d: unexpected buy %+v: %v", i, order, err)
+		}
+		if order, err = generator.Size(context.Background(), signals[1]); err != nil || order.AmountIn != c.sell || !order.MintIn.Equals(token) {
+			t.Errorf("case %d: unexpected sell %+v: %v", i, order, err)
+		}
+	}
+
+	invalid := []Sizing{{}, {Mode: SizingProportional}, {Mode: SizingMultiplier}, {Mode: "all in"}}
+	for _, sizing := range invalid {
+		if _, err := New(Config{Leaders: []solana.PublicKey{leader}, Sizing: sizing}); err == nil {
+			t.Errorf("expected %+v to be rejected", sizing)
+		}
+	}
+}
+
+func TestProcess(t *testing.T) {
+	built := &builder{}
+	generator, err := New(Config{
+		Leaders:     []solana.PublicKey{leader},
+		Follower:    follower,
+		Balances:    testBalances(),
+		Sizing:      Sizing{Amount: 50_000_000},
+		SlippageBps: 300,
+		Builder:     built,
+		Pools:       poolFinder{{Address: solana.NewWallet().PublicKey(), Protocol: tx_parser.SwapTypeOrca}, {Address: pool, Protocol: tx_parser.SwapTypeRaydium}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	trades, err := generator.Process(context.Background(), testTransaction())
+	if err != nil {
+		t.Fatalf("failed to process: %v", err)
+	}
+	if len(trades) != 2 || trades[0].Err != nil || trades[1].Err != nil || trades[0].Swap == nil || trades[1].Swap == nil {
+		t.Fatalf("unexpected trades %+v", trades)
+	}
+	if len(built.swaps) != 1 || !built.swaps[0].Pool.Equals(pool) || built.swaps[0].AmountIn != 50_000_000 || built.swaps[0].SlippageBps != 300 {
+		t.Errorf("unexpected raydium request %+v", built.swaps)
+	}
+	if len(built.pumps) != 1 || built.pumps[0].Buy || built.pumps[0].Amount != 250 || !built.pumps[0].Mint.Equals(token) {
+		t.Errorf("unexpected pump.fun request %+v", built.pumps)
+	}
+
+	generator.config.Pools = poolFinder{}
+	if trades, _ := generator.Process(context.Background(), testTransaction()); trades[0].Err == nil {
+		t.Error("expected a pair without a supported pool to fail")
+	}
+}
+
+func TestRPCBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     any    `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		var result string
+		switch request.Method {
+		case "getBalance":
+			result = `{"context":{"slot":1},"value":42}`
+		case "getTokenAccountsByOwner":
+			var accounts []string
+			for _, amount := range []uint64{5, 7} {
+				data := make([]byte, 165)
+				binary.LittleEndian.PutUint64(data[64:], amount)
+				accounts = append(accounts, fmt.Sprintf(`{"pubkey":%q,"account":{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}}`,
+					solana.NewWallet().PublicKey(), solana.TokenProgramID, base64.StdEncoding.EncodeToString(data)))
+			}
+			result = `{"context":{"slot":1},"value":[` + accounts[0] + "," + accounts[1] + `]}`
+		}
+		id, _ := json.Marshal(request.ID)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, id, result)
+	}))
+	defer server.Close()
+
+	b := NewRPCBalances(rpc.New(server.URL), "")
+	if balance, err := b.Balance(context.Background(), follower, sol); err != nil || balance != 42 {
+		t.Errorf("expected 42 lamports, got %d: %v", balance, err)
+	}
+	if balance, err := b.Balance(context.Background(), follower, token); err != nil || balance != 12 {
+		t.Errorf("expected the token accounts summed to 12, got %d: %v", balance, err)
+	}
+}
@@ -0,0 +1,152 @@
+package copytrade
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/txbuilder"
+)
+
+// Side is the direction of a trade in the signal's token
+type Side string
+
+const (
+	SideBuy  Side = "buy"  // quote is spent on the token
+	SideSell Side = "sell" // the token is sold for quote
+)
+
+// SizingMode decides how much of the follower's balance a buy spends
+type SizingMode string
+
+const (
+	// SizingFixed spends Sizing.Amount of the quote on every buy
+	SizingFixed SizingMode = "fixed"
+	// SizingProportional spends the share of the follower's quote balance the leader spent
+	// of theirs
+	SizingProportional SizingMode = "proportional"
+	// SizingMultiplier spends the leader's amount times Sizing.Multiplier
+	SizingMultiplier SizingMode = "multiplier"
+)
+
+// Signal is a leader's swap reduced to what a follower needs to copy it
+type Signal struct {
+	Leader    solana.PublicKey   `json:"leader"`
+	Signature solana.Signature   `json:"signature"`
+	Slot      uint64             `json:"slot"`
+	Protocol  tx_parser.SwapType `json:"protocol"`
+	Side      Side               `json:"side"`
+	Mint      solana.PublicKey   `json:"mint"`
+	Quote     solana.PublicKey   `json:"quote"`
+
+	// AmountIn and AmountOut are the leader's raw amounts, of the quote and the token for
+	// buys and the other way around for sells
+	AmountIn  uint64  `json:"amount_in,string"`
+	AmountOut uint64  `json:"amount_out,string"`
+	AmountUSD float64 `json:"amount_usd,omitempty"`
+
+	// Proportion is the share of the leader's balance of the input mint the swap spent,
+	// zero when unknown
+	Proportion float64 `json:"proportion"`
+}
+
+// Order is a signal sized for the follower
+type Order struct {
+	Signal      *Signal          `json:"signal"`
+	Owner       solana.PublicKey `json:"owner"`
+	MintIn      solana.PublicKey `json:"mint_in"`
+	MintOut     solana.PublicKey `json:"mint_out"`
+	AmountIn    uint64           `json:"amount_in,string"`
+	SlippageBps uint64           `json:"slippage_bps"`
+}
+
+// Trade is the outcome of copying one signal. Swap is set when a transaction was built,
+// Err when the signal could not be sized or built.
+type Trade struct {
+	Signal *Signal
+	Order  *Order
+	Swap   *txbuilder.Swap
+	Err    error
+}
+
+// Balances looks up raw token balances, lamports for wrapped SOL
+type Balances interface {
+	Balance(ctx context.Context, owner, mint solana.PublicKey) (uint64, error)
+}
+
+// Builder builds swap transactions, implemented by txbuilder.Builder
+type Builder interface {
+	BuildSwap(ctx context.Context, request txbuilder.SwapRequest) (*txbuilder.Swap, error)
+	BuildPumpFunSwap(ctx context.Context, request txbuilder.PumpFunRequest) (*txbuilder.Swap, error)
+}
+
+var _ Builder = (*txbuilder.Builder)(nil)
+
+// PoolFinder finds the pools of a pair, implemented by pools.Registry
+type PoolFinder interface {
+	Discover(ctx context.Context, mintA, mintB solana.PublicKey) ([]pools.Pool, error)
+}
+
+var _ PoolFinder = (*pools.Registry)(nil)
+
+// Sizing controls the size of the follower's trades
+type Sizing struct {
+	// Mode sizes buys, defaults to SizingFixed. Sells always sell the share of the
+	// follower's holding the leader sold of theirs, everything when that is unknown.
+	Mode SizingMode
+
+	// Amount is the raw quote amount of fixed buys, e.g. lamports
+	Amount uint64
+
+	// Multiplier scales the leader's amount of multiplier buys
+	Multiplier float64
+
+	// MaxAmount caps the raw quote amount of every buy, zero for no cap
+	MaxAmount uint64
+
+	// MinAmount skips orders of fewer raw input units, e.g. dust sells
+	MinAmount uint64
+}
+
+// Config controls which swaps become signals and how they are copied
+type Config struct {
+	// Leaders are the wallets whose swaps are copied
+	Leaders []solana.PublicKey
+
+	// Quotes are the mints signals trade against, defaults to wrapped SOL. Swaps between
+	// two quotes or two other tokens are ignored.
+	Quotes []solana.PublicKey
+
+	// Mints limits signals to the given tokens, every token if empty
+	Mints []solana.PublicKey
+
+	// Ignore skips the given tokens
+	Ignore []solana.PublicKey
+
+	// Sides limits signals to buys or sells, both if empty
+	Sides []Side
+
+	// MinUSD skips swaps worth less, as priced by the pricing package. When set, swaps
+	// without a USD value are skipped too.
+	MinUSD float64
+
+	// Balances looks up the leader's and the follower's balances, required for the
+	// proportion of signals and for proportional sizing and sells
+	Balances Balances
+
+	// Follower owns the copied trades
+	Follower solana.PublicKey
+
+	Sizing Sizing
+
+	// SlippageBps of the copied trades, the builder's default when zero
+	SlippageBps uint64
+
+	// Builder builds the copied trades when set, otherwise trades stop at the order
+	Builder Builder
+
+	// Pools finds the pool of copied trades outside pump.fun, required with Builder
+	Pools PoolFinder
+}
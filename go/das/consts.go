@@ -0,0 +1,16 @@
+package das
+
+const (
+	// MAX_PAGE_LIMIT is the most assets a page or a getAssetBatch request returns
+	MAX_PAGE_LIMIT = 1000
+
+	// Interfaces of assets
+	INTERFACE_V1_NFT           = "V1_NFT"
+	INTERFACE_PROGRAMMABLE_NFT = "ProgrammableNFT"
+	INTERFACE_MPL_CORE_ASSET   = "MplCoreAsset"
+	INTERFACE_FUNGIBLE_TOKEN   = "FungibleToken"
+	INTERFACE_FUNGIBLE_ASSET   = "FungibleAsset"
+
+	// GROUP_COLLECTION is the group key of an asset's collection
+	GROUP_COLLECTION = "collection"
+)
@@ -0,0 +1,192 @@
+package das
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/cache"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Client queries the Metaplex Digital Asset Standard API served by indexing RPC
+// providers, for the metadata of NFTs, compressed NFTs and Core assets that is not
+// readable from accounts alone
+type Client struct {
+	config Config
+	cache  *cache.Cache[solana.PublicKey, *Asset]
+}
+
+// New creates a DAS client
+func New(config Config) (*Client, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = 10_000
+	}
+	if config.TTL <= 0 {
+		config.TTL = 10 * time.Minute
+	}
+	return &Client{
+		config: config,
+		cache:  cache.New[solana.PublicKey, *Asset](cache.Config{Size: config.CacheSize, TTL: config.TTL}),
+	}, nil
+}
+
+// GetAsset returns an asset by its ID, the mint of an NFT or the asset ID of a compressed
+// NFT
+func (c *Client) GetAsset(ctx context.Context, id solana.PublicKey) (*Asset, error) {
+	var asset Asset
+	if err := c.call(ctx, "getAsset", map[string]any{"id": id}, &asset); err != nil {
+		return nil, fmt.Errorf("failed to get asset %s: %w", id, err)
+	}
+	return &asset, nil
+}
+
+// GetAssetBatch returns the assets in the order of ids, nil for assets that do not exist,
+// in requests of up to MAX_PAGE_LIMIT assets
+func (c *Client) GetAssetBatch(ctx context.Context, ids []solana.PublicKey) ([]*Asset, error) {
+	assets := make([]*Asset, 0, len(ids))
+	for start := 0; start < len(ids); start += MAX_PAGE_LIMIT {
+		chunk := ids[start:min(start+MAX_PAGE_LIMIT, len(ids))]
+		var batch []*Asset
+		if err := c.call(ctx, "getAssetBatch", map[string]any{"ids": chunk}, &batch); err != nil {
+			return nil, fmt.Errorf("failed to get assets: %w", err)
+		}
+		if len(batch) != len(chunk) {
+			return nil, fmt.Errorf("failed to get assets: expected %d assets, got %d", len(chunk), len(batch))
+		}
+		assets = append(assets, batch...)
+	}
+	return assets, nil
+}
+
+// GetAssetsByOwner returns a page of the assets a wallet owns
+func (c *Client) GetAssetsByOwner(ctx context.Context, owner solana.PublicKey, page PageOptions, options *DisplayOptions) (*Page, error) {
+	params := struct {
+		OwnerAddress solana.PublicKey `json:"ownerAddress"`
+		PageOptions
+		Options *DisplayOptions `json:"options,omitempty"`
+	}{owner, page, options}
+
+	var result Page
+	if err := c.call(ctx, "getAssetsByOwner", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get assets of %s: %w", owner, err)
+	}
+	return &result, nil
+}
+
+// SearchAssets returns a page of the assets matching every filter of the search
+func (c *Client) SearchAssets(ctx context.Context, search Search) (*Page, error) {
+	var result Page
+	if err := c.call(ctx, "searchAssets", search, &result); err != nil {
+		return nil, fmt.Errorf("failed to search assets: %w", err)
+	}
+	return &result, nil
+}
+
+// GetMany returns the assets that exist among ids, fetching uncached assets in batches.
+// Missing assets are cached too, so they are not fetched again until the TTL expires.
+func (c *Client) GetMany(ctx context.Context, ids []solana.PublicKey) (map[solana.PublicKey]*Asset, error) {
+	assets := make(map[solana.PublicKey]*Asset, len(ids))
+	seen := make(map[solana.PublicKey]struct{}, len(ids))
+	var missing []solana.PublicKey
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		if asset, ok := c.cache.Get(id); ok {
+			if asset != nil {
+				assets[id] = asset
+			}
+			continue
+		}
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return assets, nil
+	}
+
+	fetched, err := c.GetAssetBatch(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for i, asset := range fetched {
+		c.cache.Put(missing[i], asset)
+		if asset != nil {
+			assets[missing[i]] = asset
+		}
+	}
+	return assets, nil
+}
+
+// ForTransaction returns the assets of the compressed NFT events and NFT mints of a
+// transaction, keyed by asset ID
+func (c *Client) ForTransaction(ctx context.Context, tx *tx_parser.ParsedTransaction) (map[solana.PublicKey]*Asset, error) {
+	var ids []solana.PublicKey
+	for _, event := range tx.CompressedNfts {
+		if !event.AssetID.IsZero() {
+			ids = append(ids, event.AssetID)
+		}
+	}
+	for _, mint := range tx.NftMints {
+		if !mint.Mint.IsZero() {
+			ids = append(ids, mint.Mint)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return c.GetMany(ctx, ids)
+}
+
+// call sends a JSON-RPC request with named params and decodes its result
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range c.config.Headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := c.config.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", response.StatusCode, data)
+	}
+
+	var decoded rpcResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if decoded.Error != nil {
+		return fmt.Errorf("%s (code %d)", decoded.Error.Message, decoded.Error.Code)
+	}
+	return json.Unmarshal(decoded.Result, result)
+}
@@ -0,0 +1,148 @@
+package das
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	assetID    = solana.NewWallet().PublicKey()
+	owner      = solana.NewWallet().PublicKey()
+	collection = solana.NewWallet().PublicKey()
+)
+
+// compressedAsset is a getAsset result in the shape providers return
+var compressedAsset = fmt.Sprintf(`{
+	"interface": "V1_NFT",
+	"id": %q,
+	"content": {"$schema": "https://schema.metaplex.com/nft1.0.json", "json_uri": "https://example.com/1.json",
+		"files": [{"uri": "https://example.com/1.png", "mime": "image/png"}],
+		"metadata": {"name": "Ape #1", "symbol": "APE", "attributes": [{"trait_type": "Fur", "value": "Gold"}]}},
+	"compression": {"eligible": false, "compressed": true, "tree": %q, "seq": 12, "leaf_id": 7},
+	"grouping": [{"group_key": "collection", "group_value": %q}],
+	"royalty": {"royalty_model": "creators", "target": null, "percent": 0.05, "basis_points": 500},
+	"creators": [{"address": %q, "share": 100, "verified": true}],
+	"ownership": {"frozen": false, "delegated": false, "delegate": null, "ownership_model": "single", "owner": %q},
+	"supply": {"print_max_supply": 0, "print_current_supply": 0, "edition_nonce": null},
+	"mutable": true,
+	"burnt": false
+}`, assetID, solana.NewWallet().PublicKey(), collection, owner, owner)
+
+// newServer answers DAS requests, recording the params of each method
+func newServer(t *testing.T, calls *atomic.Int32, params map[string]json.RawMessage) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+		params[request.Method] = request.Params
+
+		var result string
+		switch request.Method {
+		case "getAsset":
+			result = compressedAsset
+		case "getAssetBatch":
+			result = "[" + compressedAsset + ",null]"
+		case "getAssetsByOwner", "searchAssets":
+			result = `{"total":1,"limit":10,"page":1,"items":[` + compressedAsset + `]}`
+		default:
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, result)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Config{Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestGetAsset(t *testing.T) {
+	var calls atomic.Int32
+	params := map[string]json.RawMessage{}
+	client := newServer(t, &calls, params)
+
+	asset, err := client.GetAsset(context.Background(), assetID)
+	if err != nil {
+		t.Fatalf("failed to get asset: %v", err)
+	}
+	if string(params["getAsset"]) != fmt.Sprintf(`{"id":%q}`, assetID) {
+		t.Errorf("expected named params, got %s", params["getAsset"])
+	}
+	if asset.ID.PublicKey() != assetID || asset.Content.Metadata.Name != "Ape #1" || !asset.Compression.Compressed || asset.Compression.LeafID != 7 {
+		t.Errorf("unexpected asset %+v", asset)
+	}
+	if got, ok := asset.Collection(); !ok || got != collection {
+		t.Errorf("expected collection %s, got %s", collection, got)
+	}
+	if asset.Ownership.Owner.PublicKey() != owner || !asset.Ownership.Delegate.IsZero() || !asset.Royalty.Target.IsZero() {
+		t.Errorf("unexpected ownership %+v", asset.Ownership)
+	}
+}
+
+func TestListings(t *testing.T) {
+	var calls atomic.Int32
+	params := map[string]json.RawMessage{}
+	client := newServer(t, &calls, params)
+
+	page, err := client.GetAssetsByOwner(context.Background(), owner, PageOptions{Page: 1, Limit: 10}, &DisplayOptions{ShowFungible: true})
+	if err != nil || page.Total != 1 || len(page.Items) != 1 {
+		t.Fatalf("unexpected page %+v: %v", page, err)
+	}
+	if expected := fmt.Sprintf(`{"ownerAddress":%q,"page":1,"limit":10,"options":{"showFungible":true}}`, owner); string(params["getAssetsByOwner"]) != expected {
+		t.Errorf("expected params %s, got %s", expected, params["getAssetsByOwner"])
+	}
+
+	compressed := true
+	if _, err := client.SearchAssets(context.Background(), Search{Grouping: []string{GROUP_COLLECTION, collection.String()}, Compressed: &compressed}); err != nil {
+		t.Fatalf("failed to search assets: %v", err)
+	}
+	if !strings.Contains(string(params["searchAssets"]), `"grouping":["collection",`) || !strings.Contains(string(params["searchAssets"]), `"compressed":true`) {
+		t.Errorf("unexpected search params %s", params["searchAssets"])
+	}
+}
+
+func TestForTransaction(t *testing.T) {
+	var calls atomic.Int32
+	client := newServer(t, &calls, map[string]json.RawMessage{})
+	missing := solana.NewWallet().PublicKey()
+	tx := &tx_parser.ParsedTransaction{
+		CompressedNfts: []*tx_parser.CompressedNftEvent{{AssetID: assetID}, {Type: tx_parser.CompressedNftMint}},
+		NftMints:       []*tx_parser.NftMintEvent{{Mint: missing}},
+	}
+
+	for range 2 {
+		assets, err := client.ForTransaction(context.Background(), tx)
+		if err != nil {
+			t.Fatalf("failed to get assets: %v", err)
+		}
+		if len(assets) != 1 || assets[assetID] == nil {
+			t.Errorf("expected only the existing asset, got %v", assets)
+		}
+	}
+	// the missing asset is cached as well
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected a single batch request, got %d", got)
+	}
+}
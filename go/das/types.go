@@ -0,0 +1,261 @@
+package das
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Address is a public key that DAS leaves empty when unset, e.g. the tree of an
+// uncompressed asset or the owner of a fungible token
+type Address solana.PublicKey
+
+// PublicKey returns the address as a public key, zero when unset
+func (a Address) PublicKey() solana.PublicKey {
+	return solana.PublicKey(a)
+}
+
+func (a Address) IsZero() bool {
+	return a.PublicKey().IsZero()
+}
+
+func (a Address) String() string {
+	if a.IsZero() {
+		return ""
+	}
+	return a.PublicKey().String()
+}
+
+func (a Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+func (a *Address) UnmarshalJSON(data []byte) error {
+	var text *string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if text == nil || *text == "" {
+		*a = Address{}
+		return nil
+	}
+	key, err := solana.PublicKeyFromBase58(*text)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", *text, err)
+	}
+	*a = Address(key)
+	return nil
+}
+
+// Asset is a digital asset as returned by the DAS API: an NFT, a compressed NFT, a Core
+// asset or a fungible token
+type Asset struct {
+	Interface   string       `json:"interface"` // one of the INTERFACE_ constants
+	ID          Address      `json:"id"`
+	Content     *Content     `json:"content,omitempty"`
+	Authorities []Authority  `json:"authorities,omitempty"`
+	Compression *Compression `json:"compression,omitempty"`
+	Grouping    []Grouping   `json:"grouping,omitempty"`
+	Royalty     *Royalty     `json:"royalty,omitempty"`
+	Creators    []Creator    `json:"creators,omitempty"`
+	Ownership   Ownership    `json:"ownership"`
+	Supply      *Supply      `json:"supply,omitempty"`
+	Mutable     bool         `json:"mutable"`
+	Burnt       bool         `json:"burnt"`
+	TokenInfo   *TokenInfo   `json:"token_info,omitempty"` // set for fungible tokens by providers that extend DAS
+}
+
+// Collection returns the verified collection the asset is grouped in
+func (a *Asset) Collection() (solana.PublicKey, bool) {
+	for _, group := range a.Grouping {
+		if group.GroupKey == GROUP_COLLECTION && !group.GroupValue.IsZero() {
+			return group.GroupValue.PublicKey(), true
+		}
+	}
+	return solana.PublicKey{}, false
+}
+
+// Content is the metadata of an asset, merged from on-chain metadata and the JSON
+// document its URI points to
+type Content struct {
+	Schema   string            `json:"$schema,omitempty"`
+	JSONURI  string            `json:"json_uri"`
+	Files    []File            `json:"files,omitempty"`
+	Metadata Metadata          `json:"metadata"`
+	Links    map[string]string `json:"links,omitempty"`
+}
+
+// Metadata is the name, symbol and description of an asset
+type Metadata struct {
+	Name          string      `json:"name"`
+	Symbol        string      `json:"symbol"`
+	Description   string      `json:"description,omitempty"`
+	TokenStandard string      `json:"token_standard,omitempty"`
+	Attributes    []Attribute `json:"attributes,omitempty"`
+}
+
+// Attribute is a trait of an NFT
+type Attribute struct {
+	TraitType string `json:"trait_type"`
+	Value     any    `json:"value"`
+}
+
+// File is an image or other media of an asset
+type File struct {
+	URI    string `json:"uri"`
+	CDNURI string `json:"cdn_uri,omitempty"`
+	Mime   string `json:"mime,omitempty"`
+}
+
+// Authority may update the asset within its scopes
+type Authority struct {
+	Address Address  `json:"address"`
+	Scopes  []string `json:"scopes"`
+}
+
+// Compression locates a compressed asset's leaf in its merkle tree
+type Compression struct {
+	Eligible    bool    `json:"eligible"`
+	Compressed  bool    `json:"compressed"`
+	DataHash    string  `json:"data_hash"`
+	CreatorHash string  `json:"creator_hash"`
+	AssetHash   string  `json:"asset_hash"`
+	Tree        Address `json:"tree"`
+	Seq         uint64  `json:"seq"`
+	LeafID      uint64  `json:"leaf_id"`
+}
+
+// Grouping places the asset in a group, usually its collection
+type Grouping struct {
+	GroupKey   string  `json:"group_key"`
+	GroupValue Address `json:"group_value"`
+	Verified   *bool   `json:"verified,omitempty"` // set when unverified groups are requested
+}
+
+// Royalty is the royalty an asset's creators receive on sales
+type Royalty struct {
+	RoyaltyModel        string  `json:"royalty_model"`
+	Target              Address `json:"target"`
+	Percent             float64 `json:"percent"`
+	BasisPoints         int     `json:"basis_points"`
+	PrimarySaleHappened bool    `json:"primary_sale_happened"`
+	Locked              bool    `json:"locked"`
+}
+
+// Creator is a creator of an asset and their share of royalties
+type Creator struct {
+	Address  Address `json:"address"`
+	Share    int     `json:"share"`
+	Verified bool    `json:"verified"`
+}
+
+// Ownership is the owner of an asset and its delegation
+type Ownership struct {
+	Frozen         bool    `json:"frozen"`
+	Delegated      bool    `json:"delegated"`
+	Delegate       Address `json:"delegate"`
+	OwnershipModel string  `json:"ownership_model"` // "single" or "token"
+	Owner          Address `json:"owner"`
+}
+
+// Supply is the print supply of a master edition
+type Supply struct {
+	PrintMaxSupply     uint64 `json:"print_max_supply"`
+	PrintCurrentSupply uint64 `json:"print_current_supply"`
+	EditionNonce       *int   `json:"edition_nonce"`
+}
+
+// TokenInfo describes a fungible token
+type TokenInfo struct {
+	Symbol       string  `json:"symbol,omitempty"`
+	Decimals     uint8   `json:"decimals"`
+	Supply       uint64  `json:"supply"`
+	TokenProgram Address `json:"token_program"`
+}
+
+// Page is a page of assets. Providers return either Page or Cursor, depending on how the
+// page was requested.
+type Page struct {
+	Total  int      `json:"total"`
+	Limit  int      `json:"limit"`
+	Page   int      `json:"page,omitempty"`
+	Cursor string   `json:"cursor,omitempty"`
+	Items  []*Asset `json:"items"`
+}
+
+// PageOptions selects a page of a listing, by page number or by the cursor of the
+// previous page
+type PageOptions struct {
+	Page   int    `json:"page,omitempty"` // 1-based, defaults to the first page
+	Limit  int    `json:"limit,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+	SortBy *Sort  `json:"sortBy,omitempty"`
+}
+
+// Sort orders a listing
+type Sort struct {
+	SortBy        string `json:"sortBy"`        // created, updated, recent_action or none
+	SortDirection string `json:"sortDirection"` // asc or desc
+}
+
+// DisplayOptions add optional data to listed assets
+type DisplayOptions struct {
+	ShowFungible              bool `json:"showFungible,omitempty"`
+	ShowUnverifiedCollections bool `json:"showUnverifiedCollections,omitempty"`
+	ShowCollectionMetadata    bool `json:"showCollectionMetadata,omitempty"`
+	ShowZeroBalance           bool `json:"showZeroBalance,omitempty"`
+}
+
+// Search filters searchAssets. Unset fields do not filter.
+type Search struct {
+	PageOptions
+	OwnerAddress     string          `json:"ownerAddress,omitempty"`
+	CreatorAddress   string          `json:"creatorAddress,omitempty"`
+	CreatorVerified  *bool           `json:"creatorVerified,omitempty"`
+	AuthorityAddress string          `json:"authorityAddress,omitempty"`
+	Grouping         []string        `json:"grouping,omitempty"` // group key and value, e.g. collection and its address
+	Interface        string          `json:"interface,omitempty"`
+	TokenType        string          `json:"tokenType,omitempty"` // fungible, nonFungible, regularNft, compressedNft or all
+	Compressed       *bool           `json:"compressed,omitempty"`
+	Burnt            *bool           `json:"burnt,omitempty"`
+	Frozen           *bool           `json:"frozen,omitempty"`
+	Options          *DisplayOptions `json:"options,omitempty"`
+}
+
+// Config controls the DAS client
+type Config struct {
+	// Endpoint is an RPC URL serving the DAS methods, required
+	Endpoint string
+
+	// Headers are added to every request, e.g. for API keys
+	Headers map[string]string
+
+	// HTTPClient overrides the client used for requests, defaults to a client with a 30s
+	// timeout
+	HTTPClient *http.Client
+
+	// CacheSize is the number of assets kept in memory by Get and GetMany, defaults to 10000
+	CacheSize int
+
+	// TTL expires cached assets so transfers and metadata updates are read again, defaults
+	// to 10 minutes
+	TTL time.Duration
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
@@ -0,0 +1,102 @@
+package enrich
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Pipeline runs parsed transactions through a chain of enrichment stages. Each stage runs
+// in its own goroutine, so stages work on different transactions at once, and the order
+// of transactions is kept.
+type Pipeline struct {
+	config Config
+	stages []Stage
+}
+
+// New creates an enrichment pipeline without stages
+func New(config Config) *Pipeline {
+	if config.Buffer <= 0 {
+		config.Buffer = 64
+	}
+	return &Pipeline{config: config}
+}
+
+// Use appends a stage to the pipeline and returns the pipeline. It must not be called
+// while the pipeline runs.
+func (p *Pipeline) Use(stage Stage) *Pipeline {
+	p.stages = append(p.stages, stage)
+	return p
+}
+
+// Run enriches transactions from in until it is closed, sending them to out, which is
+// closed when Run returns. It returns the first stage error, or the context's error when
+// cancelled.
+func (p *Pipeline) Run(ctx context.Context, in <-chan *tx_parser.ParsedTransaction, out chan<- *Transaction) error {
+	defer close(out)
+	group, ctx := errgroup.WithContext(ctx)
+
+	source := make(chan *Transaction, p.config.Buffer)
+	group.Go(func() error {
+		defer close(source)
+		for {
+			select {
+			case tx, ok := <-in:
+				if !ok {
+					return nil
+				}
+				if tx == nil {
+					continue
+				}
+				if !send(ctx, source, &Transaction{ParsedTransaction: tx}) {
+					return ctx.Err()
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+
+	var next <-chan *Transaction = source
+	for _, stage := range p.stages {
+		stageIn, stageOut := next, make(chan *Transaction, p.config.Buffer)
+		group.Go(func() error {
+			defer close(stageOut)
+			return stage.Run(ctx, stageIn, stageOut)
+		})
+		next = stageOut
+	}
+
+	group.Go(func() error {
+		for tx := range next {
+			if !send(ctx, out, tx) {
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	return group.Wait()
+}
+
+// send sends the transaction unless the context is cancelled first
+func send(ctx context.Context, out chan<- *Transaction, tx *Transaction) bool {
+	select {
+	case out <- tx:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// receive reads the next transaction, ok is false once in is closed or the context is
+// cancelled
+func receive(ctx context.Context, in <-chan *Transaction) (*Transaction, bool) {
+	select {
+	case tx, ok := <-in:
+		return tx, ok
+	case <-ctx.Done():
+		return nil, false
+	}
+}
@@ -0,0 +1,139 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/labels"
+	"github.com/soralabs/solana-toolkit/go/mev"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+)
+
+var (
+	sol      = tx_parser.NATIVE_SOL_PROGRAM_ID
+	token    = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+	attacker = solana.MustPublicKeyFromBase58("EWo1KkENqJgXTfLz6tGRqfu8XJVsELwmkHHUgPtHB1sc")
+	victim   = solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+)
+
+func swapTx(signature byte, slot uint64, signer, mintIn solana.PublicKey, amountIn uint64, mintOut solana.PublicKey, amountOut uint64) *tx_parser.ParsedTransaction {
+	return &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{signature},
+		Slot:      slot,
+		FeePayer:  signer,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{signer},
+			TokenIn:  tx_parser.TokenInfo{Mint: mintIn, Amount: amountIn, Decimals: 9},
+			TokenOut: tx_parser.TokenInfo{Mint: mintOut, Amount: amountOut, Decimals: 6},
+		}},
+	}
+}
+
+// run feeds the transactions through the pipeline and collects its output
+func run(t *testing.T, p *Pipeline, txs ...*tx_parser.ParsedTransaction) []*Transaction {
+	t.Helper()
+	in := make(chan *tx_parser.ParsedTransaction, len(txs))
+	for _, tx := range txs {
+		in <- tx
+	}
+	close(in)
+
+	out := make(chan *Transaction)
+	errs := make(chan error, 1)
+	go func() { errs <- p.Run(context.Background(), in, out) }()
+
+	var enriched []*Transaction
+	for tx := range out {
+		enriched = append(enriched, tx)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	return enriched
+}
+
+func TestPipeline(t *testing.T) {
+	engine := pricing.New(pricing.Config{})
+	engine.SetUSDPrice(sol, 100)
+	registry, err := labels.New(labels.Config{Labels: []labels.Label{{Address: victim, Name: "Victim", Category: labels.CategoryExchange}}})
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	p := New(Config{Buffer: 1}).
+		Use(Valuation(engine)).
+		Use(Labels(registry)).
+		Use(MEV(mev.New(mev.Config{})))
+	txs := run(t, p,
+		swapTx(1, 10, attacker, sol, 10_000, token, 1_000_000),
+		swapTx(2, 10, victim, sol, 1_000, token, 90_000),
+		swapTx(3, 10, attacker, token, 1_000_000, sol, 10_500),
+		swapTx(4, 11, victim, sol, 1_000_000_000, token, 5_000_000),
+	)
+
+	if len(txs) != 4 {
+		t.Fatalf("expected 4 transactions, got %d", len(txs))
+	}
+	for i, tx := range txs {
+		if tx.Signature[0] != byte(i+1) {
+			t.Fatalf("expected transactions in order, got %v at %d", tx.Signature[0], i)
+		}
+	}
+	if swap := txs[3].Swaps[0]; swap.AmountUSD != 100 {
+		t.Errorf("expected the swap valued at 100 USD, got %v", swap.AmountUSD)
+	}
+	if _, ok := txs[1].Labels[victim]; !ok || txs[0].Labels != nil {
+		t.Errorf("expected only the victim's transactions labeled, got %v and %v", txs[1].Labels, txs[0].Labels)
+	}
+	if txs[0].Swaps[0].MEV == nil || txs[1].Swaps[0].MEV == nil || txs[3].Swaps[0].MEV != nil {
+		t.Error("expected the sandwich of slot 10 tagged")
+	}
+}
+
+func TestEachWorkers(t *testing.T) {
+	// later transactions finish first, the stage still emits them in order
+	var txs []*tx_parser.ParsedTransaction
+	for i := range 8 {
+		txs = append(txs, &tx_parser.ParsedTransaction{Slot: uint64(i)})
+	}
+	stage := Each(4, func(_ context.Context, tx *Transaction) error {
+		time.Sleep(time.Duration(8-tx.Slot) * time.Millisecond)
+		if tx.Slot == 2 {
+			return errors.New("lookup failed")
+		}
+		return nil
+	})
+
+	enriched := run(t, New(Config{}).Use(stage), txs...)
+	for i, tx := range enriched {
+		if tx.Slot != uint64(i) {
+			t.Fatalf("expected slot %d at %d, got %d", i, i, tx.Slot)
+		}
+	}
+	if len(enriched) != 8 || len(enriched[2].Errors) != 1 || len(enriched[3].Errors) != 0 {
+		t.Errorf("expected the failure recorded on its transaction, got %d transactions", len(enriched))
+	}
+}
+
+func TestPipelineCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan *tx_parser.ParsedTransaction)
+	out := make(chan *Transaction)
+	errs := make(chan error, 1)
+	p := New(Config{}).Use(Each(2, func(context.Context, *Transaction) error { return nil }))
+	go func() { errs <- p.Run(ctx, in, out) }()
+
+	in <- &tx_parser.ParsedTransaction{}
+	cancel()
+	for range out {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the pipeline cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/soralabs/solana-toolkit/go/das"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/labels"
+	"github.com/soralabs/solana-toolkit/go/mev"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+	"github.com/soralabs/solana-toolkit/go/tokenmeta"
+)
+
+// eachStage enriches transactions one at a time with a function
+type eachStage struct {
+	workers int
+	fn      func(ctx context.Context, tx *Transaction) error
+}
+
+// Each returns a stage enriching every transaction with fn, on up to workers transactions
+// at once. Transactions leave the stage in order, so a slow one holds back those after it.
+// Errors are recorded on the transaction, which is passed on.
+func Each(workers int, fn func(ctx context.Context, tx *Transaction) error) Stage {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &eachStage{workers: workers, fn: fn}
+}
+
+// Run enriches transactions in order, handing them to workers when there are several
+func (s *eachStage) Run(ctx context.Context, in <-chan *Transaction, out chan<- *Transaction) error {
+	if s.workers == 1 {
+		for {
+			tx, ok := receive(ctx, in)
+			if !ok {
+				return ctx.Err()
+			}
+			s.enrich(ctx, tx)
+			if !send(ctx, out, tx) {
+				return ctx.Err()
+			}
+		}
+	}
+
+	// every transaction gets a result channel, queued in the order the transactions
+	// arrived, and the queue's capacity bounds the transactions in flight
+	pending := make(chan chan *Transaction, s.workers)
+	go func() {
+		defer close(pending)
+		for {
+			tx, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			done := make(chan *Transaction, 1)
+			select {
+			case pending <- done:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				s.enrich(ctx, tx)
+				done <- tx
+			}()
+		}
+	}()
+
+	for done := range pending {
+		select {
+		case tx := <-done:
+			if !send(ctx, out, tx) {
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *eachStage) enrich(ctx context.Context, tx *Transaction) {
+	if err := s.fn(ctx, tx); err != nil {
+		tx.Errors = append(tx.Errors, err.Error())
+	}
+}
+
+// Metadata returns a stage attaching the metadata of every mint a transaction touches,
+// fetching for up to workers transactions at once
+func Metadata(fetcher *tokenmeta.Fetcher, workers int) Stage {
+	return Each(workers, func(ctx context.Context, tx *Transaction) error {
+		metadata, err := fetcher.ForTransaction(ctx, tx.ParsedTransaction)
+		if err != nil {
+			return fmt.Errorf("failed to fetch metadata: %w", err)
+		}
+		tx.Metadata = metadata
+		return nil
+	})
+}
+
+// Assets returns a stage attaching the DAS assets of the compressed NFTs and NFT mints of
+// a transaction, fetching for up to workers transactions at once
+func Assets(client *das.Client, workers int) Stage {
+	return Each(workers, func(ctx context.Context, tx *Transaction) error {
+		assets, err := client.ForTransaction(ctx, tx.ParsedTransaction)
+		if err != nil {
+			return fmt.Errorf("failed to fetch assets: %w", err)
+		}
+		if len(assets) > 0 {
+			tx.Assets = assets
+		}
+		return nil
+	})
+}
+
+// Valuation returns a stage pricing swaps and valuing swaps and transfers in USD. The
+// engine's prices depend on the order of swaps, so it values one transaction at a time.
+func Valuation(engine *pricing.Engine) Stage {
+	return Each(1, func(_ context.Context, tx *Transaction) error {
+		engine.ValueTransaction(tx.ParsedTransaction)
+		return nil
+	})
+}
+
+// Labels returns a stage attaching the label of every labeled address of a transaction
+func Labels(registry *labels.Registry) Stage {
+	return Each(1, func(_ context.Context, tx *Transaction) error {
+		if found := registry.Annotate(tx.ParsedTransaction); len(found) > 0 {
+			tx.Labels = found
+		}
+		return nil
+	})
+}
+
+// mevStage tags sandwiches among the transactions of each slot
+type mevStage struct {
+	detector *mev.Detector
+}
+
+// MEV returns a stage tagging the swaps of sandwiches with MEV info. Sandwiches land in
+// one slot, so the stage holds a slot's transactions until one of a later slot arrives,
+// delaying the stream by about a slot. Transactions are expected in execution order.
+func MEV(detector *mev.Detector) Stage {
+	return &mevStage{detector: detector}
+}
+
+// Run detects sandwiches whenever the slot changes and once in is closed
+func (s *mevStage) Run(ctx context.Context, in <-chan *Transaction, out chan<- *Transaction) error {
+	var held []*Transaction
+	flush := func() bool {
+		if len(held) == 0 {
+			return true
+		}
+		txs := make([]*tx_parser.ParsedTransaction, len(held))
+		for i, tx := range held {
+			txs[i] = tx.ParsedTransaction
+		}
+		s.detector.Detect(txs)
+		for _, tx := range held {
+			if !send(ctx, out, tx) {
+				return false
+			}
+		}
+		held = held[:0]
+		return true
+	}
+
+	for {
+		tx, ok := receive(ctx, in)
+		if !ok {
+			if ctx.Err() == nil {
+				flush()
+			}
+			return ctx.Err()
+		}
+		if len(held) > 0 && held[0].Slot != tx.Slot && !flush() {
+			return ctx.Err()
+		}
+		held = append(held, tx)
+	}
+}
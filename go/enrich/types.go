@@ -0,0 +1,39 @@
+package enrich
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/das"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/labels"
+	"github.com/soralabs/solana-toolkit/go/tokenmeta"
+)
+
+// Transaction is a parsed transaction with the data enrichment stages attach to it. Stages
+// that enrich the parsed results themselves, such as USD valuation and MEV tagging, write
+// to the embedded transaction.
+type Transaction struct {
+	*tx_parser.ParsedTransaction
+	Metadata map[solana.PublicKey]*tokenmeta.Metadata `json:"metadata,omitempty"`      // set by the metadata stage
+	Labels   map[solana.PublicKey]labels.Label        `json:"labels,omitempty"`        // set by the labels stage
+	Assets   map[solana.PublicKey]*das.Asset          `json:"assets,omitempty"`        // set by the assets stage
+	Errors   []string                                 `json:"enrich_errors,omitempty"` // stages that failed, the transaction is passed on partially enriched
+}
+
+// Stage enriches the transactions flowing through a pipeline. Run reads transactions from
+// in and sends them to out in the order they were read, holding back as many as it needs,
+// and returns once in is closed and every transaction was sent. It returns the context's
+// error when cancelled.
+type Stage interface {
+	Run(ctx context.Context, in <-chan *Transaction, out chan<- *Transaction) error
+}
+
+// Config controls an enrichment pipeline
+type Config struct {
+	// Buffer is the number of transactions queued between stages, defaults to 64. A full
+	// queue blocks the stage before it, so a slow stage slows the source down rather than
+	// buffering without bound.
+	Buffer int
+}
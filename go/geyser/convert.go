@@ -0,0 +1,258 @@
+package geyser
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// NewTransactionContext converts a Geyser transaction update into a parsing context
+func NewTransactionContext(update *pb.SubscribeUpdateTransaction) (*tx_parser.TransactionContext, error) {
+	info := update.GetTransaction()
+	if info == nil || info.GetTransaction() == nil {
+		return nil, fmt.Errorf("transaction update is empty")
+	}
+	if info.GetMeta() == nil {
+		return nil, fmt.Errorf("transaction update has no metadata")
+	}
+
+	tx, err := ConvertTransaction(info.GetTransaction())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert transaction: %w", err)
+	}
+	meta, err := ConvertMeta(info.GetMeta())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert metadata: %w", err)
+	}
+
+	// Geyser updates arrive before the block is complete, so there is no block time
+	ctx, err := tx_parser.NewTransactionContextFromDecoded(update.GetSlot(), nil, tx, meta)
+	if err != nil {
+		return nil, err
+	}
+	ctx.InnerStackHeights = stackHeights(info.GetMeta())
+	return ctx, nil
+}
+
+// stackHeights collects the stack heights of inner instructions, which the RPC
+// representation drops. Updates from nodes that predate stack heights have none.
+func stackHeights(in *pb.TransactionStatusMeta) map[uint16][]int {
+	var heights map[uint16][]int
+	for _, set := range in.GetInnerInstructions() {
+		instructions := set.GetInstructions()
+		for j, instruction := range instructions {
+			if instruction.StackHeight == nil {
+				continue
+			}
+			if heights == nil {
+				heights = make(map[uint16][]int)
+			}
+			index := uint16(set.GetIndex())
+			if heights[index] == nil {
+				heights[index] = make([]int, len(instructions))
+			}
+			heights[index][j] = int(instruction.GetStackHeight())
+		}
+	}
+	return heights
+}
+
+// ConvertTransaction converts a protobuf transaction, as streamed by Geyser or stored in
+// the ledger archives, into a solana-go transaction
+func ConvertTransaction(in *pb.Transaction) (*solana.Transaction, error) {
+	message := in.GetMessage()
+	if message == nil {
+		return nil, fmt.Errorf("transaction has no message")
+	}
+
+	tx := &solana.Transaction{}
+	for _, raw := range in.GetSignatures() {
+		if len(raw) != solana.SignatureLength {
+			return nil, fmt.Errorf("invalid signature length %d", len(raw))
+		}
+		tx.Signatures = append(tx.Signatures, solana.SignatureFromBytes(raw))
+	}
+
+	header := message.GetHeader()
+	tx.Message.Header = solana.MessageHeader{
+		NumRequiredSignatures:       uint8(header.GetNumRequiredSignatures()),
+		NumReadonlySignedAccounts:   uint8(header.GetNumReadonlySignedAccounts()),
+		NumReadonlyUnsignedAccounts: uint8(header.GetNumReadonlyUnsignedAccounts()),
+	}
+
+	keys, err := publicKeys(message.GetAccountKeys())
+	if err != nil {
+		return nil, fmt.Errorf("invalid account key: %w", err)
+	}
+	tx.Message.AccountKeys = keys
+
+	if blockhash := message.GetRecentBlockhash(); len(blockhash) == 32 {
+		tx.Message.RecentBlockhash = solana.HashFromBytes(blockhash)
+	}
+
+	for _, instruction := range message.GetInstructions() {
+		tx.Message.Instructions = append(tx.Message.Instructions, compiledInstruction(instruction.GetProgramIdIndex(), instruction.GetAccounts(), instruction.GetData()))
+	}
+
+	if message.GetVersioned() {
+		tx.Message.SetVersion(solana.MessageVersionV0)
+		for _, lookup := range message.GetAddressTableLookups() {
+			table, err := publicKey(lookup.GetAccountKey())
+			if err != nil {
+				return nil, fmt.Errorf("invalid lookup table: %w", err)
+			}
+			tx.Message.AddressTableLookups = append(tx.Message.AddressTableLookups, solana.MessageAddressTableLookup{
+				AccountKey:      table,
+				WritableIndexes: lookup.GetWritableIndexes(),
+				ReadonlyIndexes: lookup.GetReadonlyIndexes(),
+			})
+		}
+	}
+
+	return tx, nil
+}
+
+// ConvertMeta converts protobuf transaction metadata into the RPC representation. Stack
+// heights of inner instructions are dropped, as the RPC representation has none.
+func ConvertMeta(in *pb.TransactionStatusMeta) (*rpc.TransactionMeta, error) {
+	meta := &rpc.TransactionMeta{
+		Fee:                  in.GetFee(),
+		PreBalances:          in.GetPreBalances(),
+		PostBalances:         in.GetPostBalances(),
+		LogMessages:          in.GetLogMessages(),
+		ComputeUnitsConsumed: in.ComputeUnitsConsumed,
+	}
+	if in.GetErr() != nil {
+		meta.Err = TransactionError(in.GetErr().GetErr())
+	}
+
+	for _, set := range in.GetInnerInstructions() {
+		inner := rpc.InnerInstruction{Index: uint16(set.GetIndex())}
+		for _, instruction := range set.GetInstructions() {
+			inner.Instructions = append(inner.Instructions, compiledInstruction(instruction.GetProgramIdIndex(), instruction.GetAccounts(), instruction.GetData()))
+		}
+		meta.InnerInstructions = append(meta.InnerInstructions, inner)
+	}
+
+	var err error
+	if meta.PreTokenBalances, err = tokenBalances(in.GetPreTokenBalances()); err != nil {
+		return nil, err
+	}
+	if meta.PostTokenBalances, err = tokenBalances(in.GetPostTokenBalances()); err != nil {
+		return nil, err
+	}
+
+	if meta.LoadedAddresses.Writable, err = publicKeys(in.GetLoadedWritableAddresses()); err != nil {
+		return nil, fmt.Errorf("invalid loaded address: %w", err)
+	}
+	if meta.LoadedAddresses.ReadOnly, err = publicKeys(in.GetLoadedReadonlyAddresses()); err != nil {
+		return nil, fmt.Errorf("invalid loaded address: %w", err)
+	}
+
+	return meta, nil
+}
+
+// tokenBalances converts protobuf token balances, which carry base58 strings
+func tokenBalances(in []*pb.TokenBalance) ([]rpc.TokenBalance, error) {
+	balances := make([]rpc.TokenBalance, 0, len(in))
+	for _, balance := range in {
+		mint, err := solana.PublicKeyFromBase58(balance.GetMint())
+		if err != nil {
+			return nil, fmt.Errorf("invalid token balance mint %q: %w", balance.GetMint(), err)
+		}
+
+		converted := rpc.TokenBalance{
+			AccountIndex: uint16(balance.GetAccountIndex()),
+			Mint:         mint,
+		}
+		if owner, err := solana.PublicKeyFromBase58(balance.GetOwner()); err == nil {
+			converted.Owner = &owner
+		}
+		if program, err := solana.PublicKeyFromBase58(balance.GetProgramId()); err == nil {
+			converted.ProgramId = &program
+		}
+		if amount := balance.GetUiTokenAmount(); amount != nil {
+			uiAmount := amount.GetUiAmount()
+			converted.UiTokenAmount = &rpc.UiTokenAmount{
+				Amount:         amount.GetAmount(),
+				Decimals:       uint8(amount.GetDecimals()),
+				UiAmount:       &uiAmount,
+				UiAmountString: amount.GetUiAmountString(),
+			}
+		}
+		balances = append(balances, converted)
+	}
+	return balances, nil
+}
+
+// convertAccount converts a protobuf account update
+func convertAccount(update *pb.SubscribeUpdateAccount) (*AccountUpdate, error) {
+	info := update.GetAccount()
+	if info == nil {
+		return nil, fmt.Errorf("account update is empty")
+	}
+
+	pubkey, err := publicKey(info.GetPubkey())
+	if err != nil {
+		return nil, fmt.Errorf("invalid account: %w", err)
+	}
+	owner, err := publicKey(info.GetOwner())
+	if err != nil {
+		return nil, fmt.Errorf("invalid account owner: %w", err)
+	}
+
+	account := &AccountUpdate{
+		Pubkey:       pubkey,
+		Owner:        owner,
+		Lamports:     info.GetLamports(),
+		Data:         info.GetData(),
+		Executable:   info.GetExecutable(),
+		RentEpoch:    info.GetRentEpoch(),
+		Slot:         update.GetSlot(),
+		WriteVersion: info.GetWriteVersion(),
+	}
+	if raw := info.GetTxnSignature(); len(raw) == solana.SignatureLength {
+		signature := solana.SignatureFromBytes(raw)
+		account.Signature = &signature
+	}
+
+	return account, nil
+}
+
+// compiledInstruction widens the byte encoded account indices used by the protobuf messages
+func compiledInstruction(programIDIndex uint32, accounts, data []byte) solana.CompiledInstruction {
+	instruction := solana.CompiledInstruction{
+		ProgramIDIndex: uint16(programIDIndex),
+		Accounts:       make([]uint16, len(accounts)),
+		Data:           data,
+	}
+	for i, account := range accounts {
+		instruction.Accounts[i] = uint16(account)
+	}
+	return instruction
+}
+
+// publicKey converts a raw 32 byte key
+func publicKey(raw []byte) (solana.PublicKey, error) {
+	if len(raw) != solana.PublicKeyLength {
+		return solana.PublicKey{}, fmt.Errorf("invalid public key length %d", len(raw))
+	}
+	return solana.PublicKeyFromBytes(raw), nil
+}
+
+// publicKeys converts a list of raw keys
+func publicKeys(raw [][]byte) (solana.PublicKeySlice, error) {
+	keys := make(solana.PublicKeySlice, 0, len(raw))
+	for _, key := range raw {
+		converted, err := publicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, converted)
+	}
+	return keys, nil
+}
@@ -0,0 +1,170 @@
+package geyser
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// transactionErrors names the TransactionError variants by their bincode index
+var transactionErrors = []string{
+	"AccountInUse",
+	"AccountLoadedTwice",
+	"AccountNotFound",
+	"ProgramAccountNotFound",
+	"InsufficientFundsForFee",
+	"InvalidAccountForFee",
+	"AlreadyProcessed",
+	"BlockhashNotFound",
+	"InstructionError",
+	"CallChainTooDeep",
+	"MissingSignatureForFee",
+	"InvalidAccountIndex",
+	"SignatureFailure",
+	"InvalidProgramForExecution",
+	"SanitizeFailure",
+	"ClusterMaintenance",
+	"AccountBorrowOutstanding",
+	"WouldExceedMaxBlockCostLimit",
+	"UnsupportedVersion",
+	"InvalidWritableAccount",
+	"WouldExceedMaxAccountCostLimit",
+	"WouldExceedAccountDataBlockLimit",
+	"TooManyAccountLocks",
+	"AddressLookupTableNotFound",
+	"InvalidAddressLookupTableOwner",
+	"InvalidAddressLookupTableData",
+	"InvalidAddressLookupTableIndex",
+	"InvalidRentPayingAccount",
+	"WouldExceedMaxVoteCostLimit",
+	"WouldExceedAccountDataTotalLimit",
+	"DuplicateInstruction",
+	"InsufficientFundsForRent",
+	"MaxLoadedAccountsDataSizeExceeded",
+	"InvalidLoadedAccountsDataSizeLimit",
+	"ResanitizationNeeded",
+	"ProgramExecutionTemporarilyRestricted",
+	"UnbalancedTransaction",
+	"ProgramCacheHitMaxLimit",
+	"CommitCancelled",
+}
+
+// instructionErrors names the InstructionError variants by their bincode index
+var instructionErrors = []string{
+	"GenericError",
+	"InvalidArgument",
+	"InvalidInstructionData",
+	"InvalidAccountData",
+	"AccountDataTooSmall",
+	"InsufficientFunds",
+	"IncorrectProgramId",
+	"MissingRequiredSignature",
+	"AccountAlreadyInitialized",
+	"UninitializedAccount",
+	"UnbalancedInstruction",
+	"ModifiedProgramId",
+	"ExternalAccountLamportSpend",
+	"ExternalAccountDataModified",
+	"ReadonlyLamportChange",
+	"ReadonlyDataModified",
+	"DuplicateAccountIndex",
+	"ExecutableModified",
+	"RentEpochModified",
+	"NotEnoughAccountKeys",
+	"AccountDataSizeChanged",
+	"AccountNotExecutable",
+	"AccountBorrowFailed",
+	"AccountBorrowOutstanding",
+	"DuplicateAccountOutOfSync",
+	"Custom",
+	"InvalidError",
+	"ExecutableDataModified",
+	"ExecutableLamportChange",
+	"ExecutableAccountNotRentExempt",
+	"UnsupportedProgramId",
+	"CallDepth",
+	"MissingAccount",
+	"ReentrancyNotAllowed",
+	"MaxSeedLengthExceeded",
+	"InvalidSeeds",
+	"InvalidRealloc",
+	"ComputationalBudgetExceeded",
+	"PrivilegeEscalation",
+	"ProgramEnvironmentSetupFailure",
+	"ProgramFailedToComplete",
+	"ProgramFailedToCompile",
+	"Immutable",
+	"IncorrectAuthority",
+	"BorshIoError",
+	"AccountNotRentExempt",
+	"InvalidAccountOwner",
+	"ArithmeticOverflow",
+	"UnsupportedSysvar",
+	"IllegalOwner",
+	"MaxAccountsDataAllocationsExceeded",
+	"MaxAccountsExceeded",
+	"MaxInstructionTraceLengthExceeded",
+	"BuiltinProgramsMustConsumeComputeUnits",
+}
+
+// MarshalJSON encodes the error in the form getTransaction returns it, e.g.
+// {"InstructionError":[2,{"Custom":6001}]}, so it is read like errors from RPC
+func (e TransactionError) MarshalJSON() ([]byte, error) {
+	value, err := e.decode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// decode converts the bincode error into its JSON value
+func (e TransactionError) decode() (any, error) {
+	if len(e) < 4 {
+		return nil, fmt.Errorf("transaction error of %d bytes is truncated", len(e))
+	}
+	variant, data := binary.LittleEndian.Uint32(e), e[4:]
+	name := fmt.Sprintf("TransactionError(%d)", variant)
+	if int(variant) < len(transactionErrors) {
+		name = transactionErrors[variant]
+	}
+
+	switch name {
+	case "InstructionError":
+		if len(data) < 5 {
+			return nil, fmt.Errorf("instruction error is truncated")
+		}
+		index, kind, data := data[0], binary.LittleEndian.Uint32(data[1:]), data[5:]
+		var detail any = fmt.Sprintf("InstructionError(%d)", kind)
+		if int(kind) < len(instructionErrors) {
+			detail = instructionErrors[kind]
+		}
+		switch detail {
+		case "Custom":
+			if len(data) < 4 {
+				return nil, fmt.Errorf("custom error is truncated")
+			}
+			detail = map[string]any{"Custom": binary.LittleEndian.Uint32(data)}
+		case "BorshIoError":
+			// Newer nodes drop the message, older ones encode a length prefixed string
+			message := ""
+			if len(data) >= 8 {
+				if length := binary.LittleEndian.Uint64(data); uint64(len(data)-8) >= length {
+					message = string(data[8 : 8+length])
+				}
+			}
+			detail = map[string]any{"BorshIoError": message}
+		}
+		return map[string]any{name: []any{index, detail}}, nil
+	case "DuplicateInstruction":
+		if len(data) < 1 {
+			return nil, fmt.Errorf("duplicate instruction error is truncated")
+		}
+		return map[string]any{name: data[0]}, nil
+	case "InsufficientFundsForRent", "ProgramExecutionTemporarilyRestricted":
+		if len(data) < 1 {
+			return nil, fmt.Errorf("%s error is truncated", name)
+		}
+		return map[string]any{name: map[string]any{"account_index": data[0]}}, nil
+	}
+	return name, nil
+}
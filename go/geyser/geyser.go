@@ -0,0 +1,338 @@
+package geyser
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+)
+
+// maxMessageSize allows for large blocks and account data in a single update
+const maxMessageSize = 1 << 30
+
+// Client consumes a Yellowstone Geyser gRPC stream and delivers parsed transactions
+type Client struct {
+	config   Config
+	results  chan *Result
+	accounts chan *AccountUpdate
+
+	// OnGap is called when slots may have been missed while reconnecting
+	OnGap func(gap Gap)
+
+	// OnError is called when the stream fails, the client keeps reconnecting
+	OnError func(err error)
+
+	// dial is replaced in tests to connect to an in-memory server
+	dial func(ctx context.Context) (*grpc.ClientConn, error)
+
+	mu           sync.Mutex
+	lastSlot     uint64
+	reconnecting bool
+}
+
+// New creates a new Geyser client
+func New(config Config) *Client {
+	if config.Commitment == "" {
+		config.Commitment = rpc.CommitmentConfirmed
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = 4096
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = time.Second
+	}
+	if config.MaxReconnectDelay <= 0 {
+		config.MaxReconnectDelay = 30 * time.Second
+	}
+	config.Logger = logging.OrNop(config.Logger)
+	if config.ParseOptions.Logger == nil {
+		config.ParseOptions.Logger = config.Logger
+	}
+
+	client := &Client{
+		config:   config,
+		results:  make(chan *Result, config.Buffer),
+		accounts: make(chan *AccountUpdate, config.Buffer),
+	}
+	client.dial = client.dialEndpoint
+	return client
+}
+
+// Results returns the channel parsed transactions are delivered on. It is closed when Run returns.
+func (c *Client) Results() <-chan *Result {
+	return c.results
+}
+
+// Accounts returns the channel account updates are delivered on. It is closed when Run returns
+// and must be drained when account filters are configured.
+func (c *Client) Accounts() <-chan *AccountUpdate {
+	return c.accounts
+}
+
+// Run subscribes and streams until the context is cancelled, reconnecting with backoff
+// whenever the stream drops
+func (c *Client) Run(ctx context.Context) error {
+	defer close(c.accounts)
+	defer close(c.results)
+
+	if c.config.Endpoint == "" {
+		return fmt.Errorf("endpoint is required")
+	}
+	if len(c.config.Programs) == 0 && len(c.config.Accounts) == 0 && len(c.config.Owners) == 0 {
+		return fmt.Errorf("at least one program, account or owner is required")
+	}
+	if _, err := commitmentLevel(c.config.Commitment); err != nil {
+		return err
+	}
+
+	updates := make(chan *pb.SubscribeUpdateTransaction, c.config.Buffer)
+	var wg sync.WaitGroup
+	for range c.config.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.parseUpdates(ctx, updates)
+		}()
+	}
+	defer wg.Wait()
+	defer close(updates)
+
+	delay := c.config.ReconnectDelay
+	for {
+		started := time.Now()
+		err := c.runOnce(ctx, updates)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			c.config.Logger.WarnContext(ctx, "stream failed, reconnecting", logging.ERROR_KEY, err, "delay", delay)
+			if c.OnError != nil {
+				c.OnError(err)
+			}
+		}
+
+		// Reset the backoff after a stream that stayed up for a while
+		if time.Since(started) > c.config.MaxReconnectDelay {
+			delay = c.config.ReconnectDelay
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.config.MaxReconnectDelay)
+
+		c.mu.Lock()
+		c.reconnecting = true
+		c.mu.Unlock()
+	}
+}
+
+// runOnce holds a single subscription until it fails
+func (c *Client) runOnce(ctx context.Context, updates chan<- *pb.SubscribeUpdateTransaction) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if c.config.Token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-token", c.config.Token)
+	}
+
+	stream, err := pb.NewGeyserClient(conn).Subscribe(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	request, err := c.subscribeRequest()
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(request); err != nil {
+		return fmt.Errorf("failed to send subscription: %w", err)
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("stream failed: %w", err)
+		}
+
+		switch u := update.GetUpdateOneof().(type) {
+		case *pb.SubscribeUpdate_Transaction:
+			c.observeSlot(u.Transaction.GetSlot())
+			select {
+			case updates <- u.Transaction:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case *pb.SubscribeUpdate_Account:
+			c.observeSlot(u.Account.GetSlot())
+			account, err := convertAccount(u.Account)
+			if err != nil {
+				c.config.Logger.WarnContext(ctx, "failed to convert account update", logging.SLOT_KEY, u.Account.GetSlot(), logging.ERROR_KEY, err)
+				if c.OnError != nil {
+					c.OnError(err)
+				}
+				continue
+			}
+			select {
+			case c.accounts <- account:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case *pb.SubscribeUpdate_Slot:
+			c.observeSlot(u.Slot.GetSlot())
+		case *pb.SubscribeUpdate_Ping:
+			// Answer server pings so load balancers keep the stream open
+			if err := stream.Send(&pb.SubscribeRequest{Ping: &pb.SubscribeRequestPing{Id: 1}}); err != nil {
+				return fmt.Errorf("failed to answer ping: %w", err)
+			}
+		}
+	}
+}
+
+// subscribeRequest builds the filters for the configured programs and accounts
+func (c *Client) subscribeRequest() (*pb.SubscribeRequest, error) {
+	commitment, err := commitmentLevel(c.config.Commitment)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &pb.SubscribeRequest{
+		Commitment:   &commitment,
+		Slots:        map[string]*pb.SubscribeRequestFilterSlots{"slots": {}},
+		Transactions: map[string]*pb.SubscribeRequestFilterTransactions{},
+		Accounts:     map[string]*pb.SubscribeRequestFilterAccounts{},
+	}
+
+	if len(c.config.Programs) > 0 {
+		vote := false
+		filter := &pb.SubscribeRequestFilterTransactions{Vote: &vote}
+		if !c.config.IncludeFailed {
+			failed := false
+			filter.Failed = &failed
+		}
+		for _, program := range c.config.Programs {
+			filter.AccountInclude = append(filter.AccountInclude, program.String())
+		}
+		request.Transactions["programs"] = filter
+	}
+
+	if len(c.config.Accounts) > 0 || len(c.config.Owners) > 0 {
+		filter := &pb.SubscribeRequestFilterAccounts{}
+		for _, account := range c.config.Accounts {
+			filter.Account = append(filter.Account, account.String())
+		}
+		for _, owner := range c.config.Owners {
+			filter.Owner = append(filter.Owner, owner.String())
+		}
+		request.Accounts["accounts"] = filter
+	}
+
+	return request, nil
+}
+
+// parseUpdates converts and parses transaction updates until the channel is closed
+func (c *Client) parseUpdates(ctx context.Context, updates <-chan *pb.SubscribeUpdateTransaction) {
+	for update := range updates {
+		result := parseUpdate(update, c.config.ParseOptions)
+		if result.Err != nil {
+			c.config.Logger.DebugContext(ctx, "failed to parse transaction", logging.SIGNATURE_KEY, result.Signature.String(), logging.SLOT_KEY, result.Slot, logging.ERROR_KEY, result.Err)
+		}
+		select {
+		case c.results <- result:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// parseUpdate runs a transaction update through the parsers
+func parseUpdate(update *pb.SubscribeUpdateTransaction, opts tx_parser.ParseOptions) *Result {
+	result := &Result{Slot: update.GetSlot()}
+
+	txCtx, err := NewTransactionContext(update)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if len(txCtx.Transaction.Signatures) > 0 {
+		result.Signature = txCtx.Transaction.Signatures[0]
+	}
+
+	result.Transaction, result.Err = tx_parser.NewFromContext(txCtx, opts).Parse()
+	return result
+}
+
+// observeSlot records progress and reports the slots skipped across a reconnect
+func (c *Client) observeSlot(slot uint64) {
+	c.mu.Lock()
+	var gap *Gap
+	if c.reconnecting && c.lastSlot > 0 && slot > c.lastSlot+1 {
+		gap = &Gap{FromSlot: c.lastSlot + 1, ToSlot: slot - 1}
+	}
+	c.reconnecting = false
+	if slot > c.lastSlot {
+		c.lastSlot = slot
+	}
+	c.mu.Unlock()
+
+	if gap == nil {
+		return
+	}
+	c.config.Logger.WarnContext(context.Background(), "possible gap in slots", "from_slot", gap.FromSlot, "to_slot", gap.ToSlot)
+	if c.OnGap != nil {
+		c.OnGap(*gap)
+	}
+}
+
+// dialEndpoint opens a gRPC connection to the configured endpoint
+func (c *Client) dialEndpoint(ctx context.Context) (*grpc.ClientConn, error) {
+	creds := credentials.NewTLS(&tls.Config{})
+	if c.config.Insecure {
+		creds = insecure.NewCredentials()
+	}
+
+	return grpc.NewClient(c.config.Endpoint,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize)),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                10 * time.Second,
+			Timeout:             time.Second,
+			PermitWithoutStream: true,
+		}),
+	)
+}
+
+// commitmentLevel maps an RPC commitment onto the Geyser enum
+func commitmentLevel(commitment rpc.CommitmentType) (pb.CommitmentLevel, error) {
+	switch commitment {
+	case rpc.CommitmentProcessed:
+		return pb.CommitmentLevel_PROCESSED, nil
+	case rpc.CommitmentConfirmed:
+		return pb.CommitmentLevel_CONFIRMED, nil
+	case rpc.CommitmentFinalized:
+		return pb.CommitmentLevel_FINALIZED, nil
+	}
+	return 0, fmt.Errorf("unsupported commitment %q", commitment)
+}
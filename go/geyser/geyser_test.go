@@ -0,0 +1,324 @@
+package geyser
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	pb "github.com/rpcpool/yellowstone-grpc/examples/golang/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// testTransactionUpdate builds a versioned transaction that moves SOL with the system program
+// and tokens with a token program instruction whose accounts come from a lookup table
+func testTransactionUpdate(t *testing.T, slot uint64) (*pb.SubscribeUpdateTransaction, solana.PublicKey, solana.PublicKey) {
+	t.Helper()
+
+	user := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	userAta := solana.NewWallet().PublicKey()
+	recipientAta := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	table := solana.NewWallet().PublicKey()
+
+	systemData := make([]byte, 12)
+	binary.LittleEndian.PutUint32(systemData, 2)
+	binary.LittleEndian.PutUint64(systemData[4:], 1_000_000)
+
+	tokenData := make([]byte, 9)
+	tokenData[0] = 3
+	binary.LittleEndian.PutUint64(tokenData[1:], 500)
+
+	signature := solana.Signature{1, 2, 3}
+	update := &pb.SubscribeUpdateTransaction{
+		Slot: slot,
+		Transaction: &pb.SubscribeUpdateTransactionInfo{
+			Signature: signature[:],
+			Transaction: &pb.Transaction{
+				Signatures: [][]byte{signature[:]},
+				Message: &pb.Message{
+					Header: &pb.MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 2},
+					AccountKeys: [][]byte{
+						user.Bytes(), recipient.Bytes(), solana.SystemProgramID.Bytes(), solana.TokenProgramID.Bytes(),
+					},
+					RecentBlockhash: make([]byte, 32),
+					Instructions: []*pb.CompiledInstruction{
+						{ProgramIdIndex: 2, Accounts: []byte{0, 1}, Data: systemData},
+						{ProgramIdIndex: 3, Accounts: []byte{4, 5, 0}, Data: tokenData},
+					},
+					Versioned: true,
+					AddressTableLookups: []*pb.MessageAddressTableLookup{
+						{AccountKey: table.Bytes(), WritableIndexes: []byte{0, 1}},
+					},
+				},
+			},
+			Meta: &pb.TransactionStatusMeta{
+				Fee:                     5000,
+				PreBalances:             []uint64{2_000_000, 0, 1, 1, 0, 0},
+				PostBalances:            []uint64{995_000, 1_000_000, 1, 1, 0, 0},
+				LoadedWritableAddresses: [][]byte{userAta.Bytes(), recipientAta.Bytes()},
+				LogMessages:             []string{"Program 11111111111111111111111111111111 invoke [1]"},
+				PreTokenBalances: []*pb.TokenBalance{
+					{AccountIndex: 4, Mint: mint.String(), Owner: user.String(), UiTokenAmount: &pb.UiTokenAmount{Amount: "1000", Decimals: 6}},
+					{AccountIndex: 5, Mint: mint.String(), Owner: recipient.String(), UiTokenAmount: &pb.UiTokenAmount{Amount: "0", Decimals: 6}},
+				},
+			},
+		},
+	}
+
+	return update, mint, recipient
+}
+
+func TestNewTransactionContext(t *testing.T) {
+	update, mint, recipient := testTransactionUpdate(t, 42)
+
+	ctx, err := NewTransactionContext(update)
+	if err != nil {
+		t.Fatalf("failed to convert update: %v", err)
+	}
+	if len(ctx.AccountKeys) != 6 {
+		t.Fatalf("expected 6 account keys including loaded addresses, got %d", len(ctx.AccountKeys))
+	}
+	if ctx.Slot != 42 || len(ctx.Logs) != 1 || ctx.Meta.Fee != 5000 {
+		t.Errorf("unexpected context metadata: slot %d, %d logs, fee %d", ctx.Slot, len(ctx.Logs), ctx.Meta.Fee)
+	}
+	if ctx.GetMintDecimals(mint) != 6 {
+		t.Errorf("expected 6 decimals for %s, got %d", mint, ctx.GetMintDecimals(mint))
+	}
+
+	result := parseUpdate(update, tx_parser.ParseOptions{})
+	if result.Err != nil {
+		t.Fatalf("failed to parse update: %v", result.Err)
+	}
+	transfers := result.Transaction.Transfers
+	if len(transfers) != 2 {
+		t.Fatalf("expected 2 transfers, got %d", len(transfers))
+	}
+	if transfers[0].Amount != 1_000_000 || !transfers[0].Destination.Equals(recipient) {
+		t.Errorf("unexpected SOL transfer: %+v", transfers[0])
+	}
+	if transfers[1].Amount != 500 || !transfers[1].Mint.Equals(mint) || !transfers[1].DestinationOwner.Equals(recipient) {
+		t.Errorf("unexpected token transfer: %+v", transfers[1])
+	}
+	if result.Signature != (solana.Signature{1, 2, 3}) {
+		t.Errorf("unexpected signature %s", result.Signature)
+	}
+}
+
+func TestNewTransactionContextStackHeights(t *testing.T) {
+	update, _, _ := testTransactionUpdate(t, 1)
+	two, three := uint32(2), uint32(3)
+	update.Transaction.Meta.InnerInstructions = []*pb.InnerInstructions{{
+		Index: 1,
+		Instructions: []*pb.InnerInstruction{
+			{ProgramIdIndex: 2, StackHeight: &two},
+			{ProgramIdIndex: 3, StackHeight: &three},
+			{ProgramIdIndex: 2}, // older nodes omit the height
+		},
+	}}
+
+	ctx, err := NewTransactionContext(update)
+	if err != nil {
+		t.Fatalf("failed to convert update: %v", err)
+	}
+	tree := ctx.CallTree()
+	if len(tree) != 2 || len(tree[1].Children) != 2 {
+		t.Fatalf("expected the inner instructions nested under the second instruction, got %+v", tree)
+	}
+	if nested := tree[1].Children[0].Children; len(nested) != 1 || nested[0].StackHeight != 3 || !nested[0].Program.Equals(solana.TokenProgramID) {
+		t.Errorf("expected the token instruction invoked at height 3, got %+v", nested)
+	}
+	if caller, ok := ctx.Caller(1, 2); !ok || caller != tree[1] {
+		t.Errorf("expected the instruction without a height to fall back to its outer instruction, got %+v", caller)
+	}
+}
+
+func TestTransactionErrorJSON(t *testing.T) {
+	custom := binary.LittleEndian.AppendUint32(nil, 8)
+	custom = append(custom, 2)
+	custom = binary.LittleEndian.AppendUint32(custom, 25)
+	custom = binary.LittleEndian.AppendUint32(custom, 6001)
+
+	for _, test := range []struct {
+		err  TransactionError
+		want string
+	}{
+		{custom, `{"InstructionError":[2,{"Custom":6001}]}`},
+		{binary.LittleEndian.AppendUint32(nil, 7), `"BlockhashNotFound"`},
+		{append(binary.LittleEndian.AppendUint32(nil, 31), 3), `{"InsufficientFundsForRent":{"account_index":3}}`},
+	} {
+		data, err := json.Marshal(test.err)
+		if err != nil || string(data) != test.want {
+			t.Errorf("expected %s, got %s, %v", test.want, data, err)
+		}
+	}
+	if _, err := json.Marshal(TransactionError(custom[:6])); err == nil {
+		t.Error("expected a truncated error to fail")
+	}
+
+	// Failure analysis reads the bincode error like an RPC error
+	update, _, _ := testTransactionUpdate(t, 1)
+	update.Transaction.Meta.Err = &pb.TransactionError{Err: custom}
+	ctx, err := NewTransactionContext(update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tx_parser.NewFromContext(ctx, tx_parser.ParseOptions{AnalyzeFailures: true}).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failure := parsed.Failure; failure == nil || failure.InstructionIndex != 2 || failure.CustomCode == nil || *failure.CustomCode != 6001 {
+		t.Errorf("unexpected failure: %+v", failure)
+	}
+}
+
+func TestNewTransactionContextRejectsMissingLookups(t *testing.T) {
+	update, _, _ := testTransactionUpdate(t, 1)
+	update.Transaction.Meta.LoadedWritableAddresses = nil
+
+	if _, err := NewTransactionContext(update); err == nil {
+		t.Error("expected an error for unresolved lookup table addresses")
+	}
+	if _, err := NewTransactionContext(&pb.SubscribeUpdateTransaction{}); err == nil {
+		t.Error("expected an error for an empty update")
+	}
+}
+
+func TestCommitmentLevel(t *testing.T) {
+	level, err := commitmentLevel(rpc.CommitmentFinalized)
+	if err != nil || level != pb.CommitmentLevel_FINALIZED {
+		t.Errorf("expected finalized, got %v, %v", level, err)
+	}
+	if _, err := commitmentLevel("max"); err == nil {
+		t.Error("expected an error for an unsupported commitment")
+	}
+}
+
+// testServer replays updates to every subscriber and records the requests it received
+type testServer struct {
+	pb.UnimplementedGeyserServer
+	updates  []*pb.SubscribeUpdate
+	requests chan *pb.SubscribeRequest
+	tokens   chan string
+}
+
+func (s *testServer) Subscribe(stream grpc.BidiStreamingServer[pb.SubscribeRequest, pb.SubscribeUpdate]) error {
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok && len(md.Get("x-token")) > 0 {
+		s.tokens <- md.Get("x-token")[0]
+	}
+
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	s.requests <- request
+
+	for _, update := range s.updates {
+		if err := stream.Send(update); err != nil {
+			return err
+		}
+	}
+
+	// Wait for the ping reply, then drop the stream to force a reconnect
+	for {
+		request, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if request.GetPing() != nil {
+			return nil
+		}
+	}
+}
+
+func TestClientRun(t *testing.T) {
+	transaction, _, _ := testTransactionUpdate(t, 100)
+	account := solana.NewWallet().PublicKey()
+	server := &testServer{
+		updates: []*pb.SubscribeUpdate{
+			{UpdateOneof: &pb.SubscribeUpdate_Slot{Slot: &pb.SubscribeUpdateSlot{Slot: 99}}},
+			{UpdateOneof: &pb.SubscribeUpdate_Transaction{Transaction: transaction}},
+			{UpdateOneof: &pb.SubscribeUpdate_Account{Account: &pb.SubscribeUpdateAccount{
+				Slot:    100,
+				Account: &pb.SubscribeUpdateAccountInfo{Pubkey: account.Bytes(), Owner: solana.TokenProgramID.Bytes(), Lamports: 7},
+			}}},
+			{UpdateOneof: &pb.SubscribeUpdate_Ping{Ping: &pb.SubscribeUpdatePing{}}},
+		},
+		requests: make(chan *pb.SubscribeRequest, 4),
+		tokens:   make(chan string, 4),
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGeyserServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	client := New(Config{
+		Endpoint:       "bufnet",
+		Token:          "secret",
+		Programs:       []solana.PublicKey{solana.SystemProgramID},
+		Accounts:       []solana.PublicKey{account},
+		Commitment:     rpc.CommitmentProcessed,
+		Workers:        2,
+		ReconnectDelay: 10 * time.Millisecond,
+	})
+	client.dial = func(ctx context.Context) (*grpc.ClientConn, error) {
+		return grpc.NewClient("passthrough:///bufnet",
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- client.Run(ctx) }()
+
+	request := <-server.requests
+	if request.GetCommitment() != pb.CommitmentLevel_PROCESSED {
+		t.Errorf("expected processed commitment, got %v", request.GetCommitment())
+	}
+	filter := request.GetTransactions()["programs"]
+	if filter == nil || len(filter.AccountInclude) != 1 || filter.GetFailed() || filter.GetVote() {
+		t.Errorf("unexpected transaction filter: %v", filter)
+	}
+	if accounts := request.GetAccounts()["accounts"]; accounts == nil || accounts.Account[0] != account.String() {
+		t.Errorf("unexpected account filter: %v", accounts)
+	}
+	if token := <-server.tokens; token != "secret" {
+		t.Errorf("expected x-token secret, got %q", token)
+	}
+
+	result := <-client.Results()
+	if result.Err != nil || result.Slot != 100 || len(result.Transaction.Transfers) != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	update := <-client.Accounts()
+	if !update.Pubkey.Equals(account) || update.Lamports != 7 || update.Signature != nil {
+		t.Errorf("unexpected account update: %+v", update)
+	}
+
+	// The server drops the stream after the ping reply and the client resubscribes
+	<-server.requests
+	cancel()
+	for range client.Results() {
+	}
+	for range client.Accounts() {
+	}
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package geyser
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+)
+
+// Config controls the Geyser connection and what it subscribes to
+type Config struct {
+	// Endpoint is the gRPC address, e.g. my-node.rpcpool.com:443
+	Endpoint string
+
+	// Token is sent as the x-token header, optional
+	Token string
+
+	// Insecure disables TLS, for local or tunnelled endpoints
+	Insecure bool
+
+	// Programs whose transactions are streamed
+	Programs []solana.PublicKey
+
+	// Accounts and Owners select the account updates delivered on Accounts(). Leave both
+	// empty to only stream transactions.
+	Accounts []solana.PublicKey
+	Owners   []solana.PublicKey
+
+	// Commitment is the commitment level updates are filtered on, defaults to confirmed
+	Commitment rpc.CommitmentType
+
+	// IncludeFailed also streams transactions that failed
+	IncludeFailed bool
+
+	// ParseOptions are passed to the transaction parser
+	ParseOptions tx_parser.ParseOptions
+
+	// Workers is the number of goroutines parsing transactions, defaults to the number of CPUs
+	Workers int
+
+	// Buffer is the size of the result channels, defaults to 4096
+	Buffer int
+
+	// ReconnectDelay is the initial delay before reconnecting, doubled up to
+	// MaxReconnectDelay after each failed attempt. Defaults to 1s and 30s.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+
+	// Logger receives connection failures, slot gaps and transactions that could not be
+	// parsed, defaults to a no-op logger. It is also passed to the parser unless
+	// ParseOptions sets its own.
+	Logger logging.Logger
+}
+
+// Result is a parsed transaction delivered by the client
+type Result struct {
+	Signature   solana.Signature
+	Slot        uint64
+	Transaction *tx_parser.ParsedTransaction
+	Err         error // set if the update could not be converted or parsed
+}
+
+// AccountUpdate is a change to an account matching the account filters
+type AccountUpdate struct {
+	Pubkey       solana.PublicKey
+	Owner        solana.PublicKey
+	Lamports     uint64
+	Data         []byte
+	Executable   bool
+	RentEpoch    uint64
+	Slot         uint64
+	WriteVersion uint64
+	Signature    *solana.Signature // transaction that caused the update, nil on startup
+}
+
+// Gap is a range of slots that may have been missed while reconnecting.
+// Callers can backfill it with getBlocks.
+type Gap struct {
+	FromSlot uint64 // first slot that may be missing
+	ToSlot   uint64 // last slot that may be missing
+}
+
+// TransactionError is the bincode encoded error of a failed transaction, set as the
+// metadata error of converted transactions
+type TransactionError []byte
@@ -0,0 +1,6 @@
+package grpc_server
+
+const (
+	DEFAULT_ADDR        = ":9090"
+	DEFAULT_BUFFER_SIZE = 1024
+)
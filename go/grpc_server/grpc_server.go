@@ -0,0 +1,247 @@
+package grpc_server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pb"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+var errTooSlow = status.Error(codes.ResourceExhausted, "subscriber is too slow, events were dropped")
+
+// Server is a gRPC StreamService fanning out the swaps written to it to every matching
+// subscription. It implements sink.Sink, so any source the sinks are fed from can drive it.
+type Server struct {
+	pb.UnimplementedStreamServiceServer
+
+	config        Config
+	mu            sync.Mutex
+	subscriptions map[*subscription]struct{}
+	closed        bool
+}
+
+var (
+	_ sink.Sink              = (*Server)(nil)
+	_ pb.StreamServiceServer = (*Server)(nil)
+)
+
+// New creates a gRPC streaming server
+func New(config Config) *Server {
+	if config.Addr == "" {
+		config.Addr = DEFAULT_ADDR
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DEFAULT_BUFFER_SIZE
+	}
+
+	return &Server{
+		config:        config,
+		subscriptions: make(map[*subscription]struct{}),
+	}
+}
+
+// Register registers the service on an existing gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	pb.RegisterStreamServiceServer(registrar, s)
+}
+
+// ListenAndServe serves the service on the configured address until the context is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the service on the listener until the context is cancelled, then ends
+// every subscription
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	grpcServer := grpc.NewServer()
+	s.Register(grpcServer)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- grpcServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	// streams only return once their subscription ends
+	s.Close()
+	grpcServer.GracefulStop()
+	return nil
+}
+
+// SubscribeSwaps streams the swaps matching the request until the client disconnects,
+// the server closes or the client falls BufferSize events behind
+func (s *Server) SubscribeSwaps(request *pb.SubscribeSwapsRequest, stream grpc.ServerStreamingServer[pb.SwapEvent]) error {
+	filter, err := newSwapFilter(request)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub := &subscription{
+		filter: filter,
+		events: make(chan *pb.SwapEvent, s.config.BufferSize),
+		done:   make(chan struct{}),
+	}
+	if !s.subscribe(sub) {
+		return status.Error(codes.Unavailable, "server is closed")
+	}
+	defer s.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.done:
+			return sub.err
+		case event := <-sub.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Write publishes the swap events to the matching subscriptions. Other event kinds are
+// ignored and subscriptions that cannot keep up are ended rather than blocking the writer.
+func (s *Server) Write(ctx context.Context, events []*sink.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		if event.Swap == nil {
+			continue
+		}
+		var message *pb.SwapEvent
+		for sub := range s.subscriptions {
+			if !sub.filter.match(event.Swap) {
+				continue
+			}
+			if message == nil {
+				message = swapEventToProto(event)
+			}
+			select {
+			case sub.events <- message:
+			default:
+				s.end(sub, errTooSlow)
+			}
+		}
+	}
+	return nil
+}
+
+// Close ends every subscription and rejects new ones
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sub := range s.subscriptions {
+		s.end(sub, nil)
+	}
+	return nil
+}
+
+// subscribe adds a subscription, false once the server is closed
+func (s *Server) subscribe(sub *subscription) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.subscriptions[sub] = struct{}{}
+	return true
+}
+
+// unsubscribe removes a subscription if it is still active
+func (s *Server) unsubscribe(sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[sub]; ok {
+		s.end(sub, nil)
+	}
+}
+
+// end removes a subscription and wakes its stream, the caller holds the lock
+func (s *Server) end(sub *subscription, err error) {
+	delete(s.subscriptions, sub)
+	sub.err = err
+	close(sub.done)
+}
+
+// newSwapFilter decodes the filter of a swap subscription
+func newSwapFilter(request *pb.SubscribeSwapsRequest) (*swapFilter, error) {
+	filter := &swapFilter{
+		protocols: make(map[tx_parser.SwapType]bool),
+		mints:     make(map[solana.PublicKey]bool),
+		wallets:   make(map[solana.PublicKey]bool),
+	}
+	for _, protocol := range request.GetProtocols() {
+		filter.protocols[tx_parser.SwapType(protocol)] = true
+	}
+	for _, raw := range request.GetMints() {
+		if len(raw) != solana.PublicKeyLength {
+			return nil, fmt.Errorf("mint filter must be %d bytes, got %d", solana.PublicKeyLength, len(raw))
+		}
+		filter.mints[solana.PublicKeyFromBytes(raw)] = true
+	}
+	for _, raw := range request.GetWallets() {
+		if len(raw) != solana.PublicKeyLength {
+			return nil, fmt.Errorf("wallet filter must be %d bytes, got %d", solana.PublicKeyLength, len(raw))
+		}
+		filter.wallets[solana.PublicKeyFromBytes(raw)] = true
+	}
+	return filter, nil
+}
+
+// match reports whether the swap passes every non-empty part of the filter
+func (f *swapFilter) match(swap *tx_parser.SwapInfo) bool {
+	if len(f.protocols) > 0 && !f.protocols[swap.Protocol] {
+		return false
+	}
+	if len(f.mints) > 0 && !f.mints[swap.TokenIn.Mint] && !f.mints[swap.TokenOut.Mint] {
+		return false
+	}
+	if len(f.wallets) > 0 {
+		for _, signer := range swap.Signers {
+			if f.wallets[signer] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// swapEventToProto converts a swap event
+func swapEventToProto(event *sink.Event) *pb.SwapEvent {
+	out := &pb.SwapEvent{
+		Signature: event.Signature[:],
+		Slot:      event.Slot,
+		Index:     int32(event.Index),
+		Swap:      pb.SwapInfoToProto(event.Swap),
+	}
+	if event.BlockTime != nil {
+		blockTime := int64(*event.BlockTime)
+		out.BlockTime = &blockTime
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package grpc_server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pb"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func newKey() solana.PublicKey {
+	return solana.NewWallet().PublicKey()
+}
+
+func swapEvent(slot uint64, protocol tx_parser.SwapType, wallet, mintIn, mintOut solana.PublicKey) *sink.Event {
+	return &sink.Event{
+		Kind: sink.KindSwap,
+		Slot: slot,
+		Swap: &tx_parser.SwapInfo{
+			Protocol: protocol,
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: mintIn, Amount: slot},
+			TokenOut: tx_parser.TokenInfo{Mint: mintOut, Amount: slot * 2},
+		},
+	}
+}
+
+// serve starts the server on a local port and returns a connected client
+func serve(t *testing.T, server *Server) pb.StreamServiceClient {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, listener) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serve failed: %v", err)
+		}
+	})
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewStreamServiceClient(conn)
+}
+
+// waitSubscribed waits until the server has n subscriptions
+func waitSubscribed(t *testing.T, server *Server, n int) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		server.mu.Lock()
+		count := len(server.subscriptions)
+		server.mu.Unlock()
+		if count == n {
+			return
+		}
+	}
+	t.Fatalf("expected %d subscriptions", n)
+}
+
+func TestSubscribeSwaps(t *testing.T) {
+	server := New(Config{})
+	client := serve(t, server)
+	wallet, mint, sol := newKey(), newKey(), tx_parser.NATIVE_SOL_PROGRAM_ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.SubscribeSwaps(ctx, &pb.SubscribeSwapsRequest{
+		Protocols: []string{string(tx_parser.SwapTypePumpFun)},
+		Wallets:   [][]byte{wallet[:]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitSubscribed(t, server, 1)
+
+	err = server.Write(ctx, []*sink.Event{
+		swapEvent(1, tx_parser.SwapTypePumpFun, newKey(), sol, mint),
+		swapEvent(2, tx_parser.SwapTypeRaydium, wallet, sol, mint),
+		{Kind: sink.KindTransfer, Slot: 3, Transfer: &tx_parser.TransferInfo{Source: wallet}},
+		swapEvent(4, tx_parser.SwapTypePumpFun, wallet, mint, sol),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swap, err := pb.SwapInfoFromProto(event.GetSwap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.GetSlot() != 4 || !swap.TokenIn.Mint.Equals(mint) || swap.TokenOut.Amount != 8 {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// closing the server ends the stream cleanly
+	server.Close()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected the stream to end, got %v", err)
+	}
+}
+
+func TestSubscribeSwapsInvalidFilter(t *testing.T) {
+	client := serve(t, New(Config{}))
+
+	stream, err := client.SubscribeSwaps(context.Background(), &pb.SubscribeSwapsRequest{Mints: [][]byte{{1, 2, 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument, got %v", err)
+	}
+}
+
+func TestSlowSubscription(t *testing.T) {
+	server := New(Config{BufferSize: 1})
+	sub := &subscription{filter: &swapFilter{}, events: make(chan *pb.SwapEvent, 1), done: make(chan struct{})}
+	server.subscribe(sub)
+
+	mint := newKey()
+	server.Write(context.Background(), []*sink.Event{
+		swapEvent(1, tx_parser.SwapTypeOrca, newKey(), mint, newKey()),
+		swapEvent(2, tx_parser.SwapTypeOrca, newKey(), mint, newKey()),
+	})
+	select {
+	case <-sub.done:
+		if status.Code(sub.err) != codes.ResourceExhausted {
+			t.Errorf("expected resource exhausted, got %v", sub.err)
+		}
+	default:
+		t.Fatal("expected the slow subscription to end")
+	}
+	if len(server.subscriptions) != 0 {
+		t.Error("expected the subscription to be removed")
+	}
+}
+
+func TestSwapFilter(t *testing.T) {
+	wallet, mint, other := newKey(), newKey(), newKey()
+	swap := swapEvent(1, tx_parser.SwapTypeMeteora, wallet, mint, tx_parser.NATIVE_SOL_PROGRAM_ID).Swap
+
+	tests := []struct {
+		name    string
+		request *pb.SubscribeSwapsRequest
+		match   bool
+	}{
+		{"empty", &pb.SubscribeSwapsRequest{}, true},
+		{"protocol", &pb.SubscribeSwapsRequest{Protocols: []string{"Orca", "Meteora"}}, true},
+		{"other protocol", &pb.SubscribeSwapsRequest{Protocols: []string{"Orca"}}, false},
+		{"mint out", &pb.SubscribeSwapsRequest{Mints: [][]byte{tx_parser.NATIVE_SOL_PROGRAM_ID.Bytes()}}, true},
+		{"other mint", &pb.SubscribeSwapsRequest{Mints: [][]byte{other[:]}}, false},
+		{"wallet and mint", &pb.SubscribeSwapsRequest{Mints: [][]byte{mint[:]}, Wallets: [][]byte{other[:], wallet[:]}}, true},
+		{"other wallet", &pb.SubscribeSwapsRequest{Mints: [][]byte{mint[:]}, Wallets: [][]byte{other[:]}}, false},
+	}
+	for _, test := range tests {
+		filter, err := newSwapFilter(test.request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filter.match(swap) != test.match {
+			t.Errorf("%s: expected match %t", test.name, test.match)
+		}
+	}
+}
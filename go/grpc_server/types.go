@@ -0,0 +1,33 @@
+package grpc_server
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pb"
+)
+
+// Config controls the gRPC server
+type Config struct {
+	// Addr is the address ListenAndServe listens on, defaults to DEFAULT_ADDR
+	Addr string
+
+	// BufferSize is the number of events queued per subscription before it is dropped
+	// as too slow, defaults to DEFAULT_BUFFER_SIZE
+	BufferSize int
+}
+
+// swapFilter is the decoded filter of a swap subscription, empty sets match everything
+type swapFilter struct {
+	protocols map[tx_parser.SwapType]bool
+	mints     map[solana.PublicKey]bool
+	wallets   map[solana.PublicKey]bool
+}
+
+// subscription is a live SubscribeSwaps stream
+type subscription struct {
+	filter *swapFilter
+	events chan *pb.SwapEvent
+	done   chan struct{}
+	err    error // why the subscription ended, nil when the server closed
+}
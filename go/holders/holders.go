@@ -0,0 +1,254 @@
+package holders
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Token account layout shared by both token programs
+const (
+	tokenAccountLength = 165
+	ownerOffset        = 32
+	amountOffset       = 64
+)
+
+// Scanner computes holder distributions of mints
+type Scanner struct {
+	rpcClient *rpc.Client
+	config    Config
+}
+
+// New creates a holder scanner
+func New(rpcClient *rpc.Client, config Config) *Scanner {
+	if config.TopHolders <= 0 {
+		config.TopHolders = 100
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 8
+	}
+	return &Scanner{rpcClient: rpcClient, config: config}
+}
+
+// Largest returns the owners of the mint's 20 largest token accounts with getTokenLargestAccounts.
+// It is cheap but sees accounts rather than wallets; use Snapshot for a complete picture.
+func (s *Scanner) Largest(ctx context.Context, mint solana.PublicKey) ([]Holder, error) {
+	result, err := s.rpcClient.GetTokenLargestAccounts(ctx, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get largest accounts of %s: %w", mint, err)
+	}
+
+	holders := make([]Holder, 0, len(result.Value))
+	addresses := make([]solana.PublicKey, 0, len(result.Value))
+	for _, account := range result.Value {
+		amount, err := strconv.ParseUint(account.Amount, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount of %s: %w", account.Address, err)
+		}
+		holders = append(holders, Holder{Accounts: []solana.PublicKey{account.Address}, Amount: amount})
+		addresses = append(addresses, account.Address)
+	}
+	if len(addresses) == 0 {
+		return holders, nil
+	}
+
+	accounts, err := s.rpcClient.GetMultipleAccountsWithOpts(ctx, append(addresses, mint), &rpc.GetMultipleAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Encoding:   solana.EncodingBase64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts: %w", err)
+	}
+
+	var supply uint64
+	if last := len(accounts.Value) - 1; last == len(addresses) && accounts.Value[last] != nil {
+		supply, _ = mintSupply(accounts.Value[last].Data.GetBinary())
+	}
+	for i := range holders {
+		if i < len(accounts.Value) && accounts.Value[i] != nil {
+			if data := accounts.Value[i].Data.GetBinary(); len(data) >= ownerOffset+solana.PublicKeyLength {
+				holders[i].Owner = solana.PublicKeyFromBytes(data[ownerOffset : ownerOffset+solana.PublicKeyLength])
+			}
+		}
+		if supply > 0 {
+			holders[i].Percent = 100 * float64(holders[i].Amount) / float64(supply)
+		}
+	}
+	return holders, nil
+}
+
+// Snapshot scans every token account of the mint and aggregates balances by owner.
+// Token accounts or owners listed in exclude, such as pool vaults, are left out.
+func (s *Scanner) Snapshot(ctx context.Context, mint solana.PublicKey, exclude ...solana.PublicKey) (*Distribution, error) {
+	account, err := s.rpcClient.GetAccountInfoWithOpts(ctx, mint, &rpc.GetAccountInfoOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Encoding:   solana.EncodingBase64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint %s: %w", mint, err)
+	}
+	supply, decimals := mintSupply(account.Value.Data.GetBinary())
+	program := account.Value.Owner
+
+	accounts, err := s.scan(ctx, program, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[solana.PublicKey]bool, len(exclude))
+	for _, key := range exclude {
+		excluded[key] = true
+	}
+
+	distribution := &Distribution{Mint: mint, Supply: supply, Decimals: decimals}
+	byOwner := make(map[solana.PublicKey]*Holder)
+	for _, keyed := range accounts {
+		data := keyed.Account.Data.GetBinary()
+		if len(data) < amountOffset+8 {
+			continue
+		}
+		owner := solana.PublicKeyFromBytes(data[ownerOffset : ownerOffset+solana.PublicKeyLength])
+		if excluded[keyed.Pubkey] || excluded[owner] {
+			continue
+		}
+
+		distribution.Accounts++
+		amount := binary.LittleEndian.Uint64(data[amountOffset:])
+		if amount == 0 {
+			continue
+		}
+		holder, ok := byOwner[owner]
+		if !ok {
+			holder = &Holder{Owner: owner}
+			byOwner[owner] = holder
+		}
+		holder.Accounts = append(holder.Accounts, keyed.Pubkey)
+		holder.Amount += amount
+	}
+
+	all := make([]Holder, 0, len(byOwner))
+	for _, holder := range byOwner {
+		if supply > 0 {
+			holder.Percent = 100 * float64(holder.Amount) / float64(supply)
+		}
+		all = append(all, *holder)
+	}
+	slices.SortFunc(all, func(a, b Holder) int {
+		if c := cmp.Compare(b.Amount, a.Amount); c != 0 {
+			return c
+		}
+		return slices.Compare(a.Owner[:], b.Owner[:])
+	})
+
+	distribution.Holders = len(all)
+	distribution.Top = all[:min(s.config.TopHolders, len(all))]
+	distribution.Top10Percent = topPercent(all, 10)
+	distribution.Top50Percent = topPercent(all, 50)
+	distribution.Top100Percent = topPercent(all, 100)
+	distribution.Gini = gini(all)
+	return distribution, nil
+}
+
+// scan returns the token accounts of the mint, keeping only the mint, owner and amount
+func (s *Scanner) scan(ctx context.Context, program, mint solana.PublicKey) (rpc.GetProgramAccountsResult, error) {
+	filters := []rpc.RPCFilter{{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: solana.Base58(mint[:])}}}
+	if program.Equals(solana.TokenProgramID) {
+		filters = append(filters, rpc.RPCFilter{DataSize: tokenAccountLength})
+	}
+
+	query := func(filters []rpc.RPCFilter) (rpc.GetProgramAccountsResult, error) {
+		offset, length := uint64(0), uint64(amountOffset+8)
+		accounts, err := s.rpcClient.GetProgramAccountsWithOpts(ctx, program, &rpc.GetProgramAccountsOpts{
+			Commitment: rpc.CommitmentConfirmed,
+			Encoding:   solana.EncodingBase64,
+			DataSlice:  &rpc.DataSlice{Offset: &offset, Length: &length},
+			Filters:    filters,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token accounts of %s: %w", mint, err)
+		}
+		return accounts, nil
+	}
+	if !s.config.Paginate {
+		return query(filters)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		accounts rpc.GetProgramAccountsResult
+		firstErr error
+	)
+	slots := make(chan struct{}, s.config.Concurrency)
+	for page := range 256 {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			ownerPrefix := rpc.RPCFilter{Memcmp: &rpc.RPCFilterMemcmp{Offset: ownerOffset, Bytes: solana.Base58{byte(page)}}}
+			result, err := query(append(slices.Clone(filters), ownerPrefix))
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				firstErr = cmp.Or(firstErr, err)
+				return
+			}
+			accounts = append(accounts, result...)
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return accounts, nil
+}
+
+// mintSupply reads the supply and decimals of a mint account
+func mintSupply(data []byte) (uint64, uint8) {
+	if len(data) < 45 {
+		return 0, 0
+	}
+	return binary.LittleEndian.Uint64(data[36:44]), data[44]
+}
+
+// topPercent sums the share of the n largest holders
+func topPercent(holders []Holder, n int) float64 {
+	var percent float64
+	for _, holder := range holders[:min(n, len(holders))] {
+		percent += holder.Percent
+	}
+	return percent
+}
+
+// gini returns the Gini coefficient of the balances, which are sorted in descending order
+func gini(holders []Holder) float64 {
+	n := len(holders)
+	if n == 0 {
+		return 0
+	}
+
+	// With balances ranked ascending as x_1..x_n: G = 2*sum(i*x_i) / (n*sum(x)) - (n+1)/n
+	var weighted, total float64
+	for i, holder := range holders {
+		rank := float64(n - i)
+		weighted += rank * float64(holder.Amount)
+		total += float64(holder.Amount)
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2*weighted/(float64(n)*total) - float64(n+1)/float64(n)
+}
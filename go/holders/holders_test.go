@@ -0,0 +1,177 @@
+package holders
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+type testAccount struct {
+	key   solana.PublicKey
+	owner solana.PublicKey
+	data  []byte
+}
+
+func newKey() solana.PublicKey {
+	return solana.NewWallet().PublicKey()
+}
+
+func tokenAccount(mint, owner solana.PublicKey, amount uint64) testAccount {
+	data := make([]byte, tokenAccountLength)
+	copy(data[0:32], mint[:])
+	copy(data[32:64], owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], amount)
+	return testAccount{key: newKey(), owner: solana.TokenProgramID, data: data}
+}
+
+func serve(t *testing.T, mint testAccount, accounts []testAccount, scans *atomic.Int32) *rpc.Client {
+	t.Helper()
+	encode := func(account testAccount, data []byte) string {
+		return fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+			account.owner, base64.StdEncoding.EncodeToString(data))
+	}
+	find := func(key solana.PublicKey) (testAccount, bool) {
+		for _, account := range append(accounts, mint) {
+			if account.key.Equals(key) {
+				return account, true
+			}
+		}
+		return testAccount{}, false
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		var result string
+		switch request.Method {
+		case "getAccountInfo":
+			result = fmt.Sprintf(`{"context":{"slot":1},"value":%s}`, encode(mint, mint.data))
+		case "getMultipleAccounts":
+			var keys []solana.PublicKey
+			json.Unmarshal(request.Params[0], &keys)
+			values := make([]string, len(keys))
+			for i, key := range keys {
+				values[i] = "null"
+				if account, ok := find(key); ok {
+					values[i] = encode(account, account.data)
+				}
+			}
+			result = fmt.Sprintf(`{"context":{"slot":1},"value":[%s]}`, strings.Join(values, ","))
+		case "getProgramAccounts":
+			scans.Add(1)
+			var opts rpc.GetProgramAccountsOpts
+			json.Unmarshal(request.Params[1], &opts)
+			var values []string
+			for _, account := range accounts {
+				matches := true
+				for _, filter := range opts.Filters {
+					if filter.DataSize > 0 && uint64(len(account.data)) != filter.DataSize {
+						matches = false
+					}
+					if m := filter.Memcmp; m != nil && !bytes.Equal(account.data[m.Offset:m.Offset+uint64(len(m.Bytes))], m.Bytes) {
+						matches = false
+					}
+				}
+				if matches {
+					values = append(values, fmt.Sprintf(`{"pubkey":%q,"account":%s}`, account.key, encode(account, account.data[:*opts.DataSlice.Length])))
+				}
+			}
+			result = "[" + strings.Join(values, ",") + "]"
+		case "getTokenLargestAccounts":
+			var values []string
+			for _, account := range accounts[:2] {
+				values = append(values, fmt.Sprintf(`{"address":%q,"amount":"%d","decimals":6}`, account.key, binary.LittleEndian.Uint64(account.data[64:72])))
+			}
+			result = fmt.Sprintf(`{"context":{"slot":1},"value":[%s]}`, strings.Join(values, ","))
+		default:
+			t.Errorf("unexpected method %s", request.Method)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, result)
+	}))
+	t.Cleanup(server.Close)
+	return rpc.New(server.URL)
+}
+
+func TestSnapshot(t *testing.T) {
+	mintData := make([]byte, 82)
+	binary.LittleEndian.PutUint64(mintData[36:44], 1_000)
+	mintData[44] = 6
+	mint := testAccount{key: newKey(), owner: solana.TokenProgramID, data: mintData}
+
+	whale, fish, shrimp, pool := newKey(), newKey(), newKey(), newKey()
+	accounts := []testAccount{
+		tokenAccount(mint.key, whale, 500),
+		tokenAccount(mint.key, pool, 300),
+		tokenAccount(mint.key, whale, 100),
+		tokenAccount(mint.key, fish, 80),
+		tokenAccount(mint.key, shrimp, 20),
+		tokenAccount(mint.key, newKey(), 0),
+		tokenAccount(newKey(), whale, 1_000_000), // another mint
+	}
+
+	for _, paginate := range []bool{false, true} {
+		var scans atomic.Int32
+		scanner := New(serve(t, mint, accounts, &scans), Config{TopHolders: 2, Paginate: paginate})
+
+		distribution, err := scanner.Snapshot(context.Background(), mint.key, pool)
+		if err != nil {
+			t.Fatalf("failed to take snapshot: %v", err)
+		}
+		if paginate && scans.Load() != 256 {
+			t.Errorf("expected 256 pages, got %d", scans.Load())
+		}
+
+		if distribution.Supply != 1_000 || distribution.Decimals != 6 || distribution.Accounts != 5 || distribution.Holders != 3 {
+			t.Errorf("unexpected totals: %+v", distribution)
+		}
+		if len(distribution.Top) != 2 || !distribution.Top[0].Owner.Equals(whale) || distribution.Top[0].Amount != 600 || len(distribution.Top[0].Accounts) != 2 {
+			t.Errorf("unexpected top holders: %+v", distribution.Top)
+		}
+		if distribution.Top10Percent != 70 || distribution.Top[1].Percent != 8 {
+			t.Errorf("unexpected shares: %+v", distribution)
+		}
+
+		// Balances 20, 80, 600: 2*(20 + 160 + 1800) / (3*700) - 4/3
+		if expected := 2*1980.0/2100 - 4.0/3; math.Abs(distribution.Gini-expected) > 1e-12 {
+			t.Errorf("expected gini %v, got %v", expected, distribution.Gini)
+		}
+	}
+}
+
+func TestLargest(t *testing.T) {
+	mintData := make([]byte, 82)
+	binary.LittleEndian.PutUint64(mintData[36:44], 2_000)
+	mint := testAccount{key: newKey(), owner: solana.TokenProgramID, data: mintData}
+	owner := newKey()
+	accounts := []testAccount{tokenAccount(mint.key, owner, 500), tokenAccount(mint.key, newKey(), 100)}
+
+	var scans atomic.Int32
+	holders, err := New(serve(t, mint, accounts, &scans), Config{}).Largest(context.Background(), mint.key)
+	if err != nil {
+		t.Fatalf("failed to get largest holders: %v", err)
+	}
+	if len(holders) != 2 || !holders[0].Owner.Equals(owner) || holders[0].Percent != 25 || !holders[0].Accounts[0].Equals(accounts[0].key) {
+		t.Errorf("unexpected holders %+v", holders)
+	}
+}
@@ -0,0 +1,38 @@
+package holders
+
+import "github.com/gagliardetto/solana-go"
+
+// Holder is a wallet's balance of a mint, summed over its token accounts
+type Holder struct {
+	Owner    solana.PublicKey   `json:"owner"`
+	Accounts []solana.PublicKey `json:"accounts"`
+	Amount   uint64             `json:"amount,string"`
+	Percent  float64            `json:"percent"` // share of the supply, 0 to 100
+}
+
+// Distribution is a snapshot of how a mint's supply is spread across wallets
+type Distribution struct {
+	Mint          solana.PublicKey `json:"mint"`
+	Supply        uint64           `json:"supply,string"`
+	Decimals      uint8            `json:"decimals"`
+	Accounts      int              `json:"accounts"`       // token accounts, including empty ones
+	Holders       int              `json:"holders"`        // wallets with a non-zero balance
+	Top           []Holder         `json:"top"`            // largest holders, in descending order
+	Top10Percent  float64          `json:"top10_percent"`  // share of the supply held by the 10 largest holders
+	Top50Percent  float64          `json:"top50_percent"`  // share of the supply held by the 50 largest holders
+	Top100Percent float64          `json:"top100_percent"` // share of the supply held by the 100 largest holders
+	Gini          float64          `json:"gini"`           // 0 when every holder has the same balance, approaching 1 when one holds everything
+}
+
+// Config controls holder scans
+type Config struct {
+	// TopHolders is the number of largest holders kept in a snapshot, defaults to 100
+	TopHolders int
+
+	// Paginate splits the getProgramAccounts scan into 256 requests by the first byte of
+	// the owner, for mints with more accounts than the RPC returns in one response
+	Paginate bool
+
+	// Concurrency bounds parallel page requests, defaults to 8
+	Concurrency int
+}
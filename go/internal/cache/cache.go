@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// Cache is a concurrent-safe LRU cache split into independently locked shards, so
+// parallel lookups of different keys rarely contend on the same mutex. Each shard
+// evicts its own least recently used entry once full.
+type Cache[K comparable, V any] struct {
+	seed   maphash.Seed
+	mask   uint64
+	shards []*shard[K, V]
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+type shard[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key    K
+	value  V
+	stored time.Time
+}
+
+// New creates a new sharded cache
+func New[K comparable, V any](config Config) *Cache[K, V] {
+	if config.Size <= 0 {
+		config.Size = DEFAULT_SIZE
+	}
+	if config.Shards <= 0 {
+		config.Shards = DEFAULT_SHARDS
+	}
+
+	count := 1
+	for count < config.Shards && count*2 <= config.Size {
+		count *= 2
+	}
+
+	c := &Cache[K, V]{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(count - 1),
+		shards: make([]*shard[K, V], count),
+		ttl:    config.TTL,
+		now:    time.Now,
+	}
+	for i := range c.shards {
+		c.shards[i] = &shard[K, V]{
+			capacity: config.Size / count,
+			order:    list.New(),
+			entries:  make(map[K]*list.Element),
+		}
+	}
+	return c
+}
+
+// Get returns the cached value of the key, treating expired entries as missing
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	s := c.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := element.Value.(*entry[K, V])
+	if c.ttl > 0 && c.now().Sub(e.stored) > c.ttl {
+		s.order.Remove(element)
+		delete(s.entries, key)
+		var zero V
+		return zero, false
+	}
+	s.order.MoveToFront(element)
+	return e.value, true
+}
+
+// Put stores the value of the key, evicting the least recently used entry of its shard when full
+func (c *Cache[K, V]) Put(key K, value V) {
+	now := c.now()
+	s := c.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		e := element.Value.(*entry[K, V])
+		e.value, e.stored = value, now
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&entry[K, V]{key: key, value: value, stored: now})
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*entry[K, V]).key)
+	}
+}
+
+// Delete removes the key
+func (c *Cache[K, V]) Delete(key K) {
+	s := c.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+}
+
+// Len returns the number of entries, including expired ones not evicted yet
+func (c *Cache[K, V]) Len() int {
+	total := 0
+	for _, s := range c.shards {
+		s.mu.Lock()
+		total += s.order.Len()
+		s.mu.Unlock()
+	}
+	return total
+}
+
+// shard returns the shard owning the key
+func (c *Cache[K, V]) shard(key K) *shard[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)&c.mask]
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := New[string, int](Config{Size: 2, Shards: 1})
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+	cache.Put("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected the least recently used key to be evicted")
+	}
+	if value, ok := cache.Get("a"); !ok || value != 1 {
+		t.Error("expected the recently used key to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cache := New[string, int](Config{TTL: time.Minute})
+	cache.now = func() time.Time { return now }
+
+	cache.Put("a", 1)
+	now = now.Add(30 * time.Second)
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a fresh entry to be served")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expected an expired entry to be missing")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("expected the expired entry to be removed, got %d entries", cache.Len())
+	}
+}
+
+func TestCacheBoundsSize(t *testing.T) {
+	cache := New[int, int](Config{Size: 100, Shards: 8})
+	for i := range 1_000 {
+		cache.Put(i, i)
+	}
+	if cache.Len() > 100 {
+		t.Errorf("expected at most 100 entries, got %d", cache.Len())
+	}
+
+	// More shards than entries are capped so every shard holds at least one
+	small := New[int, int](Config{Size: 3, Shards: 64})
+	if len(small.shards) != 2 {
+		t.Errorf("expected 2 shards, got %d", len(small.shards))
+	}
+}
+
+func TestCacheConcurrentAccess(t *testing.T) {
+	cache := New[int, int](Config{Size: 1_000})
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 10_000 {
+				key := (worker*10_000 + i) % 2_000
+				cache.Put(key, i)
+				cache.Get(key)
+				if i%7 == 0 {
+					cache.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cache.Len() > 1_000 {
+		t.Errorf("expected at most 1000 entries, got %d", cache.Len())
+	}
+}
+
+func BenchmarkCacheParallelGet(b *testing.B) {
+	cache := New[int, int](Config{})
+	for i := range DEFAULT_SIZE {
+		cache.Put(i, i)
+	}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get(i % DEFAULT_SIZE)
+			i++
+		}
+	})
+}
@@ -0,0 +1,9 @@
+package cache
+
+const (
+	// DEFAULT_SIZE is the number of entries kept when Config.Size is not set
+	DEFAULT_SIZE = 10_000
+
+	// DEFAULT_SHARDS is the number of independently locked shards when Config.Shards is not set
+	DEFAULT_SHARDS = 32
+)
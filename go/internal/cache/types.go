@@ -0,0 +1,16 @@
+package cache
+
+import "time"
+
+// Config bounds a Cache
+type Config struct {
+	// Size is the maximum number of entries across all shards, defaults to DEFAULT_SIZE
+	Size int
+
+	// TTL expires entries older than it, zero never expires
+	TTL time.Duration
+
+	// Shards is rounded up to a power of two and capped so every shard holds at least
+	// one entry, defaults to DEFAULT_SHARDS
+	Shards int
+}
@@ -0,0 +1,11 @@
+package corpus
+
+const (
+	// DEFAULT_DIR is the corpus directory relative to the module root
+	DEFAULT_DIR = "internal/corpus/testdata"
+
+	// FIXTURE_EXT and GOLDEN_EXT are the suffixes of a recorded getTransaction result
+	// and of the parser output expected for it
+	FIXTURE_EXT = ".json"
+	GOLDEN_EXT  = ".golden.json"
+)
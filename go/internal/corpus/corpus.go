@@ -0,0 +1,131 @@
+package corpus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Record fetches the transaction and writes it into the corpus under the protocol along
+// with its golden file. The golden file must be reviewed before it is committed.
+func Record(ctx context.Context, rpcClient *rpc.Client, signature solana.Signature, dir, protocol string) (Fixture, error) {
+	if protocol == "" || strings.ContainsAny(protocol, `/\`) {
+		return Fixture{}, fmt.Errorf("invalid protocol %q", protocol)
+	}
+
+	maxSupportedTxVersion := uint64(0)
+	txResult, err := rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxSupportedTxVersion,
+	})
+	if err != nil {
+		return Fixture{}, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	fixture := newFixture(dir, protocol, signature.String())
+	data, err := json.MarshalIndent(txResult, "", "  ")
+	if err != nil {
+		return Fixture{}, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(fixture.Path), 0o755); err != nil {
+		return Fixture{}, fmt.Errorf("failed to create corpus directory: %w", err)
+	}
+	if err := os.WriteFile(fixture.Path, append(data, '\n'), 0o644); err != nil {
+		return Fixture{}, fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	if err := WriteGolden(fixture); err != nil {
+		return Fixture{}, err
+	}
+	return fixture, nil
+}
+
+// List returns the fixtures of the corpus, ordered by protocol and name
+func List(dir string) ([]Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*"+FIXTURE_EXT))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+
+	var fixtures []Fixture
+	for _, path := range paths {
+		if strings.HasSuffix(path, GOLDEN_EXT) {
+			continue
+		}
+		protocol := filepath.Base(filepath.Dir(path))
+		fixtures = append(fixtures, newFixture(dir, protocol, strings.TrimSuffix(filepath.Base(path), FIXTURE_EXT)))
+	}
+	return fixtures, nil
+}
+
+// Load decodes the recorded getTransaction result of the fixture
+func Load(fixture Fixture) (*rpc.GetTransactionResult, error) {
+	data, err := os.ReadFile(fixture.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var txResult rpc.GetTransactionResult
+	if err := json.Unmarshal(data, &txResult); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", fixture.Path, err)
+	}
+	return &txResult, nil
+}
+
+// Parse runs the fixture through the parser and returns the output in the golden format
+func Parse(fixture Fixture) ([]byte, error) {
+	txResult, err := Load(fixture)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, err := tx_parser.New(txResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parser: %w", err)
+	}
+	parsed, err := parser.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(parsed); err != nil {
+		return nil, fmt.Errorf("failed to encode parsed transaction: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteGolden writes the current parser output of the fixture to its golden file
+func WriteGolden(fixture Fixture) error {
+	output, err := Parse(fixture)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(fixture.GoldenPath, output, 0o644); err != nil {
+		return fmt.Errorf("failed to write golden file: %w", err)
+	}
+	return nil
+}
+
+// newFixture builds the paths of a fixture
+func newFixture(dir, protocol, name string) Fixture {
+	base := filepath.Join(dir, protocol, name)
+	return Fixture{
+		Protocol:   protocol,
+		Name:       name,
+		Path:       base + FIXTURE_EXT,
+		GoldenPath: base + GOLDEN_EXT,
+	}
+}
@@ -0,0 +1,104 @@
+package corpus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files from the current parser output")
+
+// TestGolden asserts the parser output of every fixture in the corpus against its golden file
+func TestGolden(t *testing.T) {
+	fixtures, err := List("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Skip("no fixtures recorded in testdata")
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture.Protocol+"/"+fixture.Name, func(t *testing.T) {
+			if *update {
+				if err := WriteGolden(fixture); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			got, err := Parse(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(fixture.GoldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden file, record it with -update: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("parser output differs from %s, rerun with -update if the change is intended:\n%s", fixture.GoldenPath, got)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	source := newFixture("testdata", "raydium", "synthetic-v4-swap")
+	recorded, err := os.ReadFile(source.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Method string `json:"method"`
+		}
+		json.Unmarshal(body, &request)
+		if request.Method != "getTransaction" {
+			http.Error(w, "unexpected method "+request.Method, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, recorded)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	signature := solana.Signature{1}
+	fixture, err := Record(context.Background(), rpc.New(server.URL), signature, dir, "raydium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixture.Name != signature.String() {
+		t.Errorf("expected the fixture to be named after the signature, got %s", fixture.Name)
+	}
+
+	listed, err := List(dir)
+	if err != nil || len(listed) != 1 || listed[0] != fixture {
+		t.Fatalf("expected the recorded fixture to be listed, got %+v (%v)", listed, err)
+	}
+	got, err := os.ReadFile(fixture.GoldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("recorded golden file differs from the source fixture:\n%s", got)
+	}
+
+	if _, err := Record(context.Background(), rpc.New(server.URL), signature, dir, "../raydium"); err == nil {
+		t.Error("expected a protocol outside the corpus to be rejected")
+	}
+}
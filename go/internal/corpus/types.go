@@ -0,0 +1,10 @@
+package corpus
+
+// Fixture is a recorded transaction of the corpus, stored as <dir>/<protocol>/<name>.json
+// next to its golden file <name>.golden.json
+type Fixture struct {
+	Protocol   string
+	Name       string // the transaction signature for recorded fixtures
+	Path       string
+	GoldenPath string
+}
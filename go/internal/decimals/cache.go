@@ -0,0 +1,92 @@
+package decimals
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// PersistentCache stores resolved decimals across restarts. Mint decimals never
+// change once a mint is created, so entries never expire.
+type PersistentCache interface {
+	Get(mint solana.PublicKey) (uint8, bool)
+	Put(mint solana.PublicKey, decimals uint8) error
+}
+
+// FileCache is a PersistentCache backed by a JSON file
+type FileCache struct {
+	mu       sync.RWMutex
+	path     string
+	decimals map[string]uint8 // map[mint_address]decimals
+}
+
+// NewFileCache opens the cache file at path, starting empty if it does not exist yet
+func NewFileCache(path string) (*FileCache, error) {
+	cache := &FileCache{
+		path:     path,
+		decimals: make(map[string]uint8),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decimals cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &cache.decimals); err != nil {
+		return nil, fmt.Errorf("failed to decode decimals cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+// Get returns the cached decimals of the mint
+func (c *FileCache) Get(mint solana.PublicKey) (uint8, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	decimals, ok := c.decimals[mint.String()]
+	return decimals, ok
+}
+
+// Put stores the decimals of the mint and rewrites the cache file
+func (c *FileCache) Put(mint solana.PublicKey, decimals uint8) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.decimals[mint.String()]; ok && existing == decimals {
+		return nil
+	}
+	c.decimals[mint.String()] = decimals
+
+	data, err := json.Marshal(c.decimals)
+	if err != nil {
+		return fmt.Errorf("failed to encode decimals cache: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write decimals cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write decimals cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write decimals cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write decimals cache: %w", err)
+	}
+
+	return nil
+}
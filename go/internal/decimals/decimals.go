@@ -0,0 +1,229 @@
+package decimals
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/soralabs/solana-toolkit/go/internal/cache"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+const (
+	// Mint layout offset of the decimals field, shared by both token programs
+	mintDecimalsOffset = 44
+
+	// getMultipleAccounts accepts at most 100 keys per request
+	maxAccountsPerRequest = 100
+)
+
+// Options configures a Resolver
+type Options struct {
+	// CacheSize is the number of mints kept in memory, defaults to 10000
+	CacheSize int
+
+	// CacheShards is the number of independently locked cache shards, defaults to 32
+	CacheShards int
+
+	// Persistent is an optional cache consulted before the RPC and written after each lookup
+	Persistent PersistentCache
+
+	// Timeout bounds RPC lookups made through MintDecimals, defaults to 5 seconds
+	Timeout time.Duration
+
+	// ExtensionsTTL bounds how long Token-2022 extensions are cached, since their
+	// authorities can change fees and rates, defaults to 10 minutes
+	ExtensionsTTL time.Duration
+
+	Commitment rpc.CommitmentType
+}
+
+// Resolver resolves mint decimals from a sharded in-memory LRU cache, an optional
+// persistent cache and finally the mint account itself. It also resolves Token-2022
+// extensions, cached in memory only. It is safe for concurrent use and meant to be
+// shared by every parser in the process.
+type Resolver struct {
+	rpcClient  *rpc.Client
+	cache      *cache.Cache[solana.PublicKey, uint8]
+	extensions *cache.Cache[solana.PublicKey, *tx_parser.TokenExtensions]
+	inflight   singleflight.Group
+	opts       Options
+}
+
+// NewResolver creates a new decimals resolver
+func NewResolver(rpcClient *rpc.Client, opts Options) *Resolver {
+	if opts.CacheSize <= 0 {
+		opts.CacheSize = 10_000
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.ExtensionsTTL <= 0 {
+		opts.ExtensionsTTL = 10 * time.Minute
+	}
+	if opts.Commitment == "" {
+		opts.Commitment = rpc.CommitmentConfirmed
+	}
+
+	return &Resolver{
+		rpcClient:  rpcClient,
+		cache:      cache.New[solana.PublicKey, uint8](cache.Config{Size: opts.CacheSize, Shards: opts.CacheShards}),
+		extensions: cache.New[solana.PublicKey, *tx_parser.TokenExtensions](cache.Config{Size: opts.CacheSize, TTL: opts.ExtensionsTTL, Shards: opts.CacheShards}),
+		opts:       opts,
+	}
+}
+
+// MintDecimals returns the decimals of the mint using the configured timeout
+func (r *Resolver) MintDecimals(mint solana.PublicKey) (uint8, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+	defer cancel()
+
+	return r.Resolve(ctx, mint)
+}
+
+// Resolve returns the decimals of the mint. Concurrent lookups of the same uncached
+// mint share a single RPC call.
+func (r *Resolver) Resolve(ctx context.Context, mint solana.PublicKey) (uint8, error) {
+	if decimals, ok := r.cached(mint); ok {
+		return decimals, nil
+	}
+
+	decimals, err, _ := r.inflight.Do(mint.String(), func() (any, error) {
+		resolved, err := r.ResolveMany(ctx, []solana.PublicKey{mint})
+		if err != nil {
+			return uint8(0), err
+		}
+
+		decimals, ok := resolved[mint]
+		if !ok {
+			return uint8(0), fmt.Errorf("mint account %s not found", mint)
+		}
+		return decimals, nil
+	})
+	return decimals.(uint8), err
+}
+
+// ResolveMany returns the decimals of every mint that exists, fetching uncached mints
+// in batches. Mints without an account are left out of the result.
+func (r *Resolver) ResolveMany(ctx context.Context, mints []solana.PublicKey) (map[solana.PublicKey]uint8, error) {
+	resolved := make(map[solana.PublicKey]uint8, len(mints))
+
+	var missing []solana.PublicKey
+	for _, mint := range mints {
+		if _, ok := resolved[mint]; ok {
+			continue
+		}
+		if decimals, ok := r.cached(mint); ok {
+			resolved[mint] = decimals
+			continue
+		}
+		missing = append(missing, mint)
+	}
+
+	for start := 0; start < len(missing); start += maxAccountsPerRequest {
+		end := min(start+maxAccountsPerRequest, len(missing))
+
+		result, err := r.rpcClient.GetMultipleAccountsWithOpts(ctx, missing[start:end], &rpc.GetMultipleAccountsOpts{
+			Commitment: r.opts.Commitment,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mint accounts: %w", err)
+		}
+
+		for i, account := range result.Value {
+			if account == nil || account.Data == nil {
+				continue
+			}
+			data := account.Data.GetBinary()
+			if len(data) <= mintDecimalsOffset {
+				continue
+			}
+
+			mint := missing[start+i]
+			r.storeExtensions(mint, account)
+			resolved[mint] = data[mintDecimalsOffset]
+			if err := r.store(mint, data[mintDecimalsOffset]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+// MintExtensions returns the Token-2022 extensions of the mint using the configured
+// timeout, nil for legacy token mints and mints without extensions
+func (r *Resolver) MintExtensions(mint solana.PublicKey) (*tx_parser.TokenExtensions, error) {
+	if extensions, ok := r.extensions.Get(mint); ok {
+		return extensions, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+	defer cancel()
+
+	extensions, err, _ := r.inflight.Do("extensions:"+mint.String(), func() (any, error) {
+		result, err := r.rpcClient.GetMultipleAccountsWithOpts(ctx, []solana.PublicKey{mint}, &rpc.GetMultipleAccountsOpts{
+			Commitment: r.opts.Commitment,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return (*tx_parser.TokenExtensions)(nil), fmt.Errorf("failed to get mint account: %w", err)
+		}
+		if len(result.Value) != 1 || result.Value[0] == nil || result.Value[0].Data == nil {
+			return (*tx_parser.TokenExtensions)(nil), fmt.Errorf("mint account %s not found", mint)
+		}
+		return r.storeExtensions(mint, result.Value[0])
+	})
+	return extensions.(*tx_parser.TokenExtensions), err
+}
+
+// storeExtensions decodes and caches the extensions of a mint account
+func (r *Resolver) storeExtensions(mint solana.PublicKey, account *rpc.Account) (*tx_parser.TokenExtensions, error) {
+	var extensions *tx_parser.TokenExtensions
+	if account.Owner.Equals(solana.Token2022ProgramID) {
+		var err error
+		if extensions, err = tx_parser.DecodeTokenExtensions(account.Data.GetBinary()); err != nil {
+			return nil, fmt.Errorf("failed to decode mint %s: %w", mint, err)
+		}
+	}
+	r.extensions.Put(mint, extensions)
+	return extensions, nil
+}
+
+// Add records known decimals, e.g. taken from transaction token balances
+func (r *Resolver) Add(mint solana.PublicKey, decimals uint8) error {
+	return r.store(mint, decimals)
+}
+
+// cached looks up the mint in memory, then in the persistent cache
+func (r *Resolver) cached(mint solana.PublicKey) (uint8, bool) {
+	if decimals, ok := r.cache.Get(mint); ok {
+		return decimals, true
+	}
+	if r.opts.Persistent == nil {
+		return 0, false
+	}
+
+	decimals, ok := r.opts.Persistent.Get(mint)
+	if ok {
+		r.cache.Put(mint, decimals)
+	}
+	return decimals, ok
+}
+
+// store writes the decimals to both cache layers
+func (r *Resolver) store(mint solana.PublicKey, decimals uint8) error {
+	r.cache.Put(mint, decimals)
+	if r.opts.Persistent == nil {
+		return nil
+	}
+	if err := r.opts.Persistent.Put(mint, decimals); err != nil {
+		return fmt.Errorf("failed to persist decimals: %w", err)
+	}
+	return nil
+}
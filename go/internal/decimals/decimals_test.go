@@ -0,0 +1,172 @@
+package decimals
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// newMintServer serves getMultipleAccounts with mint accounts of the given decimals
+func newMintServer(t *testing.T, mints map[solana.PublicKey]uint8, calls *int32) *rpc.Client {
+	t.Helper()
+	accounts := make(map[solana.PublicKey]*rpc.Account, len(mints))
+	for mint, decimals := range mints {
+		data := make([]byte, 82)
+		data[mintDecimalsOffset] = decimals
+		accounts[mint] = &rpc.Account{Owner: solana.TokenProgramID, Data: rpc.DataBytesOrJSONFromBytes(data)}
+	}
+	return newAccountServer(t, accounts, calls)
+}
+
+// newAccountServer serves getMultipleAccounts with the given accounts
+func newAccountServer(t *testing.T, accounts map[solana.PublicKey]*rpc.Account, calls *int32) *rpc.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		body, _ := io.ReadAll(r.Body)
+
+		var request struct {
+			ID     any   `json:"id"`
+			Params []any `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		var values []string
+		for _, key := range request.Params[0].([]any) {
+			account, ok := accounts[solana.MustPublicKeyFromBase58(key.(string))]
+			if !ok {
+				values = append(values, "null")
+				continue
+			}
+			values = append(values, fmt.Sprintf(`{"data":[%q,"base64"],"executable":false,"lamports":1,"owner":%q,"rentEpoch":0}`,
+				base64.StdEncoding.EncodeToString(account.Data.GetBinary()), account.Owner))
+		}
+
+		id, _ := json.Marshal(request.ID)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"context":{"slot":1},"value":[%s]}}`, id, strings.Join(values, ","))
+	}))
+	t.Cleanup(server.Close)
+
+	return rpc.New(server.URL)
+}
+
+func TestResolverFetchesAndCaches(t *testing.T) {
+	usdc, bonk, missing := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	var calls int32
+	client := newMintServer(t, map[solana.PublicKey]uint8{usdc: 6, bonk: 5}, &calls)
+
+	persistent, err := NewFileCache(filepath.Join(t.TempDir(), "decimals.json"))
+	if err != nil {
+		t.Fatalf("failed to open file cache: %v", err)
+	}
+	resolver := NewResolver(client, Options{Persistent: persistent})
+
+	decimals, err := resolver.MintDecimals(usdc)
+	if err != nil || decimals != 6 {
+		t.Fatalf("expected 6 decimals, got %d (%v)", decimals, err)
+	}
+	if _, err := resolver.MintDecimals(usdc); err != nil {
+		t.Fatalf("failed to resolve cached mint: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected cached lookups to skip the RPC, got %d calls", calls)
+	}
+
+	resolved, err := resolver.ResolveMany(context.Background(), []solana.PublicKey{usdc, bonk, missing})
+	if err != nil {
+		t.Fatalf("failed to resolve mints: %v", err)
+	}
+	if len(resolved) != 2 || resolved[bonk] != 5 {
+		t.Errorf("unexpected resolved decimals: %v", resolved)
+	}
+	if _, err := resolver.MintDecimals(missing); err == nil {
+		t.Error("expected an error for a missing mint account")
+	}
+
+	// A fresh resolver reads from the persistent cache without calling the RPC
+	reopened, err := NewFileCache(persistent.path)
+	if err != nil {
+		t.Fatalf("failed to reopen file cache: %v", err)
+	}
+	before := atomic.LoadInt32(&calls)
+	decimals, err = NewResolver(client, Options{Persistent: reopened}).MintDecimals(bonk)
+	if err != nil || decimals != 5 {
+		t.Errorf("expected 5 decimals from the persistent cache, got %d (%v)", decimals, err)
+	}
+	if atomic.LoadInt32(&calls) != before {
+		t.Error("expected the persistent cache to avoid an RPC call")
+	}
+}
+
+func TestResolverSharesConcurrentLookups(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	var calls int32
+	resolver := NewResolver(newMintServer(t, map[solana.PublicKey]uint8{mint: 6}, &calls), Options{})
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if decimals, err := resolver.MintDecimals(mint); err != nil || decimals != 6 {
+				t.Errorf("expected 6 decimals, got %d (%v)", decimals, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("expected concurrent lookups to share one RPC call, got %d", calls)
+	}
+}
+
+func TestResolverMintExtensions(t *testing.T) {
+	legacy, hooked, hook := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+
+	// Token-2022 mint with a transfer hook extension: authority, program
+	data := make([]byte, 165, 166+4+64)
+	data[mintDecimalsOffset] = 6
+	data = append(data, 1, 14, 0, 64, 0)
+	data = append(data, make([]byte, 32)...)
+	data = append(data, hook[:]...)
+
+	var calls int32
+	resolver := NewResolver(newAccountServer(t, map[solana.PublicKey]*rpc.Account{
+		legacy: {Owner: solana.TokenProgramID, Data: rpc.DataBytesOrJSONFromBytes(make([]byte, 82))},
+		hooked: {Owner: solana.Token2022ProgramID, Data: rpc.DataBytesOrJSONFromBytes(data)},
+	}, &calls), Options{})
+
+	// Decimals lookups cache the extensions of the mints they fetch
+	if decimals, err := resolver.MintDecimals(hooked); err != nil || decimals != 6 {
+		t.Fatalf("expected 6 decimals, got %d (%v)", decimals, err)
+	}
+	extensions, err := resolver.MintExtensions(hooked)
+	if err != nil || extensions == nil || !extensions.TransferHookProgram.Equals(hook) {
+		t.Fatalf("expected the transfer hook program, got %+v (%v)", extensions, err)
+	}
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("expected the extensions from the decimals lookup, got %d calls", calls)
+	}
+	if extensions, err := resolver.MintExtensions(legacy); err != nil || extensions != nil {
+		t.Errorf("expected no extensions for a legacy mint, got %+v (%v)", extensions, err)
+	}
+	if _, err := resolver.MintExtensions(solana.NewWallet().PublicKey()); err == nil {
+		t.Error("expected a missing mint to fail")
+	}
+}
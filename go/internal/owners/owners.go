@@ -0,0 +1,166 @@
+package owners
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/soralabs/solana-toolkit/go/internal/cache"
+)
+
+const (
+	// Token account layout, shared by both token programs
+	tokenAccountMintOffset  = 0
+	tokenAccountOwnerOffset = 32
+	tokenAccountSize        = 165
+
+	// getMultipleAccounts accepts at most 100 keys per request
+	maxAccountsPerRequest = 100
+)
+
+// Account is the owner and mint of a token account
+type Account struct {
+	Owner solana.PublicKey
+	Mint  solana.PublicKey
+}
+
+// Options configures a Resolver
+type Options struct {
+	// CacheSize is the number of token accounts kept in memory, defaults to 10000
+	CacheSize int
+
+	// CacheShards is the number of independently locked cache shards, defaults to 32
+	CacheShards int
+
+	// TTL expires cached owners, since the owner of a token account can be changed with
+	// SetAuthority, defaults to 10 minutes
+	TTL time.Duration
+
+	// Timeout bounds RPC lookups made through TokenAccount, defaults to 5 seconds
+	Timeout time.Duration
+
+	Commitment rpc.CommitmentType
+}
+
+// Resolver resolves the owner and mint of token accounts from a sharded in-memory
+// cache, falling back to the token account itself. It is safe for concurrent use and
+// meant to be shared by every parser in the process.
+type Resolver struct {
+	rpcClient *rpc.Client
+	cache     *cache.Cache[solana.PublicKey, Account]
+	inflight  singleflight.Group
+	opts      Options
+}
+
+// NewResolver creates a new token account resolver
+func NewResolver(rpcClient *rpc.Client, opts Options) *Resolver {
+	if opts.TTL <= 0 {
+		opts.TTL = 10 * time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Commitment == "" {
+		opts.Commitment = rpc.CommitmentConfirmed
+	}
+
+	return &Resolver{
+		rpcClient: rpcClient,
+		cache:     cache.New[solana.PublicKey, Account](cache.Config{Size: opts.CacheSize, TTL: opts.TTL, Shards: opts.CacheShards}),
+		opts:      opts,
+	}
+}
+
+// TokenAccount returns the owner and mint of the token account using the configured timeout
+func (r *Resolver) TokenAccount(account solana.PublicKey) (owner, mint solana.PublicKey, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+	defer cancel()
+
+	resolved, err := r.Resolve(ctx, account)
+	if err != nil {
+		return solana.PublicKey{}, solana.PublicKey{}, err
+	}
+	return resolved.Owner, resolved.Mint, nil
+}
+
+// Resolve returns the owner and mint of the token account. Concurrent lookups of the
+// same uncached account share a single RPC call.
+func (r *Resolver) Resolve(ctx context.Context, account solana.PublicKey) (Account, error) {
+	if resolved, ok := r.cache.Get(account); ok {
+		return resolved, nil
+	}
+
+	resolved, err, _ := r.inflight.Do(account.String(), func() (any, error) {
+		accounts, err := r.ResolveMany(ctx, []solana.PublicKey{account})
+		if err != nil {
+			return Account{}, err
+		}
+
+		resolved, ok := accounts[account]
+		if !ok {
+			return Account{}, fmt.Errorf("token account %s not found", account)
+		}
+		return resolved, nil
+	})
+	return resolved.(Account), err
+}
+
+// ResolveMany returns the owner and mint of every token account that exists, fetching
+// uncached accounts in batches. Closed accounts and accounts not owned by a token
+// program are left out of the result.
+func (r *Resolver) ResolveMany(ctx context.Context, accounts []solana.PublicKey) (map[solana.PublicKey]Account, error) {
+	resolved := make(map[solana.PublicKey]Account, len(accounts))
+	var missing []solana.PublicKey
+	for _, account := range accounts {
+		if _, ok := resolved[account]; ok {
+			continue
+		}
+		if cached, ok := r.cache.Get(account); ok {
+			resolved[account] = cached
+			continue
+		}
+		missing = append(missing, account)
+	}
+
+	for start := 0; start < len(missing); start += maxAccountsPerRequest {
+		end := min(start+maxAccountsPerRequest, len(missing))
+		result, err := r.rpcClient.GetMultipleAccountsWithOpts(ctx, missing[start:end], &rpc.GetMultipleAccountsOpts{
+			Commitment: r.opts.Commitment,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token accounts: %w", err)
+		}
+
+		for i, info := range result.Value {
+			if info == nil || info.Data == nil {
+				continue
+			}
+			if !info.Owner.Equals(solana.TokenProgramID) && !info.Owner.Equals(solana.Token2022ProgramID) {
+				continue
+			}
+			data := info.Data.GetBinary()
+			if len(data) < tokenAccountSize {
+				continue
+			}
+
+			account := Account{
+				Mint:  solana.PublicKeyFromBytes(data[tokenAccountMintOffset : tokenAccountMintOffset+32]),
+				Owner: solana.PublicKeyFromBytes(data[tokenAccountOwnerOffset : tokenAccountOwnerOffset+32]),
+			}
+			resolved[missing[start+i]] = account
+			r.cache.Put(missing[start+i], account)
+		}
+	}
+
+	return resolved, nil
+}
+
+// Add records a known token account, e.g. taken from transaction token balances
+func (r *Resolver) Add(account solana.PublicKey, owner, mint solana.PublicKey) {
+	r.cache.Put(account, Account{Owner: owner, Mint: mint})
+}
@@ -0,0 +1,118 @@
+package owners
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// newTokenAccountServer serves getMultipleAccounts with token accounts owned by the given program
+func newTokenAccountServer(t *testing.T, accounts map[solana.PublicKey]Account, program solana.PublicKey, calls *int32) *rpc.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		body, _ := io.ReadAll(r.Body)
+
+		var request struct {
+			ID     any   `json:"id"`
+			Params []any `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		var values []string
+		for _, key := range request.Params[0].([]any) {
+			account, ok := accounts[solana.MustPublicKeyFromBase58(key.(string))]
+			if !ok {
+				values = append(values, "null")
+				continue
+			}
+			data := make([]byte, tokenAccountSize)
+			copy(data[tokenAccountMintOffset:], account.Mint[:])
+			copy(data[tokenAccountOwnerOffset:], account.Owner[:])
+			values = append(values, fmt.Sprintf(`{"data":[%q,"base64"],"executable":false,"lamports":1,"owner":%q,"rentEpoch":0}`,
+				base64.StdEncoding.EncodeToString(data), program))
+		}
+
+		id, _ := json.Marshal(request.ID)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"context":{"slot":1},"value":[%s]}}`, id, strings.Join(values, ","))
+	}))
+	t.Cleanup(server.Close)
+
+	return rpc.New(server.URL)
+}
+
+func TestResolverFetchesAndCaches(t *testing.T) {
+	account, closed := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	want := Account{Owner: solana.NewWallet().PublicKey(), Mint: solana.NewWallet().PublicKey()}
+	var calls int32
+	resolver := NewResolver(newTokenAccountServer(t, map[solana.PublicKey]Account{account: want}, solana.Token2022ProgramID, &calls), Options{})
+
+	resolved, err := resolver.ResolveMany(context.Background(), []solana.PublicKey{account, closed, account})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if len(resolved) != 1 || resolved[account] != want {
+		t.Fatalf("unexpected accounts: %+v", resolved)
+	}
+
+	owner, mint, err := resolver.TokenAccount(account)
+	if err != nil || owner != want.Owner || mint != want.Mint {
+		t.Errorf("unexpected token account %s %s (%v)", owner, mint, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected cached accounts to skip the RPC, got %d calls", calls)
+	}
+
+	if _, _, err := resolver.TokenAccount(closed); err == nil {
+		t.Error("expected an error for a closed account")
+	}
+}
+
+func TestResolverIgnoresNonTokenAccounts(t *testing.T) {
+	account := solana.NewWallet().PublicKey()
+	var calls int32
+	accounts := map[solana.PublicKey]Account{account: {Owner: solana.NewWallet().PublicKey()}}
+	resolver := NewResolver(newTokenAccountServer(t, accounts, solana.SystemProgramID, &calls), Options{})
+
+	if _, _, err := resolver.TokenAccount(account); err == nil {
+		t.Error("expected accounts outside the token programs to be ignored")
+	}
+}
+
+func TestResolverSharesConcurrentLookups(t *testing.T) {
+	account := solana.NewWallet().PublicKey()
+	var calls int32
+	accounts := map[solana.PublicKey]Account{account: {Owner: solana.NewWallet().PublicKey()}}
+	resolver := NewResolver(newTokenAccountServer(t, accounts, solana.TokenProgramID, &calls), Options{})
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := resolver.TokenAccount(account); err != nil {
+				t.Errorf("failed to resolve: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("expected concurrent lookups to share one RPC call, got %d", calls)
+	}
+}
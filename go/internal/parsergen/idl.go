@@ -0,0 +1,181 @@
+package parsergen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// IDL is the subset of an Anchor IDL needed to scaffold a parser.
+// Both the legacy (< 0.30) and the current IDL layouts are accepted.
+type IDL struct {
+	Address      string           `json:"address"`
+	Name         string           `json:"name"`
+	Metadata     IDLMetadata      `json:"metadata"`
+	Instructions []IDLInstruction `json:"instructions"`
+	Events       []IDLEvent       `json:"events"`
+	Types        []IDLTypeDef     `json:"types"`
+	Errors       []IDLError       `json:"errors"`
+}
+
+// IDLMetadata holds the program name and address depending on IDL version
+type IDLMetadata struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// IDLInstruction describes a single program instruction
+type IDLInstruction struct {
+	Name          string       `json:"name"`
+	Discriminator []byte       `json:"discriminator"`
+	Accounts      []IDLAccount `json:"accounts"`
+	Args          []IDLField   `json:"args"`
+}
+
+// IDLAccount describes an account passed to an instruction
+type IDLAccount struct {
+	Name     string `json:"name"`
+	IsMut    bool   `json:"isMut"`
+	IsSigner bool   `json:"isSigner"`
+	Writable bool   `json:"writable"`
+	Signer   bool   `json:"signer"`
+}
+
+// IDLEvent describes an event emitted by the program
+type IDLEvent struct {
+	Name          string     `json:"name"`
+	Discriminator []byte     `json:"discriminator"`
+	Fields        []IDLField `json:"fields"`
+}
+
+// IDLField is a named, typed field of an instruction, event or struct
+type IDLField struct {
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+}
+
+// IDLTypeDef is a user defined type referenced from fields
+type IDLTypeDef struct {
+	Name string `json:"name"`
+	Type struct {
+		Kind     string       `json:"kind"`
+		Fields   []IDLField   `json:"fields"`
+		Variants []IDLVariant `json:"variants"`
+	} `json:"type"`
+}
+
+// IDLVariant is a single enum variant
+type IDLVariant struct {
+	Name   string          `json:"name"`
+	Fields json.RawMessage `json:"fields"`
+}
+
+// IDLError is a custom program error
+type IDLError struct {
+	Code uint32 `json:"code"`
+	Name string `json:"name"`
+	Msg  string `json:"msg"`
+}
+
+// UnmarshalJSON accepts both the byte array and the legacy numeric array discriminator forms
+func (d *IDLInstruction) UnmarshalJSON(data []byte) error {
+	type alias IDLInstruction
+	var raw struct {
+		alias
+		Discriminator []int `json:"discriminator"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*d = IDLInstruction(raw.alias)
+	d.Discriminator = intsToBytes(raw.Discriminator)
+	return nil
+}
+
+// UnmarshalJSON accepts both the byte array and the legacy numeric array discriminator forms
+func (e *IDLEvent) UnmarshalJSON(data []byte) error {
+	type alias IDLEvent
+	var raw struct {
+		alias
+		Discriminator []int `json:"discriminator"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = IDLEvent(raw.alias)
+	e.Discriminator = intsToBytes(raw.Discriminator)
+	return nil
+}
+
+// LoadIDL reads and decodes an Anchor IDL file
+func LoadIDL(path string) (*IDL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IDL: %w", err)
+	}
+
+	var idl IDL
+	if err := json.Unmarshal(data, &idl); err != nil {
+		return nil, fmt.Errorf("failed to decode IDL: %w", err)
+	}
+
+	return &idl, nil
+}
+
+// ProgramName returns the program name for either IDL layout
+func (idl *IDL) ProgramName() string {
+	if idl.Metadata.Name != "" {
+		return idl.Metadata.Name
+	}
+	return idl.Name
+}
+
+// ProgramAddress returns the program address for either IDL layout
+func (idl *IDL) ProgramAddress() string {
+	if idl.Address != "" {
+		return idl.Address
+	}
+	return idl.Metadata.Address
+}
+
+// typeDef looks up a user defined type by name
+func (idl *IDL) typeDef(name string) *IDLTypeDef {
+	for i := range idl.Types {
+		if idl.Types[i].Name == name {
+			return &idl.Types[i]
+		}
+	}
+	return nil
+}
+
+// eventFields returns the fields of an event, which newer IDLs keep in the types section
+func (idl *IDL) eventFields(event IDLEvent) []IDLField {
+	if len(event.Fields) > 0 {
+		return event.Fields
+	}
+	if def := idl.typeDef(event.Name); def != nil {
+		return def.Type.Fields
+	}
+	return nil
+}
+
+// IsWritable reports whether the account is writable in either IDL layout
+func (a IDLAccount) IsWritable() bool {
+	return a.IsMut || a.Writable
+}
+
+// IsSignerAccount reports whether the account signs in either IDL layout
+func (a IDLAccount) IsSignerAccount() bool {
+	return a.IsSigner || a.Signer
+}
+
+func intsToBytes(values []int) []byte {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]byte, len(values))
+	for i, v := range values {
+		out[i] = byte(v)
+	}
+	return out
+}
@@ -0,0 +1,306 @@
+package parsergen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Options controls the generated parser package
+type Options struct {
+	// ProgramID overrides the address found in the IDL
+	ProgramID string
+
+	// Name overrides the program name found in the IDL
+	Name string
+}
+
+type templateData struct {
+	ProgramName  string
+	Package      string
+	TypeName     string
+	ConstPrefix  string
+	ProgramID    string
+	Instructions []instructionData
+	Events       []eventData
+	Types        []string
+	Errors       []IDLError
+}
+
+type instructionData struct {
+	Name          string
+	Const         string
+	Discriminator string
+	Accounts      []string
+}
+
+type eventData struct {
+	Name          string
+	TypeName      string
+	Const         string
+	Discriminator string
+	Fields        []fieldData
+	Decodable     bool
+	SkipReason    string
+}
+
+type fieldData struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// Generate renders the parser package files for the IDL, keyed by file name
+func Generate(idl *IDL, opts Options) (map[string][]byte, error) {
+	data, err := buildTemplateData(idl, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := map[string]string{
+		data.Package + ".go":      parserTemplate,
+		"register.go":             registerTemplate,
+		data.Package + "_test.go": testTemplate,
+	}
+
+	files := make(map[string][]byte, len(templates))
+	for name, text := range templates {
+		source, err := render(name, text, data)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = source
+	}
+	files[filepath.Join("testdata", "README.md")] = []byte(fmt.Sprintf(
+		"Recorded `getTransaction` JSON fixtures for %s. Every `*.json` file here is run through the parser by `TestFixtures`.\n",
+		data.TypeName,
+	))
+
+	return files, nil
+}
+
+// WriteFiles writes generated files into dir, refusing to overwrite existing files
+func WriteFiles(dir string, files map[string][]byte) error {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("refusing to overwrite existing file %s", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// PackageName returns the Go package name that Generate uses for the IDL
+func PackageName(idl *IDL, opts Options) string {
+	name := opts.Name
+	if name == "" {
+		name = idl.ProgramName()
+	}
+	return packageName(name)
+}
+
+// InstructionDiscriminator computes the Anchor sighash of an instruction
+func InstructionDiscriminator(name string) [8]byte {
+	return sighash("global:" + snakeCase(name))
+}
+
+// EventDiscriminator computes the Anchor discriminator of an event
+func EventDiscriminator(name string) [8]byte {
+	return sighash("event:" + name)
+}
+
+// EventInstructionTag is the prefix Anchor uses for events emitted through self-CPI.
+// Anchor stores the sighash as a little-endian u64, so the bytes are reversed.
+func EventInstructionTag() [8]byte {
+	hash := sighash("anchor:event")
+	var tag [8]byte
+	for i := range hash {
+		tag[i] = hash[len(hash)-1-i]
+	}
+	return tag
+}
+
+func sighash(preimage string) [8]byte {
+	var out [8]byte
+	sum := sha256.Sum256([]byte(preimage))
+	copy(out[:], sum[:8])
+	return out
+}
+
+func buildTemplateData(idl *IDL, opts Options) (*templateData, error) {
+	name := opts.Name
+	if name == "" {
+		name = idl.ProgramName()
+	}
+	if name == "" {
+		return nil, fmt.Errorf("IDL has no program name, pass one explicitly")
+	}
+
+	programID := opts.ProgramID
+	if programID == "" {
+		programID = idl.ProgramAddress()
+	}
+	if _, err := solana.PublicKeyFromBase58(programID); err != nil {
+		return nil, fmt.Errorf("invalid program ID %q: %w", programID, err)
+	}
+
+	data := &templateData{
+		ProgramName: name,
+		Package:     packageName(name),
+		TypeName:    exportedName(name),
+		ConstPrefix: constantName(name),
+		ProgramID:   programID,
+		Errors:      idl.Errors,
+	}
+
+	for _, instr := range idl.Instructions {
+		discriminator := InstructionDiscriminator(instr.Name)
+		if len(instr.Discriminator) == 8 {
+			copy(discriminator[:], instr.Discriminator)
+		}
+
+		accounts := make([]string, len(instr.Accounts))
+		for i, account := range instr.Accounts {
+			var flags []string
+			if account.IsWritable() {
+				flags = append(flags, "WRITE")
+			}
+			if account.IsSignerAccount() {
+				flags = append(flags, "SIGNER")
+			}
+			accounts[i] = fmt.Sprintf("[%s] %s", strings.Join(flags, ", "), account.Name)
+		}
+
+		data.Instructions = append(data.Instructions, instructionData{
+			Name:          exportedName(instr.Name),
+			Const:         fmt.Sprintf("%s_%s_INSTRUCTION", data.ConstPrefix, constantName(instr.Name)),
+			Discriminator: formatBytes(discriminator[:]),
+			Accounts:      accounts,
+		})
+	}
+
+	tag := EventInstructionTag()
+	for _, event := range idl.Events {
+		discriminator := EventDiscriminator(event.Name)
+		if len(event.Discriminator) == 8 {
+			copy(discriminator[:], event.Discriminator)
+		}
+
+		eventData := eventData{
+			Name:          event.Name,
+			TypeName:      data.TypeName + exportedName(event.Name),
+			Const:         fmt.Sprintf("%s_%s_DISCRIMINATOR", data.ConstPrefix, constantName(event.Name)),
+			Discriminator: formatBytes(append(tag[:], discriminator[:]...)),
+			Decodable:     true,
+		}
+		for _, field := range idl.eventFields(event) {
+			goType, err := idl.goType(field.Type)
+			if err != nil {
+				eventData.Decodable = false
+				eventData.SkipReason = err.Error()
+				break
+			}
+			eventData.Fields = append(eventData.Fields, fieldData{Name: exportedName(field.Name), Type: goType, Tag: fieldTag(goType)})
+		}
+		data.Events = append(data.Events, eventData)
+	}
+
+	for _, def := range idl.Types {
+		if isEventType(idl, def.Name) {
+			continue
+		}
+		rendered, err := renderTypeDef(idl, def)
+		if err != nil {
+			rendered = fmt.Sprintf("// %s is not generated: %v", exportedName(def.Name), err)
+		}
+		data.Types = append(data.Types, rendered)
+	}
+
+	return data, nil
+}
+
+// renderTypeDef renders a user defined struct or fieldless enum as Go source
+func renderTypeDef(idl *IDL, def IDLTypeDef) (string, error) {
+	name := exportedName(def.Name)
+
+	switch def.Type.Kind {
+	case "struct":
+		var b strings.Builder
+		fmt.Fprintf(&b, "// %s is the %s type from the IDL\ntype %s struct {\n", name, def.Name, name)
+		for _, field := range def.Type.Fields {
+			goType, err := idl.goType(field.Type)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "\t%s %s %s\n", exportedName(field.Name), goType, fieldTag(goType))
+		}
+		b.WriteString("}")
+		return b.String(), nil
+	case "enum":
+		for _, variant := range def.Type.Variants {
+			if len(variant.Fields) > 0 && string(variant.Fields) != "null" {
+				return "", fmt.Errorf("enum variants with fields are not supported")
+			}
+		}
+		return fmt.Sprintf("// %s is the %s enum from the IDL\ntype %s uint8", name, def.Name, name), nil
+	}
+
+	return "", fmt.Errorf("unsupported type kind %q", def.Type.Kind)
+}
+
+func isEventType(idl *IDL, name string) bool {
+	for _, event := range idl.Events {
+		if event.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func render(name, text string, data *templateData) ([]byte, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", name, err)
+	}
+
+	return source, nil
+}
+
+// fieldTag marks borsh options so the decoder reads the presence byte
+func fieldTag(goType string) string {
+	if strings.HasPrefix(goType, "*") {
+		return "`bin:\"optional\"`"
+	}
+	return ""
+}
+
+func formatBytes(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%d", b)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,106 @@
+package parsergen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+func TestDiscriminators(t *testing.T) {
+	buy := InstructionDiscriminator("buy")
+	if buy != [8]byte{102, 6, 61, 18, 1, 218, 235, 234} {
+		t.Errorf("unexpected buy discriminator: %v", buy)
+	}
+
+	setParams := InstructionDiscriminator("setParams")
+	if setParams != [8]byte{27, 234, 178, 52, 147, 2, 187, 141} {
+		t.Errorf("unexpected setParams discriminator: %v", setParams)
+	}
+
+	tag := EventInstructionTag()
+	event := EventDiscriminator("TradeEvent")
+	full := append(tag[:], event[:]...)
+	if !bytes.Equal(full, tx_parser.PUMPFUN_TRADE_EVENT_DISCRIMINATOR[:]) {
+		t.Errorf("expected TradeEvent discriminator %v, got %v", tx_parser.PUMPFUN_TRADE_EVENT_DISCRIMINATOR, full)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	idl, err := LoadIDL(filepath.Join("testdata", "pump.json"))
+	if err != nil {
+		t.Fatalf("failed to load IDL: %v", err)
+	}
+
+	files, err := Generate(idl, Options{})
+	if err != nil {
+		t.Fatalf("failed to generate parser: %v", err)
+	}
+
+	for _, name := range []string{"pump.go", "register.go", "pump_test.go", filepath.Join("testdata", "README.md")} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("expected generated file %s", name)
+		}
+	}
+
+	fset := token.NewFileSet()
+	for name, content := range files {
+		if filepath.Ext(name) != ".go" {
+			continue
+		}
+		if _, err := parser.ParseFile(fset, name, content, parser.AllErrors); err != nil {
+			t.Errorf("generated file %s does not parse: %v", name, err)
+		}
+	}
+
+	source := string(files["pump.go"])
+	for _, want := range []string{
+		"PUMP_BUY_INSTRUCTION = [8]byte{102, 6, 61, 18, 1, 218, 235, 234}",
+		"PUMP_TRADE_EVENT_DISCRIMINATOR = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 189, 219, 127, 211, 78, 230, 97, 238}",
+		"6002: \"TooMuchSolRequired\"",
+		"type PumpTradeEvent struct",
+		"Curve       *Curve `bin:\"optional\"`",
+		"type Side uint8",
+		"MapEvent is not decoded",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("expected generated parser to contain %q", want)
+		}
+	}
+
+	if !strings.Contains(string(files["register.go"]), "tx_parser.RegisterParser(SwapTypePump") {
+		t.Error("expected registry wiring in register.go")
+	}
+}
+
+func TestGenerateRequiresProgramID(t *testing.T) {
+	idl := &IDL{Name: "nameless"}
+	if _, err := Generate(idl, Options{}); err == nil {
+		t.Error("expected error for missing program ID")
+	}
+}
+
+func TestNames(t *testing.T) {
+	cases := []struct {
+		in, pkg, exported, constant string
+	}{
+		{"pump", "pump", "Pump", "PUMP"},
+		{"raydium_launchpad", "raydium_launchpad", "RaydiumLaunchpad", "RAYDIUM_LAUNCHPAD"},
+		{"setParams", "set_params", "SetParams", "SET_PARAMS"},
+	}
+	for _, c := range cases {
+		if got := packageName(c.in); got != c.pkg {
+			t.Errorf("packageName(%q) = %q, want %q", c.in, got, c.pkg)
+		}
+		if got := exportedName(c.in); got != c.exported {
+			t.Errorf("exportedName(%q) = %q, want %q", c.in, got, c.exported)
+		}
+		if got := constantName(c.in); got != c.constant {
+			t.Errorf("constantName(%q) = %q, want %q", c.in, got, c.constant)
+		}
+	}
+}
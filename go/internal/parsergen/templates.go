@@ -0,0 +1,242 @@
+package parsergen
+
+const parserTemplate = `// Scaffolded by "toolkit gen parser" from the {{.ProgramName}} IDL.
+// Fill in the swap mapping for each event and keep the discriminator tables in sync with the IDL.
+
+package {{.Package}}
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// SwapType{{.TypeName}} identifies swaps produced by this parser
+const SwapType{{.TypeName}} tx_parser.SwapType = "{{.TypeName}}"
+
+var (
+	// Program ID
+	{{.ConstPrefix}}_PROGRAM_ID = solana.MustPublicKeyFromBase58("{{.ProgramID}}")
+)
+
+// Instruction discriminators
+var (
+{{- range .Instructions}}
+	// {{.Name}} accounts:
+{{- range $i, $a := .Accounts}}
+	//   [{{$i}}] {{$a}}
+{{- end}}
+	{{.Const}} = [8]byte{ {{.Discriminator}} }
+{{- end}}
+)
+
+// Event discriminators, prefixed with the Anchor event instruction tag
+var (
+{{- range .Events}}
+	{{.Const}} = [16]byte{ {{.Discriminator}} }
+{{- end}}
+)
+{{- if .Errors}}
+
+// {{.ConstPrefix}}_ERRORS maps custom program error codes to their IDL names
+var {{.ConstPrefix}}_ERRORS = map[uint32]string{
+{{- range .Errors}}
+	{{.Code}}: "{{.Name}}",
+{{- end}}
+}
+{{- end}}
+{{range .Types}}
+{{.}}
+{{end}}
+{{- range .Events}}
+{{- if .Decodable}}
+
+// {{.TypeName}} represents a decoded {{.Name}} event
+type {{.TypeName}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{.Tag}}
+{{- end}}
+}
+{{- else}}
+
+// {{.Name}} is not decoded: {{.SkipReason}}
+{{- end}}
+{{- end}}
+
+// {{.TypeName}}Parser handles parsing {{.TypeName}} protocol swaps
+type {{.TypeName}}Parser struct{}
+
+// New{{.TypeName}}Parser creates a new {{.TypeName}} parser instance
+func New{{.TypeName}}Parser() *{{.TypeName}}Parser {
+	return &{{.TypeName}}Parser{}
+}
+
+// CanHandle checks if this parser can handle the given instruction
+func (p *{{.TypeName}}Parser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
+	if int(instruction.ProgramIDIndex) >= len(accountKeys) {
+		return false
+	}
+	return accountKeys[instruction.ProgramIDIndex].Equals({{.ConstPrefix}}_PROGRAM_ID)
+}
+
+// ParseInstruction processes the {{.TypeName}} instruction and returns swap information
+func (p *{{.TypeName}}Parser) ParseInstruction(instruction solana.CompiledInstruction, instructionIndex int, ctx *tx_parser.TransactionContext) ([]*tx_parser.SwapInfo, error) {
+	if InstructionName(instruction.Data) == "" {
+		return nil, fmt.Errorf("unknown {{.TypeName}} instruction")
+	}
+
+	var swaps []*tx_parser.SwapInfo
+
+	// Process all events in inner instructions
+	for _, innerSet := range ctx.Meta.InnerInstructions {
+		if innerSet.Index != uint16(instructionIndex) {
+			continue
+		}
+		for _, innerInstr := range innerSet.Instructions {
+			swap, err := p.parseEvent(innerInstr.Data, ctx)
+			if err != nil || swap == nil {
+				continue
+			}
+			swaps = append(swaps, swap)
+		}
+	}
+
+	if len(swaps) == 0 {
+		return nil, fmt.Errorf("no valid {{.TypeName}} swaps found")
+	}
+
+	return swaps, nil
+}
+
+// InstructionName returns the IDL name of the instruction, or an empty string if unknown
+func InstructionName(data []byte) string {
+	if len(data) < 8 {
+		return ""
+	}
+	switch {
+{{- range .Instructions}}
+	case bytes.Equal(data[:8], {{.Const}}[:]):
+		return "{{.Name}}"
+{{- end}}
+	}
+	return ""
+}
+
+// parseEvent decodes a self-CPI event and maps it to a swap
+func (p *{{.TypeName}}Parser) parseEvent(data []byte, ctx *tx_parser.TransactionContext) (*tx_parser.SwapInfo, error) {
+	if len(data) < 16 {
+		return nil, fmt.Errorf("instruction data too short for an event")
+	}
+	decoder := ag_binary.NewBorshDecoder(data[16:])
+
+	switch {
+{{- range .Events}}
+{{- if .Decodable}}
+	case bytes.Equal(data[:16], {{.Const}}[:]):
+		var event {{.TypeName}}
+		if err := decoder.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode {{.Name}}: %w", err)
+		}
+		return p.build{{.TypeName}}Swap(&event, ctx)
+{{- end}}
+{{- end}}
+	}
+
+	_ = decoder
+	return nil, nil
+}
+{{- range .Events}}
+{{- if .Decodable}}
+
+// build{{.TypeName}}Swap maps a {{.Name}} event to a swap, returning nil if the event is not a trade
+func (p *{{$.TypeName}}Parser) build{{.TypeName}}Swap(event *{{.TypeName}}, ctx *tx_parser.TransactionContext) (*tx_parser.SwapInfo, error) {
+	// TODO: map the event fields onto TokenIn/TokenOut using ctx.GetMintDecimals
+	return nil, nil
+}
+{{- end}}
+{{- end}}
+`
+
+const registerTemplate = `// Scaffolded by "toolkit gen parser" from the {{.ProgramName}} IDL.
+
+package {{.Package}}
+
+import "github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+
+// Registers the parser so every tx_parser.Parser created after importing this package uses it
+func init() {
+	tx_parser.RegisterParser(SwapType{{.TypeName}}, func() tx_parser.SwapParser {
+		return New{{.TypeName}}Parser()
+	})
+}
+`
+
+const testTemplate = `// Scaffolded by "toolkit gen parser" from the {{.ProgramName}} IDL.
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+func TestInstructionDiscriminators(t *testing.T) {
+{{- range .Instructions}}
+	if name := InstructionName({{.Const}}[:]); name != "{{.Name}}" {
+		t.Errorf("expected {{.Name}}, got %q", name)
+	}
+{{- end}}
+}
+
+// TestFixtures runs the parser against transactions recorded as getTransaction JSON in testdata
+func TestFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Skip("no fixtures recorded in testdata")
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			data, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+
+			var txResult rpc.GetTransactionResult
+			if err := json.Unmarshal(data, &txResult); err != nil {
+				t.Fatalf("failed to decode fixture: %v", err)
+			}
+
+			parser, err := tx_parser.New(&txResult)
+			if err != nil {
+				t.Fatalf("failed to create parser: %v", err)
+			}
+
+			swaps, err := parser.ParseTransaction()
+			if err != nil {
+				t.Fatalf("failed to parse transaction: %v", err)
+			}
+
+			for _, swap := range swaps {
+				if swap.Protocol == SwapType{{.TypeName}} {
+					return
+				}
+			}
+			t.Errorf("expected a {{.TypeName}} swap in %s", fixture)
+		})
+	}
+}
+`
@@ -0,0 +1,147 @@
+package parsergen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// primitiveTypes maps IDL primitive type names to Go types
+var primitiveTypes = map[string]string{
+	"bool":      "bool",
+	"u8":        "uint8",
+	"i8":        "int8",
+	"u16":       "uint16",
+	"i16":       "int16",
+	"u32":       "uint32",
+	"i32":       "int32",
+	"u64":       "uint64",
+	"i64":       "int64",
+	"u128":      "ag_binary.Uint128",
+	"i128":      "ag_binary.Int128",
+	"f32":       "float32",
+	"f64":       "float64",
+	"string":    "string",
+	"bytes":     "[]byte",
+	"publicKey": "solana.PublicKey",
+	"pubkey":    "solana.PublicKey",
+}
+
+// goType resolves an IDL field type into a Go type expression
+func (idl *IDL) goType(raw json.RawMessage) (string, error) {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		goType, ok := primitiveTypes[name]
+		if !ok {
+			return "", fmt.Errorf("unsupported IDL type %q", name)
+		}
+		return goType, nil
+	}
+
+	var composite struct {
+		Vec     json.RawMessage `json:"vec"`
+		Option  json.RawMessage `json:"option"`
+		Array   json.RawMessage `json:"array"`
+		Defined json.RawMessage `json:"defined"`
+	}
+	if err := json.Unmarshal(raw, &composite); err != nil {
+		return "", fmt.Errorf("invalid IDL type %s", string(raw))
+	}
+
+	switch {
+	case composite.Vec != nil:
+		inner, err := idl.goType(composite.Vec)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + inner, nil
+	case composite.Option != nil:
+		inner, err := idl.goType(composite.Option)
+		if err != nil {
+			return "", err
+		}
+		return "*" + inner, nil
+	case composite.Array != nil:
+		var array []json.RawMessage
+		if err := json.Unmarshal(composite.Array, &array); err != nil || len(array) != 2 {
+			return "", fmt.Errorf("invalid IDL array type %s", string(composite.Array))
+		}
+		inner, err := idl.goType(array[0])
+		if err != nil {
+			return "", err
+		}
+		var size int
+		if err := json.Unmarshal(array[1], &size); err != nil {
+			return "", fmt.Errorf("unsupported IDL array length %s", string(array[1]))
+		}
+		return fmt.Sprintf("[%d]%s", size, inner), nil
+	case composite.Defined != nil:
+		defined := definedName(composite.Defined)
+		if idl.typeDef(defined) == nil {
+			return "", fmt.Errorf("unknown IDL type %q", defined)
+		}
+		return exportedName(defined), nil
+	}
+
+	return "", fmt.Errorf("unsupported IDL type %s", string(raw))
+}
+
+// definedName extracts the referenced type name from `"X"` or `{"name": "X"}`
+func definedName(raw json.RawMessage) string {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return name
+	}
+	var named struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(raw, &named)
+	return named.Name
+}
+
+// exportedName converts snake_case or camelCase identifiers into an exported Go name
+func exportedName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+// snakeCase converts camelCase identifiers into snake_case as Anchor does for sighashes
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && name[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// constantName converts an identifier into the SCREAMING_SNAKE_CASE used for program constants
+func constantName(name string) string {
+	return strings.ToUpper(snakeCase(name))
+}
+
+// packageName converts the program name into a valid Go package name
+func packageName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(snakeCase(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		case r == '-' || r == ' ':
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
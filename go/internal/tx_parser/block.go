@@ -0,0 +1,171 @@
+package tx_parser
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// BlockOptions controls how a block is parsed
+type BlockOptions struct {
+	ParseOptions
+
+	// Slot of the block, getBlock results do not include it
+	Slot uint64
+
+	// Workers is the number of transactions parsed concurrently, defaults to GOMAXPROCS
+	Workers int
+
+	// SkipFailed skips transactions that failed on chain
+	SkipFailed bool
+
+	// SkipVotes skips validator vote transactions
+	SkipVotes bool
+}
+
+// BlockResult holds the parsed transactions of a block
+type BlockResult struct {
+	Slot          uint64
+	ParentSlot    uint64
+	Blockhash     solana.Hash
+	BlockTime     *solana.UnixTimeSeconds
+	BlockHeight   *uint64
+	Transactions  map[solana.Signature]*ParsedTransaction
+	Order         []solana.Signature         // signatures of Transactions in block order
+	Results       []BlockTransaction         // parsed and failed transactions in block order, skipped ones left out
+	Bundles       []*Bundle                  // Jito bundles detected among Transactions
+	Errors        map[solana.Signature]error // transactions that could not be parsed at all
+	TotalFees     uint64
+	SwapCount     int
+	TransferCount int
+}
+
+// BlockTransaction is the outcome of a single transaction of a block
+type BlockTransaction struct {
+	Index       int              // position in the block
+	Signature   solana.Signature // zero if the transaction could not be decoded and the block has no signatures
+	Transaction *ParsedTransaction
+	Err         error
+}
+
+// ParseBlock parses every transaction in the block concurrently
+func ParseBlock(block *rpc.GetBlockResult) (*BlockResult, error) {
+	return ParseBlockWithOptions(block, BlockOptions{})
+}
+
+// ParseBlockWithOptions parses every transaction in the block concurrently with the given options
+func ParseBlockWithOptions(block *rpc.GetBlockResult, opts BlockOptions) (*BlockResult, error) {
+	if block == nil {
+		return nil, fmt.Errorf("block is empty")
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+
+	result := &BlockResult{
+		Slot:         opts.Slot,
+		ParentSlot:   block.ParentSlot,
+		Blockhash:    block.Blockhash,
+		BlockTime:    block.BlockTime,
+		BlockHeight:  block.BlockHeight,
+		Transactions: make(map[solana.Signature]*ParsedTransaction, len(block.Transactions)),
+		Errors:       make(map[solana.Signature]error),
+	}
+
+	type parsedResult struct {
+		signature solana.Signature
+		parsed    *ParsedTransaction
+		err       error
+	}
+
+	// Workers claim transactions with a shared counter and write their result in place,
+	// keeping block order without channels or per-transaction allocations
+	parsed := make([]parsedResult, len(block.Transactions))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(opts.Workers, max(len(block.Transactions), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(block.Transactions) {
+					return
+				}
+				r := &parsed[i]
+				r.signature, r.parsed, r.err = parseBlockTransaction(block, i, opts)
+			}
+		}()
+	}
+	wg.Wait()
+
+	result.Order = make([]solana.Signature, 0, len(block.Transactions))
+	result.Results = make([]BlockTransaction, 0, len(block.Transactions))
+	ordered := make([]*ParsedTransaction, 0, len(block.Transactions))
+	for i, r := range parsed {
+		if r.err != nil {
+			result.Errors[r.signature] = r.err
+			result.Results = append(result.Results, BlockTransaction{Index: i, Signature: r.signature, Err: r.err})
+			continue
+		}
+		if r.parsed == nil {
+			continue // skipped
+		}
+		result.Results = append(result.Results, BlockTransaction{Index: i, Signature: r.signature, Transaction: r.parsed})
+		result.Transactions[r.signature] = r.parsed
+		result.Order = append(result.Order, r.signature)
+		ordered = append(ordered, r.parsed)
+		result.TotalFees += r.parsed.Fee
+		result.SwapCount += len(r.parsed.Swaps)
+		result.TransferCount += len(r.parsed.Transfers)
+	}
+	result.Bundles = GroupBundles(ordered)
+
+	return result, nil
+}
+
+// parseBlockTransaction parses the i-th transaction of the block, returning nil results
+// for transactions filtered out by the options
+func parseBlockTransaction(block *rpc.GetBlockResult, i int, opts BlockOptions) (solana.Signature, *ParsedTransaction, error) {
+	ctx, err := NewTransactionContextFromBlock(opts.Slot, block.BlockTime, &block.Transactions[i])
+	if err != nil {
+		var signature solana.Signature
+		if i < len(block.Signatures) {
+			signature = block.Signatures[i]
+		}
+		return signature, nil, err
+	}
+
+	var signature solana.Signature
+	if len(ctx.Transaction.Signatures) > 0 {
+		signature = ctx.Transaction.Signatures[0]
+	}
+
+	if opts.SkipFailed && ctx.Meta.Err != nil {
+		return signature, nil, nil
+	}
+	if opts.SkipVotes && isVoteTransaction(ctx) {
+		return signature, nil, nil
+	}
+
+	parsed, err := NewFromContext(ctx, opts.ParseOptions).Parse()
+	return signature, parsed, err
+}
+
+// isVoteTransaction checks if every instruction of the transaction targets the vote program
+func isVoteTransaction(ctx *TransactionContext) bool {
+	instructions := ctx.Transaction.Message.Instructions
+	if len(instructions) == 0 {
+		return false
+	}
+	for _, instruction := range instructions {
+		if int(instruction.ProgramIDIndex) >= len(ctx.AccountKeys) || !ctx.AccountKeys[instruction.ProgramIDIndex].Equals(VOTE_PROGRAM_ID) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package tx_parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// newTestBlock encodes the transactions the way getBlock returns them with base64 encoding
+func newTestBlock(t testing.TB, txs []*solana.Transaction, metas []string) *rpc.GetBlockResult {
+	t.Helper()
+
+	entries := make([]string, len(txs))
+	for i, tx := range txs {
+		encoded, err := tx.ToBase64()
+		if err != nil {
+			t.Fatalf("failed to encode transaction: %v", err)
+		}
+		entries[i] = fmt.Sprintf(`{"transaction":[%q,"base64"],"meta":%s}`, encoded, metas[i])
+	}
+
+	raw := fmt.Sprintf(`{"parentSlot":99,"blockTime":1700000000,"blockhash":%q,"transactions":[%s]}`,
+		solana.Hash(solana.NewWallet().PublicKey()), strings.Join(entries, ","))
+	var block rpc.GetBlockResult
+	if err := json.Unmarshal([]byte(raw), &block); err != nil {
+		t.Fatalf("failed to decode block: %v", err)
+	}
+	return &block
+}
+
+func TestParseBlock(t *testing.T) {
+	keys := newTestKeys(3)
+	keys = append(keys, solana.SystemProgramID, VOTE_PROGRAM_ID)
+
+	var txs []*solana.Transaction
+	var metas []string
+	for i := range 5 {
+		tx := newTestTransaction(keys, 1, testInstruction(3, systemTransferData(uint64(i+1)), 0, 1))
+		tx.Signatures[0] = solana.SignatureFromBytes(append(make([]byte, 63), byte(i+1)))
+		txs = append(txs, tx)
+		metas = append(metas, `{"fee":5000,"preBalances":[],"postBalances":[]}`)
+	}
+
+	vote := newTestTransaction(keys, 1, testInstruction(4, []byte{2, 0, 0, 0}, 0, 2))
+	vote.Signatures[0] = solana.SignatureFromBytes(append(make([]byte, 63), 100))
+	txs = append(txs, vote)
+	metas = append(metas, `{"fee":5000,"preBalances":[],"postBalances":[]}`)
+
+	failed := newTestTransaction(keys, 1, testInstruction(3, systemTransferData(1), 0, 1))
+	failed.Signatures[0] = solana.SignatureFromBytes(append(make([]byte, 63), 101))
+	txs = append(txs, failed)
+	metas = append(metas, `{"err":{"InstructionError":[0,"Custom"]},"fee":5000,"preBalances":[],"postBalances":[]}`)
+
+	block := newTestBlock(t, txs, metas)
+
+	result, err := ParseBlockWithOptions(block, BlockOptions{Slot: 100, Workers: 3, SkipVotes: true, SkipFailed: true})
+	if err != nil {
+		t.Fatalf("failed to parse block: %v", err)
+	}
+	if len(result.Transactions) != 5 || len(result.Order) != 5 || len(result.Errors) != 0 {
+		t.Fatalf("expected 5 parsed transactions, got %d (%d errors)", len(result.Transactions), len(result.Errors))
+	}
+	if result.TotalFees != 25_000 || result.TransferCount != 5 {
+		t.Errorf("unexpected aggregates: fees=%d transfers=%d", result.TotalFees, result.TransferCount)
+	}
+
+	for i, signature := range result.Order {
+		if signature != txs[i].Signatures[0] || result.Results[i].Index != i || result.Results[i].Signature != signature {
+			t.Errorf("expected %s at position %d, got %s at %+v", txs[i].Signatures[0], i, signature, result.Results[i])
+		}
+	}
+
+	parsed := result.Transactions[txs[2].Signatures[0]]
+	if parsed == nil || parsed.Slot != 100 || len(parsed.Transfers) != 1 || parsed.Transfers[0].Amount != 3 {
+		t.Errorf("unexpected parsed transaction: %+v", parsed)
+	}
+
+	all, err := ParseBlock(block)
+	if err != nil {
+		t.Fatalf("failed to parse block: %v", err)
+	}
+	if len(all.Transactions) != 7 || all.TotalFees != 35_000 {
+		t.Errorf("expected all 7 transactions without filters, got %d", len(all.Transactions))
+	}
+}
+
+// newBenchmarkBlock builds a block of Raydium swaps shaped like mainnet traffic: a
+// compute budget instruction, a swap with two inner token transfers, a SOL tip and a
+// dozen token balances per transaction
+func newBenchmarkBlock(b *testing.B, size int) *rpc.GetBlockResult {
+	var txs []*solana.Transaction
+	var metas []string
+	for i := range size {
+		keys := newTestKeys(16)
+		user, pool, wsol, usdc := keys[0], keys[1], keys[6], keys[7]
+		keys = append(keys, RAYDIUM_V4_PROGRAM_ID, solana.TokenProgramID, solana.SystemProgramID, COMPUTE_BUDGET_PROGRAM_ID)
+		raydiumIndex, tokenIndex, systemIndex, computeIndex := uint16(16), uint16(17), uint16(18), uint16(19)
+
+		tx := newTestTransaction(keys, 1,
+			testInstruction(computeIndex, []byte{2, 64, 13, 3, 0}),
+			testInstruction(raydiumIndex, []byte{9}, 0, 1, 2, 3, 4, 5),
+			testInstruction(systemIndex, systemTransferData(10_000), 0, 8),
+		)
+		tx.Signatures[0] = solana.SignatureFromBytes(append(make([]byte, 60), byte(i>>24), byte(i>>16), byte(i>>8), byte(i)))
+		meta := rpc.TransactionMeta{
+			Fee:          5000,
+			PreBalances:  make([]uint64, len(keys)),
+			PostBalances: make([]uint64, len(keys)),
+			InnerInstructions: []rpc.InnerInstruction{{
+				Index: 1,
+				Instructions: []solana.CompiledInstruction{
+					testInstruction(tokenIndex, tokenTransferData(1_000_000_000), 2, 3, 0),
+					testInstruction(tokenIndex, tokenTransferData(150_000_000), 4, 5, 1),
+				},
+			}},
+		}
+		for _, balances := range []*[]rpc.TokenBalance{&meta.PreTokenBalances, &meta.PostTokenBalances} {
+			*balances = append(*balances,
+				testTokenBalance(2, user, wsol, 2_000_000_000, 9),
+				testTokenBalance(3, pool, wsol, 9_000_000_000, 9),
+				testTokenBalance(4, pool, usdc, 500_000_000, 6),
+				testTokenBalance(5, user, usdc, 0, 6),
+			)
+			for j := uint16(9); j < 11; j++ {
+				*balances = append(*balances, testTokenBalance(j, keys[j+4], keys[j+2], 1, 6))
+			}
+		}
+		encoded, err := json.Marshal(meta)
+		if err != nil {
+			b.Fatal(err)
+		}
+		txs = append(txs, tx)
+		metas = append(metas, string(encoded))
+	}
+	return newTestBlock(b, txs, metas)
+}
+
+func BenchmarkParseBlock(b *testing.B) {
+	block := newBenchmarkBlock(b, 1_000)
+	for _, workers := range slices.Compact([]int{1, runtime.GOMAXPROCS(0)}) {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := ParseBlockWithOptions(block, BlockOptions{Workers: workers}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.N*len(block.Transactions))/b.Elapsed().Seconds(), "tx/s")
+		})
+	}
+}
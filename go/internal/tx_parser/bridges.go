@@ -0,0 +1,322 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Wormhole token bridge instruction tags
+const (
+	wormholeCompleteNativeInstruction             = 2
+	wormholeCompleteWrappedInstruction            = 3
+	wormholeTransferWrappedInstruction            = 4
+	wormholeTransferNativeInstruction             = 5
+	wormholeCompleteNativeWithPayloadInstruction  = 9
+	wormholeCompleteWrappedWithPayloadInstruction = 10
+	wormholeTransferWrappedWithPayloadInstruction = 11
+	wormholeTransferNativeWithPayloadInstruction  = 12
+)
+
+// Bridge instruction discriminators
+var (
+	DEBRIDGE_CREATE_ORDER_INSTRUCTION            = [8]byte{141, 54, 37, 207, 237, 210, 250, 215}
+	DEBRIDGE_CREATE_ORDER_WITH_NONCE_INSTRUCTION = [8]byte{130, 131, 98, 190, 40, 206, 68, 50}
+	DEBRIDGE_FULFILL_ORDER_INSTRUCTION           = [8]byte{61, 214, 39, 248, 65, 212, 153, 36}
+	MAYAN_SWIFT_INIT_ORDER_INSTRUCTION           = [8]byte{32, 76, 41, 12, 39, 162, 132, 219}
+)
+
+// wormholeTransfer locates the accounts of a Wormhole token bridge instruction
+type wormholeTransfer struct {
+	deposit bool
+	payload bool // transfers with a payload carry no relayer fee
+	mint    int
+	owner   int // -1 when the owner is read from the token account
+}
+
+// wormholeTransfers are keyed by instruction tag. Transfers send from the token account
+// at 2, completions redeem into the token account at 5.
+var wormholeTransfers = map[byte]wormholeTransfer{
+	wormholeTransferNativeInstruction:             {deposit: true, mint: 3, owner: -1},
+	wormholeTransferWrappedInstruction:            {deposit: true, mint: 4, owner: 3},
+	wormholeTransferNativeWithPayloadInstruction:  {deposit: true, payload: true, mint: 3, owner: -1},
+	wormholeTransferWrappedWithPayloadInstruction: {deposit: true, payload: true, mint: 4, owner: 3},
+	wormholeCompleteNativeInstruction:             {mint: 8, owner: -1},
+	wormholeCompleteWrappedInstruction:            {mint: 7, owner: -1},
+	wormholeCompleteNativeWithPayloadInstruction:  {mint: 9, owner: -1},
+	wormholeCompleteWrappedWithPayloadInstruction: {mint: 8, owner: -1},
+}
+
+// wormholeChains names Wormhole chain IDs, which Mayan uses too
+var wormholeChains = map[uint16]BridgeChain{
+	1:  BridgeChainSolana,
+	2:  BridgeChainEthereum,
+	4:  BridgeChainBSC,
+	5:  BridgeChainPolygon,
+	6:  BridgeChainAvalanche,
+	10: BridgeChainFantom,
+	21: BridgeChainSui,
+	22: BridgeChainAptos,
+	23: BridgeChainArbitrum,
+	24: BridgeChainOptimism,
+	30: BridgeChainBase,
+}
+
+// debridgeChains names deBridge chain IDs, which are EVM chain IDs apart from Solana
+var debridgeChains = map[uint64]BridgeChain{
+	1:       BridgeChainEthereum,
+	10:      BridgeChainOptimism,
+	56:      BridgeChainBSC,
+	137:     BridgeChainPolygon,
+	250:     BridgeChainFantom,
+	8453:    BridgeChainBase,
+	42161:   BridgeChainArbitrum,
+	43114:   BridgeChainAvalanche,
+	7565164: BridgeChainSolana,
+}
+
+// DeBridgeOffer is one side of a deBridge DLN order. Amounts are 256-bit big endian.
+type DeBridgeOffer struct {
+	ChainID      [32]byte
+	TokenAddress []byte
+	Amount       [32]byte
+}
+
+// DeBridgeOrderCreation is the leading part of the deBridge DLN create_order arguments
+type DeBridgeOrderCreation struct {
+	GiveOriginalAmount uint64
+	Take               DeBridgeOffer
+	ReceiverDst        []byte
+}
+
+// DeBridgeOrder is the leading part of the order a deBridge DLN fulfill_order fills
+type DeBridgeOrder struct {
+	MakerOrderNonce uint64
+	MakerSrc        []byte
+	Give            DeBridgeOffer
+	Take            DeBridgeOffer
+	ReceiverDst     []byte
+}
+
+// MayanSwiftOrderParams is the leading part of the Mayan Swift init_order arguments
+type MayanSwiftOrderParams struct {
+	AmountInMin uint64
+	NativeInput bool
+	FeeSubmit   uint64
+	AddrDest    [32]byte
+	ChainDest   uint16
+}
+
+// ParseBridgeEvents returns every Wormhole, deBridge and Mayan cross-chain transfer in
+// the transaction
+func ParseBridgeEvents(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*BridgeEvent, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBridgeEvents(ctx), nil
+}
+
+// ParseBridgeEvents returns every Wormhole, deBridge and Mayan cross-chain transfer in
+// the parsed transaction
+func (p *Parser) ParseBridgeEvents() ([]*BridgeEvent, error) {
+	return parseBridgeEvents(p.ctx), nil
+}
+
+func parseBridgeEvents(ctx *TransactionContext) []*BridgeEvent {
+	var events []*BridgeEvent
+	accounts := ctx.tokenAccounts()
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) || len(instruction.Data) == 0 {
+			return
+		}
+
+		var event *BridgeEvent
+		programID := ctx.AccountKeys[instruction.ProgramIDIndex]
+		switch {
+		case programID.Equals(WORMHOLE_TOKEN_BRIDGE_PROGRAM_ID):
+			event = decodeWormholeTransfer(instruction, ctx, accounts)
+		case programID.Equals(DEBRIDGE_DLN_SOURCE_PROGRAM_ID), programID.Equals(DEBRIDGE_DLN_DESTINATION_PROGRAM_ID):
+			event = decodeDeBridgeOrder(instruction, ctx)
+		case programID.Equals(MAYAN_SWIFT_PROGRAM_ID):
+			event = decodeMayanSwiftOrder(instruction, ctx)
+		}
+		if event == nil {
+			return
+		}
+
+		event.Program = programID
+		event.InstructionIndex = instructionIndex
+		event.InnerIndex = innerIndex
+		event.Decimals = ctx.GetMintDecimals(event.Mint)
+		events = append(events, event)
+	})
+	return events
+}
+
+// decodeWormholeTransfer decodes token bridge transfers out of Solana from their arguments
+// and completions from the balance change of the receiving token account. The chain a
+// completion comes from is only in the posted VAA account, so it is left unset.
+func decodeWormholeTransfer(instruction solana.CompiledInstruction, ctx *TransactionContext, accounts map[solana.PublicKey]tokenAccountInfo) *BridgeEvent {
+	layout, ok := wormholeTransfers[instruction.Data[0]]
+	if !ok || len(instruction.Accounts) <= max(layout.mint, 5) {
+		return nil
+	}
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	event := &BridgeEvent{Protocol: BridgeWormhole, Mint: account(layout.mint)}
+
+	if !layout.deposit {
+		recipient := account(5)
+		change, ok := ctx.TokenBalanceChange(recipient)
+		if !ok || change <= 0 {
+			return nil
+		}
+		event.Direction = BridgeRedeem
+		event.DestinationChain = BridgeChainSolana
+		event.Amount = uint64(change)
+		if info, ok := ctx.lookupTokenAccount(accounts, recipient); ok {
+			event.Wallet = info.Owner
+		}
+		return event
+	}
+
+	// nonce u32, amount u64, relayer fee u64 unless a payload follows, target address, target chain
+	addressOffset := 21
+	if layout.payload {
+		addressOffset = 13
+	}
+	data := instruction.Data
+	if len(data) < addressOffset+34 {
+		return nil
+	}
+	event.Direction = BridgeDeposit
+	event.SourceChain = BridgeChainSolana
+	event.DestinationChain = wormholeChain(binary.LittleEndian.Uint16(data[addressOffset+32:]))
+	event.Amount = binary.LittleEndian.Uint64(data[5:13])
+	event.ForeignAddress = foreignAddress(data[addressOffset : addressOffset+32])
+
+	event.Wallet = account(0)
+	if layout.owner >= 0 {
+		event.Wallet = account(layout.owner)
+	} else if info, ok := ctx.lookupTokenAccount(accounts, account(2)); ok && !info.Owner.IsZero() {
+		event.Wallet = info.Owner
+	}
+	return event
+}
+
+// decodeDeBridgeOrder decodes DLN orders created on Solana and orders from other chains
+// filled on Solana, taking amounts from the order rather than the settled transfers
+func decodeDeBridgeOrder(instruction solana.CompiledInstruction, ctx *TransactionContext) *BridgeEvent {
+	data := instruction.Data
+	if len(data) < 8 {
+		return nil
+	}
+	decoder := ag_binary.NewBorshDecoder(data[8:])
+
+	switch [8]byte(data[:8]) {
+	case DEBRIDGE_CREATE_ORDER_INSTRUCTION, DEBRIDGE_CREATE_ORDER_WITH_NONCE_INSTRUCTION:
+		// maker, state, token mint, ...
+		var order DeBridgeOrderCreation
+		if len(instruction.Accounts) < 3 || decoder.Decode(&order) != nil {
+			return nil
+		}
+		return &BridgeEvent{
+			Protocol:         BridgeDeBridge,
+			Direction:        BridgeDeposit,
+			SourceChain:      BridgeChainSolana,
+			DestinationChain: debridgeChain(order.Take.ChainID),
+			Wallet:           instructionAccount(instruction, 0, ctx),
+			ForeignAddress:   foreignAddress(order.ReceiverDst),
+			Mint:             instructionAccount(instruction, 2, ctx),
+			Amount:           order.GiveOriginalAmount,
+		}
+
+	case DEBRIDGE_FULFILL_ORDER_INSTRUCTION:
+		var order DeBridgeOrder
+		if decoder.Decode(&order) != nil || len(order.ReceiverDst) != solana.PublicKeyLength || len(order.Take.TokenAddress) != solana.PublicKeyLength {
+			return nil
+		}
+		amount := new(big.Int).SetBytes(order.Take.Amount[:])
+		if !amount.IsUint64() {
+			return nil
+		}
+		mint := solana.PublicKeyFromBytes(order.Take.TokenAddress)
+		if mint.IsZero() {
+			mint = NATIVE_SOL_PROGRAM_ID
+		}
+		return &BridgeEvent{
+			Protocol:         BridgeDeBridge,
+			Direction:        BridgeRedeem,
+			SourceChain:      debridgeChain(order.Give.ChainID),
+			DestinationChain: BridgeChainSolana,
+			Wallet:           solana.PublicKeyFromBytes(order.ReceiverDst),
+			ForeignAddress:   foreignAddress(order.MakerSrc),
+			Mint:             mint,
+			Amount:           amount.Uint64(),
+		}
+	}
+	return nil
+}
+
+// decodeMayanSwiftOrder decodes a Mayan Swift order leaving Solana. The amount is what
+// the order's state account received, or the minimum input when it has no recorded balance.
+func decodeMayanSwiftOrder(instruction solana.CompiledInstruction, ctx *TransactionContext) *BridgeEvent {
+	data := instruction.Data
+	// trader, relayer, state, state token account, relayer fee account, input mint, ...
+	if len(data) < 8 || [8]byte(data[:8]) != MAYAN_SWIFT_INIT_ORDER_INSTRUCTION || len(instruction.Accounts) < 6 {
+		return nil
+	}
+	var params MayanSwiftOrderParams
+	if err := ag_binary.NewBorshDecoder(data[8:]).Decode(&params); err != nil {
+		return nil
+	}
+
+	amount := params.AmountInMin
+	if change, ok := ctx.TokenBalanceChange(instructionAccount(instruction, 3, ctx)); ok && change > 0 {
+		amount = uint64(change)
+	}
+	return &BridgeEvent{
+		Protocol:         BridgeMayan,
+		Direction:        BridgeDeposit,
+		SourceChain:      BridgeChainSolana,
+		DestinationChain: wormholeChain(params.ChainDest),
+		Wallet:           instructionAccount(instruction, 0, ctx),
+		ForeignAddress:   foreignAddress(params.AddrDest[:]),
+		Mint:             instructionAccount(instruction, 5, ctx),
+		Amount:           amount,
+	}
+}
+
+// wormholeChain names a Wormhole chain ID, e.g. wormhole:42 when it is not known
+func wormholeChain(id uint16) BridgeChain {
+	if chain, ok := wormholeChains[id]; ok {
+		return chain
+	}
+	return BridgeChain(fmt.Sprintf("wormhole:%d", id))
+}
+
+// debridgeChain names a 256-bit big endian deBridge chain ID
+func debridgeChain(id [32]byte) BridgeChain {
+	chainID := new(big.Int).SetBytes(id[:])
+	if chainID.IsUint64() {
+		if chain, ok := debridgeChains[chainID.Uint64()]; ok {
+			return chain
+		}
+	}
+	return BridgeChain("debridge:" + chainID.String())
+}
+
+// foreignAddress hex encodes an address on another chain, trimming EVM addresses padded
+// to 32 bytes back to 20
+func foreignAddress(address []byte) string {
+	if len(address) == 32 && [12]byte(address[:12]) == [12]byte{} {
+		address = address[12:]
+	}
+	return "0x" + hex.EncodeToString(address)
+}
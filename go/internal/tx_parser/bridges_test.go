@@ -0,0 +1,131 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// wormholeTransferNativeData encodes a TransferNative with a relayer fee
+func wormholeTransferNativeData(amount uint64, target [32]byte, chain uint16) []byte {
+	data := binary.LittleEndian.AppendUint32([]byte{wormholeTransferNativeInstruction}, 7)
+	data = binary.LittleEndian.AppendUint64(data, amount)
+	data = binary.LittleEndian.AppendUint64(data, 0)
+	data = append(data, target[:]...)
+	return binary.LittleEndian.AppendUint16(data, chain)
+}
+
+// debridgeChainID encodes a chain ID as a 256-bit big endian integer
+func debridgeChainID(id uint64) (chainID [32]byte) {
+	binary.BigEndian.PutUint64(chainID[24:], id)
+	return chainID
+}
+
+func TestParseBridgeEvents(t *testing.T) {
+	// 0 wallet, 1 wallet token account, 2 mint, 3 recipient token account, 4 wrapped mint,
+	// 5 mayan state token account, 6 filler
+	keys := newTestKeys(7)
+	wallet, mint, wrappedMint := keys[0], keys[2], keys[4]
+	recipient, maker := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	keys = append(keys, WORMHOLE_TOKEN_BRIDGE_PROGRAM_ID, DEBRIDGE_DLN_SOURCE_PROGRAM_ID, DEBRIDGE_DLN_DESTINATION_PROGRAM_ID, MAYAN_SWIFT_PROGRAM_ID)
+	wormholeIndex, sourceIndex, destinationIndex, mayanIndex := uint16(7), uint16(8), uint16(9), uint16(10)
+
+	var evmAddress [32]byte
+	copy(evmAddress[12:], []byte{0xde, 0xad, 0xbe, 0xef, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	const evmHex = "0xdeadbeef0102030405060708090a0b0c0d0e0f10"
+
+	create, err := ag_binary.MarshalBorsh(DeBridgeOrderCreation{
+		GiveOriginalAmount: 2_000_000,
+		Take:               DeBridgeOffer{ChainID: debridgeChainID(42161), TokenAddress: evmAddress[12:], Amount: debridgeChainID(1_990_000)},
+		ReceiverDst:        evmAddress[12:],
+	})
+	if err != nil {
+		t.Fatalf("failed to encode order creation: %v", err)
+	}
+	fulfill, err := ag_binary.MarshalBorsh(DeBridgeOrder{
+		MakerSrc:    evmAddress[12:],
+		Give:        DeBridgeOffer{ChainID: debridgeChainID(999), TokenAddress: evmAddress[12:], Amount: debridgeChainID(5_000_000)},
+		Take:        DeBridgeOffer{ChainID: debridgeChainID(7565164), TokenAddress: make([]byte, 32), Amount: debridgeChainID(3_000_000_000)},
+		ReceiverDst: recipient[:],
+	})
+	if err != nil {
+		t.Fatalf("failed to encode order: %v", err)
+	}
+	params, err := ag_binary.MarshalBorsh(MayanSwiftOrderParams{AmountInMin: 900, AddrDest: evmAddress, ChainDest: 30})
+	if err != nil {
+		t.Fatalf("failed to encode order params: %v", err)
+	}
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(wormholeIndex, wormholeTransferNativeData(1_000_000, evmAddress, 2), 0, 6, 1, 2, 6, 6),
+		testInstruction(wormholeIndex, []byte{wormholeCompleteWrappedInstruction}, 0, 6, 6, 6, 6, 3, 6, 4),
+		testInstruction(sourceIndex, append(DEBRIDGE_CREATE_ORDER_WITH_NONCE_INSTRUCTION[:], create...), 0, 6, 2),
+		testInstruction(destinationIndex, append(DEBRIDGE_FULFILL_ORDER_INSTRUCTION[:], fulfill...), 0, 6),
+		testInstruction(mayanIndex, append(MAYAN_SWIFT_INIT_ORDER_INSTRUCTION[:], params...), 0, 6, 6, 5, 6, 2),
+		// unknown token bridge instruction
+		testInstruction(wormholeIndex, []byte{1}, 0, 6, 6, 6, 6, 6),
+	)
+	meta := &rpc.TransactionMeta{
+		PreTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(1, wallet, mint, 10_000_000, 6),
+			testTokenBalance(3, recipient, wrappedMint, 0, 8),
+			testTokenBalance(5, maker, mint, 0, 6),
+		},
+		PostTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(1, wallet, mint, 9_000_000, 6),
+			testTokenBalance(3, recipient, wrappedMint, 250_000_000, 8),
+			testTokenBalance(5, maker, mint, 1_000, 6),
+		},
+	}
+
+	events, err := ParseBridgeEvents(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse bridge events: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+
+	deposit := events[0]
+	if deposit.Protocol != BridgeWormhole || deposit.Direction != BridgeDeposit || deposit.SourceChain != BridgeChainSolana || deposit.DestinationChain != BridgeChainEthereum {
+		t.Errorf("unexpected wormhole deposit: %+v", deposit)
+	}
+	if !deposit.Wallet.Equals(wallet) || !deposit.Mint.Equals(mint) || deposit.Amount != 1_000_000 || deposit.Decimals != 6 || deposit.ForeignAddress != evmHex {
+		t.Errorf("expected the deposit from the instruction arguments, got %+v", deposit)
+	}
+
+	redeem := events[1]
+	if redeem.Direction != BridgeRedeem || redeem.SourceChain != "" || redeem.DestinationChain != BridgeChainSolana || redeem.InstructionIndex != 1 {
+		t.Errorf("unexpected wormhole redemption: %+v", redeem)
+	}
+	if !redeem.Wallet.Equals(recipient) || !redeem.Mint.Equals(wrappedMint) || redeem.Amount != 250_000_000 || redeem.Decimals != 8 {
+		t.Errorf("expected the redemption from the recipient's balance, got %+v", redeem)
+	}
+
+	order := events[2]
+	if order.Protocol != BridgeDeBridge || order.Direction != BridgeDeposit || order.DestinationChain != BridgeChainArbitrum || order.Amount != 2_000_000 {
+		t.Errorf("unexpected deBridge order: %+v", order)
+	}
+	if !order.Wallet.Equals(wallet) || !order.Mint.Equals(mint) || order.ForeignAddress != evmHex {
+		t.Errorf("unexpected deBridge order accounts: %+v", order)
+	}
+
+	fill := events[3]
+	if fill.Direction != BridgeRedeem || fill.SourceChain != "debridge:999" || fill.DestinationChain != BridgeChainSolana {
+		t.Errorf("expected an unnamed source chain, got %+v", fill)
+	}
+	if !fill.Wallet.Equals(recipient) || !fill.Mint.Equals(NATIVE_SOL_PROGRAM_ID) || fill.Amount != 3_000_000_000 || fill.ForeignAddress != evmHex {
+		t.Errorf("expected the fill to pay native SOL to the receiver, got %+v", fill)
+	}
+
+	swift := events[4]
+	if swift.Protocol != BridgeMayan || swift.DestinationChain != BridgeChainBase || !swift.Wallet.Equals(wallet) || !swift.Mint.Equals(mint) {
+		t.Errorf("unexpected mayan order: %+v", swift)
+	}
+	if swift.Amount != 1_000 {
+		t.Errorf("expected the amount the order state received, got %d", swift.Amount)
+	}
+}
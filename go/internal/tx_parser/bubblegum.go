@@ -0,0 +1,214 @@
+package tx_parser
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Bubblegum instruction discriminators
+var (
+	BUBBLEGUM_MINT_V1_INSTRUCTION               = [8]byte{145, 98, 192, 118, 184, 147, 118, 104}
+	BUBBLEGUM_MINT_TO_COLLECTION_V1_INSTRUCTION = [8]byte{153, 18, 178, 47, 197, 158, 86, 15}
+	BUBBLEGUM_TRANSFER_INSTRUCTION              = [8]byte{163, 52, 200, 231, 140, 3, 69, 186}
+	BUBBLEGUM_BURN_INSTRUCTION                  = [8]byte{116, 110, 29, 56, 107, 219, 42, 93}
+)
+
+// bubblegumLeafSchemaEventLength is the size of a borsh encoded V1 LeafSchemaEvent
+const bubblegumLeafSchemaEventLength = 3 + 3*32 + 8 + 3*32
+
+// BubblegumLeafSchemaEvent is the leaf Bubblegum logs through the noop program after
+// writing it to the tree. The leaf ID is the asset ID of the compressed NFT.
+type BubblegumLeafSchemaEvent struct {
+	EventType   uint8 // 1 for LeafSchemaEvent
+	Version     uint8 // 0 for V1
+	Schema      uint8 // LeafSchema variant, 0 for V1
+	ID          solana.PublicKey
+	Owner       solana.PublicKey
+	Delegate    solana.PublicKey
+	Nonce       uint64
+	DataHash    [32]byte
+	CreatorHash [32]byte
+	LeafHash    [32]byte
+}
+
+// BubblegumMetadataArgs is the leading part of the metadata a compressed NFT is minted with
+type BubblegumMetadataArgs struct {
+	Name                 string
+	Symbol               string
+	URI                  string
+	SellerFeeBasisPoints uint16
+	PrimarySaleHappened  bool
+	IsMutable            bool
+	EditionNonce         *uint8               `bin:"optional"`
+	TokenStandard        *uint8               `bin:"optional"`
+	Collection           *BubblegumCollection `bin:"optional"`
+}
+
+// BubblegumCollection is the collection a compressed NFT's metadata claims
+type BubblegumCollection struct {
+	Verified bool
+	Key      solana.PublicKey
+}
+
+// ParseCompressedNftEvents returns every Bubblegum compressed NFT mint, transfer and burn
+// in the transaction
+func ParseCompressedNftEvents(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*CompressedNftEvent, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCompressedNftEvents(ctx), nil
+}
+
+// ParseCompressedNftEvents returns every Bubblegum compressed NFT mint, transfer and burn
+// in the parsed transaction
+func (p *Parser) ParseCompressedNftEvents() ([]*CompressedNftEvent, error) {
+	return parseCompressedNftEvents(p.ctx), nil
+}
+
+func parseCompressedNftEvents(ctx *TransactionContext) []*CompressedNftEvent {
+	var events []*CompressedNftEvent
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) || !ctx.AccountKeys[instruction.ProgramIDIndex].Equals(BUBBLEGUM_PROGRAM_ID) {
+			return
+		}
+
+		event := decodeBubblegum(instruction, ctx)
+		if event == nil {
+			return
+		}
+		if leaf := bubblegumLeaf(ctx, instructionIndex, innerIndex); leaf != nil {
+			event.AssetID = leaf.ID
+			event.LeafIndex = leaf.Nonce
+		}
+		if event.AssetID.IsZero() && event.Type != CompressedNftMint {
+			event.AssetID = bubblegumAssetID(event.Tree, event.LeafIndex)
+		}
+
+		event.InstructionIndex = instructionIndex
+		event.InnerIndex = innerIndex
+		events = append(events, event)
+	})
+
+	return events
+}
+
+// decodeBubblegum decodes the Bubblegum V1 instructions that create or move a leaf
+func decodeBubblegum(instruction solana.CompiledInstruction, ctx *TransactionContext) *CompressedNftEvent {
+	if len(instruction.Data) < 8 {
+		return nil
+	}
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+
+	var discriminator [8]byte
+	copy(discriminator[:], instruction.Data)
+	switch discriminator {
+	case BUBBLEGUM_MINT_V1_INSTRUCTION, BUBBLEGUM_MINT_TO_COLLECTION_V1_INSTRUCTION:
+		// tree authority, leaf owner, leaf delegate, merkle tree, payer, tree delegate, ...
+		if len(instruction.Accounts) < 6 {
+			return nil
+		}
+		event := &CompressedNftEvent{Type: CompressedNftMint, Tree: account(3), Owner: account(1), Delegate: account(2)}
+
+		var metadata BubblegumMetadataArgs
+		if err := ag_binary.NewBorshDecoder(instruction.Data[8:]).Decode(&metadata); err == nil {
+			event.Name, event.Symbol, event.URI = metadata.Name, metadata.Symbol, metadata.URI
+			if metadata.Collection != nil {
+				event.Collection = metadata.Collection.Key
+			}
+		}
+		// ..., collection authority, collection authority record, collection mint, ...
+		if discriminator == BUBBLEGUM_MINT_TO_COLLECTION_V1_INSTRUCTION && len(instruction.Accounts) >= 9 {
+			event.Collection = account(8)
+		}
+		return event
+
+	case BUBBLEGUM_TRANSFER_INSTRUCTION, BUBBLEGUM_BURN_INSTRUCTION:
+		// root, data hash and creator hash, then the nonce and index of the leaf
+		if len(instruction.Data) < 8+3*32+8+4 {
+			return nil
+		}
+		nonce := binary.LittleEndian.Uint64(instruction.Data[8+3*32:])
+
+		// transfer: tree authority, leaf owner, leaf delegate, new leaf owner, merkle tree, ...
+		if discriminator == BUBBLEGUM_TRANSFER_INSTRUCTION {
+			if len(instruction.Accounts) < 5 {
+				return nil
+			}
+			return &CompressedNftEvent{Type: CompressedNftTransfer, Tree: account(4), LeafIndex: nonce,
+				Owner: account(1), Delegate: account(2), NewOwner: account(3)}
+		}
+
+		// burn: tree authority, leaf owner, leaf delegate, merkle tree, ...
+		if len(instruction.Accounts) < 4 {
+			return nil
+		}
+		return &CompressedNftEvent{Type: CompressedNftBurn, Tree: account(3), LeafIndex: nonce,
+			Owner: account(1), Delegate: account(2)}
+	}
+	return nil
+}
+
+// bubblegumLeaf returns the leaf logged by the Bubblegum instruction at the given position.
+// The noop calls of an instruction follow it among the inner instructions, up to the
+// next Bubblegum instruction.
+func bubblegumLeaf(ctx *TransactionContext, instructionIndex, innerIndex int) *BubblegumLeafSchemaEvent {
+	for _, innerSet := range ctx.Meta.InnerInstructions {
+		if innerSet.Index != uint16(instructionIndex) {
+			continue
+		}
+		for j := innerIndex + 1; j < len(innerSet.Instructions); j++ {
+			instruction := innerSet.Instructions[j]
+			if !hasValidIndices(instruction, ctx.AccountKeys) {
+				continue
+			}
+			programID := ctx.AccountKeys[instruction.ProgramIDIndex]
+			if programID.Equals(BUBBLEGUM_PROGRAM_ID) {
+				return nil
+			}
+			if !programID.Equals(SPL_NOOP_PROGRAM_ID) {
+				continue
+			}
+			if leaf := decodeBubblegumLeaf(instruction.Data); leaf != nil {
+				return leaf
+			}
+		}
+	}
+	return nil
+}
+
+// decodeBubblegumLeaf decodes a LeafSchemaEvent, either logged directly or wrapped in
+// the ApplicationData event of the account compression program as newer Bubblegum
+// versions do. Change log events of the account compression program are ignored.
+func decodeBubblegumLeaf(data []byte) *BubblegumLeafSchemaEvent {
+	// ApplicationData variant, V1, then the length prefixed application data
+	if len(data) == 6+bubblegumLeafSchemaEventLength && bytes.HasPrefix(data, []byte{1, 0}) &&
+		binary.LittleEndian.Uint32(data[2:]) == bubblegumLeafSchemaEventLength {
+		data = data[6:]
+	}
+	if len(data) != bubblegumLeafSchemaEventLength || !bytes.HasPrefix(data, []byte{1, 0, 0}) {
+		return nil
+	}
+
+	var leaf BubblegumLeafSchemaEvent
+	if err := ag_binary.NewBorshDecoder(data).Decode(&leaf); err != nil {
+		return nil
+	}
+	return &leaf
+}
+
+// bubblegumAssetID derives the asset ID of the leaf with the given nonce in the tree
+func bubblegumAssetID(tree solana.PublicKey, nonce uint64) solana.PublicKey {
+	seed := make([]byte, 8)
+	binary.LittleEndian.PutUint64(seed, nonce)
+	assetID, _, err := solana.FindProgramAddress([][]byte{[]byte("asset"), tree[:], seed}, BUBBLEGUM_PROGRAM_ID)
+	if err != nil {
+		return solana.PublicKey{}
+	}
+	return assetID
+}
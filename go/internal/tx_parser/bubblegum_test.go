@@ -0,0 +1,97 @@
+package tx_parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// bubblegumLeafData borsh encodes the leaf event, wrapped in an ApplicationData event if asked
+func bubblegumLeafData(t *testing.T, leaf BubblegumLeafSchemaEvent, wrapped bool) []byte {
+	t.Helper()
+
+	data, err := ag_binary.MarshalBorsh(leaf)
+	if err != nil {
+		t.Fatalf("failed to encode leaf: %v", err)
+	}
+	if !wrapped {
+		return data
+	}
+	return append(binary.LittleEndian.AppendUint32([]byte{1, 0}, uint32(len(data))), data...)
+}
+
+// bubblegumLeafArgs encodes transfer or burn arguments for the leaf with the given nonce
+func bubblegumLeafArgs(discriminator [8]byte, nonce uint64) []byte {
+	data := append(discriminator[:], make([]byte, 3*32)...)
+	data = binary.LittleEndian.AppendUint64(data, nonce)
+	return binary.LittleEndian.AppendUint32(data, uint32(nonce))
+}
+
+func TestParseCompressedNftEvents(t *testing.T) {
+	// 0 owner, 1 new owner, 2 tree, 3 collection mint, 4 filler
+	keys := newTestKeys(5)
+	owner, newOwner, tree, collection := keys[0], keys[1], keys[2], keys[3]
+	keys = append(keys, BUBBLEGUM_PROGRAM_ID, SPL_NOOP_PROGRAM_ID)
+	bubblegumIndex, noopIndex := uint16(5), uint16(6)
+	assetID := bubblegumAssetID(tree, 7)
+
+	var metadata bytes.Buffer
+	encoder := ag_binary.NewBorshEncoder(&metadata)
+	if err := encoder.Encode(BubblegumMetadataArgs{Name: "Drop #8", Symbol: "DROP", URI: "https://example.com/8.json"}); err != nil {
+		t.Fatalf("failed to encode metadata: %v", err)
+	}
+	mintLeaf := BubblegumLeafSchemaEvent{EventType: 1, ID: assetID, Owner: owner, Delegate: owner, Nonce: 7}
+	transferLeaf := mintLeaf
+	transferLeaf.Owner, transferLeaf.Delegate = newOwner, newOwner
+
+	tx := newTestTransaction(keys, 1,
+		// mint_to_collection_v1 accounts up to the collection mint
+		testInstruction(bubblegumIndex, append(BUBBLEGUM_MINT_TO_COLLECTION_V1_INSTRUCTION[:], metadata.Bytes()...), 4, 0, 0, 2, 0, 0, 4, 4, 3),
+		testInstruction(bubblegumIndex, bubblegumLeafArgs(BUBBLEGUM_TRANSFER_INSTRUCTION, 7), 4, 0, 0, 1, 2),
+		testInstruction(bubblegumIndex, bubblegumLeafArgs(BUBBLEGUM_BURN_INSTRUCTION, 7), 4, 1, 1, 2),
+	)
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{
+			{Index: 0, Instructions: []solana.CompiledInstruction{
+				testInstruction(noopIndex, []byte{0, 0, 1, 2, 3}), // change log of the compression program
+				testInstruction(noopIndex, bubblegumLeafData(t, mintLeaf, true)),
+			}},
+			{Index: 1, Instructions: []solana.CompiledInstruction{
+				testInstruction(noopIndex, bubblegumLeafData(t, transferLeaf, false)),
+			}},
+		},
+	}
+
+	events, err := ParseCompressedNftEvents(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse compressed NFT events: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+
+	mint := events[0]
+	if mint.Type != CompressedNftMint || !mint.Tree.Equals(tree) || !mint.Owner.Equals(owner) || !mint.Collection.Equals(collection) {
+		t.Errorf("unexpected mint: %+v", mint)
+	}
+	if !mint.AssetID.Equals(assetID) || mint.LeafIndex != 7 || mint.Name != "Drop #8" || mint.URI != "https://example.com/8.json" {
+		t.Errorf("expected the mint to resolve its leaf and metadata, got %+v", mint)
+	}
+
+	transfer := events[1]
+	if transfer.Type != CompressedNftTransfer || !transfer.Owner.Equals(owner) || !transfer.NewOwner.Equals(newOwner) || !transfer.AssetID.Equals(assetID) {
+		t.Errorf("unexpected transfer: %+v", transfer)
+	}
+
+	burn := events[2]
+	if burn.Type != CompressedNftBurn || !burn.Owner.Equals(newOwner) || burn.LeafIndex != 7 || burn.InstructionIndex != 2 {
+		t.Errorf("unexpected burn: %+v", burn)
+	}
+	if !burn.AssetID.Equals(assetID) {
+		t.Errorf("expected the burn to derive asset %s from its nonce, got %s", assetID, burn.AssetID)
+	}
+}
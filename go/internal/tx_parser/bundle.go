@@ -0,0 +1,81 @@
+package tx_parser
+
+import (
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// JitoTip returns the lamports the transfers pay to Jito tip accounts
+func JitoTip(transfers []*TransferInfo) uint64 {
+	var tip uint64
+	for _, transfer := range transfers {
+		if transfer.Type == TransferTypeSOL && IsJitoTipAccount(transfer.Destination) {
+			tip += transfer.Amount
+		}
+	}
+	return tip
+}
+
+// GroupBundles detects Jito bundles among transactions in block order and sets their
+// Bundle info. Bundles land as consecutive transactions with the tip usually paid last,
+// so each tipping transaction is grouped with the transactions right before it that share
+// a signer or a traded pair with the bundle, up to JITO_MAX_BUNDLE_SIZE. Blocks do not
+// record bundle boundaries, so the grouping is a heuristic.
+func GroupBundles(txs []*ParsedTransaction) []*Bundle {
+	var bundles []*Bundle
+	start := 0 // transactions before start belong to an earlier bundle
+	for position, tx := range txs {
+		if tx == nil || tx.JitoTip == 0 {
+			continue
+		}
+
+		members := []*ParsedTransaction{tx}
+		for previous := position - 1; previous >= start && len(members) < JITO_MAX_BUNDLE_SIZE; previous-- {
+			candidate := txs[previous]
+			if candidate == nil || candidate.JitoTip > 0 || !linked(candidate, members) {
+				break
+			}
+			members = append(members, candidate)
+		}
+		slices.Reverse(members)
+		start = position + 1
+
+		bundle := &Bundle{ID: members[0].Signature, Slot: tx.Slot, Tipper: tx.FeePayer}
+		for _, member := range members {
+			bundle.Tip += member.JitoTip
+			bundle.Transactions = append(bundle.Transactions, member.Signature)
+		}
+		for i, member := range members {
+			member.Bundle = &BundleInfo{ID: bundle.ID, Position: i, Size: len(members), Tip: bundle.Tip}
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles
+}
+
+// linked reports whether the transaction shares a signer or a traded pair with any member
+func linked(tx *ParsedTransaction, members []*ParsedTransaction) bool {
+	for _, member := range members {
+		if !tx.FeePayer.IsZero() && tx.FeePayer.Equals(member.FeePayer) {
+			return true
+		}
+		for _, swap := range tx.Swaps {
+			for _, other := range member.Swaps {
+				if slices.ContainsFunc(swap.Signers, func(signer solana.PublicKey) bool { return slices.Contains(other.Signers, signer) }) {
+					return true
+				}
+				if samePair(swap, other) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// samePair reports whether two swaps trade the same tokens in either direction
+func samePair(a, b *SwapInfo) bool {
+	return (a.TokenIn.Mint.Equals(b.TokenIn.Mint) && a.TokenOut.Mint.Equals(b.TokenOut.Mint)) ||
+		(a.TokenIn.Mint.Equals(b.TokenOut.Mint) && a.TokenOut.Mint.Equals(b.TokenIn.Mint))
+}
@@ -0,0 +1,56 @@
+package tx_parser
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestJitoTip(t *testing.T) {
+	wallet := solana.NewWallet().PublicKey()
+	transfers := []*TransferInfo{
+		{Type: TransferTypeSOL, Source: wallet, Destination: JITO_TIP_ACCOUNTS[0], Amount: 10_000},
+		{Type: TransferTypeSOL, Source: wallet, Destination: JITO_TIP_ACCOUNTS[3], Amount: 5_000},
+		{Type: TransferTypeSOL, Source: wallet, Destination: solana.NewWallet().PublicKey(), Amount: 1_000_000},
+	}
+	if tip := JitoTip(transfers); tip != 15_000 {
+		t.Errorf("expected a tip of 15000 lamports, got %d", tip)
+	}
+}
+
+func TestGroupBundles(t *testing.T) {
+	attacker, victim, other := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	token := solana.NewWallet().PublicKey()
+	swap := func(signer, in, out solana.PublicKey) *SwapInfo {
+		return &SwapInfo{Signers: []solana.PublicKey{signer}, TokenIn: TokenInfo{Mint: in}, TokenOut: TokenInfo{Mint: out}}
+	}
+
+	txs := []*ParsedTransaction{
+		{Signature: solana.Signature{1}, FeePayer: other},
+		{Signature: solana.Signature{2}, FeePayer: attacker, Swaps: []*SwapInfo{swap(attacker, NATIVE_SOL_PROGRAM_ID, token)}},
+		{Signature: solana.Signature{3}, FeePayer: victim, Swaps: []*SwapInfo{swap(victim, NATIVE_SOL_PROGRAM_ID, token)}},
+		{Signature: solana.Signature{4}, FeePayer: attacker, Slot: 9, JitoTip: 50_000, Swaps: []*SwapInfo{swap(attacker, token, NATIVE_SOL_PROGRAM_ID)}},
+		{Signature: solana.Signature{5}, FeePayer: other, JitoTip: 1_000},
+	}
+
+	bundles := GroupBundles(txs)
+	if len(bundles) != 2 {
+		t.Fatalf("expected two bundles, got %d", len(bundles))
+	}
+
+	sandwich := bundles[0]
+	if sandwich.ID != txs[1].Signature || len(sandwich.Transactions) != 3 || sandwich.Tip != 50_000 || !sandwich.Tipper.Equals(attacker) || sandwich.Slot != 9 {
+		t.Errorf("unexpected bundle %+v", sandwich)
+	}
+	if txs[0].Bundle != nil {
+		t.Error("expected the unrelated transaction to stay outside the bundle")
+	}
+	info := txs[2].Bundle
+	if info == nil || info.ID != txs[1].Signature || info.Position != 1 || info.Size != 3 || info.Tip != 50_000 {
+		t.Errorf("unexpected bundle info %+v", info)
+	}
+
+	if single := bundles[1]; len(single.Transactions) != 1 || txs[4].Bundle.Position != 0 {
+		t.Errorf("expected a bundle of one transaction, got %+v", single)
+	}
+}
@@ -0,0 +1,95 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Compute budget program instruction indices
+const (
+	computeBudgetRequestHeapFrameInstruction               = 1
+	computeBudgetSetComputeUnitLimitInstruction            = 2
+	computeBudgetSetComputeUnitPriceInstruction            = 3
+	computeBudgetSetLoadedAccountsDataSizeLimitInstruction = 4
+)
+
+// Runtime compute unit defaults applied when no limit is requested
+const (
+	DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT = 200_000
+	MAX_COMPUTE_UNIT_LIMIT                 = 1_400_000
+)
+
+// ParseComputeBudget decodes the compute budget instructions of the transaction.
+// It returns nil if the transaction has no compute budget instructions.
+func ParseComputeBudget(tx *solana.Transaction, meta *rpc.TransactionMeta) (*ComputeBudget, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseComputeBudget(ctx), nil
+}
+
+// ParseComputeBudget decodes the compute budget instructions of the parsed transaction
+func (p *Parser) ParseComputeBudget() (*ComputeBudget, error) {
+	return parseComputeBudget(p.ctx), nil
+}
+
+// parseComputeBudget only looks at outer instructions, as the runtime ignores
+// compute budget instructions invoked through CPI
+func parseComputeBudget(ctx *TransactionContext) *ComputeBudget {
+	var budget *ComputeBudget
+	otherInstructions := 0
+
+	for _, instruction := range ctx.Transaction.Message.Instructions {
+		if int(instruction.ProgramIDIndex) >= len(ctx.AccountKeys) ||
+			!ctx.AccountKeys[instruction.ProgramIDIndex].Equals(COMPUTE_BUDGET_PROGRAM_ID) {
+			otherInstructions++
+			continue
+		}
+
+		data := instruction.Data
+		if len(data) < 1 {
+			continue
+		}
+		if budget == nil {
+			budget = &ComputeBudget{}
+		}
+
+		switch data[0] {
+		case computeBudgetRequestHeapFrameInstruction:
+			if len(data) >= 5 {
+				budget.HeapFrameBytes = binary.LittleEndian.Uint32(data[1:5])
+			}
+		case computeBudgetSetComputeUnitLimitInstruction:
+			if len(data) >= 5 {
+				budget.UnitLimit = binary.LittleEndian.Uint32(data[1:5])
+			}
+		case computeBudgetSetComputeUnitPriceInstruction:
+			if len(data) >= 9 {
+				budget.UnitPrice = binary.LittleEndian.Uint64(data[1:9])
+			}
+		case computeBudgetSetLoadedAccountsDataSizeLimitInstruction:
+			if len(data) >= 5 {
+				budget.LoadedAccountsLimit = binary.LittleEndian.Uint32(data[1:5])
+			}
+		}
+	}
+
+	if budget == nil {
+		return nil
+	}
+
+	budget.EffectiveUnitLimit = budget.UnitLimit
+	if budget.EffectiveUnitLimit == 0 {
+		budget.EffectiveUnitLimit = uint32(min(otherInstructions*DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT, MAX_COMPUTE_UNIT_LIMIT))
+	}
+	budget.EffectiveUnitLimit = min(budget.EffectiveUnitLimit, MAX_COMPUTE_UNIT_LIMIT)
+
+	// Priority fee is price (micro-lamports) times limit, rounded up to whole lamports
+	budget.PriorityFee = (budget.UnitPrice*uint64(budget.EffectiveUnitLimit) + 999_999) / 1_000_000
+
+	return budget
+}
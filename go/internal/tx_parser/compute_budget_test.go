@@ -0,0 +1,83 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParseComputeBudgetAndMemos(t *testing.T) {
+	keys := newTestKeys(3)
+	keys = append(keys, COMPUTE_BUDGET_PROGRAM_ID, MEMO_PROGRAM_ID, solana.SystemProgramID)
+	budgetIndex, memoIndex, systemIndex := uint16(3), uint16(4), uint16(5)
+
+	limit := make([]byte, 5)
+	limit[0] = computeBudgetSetComputeUnitLimitInstruction
+	binary.LittleEndian.PutUint32(limit[1:], 300_000)
+	price := make([]byte, 9)
+	price[0] = computeBudgetSetComputeUnitPriceInstruction
+	binary.LittleEndian.PutUint64(price[1:], 50_000)
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(budgetIndex, limit),
+		testInstruction(budgetIndex, price),
+		testInstruction(systemIndex, systemTransferData(1_000), 0, 1),
+		testInstruction(memoIndex, []byte("withdrawal 12345"), 0),
+	)
+	meta := &rpc.TransactionMeta{}
+
+	budget, err := ParseComputeBudget(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse compute budget: %v", err)
+	}
+	if budget == nil || budget.UnitLimit != 300_000 || budget.UnitPrice != 50_000 {
+		t.Fatalf("unexpected compute budget: %+v", budget)
+	}
+	if budget.PriorityFee != 15_000 {
+		t.Errorf("expected priority fee of 15000 lamports, got %d", budget.PriorityFee)
+	}
+
+	memos, err := ParseMemos(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse memos: %v", err)
+	}
+	if len(memos) != 1 || memos[0].Text != "withdrawal 12345" || memos[0].InstructionIndex != 3 {
+		t.Fatalf("unexpected memos: %+v", memos)
+	}
+	if len(memos[0].Signers) != 1 || !memos[0].Signers[0].Equals(keys[0]) {
+		t.Errorf("expected memo signer %s, got %v", keys[0], memos[0].Signers)
+	}
+}
+
+func TestParseComputeBudgetDefaultLimit(t *testing.T) {
+	keys := newTestKeys(2)
+	keys = append(keys, COMPUTE_BUDGET_PROGRAM_ID, solana.SystemProgramID)
+
+	price := make([]byte, 9)
+	price[0] = computeBudgetSetComputeUnitPriceInstruction
+	binary.LittleEndian.PutUint64(price[1:], 1_000_000)
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(2, price),
+		testInstruction(3, systemTransferData(1), 0, 1),
+		testInstruction(3, systemTransferData(1), 0, 1),
+	)
+
+	budget, err := ParseComputeBudget(tx, &rpc.TransactionMeta{})
+	if err != nil {
+		t.Fatalf("failed to parse compute budget: %v", err)
+	}
+	if budget.EffectiveUnitLimit != 2*DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT {
+		t.Errorf("expected default limit %d, got %d", 2*DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT, budget.EffectiveUnitLimit)
+	}
+	if budget.PriorityFee != 400_000 {
+		t.Errorf("expected priority fee of 400000 lamports, got %d", budget.PriorityFee)
+	}
+
+	none, err := ParseComputeBudget(newTestTransaction(keys, 1), &rpc.TransactionMeta{})
+	if err != nil || none != nil {
+		t.Errorf("expected no compute budget, got %+v (%v)", none, err)
+	}
+}
@@ -13,20 +13,92 @@ var (
 	RAYDIUM_AMM_PROGRAM_ID                    = solana.MustPublicKeyFromBase58("routeUGWgWzqBWFcrCfv8tritsqukccJPu3q5GPP3xS")
 	RAYDIUM_CPMM_PROGRAM_ID                   = solana.MustPublicKeyFromBase58("CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C")
 	RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID = solana.MustPublicKeyFromBase58("CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK")
+	RAYDIUM_LAUNCHLAB_PROGRAM_ID              = solana.MustPublicKeyFromBase58("LanMV9sAd7wArD4vJFi2qDdfnVhFxYSUg6eADduJ3uj")
 
-	METEORA_PROGRAM_ID       = solana.MustPublicKeyFromBase58("LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo")
-	METEORA_POOLS_PROGRAM_ID = solana.MustPublicKeyFromBase58("Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB")
+	METEORA_PROGRAM_ID               = solana.MustPublicKeyFromBase58("LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo")
+	METEORA_POOLS_PROGRAM_ID         = solana.MustPublicKeyFromBase58("Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB")
+	METEORA_DAMM_V2_PROGRAM_ID       = solana.MustPublicKeyFromBase58("cpamdpZCGKUy5JxQXB4dcpGPiikHawvSWAd6mEn1sGG")
+	METEORA_BONDING_CURVE_PROGRAM_ID = solana.MustPublicKeyFromBase58("dbcij3LWUppWqq96dh6gJWwBifmcGfLSB5D4DuSMaqN")
 
 	MOONSHOT_PROGRAM_ID = solana.MustPublicKeyFromBase58("MoonCVVNZFSYkqNXP6bxHLPL6QQJiMagDL3qcqUQTrG")
 
+	SABER_PROGRAM_ID     = solana.MustPublicKeyFromBase58("SSwpkEEcbUqx4vtoEByFjSkhKdCT862DNVb52nZg1UZ")
+	MERCURIAL_PROGRAM_ID = solana.MustPublicKeyFromBase58("MERLuDFBMmsHnsBPZw2sDQZHvXFMwp8EdjudcU2HKky")
+
+	INVARIANT_PROGRAM_ID = solana.MustPublicKeyFromBase58("HyaB3W9q6XdA5xwpU4XnSZV94htfmbmqJXZcEbRaJutt")
+	CREMA_PROGRAM_ID     = solana.MustPublicKeyFromBase58("CLMM9tUoggJu2wagPkkqs9eFG4BWhVBZWkP1qv3Sp7tR")
+	FLUXBEAM_PROGRAM_ID  = solana.MustPublicKeyFromBase58("FLUXubRmkEi2q6K3Y9kBPg9248ggaZVsoSFhtJHSrm1X")
+
+	GOOSEFX_GAMMA_PROGRAM_ID         = solana.MustPublicKeyFromBase58("GAMMA7meSFWaBXF25oSUgmGRwaW6sCMFLmBNiMSdbHVT")
+	STABBLE_STABLE_SWAP_PROGRAM_ID   = solana.MustPublicKeyFromBase58("swapNyd8XiQwJ6ianp9snpu4brUqFxadzvHebnAXjJZ")
+	STABBLE_WEIGHTED_SWAP_PROGRAM_ID = solana.MustPublicKeyFromBase58("swapFpHZwjELNnjvThjajtiVmkz3yPQEHjLtka2fwHW")
+
+	ZETA_PROGRAM_ID          = solana.MustPublicKeyFromBase58("ZETAxsqBRek56DhiGXrn75yj2NHU3aYUnxvHXpkf3aD")
+	JUPITER_PERPS_PROGRAM_ID = solana.MustPublicKeyFromBase58("PERPHjGBqRHArX4DySjwM6UJHiR3sWAatqfdBS2qQJu")
+
+	BUBBLEGUM_PROGRAM_ID = solana.MustPublicKeyFromBase58("BGUMAp9Gq7iTEuizy4pqaxsTyUCBK68MDfK752saRPUY")
+	SPL_NOOP_PROGRAM_ID  = solana.MustPublicKeyFromBase58("noopb9bkMVfRPU8AsbpTUg8AQkHtKwMYZiFUjNRtMmV")
+
+	CANDY_MACHINE_V3_PROGRAM_ID   = solana.MustPublicKeyFromBase58("CndyV3LdqHUfDLmE5naZjVN8rBZz4tqhdefbAnjHG3JR")
+	CORE_CANDY_MACHINE_PROGRAM_ID = solana.MustPublicKeyFromBase58("CMACYFENjoBMHzapRXyo1JZkVS6EtaDDzkjMrmQLvr4J")
+	MPL_CORE_PROGRAM_ID           = solana.MustPublicKeyFromBase58("CoREENxT6tW1HoK8ypY1SxRMZTcVPm7R94rH4PZNhX7d")
+
+	NAME_SERVICE_PROGRAM_ID  = solana.MustPublicKeyFromBase58("namesLPneVptA9Z5rqUDD9tMTWEJwofgaYwp8cawRkX")
+	SOL_TLD_AUTHORITY        = solana.MustPublicKeyFromBase58("58PwtjSDuFHuUkYjH9BYnnQKHfwo9reZhC2zMJv9JPkx")
+	SNS_REVERSE_LOOKUP_CLASS = solana.MustPublicKeyFromBase58("33m47vH6Eav6jJcHA9Xep9iXWjbqRJk6gZ4ssoWkbMGa")
+
+	WORMHOLE_TOKEN_BRIDGE_PROGRAM_ID    = solana.MustPublicKeyFromBase58("wormDTUJ6AWPNvk59vGQbDvGJmqbDTdgWgAqcLBCgUb")
+	DEBRIDGE_DLN_SOURCE_PROGRAM_ID      = solana.MustPublicKeyFromBase58("src5qyZHqTqecJV4aY6Cb6zDZLMDzrDKKezs22MPHr4")
+	DEBRIDGE_DLN_DESTINATION_PROGRAM_ID = solana.MustPublicKeyFromBase58("dst5MGcFPoBeREFAA5E3tU5ij8m5uVYwkzkSAbsLbNo")
+	MAYAN_SWIFT_PROGRAM_ID              = solana.MustPublicKeyFromBase58("BLZRi6frs4X4DNLw56V4EXai1b6QVESN1BhHBTYM9VcY")
+
+	HEAVEN_PROGRAM_ID = solana.MustPublicKeyFromBase58("HEAVENoP2qxoeuF8Dj2oT1GHEnu49U5mJYkdeC8BAX2o")
+	BOOP_PROGRAM_ID   = solana.MustPublicKeyFromBase58("boop8hVGQGqehUK2iVEMEnMrL5RbjywRzHKBmBE7ry4")
+
 	ORCA_PROGRAM_ID = solana.MustPublicKeyFromBase58("whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc")
 
 	OKX_PROGRAM_ID = solana.MustPublicKeyFromBase58("6m2CDdhRgxpH4WjvdzxAYbGxwdGUz5MziiL5jek2kBma")
 
+	STAKE_PROGRAM_ID          = solana.MustPublicKeyFromBase58("Stake11111111111111111111111111111111111111")
+	SPL_STAKE_POOL_PROGRAM_ID = solana.MustPublicKeyFromBase58("SPoo1Ku8WFXoNDMHPsrGSTSG1Y47rzgn41SLUNakuHy")
+	MARINADE_PROGRAM_ID       = solana.MustPublicKeyFromBase58("MarBmsSgKXdrN1egZf5sqe1TMai9K1rChYNDJgjq7aD")
+	JITO_STAKE_POOL           = solana.MustPublicKeyFromBase58("Jito4APyf642JPZPx3hGc6WWJ8zPKtRbRs4P815Awbb")
+	MARINADE_MSOL_MINT        = solana.MustPublicKeyFromBase58("mSoLzYCxHdYgdzU16g5QSh3i5K3z3KZK7ytfqcJm7So")
+
+	COMPUTE_BUDGET_PROGRAM_ID = solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+	MEMO_PROGRAM_ID           = solana.MustPublicKeyFromBase58("MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr")
+	VOTE_PROGRAM_ID           = solana.MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+	MEMO_V1_PROGRAM_ID        = solana.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
 	// Token Program IDs
 	NATIVE_SOL_PROGRAM_ID = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
 )
 
+// JITO_TIP_ACCOUNTS receive the SOL tips paid to Jito block engines
+var JITO_TIP_ACCOUNTS = []solana.PublicKey{
+	solana.MustPublicKeyFromBase58("96gYZGLnJYVFmbjzopPSU6QiEV5fGqZNyN9nmNhvrZU5"),
+	solana.MustPublicKeyFromBase58("HFqU5x63VTqvQss8hp11i4wVV8bD44PvwucfZ2bU7gRe"),
+	solana.MustPublicKeyFromBase58("Cw8CFyM9FkoMi7K7Crf6HNQqf4uEMzpKw6QNghXLvLkY"),
+	solana.MustPublicKeyFromBase58("ADaUMid9yfUytqMBgopwjb2DTLSokTSzL1zt6iGPaS49"),
+	solana.MustPublicKeyFromBase58("DfXygSm4jCyNCybVYYK6DwvWqjKee8pbDmJGcLWNDXjh"),
+	solana.MustPublicKeyFromBase58("ADuUkR4vqLUMWXxW9gh6D6L8pMSawimctcNZ5pGwDcEt"),
+	solana.MustPublicKeyFromBase58("DttWaMuVvTiduZRnguLF7jNxTgiMBZ1hyAumKUiL2KRL"),
+	solana.MustPublicKeyFromBase58("3AVi9Tg9Uo68tJfuvoKvqKNWKkC5wPdSSdeBnizKZ6jT"),
+}
+
+// JITO_MAX_BUNDLE_SIZE is the maximum number of transactions in a Jito bundle
+const JITO_MAX_BUNDLE_SIZE = 5
+
+// IsJitoTipAccount checks if the account is a Jito tip account
+func IsJitoTipAccount(account solana.PublicKey) bool {
+	for _, tip := range JITO_TIP_ACCOUNTS {
+		if tip.Equals(account) {
+			return true
+		}
+	}
+	return false
+}
+
 // Event Discriminators
 var (
 	JUPITER_ROUTE_EVENT_DISCRIMINATOR = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 64, 198, 205, 232, 38, 8, 113, 226}
@@ -47,7 +119,8 @@ func isRaydiumProgram(programID solana.PublicKey) bool {
 
 func isMeteoraProgramID(programID solana.PublicKey) bool {
 	return programID.Equals(METEORA_PROGRAM_ID) ||
-		programID.Equals(METEORA_POOLS_PROGRAM_ID)
+		programID.Equals(METEORA_POOLS_PROGRAM_ID) ||
+		programID.Equals(METEORA_DAMM_V2_PROGRAM_ID)
 }
 
 func abs(n int64) int64 {
@@ -56,3 +129,7 @@ func abs(n int64) int64 {
 	}
 	return n
 }
+
+// SCHEMA_VERSION is the version of the JSON encoding of parsed types. It is bumped when a
+// field is renamed, removed or changes meaning; adding fields does not bump it.
+const SCHEMA_VERSION = 1
@@ -0,0 +1,122 @@
+package tx_parser
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// NewTransactionContextFromRPC builds a parsing context from a getTransaction result.
+// It decodes legacy and versioned transactions, resolves address lookup table keys from
+// the loaded addresses, and fills in logs and the mint decimals map.
+func NewTransactionContextFromRPC(result *rpc.GetTransactionResult) (*TransactionContext, error) {
+	if result == nil || result.Transaction == nil {
+		return nil, fmt.Errorf("transaction result is empty")
+	}
+	if result.Meta == nil {
+		return nil, fmt.Errorf("transaction result has no metadata")
+	}
+
+	tx, err := result.Transaction.GetTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return newResolvedTransactionContext(tx, result.Meta, result.Slot, result.BlockTime)
+}
+
+// NewTransactionContextFromBlock builds a parsing context for a transaction of a getBlock result
+func NewTransactionContextFromBlock(slot uint64, blockTime *solana.UnixTimeSeconds, txWithMeta *rpc.TransactionWithMeta) (*TransactionContext, error) {
+	if txWithMeta == nil || txWithMeta.Transaction == nil {
+		return nil, fmt.Errorf("block transaction is empty")
+	}
+	if txWithMeta.Meta == nil {
+		return nil, fmt.Errorf("block transaction has no metadata")
+	}
+
+	tx, err := txWithMeta.GetTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return newResolvedTransactionContext(tx, txWithMeta.Meta, slot, blockTime)
+}
+
+// NewTransactionContextFromDecoded builds a parsing context for a transaction that was decoded
+// from another source, e.g. a Geyser update. Loaded addresses must be set in the metadata.
+func NewTransactionContextFromDecoded(slot uint64, blockTime *solana.UnixTimeSeconds, tx *solana.Transaction, meta *rpc.TransactionMeta) (*TransactionContext, error) {
+	if tx == nil || meta == nil {
+		return nil, fmt.Errorf("transaction and metadata are required")
+	}
+
+	return newResolvedTransactionContext(tx, meta, slot, blockTime)
+}
+
+// newResolvedTransactionContext checks that lookup table keys were loaded before building the context
+func newResolvedTransactionContext(tx *solana.Transaction, meta *rpc.TransactionMeta, slot uint64, blockTime *solana.UnixTimeSeconds) (*TransactionContext, error) {
+	// Versioned transactions reference lookup table accounts that only the metadata resolves
+	loaded := meta.LoadedAddresses
+	if lookups := tx.Message.NumLookups(); lookups != len(loaded.Writable)+len(loaded.ReadOnly) {
+		return nil, fmt.Errorf("transaction uses %d lookup table addresses but metadata loaded %d", lookups, len(loaded.Writable)+len(loaded.ReadOnly))
+	}
+
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Slot = slot
+	ctx.BlockTime = blockTime
+
+	return ctx, nil
+}
+
+// newTransactionContext builds a parsing context from a decoded transaction and its metadata
+func newTransactionContext(tx *solana.Transaction, meta *rpc.TransactionMeta) (*TransactionContext, error) {
+	if tx == nil || meta == nil {
+		return nil, fmt.Errorf("transaction and metadata are required")
+	}
+
+	// Combine all account keys, legacy transactions use the message keys as they are
+	allKeys := slices.Clip(tx.Message.AccountKeys)
+	if loaded := len(meta.LoadedAddresses.Writable) + len(meta.LoadedAddresses.ReadOnly); loaded > 0 {
+		allKeys = make([]solana.PublicKey, 0, len(tx.Message.AccountKeys)+loaded)
+		allKeys = append(allKeys, tx.Message.AccountKeys...)
+		allKeys = append(allKeys, meta.LoadedAddresses.Writable...)
+		allKeys = append(allKeys, meta.LoadedAddresses.ReadOnly...)
+	}
+
+	ctx := &TransactionContext{
+		Transaction: tx,
+		Meta:        meta,
+		AccountKeys: allKeys,
+		Logs:        meta.LogMessages,
+	}
+
+	if err := ctx.ExtractMintDecimals(); err != nil {
+		return nil, fmt.Errorf("failed to extract mint decimals: %w", err)
+	}
+
+	return ctx, nil
+}
+
+// hasValidIndices checks that the program and all account indices resolve to account keys
+func hasValidIndices(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
+	if int(instruction.ProgramIDIndex) >= len(accountKeys) {
+		return false
+	}
+	for _, idx := range instruction.Accounts {
+		if int(idx) >= len(accountKeys) {
+			return false
+		}
+	}
+	return true
+}
+
+// signers returns the signing accounts, which lead the message keys. The slice is shared
+// by every swap of the transaction and must not be modified.
+func (ctx *TransactionContext) signers() []solana.PublicKey {
+	keys := ctx.Transaction.Message.AccountKeys
+	return slices.Clip(keys[:min(int(ctx.Transaction.Message.Header.NumRequiredSignatures), len(keys))])
+}
@@ -0,0 +1,126 @@
+package tx_parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// newTestRPCResult encodes the transaction the way getTransaction returns it with base64 encoding
+func newTestRPCResult(t *testing.T, tx *solana.Transaction, meta string) *rpc.GetTransactionResult {
+	t.Helper()
+
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+
+	raw := fmt.Sprintf(`{"slot":42,"blockTime":1700000000,"transaction":[%q,"base64"],"meta":%s}`, encoded, meta)
+	var result rpc.GetTransactionResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		t.Fatalf("failed to decode transaction result: %v", err)
+	}
+	return &result
+}
+
+func TestNewTransactionContextFromRPC(t *testing.T) {
+	keys := newTestKeys(3)
+	keys = append(keys, solana.SystemProgramID)
+	tx := newTestTransaction(keys, 1, testInstruction(3, systemTransferData(1), 0, 1))
+
+	mint := solana.NewWallet().PublicKey()
+	meta := fmt.Sprintf(`{
+		"err": null,
+		"fee": 5000,
+		"preBalances": [10, 0, 0, 1],
+		"postBalances": [4, 1, 0, 1],
+		"logMessages": ["Program 11111111111111111111111111111111 invoke [1]"],
+		"preTokenBalances": [{"accountIndex": 2, "mint": %q, "uiTokenAmount": {"amount": "5", "decimals": 6}}],
+		"postTokenBalances": []
+	}`, mint)
+
+	ctx, err := NewTransactionContextFromRPC(newTestRPCResult(t, tx, meta))
+	if err != nil {
+		t.Fatalf("failed to build context: %v", err)
+	}
+	if ctx.Slot != 42 || ctx.BlockTime == nil || *ctx.BlockTime != 1700000000 {
+		t.Errorf("unexpected slot and block time: %d %v", ctx.Slot, ctx.BlockTime)
+	}
+	if len(ctx.AccountKeys) != 4 || len(ctx.Logs) != 1 {
+		t.Errorf("expected 4 keys and 1 log, got %d and %d", len(ctx.AccountKeys), len(ctx.Logs))
+	}
+	if ctx.GetMintDecimals(mint) != 6 {
+		t.Errorf("expected 6 decimals for %s, got %d", mint, ctx.GetMintDecimals(mint))
+	}
+}
+
+func TestNewTransactionContextFromRPCVersioned(t *testing.T) {
+	keys := newTestKeys(2)
+	keys = append(keys, solana.SystemProgramID)
+	tx := newTestTransaction(keys, 1, testInstruction(2, systemTransferData(1), 0, 3))
+	tx.Message.SetVersion(solana.MessageVersionV0)
+	tx.Message.AddAddressTableLookup(solana.MessageAddressTableLookup{
+		AccountKey:      solana.NewWallet().PublicKey(),
+		WritableIndexes: []uint8{0},
+		ReadonlyIndexes: []uint8{1},
+	})
+
+	writable, readonly := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	meta := fmt.Sprintf(`{"fee": 5000, "preBalances": [], "postBalances": [], "loadedAddresses": {"writable": [%q], "readonly": [%q]}}`, writable, readonly)
+
+	ctx, err := NewTransactionContextFromRPC(newTestRPCResult(t, tx, meta))
+	if err != nil {
+		t.Fatalf("failed to build context: %v", err)
+	}
+	if len(ctx.AccountKeys) != 5 || !ctx.AccountKeys[3].Equals(writable) || !ctx.AccountKeys[4].Equals(readonly) {
+		t.Errorf("expected loaded addresses after static keys, got %v", ctx.AccountKeys)
+	}
+
+	missing := `{"fee": 5000, "preBalances": [], "postBalances": []}`
+	if _, err := NewTransactionContextFromRPC(newTestRPCResult(t, tx, missing)); err == nil {
+		t.Error("expected an error when loaded addresses are missing")
+	}
+}
+
+func TestNewTransactionContextFromRPCEmpty(t *testing.T) {
+	if _, err := NewTransactionContextFromRPC(nil); err == nil {
+		t.Error("expected an error for a nil result")
+	}
+	if _, err := NewTransactionContextFromRPC(&rpc.GetTransactionResult{}); err == nil {
+		t.Error("expected an error for a result without a transaction")
+	}
+}
+
+// staticResolver resolves decimals from a fixed map
+type staticResolver map[solana.PublicKey]uint8
+
+func (r staticResolver) MintDecimals(mint solana.PublicKey) (uint8, error) {
+	decimals, ok := r[mint]
+	if !ok {
+		return 0, fmt.Errorf("unknown mint %s", mint)
+	}
+	return decimals, nil
+}
+
+func TestGetMintDecimalsResolver(t *testing.T) {
+	known, unknown := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	ctx := newTestContext(newTestTransaction(newTestKeys(1), 1), &rpc.TransactionMeta{})
+
+	if decimals := ctx.GetMintDecimals(known); decimals != 9 {
+		t.Errorf("expected the default of 9 decimals without a resolver, got %d", decimals)
+	}
+
+	ctx.DecimalsResolver = staticResolver{known: 2}
+	if decimals := ctx.GetMintDecimals(known); decimals != 2 {
+		t.Errorf("expected 2 resolved decimals, got %d", decimals)
+	}
+	if decimals := ctx.MintDecimals[known]; decimals != 2 {
+		t.Errorf("expected resolved decimals to be cached on the context, got %d", decimals)
+	}
+	if decimals := ctx.GetMintDecimals(unknown); decimals != 9 {
+		t.Errorf("expected the default of 9 decimals when resolution fails, got %d", decimals)
+	}
+}
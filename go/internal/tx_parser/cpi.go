@@ -0,0 +1,130 @@
+package tx_parser
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// instructionPosition locates an instruction, innerIndex is -1 for outer instructions
+type instructionPosition struct {
+	instructionIndex int
+	innerIndex       int
+}
+
+// CallTree returns the outer instructions of the transaction with the instructions each
+// invoked nested below them. Stack heights come from InnerStackHeights, then from the
+// invocations in the logs. Inner instructions without a known height, e.g. once the logs
+// are truncated, are placed directly under their outer instruction.
+func (ctx *TransactionContext) CallTree() []*InstructionNode {
+	if ctx.callers == nil {
+		ctx.buildCallTree()
+	}
+	return ctx.callTree
+}
+
+// Caller returns the instruction that invoked an inner instruction, or false for outer
+// instructions and positions outside the transaction
+func (ctx *TransactionContext) Caller(instructionIndex, innerIndex int) (*InstructionNode, bool) {
+	if ctx.callers == nil {
+		ctx.buildCallTree()
+	}
+	caller, ok := ctx.callers[instructionPosition{instructionIndex, innerIndex}]
+	return caller, ok
+}
+
+// ComputeUnits returns the compute units an instruction consumed, including the
+// instructions it invoked, or 0 if the logs do not report them
+func (ctx *TransactionContext) ComputeUnits(instructionIndex, innerIndex int) uint64 {
+	if ctx.callers == nil {
+		ctx.buildCallTree()
+	}
+	if node, ok := ctx.callNodes[instructionPosition{instructionIndex, innerIndex}]; ok {
+		return node.ComputeUnits
+	}
+	return 0
+}
+
+// ComputeUnitsByProgram sums the compute units each program consumed itself, excluding
+// the programs it invoked, over a call tree
+func ComputeUnitsByProgram(tree []*InstructionNode) map[solana.PublicKey]uint64 {
+	usage := make(map[solana.PublicKey]uint64)
+	var walk func(nodes []*InstructionNode)
+	walk = func(nodes []*InstructionNode) {
+		for _, node := range nodes {
+			own := node.ComputeUnits
+			for _, child := range node.Children {
+				own -= min(own, child.ComputeUnits)
+			}
+			if own > 0 {
+				usage[node.Program] += own
+			}
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+	return usage
+}
+
+// InvokedBy reports whether the program invoked an inner instruction, directly or
+// through other programs
+func (ctx *TransactionContext) InvokedBy(instructionIndex, innerIndex int, program solana.PublicKey) bool {
+	for {
+		caller, ok := ctx.Caller(instructionIndex, innerIndex)
+		if !ok {
+			return false
+		}
+		if caller.Program.Equals(program) {
+			return true
+		}
+		instructionIndex, innerIndex = caller.InstructionIndex, caller.InnerIndex
+	}
+}
+
+// buildCallTree nests the inner instructions of each outer instruction by stack height
+func (ctx *TransactionContext) buildCallTree() {
+	ctx.callTree = []*InstructionNode{}
+	ctx.callNodes = make(map[instructionPosition]*InstructionNode)
+	ctx.callers = make(map[instructionPosition]*InstructionNode)
+
+	logged := make(map[instructionPosition]*ProgramInvocation)
+	walkInvocations(ctx.ProgramLogs(), func(invocation *ProgramInvocation) {
+		logged[instructionPosition{invocation.InstructionIndex, invocation.InnerIndex}] = invocation
+	})
+
+	var stack []*InstructionNode
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		node := &InstructionNode{InstructionIndex: instructionIndex, InnerIndex: innerIndex, StackHeight: 1}
+		if int(instruction.ProgramIDIndex) < len(ctx.AccountKeys) {
+			node.Program = ctx.AccountKeys[instruction.ProgramIDIndex]
+		}
+		position := instructionPosition{instructionIndex, innerIndex}
+		ctx.callNodes[position] = node
+		invocation, ok := logged[position]
+		if ok && !invocation.Program.Equals(node.Program) {
+			invocation = nil
+		}
+		if invocation != nil {
+			node.ComputeUnits = invocation.ComputeUnits
+		}
+		if innerIndex < 0 {
+			ctx.callTree = append(ctx.callTree, node)
+			stack = append(stack[:0], node)
+			return
+		}
+
+		height := 0
+		if invocation != nil {
+			height = invocation.StackHeight
+		}
+		if heights := ctx.InnerStackHeights[uint16(instructionIndex)]; innerIndex < len(heights) && heights[innerIndex] > 1 {
+			height = heights[innerIndex]
+		}
+
+		// Unknown heights fall back to the outer instruction, impossible ones to the deepest caller
+		node.StackHeight = min(max(height, 2), len(stack)+1)
+		stack = stack[:node.StackHeight-1]
+		caller := stack[len(stack)-1]
+		caller.Children = append(caller.Children, node)
+		ctx.callers[position] = caller
+		stack = append(stack, node)
+	})
+}
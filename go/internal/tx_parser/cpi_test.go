@@ -0,0 +1,91 @@
+package tx_parser
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestCallTree(t *testing.T) {
+	// 0 user, 1 aggregator, 2 AMM, 3 token program, 4 precompile
+	keys := newTestKeys(5)
+	aggregator, amm, token := keys[1], keys[2], keys[3]
+	tx := newTestTransaction(keys, 1, testInstruction(4, nil), testInstruction(1, nil))
+	inner := []rpc.InnerInstruction{{Index: 1, Instructions: []solana.CompiledInstruction{
+		testInstruction(2, nil), testInstruction(3, nil), testInstruction(3, nil),
+	}}}
+	logs := []string{
+		"Program " + aggregator.String() + " invoke [1]",
+		"Program " + amm.String() + " invoke [2]",
+		"Program " + token.String() + " invoke [3]",
+		"Program " + token.String() + " success",
+		"Program " + amm.String() + " success",
+		"Program " + token.String() + " invoke [2]",
+		"Program " + token.String() + " success",
+		"Program " + aggregator.String() + " success",
+	}
+
+	ctx := newTestContext(tx, &rpc.TransactionMeta{InnerInstructions: inner, LogMessages: logs})
+	tree := ctx.CallTree()
+	if len(tree) != 2 || len(tree[0].Children) != 0 || len(tree[1].Children) != 2 {
+		t.Fatalf("expected the precompile without children and two calls from the aggregator, got %+v", tree)
+	}
+	swap := tree[1].Children[0]
+	if !swap.Program.Equals(amm) || len(swap.Children) != 1 || swap.Children[0].StackHeight != 3 || swap.Children[0].InnerIndex != 1 {
+		t.Errorf("expected the AMM to invoke the token program, got %+v", swap)
+	}
+	if caller, ok := ctx.Caller(1, 1); !ok || caller != swap {
+		t.Errorf("expected the AMM as caller of the swap transfer, got %+v", caller)
+	}
+	if !ctx.InvokedBy(1, 1, aggregator) || ctx.InvokedBy(1, 2, amm) {
+		t.Error("expected the aggregator, and not the AMM, to invoke the fee transfer")
+	}
+	if _, ok := ctx.Caller(1, -1); ok {
+		t.Error("expected outer instructions to have no caller")
+	}
+
+	// Once the logs are truncated inner instructions fall back to their outer instruction
+	truncated := append(logs[:2:2], "Log truncated")
+	ctx = newTestContext(tx, &rpc.TransactionMeta{InnerInstructions: inner, LogMessages: truncated})
+	if caller, ok := ctx.Caller(1, 1); !ok || caller != ctx.CallTree()[1] {
+		t.Errorf("expected the aggregator as fallback caller, got %+v", caller)
+	}
+
+	// Reported stack heights take precedence over the logs
+	ctx = newTestContext(tx, &rpc.TransactionMeta{InnerInstructions: inner})
+	ctx.InnerStackHeights = map[uint16][]int{1: {2, 3, 3}}
+	if caller, ok := ctx.Caller(1, 2); !ok || !caller.Program.Equals(amm) {
+		t.Errorf("expected the AMM as caller at height 3, got %+v", caller)
+	}
+}
+
+func TestComputeUnits(t *testing.T) {
+	keys := newTestKeys(4)
+	aggregator, amm, token := keys[1], keys[2], keys[3]
+	tx := newTestTransaction(keys, 1, testInstruction(1, nil))
+	inner := []rpc.InnerInstruction{{Index: 0, Instructions: []solana.CompiledInstruction{testInstruction(2, nil), testInstruction(3, nil)}}}
+	logs := []string{
+		"Program " + aggregator.String() + " invoke [1]",
+		"Program " + amm.String() + " invoke [2]",
+		"Program " + token.String() + " invoke [3]",
+		"Program " + token.String() + " consumed 4645 of 170000 compute units",
+		"Program " + token.String() + " success",
+		"Program " + amm.String() + " consumed 30000 of 190000 compute units",
+		"Program " + amm.String() + " success",
+		"Program " + aggregator.String() + " consumed 42000 of 200000 compute units",
+		"Program " + aggregator.String() + " success",
+	}
+
+	ctx := newTestContext(tx, &rpc.TransactionMeta{InnerInstructions: inner, LogMessages: logs})
+	if got := ctx.ComputeUnits(0, -1); got != 42_000 {
+		t.Errorf("expected the outer instruction to include its invocations, got %d", got)
+	}
+	if got := ctx.ComputeUnits(0, 1); got != 4645 {
+		t.Errorf("expected the token transfer's units, got %d", got)
+	}
+	usage := ComputeUnitsByProgram(ctx.CallTree())
+	if usage[aggregator] != 12_000 || usage[amm] != 25_355 || usage[token] != 4645 {
+		t.Errorf("expected the units each program consumed itself, got %v", usage)
+	}
+}
@@ -0,0 +1,128 @@
+package tx_parser
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Name service instruction tags
+const (
+	nameServiceCreateInstruction   = 0
+	nameServiceUpdateInstruction   = 1
+	nameServiceTransferInstruction = 2
+)
+
+// SNS_HASH_PREFIX is prepended to names before hashing them into name account seeds
+const SNS_HASH_PREFIX = "SPL Name Service"
+
+// HashDomainName hashes a name, e.g. the bonfida of bonfida.sol, into its name account seed
+func HashDomainName(name string) []byte {
+	hashed := sha256.Sum256([]byte(SNS_HASH_PREFIX + name))
+	return hashed[:]
+}
+
+// NameAccountKey derives the name account of a hashed name, with zero keys for a
+// missing class or parent
+func NameAccountKey(hashedName []byte, class, parent solana.PublicKey) (solana.PublicKey, error) {
+	key, _, err := solana.FindProgramAddress([][]byte{hashedName, class[:], parent[:]}, NAME_SERVICE_PROGRAM_ID)
+	return key, err
+}
+
+// ReverseLookupKey derives the reverse lookup account holding the name of a name account
+func ReverseLookupKey(account solana.PublicKey) (solana.PublicKey, error) {
+	return NameAccountKey(HashDomainName(account.String()), SNS_REVERSE_LOOKUP_CLASS, solana.PublicKey{})
+}
+
+// ParseDomainEvents returns every Solana Name Service domain registration and transfer
+// in the transaction
+func ParseDomainEvents(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*DomainEvent, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDomainEvents(ctx), nil
+}
+
+// ParseDomainEvents returns every Solana Name Service domain registration and transfer
+// in the parsed transaction
+func (p *Parser) ParseDomainEvents() ([]*DomainEvent, error) {
+	return parseDomainEvents(p.ctx), nil
+}
+
+// parseDomainEvents decodes the name service instructions, then names each domain from
+// the reverse lookup account the transaction writes for it, as registrations do.
+// Names of top level domains get the .sol suffix.
+func parseDomainEvents(ctx *TransactionContext) []*DomainEvent {
+	var events []*DomainEvent
+	reverseNames := make(map[solana.PublicKey]string)
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) || !ctx.AccountKeys[instruction.ProgramIDIndex].Equals(NAME_SERVICE_PROGRAM_ID) {
+			return
+		}
+		if len(instruction.Data) == 0 {
+			return
+		}
+		account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+
+		var event *DomainEvent
+		switch instruction.Data[0] {
+		case nameServiceCreateInstruction:
+			// system program, payer, name account, owner, class, parent, parent owner
+			if len(instruction.Accounts) < 6 || !account(4).IsZero() || account(5).IsZero() {
+				return
+			}
+			event = &DomainEvent{Type: DomainRegister, Domain: account(2), Owner: account(3), Parent: account(5)}
+
+		case nameServiceUpdateInstruction:
+			// name account, update signer, ...; written at offset 0 with a borsh string
+			if len(instruction.Accounts) < 1 || len(instruction.Data) < 9 || binary.LittleEndian.Uint32(instruction.Data[1:5]) != 0 {
+				return
+			}
+			var name string
+			if err := ag_binary.NewBorshDecoder(instruction.Data[9:]).Decode(&name); err == nil {
+				reverseNames[account(0)] = name
+			}
+			return
+
+		case nameServiceTransferInstruction:
+			// name account, owner, ...
+			if len(instruction.Accounts) < 2 || len(instruction.Data) < 33 {
+				return
+			}
+			event = &DomainEvent{Type: DomainTransfer, Domain: account(0), Owner: account(1),
+				NewOwner: solana.PublicKeyFromBytes(instruction.Data[1:33])}
+
+		default:
+			return
+		}
+
+		event.InstructionIndex = instructionIndex
+		event.InnerIndex = innerIndex
+		events = append(events, event)
+	})
+
+	if len(reverseNames) == 0 {
+		return events
+	}
+	for _, event := range events {
+		reverseKey, err := ReverseLookupKey(event.Domain)
+		if err != nil {
+			continue
+		}
+		name, ok := reverseNames[reverseKey]
+		if !ok {
+			continue
+		}
+		event.Name = strings.TrimPrefix(name, "\x00")
+		if topLevel, err := NameAccountKey(HashDomainName(event.Name), solana.PublicKey{}, SOL_TLD_AUTHORITY); err == nil && topLevel.Equals(event.Domain) {
+			event.Name += ".sol"
+		}
+	}
+	return events
+}
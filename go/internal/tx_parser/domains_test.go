@@ -0,0 +1,68 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// nameServiceUpdateData encodes an Update writing a borsh string at offset 0
+func nameServiceUpdateData(name string) []byte {
+	value := binary.LittleEndian.AppendUint32(nil, uint32(len(name)))
+	value = append(value, name...)
+
+	data := binary.LittleEndian.AppendUint32([]byte{nameServiceUpdateInstruction}, 0)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(value)))
+	return append(data, value...)
+}
+
+func TestParseDomainEvents(t *testing.T) {
+	domain, err := NameAccountKey(HashDomainName("bonfida"), solana.PublicKey{}, SOL_TLD_AUTHORITY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reverse, err := ReverseLookupKey(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0 owner, 1 new owner, 2 domain, 3 reverse lookup, 4 TLD, 5 reverse class, 6 system program, 7 name service
+	keys := append(newTestKeys(2), domain, reverse, SOL_TLD_AUTHORITY, SNS_REVERSE_LOOKUP_CLASS, solana.SystemProgramID, NAME_SERVICE_PROGRAM_ID)
+	owner, newOwner := keys[0], keys[1]
+	nameServiceIndex := uint16(7)
+
+	create := append([]byte{nameServiceCreateInstruction}, make([]byte, 4+8+4)...)
+	tx := newTestTransaction(keys, 1,
+		testInstruction(nameServiceIndex, create, 6, 0, 2, 0, 6, 4, 4),
+		// the reverse lookup has a class, so it is not a domain
+		testInstruction(nameServiceIndex, create, 6, 0, 3, 5, 5, 6),
+		testInstruction(nameServiceIndex, nameServiceUpdateData("bonfida"), 3, 5),
+		testInstruction(nameServiceIndex, append([]byte{nameServiceTransferInstruction}, newOwner[:]...), 2, 0),
+	)
+
+	events, err := ParseDomainEvents(tx, &rpc.TransactionMeta{})
+	if err != nil {
+		t.Fatalf("failed to parse domain events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	registered := events[0]
+	if registered.Type != DomainRegister || !registered.Domain.Equals(domain) || !registered.Owner.Equals(owner) || !registered.Parent.Equals(SOL_TLD_AUTHORITY) {
+		t.Errorf("unexpected registration: %+v", registered)
+	}
+	if registered.Name != "bonfida.sol" {
+		t.Errorf("expected the name from the reverse lookup, got %q", registered.Name)
+	}
+
+	transferred := events[1]
+	if transferred.Type != DomainTransfer || transferred.InstructionIndex != 3 || !transferred.Owner.Equals(owner) || !transferred.NewOwner.Equals(newOwner) {
+		t.Errorf("unexpected transfer: %+v", transferred)
+	}
+	if transferred.Name != "bonfida.sol" {
+		t.Errorf("expected the transfer to be named in the same transaction, got %q", transferred.Name)
+	}
+}
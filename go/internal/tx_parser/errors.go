@@ -0,0 +1,61 @@
+package tx_parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ParseError describes an instruction that a protocol parser claimed but failed to parse
+type ParseError struct {
+	Protocol         SwapType
+	Program          solana.PublicKey
+	InstructionIndex int
+	InnerIndex       int // -1 for outer instructions
+	Err              error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	if e.InnerIndex < 0 {
+		return fmt.Sprintf("%s: instruction %d: %v", e.Protocol, e.InstructionIndex, e.Err)
+	}
+	return fmt.Sprintf("%s: instruction %d.%d: %v", e.Protocol, e.InstructionIndex, e.InnerIndex, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseErrorJSON is the JSON form of a ParseError
+type parseErrorJSON struct {
+	Protocol         SwapType         `json:"protocol"`
+	Program          solana.PublicKey `json:"program"`
+	InstructionIndex int              `json:"instruction_index"`
+	InnerIndex       int              `json:"inner_index"`
+	Error            string           `json:"error"`
+}
+
+// MarshalJSON encodes the error with its message, as error values have no JSON form
+func (e *ParseError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(parseErrorJSON{e.Protocol, e.Program, e.InstructionIndex, e.InnerIndex, e.Err.Error()})
+}
+
+// UnmarshalJSON decodes an error written by MarshalJSON, keeping only the message of the cause
+func (e *ParseError) UnmarshalJSON(data []byte) error {
+	var decoded parseErrorJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*e = ParseError{
+		Protocol:         decoded.Protocol,
+		Program:          decoded.Program,
+		InstructionIndex: decoded.InstructionIndex,
+		InnerIndex:       decoded.InnerIndex,
+		Err:              errors.New(decoded.Error),
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package tx_parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// newPartialSwapFixture builds a transaction with an unparseable Orca instruction
+// followed by a Raydium swap of two token transfers
+func newPartialSwapFixture() (*solana.Transaction, *rpc.TransactionMeta) {
+	keys := newTestKeys(8)
+	user, pool, wsol, usdc := keys[0], keys[1], keys[6], keys[7]
+	keys = append(keys, ORCA_PROGRAM_ID, RAYDIUM_V4_PROGRAM_ID, solana.TokenProgramID)
+	orcaIndex, raydiumIndex, tokenIndex := uint16(8), uint16(9), uint16(10)
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(orcaIndex, []byte{1}, 0),
+		testInstruction(raydiumIndex, []byte{9}, 0, 1, 2, 3, 4, 5),
+	)
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{
+			{
+				Index: 1,
+				Instructions: []solana.CompiledInstruction{
+					testInstruction(tokenIndex, tokenTransferData(1_000_000_000), 2, 3, 0),
+					testInstruction(tokenIndex, tokenTransferData(150_000_000), 4, 5, 1),
+				},
+			},
+		},
+		PreTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(2, user, wsol, 2_000_000_000, 9),
+			testTokenBalance(3, pool, wsol, 9_000_000_000, 9),
+			testTokenBalance(4, pool, usdc, 500_000_000, 6),
+			testTokenBalance(5, user, usdc, 0, 6),
+		},
+	}
+	return tx, meta
+}
+
+func TestParseSwapsLenient(t *testing.T) {
+	tx, meta := newPartialSwapFixture()
+	parser := newTestParser(tx, meta, ParseOptions{})
+
+	swaps, parseErrors, err := parser.ParseSwaps()
+	if err != nil {
+		t.Fatalf("lenient parsing should not fail: %v", err)
+	}
+	if len(swaps) != 1 || swaps[0].Protocol != SwapTypeRaydium || swaps[0].InstructionIndex != 1 {
+		t.Fatalf("expected one Raydium swap at instruction 1, got %+v", swaps)
+	}
+	if len(parseErrors) != 1 {
+		t.Fatalf("expected one parse error, got %d", len(parseErrors))
+	}
+
+	parseError := parseErrors[0]
+	if parseError.Protocol != SwapTypeOrca || parseError.InstructionIndex != 0 || parseError.InnerIndex != -1 {
+		t.Errorf("unexpected parse error: %+v", parseError)
+	}
+	if !parseError.Program.Equals(ORCA_PROGRAM_ID) || !strings.Contains(parseError.Error(), "no valid Orca swaps found") {
+		t.Errorf("unexpected parse error message: %v", parseError)
+	}
+
+	encoded, err := json.Marshal(parseError)
+	if err != nil || !strings.Contains(string(encoded), `"error":"no valid Orca swaps found"`) {
+		t.Errorf("unexpected parse error JSON %s (%v)", encoded, err)
+	}
+
+	parsed, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("failed to parse transaction: %v", err)
+	}
+	if len(parsed.Swaps) != 1 || len(parsed.Errors) != 1 {
+		t.Errorf("expected partial result with one error, got %d swaps and %d errors", len(parsed.Swaps), len(parsed.Errors))
+	}
+}
+
+func TestParseSwapsStrict(t *testing.T) {
+	tx, meta := newPartialSwapFixture()
+	parser := newTestParser(tx, meta, ParseOptions{Strict: true})
+
+	if _, err := parser.Parse(); err == nil {
+		t.Fatal("expected strict parsing to fail")
+	}
+
+	_, _, err := parser.ParseSwaps()
+	var parseError *ParseError
+	if !errors.As(err, &parseError) || parseError.Protocol != SwapTypeOrca {
+		t.Errorf("expected an Orca ParseError, got %v", err)
+	}
+}
+
+func TestParseOptionsProtocols(t *testing.T) {
+	tx, meta := newPartialSwapFixture()
+	parser := newTestParser(tx, meta, ParseOptions{Strict: true, Protocols: []SwapType{SwapTypeRaydium}})
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		t.Fatalf("expected Orca to be skipped, got %v", err)
+	}
+	if len(swaps) != 1 || swaps[0].TokenIn.Amount != 1_000_000_000 {
+		t.Errorf("unexpected swaps: %+v", swaps)
+	}
+}
+
+// recordingHook records every handler call
+type recordingHook struct {
+	calls []string
+}
+
+func (h *recordingHook) HandlerStarted(protocol SwapType, instructionIndex, innerIndex int) func([]*SwapInfo, error) {
+	return func(swaps []*SwapInfo, err error) {
+		h.calls = append(h.calls, fmt.Sprintf("%s %d %d swaps=%d failed=%t", protocol, instructionIndex, innerIndex, len(swaps), err != nil))
+	}
+}
+
+func TestParseHook(t *testing.T) {
+	tx, meta := newPartialSwapFixture()
+	hook := &recordingHook{}
+	if _, _, err := newTestParser(tx, meta, ParseOptions{Hook: hook}).ParseSwaps(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"Orca 0 -1 swaps=0 failed=true", "Raydium 1 -1 swaps=1 failed=false"}
+	if fmt.Sprint(hook.calls) != fmt.Sprint(expected) {
+		t.Errorf("expected handler calls %v, got %v", expected, hook.calls)
+	}
+}
+
+func TestParseLogger(t *testing.T) {
+	tx, meta := newPartialSwapFixture()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if _, _, err := newTestParser(tx, meta, ParseOptions{Logger: logger}).ParseSwaps(); err != nil {
+		t.Fatal(err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single record, got %s", buf.String())
+	}
+	if record["parser"] != string(SwapTypeOrca) || record["instruction_index"] != float64(0) || record["error"] == nil {
+		t.Errorf("unexpected record %v", record)
+	}
+	if record["signature"] != tx.Signatures[0].String() {
+		t.Errorf("expected signature %s, got %v", tx.Signatures[0], record["signature"])
+	}
+}
@@ -0,0 +1,303 @@
+package tx_parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ProgramError is a custom error a program returns
+type ProgramError struct {
+	Name    string `json:"name"`
+	Message string `json:"msg"`
+}
+
+// ProgramErrors maps the custom error codes of programs to their errors
+type ProgramErrors map[solana.PublicKey]map[uint32]ProgramError
+
+// LoadIDL adds the errors of an Anchor IDL, in either the legacy or the current layout.
+// The program defaults to the address in the IDL when zero.
+func (e ProgramErrors) LoadIDL(program solana.PublicKey, idl []byte) error {
+	var decoded struct {
+		Address  string `json:"address"`
+		Metadata struct {
+			Address string `json:"address"`
+		} `json:"metadata"`
+		Errors []struct {
+			Code uint32 `json:"code"`
+			ProgramError
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(idl, &decoded); err != nil {
+		return fmt.Errorf("failed to decode IDL: %w", err)
+	}
+
+	if program.IsZero() {
+		address := decoded.Address
+		if address == "" {
+			address = decoded.Metadata.Address
+		}
+		var err error
+		if program, err = solana.PublicKeyFromBase58(address); err != nil {
+			return fmt.Errorf("IDL has no valid program address: %w", err)
+		}
+	}
+
+	if e[program] == nil {
+		e[program] = make(map[uint32]ProgramError, len(decoded.Errors))
+	}
+	for _, programError := range decoded.Errors {
+		e[program][programError.Code] = programError.ProgramError
+	}
+	return nil
+}
+
+// Lookup returns the error a program returns for a custom code
+func (e ProgramErrors) Lookup(program solana.PublicKey, code uint32) (ProgramError, bool) {
+	programError, ok := e[program][code]
+	return programError, ok
+}
+
+// tokenProgramErrors are the errors of the token programs, Token-2022 shares the codes
+var tokenProgramErrors = map[uint32]ProgramError{
+	0:  {"NotRentExempt", "Lamport balance below rent-exempt threshold"},
+	1:  {"InsufficientFunds", "Insufficient funds"},
+	2:  {"InvalidMint", "Invalid Mint"},
+	3:  {"MintMismatch", "Account not associated with this Mint"},
+	4:  {"OwnerMismatch", "Owner does not match"},
+	5:  {"FixedSupply", "Fixed supply"},
+	6:  {"AlreadyInUse", "Already in use"},
+	7:  {"InvalidNumberOfProvidedSigners", "Invalid number of provided signers"},
+	8:  {"InvalidNumberOfRequiredSigners", "Invalid number of required signers"},
+	9:  {"UninitializedState", "State is uninitialized"},
+	10: {"NativeNotSupported", "Instruction does not support native tokens"},
+	11: {"NonNativeHasBalance", "Non-native account can only be closed if its balance is zero"},
+	12: {"InvalidInstruction", "Invalid instruction"},
+	13: {"InvalidState", "State is invalid for requested operation"},
+	14: {"Overflow", "Operation overflowed"},
+	15: {"AuthorityTypeNotSupported", "Account does not support specified authority type"},
+	16: {"MintCannotFreeze", "This token mint cannot freeze accounts"},
+	17: {"AccountFrozen", "Account is frozen"},
+	18: {"MintDecimalsMismatch", "The provided decimals value different from the Mint decimals"},
+	19: {"NonNativeNotSupported", "Instruction does not support non-native tokens"},
+}
+
+// builtinProgramErrors are the errors of common programs, looked up after the configured
+// errors and the errors Anchor programs log
+var builtinProgramErrors = ProgramErrors{
+	solana.TokenProgramID:     tokenProgramErrors,
+	solana.Token2022ProgramID: tokenProgramErrors,
+	solana.SystemProgramID: {
+		0: {"AccountAlreadyInUse", "an account with the same address already exists"},
+		1: {"ResultWithNegativeLamports", "account does not have enough SOL to perform the operation"},
+		2: {"InvalidProgramId", "cannot assign account to this program id"},
+		3: {"InvalidAccountDataLength", "cannot allocate account data of this length"},
+		4: {"MaxSeedLengthExceeded", "length of requested seed is too long"},
+		5: {"AddressWithSeedMismatch", "provided address does not match addressed derived from seed"},
+		6: {"NonceNoRecentBlockhashes", "advancing stored nonce requires a populated RecentBlockhashes sysvar"},
+		7: {"NonceBlockhashNotExpired", "stored nonce is still in recent_blockhashes"},
+		8: {"NonceUnexpectedBlockhashValue", "specified nonce does not match stored nonce"},
+	},
+	JUPITER_PROGRAM_ID: {
+		6000: {"EmptyRoute", "Empty route"},
+		6001: {"SlippageToleranceExceeded", "Slippage tolerance exceeded"},
+		6002: {"InvalidCalculation", "Invalid calculation"},
+	},
+	RAYDIUM_V4_PROGRAM_ID: {
+		30: {"ExceededSlippage", "exceeds desired slippage limit"},
+	},
+	PUMP_FUN_PROGRAM_ID: {
+		6000: {"NotAuthorized", "The given account is not authorized to execute this instruction."},
+		6001: {"AlreadyInitialized", "The program is already initialized."},
+		6002: {"TooMuchSolRequired", "slippage: Too much SOL required to buy the given amount of tokens."},
+		6003: {"TooLittleSolReceived", "slippage: Too little SOL received to sell the given amount of tokens."},
+		6004: {"MintDoesNotMatchBondingCurve", "The mint does not match the bonding curve."},
+		6005: {"BondingCurveComplete", "The bonding curve has completed and liquidity migrated to raydium."},
+		6006: {"BondingCurveNotComplete", "The bonding curve has not completed."},
+		6007: {"NotInitialized", "The program is not initialized."},
+	},
+}
+
+// anchorErrors are the errors the Anchor framework returns for any Anchor program
+var anchorErrors = map[uint32]ProgramError{
+	100:  {"InstructionMissing", "8 byte instruction identifier not provided"},
+	101:  {"InstructionFallbackNotFound", "Fallback functions are not supported"},
+	102:  {"InstructionDidNotDeserialize", "The program could not deserialize the given instruction"},
+	2000: {"ConstraintMut", "A mut constraint was violated"},
+	2001: {"ConstraintHasOne", "A has one constraint was violated"},
+	2002: {"ConstraintSigner", "A signer constraint was violated"},
+	2003: {"ConstraintRaw", "A raw constraint was violated"},
+	2004: {"ConstraintOwner", "An owner constraint was violated"},
+	2005: {"ConstraintRentExempt", "A rent exemption constraint was violated"},
+	2006: {"ConstraintSeeds", "A seeds constraint was violated"},
+	2012: {"ConstraintAddress", "An address constraint was violated"},
+	2014: {"ConstraintTokenMint", "A token mint constraint was violated"},
+	2015: {"ConstraintTokenOwner", "A token owner constraint was violated"},
+	3000: {"AccountDiscriminatorAlreadySet", "The account discriminator was already set on this account"},
+	3001: {"AccountDiscriminatorNotFound", "No 8 byte discriminator was found on the account"},
+	3002: {"AccountDiscriminatorMismatch", "8 byte discriminator did not match what was expected"},
+	3003: {"AccountDidNotDeserialize", "Failed to deserialize the account"},
+	3004: {"AccountDidNotSerialize", "Failed to serialize the account"},
+	3005: {"AccountNotEnoughKeys", "Not enough account keys given to the instruction"},
+	3006: {"AccountNotMutable", "The given account is not mutable"},
+	3007: {"AccountOwnedByWrongProgram", "The given account is owned by a different program than expected"},
+	3008: {"InvalidProgramId", "Program ID was not as expected"},
+	3009: {"InvalidProgramExecutable", "Program account is not executable"},
+	3010: {"AccountNotSigner", "The given account did not sign"},
+	3011: {"AccountNotSystemOwned", "The given account is not owned by the system program"},
+	3012: {"AccountNotInitialized", "The program expected this account to be already initialized"},
+}
+
+// anchorErrorLog matches the error line Anchor programs log before failing
+var anchorErrorLog = regexp.MustCompile(`Error Code: (\w+)\. Error Number: (\d+)\. Error Message: (.*?)\.?$`)
+
+// AnalyzeFailure decodes the error of a failed transaction, returning nil for successful ones
+func AnalyzeFailure(tx *solana.Transaction, meta *rpc.TransactionMeta, programErrors ProgramErrors) (*TransactionFailure, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return analyzeFailure(ctx, programErrors), nil
+}
+
+// AnalyzeFailure decodes the error of the parsed transaction, returning nil if it succeeded
+func (p *Parser) AnalyzeFailure() *TransactionFailure {
+	return analyzeFailure(p.ctx, p.opts.ProgramErrors)
+}
+
+// analyzeFailure decodes the metadata error, attributes it to the invocation that returned
+// it and classifies it
+func analyzeFailure(ctx *TransactionContext, programErrors ProgramErrors) *TransactionFailure {
+	if ctx.Meta.Err == nil {
+		return nil
+	}
+
+	failure := &TransactionFailure{InstructionIndex: -1, InnerIndex: -1}
+	decodeTransactionError(ctx.Meta.Err, failure)
+
+	var logs []string
+	if failure.InstructionIndex >= 0 {
+		instructions := ctx.Transaction.Message.Instructions
+		if failure.InstructionIndex < len(instructions) && int(instructions[failure.InstructionIndex].ProgramIDIndex) < len(ctx.AccountKeys) {
+			failure.Program = ctx.AccountKeys[instructions[failure.InstructionIndex].ProgramIDIndex]
+		}
+
+		// Every caller of the failing program fails with the same error, the deepest
+		// failed invocation is the one that returned it
+		var failed *ProgramInvocation
+		walkInvocations(ctx.ProgramLogs(), func(invocation *ProgramInvocation) {
+			if invocation.InstructionIndex == failure.InstructionIndex && invocation.Status == InvocationFailed &&
+				(failed == nil || invocation.StackHeight > failed.StackHeight) {
+				failed = invocation
+			}
+		})
+		if failed != nil {
+			failure.Program, failure.InnerIndex, logs = failed.Program, failed.InnerIndex, failed.Logs
+		}
+	}
+
+	if failure.CustomCode != nil {
+		failure.ErrorName, failure.ErrorMessage = customError(failure.Program, *failure.CustomCode, logs, programErrors)
+	}
+	failure.Kind = classifyFailure(failure, logs)
+	return failure
+}
+
+// decodeTransactionError reads the error in the form getTransaction returns it, e.g.
+// "BlockhashNotFound" or {"InstructionError":[2,{"Custom":6001}]}. Other sources encode
+// their errors into this form through MarshalJSON.
+func decodeTransactionError(transactionError any, failure *TransactionFailure) {
+	data, err := json.Marshal(transactionError)
+	if err != nil {
+		failure.Error = fmt.Sprint(transactionError)
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		failure.Error = string(data)
+		return
+	}
+
+	switch value := value.(type) {
+	case string:
+		failure.Error = value
+	case map[string]any:
+		for name, detail := range value {
+			failure.Error = name
+			args, ok := detail.([]any)
+			if name != "InstructionError" || !ok || len(args) != 2 {
+				continue
+			}
+			if index, err := strconv.Atoi(fmt.Sprint(args[0])); err == nil {
+				failure.InstructionIndex = index
+			}
+			switch instructionError := args[1].(type) {
+			case string:
+				failure.InstructionError = instructionError
+			case map[string]any:
+				for kind, detail := range instructionError {
+					failure.InstructionError = kind
+					if code, err := strconv.ParseUint(fmt.Sprint(detail), 10, 32); kind == "Custom" && err == nil {
+						custom := uint32(code)
+						failure.CustomCode = &custom
+					}
+				}
+			}
+		}
+	default:
+		failure.Error = string(data)
+	}
+}
+
+// customError names a custom error code, preferring the configured errors, then what an
+// Anchor program logged, then the errors of common programs and of Anchor itself
+func customError(program solana.PublicKey, code uint32, logs []string, programErrors ProgramErrors) (name, message string) {
+	if programError, ok := programErrors.Lookup(program, code); ok {
+		return programError.Name, programError.Message
+	}
+	for _, line := range logs {
+		match := anchorErrorLog.FindStringSubmatch(line)
+		if match != nil && match[2] == strconv.FormatUint(uint64(code), 10) {
+			return match[1], match[3]
+		}
+	}
+	if programError, ok := builtinProgramErrors.Lookup(program, code); ok {
+		return programError.Name, programError.Message
+	}
+	native := program.Equals(solana.SystemProgramID) || program.Equals(solana.TokenProgramID) || program.Equals(solana.Token2022ProgramID)
+	if programError, ok := anchorErrors[code]; ok && !native {
+		return programError.Name, programError.Message
+	}
+	return "", ""
+}
+
+// classifyFailure sorts the failure into the kinds retry logic cares about
+func classifyFailure(failure *TransactionFailure, logs []string) FailureKind {
+	name, message := strings.ToLower(failure.ErrorName), strings.ToLower(failure.ErrorMessage)
+	logged := strings.ToLower(strings.Join(logs, "\n"))
+	switch {
+	case failure.Error == "BlockhashNotFound":
+		return FailureBlockhashExpired
+	case failure.Error == "InsufficientFundsForFee", failure.Error == "InsufficientFundsForRent",
+		failure.InstructionError == "InsufficientFunds":
+		return FailureInsufficientFunds
+	case failure.InstructionError == "ComputationalBudgetExceeded", strings.Contains(logged, "exceeded cus meter"):
+		return FailureComputeExceeded
+	case strings.Contains(name, "slippage"), strings.Contains(message, "slippage"),
+		strings.HasPrefix(name, "toolittle"), strings.HasPrefix(name, "toomuch"):
+		return FailureSlippage
+	case name == "insufficientfunds", name == "resultwithnegativelamports",
+		strings.Contains(logged, "insufficient lamports"), strings.Contains(logged, "insufficient funds"):
+		return FailureInsufficientFunds
+	}
+	return FailureOther
+}
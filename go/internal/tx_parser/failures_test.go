@@ -0,0 +1,98 @@
+package tx_parser
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// instructionError builds a metadata error as the RPC client decodes it
+func instructionError(index int, detail any) any {
+	return map[string]any{"InstructionError": []any{float64(index), detail}}
+}
+
+func TestAnalyzeFailure(t *testing.T) {
+	keys := append(newTestKeys(2), JUPITER_PROGRAM_ID, solana.TokenProgramID)
+	tx := newTestTransaction(keys, 1, testInstruction(1, nil), testInstruction(2, nil))
+	jupiter, token := JUPITER_PROGRAM_ID.String(), solana.TokenProgramID.String()
+
+	slippage := &rpc.TransactionMeta{
+		Err: instructionError(1, map[string]any{"Custom": float64(6001)}),
+		LogMessages: []string{
+			"Program " + keys[1].String() + " invoke [1]",
+			"Program " + keys[1].String() + " success",
+			"Program " + jupiter + " invoke [1]",
+			"Program log: AnchorError occurred. Error Code: SlippageToleranceExceeded. Error Number: 6001. Error Message: Slippage tolerance exceeded.",
+			"Program " + jupiter + " failed: custom program error: 0x1771",
+		},
+	}
+	failure, err := AnalyzeFailure(tx, slippage, nil)
+	if err != nil {
+		t.Fatalf("failed to analyze: %v", err)
+	}
+	if failure.Kind != FailureSlippage || failure.InstructionIndex != 1 || failure.InnerIndex != -1 || !failure.Program.Equals(JUPITER_PROGRAM_ID) {
+		t.Errorf("unexpected slippage failure: %+v", failure)
+	}
+	if failure.CustomCode == nil || *failure.CustomCode != 6001 || failure.ErrorName != "SlippageToleranceExceeded" || failure.ErrorMessage != "Slippage tolerance exceeded" {
+		t.Errorf("expected the logged Anchor error, got %+v", failure)
+	}
+
+	// The token program invoked by Jupiter returns the error
+	insufficient := &rpc.TransactionMeta{
+		Err: instructionError(1, map[string]any{"Custom": float64(1)}),
+		LogMessages: []string{
+			"Program " + jupiter + " invoke [1]",
+			"Program " + token + " invoke [2]",
+			"Program log: Error: insufficient funds",
+			"Program " + token + " failed: custom program error: 0x1",
+			"Program " + jupiter + " failed: custom program error: 0x1",
+		},
+	}
+	failure, _ = AnalyzeFailure(tx, insufficient, nil)
+	if failure.Kind != FailureInsufficientFunds || !failure.Program.Equals(solana.TokenProgramID) || failure.InnerIndex != 0 || failure.ErrorName != "InsufficientFunds" {
+		t.Errorf("expected the nested token program failure, got %+v", failure)
+	}
+
+	failure, _ = AnalyzeFailure(tx, &rpc.TransactionMeta{Err: "BlockhashNotFound"}, nil)
+	if failure.Kind != FailureBlockhashExpired || failure.InstructionIndex != -1 || !failure.Program.IsZero() {
+		t.Errorf("unexpected blockhash failure: %+v", failure)
+	}
+	failure, _ = AnalyzeFailure(tx, &rpc.TransactionMeta{Err: instructionError(0, "ComputationalBudgetExceeded")}, nil)
+	if failure.Kind != FailureComputeExceeded || failure.InstructionError != "ComputationalBudgetExceeded" || !failure.Program.Equals(keys[1]) {
+		t.Errorf("unexpected compute failure: %+v", failure)
+	}
+
+	if failure, _ := AnalyzeFailure(tx, &rpc.TransactionMeta{}, nil); failure != nil {
+		t.Errorf("expected no failure for a successful transaction, got %+v", failure)
+	}
+}
+
+func TestProgramErrorsIDL(t *testing.T) {
+	keys := newTestKeys(2)
+	programErrors := ProgramErrors{}
+	idl := `{"metadata":{"address":"` + keys[1].String() + `"},"errors":[{"code":6010,"name":"PriceImpactTooHigh","msg":"Price impact too high"},
+		{"code":6011,"name":"MinimumOutNotMet","msg":"Slippage exceeded"}]}`
+	if err := programErrors.LoadIDL(solana.PublicKey{}, []byte(idl)); err != nil {
+		t.Fatalf("failed to load IDL: %v", err)
+	}
+	if err := programErrors.LoadIDL(solana.PublicKey{}, []byte(`{"errors":[]}`)); err == nil {
+		t.Error("expected an IDL without an address to need the program")
+	}
+
+	tx := newTestTransaction(keys, 1, testInstruction(1, nil))
+	meta := &rpc.TransactionMeta{Err: instructionError(0, map[string]any{"Custom": float64(6011)})}
+	parser := newTestParser(tx, meta, ParseOptions{AnalyzeFailures: true, ProgramErrors: programErrors})
+	parsed, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if failure := parsed.Failure; failure == nil || failure.ErrorName != "MinimumOutNotMet" || failure.Kind != FailureSlippage {
+		t.Errorf("expected the IDL error to name and classify the failure, got %+v", failure)
+	}
+
+	parsed, _ = newTestParser(tx, meta, ParseOptions{}).Parse()
+	if parsed.Failure != nil {
+		t.Error("expected failures to be analyzed only when enabled")
+	}
+}
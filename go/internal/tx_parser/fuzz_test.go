@@ -0,0 +1,112 @@
+package tx_parser
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// fuzzPrograms follow the fuzzed accounts so instructions can target every parser
+var fuzzPrograms = []solana.PublicKey{
+	JUPITER_PROGRAM_ID, JUPITER_DCA_PROGRAM_ID, PUMP_FUN_PROGRAM_ID, RAYDIUM_V4_PROGRAM_ID,
+	RAYDIUM_AMM_PROGRAM_ID, RAYDIUM_CPMM_PROGRAM_ID, RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID,
+	METEORA_PROGRAM_ID, METEORA_POOLS_PROGRAM_ID, METEORA_DAMM_V2_PROGRAM_ID, METEORA_BONDING_CURVE_PROGRAM_ID, MOONSHOT_PROGRAM_ID,
+	ORCA_PROGRAM_ID, OKX_PROGRAM_ID, RAYDIUM_LAUNCHLAB_PROGRAM_ID, HEAVEN_PROGRAM_ID, BOOP_PROGRAM_ID,
+	SABER_PROGRAM_ID, MERCURIAL_PROGRAM_ID, INVARIANT_PROGRAM_ID, CREMA_PROGRAM_ID, FLUXBEAM_PROGRAM_ID,
+	GOOSEFX_GAMMA_PROGRAM_ID, STABBLE_STABLE_SWAP_PROGRAM_ID, STABBLE_WEIGHTED_SWAP_PROGRAM_ID, ZETA_PROGRAM_ID, JUPITER_PERPS_PROGRAM_ID,
+	BUBBLEGUM_PROGRAM_ID, SPL_NOOP_PROGRAM_ID, CANDY_MACHINE_V3_PROGRAM_ID, CORE_CANDY_MACHINE_PROGRAM_ID, MPL_CORE_PROGRAM_ID,
+	NAME_SERVICE_PROGRAM_ID, WORMHOLE_TOKEN_BRIDGE_PROGRAM_ID, DEBRIDGE_DLN_SOURCE_PROGRAM_ID, DEBRIDGE_DLN_DESTINATION_PROGRAM_ID, MAYAN_SWIFT_PROGRAM_ID,
+	STAKE_PROGRAM_ID, SPL_STAKE_POOL_PROGRAM_ID, MARINADE_PROGRAM_ID, COMPUTE_BUDGET_PROGRAM_ID,
+	MEMO_PROGRAM_ID, MEMO_V1_PROGRAM_ID, solana.SystemProgramID, solana.TokenProgramID, solana.Token2022ProgramID,
+}
+
+// fuzzAccounts is the number of plain accounts before the programs
+const fuzzAccounts = 8
+
+// newFuzzContext builds a transaction with an outer instruction to the program and an
+// inner instruction to the inner program. Indices are taken modulo a range slightly
+// larger than the account keys so that malformed indices are exercised too.
+func newFuzzContext(program, innerProgram uint8, accounts, data []byte) (*TransactionContext, solana.CompiledInstruction) {
+	keys := make([]solana.PublicKey, fuzzAccounts, fuzzAccounts+len(fuzzPrograms))
+	for i := range keys {
+		keys[i] = solana.PublicKeyFromBytes(func() []byte { h := sha256.Sum256([]byte{byte(i)}); return h[:] }())
+	}
+	keys = append(keys, fuzzPrograms...)
+	index := func(b byte) uint16 { return uint16(int(b) % (len(keys) + 4)) }
+
+	indices := make([]uint16, len(accounts))
+	for i, b := range accounts {
+		indices[i] = index(b)
+	}
+	instruction := testInstruction(index(program), data, indices...)
+
+	var balances []rpc.TokenBalance
+	for i, b := range accounts {
+		if i >= 4 {
+			break
+		}
+		balances = append(balances, testTokenBalance(index(b), keys[i], keys[fuzzAccounts-1-i], uint64(b)<<20, b%10))
+	}
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{{
+			Index:        0,
+			Instructions: []solana.CompiledInstruction{testInstruction(index(innerProgram), data, indices...)},
+		}},
+		PreTokenBalances:  balances,
+		PostTokenBalances: balances,
+		PreBalances:       make([]uint64, len(keys)),
+		PostBalances:      make([]uint64, len(keys)),
+	}
+
+	ctx, err := newTransactionContext(newTestTransaction(keys, 1, instruction), meta)
+	if err != nil {
+		return nil, instruction
+	}
+	return ctx, instruction
+}
+
+// addFuzzSeeds adds an instruction with a transfer payload, a truncated payload and an
+// out of range account for every program, and one with an out of range program
+func addFuzzSeeds(f *testing.F) {
+	outOfRange := uint8(fuzzAccounts + len(fuzzPrograms) + 1)
+	tokenProgram := uint8(fuzzAccounts + len(fuzzPrograms) - 2)
+	for i := range fuzzPrograms {
+		program := uint8(fuzzAccounts + i)
+		f.Add(program, tokenProgram, []byte{0, 1, 2, 3, 4, 5, 6, 7}, []byte{3, 0, 202, 154, 59, 0, 0, 0, 0})
+		f.Add(program, program, []byte{0, 1}, []byte{9})
+		f.Add(program, tokenProgram, []byte{0, outOfRange, 2, 3}, []byte{12, 0, 202, 154, 59, 0, 0, 0, 0})
+	}
+	f.Add(outOfRange, outOfRange, []byte{0, 1, 2}, []byte{3, 1, 0, 0, 0, 0, 0, 0, 0})
+}
+
+// FuzzParseInstruction calls every protocol handler directly, bypassing the recovery of
+// Parse, so that any panic on malformed input fails the target
+func FuzzParseInstruction(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, program, innerProgram uint8, accounts, data []byte) {
+		ctx, instruction := newFuzzContext(program, innerProgram, accounts, data)
+		if ctx == nil {
+			return
+		}
+		for _, handler := range NewFromContext(ctx, ParseOptions{}).handlers {
+			if handler.CanHandle(instruction, ctx.AccountKeys) {
+				handler.ParseInstruction(instruction, 0, ctx)
+			}
+		}
+	})
+}
+
+// FuzzParse runs the full parser, including the transfer, stake, token event, compute
+// budget, memo and pool creation extractors
+func FuzzParse(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, program, innerProgram uint8, accounts, data []byte) {
+		ctx, _ := newFuzzContext(program, innerProgram, accounts, data)
+		if ctx == nil {
+			return
+		}
+		NewFromContext(ctx, ParseOptions{}).Parse()
+	})
+}
@@ -0,0 +1,33 @@
+package tx_parser
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the transaction with its schema version. Amounts are strings so
+// they survive decoders that read numbers as float64, public keys and signatures are base58.
+func (tx ParsedTransaction) MarshalJSON() ([]byte, error) {
+	type parsedTransaction ParsedTransaction
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		parsedTransaction
+	}{SCHEMA_VERSION, parsedTransaction(tx)})
+}
+
+// UnmarshalJSON decodes a transaction, rejecting schema versions newer than SCHEMA_VERSION
+func (tx *ParsedTransaction) UnmarshalJSON(data []byte) error {
+	type parsedTransaction ParsedTransaction
+	var decoded struct {
+		SchemaVersion int `json:"schema_version"`
+		*parsedTransaction
+	}
+	decoded.parsedTransaction = (*parsedTransaction)(tx)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.SchemaVersion > SCHEMA_VERSION {
+		return fmt.Errorf("unsupported schema version %d, expected at most %d", decoded.SchemaVersion, SCHEMA_VERSION)
+	}
+	return nil
+}
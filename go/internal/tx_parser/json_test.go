@@ -0,0 +1,71 @@
+package tx_parser
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestParsedTransactionJSON(t *testing.T) {
+	wallet, token := newTestKeys(2)[0], newTestKeys(2)[1]
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	tx := &ParsedTransaction{
+		Signature: solana.Signature{1, 2, 3},
+		Slot:      300,
+		BlockTime: &blockTime,
+		Fee:       5000,
+		Swaps: []*SwapInfo{{
+			Protocol:  SwapTypeRaydium,
+			Signers:   []solana.PublicKey{wallet},
+			Timestamp: time.Unix(1_700_000_000, 0).UTC(),
+			TokenIn:   TokenInfo{Mint: NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut:  TokenInfo{Mint: token, Amount: 18_446_744_073_709_551_615, Decimals: 6},
+		}},
+		AdminEvents: []*TokenAdminEvent{{Type: TokenAdminSetAuthority, Mint: token}},
+		Errors:      []*ParseError{{Protocol: SwapTypeOrca, InnerIndex: -1, Err: errors.New("invalid data")}},
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	for _, want := range []string{
+		`"schema_version":1`,
+		`"amount":"18446744073709551615"`,
+		`"fee":"5000"`,
+		`"mint":"` + token.String() + `"`,
+		`"token_in":{`,
+		`"new_authority":null`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in %s", want, data)
+		}
+	}
+
+	var decoded ParsedTransaction
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if decoded.Errors[0].Err.Error() != "invalid data" {
+		t.Errorf("expected the parse error message to survive, got %v", decoded.Errors[0].Err)
+	}
+	decoded.Errors[0].Err = tx.Errors[0].Err
+	if !reflect.DeepEqual(&decoded, tx) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", &decoded, tx)
+	}
+}
+
+func TestParsedTransactionJSONVersion(t *testing.T) {
+	var tx ParsedTransaction
+	if err := json.Unmarshal([]byte(`{"schema_version":99,"slot":1}`), &tx); err == nil {
+		t.Error("expected a newer schema version to be rejected")
+	}
+	if err := json.Unmarshal([]byte(`{"slot":1,"unknown_field":true}`), &tx); err != nil || tx.Slot != 1 {
+		t.Errorf("expected unknown fields to be ignored, got %v", err)
+	}
+}
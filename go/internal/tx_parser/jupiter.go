@@ -28,7 +28,7 @@ type JupiterSwapEvent struct {
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *JupiterParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	return accountKeys[instruction.ProgramIDIndex].Equals(JUPITER_PROGRAM_ID)
+	return hasValidIndices(instruction, accountKeys) && accountKeys[instruction.ProgramIDIndex].Equals(JUPITER_PROGRAM_ID)
 }
 
 // ParseInstruction processes the Jupiter instruction and returns swap information
@@ -20,7 +20,7 @@ func NewJupiterDCAParser() *JupiterDCAParser {
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *JupiterDCAParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	return accountKeys[instruction.ProgramIDIndex].Equals(JUPITER_DCA_PROGRAM_ID)
+	return hasValidIndices(instruction, accountKeys) && accountKeys[instruction.ProgramIDIndex].Equals(JUPITER_DCA_PROGRAM_ID)
 }
 
 // ParseInstruction processes the Jupiter DCA instruction and returns swap information
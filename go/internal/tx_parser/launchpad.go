@@ -0,0 +1,142 @@
+package tx_parser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// LaunchpadConfig describes a bonding curve launchpad whose trades can be parsed from
+// the balance changes of the curve's vaults. Launchpads that fit this shape need a
+// config entry rather than a parser of their own.
+type LaunchpadConfig struct {
+	Protocol SwapType
+	Program  solana.PublicKey
+
+	// Discriminators of the buy and sell instructions
+	Buy  [][8]byte
+	Sell [][8]byte
+
+	// Accounts of the trade instructions, shared by buys and sells
+	Accounts   int // minimum number of accounts
+	Mint       int // token launched on the curve
+	QuoteMint  int // token the curve trades against, 0 for SOL
+	TokenVault int // curve account holding the launched token
+	QuoteVault int // curve account holding the quote token, or the lamports of SOL curves
+}
+
+// LAUNCHPADS are the bonding curve launchpads parsed through LaunchpadParser. Believe
+// launches on the Meteora Dynamic Bonding Curve and is parsed as SwapTypeMeteoraDBC.
+var LAUNCHPADS = []LaunchpadConfig{
+	{
+		Protocol:   SwapTypeHeaven,
+		Program:    HEAVEN_PROGRAM_ID,
+		Buy:        [][8]byte{{102, 6, 61, 18, 1, 218, 235, 234}},
+		Sell:       [][8]byte{{51, 230, 133, 164, 1, 127, 131, 173}},
+		Accounts:   12,
+		Mint:       6,
+		QuoteMint:  7,
+		TokenVault: 10,
+		QuoteVault: 11,
+	},
+	{
+		Protocol:   SwapTypeBoop,
+		Program:    BOOP_PROGRAM_ID,
+		Buy:        [][8]byte{{138, 127, 14, 91, 38, 87, 115, 105}},
+		Sell:       [][8]byte{{109, 61, 40, 187, 230, 176, 135, 174}},
+		Accounts:   10,
+		Mint:       0,
+		TokenVault: 3,
+		QuoteVault: 4,
+	},
+}
+
+// RegisterLaunchpad makes a launchpad available to every Parser created afterwards
+func RegisterLaunchpad(config LaunchpadConfig) {
+	RegisterParser(config.Protocol, func() SwapParser { return NewLaunchpadParser(config) })
+}
+
+// LaunchpadParser handles parsing trades on a bonding curve launchpad described by a
+// LaunchpadConfig. Amounts are the curve's vault balance changes, so trades routed
+// through the same curve twice in one transaction are reported combined.
+type LaunchpadParser struct {
+	config LaunchpadConfig
+}
+
+// NewLaunchpadParser creates a new parser for the launchpad
+func NewLaunchpadParser(config LaunchpadConfig) *LaunchpadParser {
+	return &LaunchpadParser{config: config}
+}
+
+// CanHandle checks if this parser can handle the given instruction
+func (p *LaunchpadParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
+	if !hasValidIndices(instruction, accountKeys) || !accountKeys[instruction.ProgramIDIndex].Equals(p.config.Program) {
+		return false
+	}
+	if len(instruction.Accounts) < p.config.Accounts {
+		return false
+	}
+
+	_, ok := p.tradeDirection(instruction.Data)
+	return ok
+}
+
+// ParseInstruction processes a launchpad buy or sell and returns swap information
+func (p *LaunchpadParser) ParseInstruction(instruction solana.CompiledInstruction, instructionIndex int, ctx *TransactionContext) ([]*SwapInfo, error) {
+	isBuy, ok := p.tradeDirection(instruction.Data)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s instruction discriminator", p.config.Protocol)
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	tokenChange, ok := ctx.TokenBalanceChange(account(p.config.TokenVault))
+	if !ok || tokenChange == 0 || (tokenChange < 0) != isBuy {
+		return nil, fmt.Errorf("no %s curve token balance change found", p.config.Protocol)
+	}
+
+	quote := TokenInfo{Mint: NATIVE_SOL_PROGRAM_ID, Decimals: 9}
+	var quoteChange int64
+	if p.config.QuoteMint > 0 {
+		quote.Mint = account(p.config.QuoteMint)
+		quote.Decimals = ctx.GetMintDecimals(quote.Mint)
+		quoteChange, _ = ctx.TokenBalanceChange(account(p.config.QuoteVault))
+	} else {
+		vault := account(p.config.QuoteVault)
+		quoteChange = int64(ctx.lamportsOf(vault)) - int64(ctx.preLamportsOf(vault))
+	}
+	if quoteChange == 0 || (quoteChange > 0) != isBuy {
+		return nil, fmt.Errorf("no %s curve quote balance change found", p.config.Protocol)
+	}
+
+	mint := account(p.config.Mint)
+	base := TokenInfo{Mint: mint, Amount: uint64(abs(tokenChange)), Decimals: ctx.GetMintDecimals(mint)}
+	quote.Amount = uint64(abs(quoteChange))
+	swap := &SwapInfo{Protocol: p.config.Protocol}
+	if isBuy {
+		swap.TokenIn, swap.TokenOut = quote, base
+	} else {
+		swap.TokenIn, swap.TokenOut = base, quote
+	}
+
+	return []*SwapInfo{swap}, nil
+}
+
+// tradeDirection reports whether the instruction data is a buy or a sell
+func (p *LaunchpadParser) tradeDirection(data []byte) (isBuy, ok bool) {
+	if len(data) < 8 {
+		return false, false
+	}
+
+	for _, discriminator := range p.config.Buy {
+		if bytes.Equal(data[:8], discriminator[:]) {
+			return true, true
+		}
+	}
+	for _, discriminator := range p.config.Sell {
+		if bytes.Equal(data[:8], discriminator[:]) {
+			return false, true
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,123 @@
+package tx_parser
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestLaunchpadParser(t *testing.T) {
+	// 0 user, 1 curve, 2 mint, 3 quote mint, 4 token vault, 5 quote vault, 6 filler
+	keys := newTestKeys(7)
+	curve, mint, quoteMint := keys[1], keys[2], keys[3]
+	keys = append(keys, HEAVEN_PROGRAM_ID, BOOP_PROGRAM_ID)
+	heavenIndex, boopIndex := uint16(7), uint16(8)
+
+	heaven, boop := LAUNCHPADS[0], LAUNCHPADS[1]
+	// heaven: ..., pool 4, user 5, mint 6, quote mint 7, user vaults 8 and 9, curve vaults 10 and 11
+	heavenAccounts := []uint16{6, 6, 6, 6, 1, 0, 2, 3, 6, 6, 4, 5}
+	// boop: mint 0, curve 1, fee vault 2, token vault 3, SOL vault 4, user token 5, user 6, ...
+	boopAccounts := []uint16{2, 1, 6, 4, 5, 6, 0, 6, 6, 6}
+
+	t.Run("buy against a quote token", func(t *testing.T) {
+		buy := testInstruction(heavenIndex, append(heaven.Buy[0][:], make([]byte, 16)...), heavenAccounts...)
+		tx := newTestTransaction(keys, 1, buy)
+		meta := &rpc.TransactionMeta{
+			PreTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(4, curve, mint, 800_000_000_000, 6),
+				testTokenBalance(5, curve, quoteMint, 10_000_000_000, 9),
+			},
+			PostTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(4, curve, mint, 765_000_000_000, 6),
+				testTokenBalance(5, curve, quoteMint, 11_000_000_000, 9),
+			},
+		}
+
+		swaps, err := newTestParser(tx, meta, ParseOptions{}).ParseTransaction()
+		if err != nil {
+			t.Fatalf("failed to parse Heaven buy: %v", err)
+		}
+		if len(swaps) != 1 || swaps[0].Protocol != SwapTypeHeaven {
+			t.Fatalf("expected one Heaven swap, got %+v", swaps)
+		}
+		if !swaps[0].TokenIn.Mint.Equals(quoteMint) || swaps[0].TokenIn.Amount != 1_000_000_000 || swaps[0].TokenIn.Decimals != 9 {
+			t.Errorf("unexpected token in: %+v", swaps[0].TokenIn)
+		}
+		if !swaps[0].TokenOut.Mint.Equals(mint) || swaps[0].TokenOut.Amount != 35_000_000_000 || swaps[0].TokenOut.Decimals != 6 {
+			t.Errorf("unexpected token out: %+v", swaps[0].TokenOut)
+		}
+	})
+
+	t.Run("sell against SOL", func(t *testing.T) {
+		sell := testInstruction(boopIndex, append(boop.Sell[0][:], make([]byte, 16)...), boopAccounts...)
+		tx := newTestTransaction(keys, 1, sell)
+		meta := &rpc.TransactionMeta{
+			PreBalances:  []uint64{0, 0, 0, 0, 0, 5_000_000_000, 0, 1, 1},
+			PostBalances: []uint64{0, 0, 0, 0, 0, 4_700_000_000, 0, 1, 1},
+			PreTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(4, curve, mint, 800_000_000_000, 6),
+			},
+			PostTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(4, curve, mint, 810_000_000_000, 6),
+			},
+		}
+
+		swaps, err := NewLaunchpadParser(boop).ParseInstruction(sell, 0, newTestContext(tx, meta))
+		if err != nil {
+			t.Fatalf("failed to parse Boop sell: %v", err)
+		}
+		if swaps[0].Protocol != SwapTypeBoop || !swaps[0].TokenIn.Mint.Equals(mint) || swaps[0].TokenIn.Amount != 10_000_000_000 {
+			t.Errorf("unexpected token in: %+v", swaps[0].TokenIn)
+		}
+		if !swaps[0].TokenOut.Mint.Equals(NATIVE_SOL_PROGRAM_ID) || swaps[0].TokenOut.Amount != 300_000_000 {
+			t.Errorf("unexpected token out: %+v", swaps[0].TokenOut)
+		}
+	})
+
+	t.Run("rejects balances moving against the trade", func(t *testing.T) {
+		buy := testInstruction(boopIndex, boop.Buy[0][:], boopAccounts...)
+		tx := newTestTransaction(keys, 1, buy)
+		meta := &rpc.TransactionMeta{
+			PreTokenBalances:  []rpc.TokenBalance{testTokenBalance(4, curve, mint, 800_000_000_000, 6)},
+			PostTokenBalances: []rpc.TokenBalance{testTokenBalance(4, curve, mint, 810_000_000_000, 6)},
+		}
+		if _, err := NewLaunchpadParser(boop).ParseInstruction(buy, 0, newTestContext(tx, meta)); err == nil {
+			t.Error("expected a buy that fills the curve to fail")
+		}
+	})
+
+	t.Run("ignores other instructions", func(t *testing.T) {
+		parser := NewLaunchpadParser(boop)
+		if parser.CanHandle(testInstruction(boopIndex, heaven.Sell[0][:], boopAccounts...), keys) {
+			t.Error("expected unknown discriminators not to be handled")
+		}
+		if parser.CanHandle(testInstruction(heavenIndex, boop.Buy[0][:], boopAccounts...), keys) {
+			t.Error("expected other programs not to be handled")
+		}
+	})
+}
+
+func TestRegisterLaunchpad(t *testing.T) {
+	keys := append(newTestKeys(3), solana.NewWallet().PublicKey())
+	config := LaunchpadConfig{
+		Protocol:   "TestLaunchpad",
+		Program:    keys[3],
+		Buy:        [][8]byte{{1, 2, 3, 4, 5, 6, 7, 8}},
+		Accounts:   3,
+		TokenVault: 1,
+		QuoteVault: 2,
+	}
+	RegisterLaunchpad(config)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, config.Protocol)
+		registryMu.Unlock()
+	})
+
+	tx := newTestTransaction(keys, 1, testInstruction(3, config.Buy[0][:], 0, 1, 2))
+	parser := newTestParser(tx, &rpc.TransactionMeta{}, ParseOptions{Protocols: []SwapType{config.Protocol}})
+	if _, ok := parser.handlers[config.Protocol]; !ok {
+		t.Fatal("expected the registered launchpad to be parsed")
+	}
+}
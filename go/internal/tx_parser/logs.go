@@ -0,0 +1,121 @@
+package tx_parser
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ParseLogs splits log messages into the program invocations that wrote them. Outer
+// instructions are counted from the logs, so instructions that log nothing, such as
+// precompiles, shift the instruction indices; TransactionContext.ProgramLogs corrects
+// them against the transaction.
+func ParseLogs(logs []string) []*ProgramInvocation {
+	var invocations, stack []*ProgramInvocation
+	instructionIndex, innerIndex := -1, -1
+	for _, line := range logs {
+		if line == "Log truncated" {
+			break
+		}
+		var current *ProgramInvocation
+		if len(stack) > 0 {
+			current = stack[len(stack)-1]
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Program log: "):
+			if current != nil {
+				current.Logs = append(current.Logs, strings.TrimPrefix(line, "Program log: "))
+			}
+		case strings.HasPrefix(line, "Program data: "):
+			// sol_log_data writes each field as a separate base64 string
+			for _, field := range strings.Fields(strings.TrimPrefix(line, "Program data: ")) {
+				if data, err := base64.StdEncoding.DecodeString(field); err == nil && current != nil {
+					current.Data = append(current.Data, data)
+				}
+			}
+		case strings.HasPrefix(line, "Program return: "):
+			fields := strings.Fields(strings.TrimPrefix(line, "Program return: "))
+			if len(fields) == 2 && current != nil {
+				current.ReturnData, _ = base64.StdEncoding.DecodeString(fields[1])
+			}
+		case strings.HasPrefix(line, "Program "):
+			// Program consumption: and similar lines carry no program id
+			fields := strings.Fields(line)
+			if len(fields) < 3 || strings.HasSuffix(fields[1], ":") {
+				continue
+			}
+			switch {
+			case fields[2] == "invoke":
+				program, _ := solana.PublicKeyFromBase58(fields[1])
+				invocation := &ProgramInvocation{Program: program, StackHeight: len(stack) + 1, Status: InvocationIncomplete}
+				if current == nil {
+					instructionIndex, innerIndex = instructionIndex+1, -1
+					invocations = append(invocations, invocation)
+				} else {
+					innerIndex++
+					current.Invocations = append(current.Invocations, invocation)
+				}
+				invocation.InstructionIndex, invocation.InnerIndex = instructionIndex, innerIndex
+				stack = append(stack, invocation)
+			case fields[2] == "consumed" && len(fields) > 3 && current != nil:
+				current.ComputeUnits, _ = strconv.ParseUint(fields[3], 10, 64)
+			case fields[2] == "success" && current != nil:
+				current.Status = InvocationSuccess
+				stack = stack[:len(stack)-1]
+			case fields[2] == "failed:" && current != nil:
+				current.Status = InvocationFailed
+				current.Error = strings.Join(fields[3:], " ")
+				stack = stack[:len(stack)-1]
+			}
+		default:
+			// Native programs log without a prefix, e.g. the system program's
+			// "Transfer: insufficient lamports"
+			if current != nil {
+				current.Logs = append(current.Logs, line)
+			}
+		}
+	}
+	return invocations
+}
+
+// ProgramLogs returns the program invocations reconstructed from the transaction's logs,
+// with instruction indices matched against its outer instructions
+func (ctx *TransactionContext) ProgramLogs() []*ProgramInvocation {
+	if ctx.programLogs != nil {
+		return ctx.programLogs
+	}
+
+	ctx.programLogs = ParseLogs(ctx.Logs)
+	if ctx.programLogs == nil {
+		ctx.programLogs = []*ProgramInvocation{}
+	}
+	next := 0
+	for i, instruction := range ctx.Transaction.Message.Instructions {
+		if next == len(ctx.programLogs) {
+			break
+		}
+		if int(instruction.ProgramIDIndex) < len(ctx.AccountKeys) && ctx.AccountKeys[instruction.ProgramIDIndex].Equals(ctx.programLogs[next].Program) {
+			walkInvocations(ctx.programLogs[next:next+1], func(invocation *ProgramInvocation) {
+				invocation.InstructionIndex = i
+			})
+			next++
+		}
+	}
+	return ctx.programLogs
+}
+
+// ProgramLogs returns the program invocations reconstructed from the transaction's logs
+func (p *Parser) ProgramLogs() []*ProgramInvocation {
+	return p.ctx.ProgramLogs()
+}
+
+// walkInvocations calls fn for every invocation, callers before the programs they invoked
+func walkInvocations(invocations []*ProgramInvocation, fn func(invocation *ProgramInvocation)) {
+	for _, invocation := range invocations {
+		fn(invocation)
+		walkInvocations(invocation.Invocations, fn)
+	}
+}
@@ -0,0 +1,75 @@
+package tx_parser
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParseLogs(t *testing.T) {
+	keys := newTestKeys(3)
+	aggregator, token := keys[1].String(), keys[2].String()
+	logs := []string{
+		"Program " + aggregator + " invoke [1]",
+		"Program log: Instruction: Route",
+		"Program " + token + " invoke [2]",
+		"Program log: Instruction: Transfer",
+		"Program " + token + " consumed 4645 of 180000 compute units",
+		"Program " + token + " success",
+		"Program data: AQID BAU=",
+		"Program return: " + aggregator + " CQ==",
+		"Program " + aggregator + " consumed 24000 of 200000 compute units",
+		"Program " + aggregator + " success",
+		"Program 11111111111111111111111111111111 invoke [1]",
+		"Transfer: insufficient lamports 0, need 1",
+		"Program 11111111111111111111111111111111 failed: custom program error: 0x1",
+	}
+
+	invocations := ParseLogs(logs)
+	if len(invocations) != 2 {
+		t.Fatalf("expected 2 outer invocations, got %d", len(invocations))
+	}
+	route := invocations[0]
+	if route.Status != InvocationSuccess || route.ComputeUnits != 24_000 || len(route.Logs) != 1 || route.InnerIndex != -1 {
+		t.Errorf("unexpected outer invocation: %+v", route)
+	}
+	if len(route.Data) != 2 || !bytes.Equal(route.Data[1], []byte{4, 5}) || !bytes.Equal(route.ReturnData, []byte{9}) {
+		t.Errorf("expected the data fields and return data of the outer invocation, got %v, %v", route.Data, route.ReturnData)
+	}
+	if len(route.Invocations) != 1 {
+		t.Fatalf("expected one nested invocation, got %d", len(route.Invocations))
+	}
+	if transfer := route.Invocations[0]; transfer.Program.String() != token || transfer.StackHeight != 2 || transfer.InnerIndex != 0 ||
+		transfer.Logs[0] != "Instruction: Transfer" || transfer.ComputeUnits != 4645 {
+		t.Errorf("unexpected nested invocation: %+v", transfer)
+	}
+
+	failed := invocations[1]
+	if failed.Status != InvocationFailed || failed.Error != "custom program error: 0x1" || failed.InstructionIndex != 1 {
+		t.Errorf("expected the failure attributed to the second instruction, got %+v", failed)
+	}
+	if len(failed.Logs) != 1 || failed.Logs[0] != "Transfer: insufficient lamports 0, need 1" {
+		t.Errorf("expected unprefixed lines kept, got %v", failed.Logs)
+	}
+
+	truncated := ParseLogs(append(logs[:3:3], "Log truncated", "Program log: dropped"))
+	if truncated[0].Status != InvocationIncomplete || truncated[0].Invocations[0].Status != InvocationIncomplete || len(truncated[0].Logs) != 1 {
+		t.Errorf("expected the invocations to be incomplete after truncation, got %+v", truncated[0])
+	}
+}
+
+func TestProgramLogsInstructionIndex(t *testing.T) {
+	// A precompile logs nothing, so the first logged invocation is the second instruction
+	keys := newTestKeys(3)
+	tx := newTestTransaction(keys, 1, testInstruction(1, nil), testInstruction(2, nil))
+	ctx := newTestContext(tx, &rpc.TransactionMeta{LogMessages: []string{
+		"Program " + keys[2].String() + " invoke [1]",
+		"Program " + keys[2].String() + " success",
+	}})
+
+	invocations := ctx.ProgramLogs()
+	if len(invocations) != 1 || invocations[0].InstructionIndex != 1 {
+		t.Errorf("expected the invocation matched to the second instruction, got %+v", invocations)
+	}
+}
@@ -0,0 +1,48 @@
+package tx_parser
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ParseMemos returns every memo attached to the transaction, including memos written through CPI
+func ParseMemos(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*MemoInfo, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMemos(ctx), nil
+}
+
+// ParseMemos returns every memo attached to the parsed transaction
+func (p *Parser) ParseMemos() ([]*MemoInfo, error) {
+	return parseMemos(p.ctx), nil
+}
+
+func parseMemos(ctx *TransactionContext) []*MemoInfo {
+	var memos []*MemoInfo
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) {
+			return
+		}
+
+		programID := ctx.AccountKeys[instruction.ProgramIDIndex]
+		if !programID.Equals(MEMO_PROGRAM_ID) && !programID.Equals(MEMO_V1_PROGRAM_ID) {
+			return
+		}
+
+		memo := &MemoInfo{
+			Program:          programID,
+			InstructionIndex: instructionIndex,
+			InnerIndex:       innerIndex,
+			Text:             string(instruction.Data),
+		}
+		for _, idx := range instruction.Accounts {
+			memo.Signers = append(memo.Signers, ctx.AccountKeys[idx])
+		}
+		memos = append(memos, memo)
+	})
+
+	return memos
+}
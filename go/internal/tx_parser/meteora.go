@@ -7,15 +7,24 @@ import (
 	"github.com/gagliardetto/solana-go"
 )
 
-// MeteoraParser handles parsing Meteora protocol swaps
+// MeteoraParser handles parsing Meteora protocol swaps. The DLMM, Dynamic AMM and
+// Dynamic Bonding Curve programs all settle a swap with a pair of TransferChecked calls.
 type MeteoraParser struct {
+	protocol             SwapType
+	isProgram            func(programID solana.PublicKey) bool
 	seenInstructionPairs map[string]bool
 }
 
-// NewMeteoraParser creates a new Meteora parser instance
+// NewMeteoraParser creates a new parser for Meteora DLMM and Dynamic AMM v1 and v2 pools
 func NewMeteoraParser() *MeteoraParser {
+	return &MeteoraParser{protocol: SwapTypeMeteora, isProgram: isMeteoraProgramID}
+}
+
+// NewMeteoraDBCParser creates a new parser for the Meteora Dynamic Bonding Curve used by launchpads
+func NewMeteoraDBCParser() *MeteoraParser {
 	return &MeteoraParser{
-		seenInstructionPairs: make(map[string]bool),
+		protocol:  SwapTypeMeteoraDBC,
+		isProgram: func(programID solana.PublicKey) bool { return programID.Equals(METEORA_BONDING_CURVE_PROGRAM_ID) },
 	}
 }
 
@@ -23,8 +32,10 @@ var METEORA_SWAP_DISCRIMINATOR = []byte{0xf8, 0xc6, 0x9e, 0x91, 0xe1, 0x75, 0x87
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *MeteoraParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	programID := accountKeys[instruction.ProgramIDIndex]
-	return programID.Equals(METEORA_PROGRAM_ID) || programID.Equals(METEORA_POOLS_PROGRAM_ID)
+	if !hasValidIndices(instruction, accountKeys) {
+		return false
+	}
+	return p.isProgram(accountKeys[instruction.ProgramIDIndex])
 }
 
 // TransferCheckData represents a token transfer check instruction
@@ -67,6 +78,9 @@ func (p *MeteoraParser) ParseInstruction(instruction solana.CompiledInstruction,
 							transfers = nil
 							continue
 						}
+						if p.seenInstructionPairs == nil {
+							p.seenInstructionPairs = make(map[string]bool)
+						}
 						p.seenInstructionPairs[innerInstr.Data.String()+innerSet.Instructions[i-1].Data.String()] = true
 
 						swap, err := p.buildSwapInfo(transfers[0], transfers[1], ctx)
@@ -87,7 +101,7 @@ func (p *MeteoraParser) ParseInstruction(instruction solana.CompiledInstruction,
 	}
 
 	if len(swaps) == 0 {
-		return nil, fmt.Errorf("no valid Meteora swaps found")
+		return nil, fmt.Errorf("no valid %s swaps found", p.protocol)
 	}
 
 	return swaps, nil
@@ -95,7 +109,7 @@ func (p *MeteoraParser) ParseInstruction(instruction solana.CompiledInstruction,
 
 // isTransferChecked checks if the instruction is a token transfer check
 func isMeteoraTransferChecked(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	if len(instruction.Accounts) < 4 || len(instruction.Data) < 9 {
+	if len(instruction.Accounts) < 4 || len(instruction.Data) < 10 || !hasValidIndices(instruction, accountKeys) {
 		return false
 	}
 
@@ -123,7 +137,7 @@ func (p *MeteoraParser) parseTransferCheck(instruction solana.CompiledInstructio
 // buildSwapInfo creates the final SwapInfo from the transfer data
 func (p *MeteoraParser) buildSwapInfo(transfer1, transfer2 TransferCheckData, ctx *TransactionContext) (*SwapInfo, error) {
 	swapInfo := &SwapInfo{
-		Protocol: SwapTypeMeteora,
+		Protocol: p.protocol,
 		TokenIn: TokenInfo{
 			Mint:     transfer1.Mint,
 			Amount:   transfer1.Amount,
@@ -0,0 +1,58 @@
+package tx_parser
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestMeteoraDAMMV2AndDBCSwaps(t *testing.T) {
+	// 0 user, 1 user quote, 2 user base, 3 quote vault, 4 base vault, 5 quote mint, 6 base mint, 7 pool
+	keys := newTestKeys(8)
+	quoteMint, baseMint := keys[5], keys[6]
+	keys = append(keys, METEORA_DAMM_V2_PROGRAM_ID, METEORA_BONDING_CURVE_PROGRAM_ID, solana.TokenProgramID)
+	dammIndex, dbcIndex, tokenIndex := uint16(8), uint16(9), uint16(10)
+
+	tests := []struct {
+		name     string
+		program  uint16
+		protocol SwapType
+	}{
+		{"DAMM v2", dammIndex, SwapTypeMeteora},
+		{"DBC", dbcIndex, SwapTypeMeteoraDBC},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swap := testInstruction(tt.program, append(METEORA_SWAP_DISCRIMINATOR, make([]byte, 16)...), 7, 0, 1, 2, 3, 4, 5, 6)
+			tx := newTestTransaction(keys, 1, swap)
+			meta := &rpc.TransactionMeta{
+				InnerInstructions: []rpc.InnerInstruction{{
+					Index: 0,
+					Instructions: []solana.CompiledInstruction{
+						testInstruction(tokenIndex, tokenTransferCheckedData(2_000_000_000, 9), 1, 5, 3, 0),
+						testInstruction(tokenIndex, tokenTransferCheckedData(70_000_000, 6), 4, 6, 2, 7),
+					},
+				}},
+			}
+
+			swaps, err := newTestParser(tx, meta, ParseOptions{}).ParseTransaction()
+			if err != nil {
+				t.Fatalf("failed to parse swap: %v", err)
+			}
+			if len(swaps) != 1 || swaps[0].Protocol != tt.protocol {
+				t.Fatalf("expected one %s swap, got %+v", tt.protocol, swaps)
+			}
+			if !swaps[0].TokenIn.Mint.Equals(quoteMint) || swaps[0].TokenIn.Amount != 2_000_000_000 {
+				t.Errorf("unexpected token in: %+v", swaps[0].TokenIn)
+			}
+			if !swaps[0].TokenOut.Mint.Equals(baseMint) || swaps[0].TokenOut.Amount != 70_000_000 || swaps[0].TokenOut.Decimals != 6 {
+				t.Errorf("unexpected token out: %+v", swaps[0].TokenOut)
+			}
+		})
+	}
+
+	if NewMeteoraDBCParser().CanHandle(testInstruction(dammIndex, METEORA_SWAP_DISCRIMINATOR, 0), keys) {
+		t.Error("expected the DBC parser to ignore DAMM v2 instructions")
+	}
+}
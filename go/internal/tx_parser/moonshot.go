@@ -41,7 +41,7 @@ type MoonshotTradeData struct {
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *MoonshotParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	if !accountKeys[instruction.ProgramIDIndex].Equals(MOONSHOT_PROGRAM_ID) {
+	if !hasValidIndices(instruction, accountKeys) || !accountKeys[instruction.ProgramIDIndex].Equals(MOONSHOT_PROGRAM_ID) {
 		return false
 	}
 
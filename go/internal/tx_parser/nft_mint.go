@@ -0,0 +1,231 @@
+package tx_parser
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Metaplex Core instruction tags creating an asset
+const (
+	mplCoreCreateV1Instruction = 0
+	mplCoreCreateV2Instruction = 20
+)
+
+// Candy Machine mint instruction discriminators
+var (
+	CANDY_MACHINE_MINT_INSTRUCTION      = [8]byte{51, 57, 225, 47, 182, 146, 137, 166}
+	CANDY_MACHINE_MINT_V2_INSTRUCTION   = [8]byte{120, 121, 23, 146, 173, 110, 199, 205}
+	CORE_CANDY_MACHINE_MINT_INSTRUCTION = [8]byte{84, 175, 211, 156, 56, 250, 104, 118}
+)
+
+// candyMachineMint locates the accounts of a Candy Machine mint instruction. Candy Guard
+// mints reach the Candy Machine through a CPI, so only the Candy Machine is decoded.
+type candyMachineMint struct {
+	source        NftMintSource
+	program       solana.PublicKey
+	discriminator [8]byte
+	accounts      int // minimum number of accounts
+	payer         int
+	owner         int // -1 when the payer receives the NFT
+	mint          int
+	collection    int
+}
+
+var candyMachineMints = []candyMachineMint{
+	// mint: candy machine, authority pda, mint authority, payer, nft mint, ..., collection mint at 9
+	{source: NftMintCandyMachineV3, program: CANDY_MACHINE_V3_PROGRAM_ID, discriminator: CANDY_MACHINE_MINT_INSTRUCTION, accounts: 10, payer: 3, owner: -1, mint: 4, collection: 9},
+	// mint_v2: candy machine, authority pda, mint authority, payer, nft owner, nft mint, ..., collection mint at 12
+	{source: NftMintCandyMachineV3, program: CANDY_MACHINE_V3_PROGRAM_ID, discriminator: CANDY_MACHINE_MINT_V2_INSTRUCTION, accounts: 13, payer: 3, owner: 4, mint: 5, collection: 12},
+	// mint_asset: candy machine, authority pda, mint authority, payer, asset owner, asset, collection, ...
+	{source: NftMintCoreCandyMachine, program: CORE_CANDY_MACHINE_PROGRAM_ID, discriminator: CORE_CANDY_MACHINE_MINT_INSTRUCTION, accounts: 7, payer: 3, owner: 4, mint: 5, collection: 6},
+}
+
+// MplCoreCreateArgs is the leading part of the Metaplex Core CreateV1 and CreateV2 arguments
+type MplCoreCreateArgs struct {
+	DataState uint8
+	Name      string
+	URI       string
+}
+
+// ParseNftMints returns every Candy Machine v3 mint and Metaplex Core asset creation in
+// the transaction
+func ParseNftMints(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*NftMintEvent, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNftMints(ctx), nil
+}
+
+// ParseNftMints returns every Candy Machine v3 mint and Metaplex Core asset creation in
+// the parsed transaction
+func (p *Parser) ParseNftMints() ([]*NftMintEvent, error) {
+	return parseNftMints(p.ctx), nil
+}
+
+// parseNftMints decodes the mints, then prices each one. A Core asset minted by a Core
+// Candy Machine is reported once, as the Candy Machine mint.
+func parseNftMints(ctx *TransactionContext) []*NftMintEvent {
+	var mints, creates []*NftMintEvent
+	accounts := make(map[*NftMintEvent][]uint16)
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) {
+			return
+		}
+
+		programID := ctx.AccountKeys[instruction.ProgramIDIndex]
+		var event *NftMintEvent
+		if programID.Equals(MPL_CORE_PROGRAM_ID) {
+			event = decodeMplCoreCreate(instruction, ctx)
+		} else {
+			event = decodeCandyMachineMint(instruction, programID, ctx)
+		}
+		if event == nil {
+			return
+		}
+
+		event.Program = programID
+		event.InstructionIndex = instructionIndex
+		event.InnerIndex = innerIndex
+		accounts[event] = instruction.Accounts
+		if event.Source == NftMintMetaplexCore {
+			creates = append(creates, event)
+		} else {
+			mints = append(mints, event)
+		}
+	})
+
+	var events []*NftMintEvent
+	for _, create := range creates {
+		if minted := findNftMint(mints, create.Mint); minted != nil {
+			minted.Name, minted.URI = create.Name, create.URI
+			continue
+		}
+		events = append(events, create)
+	}
+	events = append(mints, events...)
+	slices.SortStableFunc(events, func(a, b *NftMintEvent) int {
+		return cmp.Or(cmp.Compare(a.InstructionIndex, b.InstructionIndex), cmp.Compare(a.InnerIndex, b.InnerIndex))
+	})
+
+	for i, event := range events {
+		event.Price = nftMintPrice(ctx, events, i, accounts[event])
+	}
+	return events
+}
+
+// decodeCandyMachineMint decodes a Candy Machine v3 or Core Candy Machine mint
+func decodeCandyMachineMint(instruction solana.CompiledInstruction, programID solana.PublicKey, ctx *TransactionContext) *NftMintEvent {
+	if len(instruction.Data) < 8 {
+		return nil
+	}
+
+	for _, layout := range candyMachineMints {
+		if !programID.Equals(layout.program) || !bytes.Equal(instruction.Data[:8], layout.discriminator[:]) || len(instruction.Accounts) < layout.accounts {
+			continue
+		}
+
+		account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+		event := &NftMintEvent{
+			Source:       layout.source,
+			CandyMachine: account(0),
+			Payer:        account(layout.payer),
+			Minter:       account(layout.payer),
+			Mint:         account(layout.mint),
+			Collection:   account(layout.collection),
+		}
+		if layout.owner >= 0 {
+			event.Minter = account(layout.owner)
+		}
+		return event
+	}
+	return nil
+}
+
+// decodeMplCoreCreate decodes a Metaplex Core asset creation. Core passes its own program
+// ID in place of optional accounts that are missing.
+func decodeMplCoreCreate(instruction solana.CompiledInstruction, ctx *TransactionContext) *NftMintEvent {
+	// asset, collection, authority, payer, owner, update authority, system program, ...
+	if len(instruction.Data) < 1 || len(instruction.Accounts) < 7 {
+		return nil
+	}
+	if tag := instruction.Data[0]; tag != mplCoreCreateV1Instruction && tag != mplCoreCreateV2Instruction {
+		return nil
+	}
+	account := func(n int) solana.PublicKey {
+		key := instructionAccount(instruction, n, ctx)
+		if key.Equals(MPL_CORE_PROGRAM_ID) {
+			return solana.PublicKey{}
+		}
+		return key
+	}
+
+	event := &NftMintEvent{
+		Source:     NftMintMetaplexCore,
+		Mint:       account(0),
+		Collection: account(1),
+		Payer:      account(3),
+		Minter:     account(4),
+	}
+	if event.Minter.IsZero() {
+		event.Minter = event.Payer
+	}
+
+	var args MplCoreCreateArgs
+	if err := ag_binary.NewBorshDecoder(instruction.Data[1:]).Decode(&args); err == nil {
+		event.Name, event.URI = args.Name, args.URI
+	}
+	return event
+}
+
+// findNftMint returns the mint event of the NFT or asset, or nil
+func findNftMint(events []*NftMintEvent, mint solana.PublicKey) *NftMintEvent {
+	for _, event := range events {
+		if event.Mint.Equals(mint) {
+			return event
+		}
+	}
+	return nil
+}
+
+// nftMintPrice sums the SOL the payer transfers around the mint, such as Candy Guard
+// payments made before the CPI into the Candy Machine. Transfers are taken from the
+// mint's top level instruction, between the neighbouring mints of that instruction, and
+// transfers to the mint instruction's own accounts are rent and protocol fees.
+func nftMintPrice(ctx *TransactionContext, events []*NftMintEvent, i int, mintAccounts []uint16) uint64 {
+	event := events[i]
+	from, to := -1, -1
+	if i > 0 && events[i-1].InstructionIndex == event.InstructionIndex {
+		from = events[i-1].InnerIndex
+	}
+	if i+1 < len(events) && events[i+1].InstructionIndex == event.InstructionIndex {
+		to = events[i+1].InnerIndex
+	}
+
+	var price uint64
+	for _, innerSet := range ctx.Meta.InnerInstructions {
+		if innerSet.Index != uint16(event.InstructionIndex) {
+			continue
+		}
+		for j, instruction := range innerSet.Instructions {
+			if j <= from || (to >= 0 && j >= to) || !hasValidIndices(instruction, ctx.AccountKeys) {
+				continue
+			}
+			if !ctx.AccountKeys[instruction.ProgramIDIndex].Equals(solana.SystemProgramID) {
+				continue
+			}
+			transfer := decodeSystemTransfer(instruction, ctx)
+			if transfer == nil || !transfer.Source.Equals(event.Payer) || slices.ContainsFunc(mintAccounts, func(index uint16) bool { return ctx.AccountKeys[index].Equals(transfer.Destination) }) {
+				continue
+			}
+			price += transfer.Amount
+		}
+	}
+	return price
+}
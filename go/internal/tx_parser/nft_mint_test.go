@@ -0,0 +1,77 @@
+package tx_parser
+
+import (
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParseNftMints(t *testing.T) {
+	// 0 payer, 1 minter, 2 candy machine, 3 nft mint, 4 metadata, 5 collection mint,
+	// 6 treasury, 7 core asset, 8 second core asset, 9 filler
+	keys := newTestKeys(10)
+	payer, minter, candyMachine, nftMint, collection, asset, directAsset := keys[0], keys[1], keys[2], keys[3], keys[5], keys[7], keys[8]
+	candyGuard := solana.NewWallet().PublicKey()
+	keys = append(keys, candyGuard, CANDY_MACHINE_V3_PROGRAM_ID, CORE_CANDY_MACHINE_PROGRAM_ID, MPL_CORE_PROGRAM_ID, solana.SystemProgramID)
+	guardIndex, candyMachineIndex, coreCandyMachineIndex, coreIndex, systemIndex := uint16(10), uint16(11), uint16(12), uint16(13), uint16(14)
+
+	createArgs, err := ag_binary.MarshalBorsh(MplCoreCreateArgs{Name: "Core #1", URI: "https://example.com/1.json"})
+	if err != nil {
+		t.Fatalf("failed to encode create args: %v", err)
+	}
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(guardIndex, []byte{1}, 2, 0, 1, 3),
+		testInstruction(coreCandyMachineIndex, CORE_CANDY_MACHINE_MINT_INSTRUCTION[:], 2, 9, 9, 0, 1, 7, 5, 13),
+		// direct create without an owner or collection
+		testInstruction(coreIndex, append([]byte{mplCoreCreateV2Instruction}, createArgs...), 8, 13, 13, 0, 13, 13, 14),
+	)
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{
+			{Index: 0, Instructions: []solana.CompiledInstruction{
+				testInstruction(systemIndex, systemTransferData(1_000_000_000), 0, 6), // SOL payment guard
+				// mint_v2: candy machine, authority pda, mint authority, payer, nft owner, nft mint, ..., collection mint
+				testInstruction(candyMachineIndex, CANDY_MACHINE_MINT_V2_INSTRUCTION[:], 2, 9, 9, 0, 1, 3, 9, 4, 9, 9, 9, 9, 5),
+				testInstruction(systemIndex, systemTransferData(10_000_000), 0, 4), // metadata fee
+			}},
+			{Index: 1, Instructions: []solana.CompiledInstruction{
+				testInstruction(systemIndex, systemTransferData(500_000_000), 0, 6),
+				testInstruction(coreIndex, append([]byte{mplCoreCreateV2Instruction}, createArgs...), 7, 5, 9, 0, 1, 9, 14),
+			}},
+		},
+	}
+
+	mints, err := ParseNftMints(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse NFT mints: %v", err)
+	}
+	if len(mints) != 3 {
+		t.Fatalf("expected 3 mints, got %d", len(mints))
+	}
+
+	guarded := mints[0]
+	if guarded.Source != NftMintCandyMachineV3 || guarded.InnerIndex != 1 || !guarded.CandyMachine.Equals(candyMachine) || !guarded.Mint.Equals(nftMint) {
+		t.Errorf("unexpected candy machine mint: %+v", guarded)
+	}
+	if !guarded.Minter.Equals(minter) || !guarded.Payer.Equals(payer) || !guarded.Collection.Equals(collection) || guarded.Price != 1_000_000_000 {
+		t.Errorf("expected the guard payment as the price, got %+v", guarded)
+	}
+
+	core := mints[1]
+	if core.Source != NftMintCoreCandyMachine || !core.Mint.Equals(asset) || !core.Minter.Equals(minter) || core.Price != 500_000_000 {
+		t.Errorf("unexpected core candy machine mint: %+v", core)
+	}
+	if core.Name != "Core #1" || core.URI != "https://example.com/1.json" {
+		t.Errorf("expected the asset metadata from the Core CPI, got %+v", core)
+	}
+
+	direct := mints[2]
+	if direct.Source != NftMintMetaplexCore || !direct.Mint.Equals(directAsset) || !direct.Minter.Equals(payer) || direct.InnerIndex != -1 {
+		t.Errorf("unexpected core creation: %+v", direct)
+	}
+	if !direct.Collection.IsZero() || !direct.CandyMachine.IsZero() || direct.Price != 0 {
+		t.Errorf("expected missing optional accounts to stay unset, got %+v", direct)
+	}
+}
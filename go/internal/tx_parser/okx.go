@@ -17,7 +17,7 @@ func NewOKXParser() *OKXParser {
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *OKXParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	return accountKeys[instruction.ProgramIDIndex].Equals(OKX_PROGRAM_ID)
+	return hasValidIndices(instruction, accountKeys) && accountKeys[instruction.ProgramIDIndex].Equals(OKX_PROGRAM_ID)
 }
 
 // ParseInstruction processes the OKX DEX instruction and returns swap information
@@ -70,7 +70,7 @@ func (p *OKXParser) ParseInstruction(instruction solana.CompiledInstruction, ins
 
 // isOKXTransfer checks if the instruction is a token transfer
 func isOKXTransfer(instr solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	if len(instr.Accounts) < 3 || len(instr.Data) < 9 {
+	if len(instr.Accounts) < 3 || len(instr.Data) < 9 || !hasValidIndices(instr, accountKeys) {
 		return false
 	}
 
@@ -84,7 +84,7 @@ func isOKXTransfer(instr solana.CompiledInstruction, accountKeys []solana.Public
 
 // isOKXTransferChecked checks if the instruction is a token transfer with amount check
 func isOKXTransferChecked(instr solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	if len(instr.Accounts) < 4 || len(instr.Data) < 9 {
+	if len(instr.Accounts) < 4 || len(instr.Data) < 9 || !hasValidIndices(instr, accountKeys) {
 		return false
 	}
 
@@ -109,7 +109,7 @@ func (p *OKXParser) processTransfer(instr solana.CompiledInstruction, ctx *Trans
 	destAcc := ctx.AccountKeys[instr.Accounts[1]]
 
 	// Find token mint from either source or destination account
-	mint := p.findTokenMint(sourceAcc, destAcc, ctx)
+	mint := ctx.findTokenMint(sourceAcc, destAcc)
 	if mint == (solana.PublicKey{}) {
 		return nil, fmt.Errorf("could not determine token mint")
 	}
@@ -137,21 +137,6 @@ func (p *OKXParser) processTransferChecked(instr solana.CompiledInstruction, ctx
 	}, nil
 }
 
-// findTokenMint looks up the mint for token accounts
-func (p *OKXParser) findTokenMint(source, dest solana.PublicKey, ctx *TransactionContext) solana.PublicKey {
-	// Check both pre and post token balances
-	balances := append(ctx.Meta.PreTokenBalances, ctx.Meta.PostTokenBalances...)
-
-	for _, balance := range balances {
-		accKey := ctx.AccountKeys[balance.AccountIndex]
-		if accKey.Equals(source) || accKey.Equals(dest) {
-			return balance.Mint
-		}
-	}
-
-	return solana.PublicKey{}
-}
-
 // buildSwapInfo creates a SwapInfo from a pair of transfers
 func (p *OKXParser) buildSwapInfo(transfer1, transfer2 TokenInfo, ctx *TransactionContext) (*SwapInfo, error) {
 	if transfer1.Mint.Equals(transfer2.Mint) {
@@ -163,7 +148,7 @@ func (p *OKXParser) buildSwapInfo(transfer1, transfer2 TokenInfo, ctx *Transacti
 	}
 
 	// Find input token (transferred from signer)
-	signers := ctx.Transaction.Message.Signers()
+	signers := ctx.signers()
 	found := false
 
 	// Check first transfer
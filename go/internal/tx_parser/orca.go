@@ -14,14 +14,12 @@ type OrcaParser struct {
 
 // NewOrcaParser creates a new Orca parser instance
 func NewOrcaParser() *OrcaParser {
-	return &OrcaParser{
-		seenInstructionPairs: make(map[string]bool),
-	}
+	return &OrcaParser{}
 }
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *OrcaParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	return accountKeys[instruction.ProgramIDIndex].Equals(ORCA_PROGRAM_ID)
+	return hasValidIndices(instruction, accountKeys) && accountKeys[instruction.ProgramIDIndex].Equals(ORCA_PROGRAM_ID)
 }
 
 // ParseInstruction processes the Orca instruction and returns swap information
@@ -55,6 +53,9 @@ func (p *OrcaParser) ParseInstruction(instruction solana.CompiledInstruction, in
 							currentTransfers = nil
 							continue
 						}
+						if p.seenInstructionPairs == nil {
+							p.seenInstructionPairs = make(map[string]bool)
+						}
 						p.seenInstructionPairs[innerInstr.Data.String()+innerSet.Instructions[i-1].Data.String()] = true
 
 						swap, err := p.buildSwapInfo(currentTransfers[0], currentTransfers[1], ctx)
@@ -83,7 +84,7 @@ func (p *OrcaParser) ParseInstruction(instruction solana.CompiledInstruction, in
 
 // isOrcaTransfer checks if the instruction is a token transfer
 func isOrcaTransfer(instr solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	if len(instr.Accounts) < 3 || len(instr.Data) < 9 {
+	if len(instr.Accounts) < 3 || len(instr.Data) < 9 || !hasValidIndices(instr, accountKeys) {
 		return false
 	}
 
@@ -108,7 +109,7 @@ func (p *OrcaParser) processTransfer(instr solana.CompiledInstruction, ctx *Tran
 	destAcc := ctx.AccountKeys[instr.Accounts[1]]
 
 	// Find token mint from either source or destination account
-	mint := p.findTokenMint(sourceAcc, destAcc, ctx)
+	mint := ctx.findTokenMint(sourceAcc, destAcc)
 	if mint == (solana.PublicKey{}) {
 		return nil, fmt.Errorf("could not determine token mint")
 	}
@@ -120,21 +121,6 @@ func (p *OrcaParser) processTransfer(instr solana.CompiledInstruction, ctx *Tran
 	}, nil
 }
 
-// findTokenMint looks up the mint for token accounts
-func (p *OrcaParser) findTokenMint(source, dest solana.PublicKey, ctx *TransactionContext) solana.PublicKey {
-	// Check both pre and post token balances
-	balances := append(ctx.Meta.PreTokenBalances, ctx.Meta.PostTokenBalances...)
-
-	for _, balance := range balances {
-		accKey := ctx.AccountKeys[balance.AccountIndex]
-		if accKey.Equals(source) || accKey.Equals(dest) {
-			return balance.Mint
-		}
-	}
-
-	return solana.PublicKey{}
-}
-
 // buildSwapInfo creates a SwapInfo from a pair of transfers
 func (p *OrcaParser) buildSwapInfo(transfer1, transfer2 TokenInfo, ctx *TransactionContext) (*SwapInfo, error) {
 	if transfer1.Mint.Equals(transfer2.Mint) {
@@ -146,7 +132,7 @@ func (p *OrcaParser) buildSwapInfo(transfer1, transfer2 TokenInfo, ctx *Transact
 	}
 
 	// Find input token (transferred from signer)
-	signers := ctx.Transaction.Message.Signers()
+	signers := ctx.signers()
 	found := false
 
 	// Check first transfer
@@ -1,47 +1,60 @@
 package tx_parser
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/logging"
 )
 
 // Parser is the main transaction parser
 type Parser struct {
 	ctx      *TransactionContext
 	handlers map[SwapType]SwapParser
+	opts     ParseOptions
 }
 
-// New creates a new transaction parser
+// New creates a new transaction parser in lenient mode
 func New(txResult *rpc.GetTransactionResult) (*Parser, error) {
-	tx, err := txResult.Transaction.GetTransaction()
+	return NewWithOptions(txResult, ParseOptions{})
+}
+
+// NewWithOptions creates a new transaction parser with the given options
+func NewWithOptions(txResult *rpc.GetTransactionResult, opts ParseOptions) (*Parser, error) {
+	ctx, err := NewTransactionContextFromRPC(txResult)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction: %w", err)
+		return nil, err
 	}
 
-	// Combine all account keys
-	allKeys := append(tx.Message.AccountKeys, txResult.Meta.LoadedAddresses.Writable...)
-	allKeys = append(allKeys, txResult.Meta.LoadedAddresses.ReadOnly...)
+	return NewFromContext(ctx, opts), nil
+}
 
-	ctx := &TransactionContext{
-		Transaction: tx,
-		Meta:        txResult.Meta,
-		AccountKeys: allKeys,
+// NewFromContext creates a new transaction parser over an already built context
+func NewFromContext(ctx *TransactionContext, opts ParseOptions) *Parser {
+	if opts.DecimalsResolver != nil {
+		ctx.DecimalsResolver = opts.DecimalsResolver
 	}
-
-	if err := ctx.ExtractMintDecimals(); err != nil {
-		return nil, fmt.Errorf("failed to extract mint decimals: %w", err)
+	if opts.TokenAccountResolver != nil {
+		ctx.TokenAccountResolver = opts.TokenAccountResolver
 	}
+	if opts.TokenExtensionsResolver != nil {
+		ctx.TokenExtensionsResolver = opts.TokenExtensionsResolver
+	}
+	opts.Logger = logging.OrNop(opts.Logger)
 
 	parser := &Parser{
 		ctx:      ctx,
 		handlers: make(map[SwapType]SwapParser),
+		opts:     opts,
 	}
 
 	// Register protocol parsers
 	parser.registerHandlers()
 
-	return parser, nil
+	return parser
 }
 
 // registerHandlers initializes all protocol-specific parsers
@@ -52,82 +65,222 @@ func (p *Parser) registerHandlers() {
 	p.handlers[SwapTypeRaydium] = NewRaydiumParser()
 	p.handlers[SwapTypeOrca] = NewOrcaParser()
 	p.handlers[SwapTypeMeteora] = NewMeteoraParser()
+	p.handlers[SwapTypeMeteoraDBC] = NewMeteoraDBCParser()
 	p.handlers[SwapTypeMoonshot] = NewMoonshotParser()
 	p.handlers[SwapTypeOKX] = NewOKXParser()
+	p.handlers[SwapTypeRaydiumLaunchLab] = NewRaydiumLaunchLabParser()
+	p.handlers[SwapTypeSaber] = NewSaberParser()
+	p.handlers[SwapTypeMercurial] = NewMercurialParser()
+	p.handlers[SwapTypeInvariant] = NewInvariantParser()
+	p.handlers[SwapTypeCrema] = NewCremaParser()
+	p.handlers[SwapTypeFluxBeam] = NewFluxBeamParser()
+	p.handlers[SwapTypeGooseFX] = NewGooseFXParser()
+	p.handlers[SwapTypeStabble] = NewStabbleParser()
+	for _, launchpad := range LAUNCHPADS {
+		p.handlers[launchpad.Protocol] = NewLaunchpadParser(launchpad)
+	}
+
+	// Add parsers registered by external packages
+	for swapType, factory := range registeredFactories() {
+		p.handlers[swapType] = factory()
+	}
+
+	// Restrict to the requested protocols
+	if len(p.opts.Protocols) > 0 {
+		enabled := make(map[SwapType]bool, len(p.opts.Protocols))
+		for _, protocol := range p.opts.Protocols {
+			enabled[protocol] = true
+		}
+		for swapType := range p.handlers {
+			if !enabled[swapType] {
+				delete(p.handlers, swapType)
+			}
+		}
+	}
+}
+
+// Parse runs every extractor over the transaction and returns the combined result.
+// Unlike ParseTransaction, a transaction without swaps is not an error. Instructions
+// that a swap handler failed on are reported in Errors, or fail the call in strict mode.
+func (p *Parser) Parse() (*ParsedTransaction, error) {
+	swaps, parseErrors, err := p.ParseSwaps()
+	if err != nil {
+		return nil, err
+	}
+
+	supplyEvents, adminEvents := parseTokenEvents(p.ctx)
+	parsed := &ParsedTransaction{
+		Slot:           p.ctx.Slot,
+		Fee:            p.ctx.Meta.Fee,
+		BlockTime:      p.ctx.BlockTime,
+		Swaps:          swaps,
+		Transfers:      parseTransfers(p.ctx),
+		StakeEvents:    parseStakeEvents(p.ctx),
+		SupplyEvents:   supplyEvents,
+		AdminEvents:    adminEvents,
+		ComputeBudget:  parseComputeBudget(p.ctx),
+		Memos:          parseMemos(p.ctx),
+		PoolCreations:  parsePoolCreations(p.ctx),
+		PerpFills:      parsePerpFills(p.ctx),
+		CompressedNfts: parseCompressedNftEvents(p.ctx),
+		NftMints:       parseNftMints(p.ctx),
+		DomainEvents:   parseDomainEvents(p.ctx),
+		Bridges:        parseBridgeEvents(p.ctx),
+		CallTree:       p.ctx.CallTree(),
+		Errors:         parseErrors,
+	}
+	if len(p.ctx.Transaction.Signatures) > 0 {
+		parsed.Signature = p.ctx.Transaction.Signatures[0]
+	}
+	if len(p.ctx.AccountKeys) > 0 {
+		parsed.FeePayer = p.ctx.AccountKeys[0]
+	}
+	parsed.JitoTip = JitoTip(parsed.Transfers)
+	if p.opts.AnalyzeFailures {
+		parsed.Failure = analyzeFailure(p.ctx, p.opts.ProgramErrors)
+	}
+
+	return parsed, nil
 }
 
-// ParseTransaction parses the transaction and returns all swap information
+// ParseTransaction parses the transaction and returns all swap information.
+// It fails if no swap could be parsed; use ParseSwaps for partial results.
 func (p *Parser) ParseTransaction() ([]*SwapInfo, error) {
+	swaps, parseErrors, err := p.ParseSwaps()
+	if err != nil {
+		return nil, err
+	}
+	if len(swaps) == 0 {
+		if len(parseErrors) > 0 {
+			return nil, fmt.Errorf("no valid swaps found in transaction: %w", parseErrors[0])
+		}
+		return nil, fmt.Errorf("no valid swaps found in transaction")
+	}
+
+	return swaps, nil
+}
+
+// ParseSwaps returns every swap that could be parsed along with a ParseError for each
+// instruction a handler claimed but failed to parse. In strict mode the first ParseError
+// is returned as the error instead.
+func (p *Parser) ParseSwaps() ([]*SwapInfo, []*ParseError, error) {
 	var allSwaps []*SwapInfo
+	var parseErrors []*ParseError
 
 	// Process each outer instruction in the transaction
 	for i, instruction := range p.ctx.Transaction.Message.Instructions {
 		// Try each parser for outer instruction
-		for _, handler := range p.handlers {
-			if handler.CanHandle(instruction, p.ctx.AccountKeys) {
-				swaps, err := handler.ParseInstruction(instruction, i, p.ctx)
-				if err != nil {
-					continue
-				}
-				for _, swap := range swaps {
-					swap.Signers = p.ctx.Transaction.Message.Signers()
-					swap.Signatures = p.ctx.Transaction.Signatures
-				}
-				allSwaps = append(allSwaps, swaps...)
-				break // Found matching handler, no need to try others
-			}
-		}
+		swaps, errs := p.parseInstruction(instruction, i, -1)
+		allSwaps = append(allSwaps, swaps...)
+		parseErrors = append(parseErrors, errs...)
 
 		if len(allSwaps) > 0 {
 			break
 		}
 
 		// Check inner instructions
-		innerSwaps, err := p.parseInnerInstructions(i)
-		if err != nil {
-			continue
+		for _, innerSet := range p.ctx.Meta.InnerInstructions {
+			if innerSet.Index != uint16(i) {
+				continue
+			}
+			for j, innerInstr := range innerSet.Instructions {
+				swaps, errs := p.parseInstruction(innerInstr, i, j)
+				allSwaps = append(allSwaps, swaps...)
+				parseErrors = append(parseErrors, errs...)
+			}
 		}
-		allSwaps = append(allSwaps, innerSwaps...)
 	}
 
-	if len(allSwaps) == 0 {
-		return nil, fmt.Errorf("no valid swaps found in transaction")
+	if p.opts.Strict && len(parseErrors) > 0 {
+		return nil, parseErrors, parseErrors[0]
 	}
 
 	// Remove duplicate swap sets
-	allSwaps = p.removeDuplicateSwapSets(allSwaps)
-
-	return allSwaps, nil
+	swaps := p.removeDuplicateSwapSets(allSwaps)
+	attachTokenExtensions(p.ctx, swaps)
+	return swaps, parseErrors, nil
 }
 
-// parseInnerInstructions processes inner instructions for a given outer instruction index
-func (p *Parser) parseInnerInstructions(index int) ([]*SwapInfo, error) {
-	var swaps []*SwapInfo
-
-	// Find inner instructions for this index
-	for _, innerSet := range p.ctx.Meta.InnerInstructions {
-		if innerSet.Index == uint16(index) {
-			// Try each inner instruction with each parser
-			for _, innerInstr := range innerSet.Instructions {
-				for _, handler := range p.handlers {
-					if handler.CanHandle(innerInstr, p.ctx.AccountKeys) {
-						innerSwaps, err := handler.ParseInstruction(innerInstr, index, p.ctx)
-						if err != nil {
-							continue
-						}
-						for _, swap := range innerSwaps {
-							swap.Signers = p.ctx.Transaction.Message.Signers()
-							swap.Signatures = p.ctx.Transaction.Signatures
-						}
-						swaps = append(swaps, innerSwaps...)
-						break // Found matching handler, no need to try others
-					}
-				}
+// parseInstruction runs the first handler that parses the instruction successfully.
+// Errors are only reported if every handler that claimed the instruction failed.
+func (p *Parser) parseInstruction(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) ([]*SwapInfo, []*ParseError) {
+	var parseErrors []*ParseError
+
+	for swapType, handler := range p.handlers {
+		swaps, err := p.runHandler(swapType, handler, instruction, instructionIndex, innerIndex)
+		if err == nil && swaps == nil {
+			continue
+		}
+		if err != nil {
+			parseError := &ParseError{
+				Protocol:         swapType,
+				InstructionIndex: instructionIndex,
+				InnerIndex:       innerIndex,
+				Err:              err,
 			}
+			if int(instruction.ProgramIDIndex) < len(p.ctx.AccountKeys) {
+				parseError.Program = p.ctx.AccountKeys[instruction.ProgramIDIndex]
+			}
+			parseErrors = append(parseErrors, parseError)
+			p.logHandlerError(swapType, instructionIndex, innerIndex, err)
+			continue
 		}
+
+		for _, swap := range swaps {
+			swap.Signers = p.ctx.signers()
+			swap.Signatures = p.ctx.Transaction.Signatures
+			swap.InstructionIndex = instructionIndex
+			swap.ComputeUnits = p.ctx.ComputeUnits(instructionIndex, innerIndex)
+		}
+		return swaps, nil // Found matching handler, no need to try others
 	}
 
-	return swaps, nil
+	return nil, parseErrors
+}
+
+// runHandler calls the handler if it claims the instruction, converting panics on
+// malformed instruction data into errors. It returns nil, nil if the handler does not apply.
+func (p *Parser) runHandler(swapType SwapType, handler SwapParser, instruction solana.CompiledInstruction, instructionIndex, innerIndex int) (swaps []*SwapInfo, err error) {
+	// deferred first so the hook sees panics already converted into errors
+	var done func([]*SwapInfo, error)
+	defer func() {
+		if done != nil {
+			done(swaps, err)
+		}
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			swaps, err = nil, fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	if !handler.CanHandle(instruction, p.ctx.AccountKeys) {
+		return nil, nil
+	}
+	if p.opts.Hook != nil {
+		done = p.opts.Hook.HandlerStarted(swapType, instructionIndex, innerIndex)
+	}
+
+	swaps, err = handler.ParseInstruction(instruction, instructionIndex, p.ctx)
+	if err == nil && swaps == nil {
+		swaps = []*SwapInfo{}
+	}
+	return swaps, err
+}
+
+// logHandlerError logs an instruction a handler claimed but failed to parse
+func (p *Parser) logHandlerError(swapType SwapType, instructionIndex, innerIndex int, err error) {
+	args := []any{
+		logging.SLOT_KEY, p.ctx.Slot,
+		logging.PARSER_KEY, string(swapType),
+		logging.INSTRUCTION_INDEX_KEY, instructionIndex,
+		logging.INNER_INDEX_KEY, innerIndex,
+		logging.ERROR_KEY, err,
+	}
+	if len(p.ctx.Transaction.Signatures) > 0 {
+		args = append(args, logging.SIGNATURE_KEY, p.ctx.Transaction.Signatures[0].String())
+	}
+	p.opts.Logger.DebugContext(context.Background(), "failed to parse instruction", args...)
 }
 
 // removeDuplicateSwapSets removes consecutive sets of swaps that have matching token pairs and amounts
@@ -193,3 +346,20 @@ func (p *Parser) removeDuplicateSwapSets(swaps []*SwapInfo) []*SwapInfo {
 
 	return result
 }
+
+// forEachInstruction calls fn for every outer instruction followed by its inner instructions,
+// in execution order. innerIndex is -1 for outer instructions.
+func forEachInstruction(ctx *TransactionContext, fn func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int)) {
+	for i, instruction := range ctx.Transaction.Message.Instructions {
+		fn(instruction, i, -1)
+
+		for _, innerSet := range ctx.Meta.InnerInstructions {
+			if innerSet.Index != uint16(i) {
+				continue
+			}
+			for j, innerInstr := range innerSet.Instructions {
+				fn(innerInstr, i, j)
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package tx_parser
+
+import (
+	"bytes"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Jupiter Perps events, emitted through a self CPI after the Anchor event tag
+var (
+	JUPITER_PERPS_INCREASE_POSITION_EVENT_DISCRIMINATOR  = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 245, 113, 85, 52, 214, 187, 153, 132}
+	JUPITER_PERPS_DECREASE_POSITION_EVENT_DISCRIMINATOR  = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 64, 156, 43, 74, 109, 131, 16, 127}
+	JUPITER_PERPS_LIQUIDATE_POSITION_EVENT_DISCRIMINATOR = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 128, 101, 71, 168, 128, 72, 86, 84}
+)
+
+// Zeta events, emitted as program data logs
+var (
+	ZETA_TRADE_EVENT_DISCRIMINATOR       = [8]byte{114, 162, 59, 33, 84, 134, 108, 62}
+	ZETA_LIQUIDATION_EVENT_DISCRIMINATOR = [8]byte{3, 13, 21, 93, 173, 136, 72, 144}
+)
+
+// JUPITER_PERPS_CUSTODIES names the assets of the Jupiter Perps custodies positions are opened on
+var JUPITER_PERPS_CUSTODIES = map[solana.PublicKey]string{
+	solana.MustPublicKeyFromBase58("7xS2gz2bTp3fwCC7knJvUWTEU9Tycczu6VhJYKgi1wdz"): "SOL",
+	solana.MustPublicKeyFromBase58("AQCGyheWPLeo6Qp9WpYS9m3Qj479t7R636N9ey1rEjEn"): "ETH",
+	solana.MustPublicKeyFromBase58("5Pv3gM9JrFFH883SWAhvJC9RPYmo8UNxuFtv5bMMALkm"): "BTC",
+}
+
+// zetaAssets are the names of Zeta's asset enum values
+var zetaAssets = []string{"SOL", "BTC", "ETH", "APT", "ARB", "BERA", "PYTH", "TIA", "JTO", "ONEMBONK", "SEI", "JUP", "DYM", "STRK", "WIF"}
+
+// Jupiter Perps position sides
+const (
+	jupiterPerpsSideLong  = 1
+	jupiterPerpsSideShort = 2
+)
+
+// JupiterPerpsPositionEvent is the leading part shared by the increase and decrease
+// position events, followed by the fields of the increase or decrease
+type JupiterPerpsPositionEvent struct {
+	PositionKey               solana.PublicKey
+	PositionSide              uint8
+	PositionCustody           solana.PublicKey
+	PositionCollateralCustody solana.PublicKey
+	PositionSizeUsd           uint64
+	PositionMint              solana.PublicKey
+	PositionRequestKey        solana.PublicKey
+	PositionRequestMint       solana.PublicKey
+	Owner                     solana.PublicKey
+	Pool                      solana.PublicKey
+	SizeUsdDelta              uint64
+	CollateralUsdDelta        uint64
+	CollateralTokenDelta      uint64
+	Price                     uint64
+	PriceSlippage             *uint64 `bin:"optional"`
+}
+
+// JupiterPerpsIncreaseFees follows the leading part of an increase position event
+type JupiterPerpsIncreaseFees struct {
+	FeeToken uint64
+	FeeUsd   uint64
+}
+
+// JupiterPerpsDecreaseSettlement follows the leading part of a decrease position event
+type JupiterPerpsDecreaseSettlement struct {
+	HasProfit         bool
+	PnlDelta          uint64
+	TransferAmountUsd uint64
+	TransferToken     uint64
+	FeeUsd            uint64
+}
+
+// JupiterPerpsLiquidationEvent is the leading part of the event of a full liquidation
+type JupiterPerpsLiquidationEvent struct {
+	PositionKey               solana.PublicKey
+	PositionSide              uint8
+	PositionCustody           solana.PublicKey
+	PositionCollateralCustody solana.PublicKey
+	PositionCollateralMint    solana.PublicKey
+	PositionMint              solana.PublicKey
+	PositionSizeUsd           uint64
+	HasProfit                 bool
+	PnlDelta                  uint64
+	TransferAmountUsd         uint64
+	TransferToken             uint64
+	Price                     uint64
+	FeeUsd                    uint64
+	LiquidationFeeUsd         uint64
+}
+
+// ZetaTradeEvent is the leading part of Zeta's TradeEventV3
+type ZetaTradeEvent struct {
+	MarginAccount  solana.PublicKey
+	Index          uint8
+	Size           uint64
+	CostOfTrades   uint64
+	IsBid          bool
+	ClientOrderID  uint64
+	OrderID        ag_binary.Uint128
+	Asset          uint8
+	User           solana.PublicKey
+	IsTaker        bool
+	SequenceNumber uint64
+	Fee            uint64
+	Price          uint64
+}
+
+// ZetaLiquidationEvent is the leading part of Zeta's LiquidationEvent
+type ZetaLiquidationEvent struct {
+	LiquidatorReward           uint64
+	InsuranceReward            uint64
+	CostOfTrades               uint64
+	Size                       int64 // positive when a long position was liquidated
+	RemainingLiquidateeBalance uint64
+	RemainingLiquidatorBalance uint64
+	MarkPrice                  uint64
+	UnderlyingPrice            uint64
+	Liquidatee                 solana.PublicKey
+	Liquidator                 solana.PublicKey
+	Asset                      uint8
+}
+
+// ParsePerpFills returns every Zeta and Jupiter Perps fill in the transaction
+func ParsePerpFills(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*PerpFillInfo, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePerpFills(ctx), nil
+}
+
+// ParsePerpFills returns every Zeta and Jupiter Perps fill in the parsed transaction
+func (p *Parser) ParsePerpFills() ([]*PerpFillInfo, error) {
+	return parsePerpFills(p.ctx), nil
+}
+
+func parsePerpFills(ctx *TransactionContext) []*PerpFillInfo {
+	var fills []*PerpFillInfo
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if innerIndex < 0 || !hasValidIndices(instruction, ctx.AccountKeys) || !ctx.AccountKeys[instruction.ProgramIDIndex].Equals(JUPITER_PERPS_PROGRAM_ID) {
+			return
+		}
+		if fill := decodeJupiterPerpsEvent(instruction.Data); fill != nil {
+			fill.InstructionIndex = instructionIndex
+			fill.InnerIndex = innerIndex
+			fills = append(fills, fill)
+		}
+	})
+
+	walkInvocations(ctx.ProgramLogs(), func(invocation *ProgramInvocation) {
+		if !invocation.Program.Equals(ZETA_PROGRAM_ID) {
+			return
+		}
+		for _, data := range invocation.Data {
+			if fill := decodeZetaEvent(data); fill != nil {
+				fill.InstructionIndex = invocation.InstructionIndex
+				fill.InnerIndex = invocation.InnerIndex
+				fills = append(fills, fill)
+			}
+		}
+	})
+
+	return fills
+}
+
+// decodeJupiterPerpsEvent decodes the position events Jupiter Perps emits when a keeper
+// executes a position request or a position is liquidated
+func decodeJupiterPerpsEvent(data []byte) *PerpFillInfo {
+	if len(data) < 16 {
+		return nil
+	}
+
+	fill := &PerpFillInfo{Venue: PerpVenueJupiterPerps, Program: JUPITER_PERPS_PROGRAM_ID}
+	var side uint8
+	switch discriminator := data[:16]; {
+	case bytes.Equal(discriminator, JUPITER_PERPS_INCREASE_POSITION_EVENT_DISCRIMINATOR[:]),
+		bytes.Equal(discriminator, JUPITER_PERPS_DECREASE_POSITION_EVENT_DISCRIMINATOR[:]):
+		decoder := ag_binary.NewBorshDecoder(data[16:])
+		var event JupiterPerpsPositionEvent
+		if err := decoder.Decode(&event); err != nil {
+			return nil
+		}
+		if bytes.Equal(discriminator, JUPITER_PERPS_INCREASE_POSITION_EVENT_DISCRIMINATOR[:]) {
+			var fees JupiterPerpsIncreaseFees
+			if err := decoder.Decode(&fees); err != nil {
+				return nil
+			}
+			fill.Type, fill.Fee = PerpFillOpen, fees.FeeUsd
+		} else {
+			var settlement JupiterPerpsDecreaseSettlement
+			if err := decoder.Decode(&settlement); err != nil {
+				return nil
+			}
+			fill.Type, fill.Fee = PerpFillClose, settlement.FeeUsd
+		}
+		fill.Trader, fill.Account, fill.Market = event.Owner, event.PositionKey, event.PositionCustody
+		fill.Size, fill.Price = event.SizeUsdDelta, event.Price
+		side = event.PositionSide
+	case bytes.Equal(discriminator, JUPITER_PERPS_LIQUIDATE_POSITION_EVENT_DISCRIMINATOR[:]):
+		var event JupiterPerpsLiquidationEvent
+		if err := ag_binary.NewBorshDecoder(data[16:]).Decode(&event); err != nil {
+			return nil
+		}
+		fill.Type = PerpFillLiquidation
+		fill.Account, fill.Market = event.PositionKey, event.PositionCustody
+		fill.Size, fill.Price, fill.Fee = event.PositionSizeUsd, event.Price, event.FeeUsd+event.LiquidationFeeUsd
+		side = event.PositionSide
+	default:
+		return nil
+	}
+
+	switch side {
+	case jupiterPerpsSideLong:
+		fill.Side = PerpSideLong
+	case jupiterPerpsSideShort:
+		fill.Side = PerpSideShort
+	default:
+		return nil
+	}
+	fill.Asset = JUPITER_PERPS_CUSTODIES[fill.Market]
+	return fill
+}
+
+// decodeZetaEvent decodes Zeta trade and liquidation events
+func decodeZetaEvent(data []byte) *PerpFillInfo {
+	if len(data) < 8 {
+		return nil
+	}
+
+	fill := &PerpFillInfo{Venue: PerpVenueZeta, Program: ZETA_PROGRAM_ID}
+	var asset uint8
+	switch {
+	case bytes.Equal(data[:8], ZETA_TRADE_EVENT_DISCRIMINATOR[:]):
+		var event ZetaTradeEvent
+		if err := ag_binary.NewBorshDecoder(data[8:]).Decode(&event); err != nil {
+			return nil
+		}
+		fill.Type = PerpFillTrade
+		fill.Trader, fill.Account = event.User, event.MarginAccount
+		fill.Size, fill.Price, fill.Fee = event.Size, event.Price, event.Fee
+		fill.Side = PerpSideShort
+		if event.IsBid {
+			fill.Side = PerpSideLong
+		}
+		asset = event.Asset
+	case bytes.Equal(data[:8], ZETA_LIQUIDATION_EVENT_DISCRIMINATOR[:]):
+		var event ZetaLiquidationEvent
+		if err := ag_binary.NewBorshDecoder(data[8:]).Decode(&event); err != nil {
+			return nil
+		}
+		fill.Type = PerpFillLiquidation
+		fill.Trader, fill.Liquidator = event.Liquidatee, event.Liquidator
+		fill.Size, fill.Price = uint64(abs(event.Size)), event.MarkPrice
+		fill.Side = PerpSideLong
+		if event.Size < 0 {
+			fill.Side = PerpSideShort
+		}
+		asset = event.Asset
+	default:
+		return nil
+	}
+
+	if int(asset) < len(zetaAssets) {
+		fill.Asset = zetaAssets[asset]
+	}
+	return fill
+}
@@ -0,0 +1,134 @@
+package tx_parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// perpEventData borsh encodes the event values after the discriminator
+func perpEventData(t *testing.T, discriminator []byte, values ...any) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.Write(discriminator)
+	encoder := ag_binary.NewBorshEncoder(&buf)
+	for _, value := range values {
+		if err := encoder.Encode(value); err != nil {
+			t.Fatalf("failed to encode event: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestParsePerpFillsJupiterPerps(t *testing.T) {
+	keys := newTestKeys(3)
+	owner, position := keys[0], keys[1]
+	custody := solana.MustPublicKeyFromBase58("7xS2gz2bTp3fwCC7knJvUWTEU9Tycczu6VhJYKgi1wdz")
+	keys = append(keys, JUPITER_PERPS_PROGRAM_ID)
+	perpsIndex := uint16(3)
+
+	slippage := uint64(100)
+	increase := JupiterPerpsPositionEvent{PositionKey: position, PositionSide: jupiterPerpsSideLong, PositionCustody: custody,
+		Owner: owner, SizeUsdDelta: 5_000_000_000, Price: 150_000_000, PriceSlippage: &slippage}
+	decrease := increase
+	decrease.PositionSide, decrease.SizeUsdDelta, decrease.PriceSlippage = jupiterPerpsSideShort, 2_000_000_000, nil
+	liquidation := JupiterPerpsLiquidationEvent{PositionKey: position, PositionSide: jupiterPerpsSideLong, PositionCustody: keys[2],
+		PositionSizeUsd: 3_000_000_000, Price: 120_000_000, FeeUsd: 3_000_000, LiquidationFeeUsd: 1_000_000}
+
+	tx := newTestTransaction(keys, 1, testInstruction(perpsIndex, []byte{1}, 0, 1))
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{{
+			Index: 0,
+			Instructions: []solana.CompiledInstruction{
+				testInstruction(perpsIndex, perpEventData(t, JUPITER_PERPS_INCREASE_POSITION_EVENT_DISCRIMINATOR[:], increase, JupiterPerpsIncreaseFees{FeeUsd: 3_500_000}), 2),
+				testInstruction(perpsIndex, perpEventData(t, JUPITER_PERPS_DECREASE_POSITION_EVENT_DISCRIMINATOR[:], decrease, JupiterPerpsDecreaseSettlement{FeeUsd: 1_400_000}), 2),
+				testInstruction(perpsIndex, perpEventData(t, JUPITER_PERPS_LIQUIDATE_POSITION_EVENT_DISCRIMINATOR[:], liquidation), 2),
+				testInstruction(perpsIndex, JUPITER_PERPS_INCREASE_POSITION_EVENT_DISCRIMINATOR[:], 2),
+			},
+		}},
+	}
+
+	fills, err := ParsePerpFills(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse perp fills: %v", err)
+	}
+	if len(fills) != 3 {
+		t.Fatalf("expected 3 fills, got %d", len(fills))
+	}
+
+	open := fills[0]
+	if open.Type != PerpFillOpen || open.Venue != PerpVenueJupiterPerps || open.Side != PerpSideLong || open.Asset != "SOL" || open.InnerIndex != 0 {
+		t.Errorf("unexpected open: %+v", open)
+	}
+	if !open.Trader.Equals(owner) || !open.Account.Equals(position) || open.Size != 5_000_000_000 || open.Price != 150_000_000 || open.Fee != 3_500_000 {
+		t.Errorf("unexpected open amounts: %+v", open)
+	}
+
+	closed := fills[1]
+	if closed.Type != PerpFillClose || closed.Side != PerpSideShort || closed.Size != 2_000_000_000 || closed.Fee != 1_400_000 {
+		t.Errorf("unexpected close: %+v", closed)
+	}
+
+	liquidated := fills[2]
+	if liquidated.Type != PerpFillLiquidation || liquidated.Size != 3_000_000_000 || liquidated.Fee != 4_000_000 || liquidated.Asset != "" || !liquidated.Trader.IsZero() {
+		t.Errorf("unexpected liquidation: %+v", liquidated)
+	}
+}
+
+func TestParsePerpFillsZeta(t *testing.T) {
+	keys := newTestKeys(3)
+	user, margin, liquidator := keys[0], keys[1], keys[2]
+	keys = append(keys, ZETA_PROGRAM_ID)
+
+	programData := func(data []byte) string { return "Program data: " + base64.StdEncoding.EncodeToString(data) }
+	trade := perpEventData(t, ZETA_TRADE_EVENT_DISCRIMINATOR[:], ZetaTradeEvent{MarginAccount: margin, Size: 2_500, IsBid: true,
+		Asset: 2, User: user, Fee: 150_000, Price: 3_100_000_000})
+	liquidation := perpEventData(t, ZETA_LIQUIDATION_EVENT_DISCRIMINATOR[:], ZetaLiquidationEvent{Size: -1_000,
+		MarkPrice: 140_000_000, Liquidatee: user, Liquidator: liquidator})
+
+	zeta := ZETA_PROGRAM_ID.String()
+	tx := newTestTransaction(keys, 1, testInstruction(3, []byte{1}, 0), testInstruction(3, []byte{2}, 0))
+	meta := &rpc.TransactionMeta{
+		LogMessages: []string{
+			"Program " + zeta + " invoke [1]",
+			"Program log: success",
+			"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+			programData(trade), // logged by the token program, not Zeta
+			"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+			programData(trade),
+			"Program " + zeta + " success",
+			"Program " + zeta + " invoke [1]",
+			programData(liquidation),
+			"Program " + zeta + " success",
+		},
+	}
+
+	fills, err := ParsePerpFills(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse perp fills: %v", err)
+	}
+	if len(fills) != 2 {
+		t.Fatalf("expected 2 fills, got %d", len(fills))
+	}
+
+	fill := fills[0]
+	if fill.Type != PerpFillTrade || fill.Venue != PerpVenueZeta || fill.Side != PerpSideLong || fill.Asset != "ETH" || fill.InstructionIndex != 0 || fill.InnerIndex != -1 {
+		t.Errorf("unexpected trade: %+v", fill)
+	}
+	if !fill.Trader.Equals(user) || !fill.Account.Equals(margin) || fill.Size != 2_500 || fill.Price != 3_100_000_000 || fill.Fee != 150_000 {
+		t.Errorf("unexpected trade amounts: %+v", fill)
+	}
+
+	liquidated := fills[1]
+	if liquidated.Type != PerpFillLiquidation || liquidated.Side != PerpSideShort || liquidated.Size != 1_000 || liquidated.InstructionIndex != 1 {
+		t.Errorf("unexpected liquidation: %+v", liquidated)
+	}
+	if !liquidated.Trader.Equals(user) || !liquidated.Liquidator.Equals(liquidator) || liquidated.Asset != "SOL" {
+		t.Errorf("unexpected liquidation accounts: %+v", liquidated)
+	}
+}
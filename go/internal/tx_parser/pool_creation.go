@@ -0,0 +1,253 @@
+package tx_parser
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// raydiumInitialize2Instruction is the Raydium AMM v4 instruction tag creating a pool
+const raydiumInitialize2Instruction = 1
+
+// Pool creation instruction discriminators
+var (
+	RAYDIUM_CLMM_CREATE_POOL_INSTRUCTION = [8]byte{233, 146, 209, 142, 207, 104, 64, 188}
+	PUMP_FUN_CREATE_INSTRUCTION          = [8]byte{24, 30, 200, 40, 5, 28, 7, 119}
+	MOONSHOT_TOKEN_MINT_INSTRUCTION      = [8]byte{3, 44, 164, 184, 123, 13, 245, 179}
+
+	RAYDIUM_LAUNCHLAB_INITIALIZE_INSTRUCTION        = [8]byte{175, 175, 109, 31, 13, 152, 155, 237}
+	RAYDIUM_LAUNCHLAB_MIGRATE_TO_CPSWAP_INSTRUCTION = [8]byte{136, 92, 200, 103, 28, 218, 144, 140}
+
+	METEORA_DAMM_V2_INITIALIZE_POOL_INSTRUCTION  = [8]byte{95, 180, 10, 172, 84, 174, 232, 40}
+	METEORA_DBC_INITIALIZE_SPL_POOL_INSTRUCTION  = [8]byte{140, 85, 215, 176, 102, 54, 104, 79}
+	METEORA_DBC_INITIALIZE_2022_POOL_INSTRUCTION = [8]byte{169, 118, 51, 78, 145, 110, 220, 155}
+	METEORA_DBC_MIGRATE_DAMM_INSTRUCTION         = [8]byte{27, 1, 48, 22, 180, 63, 118, 217}
+	METEORA_DBC_MIGRATE_DAMM_V2_INSTRUCTION      = [8]byte{156, 169, 230, 103, 53, 228, 80, 64}
+)
+
+// curveCreation locates the accounts of a bonding curve launch instruction
+type curveCreation struct {
+	protocol      SwapType
+	discriminator [8]byte
+	accounts      int // minimum number of accounts
+	mint          int
+	curve         int
+	curveTokens   int // token account of the curve
+	creator       int
+	quote         int // quote mint, zero for curves always quoted in SOL
+}
+
+var (
+	pumpFunCreate     = curveCreation{protocol: SwapTypePumpFun, discriminator: PUMP_FUN_CREATE_INSTRUCTION, accounts: 14, mint: 0, curve: 2, curveTokens: 3, creator: 7}
+	moonshotTokenMint = curveCreation{protocol: SwapTypeMoonshot, discriminator: MOONSHOT_TOKEN_MINT_INSTRUCTION, accounts: 11, mint: 3, curve: 2, curveTokens: 5, creator: 0}
+	launchLabCreate   = curveCreation{protocol: SwapTypeRaydiumLaunchLab, discriminator: RAYDIUM_LAUNCHLAB_INITIALIZE_INSTRUCTION, accounts: 18, mint: 6, curve: 5, curveTokens: 8, creator: 1, quote: 7}
+	dbcSPLCreate      = curveCreation{protocol: SwapTypeMeteoraDBC, discriminator: METEORA_DBC_INITIALIZE_SPL_POOL_INSTRUCTION, accounts: 8, mint: 3, curve: 5, curveTokens: 6, creator: 2, quote: 4}
+	dbc2022Create     = curveCreation{protocol: SwapTypeMeteoraDBC, discriminator: METEORA_DBC_INITIALIZE_2022_POOL_INSTRUCTION, accounts: 8, mint: 3, curve: 5, curveTokens: 6, creator: 2, quote: 4}
+)
+
+// curveMigration locates the accounts of a bonding curve graduating into an AMM pool
+type curveMigration struct {
+	protocol      SwapType
+	discriminator [8]byte
+	program       solana.PublicKey // AMM the curve graduates into
+	accounts      int              // minimum number of accounts
+	curve         int
+	pool          int
+	mintA         int
+	mintB         int
+	vaultA        int // token accounts receiving the liquidity
+	vaultB        int
+	creator       int
+}
+
+var (
+	launchLabMigration = curveMigration{protocol: SwapTypeRaydiumLaunchLab, discriminator: RAYDIUM_LAUNCHLAB_MIGRATE_TO_CPSWAP_INSTRUCTION, program: RAYDIUM_CPMM_PROGRAM_ID,
+		accounts: 21, curve: 17, pool: 5, mintA: 1, mintB: 2, vaultA: 8, vaultB: 9, creator: 0}
+	dbcDAMMMigration = curveMigration{protocol: SwapTypeMeteoraDBC, discriminator: METEORA_DBC_MIGRATE_DAMM_INSTRUCTION, program: METEORA_POOLS_PROGRAM_ID,
+		accounts: 23, curve: 0, pool: 4, mintA: 7, mintB: 8, vaultA: 11, vaultB: 12, creator: 22}
+	dbcDAMMV2Migration = curveMigration{protocol: SwapTypeMeteoraDBC, discriminator: METEORA_DBC_MIGRATE_DAMM_V2_INSTRUCTION, program: METEORA_DAMM_V2_PROGRAM_ID,
+		accounts: 20, curve: 0, pool: 4, mintA: 13, mintB: 14, vaultA: 15, vaultB: 16, creator: 19}
+)
+
+// ParsePoolCreations returns the Raydium AMM v4 and CLMM and Meteora DAMM v2 pools, the
+// pump.fun, Moonshot, Raydium LaunchLab and Meteora DBC bonding curves and the pools those
+// curves graduate into created by the transaction
+func ParsePoolCreations(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*PoolCreatedEvent, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePoolCreations(ctx), nil
+}
+
+// ParsePoolCreations returns the pools and bonding curves created by the parsed transaction
+func (p *Parser) ParsePoolCreations() ([]*PoolCreatedEvent, error) {
+	return parsePoolCreations(p.ctx), nil
+}
+
+func parsePoolCreations(ctx *TransactionContext) []*PoolCreatedEvent {
+	var events []*PoolCreatedEvent
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) {
+			return
+		}
+
+		var event *PoolCreatedEvent
+		programID := ctx.AccountKeys[instruction.ProgramIDIndex]
+		switch {
+		case programID.Equals(RAYDIUM_V4_PROGRAM_ID):
+			event = decodeRaydiumInitialize(instruction, ctx)
+		case programID.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID):
+			event = decodeRaydiumCreatePool(instruction, ctx)
+		case programID.Equals(PUMP_FUN_PROGRAM_ID):
+			event = decodeCurveCreation(instruction, ctx, pumpFunCreate)
+		case programID.Equals(MOONSHOT_PROGRAM_ID):
+			event = decodeCurveCreation(instruction, ctx, moonshotTokenMint)
+		case programID.Equals(RAYDIUM_LAUNCHLAB_PROGRAM_ID):
+			event = decodeCurveCreation(instruction, ctx, launchLabCreate)
+			if event == nil {
+				event = decodeCurveMigration(instruction, ctx, launchLabMigration)
+			}
+		case programID.Equals(METEORA_DAMM_V2_PROGRAM_ID):
+			event = decodeMeteoraDAMMV2InitializePool(instruction, ctx)
+		case programID.Equals(METEORA_BONDING_CURVE_PROGRAM_ID):
+			for _, decode := range []func() *PoolCreatedEvent{
+				func() *PoolCreatedEvent { return decodeCurveCreation(instruction, ctx, dbcSPLCreate) },
+				func() *PoolCreatedEvent { return decodeCurveCreation(instruction, ctx, dbc2022Create) },
+				func() *PoolCreatedEvent { return decodeCurveMigration(instruction, ctx, dbcDAMMMigration) },
+				func() *PoolCreatedEvent { return decodeCurveMigration(instruction, ctx, dbcDAMMV2Migration) },
+			} {
+				if event = decode(); event != nil {
+					break
+				}
+			}
+		}
+		if event == nil {
+			return
+		}
+
+		if event.Program.IsZero() {
+			event.Program = programID
+		}
+		event.InstructionIndex = instructionIndex
+		event.InnerIndex = innerIndex
+		event.DecimalsA = ctx.GetMintDecimals(event.MintA)
+		event.DecimalsB = ctx.GetMintDecimals(event.MintB)
+		events = append(events, event)
+	})
+
+	return events
+}
+
+// decodeRaydiumInitialize decodes initialize2, whose data carries the initial amounts:
+// tag u8, nonce u8, open_time u64, init_pc_amount u64, init_coin_amount u64
+func decodeRaydiumInitialize(instruction solana.CompiledInstruction, ctx *TransactionContext) *PoolCreatedEvent {
+	data := instruction.Data
+	if len(data) < 26 || data[0] != raydiumInitialize2Instruction || len(instruction.Accounts) < 18 {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	return &PoolCreatedEvent{
+		Protocol: SwapTypeRaydium,
+		Pool:     account(4),
+		MintA:    account(8),
+		MintB:    account(9),
+		Creator:  account(17),
+		AmountB:  binary.LittleEndian.Uint64(data[10:18]),
+		AmountA:  binary.LittleEndian.Uint64(data[18:26]),
+	}
+}
+
+// decodeRaydiumCreatePool decodes a CLMM create_pool. The pool starts empty, liquidity
+// comes from positions opened in the same transaction and is read from the vault balances.
+func decodeRaydiumCreatePool(instruction solana.CompiledInstruction, ctx *TransactionContext) *PoolCreatedEvent {
+	if len(instruction.Data) < 8 || !bytes.Equal(instruction.Data[:8], RAYDIUM_CLMM_CREATE_POOL_INSTRUCTION[:]) || len(instruction.Accounts) < 7 {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	return &PoolCreatedEvent{
+		Protocol: SwapTypeRaydium,
+		Creator:  account(0),
+		Pool:     account(2),
+		MintA:    account(3),
+		MintB:    account(4),
+		AmountA:  postBalance(ctx, account(5)),
+		AmountB:  postBalance(ctx, account(6)),
+	}
+}
+
+// decodeCurveCreation decodes a bonding curve launch. The curve starts without SOL, a
+// creator's first buy in the same transaction is reported as a swap.
+func decodeCurveCreation(instruction solana.CompiledInstruction, ctx *TransactionContext, layout curveCreation) *PoolCreatedEvent {
+	data := instruction.Data
+	if len(data) < 8 || !bytes.Equal(data[:8], layout.discriminator[:]) || len(instruction.Accounts) < layout.accounts {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	event := &PoolCreatedEvent{
+		Protocol: layout.protocol,
+		Pool:     account(layout.curve),
+		Creator:  account(layout.creator),
+		MintA:    account(layout.mint),
+		MintB:    NATIVE_SOL_PROGRAM_ID,
+		AmountA:  postBalance(ctx, account(layout.curveTokens)),
+	}
+	if layout.quote > 0 {
+		event.MintB = account(layout.quote)
+	}
+	return event
+}
+
+// decodeCurveMigration decodes a bonding curve graduating into a new AMM pool. The event
+// is reported for the AMM program with the curve it graduated from.
+func decodeCurveMigration(instruction solana.CompiledInstruction, ctx *TransactionContext, layout curveMigration) *PoolCreatedEvent {
+	data := instruction.Data
+	if len(data) < 8 || !bytes.Equal(data[:8], layout.discriminator[:]) || len(instruction.Accounts) < layout.accounts {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	return &PoolCreatedEvent{
+		Protocol: layout.protocol,
+		Program:  layout.program,
+		Pool:     account(layout.pool),
+		Curve:    account(layout.curve),
+		Creator:  account(layout.creator),
+		MintA:    account(layout.mintA),
+		MintB:    account(layout.mintB),
+		AmountA:  postBalance(ctx, account(layout.vaultA)),
+		AmountB:  postBalance(ctx, account(layout.vaultB)),
+	}
+}
+
+// decodeMeteoraDAMMV2InitializePool decodes a DAMM v2 initialize_pool, whose initial
+// liquidity is read from the vault balances
+func decodeMeteoraDAMMV2InitializePool(instruction solana.CompiledInstruction, ctx *TransactionContext) *PoolCreatedEvent {
+	data := instruction.Data
+	if len(data) < 8 || !bytes.Equal(data[:8], METEORA_DAMM_V2_INITIALIZE_POOL_INSTRUCTION[:]) || len(instruction.Accounts) < 12 {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	return &PoolCreatedEvent{
+		Protocol: SwapTypeMeteora,
+		Creator:  account(0),
+		Pool:     account(6),
+		MintA:    account(8),
+		MintB:    account(9),
+		AmountA:  postBalance(ctx, account(10)),
+		AmountB:  postBalance(ctx, account(11)),
+	}
+}
+
+// postBalance returns the token balance of an account created in the transaction
+func postBalance(ctx *TransactionContext, account solana.PublicKey) uint64 {
+	change, _ := ctx.TokenBalanceChange(account)
+	return uint64(max(change, 0))
+}
@@ -0,0 +1,183 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParsePoolCreations(t *testing.T) {
+	// 0 creator, 1 pool, 2 mint A, 3 mint B, 4 vault A, 5 vault B, 6 filler
+	keys := newTestKeys(7)
+	creator, pool, mintA, mintB := keys[0], keys[1], keys[2], keys[3]
+	keys = append(keys, RAYDIUM_V4_PROGRAM_ID, RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, PUMP_FUN_PROGRAM_ID, MOONSHOT_PROGRAM_ID)
+	raydiumIndex, clmmIndex, pumpIndex, moonshotIndex := uint16(7), uint16(8), uint16(9), uint16(10)
+
+	initialize := make([]byte, 26)
+	initialize[0] = raydiumInitialize2Instruction
+	binary.LittleEndian.PutUint64(initialize[10:18], 5_000_000_000)
+	binary.LittleEndian.PutUint64(initialize[18:26], 800_000_000_000)
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(raydiumIndex, initialize, 6, 6, 6, 6, 1, 6, 6, 6, 2, 3, 4, 5, 6, 6, 6, 6, 6, 0, 6, 6, 6),
+		testInstruction(clmmIndex, append(RAYDIUM_CLMM_CREATE_POOL_INSTRUCTION[:], make([]byte, 24)...), 0, 6, 1, 2, 3, 4, 5),
+		testInstruction(pumpIndex, PUMP_FUN_CREATE_INSTRUCTION[:], 2, 6, 1, 4, 6, 6, 6, 0, 6, 6, 6, 6, 6, 6),
+		testInstruction(moonshotIndex, MOONSHOT_TOKEN_MINT_INSTRUCTION[:], 0, 6, 1, 2, 6, 4, 6, 6, 6, 6, 6),
+		testInstruction(raydiumIndex, []byte{9}, 0),
+	)
+	meta := &rpc.TransactionMeta{
+		PreBalances:  make([]uint64, len(keys)),
+		PostBalances: make([]uint64, len(keys)),
+		PostTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(4, pool, mintA, 1_000, 6),
+			testTokenBalance(5, pool, mintB, 2_000, 9),
+		},
+	}
+
+	events, err := ParsePoolCreations(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse pool creations: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected 4 pool creations, got %d", len(events))
+	}
+
+	amm := events[0]
+	if amm.Protocol != SwapTypeRaydium || !amm.Program.Equals(RAYDIUM_V4_PROGRAM_ID) || !amm.Pool.Equals(pool) || !amm.Creator.Equals(creator) {
+		t.Errorf("unexpected AMM creation: %+v", amm)
+	}
+	if !amm.MintA.Equals(mintA) || !amm.MintB.Equals(mintB) || amm.AmountA != 800_000_000_000 || amm.AmountB != 5_000_000_000 || amm.DecimalsA != 6 {
+		t.Errorf("unexpected AMM liquidity: %+v", amm)
+	}
+
+	clmm := events[1]
+	if !clmm.Program.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID) || !clmm.Pool.Equals(pool) || !clmm.Creator.Equals(creator) || clmm.AmountA != 1_000 || clmm.AmountB != 2_000 {
+		t.Errorf("unexpected CLMM creation: %+v", clmm)
+	}
+
+	pump := events[2]
+	if pump.Protocol != SwapTypePumpFun || !pump.MintA.Equals(mintA) || !pump.MintB.Equals(NATIVE_SOL_PROGRAM_ID) || !pump.Creator.Equals(creator) || pump.AmountA != 1_000 || pump.DecimalsB != 9 {
+		t.Errorf("unexpected pump.fun creation: %+v", pump)
+	}
+
+	moonshot := events[3]
+	if moonshot.Protocol != SwapTypeMoonshot || !moonshot.Pool.Equals(pool) || !moonshot.MintA.Equals(mintA) || moonshot.AmountA != 1_000 || moonshot.InstructionIndex != 3 {
+		t.Errorf("unexpected Moonshot creation: %+v", moonshot)
+	}
+}
+
+func TestParseLaunchLabPoolCreations(t *testing.T) {
+	// 0 creator, 1 curve, 2 base mint, 3 quote mint, 4 curve vault, 5 CPMM pool,
+	// 6 CPMM base vault, 7 CPMM quote vault, 8 filler
+	keys := newTestKeys(9)
+	creator, curve, baseMint, quoteMint, cpmmPool := keys[0], keys[1], keys[2], keys[3], keys[5]
+	keys = append(keys, RAYDIUM_LAUNCHLAB_PROGRAM_ID)
+	launchLabIndex := uint16(9)
+
+	// initialize: payer, creator, global config, platform config, authority, pool state,
+	// base mint, quote mint, base vault, quote vault, ...
+	initialize := testInstruction(launchLabIndex, append(RAYDIUM_LAUNCHLAB_INITIALIZE_INSTRUCTION[:], make([]byte, 32)...),
+		0, 0, 8, 8, 8, 1, 2, 3, 4, 8, 8, 8, 8, 8, 8, 8, 8, 8)
+	// migrate_to_cpswap: payer, base mint, quote mint, platform config, cpswap program,
+	// cpswap pool, authority, lp mint, base vault, quote vault, ..., pool state at 17
+	migrate := testInstruction(launchLabIndex, RAYDIUM_LAUNCHLAB_MIGRATE_TO_CPSWAP_INSTRUCTION[:],
+		0, 2, 3, 8, 8, 5, 8, 8, 6, 7, 8, 8, 8, 8, 8, 8, 8, 1, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8)
+
+	tx := newTestTransaction(keys, 1, initialize, migrate)
+	meta := &rpc.TransactionMeta{
+		PreBalances:  make([]uint64, len(keys)),
+		PostBalances: make([]uint64, len(keys)),
+		PostTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(4, curve, baseMint, 1_000_000, 6),
+			testTokenBalance(6, cpmmPool, baseMint, 200_000, 6),
+			testTokenBalance(7, cpmmPool, quoteMint, 85_000, 6),
+		},
+	}
+
+	events, err := ParsePoolCreations(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse pool creations: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 pool creations, got %d", len(events))
+	}
+
+	launch := events[0]
+	if launch.Protocol != SwapTypeRaydiumLaunchLab || !launch.Program.Equals(RAYDIUM_LAUNCHLAB_PROGRAM_ID) || !launch.Pool.Equals(curve) || !launch.Creator.Equals(creator) {
+		t.Errorf("unexpected LaunchLab launch: %+v", launch)
+	}
+	if !launch.MintA.Equals(baseMint) || !launch.MintB.Equals(quoteMint) || launch.AmountA != 1_000_000 || !launch.Curve.IsZero() {
+		t.Errorf("unexpected LaunchLab curve: %+v", launch)
+	}
+
+	graduation := events[1]
+	if graduation.Protocol != SwapTypeRaydiumLaunchLab || !graduation.Program.Equals(RAYDIUM_CPMM_PROGRAM_ID) || !graduation.Pool.Equals(cpmmPool) || !graduation.Curve.Equals(curve) {
+		t.Errorf("unexpected LaunchLab graduation: %+v", graduation)
+	}
+	if !graduation.MintA.Equals(baseMint) || !graduation.MintB.Equals(quoteMint) || graduation.AmountA != 200_000 || graduation.AmountB != 85_000 || graduation.InstructionIndex != 1 {
+		t.Errorf("unexpected graduated liquidity: %+v", graduation)
+	}
+}
+
+func TestParseMeteoraPoolCreations(t *testing.T) {
+	// 0 creator, 1 curve, 2 base mint, 3 quote mint, 4 curve vault, 5 DAMM v2 pool,
+	// 6 pool vault A, 7 pool vault B, 8 filler
+	keys := newTestKeys(9)
+	creator, curve, baseMint, quoteMint, dammPool := keys[0], keys[1], keys[2], keys[3], keys[5]
+	keys = append(keys, METEORA_DAMM_V2_PROGRAM_ID, METEORA_BONDING_CURVE_PROGRAM_ID)
+	dammIndex, dbcIndex := uint16(9), uint16(10)
+
+	// initialize_virtual_pool_with_spl_token: config, pool authority, creator, base mint,
+	// quote mint, pool, base vault, quote vault, ...
+	launch := testInstruction(dbcIndex, append(METEORA_DBC_INITIALIZE_SPL_POOL_INSTRUCTION[:], make([]byte, 16)...),
+		8, 8, 0, 2, 3, 1, 4, 8, 8, 8, 8, 8)
+	// migration_damm_v2: virtual pool, migration metadata, config, pool authority, pool,
+	// ..., token mints at 13 and 14, token vaults at 15 and 16, payer at 19
+	migrate := testInstruction(dbcIndex, METEORA_DBC_MIGRATE_DAMM_V2_INSTRUCTION[:],
+		1, 8, 8, 8, 5, 8, 8, 8, 8, 8, 8, 8, 8, 2, 3, 6, 7, 8, 8, 0, 8, 8, 8)
+	// initialize_pool: creator, position nft mint, position nft account, payer, config,
+	// pool authority, pool, position, token mints, token vaults, ...
+	initialize := testInstruction(dammIndex, append(METEORA_DAMM_V2_INITIALIZE_POOL_INSTRUCTION[:], make([]byte, 40)...),
+		0, 8, 8, 0, 8, 8, 5, 8, 2, 3, 6, 7, 8, 8, 8, 8)
+
+	tx := newTestTransaction(keys, 1, launch, migrate, initialize)
+	meta := &rpc.TransactionMeta{
+		PreBalances:  make([]uint64, len(keys)),
+		PostBalances: make([]uint64, len(keys)),
+		PostTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(4, curve, baseMint, 1_000_000, 6),
+			testTokenBalance(6, dammPool, baseMint, 200_000, 6),
+			testTokenBalance(7, dammPool, quoteMint, 85_000, 9),
+		},
+	}
+
+	events, err := ParsePoolCreations(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse pool creations: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 pool creations, got %d", len(events))
+	}
+
+	dbc := events[0]
+	if dbc.Protocol != SwapTypeMeteoraDBC || !dbc.Program.Equals(METEORA_BONDING_CURVE_PROGRAM_ID) || !dbc.Pool.Equals(curve) || !dbc.Creator.Equals(creator) {
+		t.Errorf("unexpected DBC launch: %+v", dbc)
+	}
+	if !dbc.MintA.Equals(baseMint) || !dbc.MintB.Equals(quoteMint) || dbc.AmountA != 1_000_000 {
+		t.Errorf("unexpected DBC curve: %+v", dbc)
+	}
+
+	graduation := events[1]
+	if graduation.Protocol != SwapTypeMeteoraDBC || !graduation.Program.Equals(METEORA_DAMM_V2_PROGRAM_ID) || !graduation.Pool.Equals(dammPool) || !graduation.Curve.Equals(curve) {
+		t.Errorf("unexpected DBC graduation: %+v", graduation)
+	}
+	if !graduation.MintA.Equals(baseMint) || !graduation.MintB.Equals(quoteMint) || graduation.AmountA != 200_000 || graduation.AmountB != 85_000 || graduation.DecimalsB != 9 {
+		t.Errorf("unexpected graduated liquidity: %+v", graduation)
+	}
+
+	pool := events[2]
+	if pool.Protocol != SwapTypeMeteora || !pool.Program.Equals(METEORA_DAMM_V2_PROGRAM_ID) || !pool.Pool.Equals(dammPool) || !pool.Creator.Equals(creator) || !pool.Curve.IsZero() {
+		t.Errorf("unexpected DAMM v2 pool: %+v", pool)
+	}
+}
@@ -32,6 +32,9 @@ type PumpFunTradeEvent struct {
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *PumpFunParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
+	if !hasValidIndices(instruction, accountKeys) {
+		return false
+	}
 	programID := accountKeys[instruction.ProgramIDIndex]
 	return programID.Equals(PUMP_FUN_PROGRAM_ID)
 }
@@ -67,6 +70,11 @@ func (p *PumpFunParser) ParseInstruction(instruction solana.CompiledInstruction,
 
 // parsePumpFunEvent decodes a single PumpFun trade event
 func (p *PumpFunParser) parsePumpFunEvent(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) (*PumpFunTradeEvent, error) {
+	// Raydium LaunchLab emits a TradeEvent with the same discriminator
+	if int(instruction.ProgramIDIndex) >= len(accountKeys) || !accountKeys[instruction.ProgramIDIndex].Equals(PUMP_FUN_PROGRAM_ID) {
+		return nil, fmt.Errorf("not a PumpFun event")
+	}
+
 	decodedBytes, err := base58.Decode(instruction.Data.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode instruction data: %w", err)
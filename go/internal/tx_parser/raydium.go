@@ -3,6 +3,7 @@ package tx_parser
 import (
 	"encoding/binary"
 	"fmt"
+	"slices"
 
 	"github.com/gagliardetto/solana-go"
 )
@@ -17,20 +18,30 @@ func NewRaydiumParser() *RaydiumParser {
 
 // CanHandle checks if this parser can handle the given instruction
 func (p *RaydiumParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
+	if !hasValidIndices(instruction, accountKeys) {
+		return false
+	}
 	programID := accountKeys[instruction.ProgramIDIndex]
 	return isRaydiumProgram(programID)
 }
 
+// raydiumTransfer is a token transfer made by a Raydium swap along with its accounts
+type raydiumTransfer struct {
+	TokenInfo
+	Source      solana.PublicKey
+	Destination solana.PublicKey
+}
+
 // ParseInstruction processes the Raydium instruction and returns swap information
 func (p *RaydiumParser) ParseInstruction(instruction solana.CompiledInstruction, instructionIndex int, ctx *TransactionContext) ([]*SwapInfo, error) {
-	var swaps []*SwapInfo
+	var transfers []raydiumTransfer
 
-	// Process transfers in each group of inner instructions
+	// Collect transfers from the inner instructions of this swap
 	for _, innerSet := range ctx.Meta.InnerInstructions {
 		if innerSet.Index == uint16(instructionIndex) {
-			var currentTransfers []TokenInfo
+			transfers = slices.Grow(transfers, len(innerSet.Instructions))
 			for _, innerInstr := range innerSet.Instructions {
-				var transfer *TokenInfo
+				var transfer raydiumTransfer
 				var err error
 
 				switch {
@@ -38,30 +49,26 @@ func (p *RaydiumParser) ParseInstruction(instruction solana.CompiledInstruction,
 					transfer, err = p.processTransfer(innerInstr, ctx)
 				case isRaydiumTransferChecked(innerInstr, ctx.AccountKeys):
 					transfer, err = p.processTransferChecked(innerInstr, ctx)
+				default:
+					continue
 				}
 
-				if err != nil || transfer == nil {
+				if err != nil {
 					continue
 				}
 
-				currentTransfers = append(currentTransfers, *transfer)
-
-				// When we have a pair of transfers, build a swap
-				if len(currentTransfers) == 2 {
-					swap, err := p.buildSwapInfo(currentTransfers[0], currentTransfers[1], ctx)
-					if err != nil {
-						// Reset for next pair
-						currentTransfers = currentTransfers[2:]
-						continue
-					}
-					swaps = append(swaps, swap)
-					// Reset for next pair but keep any remaining transfers
-					currentTransfers = currentTransfers[2:]
-				}
+				transfers = append(transfers, transfer)
 			}
 		}
 	}
 
+	var swaps []*SwapInfo
+	if vaults := p.poolVaults(instruction, ctx); len(vaults) > 0 {
+		swaps = p.pairByVaults(transfers, vaults)
+	} else {
+		swaps = p.pairInOrder(transfers, ctx)
+	}
+
 	if len(swaps) == 0 {
 		return nil, fmt.Errorf("no valid Raydium swaps found")
 	}
@@ -69,9 +76,104 @@ func (p *RaydiumParser) ParseInstruction(instruction solana.CompiledInstruction,
 	return swaps, nil
 }
 
+// poolVaults returns the pool vault accounts referenced by the swap instruction, or nil
+// if the instruction layout is unknown
+func (p *RaydiumParser) poolVaults(instruction solana.CompiledInstruction, ctx *TransactionContext) []solana.PublicKey {
+	if int(instruction.ProgramIDIndex) >= len(ctx.AccountKeys) {
+		return nil
+	}
+
+	var positions []int
+	accountCount := len(instruction.Accounts)
+	switch programID := ctx.AccountKeys[instruction.ProgramIDIndex]; {
+	case programID.Equals(RAYDIUM_V4_PROGRAM_ID):
+		// The amm_target_orders account was made optional, shifting the vaults
+		switch {
+		case accountCount >= 18:
+			positions = []int{5, 6}
+		case accountCount == 17:
+			positions = []int{4, 5}
+		}
+	case programID.Equals(RAYDIUM_CPMM_PROGRAM_ID):
+		if accountCount >= 8 {
+			positions = []int{6, 7}
+		}
+	case programID.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID):
+		if accountCount >= 7 {
+			positions = []int{5, 6}
+		}
+	}
+
+	var vaults []solana.PublicKey
+	for _, position := range positions {
+		idx := instruction.Accounts[position]
+		if int(idx) >= len(ctx.AccountKeys) {
+			return nil
+		}
+		vaults = append(vaults, ctx.AccountKeys[idx])
+	}
+	return vaults
+}
+
+// pairByVaults builds swaps from transfers into and out of the pool vaults. Transfers that
+// touch neither vault, such as fee transfers, are ignored.
+func (p *RaydiumParser) pairByVaults(transfers []raydiumTransfer, vaults []solana.PublicKey) []*SwapInfo {
+	isVault := func(account solana.PublicKey) bool {
+		for _, vault := range vaults {
+			if vault.Equals(account) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var swaps []*SwapInfo
+	var pendingIn, pendingOut []raydiumTransfer
+	for _, transfer := range transfers {
+		switch {
+		case isVault(transfer.Destination) && !isVault(transfer.Source):
+			pendingIn = append(pendingIn, transfer)
+		case isVault(transfer.Source) && !isVault(transfer.Destination):
+			pendingOut = append(pendingOut, transfer)
+		default:
+			continue
+		}
+
+		if len(pendingIn) > 0 && len(pendingOut) > 0 {
+			in, out := pendingIn[0], pendingOut[0]
+			pendingIn, pendingOut = pendingIn[1:], pendingOut[1:]
+			if in.Mint.Equals(out.Mint) {
+				continue
+			}
+			swaps = append(swaps, &SwapInfo{
+				Protocol: SwapTypeRaydium,
+				TokenIn:  in.TokenInfo,
+				TokenOut: out.TokenInfo,
+			})
+		}
+	}
+
+	return swaps
+}
+
+// pairInOrder builds swaps from consecutive pairs of transfers, for instruction layouts
+// whose vault accounts are unknown
+func (p *RaydiumParser) pairInOrder(transfers []raydiumTransfer, ctx *TransactionContext) []*SwapInfo {
+	var swaps []*SwapInfo
+	for len(transfers) >= 2 {
+		swap, err := p.buildSwapInfo(transfers[0].TokenInfo, transfers[1].TokenInfo, ctx)
+		transfers = transfers[2:]
+		if err != nil {
+			continue
+		}
+		swaps = append(swaps, swap)
+	}
+	return swaps
+}
+
 // isRaydiumTransfer checks if the instruction is a token transfer
 func isRaydiumTransfer(instr solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	if len(instr.Accounts) < 3 || len(instr.Data) < 9 {
+	if len(instr.Accounts) < 3 || len(instr.Data) < 9 || !hasValidIndices(instr, accountKeys) {
 		return false
 	}
 
@@ -85,7 +187,7 @@ func isRaydiumTransfer(instr solana.CompiledInstruction, accountKeys []solana.Pu
 
 // isRaydiumTransferChecked checks if the instruction is a token transfer with amount check
 func isRaydiumTransferChecked(instr solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
-	if len(instr.Accounts) < 4 || len(instr.Data) < 9 {
+	if len(instr.Accounts) < 4 || len(instr.Data) < 9 || !hasValidIndices(instr, accountKeys) {
 		return false
 	}
 
@@ -98,9 +200,9 @@ func isRaydiumTransferChecked(instr solana.CompiledInstruction, accountKeys []so
 }
 
 // processTransfer handles regular token transfers
-func (p *RaydiumParser) processTransfer(instr solana.CompiledInstruction, ctx *TransactionContext) (*TokenInfo, error) {
+func (p *RaydiumParser) processTransfer(instr solana.CompiledInstruction, ctx *TransactionContext) (raydiumTransfer, error) {
 	if len(instr.Data) < 9 {
-		return nil, fmt.Errorf("invalid transfer instruction data")
+		return raydiumTransfer{}, fmt.Errorf("invalid transfer instruction data")
 	}
 
 	amount := binary.LittleEndian.Uint64(instr.Data[1:9])
@@ -110,49 +212,42 @@ func (p *RaydiumParser) processTransfer(instr solana.CompiledInstruction, ctx *T
 	destAcc := ctx.AccountKeys[instr.Accounts[1]]
 
 	// Find token mint from either source or destination account
-	mint := p.findTokenMint(sourceAcc, destAcc, ctx)
+	mint := ctx.findTokenMint(sourceAcc, destAcc)
 	if mint == (solana.PublicKey{}) {
-		return nil, fmt.Errorf("could not determine token mint")
+		return raydiumTransfer{}, fmt.Errorf("could not determine token mint")
 	}
 
-	return &TokenInfo{
-		Mint:     mint,
-		Amount:   amount,
-		Decimals: ctx.GetMintDecimals(mint),
+	return raydiumTransfer{
+		TokenInfo: TokenInfo{
+			Mint:     mint,
+			Amount:   amount,
+			Decimals: ctx.GetMintDecimals(mint),
+		},
+		Source:      sourceAcc,
+		Destination: destAcc,
 	}, nil
 }
 
 // processTransferChecked handles checked token transfers
-func (p *RaydiumParser) processTransferChecked(instr solana.CompiledInstruction, ctx *TransactionContext) (*TokenInfo, error) {
+func (p *RaydiumParser) processTransferChecked(instr solana.CompiledInstruction, ctx *TransactionContext) (raydiumTransfer, error) {
 	if len(instr.Data) < 9 {
-		return nil, fmt.Errorf("invalid transfer checked instruction data")
+		return raydiumTransfer{}, fmt.Errorf("invalid transfer checked instruction data")
 	}
 
 	amount := binary.LittleEndian.Uint64(instr.Data[1:9])
 	mint := ctx.AccountKeys[instr.Accounts[1]]
 
-	return &TokenInfo{
-		Mint:     mint,
-		Amount:   amount,
-		Decimals: ctx.GetMintDecimals(mint),
+	return raydiumTransfer{
+		TokenInfo: TokenInfo{
+			Mint:     mint,
+			Amount:   amount,
+			Decimals: ctx.GetMintDecimals(mint),
+		},
+		Source:      ctx.AccountKeys[instr.Accounts[0]],
+		Destination: ctx.AccountKeys[instr.Accounts[2]],
 	}, nil
 }
 
-// findTokenMint looks up the mint for token accounts
-func (p *RaydiumParser) findTokenMint(source, dest solana.PublicKey, ctx *TransactionContext) solana.PublicKey {
-	// Check both pre and post token balances
-	balances := append(ctx.Meta.PreTokenBalances, ctx.Meta.PostTokenBalances...)
-
-	for _, balance := range balances {
-		accKey := ctx.AccountKeys[balance.AccountIndex]
-		if accKey.Equals(source) || accKey.Equals(dest) {
-			return balance.Mint
-		}
-	}
-
-	return solana.PublicKey{}
-}
-
 // buildSwapInfo creates a SwapInfo from a pair of transfers
 func (p *RaydiumParser) buildSwapInfo(transfer1, transfer2 TokenInfo, ctx *TransactionContext) (*SwapInfo, error) {
 	if transfer1.Mint.Equals(transfer2.Mint) {
@@ -164,7 +259,7 @@ func (p *RaydiumParser) buildSwapInfo(transfer1, transfer2 TokenInfo, ctx *Trans
 	}
 
 	// Find input token (transferred from signer)
-	signers := ctx.Transaction.Message.Signers()
+	signers := ctx.signers()
 	found := false
 
 	// Check first transfer
@@ -0,0 +1,172 @@
+package tx_parser
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// RaydiumLaunchLabParser handles parsing trades on Raydium LaunchLab bonding curves,
+// the launchpad behind letsbonk.fun
+type RaydiumLaunchLabParser struct {
+	consumedEvents map[[2]int]bool // trade events already matched to a trade, by instruction and inner index
+}
+
+// NewRaydiumLaunchLabParser creates a new Raydium LaunchLab parser instance
+func NewRaydiumLaunchLabParser() *RaydiumLaunchLabParser {
+	return &RaydiumLaunchLabParser{}
+}
+
+// LaunchLab trade instruction discriminators
+var (
+	RAYDIUM_LAUNCHLAB_BUY_EXACT_IN_INSTRUCTION   = [8]byte{250, 234, 13, 123, 213, 156, 19, 236}
+	RAYDIUM_LAUNCHLAB_BUY_EXACT_OUT_INSTRUCTION  = [8]byte{24, 211, 116, 40, 105, 3, 153, 56}
+	RAYDIUM_LAUNCHLAB_SELL_EXACT_IN_INSTRUCTION  = [8]byte{149, 39, 222, 155, 211, 124, 152, 26}
+	RAYDIUM_LAUNCHLAB_SELL_EXACT_OUT_INSTRUCTION = [8]byte{95, 200, 71, 34, 8, 9, 11, 166}
+
+	// Both programs name their Anchor event TradeEvent, so the discriminators match
+	RAYDIUM_LAUNCHLAB_TRADE_EVENT_DISCRIMINATOR = PUMPFUN_TRADE_EVENT_DISCRIMINATOR
+)
+
+// Accounts of the LaunchLab buy and sell instructions
+const (
+	launchLabTradeAccounts     = 13 // minimum number of accounts
+	launchLabPoolAccount       = 4
+	launchLabBaseVaultAccount  = 7
+	launchLabQuoteVaultAccount = 8
+	launchLabBaseMintAccount   = 9
+	launchLabQuoteMintAccount  = 10
+)
+
+// RaydiumLaunchLabTradeEvent is the leading part of the TradeEvent LaunchLab emits for
+// every trade. The fee, direction and pool status fields that follow are not decoded.
+type RaydiumLaunchLabTradeEvent struct {
+	PoolState       solana.PublicKey
+	TotalBaseSell   uint64
+	VirtualBase     uint64
+	VirtualQuote    uint64
+	RealBaseBefore  uint64
+	RealQuoteBefore uint64
+	RealBaseAfter   uint64
+	RealQuoteAfter  uint64
+	AmountIn        uint64
+	AmountOut       uint64
+}
+
+// CanHandle checks if this parser can handle the given instruction
+func (p *RaydiumLaunchLabParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
+	if !hasValidIndices(instruction, accountKeys) || !accountKeys[instruction.ProgramIDIndex].Equals(RAYDIUM_LAUNCHLAB_PROGRAM_ID) {
+		return false
+	}
+	if len(instruction.Data) < 8 || len(instruction.Accounts) < launchLabTradeAccounts {
+		return false
+	}
+
+	_, ok := launchLabTradeDirection(instruction.Data)
+	return ok
+}
+
+// ParseInstruction processes a LaunchLab buy or sell and returns swap information
+func (p *RaydiumLaunchLabParser) ParseInstruction(instruction solana.CompiledInstruction, instructionIndex int, ctx *TransactionContext) ([]*SwapInfo, error) {
+	isBuy, ok := launchLabTradeDirection(instruction.Data)
+	if !ok {
+		return nil, fmt.Errorf("invalid Raydium LaunchLab instruction discriminator")
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	pool := account(launchLabPoolAccount)
+	baseMint, quoteMint := account(launchLabBaseMintAccount), account(launchLabQuoteMintAccount)
+
+	amountIn, amountOut, err := p.tradeAmounts(instruction, instructionIndex, isBuy, pool, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	base := TokenInfo{Mint: baseMint, Decimals: ctx.GetMintDecimals(baseMint)}
+	quote := TokenInfo{Mint: quoteMint, Decimals: ctx.GetMintDecimals(quoteMint)}
+	swap := &SwapInfo{Protocol: SwapTypeRaydiumLaunchLab}
+	if isBuy {
+		swap.TokenIn, swap.TokenOut = quote, base
+	} else {
+		swap.TokenIn, swap.TokenOut = base, quote
+	}
+	swap.TokenIn.Amount, swap.TokenOut.Amount = amountIn, amountOut
+
+	return []*SwapInfo{swap}, nil
+}
+
+// tradeAmounts takes the amounts from the first unmatched trade event of the pool in the
+// instruction's inner instructions, falling back to the vault balance changes
+func (p *RaydiumLaunchLabParser) tradeAmounts(instruction solana.CompiledInstruction, instructionIndex int, isBuy bool, pool solana.PublicKey, ctx *TransactionContext) (amountIn, amountOut uint64, err error) {
+	for _, innerSet := range ctx.Meta.InnerInstructions {
+		if innerSet.Index != uint16(instructionIndex) {
+			continue
+		}
+		for j, innerInstr := range innerSet.Instructions {
+			key := [2]int{instructionIndex, j}
+			if p.consumedEvents[key] {
+				continue
+			}
+			event, err := p.parseTradeEvent(innerInstr, ctx.AccountKeys)
+			if err != nil || !event.PoolState.Equals(pool) {
+				continue
+			}
+
+			if p.consumedEvents == nil {
+				p.consumedEvents = make(map[[2]int]bool)
+			}
+			p.consumedEvents[key] = true
+			if event.AmountIn == 0 || event.AmountOut == 0 {
+				return 0, 0, fmt.Errorf("invalid amounts in Raydium LaunchLab event")
+			}
+			return event.AmountIn, event.AmountOut, nil
+		}
+	}
+
+	baseChange, baseOk := ctx.TokenBalanceChange(instructionAccount(instruction, launchLabBaseVaultAccount, ctx))
+	quoteChange, quoteOk := ctx.TokenBalanceChange(instructionAccount(instruction, launchLabQuoteVaultAccount, ctx))
+	if !baseOk || !quoteOk || baseChange == 0 || quoteChange == 0 {
+		return 0, 0, fmt.Errorf("no Raydium LaunchLab trade event or vault balances found")
+	}
+	if isBuy {
+		return uint64(abs(quoteChange)), uint64(abs(baseChange)), nil
+	}
+	return uint64(abs(baseChange)), uint64(abs(quoteChange)), nil
+}
+
+// parseTradeEvent decodes a trade event emitted through a self CPI of the LaunchLab program
+func (p *RaydiumLaunchLabParser) parseTradeEvent(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) (*RaydiumLaunchLabTradeEvent, error) {
+	if int(instruction.ProgramIDIndex) >= len(accountKeys) || !accountKeys[instruction.ProgramIDIndex].Equals(RAYDIUM_LAUNCHLAB_PROGRAM_ID) {
+		return nil, fmt.Errorf("not a Raydium LaunchLab event")
+	}
+
+	data := instruction.Data
+	if len(data) < 16 || !bytes.Equal(data[:16], RAYDIUM_LAUNCHLAB_TRADE_EVENT_DISCRIMINATOR[:]) {
+		return nil, fmt.Errorf("invalid Raydium LaunchLab event discriminator")
+	}
+
+	var event RaydiumLaunchLabTradeEvent
+	if err := ag_binary.NewBorshDecoder(data[16:]).Decode(&event); err != nil {
+		return nil, fmt.Errorf("failed to decode Raydium LaunchLab event: %w", err)
+	}
+	return &event, nil
+}
+
+// launchLabTradeDirection reports whether the instruction data is a buy or a sell
+func launchLabTradeDirection(data []byte) (isBuy, ok bool) {
+	if len(data) < 8 {
+		return false, false
+	}
+
+	switch {
+	case bytes.Equal(data[:8], RAYDIUM_LAUNCHLAB_BUY_EXACT_IN_INSTRUCTION[:]),
+		bytes.Equal(data[:8], RAYDIUM_LAUNCHLAB_BUY_EXACT_OUT_INSTRUCTION[:]):
+		return true, true
+	case bytes.Equal(data[:8], RAYDIUM_LAUNCHLAB_SELL_EXACT_IN_INSTRUCTION[:]),
+		bytes.Equal(data[:8], RAYDIUM_LAUNCHLAB_SELL_EXACT_OUT_INSTRUCTION[:]):
+		return false, true
+	}
+	return false, false
+}
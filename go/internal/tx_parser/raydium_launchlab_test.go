@@ -0,0 +1,146 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// launchLabTradeEventData encodes a LaunchLab TradeEvent followed by the fields the parser skips
+func launchLabTradeEventData(pool solana.PublicKey, amountIn, amountOut uint64) []byte {
+	data := append(RAYDIUM_LAUNCHLAB_TRADE_EVENT_DISCRIMINATOR[:], pool[:]...)
+	fields := make([]byte, 9*8+4*8+3)
+	binary.LittleEndian.PutUint64(fields[7*8:], amountIn)
+	binary.LittleEndian.PutUint64(fields[8*8:], amountOut)
+	return append(data, fields...)
+}
+
+// newLaunchLabTrade builds a buy or sell instruction over the test keys:
+// 0 user, 1 pool, 2 user base, 3 user quote, 4 base vault, 5 quote vault, 6 base mint, 7 quote mint, 8 filler
+func newLaunchLabTrade(programIndex uint16, discriminator [8]byte) solana.CompiledInstruction {
+	data := append(discriminator[:], make([]byte, 24)...)
+	return testInstruction(programIndex, data, 0, 8, 8, 8, 1, 2, 3, 4, 5, 6, 7, 8, 8, 8, 8)
+}
+
+func TestRaydiumLaunchLabParser(t *testing.T) {
+	keys := newTestKeys(9)
+	user, pool, baseMint, quoteMint := keys[0], keys[1], keys[6], keys[7]
+	keys = append(keys, RAYDIUM_LAUNCHLAB_PROGRAM_ID, solana.TokenProgramID)
+	launchLabIndex, tokenIndex := uint16(9), uint16(10)
+
+	t.Run("buy from trade event", func(t *testing.T) {
+		buy := newLaunchLabTrade(launchLabIndex, RAYDIUM_LAUNCHLAB_BUY_EXACT_IN_INSTRUCTION)
+		tx := newTestTransaction(keys, 1, buy)
+		meta := &rpc.TransactionMeta{
+			InnerInstructions: []rpc.InnerInstruction{{
+				Index: 0,
+				Instructions: []solana.CompiledInstruction{
+					testInstruction(tokenIndex, tokenTransferData(1_000_000_000), 3, 5, 0),
+					testInstruction(tokenIndex, tokenTransferData(35_000_000_000), 4, 2, 8),
+					testInstruction(launchLabIndex, launchLabTradeEventData(pool, 1_000_000_000, 35_000_000_000), 8),
+				},
+			}},
+			PreTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(4, pool, baseMint, 800_000_000_000, 6),
+				testTokenBalance(5, pool, quoteMint, 0, 9),
+			},
+		}
+
+		swaps, err := newTestParser(tx, meta, ParseOptions{}).ParseTransaction()
+		if err != nil {
+			t.Fatalf("failed to parse LaunchLab buy: %v", err)
+		}
+		if len(swaps) != 1 || swaps[0].Protocol != SwapTypeRaydiumLaunchLab {
+			t.Fatalf("expected one LaunchLab swap, got %+v", swaps)
+		}
+
+		swap := swaps[0]
+		if !swap.TokenIn.Mint.Equals(quoteMint) || swap.TokenIn.Amount != 1_000_000_000 || swap.TokenIn.Decimals != 9 {
+			t.Errorf("unexpected token in: %+v", swap.TokenIn)
+		}
+		if !swap.TokenOut.Mint.Equals(baseMint) || swap.TokenOut.Amount != 35_000_000_000 || swap.TokenOut.Decimals != 6 {
+			t.Errorf("unexpected token out: %+v", swap.TokenOut)
+		}
+		if len(swap.Signers) != 1 || !swap.Signers[0].Equals(user) {
+			t.Errorf("expected the user to sign, got %v", swap.Signers)
+		}
+	})
+
+	t.Run("sell from vault balances", func(t *testing.T) {
+		sell := newLaunchLabTrade(launchLabIndex, RAYDIUM_LAUNCHLAB_SELL_EXACT_IN_INSTRUCTION)
+		tx := newTestTransaction(keys, 1, sell)
+		meta := &rpc.TransactionMeta{
+			PreTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(4, pool, baseMint, 800_000_000_000, 6),
+				testTokenBalance(5, pool, quoteMint, 5_000_000_000, 9),
+			},
+			PostTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(4, pool, baseMint, 810_000_000_000, 6),
+				testTokenBalance(5, pool, quoteMint, 4_700_000_000, 9),
+			},
+		}
+
+		swaps, err := NewRaydiumLaunchLabParser().ParseInstruction(sell, 0, newTestContext(tx, meta))
+		if err != nil {
+			t.Fatalf("failed to parse LaunchLab sell: %v", err)
+		}
+		swap := swaps[0]
+		if !swap.TokenIn.Mint.Equals(baseMint) || swap.TokenIn.Amount != 10_000_000_000 {
+			t.Errorf("unexpected token in: %+v", swap.TokenIn)
+		}
+		if !swap.TokenOut.Mint.Equals(quoteMint) || swap.TokenOut.Amount != 300_000_000 {
+			t.Errorf("unexpected token out: %+v", swap.TokenOut)
+		}
+	})
+
+	t.Run("routed trades consume one event each", func(t *testing.T) {
+		first := newLaunchLabTrade(launchLabIndex, RAYDIUM_LAUNCHLAB_BUY_EXACT_IN_INSTRUCTION)
+		second := newLaunchLabTrade(launchLabIndex, RAYDIUM_LAUNCHLAB_BUY_EXACT_OUT_INSTRUCTION)
+		tx := newTestTransaction(keys, 1, testInstruction(8, []byte{1}, 0))
+		ctx := newTestContext(tx, &rpc.TransactionMeta{
+			InnerInstructions: []rpc.InnerInstruction{{
+				Index: 0,
+				Instructions: []solana.CompiledInstruction{
+					first,
+					testInstruction(launchLabIndex, launchLabTradeEventData(pool, 100, 3_000), 8),
+					second,
+					testInstruction(launchLabIndex, launchLabTradeEventData(pool, 200, 5_000), 8),
+				},
+			}},
+		})
+
+		parser := NewRaydiumLaunchLabParser()
+		var amounts []uint64
+		for _, trade := range []solana.CompiledInstruction{first, second} {
+			swaps, err := parser.ParseInstruction(trade, 0, ctx)
+			if err != nil {
+				t.Fatalf("failed to parse routed trade: %v", err)
+			}
+			amounts = append(amounts, swaps[0].TokenIn.Amount)
+		}
+		if amounts[0] != 100 || amounts[1] != 200 {
+			t.Errorf("expected each trade to take its own event, got %v", amounts)
+		}
+	})
+
+	t.Run("ignores other instructions", func(t *testing.T) {
+		parser := NewRaydiumLaunchLabParser()
+		initialize := testInstruction(launchLabIndex, RAYDIUM_LAUNCHLAB_INITIALIZE_INSTRUCTION[:], 0, 8, 8, 8, 1, 2, 3, 4, 5, 6, 7, 8, 8, 8, 8)
+		if parser.CanHandle(initialize, keys) {
+			t.Error("expected initialize not to be handled as a trade")
+		}
+		if parser.CanHandle(newLaunchLabTrade(tokenIndex, RAYDIUM_LAUNCHLAB_BUY_EXACT_IN_INSTRUCTION), keys) {
+			t.Error("expected other programs not to be handled")
+		}
+	})
+}
+
+func TestPumpFunIgnoresLaunchLabEvents(t *testing.T) {
+	keys := append(newTestKeys(2), RAYDIUM_LAUNCHLAB_PROGRAM_ID)
+	event := testInstruction(2, launchLabTradeEventData(keys[1], 1, 1), 0)
+	if _, err := NewPumpFunParser().parsePumpFunEvent(event, keys); err == nil {
+		t.Error("expected a LaunchLab TradeEvent not to decode as a pump.fun trade")
+	}
+}
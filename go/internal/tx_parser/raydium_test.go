@@ -0,0 +1,90 @@
+package tx_parser
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestRaydiumPairsTransfersByVault(t *testing.T) {
+	keys := newTestKeys(10)
+	// user, pool, user WSOL, user USDC, WSOL vault, USDC vault, fee account, WSOL, USDC
+	user, pool := keys[0], keys[1]
+	wsol, usdc := keys[7], keys[8]
+	keys = append(keys, RAYDIUM_CPMM_PROGRAM_ID, solana.TokenProgramID)
+	cpmmIndex, tokenIndex := uint16(10), uint16(11)
+
+	// payer, authority, amm_config, pool_state, input_token_account, output_token_account, input_vault, output_vault
+	swap := testInstruction(cpmmIndex, []byte{143, 190, 90, 218, 196, 30, 51, 222}, 0, 1, 9, 9, 2, 3, 4, 5)
+	tx := newTestTransaction(keys, 1, swap)
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{
+			{
+				Index: 0,
+				Instructions: []solana.CompiledInstruction{
+					// Output is sent before the input, with a fee transfer interleaved
+					testInstruction(tokenIndex, tokenTransferData(150_000_000), 5, 3, 1),
+					testInstruction(tokenIndex, tokenTransferData(2_500_000), 2, 6, 0),
+					testInstruction(tokenIndex, tokenTransferData(1_000_000_000), 2, 4, 0),
+				},
+			},
+		},
+		PreTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(2, user, wsol, 2_000_000_000, 9),
+			testTokenBalance(3, user, usdc, 0, 6),
+			testTokenBalance(4, pool, wsol, 9_000_000_000, 9),
+			testTokenBalance(5, pool, usdc, 500_000_000, 6),
+			testTokenBalance(6, pool, wsol, 0, 9),
+		},
+	}
+	ctx := newTestContext(tx, meta)
+
+	parser := NewRaydiumParser()
+	swaps, err := parser.ParseInstruction(swap, 0, ctx)
+	if err != nil {
+		t.Fatalf("failed to parse Raydium swap: %v", err)
+	}
+	if len(swaps) != 1 {
+		t.Fatalf("expected 1 swap, got %d", len(swaps))
+	}
+
+	got := swaps[0]
+	if !got.TokenIn.Mint.Equals(wsol) || got.TokenIn.Amount != 1_000_000_000 {
+		t.Errorf("expected 1 SOL in, got %+v", got.TokenIn)
+	}
+	if !got.TokenOut.Mint.Equals(usdc) || got.TokenOut.Amount != 150_000_000 {
+		t.Errorf("expected 150 USDC out, got %+v", got.TokenOut)
+	}
+}
+
+func TestRaydiumPoolVaults(t *testing.T) {
+	keys := newTestKeys(18)
+	keys = append(keys, RAYDIUM_V4_PROGRAM_ID)
+	programIndex := uint16(18)
+
+	accounts := func(n int) []uint16 {
+		indices := make([]uint16, n)
+		for i := range indices {
+			indices[i] = uint16(i)
+		}
+		return indices
+	}
+
+	ctx := newTestContext(newTestTransaction(keys, 1), &rpc.TransactionMeta{})
+	parser := NewRaydiumParser()
+
+	vaults := parser.poolVaults(testInstruction(programIndex, []byte{9}, accounts(18)...), ctx)
+	if len(vaults) != 2 || !vaults[0].Equals(keys[5]) || !vaults[1].Equals(keys[6]) {
+		t.Errorf("unexpected vaults for 18 account layout: %v", vaults)
+	}
+
+	vaults = parser.poolVaults(testInstruction(programIndex, []byte{9}, accounts(17)...), ctx)
+	if len(vaults) != 2 || !vaults[0].Equals(keys[4]) || !vaults[1].Equals(keys[5]) {
+		t.Errorf("unexpected vaults for 17 account layout: %v", vaults)
+	}
+
+	if vaults := parser.poolVaults(testInstruction(programIndex, []byte{9}, accounts(4)...), ctx); vaults != nil {
+		t.Errorf("expected no vaults for unknown layout, got %v", vaults)
+	}
+}
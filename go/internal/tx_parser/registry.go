@@ -0,0 +1,49 @@
+package tx_parser
+
+import (
+	"sync"
+)
+
+// ParserFactory creates a new protocol parser instance for a single transaction
+type ParserFactory func() SwapParser
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[SwapType]ParserFactory)
+)
+
+// RegisterParser makes a protocol parser available to every Parser created afterwards.
+// Parser packages outside tx_parser call this from an init function.
+func RegisterParser(swapType SwapType, factory ParserFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[swapType] = factory
+}
+
+// RegisteredParsers returns the swap types of all externally registered parsers
+func RegisteredParsers() []SwapType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	swapTypes := make([]SwapType, 0, len(registry))
+	for swapType := range registry {
+		swapTypes = append(swapTypes, swapType)
+	}
+	return swapTypes
+}
+
+// registeredFactories returns a copy of the registry for use by a single Parser
+func registeredFactories() map[SwapType]ParserFactory {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if len(registry) == 0 {
+		return nil
+	}
+	factories := make(map[SwapType]ParserFactory, len(registry))
+	for swapType, factory := range registry {
+		factories[swapType] = factory
+	}
+	return factories
+}
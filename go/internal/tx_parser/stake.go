@@ -0,0 +1,313 @@
+package tx_parser
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Native stake program instruction indices
+const (
+	stakeDelegateInstruction   = 2
+	stakeSplitInstruction      = 3
+	stakeWithdrawInstruction   = 4
+	stakeDeactivateInstruction = 5
+	stakeMergeInstruction      = 7
+)
+
+// SPL stake pool instruction indices
+const (
+	stakePoolDepositStakeInstruction              = 9
+	stakePoolWithdrawStakeInstruction             = 10
+	stakePoolDepositSolInstruction                = 14
+	stakePoolWithdrawSolInstruction               = 16
+	stakePoolDepositStakeWithSlippageInstruction  = 22
+	stakePoolWithdrawStakeWithSlippageInstruction = 23
+	stakePoolDepositSolWithSlippageInstruction    = 24
+	stakePoolWithdrawSolWithSlippageInstruction   = 25
+)
+
+// Marinade instruction discriminators
+var (
+	MARINADE_DEPOSIT_INSTRUCTION               = [8]byte{242, 35, 198, 137, 82, 225, 242, 182}
+	MARINADE_DEPOSIT_STAKE_ACCOUNT_INSTRUCTION = [8]byte{110, 130, 115, 41, 164, 102, 2, 59}
+	MARINADE_LIQUID_UNSTAKE_INSTRUCTION        = [8]byte{30, 30, 119, 240, 191, 227, 12, 16}
+	MARINADE_ORDER_UNSTAKE_INSTRUCTION         = [8]byte{97, 167, 144, 107, 117, 190, 128, 36}
+	MARINADE_CLAIM_INSTRUCTION                 = [8]byte{62, 198, 214, 193, 213, 159, 108, 210}
+)
+
+// ParseStakeEvents returns every native stake and stake pool action in the transaction
+func ParseStakeEvents(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*StakeEvent, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStakeEvents(ctx), nil
+}
+
+// ParseStakeEvents returns every native stake and stake pool action in the parsed transaction
+func (p *Parser) ParseStakeEvents() ([]*StakeEvent, error) {
+	return parseStakeEvents(p.ctx), nil
+}
+
+func parseStakeEvents(ctx *TransactionContext) []*StakeEvent {
+	var events []*StakeEvent
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) {
+			return
+		}
+
+		var event *StakeEvent
+		programID := ctx.AccountKeys[instruction.ProgramIDIndex]
+		switch {
+		case programID.Equals(STAKE_PROGRAM_ID):
+			event = decodeNativeStake(instruction, ctx)
+		case programID.Equals(SPL_STAKE_POOL_PROGRAM_ID):
+			event = decodeStakePool(instruction, ctx)
+		case programID.Equals(MARINADE_PROGRAM_ID):
+			event = decodeMarinade(instruction, ctx)
+		}
+		if event == nil {
+			return
+		}
+
+		event.Program = programID
+		event.InstructionIndex = instructionIndex
+		event.InnerIndex = innerIndex
+		events = append(events, event)
+	})
+
+	return events
+}
+
+// instructionAccount returns the key of the n-th instruction account, or the zero key if missing
+func instructionAccount(instruction solana.CompiledInstruction, n int, ctx *TransactionContext) solana.PublicKey {
+	if n >= len(instruction.Accounts) || int(instruction.Accounts[n]) >= len(ctx.AccountKeys) {
+		return solana.PublicKey{}
+	}
+	return ctx.AccountKeys[instruction.Accounts[n]]
+}
+
+// decodeNativeStake decodes the native stake program instructions that move or lock stake
+func decodeNativeStake(instruction solana.CompiledInstruction, ctx *TransactionContext) *StakeEvent {
+	data := instruction.Data
+	if len(data) < 4 {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	event := &StakeEvent{
+		Pool:         StakePoolNative,
+		StakeAccount: account(0),
+	}
+
+	switch binary.LittleEndian.Uint32(data[0:4]) {
+	case stakeDelegateInstruction:
+		event.Type = StakeEventDelegate
+		event.VoteAccount = account(1)
+		event.Authority = account(5)
+		event.Lamports = ctx.lamportsOf(event.StakeAccount)
+	case stakeSplitInstruction:
+		if len(data) < 12 {
+			return nil
+		}
+		event.Type = StakeEventSplit
+		event.TargetAccount = account(1)
+		event.Authority = account(2)
+		event.Lamports = binary.LittleEndian.Uint64(data[4:12])
+	case stakeWithdrawInstruction:
+		if len(data) < 12 {
+			return nil
+		}
+		event.Type = StakeEventWithdraw
+		event.Recipient = account(1)
+		event.Authority = account(4)
+		event.Lamports = binary.LittleEndian.Uint64(data[4:12])
+	case stakeDeactivateInstruction:
+		event.Type = StakeEventDeactivate
+		event.Authority = account(2)
+		event.Lamports = ctx.lamportsOf(event.StakeAccount)
+	case stakeMergeInstruction:
+		event.Type = StakeEventMerge
+		event.TargetAccount = account(1)
+		event.Authority = account(4)
+		event.Lamports = ctx.preLamportsOf(event.TargetAccount)
+	default:
+		return nil
+	}
+
+	return event
+}
+
+// decodeStakePool decodes SPL stake pool deposits and withdrawals, including the Jito pool
+func decodeStakePool(instruction solana.CompiledInstruction, ctx *TransactionContext) *StakeEvent {
+	data := instruction.Data
+	if len(data) < 1 {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	event := &StakeEvent{
+		Pool:         StakePoolSPL,
+		StakeAccount: account(0),
+	}
+	if event.StakeAccount.Equals(JITO_STAKE_POOL) {
+		event.Pool = StakePoolJito
+	}
+
+	var poolTokenAccount solana.PublicKey
+	switch data[0] {
+	case stakePoolDepositSolInstruction, stakePoolDepositSolWithSlippageInstruction:
+		if len(data) < 9 {
+			return nil
+		}
+		event.Type = StakeEventDeposit
+		event.Authority = account(3)
+		event.Recipient = account(4)
+		event.PoolMint = account(7)
+		event.Lamports = binary.LittleEndian.Uint64(data[1:9])
+		poolTokenAccount = event.Recipient
+	case stakePoolDepositStakeInstruction, stakePoolDepositStakeWithSlippageInstruction:
+		event.Type = StakeEventDeposit
+		event.TargetAccount = account(4)
+		event.Recipient = account(7)
+		event.PoolMint = account(10)
+		event.Lamports = ctx.preLamportsOf(event.TargetAccount)
+		poolTokenAccount = event.Recipient
+	case stakePoolWithdrawSolInstruction, stakePoolWithdrawSolWithSlippageInstruction:
+		if len(data) < 9 {
+			return nil
+		}
+		event.Type = StakeEventUnstake
+		event.Authority = account(2)
+		event.Recipient = account(5)
+		event.PoolMint = account(7)
+		event.PoolTokenAmount = binary.LittleEndian.Uint64(data[1:9])
+		event.Lamports = ctx.lamportsReceived(event.Recipient)
+	case stakePoolWithdrawStakeInstruction, stakePoolWithdrawStakeWithSlippageInstruction:
+		if len(data) < 9 {
+			return nil
+		}
+		event.Type = StakeEventUnstake
+		event.TargetAccount = account(4)
+		event.Authority = account(6)
+		event.PoolMint = account(9)
+		event.PoolTokenAmount = binary.LittleEndian.Uint64(data[1:9])
+		event.Lamports = ctx.lamportsOf(event.TargetAccount)
+	default:
+		return nil
+	}
+
+	if !poolTokenAccount.IsZero() {
+		if change, ok := ctx.TokenBalanceChange(poolTokenAccount); ok && change > 0 {
+			event.PoolTokenAmount = uint64(change)
+		}
+	}
+
+	return event
+}
+
+// decodeMarinade decodes Marinade liquid staking deposits, unstakes and claims
+func decodeMarinade(instruction solana.CompiledInstruction, ctx *TransactionContext) *StakeEvent {
+	data := instruction.Data
+	if len(data) < 8 {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return instructionAccount(instruction, n, ctx) }
+	event := &StakeEvent{
+		Pool:         StakePoolMarinade,
+		StakeAccount: account(0),
+		PoolMint:     MARINADE_MSOL_MINT,
+	}
+
+	discriminator := data[:8]
+	switch {
+	case bytes.Equal(discriminator, MARINADE_DEPOSIT_INSTRUCTION[:]):
+		if len(data) < 16 {
+			return nil
+		}
+		event.Type = StakeEventDeposit
+		event.Authority = account(6)
+		event.Recipient = account(7)
+		event.Lamports = binary.LittleEndian.Uint64(data[8:16])
+		if change, ok := ctx.TokenBalanceChange(event.Recipient); ok && change > 0 {
+			event.PoolTokenAmount = uint64(change)
+		}
+	case bytes.Equal(discriminator, MARINADE_DEPOSIT_STAKE_ACCOUNT_INSTRUCTION[:]):
+		event.Type = StakeEventDeposit
+		event.TargetAccount = account(3)
+		event.Authority = account(4)
+		event.Recipient = account(9)
+		event.Lamports = ctx.preLamportsOf(event.TargetAccount)
+		if change, ok := ctx.TokenBalanceChange(event.Recipient); ok && change > 0 {
+			event.PoolTokenAmount = uint64(change)
+		}
+	case bytes.Equal(discriminator, MARINADE_LIQUID_UNSTAKE_INSTRUCTION[:]):
+		if len(data) < 16 {
+			return nil
+		}
+		event.Type = StakeEventUnstake
+		event.Authority = account(6)
+		event.Recipient = account(7)
+		event.PoolTokenAmount = binary.LittleEndian.Uint64(data[8:16])
+		event.Lamports = ctx.lamportsReceived(event.Recipient)
+	case bytes.Equal(discriminator, MARINADE_ORDER_UNSTAKE_INSTRUCTION[:]):
+		if len(data) < 16 {
+			return nil
+		}
+		event.Type = StakeEventUnstake
+		event.Authority = account(3)
+		event.TargetAccount = account(4)
+		event.PoolTokenAmount = binary.LittleEndian.Uint64(data[8:16])
+	case bytes.Equal(discriminator, MARINADE_CLAIM_INSTRUCTION[:]):
+		event.Type = StakeEventClaim
+		event.TargetAccount = account(2)
+		event.Recipient = account(3)
+		event.Lamports = ctx.lamportsReceived(event.Recipient)
+	default:
+		return nil
+	}
+
+	return event
+}
+
+// accountIndex returns the position of the account in the account key list, or -1
+func (ctx *TransactionContext) accountIndex(account solana.PublicKey) int {
+	for i, key := range ctx.AccountKeys {
+		if key.Equals(account) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lamportsOf returns the post-transaction lamport balance of an account
+func (ctx *TransactionContext) lamportsOf(account solana.PublicKey) uint64 {
+	idx := ctx.accountIndex(account)
+	if idx < 0 || idx >= len(ctx.Meta.PostBalances) {
+		return 0
+	}
+	return ctx.Meta.PostBalances[idx]
+}
+
+// preLamportsOf returns the pre-transaction lamport balance of an account
+func (ctx *TransactionContext) preLamportsOf(account solana.PublicKey) uint64 {
+	idx := ctx.accountIndex(account)
+	if idx < 0 || idx >= len(ctx.Meta.PreBalances) {
+		return 0
+	}
+	return ctx.Meta.PreBalances[idx]
+}
+
+// lamportsReceived returns the positive lamport balance change of an account
+func (ctx *TransactionContext) lamportsReceived(account solana.PublicKey) uint64 {
+	post, pre := ctx.lamportsOf(account), ctx.preLamportsOf(account)
+	if post <= pre {
+		return 0
+	}
+	return post - pre
+}
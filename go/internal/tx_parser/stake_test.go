@@ -0,0 +1,68 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParseStakeEvents(t *testing.T) {
+	keys := newTestKeys(8)
+	authority, stake, vote, recipient, poolTokens := keys[0], keys[1], keys[2], keys[3], keys[4]
+	keys = append(keys, STAKE_PROGRAM_ID, SPL_STAKE_POOL_PROGRAM_ID, JITO_STAKE_POOL)
+	stakeIndex, poolProgramIndex, jitoIndex := uint16(8), uint16(9), uint16(10)
+
+	delegate := make([]byte, 4)
+	binary.LittleEndian.PutUint32(delegate, stakeDelegateInstruction)
+	withdraw := make([]byte, 12)
+	binary.LittleEndian.PutUint32(withdraw[0:4], stakeWithdrawInstruction)
+	binary.LittleEndian.PutUint64(withdraw[4:12], 2_000_000)
+	depositSol := make([]byte, 9)
+	depositSol[0] = stakePoolDepositSolInstruction
+	binary.LittleEndian.PutUint64(depositSol[1:9], 5_000_000_000)
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(stakeIndex, delegate, 1, 2, 6, 6, 6, 0),
+		testInstruction(stakeIndex, withdraw, 1, 3, 6, 6, 0),
+		testInstruction(poolProgramIndex, depositSol, jitoIndex, 7, 5, 0, 4, 7, 7, 6),
+		testInstruction(stakeIndex, []byte{0xff, 0, 0, 0}, 1),
+	)
+	meta := &rpc.TransactionMeta{
+		PreBalances:  make([]uint64, len(keys)),
+		PostBalances: make([]uint64, len(keys)),
+		PostTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(4, authority, keys[6], 4_200_000_000, 9),
+		},
+	}
+	meta.PostBalances[1] = 10_000_000_000
+
+	events, err := ParseStakeEvents(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse stake events: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 stake events, got %d", len(events))
+	}
+
+	del := events[0]
+	if del.Type != StakeEventDelegate || del.Pool != StakePoolNative || !del.StakeAccount.Equals(stake) || !del.VoteAccount.Equals(vote) || !del.Authority.Equals(authority) {
+		t.Errorf("unexpected delegate event: %+v", del)
+	}
+	if del.Lamports != 10_000_000_000 {
+		t.Errorf("expected delegated lamports 10000000000, got %d", del.Lamports)
+	}
+
+	wd := events[1]
+	if wd.Type != StakeEventWithdraw || wd.Lamports != 2_000_000 || !wd.Recipient.Equals(recipient) || wd.InstructionIndex != 1 {
+		t.Errorf("unexpected withdraw event: %+v", wd)
+	}
+
+	dep := events[2]
+	if dep.Type != StakeEventDeposit || dep.Pool != StakePoolJito || dep.Lamports != 5_000_000_000 {
+		t.Errorf("unexpected deposit event: %+v", dep)
+	}
+	if !dep.Recipient.Equals(poolTokens) || dep.PoolTokenAmount != 4_200_000_000 || !dep.Authority.Equals(authority) {
+		t.Errorf("unexpected deposit pool tokens: %+v", dep)
+	}
+}
@@ -0,0 +1,93 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// newTestKeys generates n random public keys
+func newTestKeys(n int) []solana.PublicKey {
+	keys := make([]solana.PublicKey, n)
+	for i := range keys {
+		keys[i] = solana.NewWallet().PublicKey()
+	}
+	return keys
+}
+
+// newTestTransaction builds an unsigned transaction whose first numSigners keys are signers
+func newTestTransaction(keys []solana.PublicKey, numSigners int, instructions ...solana.CompiledInstruction) *solana.Transaction {
+	return &solana.Transaction{
+		Signatures: make([]solana.Signature, numSigners),
+		Message: solana.Message{
+			AccountKeys: keys,
+			Header: solana.MessageHeader{
+				NumRequiredSignatures: uint8(numSigners),
+			},
+			Instructions: instructions,
+		},
+	}
+}
+
+// newTestContext builds a parsing context for a synthetic transaction
+func newTestContext(tx *solana.Transaction, meta *rpc.TransactionMeta) *TransactionContext {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		panic(err)
+	}
+	return ctx
+}
+
+// testTokenBalance builds a token balance entry for the given account index
+func testTokenBalance(accountIndex uint16, owner, mint solana.PublicKey, amount uint64, decimals uint8) rpc.TokenBalance {
+	return rpc.TokenBalance{
+		AccountIndex: accountIndex,
+		Owner:        &owner,
+		Mint:         mint,
+		UiTokenAmount: &rpc.UiTokenAmount{
+			Amount:   strconv.FormatUint(amount, 10),
+			Decimals: decimals,
+		},
+	}
+}
+
+// testInstruction builds a compiled instruction
+func testInstruction(programIndex uint16, data []byte, accounts ...uint16) solana.CompiledInstruction {
+	return solana.CompiledInstruction{
+		ProgramIDIndex: programIndex,
+		Accounts:       accounts,
+		Data:           data,
+	}
+}
+
+// tokenTransferData encodes a token program Transfer instruction
+func tokenTransferData(amount uint64) []byte {
+	data := make([]byte, 9)
+	data[0] = tokenTransferInstruction
+	binary.LittleEndian.PutUint64(data[1:], amount)
+	return data
+}
+
+// tokenTransferCheckedData encodes a token program TransferChecked instruction
+func tokenTransferCheckedData(amount uint64, decimals uint8) []byte {
+	data := make([]byte, 10)
+	data[0] = tokenTransferCheckedInstruction
+	binary.LittleEndian.PutUint64(data[1:9], amount)
+	data[9] = decimals
+	return data
+}
+
+// systemTransferData encodes a system program Transfer instruction
+func systemTransferData(lamports uint64) []byte {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], systemTransferInstruction)
+	binary.LittleEndian.PutUint64(data[4:12], lamports)
+	return data
+}
+
+// newTestParser builds a parser over a synthetic transaction with all handlers registered
+func newTestParser(tx *solana.Transaction, meta *rpc.TransactionMeta, opts ParseOptions) *Parser {
+	return NewFromContext(newTestContext(tx, meta), opts)
+}
@@ -0,0 +1,176 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Token-2022 mint layout: the base mint padded to the size of a token account, then the
+// account type and the extension entries
+const (
+	token2022MintLength        = 82
+	token2022AccountTypeOffset = 165
+	token2022MintAccountType   = 1
+)
+
+// Token-2022 extension types that change how amounts are read
+const (
+	transferFeeConfigExtension        = 1
+	confidentialTransferMintExtension = 4
+	interestBearingConfigExtension    = 10
+	transferHookExtension             = 14
+	scaledUIAmountExtension           = 25
+)
+
+// tokenExtensionNames names the Token-2022 extension types by their value
+var tokenExtensionNames = []string{
+	"Uninitialized",
+	"TransferFeeConfig",
+	"TransferFeeAmount",
+	"MintCloseAuthority",
+	"ConfidentialTransferMint",
+	"ConfidentialTransferAccount",
+	"DefaultAccountState",
+	"ImmutableOwner",
+	"MemoTransfer",
+	"NonTransferable",
+	"InterestBearingConfig",
+	"CpiGuard",
+	"PermanentDelegate",
+	"NonTransferableAccount",
+	"TransferHook",
+	"TransferHookAccount",
+	"ConfidentialTransferFeeConfig",
+	"ConfidentialTransferFeeAmount",
+	"MetadataPointer",
+	"TokenMetadata",
+	"GroupPointer",
+	"TokenGroup",
+	"GroupMemberPointer",
+	"TokenGroupMember",
+	"ConfidentialMintBurn",
+	"ScaledUiAmount",
+	"Pausable",
+	"PausableAccount",
+}
+
+// TokenExtensionName names a Token-2022 extension type, e.g. TransferHook
+func TokenExtensionName(kind uint16) string {
+	if int(kind) < len(tokenExtensionNames) {
+		return tokenExtensionNames[kind]
+	}
+	return fmt.Sprintf("Unknown(%d)", kind)
+}
+
+// DecodeTokenExtensions summarizes the extensions of a Token-2022 mint account, returning
+// nil for mints without extensions
+func DecodeTokenExtensions(data []byte) (*TokenExtensions, error) {
+	if len(data) < token2022MintLength {
+		return nil, fmt.Errorf("account of %d bytes is not a mint", len(data))
+	}
+	if len(data) <= token2022AccountTypeOffset || data[token2022AccountTypeOffset] != token2022MintAccountType {
+		return nil, nil
+	}
+
+	extensions := &TokenExtensions{}
+	for offset := token2022AccountTypeOffset + 1; offset+4 <= len(data); {
+		kind := binary.LittleEndian.Uint16(data[offset:])
+		length := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		offset += 4
+		if kind == 0 {
+			break
+		}
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("extension %d overruns the account", kind)
+		}
+		value := data[offset : offset+length]
+		offset += length
+
+		extensions.Extensions = append(extensions.Extensions, TokenExtensionName(kind))
+		switch kind {
+		case transferFeeConfigExtension:
+			// authorities (2 x 32), withheld amount u64, older fee (epoch u64, maximum u64,
+			// bps u16), then the newer fee's epoch, maximum and bps
+			if len(value) >= 108 {
+				extensions.MaxTransferFee = binary.LittleEndian.Uint64(value[98:106])
+				extensions.TransferFeeBasisPoints = binary.LittleEndian.Uint16(value[106:108])
+			}
+		case confidentialTransferMintExtension:
+			extensions.ConfidentialTransfers = true
+		case interestBearingConfigExtension:
+			// rate authority, initialization timestamp i64, average rate i16, last update
+			// timestamp i64, current rate i16
+			if len(value) >= 52 {
+				extensions.InterestRate = int16(binary.LittleEndian.Uint16(value[50:52]))
+			}
+		case transferHookExtension:
+			// authority, program
+			if len(value) >= 64 {
+				extensions.TransferHookProgram = solana.PublicKeyFromBytes(value[32:64])
+			}
+		case scaledUIAmountExtension:
+			// authority, multiplier f64, ...
+			if len(value) >= 40 {
+				extensions.ScaledUIMultiplier = math.Float64frombits(binary.LittleEndian.Uint64(value[32:40]))
+			}
+		}
+	}
+	if len(extensions.Extensions) == 0 {
+		return nil, nil
+	}
+	return extensions, nil
+}
+
+// isToken2022Mint reports whether the token balances place the mint under Token-2022.
+// Balances from older nodes omit the program, in which case known is false.
+func (ctx *TransactionContext) isToken2022Mint(mint solana.PublicKey) (token2022, known bool) {
+	for _, balance := range ctx.Meta.PostTokenBalances {
+		if balance.Mint.Equals(mint) && balance.ProgramId != nil {
+			return balance.ProgramId.Equals(solana.Token2022ProgramID), true
+		}
+	}
+	for _, balance := range ctx.Meta.PreTokenBalances {
+		if balance.Mint.Equals(mint) && balance.ProgramId != nil {
+			return balance.ProgramId.Equals(solana.Token2022ProgramID), true
+		}
+	}
+	return false, false
+}
+
+// GetTokenExtensions returns the extension summary of a Token-2022 mint, or nil for
+// legacy token mints, mints without extensions or without a TokenExtensionsResolver
+func (ctx *TransactionContext) GetTokenExtensions(mint solana.PublicKey) *TokenExtensions {
+	if ctx.TokenExtensionsResolver == nil || mint.IsZero() || mint.Equals(NATIVE_SOL_PROGRAM_ID) {
+		return nil
+	}
+	if token2022, known := ctx.isToken2022Mint(mint); known && !token2022 {
+		return nil
+	}
+	if extensions, ok := ctx.tokenExtensions[mint]; ok {
+		return extensions
+	}
+
+	extensions, err := ctx.TokenExtensionsResolver.MintExtensions(mint)
+	if err != nil {
+		return nil
+	}
+	if ctx.tokenExtensions == nil {
+		ctx.tokenExtensions = make(map[solana.PublicKey]*TokenExtensions)
+	}
+	ctx.tokenExtensions[mint] = extensions
+	return extensions
+}
+
+// attachTokenExtensions sets the extension summary of the Token-2022 mints swapped
+func attachTokenExtensions(ctx *TransactionContext, swaps []*SwapInfo) {
+	if ctx.TokenExtensionsResolver == nil {
+		return
+	}
+	for _, swap := range swaps {
+		swap.TokenIn.Extensions = ctx.GetTokenExtensions(swap.TokenIn.Mint)
+		swap.TokenOut.Extensions = ctx.GetTokenExtensions(swap.TokenOut.Mint)
+	}
+}
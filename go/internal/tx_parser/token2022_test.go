@@ -0,0 +1,111 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// token2022MintData encodes a Token-2022 mint with the given extension entries
+func token2022MintData(entries ...[]byte) []byte {
+	data := make([]byte, token2022AccountTypeOffset)
+	data = append(data, token2022MintAccountType)
+	for _, entry := range entries {
+		data = append(data, entry...)
+	}
+	return data
+}
+
+// tokenExtensionEntry encodes a TLV extension entry
+func tokenExtensionEntry(kind uint16, value []byte) []byte {
+	entry := binary.LittleEndian.AppendUint16(nil, kind)
+	entry = binary.LittleEndian.AppendUint16(entry, uint16(len(value)))
+	return append(entry, value...)
+}
+
+type countingExtensionsResolver struct {
+	extensions map[solana.PublicKey]*TokenExtensions
+	calls      int
+}
+
+func (r *countingExtensionsResolver) MintExtensions(mint solana.PublicKey) (*TokenExtensions, error) {
+	r.calls++
+	return r.extensions[mint], nil
+}
+
+func TestDecodeTokenExtensions(t *testing.T) {
+	hook := solana.NewWallet().PublicKey()
+
+	transferFee := make([]byte, 108)
+	binary.LittleEndian.PutUint64(transferFee[98:], 5_000)
+	binary.LittleEndian.PutUint16(transferFee[106:], 250)
+	interest := make([]byte, 52)
+	rate := int16(-15)
+	binary.LittleEndian.PutUint16(interest[50:], uint16(rate))
+	scaled := make([]byte, 56)
+	binary.LittleEndian.PutUint64(scaled[32:], math.Float64bits(1.5))
+
+	extensions, err := DecodeTokenExtensions(token2022MintData(
+		tokenExtensionEntry(transferFeeConfigExtension, transferFee),
+		tokenExtensionEntry(transferHookExtension, append(make([]byte, 32), hook[:]...)),
+		tokenExtensionEntry(interestBearingConfigExtension, interest),
+		tokenExtensionEntry(confidentialTransferMintExtension, make([]byte, 65)),
+		tokenExtensionEntry(scaledUIAmountExtension, scaled),
+		tokenExtensionEntry(99, nil),
+	))
+	if err != nil {
+		t.Fatalf("failed to decode extensions: %v", err)
+	}
+	if len(extensions.Extensions) != 6 || extensions.Extensions[1] != "TransferHook" || extensions.Extensions[5] != "Unknown(99)" {
+		t.Errorf("unexpected extension names: %v", extensions.Extensions)
+	}
+	if !extensions.TransferHookProgram.Equals(hook) || extensions.TransferFeeBasisPoints != 250 || extensions.MaxTransferFee != 5_000 {
+		t.Errorf("unexpected transfer hook or fee: %+v", extensions)
+	}
+	if extensions.InterestRate != -15 || !extensions.ConfidentialTransfers || extensions.ScaledUIMultiplier != 1.5 {
+		t.Errorf("unexpected interest, confidential or scaled settings: %+v", extensions)
+	}
+
+	if extensions, err := DecodeTokenExtensions(make([]byte, token2022MintLength)); err != nil || extensions != nil {
+		t.Errorf("expected a base mint to have no extensions, got %+v, %v", extensions, err)
+	}
+	if _, err := DecodeTokenExtensions(token2022MintData(tokenExtensionEntry(transferHookExtension, nil)[:2], []byte{64, 0})); err == nil {
+		t.Error("expected an overrunning extension to fail")
+	}
+}
+
+func TestSwapTokenExtensions(t *testing.T) {
+	keys := newTestKeys(3)
+	token2022Mint, legacyMint := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	tx := newTestTransaction(keys, 1)
+
+	token2022, legacy := solana.Token2022ProgramID, solana.TokenProgramID
+	token2022Balance := testTokenBalance(1, keys[0], token2022Mint, 10, 6)
+	token2022Balance.ProgramId = &token2022
+	legacyBalance := testTokenBalance(2, keys[0], legacyMint, 10, 6)
+	legacyBalance.ProgramId = &legacy
+
+	hooked := &TokenExtensions{Extensions: []string{"TransferHook"}, TransferHookProgram: keys[2]}
+	resolver := &countingExtensionsResolver{extensions: map[solana.PublicKey]*TokenExtensions{token2022Mint: hooked, legacyMint: hooked}}
+	parser := newTestParser(tx, &rpc.TransactionMeta{PostTokenBalances: []rpc.TokenBalance{token2022Balance, legacyBalance}},
+		ParseOptions{TokenExtensionsResolver: resolver})
+
+	swaps := []*SwapInfo{
+		{TokenIn: TokenInfo{Mint: NATIVE_SOL_PROGRAM_ID}, TokenOut: TokenInfo{Mint: token2022Mint}},
+		{TokenIn: TokenInfo{Mint: token2022Mint}, TokenOut: TokenInfo{Mint: legacyMint}},
+	}
+	attachTokenExtensions(parser.ctx, swaps)
+
+	if swaps[0].TokenOut.Extensions != hooked || swaps[1].TokenIn.Extensions != hooked {
+		t.Errorf("expected the Token-2022 mint to carry its extensions, got %+v", swaps)
+	}
+	if swaps[0].TokenIn.Extensions != nil || swaps[1].TokenOut.Extensions != nil {
+		t.Error("expected SOL and legacy token mints to be skipped")
+	}
+	if resolver.calls != 1 {
+		t.Errorf("expected one lookup of the Token-2022 mint, got %d", resolver.calls)
+	}
+}
@@ -0,0 +1,182 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Token program instruction indices for supply and admin actions
+const (
+	tokenSetAuthorityInstruction  = 6
+	tokenMintToInstruction        = 7
+	tokenBurnInstruction          = 8
+	tokenFreezeInstruction        = 10
+	tokenThawInstruction          = 11
+	tokenMintToCheckedInstruction = 14
+	tokenBurnCheckedInstruction   = 15
+)
+
+// tokenAuthorityTypes names the SetAuthority authority types of both token programs
+var tokenAuthorityTypes = []string{
+	"MintTokens",
+	"FreezeAccount",
+	"AccountOwner",
+	"CloseAccount",
+	"TransferFeeConfig",
+	"WithheldWithdraw",
+	"CloseMint",
+	"InterestRate",
+	"PermanentDelegate",
+	"ConfidentialTransferMint",
+	"TransferHookProgramId",
+	"ConfidentialTransferFeeConfig",
+	"MetadataPointer",
+	"GroupPointer",
+	"GroupMemberPointer",
+	"ScaledUiAmount",
+	"Pause",
+}
+
+// ParseTokenEvents returns the mint, burn, authority change and freeze/thaw actions
+// of both token programs in the transaction
+func ParseTokenEvents(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*TokenSupplyEvent, []*TokenAdminEvent, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	supply, admin := parseTokenEvents(ctx)
+	return supply, admin, nil
+}
+
+// ParseTokenEvents returns the token supply and admin actions of the parsed transaction
+func (p *Parser) ParseTokenEvents() ([]*TokenSupplyEvent, []*TokenAdminEvent, error) {
+	supply, admin := parseTokenEvents(p.ctx)
+	return supply, admin, nil
+}
+
+func parseTokenEvents(ctx *TransactionContext) ([]*TokenSupplyEvent, []*TokenAdminEvent) {
+	accounts := ctx.tokenAccounts()
+
+	var supplyEvents []*TokenSupplyEvent
+	var adminEvents []*TokenAdminEvent
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if !hasValidIndices(instruction, ctx.AccountKeys) || len(instruction.Data) < 1 {
+			return
+		}
+
+		programID := ctx.AccountKeys[instruction.ProgramIDIndex]
+		if !programID.Equals(solana.TokenProgramID) && !programID.Equals(solana.Token2022ProgramID) {
+			return
+		}
+
+		if event := decodeTokenSupply(instruction, ctx, accounts); event != nil {
+			event.Program = programID
+			event.InstructionIndex = instructionIndex
+			event.InnerIndex = innerIndex
+			supplyEvents = append(supplyEvents, event)
+			return
+		}
+		if event := decodeTokenAdmin(instruction, ctx, accounts); event != nil {
+			event.Program = programID
+			event.InstructionIndex = instructionIndex
+			event.InnerIndex = innerIndex
+			adminEvents = append(adminEvents, event)
+		}
+	})
+
+	return supplyEvents, adminEvents
+}
+
+// decodeTokenSupply handles MintTo, Burn and their checked variants
+func decodeTokenSupply(instruction solana.CompiledInstruction, ctx *TransactionContext, accounts map[solana.PublicKey]tokenAccountInfo) *TokenSupplyEvent {
+	data := instruction.Data
+	if len(data) < 9 || len(instruction.Accounts) < 3 {
+		return nil
+	}
+
+	account := func(n int) solana.PublicKey { return ctx.AccountKeys[instruction.Accounts[n]] }
+
+	// Decode before allocating, most token instructions are transfers
+	var supplyType TokenSupplyEventType
+	var mint, tokenAccount solana.PublicKey
+	switch data[0] {
+	case tokenMintToInstruction, tokenMintToCheckedInstruction:
+		supplyType, mint, tokenAccount = TokenSupplyMint, account(0), account(1)
+	case tokenBurnInstruction, tokenBurnCheckedInstruction:
+		supplyType, tokenAccount, mint = TokenSupplyBurn, account(0), account(1)
+	default:
+		return nil
+	}
+
+	event := &TokenSupplyEvent{
+		Type:      supplyType,
+		Mint:      mint,
+		Account:   tokenAccount,
+		Authority: account(2),
+		Amount:    binary.LittleEndian.Uint64(data[1:9]),
+	}
+
+	event.Decimals = ctx.GetMintDecimals(event.Mint)
+	if (data[0] == tokenMintToCheckedInstruction || data[0] == tokenBurnCheckedInstruction) && len(data) >= 10 {
+		event.Decimals = data[9]
+	}
+	if info, ok := accounts[event.Account]; ok {
+		event.Owner = info.Owner
+	}
+
+	return event
+}
+
+// decodeTokenAdmin handles SetAuthority, FreezeAccount and ThawAccount
+func decodeTokenAdmin(instruction solana.CompiledInstruction, ctx *TransactionContext, accounts map[solana.PublicKey]tokenAccountInfo) *TokenAdminEvent {
+	data := instruction.Data
+	account := func(n int) solana.PublicKey { return ctx.AccountKeys[instruction.Accounts[n]] }
+
+	switch data[0] {
+	case tokenSetAuthorityInstruction:
+		// authority_type: u8, new_authority: COption<Pubkey> encoded as a u8 tag and 32 bytes
+		if len(data) < 3 || len(instruction.Accounts) < 2 {
+			return nil
+		}
+		event := &TokenAdminEvent{
+			Type:          TokenAdminSetAuthority,
+			Account:       account(0),
+			Authority:     account(1),
+			AuthorityType: "Unknown",
+		}
+		if int(data[1]) < len(tokenAuthorityTypes) {
+			event.AuthorityType = tokenAuthorityTypes[data[1]]
+		}
+		if data[2] == 1 && len(data) >= 35 {
+			newAuthority := solana.PublicKeyFromBytes(data[3:35])
+			event.NewAuthority = &newAuthority
+		}
+
+		// The account is either a mint or a token account
+		if info, ok := accounts[event.Account]; ok {
+			event.Mint = info.Mint
+		} else {
+			event.Mint = event.Account
+		}
+		return event
+	case tokenFreezeInstruction, tokenThawInstruction:
+		if len(instruction.Accounts) < 3 {
+			return nil
+		}
+		event := &TokenAdminEvent{
+			Type:      TokenAdminFreeze,
+			Account:   account(0),
+			Mint:      account(1),
+			Authority: account(2),
+		}
+		if data[0] == tokenThawInstruction {
+			event.Type = TokenAdminThaw
+		}
+		return event
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParseTokenEvents(t *testing.T) {
+	keys := newTestKeys(5)
+	authority, mint, ata, holder, newAuthority := keys[0], keys[1], keys[2], keys[3], keys[4]
+	keys = append(keys, solana.TokenProgramID, solana.Token2022ProgramID)
+	tokenIndex, token2022Index := uint16(5), uint16(6)
+
+	mintTo := make([]byte, 9)
+	mintTo[0] = tokenMintToInstruction
+	binary.LittleEndian.PutUint64(mintTo[1:], 1_000_000)
+	burnChecked := make([]byte, 10)
+	burnChecked[0] = tokenBurnCheckedInstruction
+	binary.LittleEndian.PutUint64(burnChecked[1:9], 250)
+	burnChecked[9] = 6
+	revokeMint := []byte{tokenSetAuthorityInstruction, 0, 0}
+	transferFreeze := append([]byte{tokenSetAuthorityInstruction, 1, 1}, newAuthority.Bytes()...)
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(tokenIndex, mintTo, 1, 2, 0),
+		testInstruction(token2022Index, burnChecked, 2, 1, 3),
+		testInstruction(tokenIndex, revokeMint, 1, 0),
+		testInstruction(tokenIndex, transferFreeze, 1, 0),
+		testInstruction(tokenIndex, []byte{tokenFreezeInstruction}, 2, 1, 0),
+		testInstruction(tokenIndex, tokenTransferData(5), 2, 3, 0),
+	)
+	meta := &rpc.TransactionMeta{
+		PostTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(2, holder, mint, 999_750, 6),
+		},
+	}
+
+	supply, admin, err := ParseTokenEvents(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse token events: %v", err)
+	}
+	if len(supply) != 2 || len(admin) != 3 {
+		t.Fatalf("expected 2 supply and 3 admin events, got %d and %d", len(supply), len(admin))
+	}
+
+	minted := supply[0]
+	if minted.Type != TokenSupplyMint || minted.Amount != 1_000_000 || !minted.Mint.Equals(mint) || !minted.Owner.Equals(holder) || minted.Decimals != 6 {
+		t.Errorf("unexpected mint event: %+v", minted)
+	}
+	burned := supply[1]
+	if burned.Type != TokenSupplyBurn || burned.Amount != 250 || !burned.Program.Equals(solana.Token2022ProgramID) || !burned.Authority.Equals(holder) {
+		t.Errorf("unexpected burn event: %+v", burned)
+	}
+
+	revoke := admin[0]
+	if revoke.AuthorityType != "MintTokens" || revoke.NewAuthority != nil || !revoke.Mint.Equals(mint) {
+		t.Errorf("unexpected revoke event: %+v", revoke)
+	}
+	change := admin[1]
+	if change.AuthorityType != "FreezeAccount" || change.NewAuthority == nil || !change.NewAuthority.Equals(newAuthority) {
+		t.Errorf("unexpected authority change: %+v", change)
+	}
+	freeze := admin[2]
+	if freeze.Type != TokenAdminFreeze || !freeze.Account.Equals(ata) || !freeze.Authority.Equals(authority) {
+		t.Errorf("unexpected freeze event: %+v", freeze)
+	}
+}
@@ -0,0 +1,180 @@
+package tx_parser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// transferSwapLayout identifies the swap instructions of a program and locates the user's
+// two token accounts of the pair, in either order. Negative positions count from the
+// end, for layouts with a variable number of pool accounts.
+type transferSwapLayout struct {
+	protocol       SwapType
+	program        solana.PublicKey
+	discriminators [][]byte // leading bytes of the swap instruction data
+	data           int      // minimum instruction data length
+	accounts       int      // minimum number of accounts
+	userA          int
+	userB          int
+}
+
+// anchorSwapDiscriminator is shared by every Anchor program naming its instruction swap
+var anchorSwapDiscriminator = []byte{248, 198, 158, 145, 225, 117, 135, 200}
+
+// Discriminators of the swap instructions of Raydium CPMM and its forks
+var (
+	cpmmSwapBaseInputDiscriminator  = []byte{143, 190, 90, 218, 196, 30, 51, 222}
+	cpmmSwapBaseOutputDiscriminator = []byte{55, 217, 98, 86, 163, 74, 180, 173}
+)
+
+var (
+	// swap: swap info, authority, user authority, source, pool source, pool destination,
+	// destination, admin fee destination, token program
+	saberLayout = transferSwapLayout{protocol: SwapTypeSaber, program: SABER_PROGRAM_ID, discriminators: [][]byte{{1}}, data: 17, accounts: 8, userA: 3, userB: 6}
+
+	// exchange: swap info, token program, pool authority, user authority, one pool token
+	// account per coin, source, destination
+	mercurialLayout = transferSwapLayout{protocol: SwapTypeMercurial, program: MERCURIAL_PROGRAM_ID, discriminators: [][]byte{{4}}, data: 17, accounts: 8, userA: -2, userB: -1}
+
+	// swap: state, pool, tickmap, token x, token y, account x, account y, reserve x,
+	// reserve y, owner, program authority, token program
+	invariantLayout = transferSwapLayout{protocol: SwapTypeInvariant, program: INVARIANT_PROGRAM_ID, discriminators: [][]byte{anchorSwapDiscriminator}, data: 8, accounts: 12, userA: 5, userB: 6}
+
+	// swap: pool, partner, partner account a, partner account b, pool account a,
+	// pool account b, account a, account b, tick array map, owner, token program
+	cremaLayout = transferSwapLayout{protocol: SwapTypeCrema, program: CREMA_PROGRAM_ID, discriminators: [][]byte{anchorSwapDiscriminator}, data: 8, accounts: 11, userA: 6, userB: 7}
+
+	// swap: the SPL token swap layout, followed by the mints and token programs of both
+	// sides so pools can hold Token-2022 mints
+	fluxBeamLayout = transferSwapLayout{protocol: SwapTypeFluxBeam, program: FLUXBEAM_PROGRAM_ID, discriminators: [][]byte{{1}}, data: 17, accounts: 9, userA: 3, userB: 6}
+
+	// swap_base_input and swap_base_output: the Raydium CPMM layout, payer, authority,
+	// config, pool, input account, output account, input vault, output vault, ...
+	gooseFXGammaLayout = transferSwapLayout{protocol: SwapTypeGooseFX, program: GOOSEFX_GAMMA_PROGRAM_ID,
+		discriminators: [][]byte{cpmmSwapBaseInputDiscriminator, cpmmSwapBaseOutputDiscriminator}, data: 24, accounts: 12, userA: 4, userB: 5}
+
+	// swap: user, user token in, user token out, vault token in, vault token out,
+	// beneficiary token out, pool, withdraw authority, vault, ...
+	stabbleStableLayout = transferSwapLayout{protocol: SwapTypeStabble, program: STABBLE_STABLE_SWAP_PROGRAM_ID,
+		discriminators: [][]byte{anchorSwapDiscriminator}, data: 8, accounts: 10, userA: 1, userB: 2}
+	stabbleWeightedLayout = transferSwapLayout{protocol: SwapTypeStabble, program: STABBLE_WEIGHTED_SWAP_PROGRAM_ID,
+		discriminators: [][]byte{anchorSwapDiscriminator}, data: 8, accounts: 10, userA: 1, userB: 2}
+)
+
+// TransferSwapParser handles parsing swaps on AMMs that settle with one transfer out of
+// the user's source account and one into the destination account, such as Curve style
+// stable swaps and concentrated liquidity pools. Fees paid from the pool reserves to
+// other accounts are not counted.
+type TransferSwapParser struct {
+	layouts []transferSwapLayout
+}
+
+// NewSaberParser creates a new Saber stable swap parser
+func NewSaberParser() *TransferSwapParser {
+	return &TransferSwapParser{layouts: []transferSwapLayout{saberLayout}}
+}
+
+// NewMercurialParser creates a new Mercurial stable swap parser
+func NewMercurialParser() *TransferSwapParser {
+	return &TransferSwapParser{layouts: []transferSwapLayout{mercurialLayout}}
+}
+
+// NewInvariantParser creates a new Invariant concentrated liquidity parser
+func NewInvariantParser() *TransferSwapParser {
+	return &TransferSwapParser{layouts: []transferSwapLayout{invariantLayout}}
+}
+
+// NewCremaParser creates a new Crema concentrated liquidity parser
+func NewCremaParser() *TransferSwapParser {
+	return &TransferSwapParser{layouts: []transferSwapLayout{cremaLayout}}
+}
+
+// NewFluxBeamParser creates a new FluxBeam parser
+func NewFluxBeamParser() *TransferSwapParser {
+	return &TransferSwapParser{layouts: []transferSwapLayout{fluxBeamLayout}}
+}
+
+// NewGooseFXParser creates a new parser for GooseFX GAMMA pools
+func NewGooseFXParser() *TransferSwapParser {
+	return &TransferSwapParser{layouts: []transferSwapLayout{gooseFXGammaLayout}}
+}
+
+// NewStabbleParser creates a new parser for Stabble stable and weighted pools
+func NewStabbleParser() *TransferSwapParser {
+	return &TransferSwapParser{layouts: []transferSwapLayout{stabbleStableLayout, stabbleWeightedLayout}}
+}
+
+// CanHandle checks if this parser can handle the given instruction
+func (p *TransferSwapParser) CanHandle(instruction solana.CompiledInstruction, accountKeys []solana.PublicKey) bool {
+	if !hasValidIndices(instruction, accountKeys) {
+		return false
+	}
+	return p.match(instruction, accountKeys[instruction.ProgramIDIndex]) != nil
+}
+
+// match returns the layout of the program the instruction is a swap of, or nil
+func (p *TransferSwapParser) match(instruction solana.CompiledInstruction, programID solana.PublicKey) *transferSwapLayout {
+	for i := range p.layouts {
+		layout := &p.layouts[i]
+		if !programID.Equals(layout.program) || len(instruction.Data) < layout.data || len(instruction.Accounts) < layout.accounts {
+			continue
+		}
+		for _, discriminator := range layout.discriminators {
+			if bytes.HasPrefix(instruction.Data, discriminator) {
+				return layout
+			}
+		}
+	}
+	return nil
+}
+
+// ParseInstruction processes the swap instruction and returns swap information
+func (p *TransferSwapParser) ParseInstruction(instruction solana.CompiledInstruction, instructionIndex int, ctx *TransactionContext) ([]*SwapInfo, error) {
+	if int(instruction.ProgramIDIndex) >= len(ctx.AccountKeys) {
+		return nil, fmt.Errorf("invalid program index %d", instruction.ProgramIDIndex)
+	}
+	layout := p.match(instruction, ctx.AccountKeys[instruction.ProgramIDIndex])
+	if layout == nil {
+		return nil, fmt.Errorf("not a supported swap instruction")
+	}
+	userA := instructionAccount(instruction, layoutPosition(instruction, layout.userA), ctx)
+	userB := instructionAccount(instruction, layoutPosition(instruction, layout.userB), ctx)
+
+	var in, out *TransferInfo
+	accounts := ctx.tokenAccounts()
+	for _, innerSet := range ctx.Meta.InnerInstructions {
+		if innerSet.Index != uint16(instructionIndex) {
+			continue
+		}
+		for _, innerInstr := range innerSet.Instructions {
+			transfer := decodeTransfer(innerInstr, ctx, accounts)
+			switch {
+			case transfer == nil:
+			case in == nil && (transfer.Source.Equals(userA) || transfer.Source.Equals(userB)):
+				in = transfer
+			case out == nil && (transfer.Destination.Equals(userA) || transfer.Destination.Equals(userB)):
+				out = transfer
+			}
+		}
+	}
+
+	if in == nil || out == nil || in.Source.Equals(out.Destination) || in.Amount == 0 || out.Amount == 0 {
+		return nil, fmt.Errorf("no %s swap transfers found", layout.protocol)
+	}
+
+	return []*SwapInfo{{
+		Protocol: layout.protocol,
+		TokenIn:  TokenInfo{Mint: in.Mint, Amount: in.Amount, Decimals: in.Decimals},
+		TokenOut: TokenInfo{Mint: out.Mint, Amount: out.Amount, Decimals: out.Decimals},
+	}}, nil
+}
+
+// layoutPosition resolves a layout position against the instruction's accounts
+func layoutPosition(instruction solana.CompiledInstruction, n int) int {
+	if n < 0 {
+		return len(instruction.Accounts) + n
+	}
+	return n
+}
@@ -0,0 +1,102 @@
+package tx_parser
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestTransferSwapParsers(t *testing.T) {
+	// 0 user, 1 swap info, 2 user USDC, 3 user USDT, 4 pool USDC, 5 pool USDT, 6 admin fee,
+	// 7 pool third coin, 8 USDC mint, 9 USDT mint
+	keys := newTestKeys(10)
+	user, usdc, usdt := keys[0], keys[8], keys[9]
+	keys = append(keys, SABER_PROGRAM_ID, MERCURIAL_PROGRAM_ID, INVARIANT_PROGRAM_ID, CREMA_PROGRAM_ID, FLUXBEAM_PROGRAM_ID, solana.TokenProgramID)
+	saberIndex, mercurialIndex, invariantIndex, cremaIndex, fluxBeamIndex, tokenIndex := uint16(10), uint16(11), uint16(12), uint16(13), uint16(14), uint16(15)
+	keys = append(keys, GOOSEFX_GAMMA_PROGRAM_ID, STABBLE_STABLE_SWAP_PROGRAM_ID, STABBLE_WEIGHTED_SWAP_PROGRAM_ID)
+	gammaIndex, stabbleIndex, weightedIndex := uint16(16), uint16(17), uint16(18)
+
+	swapData := func(tag byte) []byte { return append([]byte{tag}, make([]byte, 16)...) }
+	anchorSwapData := append(anchorSwapDiscriminator[:8:8], make([]byte, 25)...)
+	meta := func(inner ...solana.CompiledInstruction) *rpc.TransactionMeta {
+		return &rpc.TransactionMeta{
+			InnerInstructions: []rpc.InnerInstruction{{Index: 0, Instructions: inner}},
+			PreTokenBalances: []rpc.TokenBalance{
+				testTokenBalance(2, user, usdc, 5_000_000, 6),
+				testTokenBalance(3, user, usdt, 0, 6),
+				testTokenBalance(4, keys[1], usdc, 900_000_000, 6),
+				testTokenBalance(5, keys[1], usdt, 900_000_000, 6),
+				testTokenBalance(6, keys[1], usdt, 0, 6),
+			},
+		}
+	}
+	// The pool pays the output and the admin fee out of its reserves
+	transfers := []solana.CompiledInstruction{
+		testInstruction(tokenIndex, tokenTransferData(1_000_000), 2, 4, 0),
+		testInstruction(tokenIndex, tokenTransferData(999_500), 5, 3, 1),
+		testInstruction(tokenIndex, tokenTransferData(50), 5, 6, 1),
+	}
+
+	tests := []struct {
+		name        string
+		instruction solana.CompiledInstruction
+		protocol    SwapType
+	}{
+		{"Saber", testInstruction(saberIndex, swapData(1), 1, 1, 0, 2, 4, 5, 3, 6, 15), SwapTypeSaber},
+		{"Mercurial", testInstruction(mercurialIndex, swapData(4), 1, 15, 1, 0, 4, 5, 7, 2, 3), SwapTypeMercurial},
+		{"Invariant", testInstruction(invariantIndex, anchorSwapData, 1, 1, 1, 8, 9, 2, 3, 4, 5, 0, 1, 15), SwapTypeInvariant},
+		{"Crema", testInstruction(cremaIndex, anchorSwapData, 1, 1, 7, 7, 4, 5, 2, 3, 1, 0, 15), SwapTypeCrema},
+		{"FluxBeam", testInstruction(fluxBeamIndex, swapData(1), 1, 1, 0, 2, 4, 5, 3, 1, 6, 8, 9, 15, 15), SwapTypeFluxBeam},
+		{"GooseFX GAMMA", testInstruction(gammaIndex, append(cpmmSwapBaseOutputDiscriminator[:8:8], make([]byte, 16)...), 0, 1, 1, 1, 2, 3, 4, 5, 15, 15, 8, 9, 1), SwapTypeGooseFX},
+		{"Stabble stable", testInstruction(stabbleIndex, anchorSwapData, 0, 2, 3, 4, 5, 6, 1, 1, 1, 1, 1, 15), SwapTypeStabble},
+		{"Stabble weighted", testInstruction(weightedIndex, anchorSwapData, 0, 2, 3, 4, 5, 6, 1, 1, 1, 1, 1, 15), SwapTypeStabble},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTestTransaction(keys, 1, tt.instruction)
+			swaps, err := newTestParser(tx, meta(transfers...), ParseOptions{}).ParseTransaction()
+			if err != nil {
+				t.Fatalf("failed to parse swap: %v", err)
+			}
+			if len(swaps) != 1 || swaps[0].Protocol != tt.protocol {
+				t.Fatalf("expected one %s swap, got %+v", tt.protocol, swaps)
+			}
+			if !swaps[0].TokenIn.Mint.Equals(usdc) || swaps[0].TokenIn.Amount != 1_000_000 || swaps[0].TokenIn.Decimals != 6 {
+				t.Errorf("unexpected token in: %+v", swaps[0].TokenIn)
+			}
+			if !swaps[0].TokenOut.Mint.Equals(usdt) || swaps[0].TokenOut.Amount != 999_500 {
+				t.Errorf("unexpected token out: %+v", swaps[0].TokenOut)
+			}
+		})
+	}
+
+	t.Run("output paid before input", func(t *testing.T) {
+		crema := tests[3].instruction
+		tx := newTestTransaction(keys, 1, crema)
+		swaps, err := NewCremaParser().ParseInstruction(crema, 0, newTestContext(tx, meta(transfers[1], transfers[0])))
+		if err != nil {
+			t.Fatalf("failed to parse swap: %v", err)
+		}
+		if !swaps[0].TokenIn.Mint.Equals(usdc) || !swaps[0].TokenOut.Mint.Equals(usdt) || swaps[0].TokenOut.Amount != 999_500 {
+			t.Errorf("unexpected swap: %+v", swaps[0])
+		}
+	})
+
+	t.Run("ignores liquidity instructions", func(t *testing.T) {
+		deposit := testInstruction(saberIndex, swapData(2), 1, 1, 0, 2, 4, 5, 3, 6, 15)
+		if NewSaberParser().CanHandle(deposit, keys) {
+			t.Error("expected a deposit not to be handled as a swap")
+		}
+		if NewMercurialParser().CanHandle(tests[0].instruction, keys) {
+			t.Error("expected other programs not to be handled")
+		}
+	})
+
+	t.Run("requires both transfers", func(t *testing.T) {
+		tx := newTestTransaction(keys, 1, tests[0].instruction)
+		if _, err := NewSaberParser().ParseInstruction(tests[0].instruction, 0, newTestContext(tx, meta(transfers[0]))); err == nil {
+			t.Error("expected a swap without an output transfer to fail")
+		}
+	})
+}
@@ -0,0 +1,241 @@
+package tx_parser
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Instruction indices used when decoding transfers
+const (
+	systemTransferInstruction         = 2
+	systemTransferWithSeedInstruction = 11
+
+	tokenTransferInstruction        = 3
+	tokenTransferCheckedInstruction = 12
+
+	token2022TransferFeeExtension          = 26
+	token2022TransferCheckedWithFeeSubtype = 1
+)
+
+// tokenAccountInfo holds the owner and mint of a token account taken from token balances
+type tokenAccountInfo struct {
+	Owner solana.PublicKey
+	Mint  solana.PublicKey
+}
+
+// ParseTransfers returns every native SOL and SPL token transfer in the transaction,
+// including transfers made by inner instructions, independent of swap detection
+func ParseTransfers(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*TransferInfo, error) {
+	ctx, err := newTransactionContext(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTransfers(ctx), nil
+}
+
+// ParseTransfers returns every native SOL and SPL token transfer in the parsed transaction
+func (p *Parser) ParseTransfers() ([]*TransferInfo, error) {
+	return parseTransfers(p.ctx), nil
+}
+
+// parseTransfers walks outer instructions and their inner instructions in execution order
+func parseTransfers(ctx *TransactionContext) []*TransferInfo {
+	accounts := ctx.tokenAccounts()
+
+	var transfers []*TransferInfo
+	forEachInstruction(ctx, func(instruction solana.CompiledInstruction, instructionIndex, innerIndex int) {
+		if transfer := decodeTransfer(instruction, ctx, accounts); transfer != nil {
+			transfer.InstructionIndex = instructionIndex
+			transfer.InnerIndex = innerIndex
+			transfers = append(transfers, transfer)
+		}
+	})
+
+	return transfers
+}
+
+// tokenAccounts maps token account addresses to their owner and mint using pre and post
+// token balances. The map is built once per transaction and must not be modified.
+func (ctx *TransactionContext) tokenAccounts() map[solana.PublicKey]tokenAccountInfo {
+	if ctx.tokenAccountIndex != nil {
+		return ctx.tokenAccountIndex
+	}
+
+	accounts := make(map[solana.PublicKey]tokenAccountInfo, len(ctx.Meta.PreTokenBalances)+len(ctx.Meta.PostTokenBalances))
+	for _, balances := range [][]rpc.TokenBalance{ctx.Meta.PreTokenBalances, ctx.Meta.PostTokenBalances} {
+		for _, balance := range balances {
+			if int(balance.AccountIndex) >= len(ctx.AccountKeys) {
+				continue
+			}
+			info := tokenAccountInfo{Mint: balance.Mint}
+			if balance.Owner != nil {
+				info.Owner = *balance.Owner
+			}
+			accounts[ctx.AccountKeys[balance.AccountIndex]] = info
+		}
+	}
+
+	ctx.tokenAccountIndex = accounts
+	return accounts
+}
+
+// findTokenMint returns the mint of whichever of the token accounts has a recorded
+// balance, or the zero key if neither does
+func (ctx *TransactionContext) findTokenMint(source, dest solana.PublicKey) solana.PublicKey {
+	accounts := ctx.tokenAccounts()
+	if info, ok := accounts[source]; ok {
+		return info.Mint
+	}
+	return accounts[dest].Mint
+}
+
+// lookupTokenAccount returns the owner and mint of a token account from the token
+// balances, asking the TokenAccountResolver for accounts without a recorded balance
+func (ctx *TransactionContext) lookupTokenAccount(accounts map[solana.PublicKey]tokenAccountInfo, account solana.PublicKey) (tokenAccountInfo, bool) {
+	if info, ok := accounts[account]; ok {
+		return info, true
+	}
+	if ctx.TokenAccountResolver == nil {
+		return tokenAccountInfo{}, false
+	}
+
+	owner, mint, err := ctx.TokenAccountResolver.TokenAccount(account)
+	if err != nil {
+		return tokenAccountInfo{}, false
+	}
+	return tokenAccountInfo{Owner: owner, Mint: mint}, true
+}
+
+// decodeTransfer decodes a system or token program transfer, returning nil for any other instruction
+func decodeTransfer(instr solana.CompiledInstruction, ctx *TransactionContext, accounts map[solana.PublicKey]tokenAccountInfo) *TransferInfo {
+	if int(instr.ProgramIDIndex) >= len(ctx.AccountKeys) {
+		return nil
+	}
+	for _, idx := range instr.Accounts {
+		if int(idx) >= len(ctx.AccountKeys) {
+			return nil
+		}
+	}
+
+	programID := ctx.AccountKeys[instr.ProgramIDIndex]
+	switch {
+	case programID.Equals(solana.SystemProgramID):
+		return decodeSystemTransfer(instr, ctx)
+	case programID.Equals(solana.TokenProgramID), programID.Equals(solana.Token2022ProgramID):
+		return decodeTokenTransfer(instr, programID, ctx, accounts)
+	}
+
+	return nil
+}
+
+// decodeSystemTransfer handles native SOL transfers from the system program
+func decodeSystemTransfer(instr solana.CompiledInstruction, ctx *TransactionContext) *TransferInfo {
+	data := instr.Data
+	if len(data) < 12 {
+		return nil
+	}
+
+	var source, destination solana.PublicKey
+	switch binary.LittleEndian.Uint32(data[0:4]) {
+	case systemTransferInstruction:
+		if len(instr.Accounts) < 2 {
+			return nil
+		}
+		source = ctx.AccountKeys[instr.Accounts[0]]
+		destination = ctx.AccountKeys[instr.Accounts[1]]
+	case systemTransferWithSeedInstruction:
+		if len(instr.Accounts) < 3 {
+			return nil
+		}
+		source = ctx.AccountKeys[instr.Accounts[0]]
+		destination = ctx.AccountKeys[instr.Accounts[2]]
+	default:
+		return nil
+	}
+
+	return &TransferInfo{
+		Type:             TransferTypeSOL,
+		Program:          solana.SystemProgramID,
+		Mint:             NATIVE_SOL_PROGRAM_ID,
+		Source:           source,
+		Destination:      destination,
+		SourceOwner:      source,
+		DestinationOwner: destination,
+		Authority:        source,
+		Amount:           binary.LittleEndian.Uint64(data[4:12]),
+		Decimals:         ctx.GetMintDecimals(NATIVE_SOL_PROGRAM_ID),
+	}
+}
+
+// decodeTokenTransfer handles Transfer, TransferChecked and Token-2022 TransferCheckedWithFee
+func decodeTokenTransfer(instr solana.CompiledInstruction, programID solana.PublicKey, ctx *TransactionContext, accounts map[solana.PublicKey]tokenAccountInfo) *TransferInfo {
+	data := instr.Data
+	if len(data) < 9 {
+		return nil
+	}
+
+	transfer := &TransferInfo{
+		Type:    TransferTypeToken,
+		Program: programID,
+	}
+
+	switch {
+	case data[0] == tokenTransferInstruction:
+		if len(instr.Accounts) < 3 {
+			return nil
+		}
+		transfer.Source = ctx.AccountKeys[instr.Accounts[0]]
+		transfer.Destination = ctx.AccountKeys[instr.Accounts[1]]
+		transfer.Authority = ctx.AccountKeys[instr.Accounts[2]]
+		transfer.Amount = binary.LittleEndian.Uint64(data[1:9])
+	case data[0] == tokenTransferCheckedInstruction:
+		if len(instr.Accounts) < 4 || len(data) < 10 {
+			return nil
+		}
+		transfer.Source = ctx.AccountKeys[instr.Accounts[0]]
+		transfer.Mint = ctx.AccountKeys[instr.Accounts[1]]
+		transfer.Destination = ctx.AccountKeys[instr.Accounts[2]]
+		transfer.Authority = ctx.AccountKeys[instr.Accounts[3]]
+		transfer.Amount = binary.LittleEndian.Uint64(data[1:9])
+		transfer.Decimals = data[9]
+	case data[0] == token2022TransferFeeExtension && programID.Equals(solana.Token2022ProgramID):
+		if data[1] != token2022TransferCheckedWithFeeSubtype || len(instr.Accounts) < 4 || len(data) < 11 {
+			return nil
+		}
+		transfer.Source = ctx.AccountKeys[instr.Accounts[0]]
+		transfer.Mint = ctx.AccountKeys[instr.Accounts[1]]
+		transfer.Destination = ctx.AccountKeys[instr.Accounts[2]]
+		transfer.Authority = ctx.AccountKeys[instr.Accounts[3]]
+		transfer.Amount = binary.LittleEndian.Uint64(data[2:10])
+		transfer.Decimals = data[10]
+	default:
+		return nil
+	}
+
+	source, sourceKnown := ctx.lookupTokenAccount(accounts, transfer.Source)
+	destination, destinationKnown := ctx.lookupTokenAccount(accounts, transfer.Destination)
+
+	if transfer.Mint.IsZero() {
+		switch {
+		case sourceKnown:
+			transfer.Mint = source.Mint
+		case destinationKnown:
+			transfer.Mint = destination.Mint
+		}
+		transfer.Decimals = ctx.GetMintDecimals(transfer.Mint)
+	}
+
+	// Fall back to the signing authority when the source account has no recorded balance
+	transfer.SourceOwner = transfer.Authority
+	if sourceKnown && !source.Owner.IsZero() {
+		transfer.SourceOwner = source.Owner
+	}
+	if destinationKnown {
+		transfer.DestinationOwner = destination.Owner
+	}
+
+	return transfer
+}
@@ -0,0 +1,123 @@
+package tx_parser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParseTransfers(t *testing.T) {
+	keys := newTestKeys(7)
+	user, recipient, userAta, poolAta, mint, pool := keys[0], keys[1], keys[2], keys[3], keys[4], keys[6]
+	keys = append(keys, solana.SystemProgramID, solana.TokenProgramID)
+	systemIndex, tokenIndex := uint16(7), uint16(8)
+
+	tx := newTestTransaction(keys, 1,
+		testInstruction(systemIndex, systemTransferData(1_000_000), 0, 1),
+		testInstruction(5, []byte{1, 2, 3}, 2, 3),
+	)
+	meta := &rpc.TransactionMeta{
+		InnerInstructions: []rpc.InnerInstruction{
+			{
+				Index: 1,
+				Instructions: []solana.CompiledInstruction{
+					testInstruction(tokenIndex, tokenTransferData(500), 2, 3, 0),
+					testInstruction(tokenIndex, tokenTransferCheckedData(700, 6), 3, 4, 2, 6),
+				},
+			},
+		},
+		PreTokenBalances: []rpc.TokenBalance{
+			testTokenBalance(2, user, mint, 1000, 6),
+			testTokenBalance(3, pool, mint, 5000, 6),
+		},
+	}
+
+	transfers, err := ParseTransfers(tx, meta)
+	if err != nil {
+		t.Fatalf("failed to parse transfers: %v", err)
+	}
+	if len(transfers) != 3 {
+		t.Fatalf("expected 3 transfers, got %d", len(transfers))
+	}
+
+	sol := transfers[0]
+	if sol.Type != TransferTypeSOL || sol.Amount != 1_000_000 || !sol.Source.Equals(user) || !sol.Destination.Equals(recipient) {
+		t.Errorf("unexpected SOL transfer: %+v", sol)
+	}
+	if sol.InnerIndex != -1 || sol.InstructionIndex != 0 {
+		t.Errorf("expected outer transfer at index 0, got %d/%d", sol.InstructionIndex, sol.InnerIndex)
+	}
+
+	spl := transfers[1]
+	if spl.Type != TransferTypeToken || spl.Amount != 500 || !spl.Mint.Equals(mint) || !spl.Destination.Equals(poolAta) {
+		t.Errorf("unexpected token transfer: %+v", spl)
+	}
+	if !spl.SourceOwner.Equals(user) || !spl.DestinationOwner.Equals(pool) {
+		t.Errorf("expected owners %s -> %s, got %s -> %s", user, pool, spl.SourceOwner, spl.DestinationOwner)
+	}
+	if spl.Decimals != 6 || spl.InstructionIndex != 1 || spl.InnerIndex != 0 {
+		t.Errorf("unexpected token transfer metadata: %+v", spl)
+	}
+
+	checked := transfers[2]
+	if checked.Amount != 700 || !checked.Mint.Equals(mint) || !checked.Destination.Equals(userAta) || !checked.Authority.Equals(pool) {
+		t.Errorf("unexpected checked transfer: %+v", checked)
+	}
+	if !checked.SourceOwner.Equals(pool) || !checked.DestinationOwner.Equals(user) {
+		t.Errorf("unexpected checked transfer owners: %+v", checked)
+	}
+}
+
+func TestParseTransfersIgnoresBadIndices(t *testing.T) {
+	keys := append(newTestKeys(2), solana.TokenProgramID)
+	tx := newTestTransaction(keys, 1,
+		testInstruction(2, tokenTransferData(1), 0, 1, 9),
+		testInstruction(42, tokenTransferData(1), 0, 1, 0),
+	)
+
+	transfers, err := ParseTransfers(tx, &rpc.TransactionMeta{})
+	if err != nil {
+		t.Fatalf("failed to parse transfers: %v", err)
+	}
+	if len(transfers) != 0 {
+		t.Errorf("expected no transfers, got %d", len(transfers))
+	}
+}
+
+// staticTokenAccounts resolves token accounts from a fixed map
+type staticTokenAccounts map[solana.PublicKey]tokenAccountInfo
+
+func (r staticTokenAccounts) TokenAccount(account solana.PublicKey) (owner, mint solana.PublicKey, err error) {
+	info, ok := r[account]
+	if !ok {
+		return owner, mint, fmt.Errorf("unknown token account %s", account)
+	}
+	return info.Owner, info.Mint, nil
+}
+
+func TestParseTransfersTokenAccountResolver(t *testing.T) {
+	keys := newTestKeys(5)
+	authority, destination, owner, mint := keys[0], keys[2], keys[3], keys[4]
+	keys = append(keys, solana.TokenProgramID)
+	tx := newTestTransaction(keys, 1, testInstruction(5, tokenTransferData(250), 1, 2, 0))
+
+	ctx := newTestContext(tx, &rpc.TransactionMeta{})
+	ctx.DecimalsResolver = staticResolver{mint: 6}
+	ctx.TokenAccountResolver = staticTokenAccounts{
+		destination: {Owner: owner, Mint: mint},
+	}
+
+	transfers := parseTransfers(ctx)
+	if len(transfers) != 1 {
+		t.Fatalf("expected 1 transfer, got %d", len(transfers))
+	}
+	transfer := transfers[0]
+	if !transfer.Mint.Equals(mint) || transfer.Decimals != 6 {
+		t.Errorf("expected the mint of the resolved destination, got %+v", transfer)
+	}
+	if !transfer.DestinationOwner.Equals(owner) || !transfer.SourceOwner.Equals(authority) {
+		t.Errorf("expected owners %s -> %s, got %s -> %s", authority, owner, transfer.SourceOwner, transfer.DestinationOwner)
+	}
+}
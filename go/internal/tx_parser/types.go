@@ -1,50 +1,625 @@
 package tx_parser
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/logging"
 )
 
 // SwapType represents different DEX protocols
 type SwapType string
 
 const (
-	SwapTypeJupiter    SwapType = "Jupiter"
-	SwapTypeJupiterDCA SwapType = "JupiterDCA"
-	SwapTypePumpFun    SwapType = "PumpFun"
-	SwapTypeRaydium    SwapType = "Raydium"
-	SwapTypeOrca       SwapType = "Orca"
-	SwapTypeMeteora    SwapType = "Meteora"
-	SwapTypeMoonshot   SwapType = "Moonshot"
-	SwapTypeOKX        SwapType = "OKX"
-	SwapTypeUnknown    SwapType = "Unknown"
+	SwapTypeJupiter          SwapType = "Jupiter"
+	SwapTypeJupiterDCA       SwapType = "JupiterDCA"
+	SwapTypePumpFun          SwapType = "PumpFun"
+	SwapTypeRaydium          SwapType = "Raydium"
+	SwapTypeOrca             SwapType = "Orca"
+	SwapTypeMeteora          SwapType = "Meteora"
+	SwapTypeMeteoraDBC       SwapType = "MeteoraDBC"
+	SwapTypeMoonshot         SwapType = "Moonshot"
+	SwapTypeOKX              SwapType = "OKX"
+	SwapTypeRaydiumLaunchLab SwapType = "RaydiumLaunchLab"
+	SwapTypeSaber            SwapType = "Saber"
+	SwapTypeMercurial        SwapType = "Mercurial"
+	SwapTypeInvariant        SwapType = "Invariant"
+	SwapTypeCrema            SwapType = "Crema"
+	SwapTypeFluxBeam         SwapType = "FluxBeam"
+	SwapTypeGooseFX          SwapType = "GooseFX"
+	SwapTypeStabble          SwapType = "Stabble"
+	SwapTypeHeaven           SwapType = "Heaven"
+	SwapTypeBoop             SwapType = "Boop"
+	SwapTypeUnknown          SwapType = "Unknown"
 )
 
 // TokenInfo represents detailed information about a token
 type TokenInfo struct {
-	Mint     solana.PublicKey
-	Amount   uint64
-	Decimals uint8
+	Mint       solana.PublicKey `json:"mint"`
+	Amount     uint64           `json:"amount,string"`
+	Decimals   uint8            `json:"decimals"`
+	Extensions *TokenExtensions `json:"extensions,omitempty"` // set for Token-2022 mints with extensions
+}
+
+// TokenExtensions summarizes the Token-2022 extensions of a mint, with details of those
+// that change how amounts should be read
+type TokenExtensions struct {
+	Extensions             []string         `json:"extensions"`            // names of every extension, e.g. TransferHook
+	TransferHookProgram    solana.PublicKey `json:"transfer_hook_program"` // zero without a transfer hook
+	TransferFeeBasisPoints uint16           `json:"transfer_fee_basis_points"`
+	MaxTransferFee         uint64           `json:"max_transfer_fee,string"`
+	InterestRate           int16            `json:"interest_rate"`                  // basis points a year, applied to displayed amounts
+	ScaledUIMultiplier     float64          `json:"scaled_ui_multiplier,omitempty"` // displayed amounts are raw amounts times the multiplier
+	ConfidentialTransfers  bool             `json:"confidential_transfers"`         // transfers may move amounts hidden from the parser
 }
 
 // SwapInfo represents the parsed swap transaction data
 type SwapInfo struct {
-	Protocol   SwapType
-	Signers    []solana.PublicKey
-	Signatures []solana.Signature
-	Timestamp  time.Time
-	TokenIn    TokenInfo
-	TokenOut   TokenInfo
+	Protocol         SwapType           `json:"protocol"`
+	Signers          []solana.PublicKey `json:"signers"`
+	Signatures       []solana.Signature `json:"signatures"`
+	Timestamp        time.Time          `json:"timestamp,omitzero"`
+	TokenIn          TokenInfo          `json:"token_in"`
+	TokenOut         TokenInfo          `json:"token_out"`
+	InstructionIndex int                `json:"instruction_index"`    // index of the outer instruction the swap was parsed from
+	ComputeUnits     uint64             `json:"compute_units"`        // consumed by the instruction the swap was parsed from, 0 if not logged
+	AmountUSD        float64            `json:"amount_usd,omitempty"` // set by the pricing package, 0 if neither side has a known price
+	Price            *PriceInfo         `json:"price,omitempty"`      // set by the pricing package
+	MEV              *MEVInfo           `json:"mev,omitempty"`        // set by the mev package
+}
+
+// PriceInfo is the execution price of a swap
+type PriceInfo struct {
+	BaseMint  solana.PublicKey `json:"base_mint"`
+	QuoteMint solana.PublicKey `json:"quote_mint"`
+	Price     float64          `json:"price"`                // quote tokens per base token
+	PriceSOL  float64          `json:"price_sol,omitempty"`  // base token price in SOL, 0 if unknown
+	PriceUSD  float64          `json:"price_usd,omitempty"`  // base token price in USD, 0 if unknown
+	VolumeUSD float64          `json:"volume_usd,omitempty"` // swap value in USD, 0 if unknown
+}
+
+// MEVRole is the part a swap plays in a sandwich
+type MEVRole string
+
+const (
+	MEVRoleFrontrun MEVRole = "frontrun"
+	MEVRoleVictim   MEVRole = "victim"
+	MEVRoleBackrun  MEVRole = "backrun"
+)
+
+// MEVInfo marks a swap as part of a sandwich attack
+type MEVInfo struct {
+	Role           MEVRole          `json:"role"`
+	Attacker       solana.PublicKey `json:"attacker"`
+	Frontrun       solana.Signature `json:"frontrun"`
+	Backrun        solana.Signature `json:"backrun"`
+	VictimLoss     uint64           `json:"victim_loss,string"` // estimated output tokens the victim lost, victims only
+	VictimSlippage float64          `json:"victim_slippage"`    // VictimLoss as a share of the expected output, 0 to 1
+}
+
+// TransferType distinguishes native SOL transfers from token program transfers
+type TransferType string
+
+const (
+	TransferTypeSOL   TransferType = "SOL"
+	TransferTypeToken TransferType = "Token"
+)
+
+// TransferInfo represents a single native SOL or SPL token transfer
+type TransferInfo struct {
+	Type             TransferType     `json:"type"`
+	Program          solana.PublicKey `json:"program"`
+	InstructionIndex int              `json:"instruction_index"` // index of the outer instruction
+	InnerIndex       int              `json:"inner_index"`       // index within the inner instructions, -1 for outer instructions
+	Mint             solana.PublicKey `json:"mint"`
+	Source           solana.PublicKey `json:"source"`
+	Destination      solana.PublicKey `json:"destination"`
+	SourceOwner      solana.PublicKey `json:"source_owner"`
+	DestinationOwner solana.PublicKey `json:"destination_owner"`
+	Authority        solana.PublicKey `json:"authority"`
+	Amount           uint64           `json:"amount,string"`
+	Decimals         uint8            `json:"decimals"`
+	AmountUSD        float64          `json:"amount_usd,omitempty"` // set by the pricing package, 0 if the mint has no known price
+}
+
+// StakeEventType represents the kind of staking action
+type StakeEventType string
+
+const (
+	StakeEventDelegate   StakeEventType = "Delegate"
+	StakeEventDeactivate StakeEventType = "Deactivate"
+	StakeEventWithdraw   StakeEventType = "Withdraw"
+	StakeEventSplit      StakeEventType = "Split"
+	StakeEventMerge      StakeEventType = "Merge"
+	StakeEventDeposit    StakeEventType = "Deposit" // SOL or stake deposited into a stake pool
+	StakeEventUnstake    StakeEventType = "Unstake" // pool tokens redeemed for SOL or stake
+	StakeEventClaim      StakeEventType = "Claim"   // delayed unstake ticket claimed
+)
+
+// StakePool identifies which staking program or pool handled the event
+type StakePool string
+
+const (
+	StakePoolNative   StakePool = "Native"
+	StakePoolSPL      StakePool = "SPLStakePool"
+	StakePoolJito     StakePool = "Jito"
+	StakePoolMarinade StakePool = "Marinade"
+)
+
+// StakeEvent represents a native stake or stake pool action
+type StakeEvent struct {
+	Type             StakeEventType   `json:"type"`
+	Pool             StakePool        `json:"pool"`
+	Program          solana.PublicKey `json:"program"`
+	InstructionIndex int              `json:"instruction_index"`
+	InnerIndex       int              `json:"inner_index"`
+	StakeAccount     solana.PublicKey `json:"stake_account"`  // stake account acted on, or the pool state account
+	TargetAccount    solana.PublicKey `json:"target_account"` // split destination, merge source, or stake received from a pool
+	VoteAccount      solana.PublicKey `json:"vote_account"`
+	Authority        solana.PublicKey `json:"authority"`
+	Recipient        solana.PublicKey `json:"recipient"`
+	Lamports         uint64           `json:"lamports,string"`
+	PoolMint         solana.PublicKey `json:"pool_mint"`                // liquid staking token mint
+	PoolTokenAmount  uint64           `json:"pool_token_amount,string"` // liquid staking tokens minted or burned
+}
+
+// TokenSupplyEventType distinguishes mints from burns
+type TokenSupplyEventType string
+
+const (
+	TokenSupplyMint TokenSupplyEventType = "Mint"
+	TokenSupplyBurn TokenSupplyEventType = "Burn"
+)
+
+// TokenSupplyEvent represents a change to a token's supply
+type TokenSupplyEvent struct {
+	Type             TokenSupplyEventType `json:"type"`
+	Program          solana.PublicKey     `json:"program"`
+	InstructionIndex int                  `json:"instruction_index"`
+	InnerIndex       int                  `json:"inner_index"`
+	Mint             solana.PublicKey     `json:"mint"`
+	Account          solana.PublicKey     `json:"account"` // token account minted to or burned from
+	Owner            solana.PublicKey     `json:"owner"`   // owner of the token account, if known
+	Authority        solana.PublicKey     `json:"authority"`
+	Amount           uint64               `json:"amount,string"`
+	Decimals         uint8                `json:"decimals"`
+}
+
+// TokenAdminEventType represents the kind of administrative token action
+type TokenAdminEventType string
+
+const (
+	TokenAdminSetAuthority TokenAdminEventType = "SetAuthority"
+	TokenAdminFreeze       TokenAdminEventType = "Freeze"
+	TokenAdminThaw         TokenAdminEventType = "Thaw"
+)
+
+// TokenAdminEvent represents an authority change or account freeze/thaw
+type TokenAdminEvent struct {
+	Type             TokenAdminEventType `json:"type"`
+	Program          solana.PublicKey    `json:"program"`
+	InstructionIndex int                 `json:"instruction_index"`
+	InnerIndex       int                 `json:"inner_index"`
+	Mint             solana.PublicKey    `json:"mint"`
+	Account          solana.PublicKey    `json:"account"`        // mint or token account acted on
+	Authority        solana.PublicKey    `json:"authority"`      // current authority that signed the change
+	AuthorityType    string              `json:"authority_type"` // for SetAuthority, e.g. "MintTokens" or "FreezeAccount"
+	NewAuthority     *solana.PublicKey   `json:"new_authority"`  // for SetAuthority, nil when the authority is revoked
+}
+
+// ComputeBudget represents the compute budget requested by a transaction
+type ComputeBudget struct {
+	UnitLimit           uint32 `json:"unit_limit"`        // requested compute unit limit, 0 if not set
+	UnitPrice           uint64 `json:"unit_price,string"` // priority fee in micro-lamports per compute unit, 0 if not set
+	HeapFrameBytes      uint32 `json:"heap_frame_bytes"`
+	LoadedAccountsLimit uint32 `json:"loaded_accounts_limit"` // loaded accounts data size limit in bytes, 0 if not set
+	EffectiveUnitLimit  uint32 `json:"effective_unit_limit"`  // unit limit the runtime applies, including the default when not set
+	PriorityFee         uint64 `json:"priority_fee,string"`   // priority fee in lamports
+}
+
+// MemoInfo represents a memo program instruction
+type MemoInfo struct {
+	Program          solana.PublicKey   `json:"program"`
+	InstructionIndex int                `json:"instruction_index"`
+	InnerIndex       int                `json:"inner_index"`
+	Text             string             `json:"text"`
+	Signers          []solana.PublicKey `json:"signers"`
+}
+
+// PoolCreatedEvent represents a new liquidity pool or bonding curve. Token A is the
+// launched or base token and token B the quote token, SOL for most bonding curves.
+// A bonding curve graduating into an AMM pool is reported with Curve set.
+type PoolCreatedEvent struct {
+	Protocol         SwapType         `json:"protocol"`
+	Program          solana.PublicKey `json:"program"`
+	InstructionIndex int              `json:"instruction_index"`
+	InnerIndex       int              `json:"inner_index"`
+	Pool             solana.PublicKey `json:"pool"`
+	Creator          solana.PublicKey `json:"creator"`
+	MintA            solana.PublicKey `json:"mint_a"`
+	MintB            solana.PublicKey `json:"mint_b"`
+	DecimalsA        uint8            `json:"decimals_a"`
+	DecimalsB        uint8            `json:"decimals_b"`
+	AmountA          uint64           `json:"amount_a,string"` // initial liquidity held by the pool after the transaction
+	AmountB          uint64           `json:"amount_b,string"`
+	Curve            solana.PublicKey `json:"curve"` // bonding curve the pool graduated from, zero for new launches
+}
+
+// PerpFillType represents how a perpetual position changed
+type PerpFillType string
+
+const (
+	PerpFillOpen        PerpFillType = "Open"        // position opened or increased
+	PerpFillClose       PerpFillType = "Close"       // position reduced or closed
+	PerpFillTrade       PerpFillType = "Trade"       // order book fill that may open or reduce a position
+	PerpFillLiquidation PerpFillType = "Liquidation" // position taken over or closed by a liquidator
+)
+
+// PerpVenue identifies the perpetuals exchange of a fill
+type PerpVenue string
+
+const (
+	PerpVenueZeta         PerpVenue = "Zeta"
+	PerpVenueJupiterPerps PerpVenue = "JupiterPerps"
+)
+
+// PerpSide is the direction of the position a fill belongs to
+type PerpSide string
+
+const (
+	PerpSideLong  PerpSide = "Long"
+	PerpSideShort PerpSide = "Short"
+)
+
+// PerpFillInfo represents a fill on a perpetuals exchange. Prices and fees are in USD
+// with 6 decimals. Size is the USD notional with 6 decimals on Jupiter Perps and the
+// number of contracts with 3 decimals on Zeta.
+type PerpFillInfo struct {
+	Type             PerpFillType     `json:"type"`
+	Venue            PerpVenue        `json:"venue"`
+	Program          solana.PublicKey `json:"program"`
+	InstructionIndex int              `json:"instruction_index"`
+	InnerIndex       int              `json:"inner_index"` // -1 for fills decoded from program logs
+	Trader           solana.PublicKey `json:"trader"`      // position owner, zero when the event does not name it
+	Account          solana.PublicKey `json:"account"`     // Jupiter position or Zeta margin account
+	Market           solana.PublicKey `json:"market"`      // Jupiter custody of the traded asset, zero on Zeta
+	Asset            string           `json:"asset"`       // traded asset, e.g. SOL, empty when unknown
+	Side             PerpSide         `json:"side"`
+	Size             uint64           `json:"size,string"`
+	Price            uint64           `json:"price,string"`
+	Fee              uint64           `json:"fee,string"`
+	Liquidator       solana.PublicKey `json:"liquidator"` // set for Zeta liquidations
+}
+
+// CompressedNftEventType represents a Bubblegum compressed NFT action
+type CompressedNftEventType string
+
+const (
+	CompressedNftMint     CompressedNftEventType = "Mint"
+	CompressedNftTransfer CompressedNftEventType = "Transfer"
+	CompressedNftBurn     CompressedNftEventType = "Burn"
+)
+
+// CompressedNftEvent represents a mint, transfer or burn of a Bubblegum compressed NFT
+type CompressedNftEvent struct {
+	Type             CompressedNftEventType `json:"type"`
+	InstructionIndex int                    `json:"instruction_index"`
+	InnerIndex       int                    `json:"inner_index"`
+	Tree             solana.PublicKey       `json:"tree"`
+	LeafIndex        uint64                 `json:"leaf_index,string"` // nonce of the leaf in the tree
+	AssetID          solana.PublicKey       `json:"asset_id"`          // zero for mints without a logged leaf
+	Owner            solana.PublicKey       `json:"owner"`             // minted to, or owner before a transfer or burn
+	Delegate         solana.PublicKey       `json:"delegate"`
+	NewOwner         solana.PublicKey       `json:"new_owner"`  // set for transfers
+	Collection       solana.PublicKey       `json:"collection"` // set for mints into a collection
+	Name             string                 `json:"name,omitempty"`
+	Symbol           string                 `json:"symbol,omitempty"`
+	URI              string                 `json:"uri,omitempty"`
+}
+
+// NftMintSource identifies how an NFT was minted
+type NftMintSource string
+
+const (
+	NftMintCandyMachineV3   NftMintSource = "CandyMachineV3"
+	NftMintCoreCandyMachine NftMintSource = "CoreCandyMachine"
+	NftMintMetaplexCore     NftMintSource = "MetaplexCore" // asset created directly with Metaplex Core
+)
+
+// NftMintEvent represents an NFT minted from a Candy Machine or a Metaplex Core asset
+// creation
+type NftMintEvent struct {
+	Source           NftMintSource    `json:"source"`
+	Program          solana.PublicKey `json:"program"`
+	InstructionIndex int              `json:"instruction_index"`
+	InnerIndex       int              `json:"inner_index"`
+	Mint             solana.PublicKey `json:"mint"`   // NFT mint or Core asset
+	Minter           solana.PublicKey `json:"minter"` // wallet receiving the NFT
+	Payer            solana.PublicKey `json:"payer"`
+	Collection       solana.PublicKey `json:"collection"`     // zero when minted outside a collection
+	CandyMachine     solana.PublicKey `json:"candy_machine"`  // zero for direct Core creations
+	Price            uint64           `json:"price,string"`   // lamports paid by the payer, excluding rent and fees
+	Name             string           `json:"name,omitempty"` // set for Core assets
+	URI              string           `json:"uri,omitempty"`
+}
+
+// DomainEventType represents a Solana Name Service domain action
+type DomainEventType string
+
+const (
+	DomainRegister DomainEventType = "Register"
+	DomainTransfer DomainEventType = "Transfer"
+)
+
+// DomainEvent represents a Solana Name Service domain registration or transfer
+type DomainEvent struct {
+	Type             DomainEventType  `json:"type"`
+	InstructionIndex int              `json:"instruction_index"`
+	InnerIndex       int              `json:"inner_index"`
+	Domain           solana.PublicKey `json:"domain"`    // name account of the domain
+	Name             string           `json:"name"`      // e.g. bonfida.sol, empty unless the transaction writes its reverse lookup
+	Owner            solana.PublicKey `json:"owner"`     // registered to, or owner before a transfer
+	NewOwner         solana.PublicKey `json:"new_owner"` // set for transfers
+	Parent           solana.PublicKey `json:"parent"`    // the .sol TLD for top level domains, set for registrations
+}
+
+// BridgeProtocol identifies a cross-chain bridge
+type BridgeProtocol string
+
+const (
+	BridgeWormhole BridgeProtocol = "Wormhole"
+	BridgeDeBridge BridgeProtocol = "deBridge"
+	BridgeMayan    BridgeProtocol = "Mayan"
+)
+
+// BridgeDirection represents whether funds leave or arrive on Solana
+type BridgeDirection string
+
+const (
+	BridgeDeposit BridgeDirection = "Deposit" // sent from Solana to another chain
+	BridgeRedeem  BridgeDirection = "Redeem"  // received on Solana from another chain
+)
+
+// BridgeChain names a chain, e.g. ethereum, or the bridge's own ID for chains without a name
+// such as wormhole:42
+type BridgeChain string
+
+const (
+	BridgeChainSolana    BridgeChain = "solana"
+	BridgeChainEthereum  BridgeChain = "ethereum"
+	BridgeChainBSC       BridgeChain = "bsc"
+	BridgeChainPolygon   BridgeChain = "polygon"
+	BridgeChainAvalanche BridgeChain = "avalanche"
+	BridgeChainFantom    BridgeChain = "fantom"
+	BridgeChainArbitrum  BridgeChain = "arbitrum"
+	BridgeChainOptimism  BridgeChain = "optimism"
+	BridgeChainBase      BridgeChain = "base"
+	BridgeChainSui       BridgeChain = "sui"
+	BridgeChainAptos     BridgeChain = "aptos"
+)
+
+// BridgeEvent represents a cross-chain transfer leaving or arriving on Solana. Amount is
+// the Solana side amount in the mint's raw units.
+type BridgeEvent struct {
+	Protocol         BridgeProtocol   `json:"protocol"`
+	Direction        BridgeDirection  `json:"direction"`
+	Program          solana.PublicKey `json:"program"`
+	InstructionIndex int              `json:"instruction_index"`
+	InnerIndex       int              `json:"inner_index"`
+	SourceChain      BridgeChain      `json:"source_chain"` // empty for Wormhole redemptions, whose origin is only in the VAA
+	DestinationChain BridgeChain      `json:"destination_chain"`
+	Wallet           solana.PublicKey `json:"wallet"`          // sender of deposits, recipient of redemptions
+	ForeignAddress   string           `json:"foreign_address"` // hex address on the other chain, empty when not in the instruction
+	Mint             solana.PublicKey `json:"mint"`
+	Amount           uint64           `json:"amount,string"`
+	Decimals         uint8            `json:"decimals"`
+}
+
+// ParsedTransaction holds everything the parser extracts from a single transaction
+type ParsedTransaction struct {
+	Signature      solana.Signature        `json:"signature"`
+	Slot           uint64                  `json:"slot"`
+	BlockTime      *solana.UnixTimeSeconds `json:"block_time"`
+	FeePayer       solana.PublicKey        `json:"fee_payer"`
+	Fee            uint64                  `json:"fee,string"`
+	JitoTip        uint64                  `json:"jito_tip,string"`  // lamports transferred to Jito tip accounts
+	Bundle         *BundleInfo             `json:"bundle,omitempty"` // set when parsed as part of a block
+	Swaps          []*SwapInfo             `json:"swaps"`
+	Transfers      []*TransferInfo         `json:"transfers"`
+	StakeEvents    []*StakeEvent           `json:"stake_events"`
+	SupplyEvents   []*TokenSupplyEvent     `json:"supply_events"`
+	AdminEvents    []*TokenAdminEvent      `json:"admin_events"`
+	ComputeBudget  *ComputeBudget          `json:"compute_budget"`
+	Memos          []*MemoInfo             `json:"memos"`
+	PoolCreations  []*PoolCreatedEvent     `json:"pool_creations"`
+	PerpFills      []*PerpFillInfo         `json:"perp_fills"`
+	CompressedNfts []*CompressedNftEvent   `json:"compressed_nfts"`
+	NftMints       []*NftMintEvent         `json:"nft_mints"`
+	DomainEvents   []*DomainEvent          `json:"domain_events"`
+	Bridges        []*BridgeEvent          `json:"bridges"`
+	CallTree       []*InstructionNode      `json:"call_tree"`         // outer instructions with the instructions they invoked
+	Failure        *TransactionFailure     `json:"failure,omitempty"` // why the transaction failed, with ParseOptions.AnalyzeFailures
+	Errors         []*ParseError           `json:"errors"`            // instructions that could not be parsed, empty in strict mode
+}
+
+// InstructionNode is an instruction in the call tree of a transaction, holding the
+// instructions it invoked through CPI as children
+type InstructionNode struct {
+	Program          solana.PublicKey   `json:"program"`
+	InstructionIndex int                `json:"instruction_index"`
+	InnerIndex       int                `json:"inner_index"`   // -1 for outer instructions
+	StackHeight      int                `json:"stack_height"`  // 1 for outer instructions
+	ComputeUnits     uint64             `json:"compute_units"` // including the instructions it invoked, 0 if not logged
+	Children         []*InstructionNode `json:"children,omitempty"`
+}
+
+// ProgramInvocation is a program invocation reconstructed from the log messages, holding
+// what the program logged while it was executing and the invocations it made
+type ProgramInvocation struct {
+	Program          solana.PublicKey     `json:"program"`
+	InstructionIndex int                  `json:"instruction_index"`
+	InnerIndex       int                  `json:"inner_index"` // -1 for outer instructions
+	StackHeight      int                  `json:"stack_height"`
+	Status           InvocationStatus     `json:"status"`
+	Error            string               `json:"error,omitempty"` // reason the invocation failed
+	Logs             []string             `json:"logs"`            // "Program log:" messages and unprefixed lines
+	Data             [][]byte             `json:"data"`            // decoded "Program data:" fields, e.g. Anchor events
+	ReturnData       []byte               `json:"return_data"`
+	ComputeUnits     uint64               `json:"compute_units"`
+	Invocations      []*ProgramInvocation `json:"invocations,omitempty"`
+}
+
+// InvocationStatus is the outcome of a program invocation
+type InvocationStatus string
+
+const (
+	InvocationSuccess    InvocationStatus = "success"
+	InvocationFailed     InvocationStatus = "failed"
+	InvocationIncomplete InvocationStatus = "incomplete" // the logs end before the program returned
+)
+
+// FailureKind classifies why a transaction failed
+type FailureKind string
+
+const (
+	FailureSlippage          FailureKind = "slippage"
+	FailureInsufficientFunds FailureKind = "insufficient_funds"
+	FailureBlockhashExpired  FailureKind = "blockhash_expired"
+	FailureComputeExceeded   FailureKind = "compute_exceeded"
+	FailureOther             FailureKind = "other"
+)
+
+// TransactionFailure describes the error of a failed transaction and the instruction
+// that returned it
+type TransactionFailure struct {
+	Kind             FailureKind      `json:"kind"`
+	Error            string           `json:"error"`                       // e.g. BlockhashNotFound or InstructionError
+	InstructionError string           `json:"instruction_error,omitempty"` // e.g. InsufficientFunds or Custom
+	InstructionIndex int              `json:"instruction_index"`           // -1 when no instruction failed
+	InnerIndex       int              `json:"inner_index"`                 // -1 when the outer instruction returned the error
+	Program          solana.PublicKey `json:"program"`                     // program that returned the error
+	CustomCode       *uint32          `json:"custom_code,omitempty"`
+	ErrorName        string           `json:"error_name,omitempty"` // name of the custom error, from an IDL or the logs
+	ErrorMessage     string           `json:"error_message,omitempty"`
+}
+
+// BundleInfo places a transaction in a Jito bundle detected in its block
+type BundleInfo struct {
+	ID       solana.Signature `json:"id"`       // signature of the bundle's first transaction
+	Position int              `json:"position"` // index of the transaction within the bundle
+	Size     int              `json:"size"`
+	Tip      uint64           `json:"tip,string"` // total tip of the bundle in lamports
+}
+
+// Bundle is a group of consecutive transactions that landed together with a Jito tip
+type Bundle struct {
+	ID           solana.Signature   `json:"id"`
+	Slot         uint64             `json:"slot"`
+	Transactions []solana.Signature `json:"transactions"` // in execution order
+	Tip          uint64             `json:"tip,string"`
+	Tipper       solana.PublicKey   `json:"tipper"` // fee payer of the tipping transaction
+}
+
+// ParseOptions controls how the parser handles instructions it fails to parse
+type ParseOptions struct {
+	// Strict fails the whole transaction on the first instruction that cannot be parsed.
+	// In lenient mode (the default) partial results are returned alongside ParseErrors.
+	Strict bool
+
+	// Protocols limits swap parsing to the given protocols, all protocols if empty
+	Protocols []SwapType
+
+	// DecimalsResolver resolves decimals of mints missing from the token balances
+	DecimalsResolver DecimalsResolver
+
+	// TokenAccountResolver resolves owners and mints of token accounts missing from the
+	// token balances
+	TokenAccountResolver TokenAccountResolver
+
+	// TokenExtensionsResolver resolves the extensions of Token-2022 mints swapped, optional
+	TokenExtensionsResolver TokenExtensionsResolver
+
+	// AnalyzeFailures decodes the error of failed transactions into ParsedTransaction.Failure
+	AnalyzeFailures bool
+
+	// ProgramErrors names the custom errors of programs, typically loaded from IDLs, for
+	// failure analysis. Errors of common programs and Anchor are known without it.
+	ProgramErrors ProgramErrors
+
+	// Hook observes every protocol handler call, e.g. for tracing, optional
+	Hook ParseHook
+
+	// Logger receives a debug record for every instruction a handler fails to parse,
+	// defaults to a no-op logger
+	Logger logging.Logger
+}
+
+// ParseHook observes the protocol handlers the parser runs
+type ParseHook interface {
+	// HandlerStarted is called when a handler claims an instruction and returns the
+	// function called with the handler's outcome. innerIndex is -1 for outer instructions.
+	HandlerStarted(protocol SwapType, instructionIndex, innerIndex int) func(swaps []*SwapInfo, err error)
 }
 
 // TransactionContext holds all the necessary context for parsing a transaction
 type TransactionContext struct {
 	Transaction  *solana.Transaction
 	Meta         *rpc.TransactionMeta
-	AccountKeys  []solana.PublicKey
-	MintDecimals map[string]uint8 // map[mint_address]decimals
+	AccountKeys  []solana.PublicKey // static keys followed by loaded writable and readonly keys
+	MintDecimals map[solana.PublicKey]uint8
+	Logs         []string
+	Slot         uint64
+	BlockTime    *solana.UnixTimeSeconds
+
+	// DecimalsResolver looks up mints missing from the token balances, optional
+	DecimalsResolver DecimalsResolver
+
+	// TokenAccountResolver looks up token accounts missing from the token balances, optional
+	TokenAccountResolver TokenAccountResolver
+
+	// TokenExtensionsResolver looks up the extensions of Token-2022 mints, optional
+	TokenExtensionsResolver TokenExtensionsResolver
+
+	// tokenAccountIndex caches tokenAccounts, built on first use
+	tokenAccountIndex map[solana.PublicKey]tokenAccountInfo
+
+	// InnerStackHeights holds the stack height of each inner instruction by outer
+	// instruction index when the source reports them, as Geyser does. Zero marks an
+	// unknown height.
+	InnerStackHeights map[uint16][]int
+
+	// tokenExtensions caches resolved mint extensions, nil for mints without any
+	tokenExtensions map[solana.PublicKey]*TokenExtensions
+
+	// programLogs caches ProgramLogs, built on first use
+	programLogs []*ProgramInvocation
+
+	// callTree, callNodes and callers cache CallTree, built on first use
+	callTree  []*InstructionNode
+	callNodes map[instructionPosition]*InstructionNode
+	callers   map[instructionPosition]*InstructionNode
+}
+
+// DecimalsResolver resolves the decimals of a mint, typically by fetching the mint account
+type DecimalsResolver interface {
+	MintDecimals(mint solana.PublicKey) (uint8, error)
+}
+
+// TokenAccountResolver resolves the owner and mint of a token account, typically by
+// fetching the account. Implementations are shared across parsers and must be safe
+// for concurrent use.
+type TokenAccountResolver interface {
+	TokenAccount(account solana.PublicKey) (owner, mint solana.PublicKey, err error)
+}
+
+// TokenExtensionsResolver resolves the extensions of a Token-2022 mint, typically by
+// fetching the mint account and decoding it with DecodeTokenExtensions. It returns nil
+// for mints without extensions and must be safe for concurrent use.
+type TokenExtensionsResolver interface {
+	MintExtensions(mint solana.PublicKey) (*TokenExtensions, error)
 }
 
 // SwapParser defines the interface for protocol-specific parsers
@@ -56,34 +631,68 @@ type SwapParser interface {
 	ParseInstruction(instruction solana.CompiledInstruction, instructionIndex int, ctx *TransactionContext) ([]*SwapInfo, error)
 }
 
-// GetMintDecimals returns the decimals for a given mint address
+// GetMintDecimals returns the decimals for a given mint address, asking the
+// DecimalsResolver for mints that are not in the token balances
 func (ctx *TransactionContext) GetMintDecimals(mint solana.PublicKey) uint8 {
-	if decimals, exists := ctx.MintDecimals[mint.String()]; exists {
+	if decimals, exists := ctx.MintDecimals[mint]; exists {
 		return decimals
 	}
+
+	// Fetch mints that are not part of the token balances, e.g. created in this transaction
+	if ctx.DecimalsResolver != nil && !mint.IsZero() {
+		if decimals, err := ctx.DecimalsResolver.MintDecimals(mint); err == nil {
+			ctx.MintDecimals[mint] = decimals
+			return decimals
+		}
+	}
+
 	// Default to 9 decimals for unknown mints (common in Solana ecosystem)
 	return 9
 }
 
+// TokenBalanceChange returns the raw post minus pre balance of a token account,
+// and false if the account has no recorded token balance
+func (ctx *TransactionContext) TokenBalanceChange(account solana.PublicKey) (int64, bool) {
+	var pre, post int64
+	found := false
+
+	for _, balance := range ctx.Meta.PreTokenBalances {
+		if int(balance.AccountIndex) < len(ctx.AccountKeys) && ctx.AccountKeys[balance.AccountIndex].Equals(account) && balance.UiTokenAmount != nil {
+			pre, _ = strconv.ParseInt(balance.UiTokenAmount.Amount, 10, 64)
+			found = true
+			break
+		}
+	}
+	for _, balance := range ctx.Meta.PostTokenBalances {
+		if int(balance.AccountIndex) < len(ctx.AccountKeys) && ctx.AccountKeys[balance.AccountIndex].Equals(account) && balance.UiTokenAmount != nil {
+			post, _ = strconv.ParseInt(balance.UiTokenAmount.Amount, 10, 64)
+			found = true
+			break
+		}
+	}
+
+	return post - pre, found
+}
+
 // ExtractMintDecimals processes the transaction to extract token decimal information
 func (ctx *TransactionContext) ExtractMintDecimals() error {
-	ctx.MintDecimals = make(map[string]uint8)
+	ctx.MintDecimals = make(map[solana.PublicKey]uint8, len(ctx.Meta.PreTokenBalances)+1)
 
 	// Process token balances from transaction metadata
 	for _, balance := range ctx.Meta.PreTokenBalances {
 		if !balance.Mint.IsZero() {
-			ctx.MintDecimals[balance.Mint.String()] = uint8(balance.UiTokenAmount.Decimals)
+			ctx.MintDecimals[balance.Mint] = uint8(balance.UiTokenAmount.Decimals)
 		}
 	}
 	for _, balance := range ctx.Meta.PostTokenBalances {
 		if !balance.Mint.IsZero() {
-			ctx.MintDecimals[balance.Mint.String()] = uint8(balance.UiTokenAmount.Decimals)
+			ctx.MintDecimals[balance.Mint] = uint8(balance.UiTokenAmount.Decimals)
 		}
 	}
 
 	// Add Native SOL if not present
-	if _, exists := ctx.MintDecimals[NATIVE_SOL_PROGRAM_ID.String()]; !exists {
-		ctx.MintDecimals[NATIVE_SOL_PROGRAM_ID.String()] = 9
+	if _, exists := ctx.MintDecimals[NATIVE_SOL_PROGRAM_ID]; !exists {
+		ctx.MintDecimals[NATIVE_SOL_PROGRAM_ID] = 9
 	}
 
 	return nil
@@ -0,0 +1,20 @@
+package jito
+
+const (
+	// DEFAULT_BLOCK_ENGINE_URL is the mainnet block engine that routes to the closest region
+	DEFAULT_BLOCK_ENGINE_URL = "https://mainnet.block-engine.jito.wtf"
+
+	// BUNDLES_PATH is the JSON-RPC endpoint of the bundle API
+	BUNDLES_PATH = "/api/v1/bundles"
+
+	// MIN_TIP_LAMPORTS is the smallest tip the block engine accepts
+	MIN_TIP_LAMPORTS = 1_000
+)
+
+// Inflight bundle statuses reported by the block engine
+const (
+	StatusInvalid = "Invalid"
+	StatusPending = "Pending"
+	StatusFailed  = "Failed"
+	StatusLanded  = "Landed"
+)
@@ -0,0 +1,253 @@
+package jito
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/signer"
+)
+
+// Client submits bundles to a Jito block engine and tracks whether they land
+type Client struct {
+	rpcClient *rpc.Client
+	config    Config
+
+	mu          sync.Mutex
+	tipAccounts []solana.PublicKey
+}
+
+// New creates a block engine client, the RPC client provides blockhashes for tip transactions
+func New(rpcClient *rpc.Client, config Config) *Client {
+	if config.BlockEngineURL == "" {
+		config.BlockEngineURL = DEFAULT_BLOCK_ENGINE_URL
+	}
+	config.BlockEngineURL = strings.TrimRight(config.BlockEngineURL, "/")
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = time.Second
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Minute
+	}
+	return &Client{
+		rpcClient: rpcClient,
+		config:    config,
+	}
+}
+
+// TipAccounts returns the tip accounts of the block engine, fetched once and cached
+func (c *Client) TipAccounts(ctx context.Context) ([]solana.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tipAccounts != nil {
+		return c.tipAccounts, nil
+	}
+
+	var addresses []string
+	if err := c.call(ctx, "getTipAccounts", nil, &addresses); err != nil {
+		return nil, fmt.Errorf("failed to get tip accounts: %w", err)
+	}
+	accounts := make([]solana.PublicKey, 0, len(addresses))
+	for _, address := range addresses {
+		account, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tip account %q: %w", address, err)
+		}
+		accounts = append(accounts, account)
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("block engine returned no tip accounts")
+	}
+	c.tipAccounts = accounts
+	return accounts, nil
+}
+
+// TipInstruction transfers the tip to a random tip account, spreading load across them.
+// The well known tip accounts are used when the block engine cannot be reached.
+func (c *Client) TipInstruction(ctx context.Context, payer solana.PublicKey, lamports uint64) solana.Instruction {
+	accounts, err := c.TipAccounts(ctx)
+	if err != nil {
+		accounts = tx_parser.JITO_TIP_ACCOUNTS
+	}
+	return system.NewTransferInstruction(lamports, payer, accounts[rand.IntN(len(accounts))]).Build()
+}
+
+// BuildBundle appends a transaction paying the tip from tipper to the signed transactions.
+// The tip transaction reuses the blockhash of the first transaction so the bundle expires
+// as a whole.
+func (c *Client) BuildBundle(ctx context.Context, txs []*solana.Transaction, tipper signer.Signer, tip uint64) ([]*solana.Transaction, error) {
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("bundle has no transactions")
+	}
+	if len(txs)+1 > tx_parser.JITO_MAX_BUNDLE_SIZE {
+		return nil, fmt.Errorf("bundle of %d transactions leaves no room for the tip", len(txs))
+	}
+	if tip < MIN_TIP_LAMPORTS {
+		return nil, fmt.Errorf("tip of %d lamports is below the minimum of %d", tip, MIN_TIP_LAMPORTS)
+	}
+
+	blockhash := txs[0].Message.RecentBlockhash
+	if blockhash.IsZero() {
+		recent, err := c.rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get recent blockhash: %w", err)
+		}
+		blockhash = recent.Value.Blockhash
+	}
+
+	tipTx, err := solana.NewTransaction([]solana.Instruction{c.TipInstruction(ctx, tipper.PublicKey(), tip)}, blockhash, solana.TransactionPayer(tipper.PublicKey()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tip transaction: %w", err)
+	}
+	if err := signer.SignTransaction(ctx, tipTx, tipper); err != nil {
+		return nil, fmt.Errorf("failed to sign tip transaction: %w", err)
+	}
+	return append(append([]*solana.Transaction{}, txs...), tipTx), nil
+}
+
+// SendBundle submits signed transactions as a bundle and returns its ID
+func (c *Client) SendBundle(ctx context.Context, txs []*solana.Transaction) (string, error) {
+	if len(txs) == 0 || len(txs) > tx_parser.JITO_MAX_BUNDLE_SIZE {
+		return "", fmt.Errorf("bundle must hold 1 to %d transactions, got %d", tx_parser.JITO_MAX_BUNDLE_SIZE, len(txs))
+	}
+
+	encoded := make([]string, len(txs))
+	for i, tx := range txs {
+		if len(tx.Signatures) == 0 || tx.Signatures[0].IsZero() {
+			return "", fmt.Errorf("transaction %d of the bundle is not signed", i)
+		}
+		var err error
+		if encoded[i], err = tx.ToBase64(); err != nil {
+			return "", fmt.Errorf("failed to encode transaction %d: %w", i, err)
+		}
+	}
+
+	var bundleID string
+	if err := c.call(ctx, "sendBundle", []any{encoded, map[string]string{"encoding": "base64"}}, &bundleID); err != nil {
+		return "", fmt.Errorf("failed to send bundle: %w", err)
+	}
+	return bundleID, nil
+}
+
+// Status returns the landing status of a bundle, with the landed transactions once it landed
+func (c *Client) Status(ctx context.Context, bundleID string) (*BundleStatus, error) {
+	var inflight struct {
+		Value []*inflightStatus `json:"value"`
+	}
+	if err := c.call(ctx, "getInflightBundleStatuses", []any{[]string{bundleID}}, &inflight); err != nil {
+		return nil, fmt.Errorf("failed to get bundle status: %w", err)
+	}
+
+	status := &BundleStatus{BundleID: bundleID, Status: StatusInvalid}
+	if len(inflight.Value) > 0 && inflight.Value[0] != nil {
+		status.Status = inflight.Value[0].Status
+		status.Slot = inflight.Value[0].LandedSlot
+	}
+	if status.Status != StatusLanded {
+		return status, nil
+	}
+
+	var landed struct {
+		Value []*bundleStatus `json:"value"`
+	}
+	if err := c.call(ctx, "getBundleStatuses", []any{[]string{bundleID}}, &landed); err != nil {
+		return nil, fmt.Errorf("failed to get landed bundle: %w", err)
+	}
+	if len(landed.Value) > 0 && landed.Value[0] != nil {
+		detail := landed.Value[0]
+		status.Slot = detail.Slot
+		status.Transactions = detail.Transactions
+		status.ConfirmationStatus = detail.ConfirmationStatus
+		status.Err = detail.Err
+	}
+	return status, nil
+}
+
+// WaitForBundle polls the bundle until it lands, fails or the timeout passes. The last
+// status is returned along with the timeout error.
+func (c *Client) WaitForBundle(ctx context.Context, bundleID string) (*BundleStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+
+	var last *BundleStatus
+	for {
+		status, err := c.Status(ctx, bundleID)
+		if err == nil {
+			last = status
+			switch status.Status {
+			case StatusLanded:
+				return status, nil
+			case StatusFailed:
+				return status, fmt.Errorf("bundle %s failed", bundleID)
+			}
+			// a bundle is invalid until the block engine has seen it, so keep polling
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("bundle %s did not land: %w", bundleID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// call performs a JSON-RPC request against the bundle API
+func (c *Client) call(ctx context.Context, method string, params []any, result any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BlockEngineURL+BUNDLES_PATH, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.config.UUID != "" {
+		request.Header.Set("x-jito-auth", c.config.UUID)
+	}
+
+	response, err := c.config.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", response.StatusCode, data)
+	}
+
+	var decoded rpcResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if decoded.Error != nil {
+		return fmt.Errorf("%s (code %d)", decoded.Error.Message, decoded.Error.Code)
+	}
+	return json.Unmarshal(decoded.Result, result)
+}
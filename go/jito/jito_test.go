@@ -0,0 +1,130 @@
+package jito
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+
+	"github.com/soralabs/solana-toolkit/go/signer"
+)
+
+type blockEngine struct {
+	mu       sync.Mutex
+	tip      solana.PublicKey
+	bundles  [][]string
+	polls    int
+	landedAt int
+}
+
+func (e *blockEngine) serve(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != BUNDLES_PATH {
+			http.NotFound(w, r)
+			return
+		}
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		switch request.Method {
+		case "getTipAccounts":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":[%q]}`, e.tip)
+		case "sendBundle":
+			var txs []string
+			json.Unmarshal(request.Params[0], &txs)
+			e.bundles = append(e.bundles, txs)
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"bundle-1"}`)
+		case "getInflightBundleStatuses":
+			e.polls++
+			status := `{"bundle_id":"bundle-1","status":"Pending","landed_slot":null}`
+			if e.polls >= e.landedAt {
+				status = `{"bundle_id":"bundle-1","status":"Landed","landed_slot":42}`
+			}
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":40},"value":[%s]}}`, status)
+		case "getBundleStatuses":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":43},"value":[{"bundle_id":"bundle-1","transactions":["a","b"],"slot":42,"confirmation_status":"confirmed","err":{"Ok":null}}]}}`)
+		default:
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func signedTransfer(t *testing.T, key solana.PrivateKey) *solana.Transaction {
+	t.Helper()
+	tx, err := solana.NewTransaction([]solana.Instruction{
+		system.NewTransferInstruction(1, key.PublicKey(), solana.NewWallet().PublicKey()).Build(),
+	}, solana.Hash{9}, solana.TransactionPayer(key.PublicKey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &key })
+	return tx
+}
+
+func TestBundleLifecycle(t *testing.T) {
+	engine := &blockEngine{tip: solana.NewWallet().PublicKey(), landedAt: 3}
+	client := New(nil, Config{BlockEngineURL: engine.serve(t) + "/", PollInterval: time.Millisecond, Timeout: time.Second})
+	wallet := solana.NewWallet().PrivateKey
+
+	bundle, err := client.BuildBundle(context.Background(), []*solana.Transaction{signedTransfer(t, wallet)}, signer.NewKeypair(wallet), 10_000)
+	if err != nil {
+		t.Fatalf("failed to build bundle: %v", err)
+	}
+	tip := bundle[1]
+	if len(bundle) != 2 || tip.Message.RecentBlockhash != (solana.Hash{9}) || !tip.Message.AccountKeys.Contains(engine.tip) {
+		t.Fatalf("expected a tip to the block engine tip account with the bundle blockhash, got %v", tip)
+	}
+	if err := tip.VerifySignatures(); err != nil {
+		t.Errorf("invalid tip signature: %v", err)
+	}
+
+	id, err := client.SendBundle(context.Background(), bundle)
+	if err != nil || id != "bundle-1" || len(engine.bundles) != 1 || len(engine.bundles[0]) != 2 {
+		t.Fatalf("unexpected submission %q %v %v", id, err, engine.bundles)
+	}
+
+	status, err := client.WaitForBundle(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to wait for bundle: %v", err)
+	}
+	if status.Status != StatusLanded || status.Slot != 42 || len(status.Transactions) != 2 || status.ConfirmationStatus != "confirmed" || engine.polls != 3 {
+		t.Errorf("unexpected status %+v after %d polls", status, engine.polls)
+	}
+}
+
+func TestBundleValidation(t *testing.T) {
+	client := New(nil, Config{BlockEngineURL: (&blockEngine{tip: solana.NewWallet().PublicKey()}).serve(t)})
+	wallet := solana.NewWallet().PrivateKey
+
+	full := make([]*solana.Transaction, 5)
+	for i := range full {
+		full[i] = signedTransfer(t, wallet)
+	}
+	if _, err := client.BuildBundle(context.Background(), full, signer.NewKeypair(wallet), 10_000); err == nil {
+		t.Error("expected a full bundle to leave no room for the tip")
+	}
+	if _, err := client.BuildBundle(context.Background(), full[:1], signer.NewKeypair(wallet), 1); err == nil {
+		t.Error("expected a tip below the minimum to fail")
+	}
+
+	unsigned := signedTransfer(t, wallet)
+	unsigned.Signatures = nil
+	if _, err := client.SendBundle(context.Background(), []*solana.Transaction{unsigned}); err == nil {
+		t.Error("expected an unsigned transaction to be rejected")
+	}
+}
@@ -0,0 +1,65 @@
+package jito
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Config configures the block engine client
+type Config struct {
+	// BlockEngineURL is the block engine to submit to, defaults to DEFAULT_BLOCK_ENGINE_URL
+	BlockEngineURL string
+	// UUID authenticates requests for higher rate limits when set
+	UUID string
+	// HTTPClient overrides the client used for requests
+	HTTPClient *http.Client
+	// PollInterval is the delay between status checks while waiting, defaults to 1s
+	PollInterval time.Duration
+	// Timeout bounds how long WaitForBundle waits for a final status, defaults to 60s
+	Timeout time.Duration
+}
+
+// BundleStatus is the landing status of a submitted bundle
+type BundleStatus struct {
+	BundleID string
+	// Status is one of StatusInvalid, StatusPending, StatusFailed or StatusLanded
+	Status string
+	// Slot is the slot the bundle landed in
+	Slot uint64
+	// Transactions are the signatures of the landed transactions
+	Transactions []string
+	// ConfirmationStatus is the commitment the landed bundle reached
+	ConfirmationStatus string
+	// Err is the execution error of a landed bundle
+	Err json.RawMessage
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type inflightStatus struct {
+	BundleID   string `json:"bundle_id"`
+	Status     string `json:"status"`
+	LandedSlot uint64 `json:"landed_slot"`
+}
+
+type bundleStatus struct {
+	BundleID           string          `json:"bundle_id"`
+	Transactions       []string        `json:"transactions"`
+	Slot               uint64          `json:"slot"`
+	ConfirmationStatus string          `json:"confirmation_status"`
+	Err                json.RawMessage `json:"err"`
+}
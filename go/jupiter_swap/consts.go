@@ -0,0 +1,9 @@
+package jupiter_swap
+
+const (
+	// COMPUTE_BUDGET_SET_COMPUTE_UNIT_PRICE is the compute budget instruction that sets the priority fee
+	COMPUTE_BUDGET_SET_COMPUTE_UNIT_PRICE = 3
+
+	// DEFAULT_SLIPPAGE_BPS is the quote slippage used when none is configured
+	DEFAULT_SLIPPAGE_BPS = 50
+)
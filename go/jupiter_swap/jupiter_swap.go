@@ -0,0 +1,166 @@
+package jupiter_swap
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/ilkamo/jupiter-go/jupiter"
+
+	"github.com/soralabs/solana-toolkit/go/signer"
+)
+
+// Client quotes swaps through the Jupiter API and turns them into signed transactions
+type Client struct {
+	rpcClient *rpc.Client
+	api       *jupiter.ClientWithResponses
+	config    Config
+}
+
+// New creates a Jupiter swap client, the RPC client is only needed to send transactions
+func New(rpcClient *rpc.Client, config Config) (*Client, error) {
+	if config.APIURL == "" {
+		config.APIURL = jupiter.DefaultAPIURL
+	}
+	if config.SlippageBps == 0 {
+		config.SlippageBps = DEFAULT_SLIPPAGE_BPS
+	}
+
+	var options []jupiter.ClientOption
+	if config.HTTPClient != nil {
+		options = append(options, jupiter.WithHTTPClient(config.HTTPClient))
+	}
+	api, err := jupiter.NewClientWithResponses(config.APIURL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Jupiter client: %w", err)
+	}
+
+	return &Client{
+		rpcClient: rpcClient,
+		api:       api,
+		config:    config,
+	}, nil
+}
+
+// Quote fetches the best route for the request
+func (c *Client) Quote(ctx context.Context, request QuoteRequest) (*jupiter.QuoteResponse, error) {
+	slippage := request.SlippageBps
+	if slippage == 0 {
+		slippage = c.config.SlippageBps
+	}
+	slippageBps := int(slippage)
+	mode := jupiter.ExactIn
+	if request.ExactOut {
+		mode = jupiter.ExactOut
+	}
+
+	response, err := c.api.GetQuoteWithResponse(ctx, &jupiter.GetQuoteParams{
+		InputMint:   request.InputMint.String(),
+		OutputMint:  request.OutputMint.String(),
+		Amount:      int64(request.Amount),
+		SlippageBps: &slippageBps,
+		SwapMode:    &mode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+	if response.JSON200 == nil {
+		return nil, fmt.Errorf("failed to get quote: status %d: %s", response.StatusCode(), response.Body)
+	}
+	return response.JSON200, nil
+}
+
+// BuildSwap fetches the transaction for a quote, swapping from the user's wallet. The
+// configured fee payer and compute unit price are applied to the transaction, which is
+// returned unsigned.
+func (c *Client) BuildSwap(ctx context.Context, quote *jupiter.QuoteResponse, user solana.PublicKey) (*Swap, error) {
+	swap := &Swap{Quote: quote}
+	var err error
+	if swap.InAmount, err = strconv.ParseUint(quote.InAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("failed to parse quote in amount: %w", err)
+	}
+	if swap.OutAmount, err = strconv.ParseUint(quote.OutAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("failed to parse quote out amount: %w", err)
+	}
+	if swap.OtherAmountThreshold, err = strconv.ParseUint(quote.OtherAmountThreshold, 10, 64); err != nil {
+		return nil, fmt.Errorf("failed to parse quote threshold: %w", err)
+	}
+
+	dynamicComputeUnitLimit := true
+	body := jupiter.PostSwapJSONRequestBody{
+		QuoteResponse:           *quote,
+		UserPublicKey:           user.String(),
+		DynamicComputeUnitLimit: &dynamicComputeUnitLimit,
+	}
+	if c.config.ComputeUnitPrice == 0 {
+		prioritizationFeeLamports := jupiter.SwapRequest_PrioritizationFeeLamports{}
+		if err := prioritizationFeeLamports.UnmarshalJSON([]byte(`"auto"`)); err != nil {
+			return nil, err
+		}
+		body.PrioritizationFeeLamports = &prioritizationFeeLamports
+	}
+	if !c.config.FeePayer.IsZero() {
+		payer := c.config.FeePayer.String()
+		body.Payer = &payer
+	}
+
+	response, err := c.api.PostSwapWithResponse(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap transaction: %w", err)
+	}
+	if response.JSON200 == nil {
+		return nil, fmt.Errorf("failed to get swap transaction: status %d: %s", response.StatusCode(), response.Body)
+	}
+	swap.LastValidBlockHeight = uint64(response.JSON200.LastValidBlockHeight)
+
+	txBytes, err := base64.StdEncoding.DecodeString(response.JSON200.SwapTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 transaction: %w", err)
+	}
+	if swap.Transaction, err = solana.TransactionFromBytes(txBytes); err != nil {
+		return nil, fmt.Errorf("failed to deserialize transaction: %w", err)
+	}
+
+	if !c.config.FeePayer.IsZero() {
+		if err := SetFeePayer(swap.Transaction, c.config.FeePayer); err != nil {
+			return nil, err
+		}
+	}
+	if c.config.ComputeUnitPrice > 0 {
+		if err := SetComputeUnitPrice(swap.Transaction, c.config.ComputeUnitPrice); err != nil {
+			return nil, err
+		}
+	}
+	return swap, nil
+}
+
+// Swap quotes the request and builds the swap transaction signed by the wallet. When a
+// separate fee payer is configured its key must be among the extra signers.
+func (c *Client) Swap(ctx context.Context, request QuoteRequest, wallet signer.Signer, signers ...signer.Signer) (*Swap, error) {
+	quote, err := c.Quote(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	swap, err := c.BuildSwap(ctx, quote, wallet.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.SignTransaction(ctx, swap.Transaction, append([]signer.Signer{wallet}, signers...)...); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// Send submits a signed swap transaction
+func (c *Client) Send(ctx context.Context, swap *Swap) (solana.Signature, error) {
+	signature, err := c.rpcClient.SendTransactionWithOpts(ctx, swap.Transaction, rpc.TransactionOpts{
+		PreflightCommitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,141 @@
+package jupiter_swap
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+
+	"github.com/soralabs/solana-toolkit/go/signer"
+)
+
+// swapTransaction returns an unsigned transfer paid by the user, standing in for a Jupiter swap
+func swapTransaction(t *testing.T, user solana.PublicKey, withBudget bool) *solana.Transaction {
+	t.Helper()
+	var instructions []solana.Instruction
+	if withBudget {
+		instructions = append(instructions,
+			computebudget.NewSetComputeUnitLimitInstruction(200_000).Build(),
+			computebudget.NewSetComputeUnitPriceInstruction(5).Build(),
+		)
+	}
+	instructions = append(instructions, system.NewTransferInstruction(1, user, solana.NewWallet().PublicKey()).Build())
+	tx, err := solana.NewTransaction(instructions, solana.Hash{1}, solana.TransactionPayer(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func serve(t *testing.T, tx *solana.Transaction, swapBodies *[]map[string]any) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/quote":
+			query := r.URL.Query()
+			if query.Get("slippageBps") != "75" || query.Get("swapMode") != "ExactIn" {
+				t.Errorf("unexpected quote query %s", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `{"inputMint":%q,"outputMint":%q,"inAmount":%q,"outAmount":"2000","otherAmountThreshold":"1985","priceImpactPct":"0","routePlan":[],"slippageBps":75,"swapMode":"ExactIn"}`,
+				query.Get("inputMint"), query.Get("outputMint"), query.Get("amount"))
+		case "/swap":
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			*swapBodies = append(*swapBodies, body)
+			fmt.Fprintf(w, `{"swapTransaction":%q,"lastValidBlockHeight":1234,"prioritizationFeeLamports":0}`, tx.MustToBase64())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestSwap(t *testing.T) {
+	wallet, payer := solana.NewWallet().PrivateKey, solana.NewWallet().PrivateKey
+	var bodies []map[string]any
+	url := serve(t, swapTransaction(t, wallet.PublicKey(), true), &bodies)
+
+	client, err := New(nil, Config{APIURL: url, SlippageBps: 75, ComputeUnitPrice: 10_000, FeePayer: payer.PublicKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	swap, err := client.Swap(context.Background(), QuoteRequest{InputMint: solana.SolMint, OutputMint: solana.NewWallet().PublicKey(), Amount: 1_000}, signer.NewKeypair(wallet), signer.NewKeypair(payer))
+	if err != nil {
+		t.Fatalf("failed to swap: %v", err)
+	}
+
+	if swap.InAmount != 1_000 || swap.OutAmount != 2_000 || swap.OtherAmountThreshold != 1_985 || swap.LastValidBlockHeight != 1_234 {
+		t.Errorf("unexpected swap %+v", swap)
+	}
+	if len(bodies) != 1 || bodies[0]["payer"] != payer.PublicKey().String() || bodies[0]["userPublicKey"] != wallet.PublicKey().String() {
+		t.Errorf("unexpected swap request %v", bodies)
+	}
+	if _, ok := bodies[0]["prioritizationFeeLamports"]; ok {
+		t.Error("expected no automatic priority fee with a fixed compute unit price")
+	}
+
+	tx := swap.Transaction
+	if !tx.Message.AccountKeys[0].Equals(payer.PublicKey()) || tx.Message.Header.NumRequiredSignatures != 2 {
+		t.Fatalf("expected the payer as first of two signers, got %v", tx.Message.AccountKeys)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Errorf("invalid signatures: %v", err)
+	}
+
+	price := tx.Message.Instructions[1]
+	if binary.LittleEndian.Uint64(price.Data[1:]) != 10_000 {
+		t.Errorf("expected the compute unit price to be replaced, got %v", price.Data)
+	}
+	transfer := tx.Message.Instructions[2]
+	accounts, err := transfer.ResolveInstructionAccounts(&tx.Message)
+	if err != nil || !accounts[0].PublicKey.Equals(wallet.PublicKey()) {
+		t.Errorf("expected the transfer to stay with the wallet, got %v", accounts)
+	}
+}
+
+func TestSetComputeUnitPriceWithoutBudget(t *testing.T) {
+	user := solana.NewWallet().PublicKey()
+	tx := swapTransaction(t, user, false)
+	keys := len(tx.Message.AccountKeys)
+
+	if err := SetComputeUnitPrice(tx, 42); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Message.Instructions) != 2 || len(tx.Message.AccountKeys) != keys+1 || tx.Message.Header.NumReadonlyUnsignedAccounts != 2 {
+		t.Fatalf("expected a compute budget instruction and key, got %+v", tx.Message)
+	}
+	program, _ := tx.Message.Program(tx.Message.Instructions[0].ProgramIDIndex)
+	if !program.Equals(solana.ComputeBudget) || binary.LittleEndian.Uint64(tx.Message.Instructions[0].Data[1:]) != 42 {
+		t.Errorf("unexpected compute budget instruction %+v", tx.Message.Instructions[0])
+	}
+
+	// the transaction must survive a round trip
+	decoded, err := solana.TransactionFromBase64(tx.MustToBase64())
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, _ := decoded.Message.Program(decoded.Message.Instructions[1].ProgramIDIndex)
+	if !transfer.Equals(solana.SystemProgramID) {
+		t.Errorf("expected the transfer after the budget, got %s", transfer)
+	}
+}
+
+func TestSetFeePayerRejectsExistingAccount(t *testing.T) {
+	user := solana.NewWallet().PublicKey()
+	tx := swapTransaction(t, user, false)
+	if err := SetFeePayer(tx, user); err != nil {
+		t.Errorf("expected the current payer to be a no-op, got %v", err)
+	}
+	if err := SetFeePayer(tx, solana.SystemProgramID); err == nil {
+		t.Error("expected an error for a payer already in the transaction")
+	}
+}
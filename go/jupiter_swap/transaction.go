@@ -0,0 +1,78 @@
+package jupiter_swap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// SetFeePayer makes payer the fee payer of an unsigned transaction by adding it as the
+// first signer. Existing signatures are cleared.
+func SetFeePayer(tx *solana.Transaction, payer solana.PublicKey) error {
+	message := &tx.Message
+	if message.IsResolved() {
+		return fmt.Errorf("transaction lookups are already resolved")
+	}
+	if len(message.AccountKeys) > 0 && message.AccountKeys[0].Equals(payer) {
+		return nil
+	}
+	if message.AccountKeys.Contains(payer) {
+		return fmt.Errorf("fee payer %s is already an account of the transaction", payer)
+	}
+
+	message.AccountKeys = append(solana.PublicKeySlice{payer}, message.AccountKeys...)
+	message.Header.NumRequiredSignatures++
+	shiftIndexes(message, 0)
+	tx.Signatures = make([]solana.Signature, message.Header.NumRequiredSignatures)
+	return nil
+}
+
+// SetComputeUnitPrice replaces the priority fee of an unsigned transaction, adding a
+// compute budget instruction when it has none. Existing signatures are cleared.
+func SetComputeUnitPrice(tx *solana.Transaction, microLamports uint64) error {
+	message := &tx.Message
+	if message.IsResolved() {
+		return fmt.Errorf("transaction lookups are already resolved")
+	}
+
+	data := make([]byte, 9)
+	data[0] = COMPUTE_BUDGET_SET_COMPUTE_UNIT_PRICE
+	binary.LittleEndian.PutUint64(data[1:], microLamports)
+	tx.Signatures = make([]solana.Signature, message.Header.NumRequiredSignatures)
+
+	program := slices.Index(message.AccountKeys, solana.ComputeBudget)
+	if program >= 0 {
+		for i, instruction := range message.Instructions {
+			if int(instruction.ProgramIDIndex) == program && len(instruction.Data) > 0 && instruction.Data[0] == COMPUTE_BUDGET_SET_COMPUTE_UNIT_PRICE {
+				message.Instructions[i].Data = data
+				return nil
+			}
+		}
+	} else {
+		// read-only unsigned keys come last among the static keys, only lookup indexes move
+		program = len(message.AccountKeys)
+		message.AccountKeys = append(message.AccountKeys, solana.ComputeBudget)
+		message.Header.NumReadonlyUnsignedAccounts++
+		shiftIndexes(message, uint16(program))
+	}
+
+	message.Instructions = append([]solana.CompiledInstruction{{ProgramIDIndex: uint16(program), Data: data}}, message.Instructions...)
+	return nil
+}
+
+// shiftIndexes moves account indexes at or after from by one to make room for a new key
+func shiftIndexes(message *solana.Message, from uint16) {
+	for i := range message.Instructions {
+		instruction := &message.Instructions[i]
+		if instruction.ProgramIDIndex >= from {
+			instruction.ProgramIDIndex++
+		}
+		for j, index := range instruction.Accounts {
+			if index >= from {
+				instruction.Accounts[j] = index + 1
+			}
+		}
+	}
+}
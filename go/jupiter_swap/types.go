@@ -0,0 +1,44 @@
+package jupiter_swap
+
+import (
+	"net/http"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/ilkamo/jupiter-go/jupiter"
+)
+
+// Config configures the Jupiter swap client
+type Config struct {
+	// APIURL is the Jupiter swap API, defaults to jupiter.DefaultAPIURL
+	APIURL string
+	// HTTPClient overrides the client used for API requests
+	HTTPClient *http.Client
+	// SlippageBps is the default quote slippage, defaults to DEFAULT_SLIPPAGE_BPS
+	SlippageBps uint64
+	// ComputeUnitPrice replaces the priority fee of returned transactions, in micro-lamports.
+	// Zero keeps Jupiter's automatic priority fee.
+	ComputeUnitPrice uint64
+	// FeePayer pays the fees and rent of swaps instead of the swapping wallet
+	FeePayer solana.PublicKey
+}
+
+// QuoteRequest describes a swap to quote
+type QuoteRequest struct {
+	InputMint  solana.PublicKey
+	OutputMint solana.PublicKey
+	// Amount is the raw input amount, or the raw output amount when ExactOut is set
+	Amount      uint64
+	SlippageBps uint64
+	ExactOut    bool
+}
+
+// Swap is a quoted swap and its transaction
+type Swap struct {
+	Quote                *jupiter.QuoteResponse
+	Transaction          *solana.Transaction
+	LastValidBlockHeight uint64
+	InAmount             uint64
+	OutAmount            uint64
+	// OtherAmountThreshold is the minimum output of ExactIn swaps and the maximum input of ExactOut swaps
+	OtherAmountThreshold uint64
+}
@@ -0,0 +1,80 @@
+package labels
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// builtinLabels are the programs the parser decodes and the accounts it knows
+var builtinLabels = append([]Label{
+	{tx_parser.JUPITER_PROGRAM_ID, "Jupiter Aggregator v6", CategoryAggregator},
+	{tx_parser.JUPITER_DCA_PROGRAM_ID, "Jupiter DCA", CategoryAggregator},
+	{tx_parser.OKX_PROGRAM_ID, "OKX DEX Router", CategoryAggregator},
+
+	{tx_parser.RAYDIUM_V4_PROGRAM_ID, "Raydium AMM v4", CategoryDEX},
+	{tx_parser.RAYDIUM_AMM_PROGRAM_ID, "Raydium AMM Routing", CategoryDEX},
+	{tx_parser.RAYDIUM_CPMM_PROGRAM_ID, "Raydium CPMM", CategoryDEX},
+	{tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, "Raydium CLMM", CategoryDEX},
+	{tx_parser.METEORA_PROGRAM_ID, "Meteora DLMM", CategoryDEX},
+	{tx_parser.METEORA_POOLS_PROGRAM_ID, "Meteora Pools", CategoryDEX},
+	{tx_parser.METEORA_DAMM_V2_PROGRAM_ID, "Meteora DAMM v2", CategoryDEX},
+	{tx_parser.ORCA_PROGRAM_ID, "Orca Whirlpools", CategoryDEX},
+	{tx_parser.SABER_PROGRAM_ID, "Saber", CategoryDEX},
+	{tx_parser.MERCURIAL_PROGRAM_ID, "Mercurial", CategoryDEX},
+	{tx_parser.INVARIANT_PROGRAM_ID, "Invariant", CategoryDEX},
+	{tx_parser.CREMA_PROGRAM_ID, "Crema", CategoryDEX},
+	{tx_parser.FLUXBEAM_PROGRAM_ID, "FluxBeam", CategoryDEX},
+	{tx_parser.GOOSEFX_GAMMA_PROGRAM_ID, "GooseFX GAMMA", CategoryDEX},
+	{tx_parser.STABBLE_STABLE_SWAP_PROGRAM_ID, "Stabble Stable Swap", CategoryDEX},
+	{tx_parser.STABBLE_WEIGHTED_SWAP_PROGRAM_ID, "Stabble Weighted Swap", CategoryDEX},
+
+	{tx_parser.PUMP_FUN_PROGRAM_ID, "Pump.fun", CategoryLaunchpad},
+	{tx_parser.MOONSHOT_PROGRAM_ID, "Moonshot", CategoryLaunchpad},
+	{tx_parser.RAYDIUM_LAUNCHLAB_PROGRAM_ID, "Raydium LaunchLab", CategoryLaunchpad},
+	{tx_parser.METEORA_BONDING_CURVE_PROGRAM_ID, "Meteora Dynamic Bonding Curve", CategoryLaunchpad},
+	{tx_parser.HEAVEN_PROGRAM_ID, "Heaven", CategoryLaunchpad},
+	{tx_parser.BOOP_PROGRAM_ID, "Boop", CategoryLaunchpad},
+
+	{tx_parser.ZETA_PROGRAM_ID, "Zeta", CategoryPerps},
+	{tx_parser.JUPITER_PERPS_PROGRAM_ID, "Jupiter Perps", CategoryPerps},
+
+	{tx_parser.STAKE_PROGRAM_ID, "Stake Program", CategoryStaking},
+	{tx_parser.SPL_STAKE_POOL_PROGRAM_ID, "SPL Stake Pool", CategoryStaking},
+	{tx_parser.MARINADE_PROGRAM_ID, "Marinade", CategoryStaking},
+	{tx_parser.JITO_STAKE_POOL, "Jito Stake Pool", CategoryStaking},
+
+	{tx_parser.BUBBLEGUM_PROGRAM_ID, "Metaplex Bubblegum", CategoryNFT},
+	{tx_parser.CANDY_MACHINE_V3_PROGRAM_ID, "Candy Machine v3", CategoryNFT},
+	{tx_parser.CORE_CANDY_MACHINE_PROGRAM_ID, "Core Candy Machine", CategoryNFT},
+	{tx_parser.MPL_CORE_PROGRAM_ID, "Metaplex Core", CategoryNFT},
+
+	{tx_parser.NAME_SERVICE_PROGRAM_ID, "SPL Name Service", CategoryNameService},
+	{tx_parser.SOL_TLD_AUTHORITY, ".sol TLD", CategoryNameService},
+
+	{tx_parser.WORMHOLE_TOKEN_BRIDGE_PROGRAM_ID, "Wormhole Token Bridge", CategoryBridge},
+	{tx_parser.DEBRIDGE_DLN_SOURCE_PROGRAM_ID, "deBridge DLN Source", CategoryBridge},
+	{tx_parser.DEBRIDGE_DLN_DESTINATION_PROGRAM_ID, "deBridge DLN Destination", CategoryBridge},
+	{tx_parser.MAYAN_SWIFT_PROGRAM_ID, "Mayan Swift", CategoryBridge},
+
+	{solana.SystemProgramID, "System Program", CategorySystem},
+	{solana.TokenProgramID, "Token Program", CategorySystem},
+	{solana.Token2022ProgramID, "Token-2022 Program", CategorySystem},
+	{solana.SPLAssociatedTokenAccountProgramID, "Associated Token Account Program", CategorySystem},
+	{tx_parser.COMPUTE_BUDGET_PROGRAM_ID, "Compute Budget Program", CategorySystem},
+	{tx_parser.MEMO_PROGRAM_ID, "Memo Program", CategorySystem},
+	{tx_parser.MEMO_V1_PROGRAM_ID, "Memo Program v1", CategorySystem},
+	{tx_parser.VOTE_PROGRAM_ID, "Vote Program", CategorySystem},
+	{tx_parser.SPL_NOOP_PROGRAM_ID, "SPL Noop", CategorySystem},
+}, tipLabels()...)
+
+// tipLabels names the Jito tip accounts
+func tipLabels() []Label {
+	labels := make([]Label, len(tx_parser.JITO_TIP_ACCOUNTS))
+	for i, account := range tx_parser.JITO_TIP_ACCOUNTS {
+		labels[i] = Label{account, fmt.Sprintf("Jito Tip Account %d", i+1), CategoryTip}
+	}
+	return labels
+}
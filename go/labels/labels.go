@@ -0,0 +1,137 @@
+package labels
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/cex"
+)
+
+var publicKeyType = reflect.TypeFor[solana.PublicKey]()
+
+// Registry maps program IDs and well-known accounts to labels. It is safe for concurrent
+// use, including while labels are registered.
+type Registry struct {
+	mu     sync.RWMutex
+	labels map[solana.PublicKey]Label
+}
+
+// New creates a registry holding the built-in labels, the exchange wallets and the
+// configured labels
+func New(config Config) (*Registry, error) {
+	exchanges := config.Exchanges
+	if exchanges == nil {
+		var err error
+		if exchanges, err = cex.New(cex.Config{}); err != nil {
+			return nil, fmt.Errorf("failed to load exchange wallets: %w", err)
+		}
+	}
+
+	r := &Registry{labels: make(map[solana.PublicKey]Label, len(builtinLabels))}
+	r.Register(builtinLabels...)
+	for _, wallet := range exchanges.Wallets() {
+		r.Register(Label{Address: wallet.Address, Name: strings.TrimSpace(wallet.Exchange + " " + wallet.Label), Category: CategoryExchange})
+	}
+	r.Register(config.Labels...)
+	return r, nil
+}
+
+// Register adds labels, replacing any already registered for the same address
+func (r *Registry) Register(labels ...Label) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, label := range labels {
+		r.labels[label.Address] = label
+	}
+}
+
+// Remove drops the label of an address
+func (r *Registry) Remove(address solana.PublicKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.labels, address)
+}
+
+// Lookup returns the label of an address
+func (r *Registry) Lookup(address solana.PublicKey) (Label, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	label, ok := r.labels[address]
+	return label, ok
+}
+
+// Category returns every label in the category
+func (r *Registry) Category(category Category) []Label {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var labels []Label
+	for _, label := range r.labels {
+		if label.Category == category {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// Annotate returns the label of every labeled address in a parsed output, such as a
+// ParsedTransaction, a SwapInfo or a sink Event. Addresses are found in exported fields,
+// following pointers, slices and maps. Zero keys mark unset fields, so the System Program
+// is never annotated.
+func (r *Registry) Annotate(v any) map[solana.PublicKey]Label {
+	found := make(map[solana.PublicKey]Label)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.walk(reflect.ValueOf(v), found, make(map[uintptr]bool))
+	return found
+}
+
+// walk visits every public key reachable from the value, tracking visited pointers
+func (r *Registry) walk(value reflect.Value, found map[solana.PublicKey]Label, visited map[uintptr]bool) {
+	if !value.IsValid() {
+		return
+	}
+	if value.Type() == publicKeyType {
+		key := value.Interface().(solana.PublicKey)
+		if label, ok := r.labels[key]; ok && !key.IsZero() {
+			found[key] = label
+		}
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() || visited[value.Pointer()] {
+			return
+		}
+		visited[value.Pointer()] = true
+		r.walk(value.Elem(), found, visited)
+	case reflect.Interface:
+		if !value.IsNil() {
+			r.walk(value.Elem(), found, visited)
+		}
+	case reflect.Struct:
+		for i := range value.NumField() {
+			if value.Type().Field(i).IsExported() {
+				r.walk(value.Field(i), found, visited)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		// Byte arrays such as signatures hold no keys
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := range value.Len() {
+			r.walk(value.Index(i), found, visited)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			r.walk(iter.Key(), found, visited)
+			r.walk(iter.Value(), found, visited)
+		}
+	}
+}
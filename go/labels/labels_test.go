@@ -0,0 +1,62 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/cex"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func TestRegistry(t *testing.T) {
+	maker, wallet, exchange := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	exchanges, err := cex.New(cex.Config{Overrides: []cex.Wallet{{Address: exchange, Exchange: "Example", Label: "Hot Wallet"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := New(Config{Exchanges: exchanges, Labels: []Label{{Address: maker, Name: "Example Market Maker", Category: CategoryMarketMaker}}})
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	if label, ok := registry.Lookup(tx_parser.RAYDIUM_V4_PROGRAM_ID); !ok || label.Category != CategoryDEX {
+		t.Errorf("expected a built-in DEX label, got %+v", label)
+	}
+	if label, ok := registry.Lookup(exchange); !ok || label.Name != "Example Hot Wallet" || label.Category != CategoryExchange {
+		t.Errorf("expected the exchange wallet label, got %+v", label)
+	}
+	if tips := registry.Category(CategoryTip); len(tips) != len(tx_parser.JITO_TIP_ACCOUNTS) {
+		t.Errorf("expected every tip account labeled, got %d", len(tips))
+	}
+
+	tx := &tx_parser.ParsedTransaction{
+		Swaps:     []*tx_parser.SwapInfo{{Signers: []solana.PublicKey{wallet}}},
+		PerpFills: []*tx_parser.PerpFillInfo{{Program: tx_parser.ZETA_PROGRAM_ID, Trader: wallet}},
+		Transfers: []*tx_parser.TransferInfo{
+			{Source: wallet, Destination: tx_parser.JITO_TIP_ACCOUNTS[2], Program: solana.SystemProgramID},
+			{SourceOwner: maker, DestinationOwner: exchange},
+		},
+	}
+	found := registry.Annotate(tx)
+	for _, address := range []solana.PublicKey{tx_parser.ZETA_PROGRAM_ID, tx_parser.JITO_TIP_ACCOUNTS[2], maker, exchange} {
+		if _, ok := found[address]; !ok {
+			t.Errorf("expected %s to be annotated", address)
+		}
+	}
+	if _, ok := found[wallet]; ok || len(found) != 4 {
+		t.Errorf("expected only labeled, non-zero addresses, got %v", found)
+	}
+
+	// Labels registered at runtime apply to later annotations
+	registry.Register(Label{Address: wallet, Name: "Desk", Category: CategoryMarketMaker})
+	registry.Remove(maker)
+	found = registry.Annotate(sink.Events(tx)[0])
+	if label := found[wallet]; label.Name != "Desk" || len(found) != 1 {
+		t.Errorf("expected the sink event annotated with the new label, got %v", found)
+	}
+	if _, ok := registry.Lookup(maker); ok {
+		t.Error("expected the removed label to be gone")
+	}
+}
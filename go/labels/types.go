@@ -0,0 +1,41 @@
+package labels
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/cex"
+)
+
+// Category groups labeled addresses by what they are
+type Category string
+
+const (
+	CategoryDEX         Category = "dex"
+	CategoryAggregator  Category = "aggregator"
+	CategoryLaunchpad   Category = "launchpad"
+	CategoryPerps       Category = "perps"
+	CategoryStaking     Category = "staking"
+	CategoryNFT         Category = "nft"
+	CategoryNameService Category = "name_service"
+	CategoryBridge      Category = "bridge"
+	CategoryTip         Category = "tip"
+	CategoryExchange    Category = "exchange"
+	CategoryMarketMaker Category = "market_maker"
+	CategorySystem      Category = "system" // native and SPL programs every transaction touches
+)
+
+// Label names an address
+type Label struct {
+	Address  solana.PublicKey `json:"address"`
+	Name     string           `json:"name"`
+	Category Category         `json:"category"`
+}
+
+// Config controls the labels a registry starts with
+type Config struct {
+	// Exchanges labels exchange wallets, defaults to the embedded exchange dataset
+	Exchanges *cex.Classifier
+
+	// Labels are registered after the built-in labels, replacing any for the same address
+	Labels []Label
+}
@@ -0,0 +1,167 @@
+package landing_stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LATENCY_BUCKETS are the upper bounds of the confirmation latency histogram
+var LATENCY_BUCKETS = []time.Duration{
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	60 * time.Second,
+}
+
+// maxLatencySamples bounds the samples kept per key for percentile estimates
+const maxLatencySamples = 1024
+
+// Recorder aggregates transaction landing outcomes per venue and route. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.RWMutex
+	stats map[Key]*series
+}
+
+// series holds the running aggregates for a single key
+type series struct {
+	sent, landed, failed uint64
+	buckets              []uint64
+	latencySum           time.Duration
+	samples              []time.Duration // ring buffer of recent latencies
+	next                 int
+	failures             map[string]uint64
+}
+
+// NewRecorder creates an empty landing statistics recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		stats: make(map[Key]*series),
+	}
+}
+
+// Record adds a transaction outcome to the statistics
+func (r *Recorder) Record(outcome Outcome) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := Key{Venue: outcome.Venue, Route: outcome.Route}
+	s, ok := r.stats[key]
+	if !ok {
+		s = &series{
+			buckets:  make([]uint64, len(LATENCY_BUCKETS)),
+			failures: make(map[string]uint64),
+		}
+		r.stats[key] = s
+	}
+
+	s.sent++
+	if !outcome.Landed {
+		s.failed++
+		reason := outcome.FailureReason
+		if reason == "" {
+			reason = "unknown"
+		}
+		s.failures[reason]++
+		return
+	}
+
+	s.landed++
+	s.latencySum += outcome.Latency
+	for i, bound := range LATENCY_BUCKETS {
+		if outcome.Latency <= bound {
+			s.buckets[i]++
+		}
+	}
+	if len(s.samples) < maxLatencySamples {
+		s.samples = append(s.samples, outcome.Latency)
+	} else {
+		s.samples[s.next] = outcome.Latency
+		s.next = (s.next + 1) % maxLatencySamples
+	}
+}
+
+// Stats returns a snapshot of the aggregated statistics, sorted by venue then route
+func (r *Recorder) Stats() []Stats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Stats, 0, len(r.stats))
+	for key, s := range r.stats {
+		stats := Stats{
+			Key:            key,
+			Sent:           s.sent,
+			Landed:         s.landed,
+			Failed:         s.failed,
+			LatencyBuckets: append([]uint64(nil), s.buckets...),
+			LatencySum:     s.latencySum,
+			FailureReasons: make(map[string]uint64, len(s.failures)),
+		}
+		if s.sent > 0 {
+			stats.LandingRate = float64(s.landed) / float64(s.sent)
+		}
+		for reason, count := range s.failures {
+			stats.FailureReasons[reason] = count
+		}
+
+		samples := append([]time.Duration(nil), s.samples...)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		stats.LatencyP50 = percentile(samples, 0.50)
+		stats.LatencyP90 = percentile(samples, 0.90)
+		stats.LatencyP99 = percentile(samples, 0.99)
+
+		result = append(result, stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Venue != result[j].Venue {
+			return result[i].Venue < result[j].Venue
+		}
+		return result[i].Route < result[j].Route
+	})
+
+	return result
+}
+
+// SaveFile writes the current statistics as JSON so they can be reported on later
+func (r *Recorder) SaveFile(path string) error {
+	data, err := json.MarshalIndent(r.Stats(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode landing stats: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write landing stats: %w", err)
+	}
+	return nil
+}
+
+// LoadFile reads statistics previously written by SaveFile
+func LoadFile(path string) ([]Stats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read landing stats: %w", err)
+	}
+
+	var stats []Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode landing stats: %w", err)
+	}
+	return stats, nil
+}
+
+// percentile returns the q-th quantile of sorted samples
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(q*float64(len(sorted))+0.5) - 1
+	idx = max(0, min(idx, len(sorted)-1))
+	return sorted[idx]
+}
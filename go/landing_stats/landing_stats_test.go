@@ -0,0 +1,86 @@
+package landing_stats
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderStats(t *testing.T) {
+	recorder := NewRecorder()
+	for _, latency := range []time.Duration{300 * time.Millisecond, 800 * time.Millisecond, 4 * time.Second} {
+		recorder.Record(Outcome{Venue: "jupiter", Route: RouteJito, Landed: true, Latency: latency})
+	}
+	recorder.Record(Outcome{Venue: "jupiter", Route: RouteJito, FailureReason: FailureTimeout})
+	recorder.Record(Outcome{Venue: "jupiter", Route: RoutePublicRPC, FailureReason: FailureSendError})
+
+	stats := recorder.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(stats))
+	}
+
+	jito := stats[0]
+	if jito.Route != RouteJito || jito.Sent != 4 || jito.Landed != 3 || jito.Failed != 1 {
+		t.Fatalf("unexpected jito stats: %+v", jito)
+	}
+	if jito.LandingRate != 0.75 {
+		t.Errorf("expected landing rate 0.75, got %f", jito.LandingRate)
+	}
+	if jito.LatencyP50 != 800*time.Millisecond || jito.LatencyP99 != 4*time.Second {
+		t.Errorf("unexpected latency percentiles: p50=%s p99=%s", jito.LatencyP50, jito.LatencyP99)
+	}
+	if jito.LatencyBuckets[0] != 0 || jito.LatencyBuckets[1] != 1 || jito.LatencyBuckets[2] != 2 || jito.LatencyBuckets[4] != 3 {
+		t.Errorf("unexpected latency buckets: %v", jito.LatencyBuckets)
+	}
+	if jito.FailureReasons[FailureTimeout] != 1 {
+		t.Errorf("expected one timeout, got %v", jito.FailureReasons)
+	}
+
+	rpc := stats[1]
+	if rpc.LandingRate != 0 || rpc.FailureReasons[FailureSendError] != 1 {
+		t.Errorf("unexpected rpc stats: %+v", rpc)
+	}
+}
+
+func TestSaveLoadAndReport(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.Record(Outcome{Venue: "pumpfun", Route: RouteTPU, Landed: true, Latency: time.Second})
+	recorder.Record(Outcome{Venue: "pumpfun", Route: RouteTPU, FailureReason: FailureExecution})
+
+	path := filepath.Join(t.TempDir(), "landing.json")
+	if err := recorder.SaveFile(path); err != nil {
+		t.Fatalf("failed to save stats: %v", err)
+	}
+	stats, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("failed to load stats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Landed != 1 || stats[0].LatencyP50 != time.Second {
+		t.Fatalf("unexpected loaded stats: %+v", stats)
+	}
+
+	var report bytes.Buffer
+	if err := WriteReport(&report, stats); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	if !strings.Contains(report.String(), "50.0%") || !strings.Contains(report.String(), "execution_error=1") {
+		t.Errorf("unexpected report:\n%s", report.String())
+	}
+
+	var metrics bytes.Buffer
+	if err := WritePrometheus(&metrics, stats); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+	for _, want := range []string{
+		`solana_toolkit_tx_sent_total{venue="pumpfun",route="tpu"} 2`,
+		`solana_toolkit_tx_failed_total{venue="pumpfun",route="tpu",reason="execution_error"} 1`,
+		`solana_toolkit_tx_confirmation_seconds_bucket{venue="pumpfun",route="tpu",le="1"} 1`,
+		`solana_toolkit_tx_confirmation_seconds_count{venue="pumpfun",route="tpu"} 1`,
+	} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}
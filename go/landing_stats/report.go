@@ -0,0 +1,108 @@
+package landing_stats
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteReport renders the statistics as a human readable table
+func WriteReport(w io.Writer, stats []Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VENUE\tROUTE\tSENT\tLANDED\tRATE\tP50\tP90\tP99\tTOP FAILURES")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f%%\t%s\t%s\t%s\t%s\n",
+			s.Venue, s.Route, s.Sent, s.Landed, s.LandingRate*100,
+			s.LatencyP50, s.LatencyP90, s.LatencyP99, topFailures(s.FailureReasons, 3))
+	}
+	return tw.Flush()
+}
+
+// WritePrometheus renders the statistics in the Prometheus text exposition format
+func WritePrometheus(w io.Writer, stats []Stats) error {
+	var b strings.Builder
+
+	b.WriteString("# HELP solana_toolkit_tx_sent_total Transactions submitted.\n")
+	b.WriteString("# TYPE solana_toolkit_tx_sent_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(&b, "solana_toolkit_tx_sent_total{%s} %d\n", labels(s.Key), s.Sent)
+	}
+
+	b.WriteString("# HELP solana_toolkit_tx_landed_total Transactions confirmed on chain.\n")
+	b.WriteString("# TYPE solana_toolkit_tx_landed_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(&b, "solana_toolkit_tx_landed_total{%s} %d\n", labels(s.Key), s.Landed)
+	}
+
+	b.WriteString("# HELP solana_toolkit_tx_failed_total Transactions that did not land, by reason.\n")
+	b.WriteString("# TYPE solana_toolkit_tx_failed_total counter\n")
+	for _, s := range stats {
+		reasons := make([]string, 0, len(s.FailureReasons))
+		for reason := range s.FailureReasons {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Fprintf(&b, "solana_toolkit_tx_failed_total{%s,reason=%q} %d\n", labels(s.Key), reason, s.FailureReasons[reason])
+		}
+	}
+
+	b.WriteString("# HELP solana_toolkit_tx_confirmation_seconds Time from submission to confirmation.\n")
+	b.WriteString("# TYPE solana_toolkit_tx_confirmation_seconds histogram\n")
+	for _, s := range stats {
+		for i, bound := range LATENCY_BUCKETS {
+			var count uint64
+			if i < len(s.LatencyBuckets) {
+				count = s.LatencyBuckets[i]
+			}
+			fmt.Fprintf(&b, "solana_toolkit_tx_confirmation_seconds_bucket{%s,le=\"%g\"} %d\n", labels(s.Key), bound.Seconds(), count)
+		}
+		fmt.Fprintf(&b, "solana_toolkit_tx_confirmation_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels(s.Key), s.Landed)
+		fmt.Fprintf(&b, "solana_toolkit_tx_confirmation_seconds_sum{%s} %g\n", labels(s.Key), s.LatencySum.Seconds())
+		fmt.Fprintf(&b, "solana_toolkit_tx_confirmation_seconds_count{%s} %d\n", labels(s.Key), s.Landed)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler serves the recorder's statistics in the Prometheus text format
+func (r *Recorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := WritePrometheus(w, r.Stats()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func labels(key Key) string {
+	return fmt.Sprintf("venue=%q,route=%q", key.Venue, key.Route)
+}
+
+// topFailures formats the n most frequent failure reasons
+func topFailures(reasons map[string]uint64, n int) string {
+	if len(reasons) == 0 {
+		return "-"
+	}
+
+	names := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		names = append(names, reason)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if reasons[names[i]] != reasons[names[j]] {
+			return reasons[names[i]] > reasons[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, 0, n)
+	for _, name := range names[:min(n, len(names))] {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, reasons[name]))
+	}
+	return strings.Join(parts, " ")
+}
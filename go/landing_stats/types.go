@@ -0,0 +1,52 @@
+package landing_stats
+
+import "time"
+
+// Route identifies the path a transaction was submitted through
+type Route string
+
+const (
+	RoutePublicRPC Route = "rpc"
+	RouteJito      Route = "jito"
+	RouteTPU       Route = "tpu"
+)
+
+// Failure reasons recorded for transactions that did not land
+const (
+	FailureSendError = "send_error"
+	FailureRPCError  = "rpc_error"
+	FailureTimeout   = "timeout"
+	FailureExecution = "execution_error"
+	FailureExpired   = "blockhash_expired"
+)
+
+// Outcome is the result of a single submitted transaction
+type Outcome struct {
+	Venue         string // program or venue the transaction targeted, e.g. "jupiter"
+	Route         Route
+	Landed        bool
+	Latency       time.Duration // time from submission to confirmation, zero if not landed
+	FailureReason string
+	Timestamp     time.Time
+}
+
+// Key groups outcomes by venue and route
+type Key struct {
+	Venue string `json:"venue"`
+	Route Route  `json:"route"`
+}
+
+// Stats is the aggregated landing performance of one venue and route
+type Stats struct {
+	Key
+	Sent           uint64            `json:"sent"`
+	Landed         uint64            `json:"landed"`
+	Failed         uint64            `json:"failed"`
+	LandingRate    float64           `json:"landing_rate"`
+	LatencyP50     time.Duration     `json:"latency_p50"`
+	LatencyP90     time.Duration     `json:"latency_p90"`
+	LatencyP99     time.Duration     `json:"latency_p99"`
+	LatencyBuckets []uint64          `json:"latency_buckets"` // cumulative counts per LATENCY_BUCKETS bound
+	LatencySum     time.Duration     `json:"latency_sum"`
+	FailureReasons map[string]uint64 `json:"failure_reasons"`
+}
@@ -0,0 +1,11 @@
+package logging
+
+// Field keys shared by every component so log lines can be filtered consistently
+const (
+	SIGNATURE_KEY         = "signature"
+	SLOT_KEY              = "slot"
+	PARSER_KEY            = "parser"
+	INSTRUCTION_INDEX_KEY = "instruction_index"
+	INNER_INDEX_KEY       = "inner_index"
+	ERROR_KEY             = "error"
+)
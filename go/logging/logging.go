@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger is the structured logger the toolkit reports through. *slog.Logger implements
+// it, other libraries can be plugged in with a small adapter.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	WarnContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+var _ Logger = (*slog.Logger)(nil)
+
+// Nop returns a logger that discards everything
+func Nop() Logger {
+	return nop{}
+}
+
+// OrNop returns the logger, or a no-op logger if it is nil
+func OrNop(logger Logger) Logger {
+	if logger == nil {
+		return nop{}
+	}
+	return logger
+}
+
+// nop discards every record
+type nop struct{}
+
+func (nop) DebugContext(ctx context.Context, msg string, args ...any) {}
+func (nop) InfoContext(ctx context.Context, msg string, args ...any)  {}
+func (nop) WarnContext(ctx context.Context, msg string, args ...any)  {}
+func (nop) ErrorContext(ctx context.Context, msg string, args ...any) {}
@@ -0,0 +1,19 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestOrNop(t *testing.T) {
+	OrNop(nil).ErrorContext(context.Background(), "discarded", ERROR_KEY, "boom")
+
+	var buf bytes.Buffer
+	logger := OrNop(slog.New(slog.NewTextHandler(&buf, nil)))
+	logger.InfoContext(context.Background(), "parsed", SLOT_KEY, 42)
+	if !bytes.Contains(buf.Bytes(), []byte("slot=42")) {
+		t.Errorf("expected the slot field, got %q", buf.String())
+	}
+}
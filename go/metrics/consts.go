@@ -0,0 +1,8 @@
+package metrics
+
+const (
+	DEFAULT_NAMESPACE = "solana_toolkit"
+
+	// TRANSACTION_ERROR_PROTOCOL labels parse errors of transactions that failed as a whole
+	TRANSACTION_ERROR_PROTOCOL = "transaction"
+)
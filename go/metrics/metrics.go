@@ -0,0 +1,260 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Metrics instruments the ingestion, parsing and sink layers. Rates such as transactions
+// parsed per second are derived from the counters, e.g.
+// rate(solana_toolkit_transactions_parsed_total[1m]).
+type Metrics struct {
+	config Config
+
+	transactionsParsed *prometheus.CounterVec
+	eventsParsed       *prometheus.CounterVec
+	parseErrors        *prometheus.CounterVec
+	swapComputeUnits   *prometheus.HistogramVec
+	processedSlot      prometheus.Gauge
+	slotLag            prometheus.Gauge
+	catchingUp         prometheus.Gauge
+	catchupSlots       prometheus.Counter
+	sinkFlush          *prometheus.HistogramVec
+	sinkEvents         *prometheus.CounterVec
+	sinkErrors         *prometheus.CounterVec
+
+	lastSlot atomic.Uint64
+}
+
+// New creates the collectors and registers them
+func New(config Config) (*Metrics, error) {
+	if config.Namespace == "" {
+		config.Namespace = DEFAULT_NAMESPACE
+	}
+	if config.Registry == nil {
+		config.Registry = prometheus.NewRegistry()
+		config.Registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
+	if len(config.FlushBuckets) == 0 {
+		config.FlushBuckets = prometheus.DefBuckets
+	}
+	if len(config.ComputeUnitBuckets) == 0 {
+		config.ComputeUnitBuckets = prometheus.ExponentialBuckets(10_000, 2, 8)
+	}
+	if config.Commitment == "" {
+		config.Commitment = rpc.CommitmentConfirmed
+	}
+
+	m := &Metrics{
+		config: config,
+		transactionsParsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "transactions_parsed_total",
+			Help:      "Transactions parsed, by source.",
+		}, []string{"source"}),
+		eventsParsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "events_parsed_total",
+			Help:      "Events extracted from parsed transactions, by kind.",
+		}, []string{"kind"}),
+		parseErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "parse_errors_total",
+			Help:      "Instructions that could not be parsed, by protocol.",
+		}, []string{"protocol"}),
+		swapComputeUnits: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "swap_compute_units",
+			Help:      "Compute units consumed by the instructions swaps were parsed from, by protocol.",
+			Buckets:   config.ComputeUnitBuckets,
+		}, []string{"protocol"}),
+		processedSlot: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: config.Namespace,
+			Name:      "processed_slot",
+			Help:      "Highest slot of a parsed transaction.",
+		}),
+		slotLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: config.Namespace,
+			Name:      "slot_lag",
+			Help:      "Slots between the chain tip and the highest processed slot.",
+		}),
+		catchingUp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: config.Namespace,
+			Name:      "catching_up",
+			Help:      "1 while the pipeline catches up with a backfill instead of streaming.",
+		}),
+		catchupSlots: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "catchup_slots_total",
+			Help:      "Slots processed by catch-up backfills.",
+		}),
+		sinkFlush: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "sink_flush_seconds",
+			Help:      "Latency of sink writes, by sink.",
+			Buckets:   config.FlushBuckets,
+		}, []string{"sink"}),
+		sinkEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "sink_events_total",
+			Help:      "Events written to sinks, by sink.",
+		}, []string{"sink"}),
+		sinkErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "sink_errors_total",
+			Help:      "Failed sink writes, by sink.",
+		}, []string{"sink"}),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		m.transactionsParsed, m.eventsParsed, m.parseErrors, m.swapComputeUnits, m.processedSlot,
+		m.slotLag, m.catchingUp, m.catchupSlots, m.sinkFlush, m.sinkEvents, m.sinkErrors,
+	} {
+		if err := config.Registry.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// Handler serves the registry in the Prometheus exposition format, typically on /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.config.Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveTransaction records a transaction parsed from the source
+func (m *Metrics) ObserveTransaction(source string, tx *tx_parser.ParsedTransaction) {
+	m.transactionsParsed.WithLabelValues(source).Inc()
+	for kind, count := range map[sink.Kind]int{
+		sink.KindSwap:          len(tx.Swaps),
+		sink.KindTransfer:      len(tx.Transfers),
+		sink.KindStake:         len(tx.StakeEvents),
+		sink.KindTokenSupply:   len(tx.SupplyEvents),
+		sink.KindTokenAdmin:    len(tx.AdminEvents),
+		sink.KindPoolCreated:   len(tx.PoolCreations),
+		sink.KindPerpFill:      len(tx.PerpFills),
+		sink.KindCompressedNft: len(tx.CompressedNfts),
+		sink.KindNftMint:       len(tx.NftMints),
+		sink.KindDomain:        len(tx.DomainEvents),
+		sink.KindBridge:        len(tx.Bridges),
+	} {
+		if count > 0 {
+			m.eventsParsed.WithLabelValues(string(kind)).Add(float64(count))
+		}
+	}
+	for _, swap := range tx.Swaps {
+		if swap.ComputeUnits > 0 {
+			m.swapComputeUnits.WithLabelValues(string(swap.Protocol)).Observe(float64(swap.ComputeUnits))
+		}
+	}
+	for _, parseErr := range tx.Errors {
+		m.parseErrors.WithLabelValues(string(parseErr.Protocol)).Inc()
+	}
+
+	for {
+		last := m.lastSlot.Load()
+		if tx.Slot <= last {
+			break
+		}
+		if m.lastSlot.CompareAndSwap(last, tx.Slot) {
+			m.processedSlot.Set(float64(tx.Slot))
+			break
+		}
+	}
+}
+
+// ObserveTransactionError records a transaction that failed to be fetched or parsed as a whole
+func (m *Metrics) ObserveTransactionError() {
+	m.parseErrors.WithLabelValues(TRANSACTION_ERROR_PROTOCOL).Inc()
+}
+
+// TrackSlotLag polls the chain tip at the interval and updates the slot lag against the
+// highest processed slot until the context is cancelled. Failed polls are skipped.
+func (m *Metrics) TrackSlotLag(ctx context.Context, rpcClient *rpc.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.updateSlotLag(ctx, rpcClient)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateSlotLag sets the slot lag once a transaction was processed
+func (m *Metrics) updateSlotLag(ctx context.Context, rpcClient *rpc.Client) {
+	last := m.lastSlot.Load()
+	if last == 0 {
+		return
+	}
+	tip, err := rpcClient.GetSlot(ctx, m.config.Commitment)
+	if err != nil {
+		return
+	}
+	m.slotLag.Set(float64(max(tip, last) - last))
+}
+
+// ObserveSlotLag sets the slot lag measured elsewhere, e.g. by a catchup.Monitor, in
+// place of TrackSlotLag
+func (m *Metrics) ObserveSlotLag(lag uint64) {
+	m.slotLag.Set(float64(lag))
+}
+
+// ObserveCatchingUp records whether the pipeline switched to a catch-up backfill
+func (m *Metrics) ObserveCatchingUp(catchingUp bool) {
+	if catchingUp {
+		m.catchingUp.Set(1)
+		return
+	}
+	m.catchingUp.Set(0)
+}
+
+// ObserveCatchupSlots records slots processed by a catch-up backfill
+func (m *Metrics) ObserveCatchupSlots(slots uint64) {
+	m.catchupSlots.Add(float64(slots))
+}
+
+// InstrumentSink wraps a sink to record the latency, volume and failures of its writes
+func (m *Metrics) InstrumentSink(name string, s sink.Sink) sink.Sink {
+	return &instrumentedSink{
+		Sink:    s,
+		latency: m.sinkFlush.WithLabelValues(name),
+		events:  m.sinkEvents.WithLabelValues(name),
+		errors:  m.sinkErrors.WithLabelValues(name),
+	}
+}
+
+// instrumentedSink records the writes of the sink it wraps
+type instrumentedSink struct {
+	sink.Sink
+	latency prometheus.Observer
+	events  prometheus.Counter
+	errors  prometheus.Counter
+}
+
+// Write times the write of the wrapped sink
+func (s *instrumentedSink) Write(ctx context.Context, events []*sink.Event) error {
+	start := time.Now()
+	err := s.Sink.Write(ctx, events)
+	s.latency.Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.errors.Inc()
+		return err
+	}
+	s.events.Add(float64(len(events)))
+	return nil
+}
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// failingSink fails every other write
+type failingSink struct {
+	writes int
+}
+
+func (s *failingSink) Write(ctx context.Context, events []*sink.Event) error {
+	s.writes++
+	if s.writes%2 == 0 {
+		return errors.New("write failed")
+	}
+	return nil
+}
+
+func (s *failingSink) Close() error { return nil }
+
+func newMetrics(t *testing.T) *Metrics {
+	m, err := New(Config{Registry: prometheus.NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestObserveTransaction(t *testing.T) {
+	m := newMetrics(t)
+
+	m.ObserveTransaction("websocket", &tx_parser.ParsedTransaction{
+		Slot:      20,
+		Swaps:     []*tx_parser.SwapInfo{{Protocol: tx_parser.SwapTypeRaydium, ComputeUnits: 45_000}, {}},
+		Transfers: []*tx_parser.TransferInfo{{}},
+		Errors:    []*tx_parser.ParseError{{Protocol: tx_parser.SwapTypeOrca}},
+	})
+	m.ObserveTransaction("websocket", &tx_parser.ParsedTransaction{Slot: 10})
+	m.ObserveTransactionError()
+
+	if got := testutil.ToFloat64(m.transactionsParsed.WithLabelValues("websocket")); got != 2 {
+		t.Errorf("expected 2 transactions, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.eventsParsed.WithLabelValues(string(sink.KindSwap))); got != 2 {
+		t.Errorf("expected 2 swaps, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.swapComputeUnits); got != 1 {
+		t.Errorf("expected compute units observed for the swap that logged them, got %d series", got)
+	}
+	if got := testutil.ToFloat64(m.parseErrors.WithLabelValues(string(tx_parser.SwapTypeOrca))); got != 1 {
+		t.Errorf("expected 1 Orca error, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.parseErrors.WithLabelValues(TRANSACTION_ERROR_PROTOCOL)); got != 1 {
+		t.Errorf("expected 1 transaction error, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.processedSlot); got != 20 {
+		t.Errorf("expected processed slot 20, got %v", got)
+	}
+}
+
+func TestSlotLag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":125}`)
+	}))
+	defer server.Close()
+	m := newMetrics(t)
+
+	// no lag is reported before the first transaction
+	m.updateSlotLag(context.Background(), rpc.New(server.URL))
+	if got := testutil.ToFloat64(m.slotLag); got != 0 {
+		t.Errorf("expected no lag, got %v", got)
+	}
+	m.ObserveTransaction("geyser", &tx_parser.ParsedTransaction{Slot: 100})
+	m.updateSlotLag(context.Background(), rpc.New(server.URL))
+	if got := testutil.ToFloat64(m.slotLag); got != 25 {
+		t.Errorf("expected a lag of 25 slots, got %v", got)
+	}
+}
+
+func TestCatchup(t *testing.T) {
+	m := newMetrics(t)
+
+	m.ObserveSlotLag(400)
+	m.ObserveCatchingUp(true)
+	m.ObserveCatchupSlots(350)
+	m.ObserveCatchupSlots(40)
+	if got := testutil.ToFloat64(m.slotLag); got != 400 {
+		t.Errorf("expected a lag of 400 slots, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.catchingUp); got != 1 {
+		t.Errorf("expected catching up, got %v", got)
+	}
+	m.ObserveCatchingUp(false)
+	if got := testutil.ToFloat64(m.catchingUp); got != 0 {
+		t.Errorf("expected streaming, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.catchupSlots); got != 390 {
+		t.Errorf("expected 390 caught up slots, got %v", got)
+	}
+}
+
+func TestInstrumentSink(t *testing.T) {
+	m := newMetrics(t)
+	s := m.InstrumentSink("kafka", &failingSink{})
+
+	events := []*sink.Event{{Kind: sink.KindSwap}, {Kind: sink.KindTransfer}}
+	if err := s.Write(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(context.Background(), events); err == nil {
+		t.Fatal("expected the second write to fail")
+	}
+
+	if got := testutil.ToFloat64(m.sinkEvents.WithLabelValues("kafka")); got != 2 {
+		t.Errorf("expected 2 events, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.sinkErrors.WithLabelValues("kafka")); got != 1 {
+		t.Errorf("expected 1 error, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.sinkFlush); got != 1 {
+		t.Errorf("expected one flush histogram, got %d", got)
+	}
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+	if !strings.Contains(string(body), `solana_toolkit_sink_flush_seconds_count{sink="kafka"} 2`) {
+		t.Errorf("flush latency missing from exposition:\n%s", body)
+	}
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config controls the metrics collectors
+type Config struct {
+	// Namespace prefixes every metric name, defaults to DEFAULT_NAMESPACE
+	Namespace string
+
+	// Registry the collectors are registered with and Handler serves, defaults to a new
+	// registry that also collects Go runtime and process metrics
+	Registry *prometheus.Registry
+
+	// FlushBuckets are the histogram buckets of sink flush latencies in seconds, default
+	// to prometheus.DefBuckets
+	FlushBuckets []float64
+
+	// ComputeUnitBuckets are the histogram buckets of the compute units swaps consume,
+	// default to 10k doubling up to 1.28M
+	ComputeUnitBuckets []float64
+
+	// Commitment of the chain tip TrackSlotLag compares against, defaults to confirmed
+	Commitment rpc.CommitmentType
+}
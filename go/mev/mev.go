@@ -0,0 +1,184 @@
+package mev
+
+import (
+	"bytes"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Detector finds sandwich attacks in transactions in execution order. Swaps do not carry
+// the pool account, so swaps of the same protocol and token pair are treated as one pool.
+type Detector struct {
+	config Config
+}
+
+// entry is a swap with the position of its transaction and the wallet that made it
+type entry struct {
+	position int
+	tx       *tx_parser.ParsedTransaction
+	swap     *tx_parser.SwapInfo
+	trader   solana.PublicKey
+}
+
+type marketKey struct {
+	protocol tx_parser.SwapType
+	a, b     solana.PublicKey
+}
+
+// New creates a sandwich detector
+func New(config Config) *Detector {
+	if config.Tolerance <= 0 {
+		config.Tolerance = 0.1
+	}
+	return &Detector{config: config}
+}
+
+// DetectBlock finds the sandwiches of a parsed block
+func (d *Detector) DetectBlock(block *tx_parser.BlockResult) []*Sandwich {
+	txs := make([]*tx_parser.ParsedTransaction, 0, len(block.Order))
+	for _, signature := range block.Order {
+		txs = append(txs, block.Transactions[signature])
+	}
+	return d.Detect(txs)
+}
+
+// Detect finds sandwiches in transactions given in execution order, e.g. a block or a
+// bundle, and tags their swaps with MEV info. A sandwich is a swap followed by the same
+// wallet's reverse swap of about the amount it received, with swaps by other wallets in
+// the same direction in between.
+func (d *Detector) Detect(txs []*tx_parser.ParsedTransaction) []*Sandwich {
+	var keys []marketKey
+	markets := make(map[marketKey][]*entry)
+	for position, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		for _, swap := range tx.Swaps {
+			if swap.TokenIn.Amount == 0 || swap.TokenOut.Amount == 0 {
+				continue
+			}
+			key := d.market(swap)
+			if _, ok := markets[key]; !ok {
+				keys = append(keys, key)
+			}
+			markets[key] = append(markets[key], &entry{position: position, tx: tx, swap: swap, trader: trader(tx, swap)})
+		}
+	}
+
+	var sandwiches []*Sandwich
+	for _, key := range keys {
+		sandwiches = append(sandwiches, d.detectMarket(markets[key])...)
+	}
+	return sandwiches
+}
+
+// detectMarket matches each swap with the trader's next reverse swap in the market
+func (d *Detector) detectMarket(entries []*entry) []*Sandwich {
+	var sandwiches []*Sandwich
+	used := make([]bool, len(entries))
+
+	for i, front := range entries {
+		if used[i] {
+			continue
+		}
+		for j := i + 1; j < len(entries); j++ {
+			back := entries[j]
+			if used[j] || back.position == front.position || !back.trader.Equals(front.trader) || !reverses(front.swap, back.swap) {
+				continue
+			}
+
+			// the trader's first reverse swap closes the position, sandwich or not
+			if d.closes(front.swap, back.swap) {
+				var victims []*entry
+				for k := i + 1; k < j; k++ {
+					victim := entries[k]
+					if used[k] || victim.trader.Equals(front.trader) || victim.position == front.position || victim.position == back.position {
+						continue
+					}
+					if victim.swap.TokenIn.Mint.Equals(front.swap.TokenIn.Mint) {
+						victims = append(victims, victim)
+						used[k] = true
+					}
+				}
+				if len(victims) > 0 {
+					used[i], used[j] = true, true
+					sandwiches = append(sandwiches, newSandwich(front, back, victims))
+				}
+			}
+			break
+		}
+	}
+	return sandwiches
+}
+
+// market returns the pool key of a swap, independent of the swap direction
+func (d *Detector) market(swap *tx_parser.SwapInfo) marketKey {
+	key := marketKey{a: swap.TokenIn.Mint, b: swap.TokenOut.Mint}
+	if !d.config.IgnoreProtocol {
+		key.protocol = swap.Protocol
+	}
+	if bytes.Compare(key.a[:], key.b[:]) > 0 {
+		key.a, key.b = key.b, key.a
+	}
+	return key
+}
+
+// closes reports whether the backrun sells about what the frontrun bought
+func (d *Detector) closes(front, back *tx_parser.SwapInfo) bool {
+	bought, sold := float64(front.TokenOut.Amount), float64(back.TokenIn.Amount)
+	return sold >= bought*(1-d.config.Tolerance) && sold <= bought*(1+d.config.Tolerance)
+}
+
+// newSandwich builds the sandwich and tags its swaps
+func newSandwich(front, back *entry, victims []*entry) *Sandwich {
+	sandwich := &Sandwich{
+		Attacker:   front.trader,
+		Protocol:   front.swap.Protocol,
+		Slot:       front.tx.Slot,
+		Frontrun:   Leg{Signature: front.tx.Signature, Swap: front.swap},
+		Backrun:    Leg{Signature: back.tx.Signature, Swap: back.swap},
+		ProfitMint: front.swap.TokenIn.Mint,
+		Profit:     int64(back.swap.TokenOut.Amount) - int64(front.swap.TokenIn.Amount),
+	}
+
+	tag := func(swap *tx_parser.SwapInfo, role tx_parser.MEVRole) *tx_parser.MEVInfo {
+		swap.MEV = &tx_parser.MEVInfo{
+			Role:     role,
+			Attacker: sandwich.Attacker,
+			Frontrun: sandwich.Frontrun.Signature,
+			Backrun:  sandwich.Backrun.Signature,
+		}
+		return swap.MEV
+	}
+	tag(front.swap, tx_parser.MEVRoleFrontrun)
+	tag(back.swap, tx_parser.MEVRoleBackrun)
+
+	// the victim is assumed to have been owed the frontrun's rate, which the attacker got
+	// before moving the price. The pre-attack rate was better, so the loss is a lower bound.
+	rate := float64(front.swap.TokenOut.Amount) / float64(front.swap.TokenIn.Amount)
+	for _, victim := range victims {
+		info := tag(victim.swap, tx_parser.MEVRoleVictim)
+		expected := float64(victim.swap.TokenIn.Amount) * rate
+		if received := float64(victim.swap.TokenOut.Amount); expected > received {
+			info.VictimLoss = uint64(expected - received)
+			info.VictimSlippage = (expected - received) / expected
+		}
+		sandwich.Victims = append(sandwich.Victims, Leg{Signature: victim.tx.Signature, Swap: victim.swap})
+	}
+	return sandwich
+}
+
+// reverses reports whether b trades in the opposite direction of a
+func reverses(a, b *tx_parser.SwapInfo) bool {
+	return a.TokenIn.Mint.Equals(b.TokenOut.Mint) && a.TokenOut.Mint.Equals(b.TokenIn.Mint)
+}
+
+// trader returns the wallet that made the swap
+func trader(tx *tx_parser.ParsedTransaction, swap *tx_parser.SwapInfo) solana.PublicKey {
+	if len(swap.Signers) > 0 {
+		return swap.Signers[0]
+	}
+	return tx.FeePayer
+}
@@ -0,0 +1,104 @@
+package mev
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	sol      = tx_parser.NATIVE_SOL_PROGRAM_ID
+	token    = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+	other    = solana.MustPublicKeyFromBase58("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263")
+	attacker = solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	victim   = solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+)
+
+func swapTx(signature byte, signer, mintIn solana.PublicKey, amountIn uint64, mintOut solana.PublicKey, amountOut uint64) *tx_parser.ParsedTransaction {
+	return &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{signature},
+		Slot:      500,
+		FeePayer:  signer,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{signer},
+			TokenIn:  tx_parser.TokenInfo{Mint: mintIn, Amount: amountIn},
+			TokenOut: tx_parser.TokenInfo{Mint: mintOut, Amount: amountOut},
+		}},
+	}
+}
+
+func TestDetectSandwich(t *testing.T) {
+	txs := []*tx_parser.ParsedTransaction{
+		swapTx(1, attacker, sol, 10_000, token, 1_000_000),
+		swapTx(2, victim, sol, 1_000, token, 90_000),
+		swapTx(3, victim, other, 5, token, 5), // another market
+		swapTx(4, attacker, token, 1_000_000, sol, 10_500),
+	}
+
+	sandwiches := New(Config{}).Detect(txs)
+	if len(sandwiches) != 1 {
+		t.Fatalf("expected one sandwich, got %d", len(sandwiches))
+	}
+	s := sandwiches[0]
+	if !s.Attacker.Equals(attacker) || s.Slot != 500 || s.Frontrun.Signature != txs[0].Signature || s.Backrun.Signature != txs[3].Signature {
+		t.Errorf("unexpected sandwich %+v", s)
+	}
+	if len(s.Victims) != 1 || s.Victims[0].Signature != txs[1].Signature {
+		t.Fatalf("expected the SOL buy as the only victim, got %+v", s.Victims)
+	}
+	if !s.ProfitMint.Equals(sol) || s.Profit != 500 {
+		t.Errorf("expected a profit of 500 lamports, got %d %s", s.Profit, s.ProfitMint)
+	}
+
+	// at the frontrun's rate the victim was owed 100_000 tokens
+	info := txs[1].Swaps[0].MEV
+	if info == nil || info.Role != tx_parser.MEVRoleVictim || info.VictimLoss != 10_000 || info.VictimSlippage != 0.1 {
+		t.Errorf("unexpected victim info %+v", info)
+	}
+	if txs[0].Swaps[0].MEV.Role != tx_parser.MEVRoleFrontrun || txs[3].Swaps[0].MEV.Role != tx_parser.MEVRoleBackrun {
+		t.Error("expected the attacker's swaps to be tagged")
+	}
+	if txs[2].Swaps[0].MEV != nil {
+		t.Error("expected the swap in another market to be untagged")
+	}
+}
+
+func TestDetectIgnoresRoundTrips(t *testing.T) {
+	txs := []*tx_parser.ParsedTransaction{
+		// a round trip with nothing in between
+		swapTx(1, attacker, sol, 10_000, token, 1_000_000),
+		swapTx(2, attacker, token, 1_000_000, sol, 9_900),
+		// a partial sell does not close the position
+		swapTx(3, attacker, sol, 10_000, token, 1_000_000),
+		swapTx(4, victim, sol, 1_000, token, 90_000),
+		swapTx(5, attacker, token, 300_000, sol, 3_100),
+	}
+	if sandwiches := New(Config{}).Detect(txs); len(sandwiches) != 0 {
+		t.Errorf("expected no sandwiches, got %+v", sandwiches)
+	}
+}
+
+func TestDetectBlock(t *testing.T) {
+	txs := []*tx_parser.ParsedTransaction{
+		swapTx(1, attacker, sol, 10_000, token, 1_000_000),
+		swapTx(2, victim, sol, 1_000, token, 90_000),
+		swapTx(3, attacker, token, 1_000_000, sol, 10_500),
+	}
+	txs[1].Swaps[0].Protocol = tx_parser.SwapTypeJupiter
+
+	block := &tx_parser.BlockResult{Transactions: make(map[solana.Signature]*tx_parser.ParsedTransaction)}
+	for _, tx := range txs {
+		block.Transactions[tx.Signature] = tx
+		block.Order = append(block.Order, tx.Signature)
+	}
+
+	if sandwiches := New(Config{}).DetectBlock(block); len(sandwiches) != 0 {
+		t.Errorf("expected an aggregator swap to be a different market, got %+v", sandwiches)
+	}
+	if sandwiches := New(Config{IgnoreProtocol: true}).DetectBlock(block); len(sandwiches) != 1 {
+		t.Errorf("expected a sandwich when matching by pair only, got %+v", sandwiches)
+	}
+}
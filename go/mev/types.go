@@ -0,0 +1,37 @@
+package mev
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Config controls sandwich detection
+type Config struct {
+	// Tolerance is how far the backrun's input may differ from the frontrun's output, as a
+	// share of the frontrun's output. Defaults to 0.1.
+	Tolerance float64
+
+	// IgnoreProtocol matches swaps by token pair only, so victims routed through an
+	// aggregator are matched against attackers trading the pool directly
+	IgnoreProtocol bool
+}
+
+// Leg is a swap of a sandwich and the transaction it was parsed from
+type Leg struct {
+	Signature solana.Signature    `json:"signature"`
+	Swap      *tx_parser.SwapInfo `json:"swap"`
+}
+
+// Sandwich is an attacker buying before and selling after one or more victim swaps in
+// the same market
+type Sandwich struct {
+	Attacker   solana.PublicKey   `json:"attacker"`
+	Protocol   tx_parser.SwapType `json:"protocol"`
+	Slot       uint64             `json:"slot"`
+	Frontrun   Leg                `json:"frontrun"`
+	Victims    []Leg              `json:"victims"`
+	Backrun    Leg                `json:"backrun"`
+	ProfitMint solana.PublicKey   `json:"profit_mint"`   // the frontrun's input token
+	Profit     int64              `json:"profit,string"` // backrun output minus frontrun input, before fees and tips
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/ilkamo/jupiter-go/jupiter"
 
+	"github.com/soralabs/solana-toolkit/go/landing_stats"
 	toolkit "github.com/soralabs/toolkit/go"
 )
 
@@ -20,6 +21,8 @@ type OnchainActionsTool struct {
 	rpcClient *rpc.Client
 
 	jupClient *jupiter.ClientWithResponses
+
+	landingStats *landing_stats.Recorder
 }
 
 func NewOnchainActionsTool(rpcClient *rpc.Client) *OnchainActionsTool {
@@ -34,6 +37,11 @@ func NewOnchainActionsTool(rpcClient *rpc.Client) *OnchainActionsTool {
 	}
 }
 
+// SetLandingRecorder records the landing outcome of every transaction the tool sends
+func (t *OnchainActionsTool) SetLandingRecorder(recorder *landing_stats.Recorder) {
+	t.landingStats = recorder
+}
+
 func (t *OnchainActionsTool) GetName() string {
 	return "onchain_actions"
 }
@@ -8,6 +8,8 @@ import (
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/ilkamo/jupiter-go/jupiter"
+
+	"github.com/soralabs/solana-toolkit/go/landing_stats"
 )
 
 // Swap creates and signs a swap transaction with priority fees
@@ -70,9 +72,18 @@ func (t *OnchainActionsTool) Swap(ctx context.Context, quoteRequest jupiter.GetQ
 
 // SendSwapTransaction sends a signed swap transaction to the Solana network
 func (t *OnchainActionsTool) SendSwapTransaction(ctx context.Context, signedTx *solana.Transaction) (solana.Signature, error) {
+	sentAt := time.Now()
+	sig, reason, err := t.sendAndConfirm(ctx, signedTx)
+	t.recordLanding("jupiter", sentAt, reason, err)
+	return sig, err
+}
+
+// sendAndConfirm submits the transaction and waits for confirmation, returning
+// the landing failure reason alongside any error
+func (t *OnchainActionsTool) sendAndConfirm(ctx context.Context, signedTx *solana.Transaction) (solana.Signature, string, error) {
 	sig, err := t.rpcClient.SendTransaction(ctx, signedTx)
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("failed to send transaction: %w", err)
+		return solana.Signature{}, landing_stats.FailureSendError, fmt.Errorf("failed to send transaction: %w", err)
 	}
 
 	// Wait for confirmation with retries
@@ -81,20 +92,39 @@ func (t *OnchainActionsTool) SendSwapTransaction(ctx context.Context, signedTx *
 	for time.Now().Before(deadline) {
 		status, err := t.rpcClient.GetSignatureStatuses(ctx, true, sig)
 		if err != nil {
-			return sig, fmt.Errorf("failed to get transaction status: %w", err)
+			return sig, landing_stats.FailureRPCError, fmt.Errorf("failed to get transaction status: %w", err)
 		}
 
 		if status.Value[0] != nil {
 			if status.Value[0].Err != nil {
-				return sig, fmt.Errorf("transaction failed: %v", status.Value[0].Err)
+				return sig, landing_stats.FailureExecution, fmt.Errorf("transaction failed: %v", status.Value[0].Err)
 			}
 			if status.Value[0].Confirmations != nil && *status.Value[0].Confirmations > 0 {
-				return sig, nil
+				return sig, "", nil
 			}
 		}
 
 		time.Sleep(500 * time.Millisecond)
 	}
 
-	return sig, fmt.Errorf("transaction confirmation timeout")
+	return sig, landing_stats.FailureTimeout, fmt.Errorf("transaction confirmation timeout")
+}
+
+// recordLanding reports the outcome of a sent transaction to the landing recorder, if set
+func (t *OnchainActionsTool) recordLanding(venue string, sentAt time.Time, reason string, err error) {
+	if t.landingStats == nil {
+		return
+	}
+
+	outcome := landing_stats.Outcome{
+		Venue:         venue,
+		Route:         landing_stats.RoutePublicRPC,
+		Landed:        err == nil,
+		FailureReason: reason,
+		Timestamp:     sentAt,
+	}
+	if outcome.Landed {
+		outcome.Latency = time.Since(sentAt)
+	}
+	t.landingStats.Record(outcome)
 }
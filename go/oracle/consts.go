@@ -0,0 +1,15 @@
+package oracle
+
+import "github.com/gagliardetto/solana-go"
+
+var (
+	// PYTH_RECEIVER_PROGRAM_ID owns price updates posted by pull integrations
+	PYTH_RECEIVER_PROGRAM_ID = solana.MustPublicKeyFromBase58("rec5EKMGg6MxZYaMdyBfgwp4d5rB9T1VQH5pJv5LtFJ")
+	// PYTH_PUSH_ORACLE_PROGRAM_ID owns the continuously updated sponsored feeds
+	PYTH_PUSH_ORACLE_PROGRAM_ID = solana.MustPublicKeyFromBase58("pythWSnswVUd12oZpeFP8e9CVaEqJg25g1Vtc2biRsT")
+
+	SWITCHBOARD_ON_DEMAND_PROGRAM_ID = solana.MustPublicKeyFromBase58("SBondMDrcV3K4kxZR1HNVT7osZxAHVHgYXL5Ze1oMUv")
+)
+
+// SWITCHBOARD_PRECISION is the number of decimals of Switchboard on-demand values
+const SWITCHBOARD_PRECISION = 18
@@ -0,0 +1,126 @@
+package oracle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	priceUpdateDiscriminator = accountDiscriminator("PriceUpdateV2")
+	pullFeedDiscriminator    = accountDiscriminator("PullFeedAccountData")
+)
+
+// Switchboard PullFeedAccountData offsets, including the 8 byte discriminator
+const (
+	pullFeedHashOffset          = 8 + 2112
+	pullFeedLastUpdateOffset    = 8 + 2208
+	pullFeedResultValueOffset   = 8 + 2256
+	pullFeedResultStdDevOffset  = 8 + 2272
+	pullFeedResultNumSamples    = 8 + 2352
+	pullFeedResultSlotOffset    = 8 + 2360
+	pullFeedMinLength           = 8 + 2400
+	pythPriceMessageLength      = 84
+	pythPriceUpdateHeaderLength = 8 + 32 // discriminator and write authority
+)
+
+// accountDiscriminator returns the Anchor discriminator of an account type
+func accountDiscriminator(name string) [8]byte {
+	sum := sha256.Sum256([]byte("account:" + name))
+	var discriminator [8]byte
+	copy(discriminator[:], sum[:8])
+	return discriminator
+}
+
+// DecodePythPriceUpdate decodes a Pyth PriceUpdateV2 account, as posted by the receiver
+// program for pull integrations and kept up to date by the push oracle for sponsored feeds
+func DecodePythPriceUpdate(account solana.PublicKey, data []byte) (*Price, error) {
+	if len(data) < pythPriceUpdateHeaderLength+1 || [8]byte(data[:8]) != priceUpdateDiscriminator {
+		return nil, fmt.Errorf("account %s is not a Pyth price update", account)
+	}
+
+	offset := pythPriceUpdateHeaderLength
+	verified := data[offset] == 1
+	switch data[offset] {
+	case 0: // Partial { num_signatures: u8 }
+		offset += 2
+	case 1: // Full
+		offset++
+	default:
+		return nil, fmt.Errorf("unknown Pyth verification level %d", data[offset])
+	}
+	if len(data) < offset+pythPriceMessageLength+8 {
+		return nil, fmt.Errorf("Pyth price update %s is truncated", account)
+	}
+
+	msg := data[offset:]
+	exponent := int32(binary.LittleEndian.Uint32(msg[48:]))
+	scale := math.Pow10(int(exponent))
+
+	price := &Price{
+		Source:      SourcePyth,
+		Account:     account,
+		Price:       float64(int64(binary.LittleEndian.Uint64(msg[32:]))) * scale,
+		Confidence:  float64(binary.LittleEndian.Uint64(msg[40:])) * scale,
+		PublishTime: time.Unix(int64(binary.LittleEndian.Uint64(msg[52:])), 0),
+		EMAPrice:    float64(int64(binary.LittleEndian.Uint64(msg[68:]))) * scale,
+		Slot:        binary.LittleEndian.Uint64(msg[pythPriceMessageLength:]),
+		Verified:    verified,
+	}
+	copy(price.FeedID[:], msg[:32])
+	return price, nil
+}
+
+// DecodeSwitchboardPullFeed decodes the current result of a Switchboard on-demand pull feed
+func DecodeSwitchboardPullFeed(account solana.PublicKey, data []byte) (*Price, error) {
+	if len(data) < pullFeedMinLength || [8]byte(data[:8]) != pullFeedDiscriminator {
+		return nil, fmt.Errorf("account %s is not a Switchboard pull feed", account)
+	}
+	if data[pullFeedResultNumSamples] == 0 {
+		return nil, fmt.Errorf("Switchboard feed %s has no result yet", account)
+	}
+
+	price := &Price{
+		Source:      SourceSwitchboard,
+		Account:     account,
+		Price:       decimalI128(data[pullFeedResultValueOffset:]),
+		Confidence:  decimalI128(data[pullFeedResultStdDevOffset:]),
+		PublishTime: time.Unix(int64(binary.LittleEndian.Uint64(data[pullFeedLastUpdateOffset:])), 0),
+		Slot:        binary.LittleEndian.Uint64(data[pullFeedResultSlotOffset:]),
+		Verified:    true,
+	}
+	copy(price.FeedID[:], data[pullFeedHashOffset:])
+	return price, nil
+}
+
+// decimalI128 converts a little endian i128 with SWITCHBOARD_PRECISION decimals
+func decimalI128(data []byte) float64 {
+	be := make([]byte, 16)
+	for i := range be {
+		be[i] = data[15-i]
+	}
+	value := new(big.Int).SetBytes(be)
+	if be[0]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(value), big.NewFloat(math.Pow10(SWITCHBOARD_PRECISION))).Float64()
+	return f
+}
+
+// PythPushFeedAddress returns the account of a sponsored Pyth feed, shard 0 being the
+// default shard
+func PythPushFeedAddress(shard uint16, feedID [32]byte) (solana.PublicKey, error) {
+	seed := make([]byte, 2)
+	binary.LittleEndian.PutUint16(seed, shard)
+	address, _, err := solana.FindProgramAddress([][]byte{seed, feedID[:]}, PYTH_PUSH_ORACLE_PROGRAM_ID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive push feed address: %w", err)
+	}
+	return address, nil
+}
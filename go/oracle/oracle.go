@@ -0,0 +1,106 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var (
+	// ErrStale is returned for prices older than Config.MaxAge
+	ErrStale = errors.New("oracle price is stale")
+	// ErrUncertain is returned for prices with a confidence wider than Config.MaxConfidence
+	ErrUncertain = errors.New("oracle price confidence is too wide")
+	// ErrUnverified is returned for partially verified Pyth updates when Config.RequireVerified is set
+	ErrUnverified = errors.New("oracle price is not fully verified")
+)
+
+// Reader fetches and validates Pyth and Switchboard price accounts
+type Reader struct {
+	rpcClient *rpc.Client
+	config    Config
+	now       func() time.Time
+}
+
+// New creates an oracle reader
+func New(rpcClient *rpc.Client, config Config) *Reader {
+	return &Reader{
+		rpcClient: rpcClient,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Price fetches and validates a single price account
+func (r *Reader) Price(ctx context.Context, account solana.PublicKey) (*Price, error) {
+	prices, err := r.Prices(ctx, []solana.PublicKey{account})
+	if err != nil {
+		return nil, err
+	}
+	return prices[0], nil
+}
+
+// Prices fetches price accounts with getMultipleAccounts. It fails if any account is
+// missing, cannot be decoded or does not pass validation.
+func (r *Reader) Prices(ctx context.Context, accounts []solana.PublicKey) ([]*Price, error) {
+	prices := make([]*Price, 0, len(accounts))
+	for start := 0; start < len(accounts); start += 100 {
+		end := min(start+100, len(accounts))
+
+		result, err := r.rpcClient.GetMultipleAccountsWithOpts(ctx, accounts[start:end], &rpc.GetMultipleAccountsOpts{
+			Commitment: rpc.CommitmentConfirmed,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price accounts: %w", err)
+		}
+
+		for i, info := range result.Value {
+			account := accounts[start+i]
+			if info == nil {
+				return nil, fmt.Errorf("price account %s not found", account)
+			}
+			price, err := Decode(account, info.Owner, info.Data.GetBinary())
+			if err != nil {
+				return nil, err
+			}
+			if err := r.Validate(price); err != nil {
+				return nil, err
+			}
+			prices = append(prices, price)
+		}
+	}
+	return prices, nil
+}
+
+// Validate checks a price against the staleness, confidence and verification limits
+func (r *Reader) Validate(price *Price) error {
+	if r.config.MaxAge > 0 {
+		if age := r.now().Sub(price.PublishTime); age > r.config.MaxAge {
+			return fmt.Errorf("%w: %s published %s ago", ErrStale, price.Account, age.Round(time.Second))
+		}
+	}
+	if r.config.MaxConfidence > 0 && price.Confidence > r.config.MaxConfidence*math.Abs(price.Price) {
+		return fmt.Errorf("%w: %s confidence %g for price %g", ErrUncertain, price.Account, price.Confidence, price.Price)
+	}
+	if r.config.RequireVerified && !price.Verified {
+		return fmt.Errorf("%w: %s", ErrUnverified, price.Account)
+	}
+	return nil
+}
+
+// Decode decodes a price account by its owner program
+func Decode(account, owner solana.PublicKey, data []byte) (*Price, error) {
+	switch {
+	case owner.Equals(PYTH_RECEIVER_PROGRAM_ID), owner.Equals(PYTH_PUSH_ORACLE_PROGRAM_ID):
+		return DecodePythPriceUpdate(account, data)
+	case owner.Equals(SWITCHBOARD_ON_DEMAND_PROGRAM_ID):
+		return DecodeSwitchboardPullFeed(account, data)
+	}
+	return nil, fmt.Errorf("account %s is owned by %s, not a supported oracle program", account, owner)
+}
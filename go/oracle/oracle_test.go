@@ -0,0 +1,193 @@
+package oracle
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var publishTime = time.Unix(1_700_000_000, 0)
+
+// pythPriceUpdate encodes a PriceUpdateV2 account for SOL at 150.12345678 +- 0.05
+func pythPriceUpdate(full bool) []byte {
+	data := append([]byte{}, priceUpdateDiscriminator[:]...)
+	data = append(data, make([]byte, 32)...) // write authority
+	if full {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0, 5)
+	}
+
+	id := feedID()
+	data = append(data, id[:]...)
+	data = binary.LittleEndian.AppendUint64(data, 15_012_345_678)
+	data = binary.LittleEndian.AppendUint64(data, 5_000_000)
+	data = binary.LittleEndian.AppendUint32(data, uint32(0xfffffff8)) // exponent -8
+	data = binary.LittleEndian.AppendUint64(data, uint64(publishTime.Unix()))
+	data = binary.LittleEndian.AppendUint64(data, uint64(publishTime.Unix()-1))
+	data = binary.LittleEndian.AppendUint64(data, 15_000_000_000)
+	data = binary.LittleEndian.AppendUint64(data, 4_000_000)
+	data = binary.LittleEndian.AppendUint64(data, 300) // posted slot
+	return data
+}
+
+func feedID() [32]byte {
+	var id [32]byte
+	for i := range id {
+		id[i] = byte(i)
+	}
+	return id
+}
+
+func putI128(data []byte, value *big.Int) {
+	v := new(big.Int).Set(value)
+	if v.Sign() < 0 {
+		v.Add(v, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	be := v.FillBytes(make([]byte, 16))
+	for i := range be {
+		data[i] = be[15-i]
+	}
+}
+
+// switchboardPullFeed encodes a PullFeedAccountData account with the given result
+func switchboardPullFeed(value float64, samples byte) []byte {
+	data := make([]byte, pullFeedMinLength+320)
+	copy(data, pullFeedDiscriminator[:])
+	id := feedID()
+	copy(data[pullFeedHashOffset:], id[:])
+	binary.LittleEndian.PutUint64(data[pullFeedLastUpdateOffset:], uint64(publishTime.Unix()))
+
+	scaled, _ := new(big.Float).Mul(big.NewFloat(value), big.NewFloat(1e18)).Int(nil)
+	putI128(data[pullFeedResultValueOffset:], scaled)
+	putI128(data[pullFeedResultStdDevOffset:], big.NewInt(1e16))
+	data[pullFeedResultNumSamples] = samples
+	binary.LittleEndian.PutUint64(data[pullFeedResultSlotOffset:], 400)
+	return data
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestDecodePyth(t *testing.T) {
+	account := solana.NewWallet().PublicKey()
+	for _, full := range []bool{true, false} {
+		price, err := Decode(account, PYTH_RECEIVER_PROGRAM_ID, pythPriceUpdate(full))
+		if err != nil {
+			t.Fatalf("failed to decode: %v", err)
+		}
+		if price.Source != SourcePyth || price.FeedID != feedID() || price.Verified != full {
+			t.Errorf("unexpected price header %+v", price)
+		}
+		if !near(price.Price, 150.12345678) || !near(price.Confidence, 0.05) || !near(price.EMAPrice, 150) {
+			t.Errorf("unexpected price values %+v", price)
+		}
+		if !price.PublishTime.Equal(publishTime) || price.Slot != 300 {
+			t.Errorf("unexpected publish time or slot %+v", price)
+		}
+	}
+
+	if _, err := DecodePythPriceUpdate(account, pythPriceUpdate(true)[:60]); err == nil {
+		t.Error("expected a truncated update to fail")
+	}
+}
+
+func TestDecodeSwitchboard(t *testing.T) {
+	account := solana.NewWallet().PublicKey()
+	price, err := Decode(account, SWITCHBOARD_ON_DEMAND_PROGRAM_ID, switchboardPullFeed(-1.5, 3))
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if price.Source != SourceSwitchboard || !near(price.Price, -1.5) || !near(price.Confidence, 0.01) {
+		t.Errorf("unexpected price %+v", price)
+	}
+	if price.FeedID != feedID() || price.Slot != 400 || !price.PublishTime.Equal(publishTime) {
+		t.Errorf("unexpected feed metadata %+v", price)
+	}
+
+	if _, err := Decode(account, SWITCHBOARD_ON_DEMAND_PROGRAM_ID, switchboardPullFeed(1, 0)); err == nil {
+		t.Error("expected a feed without samples to fail")
+	}
+	if _, err := Decode(account, solana.SystemProgramID, switchboardPullFeed(1, 1)); err == nil {
+		t.Error("expected an unsupported owner to fail")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	r := New(nil, Config{MaxAge: time.Minute, MaxConfidence: 0.01, RequireVerified: true})
+	r.now = func() time.Time { return publishTime.Add(30 * time.Second) }
+
+	price := &Price{Price: 100, Confidence: 0.5, PublishTime: publishTime, Verified: true}
+	if err := r.Validate(price); err != nil {
+		t.Errorf("expected a valid price, got %v", err)
+	}
+
+	stale := *price
+	stale.PublishTime = publishTime.Add(-time.Hour)
+	if err := r.Validate(&stale); !errors.Is(err, ErrStale) {
+		t.Errorf("expected ErrStale, got %v", err)
+	}
+
+	uncertain := *price
+	uncertain.Confidence = 2
+	if err := r.Validate(&uncertain); !errors.Is(err, ErrUncertain) {
+		t.Errorf("expected ErrUncertain, got %v", err)
+	}
+
+	partial := *price
+	partial.Verified = false
+	if err := r.Validate(&partial); !errors.Is(err, ErrUnverified) {
+		t.Errorf("expected ErrUnverified, got %v", err)
+	}
+}
+
+func TestReaderPrices(t *testing.T) {
+	pyth, switchboard := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account := func(owner solana.PublicKey, data []byte) string {
+			return fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+				owner, base64.StdEncoding.EncodeToString(data))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":500},"value":[%s,%s]}}`,
+			account(PYTH_RECEIVER_PROGRAM_ID, pythPriceUpdate(true)),
+			account(SWITCHBOARD_ON_DEMAND_PROGRAM_ID, switchboardPullFeed(2, 1)))
+	}))
+	defer server.Close()
+
+	r := New(rpc.New(server.URL), Config{})
+	prices, err := r.Prices(context.Background(), []solana.PublicKey{pyth, switchboard})
+	if err != nil {
+		t.Fatalf("failed to read prices: %v", err)
+	}
+	if len(prices) != 2 || !prices[0].Account.Equals(pyth) || prices[1].Source != SourceSwitchboard || !near(prices[1].Price, 2) {
+		t.Errorf("unexpected prices %+v", prices)
+	}
+
+	r.config.MaxAge = time.Minute
+	if _, err := r.Price(context.Background(), pyth); !errors.Is(err, ErrStale) {
+		t.Errorf("expected the old fixture to be stale, got %v", err)
+	}
+}
+
+func TestPythPushFeedAddress(t *testing.T) {
+	a, err := PythPushFeedAddress(0, feedID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := PythPushFeedAddress(1, feedID())
+	if a.Equals(b) {
+		t.Error("expected shards to have distinct addresses")
+	}
+}
@@ -0,0 +1,41 @@
+package oracle
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Source is the oracle network a price was read from
+type Source string
+
+const (
+	SourcePyth        Source = "pyth"
+	SourceSwitchboard Source = "switchboard"
+)
+
+// Price is a decoded oracle price
+type Price struct {
+	Source      Source
+	Account     solana.PublicKey
+	FeedID      [32]byte // Pyth feed ID or Switchboard feed hash
+	Price       float64
+	Confidence  float64 // Pyth confidence interval or Switchboard standard deviation
+	EMAPrice    float64 // Pyth only
+	PublishTime time.Time
+	Slot        uint64
+	Verified    bool // Pyth update fully verified by Wormhole guardians, always true for Switchboard
+}
+
+// Config controls the oracle reader
+type Config struct {
+	// MaxAge rejects prices published longer ago with ErrStale, 0 disables the check
+	MaxAge time.Duration
+
+	// MaxConfidence rejects prices whose confidence exceeds this fraction of the price
+	// with ErrUncertain, e.g. 0.02 for 2%. 0 disables the check.
+	MaxConfidence float64
+
+	// RequireVerified rejects partially verified Pyth updates with ErrUnverified
+	RequireVerified bool
+}
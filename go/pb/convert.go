@@ -0,0 +1,1091 @@
+// Package pb holds the protobuf schema of the parsed transaction types and converters
+// between the Go and protobuf forms
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative solana_toolkit.proto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// TransactionToProto converts a parsed transaction
+func TransactionToProto(tx *tx_parser.ParsedTransaction) *ParsedTransaction {
+	if tx == nil {
+		return nil
+	}
+
+	out := &ParsedTransaction{
+		Signature:     tx.Signature[:],
+		Slot:          tx.Slot,
+		FeePayer:      keyBytes(tx.FeePayer),
+		Fee:           tx.Fee,
+		JitoTip:       tx.JitoTip,
+		Bundle:        BundleInfoToProto(tx.Bundle),
+		ComputeBudget: ComputeBudgetToProto(tx.ComputeBudget),
+		Failure:       TransactionFailureToProto(tx.Failure),
+	}
+	if tx.BlockTime != nil {
+		blockTime := int64(*tx.BlockTime)
+		out.BlockTime = &blockTime
+	}
+	for _, swap := range tx.Swaps {
+		out.Swaps = append(out.Swaps, SwapInfoToProto(swap))
+	}
+	for _, transfer := range tx.Transfers {
+		out.Transfers = append(out.Transfers, TransferInfoToProto(transfer))
+	}
+	for _, event := range tx.StakeEvents {
+		out.StakeEvents = append(out.StakeEvents, StakeEventToProto(event))
+	}
+	for _, event := range tx.SupplyEvents {
+		out.SupplyEvents = append(out.SupplyEvents, TokenSupplyEventToProto(event))
+	}
+	for _, event := range tx.AdminEvents {
+		out.AdminEvents = append(out.AdminEvents, TokenAdminEventToProto(event))
+	}
+	for _, memo := range tx.Memos {
+		out.Memos = append(out.Memos, MemoInfoToProto(memo))
+	}
+	for _, event := range tx.PoolCreations {
+		out.PoolCreations = append(out.PoolCreations, PoolCreatedEventToProto(event))
+	}
+	for _, fill := range tx.PerpFills {
+		out.PerpFills = append(out.PerpFills, PerpFillInfoToProto(fill))
+	}
+	for _, event := range tx.CompressedNfts {
+		out.CompressedNfts = append(out.CompressedNfts, CompressedNftEventToProto(event))
+	}
+	for _, event := range tx.NftMints {
+		out.NftMints = append(out.NftMints, NftMintEventToProto(event))
+	}
+	for _, event := range tx.DomainEvents {
+		out.DomainEvents = append(out.DomainEvents, DomainEventToProto(event))
+	}
+	for _, bridge := range tx.Bridges {
+		out.Bridges = append(out.Bridges, BridgeEventToProto(bridge))
+	}
+	for _, node := range tx.CallTree {
+		out.CallTree = append(out.CallTree, InstructionNodeToProto(node))
+	}
+	for _, parseErr := range tx.Errors {
+		out.Errors = append(out.Errors, ParseErrorToProto(parseErr))
+	}
+	return out
+}
+
+// TransactionFromProto converts a parsed transaction back, failing on malformed keys or signatures
+func TransactionFromProto(tx *ParsedTransaction) (*tx_parser.ParsedTransaction, error) {
+	if tx == nil {
+		return nil, nil
+	}
+
+	signature, err := signatureFromBytes(tx.GetSignature())
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	out := &tx_parser.ParsedTransaction{
+		Signature:     signature,
+		Slot:          tx.GetSlot(),
+		Fee:           tx.GetFee(),
+		JitoTip:       tx.GetJitoTip(),
+		ComputeBudget: ComputeBudgetFromProto(tx.GetComputeBudget()),
+	}
+	if tx.BlockTime != nil {
+		blockTime := solana.UnixTimeSeconds(tx.GetBlockTime())
+		out.BlockTime = &blockTime
+	}
+	if err := decodeKeys(keyField{"fee payer", tx.GetFeePayer(), &out.FeePayer}); err != nil {
+		return nil, err
+	}
+	if out.Bundle, err = BundleInfoFromProto(tx.GetBundle()); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if out.Failure, err = TransactionFailureFromProto(tx.GetFailure()); err != nil {
+		return nil, fmt.Errorf("invalid failure: %w", err)
+	}
+
+	for i, swap := range tx.GetSwaps() {
+		converted, err := SwapInfoFromProto(swap)
+		if err != nil {
+			return nil, fmt.Errorf("invalid swap %d: %w", i, err)
+		}
+		out.Swaps = append(out.Swaps, converted)
+	}
+	for i, transfer := range tx.GetTransfers() {
+		converted, err := TransferInfoFromProto(transfer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer %d: %w", i, err)
+		}
+		out.Transfers = append(out.Transfers, converted)
+	}
+	for i, event := range tx.GetStakeEvents() {
+		converted, err := StakeEventFromProto(event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stake event %d: %w", i, err)
+		}
+		out.StakeEvents = append(out.StakeEvents, converted)
+	}
+	for i, event := range tx.GetSupplyEvents() {
+		converted, err := TokenSupplyEventFromProto(event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid supply event %d: %w", i, err)
+		}
+		out.SupplyEvents = append(out.SupplyEvents, converted)
+	}
+	for i, event := range tx.GetAdminEvents() {
+		converted, err := TokenAdminEventFromProto(event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin event %d: %w", i, err)
+		}
+		out.AdminEvents = append(out.AdminEvents, converted)
+	}
+	for i, memo := range tx.GetMemos() {
+		converted, err := MemoInfoFromProto(memo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memo %d: %w", i, err)
+		}
+		out.Memos = append(out.Memos, converted)
+	}
+	for i, event := range tx.GetPoolCreations() {
+		converted, err := PoolCreatedEventFromProto(event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool creation %d: %w", i, err)
+		}
+		out.PoolCreations = append(out.PoolCreations, converted)
+	}
+	for i, fill := range tx.GetPerpFills() {
+		converted, err := PerpFillInfoFromProto(fill)
+		if err != nil {
+			return nil, fmt.Errorf("invalid perp fill %d: %w", i, err)
+		}
+		out.PerpFills = append(out.PerpFills, converted)
+	}
+	for i, event := range tx.GetCompressedNfts() {
+		converted, err := CompressedNftEventFromProto(event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid compressed nft event %d: %w", i, err)
+		}
+		out.CompressedNfts = append(out.CompressedNfts, converted)
+	}
+	for i, event := range tx.GetNftMints() {
+		converted, err := NftMintEventFromProto(event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nft mint %d: %w", i, err)
+		}
+		out.NftMints = append(out.NftMints, converted)
+	}
+	for i, event := range tx.GetDomainEvents() {
+		converted, err := DomainEventFromProto(event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid domain event %d: %w", i, err)
+		}
+		out.DomainEvents = append(out.DomainEvents, converted)
+	}
+	for i, bridge := range tx.GetBridges() {
+		converted, err := BridgeEventFromProto(bridge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bridge event %d: %w", i, err)
+		}
+		out.Bridges = append(out.Bridges, converted)
+	}
+	for i, node := range tx.GetCallTree() {
+		converted, err := InstructionNodeFromProto(node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid call tree instruction %d: %w", i, err)
+		}
+		out.CallTree = append(out.CallTree, converted)
+	}
+	for i, parseErr := range tx.GetErrors() {
+		converted, err := ParseErrorFromProto(parseErr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parse error %d: %w", i, err)
+		}
+		out.Errors = append(out.Errors, converted)
+	}
+	return out, nil
+}
+
+// TokenInfoToProto converts a token amount
+func TokenInfoToProto(info tx_parser.TokenInfo) *TokenInfo {
+	out := &TokenInfo{
+		Mint:     keyBytes(info.Mint),
+		Amount:   info.Amount,
+		Decimals: uint32(info.Decimals),
+	}
+	if info.Extensions != nil {
+		out.Extensions = &TokenExtensions{
+			Extensions:             info.Extensions.Extensions,
+			TransferHookProgram:    keyBytes(info.Extensions.TransferHookProgram),
+			TransferFeeBasisPoints: uint32(info.Extensions.TransferFeeBasisPoints),
+			MaxTransferFee:         info.Extensions.MaxTransferFee,
+			InterestRate:           int32(info.Extensions.InterestRate),
+			ScaledUiMultiplier:     info.Extensions.ScaledUIMultiplier,
+			ConfidentialTransfers:  info.Extensions.ConfidentialTransfers,
+		}
+	}
+	return out
+}
+
+// TokenInfoFromProto converts a token amount back
+func TokenInfoFromProto(info *TokenInfo) (tx_parser.TokenInfo, error) {
+	mint, err := keyFromBytes(info.GetMint())
+	if err != nil {
+		return tx_parser.TokenInfo{}, fmt.Errorf("invalid mint: %w", err)
+	}
+	out := tx_parser.TokenInfo{
+		Mint:     mint,
+		Amount:   info.GetAmount(),
+		Decimals: uint8(info.GetDecimals()),
+	}
+	if extensions := info.GetExtensions(); extensions != nil {
+		out.Extensions = &tx_parser.TokenExtensions{
+			Extensions:             extensions.GetExtensions(),
+			TransferFeeBasisPoints: uint16(extensions.GetTransferFeeBasisPoints()),
+			MaxTransferFee:         extensions.GetMaxTransferFee(),
+			InterestRate:           int16(extensions.GetInterestRate()),
+			ScaledUIMultiplier:     extensions.GetScaledUiMultiplier(),
+			ConfidentialTransfers:  extensions.GetConfidentialTransfers(),
+		}
+		if err := decodeKeys(keyField{"transfer hook program", extensions.GetTransferHookProgram(), &out.Extensions.TransferHookProgram}); err != nil {
+			return tx_parser.TokenInfo{}, err
+		}
+	}
+	return out, nil
+}
+
+// SwapInfoToProto converts a swap
+func SwapInfoToProto(swap *tx_parser.SwapInfo) *SwapInfo {
+	out := &SwapInfo{
+		Protocol:         string(swap.Protocol),
+		Signers:          keysBytes(swap.Signers),
+		TokenIn:          TokenInfoToProto(swap.TokenIn),
+		TokenOut:         TokenInfoToProto(swap.TokenOut),
+		InstructionIndex: int32(swap.InstructionIndex),
+		ComputeUnits:     swap.ComputeUnits,
+		Price:            PriceInfoToProto(swap.Price),
+		Mev:              MEVInfoToProto(swap.MEV),
+		AmountUsd:        swap.AmountUSD,
+	}
+	for _, signature := range swap.Signatures {
+		out.Signatures = append(out.Signatures, signature[:])
+	}
+	if !swap.Timestamp.IsZero() {
+		out.Timestamp = timestamppb.New(swap.Timestamp)
+	}
+	return out
+}
+
+// SwapInfoFromProto converts a swap back
+func SwapInfoFromProto(swap *SwapInfo) (*tx_parser.SwapInfo, error) {
+	signers, err := keysFromBytes(swap.GetSigners())
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer: %w", err)
+	}
+	tokenIn, err := TokenInfoFromProto(swap.GetTokenIn())
+	if err != nil {
+		return nil, fmt.Errorf("invalid token in: %w", err)
+	}
+	tokenOut, err := TokenInfoFromProto(swap.GetTokenOut())
+	if err != nil {
+		return nil, fmt.Errorf("invalid token out: %w", err)
+	}
+
+	out := &tx_parser.SwapInfo{
+		Protocol:         tx_parser.SwapType(swap.GetProtocol()),
+		Signers:          signers,
+		TokenIn:          tokenIn,
+		TokenOut:         tokenOut,
+		InstructionIndex: int(swap.GetInstructionIndex()),
+		ComputeUnits:     swap.GetComputeUnits(),
+		AmountUSD:        swap.GetAmountUsd(),
+	}
+	for _, raw := range swap.GetSignatures() {
+		signature, err := signatureFromBytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature: %w", err)
+		}
+		out.Signatures = append(out.Signatures, signature)
+	}
+	if swap.Timestamp != nil {
+		out.Timestamp = swap.GetTimestamp().AsTime()
+	}
+	if out.Price, err = PriceInfoFromProto(swap.GetPrice()); err != nil {
+		return nil, fmt.Errorf("invalid price: %w", err)
+	}
+	if out.MEV, err = MEVInfoFromProto(swap.GetMev()); err != nil {
+		return nil, fmt.Errorf("invalid mev: %w", err)
+	}
+	return out, nil
+}
+
+// PriceInfoToProto converts an execution price
+func PriceInfoToProto(price *tx_parser.PriceInfo) *PriceInfo {
+	if price == nil {
+		return nil
+	}
+	return &PriceInfo{
+		BaseMint:  keyBytes(price.BaseMint),
+		QuoteMint: keyBytes(price.QuoteMint),
+		Price:     price.Price,
+		PriceSol:  price.PriceSOL,
+		PriceUsd:  price.PriceUSD,
+		VolumeUsd: price.VolumeUSD,
+	}
+}
+
+// PriceInfoFromProto converts an execution price back
+func PriceInfoFromProto(price *PriceInfo) (*tx_parser.PriceInfo, error) {
+	if price == nil {
+		return nil, nil
+	}
+	out := &tx_parser.PriceInfo{
+		Price:     price.GetPrice(),
+		PriceSOL:  price.GetPriceSol(),
+		PriceUSD:  price.GetPriceUsd(),
+		VolumeUSD: price.GetVolumeUsd(),
+	}
+	err := decodeKeys(
+		keyField{"base mint", price.GetBaseMint(), &out.BaseMint},
+		keyField{"quote mint", price.GetQuoteMint(), &out.QuoteMint},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MEVInfoToProto converts a sandwich marker
+func MEVInfoToProto(mev *tx_parser.MEVInfo) *MEVInfo {
+	if mev == nil {
+		return nil
+	}
+	return &MEVInfo{
+		Role:           string(mev.Role),
+		Attacker:       keyBytes(mev.Attacker),
+		Frontrun:       mev.Frontrun[:],
+		Backrun:        mev.Backrun[:],
+		VictimLoss:     mev.VictimLoss,
+		VictimSlippage: mev.VictimSlippage,
+	}
+}
+
+// MEVInfoFromProto converts a sandwich marker back
+func MEVInfoFromProto(mev *MEVInfo) (*tx_parser.MEVInfo, error) {
+	if mev == nil {
+		return nil, nil
+	}
+	attacker, err := keyFromBytes(mev.GetAttacker())
+	if err != nil {
+		return nil, fmt.Errorf("invalid attacker: %w", err)
+	}
+	frontrun, err := signatureFromBytes(mev.GetFrontrun())
+	if err != nil {
+		return nil, fmt.Errorf("invalid frontrun: %w", err)
+	}
+	backrun, err := signatureFromBytes(mev.GetBackrun())
+	if err != nil {
+		return nil, fmt.Errorf("invalid backrun: %w", err)
+	}
+	return &tx_parser.MEVInfo{
+		Role:           tx_parser.MEVRole(mev.GetRole()),
+		Attacker:       attacker,
+		Frontrun:       frontrun,
+		Backrun:        backrun,
+		VictimLoss:     mev.GetVictimLoss(),
+		VictimSlippage: mev.GetVictimSlippage(),
+	}, nil
+}
+
+// TransferInfoToProto converts a transfer
+func TransferInfoToProto(transfer *tx_parser.TransferInfo) *TransferInfo {
+	return &TransferInfo{
+		Type:             string(transfer.Type),
+		Program:          keyBytes(transfer.Program),
+		InstructionIndex: int32(transfer.InstructionIndex),
+		InnerIndex:       int32(transfer.InnerIndex),
+		Mint:             keyBytes(transfer.Mint),
+		Source:           keyBytes(transfer.Source),
+		Destination:      keyBytes(transfer.Destination),
+		SourceOwner:      keyBytes(transfer.SourceOwner),
+		DestinationOwner: keyBytes(transfer.DestinationOwner),
+		Authority:        keyBytes(transfer.Authority),
+		Amount:           transfer.Amount,
+		Decimals:         uint32(transfer.Decimals),
+		AmountUsd:        transfer.AmountUSD,
+	}
+}
+
+// TransferInfoFromProto converts a transfer back
+func TransferInfoFromProto(transfer *TransferInfo) (*tx_parser.TransferInfo, error) {
+	out := &tx_parser.TransferInfo{
+		Type:             tx_parser.TransferType(transfer.GetType()),
+		InstructionIndex: int(transfer.GetInstructionIndex()),
+		InnerIndex:       int(transfer.GetInnerIndex()),
+		Amount:           transfer.GetAmount(),
+		Decimals:         uint8(transfer.GetDecimals()),
+		AmountUSD:        transfer.GetAmountUsd(),
+	}
+	err := decodeKeys(
+		keyField{"program", transfer.GetProgram(), &out.Program},
+		keyField{"mint", transfer.GetMint(), &out.Mint},
+		keyField{"source", transfer.GetSource(), &out.Source},
+		keyField{"destination", transfer.GetDestination(), &out.Destination},
+		keyField{"source owner", transfer.GetSourceOwner(), &out.SourceOwner},
+		keyField{"destination owner", transfer.GetDestinationOwner(), &out.DestinationOwner},
+		keyField{"authority", transfer.GetAuthority(), &out.Authority},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StakeEventToProto converts a stake event
+func StakeEventToProto(event *tx_parser.StakeEvent) *StakeEvent {
+	return &StakeEvent{
+		Type:             string(event.Type),
+		Pool:             string(event.Pool),
+		Program:          keyBytes(event.Program),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		StakeAccount:     keyBytes(event.StakeAccount),
+		TargetAccount:    keyBytes(event.TargetAccount),
+		VoteAccount:      keyBytes(event.VoteAccount),
+		Authority:        keyBytes(event.Authority),
+		Recipient:        keyBytes(event.Recipient),
+		Lamports:         event.Lamports,
+		PoolMint:         keyBytes(event.PoolMint),
+		PoolTokenAmount:  event.PoolTokenAmount,
+	}
+}
+
+// StakeEventFromProto converts a stake event back
+func StakeEventFromProto(event *StakeEvent) (*tx_parser.StakeEvent, error) {
+	out := &tx_parser.StakeEvent{
+		Type:             tx_parser.StakeEventType(event.GetType()),
+		Pool:             tx_parser.StakePool(event.GetPool()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		Lamports:         event.GetLamports(),
+		PoolTokenAmount:  event.GetPoolTokenAmount(),
+	}
+	err := decodeKeys(
+		keyField{"program", event.GetProgram(), &out.Program},
+		keyField{"stake account", event.GetStakeAccount(), &out.StakeAccount},
+		keyField{"target account", event.GetTargetAccount(), &out.TargetAccount},
+		keyField{"vote account", event.GetVoteAccount(), &out.VoteAccount},
+		keyField{"authority", event.GetAuthority(), &out.Authority},
+		keyField{"recipient", event.GetRecipient(), &out.Recipient},
+		keyField{"pool mint", event.GetPoolMint(), &out.PoolMint},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TokenSupplyEventToProto converts a mint or burn
+func TokenSupplyEventToProto(event *tx_parser.TokenSupplyEvent) *TokenSupplyEvent {
+	return &TokenSupplyEvent{
+		Type:             string(event.Type),
+		Program:          keyBytes(event.Program),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		Mint:             keyBytes(event.Mint),
+		Account:          keyBytes(event.Account),
+		Owner:            keyBytes(event.Owner),
+		Authority:        keyBytes(event.Authority),
+		Amount:           event.Amount,
+		Decimals:         uint32(event.Decimals),
+	}
+}
+
+// TokenSupplyEventFromProto converts a mint or burn back
+func TokenSupplyEventFromProto(event *TokenSupplyEvent) (*tx_parser.TokenSupplyEvent, error) {
+	out := &tx_parser.TokenSupplyEvent{
+		Type:             tx_parser.TokenSupplyEventType(event.GetType()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		Amount:           event.GetAmount(),
+		Decimals:         uint8(event.GetDecimals()),
+	}
+	err := decodeKeys(
+		keyField{"program", event.GetProgram(), &out.Program},
+		keyField{"mint", event.GetMint(), &out.Mint},
+		keyField{"account", event.GetAccount(), &out.Account},
+		keyField{"owner", event.GetOwner(), &out.Owner},
+		keyField{"authority", event.GetAuthority(), &out.Authority},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TokenAdminEventToProto converts an authority change or freeze/thaw
+func TokenAdminEventToProto(event *tx_parser.TokenAdminEvent) *TokenAdminEvent {
+	out := &TokenAdminEvent{
+		Type:             string(event.Type),
+		Program:          keyBytes(event.Program),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		Mint:             keyBytes(event.Mint),
+		Account:          keyBytes(event.Account),
+		Authority:        keyBytes(event.Authority),
+		AuthorityType:    event.AuthorityType,
+	}
+	if event.NewAuthority != nil {
+		out.NewAuthority = event.NewAuthority[:]
+	}
+	return out
+}
+
+// TokenAdminEventFromProto converts an authority change or freeze/thaw back
+func TokenAdminEventFromProto(event *TokenAdminEvent) (*tx_parser.TokenAdminEvent, error) {
+	out := &tx_parser.TokenAdminEvent{
+		Type:             tx_parser.TokenAdminEventType(event.GetType()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		AuthorityType:    event.GetAuthorityType(),
+	}
+	err := decodeKeys(
+		keyField{"program", event.GetProgram(), &out.Program},
+		keyField{"mint", event.GetMint(), &out.Mint},
+		keyField{"account", event.GetAccount(), &out.Account},
+		keyField{"authority", event.GetAuthority(), &out.Authority},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if event.NewAuthority != nil {
+		if len(event.NewAuthority) != solana.PublicKeyLength {
+			return nil, fmt.Errorf("invalid new authority: expected %d bytes, got %d", solana.PublicKeyLength, len(event.NewAuthority))
+		}
+		newAuthority := solana.PublicKeyFromBytes(event.NewAuthority)
+		out.NewAuthority = &newAuthority
+	}
+	return out, nil
+}
+
+// ComputeBudgetToProto converts a compute budget
+func ComputeBudgetToProto(budget *tx_parser.ComputeBudget) *ComputeBudget {
+	if budget == nil {
+		return nil
+	}
+	return &ComputeBudget{
+		UnitLimit:           budget.UnitLimit,
+		UnitPrice:           budget.UnitPrice,
+		HeapFrameBytes:      budget.HeapFrameBytes,
+		LoadedAccountsLimit: budget.LoadedAccountsLimit,
+		EffectiveUnitLimit:  budget.EffectiveUnitLimit,
+		PriorityFee:         budget.PriorityFee,
+	}
+}
+
+// ComputeBudgetFromProto converts a compute budget back
+func ComputeBudgetFromProto(budget *ComputeBudget) *tx_parser.ComputeBudget {
+	if budget == nil {
+		return nil
+	}
+	return &tx_parser.ComputeBudget{
+		UnitLimit:           budget.GetUnitLimit(),
+		UnitPrice:           budget.GetUnitPrice(),
+		HeapFrameBytes:      budget.GetHeapFrameBytes(),
+		LoadedAccountsLimit: budget.GetLoadedAccountsLimit(),
+		EffectiveUnitLimit:  budget.GetEffectiveUnitLimit(),
+		PriorityFee:         budget.GetPriorityFee(),
+	}
+}
+
+// BundleInfoToProto converts a bundle position
+func BundleInfoToProto(info *tx_parser.BundleInfo) *BundleInfo {
+	if info == nil {
+		return nil
+	}
+	return &BundleInfo{
+		Id:       info.ID[:],
+		Position: uint32(info.Position),
+		Size:     uint32(info.Size),
+		Tip:      info.Tip,
+	}
+}
+
+// BundleInfoFromProto converts a bundle position back
+func BundleInfoFromProto(info *BundleInfo) (*tx_parser.BundleInfo, error) {
+	if info == nil {
+		return nil, nil
+	}
+	id, err := signatureFromBytes(info.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid id: %w", err)
+	}
+	return &tx_parser.BundleInfo{
+		ID:       id,
+		Position: int(info.GetPosition()),
+		Size:     int(info.GetSize()),
+		Tip:      info.GetTip(),
+	}, nil
+}
+
+// MemoInfoToProto converts a memo
+func MemoInfoToProto(memo *tx_parser.MemoInfo) *MemoInfo {
+	return &MemoInfo{
+		Program:          keyBytes(memo.Program),
+		InstructionIndex: int32(memo.InstructionIndex),
+		InnerIndex:       int32(memo.InnerIndex),
+		Text:             memo.Text,
+		Signers:          keysBytes(memo.Signers),
+	}
+}
+
+// MemoInfoFromProto converts a memo back
+func MemoInfoFromProto(memo *MemoInfo) (*tx_parser.MemoInfo, error) {
+	program, err := keyFromBytes(memo.GetProgram())
+	if err != nil {
+		return nil, fmt.Errorf("invalid program: %w", err)
+	}
+	signers, err := keysFromBytes(memo.GetSigners())
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer: %w", err)
+	}
+	return &tx_parser.MemoInfo{
+		Program:          program,
+		InstructionIndex: int(memo.GetInstructionIndex()),
+		InnerIndex:       int(memo.GetInnerIndex()),
+		Text:             memo.GetText(),
+		Signers:          signers,
+	}, nil
+}
+
+// PoolCreatedEventToProto converts a pool creation
+func PoolCreatedEventToProto(event *tx_parser.PoolCreatedEvent) *PoolCreatedEvent {
+	return &PoolCreatedEvent{
+		Protocol:         string(event.Protocol),
+		Program:          keyBytes(event.Program),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		Pool:             keyBytes(event.Pool),
+		Creator:          keyBytes(event.Creator),
+		MintA:            keyBytes(event.MintA),
+		MintB:            keyBytes(event.MintB),
+		DecimalsA:        uint32(event.DecimalsA),
+		DecimalsB:        uint32(event.DecimalsB),
+		AmountA:          event.AmountA,
+		AmountB:          event.AmountB,
+		Curve:            keyBytes(event.Curve),
+	}
+}
+
+// PoolCreatedEventFromProto converts a pool creation back
+func PoolCreatedEventFromProto(event *PoolCreatedEvent) (*tx_parser.PoolCreatedEvent, error) {
+	out := &tx_parser.PoolCreatedEvent{
+		Protocol:         tx_parser.SwapType(event.GetProtocol()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		DecimalsA:        uint8(event.GetDecimalsA()),
+		DecimalsB:        uint8(event.GetDecimalsB()),
+		AmountA:          event.GetAmountA(),
+		AmountB:          event.GetAmountB(),
+	}
+	err := decodeKeys(
+		keyField{"program", event.GetProgram(), &out.Program},
+		keyField{"pool", event.GetPool(), &out.Pool},
+		keyField{"creator", event.GetCreator(), &out.Creator},
+		keyField{"mint a", event.GetMintA(), &out.MintA},
+		keyField{"mint b", event.GetMintB(), &out.MintB},
+		keyField{"curve", event.GetCurve(), &out.Curve},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PerpFillInfoToProto converts a perpetuals fill
+func PerpFillInfoToProto(fill *tx_parser.PerpFillInfo) *PerpFillInfo {
+	return &PerpFillInfo{
+		Type:             string(fill.Type),
+		Venue:            string(fill.Venue),
+		Program:          keyBytes(fill.Program),
+		InstructionIndex: int32(fill.InstructionIndex),
+		InnerIndex:       int32(fill.InnerIndex),
+		Trader:           keyBytes(fill.Trader),
+		Account:          keyBytes(fill.Account),
+		Market:           keyBytes(fill.Market),
+		Asset:            fill.Asset,
+		Side:             string(fill.Side),
+		Size:             fill.Size,
+		Price:            fill.Price,
+		Fee:              fill.Fee,
+		Liquidator:       keyBytes(fill.Liquidator),
+	}
+}
+
+// PerpFillInfoFromProto converts a perpetuals fill back
+func PerpFillInfoFromProto(fill *PerpFillInfo) (*tx_parser.PerpFillInfo, error) {
+	out := &tx_parser.PerpFillInfo{
+		Type:             tx_parser.PerpFillType(fill.GetType()),
+		Venue:            tx_parser.PerpVenue(fill.GetVenue()),
+		InstructionIndex: int(fill.GetInstructionIndex()),
+		InnerIndex:       int(fill.GetInnerIndex()),
+		Asset:            fill.GetAsset(),
+		Side:             tx_parser.PerpSide(fill.GetSide()),
+		Size:             fill.GetSize(),
+		Price:            fill.GetPrice(),
+		Fee:              fill.GetFee(),
+	}
+	err := decodeKeys(
+		keyField{"program", fill.GetProgram(), &out.Program},
+		keyField{"trader", fill.GetTrader(), &out.Trader},
+		keyField{"account", fill.GetAccount(), &out.Account},
+		keyField{"market", fill.GetMarket(), &out.Market},
+		keyField{"liquidator", fill.GetLiquidator(), &out.Liquidator},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CompressedNftEventToProto converts a compressed NFT event
+func CompressedNftEventToProto(event *tx_parser.CompressedNftEvent) *CompressedNftEvent {
+	return &CompressedNftEvent{
+		Type:             string(event.Type),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		Tree:             keyBytes(event.Tree),
+		LeafIndex:        event.LeafIndex,
+		AssetId:          keyBytes(event.AssetID),
+		Owner:            keyBytes(event.Owner),
+		Delegate:         keyBytes(event.Delegate),
+		NewOwner:         keyBytes(event.NewOwner),
+		Collection:       keyBytes(event.Collection),
+		Name:             event.Name,
+		Symbol:           event.Symbol,
+		Uri:              event.URI,
+	}
+}
+
+// CompressedNftEventFromProto converts a compressed NFT event back
+func CompressedNftEventFromProto(event *CompressedNftEvent) (*tx_parser.CompressedNftEvent, error) {
+	out := &tx_parser.CompressedNftEvent{
+		Type:             tx_parser.CompressedNftEventType(event.GetType()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		LeafIndex:        event.GetLeafIndex(),
+		Name:             event.GetName(),
+		Symbol:           event.GetSymbol(),
+		URI:              event.GetUri(),
+	}
+	err := decodeKeys(
+		keyField{"tree", event.GetTree(), &out.Tree},
+		keyField{"asset id", event.GetAssetId(), &out.AssetID},
+		keyField{"owner", event.GetOwner(), &out.Owner},
+		keyField{"delegate", event.GetDelegate(), &out.Delegate},
+		keyField{"new owner", event.GetNewOwner(), &out.NewOwner},
+		keyField{"collection", event.GetCollection(), &out.Collection},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NftMintEventToProto converts an NFT mint
+func NftMintEventToProto(event *tx_parser.NftMintEvent) *NftMintEvent {
+	return &NftMintEvent{
+		Source:           string(event.Source),
+		Program:          keyBytes(event.Program),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		Mint:             keyBytes(event.Mint),
+		Minter:           keyBytes(event.Minter),
+		Payer:            keyBytes(event.Payer),
+		Collection:       keyBytes(event.Collection),
+		CandyMachine:     keyBytes(event.CandyMachine),
+		Price:            event.Price,
+		Name:             event.Name,
+		Uri:              event.URI,
+	}
+}
+
+// NftMintEventFromProto converts an NFT mint back
+func NftMintEventFromProto(event *NftMintEvent) (*tx_parser.NftMintEvent, error) {
+	out := &tx_parser.NftMintEvent{
+		Source:           tx_parser.NftMintSource(event.GetSource()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		Price:            event.GetPrice(),
+		Name:             event.GetName(),
+		URI:              event.GetUri(),
+	}
+	err := decodeKeys(
+		keyField{"program", event.GetProgram(), &out.Program},
+		keyField{"mint", event.GetMint(), &out.Mint},
+		keyField{"minter", event.GetMinter(), &out.Minter},
+		keyField{"payer", event.GetPayer(), &out.Payer},
+		keyField{"collection", event.GetCollection(), &out.Collection},
+		keyField{"candy machine", event.GetCandyMachine(), &out.CandyMachine},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DomainEventToProto converts a name service domain event
+func DomainEventToProto(event *tx_parser.DomainEvent) *DomainEvent {
+	return &DomainEvent{
+		Type:             string(event.Type),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		Domain:           keyBytes(event.Domain),
+		Name:             event.Name,
+		Owner:            keyBytes(event.Owner),
+		NewOwner:         keyBytes(event.NewOwner),
+		Parent:           keyBytes(event.Parent),
+	}
+}
+
+// DomainEventFromProto converts a name service domain event back
+func DomainEventFromProto(event *DomainEvent) (*tx_parser.DomainEvent, error) {
+	out := &tx_parser.DomainEvent{
+		Type:             tx_parser.DomainEventType(event.GetType()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		Name:             event.GetName(),
+	}
+	err := decodeKeys(
+		keyField{"domain", event.GetDomain(), &out.Domain},
+		keyField{"owner", event.GetOwner(), &out.Owner},
+		keyField{"new owner", event.GetNewOwner(), &out.NewOwner},
+		keyField{"parent", event.GetParent(), &out.Parent},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BridgeEventToProto converts a cross-chain bridge transfer
+func BridgeEventToProto(event *tx_parser.BridgeEvent) *BridgeEvent {
+	return &BridgeEvent{
+		Protocol:         string(event.Protocol),
+		Direction:        string(event.Direction),
+		Program:          keyBytes(event.Program),
+		InstructionIndex: int32(event.InstructionIndex),
+		InnerIndex:       int32(event.InnerIndex),
+		SourceChain:      string(event.SourceChain),
+		DestinationChain: string(event.DestinationChain),
+		Wallet:           keyBytes(event.Wallet),
+		ForeignAddress:   event.ForeignAddress,
+		Mint:             keyBytes(event.Mint),
+		Amount:           event.Amount,
+		Decimals:         uint32(event.Decimals),
+	}
+}
+
+// BridgeEventFromProto converts a cross-chain bridge transfer back
+func BridgeEventFromProto(event *BridgeEvent) (*tx_parser.BridgeEvent, error) {
+	out := &tx_parser.BridgeEvent{
+		Protocol:         tx_parser.BridgeProtocol(event.GetProtocol()),
+		Direction:        tx_parser.BridgeDirection(event.GetDirection()),
+		InstructionIndex: int(event.GetInstructionIndex()),
+		InnerIndex:       int(event.GetInnerIndex()),
+		SourceChain:      tx_parser.BridgeChain(event.GetSourceChain()),
+		DestinationChain: tx_parser.BridgeChain(event.GetDestinationChain()),
+		ForeignAddress:   event.GetForeignAddress(),
+		Amount:           event.GetAmount(),
+		Decimals:         uint8(event.GetDecimals()),
+	}
+	err := decodeKeys(
+		keyField{"program", event.GetProgram(), &out.Program},
+		keyField{"wallet", event.GetWallet(), &out.Wallet},
+		keyField{"mint", event.GetMint(), &out.Mint},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InstructionNodeToProto converts an instruction of the call tree with its children
+func InstructionNodeToProto(node *tx_parser.InstructionNode) *InstructionNode {
+	out := &InstructionNode{
+		Program:          keyBytes(node.Program),
+		InstructionIndex: int32(node.InstructionIndex),
+		InnerIndex:       int32(node.InnerIndex),
+		StackHeight:      uint32(node.StackHeight),
+		ComputeUnits:     node.ComputeUnits,
+	}
+	for _, child := range node.Children {
+		out.Children = append(out.Children, InstructionNodeToProto(child))
+	}
+	return out
+}
+
+// InstructionNodeFromProto converts an instruction of the call tree back with its children
+func InstructionNodeFromProto(node *InstructionNode) (*tx_parser.InstructionNode, error) {
+	out := &tx_parser.InstructionNode{
+		InstructionIndex: int(node.GetInstructionIndex()),
+		InnerIndex:       int(node.GetInnerIndex()),
+		StackHeight:      int(node.GetStackHeight()),
+		ComputeUnits:     node.GetComputeUnits(),
+	}
+	if err := decodeKeys(keyField{"program", node.GetProgram(), &out.Program}); err != nil {
+		return nil, err
+	}
+	for i, child := range node.GetChildren() {
+		converted, err := InstructionNodeFromProto(child)
+		if err != nil {
+			return nil, fmt.Errorf("invalid child %d: %w", i, err)
+		}
+		out.Children = append(out.Children, converted)
+	}
+	return out, nil
+}
+
+// TransactionFailureToProto converts the failure of a transaction, nil when it succeeded
+func TransactionFailureToProto(failure *tx_parser.TransactionFailure) *TransactionFailure {
+	if failure == nil {
+		return nil
+	}
+	return &TransactionFailure{
+		Kind:             string(failure.Kind),
+		Error:            failure.Error,
+		InstructionError: failure.InstructionError,
+		InstructionIndex: int32(failure.InstructionIndex),
+		InnerIndex:       int32(failure.InnerIndex),
+		Program:          keyBytes(failure.Program),
+		CustomCode:       failure.CustomCode,
+		ErrorName:        failure.ErrorName,
+		ErrorMessage:     failure.ErrorMessage,
+	}
+}
+
+// TransactionFailureFromProto converts the failure of a transaction back
+func TransactionFailureFromProto(failure *TransactionFailure) (*tx_parser.TransactionFailure, error) {
+	if failure == nil {
+		return nil, nil
+	}
+	out := &tx_parser.TransactionFailure{
+		Kind:             tx_parser.FailureKind(failure.GetKind()),
+		Error:            failure.GetError(),
+		InstructionError: failure.GetInstructionError(),
+		InstructionIndex: int(failure.GetInstructionIndex()),
+		InnerIndex:       int(failure.GetInnerIndex()),
+		CustomCode:       failure.CustomCode,
+		ErrorName:        failure.GetErrorName(),
+		ErrorMessage:     failure.GetErrorMessage(),
+	}
+	if err := decodeKeys(keyField{"program", failure.GetProgram(), &out.Program}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ParseErrorToProto converts a parse error, keeping only the message of the underlying error
+func ParseErrorToProto(parseErr *tx_parser.ParseError) *ParseError {
+	out := &ParseError{
+		Protocol:         string(parseErr.Protocol),
+		Program:          keyBytes(parseErr.Program),
+		InstructionIndex: int32(parseErr.InstructionIndex),
+		InnerIndex:       int32(parseErr.InnerIndex),
+	}
+	if parseErr.Err != nil {
+		out.Error = parseErr.Err.Error()
+	}
+	return out
+}
+
+// ParseErrorFromProto converts a parse error back
+func ParseErrorFromProto(parseErr *ParseError) (*tx_parser.ParseError, error) {
+	program, err := keyFromBytes(parseErr.GetProgram())
+	if err != nil {
+		return nil, fmt.Errorf("invalid program: %w", err)
+	}
+	return &tx_parser.ParseError{
+		Protocol:         tx_parser.SwapType(parseErr.GetProtocol()),
+		Program:          program,
+		InstructionIndex: int(parseErr.GetInstructionIndex()),
+		InnerIndex:       int(parseErr.GetInnerIndex()),
+		Err:              errors.New(parseErr.GetError()),
+	}, nil
+}
+
+// keyBytes encodes a public key, the zero key as empty bytes
+func keyBytes(key solana.PublicKey) []byte {
+	if key.IsZero() {
+		return nil
+	}
+	return key.Bytes()
+}
+
+// keyFromBytes decodes a public key, empty bytes as the zero key
+func keyFromBytes(raw []byte) (solana.PublicKey, error) {
+	if len(raw) == 0 {
+		return solana.PublicKey{}, nil
+	}
+	if len(raw) != solana.PublicKeyLength {
+		return solana.PublicKey{}, fmt.Errorf("expected %d bytes, got %d", solana.PublicKeyLength, len(raw))
+	}
+	return solana.PublicKeyFromBytes(raw), nil
+}
+
+func keysBytes(keys []solana.PublicKey) [][]byte {
+	if len(keys) == 0 {
+		return nil
+	}
+	out := make([][]byte, len(keys))
+	for i, key := range keys {
+		out[i] = key.Bytes()
+	}
+	return out
+}
+
+func keysFromBytes(raw [][]byte) ([]solana.PublicKey, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	out := make([]solana.PublicKey, len(raw))
+	for i, b := range raw {
+		key, err := keyFromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = key
+	}
+	return out, nil
+}
+
+func signatureFromBytes(raw []byte) (solana.Signature, error) {
+	if len(raw) != solana.SignatureLength {
+		return solana.Signature{}, fmt.Errorf("expected %d bytes, got %d", solana.SignatureLength, len(raw))
+	}
+	return solana.SignatureFromBytes(raw), nil
+}
+
+// keyField is a public key field to decode
+type keyField struct {
+	name string
+	raw  []byte
+	dst  *solana.PublicKey
+}
+
+// decodeKeys decodes every field, naming the first malformed one in the error
+func decodeKeys(fields ...keyField) error {
+	for _, field := range fields {
+		key, err := keyFromBytes(field.raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", field.name, err)
+		}
+		*field.dst = key
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package pb
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func TestTransactionRoundTrip(t *testing.T) {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	newAuthority := solana.NewWallet().PublicKey()
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	customCode := uint32(6001)
+	extensions := &tx_parser.TokenExtensions{
+		Extensions:             []string{"TransferFeeConfig", "TransferHook"},
+		TransferHookProgram:    wallet,
+		TransferFeeBasisPoints: 250,
+		MaxTransferFee:         5_000,
+		InterestRate:           -15,
+	}
+
+	tx := &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{1, 2, 3},
+		Slot:      300,
+		BlockTime: &blockTime,
+		FeePayer:  wallet,
+		Fee:       5000,
+		JitoTip:   10,
+		Bundle:    &tx_parser.BundleInfo{ID: solana.Signature{4}, Position: 1, Size: 3, Tip: 10},
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol:         tx_parser.SwapTypeRaydium,
+			Signers:          []solana.PublicKey{wallet},
+			Signatures:       []solana.Signature{{1, 2, 3}},
+			Timestamp:        time.Unix(1_700_000_000, 0).UTC(),
+			TokenIn:          tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut:         tx_parser.TokenInfo{Mint: token, Amount: 18_446_744_073_709_551_615, Decimals: 6, Extensions: extensions},
+			InstructionIndex: 2,
+			ComputeUnits:     48_213,
+			AmountUSD:        150,
+			Price: &tx_parser.PriceInfo{BaseMint: token, QuoteMint: tx_parser.NATIVE_SOL_PROGRAM_ID, Price: 0.000055,
+				PriceSOL: 0.000055, PriceUSD: 0.00825, VolumeUSD: 150},
+			MEV: &tx_parser.MEVInfo{Role: tx_parser.MEVRoleVictim, Attacker: newAuthority, Frontrun: solana.Signature{5}, Backrun: solana.Signature{6},
+				VictimLoss: 1_250_000, VictimSlippage: 0.0125},
+		}},
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeSOL, Program: solana.SystemProgramID, Source: wallet, Destination: token, Amount: 10, InnerIndex: -1, AmountUSD: 0.0000015},
+		},
+		StakeEvents: []*tx_parser.StakeEvent{
+			{Type: tx_parser.StakeEventDeposit, Pool: tx_parser.StakePoolJito, PoolMint: token, Lamports: 7, PoolTokenAmount: 6},
+		},
+		SupplyEvents: []*tx_parser.TokenSupplyEvent{
+			{Type: tx_parser.TokenSupplyBurn, Mint: token, Owner: wallet, Amount: 3, Decimals: 6},
+		},
+		AdminEvents: []*tx_parser.TokenAdminEvent{
+			{Type: tx_parser.TokenAdminSetAuthority, Mint: token, AuthorityType: "MintTokens", NewAuthority: &newAuthority},
+			{Type: tx_parser.TokenAdminSetAuthority, Mint: token, AuthorityType: "FreezeAccount"},
+		},
+		ComputeBudget: &tx_parser.ComputeBudget{UnitLimit: 200_000, UnitPrice: 1000, EffectiveUnitLimit: 200_000, PriorityFee: 200},
+		Memos:         []*tx_parser.MemoInfo{{Text: "gm", Signers: []solana.PublicKey{wallet}, InnerIndex: -1}},
+		PoolCreations: []*tx_parser.PoolCreatedEvent{
+			{Protocol: tx_parser.SwapTypePumpFun, Pool: wallet, Creator: wallet, MintA: token, MintB: tx_parser.NATIVE_SOL_PROGRAM_ID, DecimalsA: 6, DecimalsB: 9, AmountA: 793_100_000_000_000},
+			{Protocol: tx_parser.SwapTypeRaydiumLaunchLab, Program: tx_parser.RAYDIUM_CPMM_PROGRAM_ID, Pool: token, Curve: wallet, MintA: token, MintB: tx_parser.NATIVE_SOL_PROGRAM_ID, AmountA: 200_000_000_000_000, AmountB: 85_000_000_000},
+		},
+		PerpFills: []*tx_parser.PerpFillInfo{
+			{Type: tx_parser.PerpFillOpen, Venue: tx_parser.PerpVenueJupiterPerps, Program: tx_parser.JUPITER_PERPS_PROGRAM_ID, Trader: wallet, Account: token,
+				Market: token, Asset: "SOL", Side: tx_parser.PerpSideLong, Size: 5_000_000_000, Price: 150_000_000, Fee: 3_500_000},
+			{Type: tx_parser.PerpFillLiquidation, Venue: tx_parser.PerpVenueZeta, Program: tx_parser.ZETA_PROGRAM_ID, InnerIndex: -1, Trader: wallet, Side: tx_parser.PerpSideShort, Liquidator: token},
+		},
+		CompressedNfts: []*tx_parser.CompressedNftEvent{
+			{Type: tx_parser.CompressedNftMint, Tree: token, LeafIndex: 7, AssetID: wallet, Owner: wallet, Delegate: wallet, Collection: token, Name: "Drop #8", URI: "https://example.com/8.json"},
+			{Type: tx_parser.CompressedNftTransfer, Tree: token, LeafIndex: 7, AssetID: wallet, Owner: wallet, NewOwner: token, InnerIndex: 2},
+		},
+		NftMints: []*tx_parser.NftMintEvent{
+			{Source: tx_parser.NftMintCandyMachineV3, Program: tx_parser.CANDY_MACHINE_V3_PROGRAM_ID, InnerIndex: 1, Mint: token, Minter: wallet, Payer: wallet,
+				Collection: token, CandyMachine: wallet, Price: 1_000_000_000},
+			{Source: tx_parser.NftMintMetaplexCore, Program: tx_parser.MPL_CORE_PROGRAM_ID, Mint: token, Minter: wallet, Payer: wallet, Name: "Core #1", URI: "https://example.com/1.json"},
+		},
+		DomainEvents: []*tx_parser.DomainEvent{
+			{Type: tx_parser.DomainRegister, InstructionIndex: 1, InnerIndex: 3, Domain: token, Name: "bonfida.sol", Owner: wallet, Parent: tx_parser.SOL_TLD_AUTHORITY},
+			{Type: tx_parser.DomainTransfer, Domain: token, Owner: wallet, NewOwner: token},
+		},
+		Bridges: []*tx_parser.BridgeEvent{
+			{Protocol: tx_parser.BridgeWormhole, Direction: tx_parser.BridgeDeposit, Program: tx_parser.WORMHOLE_TOKEN_BRIDGE_PROGRAM_ID, InnerIndex: -1,
+				SourceChain: tx_parser.BridgeChainSolana, DestinationChain: tx_parser.BridgeChainEthereum, Wallet: wallet,
+				ForeignAddress: "0xdeadbeef0102030405060708090a0b0c0d0e0f10", Mint: token, Amount: 1_000_000, Decimals: 6},
+			{Protocol: tx_parser.BridgeDeBridge, Direction: tx_parser.BridgeRedeem, Program: tx_parser.DEBRIDGE_DLN_DESTINATION_PROGRAM_ID,
+				SourceChain: "debridge:999", DestinationChain: tx_parser.BridgeChainSolana, Wallet: wallet, Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 5, Decimals: 9},
+		},
+		CallTree: []*tx_parser.InstructionNode{
+			{Program: tx_parser.RAYDIUM_V4_PROGRAM_ID, InnerIndex: -1, StackHeight: 1, ComputeUnits: 48_213, Children: []*tx_parser.InstructionNode{
+				{Program: solana.TokenProgramID, StackHeight: 2, ComputeUnits: 4_645},
+			}},
+		},
+		Failure: &tx_parser.TransactionFailure{Kind: tx_parser.FailureSlippage, Error: "InstructionError", InstructionError: "Custom",
+			InstructionIndex: 2, InnerIndex: -1, Program: tx_parser.JUPITER_PROGRAM_ID, CustomCode: &customCode, ErrorName: "SlippageToleranceExceeded"},
+		Errors: []*tx_parser.ParseError{
+			{Protocol: tx_parser.SwapTypeOrca, InstructionIndex: 1, InnerIndex: -1, Err: errors.New("invalid data")},
+		},
+	}
+
+	data, err := proto.Marshal(TransactionToProto(tx))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	decoded := &ParsedTransaction{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	got, err := TransactionFromProto(decoded)
+	if err != nil {
+		t.Fatalf("failed to convert back: %v", err)
+	}
+
+	if got.Errors[0].Err.Error() != "invalid data" {
+		t.Errorf("expected the parse error message to survive, got %v", got.Errors[0].Err)
+	}
+	got.Errors[0].Err = tx.Errors[0].Err
+	if !reflect.DeepEqual(got, tx) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, tx)
+	}
+}
+
+func TestEventEncoder(t *testing.T) {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	tx := &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{7},
+		Slot:      300,
+		BlockTime: &blockTime,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol:   tx_parser.SwapTypePumpFun,
+			Signers:    []solana.PublicKey{wallet},
+			Signatures: []solana.Signature{{7}},
+			TokenIn:    tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut:   tx_parser.TokenInfo{Mint: token, Amount: 35_000_000_000, Decimals: 6},
+			AmountUSD:  150,
+		}},
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeSOL, Program: solana.SystemProgramID, Source: wallet, Destination: token, Amount: 10, InnerIndex: -1},
+			{Type: tx_parser.TransferTypeToken, Program: solana.TokenProgramID, Mint: token, Source: token, Destination: wallet, Amount: 5, Decimals: 6},
+		},
+		PoolCreations: []*tx_parser.PoolCreatedEvent{{Protocol: tx_parser.SwapTypePumpFun, Pool: wallet, MintA: token, MintB: tx_parser.NATIVE_SOL_PROGRAM_ID}},
+	}
+
+	encoder := EventEncoder{}
+	if encoder.ContentType() != "application/x-protobuf" {
+		t.Errorf("unexpected content type %s", encoder.ContentType())
+	}
+	events := sink.Events(tx)
+	for _, event := range events {
+		data, err := encoder.Encode(event)
+		if err != nil {
+			t.Fatalf("failed to encode: %v", err)
+		}
+		decoded := &Event{}
+		if err := proto.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+		got, err := EventFromProto(decoded)
+		if err != nil {
+			t.Fatalf("failed to convert back: %v", err)
+		}
+		if !reflect.DeepEqual(got, event) {
+			t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, event)
+		}
+	}
+
+	decoded := EventToProto(events[0])
+	decoded.SchemaVersion = tx_parser.SCHEMA_VERSION + 1
+	if _, err := EventFromProto(decoded); err == nil {
+		t.Error("expected a newer schema version to be rejected")
+	}
+}
+
+func TestTransactionWithoutBlockTime(t *testing.T) {
+	tx := &tx_parser.ParsedTransaction{Signature: solana.Signature{9}}
+	got, err := TransactionFromProto(TransactionToProto(tx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.BlockTime != nil || got.ComputeBudget != nil {
+		t.Errorf("expected unset optional fields to stay unset, got %+v", got)
+	}
+}
+
+func TestFromProtoRejectsMalformedKeys(t *testing.T) {
+	msg := &ParsedTransaction{
+		Signature: make([]byte, solana.SignatureLength),
+		Transfers: []*TransferInfo{{Mint: []byte{1, 2, 3}}},
+	}
+	_, err := TransactionFromProto(msg)
+	if err == nil || !strings.Contains(err.Error(), "invalid transfer 0: invalid mint") {
+		t.Errorf("expected a malformed mint error, got %v", err)
+	}
+
+	msg = &ParsedTransaction{
+		Signature: make([]byte, solana.SignatureLength),
+		Swaps:     []*SwapInfo{{Price: &PriceInfo{QuoteMint: []byte{1}}}},
+	}
+	_, err = TransactionFromProto(msg)
+	if err == nil || !strings.Contains(err.Error(), "invalid price: invalid quote mint") {
+		t.Errorf("expected a malformed quote mint error, got %v", err)
+	}
+
+	msg = &ParsedTransaction{Signature: []byte{1}}
+	if _, err := TransactionFromProto(msg); err == nil {
+		t.Error("expected a malformed signature error")
+	}
+}
@@ -0,0 +1,129 @@
+package pb
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// EventEncoder encodes sink events as Event messages
+type EventEncoder struct{}
+
+var _ sink.Encoder = EventEncoder{}
+
+// Encode marshals the event as an Event message
+func (EventEncoder) Encode(event *sink.Event) ([]byte, error) {
+	data, err := proto.Marshal(EventToProto(event))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return data, nil
+}
+
+// ContentType returns application/x-protobuf
+func (EventEncoder) ContentType() string {
+	return "application/x-protobuf"
+}
+
+// EventToProto converts a sink event
+func EventToProto(event *sink.Event) *Event {
+	if event == nil {
+		return nil
+	}
+
+	out := &Event{
+		Kind:          string(event.Kind),
+		Signature:     event.Signature[:],
+		Slot:          event.Slot,
+		Index:         int32(event.Index),
+		SchemaVersion: tx_parser.SCHEMA_VERSION,
+	}
+	if event.BlockTime != nil {
+		blockTime := int64(*event.BlockTime)
+		out.BlockTime = &blockTime
+	}
+	switch {
+	case event.Swap != nil:
+		out.Payload = &Event_Swap{Swap: SwapInfoToProto(event.Swap)}
+	case event.Transfer != nil:
+		out.Payload = &Event_Transfer{Transfer: TransferInfoToProto(event.Transfer)}
+	case event.Stake != nil:
+		out.Payload = &Event_Stake{Stake: StakeEventToProto(event.Stake)}
+	case event.TokenSupply != nil:
+		out.Payload = &Event_TokenSupply{TokenSupply: TokenSupplyEventToProto(event.TokenSupply)}
+	case event.TokenAdmin != nil:
+		out.Payload = &Event_TokenAdmin{TokenAdmin: TokenAdminEventToProto(event.TokenAdmin)}
+	case event.PoolCreated != nil:
+		out.Payload = &Event_PoolCreated{PoolCreated: PoolCreatedEventToProto(event.PoolCreated)}
+	case event.PerpFill != nil:
+		out.Payload = &Event_PerpFill{PerpFill: PerpFillInfoToProto(event.PerpFill)}
+	case event.CompressedNft != nil:
+		out.Payload = &Event_CompressedNft{CompressedNft: CompressedNftEventToProto(event.CompressedNft)}
+	case event.NftMint != nil:
+		out.Payload = &Event_NftMint{NftMint: NftMintEventToProto(event.NftMint)}
+	case event.Domain != nil:
+		out.Payload = &Event_Domain{Domain: DomainEventToProto(event.Domain)}
+	case event.Bridge != nil:
+		out.Payload = &Event_Bridge{Bridge: BridgeEventToProto(event.Bridge)}
+	}
+	return out
+}
+
+// EventFromProto converts a sink event back, rejecting schema versions newer than the
+// parser's
+func EventFromProto(event *Event) (*sink.Event, error) {
+	if event == nil {
+		return nil, nil
+	}
+	if event.GetSchemaVersion() > tx_parser.SCHEMA_VERSION {
+		return nil, fmt.Errorf("unsupported schema version %d, expected at most %d", event.GetSchemaVersion(), tx_parser.SCHEMA_VERSION)
+	}
+
+	signature, err := signatureFromBytes(event.GetSignature())
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	out := &sink.Event{
+		Kind:      sink.Kind(event.GetKind()),
+		Signature: signature,
+		Slot:      event.GetSlot(),
+		Index:     int(event.GetIndex()),
+	}
+	if event.BlockTime != nil {
+		blockTime := solana.UnixTimeSeconds(event.GetBlockTime())
+		out.BlockTime = &blockTime
+	}
+
+	switch payload := event.GetPayload().(type) {
+	case *Event_Swap:
+		out.Swap, err = SwapInfoFromProto(payload.Swap)
+	case *Event_Transfer:
+		out.Transfer, err = TransferInfoFromProto(payload.Transfer)
+	case *Event_Stake:
+		out.Stake, err = StakeEventFromProto(payload.Stake)
+	case *Event_TokenSupply:
+		out.TokenSupply, err = TokenSupplyEventFromProto(payload.TokenSupply)
+	case *Event_TokenAdmin:
+		out.TokenAdmin, err = TokenAdminEventFromProto(payload.TokenAdmin)
+	case *Event_PoolCreated:
+		out.PoolCreated, err = PoolCreatedEventFromProto(payload.PoolCreated)
+	case *Event_PerpFill:
+		out.PerpFill, err = PerpFillInfoFromProto(payload.PerpFill)
+	case *Event_CompressedNft:
+		out.CompressedNft, err = CompressedNftEventFromProto(payload.CompressedNft)
+	case *Event_NftMint:
+		out.NftMint, err = NftMintEventFromProto(payload.NftMint)
+	case *Event_Domain:
+		out.Domain, err = DomainEventFromProto(payload.Domain)
+	case *Event_Bridge:
+		out.Bridge, err = BridgeEventFromProto(payload.Bridge)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", out.Kind, err)
+	}
+	return out, nil
+}
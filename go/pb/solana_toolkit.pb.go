@@ -0,0 +1,3397 @@
+// Parsed transaction types of the solana-toolkit parser. Public keys and signatures are
+// raw bytes (32 and 64 bytes). Type and protocol fields carry the same strings as the Go
+// types so new protocols do not require a schema change.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: solana_toolkit.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TokenInfo struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Mint     []byte                 `protobuf:"bytes,1,opt,name=mint,proto3" json:"mint,omitempty"`
+	Amount   uint64                 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Decimals uint32                 `protobuf:"varint,3,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	// set for Token-2022 mints with extensions
+	Extensions    *TokenExtensions `protobuf:"bytes,4,opt,name=extensions,proto3" json:"extensions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenInfo) Reset() {
+	*x = TokenInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenInfo) ProtoMessage() {}
+
+func (x *TokenInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenInfo.ProtoReflect.Descriptor instead.
+func (*TokenInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TokenInfo) GetMint() []byte {
+	if x != nil {
+		return x.Mint
+	}
+	return nil
+}
+
+func (x *TokenInfo) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TokenInfo) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *TokenInfo) GetExtensions() *TokenExtensions {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+type TokenExtensions struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Extensions             []string               `protobuf:"bytes,1,rep,name=extensions,proto3" json:"extensions,omitempty"`
+	TransferHookProgram    []byte                 `protobuf:"bytes,2,opt,name=transfer_hook_program,json=transferHookProgram,proto3" json:"transfer_hook_program,omitempty"`
+	TransferFeeBasisPoints uint32                 `protobuf:"varint,3,opt,name=transfer_fee_basis_points,json=transferFeeBasisPoints,proto3" json:"transfer_fee_basis_points,omitempty"`
+	MaxTransferFee         uint64                 `protobuf:"varint,4,opt,name=max_transfer_fee,json=maxTransferFee,proto3" json:"max_transfer_fee,omitempty"`
+	InterestRate           int32                  `protobuf:"varint,5,opt,name=interest_rate,json=interestRate,proto3" json:"interest_rate,omitempty"`
+	ScaledUiMultiplier     float64                `protobuf:"fixed64,6,opt,name=scaled_ui_multiplier,json=scaledUiMultiplier,proto3" json:"scaled_ui_multiplier,omitempty"`
+	ConfidentialTransfers  bool                   `protobuf:"varint,7,opt,name=confidential_transfers,json=confidentialTransfers,proto3" json:"confidential_transfers,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *TokenExtensions) Reset() {
+	*x = TokenExtensions{}
+	mi := &file_solana_toolkit_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenExtensions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenExtensions) ProtoMessage() {}
+
+func (x *TokenExtensions) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenExtensions.ProtoReflect.Descriptor instead.
+func (*TokenExtensions) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TokenExtensions) GetExtensions() []string {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+func (x *TokenExtensions) GetTransferHookProgram() []byte {
+	if x != nil {
+		return x.TransferHookProgram
+	}
+	return nil
+}
+
+func (x *TokenExtensions) GetTransferFeeBasisPoints() uint32 {
+	if x != nil {
+		return x.TransferFeeBasisPoints
+	}
+	return 0
+}
+
+func (x *TokenExtensions) GetMaxTransferFee() uint64 {
+	if x != nil {
+		return x.MaxTransferFee
+	}
+	return 0
+}
+
+func (x *TokenExtensions) GetInterestRate() int32 {
+	if x != nil {
+		return x.InterestRate
+	}
+	return 0
+}
+
+func (x *TokenExtensions) GetScaledUiMultiplier() float64 {
+	if x != nil {
+		return x.ScaledUiMultiplier
+	}
+	return 0
+}
+
+func (x *TokenExtensions) GetConfidentialTransfers() bool {
+	if x != nil {
+		return x.ConfidentialTransfers
+	}
+	return false
+}
+
+type SwapInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Protocol         string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Signers          [][]byte               `protobuf:"bytes,2,rep,name=signers,proto3" json:"signers,omitempty"`
+	Signatures       [][]byte               `protobuf:"bytes,3,rep,name=signatures,proto3" json:"signatures,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TokenIn          *TokenInfo             `protobuf:"bytes,5,opt,name=token_in,json=tokenIn,proto3" json:"token_in,omitempty"`
+	TokenOut         *TokenInfo             `protobuf:"bytes,6,opt,name=token_out,json=tokenOut,proto3" json:"token_out,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,7,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	ComputeUnits     uint64                 `protobuf:"varint,8,opt,name=compute_units,json=computeUnits,proto3" json:"compute_units,omitempty"`
+	// set by the pricing package
+	Price *PriceInfo `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	// set by the mev package
+	Mev *MEVInfo `protobuf:"bytes,10,opt,name=mev,proto3" json:"mev,omitempty"`
+	// set by the pricing package, 0 if neither side has a known price
+	AmountUsd     float64 `protobuf:"fixed64,11,opt,name=amount_usd,json=amountUsd,proto3" json:"amount_usd,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SwapInfo) Reset() {
+	*x = SwapInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwapInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwapInfo) ProtoMessage() {}
+
+func (x *SwapInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwapInfo.ProtoReflect.Descriptor instead.
+func (*SwapInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SwapInfo) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *SwapInfo) GetSigners() [][]byte {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+func (x *SwapInfo) GetSignatures() [][]byte {
+	if x != nil {
+		return x.Signatures
+	}
+	return nil
+}
+
+func (x *SwapInfo) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SwapInfo) GetTokenIn() *TokenInfo {
+	if x != nil {
+		return x.TokenIn
+	}
+	return nil
+}
+
+func (x *SwapInfo) GetTokenOut() *TokenInfo {
+	if x != nil {
+		return x.TokenOut
+	}
+	return nil
+}
+
+func (x *SwapInfo) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *SwapInfo) GetComputeUnits() uint64 {
+	if x != nil {
+		return x.ComputeUnits
+	}
+	return 0
+}
+
+func (x *SwapInfo) GetPrice() *PriceInfo {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *SwapInfo) GetMev() *MEVInfo {
+	if x != nil {
+		return x.Mev
+	}
+	return nil
+}
+
+func (x *SwapInfo) GetAmountUsd() float64 {
+	if x != nil {
+		return x.AmountUsd
+	}
+	return 0
+}
+
+// PriceInfo is the execution price of a swap
+type PriceInfo struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BaseMint  []byte                 `protobuf:"bytes,1,opt,name=base_mint,json=baseMint,proto3" json:"base_mint,omitempty"`
+	QuoteMint []byte                 `protobuf:"bytes,2,opt,name=quote_mint,json=quoteMint,proto3" json:"quote_mint,omitempty"`
+	// quote tokens per base token
+	Price float64 `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	// base token price in SOL, 0 if unknown
+	PriceSol float64 `protobuf:"fixed64,4,opt,name=price_sol,json=priceSol,proto3" json:"price_sol,omitempty"`
+	// base token price in USD, 0 if unknown
+	PriceUsd float64 `protobuf:"fixed64,5,opt,name=price_usd,json=priceUsd,proto3" json:"price_usd,omitempty"`
+	// swap value in USD, 0 if unknown
+	VolumeUsd     float64 `protobuf:"fixed64,6,opt,name=volume_usd,json=volumeUsd,proto3" json:"volume_usd,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceInfo) Reset() {
+	*x = PriceInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceInfo) ProtoMessage() {}
+
+func (x *PriceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceInfo.ProtoReflect.Descriptor instead.
+func (*PriceInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PriceInfo) GetBaseMint() []byte {
+	if x != nil {
+		return x.BaseMint
+	}
+	return nil
+}
+
+func (x *PriceInfo) GetQuoteMint() []byte {
+	if x != nil {
+		return x.QuoteMint
+	}
+	return nil
+}
+
+func (x *PriceInfo) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceInfo) GetPriceSol() float64 {
+	if x != nil {
+		return x.PriceSol
+	}
+	return 0
+}
+
+func (x *PriceInfo) GetPriceUsd() float64 {
+	if x != nil {
+		return x.PriceUsd
+	}
+	return 0
+}
+
+func (x *PriceInfo) GetVolumeUsd() float64 {
+	if x != nil {
+		return x.VolumeUsd
+	}
+	return 0
+}
+
+// MEVInfo marks a swap as part of a sandwich attack
+type MEVInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// frontrun, victim or backrun
+	Role     string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Attacker []byte `protobuf:"bytes,2,opt,name=attacker,proto3" json:"attacker,omitempty"`
+	Frontrun []byte `protobuf:"bytes,3,opt,name=frontrun,proto3" json:"frontrun,omitempty"`
+	Backrun  []byte `protobuf:"bytes,4,opt,name=backrun,proto3" json:"backrun,omitempty"`
+	// estimated output tokens the victim lost, victims only
+	VictimLoss uint64 `protobuf:"varint,5,opt,name=victim_loss,json=victimLoss,proto3" json:"victim_loss,omitempty"`
+	// victim_loss as a share of the expected output, 0 to 1
+	VictimSlippage float64 `protobuf:"fixed64,6,opt,name=victim_slippage,json=victimSlippage,proto3" json:"victim_slippage,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MEVInfo) Reset() {
+	*x = MEVInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MEVInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MEVInfo) ProtoMessage() {}
+
+func (x *MEVInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MEVInfo.ProtoReflect.Descriptor instead.
+func (*MEVInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MEVInfo) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *MEVInfo) GetAttacker() []byte {
+	if x != nil {
+		return x.Attacker
+	}
+	return nil
+}
+
+func (x *MEVInfo) GetFrontrun() []byte {
+	if x != nil {
+		return x.Frontrun
+	}
+	return nil
+}
+
+func (x *MEVInfo) GetBackrun() []byte {
+	if x != nil {
+		return x.Backrun
+	}
+	return nil
+}
+
+func (x *MEVInfo) GetVictimLoss() uint64 {
+	if x != nil {
+		return x.VictimLoss
+	}
+	return 0
+}
+
+func (x *MEVInfo) GetVictimSlippage() float64 {
+	if x != nil {
+		return x.VictimSlippage
+	}
+	return 0
+}
+
+type TransferInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Program          []byte                 `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,3,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,4,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Mint             []byte                 `protobuf:"bytes,5,opt,name=mint,proto3" json:"mint,omitempty"`
+	Source           []byte                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	Destination      []byte                 `protobuf:"bytes,7,opt,name=destination,proto3" json:"destination,omitempty"`
+	SourceOwner      []byte                 `protobuf:"bytes,8,opt,name=source_owner,json=sourceOwner,proto3" json:"source_owner,omitempty"`
+	DestinationOwner []byte                 `protobuf:"bytes,9,opt,name=destination_owner,json=destinationOwner,proto3" json:"destination_owner,omitempty"`
+	Authority        []byte                 `protobuf:"bytes,10,opt,name=authority,proto3" json:"authority,omitempty"`
+	Amount           uint64                 `protobuf:"varint,11,opt,name=amount,proto3" json:"amount,omitempty"`
+	Decimals         uint32                 `protobuf:"varint,12,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	// set by the pricing package, 0 if the mint has no known price
+	AmountUsd     float64 `protobuf:"fixed64,13,opt,name=amount_usd,json=amountUsd,proto3" json:"amount_usd,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferInfo) Reset() {
+	*x = TransferInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferInfo) ProtoMessage() {}
+
+func (x *TransferInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferInfo.ProtoReflect.Descriptor instead.
+func (*TransferInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TransferInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TransferInfo) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *TransferInfo) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *TransferInfo) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *TransferInfo) GetMint() []byte {
+	if x != nil {
+		return x.Mint
+	}
+	return nil
+}
+
+func (x *TransferInfo) GetSource() []byte {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *TransferInfo) GetDestination() []byte {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
+func (x *TransferInfo) GetSourceOwner() []byte {
+	if x != nil {
+		return x.SourceOwner
+	}
+	return nil
+}
+
+func (x *TransferInfo) GetDestinationOwner() []byte {
+	if x != nil {
+		return x.DestinationOwner
+	}
+	return nil
+}
+
+func (x *TransferInfo) GetAuthority() []byte {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *TransferInfo) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransferInfo) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *TransferInfo) GetAmountUsd() float64 {
+	if x != nil {
+		return x.AmountUsd
+	}
+	return 0
+}
+
+type StakeEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Pool             string                 `protobuf:"bytes,2,opt,name=pool,proto3" json:"pool,omitempty"`
+	Program          []byte                 `protobuf:"bytes,3,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,4,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,5,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	StakeAccount     []byte                 `protobuf:"bytes,6,opt,name=stake_account,json=stakeAccount,proto3" json:"stake_account,omitempty"`
+	TargetAccount    []byte                 `protobuf:"bytes,7,opt,name=target_account,json=targetAccount,proto3" json:"target_account,omitempty"`
+	VoteAccount      []byte                 `protobuf:"bytes,8,opt,name=vote_account,json=voteAccount,proto3" json:"vote_account,omitempty"`
+	Authority        []byte                 `protobuf:"bytes,9,opt,name=authority,proto3" json:"authority,omitempty"`
+	Recipient        []byte                 `protobuf:"bytes,10,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Lamports         uint64                 `protobuf:"varint,11,opt,name=lamports,proto3" json:"lamports,omitempty"`
+	PoolMint         []byte                 `protobuf:"bytes,12,opt,name=pool_mint,json=poolMint,proto3" json:"pool_mint,omitempty"`
+	PoolTokenAmount  uint64                 `protobuf:"varint,13,opt,name=pool_token_amount,json=poolTokenAmount,proto3" json:"pool_token_amount,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StakeEvent) Reset() {
+	*x = StakeEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StakeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StakeEvent) ProtoMessage() {}
+
+func (x *StakeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StakeEvent.ProtoReflect.Descriptor instead.
+func (*StakeEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StakeEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *StakeEvent) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *StakeEvent) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *StakeEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *StakeEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *StakeEvent) GetStakeAccount() []byte {
+	if x != nil {
+		return x.StakeAccount
+	}
+	return nil
+}
+
+func (x *StakeEvent) GetTargetAccount() []byte {
+	if x != nil {
+		return x.TargetAccount
+	}
+	return nil
+}
+
+func (x *StakeEvent) GetVoteAccount() []byte {
+	if x != nil {
+		return x.VoteAccount
+	}
+	return nil
+}
+
+func (x *StakeEvent) GetAuthority() []byte {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *StakeEvent) GetRecipient() []byte {
+	if x != nil {
+		return x.Recipient
+	}
+	return nil
+}
+
+func (x *StakeEvent) GetLamports() uint64 {
+	if x != nil {
+		return x.Lamports
+	}
+	return 0
+}
+
+func (x *StakeEvent) GetPoolMint() []byte {
+	if x != nil {
+		return x.PoolMint
+	}
+	return nil
+}
+
+func (x *StakeEvent) GetPoolTokenAmount() uint64 {
+	if x != nil {
+		return x.PoolTokenAmount
+	}
+	return 0
+}
+
+type TokenSupplyEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Program          []byte                 `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,3,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,4,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Mint             []byte                 `protobuf:"bytes,5,opt,name=mint,proto3" json:"mint,omitempty"`
+	Account          []byte                 `protobuf:"bytes,6,opt,name=account,proto3" json:"account,omitempty"`
+	Owner            []byte                 `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`
+	Authority        []byte                 `protobuf:"bytes,8,opt,name=authority,proto3" json:"authority,omitempty"`
+	Amount           uint64                 `protobuf:"varint,9,opt,name=amount,proto3" json:"amount,omitempty"`
+	Decimals         uint32                 `protobuf:"varint,10,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TokenSupplyEvent) Reset() {
+	*x = TokenSupplyEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenSupplyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenSupplyEvent) ProtoMessage() {}
+
+func (x *TokenSupplyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenSupplyEvent.ProtoReflect.Descriptor instead.
+func (*TokenSupplyEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TokenSupplyEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TokenSupplyEvent) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *TokenSupplyEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *TokenSupplyEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *TokenSupplyEvent) GetMint() []byte {
+	if x != nil {
+		return x.Mint
+	}
+	return nil
+}
+
+func (x *TokenSupplyEvent) GetAccount() []byte {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *TokenSupplyEvent) GetOwner() []byte {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *TokenSupplyEvent) GetAuthority() []byte {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *TokenSupplyEvent) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TokenSupplyEvent) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+type TokenAdminEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Program          []byte                 `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,3,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,4,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Mint             []byte                 `protobuf:"bytes,5,opt,name=mint,proto3" json:"mint,omitempty"`
+	Account          []byte                 `protobuf:"bytes,6,opt,name=account,proto3" json:"account,omitempty"`
+	Authority        []byte                 `protobuf:"bytes,7,opt,name=authority,proto3" json:"authority,omitempty"`
+	AuthorityType    string                 `protobuf:"bytes,8,opt,name=authority_type,json=authorityType,proto3" json:"authority_type,omitempty"`
+	// unset when the authority is revoked
+	NewAuthority  []byte `protobuf:"bytes,9,opt,name=new_authority,json=newAuthority,proto3,oneof" json:"new_authority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenAdminEvent) Reset() {
+	*x = TokenAdminEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenAdminEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenAdminEvent) ProtoMessage() {}
+
+func (x *TokenAdminEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenAdminEvent.ProtoReflect.Descriptor instead.
+func (*TokenAdminEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TokenAdminEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TokenAdminEvent) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *TokenAdminEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *TokenAdminEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *TokenAdminEvent) GetMint() []byte {
+	if x != nil {
+		return x.Mint
+	}
+	return nil
+}
+
+func (x *TokenAdminEvent) GetAccount() []byte {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *TokenAdminEvent) GetAuthority() []byte {
+	if x != nil {
+		return x.Authority
+	}
+	return nil
+}
+
+func (x *TokenAdminEvent) GetAuthorityType() string {
+	if x != nil {
+		return x.AuthorityType
+	}
+	return ""
+}
+
+func (x *TokenAdminEvent) GetNewAuthority() []byte {
+	if x != nil {
+		return x.NewAuthority
+	}
+	return nil
+}
+
+type ComputeBudget struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	UnitLimit           uint32                 `protobuf:"varint,1,opt,name=unit_limit,json=unitLimit,proto3" json:"unit_limit,omitempty"`
+	UnitPrice           uint64                 `protobuf:"varint,2,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	HeapFrameBytes      uint32                 `protobuf:"varint,3,opt,name=heap_frame_bytes,json=heapFrameBytes,proto3" json:"heap_frame_bytes,omitempty"`
+	LoadedAccountsLimit uint32                 `protobuf:"varint,4,opt,name=loaded_accounts_limit,json=loadedAccountsLimit,proto3" json:"loaded_accounts_limit,omitempty"`
+	EffectiveUnitLimit  uint32                 `protobuf:"varint,5,opt,name=effective_unit_limit,json=effectiveUnitLimit,proto3" json:"effective_unit_limit,omitempty"`
+	PriorityFee         uint64                 `protobuf:"varint,6,opt,name=priority_fee,json=priorityFee,proto3" json:"priority_fee,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ComputeBudget) Reset() {
+	*x = ComputeBudget{}
+	mi := &file_solana_toolkit_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeBudget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeBudget) ProtoMessage() {}
+
+func (x *ComputeBudget) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeBudget.ProtoReflect.Descriptor instead.
+func (*ComputeBudget) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ComputeBudget) GetUnitLimit() uint32 {
+	if x != nil {
+		return x.UnitLimit
+	}
+	return 0
+}
+
+func (x *ComputeBudget) GetUnitPrice() uint64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *ComputeBudget) GetHeapFrameBytes() uint32 {
+	if x != nil {
+		return x.HeapFrameBytes
+	}
+	return 0
+}
+
+func (x *ComputeBudget) GetLoadedAccountsLimit() uint32 {
+	if x != nil {
+		return x.LoadedAccountsLimit
+	}
+	return 0
+}
+
+func (x *ComputeBudget) GetEffectiveUnitLimit() uint32 {
+	if x != nil {
+		return x.EffectiveUnitLimit
+	}
+	return 0
+}
+
+func (x *ComputeBudget) GetPriorityFee() uint64 {
+	if x != nil {
+		return x.PriorityFee
+	}
+	return 0
+}
+
+type BundleInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            []byte                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Position      uint32                 `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	Size          uint32                 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Tip           uint64                 `protobuf:"varint,4,opt,name=tip,proto3" json:"tip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BundleInfo) Reset() {
+	*x = BundleInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BundleInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BundleInfo) ProtoMessage() {}
+
+func (x *BundleInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BundleInfo.ProtoReflect.Descriptor instead.
+func (*BundleInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BundleInfo) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *BundleInfo) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *BundleInfo) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BundleInfo) GetTip() uint64 {
+	if x != nil {
+		return x.Tip
+	}
+	return 0
+}
+
+type MemoInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Program          []byte                 `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,2,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,3,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Text             string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	Signers          [][]byte               `protobuf:"bytes,5,rep,name=signers,proto3" json:"signers,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MemoInfo) Reset() {
+	*x = MemoInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemoInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemoInfo) ProtoMessage() {}
+
+func (x *MemoInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemoInfo.ProtoReflect.Descriptor instead.
+func (*MemoInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MemoInfo) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *MemoInfo) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *MemoInfo) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *MemoInfo) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *MemoInfo) GetSigners() [][]byte {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+type PoolCreatedEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Protocol         string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Program          []byte                 `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,3,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,4,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Pool             []byte                 `protobuf:"bytes,5,opt,name=pool,proto3" json:"pool,omitempty"`
+	Creator          []byte                 `protobuf:"bytes,6,opt,name=creator,proto3" json:"creator,omitempty"`
+	MintA            []byte                 `protobuf:"bytes,7,opt,name=mint_a,json=mintA,proto3" json:"mint_a,omitempty"`
+	MintB            []byte                 `protobuf:"bytes,8,opt,name=mint_b,json=mintB,proto3" json:"mint_b,omitempty"`
+	DecimalsA        uint32                 `protobuf:"varint,9,opt,name=decimals_a,json=decimalsA,proto3" json:"decimals_a,omitempty"`
+	DecimalsB        uint32                 `protobuf:"varint,10,opt,name=decimals_b,json=decimalsB,proto3" json:"decimals_b,omitempty"`
+	AmountA          uint64                 `protobuf:"varint,11,opt,name=amount_a,json=amountA,proto3" json:"amount_a,omitempty"`
+	AmountB          uint64                 `protobuf:"varint,12,opt,name=amount_b,json=amountB,proto3" json:"amount_b,omitempty"`
+	// bonding curve the pool graduated from, empty for new launches
+	Curve         []byte `protobuf:"bytes,13,opt,name=curve,proto3" json:"curve,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolCreatedEvent) Reset() {
+	*x = PoolCreatedEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolCreatedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolCreatedEvent) ProtoMessage() {}
+
+func (x *PoolCreatedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolCreatedEvent.ProtoReflect.Descriptor instead.
+func (*PoolCreatedEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PoolCreatedEvent) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *PoolCreatedEvent) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *PoolCreatedEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *PoolCreatedEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *PoolCreatedEvent) GetPool() []byte {
+	if x != nil {
+		return x.Pool
+	}
+	return nil
+}
+
+func (x *PoolCreatedEvent) GetCreator() []byte {
+	if x != nil {
+		return x.Creator
+	}
+	return nil
+}
+
+func (x *PoolCreatedEvent) GetMintA() []byte {
+	if x != nil {
+		return x.MintA
+	}
+	return nil
+}
+
+func (x *PoolCreatedEvent) GetMintB() []byte {
+	if x != nil {
+		return x.MintB
+	}
+	return nil
+}
+
+func (x *PoolCreatedEvent) GetDecimalsA() uint32 {
+	if x != nil {
+		return x.DecimalsA
+	}
+	return 0
+}
+
+func (x *PoolCreatedEvent) GetDecimalsB() uint32 {
+	if x != nil {
+		return x.DecimalsB
+	}
+	return 0
+}
+
+func (x *PoolCreatedEvent) GetAmountA() uint64 {
+	if x != nil {
+		return x.AmountA
+	}
+	return 0
+}
+
+func (x *PoolCreatedEvent) GetAmountB() uint64 {
+	if x != nil {
+		return x.AmountB
+	}
+	return 0
+}
+
+func (x *PoolCreatedEvent) GetCurve() []byte {
+	if x != nil {
+		return x.Curve
+	}
+	return nil
+}
+
+type PerpFillInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Venue            string                 `protobuf:"bytes,2,opt,name=venue,proto3" json:"venue,omitempty"`
+	Program          []byte                 `protobuf:"bytes,3,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,4,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,5,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Trader           []byte                 `protobuf:"bytes,6,opt,name=trader,proto3" json:"trader,omitempty"`
+	Account          []byte                 `protobuf:"bytes,7,opt,name=account,proto3" json:"account,omitempty"`
+	Market           []byte                 `protobuf:"bytes,8,opt,name=market,proto3" json:"market,omitempty"`
+	Asset            string                 `protobuf:"bytes,9,opt,name=asset,proto3" json:"asset,omitempty"`
+	Side             string                 `protobuf:"bytes,10,opt,name=side,proto3" json:"side,omitempty"`
+	Size             uint64                 `protobuf:"varint,11,opt,name=size,proto3" json:"size,omitempty"`
+	Price            uint64                 `protobuf:"varint,12,opt,name=price,proto3" json:"price,omitempty"`
+	Fee              uint64                 `protobuf:"varint,13,opt,name=fee,proto3" json:"fee,omitempty"`
+	Liquidator       []byte                 `protobuf:"bytes,14,opt,name=liquidator,proto3" json:"liquidator,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PerpFillInfo) Reset() {
+	*x = PerpFillInfo{}
+	mi := &file_solana_toolkit_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PerpFillInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PerpFillInfo) ProtoMessage() {}
+
+func (x *PerpFillInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PerpFillInfo.ProtoReflect.Descriptor instead.
+func (*PerpFillInfo) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PerpFillInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PerpFillInfo) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *PerpFillInfo) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *PerpFillInfo) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *PerpFillInfo) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *PerpFillInfo) GetTrader() []byte {
+	if x != nil {
+		return x.Trader
+	}
+	return nil
+}
+
+func (x *PerpFillInfo) GetAccount() []byte {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *PerpFillInfo) GetMarket() []byte {
+	if x != nil {
+		return x.Market
+	}
+	return nil
+}
+
+func (x *PerpFillInfo) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *PerpFillInfo) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *PerpFillInfo) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PerpFillInfo) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PerpFillInfo) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *PerpFillInfo) GetLiquidator() []byte {
+	if x != nil {
+		return x.Liquidator
+	}
+	return nil
+}
+
+type CompressedNftEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,2,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,3,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Tree             []byte                 `protobuf:"bytes,4,opt,name=tree,proto3" json:"tree,omitempty"`
+	LeafIndex        uint64                 `protobuf:"varint,5,opt,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	AssetId          []byte                 `protobuf:"bytes,6,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	Owner            []byte                 `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`
+	Delegate         []byte                 `protobuf:"bytes,8,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	NewOwner         []byte                 `protobuf:"bytes,9,opt,name=new_owner,json=newOwner,proto3" json:"new_owner,omitempty"`
+	Collection       []byte                 `protobuf:"bytes,10,opt,name=collection,proto3" json:"collection,omitempty"`
+	Name             string                 `protobuf:"bytes,11,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol           string                 `protobuf:"bytes,12,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Uri              string                 `protobuf:"bytes,13,opt,name=uri,proto3" json:"uri,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CompressedNftEvent) Reset() {
+	*x = CompressedNftEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressedNftEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressedNftEvent) ProtoMessage() {}
+
+func (x *CompressedNftEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressedNftEvent.ProtoReflect.Descriptor instead.
+func (*CompressedNftEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CompressedNftEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CompressedNftEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *CompressedNftEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *CompressedNftEvent) GetTree() []byte {
+	if x != nil {
+		return x.Tree
+	}
+	return nil
+}
+
+func (x *CompressedNftEvent) GetLeafIndex() uint64 {
+	if x != nil {
+		return x.LeafIndex
+	}
+	return 0
+}
+
+func (x *CompressedNftEvent) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *CompressedNftEvent) GetOwner() []byte {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *CompressedNftEvent) GetDelegate() []byte {
+	if x != nil {
+		return x.Delegate
+	}
+	return nil
+}
+
+func (x *CompressedNftEvent) GetNewOwner() []byte {
+	if x != nil {
+		return x.NewOwner
+	}
+	return nil
+}
+
+func (x *CompressedNftEvent) GetCollection() []byte {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+func (x *CompressedNftEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CompressedNftEvent) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CompressedNftEvent) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+type NftMintEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Source           string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Program          []byte                 `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,3,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,4,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Mint             []byte                 `protobuf:"bytes,5,opt,name=mint,proto3" json:"mint,omitempty"`
+	Minter           []byte                 `protobuf:"bytes,6,opt,name=minter,proto3" json:"minter,omitempty"`
+	Payer            []byte                 `protobuf:"bytes,7,opt,name=payer,proto3" json:"payer,omitempty"`
+	Collection       []byte                 `protobuf:"bytes,8,opt,name=collection,proto3" json:"collection,omitempty"`
+	CandyMachine     []byte                 `protobuf:"bytes,9,opt,name=candy_machine,json=candyMachine,proto3" json:"candy_machine,omitempty"`
+	Price            uint64                 `protobuf:"varint,10,opt,name=price,proto3" json:"price,omitempty"`
+	Name             string                 `protobuf:"bytes,11,opt,name=name,proto3" json:"name,omitempty"`
+	Uri              string                 `protobuf:"bytes,12,opt,name=uri,proto3" json:"uri,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NftMintEvent) Reset() {
+	*x = NftMintEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NftMintEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NftMintEvent) ProtoMessage() {}
+
+func (x *NftMintEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NftMintEvent.ProtoReflect.Descriptor instead.
+func (*NftMintEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NftMintEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *NftMintEvent) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *NftMintEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *NftMintEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *NftMintEvent) GetMint() []byte {
+	if x != nil {
+		return x.Mint
+	}
+	return nil
+}
+
+func (x *NftMintEvent) GetMinter() []byte {
+	if x != nil {
+		return x.Minter
+	}
+	return nil
+}
+
+func (x *NftMintEvent) GetPayer() []byte {
+	if x != nil {
+		return x.Payer
+	}
+	return nil
+}
+
+func (x *NftMintEvent) GetCollection() []byte {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+func (x *NftMintEvent) GetCandyMachine() []byte {
+	if x != nil {
+		return x.CandyMachine
+	}
+	return nil
+}
+
+func (x *NftMintEvent) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *NftMintEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NftMintEvent) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+type DomainEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,2,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,3,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Domain           []byte                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	Name             string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Owner            []byte                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	NewOwner         []byte                 `protobuf:"bytes,7,opt,name=new_owner,json=newOwner,proto3" json:"new_owner,omitempty"`
+	Parent           []byte                 `protobuf:"bytes,8,opt,name=parent,proto3" json:"parent,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DomainEvent) Reset() {
+	*x = DomainEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainEvent) ProtoMessage() {}
+
+func (x *DomainEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainEvent.ProtoReflect.Descriptor instead.
+func (*DomainEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DomainEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DomainEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *DomainEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *DomainEvent) GetDomain() []byte {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+func (x *DomainEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DomainEvent) GetOwner() []byte {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *DomainEvent) GetNewOwner() []byte {
+	if x != nil {
+		return x.NewOwner
+	}
+	return nil
+}
+
+func (x *DomainEvent) GetParent() []byte {
+	if x != nil {
+		return x.Parent
+	}
+	return nil
+}
+
+type BridgeEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Protocol         string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Direction        string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	Program          []byte                 `protobuf:"bytes,3,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,4,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,5,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	SourceChain      string                 `protobuf:"bytes,6,opt,name=source_chain,json=sourceChain,proto3" json:"source_chain,omitempty"`
+	DestinationChain string                 `protobuf:"bytes,7,opt,name=destination_chain,json=destinationChain,proto3" json:"destination_chain,omitempty"`
+	Wallet           []byte                 `protobuf:"bytes,8,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	ForeignAddress   string                 `protobuf:"bytes,9,opt,name=foreign_address,json=foreignAddress,proto3" json:"foreign_address,omitempty"`
+	Mint             []byte                 `protobuf:"bytes,10,opt,name=mint,proto3" json:"mint,omitempty"`
+	Amount           uint64                 `protobuf:"varint,11,opt,name=amount,proto3" json:"amount,omitempty"`
+	Decimals         uint32                 `protobuf:"varint,12,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BridgeEvent) Reset() {
+	*x = BridgeEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BridgeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BridgeEvent) ProtoMessage() {}
+
+func (x *BridgeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BridgeEvent.ProtoReflect.Descriptor instead.
+func (*BridgeEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BridgeEvent) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *BridgeEvent) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *BridgeEvent) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *BridgeEvent) GetSourceChain() string {
+	if x != nil {
+		return x.SourceChain
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetDestinationChain() string {
+	if x != nil {
+		return x.DestinationChain
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetWallet() []byte {
+	if x != nil {
+		return x.Wallet
+	}
+	return nil
+}
+
+func (x *BridgeEvent) GetForeignAddress() string {
+	if x != nil {
+		return x.ForeignAddress
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetMint() []byte {
+	if x != nil {
+		return x.Mint
+	}
+	return nil
+}
+
+func (x *BridgeEvent) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *BridgeEvent) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+// InstructionNode is an instruction in the call tree of a transaction
+type InstructionNode struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Program          []byte                 `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,2,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	// -1 for outer instructions
+	InnerIndex int32 `protobuf:"varint,3,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	// 1 for outer instructions
+	StackHeight   uint32             `protobuf:"varint,4,opt,name=stack_height,json=stackHeight,proto3" json:"stack_height,omitempty"`
+	Children      []*InstructionNode `protobuf:"bytes,5,rep,name=children,proto3" json:"children,omitempty"`
+	ComputeUnits  uint64             `protobuf:"varint,6,opt,name=compute_units,json=computeUnits,proto3" json:"compute_units,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstructionNode) Reset() {
+	*x = InstructionNode{}
+	mi := &file_solana_toolkit_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstructionNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstructionNode) ProtoMessage() {}
+
+func (x *InstructionNode) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstructionNode.ProtoReflect.Descriptor instead.
+func (*InstructionNode) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *InstructionNode) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *InstructionNode) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *InstructionNode) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *InstructionNode) GetStackHeight() uint32 {
+	if x != nil {
+		return x.StackHeight
+	}
+	return 0
+}
+
+func (x *InstructionNode) GetChildren() []*InstructionNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *InstructionNode) GetComputeUnits() uint64 {
+	if x != nil {
+		return x.ComputeUnits
+	}
+	return 0
+}
+
+// TransactionFailure describes why a failed transaction failed
+type TransactionFailure struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Kind             string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Error            string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	InstructionError string                 `protobuf:"bytes,3,opt,name=instruction_error,json=instructionError,proto3" json:"instruction_error,omitempty"`
+	// -1 when no instruction failed
+	InstructionIndex int32   `protobuf:"varint,4,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32   `protobuf:"varint,5,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Program          []byte  `protobuf:"bytes,6,opt,name=program,proto3" json:"program,omitempty"`
+	CustomCode       *uint32 `protobuf:"varint,7,opt,name=custom_code,json=customCode,proto3,oneof" json:"custom_code,omitempty"`
+	ErrorName        string  `protobuf:"bytes,8,opt,name=error_name,json=errorName,proto3" json:"error_name,omitempty"`
+	ErrorMessage     string  `protobuf:"bytes,9,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransactionFailure) Reset() {
+	*x = TransactionFailure{}
+	mi := &file_solana_toolkit_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionFailure) ProtoMessage() {}
+
+func (x *TransactionFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionFailure.ProtoReflect.Descriptor instead.
+func (*TransactionFailure) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TransactionFailure) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TransactionFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TransactionFailure) GetInstructionError() string {
+	if x != nil {
+		return x.InstructionError
+	}
+	return ""
+}
+
+func (x *TransactionFailure) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *TransactionFailure) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *TransactionFailure) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *TransactionFailure) GetCustomCode() uint32 {
+	if x != nil && x.CustomCode != nil {
+		return *x.CustomCode
+	}
+	return 0
+}
+
+func (x *TransactionFailure) GetErrorName() string {
+	if x != nil {
+		return x.ErrorName
+	}
+	return ""
+}
+
+func (x *TransactionFailure) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ParseError struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Protocol         string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Program          []byte                 `protobuf:"bytes,2,opt,name=program,proto3" json:"program,omitempty"`
+	InstructionIndex int32                  `protobuf:"varint,3,opt,name=instruction_index,json=instructionIndex,proto3" json:"instruction_index,omitempty"`
+	InnerIndex       int32                  `protobuf:"varint,4,opt,name=inner_index,json=innerIndex,proto3" json:"inner_index,omitempty"`
+	Error            string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ParseError) Reset() {
+	*x = ParseError{}
+	mi := &file_solana_toolkit_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseError) ProtoMessage() {}
+
+func (x *ParseError) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseError.ProtoReflect.Descriptor instead.
+func (*ParseError) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ParseError) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ParseError) GetProgram() []byte {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *ParseError) GetInstructionIndex() int32 {
+	if x != nil {
+		return x.InstructionIndex
+	}
+	return 0
+}
+
+func (x *ParseError) GetInnerIndex() int32 {
+	if x != nil {
+		return x.InnerIndex
+	}
+	return 0
+}
+
+func (x *ParseError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ParsedTransaction struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Signature []byte                 `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Slot      uint64                 `protobuf:"varint,2,opt,name=slot,proto3" json:"slot,omitempty"`
+	// unix seconds, unset when the block time is unknown
+	BlockTime     *int64              `protobuf:"varint,3,opt,name=block_time,json=blockTime,proto3,oneof" json:"block_time,omitempty"`
+	Fee           uint64              `protobuf:"varint,4,opt,name=fee,proto3" json:"fee,omitempty"`
+	Swaps         []*SwapInfo         `protobuf:"bytes,5,rep,name=swaps,proto3" json:"swaps,omitempty"`
+	Transfers     []*TransferInfo     `protobuf:"bytes,6,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	StakeEvents   []*StakeEvent       `protobuf:"bytes,7,rep,name=stake_events,json=stakeEvents,proto3" json:"stake_events,omitempty"`
+	SupplyEvents  []*TokenSupplyEvent `protobuf:"bytes,8,rep,name=supply_events,json=supplyEvents,proto3" json:"supply_events,omitempty"`
+	AdminEvents   []*TokenAdminEvent  `protobuf:"bytes,9,rep,name=admin_events,json=adminEvents,proto3" json:"admin_events,omitempty"`
+	ComputeBudget *ComputeBudget      `protobuf:"bytes,10,opt,name=compute_budget,json=computeBudget,proto3" json:"compute_budget,omitempty"`
+	Memos         []*MemoInfo         `protobuf:"bytes,11,rep,name=memos,proto3" json:"memos,omitempty"`
+	Errors        []*ParseError       `protobuf:"bytes,12,rep,name=errors,proto3" json:"errors,omitempty"`
+	PoolCreations []*PoolCreatedEvent `protobuf:"bytes,13,rep,name=pool_creations,json=poolCreations,proto3" json:"pool_creations,omitempty"`
+	FeePayer      []byte              `protobuf:"bytes,14,opt,name=fee_payer,json=feePayer,proto3" json:"fee_payer,omitempty"`
+	JitoTip       uint64              `protobuf:"varint,15,opt,name=jito_tip,json=jitoTip,proto3" json:"jito_tip,omitempty"`
+	// set when the transaction was parsed as part of a block
+	Bundle         *BundleInfo           `protobuf:"bytes,16,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	PerpFills      []*PerpFillInfo       `protobuf:"bytes,17,rep,name=perp_fills,json=perpFills,proto3" json:"perp_fills,omitempty"`
+	CompressedNfts []*CompressedNftEvent `protobuf:"bytes,18,rep,name=compressed_nfts,json=compressedNfts,proto3" json:"compressed_nfts,omitempty"`
+	NftMints       []*NftMintEvent       `protobuf:"bytes,19,rep,name=nft_mints,json=nftMints,proto3" json:"nft_mints,omitempty"`
+	DomainEvents   []*DomainEvent        `protobuf:"bytes,20,rep,name=domain_events,json=domainEvents,proto3" json:"domain_events,omitempty"`
+	Bridges        []*BridgeEvent        `protobuf:"bytes,21,rep,name=bridges,proto3" json:"bridges,omitempty"`
+	CallTree       []*InstructionNode    `protobuf:"bytes,22,rep,name=call_tree,json=callTree,proto3" json:"call_tree,omitempty"`
+	// set for failed transactions parsed with failure analysis
+	Failure       *TransactionFailure `protobuf:"bytes,23,opt,name=failure,proto3" json:"failure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParsedTransaction) Reset() {
+	*x = ParsedTransaction{}
+	mi := &file_solana_toolkit_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParsedTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParsedTransaction) ProtoMessage() {}
+
+func (x *ParsedTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParsedTransaction.ProtoReflect.Descriptor instead.
+func (*ParsedTransaction) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ParsedTransaction) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *ParsedTransaction) GetBlockTime() int64 {
+	if x != nil && x.BlockTime != nil {
+		return *x.BlockTime
+	}
+	return 0
+}
+
+func (x *ParsedTransaction) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *ParsedTransaction) GetSwaps() []*SwapInfo {
+	if x != nil {
+		return x.Swaps
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetTransfers() []*TransferInfo {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetStakeEvents() []*StakeEvent {
+	if x != nil {
+		return x.StakeEvents
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetSupplyEvents() []*TokenSupplyEvent {
+	if x != nil {
+		return x.SupplyEvents
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetAdminEvents() []*TokenAdminEvent {
+	if x != nil {
+		return x.AdminEvents
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetComputeBudget() *ComputeBudget {
+	if x != nil {
+		return x.ComputeBudget
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetMemos() []*MemoInfo {
+	if x != nil {
+		return x.Memos
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetErrors() []*ParseError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetPoolCreations() []*PoolCreatedEvent {
+	if x != nil {
+		return x.PoolCreations
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetFeePayer() []byte {
+	if x != nil {
+		return x.FeePayer
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetJitoTip() uint64 {
+	if x != nil {
+		return x.JitoTip
+	}
+	return 0
+}
+
+func (x *ParsedTransaction) GetBundle() *BundleInfo {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetPerpFills() []*PerpFillInfo {
+	if x != nil {
+		return x.PerpFills
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetCompressedNfts() []*CompressedNftEvent {
+	if x != nil {
+		return x.CompressedNfts
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetNftMints() []*NftMintEvent {
+	if x != nil {
+		return x.NftMints
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetDomainEvents() []*DomainEvent {
+	if x != nil {
+		return x.DomainEvents
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetBridges() []*BridgeEvent {
+	if x != nil {
+		return x.Bridges
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetCallTree() []*InstructionNode {
+	if x != nil {
+		return x.CallTree
+	}
+	return nil
+}
+
+func (x *ParsedTransaction) GetFailure() *TransactionFailure {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
+}
+
+// SwapEvent is a swap together with the transaction it was parsed from
+type SwapEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Signature []byte                 `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Slot      uint64                 `protobuf:"varint,2,opt,name=slot,proto3" json:"slot,omitempty"`
+	// unix seconds, unset when the block time is unknown
+	BlockTime *int64 `protobuf:"varint,3,opt,name=block_time,json=blockTime,proto3,oneof" json:"block_time,omitempty"`
+	// position of the swap among the swaps of the transaction
+	Index         int32     `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	Swap          *SwapInfo `protobuf:"bytes,5,opt,name=swap,proto3" json:"swap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SwapEvent) Reset() {
+	*x = SwapEvent{}
+	mi := &file_solana_toolkit_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwapEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwapEvent) ProtoMessage() {}
+
+func (x *SwapEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwapEvent.ProtoReflect.Descriptor instead.
+func (*SwapEvent) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SwapEvent) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *SwapEvent) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *SwapEvent) GetBlockTime() int64 {
+	if x != nil && x.BlockTime != nil {
+		return *x.BlockTime
+	}
+	return 0
+}
+
+func (x *SwapEvent) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SwapEvent) GetSwap() *SwapInfo {
+	if x != nil {
+		return x.Swap
+	}
+	return nil
+}
+
+// Event is the envelope sinks write, holding exactly one parsed event and where it came from
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// kind of the payload as reported by the sink package, e.g. "swap" or "transfer"
+	Kind      string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Slot      uint64 `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`
+	// unix seconds, unset when the block time is unknown
+	BlockTime *int64 `protobuf:"varint,4,opt,name=block_time,json=blockTime,proto3,oneof" json:"block_time,omitempty"`
+	// position of the event among events of its kind in the transaction
+	Index int32 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	// version of the parsed types the event was encoded with
+	SchemaVersion uint32 `protobuf:"varint,6,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Event_Swap
+	//	*Event_Transfer
+	//	*Event_Stake
+	//	*Event_TokenSupply
+	//	*Event_TokenAdmin
+	//	*Event_PoolCreated
+	//	*Event_PerpFill
+	//	*Event_CompressedNft
+	//	*Event_NftMint
+	//	*Event_Domain
+	//	*Event_Bridge
+	Payload       isEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_solana_toolkit_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Event) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Event) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Event) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *Event) GetBlockTime() int64 {
+	if x != nil && x.BlockTime != nil {
+		return *x.BlockTime
+	}
+	return 0
+}
+
+func (x *Event) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Event) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Event) GetPayload() isEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Event) GetSwap() *SwapInfo {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Swap); ok {
+			return x.Swap
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetTransfer() *TransferInfo {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Transfer); ok {
+			return x.Transfer
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetStake() *StakeEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Stake); ok {
+			return x.Stake
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetTokenSupply() *TokenSupplyEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_TokenSupply); ok {
+			return x.TokenSupply
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetTokenAdmin() *TokenAdminEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_TokenAdmin); ok {
+			return x.TokenAdmin
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetPoolCreated() *PoolCreatedEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_PoolCreated); ok {
+			return x.PoolCreated
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetPerpFill() *PerpFillInfo {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_PerpFill); ok {
+			return x.PerpFill
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetCompressedNft() *CompressedNftEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_CompressedNft); ok {
+			return x.CompressedNft
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetNftMint() *NftMintEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_NftMint); ok {
+			return x.NftMint
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetDomain() *DomainEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Domain); ok {
+			return x.Domain
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetBridge() *BridgeEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Bridge); ok {
+			return x.Bridge
+		}
+	}
+	return nil
+}
+
+type isEvent_Payload interface {
+	isEvent_Payload()
+}
+
+type Event_Swap struct {
+	Swap *SwapInfo `protobuf:"bytes,10,opt,name=swap,proto3,oneof"`
+}
+
+type Event_Transfer struct {
+	Transfer *TransferInfo `protobuf:"bytes,11,opt,name=transfer,proto3,oneof"`
+}
+
+type Event_Stake struct {
+	Stake *StakeEvent `protobuf:"bytes,12,opt,name=stake,proto3,oneof"`
+}
+
+type Event_TokenSupply struct {
+	TokenSupply *TokenSupplyEvent `protobuf:"bytes,13,opt,name=token_supply,json=tokenSupply,proto3,oneof"`
+}
+
+type Event_TokenAdmin struct {
+	TokenAdmin *TokenAdminEvent `protobuf:"bytes,14,opt,name=token_admin,json=tokenAdmin,proto3,oneof"`
+}
+
+type Event_PoolCreated struct {
+	PoolCreated *PoolCreatedEvent `protobuf:"bytes,15,opt,name=pool_created,json=poolCreated,proto3,oneof"`
+}
+
+type Event_PerpFill struct {
+	PerpFill *PerpFillInfo `protobuf:"bytes,16,opt,name=perp_fill,json=perpFill,proto3,oneof"`
+}
+
+type Event_CompressedNft struct {
+	CompressedNft *CompressedNftEvent `protobuf:"bytes,17,opt,name=compressed_nft,json=compressedNft,proto3,oneof"`
+}
+
+type Event_NftMint struct {
+	NftMint *NftMintEvent `protobuf:"bytes,18,opt,name=nft_mint,json=nftMint,proto3,oneof"`
+}
+
+type Event_Domain struct {
+	Domain *DomainEvent `protobuf:"bytes,19,opt,name=domain,proto3,oneof"`
+}
+
+type Event_Bridge struct {
+	Bridge *BridgeEvent `protobuf:"bytes,20,opt,name=bridge,proto3,oneof"`
+}
+
+func (*Event_Swap) isEvent_Payload() {}
+
+func (*Event_Transfer) isEvent_Payload() {}
+
+func (*Event_Stake) isEvent_Payload() {}
+
+func (*Event_TokenSupply) isEvent_Payload() {}
+
+func (*Event_TokenAdmin) isEvent_Payload() {}
+
+func (*Event_PoolCreated) isEvent_Payload() {}
+
+func (*Event_PerpFill) isEvent_Payload() {}
+
+func (*Event_CompressedNft) isEvent_Payload() {}
+
+func (*Event_NftMint) isEvent_Payload() {}
+
+func (*Event_Domain) isEvent_Payload() {}
+
+func (*Event_Bridge) isEvent_Payload() {}
+
+// SubscribeSwapsRequest filters a swap subscription. A swap must match every non-empty
+// field and matches a field when it matches any of its values.
+type SubscribeSwapsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// protocol names as reported by the parser, e.g. "Raydium" or "PumpFun"
+	Protocols []string `protobuf:"bytes,1,rep,name=protocols,proto3" json:"protocols,omitempty"`
+	// mints bought or sold
+	Mints [][]byte `protobuf:"bytes,2,rep,name=mints,proto3" json:"mints,omitempty"`
+	// wallets that signed the swap
+	Wallets       [][]byte `protobuf:"bytes,3,rep,name=wallets,proto3" json:"wallets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeSwapsRequest) Reset() {
+	*x = SubscribeSwapsRequest{}
+	mi := &file_solana_toolkit_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeSwapsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeSwapsRequest) ProtoMessage() {}
+
+func (x *SubscribeSwapsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_solana_toolkit_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeSwapsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeSwapsRequest) Descriptor() ([]byte, []int) {
+	return file_solana_toolkit_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SubscribeSwapsRequest) GetProtocols() []string {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+func (x *SubscribeSwapsRequest) GetMints() [][]byte {
+	if x != nil {
+		return x.Mints
+	}
+	return nil
+}
+
+func (x *SubscribeSwapsRequest) GetWallets() [][]byte {
+	if x != nil {
+		return x.Wallets
+	}
+	return nil
+}
+
+var File_solana_toolkit_proto protoreflect.FileDescriptor
+
+const file_solana_toolkit_proto_rawDesc = "" +
+	"\n" +
+	"\x14solana_toolkit.proto\x12\x11solana_toolkit.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x97\x01\n" +
+	"\tTokenInfo\x12\x12\n" +
+	"\x04mint\x18\x01 \x01(\fR\x04mint\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x04R\x06amount\x12\x1a\n" +
+	"\bdecimals\x18\x03 \x01(\rR\bdecimals\x12B\n" +
+	"\n" +
+	"extensions\x18\x04 \x01(\v2\".solana_toolkit.v1.TokenExtensionsR\n" +
+	"extensions\"\xd8\x02\n" +
+	"\x0fTokenExtensions\x12\x1e\n" +
+	"\n" +
+	"extensions\x18\x01 \x03(\tR\n" +
+	"extensions\x122\n" +
+	"\x15transfer_hook_program\x18\x02 \x01(\fR\x13transferHookProgram\x129\n" +
+	"\x19transfer_fee_basis_points\x18\x03 \x01(\rR\x16transferFeeBasisPoints\x12(\n" +
+	"\x10max_transfer_fee\x18\x04 \x01(\x04R\x0emaxTransferFee\x12#\n" +
+	"\rinterest_rate\x18\x05 \x01(\x05R\finterestRate\x120\n" +
+	"\x14scaled_ui_multiplier\x18\x06 \x01(\x01R\x12scaledUiMultiplier\x125\n" +
+	"\x16confidential_transfers\x18\a \x01(\bR\x15confidentialTransfers\"\xe1\x03\n" +
+	"\bSwapInfo\x12\x1a\n" +
+	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x18\n" +
+	"\asigners\x18\x02 \x03(\fR\asigners\x12\x1e\n" +
+	"\n" +
+	"signatures\x18\x03 \x03(\fR\n" +
+	"signatures\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x127\n" +
+	"\btoken_in\x18\x05 \x01(\v2\x1c.solana_toolkit.v1.TokenInfoR\atokenIn\x129\n" +
+	"\ttoken_out\x18\x06 \x01(\v2\x1c.solana_toolkit.v1.TokenInfoR\btokenOut\x12+\n" +
+	"\x11instruction_index\x18\a \x01(\x05R\x10instructionIndex\x12#\n" +
+	"\rcompute_units\x18\b \x01(\x04R\fcomputeUnits\x122\n" +
+	"\x05price\x18\t \x01(\v2\x1c.solana_toolkit.v1.PriceInfoR\x05price\x12,\n" +
+	"\x03mev\x18\n" +
+	" \x01(\v2\x1a.solana_toolkit.v1.MEVInfoR\x03mev\x12\x1d\n" +
+	"\n" +
+	"amount_usd\x18\v \x01(\x01R\tamountUsd\"\xb6\x01\n" +
+	"\tPriceInfo\x12\x1b\n" +
+	"\tbase_mint\x18\x01 \x01(\fR\bbaseMint\x12\x1d\n" +
+	"\n" +
+	"quote_mint\x18\x02 \x01(\fR\tquoteMint\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1b\n" +
+	"\tprice_sol\x18\x04 \x01(\x01R\bpriceSol\x12\x1b\n" +
+	"\tprice_usd\x18\x05 \x01(\x01R\bpriceUsd\x12\x1d\n" +
+	"\n" +
+	"volume_usd\x18\x06 \x01(\x01R\tvolumeUsd\"\xb9\x01\n" +
+	"\aMEVInfo\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x1a\n" +
+	"\battacker\x18\x02 \x01(\fR\battacker\x12\x1a\n" +
+	"\bfrontrun\x18\x03 \x01(\fR\bfrontrun\x12\x18\n" +
+	"\abackrun\x18\x04 \x01(\fR\abackrun\x12\x1f\n" +
+	"\vvictim_loss\x18\x05 \x01(\x04R\n" +
+	"victimLoss\x12'\n" +
+	"\x0fvictim_slippage\x18\x06 \x01(\x01R\x0evictimSlippage\"\x99\x03\n" +
+	"\fTransferInfo\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aprogram\x18\x02 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x03 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x04 \x01(\x05R\n" +
+	"innerIndex\x12\x12\n" +
+	"\x04mint\x18\x05 \x01(\fR\x04mint\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\fR\x06source\x12 \n" +
+	"\vdestination\x18\a \x01(\fR\vdestination\x12!\n" +
+	"\fsource_owner\x18\b \x01(\fR\vsourceOwner\x12+\n" +
+	"\x11destination_owner\x18\t \x01(\fR\x10destinationOwner\x12\x1c\n" +
+	"\tauthority\x18\n" +
+	" \x01(\fR\tauthority\x12\x16\n" +
+	"\x06amount\x18\v \x01(\x04R\x06amount\x12\x1a\n" +
+	"\bdecimals\x18\f \x01(\rR\bdecimals\x12\x1d\n" +
+	"\n" +
+	"amount_usd\x18\r \x01(\x01R\tamountUsd\"\xac\x03\n" +
+	"\n" +
+	"StakeEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04pool\x18\x02 \x01(\tR\x04pool\x12\x18\n" +
+	"\aprogram\x18\x03 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x04 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x05 \x01(\x05R\n" +
+	"innerIndex\x12#\n" +
+	"\rstake_account\x18\x06 \x01(\fR\fstakeAccount\x12%\n" +
+	"\x0etarget_account\x18\a \x01(\fR\rtargetAccount\x12!\n" +
+	"\fvote_account\x18\b \x01(\fR\vvoteAccount\x12\x1c\n" +
+	"\tauthority\x18\t \x01(\fR\tauthority\x12\x1c\n" +
+	"\trecipient\x18\n" +
+	" \x01(\fR\trecipient\x12\x1a\n" +
+	"\blamports\x18\v \x01(\x04R\blamports\x12\x1b\n" +
+	"\tpool_mint\x18\f \x01(\fR\bpoolMint\x12*\n" +
+	"\x11pool_token_amount\x18\r \x01(\x04R\x0fpoolTokenAmount\"\xa4\x02\n" +
+	"\x10TokenSupplyEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aprogram\x18\x02 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x03 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x04 \x01(\x05R\n" +
+	"innerIndex\x12\x12\n" +
+	"\x04mint\x18\x05 \x01(\fR\x04mint\x12\x18\n" +
+	"\aaccount\x18\x06 \x01(\fR\aaccount\x12\x14\n" +
+	"\x05owner\x18\a \x01(\fR\x05owner\x12\x1c\n" +
+	"\tauthority\x18\b \x01(\fR\tauthority\x12\x16\n" +
+	"\x06amount\x18\t \x01(\x04R\x06amount\x12\x1a\n" +
+	"\bdecimals\x18\n" +
+	" \x01(\rR\bdecimals\"\xbc\x02\n" +
+	"\x0fTokenAdminEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aprogram\x18\x02 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x03 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x04 \x01(\x05R\n" +
+	"innerIndex\x12\x12\n" +
+	"\x04mint\x18\x05 \x01(\fR\x04mint\x12\x18\n" +
+	"\aaccount\x18\x06 \x01(\fR\aaccount\x12\x1c\n" +
+	"\tauthority\x18\a \x01(\fR\tauthority\x12%\n" +
+	"\x0eauthority_type\x18\b \x01(\tR\rauthorityType\x12(\n" +
+	"\rnew_authority\x18\t \x01(\fH\x00R\fnewAuthority\x88\x01\x01B\x10\n" +
+	"\x0e_new_authority\"\x80\x02\n" +
+	"\rComputeBudget\x12\x1d\n" +
+	"\n" +
+	"unit_limit\x18\x01 \x01(\rR\tunitLimit\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x02 \x01(\x04R\tunitPrice\x12(\n" +
+	"\x10heap_frame_bytes\x18\x03 \x01(\rR\x0eheapFrameBytes\x122\n" +
+	"\x15loaded_accounts_limit\x18\x04 \x01(\rR\x13loadedAccountsLimit\x120\n" +
+	"\x14effective_unit_limit\x18\x05 \x01(\rR\x12effectiveUnitLimit\x12!\n" +
+	"\fpriority_fee\x18\x06 \x01(\x04R\vpriorityFee\"^\n" +
+	"\n" +
+	"BundleInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\rR\bposition\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\rR\x04size\x12\x10\n" +
+	"\x03tip\x18\x04 \x01(\x04R\x03tip\"\xa0\x01\n" +
+	"\bMemoInfo\x12\x18\n" +
+	"\aprogram\x18\x01 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x02 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x03 \x01(\x05R\n" +
+	"innerIndex\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\x12\x18\n" +
+	"\asigners\x18\x05 \x03(\fR\asigners\"\xfc\x02\n" +
+	"\x10PoolCreatedEvent\x12\x1a\n" +
+	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x18\n" +
+	"\aprogram\x18\x02 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x03 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x04 \x01(\x05R\n" +
+	"innerIndex\x12\x12\n" +
+	"\x04pool\x18\x05 \x01(\fR\x04pool\x12\x18\n" +
+	"\acreator\x18\x06 \x01(\fR\acreator\x12\x15\n" +
+	"\x06mint_a\x18\a \x01(\fR\x05mintA\x12\x15\n" +
+	"\x06mint_b\x18\b \x01(\fR\x05mintB\x12\x1d\n" +
+	"\n" +
+	"decimals_a\x18\t \x01(\rR\tdecimalsA\x12\x1d\n" +
+	"\n" +
+	"decimals_b\x18\n" +
+	" \x01(\rR\tdecimalsB\x12\x19\n" +
+	"\bamount_a\x18\v \x01(\x04R\aamountA\x12\x19\n" +
+	"\bamount_b\x18\f \x01(\x04R\aamountB\x12\x14\n" +
+	"\x05curve\x18\r \x01(\fR\x05curve\"\xf0\x02\n" +
+	"\fPerpFillInfo\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05venue\x18\x02 \x01(\tR\x05venue\x12\x18\n" +
+	"\aprogram\x18\x03 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x04 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x05 \x01(\x05R\n" +
+	"innerIndex\x12\x16\n" +
+	"\x06trader\x18\x06 \x01(\fR\x06trader\x12\x18\n" +
+	"\aaccount\x18\a \x01(\fR\aaccount\x12\x16\n" +
+	"\x06market\x18\b \x01(\fR\x06market\x12\x14\n" +
+	"\x05asset\x18\t \x01(\tR\x05asset\x12\x12\n" +
+	"\x04side\x18\n" +
+	" \x01(\tR\x04side\x12\x12\n" +
+	"\x04size\x18\v \x01(\x04R\x04size\x12\x14\n" +
+	"\x05price\x18\f \x01(\x04R\x05price\x12\x10\n" +
+	"\x03fee\x18\r \x01(\x04R\x03fee\x12\x1e\n" +
+	"\n" +
+	"liquidator\x18\x0e \x01(\fR\n" +
+	"liquidator\"\xf1\x02\n" +
+	"\x12CompressedNftEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12+\n" +
+	"\x11instruction_index\x18\x02 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x03 \x01(\x05R\n" +
+	"innerIndex\x12\x12\n" +
+	"\x04tree\x18\x04 \x01(\fR\x04tree\x12\x1d\n" +
+	"\n" +
+	"leaf_index\x18\x05 \x01(\x04R\tleafIndex\x12\x19\n" +
+	"\basset_id\x18\x06 \x01(\fR\aassetId\x12\x14\n" +
+	"\x05owner\x18\a \x01(\fR\x05owner\x12\x1a\n" +
+	"\bdelegate\x18\b \x01(\fR\bdelegate\x12\x1b\n" +
+	"\tnew_owner\x18\t \x01(\fR\bnewOwner\x12\x1e\n" +
+	"\n" +
+	"collection\x18\n" +
+	" \x01(\fR\n" +
+	"collection\x12\x12\n" +
+	"\x04name\x18\v \x01(\tR\x04name\x12\x16\n" +
+	"\x06symbol\x18\f \x01(\tR\x06symbol\x12\x10\n" +
+	"\x03uri\x18\r \x01(\tR\x03uri\"\xd1\x02\n" +
+	"\fNftMintEvent\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x18\n" +
+	"\aprogram\x18\x02 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x03 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x04 \x01(\x05R\n" +
+	"innerIndex\x12\x12\n" +
+	"\x04mint\x18\x05 \x01(\fR\x04mint\x12\x16\n" +
+	"\x06minter\x18\x06 \x01(\fR\x06minter\x12\x14\n" +
+	"\x05payer\x18\a \x01(\fR\x05payer\x12\x1e\n" +
+	"\n" +
+	"collection\x18\b \x01(\fR\n" +
+	"collection\x12#\n" +
+	"\rcandy_machine\x18\t \x01(\fR\fcandyMachine\x12\x14\n" +
+	"\x05price\x18\n" +
+	" \x01(\x04R\x05price\x12\x12\n" +
+	"\x04name\x18\v \x01(\tR\x04name\x12\x10\n" +
+	"\x03uri\x18\f \x01(\tR\x03uri\"\xe6\x01\n" +
+	"\vDomainEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12+\n" +
+	"\x11instruction_index\x18\x02 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x03 \x01(\x05R\n" +
+	"innerIndex\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\fR\x06domain\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\fR\x05owner\x12\x1b\n" +
+	"\tnew_owner\x18\a \x01(\fR\bnewOwner\x12\x16\n" +
+	"\x06parent\x18\b \x01(\fR\x06parent\"\x88\x03\n" +
+	"\vBridgeEvent\x12\x1a\n" +
+	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\x12\x18\n" +
+	"\aprogram\x18\x03 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x04 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x05 \x01(\x05R\n" +
+	"innerIndex\x12!\n" +
+	"\fsource_chain\x18\x06 \x01(\tR\vsourceChain\x12+\n" +
+	"\x11destination_chain\x18\a \x01(\tR\x10destinationChain\x12\x16\n" +
+	"\x06wallet\x18\b \x01(\fR\x06wallet\x12'\n" +
+	"\x0fforeign_address\x18\t \x01(\tR\x0eforeignAddress\x12\x12\n" +
+	"\x04mint\x18\n" +
+	" \x01(\fR\x04mint\x12\x16\n" +
+	"\x06amount\x18\v \x01(\x04R\x06amount\x12\x1a\n" +
+	"\bdecimals\x18\f \x01(\rR\bdecimals\"\x81\x02\n" +
+	"\x0fInstructionNode\x12\x18\n" +
+	"\aprogram\x18\x01 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x02 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x03 \x01(\x05R\n" +
+	"innerIndex\x12!\n" +
+	"\fstack_height\x18\x04 \x01(\rR\vstackHeight\x12>\n" +
+	"\bchildren\x18\x05 \x03(\v2\".solana_toolkit.v1.InstructionNodeR\bchildren\x12#\n" +
+	"\rcompute_units\x18\x06 \x01(\x04R\fcomputeUnits\"\xcd\x02\n" +
+	"\x12TransactionFailure\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12+\n" +
+	"\x11instruction_error\x18\x03 \x01(\tR\x10instructionError\x12+\n" +
+	"\x11instruction_index\x18\x04 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x05 \x01(\x05R\n" +
+	"innerIndex\x12\x18\n" +
+	"\aprogram\x18\x06 \x01(\fR\aprogram\x12$\n" +
+	"\vcustom_code\x18\a \x01(\rH\x00R\n" +
+	"customCode\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"error_name\x18\b \x01(\tR\terrorName\x12#\n" +
+	"\rerror_message\x18\t \x01(\tR\ferrorMessageB\x0e\n" +
+	"\f_custom_code\"\xa6\x01\n" +
+	"\n" +
+	"ParseError\x12\x1a\n" +
+	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x18\n" +
+	"\aprogram\x18\x02 \x01(\fR\aprogram\x12+\n" +
+	"\x11instruction_index\x18\x03 \x01(\x05R\x10instructionIndex\x12\x1f\n" +
+	"\vinner_index\x18\x04 \x01(\x05R\n" +
+	"innerIndex\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\x8c\n" +
+	"\n" +
+	"\x11ParsedTransaction\x12\x1c\n" +
+	"\tsignature\x18\x01 \x01(\fR\tsignature\x12\x12\n" +
+	"\x04slot\x18\x02 \x01(\x04R\x04slot\x12\"\n" +
+	"\n" +
+	"block_time\x18\x03 \x01(\x03H\x00R\tblockTime\x88\x01\x01\x12\x10\n" +
+	"\x03fee\x18\x04 \x01(\x04R\x03fee\x121\n" +
+	"\x05swaps\x18\x05 \x03(\v2\x1b.solana_toolkit.v1.SwapInfoR\x05swaps\x12=\n" +
+	"\ttransfers\x18\x06 \x03(\v2\x1f.solana_toolkit.v1.TransferInfoR\ttransfers\x12@\n" +
+	"\fstake_events\x18\a \x03(\v2\x1d.solana_toolkit.v1.StakeEventR\vstakeEvents\x12H\n" +
+	"\rsupply_events\x18\b \x03(\v2#.solana_toolkit.v1.TokenSupplyEventR\fsupplyEvents\x12E\n" +
+	"\fadmin_events\x18\t \x03(\v2\".solana_toolkit.v1.TokenAdminEventR\vadminEvents\x12G\n" +
+	"\x0ecompute_budget\x18\n" +
+	" \x01(\v2 .solana_toolkit.v1.ComputeBudgetR\rcomputeBudget\x121\n" +
+	"\x05memos\x18\v \x03(\v2\x1b.solana_toolkit.v1.MemoInfoR\x05memos\x125\n" +
+	"\x06errors\x18\f \x03(\v2\x1d.solana_toolkit.v1.ParseErrorR\x06errors\x12J\n" +
+	"\x0epool_creations\x18\r \x03(\v2#.solana_toolkit.v1.PoolCreatedEventR\rpoolCreations\x12\x1b\n" +
+	"\tfee_payer\x18\x0e \x01(\fR\bfeePayer\x12\x19\n" +
+	"\bjito_tip\x18\x0f \x01(\x04R\ajitoTip\x125\n" +
+	"\x06bundle\x18\x10 \x01(\v2\x1d.solana_toolkit.v1.BundleInfoR\x06bundle\x12>\n" +
+	"\n" +
+	"perp_fills\x18\x11 \x03(\v2\x1f.solana_toolkit.v1.PerpFillInfoR\tperpFills\x12N\n" +
+	"\x0fcompressed_nfts\x18\x12 \x03(\v2%.solana_toolkit.v1.CompressedNftEventR\x0ecompressedNfts\x12<\n" +
+	"\tnft_mints\x18\x13 \x03(\v2\x1f.solana_toolkit.v1.NftMintEventR\bnftMints\x12C\n" +
+	"\rdomain_events\x18\x14 \x03(\v2\x1e.solana_toolkit.v1.DomainEventR\fdomainEvents\x128\n" +
+	"\abridges\x18\x15 \x03(\v2\x1e.solana_toolkit.v1.BridgeEventR\abridges\x12?\n" +
+	"\tcall_tree\x18\x16 \x03(\v2\".solana_toolkit.v1.InstructionNodeR\bcallTree\x12?\n" +
+	"\afailure\x18\x17 \x01(\v2%.solana_toolkit.v1.TransactionFailureR\afailureB\r\n" +
+	"\v_block_time\"\xb7\x01\n" +
+	"\tSwapEvent\x12\x1c\n" +
+	"\tsignature\x18\x01 \x01(\fR\tsignature\x12\x12\n" +
+	"\x04slot\x18\x02 \x01(\x04R\x04slot\x12\"\n" +
+	"\n" +
+	"block_time\x18\x03 \x01(\x03H\x00R\tblockTime\x88\x01\x01\x12\x14\n" +
+	"\x05index\x18\x04 \x01(\x05R\x05index\x12/\n" +
+	"\x04swap\x18\x05 \x01(\v2\x1b.solana_toolkit.v1.SwapInfoR\x04swapB\r\n" +
+	"\v_block_time\"\x8e\a\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\fR\tsignature\x12\x12\n" +
+	"\x04slot\x18\x03 \x01(\x04R\x04slot\x12\"\n" +
+	"\n" +
+	"block_time\x18\x04 \x01(\x03H\x01R\tblockTime\x88\x01\x01\x12\x14\n" +
+	"\x05index\x18\x05 \x01(\x05R\x05index\x12%\n" +
+	"\x0eschema_version\x18\x06 \x01(\rR\rschemaVersion\x121\n" +
+	"\x04swap\x18\n" +
+	" \x01(\v2\x1b.solana_toolkit.v1.SwapInfoH\x00R\x04swap\x12=\n" +
+	"\btransfer\x18\v \x01(\v2\x1f.solana_toolkit.v1.TransferInfoH\x00R\btransfer\x125\n" +
+	"\x05stake\x18\f \x01(\v2\x1d.solana_toolkit.v1.StakeEventH\x00R\x05stake\x12H\n" +
+	"\ftoken_supply\x18\r \x01(\v2#.solana_toolkit.v1.TokenSupplyEventH\x00R\vtokenSupply\x12E\n" +
+	"\vtoken_admin\x18\x0e \x01(\v2\".solana_toolkit.v1.TokenAdminEventH\x00R\n" +
+	"tokenAdmin\x12H\n" +
+	"\fpool_created\x18\x0f \x01(\v2#.solana_toolkit.v1.PoolCreatedEventH\x00R\vpoolCreated\x12>\n" +
+	"\tperp_fill\x18\x10 \x01(\v2\x1f.solana_toolkit.v1.PerpFillInfoH\x00R\bperpFill\x12N\n" +
+	"\x0ecompressed_nft\x18\x11 \x01(\v2%.solana_toolkit.v1.CompressedNftEventH\x00R\rcompressedNft\x12<\n" +
+	"\bnft_mint\x18\x12 \x01(\v2\x1f.solana_toolkit.v1.NftMintEventH\x00R\anftMint\x128\n" +
+	"\x06domain\x18\x13 \x01(\v2\x1e.solana_toolkit.v1.DomainEventH\x00R\x06domain\x128\n" +
+	"\x06bridge\x18\x14 \x01(\v2\x1e.solana_toolkit.v1.BridgeEventH\x00R\x06bridgeB\t\n" +
+	"\apayloadB\r\n" +
+	"\v_block_time\"e\n" +
+	"\x15SubscribeSwapsRequest\x12\x1c\n" +
+	"\tprotocols\x18\x01 \x03(\tR\tprotocols\x12\x14\n" +
+	"\x05mints\x18\x02 \x03(\fR\x05mints\x12\x18\n" +
+	"\awallets\x18\x03 \x03(\fR\awallets2k\n" +
+	"\rStreamService\x12Z\n" +
+	"\x0eSubscribeSwaps\x12(.solana_toolkit.v1.SubscribeSwapsRequest\x1a\x1c.solana_toolkit.v1.SwapEvent0\x01B-Z+github.com/soralabs/solana-toolkit/go/pb;pbb\x06proto3"
+
+var (
+	file_solana_toolkit_proto_rawDescOnce sync.Once
+	file_solana_toolkit_proto_rawDescData []byte
+)
+
+func file_solana_toolkit_proto_rawDescGZIP() []byte {
+	file_solana_toolkit_proto_rawDescOnce.Do(func() {
+		file_solana_toolkit_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_solana_toolkit_proto_rawDesc), len(file_solana_toolkit_proto_rawDesc)))
+	})
+	return file_solana_toolkit_proto_rawDescData
+}
+
+var file_solana_toolkit_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_solana_toolkit_proto_goTypes = []any{
+	(*TokenInfo)(nil),             // 0: solana_toolkit.v1.TokenInfo
+	(*TokenExtensions)(nil),       // 1: solana_toolkit.v1.TokenExtensions
+	(*SwapInfo)(nil),              // 2: solana_toolkit.v1.SwapInfo
+	(*PriceInfo)(nil),             // 3: solana_toolkit.v1.PriceInfo
+	(*MEVInfo)(nil),               // 4: solana_toolkit.v1.MEVInfo
+	(*TransferInfo)(nil),          // 5: solana_toolkit.v1.TransferInfo
+	(*StakeEvent)(nil),            // 6: solana_toolkit.v1.StakeEvent
+	(*TokenSupplyEvent)(nil),      // 7: solana_toolkit.v1.TokenSupplyEvent
+	(*TokenAdminEvent)(nil),       // 8: solana_toolkit.v1.TokenAdminEvent
+	(*ComputeBudget)(nil),         // 9: solana_toolkit.v1.ComputeBudget
+	(*BundleInfo)(nil),            // 10: solana_toolkit.v1.BundleInfo
+	(*MemoInfo)(nil),              // 11: solana_toolkit.v1.MemoInfo
+	(*PoolCreatedEvent)(nil),      // 12: solana_toolkit.v1.PoolCreatedEvent
+	(*PerpFillInfo)(nil),          // 13: solana_toolkit.v1.PerpFillInfo
+	(*CompressedNftEvent)(nil),    // 14: solana_toolkit.v1.CompressedNftEvent
+	(*NftMintEvent)(nil),          // 15: solana_toolkit.v1.NftMintEvent
+	(*DomainEvent)(nil),           // 16: solana_toolkit.v1.DomainEvent
+	(*BridgeEvent)(nil),           // 17: solana_toolkit.v1.BridgeEvent
+	(*InstructionNode)(nil),       // 18: solana_toolkit.v1.InstructionNode
+	(*TransactionFailure)(nil),    // 19: solana_toolkit.v1.TransactionFailure
+	(*ParseError)(nil),            // 20: solana_toolkit.v1.ParseError
+	(*ParsedTransaction)(nil),     // 21: solana_toolkit.v1.ParsedTransaction
+	(*SwapEvent)(nil),             // 22: solana_toolkit.v1.SwapEvent
+	(*Event)(nil),                 // 23: solana_toolkit.v1.Event
+	(*SubscribeSwapsRequest)(nil), // 24: solana_toolkit.v1.SubscribeSwapsRequest
+	(*timestamppb.Timestamp)(nil), // 25: google.protobuf.Timestamp
+}
+var file_solana_toolkit_proto_depIdxs = []int32{
+	1,  // 0: solana_toolkit.v1.TokenInfo.extensions:type_name -> solana_toolkit.v1.TokenExtensions
+	25, // 1: solana_toolkit.v1.SwapInfo.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 2: solana_toolkit.v1.SwapInfo.token_in:type_name -> solana_toolkit.v1.TokenInfo
+	0,  // 3: solana_toolkit.v1.SwapInfo.token_out:type_name -> solana_toolkit.v1.TokenInfo
+	3,  // 4: solana_toolkit.v1.SwapInfo.price:type_name -> solana_toolkit.v1.PriceInfo
+	4,  // 5: solana_toolkit.v1.SwapInfo.mev:type_name -> solana_toolkit.v1.MEVInfo
+	18, // 6: solana_toolkit.v1.InstructionNode.children:type_name -> solana_toolkit.v1.InstructionNode
+	2,  // 7: solana_toolkit.v1.ParsedTransaction.swaps:type_name -> solana_toolkit.v1.SwapInfo
+	5,  // 8: solana_toolkit.v1.ParsedTransaction.transfers:type_name -> solana_toolkit.v1.TransferInfo
+	6,  // 9: solana_toolkit.v1.ParsedTransaction.stake_events:type_name -> solana_toolkit.v1.StakeEvent
+	7,  // 10: solana_toolkit.v1.ParsedTransaction.supply_events:type_name -> solana_toolkit.v1.TokenSupplyEvent
+	8,  // 11: solana_toolkit.v1.ParsedTransaction.admin_events:type_name -> solana_toolkit.v1.TokenAdminEvent
+	9,  // 12: solana_toolkit.v1.ParsedTransaction.compute_budget:type_name -> solana_toolkit.v1.ComputeBudget
+	11, // 13: solana_toolkit.v1.ParsedTransaction.memos:type_name -> solana_toolkit.v1.MemoInfo
+	20, // 14: solana_toolkit.v1.ParsedTransaction.errors:type_name -> solana_toolkit.v1.ParseError
+	12, // 15: solana_toolkit.v1.ParsedTransaction.pool_creations:type_name -> solana_toolkit.v1.PoolCreatedEvent
+	10, // 16: solana_toolkit.v1.ParsedTransaction.bundle:type_name -> solana_toolkit.v1.BundleInfo
+	13, // 17: solana_toolkit.v1.ParsedTransaction.perp_fills:type_name -> solana_toolkit.v1.PerpFillInfo
+	14, // 18: solana_toolkit.v1.ParsedTransaction.compressed_nfts:type_name -> solana_toolkit.v1.CompressedNftEvent
+	15, // 19: solana_toolkit.v1.ParsedTransaction.nft_mints:type_name -> solana_toolkit.v1.NftMintEvent
+	16, // 20: solana_toolkit.v1.ParsedTransaction.domain_events:type_name -> solana_toolkit.v1.DomainEvent
+	17, // 21: solana_toolkit.v1.ParsedTransaction.bridges:type_name -> solana_toolkit.v1.BridgeEvent
+	18, // 22: solana_toolkit.v1.ParsedTransaction.call_tree:type_name -> solana_toolkit.v1.InstructionNode
+	19, // 23: solana_toolkit.v1.ParsedTransaction.failure:type_name -> solana_toolkit.v1.TransactionFailure
+	2,  // 24: solana_toolkit.v1.SwapEvent.swap:type_name -> solana_toolkit.v1.SwapInfo
+	2,  // 25: solana_toolkit.v1.Event.swap:type_name -> solana_toolkit.v1.SwapInfo
+	5,  // 26: solana_toolkit.v1.Event.transfer:type_name -> solana_toolkit.v1.TransferInfo
+	6,  // 27: solana_toolkit.v1.Event.stake:type_name -> solana_toolkit.v1.StakeEvent
+	7,  // 28: solana_toolkit.v1.Event.token_supply:type_name -> solana_toolkit.v1.TokenSupplyEvent
+	8,  // 29: solana_toolkit.v1.Event.token_admin:type_name -> solana_toolkit.v1.TokenAdminEvent
+	12, // 30: solana_toolkit.v1.Event.pool_created:type_name -> solana_toolkit.v1.PoolCreatedEvent
+	13, // 31: solana_toolkit.v1.Event.perp_fill:type_name -> solana_toolkit.v1.PerpFillInfo
+	14, // 32: solana_toolkit.v1.Event.compressed_nft:type_name -> solana_toolkit.v1.CompressedNftEvent
+	15, // 33: solana_toolkit.v1.Event.nft_mint:type_name -> solana_toolkit.v1.NftMintEvent
+	16, // 34: solana_toolkit.v1.Event.domain:type_name -> solana_toolkit.v1.DomainEvent
+	17, // 35: solana_toolkit.v1.Event.bridge:type_name -> solana_toolkit.v1.BridgeEvent
+	24, // 36: solana_toolkit.v1.StreamService.SubscribeSwaps:input_type -> solana_toolkit.v1.SubscribeSwapsRequest
+	22, // 37: solana_toolkit.v1.StreamService.SubscribeSwaps:output_type -> solana_toolkit.v1.SwapEvent
+	37, // [37:38] is the sub-list for method output_type
+	36, // [36:37] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
+}
+
+func init() { file_solana_toolkit_proto_init() }
+func file_solana_toolkit_proto_init() {
+	if File_solana_toolkit_proto != nil {
+		return
+	}
+	file_solana_toolkit_proto_msgTypes[8].OneofWrappers = []any{}
+	file_solana_toolkit_proto_msgTypes[19].OneofWrappers = []any{}
+	file_solana_toolkit_proto_msgTypes[21].OneofWrappers = []any{}
+	file_solana_toolkit_proto_msgTypes[22].OneofWrappers = []any{}
+	file_solana_toolkit_proto_msgTypes[23].OneofWrappers = []any{
+		(*Event_Swap)(nil),
+		(*Event_Transfer)(nil),
+		(*Event_Stake)(nil),
+		(*Event_TokenSupply)(nil),
+		(*Event_TokenAdmin)(nil),
+		(*Event_PoolCreated)(nil),
+		(*Event_PerpFill)(nil),
+		(*Event_CompressedNft)(nil),
+		(*Event_NftMint)(nil),
+		(*Event_Domain)(nil),
+		(*Event_Bridge)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_solana_toolkit_proto_rawDesc), len(file_solana_toolkit_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_solana_toolkit_proto_goTypes,
+		DependencyIndexes: file_solana_toolkit_proto_depIdxs,
+		MessageInfos:      file_solana_toolkit_proto_msgTypes,
+	}.Build()
+	File_solana_toolkit_proto = out.File
+	file_solana_toolkit_proto_goTypes = nil
+	file_solana_toolkit_proto_depIdxs = nil
+}
@@ -0,0 +1,378 @@
+package pnl
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+)
+
+// Tracker computes a wallet's PnL from its parsed transactions. Swaps signed by the wallet
+// buy and sell positions, token transfers in and out of transactions without a swap add
+// lots at the market price or remove them at cost. It is safe for concurrent use.
+type Tracker struct {
+	wallet solana.PublicKey
+	engine *pricing.Engine
+	config Config
+	quote  map[solana.PublicKey]bool
+	now    func() time.Time
+
+	mu        sync.Mutex
+	seen      map[solana.Signature]bool
+	positions map[solana.PublicKey]*position
+	disposals []*Disposal
+	feesSOL   float64
+	feesUSD   float64
+	tipsSOL   float64
+	tipsUSD   float64
+}
+
+// position is a Position with its open lots, oldest first. Average cost positions keep a
+// single lot.
+type position struct {
+	Position
+	lots []lot
+}
+
+type lot struct {
+	amount   uint64
+	usd, sol float64
+	acquired time.Time // earliest acquisition of the tokens merged into the lot
+}
+
+// New creates a tracker for the wallet, valuing trades with the pricing engine
+func New(wallet solana.PublicKey, engine *pricing.Engine, config Config) *Tracker {
+	if config.Method == "" {
+		config.Method = MethodFIFO
+	}
+	if len(config.QuoteMints) == 0 {
+		config.QuoteMints = []solana.PublicKey{tx_parser.NATIVE_SOL_PROGRAM_ID, pricing.USDC_MINT, pricing.USDT_MINT}
+	}
+
+	quote := make(map[solana.PublicKey]bool, len(config.QuoteMints))
+	for _, mint := range config.QuoteMints {
+		quote[mint] = true
+	}
+
+	return &Tracker{
+		wallet:    wallet,
+		engine:    engine,
+		config:    config,
+		quote:     quote,
+		now:       time.Now,
+		seen:      make(map[solana.Signature]bool),
+		positions: make(map[solana.PublicKey]*position),
+	}
+}
+
+// Add applies a transaction to the wallet's positions. Transactions are expected in
+// chronological order, a signature added twice is ignored.
+func (t *Tracker) Add(tx *tx_parser.ParsedTransaction) {
+	at := t.now()
+	if tx.BlockTime != nil {
+		at = tx.BlockTime.Time()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.seen[tx.Signature] {
+		return
+	}
+	t.seen[tx.Signature] = true
+
+	swapped := false
+	for _, swap := range tx.Swaps {
+		// every swap is market data, only the wallet's own swaps move its positions
+		if swap.Price == nil {
+			swapAt := at
+			if !swap.Timestamp.IsZero() {
+				swapAt = swap.Timestamp
+			}
+			t.engine.PriceSwap(swap, swapAt)
+		}
+		if !slices.ContainsFunc(swap.Signers, t.wallet.Equals) {
+			continue
+		}
+		swapped = true
+
+		usd, sol := t.swapValue(swap)
+		t.sell(tx.Signature, at, swap.TokenIn, usd, sol)
+		t.buy(at, swap.TokenOut, usd, sol)
+	}
+
+	solUSD, _ := t.engine.USDPrice(tx_parser.NATIVE_SOL_PROGRAM_ID)
+	if tx.FeePayer.Equals(t.wallet) {
+		fee := lamportsToSOL(tx.Fee)
+		t.feesSOL += fee
+		t.feesUSD += fee * solUSD
+	}
+
+	for _, transfer := range tx.Transfers {
+		switch transfer.Type {
+		case tx_parser.TransferTypeSOL:
+			if transfer.Source.Equals(t.wallet) && tx_parser.IsJitoTipAccount(transfer.Destination) {
+				tip := lamportsToSOL(transfer.Amount)
+				t.tipsSOL += tip
+				t.tipsUSD += tip * solUSD
+			}
+		case tx_parser.TransferTypeToken:
+			// transfers inside a swap are its legs, already counted
+			if swapped {
+				continue
+			}
+			t.transfer(at, transfer, solUSD)
+		}
+	}
+}
+
+// Position returns the wallet's position in a mint
+func (t *Tracker) Position(mint solana.PublicKey) (*Position, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pos, ok := t.positions[mint]
+	if !ok {
+		return nil, false
+	}
+	return t.value(pos), true
+}
+
+// Disposals returns every sale matched against the lots it consumed, in the order they
+// happened
+func (t *Tracker) Disposals() []*Disposal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	disposals := make([]*Disposal, len(t.disposals))
+	for i, d := range t.disposals {
+		copied := *d
+		disposals[i] = &copied
+	}
+	return disposals
+}
+
+// Summary returns every position and the wallet's totals, with unrealized PnL valued at
+// the engine's latest prices
+func (t *Tracker) Summary() *Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summary := &Summary{
+		Wallet:    t.wallet,
+		Positions: make([]*Position, 0, len(t.positions)),
+		FeesSOL:   t.feesSOL,
+		FeesUSD:   t.feesUSD,
+		TipsSOL:   t.tipsSOL,
+		TipsUSD:   t.tipsUSD,
+	}
+	for _, pos := range t.positions {
+		valued := t.value(pos)
+		summary.Positions = append(summary.Positions, valued)
+		summary.RealizedUSD += valued.RealizedUSD
+		summary.RealizedSOL += valued.RealizedSOL
+		summary.UnrealizedUSD += valued.UnrealizedUSD
+		summary.UnrealizedSOL += valued.UnrealizedSOL
+	}
+	slices.SortFunc(summary.Positions, func(a, b *Position) int {
+		return bytes.Compare(a.Mint[:], b.Mint[:])
+	})
+
+	summary.NetUSD = summary.RealizedUSD + summary.UnrealizedUSD - summary.FeesUSD - summary.TipsUSD
+	summary.NetSOL = summary.RealizedSOL + summary.UnrealizedSOL - summary.FeesSOL - summary.TipsSOL
+	return summary
+}
+
+// swapValue returns the USD and SOL value of a swap, 0 when unknown
+func (t *Tracker) swapValue(swap *tx_parser.SwapInfo) (float64, float64) {
+	var usd, sol float64
+	switch {
+	case swap.TokenIn.Mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		sol = uiAmount(swap.TokenIn.Amount, swap.TokenIn.Decimals)
+	case swap.TokenOut.Mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		sol = uiAmount(swap.TokenOut.Amount, swap.TokenOut.Decimals)
+	}
+
+	if price := swap.Price; price != nil {
+		usd = price.VolumeUSD
+		if sol == 0 && price.PriceSOL > 0 {
+			base := swap.TokenIn
+			if swap.TokenOut.Mint.Equals(price.BaseMint) {
+				base = swap.TokenOut
+			}
+			sol = price.PriceSOL * uiAmount(base.Amount, base.Decimals)
+		}
+	}
+	return usd, sol
+}
+
+// transfer adds tokens received at the market price and removes tokens sent at cost
+func (t *Tracker) transfer(at time.Time, transfer *tx_parser.TransferInfo, solUSD float64) {
+	if t.quote[transfer.Mint] {
+		return
+	}
+	in := transfer.DestinationOwner.Equals(t.wallet) && !transfer.SourceOwner.Equals(t.wallet)
+	out := transfer.SourceOwner.Equals(t.wallet) && !transfer.DestinationOwner.Equals(t.wallet)
+
+	switch {
+	case in:
+		price, _ := t.engine.USDPrice(transfer.Mint)
+		usd := price * uiAmount(transfer.Amount, transfer.Decimals)
+		var sol float64
+		if solUSD > 0 {
+			sol = usd / solUSD
+		}
+		pos := t.position(transfer.Mint, transfer.Decimals)
+		t.add(pos, lot{amount: transfer.Amount, usd: usd, sol: sol, acquired: at})
+	case out:
+		if pos, ok := t.positions[transfer.Mint]; ok {
+			t.take(pos, transfer.Amount)
+		}
+	}
+}
+
+// buy opens a lot for the tokens received in a swap
+func (t *Tracker) buy(at time.Time, info tx_parser.TokenInfo, usd, sol float64) {
+	if t.quote[info.Mint] || info.Amount == 0 {
+		return
+	}
+	pos := t.position(info.Mint, info.Decimals)
+	t.add(pos, lot{amount: info.Amount, usd: usd, sol: sol, acquired: at})
+	pos.Buys++
+}
+
+// sell realizes the proceeds of the tokens sold in a swap against their cost, recording a
+// disposal per lot consumed. Tokens the tracker has not seen bought are realized at zero
+// cost.
+func (t *Tracker) sell(signature solana.Signature, at time.Time, info tx_parser.TokenInfo, usd, sol float64) {
+	if t.quote[info.Mint] || info.Amount == 0 {
+		return
+	}
+	pos := t.position(info.Mint, info.Decimals)
+	costUSD, costSOL, consumed := t.take(pos, info.Amount)
+	pos.RealizedUSD += usd - costUSD
+	pos.RealizedSOL += sol - costSOL
+	pos.Sells++
+
+	var matched uint64
+	for _, l := range consumed {
+		matched += l.amount
+	}
+	if matched < info.Amount {
+		consumed = append(consumed, lot{amount: info.Amount - matched})
+	}
+	for _, l := range consumed {
+		share := float64(l.amount) / float64(info.Amount)
+		t.disposals = append(t.disposals, &Disposal{
+			Signature:    signature,
+			Mint:         info.Mint,
+			Amount:       l.amount,
+			Decimals:     info.Decimals,
+			Acquired:     l.acquired,
+			Sold:         at,
+			ProceedsUSD:  usd * share,
+			CostBasisUSD: l.usd,
+			GainUSD:      usd*share - l.usd,
+			ProceedsSOL:  sol * share,
+			CostBasisSOL: l.sol,
+			GainSOL:      sol*share - l.sol,
+		})
+	}
+}
+
+func (t *Tracker) position(mint solana.PublicKey, decimals uint8) *position {
+	pos, ok := t.positions[mint]
+	if !ok {
+		pos = &position{Position: Position{Mint: mint, Decimals: decimals}}
+		t.positions[mint] = pos
+	}
+	return pos
+}
+
+// add appends a lot, merging it into the single lot of average cost positions
+func (t *Tracker) add(pos *position, l lot) {
+	pos.Amount += l.amount
+	pos.CostBasisUSD += l.usd
+	pos.CostBasisSOL += l.sol
+
+	if t.config.Method == MethodAverageCost && len(pos.lots) > 0 {
+		pos.lots[0].amount += l.amount
+		pos.lots[0].usd += l.usd
+		pos.lots[0].sol += l.sol
+		if l.acquired.Before(pos.lots[0].acquired) {
+			pos.lots[0].acquired = l.acquired
+		}
+		return
+	}
+	pos.lots = append(pos.lots, l)
+}
+
+// take removes up to amount tokens from the oldest lots and returns their cost and the
+// parts of the lots taken
+func (t *Tracker) take(pos *position, amount uint64) (float64, float64, []lot) {
+	var costUSD, costSOL float64
+	var consumed []lot
+	for amount > 0 && len(pos.lots) > 0 {
+		l := &pos.lots[0]
+		if amount < l.amount {
+			share := float64(amount) / float64(l.amount)
+			usd, sol := l.usd*share, l.sol*share
+			l.amount -= amount
+			l.usd -= usd
+			l.sol -= sol
+			costUSD += usd
+			costSOL += sol
+			pos.Amount -= amount
+			consumed = append(consumed, lot{amount: amount, usd: usd, sol: sol, acquired: l.acquired})
+			break
+		}
+
+		amount -= l.amount
+		costUSD += l.usd
+		costSOL += l.sol
+		pos.Amount -= l.amount
+		consumed = append(consumed, *l)
+		pos.lots = pos.lots[1:]
+	}
+
+	pos.CostBasisUSD -= costUSD
+	pos.CostBasisSOL -= costSOL
+	if len(pos.lots) == 0 {
+		pos.CostBasisUSD, pos.CostBasisSOL = 0, 0
+	}
+	return costUSD, costSOL, consumed
+}
+
+// value copies a position and values its open amount at the engine's latest price
+func (t *Tracker) value(pos *position) *Position {
+	valued := pos.Position
+	if valued.Amount == 0 {
+		return &valued
+	}
+
+	price, ok := t.engine.USDPrice(valued.Mint)
+	if !ok {
+		return &valued
+	}
+	usd := price * uiAmount(valued.Amount, valued.Decimals)
+	valued.UnrealizedUSD = usd - valued.CostBasisUSD
+	if solUSD, ok := t.engine.USDPrice(tx_parser.NATIVE_SOL_PROGRAM_ID); ok {
+		valued.UnrealizedSOL = usd/solUSD - valued.CostBasisSOL
+	}
+	return &valued
+}
+
+// uiAmount converts a raw token amount to whole tokens
+func uiAmount(amount uint64, decimals uint8) float64 {
+	return float64(amount) / math.Pow10(int(decimals))
+}
+
+func lamportsToSOL(lamports uint64) float64 {
+	return float64(lamports) / float64(solana.LAMPORTS_PER_SOL)
+}
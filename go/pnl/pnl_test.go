@@ -0,0 +1,168 @@
+package pnl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+)
+
+var (
+	sol    = tx_parser.NATIVE_SOL_PROGRAM_ID
+	wallet = solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	token  = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+	other  = solana.MustPublicKeyFromBase58("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263")
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+func swapTx(signature byte, signer solana.PublicKey, mintIn solana.PublicKey, amountIn uint64, decimalsIn uint8, mintOut solana.PublicKey, amountOut uint64, decimalsOut uint8) *tx_parser.ParsedTransaction {
+	blockTime := solana.UnixTimeSeconds(1_700_000_000 + int64(signature))
+	return &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{signature},
+		BlockTime: &blockTime,
+		FeePayer:  signer,
+		Fee:       5000,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{signer},
+			TokenIn:  tx_parser.TokenInfo{Mint: mintIn, Amount: amountIn, Decimals: decimalsIn},
+			TokenOut: tx_parser.TokenInfo{Mint: mintOut, Amount: amountOut, Decimals: decimalsOut},
+		}},
+	}
+}
+
+// trade buys 1000 tokens for 1 SOL, 1000 for 3 SOL and sells 1500 for 3 SOL with SOL at $150
+func trade(t *testing.T, method Method) *Tracker {
+	t.Helper()
+	engine := pricing.New(pricing.Config{})
+	engine.SetUSDPrice(sol, 150)
+	tracker := New(wallet, engine, Config{Method: method})
+
+	first := swapTx(1, wallet, sol, 1_000_000_000, 9, token, 1_000_000_000, 6)
+	first.Transfers = []*tx_parser.TransferInfo{
+		{Type: tx_parser.TransferTypeSOL, Source: wallet, Destination: tx_parser.JITO_TIP_ACCOUNTS[0], Amount: 1_000_000},
+	}
+	tracker.Add(first)
+	tracker.Add(first)
+	tracker.Add(swapTx(2, wallet, sol, 3_000_000_000, 9, token, 1_000_000_000, 6))
+	tracker.Add(swapTx(3, wallet, token, 1_500_000_000, 6, sol, 3_000_000_000, 9))
+
+	// another wallet's swap moves the price but not the positions
+	tracker.Add(swapTx(4, other, sol, 4_000_000_000, 9, token, 1_000_000_000, 6))
+	return tracker
+}
+
+func TestFIFO(t *testing.T) {
+	tracker := trade(t, MethodFIFO)
+
+	pos, ok := tracker.Position(token)
+	if !ok {
+		t.Fatal("expected a position")
+	}
+	if pos.Amount != 500_000_000 || pos.Buys != 2 || pos.Sells != 1 {
+		t.Errorf("unexpected position %+v", pos)
+	}
+	// the sale consumes the first lot and half of the second: cost $375 / 2.5 SOL
+	if !near(pos.RealizedUSD, 75) || !near(pos.RealizedSOL, 0.5) {
+		t.Errorf("expected $75 / 0.5 SOL realized, got %v / %v", pos.RealizedUSD, pos.RealizedSOL)
+	}
+	if !near(pos.CostBasisUSD, 225) || !near(pos.CostBasisSOL, 1.5) {
+		t.Errorf("expected $225 / 1.5 SOL cost left, got %v / %v", pos.CostBasisUSD, pos.CostBasisSOL)
+	}
+	// the last swap prices the token at 0.004 SOL, 500 tokens are worth $300
+	if !near(pos.UnrealizedUSD, 75) || !near(pos.UnrealizedSOL, 0.5) {
+		t.Errorf("expected $75 / 0.5 SOL unrealized, got %v / %v", pos.UnrealizedUSD, pos.UnrealizedSOL)
+	}
+
+	summary := tracker.Summary()
+	if !near(summary.FeesSOL, 0.000015) || !near(summary.TipsSOL, 0.001) || !near(summary.TipsUSD, 0.15) {
+		t.Errorf("expected fees of three transactions and one tip, got %+v", summary)
+	}
+	if !near(summary.NetUSD, 150-summary.FeesUSD-summary.TipsUSD) {
+		t.Errorf("unexpected net PnL %v", summary.NetUSD)
+	}
+}
+
+func TestAverageCost(t *testing.T) {
+	tracker := trade(t, MethodAverageCost)
+
+	pos, _ := tracker.Position(token)
+	// 2000 tokens cost $600, the 1500 sold cost $450 at the average price
+	if pos.Amount != 500_000_000 || !near(pos.RealizedUSD, 0) || !near(pos.RealizedSOL, 0) {
+		t.Errorf("expected nothing realized at the average cost, got %+v", pos)
+	}
+	if !near(pos.CostBasisUSD, 150) || !near(pos.UnrealizedUSD, 150) {
+		t.Errorf("expected $150 cost and $150 unrealized, got %+v", pos)
+	}
+}
+
+func TestTransfers(t *testing.T) {
+	engine := pricing.New(pricing.Config{})
+	engine.SetUSDPrice(sol, 100)
+	engine.SetUSDPrice(other, 2)
+	tracker := New(wallet, engine, Config{})
+
+	sender := solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	tracker.Add(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{1},
+		FeePayer:  sender,
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeToken, Mint: other, SourceOwner: sender, DestinationOwner: wallet, Amount: 100_000_000, Decimals: 6},
+		},
+	})
+	tracker.Add(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{2},
+		FeePayer:  wallet,
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeToken, Mint: other, SourceOwner: wallet, DestinationOwner: sender, Amount: 40_000_000, Decimals: 6},
+		},
+	})
+
+	pos, ok := tracker.Position(other)
+	if !ok || pos.Amount != 60_000_000 || pos.Buys != 0 || pos.Sells != 0 {
+		t.Fatalf("unexpected position %+v", pos)
+	}
+	if !near(pos.CostBasisUSD, 120) || !near(pos.CostBasisSOL, 1.2) || pos.RealizedUSD != 0 {
+		t.Errorf("expected received tokens at the market price and sent tokens removed at cost, got %+v", pos)
+	}
+
+	summary := tracker.Summary()
+	if len(summary.Positions) != 1 || !near(summary.FeesSOL, 0) {
+		t.Errorf("expected only the wallet's own fees, got %+v", summary)
+	}
+}
+
+func TestDisposals(t *testing.T) {
+	disposals := trade(t, MethodFIFO).Disposals()
+
+	// the sale splits across the first lot and half of the second
+	if len(disposals) != 2 {
+		t.Fatalf("expected 2 disposals, got %d", len(disposals))
+	}
+	first, second := disposals[0], disposals[1]
+	if first.Amount != 1_000_000_000 || first.Acquired.Unix() != 1_700_000_001 || first.Sold.Unix() != 1_700_000_003 {
+		t.Errorf("unexpected first disposal %+v", first)
+	}
+	if !near(first.ProceedsUSD, 300) || !near(first.CostBasisUSD, 150) || !near(first.GainUSD, 150) || !near(first.GainSOL, 1) {
+		t.Errorf("expected $300 proceeds on $150 cost, got %+v", first)
+	}
+	if second.Amount != 500_000_000 || second.Acquired.Unix() != 1_700_000_002 || !near(second.GainUSD, -75) || !near(second.CostBasisSOL, 1.5) {
+		t.Errorf("expected a $75 loss on half of the second lot, got %+v", second)
+	}
+
+	// selling tokens never bought realizes them at zero cost with an unknown acquisition
+	engine := pricing.New(pricing.Config{})
+	engine.SetUSDPrice(sol, 100)
+	tracker := New(wallet, engine, Config{Method: MethodAverageCost})
+	tracker.Add(swapTx(1, wallet, token, 1_000_000, 6, sol, 1_000_000_000, 9))
+	disposals = tracker.Disposals()
+	if len(disposals) != 1 || !disposals[0].Acquired.IsZero() || disposals[0].CostBasisUSD != 0 || !near(disposals[0].ProceedsSOL, 1) {
+		t.Errorf("unexpected disposal without lots %+v", disposals[0])
+	}
+}
@@ -0,0 +1,73 @@
+package pnl
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Method is the cost basis accounting method
+type Method string
+
+const (
+	MethodFIFO        Method = "fifo"    // sells consume the oldest lots first
+	MethodAverageCost Method = "average" // sells are costed at the average price of the position
+)
+
+// Config controls the PnL tracker
+type Config struct {
+	// Method is the cost basis method, defaults to FIFO
+	Method Method
+
+	// QuoteMints are treated as cash rather than positions, defaults to SOL, USDC and USDT
+	QuoteMints []solana.PublicKey
+}
+
+// Position is the wallet's PnL in one token. USD values use the pricing engine's prices at
+// the time of each trade, SOL values the SOL leg of the trade or the token's SOL price.
+type Position struct {
+	Mint          solana.PublicKey `json:"mint"`
+	Amount        uint64           `json:"amount,string"` // raw amount still held
+	Decimals      uint8            `json:"decimals"`
+	CostBasisUSD  float64          `json:"cost_basis_usd"`
+	CostBasisSOL  float64          `json:"cost_basis_sol"`
+	RealizedUSD   float64          `json:"realized_usd"`
+	RealizedSOL   float64          `json:"realized_sol"`
+	UnrealizedUSD float64          `json:"unrealized_usd"` // 0 if the token has no price
+	UnrealizedSOL float64          `json:"unrealized_sol"`
+	Buys          int              `json:"buys"`
+	Sells         int              `json:"sells"`
+}
+
+// Summary is the wallet's PnL across every position, net of fees and tips
+type Summary struct {
+	Wallet        solana.PublicKey `json:"wallet"`
+	Positions     []*Position      `json:"positions"`
+	RealizedUSD   float64          `json:"realized_usd"`
+	RealizedSOL   float64          `json:"realized_sol"`
+	UnrealizedUSD float64          `json:"unrealized_usd"`
+	UnrealizedSOL float64          `json:"unrealized_sol"`
+	FeesSOL       float64          `json:"fees_sol"` // transaction fees paid by the wallet
+	FeesUSD       float64          `json:"fees_usd"`
+	TipsSOL       float64          `json:"tips_sol"` // Jito tips paid by the wallet
+	TipsUSD       float64          `json:"tips_usd"`
+	NetUSD        float64          `json:"net_usd"` // realized + unrealized - fees - tips
+	NetSOL        float64          `json:"net_sol"`
+}
+
+// Disposal is part of a sale matched against one lot it consumed, the unit of tax lot
+// reports. Tokens sold beyond the known lots form a disposal with a zero Acquired and cost.
+type Disposal struct {
+	Signature    solana.Signature `json:"signature"`
+	Mint         solana.PublicKey `json:"mint"`
+	Amount       uint64           `json:"amount,string"` // raw amount sold from the lot
+	Decimals     uint8            `json:"decimals"`
+	Acquired     time.Time        `json:"acquired,omitzero"`
+	Sold         time.Time        `json:"sold"`
+	ProceedsUSD  float64          `json:"proceeds_usd"` // share of the sale's value
+	CostBasisUSD float64          `json:"cost_basis_usd"`
+	GainUSD      float64          `json:"gain_usd"`
+	ProceedsSOL  float64          `json:"proceeds_sol"`
+	CostBasisSOL float64          `json:"cost_basis_sol"`
+	GainSOL      float64          `json:"gain_sol"`
+}
@@ -0,0 +1,13 @@
+package pools
+
+// PUMP_FUN_FEE_BPS is the bonding curve trading fee, charged on the SOL side
+const PUMP_FUN_FEE_BPS = 100
+
+const (
+	// RAYDIUM_AMM_ACCOUNT_LENGTH is the size of a Raydium AMM v4 AmmInfo account
+	RAYDIUM_AMM_ACCOUNT_LENGTH = 752
+
+	// DLMM_MAX_FEE_RATE caps the Meteora DLMM fee, in units of DLMM_FEE_PRECISION
+	DLMM_MAX_FEE_RATE  = 100_000_000
+	DLMM_FEE_PRECISION = 1_000_000_000
+)
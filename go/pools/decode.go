@@ -0,0 +1,181 @@
+package pools
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	clmmPoolDiscriminator   = accountDiscriminator("PoolState")
+	clmmConfigDiscriminator = accountDiscriminator("AmmConfig")
+	whirlpoolDiscriminator  = accountDiscriminator("Whirlpool")
+	lbPairDiscriminator     = accountDiscriminator("LbPair")
+)
+
+// accountDiscriminator returns the Anchor discriminator of an account type
+func accountDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("account:" + name))
+	return sum[:8]
+}
+
+// Decode decodes a pool account by its owner program. Reserves, and the fee of Raydium
+// CLMM pools, live in other accounts and are left empty; Reader.Load fills them in.
+func Decode(address, owner solana.PublicKey, data []byte) (PoolState, error) {
+	switch {
+	case owner.Equals(tx_parser.RAYDIUM_V4_PROGRAM_ID):
+		return DecodeRaydiumAMM(address, data)
+	case owner.Equals(tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID):
+		return DecodeRaydiumCLMM(address, data)
+	case owner.Equals(tx_parser.ORCA_PROGRAM_ID):
+		return DecodeWhirlpool(address, data)
+	case owner.Equals(tx_parser.METEORA_PROGRAM_ID):
+		return DecodeDLMM(address, data)
+	}
+	return nil, fmt.Errorf("account %s is owned by %s, not a supported pool program", address, owner)
+}
+
+// DecodeRaydiumAMM decodes a Raydium AMM v4 AmmInfo account
+func DecodeRaydiumAMM(address solana.PublicKey, data []byte) (*RaydiumAMM, error) {
+	if len(data) != RAYDIUM_AMM_ACCOUNT_LENGTH {
+		return nil, fmt.Errorf("account %s is not a Raydium AMM pool", address)
+	}
+	return &RaydiumAMM{
+		Pool:            address,
+		CoinDecimals:    uint8(u64(data, 32)),
+		PCDecimals:      uint8(u64(data, 40)),
+		FeeNumerator:    u64(data, 176),
+		FeeDenominator:  u64(data, 184),
+		NeedTakePnLCoin: u64(data, 192),
+		NeedTakePnLPC:   u64(data, 200),
+		CoinVault:       publicKey(data, 336),
+		PCVault:         publicKey(data, 368),
+		CoinMint:        publicKey(data, 400),
+		PCMint:          publicKey(data, 432),
+		LPMint:          publicKey(data, 464),
+		OpenOrders:      publicKey(data, 496),
+		Market:          publicKey(data, 528),
+		MarketProgram:   publicKey(data, 560),
+		TargetOrders:    publicKey(data, 592),
+		LPAmount:        u64(data, 720),
+	}, nil
+}
+
+// DecodeRaydiumCLMM decodes a Raydium CLMM PoolState account
+func DecodeRaydiumCLMM(address solana.PublicKey, data []byte) (*ConcentratedPool, error) {
+	if len(data) < 273 || !bytes.Equal(data[:8], clmmPoolDiscriminator) {
+		return nil, fmt.Errorf("account %s is not a Raydium CLMM pool", address)
+	}
+	return &ConcentratedPool{
+		SwapType:     tx_parser.SwapTypeRaydium,
+		Pool:         address,
+		AMMConfig:    publicKey(data, 9),
+		Observation:  publicKey(data, 201),
+		MintA:        publicKey(data, 73),
+		MintB:        publicKey(data, 105),
+		VaultA:       publicKey(data, 137),
+		VaultB:       publicKey(data, 169),
+		TickSpacing:  binary.LittleEndian.Uint16(data[235:]),
+		Liquidity:    u128(data, 237),
+		SqrtPriceX64: u128(data, 253),
+		TickCurrent:  int32(binary.LittleEndian.Uint32(data[269:])),
+	}, nil
+}
+
+// decodeCLMMFeeRate returns the trade fee of a Raydium CLMM AmmConfig account
+func decodeCLMMFeeRate(address solana.PublicKey, data []byte) (uint32, error) {
+	if len(data) < 51 || !bytes.Equal(data[:8], clmmConfigDiscriminator) {
+		return 0, fmt.Errorf("account %s is not a Raydium CLMM config", address)
+	}
+	return binary.LittleEndian.Uint32(data[47:]), nil
+}
+
+// DecodeWhirlpool decodes an Orca Whirlpool account
+func DecodeWhirlpool(address solana.PublicKey, data []byte) (*ConcentratedPool, error) {
+	if len(data) < 245 || !bytes.Equal(data[:8], whirlpoolDiscriminator) {
+		return nil, fmt.Errorf("account %s is not an Orca Whirlpool", address)
+	}
+	return &ConcentratedPool{
+		SwapType:     tx_parser.SwapTypeOrca,
+		Pool:         address,
+		TickSpacing:  binary.LittleEndian.Uint16(data[41:]),
+		FeeRateE6:    uint32(binary.LittleEndian.Uint16(data[45:])),
+		Liquidity:    u128(data, 49),
+		SqrtPriceX64: u128(data, 65),
+		TickCurrent:  int32(binary.LittleEndian.Uint32(data[81:])),
+		MintA:        publicKey(data, 101),
+		VaultA:       publicKey(data, 133),
+		MintB:        publicKey(data, 181),
+		VaultB:       publicKey(data, 213),
+	}, nil
+}
+
+// DecodeDLMM decodes a Meteora DLMM LbPair account
+func DecodeDLMM(address solana.PublicKey, data []byte) (*DLMMPool, error) {
+	if len(data) < 216 || !bytes.Equal(data[:8], lbPairDiscriminator) {
+		return nil, fmt.Errorf("account %s is not a Meteora DLMM pair", address)
+	}
+	return &DLMMPool{
+		Pair:                  address,
+		BaseFactor:            binary.LittleEndian.Uint16(data[8:]),
+		VariableFeeControl:    binary.LittleEndian.Uint32(data[16:]),
+		BaseFeePowerFactor:    data[34],
+		VolatilityAccumulator: binary.LittleEndian.Uint32(data[40:]),
+		ActiveID:              int32(binary.LittleEndian.Uint32(data[76:])),
+		BinStep:               binary.LittleEndian.Uint16(data[80:]),
+		MintX:                 publicKey(data, 88),
+		MintY:                 publicKey(data, 120),
+		ReserveXAccount:       publicKey(data, 152),
+		ReserveYAccount:       publicKey(data, 184),
+	}, nil
+}
+
+// DecodePumpFunCurve decodes a pump.fun BondingCurve account of the given mint
+func DecodePumpFunCurve(address, mint solana.PublicKey, data []byte) (*PumpFunCurve, error) {
+	var curve pump.BondingCurve
+	if err := curve.UnmarshalWithDecoder(ag_binary.NewBorshDecoder(data)); err != nil {
+		return nil, fmt.Errorf("account %s is not a pump.fun bonding curve: %w", address, err)
+	}
+	return &PumpFunCurve{
+		Curve:                address,
+		Mint:                 mint,
+		VirtualTokenReserves: curve.VirtualTokenReserves,
+		VirtualSolReserves:   curve.VirtualSolReserves,
+		RealTokenReserves:    curve.RealTokenReserves,
+		RealSolReserves:      curve.RealSolReserves,
+		TokenTotalSupply:     curve.TokenTotalSupply,
+		Complete:             curve.Complete,
+	}, nil
+}
+
+// tokenAccountAmount returns the balance of an SPL token account
+func tokenAccountAmount(address solana.PublicKey, data []byte) (uint64, error) {
+	if len(data) < 72 {
+		return 0, fmt.Errorf("account %s is not a token account", address)
+	}
+	return u64(data, 64), nil
+}
+
+func u64(data []byte, offset int) uint64 {
+	return binary.LittleEndian.Uint64(data[offset:])
+}
+
+func u128(data []byte, offset int) *big.Int {
+	be := make([]byte, 16)
+	for i := range be {
+		be[i] = data[offset+15-i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+func publicKey(data []byte, offset int) solana.PublicKey {
+	return solana.PublicKeyFromBytes(data[offset : offset+32])
+}
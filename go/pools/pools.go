@@ -0,0 +1,184 @@
+package pools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+)
+
+// maxAccountsPerRequest is the getMultipleAccounts limit
+const maxAccountsPerRequest = 100
+
+// Reader loads live pool state with getMultipleAccounts
+type Reader struct {
+	rpcClient *rpc.Client
+}
+
+// New creates a pool state reader
+func New(rpcClient *rpc.Client) *Reader {
+	return &Reader{rpcClient: rpcClient}
+}
+
+// Load reads Raydium AMM v4, Raydium CLMM, Orca Whirlpool and Meteora DLMM pools by address,
+// followed by the vaults and fee configs they reference, in two batched round trips
+func (r *Reader) Load(ctx context.Context, addresses []solana.PublicKey) ([]PoolState, error) {
+	accounts, err := r.fetch(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]PoolState, len(addresses))
+	var dependencies []solana.PublicKey
+	for i, address := range addresses {
+		account := accounts[address]
+		if account == nil {
+			return nil, fmt.Errorf("pool %s not found", address)
+		}
+		state, err := Decode(address, account.Owner, account.Data.GetBinary())
+		if err != nil {
+			return nil, err
+		}
+		states[i] = state
+		dependencies = append(dependencies, dependenciesOf(state)...)
+	}
+
+	dependencyAccounts, err := r.fetch(ctx, dependencies)
+	if err != nil {
+		return nil, err
+	}
+	data := func(address solana.PublicKey) ([]byte, error) {
+		account := dependencyAccounts[address]
+		if account == nil {
+			return nil, fmt.Errorf("account %s not found", address)
+		}
+		return account.Data.GetBinary(), nil
+	}
+
+	for _, state := range states {
+		if err := resolve(state, data); err != nil {
+			return nil, fmt.Errorf("failed to load pool %s: %w", state.Address(), err)
+		}
+	}
+	return states, nil
+}
+
+// LoadBondingCurves reads the pump.fun bonding curves of the given mints
+func (r *Reader) LoadBondingCurves(ctx context.Context, mints []solana.PublicKey) ([]*PumpFunCurve, error) {
+	addresses := make([]solana.PublicKey, len(mints))
+	for i, mint := range mints {
+		curve, _, err := pumpfun.DeriveBondingCurveAddresses(mint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive bonding curve of %s: %w", mint, err)
+		}
+		addresses[i] = curve
+	}
+
+	accounts, err := r.fetch(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	curves := make([]*PumpFunCurve, len(mints))
+	for i, address := range addresses {
+		account := accounts[address]
+		if account == nil {
+			return nil, fmt.Errorf("bonding curve of %s not found", mints[i])
+		}
+		curve, err := DecodePumpFunCurve(address, mints[i], account.Data.GetBinary())
+		if err != nil {
+			return nil, err
+		}
+		curves[i] = curve
+	}
+	return curves, nil
+}
+
+// fetch reads accounts in chunks, returning them by address. Missing accounts are absent.
+func (r *Reader) fetch(ctx context.Context, addresses []solana.PublicKey) (map[solana.PublicKey]*rpc.Account, error) {
+	accounts := make(map[solana.PublicKey]*rpc.Account, len(addresses))
+	for start := 0; start < len(addresses); start += maxAccountsPerRequest {
+		chunk := addresses[start:min(start+maxAccountsPerRequest, len(addresses))]
+
+		result, err := r.rpcClient.GetMultipleAccountsWithOpts(ctx, chunk, &rpc.GetMultipleAccountsOpts{
+			Commitment: rpc.CommitmentConfirmed,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accounts: %w", err)
+		}
+		for i, account := range result.Value {
+			if account != nil {
+				accounts[chunk[i]] = account
+			}
+		}
+	}
+	return accounts, nil
+}
+
+// dependenciesOf returns the accounts a decoded pool needs to compute its reserves and fee
+func dependenciesOf(state PoolState) []solana.PublicKey {
+	switch p := state.(type) {
+	case *RaydiumAMM:
+		return []solana.PublicKey{p.CoinVault, p.PCVault}
+	case *ConcentratedPool:
+		if !p.AMMConfig.IsZero() {
+			return []solana.PublicKey{p.VaultA, p.VaultB, p.AMMConfig}
+		}
+		return []solana.PublicKey{p.VaultA, p.VaultB}
+	case *DLMMPool:
+		return []solana.PublicKey{p.ReserveXAccount, p.ReserveYAccount}
+	}
+	return nil
+}
+
+// resolve fills in the reserves and fee of a decoded pool from its dependencies
+func resolve(state PoolState, data func(solana.PublicKey) ([]byte, error)) error {
+	balance := func(address solana.PublicKey) (uint64, error) {
+		raw, err := data(address)
+		if err != nil {
+			return 0, err
+		}
+		return tokenAccountAmount(address, raw)
+	}
+	balances := func(a, b solana.PublicKey) (uint64, uint64, error) {
+		balanceA, err := balance(a)
+		if err != nil {
+			return 0, 0, err
+		}
+		balanceB, err := balance(b)
+		return balanceA, balanceB, err
+	}
+
+	var err error
+	switch p := state.(type) {
+	case *RaydiumAMM:
+		var coin, pc uint64
+		if coin, pc, err = balances(p.CoinVault, p.PCVault); err != nil {
+			return err
+		}
+		p.CoinReserve = coin - min(coin, p.NeedTakePnLCoin)
+		p.PCReserve = pc - min(pc, p.NeedTakePnLPC)
+	case *ConcentratedPool:
+		if p.ReserveA, p.ReserveB, err = balances(p.VaultA, p.VaultB); err != nil {
+			return err
+		}
+		if !p.AMMConfig.IsZero() {
+			raw, err := data(p.AMMConfig)
+			if err != nil {
+				return err
+			}
+			if p.FeeRateE6, err = decodeCLMMFeeRate(p.AMMConfig, raw); err != nil {
+				return err
+			}
+		}
+	case *DLMMPool:
+		if p.ReserveX, p.ReserveY, err = balances(p.ReserveXAccount, p.ReserveYAccount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,538 @@
+package pools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	mintA = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+	mintB = tx_parser.NATIVE_SOL_PROGRAM_ID
+)
+
+type testAccount struct {
+	owner solana.PublicKey
+	data  []byte
+}
+
+func putKey(data []byte, offset int, key solana.PublicKey) {
+	copy(data[offset:], key[:])
+}
+
+func putU128(data []byte, offset int, value *big.Int) {
+	be := value.FillBytes(make([]byte, 16))
+	for i := range be {
+		data[offset+i] = be[15-i]
+	}
+}
+
+func tokenAccount(amount uint64) testAccount {
+	data := make([]byte, 165)
+	binary.LittleEndian.PutUint64(data[64:], amount)
+	return testAccount{owner: solana.TokenProgramID, data: data}
+}
+
+func raydiumAMM(coinVault, pcVault solana.PublicKey) testAccount {
+	data := make([]byte, RAYDIUM_AMM_ACCOUNT_LENGTH)
+	binary.LittleEndian.PutUint64(data[32:], 6)
+	binary.LittleEndian.PutUint64(data[40:], 9)
+	binary.LittleEndian.PutUint64(data[176:], 25)
+	binary.LittleEndian.PutUint64(data[184:], 10_000)
+	binary.LittleEndian.PutUint64(data[192:], 1_000) // coin PnL owed
+	putKey(data, 336, coinVault)
+	putKey(data, 368, pcVault)
+	putKey(data, 400, mintA)
+	putKey(data, 432, mintB)
+	return testAccount{owner: tx_parser.RAYDIUM_V4_PROGRAM_ID, data: data}
+}
+
+// sqrtPriceX64 of price 4 with liquidity 1e12
+func concentratedFields(data []byte, liquidityOffset, sqrtPriceOffset int) {
+	putU128(data, liquidityOffset, big.NewInt(1_000_000_000_000))
+	putU128(data, sqrtPriceOffset, new(big.Int).Lsh(big.NewInt(2), 64))
+}
+
+func raydiumCLMM(config, vaultA, vaultB solana.PublicKey) testAccount {
+	data := make([]byte, 1544)
+	copy(data, clmmPoolDiscriminator)
+	putKey(data, 9, config)
+	putKey(data, 73, mintA)
+	putKey(data, 105, mintB)
+	putKey(data, 137, vaultA)
+	putKey(data, 169, vaultB)
+	binary.LittleEndian.PutUint16(data[235:], 60)
+	concentratedFields(data, 237, 253)
+	binary.LittleEndian.PutUint32(data[269:], uint32(13_863))
+	return testAccount{owner: tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, data: data}
+}
+
+func clmmConfig(feeRate uint32) testAccount {
+	data := make([]byte, 117)
+	copy(data, clmmConfigDiscriminator)
+	binary.LittleEndian.PutUint32(data[47:], feeRate)
+	return testAccount{owner: tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, data: data}
+}
+
+func whirlpool(vaultA, vaultB solana.PublicKey) testAccount {
+	data := make([]byte, 653)
+	copy(data, whirlpoolDiscriminator)
+	binary.LittleEndian.PutUint16(data[41:], 64)
+	binary.LittleEndian.PutUint16(data[45:], 3_000)
+	concentratedFields(data, 49, 65)
+	putKey(data, 101, mintA)
+	putKey(data, 133, vaultA)
+	putKey(data, 181, mintB)
+	putKey(data, 213, vaultB)
+	return testAccount{owner: tx_parser.ORCA_PROGRAM_ID, data: data}
+}
+
+func dlmm(reserveX, reserveY solana.PublicKey) testAccount {
+	data := make([]byte, 904)
+	copy(data, lbPairDiscriminator)
+	binary.LittleEndian.PutUint16(data[8:], 10_000) // base factor
+	binary.LittleEndian.PutUint32(data[16:], 7_500)
+	binary.LittleEndian.PutUint32(data[76:], uint32(100))
+	binary.LittleEndian.PutUint16(data[80:], 25)
+	putKey(data, 88, mintA)
+	putKey(data, 120, mintB)
+	putKey(data, 152, reserveX)
+	putKey(data, 184, reserveY)
+	return testAccount{owner: tx_parser.METEORA_PROGRAM_ID, data: data}
+}
+
+func encodeAccount(account testAccount, data []byte) string {
+	return fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+		account.owner, base64.StdEncoding.EncodeToString(data))
+}
+
+// programAccounts applies the getProgramAccounts filters and data slice
+func programAccounts(accounts map[solana.PublicKey]testAccount, params []json.RawMessage) string {
+	var program solana.PublicKey
+	json.Unmarshal(params[0], &program)
+	var opts rpc.GetProgramAccountsOpts
+	json.Unmarshal(params[1], &opts)
+
+	var values []string
+	for key, account := range accounts {
+		matches := account.owner.Equals(program)
+		for _, filter := range opts.Filters {
+			if filter.DataSize > 0 && uint64(len(account.data)) != filter.DataSize {
+				matches = false
+			}
+			if memcmp := filter.Memcmp; memcmp != nil {
+				end := memcmp.Offset + uint64(len(memcmp.Bytes))
+				if end > uint64(len(account.data)) || !bytes.Equal(account.data[memcmp.Offset:end], memcmp.Bytes) {
+					matches = false
+				}
+			}
+		}
+		if !matches {
+			continue
+		}
+		data := account.data
+		if slice := opts.DataSlice; slice != nil {
+			data = data[*slice.Offset : *slice.Offset+*slice.Length]
+		}
+		values = append(values, fmt.Sprintf(`{"pubkey":%q,"account":%s}`, key, encodeAccount(account, data)))
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+func serve(t *testing.T, accounts map[solana.PublicKey]testAccount) *rpc.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		if request.Method == "getProgramAccounts" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, programAccounts(accounts, request.Params))
+			return
+		}
+
+		var keys []solana.PublicKey
+		json.Unmarshal(request.Params[0], &keys)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			account, ok := accounts[key]
+			if !ok {
+				values[i] = "null"
+				continue
+			}
+			values[i] = encodeAccount(account, account.data)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":[%s]}}`, strings.Join(values, ","))
+	}))
+	t.Cleanup(server.Close)
+	return rpc.New(server.URL)
+}
+
+func newKey() solana.PublicKey {
+	return solana.NewWallet().PublicKey()
+}
+
+func TestLoad(t *testing.T) {
+	ammPool, ammCoin, ammPC := newKey(), newKey(), newKey()
+	clmmPool, clmmConfigKey, clmmA, clmmB := newKey(), newKey(), newKey(), newKey()
+	orcaPool, orcaA, orcaB := newKey(), newKey(), newKey()
+	dlmmPair, dlmmX, dlmmY := newKey(), newKey(), newKey()
+
+	client := serve(t, map[solana.PublicKey]testAccount{
+		ammPool: raydiumAMM(ammCoin, ammPC), ammCoin: tokenAccount(1_001_000), ammPC: tokenAccount(2_000_000),
+		clmmPool: raydiumCLMM(clmmConfigKey, clmmA, clmmB), clmmConfigKey: clmmConfig(2_500), clmmA: tokenAccount(7), clmmB: tokenAccount(8),
+		orcaPool: whirlpool(orcaA, orcaB), orcaA: tokenAccount(1), orcaB: tokenAccount(2),
+		dlmmPair: dlmm(dlmmX, dlmmY), dlmmX: tokenAccount(1_000_000), dlmmY: tokenAccount(500),
+	})
+
+	states, err := New(client).Load(context.Background(), []solana.PublicKey{ammPool, clmmPool, orcaPool, dlmmPair})
+	if err != nil {
+		t.Fatalf("failed to load pools: %v", err)
+	}
+
+	amm := states[0].(*RaydiumAMM)
+	if a, b := amm.Reserves(); a != 1_000_000 || b != 2_000_000 {
+		t.Errorf("expected AMM reserves net of PnL, got %d %d", a, b)
+	}
+	if amm.FeeRate() != 0.0025 || amm.SpotPrice() != 2 || amm.CoinDecimals != 6 {
+		t.Errorf("unexpected AMM state %+v", amm)
+	}
+
+	clmm := states[1].(*ConcentratedPool)
+	if clmm.Protocol() != tx_parser.SwapTypeRaydium || clmm.FeeRateE6 != 2_500 || clmm.ReserveA != 7 || clmm.TickCurrent != 13_863 {
+		t.Errorf("unexpected CLMM state %+v", clmm)
+	}
+	if math.Abs(clmm.SpotPrice()-4) > 1e-12 {
+		t.Errorf("expected CLMM price 4, got %v", clmm.SpotPrice())
+	}
+
+	orca := states[2].(*ConcentratedPool)
+	if orca.Protocol() != tx_parser.SwapTypeOrca || orca.FeeRate() != 0.003 || orca.TickSpacing != 64 || orca.ReserveB != 2 {
+		t.Errorf("unexpected Whirlpool state %+v", orca)
+	}
+
+	pair := states[3].(*DLMMPool)
+	if pair.ActiveID != 100 || pair.BinStep != 25 || pair.ReserveY != 500 {
+		t.Errorf("unexpected DLMM state %+v", pair)
+	}
+
+	if _, err := New(client).Load(context.Background(), []solana.PublicKey{newKey()}); err == nil {
+		t.Error("expected a missing pool to fail")
+	}
+}
+
+func TestConstantProductQuote(t *testing.T) {
+	amm := &RaydiumAMM{CoinMint: mintA, PCMint: mintB, CoinReserve: 1_000_000, PCReserve: 2_000_000, FeeNumerator: 25, FeeDenominator: 10_000}
+
+	out, err := amm.Quote(mintA, 10_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 9975 in after fees: 2_000_000 * 9975 / 1_009_975
+	if out != 19_752 {
+		t.Errorf("expected 19752 out, got %d", out)
+	}
+	if _, err := amm.Quote(newKey(), 1); err == nil {
+		t.Error("expected an unknown mint to fail")
+	}
+}
+
+func TestPumpFunCurve(t *testing.T) {
+	curve := pump.BondingCurve{
+		VirtualTokenReserves: 1_073_000_000_000_000,
+		VirtualSolReserves:   30_000_000_000,
+		RealTokenReserves:    793_100_000_000_000,
+		TokenTotalSupply:     1_000_000_000_000_000,
+	}
+	data := append([]byte{}, pump.BondingCurveDiscriminator[:]...)
+	for _, v := range []uint64{curve.VirtualTokenReserves, curve.VirtualSolReserves, curve.RealTokenReserves, curve.RealSolReserves, curve.TokenTotalSupply} {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	data = append(data, 0)
+
+	state, err := DecodePumpFunCurve(newKey(), mintA, data)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if a, b := state.Mints(); !a.Equals(mintA) || !b.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		t.Errorf("unexpected mints %s %s", a, b)
+	}
+
+	tokens, err := state.Quote(tx_parser.NATIVE_SOL_PROGRAM_ID, 1_000_000_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0.99 SOL after fees buys 1.073e15 * 0.99 / 30.99 tokens
+	if tokens != 34_277_831_558_567 {
+		t.Errorf("unexpected buy quote %d", tokens)
+	}
+
+	// Selling into the unchanged curve: 30e9 * tokens / (1.073e15 + tokens), less the fee
+	sol, _ := state.Quote(mintA, tokens)
+	if sol != 919_418_385 {
+		t.Errorf("unexpected sell quote %d", sol)
+	}
+
+	state.Complete = true
+	if _, err := state.Quote(mintA, 1); err == nil {
+		t.Error("expected a complete curve to fail")
+	}
+}
+
+func TestConcentratedQuote(t *testing.T) {
+	pool := &ConcentratedPool{
+		Pool: newKey(), MintA: mintA, MintB: mintB,
+		Liquidity:    big.NewInt(1_000_000_000_000),
+		SqrtPriceX64: new(big.Int).Lsh(big.NewInt(2), 64),
+		FeeRateE6:    3_000,
+	}
+
+	out, err := pool.Quote(mintA, 1_000_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out > 3_988_000 || out < 3_980_000 {
+		t.Errorf("expected close to 4x the input after fees, got %d", out)
+	}
+
+	out, _ = pool.Quote(mintB, 4_000_000)
+	if out > 997_000 || out < 990_000 {
+		t.Errorf("expected close to a quarter of the input after fees, got %d", out)
+	}
+
+	pool.Liquidity = big.NewInt(0)
+	if _, err := pool.Quote(mintA, 1); err == nil {
+		t.Error("expected a pool without liquidity to fail")
+	}
+}
+
+func TestDLMMQuote(t *testing.T) {
+	pair := &DLMMPool{MintX: mintA, MintY: mintB, ReserveX: 1_000_000, ReserveY: 1_000_000, BinStep: 25, BaseFactor: 10_000}
+
+	if fee := pair.FeeRate(); math.Abs(fee-0.0025) > 1e-12 {
+		t.Errorf("expected a 0.25%% base fee, got %v", fee)
+	}
+	out, err := pair.Quote(mintA, 10_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != 9_975 {
+		t.Errorf("expected 9975 at price 1, got %d", out)
+	}
+
+	pair.ActiveID = 100
+	if price := pair.SpotPrice(); math.Abs(price-math.Pow(1.0025, 100)) > 1e-12 {
+		t.Errorf("unexpected spot price %v", price)
+	}
+	if out, _ := pair.Quote(mintA, 10_000_000); out != pair.ReserveY {
+		t.Errorf("expected the quote to be capped by the reserve, got %d", out)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	ammPool, orcaPool, otherPool := newKey(), newKey(), newKey()
+	other := raydiumAMM(newKey(), newKey())
+	putKey(other.data, 400, newKey())
+
+	// The Whirlpool lists the mints in the opposite order
+	orca := whirlpool(newKey(), newKey())
+	putKey(orca.data, 101, mintB)
+	putKey(orca.data, 181, mintA)
+
+	curve := make([]byte, 49)
+	copy(curve, pump.BondingCurveDiscriminator[:])
+	curveAddress, _, _ := pumpfun.DeriveBondingCurveAddresses(mintA)
+
+	registry := NewRegistry(serve(t, map[solana.PublicKey]testAccount{
+		ammPool:      raydiumAMM(newKey(), newKey()),
+		orcaPool:     orca,
+		otherPool:    other,
+		curveAddress: {owner: tx_parser.PUMP_FUN_PROGRAM_ID, data: curve},
+	}), RegistryConfig{TTL: time.Minute})
+	now := time.Unix(1_700_000_000, 0)
+	registry.now = func() time.Time { return now }
+
+	if _, ok := registry.Pools(mintA, mintB); ok {
+		t.Fatal("expected an undiscovered pair to miss the cache")
+	}
+	pools, err := registry.Discover(context.Background(), mintB, mintA)
+	if err != nil {
+		t.Fatalf("failed to discover pools: %v", err)
+	}
+	if len(pools) != 3 {
+		t.Fatalf("expected 3 pools, got %+v", pools)
+	}
+	for _, pool := range pools {
+		switch pool.Address {
+		case ammPool:
+			if pool.Protocol != tx_parser.SwapTypeRaydium || !pool.MintA.Equals(mintA) || !pool.MintB.Equals(mintB) {
+				t.Errorf("unexpected AMM pool %+v", pool)
+			}
+		case orcaPool:
+			if pool.Protocol != tx_parser.SwapTypeOrca || !pool.MintA.Equals(mintB) || !pool.MintB.Equals(mintA) {
+				t.Errorf("unexpected Whirlpool %+v", pool)
+			}
+		case curveAddress:
+			if pool.Protocol != tx_parser.SwapTypePumpFun {
+				t.Errorf("unexpected bonding curve %+v", pool)
+			}
+		default:
+			t.Errorf("unexpected pool %s", pool.Address)
+		}
+	}
+
+	created := Pool{Address: newKey(), Protocol: tx_parser.SwapTypeMeteora, MintA: mintA, MintB: mintB}
+	if !registry.Register(created) || registry.Register(created) {
+		t.Error("expected only the first registration to add the pool")
+	}
+	launch := &tx_parser.ParsedTransaction{PoolCreations: []*tx_parser.PoolCreatedEvent{
+		{Protocol: tx_parser.SwapTypeRaydium, Pool: ammPool, MintA: mintA, MintB: mintB},
+		{Protocol: tx_parser.SwapTypeRaydium, Pool: newKey(), MintA: mintA, MintB: newKey()},
+	}}
+	if added := registry.Observe(launch); added != 1 {
+		t.Errorf("expected only the unknown pool to be added, got %d", added)
+	}
+	if cached, ok := registry.Pools(mintA, mintB); !ok || len(cached) != 4 {
+		t.Errorf("expected the registered pool in the cached pair, got %+v", cached)
+	}
+
+	registry.Remove(created.Address)
+	if _, ok := registry.Lookup(created.Address); ok {
+		t.Error("expected the removed pool to be forgotten")
+	}
+	if cached, _ := registry.Pools(mintA, mintB); len(cached) != 3 {
+		t.Errorf("expected 3 cached pools, got %d", len(cached))
+	}
+
+	graduated := newKey()
+	registry.Register(Pool{Address: graduated, Protocol: tx_parser.SwapTypeRaydiumLaunchLab, MintA: mintA, MintB: mintB})
+	migration := &tx_parser.ParsedTransaction{PoolCreations: []*tx_parser.PoolCreatedEvent{
+		{Protocol: tx_parser.SwapTypeRaydiumLaunchLab, Program: tx_parser.RAYDIUM_CPMM_PROGRAM_ID, Pool: newKey(), Curve: graduated, MintA: mintA, MintB: mintB},
+	}}
+	if added := registry.Observe(migration); added != 1 {
+		t.Errorf("expected the graduated pool to be added, got %d", added)
+	}
+	if _, ok := registry.Lookup(graduated); ok {
+		t.Error("expected the graduated curve to be forgotten")
+	}
+	if cached, _ := registry.Pools(mintA, mintB); len(cached) != 4 {
+		t.Errorf("expected the curve to be replaced by its pool, got %d pools", len(cached))
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := registry.Pools(mintA, mintB); ok {
+		t.Error("expected the pair to expire")
+	}
+}
+
+func TestRegistryLoad(t *testing.T) {
+	ammPool, orcaPool := newKey(), newKey()
+	accounts := map[solana.PublicKey]testAccount{
+		ammPool:  raydiumAMM(newKey(), newKey()),
+		orcaPool: whirlpool(newKey(), newKey()),
+		newKey(): clmmConfig(100),
+		newKey(): tokenAccount(1),
+	}
+	var loaded []Pool
+	for address, account := range accounts {
+		if pool, ok := Identify(address, account.owner, account.data); ok {
+			loaded = append(loaded, pool)
+		}
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected the AMM and the Whirlpool identified, got %+v", loaded)
+	}
+
+	// the RPC serves no accounts, so a query would find nothing
+	registry := NewRegistry(serve(t, map[solana.PublicKey]testAccount{}), RegistryConfig{})
+	if added := registry.Load(loaded); added != 2 || registry.Load(loaded) != 0 {
+		t.Errorf("expected the pools added once, got %d", added)
+	}
+	pools, err := registry.Discover(context.Background(), mintB, mintA)
+	if err != nil || len(pools) != 2 {
+		t.Errorf("expected the loaded pools without querying, got %+v: %v", pools, err)
+	}
+}
+
+// mapCache is an in-memory PairCache
+type mapCache map[[2]solana.PublicKey][]Pool
+
+func (c mapCache) Get(mintA, mintB solana.PublicKey) ([]Pool, bool) {
+	pools, ok := c[newPairKey(mintA, mintB)]
+	return pools, ok
+}
+
+func (c mapCache) Put(mintA, mintB solana.PublicKey, pools []Pool) error {
+	c[newPairKey(mintA, mintB)] = pools
+	return nil
+}
+
+func TestRegistryPersistentCache(t *testing.T) {
+	cached := Pool{Address: newKey(), Protocol: tx_parser.SwapTypeOrca, MintA: mintA, MintB: mintB}
+	persistent := mapCache{newPairKey(mintA, mintB): {cached}}
+
+	// the RPC serves no accounts, so pools come from the cache or not at all
+	registry := NewRegistry(serve(t, map[solana.PublicKey]testAccount{}), RegistryConfig{Persistent: persistent})
+	pools, err := registry.Discover(context.Background(), mintB, mintA)
+	if err != nil || len(pools) != 1 || !pools[0].Address.Equals(cached.Address) {
+		t.Fatalf("expected the persisted pool, got %+v: %v", pools, err)
+	}
+	if _, ok := registry.Lookup(cached.Address); !ok {
+		t.Error("expected the persisted pool registered")
+	}
+
+	other := newKey()
+	if _, err := registry.Discover(context.Background(), mintA, other); err != nil {
+		t.Fatalf("failed to discover pools: %v", err)
+	}
+	if _, ok := persistent[newPairKey(other, mintA)]; !ok {
+		t.Error("expected the discovered pair persisted")
+	}
+}
+
+func TestPrices(t *testing.T) {
+	ammPool, ammCoin, ammPC := newKey(), newKey(), newKey()
+	registry := NewRegistry(serve(t, map[solana.PublicKey]testAccount{
+		ammPool: raydiumAMM(ammCoin, ammPC), ammCoin: tokenAccount(1_001_000), ammPC: tokenAccount(2_000_000),
+	}), RegistryConfig{})
+
+	// 2 raw units of mint B per raw unit of mint A
+	prices, err := registry.Prices(context.Background(), mintA, 6, mintB, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 1 || math.Abs(prices[0].Price-0.002) > 1e-12 || prices[0].QuoteReserve != 2_000_000 {
+		t.Fatalf("unexpected prices %+v", prices)
+	}
+	inverse, err := registry.Prices(context.Background(), mintB, 9, mintA, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deepest, ok := DeepestPrice(inverse); !ok || math.Abs(deepest.Price-500) > 1e-9 || deepest.Reserve != 2_000_000 {
+		t.Errorf("unexpected inverse price %+v", deepest)
+	}
+}
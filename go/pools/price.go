@@ -0,0 +1,71 @@
+package pools
+
+import (
+	"context"
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Prices discovers and loads the pools trading mint against quote and returns the spot
+// price of each. Bonding curves that fail to load or have migrated and pools without a
+// price are skipped.
+func (r *Registry) Prices(ctx context.Context, mint solana.PublicKey, mintDecimals uint8, quote solana.PublicKey, quoteDecimals uint8) ([]PoolPrice, error) {
+	discovered, err := r.Discover(ctx, mint, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []PoolState
+	var addresses []solana.PublicKey
+	for _, pool := range discovered {
+		if pool.Protocol != tx_parser.SwapTypePumpFun {
+			addresses = append(addresses, pool.Address)
+			continue
+		}
+		curves, err := r.reader.LoadBondingCurves(ctx, []solana.PublicKey{mint})
+		if err == nil && len(curves) == 1 && !curves[0].Complete {
+			states = append(states, curves[0])
+		}
+	}
+	if len(addresses) > 0 {
+		loaded, err := r.reader.Load(ctx, addresses)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, loaded...)
+	}
+
+	scale := math.Pow10(int(mintDecimals) - int(quoteDecimals))
+	prices := make([]PoolPrice, 0, len(states))
+	for _, state := range states {
+		spot := state.SpotPrice()
+		if spot == 0 {
+			continue
+		}
+		mintA, _ := state.Mints()
+		reserveA, reserveB := state.Reserves()
+		price := PoolPrice{Pool: state.Address(), Protocol: state.Protocol(), Mint: mint, QuoteMint: quote}
+		if mintA.Equals(mint) {
+			price.Reserve, price.QuoteReserve, price.Price = reserveA, reserveB, spot*scale
+		} else {
+			price.Reserve, price.QuoteReserve, price.Price = reserveB, reserveA, scale/spot
+		}
+		prices = append(prices, price)
+	}
+	return prices, nil
+}
+
+// DeepestPrice returns the price of the pool holding the most of the quote token
+func DeepestPrice(prices []PoolPrice) (PoolPrice, bool) {
+	var deepest PoolPrice
+	found := false
+	for _, price := range prices {
+		if !found || price.QuoteReserve > deepest.QuoteReserve {
+			deepest, found = price, true
+		}
+	}
+	return deepest, found
+}
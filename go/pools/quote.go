@@ -0,0 +1,214 @@
+package pools
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	_ PoolState = (*RaydiumAMM)(nil)
+	_ PoolState = (*PumpFunCurve)(nil)
+	_ PoolState = (*ConcentratedPool)(nil)
+	_ PoolState = (*DLMMPool)(nil)
+
+	q64 = new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 64))
+)
+
+func (p *RaydiumAMM) Address() solana.PublicKey    { return p.Pool }
+func (p *RaydiumAMM) Protocol() tx_parser.SwapType { return tx_parser.SwapTypeRaydium }
+func (p *RaydiumAMM) Mints() (solana.PublicKey, solana.PublicKey) {
+	return p.CoinMint, p.PCMint
+}
+func (p *RaydiumAMM) Reserves() (uint64, uint64) { return p.CoinReserve, p.PCReserve }
+
+// FeeRate returns the swap fee, 0.25% on current pools
+func (p *RaydiumAMM) FeeRate() float64 {
+	if p.FeeDenominator == 0 {
+		return 0
+	}
+	return float64(p.FeeNumerator) / float64(p.FeeDenominator)
+}
+
+// SpotPrice returns the reserve ratio
+func (p *RaydiumAMM) SpotPrice() float64 {
+	if p.CoinReserve == 0 {
+		return 0
+	}
+	return float64(p.PCReserve) / float64(p.CoinReserve)
+}
+
+// Quote computes the exact constant product output
+func (p *RaydiumAMM) Quote(mintIn solana.PublicKey, amountIn uint64) (uint64, error) {
+	reserveIn, reserveOut, err := orient(mintIn, p.CoinMint, p.PCMint, p.CoinReserve, p.PCReserve)
+	if err != nil {
+		return 0, err
+	}
+	fee := ceilDiv(amountIn, p.FeeNumerator, p.FeeDenominator)
+	return constantProduct(reserveIn, reserveOut, amountIn-fee), nil
+}
+
+func (c *PumpFunCurve) Address() solana.PublicKey    { return c.Curve }
+func (c *PumpFunCurve) Protocol() tx_parser.SwapType { return tx_parser.SwapTypePumpFun }
+func (c *PumpFunCurve) Mints() (solana.PublicKey, solana.PublicKey) {
+	return c.Mint, tx_parser.NATIVE_SOL_PROGRAM_ID
+}
+func (c *PumpFunCurve) Reserves() (uint64, uint64) { return c.RealTokenReserves, c.RealSolReserves }
+func (c *PumpFunCurve) FeeRate() float64           { return PUMP_FUN_FEE_BPS / 10_000.0 }
+
+// SpotPrice returns the virtual reserve ratio in lamports per raw token unit
+func (c *PumpFunCurve) SpotPrice() float64 {
+	if c.VirtualTokenReserves == 0 {
+		return 0
+	}
+	return float64(c.VirtualSolReserves) / float64(c.VirtualTokenReserves)
+}
+
+// Quote computes the bonding curve output, taking the fee from the SOL side. Buys are
+// capped at the tokens left on the curve.
+func (c *PumpFunCurve) Quote(mintIn solana.PublicKey, amountIn uint64) (uint64, error) {
+	if c.Complete {
+		return 0, fmt.Errorf("bonding curve %s is complete", c.Curve)
+	}
+
+	switch {
+	case mintIn.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		solIn := amountIn - ceilDiv(amountIn, PUMP_FUN_FEE_BPS, 10_000)
+		return min(constantProduct(c.VirtualSolReserves, c.VirtualTokenReserves, solIn), c.RealTokenReserves), nil
+	case mintIn.Equals(c.Mint):
+		solOut := constantProduct(c.VirtualTokenReserves, c.VirtualSolReserves, amountIn)
+		return solOut - ceilDiv(solOut, PUMP_FUN_FEE_BPS, 10_000), nil
+	}
+	return 0, fmt.Errorf("mint %s is not traded on bonding curve %s", mintIn, c.Curve)
+}
+
+func (p *ConcentratedPool) Address() solana.PublicKey    { return p.Pool }
+func (p *ConcentratedPool) Protocol() tx_parser.SwapType { return p.SwapType }
+func (p *ConcentratedPool) Mints() (solana.PublicKey, solana.PublicKey) {
+	return p.MintA, p.MintB
+}
+func (p *ConcentratedPool) Reserves() (uint64, uint64) { return p.ReserveA, p.ReserveB }
+func (p *ConcentratedPool) FeeRate() float64           { return float64(p.FeeRateE6) / 1_000_000 }
+
+// SpotPrice returns the square of the pool's sqrt price
+func (p *ConcentratedPool) SpotPrice() float64 {
+	sqrtPrice, _ := p.sqrtPrice().Float64()
+	return sqrtPrice * sqrtPrice
+}
+
+// Quote estimates the output using the active liquidity only. Swaps that cross into the
+// next initialized tick range are priced as if liquidity stayed constant.
+func (p *ConcentratedPool) Quote(mintIn solana.PublicKey, amountIn uint64) (uint64, error) {
+	if _, _, err := orient(mintIn, p.MintA, p.MintB, p.ReserveA, p.ReserveB); err != nil {
+		return 0, err
+	}
+	if p.Liquidity == nil || p.Liquidity.Sign() == 0 || p.SqrtPriceX64 == nil || p.SqrtPriceX64.Sign() == 0 {
+		return 0, fmt.Errorf("pool %s has no active liquidity", p.Pool)
+	}
+
+	in := new(big.Float).SetUint64(amountIn - ceilDiv(amountIn, uint64(p.FeeRateE6), 1_000_000))
+	liquidity := new(big.Float).SetInt(p.Liquidity)
+	sqrtPrice := p.sqrtPrice()
+
+	out := new(big.Float)
+	if mintIn.Equals(p.MintA) {
+		// A in moves the price down: sqrtP' = L*sqrtP / (L + in*sqrtP), out = L*(sqrtP - sqrtP')
+		denominator := new(big.Float).Add(liquidity, new(big.Float).Mul(in, sqrtPrice))
+		next := new(big.Float).Quo(new(big.Float).Mul(liquidity, sqrtPrice), denominator)
+		out.Mul(liquidity, new(big.Float).Sub(sqrtPrice, next))
+	} else {
+		// B in moves the price up: sqrtP' = sqrtP + in/L, out = L*(1/sqrtP - 1/sqrtP')
+		next := new(big.Float).Add(sqrtPrice, new(big.Float).Quo(in, liquidity))
+		inverse := new(big.Float).Sub(new(big.Float).Quo(big.NewFloat(1), sqrtPrice), new(big.Float).Quo(big.NewFloat(1), next))
+		out.Mul(liquidity, inverse)
+	}
+	return floorUint64(out), nil
+}
+
+// sqrtPrice converts the Q64.64 sqrt price to a float
+func (p *ConcentratedPool) sqrtPrice() *big.Float {
+	if p.SqrtPriceX64 == nil {
+		return new(big.Float)
+	}
+	return new(big.Float).Quo(new(big.Float).SetInt(p.SqrtPriceX64), q64)
+}
+
+func (p *DLMMPool) Address() solana.PublicKey    { return p.Pair }
+func (p *DLMMPool) Protocol() tx_parser.SwapType { return tx_parser.SwapTypeMeteora }
+func (p *DLMMPool) Mints() (solana.PublicKey, solana.PublicKey) {
+	return p.MintX, p.MintY
+}
+func (p *DLMMPool) Reserves() (uint64, uint64) { return p.ReserveX, p.ReserveY }
+
+// FeeRate returns the base fee plus the variable fee of the current volatility
+func (p *DLMMPool) FeeRate() float64 {
+	baseFee := float64(p.BaseFactor) * float64(p.BinStep) * 10 * math.Pow10(int(p.BaseFeePowerFactor))
+	volatility := float64(p.VolatilityAccumulator) * float64(p.BinStep)
+	variableFee := math.Ceil(volatility * volatility * float64(p.VariableFeeControl) / 100_000_000_000)
+	return math.Min(baseFee+variableFee, DLMM_MAX_FEE_RATE) / DLMM_FEE_PRECISION
+}
+
+// SpotPrice returns the price of the active bin, (1 + binStep/10000)^activeID
+func (p *DLMMPool) SpotPrice() float64 {
+	return math.Pow(1+float64(p.BinStep)/10_000, float64(p.ActiveID))
+}
+
+// Quote estimates the output at the active bin price, capped by the reserve of the
+// output token. Swaps that move across bins receive less than quoted.
+func (p *DLMMPool) Quote(mintIn solana.PublicKey, amountIn uint64) (uint64, error) {
+	_, reserveOut, err := orient(mintIn, p.MintX, p.MintY, p.ReserveX, p.ReserveY)
+	if err != nil {
+		return 0, err
+	}
+
+	in := float64(amountIn) * (1 - p.FeeRate())
+	out := in * p.SpotPrice()
+	if mintIn.Equals(p.MintY) {
+		out = in / p.SpotPrice()
+	}
+	return min(uint64(out), reserveOut), nil
+}
+
+// orient returns the reserves in swap direction
+func orient(mintIn, mintA, mintB solana.PublicKey, reserveA, reserveB uint64) (uint64, uint64, error) {
+	switch {
+	case mintIn.Equals(mintA):
+		return reserveA, reserveB, nil
+	case mintIn.Equals(mintB):
+		return reserveB, reserveA, nil
+	}
+	return 0, 0, fmt.Errorf("mint %s is not in the pool", mintIn)
+}
+
+// constantProduct returns reserveOut*in / (reserveIn+in), rounded down
+func constantProduct(reserveIn, reserveOut, in uint64) uint64 {
+	numerator := new(big.Int).Mul(new(big.Int).SetUint64(reserveOut), new(big.Int).SetUint64(in))
+	denominator := new(big.Int).Add(new(big.Int).SetUint64(reserveIn), new(big.Int).SetUint64(in))
+	if denominator.Sign() == 0 {
+		return 0
+	}
+	return numerator.Quo(numerator, denominator).Uint64()
+}
+
+// ceilDiv returns amount*numerator/denominator rounded up, 0 for a zero denominator
+func ceilDiv(amount, numerator, denominator uint64) uint64 {
+	if denominator == 0 {
+		return 0
+	}
+	product := new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(numerator))
+	product.Add(product, new(big.Int).SetUint64(denominator-1))
+	return product.Quo(product, new(big.Int).SetUint64(denominator)).Uint64()
+}
+
+// floorUint64 converts a non-negative float, saturating at the uint64 range
+func floorUint64(f *big.Float) uint64 {
+	if f.Sign() <= 0 {
+		return 0
+	}
+	value, _ := f.Uint64()
+	return value
+}
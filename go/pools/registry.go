@@ -0,0 +1,344 @@
+package pools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// poolLayout locates the mints of a pool account so pools can be found with memcmp filters
+type poolLayout struct {
+	program       solana.PublicKey
+	protocol      tx_parser.SwapType
+	dataSize      uint64
+	discriminator []byte
+	mintA, mintB  uint64
+}
+
+var poolLayouts = []poolLayout{
+	{program: tx_parser.RAYDIUM_V4_PROGRAM_ID, protocol: tx_parser.SwapTypeRaydium, dataSize: RAYDIUM_AMM_ACCOUNT_LENGTH, mintA: 400, mintB: 432},
+	{program: tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, protocol: tx_parser.SwapTypeRaydium, discriminator: clmmPoolDiscriminator, mintA: 73, mintB: 105},
+	{program: tx_parser.ORCA_PROGRAM_ID, protocol: tx_parser.SwapTypeOrca, discriminator: whirlpoolDiscriminator, mintA: 101, mintB: 181},
+	{program: tx_parser.METEORA_PROGRAM_ID, protocol: tx_parser.SwapTypeMeteora, discriminator: lbPairDiscriminator, mintA: 88, mintB: 120},
+}
+
+// pairKey identifies a mint pair regardless of order
+type pairKey [2]solana.PublicKey
+
+func newPairKey(a, b solana.PublicKey) pairKey {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return pairKey{a, b}
+}
+
+// pairEntry is a discovered pair
+type pairEntry struct {
+	pools      []solana.PublicKey
+	discovered time.Time
+}
+
+// Registry discovers the pools trading a mint pair and caches them. Pools created after a
+// pair was discovered are added with Observe or Register.
+type Registry struct {
+	reader *Reader
+	config RegistryConfig
+	now    func() time.Time
+
+	mu    sync.RWMutex
+	pools map[solana.PublicKey]Pool
+	pairs map[pairKey]*pairEntry
+}
+
+// NewRegistry creates a pool registry
+func NewRegistry(rpcClient *rpc.Client, config RegistryConfig) *Registry {
+	return &Registry{
+		reader: New(rpcClient),
+		config: config,
+		now:    time.Now,
+		pools:  make(map[solana.PublicKey]Pool),
+		pairs:  make(map[pairKey]*pairEntry),
+	}
+}
+
+// Discover returns the Raydium AMM v4, Raydium CLMM, Orca Whirlpool, Meteora DLMM and
+// pump.fun pools trading the pair. The programs are queried on the first call for a pair,
+// later calls are served from the cache.
+func (r *Registry) Discover(ctx context.Context, mintA, mintB solana.PublicKey) ([]Pool, error) {
+	if pools, ok := r.Pools(mintA, mintB); ok {
+		return pools, nil
+	}
+	if r.config.Persistent != nil {
+		if pools, ok := r.config.Persistent.Get(mintA, mintB); ok {
+			r.store(mintA, mintB, pools)
+			return pools, nil
+		}
+	}
+
+	var found []Pool
+	for _, layout := range poolLayouts {
+		for _, order := range [][2]solana.PublicKey{{mintA, mintB}, {mintB, mintA}} {
+			pools, err := r.query(ctx, layout, order[0], order[1])
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, pools...)
+		}
+	}
+
+	curve, err := r.bondingCurve(ctx, mintA, mintB)
+	if err != nil {
+		return nil, err
+	}
+	if curve != nil {
+		found = append(found, *curve)
+	}
+
+	r.store(mintA, mintB, found)
+	if r.config.Persistent != nil {
+		if err := r.config.Persistent.Put(mintA, mintB, found); err != nil {
+			return nil, fmt.Errorf("failed to persist pools: %w", err)
+		}
+	}
+	return found, nil
+}
+
+// store caches the discovered pools of a pair
+func (r *Registry) store(mintA, mintB solana.PublicKey, found []Pool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := &pairEntry{discovered: r.now()}
+	r.pairs[newPairKey(mintA, mintB)] = entry
+	for _, pool := range found {
+		r.pools[pool.Address] = pool
+		entry.pools = append(entry.pools, pool.Address)
+	}
+}
+
+// Pools returns the cached pools of the pair without querying, false if the pair was not
+// discovered or has expired
+func (r *Registry) Pools(mintA, mintB solana.PublicKey) ([]Pool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.pairs[newPairKey(mintA, mintB)]
+	if !ok || (r.config.TTL > 0 && r.now().Sub(entry.discovered) > r.config.TTL) {
+		return nil, false
+	}
+	pools := make([]Pool, 0, len(entry.pools))
+	for _, address := range entry.pools {
+		pools = append(pools, r.pools[address])
+	}
+	return pools, true
+}
+
+// Lookup returns a known pool by address
+func (r *Registry) Lookup(address solana.PublicKey) (Pool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pool, ok := r.pools[address]
+	return pool, ok
+}
+
+// Register records a new pool and adds it to its pair if the pair was discovered.
+// It returns false if the pool was already known.
+func (r *Registry) Register(pool Pool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pools[pool.Address]; ok {
+		return false
+	}
+	r.pools[pool.Address] = pool
+	if entry, ok := r.pairs[newPairKey(pool.MintA, pool.MintB)]; ok {
+		entry.pools = append(entry.pools, pool.Address)
+	}
+	return true
+}
+
+// Load registers pools read in bulk, e.g. from a snapshot, and caches their pairs as
+// discovered so Discover serves them without querying. The pools must include every pool
+// of their pairs. It returns how many were new.
+func (r *Registry) Load(pools []Pool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	added := 0
+	for _, pool := range pools {
+		if _, ok := r.pools[pool.Address]; ok {
+			continue
+		}
+		r.pools[pool.Address] = pool
+		added++
+
+		key := newPairKey(pool.MintA, pool.MintB)
+		entry, ok := r.pairs[key]
+		if !ok {
+			entry = &pairEntry{discovered: now}
+			r.pairs[key] = entry
+		}
+		entry.pools = append(entry.pools, pool.Address)
+	}
+	return added
+}
+
+// Identify recognizes a Raydium AMM v4, Raydium CLMM, Orca Whirlpool or Meteora DLMM pool
+// account by its program and layout. Bonding curves are not recognized as they do not
+// record their mint.
+func Identify(address, owner solana.PublicKey, data []byte) (Pool, bool) {
+	for _, layout := range poolLayouts {
+		if !owner.Equals(layout.program) {
+			continue
+		}
+		if layout.dataSize > 0 && uint64(len(data)) != layout.dataSize {
+			continue
+		}
+		if layout.discriminator != nil && !bytes.HasPrefix(data, layout.discriminator) {
+			continue
+		}
+		if uint64(len(data)) < max(layout.mintA, layout.mintB)+solana.PublicKeyLength {
+			continue
+		}
+		return Pool{
+			Address:  address,
+			Program:  layout.program,
+			Protocol: layout.protocol,
+			MintA:    publicKey(data, int(layout.mintA)),
+			MintB:    publicKey(data, int(layout.mintB)),
+		}, true
+	}
+	return Pool{}, false
+}
+
+// Observe registers the pools created by a parsed transaction and returns how many were new.
+// Bonding curves that graduated into a new pool are forgotten.
+func (r *Registry) Observe(tx *tx_parser.ParsedTransaction) int {
+	added := 0
+	for _, created := range tx.PoolCreations {
+		if !created.Curve.IsZero() {
+			r.Remove(created.Curve)
+		}
+		pool := Pool{
+			Address:  created.Pool,
+			Program:  created.Program,
+			Protocol: created.Protocol,
+			MintA:    created.MintA,
+			MintB:    created.MintB,
+		}
+		if r.Register(pool) {
+			added++
+		}
+	}
+	return added
+}
+
+// Remove forgets a pool, e.g. a closed pool or a migrated bonding curve
+func (r *Registry) Remove(address solana.PublicKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pool, ok := r.pools[address]
+	if !ok {
+		return
+	}
+	delete(r.pools, address)
+	if entry, ok := r.pairs[newPairKey(pool.MintA, pool.MintB)]; ok {
+		for i, known := range entry.pools {
+			if known.Equals(address) {
+				entry.pools = append(entry.pools[:i], entry.pools[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// query finds the pools of one program with mintA and mintB in the layout's mint slots.
+// Only the mint bytes are downloaded.
+func (r *Registry) query(ctx context.Context, layout poolLayout, mintA, mintB solana.PublicKey) ([]Pool, error) {
+	filters := []rpc.RPCFilter{
+		{Memcmp: &rpc.RPCFilterMemcmp{Offset: layout.mintA, Bytes: solana.Base58(mintA[:])}},
+		{Memcmp: &rpc.RPCFilterMemcmp{Offset: layout.mintB, Bytes: solana.Base58(mintB[:])}},
+	}
+	if layout.dataSize > 0 {
+		filters = append(filters, rpc.RPCFilter{DataSize: layout.dataSize})
+	}
+	if layout.discriminator != nil {
+		filters = append(filters, rpc.RPCFilter{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: solana.Base58(layout.discriminator)}})
+	}
+
+	offset := min(layout.mintA, layout.mintB)
+	length := max(layout.mintA, layout.mintB) + solana.PublicKeyLength - offset
+	accounts, err := r.reader.rpcClient.GetProgramAccountsWithOpts(ctx, layout.program, &rpc.GetProgramAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Encoding:   solana.EncodingBase64,
+		DataSlice:  &rpc.DataSlice{Offset: &offset, Length: &length},
+		Filters:    filters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s pools: %w", layout.program, err)
+	}
+
+	pools := make([]Pool, 0, len(accounts))
+	for _, account := range accounts {
+		data := account.Account.Data.GetBinary()
+		if uint64(len(data)) < length {
+			return nil, fmt.Errorf("account %s returned %d bytes, expected %d", account.Pubkey, len(data), length)
+		}
+		pools = append(pools, Pool{
+			Address:  account.Pubkey,
+			Program:  layout.program,
+			Protocol: layout.protocol,
+			MintA:    publicKey(data, int(layout.mintA-offset)),
+			MintB:    publicKey(data, int(layout.mintB-offset)),
+		})
+	}
+	return pools, nil
+}
+
+// bondingCurve returns the active pump.fun bonding curve of a token/SOL pair, if any.
+// Curves are derived from the mint rather than searched for.
+func (r *Registry) bondingCurve(ctx context.Context, mintA, mintB solana.PublicKey) (*Pool, error) {
+	mint := mintA
+	switch {
+	case mintA.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		mint = mintB
+	case !mintB.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		return nil, nil
+	}
+
+	address, _, err := pumpfun.DeriveBondingCurveAddresses(mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive bonding curve of %s: %w", mint, err)
+	}
+	accounts, err := r.reader.fetch(ctx, []solana.PublicKey{address})
+	if err != nil {
+		return nil, err
+	}
+	account := accounts[address]
+	if account == nil || !account.Owner.Equals(tx_parser.PUMP_FUN_PROGRAM_ID) {
+		return nil, nil
+	}
+	curve, err := DecodePumpFunCurve(address, mint, account.Data.GetBinary())
+	if err != nil || curve.Complete {
+		return nil, err
+	}
+
+	return &Pool{
+		Address:  address,
+		Program:  tx_parser.PUMP_FUN_PROGRAM_ID,
+		Protocol: tx_parser.SwapTypePumpFun,
+		MintA:    mint,
+		MintB:    tx_parser.NATIVE_SOL_PROGRAM_ID,
+	}, nil
+}
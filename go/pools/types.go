@@ -0,0 +1,143 @@
+package pools
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// PoolState is the live state of a liquidity pool. Amounts are raw token units.
+type PoolState interface {
+	Address() solana.PublicKey
+	Protocol() tx_parser.SwapType
+
+	// Mints returns the pool tokens, prices are quoted in MintB per MintA
+	Mints() (mintA, mintB solana.PublicKey)
+
+	// Reserves returns the token balances held by the pool
+	Reserves() (reserveA, reserveB uint64)
+
+	// FeeRate returns the swap fee as a fraction of the input, e.g. 0.0025
+	FeeRate() float64
+
+	// SpotPrice returns raw units of MintB per raw unit of MintA, before fees
+	SpotPrice() float64
+
+	// Quote estimates the output of swapping amountIn of mintIn, after fees
+	Quote(mintIn solana.PublicKey, amountIn uint64) (uint64, error)
+}
+
+// RaydiumAMM is a Raydium AMM v4 constant product pool. Coin is token A and PC is token B.
+type RaydiumAMM struct {
+	Pool            solana.PublicKey
+	CoinMint        solana.PublicKey
+	PCMint          solana.PublicKey
+	LPMint          solana.PublicKey
+	CoinVault       solana.PublicKey
+	PCVault         solana.PublicKey
+	CoinDecimals    uint8
+	PCDecimals      uint8
+	CoinReserve     uint64 // vault balance minus PnL owed to the protocol
+	PCReserve       uint64
+	NeedTakePnLCoin uint64
+	NeedTakePnLPC   uint64
+	FeeNumerator    uint64
+	FeeDenominator  uint64
+	LPAmount        uint64 // LP tokens issued by the pool, burning LP tokens does not lower it
+	OpenOrders      solana.PublicKey
+	TargetOrders    solana.PublicKey
+	Market          solana.PublicKey // OpenBook market the pool was created with
+	MarketProgram   solana.PublicKey
+}
+
+// PumpFunCurve is a pump.fun bonding curve. The token is token A and SOL is token B.
+type PumpFunCurve struct {
+	Curve                solana.PublicKey
+	Mint                 solana.PublicKey
+	VirtualTokenReserves uint64
+	VirtualSolReserves   uint64
+	RealTokenReserves    uint64
+	RealSolReserves      uint64
+	TokenTotalSupply     uint64
+	Complete             bool // migrated, the curve no longer trades
+}
+
+// ConcentratedPool is a Raydium CLMM or Orca Whirlpool pool
+type ConcentratedPool struct {
+	SwapType     tx_parser.SwapType
+	Pool         solana.PublicKey
+	MintA        solana.PublicKey
+	MintB        solana.PublicKey
+	VaultA       solana.PublicKey
+	VaultB       solana.PublicKey
+	ReserveA     uint64
+	ReserveB     uint64
+	Liquidity    *big.Int // active liquidity
+	SqrtPriceX64 *big.Int // square root of the price as Q64.64
+	TickCurrent  int32
+	TickSpacing  uint16
+	FeeRateE6    uint32 // fee in millionths of the input
+
+	AMMConfig   solana.PublicKey // Raydium CLMM fee configuration
+	Observation solana.PublicKey // Raydium CLMM price observation account
+}
+
+// DLMMPool is a Meteora DLMM pair. Token X is token A and token Y is token B.
+type DLMMPool struct {
+	Pair                  solana.PublicKey
+	MintX                 solana.PublicKey
+	MintY                 solana.PublicKey
+	ReserveXAccount       solana.PublicKey
+	ReserveYAccount       solana.PublicKey
+	ReserveX              uint64
+	ReserveY              uint64
+	ActiveID              int32
+	BinStep               uint16
+	BaseFactor            uint16
+	BaseFeePowerFactor    uint8
+	VariableFeeControl    uint32
+	VolatilityAccumulator uint32
+}
+
+// Pool identifies a pool and the pair it trades, without its live state
+type Pool struct {
+	Address  solana.PublicKey
+	Program  solana.PublicKey
+	Protocol tx_parser.SwapType
+	MintA    solana.PublicKey
+	MintB    solana.PublicKey
+}
+
+// RegistryConfig controls pool discovery
+type RegistryConfig struct {
+	// TTL expires discovered pairs so they are queried again, zero keeps them until removed.
+	// Registered pools keep cached pairs current in between.
+	TTL time.Duration
+
+	// Persistent is an optional cache of discovered pairs shared across processes or
+	// restarts, consulted before querying the programs
+	Persistent PairCache
+}
+
+// PairCache stores the pools discovered for mint pairs outside the process. A pair is
+// looked up with its mints in either order.
+type PairCache interface {
+	Get(mintA, mintB solana.PublicKey) ([]Pool, bool)
+	Put(mintA, mintB solana.PublicKey, pools []Pool) error
+}
+
+// PoolPrice is the price of a token in one pool trading it against a quote token
+type PoolPrice struct {
+	Pool      solana.PublicKey   `json:"pool"`
+	Protocol  tx_parser.SwapType `json:"protocol"`
+	Mint      solana.PublicKey   `json:"mint"`
+	QuoteMint solana.PublicKey   `json:"quote_mint"`
+	// Reserve and QuoteReserve are the raw amounts of each token held by the pool
+	Reserve      uint64 `json:"reserve,string"`
+	QuoteReserve uint64 `json:"quote_reserve,string"`
+	// Price is in quote tokens per token, adjusted for decimals
+	Price float64 `json:"price"`
+}
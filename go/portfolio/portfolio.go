@@ -0,0 +1,385 @@
+package portfolio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// SEEN_SLOTS is how many slots applied signatures are remembered for, to ignore the same
+// transaction delivered twice
+const SEEN_SLOTS = 10_000
+
+// Portfolio keeps the balances of a set of wallets current by applying the parsed
+// transactions that move them, instead of querying RPC. Balances the parser cannot see,
+// e.g. rent of created accounts, drift over time and are corrected by Reconcile.
+type Portfolio struct {
+	config Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	wallets map[solana.PublicKey]*wallet
+	seen    map[solana.Signature]uint64 // slot of each applied signature
+	slot    uint64                      // latest slot applied
+	pruned  uint64                      // slot seen was last pruned at
+}
+
+// wallet is the mutable state of a tracked wallet
+type wallet struct {
+	address  solana.PublicKey
+	wsol     solana.PublicKey // associated wrapped SOL account, transfers into it are wrapping
+	synced   uint64
+	holdings map[solana.PublicKey]*Holding
+}
+
+// New creates a portfolio of the wallets with empty balances, see Restore and Reconcile
+func New(config Config) *Portfolio {
+	p := &Portfolio{
+		config:  config,
+		now:     time.Now,
+		wallets: make(map[solana.PublicKey]*wallet),
+		seen:    make(map[solana.Signature]uint64),
+	}
+	for _, address := range config.Wallets {
+		p.wallet(address)
+	}
+	return p
+}
+
+// Apply updates the balances of the tracked wallets a transaction moves, returning
+// whether it was applied. Transactions seen before, or already reflected in a wallet's
+// restored or corrected state, are ignored. Failed transactions only charge their fee.
+func (p *Portfolio) Apply(tx *tx_parser.ParsedTransaction) bool {
+	at := p.now()
+	if tx.BlockTime != nil {
+		at = tx.BlockTime.Time()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.seen[tx.Signature]; ok {
+		return false
+	}
+	p.seen[tx.Signature] = tx.Slot
+	p.slot = max(p.slot, tx.Slot)
+	if p.slot >= p.pruned+SEEN_SLOTS {
+		p.prune()
+	}
+
+	applied := false
+	for _, w := range p.wallets {
+		if tx.Slot <= w.synced {
+			continue
+		}
+		if w.apply(tx, at) {
+			applied = true
+		}
+	}
+	return applied
+}
+
+// Holdings returns copies of the wallet's non-zero holdings ordered by mint
+func (p *Portfolio) Holdings(address solana.PublicKey) []*Holding {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w, ok := p.wallets[address]
+	if !ok {
+		return nil
+	}
+	return w.list(func(h *Holding) bool { return h.Amount > 0 })
+}
+
+// Positions returns copies of the wallet's open token positions, its non-zero holdings
+// other than SOL
+func (p *Portfolio) Positions(address solana.PublicKey) []*Holding {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w, ok := p.wallets[address]
+	if !ok {
+		return nil
+	}
+	return w.list(func(h *Holding) bool {
+		return h.Amount > 0 && !h.Mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID)
+	})
+}
+
+// Balance returns the wallet's raw balance of a mint, lamports for SOL
+func (p *Portfolio) Balance(address, mint solana.PublicKey) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if w, ok := p.wallets[address]; ok {
+		if h, ok := w.holdings[mint]; ok {
+			return h.Amount
+		}
+	}
+	return 0
+}
+
+// Snapshot captures the state of every wallet
+func (p *Portfolio) Snapshot() *Snapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := &Snapshot{Time: p.now().UTC(), Slot: p.slot}
+	for _, w := range p.wallets {
+		snapshot.Wallets = append(snapshot.Wallets, &WalletState{
+			Wallet:   w.address,
+			Synced:   w.synced,
+			Holdings: w.list(func(h *Holding) bool { return h.Amount > 0 }),
+		})
+	}
+	slices.SortFunc(snapshot.Wallets, func(a, b *WalletState) int { return bytes.Compare(a.Wallet[:], b.Wallet[:]) })
+	return snapshot
+}
+
+// Restore replaces the state of the snapshot's wallets, which are tracked from then on.
+// Transactions up to the snapshot's slot are treated as already applied.
+func (p *Portfolio) Restore(snapshot *Snapshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, state := range snapshot.Wallets {
+		w := p.wallet(state.Wallet)
+		w.synced = max(state.Synced, snapshot.Slot)
+		w.holdings = make(map[solana.PublicKey]*Holding, len(state.Holdings))
+		for _, h := range state.Holdings {
+			holding := *h
+			w.holdings[h.Mint] = &holding
+		}
+	}
+	p.slot = max(p.slot, snapshot.Slot)
+}
+
+// Reconcile compares the wallet's tracked balances with its balances on chain, returning
+// every drifted mint. With Correct set, drifted balances are replaced by the chain's when
+// it was read at or after the wallet's latest change.
+func (p *Portfolio) Reconcile(ctx context.Context, address solana.PublicKey) ([]*Drift, error) {
+	if p.config.Fetcher == nil {
+		return nil, fmt.Errorf("no fetcher configured")
+	}
+	actual, err := p.config.Fetcher.TakeSnapshot(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch balances of %s: %w", address, err)
+	}
+
+	type balance struct {
+		amount   uint64
+		decimals uint8
+	}
+	balances := map[solana.PublicKey]balance{
+		tx_parser.NATIVE_SOL_PROGRAM_ID: {actual.Lamports, 9},
+	}
+	for _, token := range actual.Tokens {
+		b := balances[token.Mint]
+		balances[token.Mint] = balance{b.amount + token.Amount, token.Decimals}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := p.wallet(address)
+	latest := w.synced
+	for _, h := range w.holdings {
+		latest = max(latest, h.Slot)
+	}
+	correct := p.config.Correct && actual.Slot >= latest
+
+	var drifts []*Drift
+	for mint, h := range w.holdings {
+		if _, ok := balances[mint]; !ok && h.Amount > 0 {
+			balances[mint] = balance{0, h.Decimals}
+		}
+	}
+	for mint, b := range balances {
+		tracked := uint64(0)
+		if h, ok := w.holdings[mint]; ok {
+			tracked = h.Amount
+		}
+		if tracked == b.amount {
+			continue
+		}
+		drifts = append(drifts, &Drift{Wallet: address, Mint: mint, Tracked: tracked, Actual: b.amount, Slot: actual.Slot, Corrected: correct})
+		if correct {
+			h := w.holding(mint, b.decimals)
+			h.Slot = actual.Slot
+			w.set(h, b.amount, p.now())
+		}
+	}
+	if correct {
+		w.synced = max(w.synced, actual.Slot)
+	}
+	slices.SortFunc(drifts, func(a, b *Drift) int { return bytes.Compare(a.Mint[:], b.Mint[:]) })
+	return drifts, nil
+}
+
+// ReconcileAll reconciles every tracked wallet
+func (p *Portfolio) ReconcileAll(ctx context.Context) ([]*Drift, error) {
+	p.mu.Lock()
+	addresses := make([]solana.PublicKey, 0, len(p.wallets))
+	for address := range p.wallets {
+		addresses = append(addresses, address)
+	}
+	p.mu.Unlock()
+
+	var drifts []*Drift
+	for _, address := range addresses {
+		found, err := p.Reconcile(ctx, address)
+		if err != nil {
+			return drifts, err
+		}
+		drifts = append(drifts, found...)
+	}
+	return drifts, nil
+}
+
+// wallet returns the state of a wallet, tracking it if it is new
+func (p *Portfolio) wallet(address solana.PublicKey) *wallet {
+	if w, ok := p.wallets[address]; ok {
+		return w
+	}
+	wsol, _, _ := solana.FindAssociatedTokenAddress(address, tx_parser.NATIVE_SOL_PROGRAM_ID)
+	w := &wallet{address: address, wsol: wsol, holdings: make(map[solana.PublicKey]*Holding)}
+	p.wallets[address] = w
+	return w
+}
+
+// prune forgets signatures too old to be delivered again
+func (p *Portfolio) prune() {
+	for signature, slot := range p.seen {
+		if slot+SEEN_SLOTS < p.slot {
+			delete(p.seen, signature)
+		}
+	}
+	p.pruned = p.slot
+}
+
+// apply moves the wallet's balances by the transaction, as the pnl tracker does: swaps
+// move both of their mints and their legs are not counted again as transfers
+func (w *wallet) apply(tx *tx_parser.ParsedTransaction, at time.Time) bool {
+	applied := false
+	if tx.FeePayer.Equals(w.address) {
+		w.sub(tx_parser.NATIVE_SOL_PROGRAM_ID, 9, tx.Fee, tx.Slot, at)
+		applied = true
+	}
+	if tx.Failure != nil {
+		return applied
+	}
+
+	swapped := false
+	for _, swap := range tx.Swaps {
+		if !slices.ContainsFunc(swap.Signers, w.address.Equals) {
+			continue
+		}
+		swapped, applied = true, true
+		value := swap.AmountUSD
+		if value == 0 && swap.Price != nil {
+			value = swap.Price.VolumeUSD
+		}
+		w.sub(swap.TokenIn.Mint, swap.TokenIn.Decimals, swap.TokenIn.Amount, tx.Slot, at)
+		h := w.add(swap.TokenOut.Mint, swap.TokenOut.Decimals, swap.TokenOut.Amount, tx.Slot, at)
+		if !h.Mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+			h.CostBasisUSD += value
+		}
+	}
+
+	for _, transfer := range tx.Transfers {
+		switch transfer.Type {
+		case tx_parser.TransferTypeSOL:
+			// wrapping moves SOL into the wallet's own wrapped SOL, held together
+			if transfer.Source.Equals(w.address) && transfer.Destination.Equals(w.wsol) {
+				continue
+			}
+			if swapped && !tx_parser.IsJitoTipAccount(transfer.Destination) {
+				continue
+			}
+			if transfer.Source.Equals(w.address) {
+				w.sub(tx_parser.NATIVE_SOL_PROGRAM_ID, 9, transfer.Amount, tx.Slot, at)
+				applied = true
+			}
+			if transfer.Destination.Equals(w.address) {
+				w.add(tx_parser.NATIVE_SOL_PROGRAM_ID, 9, transfer.Amount, tx.Slot, at)
+				applied = true
+			}
+		case tx_parser.TransferTypeToken:
+			if swapped {
+				continue
+			}
+			if transfer.SourceOwner.Equals(w.address) {
+				w.sub(transfer.Mint, transfer.Decimals, transfer.Amount, tx.Slot, at)
+				applied = true
+			}
+			if transfer.DestinationOwner.Equals(w.address) {
+				w.add(transfer.Mint, transfer.Decimals, transfer.Amount, tx.Slot, at)
+				applied = true
+			}
+		}
+	}
+	return applied
+}
+
+// add increases a holding
+func (w *wallet) add(mint solana.PublicKey, decimals uint8, amount, slot uint64, at time.Time) *Holding {
+	h := w.holding(mint, decimals)
+	h.Slot = max(h.Slot, slot)
+	w.set(h, h.Amount+amount, at)
+	return h
+}
+
+// sub decreases a holding, saturating at zero
+func (w *wallet) sub(mint solana.PublicKey, decimals uint8, amount, slot uint64, at time.Time) *Holding {
+	h := w.holding(mint, decimals)
+	h.Slot = max(h.Slot, slot)
+	w.set(h, h.Amount-min(amount, h.Amount), at)
+	return h
+}
+
+// set changes a holding's amount, scaling its cost basis down on decreases
+func (w *wallet) set(h *Holding, amount uint64, at time.Time) {
+	switch {
+	case amount == 0:
+		h.CostBasisUSD = 0
+		h.Opened = time.Time{}
+	case amount < h.Amount:
+		h.CostBasisUSD *= float64(amount) / float64(h.Amount)
+	case h.Amount == 0:
+		h.Opened = at
+	}
+	h.Amount = amount
+}
+
+// holding returns the wallet's holding of a mint, creating an empty one
+func (w *wallet) holding(mint solana.PublicKey, decimals uint8) *Holding {
+	h, ok := w.holdings[mint]
+	if !ok {
+		h = &Holding{Mint: mint, Decimals: decimals}
+		w.holdings[mint] = h
+	}
+	if decimals != 0 {
+		h.Decimals = decimals
+	}
+	return h
+}
+
+// list returns copies of the holdings passing the filter ordered by mint
+func (w *wallet) list(filter func(*Holding) bool) []*Holding {
+	var holdings []*Holding
+	for _, h := range w.holdings {
+		if filter(h) {
+			holding := *h
+			holdings = append(holdings, &holding)
+		}
+	}
+	slices.SortFunc(holdings, func(a, b *Holding) int { return bytes.Compare(a.Mint[:], b.Mint[:]) })
+	return holdings
+}
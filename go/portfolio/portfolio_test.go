@@ -0,0 +1,187 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/balance_snapshot"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	owner = solana.NewWallet().PublicKey()
+	other = solana.NewWallet().PublicKey()
+	token = solana.NewWallet().PublicKey()
+	sol   = tx_parser.NATIVE_SOL_PROGRAM_ID
+)
+
+// fetcher returns a fixed chain state
+type fetcher struct {
+	snapshot *balance_snapshot.Snapshot
+}
+
+func (f *fetcher) TakeSnapshot(ctx context.Context, wallet solana.PublicKey) (*balance_snapshot.Snapshot, error) {
+	return f.snapshot, nil
+}
+
+// history funds the wallet with 10 SOL, buys 1000 tokens for 2 SOL worth $300 paying a
+// tip, sends 250 tokens away and receives 50
+func history() []*tx_parser.ParsedTransaction {
+	wsol, _, _ := solana.FindAssociatedTokenAddress(owner, sol)
+	tip := solana.MustPublicKeyFromBase58("96gYZGLnJYVFmbjzopPSU6QiEV5fGqZNyN9nmNhvrZU5")
+	return []*tx_parser.ParsedTransaction{
+		{
+			Signature: solana.Signature{1},
+			Slot:      10,
+			FeePayer:  other,
+			Transfers: []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeSOL, Source: other, Destination: owner, Amount: 10_000_000_000}},
+		},
+		{
+			Signature: solana.Signature{2},
+			Slot:      11,
+			FeePayer:  owner,
+			Fee:       5_000,
+			Swaps: []*tx_parser.SwapInfo{{
+				Signers:   []solana.PublicKey{owner},
+				TokenIn:   tx_parser.TokenInfo{Mint: sol, Amount: 2_000_000_000, Decimals: 9},
+				TokenOut:  tx_parser.TokenInfo{Mint: token, Amount: 1_000, Decimals: 2},
+				AmountUSD: 300,
+			}},
+			Transfers: []*tx_parser.TransferInfo{
+				// wrapping and the swap's legs are part of the swap
+				{Type: tx_parser.TransferTypeSOL, Source: owner, Destination: wsol, Amount: 2_000_000_000},
+				{Type: tx_parser.TransferTypeToken, Mint: token, SourceOwner: other, DestinationOwner: owner, Amount: 1_000},
+				{Type: tx_parser.TransferTypeSOL, Source: owner, Destination: tip, Amount: 1_000_000},
+			},
+		},
+		{
+			Signature: solana.Signature{3},
+			Slot:      12,
+			FeePayer:  owner,
+			Fee:       5_000,
+			Transfers: []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeToken, Mint: token, Decimals: 2, SourceOwner: owner, DestinationOwner: other, Amount: 250}},
+		},
+		{
+			Signature: solana.Signature{4},
+			Slot:      13,
+			FeePayer:  other,
+			Transfers: []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeToken, Mint: token, Decimals: 2, SourceOwner: other, DestinationOwner: owner, Amount: 50}},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	p := New(Config{Wallets: []solana.PublicKey{owner}})
+	for _, tx := range history() {
+		if !p.Apply(tx) {
+			t.Errorf("expected %s to be applied", tx.Signature)
+		}
+	}
+	if p.Apply(history()[1]) {
+		t.Error("expected a duplicate to be ignored")
+	}
+	p.Apply(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{5},
+		Slot:      14,
+		FeePayer:  owner,
+		Fee:       5_000,
+		Failure:   &tx_parser.TransactionFailure{},
+		Transfers: []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeSOL, Source: owner, Destination: other, Amount: 1}},
+	})
+
+	if balance := p.Balance(owner, sol); balance != 10_000_000_000-2_000_000_000-3*5_000-1_000_000 {
+		t.Errorf("unexpected SOL balance %d", balance)
+	}
+	positions := p.Positions(owner)
+	if len(positions) != 1 {
+		t.Fatalf("expected one position, got %d", len(positions))
+	}
+	position := positions[0]
+	// a quarter sent away takes a quarter of the cost, the tokens received add none
+	if position.Amount != 800 || position.Decimals != 2 || position.CostBasisUSD != 225 || position.Slot != 13 {
+		t.Errorf("unexpected position %+v", position)
+	}
+	if len(p.Holdings(owner)) != 2 || p.Holdings(other) != nil {
+		t.Error("expected only the owner's SOL and token holdings")
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	p := New(Config{Wallets: []solana.PublicKey{owner}})
+	transactions := history()
+	for _, tx := range transactions[:3] {
+		p.Apply(tx)
+	}
+	data, err := json.Marshal(p.Snapshot())
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	if snapshot.Slot != 12 || len(snapshot.Wallets) != 1 || len(snapshot.Wallets[0].Holdings) != 2 {
+		t.Fatalf("unexpected snapshot %s", data)
+	}
+	restored := New(Config{})
+	restored.Restore(&snapshot)
+	// replaying from before the snapshot only applies what it misses
+	for _, tx := range transactions {
+		restored.Apply(tx)
+	}
+	if balance := restored.Balance(owner, token); balance != 800 {
+		t.Errorf("expected the restored balance to continue at 800, got %d", balance)
+	}
+	if balance := restored.Balance(owner, sol); balance != p.Balance(owner, sol) {
+		t.Errorf("expected the restored SOL balance %d, got %d", p.Balance(owner, sol), balance)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	wsol := solana.NewWallet().PublicKey()
+	chain := &fetcher{&balance_snapshot.Snapshot{
+		Wallet:   owner,
+		Slot:     12,
+		Lamports: 7_000_000_000,
+		Tokens: []balance_snapshot.TokenBalance{
+			{Account: wsol, Mint: sol, Amount: 990_000_000, Decimals: 9},
+			{Mint: token, Amount: 800, Decimals: 2},
+		},
+	}}
+	p := New(Config{Wallets: []solana.PublicKey{owner}, Fetcher: chain, Correct: true})
+	for _, tx := range history() {
+		p.Apply(tx)
+	}
+
+	// the chain was read before the last transfer, so nothing is corrected
+	drifts, err := p.Reconcile(context.Background(), owner)
+	if err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if len(drifts) != 1 || !drifts[0].Mint.Equals(sol) || drifts[0].Tracked != 7_998_990_000 || drifts[0].Corrected {
+		t.Fatalf("expected the SOL drift left uncorrected, got %+v", drifts)
+	}
+
+	chain.snapshot.Slot = 20
+	chain.snapshot.Tokens[1].Amount = 790
+	if drifts, err = p.ReconcileAll(context.Background()); err != nil || len(drifts) != 2 || !drifts[0].Corrected {
+		t.Fatalf("expected corrected drifts, got %+v: %v", drifts, err)
+	}
+	if balance := p.Balance(owner, sol); balance != 7_990_000_000 {
+		t.Errorf("expected native and wrapped SOL combined, got %d", balance)
+	}
+	if balance := p.Balance(owner, token); balance != 790 {
+		t.Errorf("expected the corrected token balance, got %d", balance)
+	}
+	// transactions the chain state already reflects are not applied again
+	if p.Apply(&tx_parser.ParsedTransaction{Signature: solana.Signature{9}, Slot: 19, FeePayer: owner, Fee: 5_000}) {
+		t.Error("expected a transaction before the correction to be ignored")
+	}
+	if drifts, _ := p.Reconcile(context.Background(), owner); len(drifts) != 0 {
+		t.Errorf("expected no drift after correcting, got %+v", drifts)
+	}
+}
@@ -0,0 +1,77 @@
+package portfolio
+
+import (
+	"context"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/balance_snapshot"
+)
+
+// Holding is a wallet's balance of one mint, in lamports for SOL. Native SOL and wrapped
+// SOL are held together under the wrapped SOL mint.
+type Holding struct {
+	Mint     solana.PublicKey `json:"mint"`
+	Amount   uint64           `json:"amount,string"`
+	Decimals uint8            `json:"decimals"`
+
+	// CostBasisUSD is what the amount held cost at average cost, from the USD value of
+	// the swaps buying it as priced by the pricing package. Tokens received by transfer
+	// add no cost.
+	CostBasisUSD float64 `json:"cost_basis_usd"`
+
+	// Opened is when the balance last became non-zero, zero when unknown
+	Opened time.Time `json:"opened,omitzero"`
+
+	// Slot of the last change
+	Slot uint64 `json:"slot"`
+}
+
+// WalletState is the holdings of one wallet
+type WalletState struct {
+	Wallet solana.PublicKey `json:"wallet"`
+
+	// Synced is the slot the holdings were last restored or corrected at, transactions up
+	// to it are already reflected and ignored
+	Synced uint64 `json:"synced"`
+
+	Holdings []*Holding `json:"holdings"`
+}
+
+// Snapshot is the state of a portfolio, to be persisted and restored
+type Snapshot struct {
+	Time    time.Time      `json:"time"`
+	Slot    uint64         `json:"slot"` // latest slot applied
+	Wallets []*WalletState `json:"wallets"`
+}
+
+// Drift is a difference between a tracked balance and the balance on chain
+type Drift struct {
+	Wallet    solana.PublicKey `json:"wallet"`
+	Mint      solana.PublicKey `json:"mint"`
+	Tracked   uint64           `json:"tracked,string"`
+	Actual    uint64           `json:"actual,string"`
+	Slot      uint64           `json:"slot"`      // slot the actual balance was read at
+	Corrected bool             `json:"corrected"` // whether the tracked balance was set to the actual one
+}
+
+// Fetcher reads a wallet's balances on chain, implemented by balance_snapshot.Snapshotter
+// with getBalance and getTokenAccountsByOwner
+type Fetcher interface {
+	TakeSnapshot(ctx context.Context, wallet solana.PublicKey) (*balance_snapshot.Snapshot, error)
+}
+
+var _ Fetcher = (*balance_snapshot.Snapshotter)(nil)
+
+// Config controls the tracked wallets and their reconciliation
+type Config struct {
+	Wallets []solana.PublicKey
+
+	// Fetcher reads balances for Reconcile, required to reconcile
+	Fetcher Fetcher
+
+	// Correct sets drifted balances to the balance on chain when reconciling, unless the
+	// chain was read at an older slot than the latest change
+	Correct bool
+}
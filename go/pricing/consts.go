@@ -0,0 +1,8 @@
+package pricing
+
+import "github.com/gagliardetto/solana-go"
+
+var (
+	USDC_MINT = solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	USDT_MINT = solana.MustPublicKeyFromBase58("Es9vMFrzaCERmJrF8r9fWNpnXa7JWg4RnoLNTEXuH3M8")
+)
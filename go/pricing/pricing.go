@@ -0,0 +1,263 @@
+package pricing
+
+import (
+	"bytes"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Engine derives execution prices from swaps. Stablecoins quote at one USD, SOL is valued
+// from the latest SOL/stablecoin swap and other tokens from their latest USD or SOL price,
+// so token/token swaps are valued once either side has traded against SOL or a stablecoin.
+// It is safe for concurrent use.
+type Engine struct {
+	config Config
+	stable map[solana.PublicKey]bool
+
+	mu      sync.RWMutex
+	solUSD  float64
+	usd     map[solana.PublicKey]float64
+	sol     map[solana.PublicKey]float64
+	markets map[Market]*market
+}
+
+// market holds the last price and the samples of the VWAP window of one market
+type market struct {
+	last        float64
+	lastAt      time.Time
+	samples     []sample
+	sumPV, sumV float64
+}
+
+type sample struct {
+	at            time.Time
+	price, volume float64
+}
+
+// New creates a pricing engine
+func New(config Config) *Engine {
+	if config.VWAPWindow <= 0 {
+		config.VWAPWindow = 5 * time.Minute
+	}
+	if len(config.StableMints) == 0 {
+		config.StableMints = []solana.PublicKey{USDC_MINT, USDT_MINT}
+	}
+
+	stable := make(map[solana.PublicKey]bool, len(config.StableMints))
+	for _, mint := range config.StableMints {
+		stable[mint] = true
+	}
+
+	return &Engine{
+		config:  config,
+		stable:  stable,
+		usd:     make(map[solana.PublicKey]float64),
+		sol:     make(map[solana.PublicKey]float64),
+		markets: make(map[Market]*market),
+	}
+}
+
+// PriceTransaction prices every swap of the transaction, using the block time for swaps
+// without a timestamp
+func (e *Engine) PriceTransaction(tx *tx_parser.ParsedTransaction) {
+	at := time.Now()
+	if tx.BlockTime != nil {
+		at = tx.BlockTime.Time()
+	}
+	for _, swap := range tx.Swaps {
+		swapAt := at
+		if !swap.Timestamp.IsZero() {
+			swapAt = swap.Timestamp
+		}
+		e.PriceSwap(swap, swapAt)
+	}
+}
+
+// PriceSwap computes the execution price of the swap, attaches it to swap.Price and updates
+// the market. It returns nil for swaps with a zero amount on either side.
+func (e *Engine) PriceSwap(swap *tx_parser.SwapInfo, at time.Time) *tx_parser.PriceInfo {
+	in, out := uiAmount(swap.TokenIn), uiAmount(swap.TokenOut)
+	if in == 0 || out == 0 {
+		return nil
+	}
+
+	base, quote := swap.TokenIn.Mint, swap.TokenOut.Mint
+	baseAmount, quoteAmount := in, out
+	if e.isQuote(base, quote) {
+		base, quote = quote, base
+		baseAmount, quoteAmount = quoteAmount, baseAmount
+	}
+	price := quoteAmount / baseAmount
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.record(Market{Protocol: swap.Protocol, BaseMint: base, QuoteMint: quote}, price, baseAmount, at)
+	if base.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) && e.stable[quote] {
+		e.solUSD = price
+	}
+
+	// in token/token swaps only the base may have a known price, value the quote from it
+	quoteUSD, quoteSOL := e.usdPrice(quote), e.solPrice(quote)
+	if e.quoteRank(quote) == 0 {
+		if baseUSD := e.usd[base]; quoteUSD == 0 && baseUSD > 0 {
+			quoteUSD = baseUSD / price
+			e.usd[quote] = quoteUSD
+		}
+		if baseSOL := e.sol[base]; quoteSOL == 0 && baseSOL > 0 {
+			quoteSOL = baseSOL / price
+			e.sol[quote] = quoteSOL
+		}
+	}
+
+	info := &tx_parser.PriceInfo{BaseMint: base, QuoteMint: quote, Price: price}
+	if quoteUSD > 0 {
+		info.PriceUSD = price * quoteUSD
+		info.VolumeUSD = quoteAmount * quoteUSD
+		e.usd[base] = info.PriceUSD
+	}
+	if quoteSOL > 0 {
+		info.PriceSOL = price * quoteSOL
+		e.sol[base] = info.PriceSOL
+	}
+
+	swap.Price = info
+	return info
+}
+
+// LastPrice returns the price of the latest swap in the market
+func (e *Engine) LastPrice(m Market) (float64, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	state, ok := e.markets[m]
+	if !ok {
+		return 0, false
+	}
+	return state.last, true
+}
+
+// VWAP returns the volume weighted average price of the market over the VWAP window
+// ending at its latest swap
+func (e *Engine) VWAP(m Market) (float64, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	state, ok := e.markets[m]
+	if !ok || state.sumV <= 0 {
+		return 0, false
+	}
+	return state.sumPV / state.sumV, true
+}
+
+// USDPrice returns the latest USD price of a mint
+func (e *Engine) USDPrice(mint solana.PublicKey) (float64, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	price := e.usdPrice(mint)
+	return price, price > 0
+}
+
+// SetUSDPrice sets the USD price of a mint from an external source such as an oracle.
+// Setting the SOL price values every SOL quoted swap until a SOL/stablecoin swap replaces it.
+func (e *Engine) SetUSDPrice(mint solana.PublicKey, price float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		e.solUSD = price
+		return
+	}
+	e.usd[mint] = price
+	if e.solUSD > 0 {
+		e.sol[mint] = price / e.solUSD
+	}
+}
+
+// isQuote reports whether a should be the quote of the pair, preferring stablecoins over
+// SOL over other tokens and falling back to the larger mint so each pair has one market
+func (e *Engine) isQuote(a, b solana.PublicKey) bool {
+	rankA, rankB := e.quoteRank(a), e.quoteRank(b)
+	if rankA != rankB {
+		return rankA > rankB
+	}
+	return bytes.Compare(a[:], b[:]) > 0
+}
+
+func (e *Engine) quoteRank(mint solana.PublicKey) int {
+	switch {
+	case e.stable[mint]:
+		return 2
+	case mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		return 1
+	}
+	return 0
+}
+
+// usdPrice returns the USD value of one token, 0 if unknown
+func (e *Engine) usdPrice(mint solana.PublicKey) float64 {
+	switch {
+	case e.stable[mint]:
+		return 1
+	case mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		return e.solUSD
+	}
+	return e.usd[mint]
+}
+
+// solPrice returns the SOL value of one token, 0 if unknown
+func (e *Engine) solPrice(mint solana.PublicKey) float64 {
+	switch {
+	case mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID):
+		return 1
+	case e.stable[mint]:
+		if e.solUSD > 0 {
+			return 1 / e.solUSD
+		}
+		return 0
+	}
+	return e.sol[mint]
+}
+
+// record updates the last price and the VWAP window of a market
+func (e *Engine) record(m Market, price, volume float64, at time.Time) {
+	state, ok := e.markets[m]
+	if !ok {
+		state = &market{}
+		e.markets[m] = state
+	}
+
+	if !at.Before(state.lastAt) {
+		state.last = price
+		state.lastAt = at
+	}
+	cutoff := state.lastAt.Add(-e.config.VWAPWindow)
+	if !at.Before(cutoff) {
+		state.samples = append(state.samples, sample{at: at, price: price, volume: volume})
+		state.sumPV += price * volume
+		state.sumV += volume
+	}
+
+	// samples arrive roughly in order, so expired samples are dropped from the front
+	drop := 0
+	for drop < len(state.samples) && state.samples[drop].at.Before(cutoff) {
+		state.sumPV -= state.samples[drop].price * state.samples[drop].volume
+		state.sumV -= state.samples[drop].volume
+		drop++
+	}
+	state.samples = state.samples[drop:]
+	if len(state.samples) == 0 {
+		state.sumPV, state.sumV = 0, 0
+	}
+}
+
+// uiAmount converts a raw token amount to whole tokens
+func uiAmount(info tx_parser.TokenInfo) float64 {
+	return float64(info.Amount) / math.Pow10(int(info.Decimals))
+}
@@ -0,0 +1,128 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	sol   = tx_parser.NATIVE_SOL_PROGRAM_ID
+	token = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+	other = solana.MustPublicKeyFromBase58("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263")
+)
+
+func swap(mintIn solana.PublicKey, amountIn uint64, decimalsIn uint8, mintOut solana.PublicKey, amountOut uint64, decimalsOut uint8) *tx_parser.SwapInfo {
+	return &tx_parser.SwapInfo{
+		Protocol: tx_parser.SwapTypeRaydium,
+		TokenIn:  tx_parser.TokenInfo{Mint: mintIn, Amount: amountIn, Decimals: decimalsIn},
+		TokenOut: tx_parser.TokenInfo{Mint: mintOut, Amount: amountOut, Decimals: decimalsOut},
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestPriceSwap(t *testing.T) {
+	e := New(Config{})
+	now := time.Unix(1_700_000_000, 0)
+
+	// sell 2 SOL for 300 USDC
+	solSwap := swap(sol, 2_000_000_000, 9, USDC_MINT, 300_000_000, 6)
+	info := e.PriceSwap(solSwap, now)
+	if solSwap.Price != info || !info.BaseMint.Equals(sol) || !info.QuoteMint.Equals(USDC_MINT) {
+		t.Fatalf("expected SOL priced in USDC, got %+v", info)
+	}
+	if !near(info.Price, 150) || !near(info.PriceUSD, 150) || !near(info.VolumeUSD, 300) || !near(info.PriceSOL, 1) {
+		t.Errorf("unexpected SOL price %+v", info)
+	}
+
+	// buy 1000 tokens for 0.5 SOL
+	info = e.PriceSwap(swap(sol, 500_000_000, 9, token, 1_000_000_000, 6), now)
+	if !info.BaseMint.Equals(token) || !near(info.Price, 0.0005) || !near(info.PriceSOL, 0.0005) {
+		t.Errorf("unexpected token price in SOL %+v", info)
+	}
+	if !near(info.PriceUSD, 0.075) || !near(info.VolumeUSD, 75) {
+		t.Errorf("expected USD via the SOL leg, got %+v", info)
+	}
+
+	// token/token swaps are valued from the leg with a known price
+	info = e.PriceSwap(swap(token, 1_000_000, 6, other, 2_000_000, 6), now)
+	if info.PriceUSD == 0 {
+		t.Errorf("expected a USD price from the known token, got %+v", info)
+	}
+	if price, ok := e.USDPrice(other); !ok || price <= 0 {
+		t.Errorf("expected a USD price for %s, got %v", other, price)
+	}
+
+	if e.PriceSwap(swap(sol, 0, 9, token, 1, 6), now) != nil {
+		t.Error("expected no price for a zero amount")
+	}
+}
+
+func TestMarketDirection(t *testing.T) {
+	e := New(Config{})
+	now := time.Unix(1_700_000_000, 0)
+
+	buy := e.PriceSwap(swap(sol, 1_000_000_000, 9, token, 2_000_000, 6), now)
+	sell := e.PriceSwap(swap(token, 2_000_000, 6, sol, 1_000_000_000, 9), now)
+	if buy.BaseMint != sell.BaseMint || !near(buy.Price, sell.Price) {
+		t.Errorf("expected buys and sells to share a market, got %+v and %+v", buy, sell)
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	e := New(Config{VWAPWindow: time.Minute})
+	start := time.Unix(1_700_000_000, 0)
+	m := Market{Protocol: tx_parser.SwapTypeRaydium, BaseMint: token, QuoteMint: sol}
+
+	// 1 token at 1 SOL, then 3 tokens at 2 SOL
+	e.PriceSwap(swap(sol, 1_000_000_000, 9, token, 1_000_000, 6), start)
+	e.PriceSwap(swap(sol, 6_000_000_000, 9, token, 3_000_000, 6), start.Add(30*time.Second))
+	if vwap, ok := e.VWAP(m); !ok || !near(vwap, 1.75) {
+		t.Errorf("expected VWAP 1.75, got %v", vwap)
+	}
+	if last, ok := e.LastPrice(m); !ok || !near(last, 2) {
+		t.Errorf("expected last price 2, got %v", last)
+	}
+
+	// the first swap leaves the window
+	e.PriceSwap(swap(sol, 4_000_000_000, 9, token, 1_000_000, 6), start.Add(90*time.Second))
+	if vwap, _ := e.VWAP(m); !near(vwap, 2.5) {
+		t.Errorf("expected VWAP 2.5 after the window moved, got %v", vwap)
+	}
+
+	// a late swap does not replace the last price
+	e.PriceSwap(swap(sol, 3_000_000_000, 9, token, 1_000_000, 6), start.Add(80*time.Second))
+	if last, _ := e.LastPrice(m); !near(last, 4) {
+		t.Errorf("expected last price 4, got %v", last)
+	}
+}
+
+func TestPriceTransaction(t *testing.T) {
+	e := New(Config{})
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	tx := &tx_parser.ParsedTransaction{
+		BlockTime: &blockTime,
+		Swaps:     []*tx_parser.SwapInfo{swap(sol, 1_000_000_000, 9, USDC_MINT, 150_000_000, 6)},
+	}
+	e.PriceTransaction(tx)
+	if tx.Swaps[0].Price == nil || !near(tx.Swaps[0].Price.PriceUSD, 150) {
+		t.Errorf("expected the swap to be priced, got %+v", tx.Swaps[0].Price)
+	}
+}
+
+func TestSetUSDPrice(t *testing.T) {
+	e := New(Config{})
+	e.SetUSDPrice(sol, 200)
+
+	info := e.PriceSwap(swap(sol, 1_000_000_000, 9, token, 100_000_000, 6), time.Now())
+	if !near(info.PriceUSD, 2) || !near(info.VolumeUSD, 200) {
+		t.Errorf("expected USD from the external SOL price, got %+v", info)
+	}
+}
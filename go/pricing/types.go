@@ -0,0 +1,26 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Config controls the pricing engine
+type Config struct {
+	// VWAPWindow is the rolling window of the volume weighted average price, defaults to 5m
+	VWAPWindow time.Duration
+
+	// StableMints are valued at one USD, defaults to USDC and USDT
+	StableMints []solana.PublicKey
+}
+
+// Market identifies a pool by protocol and token pair. Swaps do not carry the pool
+// account, so pools of the same protocol and pair share a market.
+type Market struct {
+	Protocol  tx_parser.SwapType `json:"protocol"`
+	BaseMint  solana.PublicKey   `json:"base_mint"`
+	QuoteMint solana.PublicKey   `json:"quote_mint"`
+}
@@ -0,0 +1,41 @@
+package pricing
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// ValueTransaction prices the swaps of the transaction and sets AmountUSD on its swaps
+// and transfers. Values use the prices known once the transaction's own swaps are
+// recorded, from pools or from SetUSDPrice, so transactions should be valued in order.
+func (e *Engine) ValueTransaction(tx *tx_parser.ParsedTransaction) {
+	e.PriceTransaction(tx)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, swap := range tx.Swaps {
+		swap.AmountUSD = e.swapValue(swap)
+	}
+	for _, transfer := range tx.Transfers {
+		transfer.AmountUSD = e.value(transfer.Mint, transfer.Amount, transfer.Decimals)
+	}
+}
+
+// swapValue values the swap by the quote side of its price, falling back to whichever
+// side has a known USD price
+func (e *Engine) swapValue(swap *tx_parser.SwapInfo) float64 {
+	if swap.Price != nil && swap.Price.VolumeUSD > 0 {
+		return swap.Price.VolumeUSD
+	}
+	if value := e.value(swap.TokenIn.Mint, swap.TokenIn.Amount, swap.TokenIn.Decimals); value > 0 {
+		return value
+	}
+	return e.value(swap.TokenOut.Mint, swap.TokenOut.Amount, swap.TokenOut.Decimals)
+}
+
+// value returns the USD value of a raw token amount, 0 if the mint has no known price
+func (e *Engine) value(mint solana.PublicKey, amount uint64, decimals uint8) float64 {
+	return uiAmount(tx_parser.TokenInfo{Amount: amount, Decimals: decimals}) * e.usdPrice(mint)
+}
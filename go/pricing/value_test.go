@@ -0,0 +1,52 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+func TestValueTransaction(t *testing.T) {
+	e := New(Config{})
+	e.SetUSDPrice(sol, 150)
+
+	// buy 1000 tokens for 0.5 SOL, then move 100 of them and 1 SOL
+	tx := &tx_parser.ParsedTransaction{
+		Swaps: []*tx_parser.SwapInfo{
+			swap(sol, 500_000_000, 9, token, 1_000_000_000, 6),
+			swap(other, 1_000, 0, USDC_MINT, 0, 6),
+		},
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeToken, Mint: token, Amount: 100_000_000, Decimals: 6},
+			{Type: tx_parser.TransferTypeSOL, Mint: sol, Amount: 1_000_000_000, Decimals: 9},
+			{Type: tx_parser.TransferTypeToken, Mint: other, Amount: 5, Decimals: 0},
+		},
+	}
+	e.ValueTransaction(tx)
+
+	if swap := tx.Swaps[0]; !near(swap.AmountUSD, 75) || swap.Price == nil {
+		t.Errorf("expected the swap valued at 75 USD, got %v", swap.AmountUSD)
+	}
+	if swap := tx.Swaps[1]; swap.AmountUSD != 0 {
+		t.Errorf("expected an unpriced swap to have no value, got %v", swap.AmountUSD)
+	}
+	if transfer := tx.Transfers[0]; !near(transfer.AmountUSD, 7.5) {
+		t.Errorf("expected the token transfer valued from the swap price, got %v", transfer.AmountUSD)
+	}
+	if transfer := tx.Transfers[1]; !near(transfer.AmountUSD, 150) {
+		t.Errorf("expected the SOL transfer valued from the oracle price, got %v", transfer.AmountUSD)
+	}
+	if transfer := tx.Transfers[2]; transfer.AmountUSD != 0 {
+		t.Errorf("expected a transfer of an unpriced mint to have no value, got %v", transfer.AmountUSD)
+	}
+
+	// a swap with only the output priced is valued by its output
+	e.SetUSDPrice(other, 2)
+	out := swap(token, 0, 6, other, 10, 0)
+	out.Timestamp = time.Unix(1_700_000_000, 0)
+	e.ValueTransaction(&tx_parser.ParsedTransaction{Swaps: []*tx_parser.SwapInfo{out}})
+	if !near(out.AmountUSD, 20) {
+		t.Errorf("expected the swap valued by its output, got %v", out.AmountUSD)
+	}
+}
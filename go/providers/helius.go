@@ -0,0 +1,274 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// heliusSources maps the Helius sources of swaps to protocols
+var heliusSources = map[string]tx_parser.SwapType{
+	"JUPITER":        tx_parser.SwapTypeJupiter,
+	"RAYDIUM":        tx_parser.SwapTypeRaydium,
+	"ORCA":           tx_parser.SwapTypeOrca,
+	"METEORA":        tx_parser.SwapTypeMeteora,
+	"PUMP_FUN":       tx_parser.SwapTypePumpFun,
+	"MOONSHOT":       tx_parser.SwapTypeMoonshot,
+	"OKX_DEX_ROUTER": tx_parser.SwapTypeOKX,
+	"SABER":          tx_parser.SwapTypeSaber,
+	"MERCURIAL":      tx_parser.SwapTypeMercurial,
+	"INVARIANT":      tx_parser.SwapTypeInvariant,
+	"CREMA":          tx_parser.SwapTypeCrema,
+	"FLUXBEAM":       tx_parser.SwapTypeFluxBeam,
+	"GOOSEFX":        tx_parser.SwapTypeGooseFX,
+	"STABBLE":        tx_parser.SwapTypeStabble,
+}
+
+// heliusCompressed maps the Helius types of compressed NFT events
+var heliusCompressed = map[string]tx_parser.CompressedNftEventType{
+	"COMPRESSED_NFT_MINT":     tx_parser.CompressedNftMint,
+	"COMPRESSED_NFT_TRANSFER": tx_parser.CompressedNftTransfer,
+	"COMPRESSED_NFT_BURN":     tx_parser.CompressedNftBurn,
+}
+
+// HeliusEnhanced maps Helius enhanced transactions, as delivered by enhanced webhooks or
+// returned by the parsed transactions API, into parsed transactions. The payloads carry
+// the swap of the fee payer, transfers, compressed NFT events and the failure, without
+// instruction indexes, which are set to -1.
+type HeliusEnhanced struct {
+	config HeliusConfig
+}
+
+// NewHeliusEnhanced creates a Helius enhanced transaction adapter
+func NewHeliusEnhanced(config HeliusConfig) *HeliusEnhanced {
+	return &HeliusEnhanced{config: config}
+}
+
+// Decode maps a JSON array of enhanced transactions
+func (h *HeliusEnhanced) Decode(payload []byte) ([]*tx_parser.ParsedTransaction, error) {
+	var enhanced []*EnhancedTransaction
+	if err := json.Unmarshal(payload, &enhanced); err != nil {
+		return nil, fmt.Errorf("failed to decode enhanced transactions: %w", err)
+	}
+	parsed := make([]*tx_parser.ParsedTransaction, 0, len(enhanced))
+	for _, tx := range enhanced {
+		parsed = append(parsed, h.Convert(tx))
+	}
+	return parsed, nil
+}
+
+// Convert maps an enhanced transaction. Token transfers whose decimals are unknown are
+// reported in Errors.
+func (h *HeliusEnhanced) Convert(tx *EnhancedTransaction) *tx_parser.ParsedTransaction {
+	parsed := &tx_parser.ParsedTransaction{
+		Signature: tx.Signature,
+		Slot:      tx.Slot,
+		FeePayer:  tx.FeePayer,
+		Fee:       tx.Fee,
+	}
+	if tx.Timestamp > 0 {
+		blockTime := solana.UnixTimeSeconds(tx.Timestamp)
+		parsed.BlockTime = &blockTime
+	}
+
+	// decimals of the mints the payload reveals them for
+	decimals := make(map[solana.PublicKey]uint8)
+	for _, account := range tx.AccountData {
+		for _, change := range account.TokenBalanceChanges {
+			decimals[change.Mint] = change.RawTokenAmount.Decimals
+		}
+	}
+
+	for _, transfer := range tx.NativeTransfers {
+		parsed.Transfers = append(parsed.Transfers, &tx_parser.TransferInfo{
+			Type:             tx_parser.TransferTypeSOL,
+			Program:          solana.SystemProgramID,
+			InstructionIndex: -1,
+			InnerIndex:       -1,
+			Mint:             tx_parser.NATIVE_SOL_PROGRAM_ID,
+			Source:           transfer.FromUserAccount.PublicKey(),
+			Destination:      transfer.ToUserAccount.PublicKey(),
+			SourceOwner:      transfer.FromUserAccount.PublicKey(),
+			DestinationOwner: transfer.ToUserAccount.PublicKey(),
+			Authority:        transfer.FromUserAccount.PublicKey(),
+			Amount:           uint64(transfer.Amount),
+			Decimals:         9,
+		})
+	}
+	for _, transfer := range tx.TokenTransfers {
+		info, err := h.tokenTransfer(transfer, decimals)
+		if err != nil {
+			parsed.Errors = append(parsed.Errors, &tx_parser.ParseError{
+				Protocol:         tx_parser.SwapTypeUnknown,
+				InstructionIndex: -1,
+				InnerIndex:       -1,
+				Err:              err,
+			})
+			continue
+		}
+		parsed.Transfers = append(parsed.Transfers, info)
+	}
+	parsed.JitoTip = tx_parser.JitoTip(parsed.Transfers)
+
+	if swap := heliusSwap(tx); swap != nil {
+		parsed.Swaps = append(parsed.Swaps, swap)
+	}
+	for _, event := range tx.Events.Compressed {
+		if compressed := heliusCompressedNft(event); compressed != nil {
+			parsed.CompressedNfts = append(parsed.CompressedNfts, compressed)
+		}
+	}
+	parsed.Failure = heliusFailure(tx.TransactionError)
+	return parsed
+}
+
+// tokenTransfer converts the token amount of a transfer to base units
+func (h *HeliusEnhanced) tokenTransfer(transfer TokenTransfer, known map[solana.PublicKey]uint8) (*tx_parser.TransferInfo, error) {
+	decimals, ok := known[transfer.Mint]
+	if !ok {
+		if h.config.Decimals == nil {
+			return nil, fmt.Errorf("decimals of %s are unknown", transfer.Mint)
+		}
+		var err error
+		if decimals, err = h.config.Decimals.MintDecimals(transfer.Mint); err != nil {
+			return nil, fmt.Errorf("failed to resolve decimals of %s: %w", transfer.Mint, err)
+		}
+	}
+	amount, err := baseUnits(transfer.TokenAmount.String(), decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount of %s: %w", transfer.Mint, err)
+	}
+
+	return &tx_parser.TransferInfo{
+		Type:             tx_parser.TransferTypeToken,
+		InstructionIndex: -1,
+		InnerIndex:       -1,
+		Mint:             transfer.Mint,
+		Source:           transfer.FromTokenAccount.PublicKey(),
+		Destination:      transfer.ToTokenAccount.PublicKey(),
+		SourceOwner:      transfer.FromUserAccount.PublicKey(),
+		DestinationOwner: transfer.ToUserAccount.PublicKey(),
+		Authority:        transfer.FromUserAccount.PublicKey(),
+		Amount:           amount,
+		Decimals:         decimals,
+	}, nil
+}
+
+// heliusSwap maps the swap event, nil if there is none or a side is missing
+func heliusSwap(tx *EnhancedTransaction) *tx_parser.SwapInfo {
+	event := tx.Events.Swap
+	if event == nil {
+		return nil
+	}
+	in, inOK := swapSide(event.NativeInput, event.TokenInputs)
+	out, outOK := swapSide(event.NativeOutput, event.TokenOutputs)
+	if !inOK || !outOK {
+		return nil
+	}
+
+	protocol, ok := heliusSources[tx.Source]
+	if !ok {
+		protocol = tx_parser.SwapTypeUnknown
+	}
+	swap := &tx_parser.SwapInfo{
+		Protocol:         protocol,
+		Signers:          []solana.PublicKey{tx.FeePayer},
+		Signatures:       []solana.Signature{tx.Signature},
+		TokenIn:          in,
+		TokenOut:         out,
+		InstructionIndex: -1,
+	}
+	if tx.Timestamp > 0 {
+		swap.Timestamp = time.Unix(tx.Timestamp, 0)
+	}
+	return swap
+}
+
+// swapSide returns the SOL amount of a side, or its first token
+func swapSide(native *NativeAmount, tokens []TokenBalance) (tx_parser.TokenInfo, bool) {
+	if native != nil && native.Amount > 0 {
+		return tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: uint64(native.Amount), Decimals: 9}, true
+	}
+	for _, token := range tokens {
+		amount, err := baseUnits(token.RawTokenAmount.TokenAmount, 0)
+		if err != nil || amount == 0 {
+			continue
+		}
+		return tx_parser.TokenInfo{Mint: token.Mint, Amount: amount, Decimals: token.RawTokenAmount.Decimals}, true
+	}
+	return tx_parser.TokenInfo{}, false
+}
+
+func heliusCompressedNft(event CompressedEvent) *tx_parser.CompressedNftEvent {
+	eventType, ok := heliusCompressed[event.Type]
+	if !ok {
+		return nil
+	}
+	compressed := &tx_parser.CompressedNftEvent{
+		Type:             eventType,
+		InstructionIndex: event.InstructionIndex,
+		InnerIndex:       event.InnerInstructionIndex,
+		Tree:             event.TreeID.PublicKey(),
+		LeafIndex:        event.LeafIndex,
+		AssetID:          event.AssetID.PublicKey(),
+		Delegate:         event.NewLeafDelegate.PublicKey(),
+	}
+	switch eventType {
+	case tx_parser.CompressedNftMint:
+		compressed.Owner = event.NewLeafOwner.PublicKey()
+	case tx_parser.CompressedNftTransfer:
+		compressed.Owner = event.OldLeafOwner.PublicKey()
+		compressed.NewOwner = event.NewLeafOwner.PublicKey()
+	case tx_parser.CompressedNftBurn:
+		compressed.Owner = event.OldLeafOwner.PublicKey()
+	}
+	if event.Metadata != nil {
+		compressed.Name, compressed.Symbol, compressed.URI = event.Metadata.Name, event.Metadata.Symbol, event.Metadata.URI
+	}
+	return compressed
+}
+
+// heliusFailure maps the transaction error, an object with an error message or null
+func heliusFailure(transactionError json.RawMessage) *tx_parser.TransactionFailure {
+	if len(transactionError) == 0 || string(transactionError) == "null" {
+		return nil
+	}
+	message := string(transactionError)
+	var wrapped struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(transactionError, &wrapped) == nil && wrapped.Error != "" {
+		message = wrapped.Error
+	}
+	return &tx_parser.TransactionFailure{
+		Kind:             tx_parser.FailureOther,
+		Error:            message,
+		InstructionIndex: -1,
+		InnerIndex:       -1,
+	}
+}
+
+// baseUnits converts a decimal amount in tokens to base units
+func baseUnits(tokens string, decimals uint8) (uint64, error) {
+	value, _, err := big.ParseFloat(tokens, 10, 256, big.ToNearestEven)
+	if err != nil {
+		return 0, err
+	}
+	if value.Sign() < 0 {
+		return 0, errors.New("negative amount")
+	}
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	value.Mul(value, scale).Add(value, big.NewFloat(0.5))
+	if value.Cmp(new(big.Float).SetUint64(math.MaxUint64)) > 0 {
+		return 0, errors.New("amount out of range")
+	}
+	units, _ := value.Uint64()
+	return units, nil
+}
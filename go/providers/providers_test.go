@@ -0,0 +1,188 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	wallet   = solana.NewWallet().PublicKey()
+	pool     = solana.NewWallet().PublicKey()
+	mint     = solana.NewWallet().PublicKey()
+	unknown  = solana.NewWallet().PublicKey()
+	tree     = solana.NewWallet().PublicKey()
+	asset    = solana.NewWallet().PublicKey()
+	receiver = solana.NewWallet().PublicKey()
+)
+
+// decimalsOf resolves the decimals of the unknown mint
+type decimalsOf map[solana.PublicKey]uint8
+
+func (d decimalsOf) MintDecimals(mint solana.PublicKey) (uint8, error) {
+	if decimals, ok := d[mint]; ok {
+		return decimals, nil
+	}
+	return 0, errors.New("mint not found")
+}
+
+func enhancedPayload(t *testing.T) []byte {
+	t.Helper()
+	return []byte(fmt.Sprintf(`[{
+		"description": "swapped 1.5 SOL for 2500.5 tokens",
+		"type": "SWAP",
+		"source": "RAYDIUM",
+		"fee": 5000,
+		"feePayer": %[1]q,
+		"signature": %[6]q,
+		"slot": 300,
+		"timestamp": 1700000000,
+		"nativeTransfers": [{"fromUserAccount": %[1]q, "toUserAccount": %[2]q, "amount": 1500000000}],
+		"tokenTransfers": [
+			{"fromUserAccount": %[2]q, "toUserAccount": %[1]q, "fromTokenAccount": %[2]q, "toTokenAccount": %[1]q, "tokenAmount": 2500.5, "mint": %[3]q, "tokenStandard": "Fungible"},
+			{"fromUserAccount": %[1]q, "toUserAccount": %[2]q, "fromTokenAccount": "", "toTokenAccount": "", "tokenAmount": 0.25, "mint": %[4]q, "tokenStandard": "Fungible"}
+		],
+		"accountData": [{"account": %[1]q, "nativeBalanceChange": -1500005000, "tokenBalanceChanges": [
+			{"userAccount": %[1]q, "tokenAccount": %[1]q, "mint": %[3]q, "rawTokenAmount": {"tokenAmount": "2500500000", "decimals": 6}}
+		]}],
+		"transactionError": null,
+		"events": {
+			"swap": {
+				"nativeInput": {"account": %[1]q, "amount": "1500000000"},
+				"nativeOutput": null,
+				"tokenInputs": [],
+				"tokenOutputs": [{"userAccount": %[1]q, "tokenAccount": %[1]q, "mint": %[3]q, "rawTokenAmount": {"tokenAmount": "2500500000", "decimals": 6}}]
+			},
+			"compressed": [{"type": "COMPRESSED_NFT_TRANSFER", "treeId": %[5]q, "assetId": %[7]q, "leafIndex": 42, "instructionIndex": 1, "innerInstructionIndex": 0,
+				"newLeafOwner": %[8]q, "oldLeafOwner": %[1]q, "newLeafDelegate": null, "metadata": null}]
+		}
+	}, {"signature": %[6]q, "feePayer": %[1]q, "slot": 301, "transactionError": {"error": "custom program error: 0x1771"}, "events": {}}]`,
+		wallet, pool, mint, unknown, tree, solana.Signature{1}, asset, receiver))
+}
+
+func TestHeliusEnhanced(t *testing.T) {
+	txs, err := NewHeliusEnhanced(HeliusConfig{}).Decode(enhancedPayload(t))
+	if err != nil || len(txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d: %v", len(txs), err)
+	}
+	tx := txs[0]
+	if tx.Slot != 300 || tx.Fee != 5000 || !tx.FeePayer.Equals(wallet) || tx.BlockTime == nil || *tx.BlockTime != 1_700_000_000 || tx.Failure != nil {
+		t.Errorf("unexpected transaction %+v", tx)
+	}
+
+	if len(tx.Swaps) != 1 {
+		t.Fatalf("expected a swap, got %d", len(tx.Swaps))
+	}
+	swap := tx.Swaps[0]
+	if swap.Protocol != tx_parser.SwapTypeRaydium || !swap.TokenIn.Mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) || swap.TokenIn.Amount != 1_500_000_000 ||
+		!swap.TokenOut.Mint.Equals(mint) || swap.TokenOut.Amount != 2_500_500_000 || swap.TokenOut.Decimals != 6 {
+		t.Errorf("unexpected swap %+v", swap)
+	}
+
+	// the transfer of the mint without a balance change is reported
+	if len(tx.Transfers) != 2 || len(tx.Errors) != 1 || !strings.Contains(tx.Errors[0].Error(), unknown.String()) {
+		t.Fatalf("expected the SOL and known token transfers, got %d transfers and errors %v", len(tx.Transfers), tx.Errors)
+	}
+	if transfer := tx.Transfers[1]; transfer.Amount != 2_500_500_000 || transfer.Decimals != 6 || !transfer.DestinationOwner.Equals(wallet) {
+		t.Errorf("unexpected token transfer %+v", transfer)
+	}
+
+	if len(tx.CompressedNfts) != 1 {
+		t.Fatalf("expected a compressed NFT event, got %d", len(tx.CompressedNfts))
+	}
+	if event := tx.CompressedNfts[0]; event.Type != tx_parser.CompressedNftTransfer || !event.Owner.Equals(wallet) || !event.NewOwner.Equals(receiver) ||
+		!event.AssetID.Equals(asset) || event.LeafIndex != 42 || !event.Delegate.IsZero() {
+		t.Errorf("unexpected compressed NFT event %+v", event)
+	}
+
+	if failure := txs[1].Failure; failure == nil || failure.Error != "custom program error: 0x1771" || failure.InstructionIndex != -1 {
+		t.Errorf("unexpected failure %+v", failure)
+	}
+
+	// with a resolver the unknown transfer is kept
+	txs, _ = NewHeliusEnhanced(HeliusConfig{Decimals: decimalsOf{unknown: 9}}).Decode(enhancedPayload(t))
+	if transfers := txs[0].Transfers; len(transfers) != 3 || transfers[2].Amount != 250_000_000 {
+		t.Errorf("expected the resolved transfer of 0.25 tokens, got %+v", transfers)
+	}
+}
+
+func rawPayload(t *testing.T, lamports uint64) []byte {
+	t.Helper()
+	tx, err := solana.NewTransaction([]solana.Instruction{
+		system.NewTransferInstruction(lamports, wallet, receiver).Build(),
+	}, solana.Hash{1}, solana.TransactionPayer(wallet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Signatures = []solana.Signature{{2}}
+	encoded, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(fmt.Sprintf(`{"slot": 400, "blockTime": 1700000100, "version": "legacy", "transaction": %s,
+		"meta": {"err": null, "fee": 5000, "preBalances": [%d, 0, 1], "postBalances": [%d, %d, 1], "innerInstructions": [], "logMessages": [],
+			"preTokenBalances": [], "postTokenBalances": [], "loadedAddresses": {"readonly": [], "writable": []}}}`,
+		encoded, lamports+10_000, 5_000, lamports))
+}
+
+func TestRaw(t *testing.T) {
+	payload := rawPayload(t, 1_000_000)
+	for _, body := range [][]byte{payload, []byte("[" + string(payload) + `, {"slot": 401}]`)} {
+		txs, err := NewRaw(tx_parser.ParseOptions{}).Decode(body)
+		if len(txs) != 1 {
+			t.Fatalf("expected a parsed transaction, got %d: %v", len(txs), err)
+		}
+		tx := txs[0]
+		if tx.Slot != 400 || !tx.FeePayer.Equals(wallet) || len(tx.Transfers) != 1 || tx.Transfers[0].Amount != 1_000_000 {
+			t.Errorf("unexpected transaction %+v", tx)
+		}
+		if body[0] == '[' && err == nil {
+			t.Error("expected the transaction without metadata to be reported")
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var received []*tx_parser.ParsedTransaction
+	failing := false
+	handler := Handler(NewHeliusEnhanced(HeliusConfig{}), WebhookConfig{Authorization: "secret"}, func(ctx context.Context, txs []*tx_parser.ParsedTransaction) error {
+		if failing {
+			return errors.New("sink unavailable")
+		}
+		received = append(received, txs...)
+		return nil
+	})
+
+	deliver := func(authorization, body string) int {
+		request := httptest.NewRequest(http.MethodPost, "/webhooks/helius", strings.NewReader(body))
+		request.Header.Set("Authorization", authorization)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	payload := string(enhancedPayload(t))
+	if code := deliver("wrong", payload); code != http.StatusUnauthorized {
+		t.Errorf("expected an unauthorized delivery, got %d", code)
+	}
+	if code := deliver("secret", "{not json"); code != http.StatusBadRequest {
+		t.Errorf("expected an invalid payload, got %d", code)
+	}
+	if code := deliver("secret", payload); code != http.StatusOK || len(received) != 2 {
+		t.Errorf("expected 2 transactions delivered, got %d with %d", code, len(received))
+	}
+	failing = true
+	if code := deliver("secret", payload); code != http.StatusInternalServerError {
+		t.Errorf("expected a failed delivery to be retried, got %d", code)
+	}
+}
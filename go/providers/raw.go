@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Raw parses transactions in the getTransaction format with the local parser, as
+// delivered by Helius raw webhooks and other vendors relaying unparsed transactions.
+// Vendors streaming Yellowstone gRPC, such as Triton, are read with the geyser package
+// instead.
+type Raw struct {
+	options tx_parser.ParseOptions
+}
+
+// NewRaw creates a raw transaction adapter
+func NewRaw(options tx_parser.ParseOptions) *Raw {
+	return &Raw{options: options}
+}
+
+// Decode parses a JSON array of transactions, or a single transaction. Transactions that
+// fail to parse are left out and reported in the error.
+func (r *Raw) Decode(payload []byte) ([]*tx_parser.ParsedTransaction, error) {
+	var results []*rpc.GetTransactionResult
+	if trimmed := bytes.TrimSpace(payload); len(trimmed) > 0 && trimmed[0] == '{' {
+		var result rpc.GetTransactionResult
+		if err := json.Unmarshal(trimmed, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction: %w", err)
+		}
+		results = append(results, &result)
+	} else if err := json.Unmarshal(payload, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
+	}
+
+	parsed := make([]*tx_parser.ParsedTransaction, 0, len(results))
+	var errs []error
+	for i, result := range results {
+		parser, err := tx_parser.NewWithOptions(result, r.options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create parser for transaction %d: %w", i, err))
+			continue
+		}
+		tx, err := parser.Parse()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse transaction %d: %w", i, err))
+			continue
+		}
+		parsed = append(parsed, tx)
+	}
+	return parsed, errors.Join(errs...)
+}
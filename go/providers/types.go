@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/decimals"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+)
+
+// Adapter maps the transaction payloads of a provider, e.g. webhook deliveries, into
+// parsed transactions
+type Adapter interface {
+	// Decode maps a payload into parsed transactions. Transactions that fail to map are
+	// reported in the error alongside the transactions that did.
+	Decode(payload []byte) ([]*tx_parser.ParsedTransaction, error)
+}
+
+// Decimals resolves mint decimals, implemented by decimals.Resolver
+type Decimals interface {
+	MintDecimals(mint solana.PublicKey) (uint8, error)
+}
+
+var (
+	_ Adapter  = (*HeliusEnhanced)(nil)
+	_ Adapter  = (*Raw)(nil)
+	_ Decimals = (*decimals.Resolver)(nil)
+)
+
+// HeliusConfig controls the Helius enhanced transaction adapter
+type HeliusConfig struct {
+	// Decimals resolves the decimals of token transfers whose mint has no balance change
+	// in the payload. Without it such transfers are reported in Errors and left out.
+	Decimals Decimals
+}
+
+// WebhookConfig controls a webhook handler
+type WebhookConfig struct {
+	// Authorization is compared to the Authorization header of every delivery, e.g. the
+	// auth header configured on a Helius webhook. Empty accepts every delivery.
+	Authorization string
+
+	// MaxBodySize bounds a delivery, defaults to 16 MiB
+	MaxBodySize int64
+
+	Logger logging.Logger
+}
+
+// EnhancedTransaction is a transaction in the Helius enhanced format, as delivered by
+// enhanced webhooks and the parsed transactions API
+type EnhancedTransaction struct {
+	Description      string           `json:"description"`
+	Type             string           `json:"type"`   // e.g. SWAP, TRANSFER or COMPRESSED_NFT_MINT
+	Source           string           `json:"source"` // program or marketplace, e.g. RAYDIUM or JUPITER
+	Fee              uint64           `json:"fee"`
+	FeePayer         solana.PublicKey `json:"feePayer"`
+	Signature        solana.Signature `json:"signature"`
+	Slot             uint64           `json:"slot"`
+	Timestamp        int64            `json:"timestamp"`
+	NativeTransfers  []NativeTransfer `json:"nativeTransfers"`
+	TokenTransfers   []TokenTransfer  `json:"tokenTransfers"`
+	AccountData      []AccountData    `json:"accountData"`
+	TransactionError json.RawMessage  `json:"transactionError"`
+	Events           Events           `json:"events"`
+}
+
+// NativeTransfer is a transfer of lamports
+type NativeTransfer struct {
+	FromUserAccount Address `json:"fromUserAccount"`
+	ToUserAccount   Address `json:"toUserAccount"`
+	Amount          Amount  `json:"amount"`
+}
+
+// TokenTransfer is a token transfer, with its amount in tokens rather than base units
+type TokenTransfer struct {
+	FromUserAccount  Address          `json:"fromUserAccount"`
+	ToUserAccount    Address          `json:"toUserAccount"`
+	FromTokenAccount Address          `json:"fromTokenAccount"`
+	ToTokenAccount   Address          `json:"toTokenAccount"`
+	TokenAmount      json.Number      `json:"tokenAmount"`
+	Mint             solana.PublicKey `json:"mint"`
+	TokenStandard    string           `json:"tokenStandard"`
+}
+
+// AccountData is the balance change of an account
+type AccountData struct {
+	Account             Address              `json:"account"`
+	NativeBalanceChange int64                `json:"nativeBalanceChange"`
+	TokenBalanceChanges []TokenBalanceChange `json:"tokenBalanceChanges"`
+}
+
+// TokenBalanceChange is the change of a token account's balance
+type TokenBalanceChange struct {
+	UserAccount    Address          `json:"userAccount"`
+	TokenAccount   Address          `json:"tokenAccount"`
+	Mint           solana.PublicKey `json:"mint"`
+	RawTokenAmount RawTokenAmount   `json:"rawTokenAmount"`
+}
+
+// RawTokenAmount is an amount in base units, signed for balance changes
+type RawTokenAmount struct {
+	TokenAmount string `json:"tokenAmount"`
+	Decimals    uint8  `json:"decimals"`
+}
+
+// Events are the actions Helius decoded from the transaction
+type Events struct {
+	Swap       *SwapEvent        `json:"swap"`
+	Compressed []CompressedEvent `json:"compressed"`
+}
+
+// SwapEvent is the net swap of the fee payer
+type SwapEvent struct {
+	NativeInput  *NativeAmount  `json:"nativeInput"`
+	NativeOutput *NativeAmount  `json:"nativeOutput"`
+	TokenInputs  []TokenBalance `json:"tokenInputs"`
+	TokenOutputs []TokenBalance `json:"tokenOutputs"`
+}
+
+// NativeAmount is an amount of lamports
+type NativeAmount struct {
+	Account Address `json:"account"`
+	Amount  Amount  `json:"amount"`
+}
+
+// TokenBalance is an amount of a token of an account
+type TokenBalance struct {
+	UserAccount    Address          `json:"userAccount"`
+	TokenAccount   Address          `json:"tokenAccount"`
+	Mint           solana.PublicKey `json:"mint"`
+	RawTokenAmount RawTokenAmount   `json:"rawTokenAmount"`
+}
+
+// CompressedEvent is a Bubblegum compressed NFT action
+type CompressedEvent struct {
+	Type                  string  `json:"type"` // COMPRESSED_NFT_MINT, COMPRESSED_NFT_TRANSFER or COMPRESSED_NFT_BURN
+	TreeID                Address `json:"treeId"`
+	AssetID               Address `json:"assetId"`
+	LeafIndex             uint64  `json:"leafIndex"`
+	InstructionIndex      int     `json:"instructionIndex"`
+	InnerInstructionIndex int     `json:"innerInstructionIndex"`
+	NewLeafOwner          Address `json:"newLeafOwner"`
+	OldLeafOwner          Address `json:"oldLeafOwner"`
+	NewLeafDelegate       Address `json:"newLeafDelegate"`
+	Metadata              *struct {
+		Name   string `json:"name"`
+		Symbol string `json:"symbol"`
+		URI    string `json:"uri"`
+	} `json:"metadata"`
+}
+
+// Address is a public key that payloads leave empty or null when unset
+type Address solana.PublicKey
+
+// PublicKey returns the address as a public key, zero when unset
+func (a Address) PublicKey() solana.PublicKey {
+	return solana.PublicKey(a)
+}
+
+func (a *Address) UnmarshalJSON(data []byte) error {
+	var text *string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if text == nil || *text == "" {
+		*a = Address{}
+		return nil
+	}
+	key, err := solana.PublicKeyFromBase58(*text)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", *text, err)
+	}
+	*a = Address(key)
+	return nil
+}
+
+func (a Address) MarshalJSON() ([]byte, error) {
+	if solana.PublicKey(a).IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(solana.PublicKey(a).String())
+}
+
+// Amount is an amount in base units, sent as a string or a number
+type Amount uint64
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "null" {
+		*a = 0
+		return nil
+	}
+	value, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", data, err)
+	}
+	*a = Amount(value)
+	return nil
+}
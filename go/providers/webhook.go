@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+)
+
+// Handler returns an HTTP handler receiving webhook deliveries, decoding them with the
+// adapter and passing the transactions to handle. Deliveries handle fails for are
+// answered with a server error so the provider retries them. Transactions the adapter
+// fails to map are logged and skipped.
+func Handler(adapter Adapter, config WebhookConfig, handle func(ctx context.Context, txs []*tx_parser.ParsedTransaction) error) http.Handler {
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = 16 << 20
+	}
+	logger := logging.OrNop(config.Logger)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if config.Authorization != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(config.Authorization)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.MaxBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "failed to read payload", http.StatusBadRequest)
+			return
+		}
+
+		txs, err := adapter.Decode(payload)
+		if err != nil {
+			if len(txs) == 0 {
+				logger.WarnContext(r.Context(), "failed to decode webhook payload", logging.ERROR_KEY, err)
+				http.Error(w, "invalid payload", http.StatusBadRequest)
+				return
+			}
+			logger.WarnContext(r.Context(), "skipped webhook transactions", logging.ERROR_KEY, err)
+		}
+		if len(txs) > 0 {
+			if err := handle(r.Context(), txs); err != nil {
+				logger.ErrorContext(r.Context(), "failed to handle webhook transactions", logging.ERROR_KEY, err, "transactions", len(txs))
+				http.Error(w, "failed to handle transactions", http.StatusInternalServerError)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
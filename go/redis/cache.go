@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/decimals"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/tokenmeta"
+)
+
+// cache stores JSON values under a key prefix. The adapters' interfaces take no context,
+// so requests are bounded by the client's timeout only, and failed lookups are misses.
+type cache struct {
+	client *Client
+	config CacheConfig
+}
+
+func newCache(client *Client, config CacheConfig, prefix string) cache {
+	if config.Prefix == "" {
+		config.Prefix = prefix
+	}
+	return cache{client: client, config: config}
+}
+
+// get decodes the value of the key, false if it is missing or unreadable
+func (c cache) get(key string, value any) bool {
+	reply, err := c.client.Do(context.Background(), "GET", c.config.Prefix+key)
+	data, ok := reply.(string)
+	if err != nil || !ok {
+		return false
+	}
+	return json.Unmarshal([]byte(data), value) == nil
+}
+
+// put encodes and stores the value with the TTL
+func (c cache) put(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	args := []string{"SET", c.config.Prefix + key, string(data)}
+	if c.config.TTL > 0 {
+		args = append(args, "PX", strconv.FormatInt(c.config.TTL.Milliseconds(), 10))
+	}
+	_, err = c.client.Do(context.Background(), args...)
+	return err
+}
+
+// DecimalsCache shares resolved mint decimals between processes, as the persistent cache
+// of a decimals resolver
+type DecimalsCache struct {
+	cache
+}
+
+var _ decimals.PersistentCache = (*DecimalsCache)(nil)
+
+// NewDecimalsCache creates a decimals cache, keys default to the
+// "solana-toolkit:decimals:" prefix
+func NewDecimalsCache(client *Client, config CacheConfig) *DecimalsCache {
+	return &DecimalsCache{newCache(client, config, "solana-toolkit:decimals:")}
+}
+
+// Get returns the cached decimals of the mint
+func (c *DecimalsCache) Get(mint solana.PublicKey) (uint8, bool) {
+	var value uint8
+	ok := c.get(mint.String(), &value)
+	return value, ok
+}
+
+// Put stores the decimals of the mint
+func (c *DecimalsCache) Put(mint solana.PublicKey, value uint8) error {
+	return c.put(mint.String(), value)
+}
+
+// MetadataCache shares token metadata between processes, as the persistent cache of a
+// tokenmeta fetcher
+type MetadataCache struct {
+	cache
+}
+
+var _ tokenmeta.PersistentCache = (*MetadataCache)(nil)
+
+// NewMetadataCache creates a metadata cache, keys default to the
+// "solana-toolkit:metadata:" prefix. Set a TTL so mutable metadata is read again.
+func NewMetadataCache(client *Client, config CacheConfig) *MetadataCache {
+	return &MetadataCache{newCache(client, config, "solana-toolkit:metadata:")}
+}
+
+// Get returns the cached metadata of the mint, nil for mints known to have none
+func (c *MetadataCache) Get(mint solana.PublicKey) (*tokenmeta.Metadata, bool) {
+	var metadata *tokenmeta.Metadata
+	ok := c.get(mint.String(), &metadata)
+	return metadata, ok
+}
+
+// Put stores the metadata of the mint
+func (c *MetadataCache) Put(mint solana.PublicKey, metadata *tokenmeta.Metadata) error {
+	return c.put(mint.String(), metadata)
+}
+
+// PoolCache shares discovered pools between processes, as the persistent cache of a pool
+// registry
+type PoolCache struct {
+	cache
+}
+
+var _ pools.PairCache = (*PoolCache)(nil)
+
+// NewPoolCache creates a pool cache, keys default to the "solana-toolkit:pools:" prefix.
+// Set a TTL so pools created later are discovered.
+func NewPoolCache(client *Client, config CacheConfig) *PoolCache {
+	return &PoolCache{newCache(client, config, "solana-toolkit:pools:")}
+}
+
+// Get returns the cached pools of the pair
+func (c *PoolCache) Get(mintA, mintB solana.PublicKey) ([]pools.Pool, bool) {
+	var found []pools.Pool
+	ok := c.get(pairKey(mintA, mintB), &found)
+	return found, ok
+}
+
+// Put stores the pools of the pair
+func (c *PoolCache) Put(mintA, mintB solana.PublicKey, found []pools.Pool) error {
+	if found == nil {
+		found = []pools.Pool{}
+	}
+	return c.put(pairKey(mintA, mintB), found)
+}
+
+// pairKey orders the mints so a pair has one key
+func pairKey(a, b solana.PublicKey) string {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return a.String() + ":" + b.String()
+}
@@ -0,0 +1,250 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Error is an error reply of the server
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client is a minimal RESP client keeping a pool of connections. Replies are returned as
+// string for simple and bulk strings, int64 for integers, []any for arrays, nil for null
+// replies and Error for error replies. It is safe for concurrent use.
+type Client struct {
+	config Config
+	idle   chan *conn
+	slots  chan struct{} // one per open connection
+	closed atomic.Bool
+}
+
+// conn is a pooled connection
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// New creates a client, connecting on first use
+func New(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = 8
+	}
+	return &Client{
+		config: config,
+		idle:   make(chan *conn, config.PoolSize),
+		slots:  make(chan struct{}, config.PoolSize),
+	}
+}
+
+// Do sends one command and returns its reply
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	replies, err := c.Pipeline(ctx, [][]string{args})
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// Pipeline sends the commands in one round trip and returns one reply per command. If
+// any reply is an error the first is returned along with the replies.
+func (c *Client) Pipeline(ctx context.Context, commands [][]string) ([]any, error) {
+	cn, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := c.roundTrip(ctx, cn, commands)
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) {
+		// the connection may hold unread replies, so it is not reused
+		cn.Close()
+		<-c.slots
+		return nil, err
+	}
+	c.release(cn)
+	return replies, err
+}
+
+// Ping checks the connection to the server
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections. Connections in use are closed when released.
+func (c *Client) Close() error {
+	c.closed.Store(true)
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+			<-c.slots
+		default:
+			return nil
+		}
+	}
+}
+
+// acquire returns an idle connection or dials a new one while the pool has room
+func (c *Client) acquire(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	case c.slots <- struct{}{}:
+		cn, err := c.dial(ctx)
+		if err != nil {
+			<-c.slots
+			return nil, err
+		}
+		return cn, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// release returns a connection to the pool
+func (c *Client) release(cn *conn) {
+	if c.closed.Load() {
+		cn.Close()
+		<-c.slots
+		return
+	}
+	select {
+	case c.idle <- cn:
+	default:
+		cn.Close()
+		<-c.slots
+	}
+}
+
+// dial connects to the server, authenticating and selecting the database
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := net.Dialer{Timeout: c.config.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	var setup [][]string
+	if c.config.Password != "" {
+		setup = append(setup, []string{"AUTH", c.config.Password})
+	}
+	if c.config.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.config.DB)})
+	}
+	if len(setup) > 0 {
+		if _, err := c.roundTrip(ctx, cn, setup); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("failed to set up redis connection: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+// roundTrip writes the commands and reads one reply per command, returning the first
+// error reply after reading all of them
+func (c *Client) roundTrip(ctx context.Context, cn *conn, commands [][]string) ([]any, error) {
+	deadline := time.Now().Add(c.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	for _, args := range commands {
+		buf = append(buf, '*')
+		buf = strconv.AppendInt(buf, int64(len(args)), 10)
+		buf = append(buf, '\r', '\n')
+		for _, arg := range args {
+			buf = append(buf, '$')
+			buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+			buf = append(buf, '\r', '\n')
+			buf = append(buf, arg...)
+			buf = append(buf, '\r', '\n')
+		}
+	}
+	if _, err := cn.Write(buf); err != nil {
+		return nil, fmt.Errorf("failed to write to redis: %w", err)
+	}
+
+	replies := make([]any, len(commands))
+	var firstErr error
+	for i := range commands {
+		reply, err := ReadReply(cn.reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from redis: %w", err)
+		}
+		if replyErr, ok := reply.(Error); ok && firstErr == nil {
+			firstErr = replyErr
+		}
+		replies[i] = reply
+	}
+	return replies, firstErr
+}
+
+// ReadReply reads one RESP value. Requests are arrays of bulk strings, so it also reads
+// commands, e.g. in a test server.
+func ReadReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return Error(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		// a length of -1 is the null reply
+		length, err := strconv.Atoi(body)
+		if err != nil || length < 0 {
+			return nil, err
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:length]), nil
+	case '*':
+		count, err := strconv.Atoi(body)
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		values := make([]any, count)
+		for i := range values {
+			if values[i], err = ReadReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unknown reply type %q", kind)
+}
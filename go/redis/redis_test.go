@@ -0,0 +1,214 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/sink"
+	"github.com/soralabs/solana-toolkit/go/tokenmeta"
+)
+
+// server is a minimal Redis server keeping the commands it received
+type server struct {
+	addr string
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands [][]string
+}
+
+func serve(t *testing.T) *server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &server{addr: listener.Addr().String(), values: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s
+}
+
+func (s *server) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		request, err := ReadReply(reader)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range request.([]any) {
+			args = append(args, arg.(string))
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args)
+		switch args[0] {
+		case "PING", "AUTH":
+			fmt.Fprint(conn, "+OK\r\n")
+		case "SET":
+			s.values[args[1]] = args[2]
+			fmt.Fprint(conn, "+OK\r\n")
+		case "GET":
+			if value, ok := s.values[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "XADD":
+			fmt.Fprint(conn, "$3\r\n1-0\r\n")
+		case "PUBLISH":
+			fmt.Fprint(conn, ":1\r\n")
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		s.mu.Unlock()
+	}
+}
+
+// received returns the commands named name
+func (s *server) received(name string) [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found [][]string
+	for _, command := range s.commands {
+		if command[0] == name {
+			found = append(found, command)
+		}
+	}
+	return found
+}
+
+func TestClient(t *testing.T) {
+	server := serve(t)
+	client := New(Config{Addr: server.addr, Password: "secret", PoolSize: 2})
+	defer client.Close()
+	ctx := context.Background()
+
+	replies, err := client.Pipeline(ctx, [][]string{{"SET", "a", "1"}, {"NOPE"}, {"GET", "a"}, {"GET", "b"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("expected the error reply returned, got %v", err)
+	}
+	if len(replies) != 4 || replies[0] != "OK" || replies[2] != "1" || replies[3] != nil {
+		t.Errorf("unexpected replies %v", replies)
+	}
+
+	// the connection stays usable after an error reply, and the pool bounds connections
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Ping(ctx); err != nil {
+				t.Errorf("failed to ping: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if auths := len(server.received("AUTH")); auths < 1 || auths > 2 {
+		t.Errorf("expected at most 2 connections, got %d", auths)
+	}
+
+	unreachable := New(Config{Addr: "127.0.0.1:1", Timeout: time.Second})
+	if err := unreachable.Ping(ctx); err == nil {
+		t.Error("expected an unreachable server to fail")
+	}
+}
+
+func TestSink(t *testing.T) {
+	server := serve(t)
+	ctx := context.Background()
+	events := sink.Events(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{3},
+		Swaps:     []*tx_parser.SwapInfo{{Protocol: tx_parser.SwapTypeRaydium}},
+		Transfers: []*tx_parser.TransferInfo{{Amount: 5}},
+	})
+
+	streams, err := OpenSink(ctx, Config{Addr: server.addr}, SinkConfig{MaxLen: 1000})
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	if err := streams.Write(ctx, events); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	streams.Close()
+
+	added := server.received("XADD")
+	if len(added) != 2 || added[0][1] != "solana-toolkit:events:swap" || added[1][1] != "solana-toolkit:events:transfer" {
+		t.Fatalf("expected one entry per event in its kind's stream, got %v", added)
+	}
+	if strings.Join(added[0][2:6], " ") != "MAXLEN ~ 1000 *" || added[0][7] != events[0].ID() || added[0][9] != "swap" {
+		t.Errorf("unexpected entry %v", added[0])
+	}
+	var decoded sink.Event
+	if err := json.Unmarshal([]byte(added[1][11]), &decoded); err != nil || decoded.Transfer.Amount != 5 {
+		t.Errorf("expected the encoded event, got %s: %v", added[1][11], err)
+	}
+
+	channels := NewSink(New(Config{Addr: server.addr}), SinkConfig{Mode: ModePubSub, Key: "events"})
+	if err := channels.Write(ctx, events[:1]); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	if published := server.received("PUBLISH"); len(published) != 1 || published[0][1] != "events" {
+		t.Errorf("expected the event published, got %v", published)
+	}
+}
+
+func TestCaches(t *testing.T) {
+	server := serve(t)
+	client := New(Config{Addr: server.addr})
+	defer client.Close()
+	mint, quote := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+
+	decimals := NewDecimalsCache(client, CacheConfig{})
+	if _, ok := decimals.Get(mint); ok {
+		t.Error("expected an unknown mint to miss")
+	}
+	if err := decimals.Put(mint, 6); err != nil {
+		t.Fatalf("failed to store decimals: %v", err)
+	}
+	if value, ok := decimals.Get(mint); !ok || value != 6 {
+		t.Errorf("expected 6 decimals, got %d", value)
+	}
+
+	metadata := NewMetadataCache(client, CacheConfig{TTL: time.Hour})
+	metadata.Put(mint, &tokenmeta.Metadata{Mint: mint, Symbol: "ONE"})
+	metadata.Put(quote, nil)
+	if cached, ok := metadata.Get(mint); !ok || cached.Symbol != "ONE" {
+		t.Errorf("unexpected metadata %+v", cached)
+	}
+	if cached, ok := metadata.Get(quote); !ok || cached != nil {
+		t.Errorf("expected the mint without metadata cached as nil, got %+v", cached)
+	}
+	if set := server.received("SET"); set[len(set)-1][3] != "PX" || set[len(set)-1][4] != "3600000" {
+		t.Errorf("expected entries stored with the TTL, got %v", set[len(set)-1])
+	}
+
+	pairs := NewPoolCache(client, CacheConfig{})
+	pool := pools.Pool{Address: solana.NewWallet().PublicKey(), Protocol: tx_parser.SwapTypeOrca, MintA: mint, MintB: quote}
+	pairs.Put(mint, quote, []pools.Pool{pool})
+	if cached, ok := pairs.Get(quote, mint); !ok || len(cached) != 1 || cached[0] != pool {
+		t.Errorf("expected the pool for the pair in either order, got %+v", cached)
+	}
+}
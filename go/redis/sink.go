@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Sink publishes events to Redis streams or pub/sub channels, one per event kind by default
+type Sink struct {
+	client     *Client
+	config     SinkConfig
+	ownsClient bool
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// OpenSink connects to Redis and returns a sink that closes the client on Close
+func OpenSink(ctx context.Context, config Config, sinkConfig SinkConfig) (*Sink, error) {
+	client := New(config)
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	s := NewSink(client, sinkConfig)
+	s.ownsClient = true
+	return s, nil
+}
+
+// NewSink creates a sink over an existing client
+func NewSink(client *Client, config SinkConfig) *Sink {
+	if config.Mode == "" {
+		config.Mode = ModeStream
+	}
+	if config.Key == "" {
+		config.Key = "solana-toolkit:events:{kind}"
+	}
+	if config.Encoder == nil {
+		config.Encoder = sink.JSONEncoder{}
+	}
+	return &Sink{client: client, config: config}
+}
+
+// Write publishes the events in one pipeline. Streams entries carry the event ID, kind and
+// encoded event.
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	commands := make([][]string, 0, len(events))
+	for _, event := range events {
+		data, err := s.config.Encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+		key := strings.ReplaceAll(s.config.Key, "{kind}", string(event.Kind))
+
+		switch s.config.Mode {
+		case ModeStream:
+			args := []string{"XADD", key}
+			if s.config.MaxLen > 0 {
+				args = append(args, "MAXLEN", "~", strconv.FormatInt(s.config.MaxLen, 10))
+			}
+			args = append(args, "*", "id", event.ID(), "kind", string(event.Kind), "data", string(data))
+			commands = append(commands, args)
+		case ModePubSub:
+			commands = append(commands, []string{"PUBLISH", key, string(data)})
+		default:
+			return fmt.Errorf("unknown mode %q", s.config.Mode)
+		}
+	}
+
+	if _, err := s.client.Pipeline(ctx, commands); err != nil {
+		return fmt.Errorf("failed to publish events: %w", err)
+	}
+	return nil
+}
+
+// Close closes the client if the sink opened it
+func (s *Sink) Close() error {
+	if s.ownsClient {
+		return s.client.Close()
+	}
+	return nil
+}
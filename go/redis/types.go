@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Config controls the Redis client
+type Config struct {
+	// Addr is the host:port of the Redis server
+	Addr string
+
+	// Password authenticates connections when set
+	Password string
+
+	// DB selects the database, defaults to 0
+	DB int
+
+	// Timeout bounds dialing and each round trip, defaults to 5s
+	Timeout time.Duration
+
+	// PoolSize is the maximum number of open connections, defaults to 8
+	PoolSize int
+}
+
+// Mode is how the sink delivers events
+type Mode string
+
+const (
+	ModeStream Mode = "stream" // XADD to a stream, read with XREAD or consumer groups
+	ModePubSub Mode = "pubsub" // PUBLISH to a channel, delivered to current subscribers only
+)
+
+// SinkConfig controls the Redis sink
+type SinkConfig struct {
+	// Mode defaults to ModeStream
+	Mode Mode
+
+	// Key is the stream or channel name, "{kind}" is replaced by the event kind. Defaults
+	// to "solana-toolkit:events:{kind}".
+	Key string
+
+	// MaxLen trims each stream to about this many entries, zero leaves streams unbounded
+	MaxLen int64
+
+	// Encoder serializes events, defaults to JSON
+	Encoder sink.Encoder
+}
+
+// CacheConfig controls a cache adapter
+type CacheConfig struct {
+	// Prefix is prepended to every key, each adapter has its own default
+	Prefix string
+
+	// TTL expires entries, zero keeps them until Redis evicts them
+	TTL time.Duration
+}
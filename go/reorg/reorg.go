@@ -0,0 +1,231 @@
+package reorg
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// pendingSlot holds the transactions of a slot that is not finalized yet
+type pendingSlot struct {
+	status       Status
+	transactions []*tx_parser.ParsedTransaction
+}
+
+// Tracker buffers parsed transactions by slot until the slot is finalized or skipped.
+// It assumes finalized updates arrive in slot order, as root notifications do, so any
+// buffered slot below a new root that was not rooted itself is on an abandoned fork.
+type Tracker struct {
+	config Config
+	events chan Event
+
+	mu        sync.Mutex
+	pending   map[uint64]*pendingSlot
+	finalized map[uint64]bool // recently rooted slots, to finalize late transactions
+	root      uint64
+	newest    uint64
+}
+
+// New creates a new reorg tracker
+func New(config Config) *Tracker {
+	if config.MaxPendingSlots == 0 {
+		config.MaxPendingSlots = 300
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = 1024
+	}
+
+	return &Tracker{
+		config:    config,
+		events:    make(chan Event, config.Buffer),
+		pending:   make(map[uint64]*pendingSlot),
+		finalized: make(map[uint64]bool),
+	}
+}
+
+// Add buffers a parsed transaction and returns any events it triggers. Transactions of a
+// slot that is already confirmed or finalized are emitted immediately.
+func (t *Tracker) Add(tx *tx_parser.ParsedTransaction) []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slot := tx.Slot
+	if t.root > 0 && slot <= t.root {
+		if t.finalized[slot] {
+			return []Event{{Type: EventFinalized, Slot: slot, Transactions: []*tx_parser.ParsedTransaction{tx}}}
+		}
+		// The slot was skipped, the transaction never became part of the chain
+		return nil
+	}
+
+	pending := t.slot(slot)
+	pending.transactions = append(pending.transactions, tx)
+
+	var events []Event
+	if pending.status == StatusConfirmed {
+		events = append(events, Event{Type: EventConfirmed, Slot: slot, Transactions: []*tx_parser.ParsedTransaction{tx}})
+	}
+	return append(events, t.observe(slot)...)
+}
+
+// Update applies a slot status change and returns the resulting events in slot order.
+// Only slots with buffered transactions produce events.
+func (t *Tracker) Update(update SlotUpdate) []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.root > 0 && update.Slot <= t.root {
+		return nil
+	}
+
+	var events []Event
+	switch update.Status {
+	case StatusProcessed:
+		t.slot(update.Slot)
+	case StatusConfirmed:
+		pending := t.slot(update.Slot)
+		if pending.status != StatusConfirmed {
+			pending.status = StatusConfirmed
+			events = appendEvent(events, EventConfirmed, update.Slot, pending.transactions)
+		}
+	case StatusFinalized:
+		events = t.finalize(update.Slot)
+	case StatusDead:
+		if pending, ok := t.pending[update.Slot]; ok {
+			delete(t.pending, update.Slot)
+			events = appendEvent(events, EventRolledBack, update.Slot, pending.transactions)
+		}
+	}
+
+	return append(events, t.observe(update.Slot)...)
+}
+
+// Events returns the channel Run emits events on. It is closed when Run returns.
+func (t *Tracker) Events() <-chan Event {
+	return t.events
+}
+
+// Run feeds transactions and slot updates into the tracker and emits the resulting events
+// until the context is cancelled or both inputs are closed
+func (t *Tracker) Run(ctx context.Context, transactions <-chan *tx_parser.ParsedTransaction, slots <-chan SlotUpdate) error {
+	defer close(t.events)
+
+	for transactions != nil || slots != nil {
+		var events []Event
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case tx, ok := <-transactions:
+			if !ok {
+				transactions = nil
+				continue
+			}
+			events = t.Add(tx)
+		case update, ok := <-slots:
+			if !ok {
+				slots = nil
+				continue
+			}
+			events = t.Update(update)
+		}
+
+		for _, event := range events {
+			select {
+			case t.events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return nil
+}
+
+// Pending returns the number of slots with buffered transactions
+func (t *Tracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for _, pending := range t.pending {
+		if len(pending.transactions) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// finalize roots the slot and rolls back every older slot still buffered
+func (t *Tracker) finalize(slot uint64) []Event {
+	var events []Event
+	for _, older := range t.sortedSlots() {
+		if older >= slot {
+			break
+		}
+		events = appendEvent(events, EventRolledBack, older, t.pending[older].transactions)
+		delete(t.pending, older)
+	}
+
+	if pending, ok := t.pending[slot]; ok {
+		events = appendEvent(events, EventFinalized, slot, pending.transactions)
+		delete(t.pending, slot)
+	}
+
+	t.root = slot
+	t.finalized[slot] = true
+	for finalized := range t.finalized {
+		if finalized+t.config.MaxPendingSlots < slot {
+			delete(t.finalized, finalized)
+		}
+	}
+
+	return events
+}
+
+// observe records the newest slot seen and rolls back unconfirmed slots that fell too far behind
+func (t *Tracker) observe(slot uint64) []Event {
+	t.newest = max(t.newest, slot)
+
+	var events []Event
+	for _, pending := range t.sortedSlots() {
+		if pending+t.config.MaxPendingSlots >= t.newest {
+			break
+		}
+		if t.pending[pending].status == StatusConfirmed {
+			continue
+		}
+		events = appendEvent(events, EventRolledBack, pending, t.pending[pending].transactions)
+		delete(t.pending, pending)
+	}
+	return events
+}
+
+// slot returns the buffer of the slot, creating it if needed
+func (t *Tracker) slot(slot uint64) *pendingSlot {
+	pending, ok := t.pending[slot]
+	if !ok {
+		pending = &pendingSlot{status: StatusProcessed}
+		t.pending[slot] = pending
+	}
+	return pending
+}
+
+// sortedSlots returns the buffered slots in ascending order
+func (t *Tracker) sortedSlots() []uint64 {
+	slots := make([]uint64, 0, len(t.pending))
+	for slot := range t.pending {
+		slots = append(slots, slot)
+	}
+	slices.Sort(slots)
+	return slots
+}
+
+// appendEvent adds an event for the slot if it has transactions
+func appendEvent(events []Event, eventType EventType, slot uint64, transactions []*tx_parser.ParsedTransaction) []Event {
+	if len(transactions) == 0 {
+		return events
+	}
+	return append(events, Event{Type: eventType, Slot: slot, Transactions: transactions})
+}
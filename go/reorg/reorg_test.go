@@ -0,0 +1,121 @@
+package reorg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+func testTransaction(slot uint64, id byte) *tx_parser.ParsedTransaction {
+	return &tx_parser.ParsedTransaction{Slot: slot, Signature: solana.Signature{id}}
+}
+
+// expectEvents checks the event types and slots in order
+func expectEvents(t *testing.T, events []Event, expected ...Event) {
+	t.Helper()
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range events {
+		if events[i].Type != expected[i].Type || events[i].Slot != expected[i].Slot {
+			t.Errorf("event %d: expected %s at %d, got %s at %d", i, expected[i].Type, expected[i].Slot, events[i].Type, events[i].Slot)
+		}
+	}
+}
+
+func TestTrackerFinalizesAndRollsBack(t *testing.T) {
+	tracker := New(Config{})
+
+	expectEvents(t, tracker.Add(testTransaction(10, 1)))
+	expectEvents(t, tracker.Add(testTransaction(10, 2)))
+	expectEvents(t, tracker.Add(testTransaction(11, 3))) // on a fork that gets skipped
+	expectEvents(t, tracker.Add(testTransaction(12, 4)))
+
+	events := tracker.Update(SlotUpdate{Slot: 10, Status: StatusConfirmed})
+	expectEvents(t, events, Event{Type: EventConfirmed, Slot: 10})
+	if len(events[0].Transactions) != 2 {
+		t.Errorf("expected 2 confirmed transactions, got %d", len(events[0].Transactions))
+	}
+
+	// Transactions arriving after confirmation are emitted right away
+	expectEvents(t, tracker.Add(testTransaction(10, 5)), Event{Type: EventConfirmed, Slot: 10})
+
+	expectEvents(t, tracker.Update(SlotUpdate{Slot: 10, Status: StatusFinalized}), Event{Type: EventFinalized, Slot: 10})
+
+	// Rooting 12 abandons 11
+	events = tracker.Update(SlotUpdate{Slot: 12, Parent: 10, Status: StatusFinalized})
+	expectEvents(t, events, Event{Type: EventRolledBack, Slot: 11}, Event{Type: EventFinalized, Slot: 12})
+	if events[0].Transactions[0].Signature != (solana.Signature{3}) {
+		t.Errorf("unexpected rolled back transaction: %+v", events[0].Transactions[0])
+	}
+
+	// Late transactions of rooted and skipped slots
+	expectEvents(t, tracker.Add(testTransaction(12, 6)), Event{Type: EventFinalized, Slot: 12})
+	expectEvents(t, tracker.Add(testTransaction(11, 7)))
+
+	if tracker.Pending() != 0 {
+		t.Errorf("expected no pending slots, got %d", tracker.Pending())
+	}
+}
+
+func TestTrackerDeadAndStaleSlots(t *testing.T) {
+	tracker := New(Config{MaxPendingSlots: 5})
+
+	tracker.Add(testTransaction(20, 1))
+	expectEvents(t, tracker.Update(SlotUpdate{Slot: 20, Status: StatusDead}), Event{Type: EventRolledBack, Slot: 20})
+
+	tracker.Add(testTransaction(21, 2))
+	tracker.Add(testTransaction(22, 3))
+	tracker.Update(SlotUpdate{Slot: 22, Status: StatusConfirmed})
+
+	// Slot 21 never confirmed and falls out of the window, confirmed 22 waits for its root
+	expectEvents(t, tracker.Update(SlotUpdate{Slot: 27, Status: StatusProcessed}), Event{Type: EventRolledBack, Slot: 21})
+	if tracker.Pending() != 1 {
+		t.Errorf("expected the confirmed slot to stay pending, got %d", tracker.Pending())
+	}
+}
+
+func TestTrackerRun(t *testing.T) {
+	tracker := New(Config{})
+	transactions := make(chan *tx_parser.ParsedTransaction, 2)
+	slots := make(chan SlotUpdate, 2)
+
+	transactions <- testTransaction(5, 1)
+	close(transactions)
+	done := make(chan error, 1)
+	go func() { done <- tracker.Run(context.Background(), transactions, slots) }()
+
+	// Wait for the transaction to be buffered before rooting its slot
+	for tracker.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	slots <- SlotUpdate{Slot: 5, Status: StatusFinalized}
+	close(slots)
+
+	event := <-tracker.Events()
+	if event.Type != EventFinalized || event.Slot != 5 || len(event.Transactions) != 1 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("expected Run to return nil when inputs close, got %v", err)
+	}
+	if _, ok := <-tracker.Events(); ok {
+		t.Error("expected the events channel to be closed")
+	}
+}
+
+func TestSlotUpdateMapping(t *testing.T) {
+	update, ok := slotUpdate(&ws.SlotsUpdatesResult{Slot: 9, Parent: 8, Type: ws.SlotsUpdatesRoot})
+	if !ok || update.Status != StatusFinalized || update.Parent != 8 {
+		t.Errorf("unexpected root mapping: %+v", update)
+	}
+	if _, ok := slotUpdate(&ws.SlotsUpdatesResult{Slot: 9, Type: ws.SlotsUpdatesFirstShredReceived}); ok {
+		t.Error("expected shred notifications to be ignored")
+	}
+}
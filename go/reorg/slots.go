@@ -0,0 +1,53 @@
+package reorg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// WatchSlots subscribes to slotsUpdatesSubscribe and forwards confirmations, roots and dead
+// slots to updates until the context is cancelled or the subscription fails
+func WatchSlots(ctx context.Context, client *ws.Client, updates chan<- SlotUpdate) error {
+	sub, err := client.SlotsUpdatesSubscribe()
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to slot updates: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		notification, err := sub.Recv(ctx)
+		if err != nil {
+			return fmt.Errorf("slot updates subscription failed: %w", err)
+		}
+
+		update, ok := slotUpdate(notification)
+		if !ok {
+			continue
+		}
+		select {
+		case updates <- update:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// slotUpdate maps a slots update notification onto a status change
+func slotUpdate(notification *ws.SlotsUpdatesResult) (SlotUpdate, bool) {
+	update := SlotUpdate{Slot: notification.Slot, Parent: notification.Parent}
+	switch notification.Type {
+	case ws.SlotsUpdatesFrozen:
+		update.Status = StatusProcessed
+	case ws.SlotsUpdatesOptimisticConfirmation:
+		update.Status = StatusConfirmed
+	case ws.SlotsUpdatesRoot:
+		update.Status = StatusFinalized
+	case ws.SlotsUpdatesDead:
+		update.Status = StatusDead
+	default:
+		return update, false
+	}
+	return update, true
+}
@@ -0,0 +1,52 @@
+package reorg
+
+import "github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+
+// Status is the commitment a slot has reached
+type Status string
+
+const (
+	StatusProcessed Status = "processed"
+	StatusConfirmed Status = "confirmed" // optimistically confirmed by a supermajority
+	StatusFinalized Status = "finalized" // rooted, can no longer be rolled back
+	StatusDead      Status = "dead"      // abandoned by the validator, e.g. a skipped fork
+)
+
+// SlotUpdate reports a change in the commitment of a slot
+type SlotUpdate struct {
+	Slot   uint64
+	Parent uint64 // parent slot, 0 if unknown
+	Status Status
+}
+
+// EventType is the kind of event emitted for a slot
+type EventType string
+
+const (
+	// EventConfirmed is emitted when a slot is confirmed. Its transactions are very unlikely
+	// to be rolled back but are re-emitted by EventFinalized or EventRolledBack later.
+	EventConfirmed EventType = "confirmed"
+
+	// EventFinalized is emitted when a slot is rooted, its transactions are permanent
+	EventFinalized EventType = "finalized"
+
+	// EventRolledBack is emitted when a slot was skipped, consumers must undo its transactions
+	EventRolledBack EventType = "rolled_back"
+)
+
+// Event carries the buffered transactions of a slot whose status changed
+type Event struct {
+	Type         EventType
+	Slot         uint64
+	Transactions []*tx_parser.ParsedTransaction
+}
+
+// Config controls the reorg buffer
+type Config struct {
+	// MaxPendingSlots is how far an unconfirmed slot may fall behind the newest slot before
+	// it is treated as skipped, defaults to 300
+	MaxPendingSlots uint64
+
+	// Buffer is the size of the events channel used by Run, defaults to 1024
+	Buffer int
+}
@@ -0,0 +1,52 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// JSON-RPC error codes returned by Solana nodes that are worth retrying elsewhere
+const (
+	errCodeBlockNotAvailable        = -32004
+	errCodeNodeUnhealthy            = -32005
+	errCodeSlotSkipped              = -32007
+	errCodeLongTermStorageSlotSkip  = -32009
+	errCodeMinContextSlotNotReached = -32016
+)
+
+// IsRetryable checks if the error is transient and the request may succeed on a retry
+// or another endpoint
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code == 429 || httpErr.Code >= 500
+	}
+
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case errCodeBlockNotAvailable, errCodeNodeUnhealthy, errCodeSlotSkipped,
+			errCodeLongTermStorageSlotSkip, errCodeMinContextSlotNotReached:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Transport and decoding failures without a structured error, e.g. connection resets
+	return true
+}
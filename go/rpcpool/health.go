@@ -0,0 +1,47 @@
+package rpcpool
+
+import (
+	"context"
+	"time"
+)
+
+// RunHealthChecks calls getHealth on every endpoint on the configured interval until the
+// context is cancelled. Healthy answers bring endpoints back into rotation early.
+func (p *Pool) RunHealthChecks(ctx context.Context) error {
+	ticker := time.NewTicker(p.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth calls getHealth once on every endpoint and updates its health
+func (p *Pool) CheckHealth(ctx context.Context) {
+	for _, e := range p.endpoints {
+		checkCtx, cancel := context.WithTimeout(ctx, p.opts.HealthCheckInterval)
+		var result string
+		err := e.client.CallForInto(checkCtx, &result, "getHealth", nil)
+		cancel()
+
+		if err != nil {
+			e.mu.Lock()
+			e.consecutiveFailures = max(e.consecutiveFailures+1, p.opts.UnhealthyAfter)
+			e.lastError = err.Error()
+			e.unhealthyUntil = time.Now().Add(p.opts.Cooldown)
+			e.mu.Unlock()
+			continue
+		}
+
+		e.mu.Lock()
+		e.consecutiveFailures = 0
+		e.unhealthyUntil = time.Time{}
+		e.mu.Unlock()
+	}
+}
@@ -0,0 +1,333 @@
+package rpcpool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"golang.org/x/time/rate"
+)
+
+// Pool is a Solana JSON-RPC client spread over several endpoints. It implements
+// rpc.JSONRPCClient, so it can back a regular *rpc.Client.
+type Pool struct {
+	opts      Options
+	endpoints []*endpoint
+}
+
+// endpoint is the runtime state of a configured Endpoint
+type endpoint struct {
+	config  Endpoint
+	client  jsonrpc.RPCClient
+	limiter *rate.Limiter
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	requests            uint64
+	failures            uint64
+	lastError           string
+	unhealthyUntil      time.Time
+}
+
+// New creates a new RPC pool over the configured endpoints
+func New(opts Options) (*Pool, error) {
+	if len(opts.Endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 2 * time.Second
+	}
+	if opts.UnhealthyAfter <= 0 {
+		opts.UnhealthyAfter = 3
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 30 * time.Second
+	}
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = 10 * time.Second
+	}
+
+	pool := &Pool{opts: opts}
+	for _, config := range opts.Endpoints {
+		if config.URL == "" {
+			return nil, fmt.Errorf("endpoint URL is required")
+		}
+
+		limit := rate.Inf
+		if config.RateLimit > 0 {
+			limit = rate.Limit(config.RateLimit)
+		}
+		burst := max(config.Burst, 1)
+
+		pool.endpoints = append(pool.endpoints, &endpoint{
+			config: config,
+			client: jsonrpc.NewClientWithOpts(config.URL, &jsonrpc.RPCClientOpts{
+				HTTPClient:    &http.Client{Timeout: time.Minute},
+				CustomHeaders: config.Headers,
+			}),
+			limiter: rate.NewLimiter(limit, burst),
+		})
+	}
+
+	return pool, nil
+}
+
+// Client returns a Solana RPC client that sends every request through the pool
+func (p *Pool) Client() *rpc.Client {
+	return rpc.NewWithCustomRPCClient(p)
+}
+
+// CallForInto sends the request with failover, retries and hedging, decoding the result into out
+func (p *Pool) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	// Attempts decode into their own buffer so a failed or losing hedged attempt never
+	// leaves a partially written result behind
+	raw, err := p.withRetries(ctx, func(ctx context.Context, attempt int) (json.RawMessage, error) {
+		return p.hedged(ctx, attempt, func(ctx context.Context, e *endpoint) (json.RawMessage, error) {
+			var result json.RawMessage
+			err := e.client.CallForInto(ctx, &result, method, params)
+			return result, err
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// CallWithCallback sends the request with failover and retries
+func (p *Pool) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	_, err := p.withRetries(ctx, func(ctx context.Context, attempt int) (json.RawMessage, error) {
+		e, err := p.acquire(ctx, attempt)
+		if err != nil {
+			return nil, err
+		}
+		return nil, p.observe(e, e.client.CallWithCallback(ctx, method, params, callback))
+	})
+	return err
+}
+
+// CallBatch sends the batch with failover and retries
+func (p *Pool) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	var responses jsonrpc.RPCResponses
+	_, err := p.withRetries(ctx, func(ctx context.Context, attempt int) (json.RawMessage, error) {
+		e, err := p.acquire(ctx, attempt)
+		if err != nil {
+			return nil, err
+		}
+		result, err := e.client.CallBatch(ctx, requests)
+		if err = p.observe(e, err); err != nil {
+			return nil, err
+		}
+		responses = result
+		return nil, nil
+	})
+	return responses, err
+}
+
+// Close closes the connections of every endpoint
+func (p *Pool) Close() error {
+	var errs []error
+	for _, e := range p.endpoints {
+		if closer, ok := e.client.(interface{ Close() error }); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Status returns the health of every endpoint
+func (p *Pool) Status() []EndpointStatus {
+	now := time.Now()
+	statuses := make([]EndpointStatus, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		statuses = append(statuses, EndpointStatus{
+			URL:                 e.config.URL,
+			Healthy:             !now.Before(e.unhealthyUntil),
+			ConsecutiveFailures: e.consecutiveFailures,
+			Requests:            e.requests,
+			Failures:            e.failures,
+			LastError:           e.lastError,
+			UnhealthyUntil:      e.unhealthyUntil,
+		})
+		e.mu.Unlock()
+	}
+	return statuses
+}
+
+// withRetries runs the call until it succeeds, fails with a non-retryable error or runs out of attempts
+func (p *Pool) withRetries(ctx context.Context, call func(ctx context.Context, attempt int) (json.RawMessage, error)) (json.RawMessage, error) {
+	var lastErr error
+	for attempt := 0; attempt <= p.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(p.backoff(attempt)):
+			}
+		}
+
+		result, err := call(ctx, attempt)
+		if err == nil {
+			return result, nil
+		}
+		if !IsRetryable(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("rpc request failed after %d attempts: %w", p.opts.MaxRetries+1, lastErr)
+}
+
+// hedged runs the call on the preferred endpoint and, if it is slow to answer, on the
+// next endpoint as well, returning the first successful result
+func (p *Pool) hedged(ctx context.Context, attempt int, call func(ctx context.Context, e *endpoint) (json.RawMessage, error)) (json.RawMessage, error) {
+	primary, err := p.acquire(ctx, attempt)
+	if err != nil {
+		return nil, err
+	}
+	if p.opts.HedgeAfter <= 0 || len(p.endpoints) < 2 {
+		result, err := call(ctx, primary)
+		return result, p.observe(primary, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result json.RawMessage
+		err    error
+	}
+	outcomes := make(chan outcome, 2)
+	run := func(e *endpoint) {
+		result, err := call(ctx, e)
+		// Losing hedges are cancelled, which says nothing about the endpoint's health
+		if ctx.Err() == nil {
+			err = p.observe(e, err)
+		}
+		outcomes <- outcome{result, err}
+	}
+	go run(primary)
+
+	pending := 1
+	timer := time.NewTimer(p.opts.HedgeAfter)
+	defer timer.Stop()
+
+	var lastErr error
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if hedge := p.next(primary); hedge != nil && hedge.limiter.Allow() {
+				pending++
+				go run(hedge)
+			}
+		case o := <-outcomes:
+			pending--
+			if o.err == nil {
+				return o.result, nil
+			}
+			lastErr = o.err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// acquire picks the endpoint for the given attempt, rotating through healthy endpoints
+// on retries, and waits for its rate limiter
+func (p *Pool) acquire(ctx context.Context, attempt int) (*endpoint, error) {
+	healthy := p.healthy()
+	e := healthy[attempt%len(healthy)]
+
+	// Prefer a healthy endpoint with spare capacity over waiting on the preferred one
+	if !e.limiter.Allow() {
+		for _, candidate := range healthy {
+			if candidate != e && candidate.limiter.Allow() {
+				return candidate, nil
+			}
+		}
+		if err := e.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// healthy returns the endpoints currently in rotation, or all endpoints if none are
+func (p *Pool) healthy() []*endpoint {
+	now := time.Now()
+	var healthy []*endpoint
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		if !now.Before(e.unhealthyUntil) {
+			healthy = append(healthy, e)
+		}
+		e.mu.Unlock()
+	}
+	if len(healthy) == 0 {
+		return p.endpoints
+	}
+	return healthy
+}
+
+// next returns the healthy endpoint following e, or nil if there is none
+func (p *Pool) next(e *endpoint) *endpoint {
+	healthy := p.healthy()
+	for i, candidate := range healthy {
+		if candidate == e && len(healthy) > 1 {
+			return healthy[(i+1)%len(healthy)]
+		}
+	}
+	if len(healthy) > 0 && healthy[0] != e {
+		return healthy[0]
+	}
+	return nil
+}
+
+// observe records the outcome of a request against the endpoint's health and returns err
+func (p *Pool) observe(e *endpoint, err error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	if err == nil || !IsRetryable(err) {
+		// Errors such as invalid params are the caller's fault, not the endpoint's
+		e.consecutiveFailures = 0
+		return err
+	}
+
+	e.failures++
+	e.consecutiveFailures++
+	e.lastError = err.Error()
+	if e.consecutiveFailures >= p.opts.UnhealthyAfter {
+		e.unhealthyUntil = time.Now().Add(p.opts.Cooldown)
+	}
+	return err
+}
+
+// backoff returns the jittered exponential delay before the given retry
+func (p *Pool) backoff(attempt int) time.Duration {
+	delay := p.opts.BaseBackoff << (attempt - 1)
+	if delay <= 0 || delay > p.opts.MaxBackoff {
+		delay = p.opts.MaxBackoff
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
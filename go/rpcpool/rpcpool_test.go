@@ -0,0 +1,187 @@
+package rpcpool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestServer answers every JSON-RPC request with handler's status code and result
+func newTestServer(t *testing.T, calls *int32, handler func(method string) (int, string)) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		body, _ := io.ReadAll(r.Body)
+
+		var request struct {
+			ID     any    `json:"id"`
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		status, payload := handler(request.Method)
+		id, _ := json.Marshal(request.ID)
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,%s}`, id, payload)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func fastOptions(endpoints ...string) Options {
+	opts := Options{
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  2 * time.Millisecond,
+	}
+	for _, url := range endpoints {
+		opts.Endpoints = append(opts.Endpoints, Endpoint{URL: url})
+	}
+	return opts
+}
+
+func TestPoolFailsOver(t *testing.T) {
+	var downCalls, upCalls int32
+	down := newTestServer(t, &downCalls, func(string) (int, string) {
+		return http.StatusServiceUnavailable, `"error":{"code":-32005,"message":"node is behind"}`
+	})
+	up := newTestServer(t, &upCalls, func(string) (int, string) {
+		return http.StatusOK, `"result":1234`
+	})
+
+	opts := fastOptions(down, up)
+	opts.UnhealthyAfter = 2
+	pool, err := New(opts)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	client := pool.Client()
+
+	for range 3 {
+		slot, err := client.GetSlot(context.Background(), "")
+		if err != nil {
+			t.Fatalf("expected failover to succeed: %v", err)
+		}
+		if slot != 1234 {
+			t.Fatalf("expected slot 1234, got %d", slot)
+		}
+	}
+
+	status := pool.Status()
+	if status[0].Healthy || !status[1].Healthy {
+		t.Errorf("expected the failing endpoint to be out of rotation: %+v", status)
+	}
+
+	// Unhealthy endpoints are skipped until their cooldown ends
+	before := atomic.LoadInt32(&downCalls)
+	if _, err := client.GetSlot(context.Background(), ""); err != nil {
+		t.Fatalf("failed to get slot: %v", err)
+	}
+	if atomic.LoadInt32(&downCalls) != before {
+		t.Error("expected the unhealthy endpoint to be skipped")
+	}
+}
+
+func TestPoolDoesNotRetryCallerErrors(t *testing.T) {
+	var calls int32
+	url := newTestServer(t, &calls, func(string) (int, string) {
+		return http.StatusOK, `"error":{"code":-32602,"message":"invalid params"}`
+	})
+
+	pool, err := New(fastOptions(url))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	if _, err := pool.Client().GetSlot(context.Background(), ""); err == nil {
+		t.Fatal("expected an invalid params error")
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+	if !pool.Status()[0].Healthy {
+		t.Error("caller errors should not mark the endpoint unhealthy")
+	}
+}
+
+func TestPoolHedgesSlowRequests(t *testing.T) {
+	var slowCalls, fastCalls int32
+	slow := newTestServer(t, &slowCalls, func(string) (int, string) {
+		time.Sleep(time.Second)
+		return http.StatusOK, `"result":1`
+	})
+	fast := newTestServer(t, &fastCalls, func(string) (int, string) {
+		return http.StatusOK, `"result":2`
+	})
+
+	opts := fastOptions(slow, fast)
+	opts.HedgeAfter = 20 * time.Millisecond
+	pool, err := New(opts)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	start := time.Now()
+	slot, err := pool.Client().GetSlot(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed to get slot: %v", err)
+	}
+	if slot != 2 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("expected the hedged request to win quickly, got slot %d after %s", slot, time.Since(start))
+	}
+}
+
+func TestPoolHealthChecks(t *testing.T) {
+	var calls int32
+	healthy := atomic.Bool{}
+	url := newTestServer(t, &calls, func(method string) (int, string) {
+		if method == "getHealth" && healthy.Load() {
+			return http.StatusOK, `"result":"ok"`
+		}
+		return http.StatusOK, `"error":{"code":-32005,"message":"node is behind"}`
+	})
+
+	pool, err := New(fastOptions(url))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	pool.CheckHealth(context.Background())
+	if pool.Status()[0].Healthy {
+		t.Error("expected a failed health check to mark the endpoint unhealthy")
+	}
+
+	healthy.Store(true)
+	pool.CheckHealth(context.Background())
+	if !pool.Status()[0].Healthy {
+		t.Error("expected a passing health check to restore the endpoint")
+	}
+}
+
+func TestBackoffIsBounded(t *testing.T) {
+	pool, err := New(Options{
+		Endpoints:   []Endpoint{{URL: "http://localhost"}},
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	for attempt := 1; attempt < 70; attempt++ {
+		delay := pool.backoff(attempt)
+		if delay < 0 || delay > time.Second {
+			t.Fatalf("backoff for attempt %d out of range: %s", attempt, delay)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package rpcpool
+
+import "time"
+
+// Endpoint is a single RPC provider in the pool
+type Endpoint struct {
+	URL string
+
+	// Headers are added to every request, e.g. for API keys
+	Headers map[string]string
+
+	// RateLimit is the maximum number of requests per second, zero means unlimited
+	RateLimit float64
+
+	// Burst is the number of requests allowed above the rate limit, defaults to 1
+	Burst int
+}
+
+// Options configures a Pool
+type Options struct {
+	// Endpoints in order of preference, the first healthy endpoint serves requests
+	Endpoints []Endpoint
+
+	// MaxRetries is the number of retries after the first attempt, defaults to 3
+	MaxRetries int
+
+	// BaseBackoff is the initial delay between retries, doubled on every attempt and
+	// randomized by up to half, defaults to 100ms
+	BaseBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries, defaults to 2s
+	MaxBackoff time.Duration
+
+	// HedgeAfter sends the same request to the next endpoint if the first one has not
+	// answered in time, zero disables hedging
+	HedgeAfter time.Duration
+
+	// UnhealthyAfter is the number of consecutive failures after which an endpoint is
+	// taken out of rotation, defaults to 3
+	UnhealthyAfter int
+
+	// Cooldown is how long an unhealthy endpoint is skipped before it is tried again, defaults to 30s
+	Cooldown time.Duration
+
+	// HealthCheckInterval controls how often RunHealthChecks calls getHealth, defaults to 10s
+	HealthCheckInterval time.Duration
+}
+
+// EndpointStatus is a snapshot of an endpoint's health
+type EndpointStatus struct {
+	URL                 string    `json:"url"`
+	Healthy             bool      `json:"healthy"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Requests            uint64    `json:"requests"`
+	Failures            uint64    `json:"failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	UnhealthyUntil      time.Time `json:"unhealthy_until,omitempty"`
+}
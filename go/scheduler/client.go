@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// client waits for the scheduler before every request
+type client struct {
+	scheduler *Scheduler
+	next      rpc.JSONRPCClient
+}
+
+var _ rpc.JSONRPCClient = (*client)(nil)
+
+// Wrap returns a JSON-RPC client that schedules every request before sending it through
+// next, e.g. an rpcpool.Pool or jsonrpc.NewClient. Requests take the priority of their
+// context.
+func (s *Scheduler) Wrap(next rpc.JSONRPCClient) rpc.JSONRPCClient {
+	return &client{scheduler: s, next: next}
+}
+
+// Client returns a Solana RPC client that schedules every request before sending it
+// through next
+func (s *Scheduler) Client(next rpc.JSONRPCClient) *rpc.Client {
+	return rpc.NewWithCustomRPCClient(s.Wrap(next))
+}
+
+func (c *client) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	if err := c.scheduler.Wait(ctx, method); err != nil {
+		return err
+	}
+	return c.next.CallForInto(ctx, out, method, params)
+}
+
+func (c *client) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	if err := c.scheduler.Wait(ctx, method); err != nil {
+		return err
+	}
+	return c.next.CallWithCallback(ctx, method, params, callback)
+}
+
+// CallBatch spends the credits of every request in the batch at once
+func (c *client) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	methods := make([]string, len(requests))
+	for i, request := range requests {
+		methods[i] = request.Method
+	}
+	if err := c.scheduler.Wait(ctx, methods...); err != nil {
+		return nil, err
+	}
+	return c.next.CallBatch(ctx, requests)
+}
+
+// Close closes next if it can be closed
+func (c *client) Close() error {
+	if closer, ok := c.next.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Scheduler shares the credits of a provider plan between priorities. Requests are
+// granted credits strictly by priority, so a burst of enrichment lookups queues behind
+// live requests instead of exhausting the plan under them. Use one scheduler per
+// provider.
+type Scheduler struct {
+	config Config
+
+	mu      sync.Mutex
+	credits float64
+	updated time.Time
+	queues  [priorities]*list.List
+	stats   [priorities]Stats
+	timer   *time.Timer
+}
+
+// waiter is a request queued for credits
+type waiter struct {
+	priority Priority
+	cost     float64
+	queued   time.Time
+	ready    chan struct{}
+}
+
+type priorityKey struct{}
+
+// WithPriority tags the requests made with the context with the priority
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// New creates a scheduler with a full bucket
+func New(config Config) (*Scheduler, error) {
+	if config.Plan.CreditsPerSecond <= 0 {
+		return nil, fmt.Errorf("plan credits per second are required")
+	}
+	if config.Plan.Burst <= 0 {
+		config.Plan.Burst = config.Plan.CreditsPerSecond
+	}
+	if config.Plan.Costs == nil {
+		config.Plan.Costs = DefaultCosts
+	}
+	if config.Plan.DefaultCost <= 0 {
+		config.Plan.DefaultCost = 1
+	}
+	if config.Reserve == nil {
+		config.Reserve = map[Priority]float64{PriorityBackfill: 0.1, PriorityEnrichment: 0.25}
+	}
+	for priority, reserve := range config.Reserve {
+		if reserve < 0 || reserve >= 1 {
+			return nil, fmt.Errorf("reserve of %s must be within [0, 1), got %v", priority, reserve)
+		}
+	}
+	if err := validPriority(config.Priority); err != nil {
+		return nil, err
+	}
+
+	s := &Scheduler{
+		config:  config,
+		credits: config.Plan.Burst,
+		updated: time.Now(),
+	}
+	for i := range s.queues {
+		s.queues[i] = list.New()
+		s.stats[i] = Stats{Priority: Priority(i), Name: Priority(i).String()}
+	}
+	return s, nil
+}
+
+// Cost returns the credits of a method under the plan
+func (s *Scheduler) Cost(method string) float64 {
+	if cost, ok := s.config.Plan.Costs[method]; ok {
+		return cost
+	}
+	return s.config.Plan.DefaultCost
+}
+
+// Wait blocks until the request is granted the credits of its methods, at the priority
+// of the context
+func (s *Scheduler) Wait(ctx context.Context, methods ...string) error {
+	priority := s.config.Priority
+	if tagged, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		priority = tagged
+	}
+	if err := validPriority(priority); err != nil {
+		return err
+	}
+	var cost float64
+	for _, method := range methods {
+		cost += s.Cost(method)
+	}
+	return s.wait(ctx, priority, cost)
+}
+
+// Stats returns the requests of every priority
+func (s *Scheduler) Stats() []Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make([]Stats, priorities)
+	for i := range stats {
+		stats[i] = s.stats[i]
+		stats[i].Queued = s.queues[i].Len()
+	}
+	return stats
+}
+
+// Credits returns the credits left in the bucket, negative while a request larger than
+// the bucket is being repaid
+func (s *Scheduler) Credits() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refill(time.Now())
+	return s.credits
+}
+
+func (s *Scheduler) wait(ctx context.Context, priority Priority, cost float64) error {
+	now := time.Now()
+	w := &waiter{priority: priority, cost: cost, queued: now, ready: make(chan struct{})}
+
+	s.mu.Lock()
+	element := s.queues[priority].PushBack(w)
+	s.dispatch(now)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-w.ready:
+		// granted while cancelled, the credits are spent
+		return nil
+	default:
+	}
+	s.queues[priority].Remove(element)
+	// the cancelled request may have held back lower priorities
+	s.dispatch(time.Now())
+	return ctx.Err()
+}
+
+// dispatch grants credits to queued requests by priority, then arms the timer for when
+// the first request left waiting can be granted. Lower priorities never go ahead of a
+// waiting higher priority. It must be called with the lock held.
+func (s *Scheduler) dispatch(now time.Time) {
+	s.refill(now)
+	for priority, queue := range s.queues {
+		for queue.Len() > 0 {
+			w := queue.Front().Value.(*waiter)
+			// requests larger than the bucket wait for a full bucket and leave a debt
+			needed := min(w.cost+s.config.Reserve[Priority(priority)]*s.config.Plan.Burst, s.config.Plan.Burst)
+			if s.credits < needed {
+				s.arm(time.Duration(math.Ceil((needed - s.credits) / s.config.Plan.CreditsPerSecond * float64(time.Second))))
+				return
+			}
+			queue.Remove(queue.Front())
+			s.credits -= w.cost
+			stats := &s.stats[priority]
+			stats.Requests++
+			stats.Credits += w.cost
+			stats.Waited += now.Sub(w.queued)
+			close(w.ready)
+		}
+	}
+}
+
+// arm schedules a dispatch after the delay
+func (s *Scheduler) arm(delay time.Duration) {
+	if s.timer == nil {
+		s.timer = time.AfterFunc(delay, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.dispatch(time.Now())
+		})
+		return
+	}
+	s.timer.Reset(delay)
+}
+
+// refill adds the credits accrued since the last refill
+func (s *Scheduler) refill(now time.Time) {
+	if elapsed := now.Sub(s.updated); elapsed > 0 {
+		s.credits = min(s.credits+elapsed.Seconds()*s.config.Plan.CreditsPerSecond, s.config.Plan.Burst)
+		s.updated = now
+	}
+}
+
+func validPriority(priority Priority) error {
+	if priority < 0 || priority >= priorities {
+		return fmt.Errorf("unknown %s", priority)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+func TestPriorityOrder(t *testing.T) {
+	s, err := New(Config{Plan: Plan{CreditsPerSecond: 100, Burst: 10}, Reserve: map[Priority]float64{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// drain the bucket so every request below queues
+	if err := s.Wait(context.Background(), "getTransaction"); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []Priority
+		wg    sync.WaitGroup
+	)
+	request := func(priority Priority, method string) {
+		defer wg.Done()
+		if err := s.Wait(WithPriority(context.Background(), priority), method); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		order = append(order, priority)
+		mu.Unlock()
+	}
+	wg.Add(3)
+	go request(PriorityEnrichment, "getAsset")
+	waitQueued(t, s, PriorityEnrichment)
+	go request(PriorityBackfill, "getBlock")
+	waitQueued(t, s, PriorityBackfill)
+	go request(PriorityLive, "getSlot")
+	wg.Wait()
+
+	if fmt.Sprint(order) != "[live backfill enrichment]" {
+		t.Errorf("expected requests granted by priority, got %v", order)
+	}
+	stats := s.Stats()
+	if stats[PriorityEnrichment].Requests != 1 || stats[PriorityEnrichment].Credits != 10 || stats[PriorityLive].Credits != 11 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	s, err := New(Config{Plan: Plan{CreditsPerSecond: 0.001, Burst: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(WithPriority(context.Background(), PriorityEnrichment), 20*time.Millisecond)
+	defer cancel()
+
+	// enrichment leaves a quarter of the bucket to higher priorities
+	for range 7 {
+		if err := s.Wait(ctx, "getAccountInfo"); err != nil {
+			t.Fatalf("expected enrichment credits: %v", err)
+		}
+	}
+	if err := s.Wait(ctx, "getAccountInfo"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected enrichment to stop at its reserve, got %v", err)
+	}
+	if err := s.Wait(context.Background(), "getSlot", "getSlot", "getSlot"); err != nil {
+		t.Fatalf("expected live requests to use the reserve: %v", err)
+	}
+	if stats := s.Stats(); stats[PriorityEnrichment].Queued != 0 || stats[PriorityLive].Requests != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if _, err := New(Config{Plan: Plan{CreditsPerSecond: 1}, Reserve: map[Priority]float64{PriorityBackfill: 1}}); err == nil {
+		t.Error("expected a full reserve to be rejected")
+	}
+}
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":125}`)
+	}))
+	defer server.Close()
+
+	s, err := New(Config{Plan: Plan{CreditsPerSecond: 1000, Costs: map[string]float64{"getSlot": 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := s.Client(jsonrpc.NewClient(server.URL))
+	slot, err := client.GetSlot(WithPriority(context.Background(), PriorityBackfill), "")
+	if err != nil || slot != 125 {
+		t.Fatalf("expected slot 125, got %d: %v", slot, err)
+	}
+	if stats := s.Stats(); stats[PriorityBackfill].Requests != 1 || stats[PriorityBackfill].Credits != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func waitQueued(t *testing.T, s *Scheduler, priority Priority) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for s.Stats()[priority].Queued == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s request was not queued", priority)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// Priority orders requests competing for the credits of a plan, lower values first
+type Priority int
+
+const (
+	PriorityLive       Priority = iota // requests on the live stream's path, e.g. fetching streamed transactions
+	PriorityBackfill                   // historical backfills and catch-ups
+	PriorityEnrichment                 // metadata, prices and other lookups that can wait
+
+	priorities = 3
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLive:
+		return "live"
+	case PriorityBackfill:
+		return "backfill"
+	case PriorityEnrichment:
+		return "enrichment"
+	}
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
+// Plan is the rate limit of a provider plan, a token bucket of credits that requests
+// spend by method
+type Plan struct {
+	Name string
+
+	// CreditsPerSecond is the rate the bucket refills at, required
+	CreditsPerSecond float64
+
+	// Burst is the capacity of the bucket in credits, defaults to CreditsPerSecond
+	Burst float64
+
+	// Costs are the credits of each method, defaults to DefaultCosts
+	Costs map[string]float64
+
+	// DefaultCost is the credits of methods missing from Costs, defaults to 1
+	DefaultCost float64
+}
+
+// DefaultCosts weighs the methods credit-based providers bill above a plain call:
+// archival lookups, program account scans and DAS queries
+var DefaultCosts = map[string]float64{
+	"getBlock":                10,
+	"getBlocks":               10,
+	"getTransaction":          10,
+	"getSignaturesForAddress": 10,
+	"getProgramAccounts":      10,
+	"getTokenLargestAccounts": 10,
+	"getAsset":                10,
+	"getAssetBatch":           10,
+	"getAssetsByOwner":        10,
+	"getAssetsByGroup":        10,
+	"searchAssets":            10,
+}
+
+// Config controls the scheduler
+type Config struct {
+	Plan Plan
+
+	// Reserve is the fraction of the bucket each priority leaves to higher priorities, so
+	// live requests find credits even while lower priorities are saturating the plan.
+	// Defaults to none for live, 10% for backfill and 25% for enrichment.
+	Reserve map[Priority]float64
+
+	// Priority of requests whose context carries none, defaults to PriorityLive
+	Priority Priority
+}
+
+// Stats counts the requests of a priority
+type Stats struct {
+	Priority Priority      `json:"-"`
+	Name     string        `json:"priority"`
+	Requests uint64        `json:"requests"`
+	Credits  float64       `json:"credits"`
+	Queued   int           `json:"queued"`
+	Waited   time.Duration `json:"waited"` // total time spent queued
+}
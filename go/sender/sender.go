@@ -0,0 +1,171 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/landing_stats"
+	"github.com/soralabs/solana-toolkit/go/signer"
+)
+
+// Sender submits transactions and follows them until they land or their blockhash expires
+type Sender struct {
+	rpcClient *rpc.Client
+	config    Config
+	now       func() time.Time
+}
+
+// New creates a transaction sender
+func New(rpcClient *rpc.Client, config Config) *Sender {
+	if config.Commitment == "" {
+		config.Commitment = rpc.CommitmentConfirmed
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 500 * time.Millisecond
+	}
+	if config.RebroadcastInterval == 0 {
+		config.RebroadcastInterval = 2 * time.Second
+	}
+	if config.MaxRefreshes == 0 {
+		config.MaxRefreshes = 3
+	}
+	return &Sender{
+		rpcClient: rpcClient,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Send submits a signed transaction and rebroadcasts it until it reaches the configured
+// commitment, fails on chain or its blockhash expires. When signers are given, a
+// "blockhash not found" rejection replaces the blockhash and signs the transaction again.
+// The report is returned along with any error.
+func (s *Sender) Send(ctx context.Context, tx *solana.Transaction, signers ...signer.Signer) (*Report, error) {
+	report := &Report{SentAt: s.now()}
+	err := s.track(ctx, tx, signers, report)
+	s.record(report)
+	return report, err
+}
+
+// track runs the submission and confirmation loop, filling in the report
+func (s *Sender) track(ctx context.Context, tx *solana.Transaction, signers []signer.Signer, report *Report) error {
+	var err error
+	if report.Signature, err = s.broadcast(ctx, tx, signers, report); err != nil {
+		report.FailureReason = landing_stats.FailureSendError
+		return fmt.Errorf("failed to send transaction: %w", err)
+	}
+	lastBroadcast := s.now()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		statuses, err := s.rpcClient.GetSignatureStatuses(ctx, false, report.Signature)
+		if err == nil && len(statuses.Value) > 0 && statuses.Value[0] != nil {
+			status := statuses.Value[0]
+			if status.Err != nil {
+				report.Slot, report.Err = status.Slot, status.Err
+				report.FailureReason = landing_stats.FailureExecution
+				report.Fee = s.fee(ctx, report.Signature)
+				return fmt.Errorf("transaction failed: %v", status.Err)
+			}
+			if reached(status.ConfirmationStatus, s.config.Commitment) {
+				report.Landed, report.Slot = true, status.Slot
+				report.Latency = s.now().Sub(report.SentAt)
+				report.Fee = s.fee(ctx, report.Signature)
+				return nil
+			}
+		}
+
+		if s.now().Sub(lastBroadcast) >= s.config.RebroadcastInterval {
+			valid, err := s.rpcClient.IsBlockhashValid(ctx, tx.Message.RecentBlockhash, rpc.CommitmentProcessed)
+			if err == nil && !valid.Value {
+				report.FailureReason = landing_stats.FailureExpired
+				return fmt.Errorf("blockhash of transaction %s expired before it landed", report.Signature)
+			}
+			// resubmissions skip preflight, the transaction already passed it once
+			if _, err := s.rpcClient.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{SkipPreflight: true}); err == nil {
+				report.Broadcasts++
+			}
+			lastBroadcast = s.now()
+		}
+
+		select {
+		case <-ctx.Done():
+			report.FailureReason = landing_stats.FailureTimeout
+			return fmt.Errorf("transaction %s did not land: %w", report.Signature, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// broadcast submits the transaction, replacing an unknown blockhash when it can sign again
+func (s *Sender) broadcast(ctx context.Context, tx *solana.Transaction, signers []signer.Signer, report *Report) (solana.Signature, error) {
+	for {
+		signature, err := s.rpcClient.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{
+			SkipPreflight:       s.config.SkipPreflight,
+			PreflightCommitment: rpc.CommitmentProcessed,
+		})
+		if err == nil {
+			report.Broadcasts++
+			return signature, nil
+		}
+		if !strings.Contains(strings.ToLower(err.Error()), "blockhash not found") || len(signers) == 0 || report.Refreshes >= s.config.MaxRefreshes {
+			return solana.Signature{}, err
+		}
+
+		recent, err := s.rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
+		if err != nil {
+			return solana.Signature{}, fmt.Errorf("failed to refresh blockhash: %w", err)
+		}
+		tx.Message.RecentBlockhash = recent.Value.Blockhash
+		tx.Signatures = nil
+		if err := signer.SignTransaction(ctx, tx, signers...); err != nil {
+			return solana.Signature{}, err
+		}
+		report.Refreshes++
+	}
+}
+
+// fee looks up the fee a confirmed transaction paid, zero when it cannot be read
+func (s *Sender) fee(ctx context.Context, signature solana.Signature) uint64 {
+	maxVersion := uint64(0)
+	tx, err := s.rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil || tx == nil || tx.Meta == nil {
+		return 0
+	}
+	return tx.Meta.Fee
+}
+
+// record reports the outcome to the landing recorder, if set
+func (s *Sender) record(report *Report) {
+	if s.config.Recorder == nil {
+		return
+	}
+	s.config.Recorder.Record(landing_stats.Outcome{
+		Venue:         s.config.Venue,
+		Route:         landing_stats.RoutePublicRPC,
+		Landed:        report.Landed,
+		Latency:       report.Latency,
+		FailureReason: report.FailureReason,
+		Timestamp:     report.SentAt,
+	})
+}
+
+// reached reports whether a confirmation status meets the commitment
+func reached(status rpc.ConfirmationStatusType, commitment rpc.CommitmentType) bool {
+	levels := map[string]int{
+		string(rpc.ConfirmationStatusProcessed): 1,
+		string(rpc.ConfirmationStatusConfirmed): 2,
+		string(rpc.ConfirmationStatusFinalized): 3,
+	}
+	return levels[string(status)] > 0 && levels[string(status)] >= levels[string(commitment)]
+}
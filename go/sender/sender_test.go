@@ -0,0 +1,137 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/landing_stats"
+	"github.com/soralabs/solana-toolkit/go/signer"
+)
+
+// node is a scripted RPC node, statuses are served in order and the last one repeats
+type node struct {
+	mu             sync.Mutex
+	statuses       []string
+	staleBlockhash bool
+	blockhashValid bool
+	sent           []string
+}
+
+func (n *node) serve(t *testing.T) *rpc.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		switch request.Method {
+		case "sendTransaction":
+			var encoded string
+			json.Unmarshal(request.Params[0], &encoded)
+			tx, _ := solana.TransactionFromBase64(encoded)
+			if n.staleBlockhash && tx.Message.RecentBlockhash == (solana.Hash{1}) {
+				fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32002,"message":"Transaction simulation failed: Blockhash not found"}}`)
+				return
+			}
+			n.sent = append(n.sent, encoded)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%q}`, tx.Signatures[0])
+		case "getLatestBlockhash":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":{"blockhash":%q,"lastValidBlockHeight":100}}}`, solana.Hash{2})
+		case "getSignatureStatuses":
+			status := n.statuses[0]
+			if len(n.statuses) > 1 {
+				n.statuses = n.statuses[1:]
+			}
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":12},"value":[%s]}}`, status)
+		case "isBlockhashValid":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":12},"value":%t}}`, n.blockhashValid)
+		case "getTransaction":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"slot":11,"blockTime":null,"meta":{"err":null,"fee":5000,"preBalances":[],"postBalances":[]},"transaction":["","base64"]}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return rpc.New(server.URL)
+}
+
+func transfer(t *testing.T, key solana.PrivateKey) *solana.Transaction {
+	t.Helper()
+	tx, err := solana.NewTransaction([]solana.Instruction{
+		system.NewTransferInstruction(1, key.PublicKey(), solana.NewWallet().PublicKey()).Build(),
+	}, solana.Hash{1}, solana.TransactionPayer(key.PublicKey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &key })
+	return tx
+}
+
+func TestSendRefreshesAndRebroadcasts(t *testing.T) {
+	n := &node{
+		staleBlockhash: true,
+		blockhashValid: true,
+		statuses: []string{
+			"null",
+			"null",
+			`{"slot":11,"confirmations":1,"err":null,"confirmationStatus":"processed"}`,
+			`{"slot":11,"confirmations":2,"err":null,"confirmationStatus":"confirmed"}`,
+		},
+	}
+	recorder := landing_stats.NewRecorder()
+	s := New(n.serve(t), Config{PollInterval: time.Millisecond, RebroadcastInterval: time.Nanosecond, Recorder: recorder, Venue: "test"})
+
+	wallet := solana.NewWallet().PrivateKey
+	tx := transfer(t, wallet)
+	report, err := s.Send(context.Background(), tx, signer.NewKeypair(wallet))
+	if err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+
+	if !report.Landed || report.Slot != 11 || report.Fee != 5_000 || report.Refreshes != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if report.Broadcasts < 2 || len(n.sent) != report.Broadcasts {
+		t.Errorf("expected rebroadcasts while unconfirmed, got %d of %d", report.Broadcasts, len(n.sent))
+	}
+	if tx.Message.RecentBlockhash != (solana.Hash{2}) || report.Signature != tx.Signatures[0] {
+		t.Error("expected the transaction to be signed again with the fresh blockhash")
+	}
+	if stats := recorder.Stats(); len(stats) != 1 || stats[0].Landed != 1 {
+		t.Errorf("expected one landed outcome, got %+v", stats)
+	}
+}
+
+func TestSendReportsFailures(t *testing.T) {
+	wallet := solana.NewWallet().PrivateKey
+
+	failed := &node{blockhashValid: true, statuses: []string{`{"slot":11,"confirmations":1,"err":{"InstructionError":[0,{"Custom":1}]},"confirmationStatus":"confirmed"}`}}
+	report, err := New(failed.serve(t), Config{PollInterval: time.Millisecond}).Send(context.Background(), transfer(t, wallet))
+	if err == nil || report.Landed || report.FailureReason != landing_stats.FailureExecution || report.Fee != 5_000 || report.Err == nil {
+		t.Errorf("expected an execution failure with its fee, got %+v %v", report, err)
+	}
+
+	expired := &node{statuses: []string{"null"}}
+	report, err = New(expired.serve(t), Config{PollInterval: time.Millisecond, RebroadcastInterval: time.Nanosecond}).Send(context.Background(), transfer(t, wallet))
+	if err == nil || report.FailureReason != landing_stats.FailureExpired {
+		t.Errorf("expected an expired blockhash, got %+v %v", report, err)
+	}
+
+	stale := &node{staleBlockhash: true, statuses: []string{"null"}}
+	report, err = New(stale.serve(t), Config{}).Send(context.Background(), transfer(t, wallet))
+	if err == nil || report.FailureReason != landing_stats.FailureSendError || report.Refreshes != 0 {
+		t.Errorf("expected a send error without signers to refresh with, got %+v %v", report, err)
+	}
+}
@@ -0,0 +1,48 @@
+package sender
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/landing_stats"
+)
+
+// Config controls how transactions are submitted and tracked
+type Config struct {
+	// Commitment a transaction must reach to count as landed, defaults to confirmed
+	Commitment rpc.CommitmentType
+	// PollInterval is the delay between signature status checks, defaults to 500ms
+	PollInterval time.Duration
+	// RebroadcastInterval is the delay between resubmissions, defaults to 2s
+	RebroadcastInterval time.Duration
+	// SkipPreflight submits without simulating first
+	SkipPreflight bool
+	// MaxRefreshes bounds how often an unknown blockhash is replaced, defaults to 3
+	MaxRefreshes int
+
+	// Recorder receives the landing outcome of every transaction when set
+	Recorder *landing_stats.Recorder
+	// Venue labels outcomes in the recorder
+	Venue string
+}
+
+// Report describes how a transaction landed, or why it did not
+type Report struct {
+	Signature solana.Signature
+	Landed    bool
+	Slot      uint64
+	// Fee is the fee paid in lamports, also charged when the transaction failed on chain
+	Fee uint64
+	// Err is the on-chain execution error of a landed transaction
+	Err any
+	// FailureReason is one of the landing_stats failure reasons when the transaction did not land
+	FailureReason string
+	// Broadcasts counts submissions, including rebroadcasts
+	Broadcasts int
+	// Refreshes counts blockhash replacements after "blockhash not found"
+	Refreshes int
+	SentAt    time.Time
+	Latency   time.Duration // time from the first submission to confirmation
+}
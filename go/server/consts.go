@@ -0,0 +1,14 @@
+package server
+
+import "time"
+
+const (
+	DEFAULT_ADDR          = ":8080"
+	DEFAULT_SWAPS_LIMIT   = 20
+	MAX_SWAPS_LIMIT       = 100
+	DEFAULT_TIMEOUT       = 30 * time.Second
+	DEFAULT_CONCURRENCY   = 8
+	SOL_DECIMALS          = 9
+	USDC_DECIMALS         = 6
+	SHUTDOWN_GRACE_PERIOD = 5 * time.Second
+)
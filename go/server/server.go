@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/decimals"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+	"github.com/soralabs/solana-toolkit/go/tokenmeta"
+)
+
+// Server exposes the parser and enrichment modules as a JSON REST API
+type Server struct {
+	rpcClient *rpc.Client
+	config    Config
+	decimals  *decimals.Resolver
+	registry  *pools.Registry
+	metadata  *tokenmeta.Fetcher
+}
+
+// New creates an API server reading from the RPC client
+func New(rpcClient *rpc.Client, config Config) *Server {
+	if config.Addr == "" {
+		config.Addr = DEFAULT_ADDR
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DEFAULT_TIMEOUT
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DEFAULT_CONCURRENCY
+	}
+
+	return &Server{
+		rpcClient: rpcClient,
+		config:    config,
+		decimals:  decimals.NewResolver(rpcClient, decimals.Options{}),
+		registry:  pools.NewRegistry(rpcClient, pools.RegistryConfig{}),
+		metadata:  tokenmeta.New(rpcClient, tokenmeta.Config{FetchOffChain: config.FetchOffChain}),
+	}
+}
+
+// Handler returns the HTTP handler serving the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tx/{sig}/parsed", s.handleParsedTx)
+	mux.HandleFunc("GET /wallet/{addr}/swaps", s.handleWalletSwaps)
+	mux.HandleFunc("GET /token/{mint}/price", s.handleTokenPrice)
+	return mux
+}
+
+// ListenAndServe serves the API on the configured address until the context is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	httpServer := &http.Server{Addr: s.config.Addr, Handler: s.Handler()}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_GRACE_PERIOD)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
+
+// handleParsedTx serves GET /tx/{sig}/parsed
+func (s *Server) handleParsedTx(w http.ResponseWriter, r *http.Request) {
+	signature, err := solana.SignatureFromBase58(r.PathValue("sig"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid signature: %w", err))
+		return
+	}
+	withMetadata, _ := strconv.ParseBool(r.URL.Query().Get("metadata"))
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.config.Timeout)
+	defer cancel()
+
+	parsed, err := s.parseTransaction(ctx, signature)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	response := &ParsedResponse{Transaction: parsed}
+	if withMetadata {
+		if response.Metadata, err = s.metadata.ForTransaction(ctx, parsed); err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleWalletSwaps serves GET /wallet/{addr}/swaps. It walks one page of the wallet's
+// signatures, newest first, and returns the swaps the wallet signed. Failed transactions
+// are skipped.
+func (s *Server) handleWalletSwaps(w http.ResponseWriter, r *http.Request) {
+	wallet, err := solana.PublicKeyFromBase58(r.PathValue("addr"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid wallet: %w", err))
+		return
+	}
+	query := r.URL.Query()
+	limit := DEFAULT_SWAPS_LIMIT
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 || limit > MAX_SWAPS_LIMIT {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", MAX_SWAPS_LIMIT))
+			return
+		}
+	}
+	opts := &rpc.GetSignaturesForAddressOpts{Limit: &limit, Commitment: rpc.CommitmentConfirmed}
+	if value := query.Get("before"); value != "" {
+		if opts.Before, err = solana.SignatureFromBase58(value); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid before cursor: %w", err))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.config.Timeout)
+	defer cancel()
+
+	signatures, err := s.rpcClient.GetSignaturesForAddressWithOpts(ctx, wallet, opts)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to get signatures: %w", err))
+		return
+	}
+
+	parsed := make([]*tx_parser.ParsedTransaction, len(signatures))
+	errs := make([]error, len(signatures))
+	sem := make(chan struct{}, s.config.Concurrency)
+	var wg sync.WaitGroup
+	for i, signature := range signatures {
+		if signature.Err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			parsed[i], errs[i] = s.parseTransaction(ctx, signature.Signature)
+		}()
+	}
+	wg.Wait()
+
+	response := &SwapsResponse{Wallet: wallet, Swaps: []*tx_parser.SwapInfo{}}
+	for i, tx := range parsed {
+		if errs[i] != nil {
+			writeError(w, statusOf(errs[i]), errs[i])
+			return
+		}
+		if tx == nil {
+			continue
+		}
+		for _, swap := range tx.Swaps {
+			if solana.PublicKeySlice(swap.Signers).Contains(wallet) {
+				response.Swaps = append(response.Swaps, swap)
+			}
+		}
+	}
+	if len(signatures) == limit {
+		response.Next = signatures[len(signatures)-1].Signature.String()
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleTokenPrice serves GET /token/{mint}/price from the spot prices of the deepest
+// SOL and USDC pools. Tokens only quoted in SOL are valued in USD through the deepest
+// SOL/USDC pool.
+func (s *Server) handleTokenPrice(w http.ResponseWriter, r *http.Request) {
+	mint, err := solana.PublicKeyFromBase58(r.PathValue("mint"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid mint: %w", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.config.Timeout)
+	defer cancel()
+
+	mintDecimals, err := s.decimals.Resolve(ctx, mint)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("failed to resolve mint %s: %w", mint, err))
+		return
+	}
+	response := &PriceResponse{Mint: mint, Decimals: mintDecimals, Pools: []pools.PoolPrice{}}
+
+	sol, usdc := tx_parser.NATIVE_SOL_PROGRAM_ID, pricing.USDC_MINT
+	for _, quote := range []struct {
+		mint     solana.PublicKey
+		decimals uint8
+		price    *float64
+	}{
+		{sol, SOL_DECIMALS, &response.PriceSOL},
+		{usdc, USDC_DECIMALS, &response.PriceUSD},
+	} {
+		if mint.Equals(quote.mint) {
+			continue
+		}
+		prices, err := s.registry.Prices(ctx, mint, mintDecimals, quote.mint, quote.decimals)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Errorf("failed to price pools: %w", err))
+			return
+		}
+		response.Pools = append(response.Pools, prices...)
+		if deepest, ok := pools.DeepestPrice(prices); ok {
+			*quote.price = deepest.Price
+		}
+	}
+	if mint.Equals(sol) {
+		response.PriceSOL = 1
+	}
+	if response.PriceUSD == 0 && response.PriceSOL > 0 {
+		prices, err := s.registry.Prices(ctx, sol, SOL_DECIMALS, usdc, USDC_DECIMALS)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Errorf("failed to price SOL: %w", err))
+			return
+		}
+		if deepest, ok := pools.DeepestPrice(prices); ok {
+			response.PriceUSD = response.PriceSOL * deepest.Price
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// parseTransaction fetches and parses a confirmed transaction
+func (s *Server) parseTransaction(ctx context.Context, signature solana.Signature) (*tx_parser.ParsedTransaction, error) {
+	maxVersion := uint64(0)
+	result, err := s.rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", signature, err)
+	}
+	parser, err := tx_parser.NewWithOptions(result, tx_parser.ParseOptions{DecimalsResolver: s.decimals, TokenExtensionsResolver: s.decimals})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transaction %s: %w", signature, err)
+	}
+	parsed, err := parser.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction %s: %w", signature, err)
+	}
+	return parsed, nil
+}
+
+// statusOf maps an error of the RPC and parsing layers to an HTTP status
+func statusOf(err error) int {
+	if errors.Is(err, rpc.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadGateway
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// testChain serves a wallet with one SOL transfer per slot in [1, last] and a mint
+// without any pools
+type testChain struct {
+	last uint64
+	keys []solana.PublicKey
+	mint solana.PublicKey
+}
+
+func newTestChain(last uint64) *testChain {
+	return &testChain{
+		last: last,
+		keys: []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.SystemProgramID},
+		mint: solana.NewWallet().PublicKey(),
+	}
+}
+
+func slotSignature(slot uint64) solana.Signature {
+	var signature solana.Signature
+	binary.LittleEndian.PutUint64(signature[:], slot)
+	return signature
+}
+
+// encodedTransaction returns the transaction of the slot, transferring slot lamports
+func (c *testChain) encodedTransaction(t *testing.T, slot uint64) string {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], 2)
+	binary.LittleEndian.PutUint64(data[4:12], slot)
+
+	tx := &solana.Transaction{
+		Signatures: []solana.Signature{slotSignature(slot)},
+		Message: solana.Message{
+			AccountKeys:  c.keys,
+			Header:       solana.MessageHeader{NumRequiredSignatures: 1},
+			Instructions: []solana.CompiledInstruction{{ProgramIDIndex: 2, Accounts: []uint16{0, 1}, Data: data}},
+		},
+	}
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	return encoded
+}
+
+func (c *testChain) serve(t *testing.T) *rpc.Client {
+	t.Helper()
+
+	mintData := make([]byte, 82)
+	mintData[44] = 6
+	const meta = `{"fee":5000,"preBalances":[],"postBalances":[]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			ID     any               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		var result string
+		switch request.Method {
+		case "getSignaturesForAddress":
+			var opts struct {
+				Limit  int              `json:"limit"`
+				Before solana.Signature `json:"before"`
+			}
+			json.Unmarshal(request.Params[1], &opts)
+			slot := c.last
+			if !opts.Before.IsZero() {
+				slot = binary.LittleEndian.Uint64(opts.Before[:]) - 1
+			}
+			var signatures []string
+			for ; slot >= 1 && len(signatures) < opts.Limit; slot-- {
+				signatures = append(signatures, fmt.Sprintf(`{"signature":%q,"slot":%d,"err":null}`, slotSignature(slot), slot))
+			}
+			result = "[" + strings.Join(signatures, ",") + "]"
+		case "getTransaction":
+			var signature solana.Signature
+			json.Unmarshal(request.Params[0], &signature)
+			slot := binary.LittleEndian.Uint64(signature[:])
+			if slot == 0 || slot > c.last {
+				result = "null"
+				break
+			}
+			result = fmt.Sprintf(`{"slot":%d,"transaction":[%q,"base64"],"meta":%s}`, slot, c.encodedTransaction(t, slot), meta)
+		case "getMultipleAccounts":
+			var keys []solana.PublicKey
+			json.Unmarshal(request.Params[0], &keys)
+			accounts := make([]string, len(keys))
+			for i, key := range keys {
+				accounts[i] = "null"
+				if key.Equals(c.mint) {
+					accounts[i] = fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+						solana.TokenProgramID, base64.StdEncoding.EncodeToString(mintData))
+				}
+			}
+			result = `{"context":{"slot":1},"value":[` + strings.Join(accounts, ",") + `]}`
+		case "getProgramAccounts":
+			result = "[]"
+		default:
+			t.Errorf("unexpected method %s", request.Method)
+		}
+
+		id, _ := json.Marshal(request.ID)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, id, result)
+	}))
+	t.Cleanup(server.Close)
+
+	return rpc.New(server.URL)
+}
+
+// get requests a path from the API and decodes the JSON body
+func get(t *testing.T, handler http.Handler, path string, body any) int {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if content := recorder.Header().Get("Content-Type"); content != "application/json" {
+		t.Errorf("unexpected content type %q", content)
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), body); err != nil {
+		t.Fatalf("invalid body %q: %v", recorder.Body.String(), err)
+	}
+	return recorder.Code
+}
+
+func TestParsedTx(t *testing.T) {
+	chain := newTestChain(3)
+	handler := New(chain.serve(t), Config{}).Handler()
+
+	var parsed ParsedResponse
+	if status := get(t, handler, "/tx/"+slotSignature(2).String()+"/parsed", &parsed); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	tx := parsed.Transaction
+	if tx.Slot != 2 || len(tx.Transfers) != 1 || tx.Transfers[0].Amount != 2 || !tx.Transfers[0].Source.Equals(chain.keys[0]) {
+		t.Errorf("unexpected transaction %+v", tx)
+	}
+
+	var failed errorResponse
+	if status := get(t, handler, "/tx/"+slotSignature(9).String()+"/parsed", &failed); status != http.StatusNotFound || failed.Error == "" {
+		t.Errorf("expected not found, got %d %+v", status, failed)
+	}
+	if status := get(t, handler, "/tx/invalid/parsed", &failed); status != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %d", status)
+	}
+}
+
+func TestWalletSwaps(t *testing.T) {
+	chain := newTestChain(5)
+	handler := New(chain.serve(t), Config{}).Handler()
+
+	var page SwapsResponse
+	path := "/wallet/" + chain.keys[0].String() + "/swaps?limit=3"
+	if status := get(t, handler, path, &page); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if page.Swaps == nil || len(page.Swaps) != 0 || page.Next != slotSignature(3).String() {
+		t.Errorf("unexpected first page %+v", page)
+	}
+
+	page = SwapsResponse{}
+	if status := get(t, handler, path+"&before="+slotSignature(3).String(), &page); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if page.Next != "" {
+		t.Errorf("expected the last page, got cursor %s", page.Next)
+	}
+
+	var failed errorResponse
+	if status := get(t, handler, "/wallet/"+chain.keys[0].String()+"/swaps?limit=1000", &failed); status != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %d", status)
+	}
+}
+
+func TestTokenPrice(t *testing.T) {
+	chain := newTestChain(1)
+	handler := New(chain.serve(t), Config{}).Handler()
+
+	var price PriceResponse
+	if status := get(t, handler, "/token/"+chain.mint.String()+"/price", &price); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if !price.Mint.Equals(chain.mint) || price.Decimals != 6 || price.Pools == nil || price.PriceSOL != 0 || price.PriceUSD != 0 {
+		t.Errorf("unexpected price %+v", price)
+	}
+
+	var failed errorResponse
+	if status := get(t, handler, "/token/"+solana.NewWallet().PublicKey().String()+"/price", &failed); status != http.StatusNotFound {
+		t.Errorf("expected not found, got %d", status)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/tokenmeta"
+)
+
+// Config controls the API server
+type Config struct {
+	// Addr is the address ListenAndServe listens on, defaults to DEFAULT_ADDR
+	Addr string
+
+	// Timeout bounds the RPC work of each request, defaults to DEFAULT_TIMEOUT
+	Timeout time.Duration
+
+	// Concurrency is the number of transactions fetched in parallel for the swaps
+	// endpoint, defaults to DEFAULT_CONCURRENCY
+	Concurrency int
+
+	// FetchOffChain downloads the off-chain metadata JSON when metadata is requested
+	FetchOffChain bool
+}
+
+// ParsedResponse is the body of GET /tx/{sig}/parsed
+type ParsedResponse struct {
+	Transaction *tx_parser.ParsedTransaction `json:"transaction"`
+	// Metadata of the mints the transaction touches, set with ?metadata=true
+	Metadata map[solana.PublicKey]*tokenmeta.Metadata `json:"metadata,omitempty"`
+}
+
+// SwapsResponse is the body of GET /wallet/{addr}/swaps
+type SwapsResponse struct {
+	Wallet solana.PublicKey      `json:"wallet"`
+	Swaps  []*tx_parser.SwapInfo `json:"swaps"`
+	// Next is the before cursor of the following page, empty on the last page
+	Next string `json:"next,omitempty"`
+}
+
+// PriceResponse is the body of GET /token/{mint}/price
+type PriceResponse struct {
+	Mint     solana.PublicKey  `json:"mint"`
+	Decimals uint8             `json:"decimals"`
+	PriceSOL float64           `json:"price_sol,omitempty"`
+	PriceUSD float64           `json:"price_usd,omitempty"`
+	Pools    []pools.PoolPrice `json:"pools"`
+}
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
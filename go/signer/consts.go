@@ -0,0 +1,47 @@
+package signer
+
+const (
+	// KEYSTORE_VERSION is the version of the encrypted keystore format
+	KEYSTORE_VERSION = 1
+
+	// SCRYPT_N is the scrypt cost parameter used for new keystores
+	SCRYPT_N = 1 << 15
+	// SCRYPT_R is the scrypt block size used for new keystores
+	SCRYPT_R = 8
+	// SCRYPT_P is the scrypt parallelization used for new keystores
+	SCRYPT_P = 1
+
+	// DEFAULT_KEY_ENV is the environment variable read when no name is given
+	DEFAULT_KEY_ENV = "SOLANA_PRIVATE_KEY"
+)
+
+const (
+	// LEDGER_VENDOR_ID is the USB vendor ID of Ledger devices
+	LEDGER_VENDOR_ID = 0x2c97
+
+	// LEDGER_CLA is the APDU class of the Solana Ledger app
+	LEDGER_CLA = 0xe0
+	// LEDGER_INS_GET_PUBKEY returns the public key of a derivation path
+	LEDGER_INS_GET_PUBKEY = 0x05
+	// LEDGER_INS_SIGN_MESSAGE signs a serialized transaction message
+	LEDGER_INS_SIGN_MESSAGE = 0x06
+
+	// LEDGER_MAX_CHUNK is the largest APDU payload the app accepts
+	LEDGER_MAX_CHUNK = 255
+	// LEDGER_HID_PACKET_SIZE is the size of one HID report
+	LEDGER_HID_PACKET_SIZE = 64
+)
+
+// APDU parameters of the Solana Ledger app
+const (
+	ledgerP1NonConfirm = 0x00
+	ledgerP1Confirm    = 0x01
+	ledgerP2Extend     = 0x01
+	ledgerP2More       = 0x02
+
+	ledgerChannel = 0x0101
+	ledgerTagAPDU = 0x05
+
+	ledgerStatusOK       = 0x9000
+	ledgerStatusRejected = 0x6985
+)
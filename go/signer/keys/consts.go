@@ -0,0 +1,20 @@
+package keys
+
+const (
+	// SOLANA_COIN_TYPE is the SLIP-44 coin type of Solana
+	SOLANA_COIN_TYPE = 501
+
+	// DEFAULT_PATH is the derivation path wallets use for the first account
+	DEFAULT_PATH = "m/44'/501'/0'/0'"
+
+	// DEFAULT_MNEMONIC_BITS is the entropy of new mnemonics, 24 words
+	DEFAULT_MNEMONIC_BITS = 256
+
+	// BASE58_ALPHABET are the characters an address can contain
+	BASE58_ALPHABET = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+const (
+	hardenedOffset = 0x80000000
+	ed25519Curve   = "ed25519 seed"
+)
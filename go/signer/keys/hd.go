@@ -0,0 +1,97 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// NewMnemonic generates a BIP39 mnemonic from the given entropy bits, 128 to 256 in steps
+// of 32. Zero uses DEFAULT_MNEMONIC_BITS.
+func NewMnemonic(bits int) (string, error) {
+	if bits == 0 {
+		bits = DEFAULT_MNEMONIC_BITS
+	}
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("failed to create mnemonic: %w", err)
+	}
+	return mnemonic, nil
+}
+
+// FromMnemonic derives the key at a path from a BIP39 mnemonic and optional passphrase.
+// An empty path uses DEFAULT_PATH.
+func FromMnemonic(mnemonic, passphrase, path string) (solana.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(strings.Join(strings.Fields(mnemonic), " "), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	if path == "" {
+		path = DEFAULT_PATH
+	}
+	return DeriveKey(seed, path)
+}
+
+// AccountPath is the derivation path of the nth wallet account, m/44'/501'/n'/0'
+func AccountPath(account uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/0'", SOLANA_COIN_TYPE, account)
+}
+
+// DeriveKey derives an ed25519 key from a seed along a SLIP-10 path. Ed25519 only supports
+// hardened derivation, so every index is hardened whether or not it is marked.
+func DeriveKey(seed []byte, path string) (solana.PrivateKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	key, chainCode := slip10(seed)
+	for _, index := range indexes {
+		data := make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, index)
+		key, chainCode = hmacSplit(chainCode, data)
+	}
+	return solana.PrivateKey(ed25519.NewKeyFromSeed(key)), nil
+}
+
+// ParsePath parses a path like m/44'/501'/0'/0' into hardened indexes
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		index, err := strconv.ParseUint(strings.TrimRight(part, "'hH"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q in derivation path %q", part, path)
+		}
+		indexes = append(indexes, uint32(index)|hardenedOffset)
+	}
+	return indexes, nil
+}
+
+// slip10 derives the master key and chain code from a seed
+func slip10(seed []byte) ([]byte, []byte) {
+	return hmacSplit([]byte(ed25519Curve), seed)
+}
+
+// hmacSplit returns both halves of HMAC-SHA512(key, data)
+func hmacSplit(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
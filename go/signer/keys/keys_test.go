@@ -0,0 +1,73 @@
+package keys
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// SLIP-10 ed25519 test vector 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	for path, expected := range map[string]string{
+		"m":          "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+		"m/0'":       "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+		"m/0'/1'/2'": "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9",
+	} {
+		key, err := DeriveKey(seed, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key[:32]); got != expected {
+			t.Errorf("%s: expected %s, got %s", path, expected, got)
+		}
+	}
+
+	if _, err := ParsePath("44'/501'"); err == nil {
+		t.Error("expected a path without m to fail")
+	}
+	if AccountPath(3) != "m/44'/501'/3'/0'" {
+		t.Errorf("unexpected account path %s", AccountPath(3))
+	}
+}
+
+func TestFromMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := len(strings.Fields(mnemonic)); words != 24 {
+		t.Fatalf("expected 24 words, got %d", words)
+	}
+	first, err := FromMnemonic(mnemonic, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := FromMnemonic(mnemonic, "", AccountPath(1))
+	withPassphrase, _ := FromMnemonic(mnemonic, "secret", "")
+	if first.PublicKey().Equals(second.PublicKey()) || first.PublicKey().Equals(withPassphrase.PublicKey()) {
+		t.Error("expected distinct keys per account and passphrase")
+	}
+	if _, err := FromMnemonic("abandon abandon abandon", "", ""); err == nil {
+		t.Error("expected an invalid mnemonic to fail")
+	}
+}
+
+func TestGrind(t *testing.T) {
+	result, err := Grind(context.Background(), VanityOptions{Prefix: "a", Suffix: "B", IgnoreCase: true, Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := strings.ToLower(result.Key.PublicKey().String())
+	if !strings.HasPrefix(address, "a") || !strings.HasSuffix(address, "b") || result.Attempts == 0 {
+		t.Errorf("unexpected match %s after %d attempts", address, result.Attempts)
+	}
+
+	if _, err := Grind(context.Background(), VanityOptions{Prefix: "0"}); err == nil {
+		t.Error("expected a character outside base58 to fail")
+	}
+	if _, err := Grind(context.Background(), VanityOptions{Prefix: "zzzzzzzz", MaxAttempts: 100}); err == nil {
+		t.Error("expected the attempt limit to stop the search")
+	}
+}
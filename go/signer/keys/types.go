@@ -0,0 +1,28 @@
+package keys
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// VanityOptions configures a vanity address search
+type VanityOptions struct {
+	// Prefix the address must start with
+	Prefix string
+	// Suffix the address must end with
+	Suffix string
+	// IgnoreCase matches the prefix and suffix case insensitively
+	IgnoreCase bool
+	// Workers is the number of goroutines grinding keys, defaults to the number of CPUs
+	Workers int
+	// MaxAttempts stops the search after this many keys when set
+	MaxAttempts uint64
+}
+
+// VanityResult is a key pair whose address matched
+type VanityResult struct {
+	Key      solana.PrivateKey
+	Attempts uint64
+	Duration time.Duration
+}
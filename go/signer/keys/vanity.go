@@ -0,0 +1,88 @@
+package keys
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Grind generates key pairs on every worker until an address matches the prefix and
+// suffix, the context is cancelled or MaxAttempts is reached
+func Grind(ctx context.Context, options VanityOptions) (*VanityResult, error) {
+	if options.Prefix == "" && options.Suffix == "" {
+		return nil, fmt.Errorf("a prefix or suffix is required")
+	}
+	for _, c := range options.Prefix + options.Suffix {
+		if !possible(c, options.IgnoreCase) {
+			return nil, fmt.Errorf("%q can never appear in an address", c)
+		}
+	}
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+	prefix, suffix := options.Prefix, options.Suffix
+	if options.IgnoreCase {
+		prefix, suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	start := time.Now()
+
+	var attempts atomic.Uint64
+	var once sync.Once
+	var found solana.PrivateKey
+	var wg sync.WaitGroup
+	for range options.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				n := attempts.Add(1)
+				if options.MaxAttempts > 0 && n > options.MaxAttempts {
+					cancel()
+					return
+				}
+				public, private, err := ed25519.GenerateKey(rand.Reader)
+				if err != nil {
+					continue
+				}
+				address := solana.PublicKeyFromBytes(public).String()
+				if options.IgnoreCase {
+					address = strings.ToLower(address)
+				}
+				if strings.HasPrefix(address, prefix) && strings.HasSuffix(address, suffix) {
+					once.Do(func() { found = solana.PrivateKey(private) })
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if found == nil {
+		if err := parent.Err(); err != nil {
+			return nil, fmt.Errorf("vanity search stopped: %w", err)
+		}
+		return nil, fmt.Errorf("no matching address after %d attempts", options.MaxAttempts)
+	}
+	return &VanityResult{Key: found, Attempts: attempts.Load(), Duration: time.Since(start)}, nil
+}
+
+// possible reports whether a character can appear in a base58 address
+func possible(c rune, ignoreCase bool) bool {
+	if ignoreCase {
+		return strings.ContainsAny(BASE58_ALPHABET, strings.ToUpper(string(c))+strings.ToLower(string(c)))
+	}
+	return strings.ContainsRune(BASE58_ALPHABET, c)
+}
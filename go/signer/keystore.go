@@ -0,0 +1,108 @@
+package signer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gagliardetto/solana-go"
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptKey seals a private key under a passphrase
+func EncryptKey(key solana.PrivateKey, passphrase string) (*Keystore, error) {
+	if _, err := solana.ValidatePrivateKey(key); err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	keystore := &Keystore{
+		Version:   KEYSTORE_VERSION,
+		PublicKey: key.PublicKey(),
+		KDF:       KDFParams{N: SCRYPT_N, R: SCRYPT_R, P: SCRYPT_P, Salt: make([]byte, 32)},
+	}
+	if _, err := rand.Read(keystore.KDF.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := keystore.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	keystore.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(keystore.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	keystore.Ciphertext = aead.Seal(nil, keystore.Nonce, key, keystore.PublicKey[:])
+	return keystore, nil
+}
+
+// Decrypt opens the keystore, failing on a wrong passphrase
+func (k *Keystore) Decrypt(passphrase string) (solana.PrivateKey, error) {
+	if k.Version != KEYSTORE_VERSION {
+		return nil, fmt.Errorf("unsupported keystore version %d", k.Version)
+	}
+	aead, err := k.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(k.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid keystore nonce")
+	}
+	plaintext, err := aead.Open(nil, k.Nonce, k.Ciphertext, k.PublicKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore, wrong passphrase?")
+	}
+	key := solana.PrivateKey(plaintext)
+	if !key.PublicKey().Equals(k.PublicKey) {
+		return nil, fmt.Errorf("keystore key does not match public key %s", k.PublicKey)
+	}
+	return key, nil
+}
+
+// WriteKeystore encrypts a private key and writes it to path, readable only by the owner
+func WriteKeystore(path string, key solana.PrivateKey, passphrase string) error {
+	keystore, err := EncryptKey(key, passphrase)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(keystore, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode keystore: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	return nil
+}
+
+// FromKeystore loads and decrypts a keystore file
+func FromKeystore(path, passphrase string) (*Keypair, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	var keystore Keystore
+	if err := json.Unmarshal(data, &keystore); err != nil {
+		return nil, fmt.Errorf("failed to decode keystore %s: %w", path, err)
+	}
+	key, err := keystore.Decrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeypair(key), nil
+}
+
+// cipher derives the AES-256-GCM cipher from the passphrase
+func (k *Keystore) cipher(passphrase string) (cipher.AEAD, error) {
+	derived, err := scrypt.Key([]byte(passphrase), k.KDF.Salt, k.KDF.N, k.KDF.R, k.KDF.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive keystore key: %w", err)
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,169 @@
+package signer
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Transport exchanges APDUs with a Ledger device
+type Transport interface {
+	Exchange(apdu []byte) ([]byte, error)
+	Close() error
+}
+
+// Ledger signs on a Ledger device running the Solana app, each signature is confirmed on
+// the device
+type Ledger struct {
+	mu        sync.Mutex
+	transport Transport
+	path      []byte
+	publicKey solana.PublicKey
+}
+
+// NewLedger opens the Solana app key at m/44'/501'/account'/change' over the transport
+func NewLedger(transport Transport, account, change uint32) (*Ledger, error) {
+	l := &Ledger{transport: transport, path: derivationPath(account, change)}
+	response, err := l.exchange(LEDGER_INS_GET_PUBKEY, ledgerP1NonConfirm, 0, l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger public key: %w", err)
+	}
+	if len(response) != solana.PublicKeyLength {
+		return nil, fmt.Errorf("unexpected ledger public key of %d bytes", len(response))
+	}
+	l.publicKey = solana.PublicKeyFromBytes(response)
+	return l, nil
+}
+
+// PublicKey returns the key of the derivation path
+func (l *Ledger) PublicKey() solana.PublicKey {
+	return l.publicKey
+}
+
+// Sign sends the message to the device and waits for the user to approve it
+func (l *Ledger) Sign(ctx context.Context, message []byte) (solana.Signature, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// the first chunk carries the signer count and derivation path ahead of the message
+	payload := append([]byte{1}, l.path...)
+	payload = append(payload, message...)
+
+	var response []byte
+	for offset, p2 := 0, byte(0); offset < len(payload); p2 |= ledgerP2Extend {
+		if err := ctx.Err(); err != nil {
+			return solana.Signature{}, err
+		}
+		end := min(offset+LEDGER_MAX_CHUNK, len(payload))
+		chunkP2 := p2
+		if end < len(payload) {
+			chunkP2 |= ledgerP2More
+		}
+		var err error
+		if response, err = l.exchange(LEDGER_INS_SIGN_MESSAGE, ledgerP1Confirm, chunkP2, payload[offset:end]); err != nil {
+			return solana.Signature{}, fmt.Errorf("failed to sign on ledger: %w", err)
+		}
+		offset = end
+	}
+
+	if len(response) != 64 {
+		return solana.Signature{}, fmt.Errorf("unexpected ledger signature of %d bytes", len(response))
+	}
+	signature := solana.SignatureFromBytes(response)
+	if !signature.Verify(l.publicKey, message) {
+		return solana.Signature{}, fmt.Errorf("ledger returned an invalid signature")
+	}
+	return signature, nil
+}
+
+// Close releases the device
+func (l *Ledger) Close() error {
+	return l.transport.Close()
+}
+
+// exchange sends one APDU and checks its status word
+func (l *Ledger) exchange(ins, p1, p2 byte, data []byte) ([]byte, error) {
+	apdu := append([]byte{LEDGER_CLA, ins, p1, p2, byte(len(data))}, data...)
+	response, err := l.transport.Exchange(apdu)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) < 2 {
+		return nil, fmt.Errorf("short ledger response")
+	}
+	status := binary.BigEndian.Uint16(response[len(response)-2:])
+	switch status {
+	case ledgerStatusOK:
+		return response[:len(response)-2], nil
+	case ledgerStatusRejected:
+		return nil, fmt.Errorf("rejected on the device")
+	default:
+		return nil, fmt.Errorf("ledger status 0x%04x, is the Solana app open?", status)
+	}
+}
+
+// derivationPath encodes m/44'/501'/account'/change' as the app expects
+func derivationPath(account, change uint32) []byte {
+	const hardened = 0x80000000
+	path := []byte{4}
+	for _, index := range []uint32{44, 501, account, change} {
+		path = binary.BigEndian.AppendUint32(path, index|hardened)
+	}
+	return path
+}
+
+// hidTransport frames APDUs into Ledger HID reports over a raw HID device
+type hidTransport struct {
+	device io.ReadWriteCloser
+}
+
+// NewHIDTransport frames APDUs over an opened raw HID device
+func NewHIDTransport(device io.ReadWriteCloser) Transport {
+	return &hidTransport{device: device}
+}
+
+// Exchange writes the APDU and reads the reassembled response
+func (h *hidTransport) Exchange(apdu []byte) ([]byte, error) {
+	data := binary.BigEndian.AppendUint16(nil, uint16(len(apdu)))
+	data = append(data, apdu...)
+	for sequence := uint16(0); len(data) > 0 || sequence == 0; sequence++ {
+		// report ID 0 followed by the channel, tag and sequence header
+		packet := make([]byte, 1+LEDGER_HID_PACKET_SIZE)
+		binary.BigEndian.PutUint16(packet[1:], ledgerChannel)
+		packet[3] = ledgerTagAPDU
+		binary.BigEndian.PutUint16(packet[4:], sequence)
+		n := copy(packet[6:], data)
+		data = data[n:]
+		if _, err := h.device.Write(packet); err != nil {
+			return nil, fmt.Errorf("failed to write to ledger: %w", err)
+		}
+	}
+
+	var response []byte
+	length := -1
+	for sequence := uint16(0); length < 0 || len(response) < length; sequence++ {
+		packet := make([]byte, LEDGER_HID_PACKET_SIZE)
+		if _, err := io.ReadFull(h.device, packet); err != nil {
+			return nil, fmt.Errorf("failed to read from ledger: %w", err)
+		}
+		if binary.BigEndian.Uint16(packet) != ledgerChannel || packet[2] != ledgerTagAPDU || binary.BigEndian.Uint16(packet[3:]) != sequence {
+			return nil, fmt.Errorf("unexpected ledger packet header %x", packet[:5])
+		}
+		body := packet[5:]
+		if sequence == 0 {
+			length = int(binary.BigEndian.Uint16(body))
+			body = body[2:]
+		}
+		response = append(response, body...)
+	}
+	return response[:length], nil
+}
+
+// Close closes the device
+func (h *hidTransport) Close() error {
+	return h.device.Close()
+}
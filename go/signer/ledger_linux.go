@@ -0,0 +1,29 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OpenLedger opens the first Ledger device found among the raw HID devices
+func OpenLedger() (Transport, error) {
+	devices, err := filepath.Glob("/sys/class/hidraw/hidraw*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hid devices: %w", err)
+	}
+	vendor := fmt.Sprintf(":%08X:", LEDGER_VENDOR_ID)
+	for _, device := range devices {
+		uevent, err := os.ReadFile(filepath.Join(device, "device", "uevent"))
+		if err != nil || !strings.Contains(string(uevent), vendor) {
+			continue
+		}
+		file, err := os.OpenFile(filepath.Join("/dev", filepath.Base(device)), os.O_RDWR, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ledger: %w", err)
+		}
+		return NewHIDTransport(file), nil
+	}
+	return nil, fmt.Errorf("no ledger device found")
+}
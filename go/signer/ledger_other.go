@@ -0,0 +1,10 @@
+//go:build !linux
+
+package signer
+
+import "fmt"
+
+// OpenLedger is only supported on Linux, elsewhere pass a Transport to NewLedger
+func OpenLedger() (Transport, error) {
+	return nil, fmt.Errorf("ledger hid transport is not supported on this platform")
+}
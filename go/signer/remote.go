@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Remote signs through an HTTP signing service so the key never leaves it
+type Remote struct {
+	config RemoteConfig
+}
+
+// NewRemote creates a remote signer
+func NewRemote(config RemoteConfig) (*Remote, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("remote signer URL is required")
+	}
+	if config.PublicKey.IsZero() {
+		return nil, fmt.Errorf("remote signer public key is required")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Remote{config: config}, nil
+}
+
+// PublicKey returns the key the service signs for
+func (r *Remote) PublicKey() solana.PublicKey {
+	return r.config.PublicKey
+}
+
+// Sign asks the service to sign the message and verifies the returned signature
+func (r *Remote) Sign(ctx context.Context, message []byte) (solana.Signature, error) {
+	body, err := json.Marshal(signRequest{PublicKey: r.config.PublicKey, Message: message})
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to encode sign request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to create sign request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.config.Token)
+	}
+
+	resp, err := r.config.HTTPClient.Do(req)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to call remote signer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result signResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to decode sign response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Error != "" {
+		return solana.Signature{}, fmt.Errorf("remote signer returned %d: %s", resp.StatusCode, result.Error)
+	}
+	if !result.Signature.Verify(r.config.PublicKey, message) {
+		return solana.Signature{}, fmt.Errorf("remote signer returned an invalid signature")
+	}
+	return result.Signature, nil
+}
@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Signer signs transaction messages for a single public key
+type Signer interface {
+	PublicKey() solana.PublicKey
+	// Sign returns the ed25519 signature of the serialized message
+	Sign(ctx context.Context, message []byte) (solana.Signature, error)
+}
+
+// SignTransaction fills in the signatures of the transaction's required signers. Existing
+// signatures of keys without a signer are kept, any missing signature fails.
+func SignTransaction(ctx context.Context, tx *solana.Transaction, signers ...Signer) error {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction message: %w", err)
+	}
+
+	required := tx.Message.Signers()
+	signatures := make([]solana.Signature, len(required))
+	copy(signatures, tx.Signatures)
+	for i, key := range required {
+		var found Signer
+		for _, s := range signers {
+			if s.PublicKey().Equals(key) {
+				found = s
+				break
+			}
+		}
+		if found == nil {
+			if signatures[i].IsZero() {
+				return fmt.Errorf("missing signer for %s", key)
+			}
+			continue
+		}
+		if signatures[i], err = found.Sign(ctx, message); err != nil {
+			return fmt.Errorf("failed to sign for %s: %w", key, err)
+		}
+	}
+	tx.Signatures = signatures
+	return nil
+}
+
+// Keypair signs with a private key held in memory
+type Keypair struct {
+	key solana.PrivateKey
+}
+
+// NewKeypair wraps a private key
+func NewKeypair(key solana.PrivateKey) *Keypair {
+	return &Keypair{key: key}
+}
+
+// FromFile loads a keypair file written by solana-keygen, a JSON array of the 64 key bytes
+func FromFile(path string) (*Keypair, error) {
+	key, err := solana.PrivateKeyFromSolanaKeygenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keypair %s: %w", path, err)
+	}
+	return NewKeypair(key), nil
+}
+
+// FromEnv loads a key from an environment variable holding either a base58 private key or
+// a solana-keygen JSON array. An empty name reads DEFAULT_KEY_ENV.
+func FromEnv(name string) (*Keypair, error) {
+	if name == "" {
+		name = DEFAULT_KEY_ENV
+	}
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", name)
+	}
+	key, err := parseKey(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key from %s: %w", name, err)
+	}
+	return NewKeypair(key), nil
+}
+
+// PublicKey returns the key pair's public key
+func (k *Keypair) PublicKey() solana.PublicKey {
+	return k.key.PublicKey()
+}
+
+// Sign signs the message with the private key
+func (k *Keypair) Sign(_ context.Context, message []byte) (solana.Signature, error) {
+	return k.key.Sign(message)
+}
+
+// parseKey reads a base58 or JSON array private key
+func parseKey(value string) (solana.PrivateKey, error) {
+	if strings.HasPrefix(value, "[") {
+		return solana.PrivateKeyFromSolanaKeygenFileBytes([]byte(value))
+	}
+	return solana.PrivateKeyFromBase58(value)
+}
@@ -0,0 +1,196 @@
+package signer
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+func transfer(t *testing.T, from, to solana.PublicKey) *solana.Transaction {
+	t.Helper()
+	tx, err := solana.NewTransaction([]solana.Instruction{
+		system.NewTransferInstruction(1, from, to).Build(),
+	}, solana.Hash{1}, solana.TransactionPayer(from))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestSignTransaction(t *testing.T) {
+	key := solana.NewWallet().PrivateKey
+	tx := transfer(t, key.PublicKey(), solana.NewWallet().PublicKey())
+
+	if err := SignTransaction(context.Background(), tx); err == nil {
+		t.Error("expected a missing signer to fail")
+	}
+	if err := SignTransaction(context.Background(), tx, NewKeypair(key)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Errorf("invalid signature: %v", err)
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	key := solana.NewWallet().PrivateKey
+	dir := t.TempDir()
+
+	ints := make([]int, len(key))
+	for i, b := range key {
+		ints[i] = int(b)
+	}
+	keygen, _ := json.Marshal(ints)
+	path := filepath.Join(dir, "id.json")
+	os.WriteFile(path, keygen, 0o600)
+	if k, err := FromFile(path); err != nil || !k.PublicKey().Equals(key.PublicKey()) {
+		t.Errorf("failed to load keypair file: %v", err)
+	}
+
+	t.Setenv("TEST_KEY", key.String())
+	if k, err := FromEnv("TEST_KEY"); err != nil || !k.PublicKey().Equals(key.PublicKey()) {
+		t.Errorf("failed to load base58 key: %v", err)
+	}
+	t.Setenv(DEFAULT_KEY_ENV, string(keygen))
+	if k, err := FromEnv(""); err != nil || !k.PublicKey().Equals(key.PublicKey()) {
+		t.Errorf("failed to load JSON key: %v", err)
+	}
+	if _, err := FromEnv("TEST_KEY_UNSET"); err == nil {
+		t.Error("expected an unset variable to fail")
+	}
+
+	keystore := filepath.Join(dir, "keystore.json")
+	if err := WriteKeystore(keystore, key, "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if k, err := FromKeystore(keystore, "hunter2"); err != nil || !k.PublicKey().Equals(key.PublicKey()) {
+		t.Errorf("failed to decrypt keystore: %v", err)
+	}
+	if _, err := FromKeystore(keystore, "wrong"); err == nil {
+		t.Error("expected a wrong passphrase to fail")
+	}
+}
+
+func TestRemote(t *testing.T) {
+	key := solana.NewWallet().PrivateKey
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		var request signRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		signature, _ := key.Sign(request.Message)
+		json.NewEncoder(w).Encode(signResponse{Signature: signature})
+	}))
+	t.Cleanup(server.Close)
+
+	remote, err := NewRemote(RemoteConfig{URL: server.URL, PublicKey: key.PublicKey(), Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transfer(t, key.PublicKey(), solana.NewWallet().PublicKey())
+	if err := SignTransaction(context.Background(), tx, remote); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.VerifySignatures(); err != nil || authorization != "Bearer secret" {
+		t.Errorf("unexpected remote signature %v, authorization %q", err, authorization)
+	}
+
+	// a service signing with another key is rejected
+	other, _ := NewRemote(RemoteConfig{URL: server.URL, PublicKey: solana.NewWallet().PublicKey()})
+	if _, err := other.Sign(context.Background(), []byte("message")); err == nil {
+		t.Error("expected a mismatched signature to fail")
+	}
+}
+
+// ledgerDevice emulates the Solana app behind the HID framing
+type ledgerDevice struct {
+	key     solana.PrivateKey
+	pending []byte
+	message []byte
+	chunks  int
+	out     [][]byte
+}
+
+func (d *ledgerDevice) Write(packet []byte) (int, error) {
+	d.pending = append(d.pending, packet[6:]...)
+	length := int(binary.BigEndian.Uint16(d.pending))
+	if len(d.pending)-2 < length {
+		return len(packet), nil
+	}
+	apdu := d.pending[2 : 2+length]
+	d.pending = nil
+
+	response := []byte{0x90, 0x00}
+	switch apdu[1] {
+	case LEDGER_INS_GET_PUBKEY:
+		response = append(d.key.PublicKey().Bytes(), response...)
+	case LEDGER_INS_SIGN_MESSAGE:
+		data := apdu[5:]
+		if apdu[3]&ledgerP2Extend == 0 {
+			data = data[1+1+4*4:]
+		}
+		d.message = append(d.message, data...)
+		d.chunks++
+		if apdu[3]&ledgerP2More == 0 {
+			signature, _ := d.key.Sign(d.message)
+			response = append(signature[:], response...)
+		}
+	}
+
+	data := binary.BigEndian.AppendUint16(nil, uint16(len(response)))
+	data = append(data, response...)
+	for sequence := uint16(0); len(data) > 0; sequence++ {
+		packet := make([]byte, LEDGER_HID_PACKET_SIZE)
+		binary.BigEndian.PutUint16(packet, ledgerChannel)
+		packet[2] = ledgerTagAPDU
+		binary.BigEndian.PutUint16(packet[3:], sequence)
+		data = data[copy(packet[5:], data):]
+		d.out = append(d.out, packet)
+	}
+	return len(packet), nil
+}
+
+func (d *ledgerDevice) Read(p []byte) (int, error) {
+	n := copy(p, d.out[0])
+	d.out = d.out[1:]
+	return n, nil
+}
+
+func (d *ledgerDevice) Close() error {
+	return nil
+}
+
+func TestLedger(t *testing.T) {
+	device := &ledgerDevice{key: solana.NewWallet().PrivateKey}
+	ledger, err := NewLedger(NewHIDTransport(device), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ledger.PublicKey().Equals(device.key.PublicKey()) {
+		t.Fatalf("unexpected ledger key %s", ledger.PublicKey())
+	}
+
+	// enough transfers to span several APDU chunks
+	var instructions []solana.Instruction
+	for range 8 {
+		instructions = append(instructions, system.NewTransferInstruction(1, ledger.PublicKey(), solana.NewWallet().PublicKey()).Build())
+	}
+	tx, err := solana.NewTransaction(instructions, solana.Hash{1}, solana.TransactionPayer(ledger.PublicKey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignTransaction(context.Background(), tx, ledger); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.VerifySignatures(); err != nil || device.chunks < 2 {
+		t.Errorf("expected a chunked valid signature, got %v over %d chunks", err, device.chunks)
+	}
+}
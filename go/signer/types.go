@@ -0,0 +1,50 @@
+package signer
+
+import (
+	"net/http"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Keystore is the JSON form of a private key encrypted under a passphrase with scrypt
+// and AES-256-GCM
+type Keystore struct {
+	Version   int              `json:"version"`
+	PublicKey solana.PublicKey `json:"public_key"`
+	KDF       KDFParams        `json:"kdf"`
+	// Nonce is the AES-GCM nonce
+	Nonce []byte `json:"nonce"`
+	// Ciphertext is the sealed 64 byte private key, authenticated with the public key
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// KDFParams are the scrypt parameters that derive the keystore encryption key
+type KDFParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// RemoteConfig configures a signer that delegates to a signing service
+type RemoteConfig struct {
+	// URL is the signing endpoint, it receives POST requests with a signRequest body
+	URL string
+	// PublicKey is the key the service signs for
+	PublicKey solana.PublicKey
+	// Token is sent as a bearer token when set
+	Token string
+	// HTTPClient overrides the client used for requests
+	HTTPClient *http.Client
+}
+
+type signRequest struct {
+	PublicKey solana.PublicKey `json:"public_key"`
+	// Message is the serialized transaction message
+	Message []byte `json:"message"`
+}
+
+type signResponse struct {
+	Signature solana.Signature `json:"signature"`
+	Error     string           `json:"error"`
+}
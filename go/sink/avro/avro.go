@@ -0,0 +1,150 @@
+package avro
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// magicByte starts messages in the Confluent wire format, followed by the schema ID
+const magicByte = 0
+
+// Encoder encodes events as Avro, with a record per event kind holding the envelope and the
+// kind's payload. Field names follow the JSON encoding. With a registry each kind's schema
+// is registered on first use and messages use the Confluent wire format, readable by
+// Confluent deserializers, otherwise messages are bare Avro binary.
+type Encoder struct {
+	registry *Registry
+	config   Config
+	kinds    map[sink.Kind]*kindCodec
+}
+
+var _ sink.Encoder = (*Encoder)(nil)
+
+// kindCodec is the schema and encoder of one event kind
+type kindCodec struct {
+	schema  string
+	subject string
+	encode  encodeFunc
+	payload int // index of the payload field in sink.Event
+}
+
+// NewEncoder derives the schemas of every event kind. The registry may be nil.
+func NewEncoder(registry *Registry, config Config) (*Encoder, error) {
+	if config.Namespace == "" {
+		config.Namespace = "solana_toolkit"
+	}
+	if config.Subject == "" {
+		config.Subject = "{record}"
+	}
+
+	eventType := reflect.TypeOf(sink.Event{})
+	var envelope, payloads []field
+	for _, f := range fields(eventType) {
+		t := eventType.Field(f.index).Type
+		if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+			payloads = append(payloads, f)
+		} else {
+			envelope = append(envelope, f)
+		}
+	}
+
+	b := newBuilder(config.Namespace)
+	envelopeEncode, err := b.recordEncoder(eventType, envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive envelope encoder: %w", err)
+	}
+	e := &Encoder{registry: registry, config: config, kinds: make(map[sink.Kind]*kindCodec)}
+	for _, payload := range payloads {
+		kind := sink.Kind(payload.name)
+		record := config.Namespace + ".events." + pascal(payload.name)
+
+		// the payload is required in its kind's record
+		payloadType := eventType.Field(payload.index).Type.Elem()
+		recordFields := append(append([]field{}, envelope...), payload)
+		schema, err := b.record(record, eventType, recordFields, map[string]bool{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive schema of %s: %w", kind, err)
+		}
+		for _, f := range schema.(map[string]any)["fields"].([]any) {
+			if f := f.(map[string]any); f["name"] == payload.name {
+				f["type"] = f["type"].([]any)[1]
+				delete(f, "default")
+			}
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode schema of %s: %w", kind, err)
+		}
+
+		payloadEncode, err := b.encoder(payloadType, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive encoder of %s: %w", kind, err)
+		}
+		e.kinds[kind] = &kindCodec{
+			schema:  string(data),
+			subject: strings.NewReplacer("{kind}", string(kind), "{record}", record).Replace(config.Subject),
+			payload: payload.index,
+			encode: func(buf []byte, v reflect.Value) []byte {
+				return payloadEncode(envelopeEncode(buf, v), v.Field(payload.index).Elem())
+			},
+		}
+	}
+	return e, nil
+}
+
+// Encode encodes the event with the schema of its kind, registering the schema first if
+// needed
+func (e *Encoder) Encode(event *sink.Event) ([]byte, error) {
+	codec, ok := e.kinds[event.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown event kind %q", event.Kind)
+	}
+	v := reflect.ValueOf(event).Elem()
+	if v.Field(codec.payload).IsNil() {
+		return nil, fmt.Errorf("%s event has no payload", event.Kind)
+	}
+
+	var buf []byte
+	if e.registry != nil {
+		id, err := e.registry.Register(context.Background(), codec.subject, codec.schema)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.BigEndian.AppendUint32([]byte{magicByte}, uint32(id))
+	}
+	return codec.encode(buf, v), nil
+}
+
+// ContentType returns application/vnd.confluent.avro with a registry, avro/binary otherwise
+func (e *Encoder) ContentType() string {
+	if e.registry != nil {
+		return "application/vnd.confluent.avro"
+	}
+	return "avro/binary"
+}
+
+// Schema returns the JSON schema of the event kind, e.g. for consumers without a registry
+func (e *Encoder) Schema(kind sink.Kind) (string, bool) {
+	codec, ok := e.kinds[kind]
+	if !ok {
+		return "", false
+	}
+	return codec.schema, true
+}
+
+// pascal converts a snake case name to PascalCase
+func pascal(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,220 @@
+package avro
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// reader decodes Avro binary values
+type reader struct {
+	*bytes.Reader
+	t *testing.T
+}
+
+func (r reader) long() int64 {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		r.t.Fatalf("failed to read long: %v", err)
+	}
+	return int64(n>>1) ^ -int64(n&1)
+}
+
+func (r reader) string() string {
+	data := make([]byte, r.long())
+	if _, err := io.ReadFull(r, data); err != nil {
+		r.t.Fatalf("failed to read string: %v", err)
+	}
+	return string(data)
+}
+
+func (r reader) double() float64 {
+	var data [8]byte
+	if _, err := io.ReadFull(r, data[:]); err != nil {
+		r.t.Fatalf("failed to read double: %v", err)
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(data[:]))
+}
+
+func testEvents() []*sink.Event {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	return sink.Events(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{7},
+		Slot:      300,
+		BlockTime: &blockTime,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut: tx_parser.TokenInfo{Mint: token, Amount: 18_446_744_073_709_551_615, Decimals: 6},
+		}},
+		Transfers: []*tx_parser.TransferInfo{{
+			Type:        tx_parser.TransferTypeToken,
+			Mint:        token,
+			SourceOwner: wallet,
+			Amount:      5,
+			Decimals:    6,
+			InnerIndex:  -1,
+			AmountUSD:   1.5,
+		}},
+	})
+}
+
+func TestSchemas(t *testing.T) {
+	encoder, err := NewEncoder(nil, Config{})
+	if err != nil {
+		t.Fatalf("failed to create encoder: %v", err)
+	}
+
+	for _, kind := range []sink.Kind{sink.KindSwap, sink.KindTransfer, sink.KindStake, sink.KindPerpFill, sink.KindBridge} {
+		if _, ok := encoder.Schema(kind); !ok {
+			t.Errorf("expected a schema for %s", kind)
+		}
+	}
+
+	data, _ := encoder.Schema(sink.KindSwap)
+	var schema struct {
+		Name   string
+		Fields []struct {
+			Name string
+			Type json.RawMessage
+		}
+	}
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatalf("failed to decode schema: %v", err)
+	}
+	if schema.Name != "solana_toolkit.events.Swap" {
+		t.Errorf("unexpected record name %s", schema.Name)
+	}
+	var names []string
+	for _, f := range schema.Fields {
+		names = append(names, f.Name)
+	}
+	if strings.Join(names, " ") != "kind signature slot block_time index swap" {
+		t.Errorf("unexpected fields %v", names)
+	}
+	swap := string(schema.Fields[5].Type)
+	if !strings.HasPrefix(swap, `{"fields"`) {
+		t.Errorf("expected the payload required, got %s", swap)
+	}
+	// the second token is a reference to the record defined by the first
+	if !strings.Contains(swap, `"name":"token_out","type":"solana_toolkit.TokenInfo"`) {
+		t.Errorf("expected TokenInfo referenced by name, got %s", swap)
+	}
+	if !strings.Contains(swap, `{"name":"amount","type":"string"}`) {
+		t.Errorf("expected amounts as strings, got %s", swap)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	events := testEvents()
+	encoder, err := NewEncoder(nil, Config{})
+	if err != nil {
+		t.Fatalf("failed to create encoder: %v", err)
+	}
+
+	data, err := encoder.Encode(events[1])
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	r := reader{bytes.NewReader(data), t}
+	if kind := r.string(); kind != "transfer" {
+		t.Errorf("unexpected kind %s", kind)
+	}
+	if signature := r.string(); signature != events[1].Signature.String() {
+		t.Errorf("unexpected signature %s", signature)
+	}
+	if slot := r.long(); slot != 300 {
+		t.Errorf("unexpected slot %d", slot)
+	}
+	if branch, blockTime := r.long(), r.long(); branch != 1 || blockTime != 1_700_000_000 {
+		t.Errorf("unexpected block time %d %d", branch, blockTime)
+	}
+	if index := r.long(); index != 0 {
+		t.Errorf("unexpected index %d", index)
+	}
+
+	transfer := events[1].Transfer
+	if r.string() != string(tx_parser.TransferTypeToken) || r.string() != transfer.Program.String() {
+		t.Error("unexpected type or program")
+	}
+	if instruction, inner := r.long(), r.long(); instruction != 0 || inner != -1 {
+		t.Errorf("unexpected indexes %d %d", instruction, inner)
+	}
+	for _, key := range []solana.PublicKey{transfer.Mint, transfer.Source, transfer.Destination, transfer.SourceOwner, transfer.DestinationOwner, transfer.Authority} {
+		if value := r.string(); value != key.String() {
+			t.Errorf("expected %s, got %s", key, value)
+		}
+	}
+	if amount, decimals, usd := r.string(), r.long(), r.double(); amount != "5" || decimals != 6 || usd != 1.5 {
+		t.Errorf("unexpected amount %s %d %f", amount, decimals, usd)
+	}
+	if r.Len() != 0 {
+		t.Errorf("expected the transfer fully read, %d bytes left", r.Len())
+	}
+
+	if _, err := encoder.Encode(&sink.Event{Kind: sink.KindSwap}); err == nil {
+		t.Error("expected an event without payload to fail")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		user, password, _ := r.BasicAuth()
+		if user != "key" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_code":40101,"message":"Unauthorized"}`))
+			return
+		}
+		var body registerRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/subjects/solana-swap-value/versions" || !json.Valid([]byte(body.Schema)) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error_code":42201,"message":"Invalid schema"}`))
+			return
+		}
+		w.Write([]byte(`{"id":258}`))
+	}))
+	defer server.Close()
+
+	events := testEvents()
+	encoder, err := NewEncoder(NewRegistry(RegistryConfig{URL: server.URL + "/", Username: "key", Password: "secret"}), Config{Subject: "solana-{kind}-value"})
+	if err != nil {
+		t.Fatalf("failed to create encoder: %v", err)
+	}
+	for range 2 {
+		data, err := encoder.Encode(events[0])
+		if err != nil {
+			t.Fatalf("failed to encode: %v", err)
+		}
+		if data[0] != magicByte || binary.BigEndian.Uint32(data[1:5]) != 258 {
+			t.Errorf("expected the wire format header, got %x", data[:5])
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected the schema registered once, got %d requests", requests.Load())
+	}
+
+	if _, err := encoder.Encode(events[1]); err == nil || !strings.Contains(err.Error(), "Invalid schema") {
+		t.Errorf("expected the registry error, got %v", err)
+	}
+	unauthorized, _ := NewEncoder(NewRegistry(RegistryConfig{URL: server.URL}), Config{})
+	if _, err := unauthorized.Encode(events[0]); err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("expected the registry error, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package avro
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Registry registers schemas with a Confluent Schema Registry, caching their IDs
+type Registry struct {
+	config RegistryConfig
+	client *http.Client
+
+	mu  sync.Mutex
+	ids map[string]int // by subject and schema
+}
+
+// NewRegistry creates a registry client
+func NewRegistry(config RegistryConfig) *Registry {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	return &Registry{config: config, client: client, ids: make(map[string]int)}
+}
+
+// Register registers the schema under the subject and returns its ID. Registering a schema
+// the subject already has returns the existing ID.
+func (r *Registry) Register(ctx context.Context, subject, schema string) (int, error) {
+	key := subject + "\x00" + schema
+	r.mu.Lock()
+	id, ok := r.ids[key]
+	r.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	body, err := json.Marshal(registerRequest{Schema: schema})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.config.URL+"/subjects/"+url.PathEscape(subject)+"/versions", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	if r.config.Username != "" {
+		req.SetBasicAuth(r.config.Username, r.config.Password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register schema for %s: %w", subject, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read registry response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var replyErr registryError
+		if json.Unmarshal(data, &replyErr) == nil && replyErr.Message != "" {
+			return 0, fmt.Errorf("failed to register schema for %s: %s (%d)", subject, replyErr.Message, replyErr.ErrorCode)
+		}
+		return 0, fmt.Errorf("failed to register schema for %s: status %d", subject, resp.StatusCode)
+	}
+
+	var registered registerResponse
+	if err := json.Unmarshal(data, &registered); err != nil {
+		return 0, fmt.Errorf("failed to decode registry response: %w", err)
+	}
+	r.mu.Lock()
+	r.ids[key] = registered.ID
+	r.mu.Unlock()
+	return registered.ID, nil
+}
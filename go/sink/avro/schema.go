@@ -0,0 +1,287 @@
+package avro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	publicKeyType = reflect.TypeOf(solana.PublicKey{})
+	signatureType = reflect.TypeOf(solana.Signature{})
+	timeType      = reflect.TypeOf(time.Time{})
+	bytesType     = reflect.TypeOf([]byte(nil))
+)
+
+// encodeFunc appends the Avro binary encoding of a value
+type encodeFunc func(buf []byte, v reflect.Value) []byte
+
+// field is a struct field as it appears in JSON, which the schemas mirror
+type field struct {
+	index    int
+	name     string
+	asString bool // integers encoded as decimal strings, ",string" in the JSON tag
+}
+
+// builder derives Avro schemas and encoders from Go types. Records are named after their
+// Go type in the namespace. Public keys and signatures are base58 strings, times are
+// nullable timestamp-millis and pointers are nullable.
+type builder struct {
+	namespace string
+	encoders  map[reflect.Type]*encodeFunc
+}
+
+func newBuilder(namespace string) *builder {
+	return &builder{namespace: namespace, encoders: make(map[reflect.Type]*encodeFunc)}
+}
+
+// schema returns the JSON form of the schema of t. Records already defined in the
+// enclosing schema are referenced by name.
+func (b *builder) schema(t reflect.Type, asString bool, defined map[string]bool) (any, error) {
+	switch t {
+	case publicKeyType, signatureType:
+		return "string", nil
+	case timeType:
+		return []any{"null", map[string]any{"type": "long", "logicalType": "timestamp-millis"}}, nil
+	case bytesType:
+		return "bytes", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := b.schema(t.Elem(), asString, defined)
+		if err != nil {
+			return nil, err
+		}
+		return []any{"null", elem}, nil
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Float32:
+		return "float", nil
+	case reflect.Float64:
+		return "double", nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		if asString {
+			return "string", nil
+		}
+		return "int", nil
+	case reflect.Int, reflect.Int64, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		// unsigned 64-bit values above the long range wrap, tag them ",string" to keep them
+		if asString {
+			return "string", nil
+		}
+		return "long", nil
+	case reflect.Slice:
+		items, err := b.schema(t.Elem(), false, defined)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key %s", t.Key())
+		}
+		values, err := b.schema(t.Elem(), false, defined)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "map", "values": values}, nil
+	case reflect.Struct:
+		return b.record(b.namespace+"."+t.Name(), t, fields(t), defined)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// record returns the schema of a record of the struct fields
+func (b *builder) record(name string, t reflect.Type, fields []field, defined map[string]bool) (any, error) {
+	if defined[name] {
+		return name, nil
+	}
+	defined[name] = true
+
+	schemas := make([]any, 0, len(fields))
+	for _, f := range fields {
+		schema, err := b.schema(t.Field(f.index).Type, f.asString, defined)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), f.name, err)
+		}
+		fieldSchema := map[string]any{"name": f.name, "type": schema}
+		if union, ok := schema.([]any); ok && union[0] == "null" {
+			fieldSchema["default"] = nil
+		}
+		schemas = append(schemas, fieldSchema)
+	}
+	return map[string]any{"type": "record", "name": name, "fields": schemas}, nil
+}
+
+// encoder returns the encoder of values of t
+func (b *builder) encoder(t reflect.Type, asString bool) (encodeFunc, error) {
+	switch t {
+	case publicKeyType:
+		return func(buf []byte, v reflect.Value) []byte {
+			return appendString(buf, v.Interface().(solana.PublicKey).String())
+		}, nil
+	case signatureType:
+		return func(buf []byte, v reflect.Value) []byte {
+			return appendString(buf, v.Interface().(solana.Signature).String())
+		}, nil
+	case timeType:
+		return func(buf []byte, v reflect.Value) []byte {
+			value := v.Interface().(time.Time)
+			if value.IsZero() {
+				return appendLong(buf, 0)
+			}
+			return appendLong(appendLong(buf, 1), value.UnixMilli())
+		}, nil
+	case bytesType:
+		return func(buf []byte, v reflect.Value) []byte {
+			return append(appendLong(buf, int64(v.Len())), v.Bytes()...)
+		}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := b.encoder(t.Elem(), asString)
+		if err != nil {
+			return nil, err
+		}
+		return func(buf []byte, v reflect.Value) []byte {
+			if v.IsNil() {
+				return appendLong(buf, 0)
+			}
+			return elem(appendLong(buf, 1), v.Elem())
+		}, nil
+	case reflect.Bool:
+		return func(buf []byte, v reflect.Value) []byte {
+			if v.Bool() {
+				return append(buf, 1)
+			}
+			return append(buf, 0)
+		}, nil
+	case reflect.String:
+		return func(buf []byte, v reflect.Value) []byte {
+			return appendString(buf, v.String())
+		}, nil
+	case reflect.Float32:
+		return func(buf []byte, v reflect.Value) []byte {
+			return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(v.Float())))
+		}, nil
+	case reflect.Float64:
+		return func(buf []byte, v reflect.Value) []byte {
+			return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v.Float()))
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if asString {
+			return func(buf []byte, v reflect.Value) []byte {
+				return appendString(buf, strconv.FormatInt(v.Int(), 10))
+			}, nil
+		}
+		return func(buf []byte, v reflect.Value) []byte {
+			return appendLong(buf, v.Int())
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if asString {
+			return func(buf []byte, v reflect.Value) []byte {
+				return appendString(buf, strconv.FormatUint(v.Uint(), 10))
+			}, nil
+		}
+		return func(buf []byte, v reflect.Value) []byte {
+			return appendLong(buf, int64(v.Uint()))
+		}, nil
+	case reflect.Slice, reflect.Map:
+		elem, err := b.encoder(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind() == reflect.Slice {
+			return func(buf []byte, v reflect.Value) []byte {
+				// one block holding every item, then the empty block ending the array
+				if v.Len() > 0 {
+					buf = appendLong(buf, int64(v.Len()))
+					for i := range v.Len() {
+						buf = elem(buf, v.Index(i))
+					}
+				}
+				return appendLong(buf, 0)
+			}, nil
+		}
+		return func(buf []byte, v reflect.Value) []byte {
+			if v.Len() > 0 {
+				buf = appendLong(buf, int64(v.Len()))
+				iter := v.MapRange()
+				for iter.Next() {
+					buf = elem(appendString(buf, iter.Key().String()), iter.Value())
+				}
+			}
+			return appendLong(buf, 0)
+		}, nil
+	case reflect.Struct:
+		return b.recordEncoder(t, fields(t))
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// recordEncoder returns the encoder of the struct fields. Encoders of struct types are
+// cached, which also ends the recursion of self-referencing types.
+func (b *builder) recordEncoder(t reflect.Type, fields []field) (encodeFunc, error) {
+	if cached, ok := b.encoders[t]; ok {
+		return func(buf []byte, v reflect.Value) []byte { return (*cached)(buf, v) }, nil
+	}
+	encode := new(encodeFunc)
+	b.encoders[t] = encode
+
+	encoders := make([]encodeFunc, len(fields))
+	for i, f := range fields {
+		var err error
+		if encoders[i], err = b.encoder(t.Field(f.index).Type, f.asString); err != nil {
+			delete(b.encoders, t)
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), f.name, err)
+		}
+	}
+	*encode = func(buf []byte, v reflect.Value) []byte {
+		for i, f := range fields {
+			buf = encoders[i](buf, v.Field(f.index))
+		}
+		return buf
+	}
+	return *encode, nil
+}
+
+// fields returns the exported fields of the struct that are encoded in JSON
+func fields(t reflect.Type) []field {
+	var result []field
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		result = append(result, field{index: i, name: name, asString: strings.Contains(","+options+",", ",string,")})
+	}
+	return result
+}
+
+// appendLong appends a zig-zag varint
+func appendLong(buf []byte, n int64) []byte {
+	return binary.AppendUvarint(buf, uint64((n<<1)^(n>>63)))
+}
+
+// appendString appends a length prefixed string
+func appendString(buf []byte, s string) []byte {
+	return append(appendLong(buf, int64(len(s))), s...)
+}
@@ -0,0 +1,51 @@
+package avro
+
+import (
+	"net/http"
+	"time"
+)
+
+// Config controls the Avro encoder
+type Config struct {
+	// Namespace of the generated records, defaults to "solana_toolkit"
+	Namespace string
+
+	// Subject is the registry subject of each kind's schema. "{kind}" is replaced by the
+	// event kind and "{record}" by the full name of the kind's record. Defaults to
+	// "{record}", the record name strategy, use e.g. "solana-{kind}-value" for the topic
+	// name strategy with a topic per kind.
+	Subject string
+}
+
+// RegistryConfig controls the Schema Registry client
+type RegistryConfig struct {
+	// URL of the registry, e.g. http://localhost:8081
+	URL string
+
+	// Username and Password authenticate with basic auth when set, e.g. a Confluent Cloud
+	// API key and secret
+	Username string
+	Password string
+
+	// Timeout bounds each request, defaults to 10s
+	Timeout time.Duration
+
+	// HTTPClient defaults to a client with the timeout
+	HTTPClient *http.Client
+}
+
+// registerRequest is the body of a schema registration
+type registerRequest struct {
+	Schema string `json:"schema"`
+}
+
+// registerResponse is the reply to a schema registration
+type registerResponse struct {
+	ID int `json:"id"`
+}
+
+// registryError is an error reply of the registry
+type registryError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
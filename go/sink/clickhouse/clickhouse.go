@@ -0,0 +1,257 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	ch "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// createTableSQL is the built-in swaps schema. ReplacingMergeTree collapses rows written
+// twice, e.g. after a retried flush.
+const createTableSQL = `CREATE TABLE IF NOT EXISTS %s (
+	signature         String,
+	idx               UInt32,
+	slot              UInt64,
+	block_time        DateTime,
+	protocol          LowCardinality(String),
+	wallet            String,
+	mint_in           String,
+	amount_in         UInt64,
+	decimals_in       UInt8,
+	mint_out          String,
+	amount_out        UInt64,
+	decimals_out      UInt8,
+	instruction_index UInt32
+) ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(block_time)
+ORDER BY (mint_out, slot, signature, idx)`
+
+// Conn is the subset of the ClickHouse connection the sink uses, implemented by driver.Conn
+type Conn interface {
+	PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error)
+	Exec(ctx context.Context, query string, args ...any) error
+	Close() error
+}
+
+// Sink buffers swap rows and bulk inserts them into ClickHouse. Other event kinds are ignored.
+type Sink struct {
+	conn   Conn
+	config Config
+	ownsDB bool
+
+	mu      sync.Mutex
+	pending [][]any
+	flushMu sync.Mutex
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// New connects to ClickHouse over the native protocol and starts the flush loop
+func New(ctx context.Context, config Config) (*Sink, error) {
+	if len(config.Addr) == 0 {
+		return nil, fmt.Errorf("at least one address is required")
+	}
+	config = withDefaults(config)
+
+	settings := ch.Settings{}
+	if config.AsyncInsert {
+		settings["async_insert"] = 1
+		settings["wait_for_async_insert"] = 0
+		if config.WaitForAsyncInsert {
+			settings["wait_for_async_insert"] = 1
+		}
+	}
+
+	conn, err := ch.Open(&ch.Options{
+		Addr: config.Addr,
+		Auth: ch.Auth{
+			Database: config.Database,
+			Username: config.Username,
+			Password: config.Password,
+		},
+		Settings:    settings,
+		Compression: &ch.Compression{Method: ch.CompressionLZ4},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open clickhouse: %w", err)
+	}
+	if err := conn.Ping(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to clickhouse: %w", err)
+	}
+
+	if config.CreateTable {
+		if err := conn.Exec(ctx, fmt.Sprintf(createTableSQL, config.Table)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create table %s: %w", config.Table, err)
+		}
+	}
+
+	s := NewWithConn(conn, config)
+	s.ownsDB = true
+	return s, nil
+}
+
+// NewWithConn creates a sink over an existing connection and starts the flush loop
+func NewWithConn(conn Conn, config Config) *Sink {
+	config = withDefaults(config)
+
+	s := &Sink{
+		conn:   conn,
+		config: config,
+		stop:   make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop()
+	return s
+}
+
+// withDefaults fills in unset options
+func withDefaults(config Config) Config {
+	if config.Table == "" {
+		config.Table = "swaps"
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 10_000
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.MaxBuffered <= 0 {
+		config.MaxBuffered = 10 * config.BatchSize
+	}
+	return config
+}
+
+// Write buffers the swap events and sends a batch once BatchSize rows are buffered
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	s.mu.Lock()
+	for _, event := range events {
+		if event.Swap == nil {
+			continue
+		}
+		if len(s.pending) >= s.config.MaxBuffered {
+			s.mu.Unlock()
+			return fmt.Errorf("buffer full with %d rows", len(s.pending))
+		}
+		s.pending = append(s.pending, swapRow(event))
+	}
+	full := len(s.pending) >= s.config.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush sends every buffered row. On failure the rows stay buffered for the next flush.
+func (s *Sink) Flush(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	rows := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := s.send(ctx, rows); err != nil {
+		s.mu.Lock()
+		s.pending = append(rows, s.pending...)
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Close flushes buffered rows, stops the flush loop and closes the connection if the sink opened it
+func (s *Sink) Close() error {
+	close(s.stop)
+	s.wg.Wait()
+
+	err := s.Flush(context.Background())
+	if s.ownsDB {
+		if closeErr := s.conn.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// send inserts the rows as a single native protocol block
+func (s *Sink) send(ctx context.Context, rows [][]any) error {
+	batch, err := s.conn.PrepareBatch(ctx, "INSERT INTO "+s.config.Table)
+	if err != nil {
+		return fmt.Errorf("failed to prepare batch: %w", err)
+	}
+	for _, row := range rows {
+		if err := batch.Append(row...); err != nil {
+			batch.Abort()
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to send %d rows: %w", len(rows), err)
+	}
+	return nil
+}
+
+// flushLoop flushes buffered rows every FlushInterval until the sink is closed
+func (s *Sink) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.Flush(context.Background()); err != nil && s.config.OnError != nil {
+				s.config.OnError(err)
+			}
+		}
+	}
+}
+
+// swapRow converts a swap event into the column values of the swaps table
+func swapRow(event *sink.Event) []any {
+	swap := event.Swap
+
+	var blockTime time.Time
+	if event.BlockTime != nil {
+		blockTime = event.BlockTime.Time().UTC()
+	} else {
+		blockTime = time.Unix(0, 0).UTC()
+	}
+
+	return []any{
+		event.Signature.String(),
+		uint32(event.Index),
+		event.Slot,
+		blockTime,
+		string(swap.Protocol),
+		event.Wallet().String(),
+		swap.TokenIn.Mint.String(),
+		swap.TokenIn.Amount,
+		swap.TokenIn.Decimals,
+		swap.TokenOut.Mint.String(),
+		swap.TokenOut.Amount,
+		swap.TokenOut.Decimals,
+		uint32(swap.InstructionIndex),
+	}
+}
@@ -0,0 +1,202 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+type fakeBatch struct {
+	driver.Batch
+	conn *fakeConn
+	rows [][]any
+}
+
+func (b *fakeBatch) Append(v ...any) error {
+	b.rows = append(b.rows, v)
+	return nil
+}
+
+func (b *fakeBatch) Abort() error { return nil }
+
+func (b *fakeBatch) Send() error {
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+	if b.conn.fail {
+		return errors.New("connection refused")
+	}
+	b.conn.batches = append(b.conn.batches, b.rows)
+	return nil
+}
+
+type fakeConn struct {
+	mu      sync.Mutex
+	fail    bool
+	queries []string
+	batches [][][]any
+}
+
+func (c *fakeConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	c.mu.Lock()
+	c.queries = append(c.queries, query)
+	c.mu.Unlock()
+	return &fakeBatch{conn: c}, nil
+}
+
+func (c *fakeConn) Exec(ctx context.Context, query string, args ...any) error { return nil }
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) sent() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, batch := range c.batches {
+		n += len(batch)
+	}
+	return n
+}
+
+func swapEvents(n int) []*sink.Event {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	tx := &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{7},
+		Slot:      42,
+		BlockTime: &blockTime,
+		Transfers: []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeToken, Mint: token, Amount: 1}},
+	}
+	for range n {
+		tx.Swaps = append(tx.Swaps, &tx_parser.SwapInfo{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut: tx_parser.TokenInfo{Mint: token, Amount: 5_000_000, Decimals: 6},
+		})
+	}
+	return sink.Events(tx)
+}
+
+func TestSwapRow(t *testing.T) {
+	row := swapRow(swapEvents(1)[0])
+	if len(row) != 13 {
+		t.Fatalf("expected 13 columns, got %d", len(row))
+	}
+	if row[2] != uint64(42) {
+		t.Errorf("expected slot 42, got %v", row[2])
+	}
+	if row[3].(time.Time).Unix() != 1_700_000_000 {
+		t.Errorf("unexpected block time %v", row[3])
+	}
+	if row[7] != uint64(1_000_000_000) {
+		t.Errorf("unexpected amount in %v", row[7])
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+	conn := &fakeConn{}
+	s := NewWithConn(conn, Config{BatchSize: 3, FlushInterval: time.Hour})
+	ctx := context.Background()
+
+	if err := s.Write(ctx, swapEvents(2)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if conn.sent() != 0 {
+		t.Fatalf("expected rows to be buffered, got %d sent", conn.sent())
+	}
+	if err := s.Write(ctx, swapEvents(2)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if conn.sent() != 4 {
+		t.Fatalf("expected 4 rows sent once the batch was full, got %d", conn.sent())
+	}
+	if conn.queries[0] != "INSERT INTO swaps" {
+		t.Errorf("unexpected insert query %q", conn.queries[0])
+	}
+
+	if err := s.Write(ctx, swapEvents(1)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+	if conn.sent() != 5 {
+		t.Errorf("expected close to flush the remaining row, got %d sent", conn.sent())
+	}
+}
+
+func TestFlushInterval(t *testing.T) {
+	conn := &fakeConn{}
+	s := NewWithConn(conn, Config{FlushInterval: 10 * time.Millisecond})
+	defer s.Close()
+
+	if err := s.Write(context.Background(), swapEvents(1)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for conn.sent() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if conn.sent() != 1 {
+		t.Errorf("expected the flush loop to send the row, got %d sent", conn.sent())
+	}
+}
+
+func TestFlushRetry(t *testing.T) {
+	conn := &fakeConn{fail: true}
+	s := NewWithConn(conn, Config{BatchSize: 2, MaxBuffered: 3, FlushInterval: time.Hour})
+	ctx := context.Background()
+
+	if err := s.Write(ctx, swapEvents(2)); err == nil {
+		t.Fatal("expected the failed flush to be reported")
+	}
+	if err := s.Write(ctx, swapEvents(2)); err == nil {
+		t.Fatal("expected a full buffer error")
+	}
+
+	conn.mu.Lock()
+	conn.fail = false
+	conn.mu.Unlock()
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+	if conn.sent() != 3 {
+		t.Errorf("expected the buffered rows to be retried, got %d sent", conn.sent())
+	}
+}
+
+func TestFlushLoopReportsErrors(t *testing.T) {
+	conn := &fakeConn{fail: true}
+	reported := make(chan error, 1)
+	s := NewWithConn(conn, Config{FlushInterval: 10 * time.Millisecond, OnError: func(err error) {
+		select {
+		case reported <- err:
+		default:
+		}
+	}})
+	defer s.Close()
+
+	if err := s.Write(context.Background(), swapEvents(1)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	select {
+	case err := <-reported:
+		if err == nil {
+			t.Error("expected the flush error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the failed background flush to be reported")
+	}
+
+	conn.mu.Lock()
+	conn.fail = false
+	conn.mu.Unlock()
+}
@@ -0,0 +1,36 @@
+package clickhouse
+
+import "time"
+
+// Config controls the ClickHouse sink
+type Config struct {
+	// Addr lists host:port native protocol endpoints, e.g. localhost:9000
+	Addr     []string
+	Database string
+	Username string
+	Password string
+
+	// Table receives swap rows, defaults to swaps
+	Table string
+
+	// CreateTable creates the table with the built-in schema if it does not exist
+	CreateTable bool
+
+	// BatchSize is the number of rows buffered before they are sent, defaults to 10000
+	BatchSize int
+
+	// FlushInterval sends buffered rows at least this often, defaults to 1s
+	FlushInterval time.Duration
+
+	// AsyncInsert enables server-side asynchronous inserts, letting ClickHouse merge small
+	// batches from many writers. WaitForAsyncInsert makes each flush wait until the data
+	// is written.
+	AsyncInsert        bool
+	WaitForAsyncInsert bool
+
+	// MaxBuffered bounds the rows kept while ClickHouse is unavailable, defaults to 10 batches
+	MaxBuffered int
+
+	// OnError is called when a background flush fails, the rows are kept for the next flush
+	OnError func(err error)
+}
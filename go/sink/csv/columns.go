@@ -0,0 +1,201 @@
+package csv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/pnl"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// column renders one field of a row
+type column[T any] struct {
+	name   string
+	render func(f Format, row T) string
+}
+
+var swapColumns = append(envelopeColumns(), []column[*sink.Event]{
+	{"protocol", func(_ Format, e *sink.Event) string { return string(e.Swap.Protocol) }},
+	{"wallet", func(_ Format, e *sink.Event) string { return e.Wallet().String() }},
+	{"mint_in", func(_ Format, e *sink.Event) string { return e.Swap.TokenIn.Mint.String() }},
+	{"amount_in", func(f Format, e *sink.Event) string {
+		return f.amount(e.Swap.TokenIn.Amount, e.Swap.TokenIn.Decimals)
+	}},
+	{"raw_amount_in", func(_ Format, e *sink.Event) string { return strconv.FormatUint(e.Swap.TokenIn.Amount, 10) }},
+	{"decimals_in", func(_ Format, e *sink.Event) string { return strconv.Itoa(int(e.Swap.TokenIn.Decimals)) }},
+	{"mint_out", func(_ Format, e *sink.Event) string { return e.Swap.TokenOut.Mint.String() }},
+	{"amount_out", func(f Format, e *sink.Event) string {
+		return f.amount(e.Swap.TokenOut.Amount, e.Swap.TokenOut.Decimals)
+	}},
+	{"raw_amount_out", func(_ Format, e *sink.Event) string { return strconv.FormatUint(e.Swap.TokenOut.Amount, 10) }},
+	{"decimals_out", func(_ Format, e *sink.Event) string { return strconv.Itoa(int(e.Swap.TokenOut.Decimals)) }},
+	{"amount_usd", func(f Format, e *sink.Event) string { return f.float(e.Swap.AmountUSD) }},
+	{"price", func(f Format, e *sink.Event) string {
+		if e.Swap.Price == nil {
+			return ""
+		}
+		return f.float(e.Swap.Price.Price)
+	}},
+	{"price_usd", func(f Format, e *sink.Event) string {
+		if e.Swap.Price == nil {
+			return ""
+		}
+		return f.float(e.Swap.Price.PriceUSD)
+	}},
+	{"compute_units", func(_ Format, e *sink.Event) string { return strconv.FormatUint(e.Swap.ComputeUnits, 10) }},
+	{"instruction_index", func(_ Format, e *sink.Event) string { return strconv.Itoa(e.Swap.InstructionIndex) }},
+}...)
+
+var transferColumns = append(envelopeColumns(), []column[*sink.Event]{
+	{"type", func(_ Format, e *sink.Event) string { return string(e.Transfer.Type) }},
+	{"program", func(_ Format, e *sink.Event) string { return e.Transfer.Program.String() }},
+	{"mint", func(_ Format, e *sink.Event) string { return e.Transfer.Mint.String() }},
+	{"source", func(_ Format, e *sink.Event) string { return e.Transfer.Source.String() }},
+	{"destination", func(_ Format, e *sink.Event) string { return e.Transfer.Destination.String() }},
+	{"source_owner", func(_ Format, e *sink.Event) string { return e.Transfer.SourceOwner.String() }},
+	{"destination_owner", func(_ Format, e *sink.Event) string { return e.Transfer.DestinationOwner.String() }},
+	{"authority", func(_ Format, e *sink.Event) string { return e.Transfer.Authority.String() }},
+	{"amount", func(f Format, e *sink.Event) string { return f.amount(e.Transfer.Amount, e.Transfer.Decimals) }},
+	{"raw_amount", func(_ Format, e *sink.Event) string { return strconv.FormatUint(e.Transfer.Amount, 10) }},
+	{"decimals", func(_ Format, e *sink.Event) string { return strconv.Itoa(int(e.Transfer.Decimals)) }},
+	{"amount_usd", func(f Format, e *sink.Event) string { return f.float(e.Transfer.AmountUSD) }},
+	{"instruction_index", func(_ Format, e *sink.Event) string { return strconv.Itoa(e.Transfer.InstructionIndex) }},
+	{"inner_index", func(_ Format, e *sink.Event) string { return strconv.Itoa(e.Transfer.InnerIndex) }},
+}...)
+
+var pnlColumns = []column[*pnl.Position]{
+	{"mint", func(_ Format, p *pnl.Position) string { return p.Mint.String() }},
+	{"amount", func(f Format, p *pnl.Position) string { return f.amount(p.Amount, p.Decimals) }},
+	{"raw_amount", func(_ Format, p *pnl.Position) string { return strconv.FormatUint(p.Amount, 10) }},
+	{"decimals", func(_ Format, p *pnl.Position) string { return strconv.Itoa(int(p.Decimals)) }},
+	{"cost_basis_usd", func(f Format, p *pnl.Position) string { return f.float(p.CostBasisUSD) }},
+	{"cost_basis_sol", func(f Format, p *pnl.Position) string { return f.float(p.CostBasisSOL) }},
+	{"realized_usd", func(f Format, p *pnl.Position) string { return f.float(p.RealizedUSD) }},
+	{"realized_sol", func(f Format, p *pnl.Position) string { return f.float(p.RealizedSOL) }},
+	{"unrealized_usd", func(f Format, p *pnl.Position) string { return f.float(p.UnrealizedUSD) }},
+	{"unrealized_sol", func(f Format, p *pnl.Position) string { return f.float(p.UnrealizedSOL) }},
+	{"buys", func(_ Format, p *pnl.Position) string { return strconv.Itoa(p.Buys) }},
+	{"sells", func(_ Format, p *pnl.Position) string { return strconv.Itoa(p.Sells) }},
+}
+
+// envelopeColumns are the leading columns of event tables
+func envelopeColumns() []column[*sink.Event] {
+	return []column[*sink.Event]{
+		{"signature", func(_ Format, e *sink.Event) string { return e.Signature.String() }},
+		{"index", func(_ Format, e *sink.Event) string { return strconv.Itoa(e.Index) }},
+		{"slot", func(_ Format, e *sink.Event) string { return strconv.FormatUint(e.Slot, 10) }},
+		{"block_time", func(_ Format, e *sink.Event) string { return formatTime(e.BlockTime, time.RFC3339) }},
+		{"date", func(_ Format, e *sink.Event) string { return formatTime(e.BlockTime, time.DateOnly) }},
+	}
+}
+
+// Columns returns the names of the table's columns in their default order
+func Columns(table Table) []string {
+	switch table {
+	case TableSwaps:
+		return names(swapColumns)
+	case TableTransfers:
+		return names(transferColumns)
+	case TablePnL:
+		return names(pnlColumns)
+	}
+	return nil
+}
+
+// Locale returns the format of a locale: en for a decimal point, de, fr, es, it, nl, pt or
+// ru for a decimal comma with semicolon delimited fields
+func Locale(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "", "en":
+		return Format{Decimal: '.', Delimiter: ','}, nil
+	case "de", "fr", "es", "it", "nl", "pt", "ru":
+		return Format{Decimal: ',', Delimiter: ';'}, nil
+	}
+	return Format{}, fmt.Errorf("unknown locale %q", name)
+}
+
+// selectColumns returns the named columns in order, every column if names is empty
+func selectColumns[T any](all []column[T], names []string) ([]column[T], error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	selected := make([]column[T], 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, c := range all {
+			if c.name == name {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// names returns the names of the columns
+func names[T any](columns []column[T]) []string {
+	result := make([]string, len(columns))
+	for i, c := range columns {
+		result[i] = c.name
+	}
+	return result
+}
+
+// formatTime formats an optional block time in UTC, empty if unknown
+func formatTime(t *solana.UnixTimeSeconds, layout string) string {
+	if t == nil {
+		return ""
+	}
+	return t.Time().UTC().Format(layout)
+}
+
+// amount formats a raw token amount in whole tokens, exactly
+func (f Format) amount(raw uint64, decimals uint8) string {
+	digits := strconv.FormatUint(raw, 10)
+	if decimals == 0 {
+		return f.localize(digits)
+	}
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	split := len(digits) - int(decimals)
+	return f.localize(digits[:split] + "." + digits[split:])
+}
+
+// float formats a float with the fewest digits that represent it
+func (f Format) float(value float64) string {
+	return f.localize(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// localize rewrites a number with a decimal point in the format's separators, dropping
+// trailing zeros of the fraction
+func (f Format) localize(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	integer, fraction, _ := strings.Cut(number, ".")
+	fraction = strings.TrimRight(fraction, "0")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if f.Thousands != 0 && i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteRune(f.Thousands)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteRune(f.Decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
@@ -0,0 +1,216 @@
+package csv
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/pnl"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Exporter writes swaps, transfers and PnL positions to CSV files with a header row, for
+// spreadsheets and tax tools. Files are written under a temporary name and renamed once
+// complete, a new file is started every MaxRows rows. Other event kinds are ignored.
+type Exporter struct {
+	config Config
+	run    string
+
+	swaps     []column[*sink.Event]
+	transfers []column[*sink.Event]
+	positions []column[*pnl.Position]
+
+	mu    sync.Mutex
+	files map[Table]*chunkFile
+	seq   map[Table]int
+}
+
+var _ sink.Sink = (*Exporter)(nil)
+
+// chunkFile is a CSV file being written
+type chunkFile struct {
+	path   string
+	out    *os.File
+	writer *csv.Writer
+	rows   int
+}
+
+// New creates an exporter writing to config.Dir
+func New(config Config) (*Exporter, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("directory is required")
+	}
+	if config.Format.Decimal == 0 {
+		config.Format.Decimal = '.'
+	}
+	if config.Format.Delimiter == 0 {
+		config.Format.Delimiter = ','
+	}
+	if config.Format.Decimal == config.Format.Delimiter || config.Format.Thousands == config.Format.Delimiter {
+		return nil, fmt.Errorf("the delimiter %q is also a number separator", config.Format.Delimiter)
+	}
+	if config.MaxRows <= 0 {
+		config.MaxRows = 1_000_000
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", config.Dir, err)
+	}
+
+	e := &Exporter{
+		config: config,
+		run:    strconv.FormatInt(time.Now().UnixNano(), 10),
+		files:  make(map[Table]*chunkFile),
+		seq:    make(map[Table]int),
+	}
+	var err error
+	if e.swaps, err = selectColumns(swapColumns, config.Columns[TableSwaps]); err != nil {
+		return nil, fmt.Errorf("invalid swap columns: %w", err)
+	}
+	if e.transfers, err = selectColumns(transferColumns, config.Columns[TableTransfers]); err != nil {
+		return nil, fmt.Errorf("invalid transfer columns: %w", err)
+	}
+	if e.positions, err = selectColumns(pnlColumns, config.Columns[TablePnL]); err != nil {
+		return nil, fmt.Errorf("invalid pnl columns: %w", err)
+	}
+	for table, names := range config.Columns {
+		if Columns(table) == nil && len(names) > 0 {
+			return nil, fmt.Errorf("unknown table %q", table)
+		}
+	}
+	return e, nil
+}
+
+// Write appends the swaps and transfers to their files
+func (e *Exporter) Write(ctx context.Context, events []*sink.Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, event := range events {
+		var err error
+		switch {
+		case event.Swap != nil:
+			err = e.write(TableSwaps, render(e.config.Format, e.swaps, event))
+		case event.Transfer != nil:
+			err = e.write(TableTransfers, render(e.config.Format, e.transfers, event))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePositions appends PnL positions, e.g. those of a pnl.Summary, to the pnl files
+func (e *Exporter) WritePositions(positions []*pnl.Position) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, position := range positions {
+		if err := e.write(TablePnL, render(e.config.Format, e.positions, position)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush finishes every open file so that all rows written so far are in place
+func (e *Exporter) Flush() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var errs []error
+	for table, f := range e.files {
+		errs = append(errs, f.close())
+		delete(e.files, table)
+	}
+	return errors.Join(errs...)
+}
+
+// Close finishes every open file
+func (e *Exporter) Close() error {
+	return e.Flush()
+}
+
+// write appends a row to the table's file, starting a new file when it is full
+func (e *Exporter) write(table Table, row []string) error {
+	f := e.files[table]
+	if f != nil && f.rows >= e.config.MaxRows {
+		delete(e.files, table)
+		if err := f.close(); err != nil {
+			return err
+		}
+		f = nil
+	}
+	if f == nil {
+		var err error
+		if f, err = e.create(table); err != nil {
+			return err
+		}
+		e.files[table] = f
+	}
+
+	if err := f.writer.Write(row); err != nil {
+		return fmt.Errorf("failed to write to %s: %w", f.path, err)
+	}
+	f.rows++
+	return nil
+}
+
+// create starts the next file of the table with its header row
+func (e *Exporter) create(table Table) (*chunkFile, error) {
+	e.seq[table]++
+	path := filepath.Join(e.config.Dir, fmt.Sprintf("%s-%s-%05d.csv", table, e.run, e.seq[table]))
+	out, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	writer := csv.NewWriter(out)
+	writer.Comma = e.config.Format.Delimiter
+	var header []string
+	switch table {
+	case TableSwaps:
+		header = names(e.swaps)
+	case TableTransfers:
+		header = names(e.transfers)
+	case TablePnL:
+		header = names(e.positions)
+	}
+	if err := writer.Write(header); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return nil, fmt.Errorf("failed to write header of %s: %w", path, err)
+	}
+	return &chunkFile{path: path, out: out, writer: writer}, nil
+}
+
+// close flushes the file and moves it into place
+func (f *chunkFile) close() error {
+	f.writer.Flush()
+	flushErr := f.writer.Error()
+	if err := f.out.Close(); err != nil {
+		return fmt.Errorf("failed to finish %s: %w", f.path, err)
+	}
+	if flushErr != nil {
+		return fmt.Errorf("failed to write %s: %w", f.path, flushErr)
+	}
+	if err := os.Rename(f.path+".tmp", f.path); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", f.path, err)
+	}
+	return nil
+}
+
+// render renders a row of the columns
+func render[T any](format Format, columns []column[T], row T) []string {
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = c.render(format, row)
+	}
+	return values
+}
@@ -0,0 +1,162 @@
+package csv
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pnl"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func testEvents() []*sink.Event {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	return sink.Events(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{4},
+		Slot:      300,
+		BlockTime: &blockTime,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol:  tx_parser.SwapTypeRaydium,
+			Signers:   []solana.PublicKey{wallet},
+			TokenIn:   tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_500_000_000, Decimals: 9},
+			TokenOut:  tx_parser.TokenInfo{Mint: token, Amount: 1_234_567_000_000, Decimals: 6},
+			AmountUSD: 225.5,
+		}},
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeToken, Mint: token, SourceOwner: wallet, Amount: 5, Decimals: 6, InnerIndex: -1},
+		},
+		StakeEvents: []*tx_parser.StakeEvent{{Type: tx_parser.StakeEventDelegate}},
+	})
+}
+
+// readDir returns the rows of every finished file by name
+func readDir(t *testing.T, dir string, comma rune) map[string][][]string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", dir, err)
+	}
+	files := make(map[string][][]string)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", entry.Name(), err)
+		}
+		reader := csv.NewReader(strings.NewReader(string(data)))
+		reader.Comma = comma
+		if files[entry.Name()], err = reader.ReadAll(); err != nil {
+			t.Fatalf("failed to parse %s: %v", entry.Name(), err)
+		}
+	}
+	return files
+}
+
+func TestFormat(t *testing.T) {
+	en, _ := Locale("en")
+	de, _ := Locale("DE")
+	grouped := Format{Decimal: ',', Thousands: '.', Delimiter: ';'}
+
+	cases := []struct {
+		format   Format
+		raw      uint64
+		decimals uint8
+		expected string
+	}{
+		{en, 1_500_000_000, 9, "1.5"},
+		{en, 5, 6, "0.000005"},
+		{en, 1_000_000, 6, "1"},
+		{en, 42, 0, "42"},
+		{de, 1_500_000_000, 9, "1,5"},
+		{grouped, 1_234_567_000_000, 6, "1.234.567"},
+		{grouped, 123_456_789, 3, "123.456,789"},
+	}
+	for _, c := range cases {
+		if value := c.format.amount(c.raw, c.decimals); value != c.expected {
+			t.Errorf("expected %d with %d decimals as %s, got %s", c.raw, c.decimals, c.expected, value)
+		}
+	}
+	if value := grouped.float(-1234.5); value != "-1.234,5" {
+		t.Errorf("expected a grouped negative float, got %s", value)
+	}
+	if _, err := Locale("xx"); err == nil {
+		t.Error("expected an unknown locale to fail")
+	}
+}
+
+func TestExporter(t *testing.T) {
+	dir := t.TempDir()
+	de, _ := Locale("de")
+	exporter, err := New(Config{
+		Dir:     dir,
+		Format:  de,
+		Columns: map[Table][]string{TableSwaps: {"date", "protocol", "amount_in", "amount_usd"}},
+		MaxRows: 2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+
+	events := testEvents()
+	for range 3 {
+		if err := exporter.Write(context.Background(), events); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+	if err := exporter.WritePositions([]*pnl.Position{{Mint: events[0].Swap.TokenOut.Mint, Amount: 2_500_000, Decimals: 6, RealizedUSD: 10.25}}); err != nil {
+		t.Fatalf("failed to write positions: %v", err)
+	}
+	// the full files are in place, the others still being written
+	if files := readDir(t, dir, ';'); len(files) != 2 {
+		t.Errorf("expected the first swap and transfer files finished, got %d files", len(files))
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	files := readDir(t, dir, ';')
+	var swapRows, transferRows int
+	var names []string
+	for name, rows := range files {
+		names = append(names, name)
+		switch {
+		case strings.HasPrefix(name, "swaps-"):
+			swapRows += len(rows) - 1
+			if strings.Join(rows[0], " ") != "date protocol amount_in amount_usd" {
+				t.Errorf("unexpected swap header %v", rows[0])
+			}
+			if strings.Join(rows[1], " ") != "2023-11-14 Raydium 1,5 225,5" {
+				t.Errorf("unexpected swap row %v", rows[1])
+			}
+		case strings.HasPrefix(name, "transfers-"):
+			transferRows += len(rows) - 1
+			if !slices.Equal(rows[0], Columns(TableTransfers)) {
+				t.Errorf("expected every transfer column, got %v", rows[0])
+			}
+		case strings.HasPrefix(name, "pnl-"):
+			if rows[1][1] != "2,5" || rows[1][6] != "10,25" {
+				t.Errorf("unexpected position row %v", rows[1])
+			}
+		}
+	}
+	// three rows of each table rotate into two files
+	if len(files) != 5 || swapRows != 3 || transferRows != 3 {
+		t.Errorf("expected 2 swap, 2 transfer and 1 pnl files, got %v", names)
+	}
+
+	if _, err := New(Config{Dir: dir, Columns: map[Table][]string{TableSwaps: {"nope"}}}); err == nil {
+		t.Error("expected an unknown column to fail")
+	}
+	if _, err := New(Config{Dir: dir, Format: Format{Decimal: ','}}); err == nil {
+		t.Error("expected a decimal comma with comma delimited fields to fail")
+	}
+}
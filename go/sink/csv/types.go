@@ -0,0 +1,40 @@
+package csv
+
+// Table is a kind of CSV file, each with its own column set
+type Table string
+
+const (
+	TableSwaps     Table = "swaps"
+	TableTransfers Table = "transfers"
+	TablePnL       Table = "pnl" // positions of a pnl.Summary
+)
+
+// Format controls how numbers and fields are written, spreadsheets expect the conventions
+// of their locale
+type Format struct {
+	// Decimal separates the fraction of numbers, defaults to '.'
+	Decimal rune
+
+	// Thousands groups the integer digits of numbers, zero leaves them ungrouped
+	Thousands rune
+
+	// Delimiter separates fields, defaults to ','. Locales with a decimal comma use ';'.
+	Delimiter rune
+}
+
+// Config controls the CSV exporter
+type Config struct {
+	// Dir receives the files, named <table>-<run>-<chunk>.csv
+	Dir string
+
+	// Columns selects the columns of each table and their order, tables without an entry
+	// get every column. See Columns for the names.
+	Columns map[Table][]string
+
+	// Format defaults to the en locale
+	Format Format
+
+	// MaxRows starts a new file once a file has this many rows, defaults to 1,000,000 which
+	// common spreadsheets can open
+	MaxRows int
+}
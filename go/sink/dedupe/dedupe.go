@@ -0,0 +1,89 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Store remembers the keys of events that were written
+type Store interface {
+	// Seen reports for each key whether it was marked
+	Seen(ctx context.Context, keys []string) ([]bool, error)
+
+	// Mark records the keys as written
+	Mark(ctx context.Context, keys []string) error
+
+	// Close releases the store's resources
+	Close() error
+}
+
+// Sink drops events another sink already received, so blocks processed again or replayed
+// after a reconnect do not produce duplicate rows. Keys are marked once the wrapped sink
+// accepted the batch, so a failed write is retried in full; a crash between the two
+// writes the batch again on replay.
+type Sink struct {
+	next  sink.Sink
+	store Store
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// New wraps a sink, deduplicating its events with the store. Close closes both.
+func New(next sink.Sink, store Store) *Sink {
+	return &Sink{next: next, store: store}
+}
+
+// Key identifies an event by its signature, instruction index, kind and index among the
+// events of its kind. Balances have no transaction and are identified by their ID.
+func Key(event *sink.Event) string {
+	if event.Balance != nil {
+		return event.ID()
+	}
+	return fmt.Sprintf("%s:%d:%s:%d", event.Signature, event.InstructionIndex(), event.Kind, event.Index)
+}
+
+// Write passes on the events whose keys are unseen, dropping duplicates within the batch
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	keys := make([]string, len(events))
+	for i, event := range events {
+		keys[i] = Key(event)
+	}
+	seen, err := s.store.Seen(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("failed to look up events: %w", err)
+	}
+
+	var fresh []*sink.Event
+	var freshKeys []string
+	batch := make(map[string]bool, len(keys))
+	for i, event := range events {
+		if seen[i] || batch[keys[i]] {
+			continue
+		}
+		batch[keys[i]] = true
+		fresh = append(fresh, event)
+		freshKeys = append(freshKeys, keys[i])
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if err := s.next.Write(ctx, fresh); err != nil {
+		return err
+	}
+	if err := s.store.Mark(ctx, freshKeys); err != nil {
+		return fmt.Errorf("failed to mark events: %w", err)
+	}
+	return nil
+}
+
+// Close closes the wrapped sink and the store
+func (s *Sink) Close() error {
+	return errors.Join(s.next.Close(), s.store.Close())
+}
@@ -0,0 +1,206 @@
+package dedupe
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/redis"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// recordingSink keeps the events written to it and fails when told to
+type recordingSink struct {
+	events []*sink.Event
+	fail   bool
+	closed bool
+}
+
+func (s *recordingSink) Write(_ context.Context, events []*sink.Event) error {
+	if s.fail {
+		return errors.New("unavailable")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func testEvents() []*sink.Event {
+	return sink.Events(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{7},
+		Swaps:     []*tx_parser.SwapInfo{{InstructionIndex: 2}},
+		Transfers: []*tx_parser.TransferInfo{{InstructionIndex: 2}, {InstructionIndex: 3}},
+	})
+}
+
+func TestSink(t *testing.T) {
+	events := testEvents()
+	if key := Key(events[2]); key != (solana.Signature{7}).String()+":3:transfer:1" {
+		t.Errorf("unexpected key %s", key)
+	}
+
+	next := &recordingSink{}
+	s := New(next, NewMemoryStore(0))
+	ctx := context.Background()
+
+	// a replayed batch repeating an event
+	if err := s.Write(ctx, append(events[:2:2], events[0])); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if len(next.events) != 2 {
+		t.Fatalf("expected the duplicate within the batch dropped, got %d events", len(next.events))
+	}
+
+	// a failed write marks nothing, so the retry delivers the events
+	next.fail = true
+	if err := s.Write(ctx, testEvents()); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	next.fail = false
+	if err := s.Write(ctx, testEvents()); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if len(next.events) != 3 || next.events[2].Kind != sink.KindTransfer || next.events[2].Index != 1 {
+		t.Errorf("expected only the unseen transfer written, got %d events", len(next.events))
+	}
+
+	if err := s.Close(); err != nil || !next.closed {
+		t.Errorf("expected the wrapped sink closed, got %v", err)
+	}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	store := NewMemoryStore(2)
+	ctx := context.Background()
+	store.Mark(ctx, []string{"a", "b", "c"})
+
+	seen, _ := store.Seen(ctx, []string{"a", "b", "c"})
+	if seen[0] || !seen[1] || !seen[2] {
+		t.Errorf("expected the oldest key evicted, got %v", seen)
+	}
+}
+
+// serveRedis runs a minimal Redis server answering AUTH, MGET and SET
+func serveRedis(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	data := make(map[string]string)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					request, err := redis.ReadReply(reader)
+					if err != nil {
+						return
+					}
+					args := request.([]any)
+					mu.Lock()
+					switch args[0] {
+					case "AUTH":
+						if args[1] == "secret" {
+							fmt.Fprint(conn, "+OK\r\n")
+						} else {
+							fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+						}
+					case "SET":
+						data[args[1].(string)] = args[2].(string)
+						fmt.Fprint(conn, "+OK\r\n")
+					case "MGET":
+						fmt.Fprintf(conn, "*%d\r\n", len(args)-1)
+						for _, key := range args[1:] {
+							if value, ok := data[key.(string)]; ok {
+								fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+							} else {
+								fmt.Fprint(conn, "$-1\r\n")
+							}
+						}
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestRedisStore(t *testing.T) {
+	addr := serveRedis(t)
+	ctx := context.Background()
+
+	client := redis.New(redis.Config{Addr: addr, Password: "secret"})
+	defer client.Close()
+	store := NewRedisStore(client, RedisConfig{Prefix: "test:"})
+	if err := store.Mark(ctx, []string{"a", "b"}); err != nil {
+		t.Fatalf("failed to mark: %v", err)
+	}
+	seen, err := store.Seen(ctx, []string{"b", "c", "a"})
+	if err != nil {
+		t.Fatalf("failed to look up: %v", err)
+	}
+	if !seen[0] || seen[1] || !seen[2] {
+		t.Errorf("unexpected lookup %v", seen)
+	}
+
+	wrong := NewRedisStore(redis.New(redis.Config{Addr: addr, Password: "wrong", Timeout: time.Second}), RedisConfig{})
+	if _, err := wrong.Seen(ctx, []string{"a"}); err == nil {
+		t.Error("expected a wrong password to fail")
+	}
+}
+
+// TestPostgresStore runs against a real database when SOLANA_TOOLKIT_POSTGRES_URL is set
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv("SOLANA_TOOLKIT_POSTGRES_URL")
+	if dsn == "" {
+		t.Skip("SOLANA_TOOLKIT_POSTGRES_URL not set")
+	}
+	ctx := context.Background()
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewPostgresStore(ctx, db, "sink_dedupe_test")
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer db.ExecContext(ctx, "DROP TABLE sink_dedupe_test")
+
+	if err := store.Mark(ctx, []string{"a", "b", "a"}); err != nil {
+		t.Fatalf("failed to mark: %v", err)
+	}
+	seen, err := store.Seen(ctx, []string{"a", "c"})
+	if err != nil || !seen[0] || seen[1] {
+		t.Errorf("unexpected lookup %v: %v", seen, err)
+	}
+	if pruned, err := store.Prune(ctx, time.Now().Add(time.Minute)); err != nil || pruned != 2 {
+		t.Errorf("expected both keys pruned, got %d: %v", pruned, err)
+	}
+}
@@ -0,0 +1,64 @@
+package dedupe
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps the most recent keys in memory, forgetting the oldest once full. It
+// covers replays within a process, such as reconnects, but not restarts.
+type MemoryStore struct {
+	mu    sync.Mutex
+	size  int
+	keys  map[string]struct{}
+	order []string // ring of keys in the order they were marked
+	next  int
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a store remembering up to size keys, defaulting to 1000000
+func NewMemoryStore(size int) *MemoryStore {
+	if size <= 0 {
+		size = 1_000_000
+	}
+	return &MemoryStore{size: size, keys: make(map[string]struct{})}
+}
+
+// Seen reports for each key whether it is remembered
+func (m *MemoryStore) Seen(_ context.Context, keys []string) ([]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make([]bool, len(keys))
+	for i, key := range keys {
+		_, seen[i] = m.keys[key]
+	}
+	return seen, nil
+}
+
+// Mark remembers the keys, evicting the oldest when the store is full
+func (m *MemoryStore) Mark(_ context.Context, keys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if _, ok := m.keys[key]; ok {
+			continue
+		}
+		if len(m.order) < m.size {
+			m.order = append(m.order, key)
+		} else {
+			delete(m.keys, m.order[m.next])
+			m.order[m.next] = key
+			m.next = (m.next + 1) % m.size
+		}
+		m.keys[key] = struct{}{}
+	}
+	return nil
+}
+
+// Close does nothing
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package dedupe
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresStore keeps keys in a PostgreSQL table, so they survive restarts and can be
+// shared with the rows they protect. Keys are kept until pruned.
+type PostgresStore struct {
+	db    *sql.DB
+	table string
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates the table, named sink_dedupe by default, if it does not exist
+// and returns a store over the lib/pq connection pool. Close leaves the pool open.
+func NewPostgresStore(ctx context.Context, db *sql.DB, table string) (*PostgresStore, error) {
+	if table == "" {
+		table = "sink_dedupe"
+	}
+	table = pq.QuoteIdentifier(table)
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+table+` (
+		key        TEXT PRIMARY KEY,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dedupe table: %w", err)
+	}
+	return &PostgresStore{db: db, table: table}, nil
+}
+
+// Seen looks the keys up in one query
+func (p *PostgresStore) Seen(ctx context.Context, keys []string) ([]bool, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT key FROM `+p.table+` WHERE key = ANY($1)`, pq.Array(keys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query keys: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan key: %w", err)
+		}
+		found[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query keys: %w", err)
+	}
+
+	seen := make([]bool, len(keys))
+	for i, key := range keys {
+		seen[i] = found[key]
+	}
+	return seen, nil
+}
+
+// Mark inserts the keys in one statement, skipping keys already present
+func (p *PostgresStore) Mark(ctx context.Context, keys []string) error {
+	_, err := p.db.ExecContext(ctx, `INSERT INTO `+p.table+` (key) SELECT unnest($1::text[]) ON CONFLICT DO NOTHING`, pq.Array(keys))
+	if err != nil {
+		return fmt.Errorf("failed to insert keys: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes keys marked before the cutoff, returning how many were deleted
+func (p *PostgresStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := p.db.ExecContext(ctx, `DELETE FROM `+p.table+` WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Close does nothing, the connection pool belongs to the caller
+func (p *PostgresStore) Close() error {
+	return nil
+}
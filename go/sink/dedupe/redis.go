@@ -0,0 +1,82 @@
+package dedupe
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/redis"
+)
+
+// RedisConfig controls the Redis store
+type RedisConfig struct {
+	// Prefix is prepended to every key, defaults to "solana-toolkit:dedupe:"
+	Prefix string
+
+	// TTL expires marked keys, defaults to 24h. Replays older than the TTL are written again.
+	TTL time.Duration
+}
+
+// RedisStore keeps keys in Redis with an expiry, so several indexer processes and restarts
+// share them
+type RedisStore struct {
+	client *redis.Client
+	config RedisConfig
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a store over the client. Close leaves the client open.
+func NewRedisStore(client *redis.Client, config RedisConfig) *RedisStore {
+	if config.Prefix == "" {
+		config.Prefix = "solana-toolkit:dedupe:"
+	}
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	return &RedisStore{client: client, config: config}
+}
+
+// Seen looks the keys up with a single MGET
+func (r *RedisStore) Seen(ctx context.Context, keys []string) ([]bool, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	args := []string{"MGET"}
+	for _, key := range keys {
+		args = append(args, r.config.Prefix+key)
+	}
+	reply, err := r.client.Do(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != len(keys) {
+		return nil, fmt.Errorf("unexpected MGET reply %v", reply)
+	}
+	seen := make([]bool, len(keys))
+	for i, value := range values {
+		seen[i] = value != nil
+	}
+	return seen, nil
+}
+
+// Mark sets the keys with the TTL in one pipeline
+func (r *RedisStore) Mark(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	ttl := strconv.FormatInt(r.config.TTL.Milliseconds(), 10)
+	commands := make([][]string, len(keys))
+	for i, key := range keys {
+		commands[i] = []string{"SET", r.config.Prefix + key, "1", "PX", ttl}
+	}
+	_, err := r.client.Pipeline(ctx, commands)
+	return err
+}
+
+// Close does nothing, the client belongs to the caller
+func (r *RedisStore) Close() error {
+	return nil
+}
@@ -0,0 +1,147 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// MessageWriter produces messages to Kafka, implemented by *kafka.Writer
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafkago.Message) error
+	Close() error
+}
+
+// Sink produces parsed events to Kafka topics
+type Sink struct {
+	writer MessageWriter
+	config Config
+}
+
+// New creates a Kafka sink connected to the configured brokers
+func New(config Config) (*Sink, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("at least one broker is required")
+	}
+	config = withDefaults(config)
+
+	writer := &kafkago.Writer{
+		Addr:         kafkago.TCP(config.Brokers...),
+		Balancer:     &kafkago.Hash{},
+		MaxAttempts:  config.MaxAttempts,
+		BatchSize:    config.BatchSize,
+		BatchTimeout: config.BatchTimeout,
+		RequiredAcks: kafkago.RequireAll,
+	}
+	if config.Delivery == DeliveryAtMostOnce {
+		writer.RequiredAcks = kafkago.RequireNone
+		writer.Async = true
+	}
+
+	return NewWithWriter(writer, config)
+}
+
+// NewWithWriter creates a Kafka sink that produces through an existing writer. The writer
+// must not have a topic set, topics are chosen per event.
+func NewWithWriter(writer MessageWriter, config Config) (*Sink, error) {
+	config = withDefaults(config)
+	if config.Topic == "" && len(config.Topics) == 0 {
+		return nil, fmt.Errorf("a topic is required")
+	}
+	switch config.PartitionBy {
+	case PartitionByMint, PartitionByWallet, PartitionBySignature:
+	default:
+		return nil, fmt.Errorf("unsupported partition key %q", config.PartitionBy)
+	}
+
+	return &Sink{
+		writer: writer,
+		config: config,
+	}, nil
+}
+
+// withDefaults fills in unset options
+func withDefaults(config Config) Config {
+	if config.PartitionBy == "" {
+		config.PartitionBy = PartitionByMint
+	}
+	if config.Encoder == nil {
+		config.Encoder = sink.JSONEncoder{}
+	}
+	if config.Delivery == "" {
+		config.Delivery = DeliveryAtLeastOnce
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 10
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.BatchTimeout <= 0 {
+		config.BatchTimeout = 10 * time.Millisecond
+	}
+	return config
+}
+
+// Write encodes the events and produces them. With DeliveryAtLeastOnce it returns once
+// every message has been acknowledged.
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	messages := make([]kafkago.Message, 0, len(events))
+	for _, event := range events {
+		topic := s.topic(event.Kind)
+		if topic == "" {
+			continue
+		}
+
+		value, err := s.config.Encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, kafkago.Message{
+			Topic: topic,
+			Key:   s.key(event),
+			Value: value,
+			Headers: []kafkago.Header{
+				{Key: "kind", Value: []byte(event.Kind)},
+				{Key: "content-type", Value: []byte(s.config.Encoder.ContentType())},
+				{Key: "event-id", Value: []byte(event.ID())},
+			},
+		})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := s.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to produce %d events: %w", len(messages), err)
+	}
+	return nil
+}
+
+// Close flushes buffered messages and closes the writer
+func (s *Sink) Close() error {
+	return s.writer.Close()
+}
+
+// topic returns the topic of the event kind, empty if the kind is not produced
+func (s *Sink) topic(kind sink.Kind) string {
+	if topic, ok := s.config.Topics[kind]; ok {
+		return topic
+	}
+	return s.config.Topic
+}
+
+// key returns the partitioning key of the event
+func (s *Sink) key(event *sink.Event) []byte {
+	switch s.config.PartitionBy {
+	case PartitionByWallet:
+		return []byte(event.Wallet().String())
+	case PartitionBySignature:
+		return []byte(event.Signature.String())
+	}
+	return []byte(event.Mint().String())
+}
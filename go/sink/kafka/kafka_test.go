@@ -0,0 +1,119 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// recordingWriter collects produced messages
+type recordingWriter struct {
+	messages []kafkago.Message
+	err      error
+	closed   bool
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, messages ...kafkago.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.messages = append(w.messages, messages...)
+	return nil
+}
+
+func (w *recordingWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func testEvents() ([]*sink.Event, solana.PublicKey, solana.PublicKey) {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	events := sink.Events(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{1},
+		Swaps: []*tx_parser.SwapInfo{{
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: token},
+			TokenOut: tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID},
+		}},
+		Transfers:   []*tx_parser.TransferInfo{{Mint: token, SourceOwner: wallet}},
+		AdminEvents: []*tx_parser.TokenAdminEvent{{Mint: token, Authority: wallet}},
+	})
+	return events, wallet, token
+}
+
+func TestSinkRoutesAndKeysEvents(t *testing.T) {
+	events, _, token := testEvents()
+	writer := &recordingWriter{}
+	s, err := NewWithWriter(writer, Config{
+		Topic:  "events",
+		Topics: map[sink.Kind]string{sink.KindSwap: "swaps", sink.KindTokenAdmin: ""},
+	})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	if err := s.Write(context.Background(), events); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if len(writer.messages) != 2 {
+		t.Fatalf("expected the admin event to be skipped, got %d messages", len(writer.messages))
+	}
+
+	swap, transfer := writer.messages[0], writer.messages[1]
+	if swap.Topic != "swaps" || transfer.Topic != "events" {
+		t.Errorf("unexpected topics %q and %q", swap.Topic, transfer.Topic)
+	}
+	if string(swap.Key) != token.String() || string(transfer.Key) != token.String() {
+		t.Errorf("expected both events keyed by mint %s, got %s and %s", token, swap.Key, transfer.Key)
+	}
+	if len(swap.Headers) != 3 || string(swap.Headers[0].Value) != "swap" {
+		t.Errorf("unexpected headers: %+v", swap.Headers)
+	}
+
+	if err := s.Close(); err != nil || !writer.closed {
+		t.Errorf("expected the writer to be closed, got %v", err)
+	}
+}
+
+func TestSinkPartitionByWallet(t *testing.T) {
+	events, wallet, _ := testEvents()
+	writer := &recordingWriter{}
+	s, err := NewWithWriter(writer, Config{Topic: "events", PartitionBy: PartitionByWallet})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	if err := s.Write(context.Background(), events); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	for _, message := range writer.messages {
+		if string(message.Key) != wallet.String() {
+			t.Errorf("expected key %s, got %s", wallet, message.Key)
+		}
+	}
+}
+
+func TestSinkErrors(t *testing.T) {
+	if _, err := NewWithWriter(&recordingWriter{}, Config{}); err == nil {
+		t.Error("expected an error without a topic")
+	}
+	if _, err := NewWithWriter(&recordingWriter{}, Config{Topic: "events", PartitionBy: "pool"}); err == nil {
+		t.Error("expected an error for an unknown partition key")
+	}
+	if _, err := New(Config{Topic: "events"}); err == nil {
+		t.Error("expected an error without brokers")
+	}
+
+	events, _, _ := testEvents()
+	broker := errors.New("broker down")
+	s, _ := NewWithWriter(&recordingWriter{err: broker}, Config{Topic: "events"})
+	if err := s.Write(context.Background(), events); !errors.Is(err, broker) {
+		t.Errorf("expected the writer error, got %v", err)
+	}
+}
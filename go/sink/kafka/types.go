@@ -0,0 +1,56 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// PartitionKey selects the message key, and therefore the partition, of each event
+type PartitionKey string
+
+const (
+	PartitionByMint      PartitionKey = "mint"      // all events of a token land on one partition
+	PartitionByWallet    PartitionKey = "wallet"    // all events of a wallet land on one partition
+	PartitionBySignature PartitionKey = "signature" // events of a transaction stay together
+)
+
+// Delivery is the delivery guarantee of the sink
+type Delivery string
+
+const (
+	// DeliveryAtLeastOnce waits for all in-sync replicas to acknowledge every batch and
+	// retries failed writes, so events may be duplicated but are never lost
+	DeliveryAtLeastOnce Delivery = "at_least_once"
+
+	// DeliveryAtMostOnce writes asynchronously without acknowledgements, trading losses on
+	// broker failures for throughput
+	DeliveryAtMostOnce Delivery = "at_most_once"
+)
+
+// Config controls the Kafka sink
+type Config struct {
+	Brokers []string
+
+	// Topic receives every event unless Topics has an entry for its kind. Map a kind to
+	// an empty topic to skip it.
+	Topic  string
+	Topics map[sink.Kind]string
+
+	// PartitionBy defaults to PartitionByMint
+	PartitionBy PartitionKey
+
+	// Encoder defaults to sink.JSONEncoder, pb.EventEncoder encodes protobuf
+	Encoder sink.Encoder
+
+	// Delivery defaults to DeliveryAtLeastOnce
+	Delivery Delivery
+
+	// MaxAttempts is the number of times a batch is attempted before failing, defaults to 10
+	MaxAttempts int
+
+	// BatchSize and BatchTimeout bound how long messages are buffered before being sent,
+	// default to 100 messages and 10ms
+	BatchSize    int
+	BatchTimeout time.Duration
+}
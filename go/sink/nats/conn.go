@@ -0,0 +1,397 @@
+package nats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// errClosed is returned for requests pending when the connection closes
+var errClosed = errors.New("nats: connection closed")
+
+// conn is a connection speaking the NATS client protocol. Replies to published messages
+// arrive on the connection's inbox and are routed by their reply subject.
+type conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	inbox   string
+
+	writeMu sync.Mutex
+	writer  *bufio.Writer
+
+	mu      sync.Mutex
+	pending map[string]chan reply
+	next    uint64
+	lastErr error // last -ERR of the server, reported when it closes the connection
+	err     error
+	done    chan struct{}
+}
+
+// message is a message to publish
+type message struct {
+	subject string
+	headers [][2]string
+	data    []byte
+}
+
+// reply is a message received on the inbox
+type reply struct {
+	status string // status code of header-only replies, e.g. 503 when nothing is subscribed
+	data   []byte
+}
+
+// dial connects and authenticates, then starts reading from the server
+func dial(ctx context.Context, rawURL string, timeout time.Duration) (*conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	netConn.SetDeadline(time.Now().Add(timeout))
+	reader := bufio.NewReader(netConn)
+
+	line, err := readLine(reader)
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to read server info %q: %v", line, err)
+	}
+	var serverInfo info
+	if err := json.Unmarshal([]byte(line[len("INFO "):]), &serverInfo); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to decode server info: %w", err)
+	}
+	if !serverInfo.Headers {
+		netConn.Close()
+		return nil, fmt.Errorf("server does not support headers, NATS 2.2 or newer is required")
+	}
+
+	// TLS is negotiated after the plain text INFO
+	if u.Scheme == "tls" || serverInfo.TLSRequired {
+		tlsConn := tls.Client(netConn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("failed tls handshake: %w", err)
+		}
+		netConn = tlsConn
+		reader = bufio.NewReader(netConn)
+	}
+
+	options := map[string]any{
+		"verbose":       false,
+		"pedantic":      false,
+		"lang":          "go",
+		"version":       "solana-toolkit",
+		"protocol":      1,
+		"headers":       true,
+		"no_responders": true,
+	}
+	if user := u.User; user != nil {
+		if password, ok := user.Password(); ok {
+			options["user"], options["pass"] = user.Username(), password
+		} else {
+			options["auth_token"] = user.Username()
+		}
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to encode connect options: %w", err)
+	}
+
+	c := &conn{
+		netConn: netConn,
+		reader:  reader,
+		timeout: timeout,
+		inbox:   "_INBOX." + solana.NewWallet().PublicKey().String(),
+		writer:  bufio.NewWriter(netConn),
+		pending: make(map[string]chan reply),
+		done:    make(chan struct{}),
+	}
+	fmt.Fprintf(c.writer, "CONNECT %s\r\nPING\r\n", connect)
+	if err := c.writer.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to write to nats: %w", err)
+	}
+
+	// the server answers the PING once CONNECT is accepted
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("failed to read from nats: %w", err)
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			netConn.Close()
+			return nil, fmt.Errorf("nats refused the connection: %s", serverError(line))
+		}
+		if line == "PONG" {
+			break
+		}
+	}
+	netConn.SetDeadline(time.Time{})
+
+	fmt.Fprintf(c.writer, "SUB %s.* 1\r\n", c.inbox)
+	if err := c.writer.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to subscribe to the inbox: %w", err)
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// send publishes the messages in one write. With wait it returns one reply per message,
+// waiting for all of them.
+func (c *conn) send(ctx context.Context, messages []message, wait bool) ([]reply, error) {
+	var channels []chan reply
+	var subjects []string
+	if wait {
+		channels = make([]chan reply, len(messages))
+		subjects = make([]string, len(messages))
+		c.mu.Lock()
+		if c.err != nil {
+			c.mu.Unlock()
+			return nil, c.err
+		}
+		for i := range messages {
+			c.next++
+			subjects[i] = c.inbox + "." + strconv.FormatUint(c.next, 10)
+			channels[i] = make(chan reply, 1)
+			c.pending[subjects[i]] = channels[i]
+		}
+		c.mu.Unlock()
+		defer func() {
+			c.mu.Lock()
+			for _, subject := range subjects {
+				delete(c.pending, subject)
+			}
+			c.mu.Unlock()
+		}()
+	}
+
+	var buf bytes.Buffer
+	for i, msg := range messages {
+		replyTo := ""
+		if wait {
+			replyTo = " " + subjects[i]
+		}
+		if len(msg.headers) == 0 {
+			fmt.Fprintf(&buf, "PUB %s%s %d\r\n", msg.subject, replyTo, len(msg.data))
+		} else {
+			var header bytes.Buffer
+			header.WriteString("NATS/1.0\r\n")
+			for _, h := range msg.headers {
+				fmt.Fprintf(&header, "%s: %s\r\n", h[0], h[1])
+			}
+			header.WriteString("\r\n")
+			fmt.Fprintf(&buf, "HPUB %s%s %d %d\r\n", msg.subject, replyTo, header.Len(), header.Len()+len(msg.data))
+			buf.Write(header.Bytes())
+		}
+		buf.Write(msg.data)
+		buf.WriteString("\r\n")
+	}
+
+	c.writeMu.Lock()
+	c.netConn.SetWriteDeadline(time.Now().Add(c.timeout))
+	_, err := c.writer.Write(buf.Bytes())
+	if err == nil {
+		err = c.writer.Flush()
+	}
+	c.writeMu.Unlock()
+	if err != nil {
+		c.fail(err)
+		return nil, fmt.Errorf("failed to write to nats: %w", err)
+	}
+	if !wait {
+		return nil, nil
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	replies := make([]reply, len(messages))
+	for i, ch := range channels {
+		select {
+		case replies[i] = <-ch:
+		case <-c.done:
+			return nil, c.closedErr()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, fmt.Errorf("timed out waiting for %d replies", len(messages)-i)
+		}
+	}
+	return replies, nil
+}
+
+// request publishes one message and waits for its reply
+func (c *conn) request(ctx context.Context, subject string, data []byte) (reply, error) {
+	replies, err := c.send(ctx, []message{{subject: subject, data: data}}, true)
+	if err != nil {
+		return reply{}, err
+	}
+	return replies[0], nil
+}
+
+// readLoop reads from the server until the connection fails
+func (c *conn) readLoop() {
+	for {
+		line, err := readLine(c.reader)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		op, args, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(op) {
+		case "MSG":
+			// MSG <subject> <sid> [reply] <size>
+			fields := strings.Fields(args)
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil || len(fields) < 3 {
+				c.fail(fmt.Errorf("malformed message %q", line))
+				return
+			}
+			data, err := readPayload(c.reader, size)
+			if err != nil {
+				c.fail(err)
+				return
+			}
+			c.dispatch(fields[0], reply{data: data})
+		case "HMSG":
+			// HMSG <subject> <sid> [reply] <header size> <total size>
+			fields := strings.Fields(args)
+			if len(fields) < 4 {
+				c.fail(fmt.Errorf("malformed message %q", line))
+				return
+			}
+			headerSize, err1 := strconv.Atoi(fields[len(fields)-2])
+			total, err2 := strconv.Atoi(fields[len(fields)-1])
+			if err1 != nil || err2 != nil || headerSize > total {
+				c.fail(fmt.Errorf("malformed message %q", line))
+				return
+			}
+			data, err := readPayload(c.reader, total)
+			if err != nil {
+				c.fail(err)
+				return
+			}
+			c.dispatch(fields[0], reply{status: status(data[:headerSize]), data: data[headerSize:]})
+		case "PING":
+			c.writeMu.Lock()
+			c.writer.WriteString("PONG\r\n")
+			c.writer.Flush()
+			c.writeMu.Unlock()
+		case "-ERR":
+			c.mu.Lock()
+			c.lastErr = errors.New("nats: " + serverError(line))
+			c.mu.Unlock()
+		}
+	}
+}
+
+// dispatch hands a reply to the request waiting for it
+func (c *conn) dispatch(subject string, r reply) {
+	c.mu.Lock()
+	ch, ok := c.pending[subject]
+	delete(c.pending, subject)
+	c.mu.Unlock()
+	if ok {
+		ch <- r
+	}
+}
+
+// fail closes the connection, recording why
+func (c *conn) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	if c.lastErr != nil {
+		err = c.lastErr
+	}
+	c.err = err
+	close(c.done)
+	c.netConn.Close()
+}
+
+// closedErr returns why the connection closed
+func (c *conn) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// broken reports whether the connection failed
+func (c *conn) broken() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the connection
+func (c *conn) close() error {
+	c.fail(errClosed)
+	return nil
+}
+
+// readLine reads one protocol line without its line ending
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPayload reads a payload of size bytes and its line ending
+func readPayload(reader *bufio.Reader, size int) ([]byte, error) {
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+	return data[:size], nil
+}
+
+// status returns the status code of a header block, e.g. "503" for "NATS/1.0 503"
+func status(header []byte) string {
+	line, _, _ := bytes.Cut(header, []byte("\r\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// serverError extracts the message of an -ERR line
+func serverError(line string) string {
+	return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'")
+}
@@ -0,0 +1,262 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// DEFAULT_URL is the address of a local server
+const DEFAULT_URL = "nats://127.0.0.1:4222"
+
+// errStreamNameInUse is the JetStream error code of creating a stream that exists with a
+// different configuration
+const errStreamNameInUse = 10058
+
+// Sink publishes parsed events to NATS subjects, through JetStream by default, so
+// subscribers can pick events with wildcards such as "swaps.*.<mint>"
+type Sink struct {
+	config Config
+
+	mu   sync.Mutex
+	conn *conn
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// Open connects to the server and, when a stream is configured, creates or updates it to
+// capture the sink's subjects. A failed connection is dialed again on the next Write.
+func Open(ctx context.Context, config Config) (*Sink, error) {
+	config = withDefaults(config)
+	switch config.Delivery {
+	case DeliveryAtLeastOnce, DeliveryAtMostOnce:
+	default:
+		return nil, fmt.Errorf("unsupported delivery %q", config.Delivery)
+	}
+
+	s := &Sink{config: config}
+	c, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if config.Stream != "" {
+		if err := ensureStream(ctx, c, config.Stream, s.streamSubjects()); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// withDefaults fills in unset options
+func withDefaults(config Config) Config {
+	if config.URL == "" {
+		config.URL = DEFAULT_URL
+	}
+	if config.Subject == "" {
+		config.Subject = "events.{kind}.{mint}"
+	}
+	subjects := map[sink.Kind]string{
+		sink.KindSwap:     "swaps.{protocol}.{mint}",
+		sink.KindTransfer: "transfers.{mint}",
+	}
+	for kind, subject := range config.Subjects {
+		subjects[kind] = subject
+	}
+	config.Subjects = subjects
+	if config.Encoder == nil {
+		config.Encoder = sink.JSONEncoder{}
+	}
+	if config.Delivery == "" {
+		config.Delivery = DeliveryAtLeastOnce
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	return config
+}
+
+// Write publishes the events in one round trip. With DeliveryAtLeastOnce it returns once
+// every message is stored by a stream.
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	messages := make([]message, 0, len(events))
+	for _, event := range events {
+		subject := s.subject(event)
+		if subject == "" {
+			continue
+		}
+
+		data, err := s.config.Encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message{
+			subject: subject,
+			headers: [][2]string{
+				{"Nats-Msg-Id", event.ID()},
+				{"Content-Type", s.config.Encoder.ContentType()},
+				{"Kind", string(event.Kind)},
+			},
+			data: data,
+		})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	c, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	wait := s.config.Delivery == DeliveryAtLeastOnce
+	replies, err := c.send(ctx, messages, wait)
+	if err != nil {
+		return fmt.Errorf("failed to publish %d events: %w", len(messages), err)
+	}
+	for i, r := range replies {
+		if err := checkAck(r); err != nil {
+			return fmt.Errorf("failed to publish to %s: %w", messages[i].subject, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.close()
+}
+
+// connect returns the open connection, dialing again if it failed
+func (s *Sink) connect(ctx context.Context) (*conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil && !s.conn.broken() {
+		return s.conn, nil
+	}
+	c, err := dial(ctx, s.config.URL, s.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	s.conn = c
+	return c, nil
+}
+
+// subject returns the subject of the event, empty if the kind is not published
+func (s *Sink) subject(event *sink.Event) string {
+	template, ok := s.config.Subjects[event.Kind]
+	if !ok {
+		template = s.config.Subject
+	}
+	if template == "" {
+		return ""
+	}
+
+	protocol := ""
+	if event.Swap != nil {
+		protocol = strings.ToLower(string(event.Swap.Protocol))
+	}
+	subject := strings.NewReplacer(
+		"{kind}", token(string(event.Kind)),
+		"{protocol}", token(protocol),
+		"{mint}", token(event.Mint().String()),
+		"{wallet}", token(event.Wallet().String()),
+	).Replace(template)
+	if s.config.Prefix != "" {
+		subject = s.config.Prefix + "." + subject
+	}
+	return subject
+}
+
+// streamSubjects returns wildcards matching every subject the sink publishes to
+func (s *Sink) streamSubjects() []string {
+	templates := []string{s.config.Subject}
+	for _, template := range s.config.Subjects {
+		templates = append(templates, template)
+	}
+
+	var subjects []string
+	for _, template := range templates {
+		if template == "" {
+			continue
+		}
+		tokens := strings.Split(template, ".")
+		for i, t := range tokens {
+			if strings.Contains(t, "{") {
+				tokens[i] = "*"
+			}
+		}
+		subject := strings.Join(tokens, ".")
+		if s.config.Prefix != "" {
+			subject = s.config.Prefix + "." + subject
+		}
+		if !slices.Contains(subjects, subject) {
+			subjects = append(subjects, subject)
+		}
+	}
+	slices.Sort(subjects)
+	return subjects
+}
+
+// ensureStream creates the stream, or updates the subjects of an existing one
+func ensureStream(ctx context.Context, c *conn, name string, subjects []string) error {
+	request, err := json.Marshal(map[string]any{"name": name, "subjects": subjects})
+	if err != nil {
+		return fmt.Errorf("failed to encode stream config: %w", err)
+	}
+
+	r, err := c.request(ctx, "$JS.API.STREAM.CREATE."+name, request)
+	if err != nil {
+		return fmt.Errorf("failed to create stream %s: %w", name, err)
+	}
+	err = checkAck(r)
+	if apiErr, ok := err.(*apiError); ok && apiErr.ErrCode == errStreamNameInUse {
+		if r, err = c.request(ctx, "$JS.API.STREAM.UPDATE."+name, request); err != nil {
+			return fmt.Errorf("failed to update stream %s: %w", name, err)
+		}
+		err = checkAck(r)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set up stream %s: %w", name, err)
+	}
+	return nil
+}
+
+// checkAck returns the error of a JetStream reply
+func checkAck(r reply) error {
+	if r.status == "503" {
+		return fmt.Errorf("no stream captures the subject, is JetStream enabled?")
+	}
+	var decoded ack
+	if err := json.Unmarshal(r.data, &decoded); err != nil {
+		return fmt.Errorf("failed to decode acknowledgement %q: %w", r.data, err)
+	}
+	if decoded.Error != nil {
+		return decoded.Error
+	}
+	return nil
+}
+
+// token makes a value safe as one subject token
+func token(value string) string {
+	if value == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, value)
+}
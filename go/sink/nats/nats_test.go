@@ -0,0 +1,233 @@
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// published is a message received by the test server
+type published struct {
+	subject string
+	header  string
+	data    string
+}
+
+// server is a minimal NATS server acknowledging publishes like JetStream
+type server struct {
+	url string
+
+	mu        sync.Mutex
+	connects  []string
+	published []published
+}
+
+func serve(t *testing.T) *server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &server{url: "nats://" + listener.Addr().String()}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s
+}
+
+func (s *server) handle(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"headers\":true}\r\n")
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(line, " ")
+		fields := strings.Fields(args)
+
+		switch op {
+		case "CONNECT":
+			s.mu.Lock()
+			s.connects = append(s.connects, args)
+			s.mu.Unlock()
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "PUB", "HPUB":
+			headerSize, sizes := 0, 1
+			if op == "HPUB" {
+				headerSize, _ = strconv.Atoi(fields[len(fields)-2])
+				sizes = 2
+			}
+			total, _ := strconv.Atoi(fields[len(fields)-1])
+			data, err := readPayload(reader, total)
+			if err != nil {
+				return
+			}
+			msg := published{subject: fields[0], header: string(data[:headerSize]), data: string(data[headerSize:])}
+			s.mu.Lock()
+			s.published = append(s.published, msg)
+			seq := len(s.published)
+			s.mu.Unlock()
+
+			// the reply subject is present when there are more fields than the subject and sizes
+			if len(fields) == 1+sizes {
+				continue
+			}
+			replyTo := fields[1]
+			switch {
+			case strings.HasPrefix(msg.subject, "$JS.API.STREAM.CREATE."):
+				respond(conn, replyTo, `{"error":{"code":400,"err_code":10058,"description":"stream name already in use"}}`)
+			case strings.HasPrefix(msg.subject, "$JS.API.STREAM.UPDATE."):
+				respond(conn, replyTo, `{"config":{}}`)
+			case strings.HasPrefix(msg.subject, "unrouted."):
+				fmt.Fprintf(conn, "HMSG %s 1 16 16\r\nNATS/1.0 503\r\n\r\n\r\n", replyTo)
+			default:
+				respond(conn, replyTo, fmt.Sprintf(`{"stream":"EVENTS","seq":%d}`, seq))
+			}
+		}
+	}
+}
+
+func respond(conn net.Conn, subject, data string) {
+	fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", subject, len(data), data)
+}
+
+// received returns the messages published to subjects with the prefix
+func (s *server) received(prefix string) []published {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found []published
+	for _, msg := range s.published {
+		if strings.HasPrefix(msg.subject, prefix) {
+			found = append(found, msg)
+		}
+	}
+	return found
+}
+
+func testEvents() ([]*sink.Event, solana.PublicKey) {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	events := sink.Events(&tx_parser.ParsedTransaction{
+		Signature: solana.Signature{1},
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: token},
+			TokenOut: tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID},
+		}},
+		Transfers:   []*tx_parser.TransferInfo{{Mint: token, SourceOwner: wallet}},
+		AdminEvents: []*tx_parser.TokenAdminEvent{{Mint: token, Authority: wallet}},
+	})
+	return events, token
+}
+
+func TestSubjects(t *testing.T) {
+	events, mint := testEvents()
+	s := &Sink{config: withDefaults(Config{
+		Prefix:   "mainnet",
+		Subjects: map[sink.Kind]string{sink.KindTokenAdmin: "", sink.KindStake: "stakes.{wallet}"},
+	})}
+
+	expected := []string{"mainnet.swaps.raydium." + mint.String(), "mainnet.transfers." + mint.String(), ""}
+	for i, event := range events {
+		if subject := s.subject(event); subject != expected[i] {
+			t.Errorf("expected subject %q for the %s, got %q", expected[i], event.Kind, subject)
+		}
+	}
+	if subject := s.subject(&sink.Event{Kind: sink.KindBridge}); subject != "mainnet.events.bridge.11111111111111111111111111111111" {
+		t.Errorf("unexpected default subject %q", subject)
+	}
+
+	wildcards := strings.Join(s.streamSubjects(), " ")
+	if wildcards != "mainnet.events.*.* mainnet.stakes.* mainnet.swaps.*.* mainnet.transfers.*" {
+		t.Errorf("unexpected stream subjects %s", wildcards)
+	}
+	if value := token(""); value != "_" {
+		t.Errorf("expected an empty token replaced, got %q", value)
+	}
+	if value := token("a.b *>"); value != "a_b___" {
+		t.Errorf("expected separators and wildcards replaced, got %q", value)
+	}
+}
+
+func TestSink(t *testing.T) {
+	server := serve(t)
+	ctx := context.Background()
+	events, mint := testEvents()
+
+	s, err := Open(ctx, Config{URL: strings.Replace(server.url, "nats://", "nats://secret@", 1), Stream: "EVENTS"})
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	defer s.Close()
+
+	server.mu.Lock()
+	connect := server.connects[0]
+	server.mu.Unlock()
+	var options map[string]any
+	if err := json.Unmarshal([]byte(connect), &options); err != nil || options["auth_token"] != "secret" || options["headers"] != true {
+		t.Errorf("unexpected connect options %s", connect)
+	}
+	updated := server.received("$JS.API.STREAM.UPDATE.EVENTS")
+	if len(updated) != 1 {
+		t.Fatalf("expected the existing stream updated, got %v", updated)
+	}
+	var stream struct{ Subjects []string }
+	if err := json.Unmarshal([]byte(updated[0].data), &stream); err != nil || len(stream.Subjects) != 3 {
+		t.Errorf("unexpected stream config %s", updated[0].data)
+	}
+
+	if err := s.Write(ctx, events); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	swaps := server.received("swaps.")
+	if len(swaps) != 1 || swaps[0].subject != "swaps.raydium."+mint.String() {
+		t.Fatalf("expected the swap on its subject, got %v", swaps)
+	}
+	if !strings.Contains(swaps[0].header, "Nats-Msg-Id: "+events[0].ID()+"\r\n") {
+		t.Errorf("expected the event ID as message ID, got %q", swaps[0].header)
+	}
+	var decoded sink.Event
+	if err := json.Unmarshal([]byte(swaps[0].data), &decoded); err != nil || decoded.Swap == nil {
+		t.Errorf("expected the encoded swap, got %s: %v", swaps[0].data, err)
+	}
+
+	// nothing captures the subject
+	unrouted, err := Open(ctx, Config{URL: server.url, Subject: "unrouted.{kind}", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	defer unrouted.Close()
+	if err := unrouted.Write(ctx, []*sink.Event{{Kind: sink.KindBridge}}); err == nil || !strings.Contains(err.Error(), "no stream") {
+		t.Errorf("expected a missing stream reported, got %v", err)
+	}
+
+	// a broken connection is dialed again
+	unrouted.conn.close()
+	unrouted.config.Delivery = DeliveryAtMostOnce
+	if err := unrouted.Write(ctx, []*sink.Event{{Kind: sink.KindBridge}}); err != nil {
+		t.Errorf("expected the sink to reconnect, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package nats
+
+import (
+	"time"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Delivery is the delivery guarantee of the sink
+type Delivery string
+
+const (
+	// DeliveryAtLeastOnce publishes to JetStream and waits for the stream to acknowledge
+	// every message. Messages carry the event ID as Nats-Msg-Id, so retried writes within
+	// the stream's duplicate window are stored once.
+	DeliveryAtLeastOnce Delivery = "at_least_once"
+
+	// DeliveryAtMostOnce publishes with core NATS without acknowledgements, delivered to
+	// current subscribers and to streams that happen to capture the subjects
+	DeliveryAtMostOnce Delivery = "at_most_once"
+)
+
+// Config controls the NATS sink
+type Config struct {
+	// URL of the server, nats:// or tls://, defaults to nats://127.0.0.1:4222. Credentials
+	// in the URL authenticate the connection, a user without a password is a token.
+	URL string
+
+	// Subject is the template of every event's subject unless Subjects has an entry for its
+	// kind. "{kind}", "{protocol}", "{mint}" and "{wallet}" are replaced by the event's
+	// values, lower cased for the protocol. Defaults to "events.{kind}.{mint}".
+	Subject string
+
+	// Subjects overrides the template per kind. Swaps default to "swaps.{protocol}.{mint}"
+	// and transfers to "transfers.{mint}". Map a kind to an empty template to skip it.
+	Subjects map[sink.Kind]string
+
+	// Prefix is prepended to every subject with a dot, e.g. the deployment name
+	Prefix string
+
+	// Stream creates or updates a JetStream stream capturing the sink's subjects on Open,
+	// when set
+	Stream string
+
+	// Encoder defaults to sink.JSONEncoder
+	Encoder sink.Encoder
+
+	// Delivery defaults to DeliveryAtLeastOnce
+	Delivery Delivery
+
+	// Timeout bounds dialing and waiting for acknowledgements, defaults to 5s
+	Timeout time.Duration
+}
+
+// info is the INFO message the server sends on connect
+type info struct {
+	Headers      bool `json:"headers"`
+	AuthRequired bool `json:"auth_required"`
+	TLSRequired  bool `json:"tls_required"`
+}
+
+// ack is the reply of JetStream to a publish or API request
+type ack struct {
+	Stream    string    `json:"stream"`
+	Sequence  uint64    `json:"seq"`
+	Duplicate bool      `json:"duplicate"`
+	Error     *apiError `json:"error"`
+}
+
+// apiError is an error reply of JetStream
+type apiError struct {
+	Code        int    `json:"code"`
+	ErrCode     int    `json:"err_code"`
+	Description string `json:"description"`
+}
+
+func (e *apiError) Error() string {
+	return "nats: " + e.Description
+}
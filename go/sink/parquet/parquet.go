@@ -0,0 +1,239 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// unknownPartition is the Hive default partition, used for events without a block time
+const unknownPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// Exporter writes swaps and transfers to Hive-partitioned Parquet files that can be queried
+// directly, e.g. read_parquet('dir/swaps/*/*.parquet', hive_partitioning = true) in DuckDB.
+// Files are written under a temporary name and renamed once complete. Other event kinds
+// are ignored.
+type Exporter struct {
+	config Config
+	props  *parquet.WriterProperties
+	run    string
+
+	mu    sync.Mutex
+	files map[fileKey]*partFile
+	seq   int
+	tick  uint64
+}
+
+var _ sink.Sink = (*Exporter)(nil)
+
+// fileKey identifies an open file by table and partition directory
+type fileKey struct {
+	table     *table
+	partition string
+}
+
+// partFile is a Parquet file being written
+type partFile struct {
+	path    string
+	writer  *pqarrow.FileWriter
+	builder *array.RecordBuilder
+	rows    int
+	used    uint64
+}
+
+// New creates an exporter writing below config.Dir
+func New(config Config) (*Exporter, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("directory is required")
+	}
+	if config.PartitionBy == "" {
+		config.PartitionBy = PartitionByDate
+	}
+	if config.PartitionBy != PartitionByDate && config.PartitionBy != PartitionBySlot {
+		return nil, fmt.Errorf("unknown partitioning %q", config.PartitionBy)
+	}
+	if config.SlotsPerPartition == 0 {
+		config.SlotsPerPartition = 432_000
+	}
+	if config.RowGroupSize <= 0 {
+		config.RowGroupSize = 65_536
+	}
+	if config.MaxOpenFiles <= 0 {
+		config.MaxOpenFiles = 16
+	}
+	compression := compress.Codecs.Zstd
+	if config.Compression != nil {
+		compression = *config.Compression
+	}
+
+	return &Exporter{
+		config: config,
+		props: parquet.NewWriterProperties(
+			parquet.WithCompression(compression),
+			parquet.WithMaxRowGroupLength(int64(config.RowGroupSize)),
+		),
+		run:   strconv.FormatInt(time.Now().UnixNano(), 10),
+		files: make(map[fileKey]*partFile),
+	}, nil
+}
+
+// Write appends the events to the files of their partitions, writing a row group whenever
+// a file has RowGroupSize rows buffered
+func (e *Exporter) Write(ctx context.Context, events []*sink.Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, event := range events {
+		t := tableFor(event)
+		if t == nil {
+			continue
+		}
+
+		f, err := e.file(fileKey{table: t, partition: e.partition(event)})
+		if err != nil {
+			return err
+		}
+		t.append(f.builder, event)
+		f.rows++
+		if f.rows >= e.config.RowGroupSize {
+			if err := f.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Flush finishes every open file so that all events written so far are readable
+func (e *Exporter) Flush() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.closeAll()
+}
+
+// Close finishes every open file
+func (e *Exporter) Close() error {
+	return e.Flush()
+}
+
+// closeAll finishes every open file, keeping the first error
+func (e *Exporter) closeAll() error {
+	var errs []error
+	for key, f := range e.files {
+		errs = append(errs, f.close())
+		delete(e.files, key)
+	}
+	return errors.Join(errs...)
+}
+
+// partition returns the partition directory of an event
+func (e *Exporter) partition(event *sink.Event) string {
+	if e.config.PartitionBy == PartitionBySlot {
+		start := event.Slot / e.config.SlotsPerPartition * e.config.SlotsPerPartition
+		return "slot=" + strconv.FormatUint(start, 10)
+	}
+	if event.BlockTime == nil {
+		return "date=" + unknownPartition
+	}
+	return "date=" + event.BlockTime.Time().UTC().Format(time.DateOnly)
+}
+
+// file returns the open file of a partition, starting a new one if needed
+func (e *Exporter) file(key fileKey) (*partFile, error) {
+	e.tick++
+	if f, ok := e.files[key]; ok {
+		f.used = e.tick
+		return f, nil
+	}
+
+	if len(e.files) >= e.config.MaxOpenFiles {
+		if err := e.closeOldest(); err != nil {
+			return nil, err
+		}
+	}
+
+	dir := filepath.Join(e.config.Dir, key.table.name, key.partition)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create partition %s: %w", dir, err)
+	}
+
+	e.seq++
+	path := filepath.Join(dir, fmt.Sprintf("part-%s-%05d.parquet", e.run, e.seq))
+	out, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	writer, err := pqarrow.NewFileWriter(key.table.schema, out, e.props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return nil, fmt.Errorf("failed to start %s: %w", path, err)
+	}
+
+	f := &partFile{
+		path:    path,
+		writer:  writer,
+		builder: array.NewRecordBuilder(memory.DefaultAllocator, key.table.schema),
+		used:    e.tick,
+	}
+	e.files[key] = f
+	return f, nil
+}
+
+// closeOldest finishes the least recently written file
+func (e *Exporter) closeOldest() error {
+	var oldest fileKey
+	var oldestFile *partFile
+	for key, f := range e.files {
+		if oldestFile == nil || f.used < oldestFile.used {
+			oldest, oldestFile = key, f
+		}
+	}
+	delete(e.files, oldest)
+	return oldestFile.close()
+}
+
+// flush writes the buffered rows as a row group
+func (f *partFile) flush() error {
+	if f.rows == 0 {
+		return nil
+	}
+	record := f.builder.NewRecord()
+	defer record.Release()
+	f.rows = 0
+
+	if err := f.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write row group to %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// close writes the remaining rows and the footer and moves the file into place
+func (f *partFile) close() error {
+	defer f.builder.Release()
+
+	flushErr := f.flush()
+	if err := f.writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish %s: %w", f.path, err)
+	}
+	if flushErr != nil {
+		return flushErr
+	}
+	if err := os.Rename(f.path+".tmp", f.path); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", f.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package parquet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func testEvents(slot uint64, blockTime *solana.UnixTimeSeconds, swaps int) []*sink.Event {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	tx := &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{byte(slot)},
+		Slot:      slot,
+		BlockTime: blockTime,
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeToken, Mint: token, SourceOwner: wallet, Amount: 5, Decimals: 6, InnerIndex: -1},
+		},
+		StakeEvents: []*tx_parser.StakeEvent{{Type: tx_parser.StakeEventDelegate}},
+	}
+	for range swaps {
+		tx.Swaps = append(tx.Swaps, &tx_parser.SwapInfo{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut: tx_parser.TokenInfo{Mint: token, Amount: 18_446_744_073_709_551_615, Decimals: 6},
+		})
+	}
+	return sink.Events(tx)
+}
+
+func readRows(t *testing.T, pattern string) (files int, rows int64, amounts []uint64) {
+	t.Helper()
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		table, err := pqarrow.ReadTable(context.Background(), f, nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+		f.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		rows += table.NumRows()
+		if idx := table.Schema().FieldIndices("amount_out"); len(idx) == 1 {
+			for _, chunk := range table.Column(idx[0]).Data().Chunks() {
+				amounts = append(amounts, chunk.(*array.Uint64).Values()...)
+			}
+		}
+		table.Release()
+	}
+	return len(paths), rows, amounts
+}
+
+func TestExportByDate(t *testing.T) {
+	dir := t.TempDir()
+	e, err := New(Config{Dir: dir, RowGroupSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day1 := solana.UnixTimeSeconds(1_700_000_000)
+	day2 := solana.UnixTimeSeconds(1_700_100_000)
+	ctx := context.Background()
+	for _, events := range [][]*sink.Event{testEvents(1, &day1, 3), testEvents(2, &day2, 1), testEvents(3, nil, 1)} {
+		if err := e.Write(ctx, events); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	files, rows, amounts := readRows(t, filepath.Join(dir, "swaps", "date=2023-11-14", "*.parquet"))
+	if files != 1 || rows != 3 {
+		t.Errorf("expected 3 swaps in one file for 2023-11-14, got %d rows in %d files", rows, files)
+	}
+	if len(amounts) != 3 || amounts[0] != 18_446_744_073_709_551_615 {
+		t.Errorf("expected full uint64 amounts, got %v", amounts)
+	}
+	if _, rows, _ := readRows(t, filepath.Join(dir, "swaps", "date=2023-11-16", "*.parquet")); rows != 1 {
+		t.Errorf("expected 1 swap for 2023-11-16, got %d", rows)
+	}
+	if _, rows, _ := readRows(t, filepath.Join(dir, "swaps", "date="+unknownPartition, "*.parquet")); rows != 1 {
+		t.Errorf("expected 1 swap without a block time, got %d", rows)
+	}
+	if _, rows, _ := readRows(t, filepath.Join(dir, "transfers", "*", "*.parquet")); rows != 3 {
+		t.Errorf("expected 3 transfers, got %d", rows)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*", "*", "*.tmp")); len(tmp) != 0 {
+		t.Errorf("expected no temporary files, got %v", tmp)
+	}
+}
+
+func TestExportBySlot(t *testing.T) {
+	dir := t.TempDir()
+	e, err := New(Config{Dir: dir, PartitionBy: PartitionBySlot, SlotsPerPartition: 100, MaxOpenFiles: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, slot := range []uint64{150, 250, 199} {
+		if err := e.Write(ctx, testEvents(slot, nil, 1)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	if files, rows, _ := readRows(t, filepath.Join(dir, "swaps", "slot=100", "*.parquet")); files != 2 || rows != 2 {
+		t.Errorf("expected 2 swaps in 2 files after eviction for slot=100, got %d rows in %d files", rows, files)
+	}
+	if _, rows, _ := readRows(t, filepath.Join(dir, "swaps", "slot=200", "*.parquet")); rows != 1 {
+		t.Errorf("expected 1 swap for slot=200, got %d", rows)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	e, err := New(Config{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.props.Compression() != compress.Codecs.Zstd {
+		t.Errorf("expected zstd by default, got %s", e.props.Compression())
+	}
+
+	dir := t.TempDir()
+	uncompressed := compress.Codecs.Uncompressed
+	if e, err = New(Config{Dir: dir, Compression: &uncompressed}); err != nil {
+		t.Fatal(err)
+	}
+	if e.props.Compression() != compress.Codecs.Uncompressed {
+		t.Errorf("expected uncompressed files, got %s", e.props.Compression())
+	}
+	if err := e.Write(context.Background(), testEvents(1, nil, 1)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+	if _, rows, _ := readRows(t, filepath.Join(dir, "swaps", "*", "*.parquet")); rows != 1 {
+		t.Errorf("expected 1 uncompressed swap, got %d", rows)
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("expected an error without a directory")
+	}
+	if _, err := New(Config{Dir: t.TempDir(), PartitionBy: "hour"}); err == nil {
+		t.Error("expected an error for unknown partitioning")
+	}
+}
@@ -0,0 +1,108 @@
+package parquet
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// table maps one event kind to a Parquet schema
+type table struct {
+	name   string
+	schema *arrow.Schema
+	append func(b *array.RecordBuilder, event *sink.Event)
+}
+
+var swapsTable = &table{
+	name: "swaps",
+	schema: arrow.NewSchema([]arrow.Field{
+		{Name: "signature", Type: arrow.BinaryTypes.String},
+		{Name: "idx", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "slot", Type: arrow.PrimitiveTypes.Uint64},
+		{Name: "block_time", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+		{Name: "protocol", Type: arrow.BinaryTypes.String},
+		{Name: "wallet", Type: arrow.BinaryTypes.String},
+		{Name: "mint_in", Type: arrow.BinaryTypes.String},
+		{Name: "amount_in", Type: arrow.PrimitiveTypes.Uint64},
+		{Name: "decimals_in", Type: arrow.PrimitiveTypes.Uint8},
+		{Name: "mint_out", Type: arrow.BinaryTypes.String},
+		{Name: "amount_out", Type: arrow.PrimitiveTypes.Uint64},
+		{Name: "decimals_out", Type: arrow.PrimitiveTypes.Uint8},
+		{Name: "instruction_index", Type: arrow.PrimitiveTypes.Int32},
+	}, nil),
+	append: func(b *array.RecordBuilder, event *sink.Event) {
+		swap := event.Swap
+		appendEnvelope(b, event)
+		b.Field(4).(*array.StringBuilder).Append(string(swap.Protocol))
+		b.Field(5).(*array.StringBuilder).Append(event.Wallet().String())
+		b.Field(6).(*array.StringBuilder).Append(swap.TokenIn.Mint.String())
+		b.Field(7).(*array.Uint64Builder).Append(swap.TokenIn.Amount)
+		b.Field(8).(*array.Uint8Builder).Append(swap.TokenIn.Decimals)
+		b.Field(9).(*array.StringBuilder).Append(swap.TokenOut.Mint.String())
+		b.Field(10).(*array.Uint64Builder).Append(swap.TokenOut.Amount)
+		b.Field(11).(*array.Uint8Builder).Append(swap.TokenOut.Decimals)
+		b.Field(12).(*array.Int32Builder).Append(int32(swap.InstructionIndex))
+	},
+}
+
+var transfersTable = &table{
+	name: "transfers",
+	schema: arrow.NewSchema([]arrow.Field{
+		{Name: "signature", Type: arrow.BinaryTypes.String},
+		{Name: "idx", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "slot", Type: arrow.PrimitiveTypes.Uint64},
+		{Name: "block_time", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+		{Name: "type", Type: arrow.BinaryTypes.String},
+		{Name: "program", Type: arrow.BinaryTypes.String},
+		{Name: "mint", Type: arrow.BinaryTypes.String},
+		{Name: "source", Type: arrow.BinaryTypes.String},
+		{Name: "destination", Type: arrow.BinaryTypes.String},
+		{Name: "source_owner", Type: arrow.BinaryTypes.String},
+		{Name: "destination_owner", Type: arrow.BinaryTypes.String},
+		{Name: "authority", Type: arrow.BinaryTypes.String},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Uint64},
+		{Name: "decimals", Type: arrow.PrimitiveTypes.Uint8},
+		{Name: "instruction_index", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "inner_index", Type: arrow.PrimitiveTypes.Int32},
+	}, nil),
+	append: func(b *array.RecordBuilder, event *sink.Event) {
+		transfer := event.Transfer
+		appendEnvelope(b, event)
+		b.Field(4).(*array.StringBuilder).Append(string(transfer.Type))
+		for i, key := range []solana.PublicKey{
+			transfer.Program, transfer.Mint, transfer.Source, transfer.Destination,
+			transfer.SourceOwner, transfer.DestinationOwner, transfer.Authority,
+		} {
+			b.Field(5 + i).(*array.StringBuilder).Append(key.String())
+		}
+		b.Field(12).(*array.Uint64Builder).Append(transfer.Amount)
+		b.Field(13).(*array.Uint8Builder).Append(transfer.Decimals)
+		b.Field(14).(*array.Int32Builder).Append(int32(transfer.InstructionIndex))
+		b.Field(15).(*array.Int32Builder).Append(int32(transfer.InnerIndex))
+	},
+}
+
+// tableFor returns the table an event is exported to, nil for kinds that are not exported
+func tableFor(event *sink.Event) *table {
+	switch {
+	case event.Swap != nil:
+		return swapsTable
+	case event.Transfer != nil:
+		return transfersTable
+	}
+	return nil
+}
+
+// appendEnvelope appends the columns every table starts with
+func appendEnvelope(b *array.RecordBuilder, event *sink.Event) {
+	b.Field(0).(*array.StringBuilder).Append(event.Signature.String())
+	b.Field(1).(*array.Int32Builder).Append(int32(event.Index))
+	b.Field(2).(*array.Uint64Builder).Append(event.Slot)
+	if event.BlockTime != nil {
+		b.Field(3).(*array.TimestampBuilder).Append(arrow.Timestamp(*event.BlockTime))
+	} else {
+		b.Field(3).AppendNull()
+	}
+}
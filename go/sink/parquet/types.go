@@ -0,0 +1,37 @@
+package parquet
+
+import "github.com/apache/arrow-go/v18/parquet/compress"
+
+// Partition selects how files are split into directories
+type Partition string
+
+const (
+	// PartitionByDate writes to <table>/date=YYYY-MM-DD by block time
+	PartitionByDate Partition = "date"
+	// PartitionBySlot writes to <table>/slot=N where N is the first slot of the range
+	PartitionBySlot Partition = "slot"
+)
+
+// Config controls the Parquet exporter
+type Config struct {
+	// Dir is the root directory, one subdirectory per table (swaps, transfers)
+	Dir string
+
+	// PartitionBy defaults to PartitionByDate
+	PartitionBy Partition
+
+	// SlotsPerPartition is the width of slot partitions, defaults to one epoch (432000)
+	SlotsPerPartition uint64
+
+	// RowGroupSize is the number of rows buffered per file before a row group is written,
+	// defaults to 65536
+	RowGroupSize int
+
+	// MaxOpenFiles bounds the partitions written at once, the least recently used file is
+	// finished when a new one is needed. Defaults to 16.
+	MaxOpenFiles int
+
+	// Compression defaults to zstd when nil, point it at compress.Codecs.Uncompressed to
+	// write uncompressed files
+	Compression *compress.Compression
+}
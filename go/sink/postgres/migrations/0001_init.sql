@@ -0,0 +1,61 @@
+CREATE TABLE IF NOT EXISTS tokens (
+    mint            TEXT PRIMARY KEY,
+    decimals        SMALLINT NOT NULL,
+    first_seen_slot BIGINT NOT NULL,
+    last_seen_slot  BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pools (
+    address         TEXT PRIMARY KEY,
+    protocol        TEXT NOT NULL,
+    mint_a          TEXT NOT NULL,
+    mint_b          TEXT NOT NULL,
+    first_seen_slot BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS swaps (
+    signature         TEXT NOT NULL,
+    idx               INTEGER NOT NULL,
+    slot              BIGINT NOT NULL,
+    block_time        TIMESTAMPTZ,
+    protocol          TEXT NOT NULL,
+    wallet            TEXT NOT NULL,
+    mint_in           TEXT NOT NULL,
+    amount_in         NUMERIC(20, 0) NOT NULL,
+    decimals_in       SMALLINT NOT NULL,
+    mint_out          TEXT NOT NULL,
+    amount_out        NUMERIC(20, 0) NOT NULL,
+    decimals_out      SMALLINT NOT NULL,
+    instruction_index INTEGER NOT NULL,
+    PRIMARY KEY (signature, idx)
+);
+
+CREATE INDEX IF NOT EXISTS swaps_slot_idx ON swaps (slot);
+CREATE INDEX IF NOT EXISTS swaps_wallet_idx ON swaps (wallet, slot);
+CREATE INDEX IF NOT EXISTS swaps_mint_in_idx ON swaps (mint_in, slot);
+CREATE INDEX IF NOT EXISTS swaps_mint_out_idx ON swaps (mint_out, slot);
+
+CREATE TABLE IF NOT EXISTS transfers (
+    signature         TEXT NOT NULL,
+    idx               INTEGER NOT NULL,
+    slot              BIGINT NOT NULL,
+    block_time        TIMESTAMPTZ,
+    type              TEXT NOT NULL,
+    program           TEXT NOT NULL,
+    mint              TEXT NOT NULL,
+    source            TEXT NOT NULL,
+    destination       TEXT NOT NULL,
+    source_owner      TEXT NOT NULL,
+    destination_owner TEXT NOT NULL,
+    authority         TEXT NOT NULL,
+    amount            NUMERIC(20, 0) NOT NULL,
+    decimals          SMALLINT NOT NULL,
+    instruction_index INTEGER NOT NULL,
+    inner_index       INTEGER NOT NULL,
+    PRIMARY KEY (signature, idx)
+);
+
+CREATE INDEX IF NOT EXISTS transfers_slot_idx ON transfers (slot);
+CREATE INDEX IF NOT EXISTS transfers_source_owner_idx ON transfers (source_owner, slot);
+CREATE INDEX IF NOT EXISTS transfers_destination_owner_idx ON transfers (destination_owner, slot);
+CREATE INDEX IF NOT EXISTS transfers_mint_idx ON transfers (mint, slot);
@@ -0,0 +1,311 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lib/pq"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+var (
+	swapColumns = []string{
+		"signature", "idx", "slot", "block_time", "protocol", "wallet",
+		"mint_in", "amount_in", "decimals_in", "mint_out", "amount_out", "decimals_out", "instruction_index",
+	}
+	transferColumns = []string{
+		"signature", "idx", "slot", "block_time", "type", "program", "mint", "source", "destination",
+		"source_owner", "destination_owner", "authority", "amount", "decimals", "instruction_index", "inner_index",
+	}
+	tokenColumns = []string{"mint", "decimals", "first_seen_slot", "last_seen_slot"}
+	poolColumns  = []string{"address", "protocol", "mint_a", "mint_b", "first_seen_slot"}
+)
+
+// Pool is a liquidity pool row
+type Pool struct {
+	Address       solana.PublicKey
+	Protocol      tx_parser.SwapType
+	MintA         solana.PublicKey
+	MintB         solana.PublicKey
+	FirstSeenSlot uint64
+}
+
+// Sink writes swaps, transfers and pool creations to PostgreSQL and keeps the tokens
+// table up to date. Other event kinds are ignored.
+type Sink struct {
+	db     *sql.DB
+	ownsDB bool
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// Open connects to the database, applies pending migrations and returns a sink that
+// closes the connection on Close
+func Open(ctx context.Context, dsn string) (*Sink, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := New(db)
+	s.ownsDB = true
+	return s, nil
+}
+
+// New creates a sink over an existing lib/pq connection pool. Call Migrate first to
+// create the schema.
+func New(db *sql.DB) *Sink {
+	return &Sink{db: db}
+}
+
+// Write inserts the events in a single transaction. Rows are bulk loaded with COPY into
+// temporary staging tables and then merged, so events that were already written are skipped.
+// Callers should batch events, e.g. per block, to make use of COPY.
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	var swaps, transfers, pools [][]any
+	tokens := newTokenSet()
+	for _, event := range events {
+		switch {
+		case event.Swap != nil:
+			swaps = append(swaps, swapRow(event))
+			tokens.add(event.Swap.TokenIn.Mint, event.Swap.TokenIn.Decimals, event.Slot)
+			tokens.add(event.Swap.TokenOut.Mint, event.Swap.TokenOut.Decimals, event.Slot)
+		case event.Transfer != nil:
+			transfers = append(transfers, transferRow(event))
+			tokens.add(event.Transfer.Mint, event.Transfer.Decimals, event.Slot)
+		case event.PoolCreated != nil:
+			created := event.PoolCreated
+			pools = append(pools, poolRow(Pool{
+				Address:       created.Pool,
+				Protocol:      created.Protocol,
+				MintA:         created.MintA,
+				MintB:         created.MintB,
+				FirstSeenSlot: event.Slot,
+			}))
+			tokens.addResolved(created.MintA, created.DecimalsA, event.Slot)
+			tokens.addResolved(created.MintB, created.DecimalsB, event.Slot)
+		}
+	}
+	if len(swaps) == 0 && len(transfers) == 0 && len(pools) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := copyRows(ctx, tx, "swaps", swapColumns, swaps, "ON CONFLICT DO NOTHING"); err != nil {
+		return err
+	}
+	if err := copyRows(ctx, tx, "transfers", transferColumns, transfers, "ON CONFLICT DO NOTHING"); err != nil {
+		return err
+	}
+	if err := copyRows(ctx, tx, "pools", poolColumns, pools, "ON CONFLICT DO NOTHING"); err != nil {
+		return err
+	}
+	if err := copyRows(ctx, tx, "tokens", tokenColumns, tokens.rows(), `ON CONFLICT (mint) DO UPDATE SET
+		first_seen_slot = LEAST(tokens.first_seen_slot, EXCLUDED.first_seen_slot),
+		last_seen_slot = GREATEST(tokens.last_seen_slot, EXCLUDED.last_seen_slot)`); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit events: %w", err)
+	}
+	return nil
+}
+
+// WritePools inserts pools that are not known yet
+func (s *Sink) WritePools(ctx context.Context, pools []Pool) error {
+	rows := make([][]any, 0, len(pools))
+	for _, pool := range pools {
+		rows = append(rows, poolRow(pool))
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := copyRows(ctx, tx, "pools", poolColumns, rows, "ON CONFLICT DO NOTHING"); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit pools: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database if the sink opened it
+func (s *Sink) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// copyRows bulk loads rows into a staging copy of the table and merges them with the
+// given conflict clause
+func copyRows(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any, conflict string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	staging := table + "_staging"
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`CREATE TEMP TABLE IF NOT EXISTS %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DELETE ROWS`,
+		pq.QuoteIdentifier(staging), pq.QuoteIdentifier(table))); err != nil {
+		return fmt.Errorf("failed to create staging table for %s: %w", table, err)
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+	}
+	columnList := strings.Join(quoted, ", ")
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`COPY %s (%s) FROM STDIN`, pq.QuoteIdentifier(staging), columnList))
+	if err != nil {
+		return fmt.Errorf("failed to start copy into %s: %w", table, err)
+	}
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy row into %s: %w", table, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to finish copy into %s: %w", table, err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to finish copy into %s: %w", table, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s %s`,
+		pq.QuoteIdentifier(table), columnList, columnList, pq.QuoteIdentifier(staging), conflict)); err != nil {
+		return fmt.Errorf("failed to merge %s: %w", table, err)
+	}
+	return nil
+}
+
+// swapRow converts a swap event into a row of the swaps table
+func swapRow(event *sink.Event) []any {
+	swap := event.Swap
+	return []any{
+		event.Signature.String(),
+		event.Index,
+		int64(event.Slot),
+		blockTime(event.BlockTime),
+		string(swap.Protocol),
+		event.Wallet().String(),
+		swap.TokenIn.Mint.String(),
+		strconv.FormatUint(swap.TokenIn.Amount, 10),
+		int(swap.TokenIn.Decimals),
+		swap.TokenOut.Mint.String(),
+		strconv.FormatUint(swap.TokenOut.Amount, 10),
+		int(swap.TokenOut.Decimals),
+		swap.InstructionIndex,
+	}
+}
+
+// transferRow converts a transfer event into a row of the transfers table
+func transferRow(event *sink.Event) []any {
+	transfer := event.Transfer
+	return []any{
+		event.Signature.String(),
+		event.Index,
+		int64(event.Slot),
+		blockTime(event.BlockTime),
+		string(transfer.Type),
+		transfer.Program.String(),
+		transfer.Mint.String(),
+		transfer.Source.String(),
+		transfer.Destination.String(),
+		transfer.SourceOwner.String(),
+		transfer.DestinationOwner.String(),
+		transfer.Authority.String(),
+		strconv.FormatUint(transfer.Amount, 10),
+		int(transfer.Decimals),
+		transfer.InstructionIndex,
+		transfer.InnerIndex,
+	}
+}
+
+// poolRow converts a pool into a row of the pools table
+func poolRow(pool Pool) []any {
+	return []any{pool.Address.String(), string(pool.Protocol), pool.MintA.String(), pool.MintB.String(), int64(pool.FirstSeenSlot)}
+}
+
+// blockTime converts an optional block time into a nullable timestamp
+func blockTime(t *solana.UnixTimeSeconds) any {
+	if t == nil {
+		return nil
+	}
+	return t.Time().UTC()
+}
+
+// tokenSet collects the mints seen in a batch with the slots they were seen at
+type tokenSet struct {
+	order  []solana.PublicKey
+	tokens map[solana.PublicKey]*tokenSeen
+}
+
+type tokenSeen struct {
+	decimals    uint8
+	first, last uint64
+}
+
+func newTokenSet() *tokenSet {
+	return &tokenSet{tokens: make(map[solana.PublicKey]*tokenSeen)}
+}
+
+// add records the mint, ignoring the zero key of unresolved mints
+func (s *tokenSet) add(mint solana.PublicKey, decimals uint8, slot uint64) {
+	if mint.IsZero() {
+		return
+	}
+	seen, ok := s.tokens[mint]
+	if !ok {
+		s.tokens[mint] = &tokenSeen{decimals: decimals, first: slot, last: slot}
+		s.order = append(s.order, mint)
+		return
+	}
+	seen.first = min(seen.first, slot)
+	seen.last = max(seen.last, slot)
+}
+
+// addResolved records the mint only if its decimals are known, pool creations leave the
+// decimals of mints missing from the transaction balances at 0
+func (s *tokenSet) addResolved(mint solana.PublicKey, decimals uint8, slot uint64) {
+	if decimals == 0 {
+		return
+	}
+	s.add(mint, decimals, slot)
+}
+
+// rows returns one row per mint of the tokens table
+func (s *tokenSet) rows() [][]any {
+	rows := make([][]any, 0, len(s.order))
+	for _, mint := range s.order {
+		seen := s.tokens[mint]
+		rows = append(rows, []any{mint.String(), int(seen.decimals), int64(seen.first), int64(seen.last)})
+	}
+	return rows
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func testTransaction() *tx_parser.ParsedTransaction {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	return &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{4},
+		Slot:      300,
+		BlockTime: &blockTime,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut: tx_parser.TokenInfo{Mint: token, Amount: 18_446_744_073_709_551_615, Decimals: 6},
+		}},
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeToken, Mint: token, SourceOwner: wallet, Amount: 5, Decimals: 6, InnerIndex: -1},
+		},
+		StakeEvents: []*tx_parser.StakeEvent{{Type: tx_parser.StakeEventDelegate}},
+		PoolCreations: []*tx_parser.PoolCreatedEvent{
+			{Protocol: tx_parser.SwapTypePumpFun, Pool: wallet, Creator: wallet, MintA: token, MintB: tx_parser.NATIVE_SOL_PROGRAM_ID, DecimalsA: 6, DecimalsB: 9},
+		},
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].version != 1 {
+		t.Fatalf("expected migrations starting at version 1, got %+v", migrations)
+	}
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version <= migrations[i-1].version {
+			t.Errorf("migrations out of order: %s after %s", migrations[i].name, migrations[i-1].name)
+		}
+	}
+	for _, table := range []string{"swaps", "transfers", "pools", "tokens"} {
+		if !strings.Contains(migrations[0].sql, "CREATE TABLE IF NOT EXISTS "+table+" ") {
+			t.Errorf("expected the initial migration to create %s", table)
+		}
+	}
+}
+
+func TestRows(t *testing.T) {
+	events := sink.Events(testTransaction())
+
+	swap := swapRow(events[0])
+	if len(swap) != len(swapColumns) {
+		t.Fatalf("swap row has %d values for %d columns", len(swap), len(swapColumns))
+	}
+	if swap[10] != "18446744073709551615" {
+		t.Errorf("expected the full uint64 amount as text, got %v", swap[10])
+	}
+	if swap[3] == nil {
+		t.Error("expected a block time")
+	}
+
+	transfer := transferRow(events[1])
+	if len(transfer) != len(transferColumns) {
+		t.Fatalf("transfer row has %d values for %d columns", len(transfer), len(transferColumns))
+	}
+	if transfer[15] != -1 {
+		t.Errorf("expected inner index -1, got %v", transfer[15])
+	}
+
+	created := events[3].PoolCreated
+	pool := poolRow(Pool{Address: created.Pool, Protocol: created.Protocol, MintA: created.MintA, MintB: created.MintB, FirstSeenSlot: events[3].Slot})
+	if len(pool) != len(poolColumns) || pool[1] != "PumpFun" || pool[4] != int64(300) {
+		t.Errorf("unexpected pool row %v", pool)
+	}
+}
+
+func TestTokenSet(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	tokens := newTokenSet()
+	tokens.add(mint, 6, 20)
+	tokens.add(mint, 6, 10)
+	tokens.add(solana.PublicKey{}, 0, 15)
+	tokens.addResolved(solana.NewWallet().PublicKey(), 0, 15)
+
+	rows := tokens.rows()
+	if len(rows) != 1 {
+		t.Fatalf("expected one token, got %d", len(rows))
+	}
+	if rows[0][2] != int64(10) || rows[0][3] != int64(20) {
+		t.Errorf("expected slots 10-20, got %v", rows[0])
+	}
+}
+
+// TestSinkIntegration runs against a real database when SOLANA_TOOLKIT_POSTGRES_URL is set
+func TestSinkIntegration(t *testing.T) {
+	dsn := os.Getenv("SOLANA_TOOLKIT_POSTGRES_URL")
+	if dsn == "" {
+		t.Skip("SOLANA_TOOLKIT_POSTGRES_URL not set")
+	}
+
+	ctx := context.Background()
+	s, err := Open(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to open sink: %v", err)
+	}
+	defer s.Close()
+
+	events := sink.Events(testTransaction())
+	for range 2 {
+		if err := s.Write(ctx, events); err != nil {
+			t.Fatalf("failed to write events: %v", err)
+		}
+	}
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM swaps WHERE signature = $1`, events[0].Signature.String()).Scan(&count); err != nil {
+		t.Fatalf("failed to count swaps: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected rewriting events to be idempotent, got %d swaps", count)
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM pools WHERE address = $1`, events[3].PoolCreated.Pool.String()).Scan(&count); err != nil {
+		t.Fatalf("failed to count pools: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected the created pool to be written, got %d pools", count)
+	}
+}
@@ -0,0 +1,228 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Sink writes parsed events to external storage or a message bus
+type Sink interface {
+	// Write delivers a batch of events, returning once the sink's delivery guarantee is met
+	Write(ctx context.Context, events []*Event) error
+
+	// Close flushes pending events and releases the sink's resources
+	Close() error
+}
+
+// Encoder serializes events for sinks that store raw bytes
+type Encoder interface {
+	Encode(event *Event) ([]byte, error)
+
+	// ContentType describes the encoding, e.g. application/json
+	ContentType() string
+}
+
+// JSONEncoder encodes events as JSON
+type JSONEncoder struct{}
+
+// Encode marshals the event as JSON
+func (JSONEncoder) Encode(event *Event) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return data, nil
+}
+
+// ContentType returns application/json
+func (JSONEncoder) ContentType() string {
+	return "application/json"
+}
+
+// Events flattens a parsed transaction into one event per swap, transfer, stake, token event,
+// pool creation, perp fill, compressed NFT event, NFT mint, domain event and bridge transfer
+func Events(tx *tx_parser.ParsedTransaction) []*Event {
+	var events []*Event
+	newEvent := func(kind Kind, index int) *Event {
+		event := &Event{
+			Kind:      kind,
+			Signature: tx.Signature,
+			Slot:      tx.Slot,
+			BlockTime: tx.BlockTime,
+			Index:     index,
+		}
+		events = append(events, event)
+		return event
+	}
+
+	for i, swap := range tx.Swaps {
+		newEvent(KindSwap, i).Swap = swap
+	}
+	for i, transfer := range tx.Transfers {
+		newEvent(KindTransfer, i).Transfer = transfer
+	}
+	for i, stake := range tx.StakeEvents {
+		newEvent(KindStake, i).Stake = stake
+	}
+	for i, supply := range tx.SupplyEvents {
+		newEvent(KindTokenSupply, i).TokenSupply = supply
+	}
+	for i, admin := range tx.AdminEvents {
+		newEvent(KindTokenAdmin, i).TokenAdmin = admin
+	}
+	for i, created := range tx.PoolCreations {
+		newEvent(KindPoolCreated, i).PoolCreated = created
+	}
+	for i, fill := range tx.PerpFills {
+		newEvent(KindPerpFill, i).PerpFill = fill
+	}
+	for i, nft := range tx.CompressedNfts {
+		newEvent(KindCompressedNft, i).CompressedNft = nft
+	}
+	for i, minted := range tx.NftMints {
+		newEvent(KindNftMint, i).NftMint = minted
+	}
+	for i, domain := range tx.DomainEvents {
+		newEvent(KindDomain, i).Domain = domain
+	}
+	for i, bridge := range tx.Bridges {
+		newEvent(KindBridge, i).Bridge = bridge
+	}
+
+	return events
+}
+
+// MarshalJSON encodes the event with the schema version of the parsed types
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		event
+	}{tx_parser.SCHEMA_VERSION, event(e)})
+}
+
+// UnmarshalJSON decodes an event, rejecting schema versions newer than the parser's
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	var decoded struct {
+		SchemaVersion int `json:"schema_version"`
+		*event
+	}
+	decoded.event = (*event)(e)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.SchemaVersion > tx_parser.SCHEMA_VERSION {
+		return fmt.Errorf("unsupported schema version %d, expected at most %d", decoded.SchemaVersion, tx_parser.SCHEMA_VERSION)
+	}
+	return nil
+}
+
+// ID uniquely identifies the event, e.g. for idempotent writes
+func (e *Event) ID() string {
+	if e.Balance != nil {
+		// balances have no transaction, a wallet has one per slot
+		return fmt.Sprintf("%s:%s:%d", e.Balance.Wallet, e.Kind, e.Slot)
+	}
+	return fmt.Sprintf("%s:%s:%d", e.Signature, e.Kind, e.Index)
+}
+
+// Mint returns the token the event is about. For swaps this is the side that is not
+// wrapped SOL, so swaps of a token in either direction share the same mint.
+func (e *Event) Mint() solana.PublicKey {
+	switch {
+	case e.Swap != nil:
+		if e.Swap.TokenIn.Mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+			return e.Swap.TokenOut.Mint
+		}
+		return e.Swap.TokenIn.Mint
+	case e.Transfer != nil:
+		return e.Transfer.Mint
+	case e.Stake != nil:
+		return e.Stake.PoolMint
+	case e.TokenSupply != nil:
+		return e.TokenSupply.Mint
+	case e.TokenAdmin != nil:
+		return e.TokenAdmin.Mint
+	case e.PoolCreated != nil:
+		return e.PoolCreated.MintA
+	case e.CompressedNft != nil:
+		return e.CompressedNft.AssetID
+	case e.NftMint != nil:
+		return e.NftMint.Mint
+	case e.Bridge != nil:
+		return e.Bridge.Mint
+	}
+	return solana.PublicKey{}
+}
+
+// Wallet returns the wallet that initiated the event
+func (e *Event) Wallet() solana.PublicKey {
+	switch {
+	case e.Swap != nil:
+		if len(e.Swap.Signers) > 0 {
+			return e.Swap.Signers[0]
+		}
+	case e.Transfer != nil:
+		return e.Transfer.SourceOwner
+	case e.Stake != nil:
+		return e.Stake.Authority
+	case e.TokenSupply != nil:
+		if !e.TokenSupply.Owner.IsZero() {
+			return e.TokenSupply.Owner
+		}
+		return e.TokenSupply.Authority
+	case e.TokenAdmin != nil:
+		return e.TokenAdmin.Authority
+	case e.PoolCreated != nil:
+		return e.PoolCreated.Creator
+	case e.PerpFill != nil:
+		return e.PerpFill.Trader
+	case e.CompressedNft != nil:
+		return e.CompressedNft.Owner
+	case e.NftMint != nil:
+		return e.NftMint.Minter
+	case e.Domain != nil:
+		return e.Domain.Owner
+	case e.Bridge != nil:
+		return e.Bridge.Wallet
+	case e.Balance != nil:
+		return e.Balance.Wallet
+	}
+	return solana.PublicKey{}
+}
+
+// InstructionIndex returns the index of the outer instruction the event was parsed from,
+// -1 if the event carries none
+func (e *Event) InstructionIndex() int {
+	switch {
+	case e.Swap != nil:
+		return e.Swap.InstructionIndex
+	case e.Transfer != nil:
+		return e.Transfer.InstructionIndex
+	case e.Stake != nil:
+		return e.Stake.InstructionIndex
+	case e.TokenSupply != nil:
+		return e.TokenSupply.InstructionIndex
+	case e.TokenAdmin != nil:
+		return e.TokenAdmin.InstructionIndex
+	case e.PoolCreated != nil:
+		return e.PoolCreated.InstructionIndex
+	case e.PerpFill != nil:
+		return e.PerpFill.InstructionIndex
+	case e.CompressedNft != nil:
+		return e.CompressedNft.InstructionIndex
+	case e.NftMint != nil:
+		return e.NftMint.InstructionIndex
+	case e.Domain != nil:
+		return e.Domain.InstructionIndex
+	case e.Bridge != nil:
+		return e.Bridge.InstructionIndex
+	}
+	return -1
+}
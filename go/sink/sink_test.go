@@ -0,0 +1,95 @@
+package sink
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+func TestEventsFlattensTransaction(t *testing.T) {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	tx := &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{9},
+		Slot:      77,
+		Swaps: []*tx_parser.SwapInfo{{
+			Signers:  []solana.PublicKey{wallet},
+			TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1},
+			TokenOut: tx_parser.TokenInfo{Mint: token, Amount: 2},
+		}},
+		Transfers: []*tx_parser.TransferInfo{
+			{Mint: token, SourceOwner: wallet},
+			{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, SourceOwner: wallet},
+		},
+		SupplyEvents:   []*tx_parser.TokenSupplyEvent{{Mint: token, Authority: wallet}},
+		PerpFills:      []*tx_parser.PerpFillInfo{{Type: tx_parser.PerpFillTrade, Trader: wallet}},
+		CompressedNfts: []*tx_parser.CompressedNftEvent{{Type: tx_parser.CompressedNftTransfer, AssetID: token, Owner: wallet}},
+		NftMints:       []*tx_parser.NftMintEvent{{Source: tx_parser.NftMintMetaplexCore, Mint: token, Minter: wallet}},
+		DomainEvents:   []*tx_parser.DomainEvent{{Type: tx_parser.DomainRegister, Domain: token, Owner: wallet}},
+		Bridges:        []*tx_parser.BridgeEvent{{Protocol: tx_parser.BridgeWormhole, Wallet: wallet, Mint: token}},
+	}
+
+	events := Events(tx)
+	if len(events) != 9 {
+		t.Fatalf("expected 9 events, got %d", len(events))
+	}
+
+	swap := events[0]
+	if swap.Kind != KindSwap || swap.Slot != 77 || swap.Signature != tx.Signature {
+		t.Errorf("unexpected swap envelope: %+v", swap)
+	}
+	if !swap.Mint().Equals(token) || !swap.Wallet().Equals(wallet) {
+		t.Errorf("expected swap keyed by %s and %s, got %s and %s", token, wallet, swap.Mint(), swap.Wallet())
+	}
+	if events[2].Kind != KindTransfer || events[2].Index != 1 || events[2].ID() != tx.Signature.String()+":transfer:1" {
+		t.Errorf("unexpected second transfer: %+v", events[2])
+	}
+	if supply := events[3]; supply.Kind != KindTokenSupply || !supply.Wallet().Equals(wallet) {
+		t.Errorf("expected the supply event to fall back to its authority, got %+v", supply)
+	}
+	if fill := events[4]; fill.Kind != KindPerpFill || !fill.Wallet().Equals(wallet) || !fill.Mint().IsZero() {
+		t.Errorf("expected the perp fill keyed by its trader, got %+v", fill)
+	}
+	if nft := events[5]; nft.Kind != KindCompressedNft || !nft.Mint().Equals(token) || !nft.Wallet().Equals(wallet) {
+		t.Errorf("expected the compressed NFT event keyed by its asset and owner, got %+v", nft)
+	}
+	if minted := events[6]; minted.Kind != KindNftMint || !minted.Mint().Equals(token) || !minted.Wallet().Equals(wallet) {
+		t.Errorf("expected the NFT mint keyed by its mint and minter, got %+v", minted)
+	}
+	if domain := events[7]; domain.Kind != KindDomain || !domain.Wallet().Equals(wallet) || !domain.Mint().IsZero() {
+		t.Errorf("expected the domain event keyed by its owner only, got %+v", domain)
+	}
+	if bridge := events[8]; bridge.Kind != KindBridge || !bridge.Mint().Equals(token) || !bridge.Wallet().Equals(wallet) {
+		t.Errorf("expected the bridge transfer keyed by its mint and wallet, got %+v", bridge)
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	event := &Event{Kind: KindTransfer, Slot: 5, Transfer: &tx_parser.TransferInfo{Amount: 10}}
+
+	data, err := JSONEncoder{}.Encode(event)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if decoded["kind"] != "transfer" || decoded["slot"] != float64(5) || decoded["transfer"] == nil {
+		t.Errorf("unexpected encoding: %s", data)
+	}
+	if _, ok := decoded["swap"]; ok {
+		t.Errorf("expected empty payloads to be omitted: %s", data)
+	}
+	if decoded["schema_version"] != float64(1) || decoded["transfer"].(map[string]any)["amount"] != "10" {
+		t.Errorf("expected a versioned envelope with string amounts: %s", data)
+	}
+
+	var roundTrip Event
+	if err := json.Unmarshal(data, &roundTrip); err != nil || roundTrip.Transfer.Amount != 10 {
+		t.Errorf("failed to decode the event back: %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// DEFAULT_LIMIT bounds queries that set no limit
+const DEFAULT_LIMIT = 100
+
+// SwapFilter selects stored swaps, zero fields match everything
+type SwapFilter struct {
+	Wallet   solana.PublicKey
+	Mint     solana.PublicKey // either side of the swap
+	Protocol tx_parser.SwapType
+	FromSlot uint64 // inclusive
+	ToSlot   uint64 // inclusive
+	Limit    int    // defaults to DEFAULT_LIMIT
+}
+
+// Swaps returns the swaps matching the filter, newest first
+func (s *Sink) Swaps(ctx context.Context, filter SwapFilter) ([]*sink.Event, error) {
+	query, args := swapQuery(filter)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query swaps: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*sink.Event
+	for rows.Next() {
+		event, err := scanSwap(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read swaps: %w", err)
+	}
+	return events, nil
+}
+
+// WalletHistory returns the swaps signed by the wallet and the transfers it sent or
+// received, newest first
+func (s *Sink) WalletHistory(ctx context.Context, wallet solana.PublicKey, limit int) ([]*sink.Event, error) {
+	if limit <= 0 {
+		limit = DEFAULT_LIMIT
+	}
+	events, err := s.Swaps(ctx, SwapFilter{Wallet: wallet, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+strings.Join(transferColumns, ", ")+" FROM transfers"+
+			" WHERE source_owner = ? OR destination_owner = ?"+
+			" ORDER BY slot DESC, signature, idx LIMIT ?",
+		wallet.String(), wallet.String(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transfers: %w", err)
+	}
+
+	sortNewestFirst(events)
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// swapQuery builds the query selecting the swaps matching the filter
+func swapQuery(filter SwapFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	if !filter.Wallet.IsZero() {
+		conditions = append(conditions, "wallet = ?")
+		args = append(args, filter.Wallet.String())
+	}
+	if !filter.Mint.IsZero() {
+		conditions = append(conditions, "(mint_in = ? OR mint_out = ?)")
+		args = append(args, filter.Mint.String(), filter.Mint.String())
+	}
+	if filter.Protocol != "" {
+		conditions = append(conditions, "protocol = ?")
+		args = append(args, string(filter.Protocol))
+	}
+	if filter.FromSlot > 0 {
+		conditions = append(conditions, "slot >= ?")
+		args = append(args, int64(filter.FromSlot))
+	}
+	if filter.ToSlot > 0 {
+		conditions = append(conditions, "slot <= ?")
+		args = append(args, int64(filter.ToSlot))
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DEFAULT_LIMIT
+	}
+
+	query := "SELECT " + strings.Join(swapColumns, ", ") + " FROM swaps"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY slot DESC, signature, idx LIMIT ?"
+	return query, append(args, limit)
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSwap reads a row of the swaps table into a swap event
+func scanSwap(row scanner) (*sink.Event, error) {
+	var (
+		signature, protocol, wallet, mintIn, amountIn, mintOut, amountOut string
+		index, decimalsIn, decimalsOut, instructionIndex                  int
+		slot                                                              int64
+		blockTime                                                         sql.NullInt64
+	)
+	if err := row.Scan(&signature, &index, &slot, &blockTime, &protocol, &wallet,
+		&mintIn, &amountIn, &decimalsIn, &mintOut, &amountOut, &decimalsOut, &instructionIndex); err != nil {
+		return nil, fmt.Errorf("failed to scan swap: %w", err)
+	}
+
+	event := &sink.Event{Kind: sink.KindSwap, Slot: uint64(slot), BlockTime: unixTime(blockTime), Index: index}
+	swap := &tx_parser.SwapInfo{
+		Protocol:         tx_parser.SwapType(protocol),
+		TokenIn:          tx_parser.TokenInfo{Decimals: uint8(decimalsIn)},
+		TokenOut:         tx_parser.TokenInfo{Decimals: uint8(decimalsOut)},
+		InstructionIndex: instructionIndex,
+	}
+	var signer solana.PublicKey
+	var err error
+	if event.Signature, err = solana.SignatureFromBase58(signature); err != nil {
+		return nil, fmt.Errorf("failed to decode signature %q: %w", signature, err)
+	}
+	if err := decodeAll(
+		decodeKey(wallet, &signer),
+		decodeKey(mintIn, &swap.TokenIn.Mint),
+		decodeKey(mintOut, &swap.TokenOut.Mint),
+		decodeAmount(amountIn, &swap.TokenIn.Amount),
+		decodeAmount(amountOut, &swap.TokenOut.Amount),
+	); err != nil {
+		return nil, fmt.Errorf("failed to decode swap %s: %w", signature, err)
+	}
+	if !signer.IsZero() {
+		swap.Signers = []solana.PublicKey{signer}
+	}
+	event.Swap = swap
+	return event, nil
+}
+
+// scanTransfer reads a row of the transfers table into a transfer event
+func scanTransfer(row scanner) (*sink.Event, error) {
+	var (
+		signature, transferType, program, mint, source, destination string
+		sourceOwner, destinationOwner, authority, amount            string
+		index, decimals, instructionIndex, innerIndex               int
+		slot                                                        int64
+		blockTime                                                   sql.NullInt64
+	)
+	if err := row.Scan(&signature, &index, &slot, &blockTime, &transferType, &program, &mint, &source, &destination,
+		&sourceOwner, &destinationOwner, &authority, &amount, &decimals, &instructionIndex, &innerIndex); err != nil {
+		return nil, fmt.Errorf("failed to scan transfer: %w", err)
+	}
+
+	event := &sink.Event{Kind: sink.KindTransfer, Slot: uint64(slot), BlockTime: unixTime(blockTime), Index: index}
+	transfer := &tx_parser.TransferInfo{
+		Type:             tx_parser.TransferType(transferType),
+		Decimals:         uint8(decimals),
+		InstructionIndex: instructionIndex,
+		InnerIndex:       innerIndex,
+	}
+	var err error
+	if event.Signature, err = solana.SignatureFromBase58(signature); err != nil {
+		return nil, fmt.Errorf("failed to decode signature %q: %w", signature, err)
+	}
+	if err := decodeAll(
+		decodeKey(program, &transfer.Program),
+		decodeKey(mint, &transfer.Mint),
+		decodeKey(source, &transfer.Source),
+		decodeKey(destination, &transfer.Destination),
+		decodeKey(sourceOwner, &transfer.SourceOwner),
+		decodeKey(destinationOwner, &transfer.DestinationOwner),
+		decodeKey(authority, &transfer.Authority),
+		decodeAmount(amount, &transfer.Amount),
+	); err != nil {
+		return nil, fmt.Errorf("failed to decode transfer %s: %w", signature, err)
+	}
+	event.Transfer = transfer
+	return event, nil
+}
+
+// decodeKey decodes a base58 public key into dst
+func decodeKey(value string, dst *solana.PublicKey) error {
+	key, err := solana.PublicKeyFromBase58(value)
+	if err != nil {
+		return fmt.Errorf("invalid public key %q: %w", value, err)
+	}
+	*dst = key
+	return nil
+}
+
+// decodeAmount decodes a decimal amount into dst
+func decodeAmount(value string, dst *uint64) error {
+	amount, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", value, err)
+	}
+	*dst = amount
+	return nil
+}
+
+// decodeAll returns the first error
+func decodeAll(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unixTime converts nullable unix seconds into an optional block time
+func unixTime(value sql.NullInt64) *solana.UnixTimeSeconds {
+	if !value.Valid {
+		return nil
+	}
+	t := solana.UnixTimeSeconds(value.Int64)
+	return &t
+}
+
+// sortNewestFirst orders events by descending slot, then by signature and index as the
+// queries do
+func sortNewestFirst(events []*sink.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.Slot != b.Slot {
+			return a.Slot > b.Slot
+		}
+		if sa, sb := a.Signature.String(), b.Signature.String(); sa != sb {
+			return sa < sb
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Index < b.Index
+	})
+}
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS swaps (
+    signature         TEXT NOT NULL,
+    idx               INTEGER NOT NULL,
+    slot              INTEGER NOT NULL,
+    block_time        INTEGER,
+    protocol          TEXT NOT NULL,
+    wallet            TEXT NOT NULL,
+    mint_in           TEXT NOT NULL,
+    amount_in         TEXT NOT NULL,
+    decimals_in       INTEGER NOT NULL,
+    mint_out          TEXT NOT NULL,
+    amount_out        TEXT NOT NULL,
+    decimals_out      INTEGER NOT NULL,
+    instruction_index INTEGER NOT NULL,
+    PRIMARY KEY (signature, idx)
+);
+
+CREATE INDEX IF NOT EXISTS swaps_slot_idx ON swaps (slot);
+CREATE INDEX IF NOT EXISTS swaps_wallet_idx ON swaps (wallet, slot);
+CREATE INDEX IF NOT EXISTS swaps_mint_in_idx ON swaps (mint_in, slot);
+CREATE INDEX IF NOT EXISTS swaps_mint_out_idx ON swaps (mint_out, slot);
+
+CREATE TABLE IF NOT EXISTS transfers (
+    signature         TEXT NOT NULL,
+    idx               INTEGER NOT NULL,
+    slot              INTEGER NOT NULL,
+    block_time        INTEGER,
+    type              TEXT NOT NULL,
+    program           TEXT NOT NULL,
+    mint              TEXT NOT NULL,
+    source            TEXT NOT NULL,
+    destination       TEXT NOT NULL,
+    source_owner      TEXT NOT NULL,
+    destination_owner TEXT NOT NULL,
+    authority         TEXT NOT NULL,
+    amount            TEXT NOT NULL,
+    decimals          INTEGER NOT NULL,
+    instruction_index INTEGER NOT NULL,
+    inner_index       INTEGER NOT NULL,
+    PRIMARY KEY (signature, idx)
+);
+
+CREATE INDEX IF NOT EXISTS transfers_slot_idx ON transfers (slot);
+CREATE INDEX IF NOT EXISTS transfers_source_owner_idx ON transfers (source_owner, slot);
+CREATE INDEX IF NOT EXISTS transfers_destination_owner_idx ON transfers (destination_owner, slot);
+CREATE INDEX IF NOT EXISTS transfers_mint_idx ON transfers (mint, slot);
@@ -0,0 +1,240 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// DEFAULT_DRIVER is the database/sql driver name of modernc.org/sqlite
+const DEFAULT_DRIVER = "sqlite"
+
+//go:embed schema.sql
+var schema string
+
+// migrations are the schema versions in order, the applied version is kept in the
+// database's user_version
+var migrations = []string{schema}
+
+var (
+	swapColumns = []string{
+		"signature", "idx", "slot", "block_time", "protocol", "wallet",
+		"mint_in", "amount_in", "decimals_in", "mint_out", "amount_out", "decimals_out", "instruction_index",
+	}
+	transferColumns = []string{
+		"signature", "idx", "slot", "block_time", "type", "program", "mint", "source", "destination",
+		"source_owner", "destination_owner", "authority", "amount", "decimals", "instruction_index", "inner_index",
+	}
+)
+
+// Sink stores swaps and transfers in an SQLite database and queries them back, for single
+// node setups without external infrastructure. Other event kinds are ignored. The package
+// links no driver, import one such as modernc.org/sqlite or github.com/mattn/go-sqlite3.
+type Sink struct {
+	db     *sql.DB
+	ownsDB bool
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// Open opens the database file with the named driver, DEFAULT_DRIVER if empty, applies
+// pending migrations and returns a sink that closes the database on Close. SQLite allows
+// one writer, so the sink uses a single connection in WAL mode.
+func Open(ctx context.Context, driver, path string) (*Sink, error) {
+	if driver == "" {
+		driver = DEFAULT_DRIVER
+	}
+	db, err := sql.Open(driver, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000", "PRAGMA synchronous = NORMAL"} {
+		if _, err := db.ExecContext(ctx, pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to configure database: %w", err)
+		}
+	}
+	if err := Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := New(db)
+	s.ownsDB = true
+	return s, nil
+}
+
+// New creates a sink over an open database. Call Migrate first to create the schema.
+func New(db *sql.DB) *Sink {
+	return &Sink{db: db}
+}
+
+// Migrate applies the schema versions newer than the database's user_version, each in its
+// own transaction
+func Migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for ; version < len(migrations); version++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", version+1, err)
+		}
+		for _, statement := range statements(migrations[version]) {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to apply migration %d: %w", version+1, err)
+			}
+		}
+		// PRAGMA does not take parameters
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", version+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version+1, err)
+		}
+	}
+	return nil
+}
+
+// statements splits a schema into statements, since not every driver executes several
+// statements at once
+func statements(schema string) []string {
+	var result []string
+	for _, statement := range strings.Split(schema, ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			result = append(result, statement)
+		}
+	}
+	return result
+}
+
+// Write inserts the events in a single transaction, skipping events already written
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	var swaps, transfers [][]any
+	for _, event := range events {
+		switch {
+		case event.Swap != nil:
+			swaps = append(swaps, swapRow(event))
+		case event.Transfer != nil:
+			transfers = append(transfers, transferRow(event))
+		}
+	}
+	if len(swaps) == 0 && len(transfers) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertRows(ctx, tx, "swaps", swapColumns, swaps); err != nil {
+		return err
+	}
+	if err := insertRows(ctx, tx, "transfers", transferColumns, transfers); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit events: %w", err)
+	}
+	return nil
+}
+
+// DB returns the underlying database for queries the sink does not cover
+func (s *Sink) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes the database if the sink opened it
+func (s *Sink) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// insertRows inserts the rows with one prepared statement, ignoring existing keys
+func insertRows(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), placeholders))
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert into %s: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// swapRow converts a swap event into a row of the swaps table. Amounts are stored as text
+// since SQLite integers are signed 64-bit.
+func swapRow(event *sink.Event) []any {
+	swap := event.Swap
+	return []any{
+		event.Signature.String(),
+		event.Index,
+		int64(event.Slot),
+		blockTime(event.BlockTime),
+		string(swap.Protocol),
+		event.Wallet().String(),
+		swap.TokenIn.Mint.String(),
+		strconv.FormatUint(swap.TokenIn.Amount, 10),
+		int(swap.TokenIn.Decimals),
+		swap.TokenOut.Mint.String(),
+		strconv.FormatUint(swap.TokenOut.Amount, 10),
+		int(swap.TokenOut.Decimals),
+		swap.InstructionIndex,
+	}
+}
+
+// transferRow converts a transfer event into a row of the transfers table
+func transferRow(event *sink.Event) []any {
+	transfer := event.Transfer
+	return []any{
+		event.Signature.String(),
+		event.Index,
+		int64(event.Slot),
+		blockTime(event.BlockTime),
+		string(transfer.Type),
+		transfer.Program.String(),
+		transfer.Mint.String(),
+		transfer.Source.String(),
+		transfer.Destination.String(),
+		transfer.SourceOwner.String(),
+		transfer.DestinationOwner.String(),
+		transfer.Authority.String(),
+		strconv.FormatUint(transfer.Amount, 10),
+		int(transfer.Decimals),
+		transfer.InstructionIndex,
+		transfer.InnerIndex,
+	}
+}
+
+// blockTime converts an optional block time into nullable unix seconds
+func blockTime(t *solana.UnixTimeSeconds) any {
+	if t == nil {
+		return nil
+	}
+	return int64(*t)
+}
@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+func testTransaction() *tx_parser.ParsedTransaction {
+	wallet, token := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	return &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{4},
+		Slot:      300,
+		BlockTime: &blockTime,
+		Swaps: []*tx_parser.SwapInfo{{
+			Protocol:         tx_parser.SwapTypeRaydium,
+			Signers:          []solana.PublicKey{wallet},
+			TokenIn:          tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut:         tx_parser.TokenInfo{Mint: token, Amount: 18_446_744_073_709_551_615, Decimals: 6},
+			InstructionIndex: 2,
+		}},
+		Transfers: []*tx_parser.TransferInfo{
+			{Type: tx_parser.TransferTypeToken, Mint: token, SourceOwner: wallet, Amount: 5, Decimals: 6, InnerIndex: -1},
+		},
+		StakeEvents: []*tx_parser.StakeEvent{{Type: tx_parser.StakeEventDelegate}},
+	}
+}
+
+// row scans stored values the way a driver would
+type row []any
+
+func (r row) Scan(dest ...any) error {
+	if len(dest) != len(r) {
+		return fmt.Errorf("scanning %d values into %d destinations", len(r), len(dest))
+	}
+	for i, value := range r {
+		switch d := dest[i].(type) {
+		case *string:
+			*d = value.(string)
+		case *int:
+			*d = value.(int)
+		case *int64:
+			*d = value.(int64)
+		case *sql.NullInt64:
+			d.Int64, d.Valid = 0, value != nil
+			if value != nil {
+				d.Int64 = value.(int64)
+			}
+		default:
+			return fmt.Errorf("unsupported destination %T", dest[i])
+		}
+	}
+	return nil
+}
+
+func TestStatements(t *testing.T) {
+	tables := 0
+	for _, statement := range statements(schema) {
+		if strings.HasSuffix(statement, ";") || statement == "" {
+			t.Errorf("unexpected statement %q", statement)
+		}
+		if strings.HasPrefix(statement, "CREATE TABLE") {
+			tables++
+		}
+	}
+	if tables != 2 {
+		t.Errorf("expected the swaps and transfers tables, got %d tables", tables)
+	}
+}
+
+func TestRowsRoundTrip(t *testing.T) {
+	events := sink.Events(testTransaction())
+
+	swap, err := scanSwap(row(swapRow(events[0])))
+	if err != nil {
+		t.Fatalf("failed to scan swap: %v", err)
+	}
+	if !reflect.DeepEqual(swap, events[0]) {
+		t.Errorf("expected the swap event back, got %+v", swap.Swap)
+	}
+
+	transfer, err := scanTransfer(row(transferRow(events[1])))
+	if err != nil {
+		t.Fatalf("failed to scan transfer: %v", err)
+	}
+	if !reflect.DeepEqual(transfer, events[1]) {
+		t.Errorf("expected the transfer event back, got %+v", transfer.Transfer)
+	}
+
+	values := swapRow(events[0])
+	values[3], values[7] = nil, "not a number"
+	if _, err := scanSwap(row(values)); err == nil || !strings.Contains(err.Error(), "invalid amount") {
+		t.Errorf("expected an invalid amount to fail, got %v", err)
+	}
+}
+
+func TestSwapQuery(t *testing.T) {
+	query, args := swapQuery(SwapFilter{})
+	if strings.Contains(query, "WHERE") || !reflect.DeepEqual(args, []any{DEFAULT_LIMIT}) {
+		t.Errorf("expected an unfiltered query, got %q %v", query, args)
+	}
+
+	mint := solana.NewWallet().PublicKey()
+	query, args = swapQuery(SwapFilter{Mint: mint, Protocol: tx_parser.SwapTypeOrca, FromSlot: 10, ToSlot: 20, Limit: 5})
+	if !strings.Contains(query, "WHERE (mint_in = ? OR mint_out = ?) AND protocol = ? AND slot >= ? AND slot <= ? ORDER BY") {
+		t.Errorf("unexpected query %q", query)
+	}
+	expected := []any{mint.String(), mint.String(), string(tx_parser.SwapTypeOrca), int64(10), int64(20), 5}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected args %v, got %v", expected, args)
+	}
+}
+
+// TestSink runs against a real database when a driver is linked into the test binary
+func TestSink(t *testing.T) {
+	drivers := sql.Drivers()
+	driver := ""
+	for _, name := range []string{"sqlite", "sqlite3"} {
+		if slices.Contains(drivers, name) {
+			driver = name
+		}
+	}
+	if driver == "" {
+		t.Skip("no sqlite driver linked")
+	}
+
+	ctx := context.Background()
+	s, err := Open(ctx, driver, filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer s.Close()
+
+	tx := testTransaction()
+	events := sink.Events(tx)
+	for range 2 {
+		if err := s.Write(ctx, events); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+	if err := Migrate(ctx, s.DB()); err != nil {
+		t.Fatalf("expected migrating again to be a no-op: %v", err)
+	}
+
+	swaps, err := s.Swaps(ctx, SwapFilter{Mint: tx.Swaps[0].TokenOut.Mint})
+	if err != nil {
+		t.Fatalf("failed to query swaps: %v", err)
+	}
+	if len(swaps) != 1 || !reflect.DeepEqual(swaps[0], events[0]) {
+		t.Errorf("expected the swap written once, got %v", swaps)
+	}
+
+	history, err := s.WalletHistory(ctx, tx.Swaps[0].Signers[0], 0)
+	if err != nil {
+		t.Fatalf("failed to query history: %v", err)
+	}
+	if len(history) != 2 || history[0].Kind != sink.KindSwap || history[1].Kind != sink.KindTransfer {
+		t.Errorf("expected the swap and transfer, got %v", history)
+	}
+}
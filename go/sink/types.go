@@ -0,0 +1,64 @@
+package sink
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Kind is the type of parsed event carried by an Event
+type Kind string
+
+const (
+	KindSwap          Kind = "swap"
+	KindTransfer      Kind = "transfer"
+	KindStake         Kind = "stake"
+	KindTokenSupply   Kind = "token_supply"
+	KindTokenAdmin    Kind = "token_admin"
+	KindPoolCreated   Kind = "pool_created"
+	KindPerpFill      Kind = "perp_fill"
+	KindCompressedNft Kind = "compressed_nft"
+	KindNftMint       Kind = "nft_mint"
+	KindDomain        Kind = "domain"
+	KindBridge        Kind = "bridge"
+	KindBalance       Kind = "balance" // wallet balances recorded by balance snapshots, not parsed from a transaction
+)
+
+// Event is the envelope sinks write, holding exactly one parsed event and where it came from
+type Event struct {
+	Kind      Kind                    `json:"kind"`
+	Signature solana.Signature        `json:"signature"`
+	Slot      uint64                  `json:"slot"`
+	BlockTime *solana.UnixTimeSeconds `json:"block_time,omitempty"`
+	Index     int                     `json:"index"` // position of the event among events of its kind in the transaction
+
+	Swap          *tx_parser.SwapInfo           `json:"swap,omitempty"`
+	Transfer      *tx_parser.TransferInfo       `json:"transfer,omitempty"`
+	Stake         *tx_parser.StakeEvent         `json:"stake,omitempty"`
+	TokenSupply   *tx_parser.TokenSupplyEvent   `json:"token_supply,omitempty"`
+	TokenAdmin    *tx_parser.TokenAdminEvent    `json:"token_admin,omitempty"`
+	PoolCreated   *tx_parser.PoolCreatedEvent   `json:"pool_created,omitempty"`
+	PerpFill      *tx_parser.PerpFillInfo       `json:"perp_fill,omitempty"`
+	CompressedNft *tx_parser.CompressedNftEvent `json:"compressed_nft,omitempty"`
+	NftMint       *tx_parser.NftMintEvent       `json:"nft_mint,omitempty"`
+	Domain        *tx_parser.DomainEvent        `json:"domain,omitempty"`
+	Bridge        *tx_parser.BridgeEvent        `json:"bridge,omitempty"`
+	Balance       *Balance                      `json:"balance,omitempty"`
+}
+
+// Balance is a wallet's SOL and token balances at the event's slot
+type Balance struct {
+	Wallet   solana.PublicKey `json:"wallet"`
+	Epoch    uint64           `json:"epoch"`
+	Lamports uint64           `json:"lamports,string"`
+	Tokens   []TokenBalance   `json:"tokens"`
+}
+
+// TokenBalance is the balance of a single token account of a Balance
+type TokenBalance struct {
+	Account  solana.PublicKey `json:"account"`
+	Mint     solana.PublicKey `json:"mint"`
+	Program  solana.PublicKey `json:"program"`
+	Amount   uint64           `json:"amount,string"`
+	Decimals uint8            `json:"decimals"`
+}
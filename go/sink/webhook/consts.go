@@ -0,0 +1,10 @@
+package webhook
+
+const (
+	// SIGNATURE_HEADER carries the hex HMAC-SHA256 of the timestamp, a dot and the body
+	SIGNATURE_HEADER = "X-Webhook-Signature"
+	// TIMESTAMP_HEADER carries the unix time of the delivery attempt in seconds
+	TIMESTAMP_HEADER = "X-Webhook-Timestamp"
+	// EVENT_COUNT_HEADER carries the number of events in the body
+	EVENT_COUNT_HEADER = "X-Webhook-Events"
+)
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/logging"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Filter selects the events delivered to a webhook. An event must match every non-empty
+// field and matches a field when it matches any of its values.
+type Filter struct {
+	Kinds []sink.Kind
+
+	// Mints match either side of a swap and the mint of any other event
+	Mints []solana.PublicKey
+
+	// Wallets match the wallet that initiated the event and the receiving owner of transfers
+	Wallets []solana.PublicKey
+
+	// MinUSD skips swaps worth less, as priced by the pricing package. When set, events
+	// without a USD value are skipped too.
+	MinUSD float64
+}
+
+// Webhook is an endpoint receiving the events matching its filter
+type Webhook struct {
+	URL string
+
+	// Secret signs every delivery with HMAC-SHA256, empty disables signing
+	Secret string
+
+	Filter Filter
+}
+
+// Config controls the webhook sink
+type Config struct {
+	Webhooks []Webhook
+
+	// HTTPClient defaults to a client with a 10s timeout
+	HTTPClient *http.Client
+
+	// MaxAttempts is the number of times a delivery is attempted before it is dead
+	// lettered, defaults to 5
+	MaxAttempts int
+
+	// BaseBackoff is the delay before the first retry, doubled on every attempt and
+	// randomized by up to half, defaults to 500ms
+	BaseBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries, defaults to 30s
+	MaxBackoff time.Duration
+
+	// DeadLetter receives one JSON line per delivery that failed for good, optional
+	DeadLetter io.Writer
+
+	// Logger receives an error record per delivery that failed for good, defaults to a
+	// no-op logger
+	Logger logging.Logger
+}
+
+// DeadLetter is a delivery that could not be made
+type DeadLetter struct {
+	URL      string        `json:"url"`
+	Error    string        `json:"error"`
+	Attempts int           `json:"attempts"`
+	Time     time.Time     `json:"time"`
+	Events   []*sink.Event `json:"events"`
+}
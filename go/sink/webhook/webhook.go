@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/logging"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Sink POSTs the events matching each webhook's filter as a JSON array. Deliveries that
+// still fail after the retries are dead lettered instead of failing the write, so one
+// broken endpoint does not stall the others.
+type Sink struct {
+	config Config
+	mu     sync.Mutex // serializes dead letter writes
+	now    func() time.Time
+}
+
+var _ sink.Sink = (*Sink)(nil)
+
+// errPermanent marks deliveries that retrying cannot fix
+var errPermanent = errors.New("permanent failure")
+
+// New creates a webhook sink
+func New(config Config) (*Sink, error) {
+	if len(config.Webhooks) == 0 {
+		return nil, fmt.Errorf("at least one webhook is required")
+	}
+	for _, webhook := range config.Webhooks {
+		if webhook.URL == "" {
+			return nil, fmt.Errorf("webhook URL is required")
+		}
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = 500 * time.Millisecond
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 30 * time.Second
+	}
+	config.Logger = logging.OrNop(config.Logger)
+
+	return &Sink{config: config, now: time.Now}, nil
+}
+
+// Write delivers the matching events to every webhook in parallel. It only fails when a
+// failed delivery cannot be dead lettered.
+func (s *Sink) Write(ctx context.Context, events []*sink.Event) error {
+	errs := make([]error, len(s.config.Webhooks))
+	var wg sync.WaitGroup
+	for i, webhook := range s.config.Webhooks {
+		var matched []*sink.Event
+		for _, event := range events {
+			if webhook.Filter.Match(event) {
+				matched = append(matched, event)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempts, err := s.deliver(ctx, webhook, matched)
+			if err != nil {
+				errs[i] = s.deadLetter(ctx, webhook, matched, attempts, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close is a no-op, deliveries finish within Write
+func (s *Sink) Close() error {
+	return nil
+}
+
+// Match reports whether the event passes the filter
+func (f *Filter) Match(event *sink.Event) bool {
+	if len(f.Kinds) > 0 && !slices.Contains(f.Kinds, event.Kind) {
+		return false
+	}
+	if len(f.Mints) > 0 && !containsAny(f.Mints, mints(event)...) {
+		return false
+	}
+	if len(f.Wallets) > 0 && !containsAny(f.Wallets, wallets(event)...) {
+		return false
+	}
+	if f.MinUSD > 0 && usdValue(event) < f.MinUSD {
+		return false
+	}
+	return true
+}
+
+// Sign returns the signature of a delivery as sent in SIGNATURE_HEADER
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery, for use by webhook receivers
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// deliver POSTs the events until the webhook accepts them, returning the number of attempts
+func (s *Sink) deliver(ctx context.Context, webhook Webhook, events []*sink.Event) (int, error) {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return attempt - 1, ctx.Err()
+			case <-time.After(s.backoff(attempt - 1)):
+			}
+		}
+
+		lastErr = s.post(ctx, webhook, body, len(events))
+		if lastErr == nil {
+			return attempt, nil
+		}
+		if errors.Is(lastErr, errPermanent) {
+			return attempt, lastErr
+		}
+	}
+	return s.config.MaxAttempts, lastErr
+}
+
+// post makes a single delivery attempt. Client errors other than 408 and 429 are permanent.
+func (s *Sink) post(ctx context.Context, webhook Webhook, body []byte, count int) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w: %w", errPermanent, err)
+	}
+	timestamp := s.now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TIMESTAMP_HEADER, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(EVENT_COUNT_HEADER, strconv.Itoa(count))
+	if webhook.Secret != "" {
+		request.Header.Set(SIGNATURE_HEADER, Sign(webhook.Secret, timestamp, body))
+	}
+
+	response, err := s.config.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post events: %w", err)
+	}
+	io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+	response.Body.Close()
+
+	switch status := response.StatusCode; {
+	case status >= 200 && status < 300:
+		return nil
+	case status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: webhook returned %s", errPermanent, response.Status)
+	default:
+		return fmt.Errorf("webhook returned %s", response.Status)
+	}
+}
+
+// backoff returns the jittered exponential delay before the given retry
+func (s *Sink) backoff(retry int) time.Duration {
+	delay := s.config.BaseBackoff << (retry - 1)
+	if delay <= 0 || delay > s.config.MaxBackoff {
+		delay = s.config.MaxBackoff
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// deadLetter records a failed delivery
+func (s *Sink) deadLetter(ctx context.Context, webhook Webhook, events []*sink.Event, attempts int, cause error) error {
+	letter := DeadLetter{
+		URL:      webhook.URL,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		Time:     s.now().UTC(),
+		Events:   events,
+	}
+	s.config.Logger.ErrorContext(ctx, "webhook delivery failed", "url", webhook.URL, "events", len(events), "attempts", attempts, logging.ERROR_KEY, cause)
+	if s.config.DeadLetter == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.config.DeadLetter.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return nil
+}
+
+// mints returns every mint the event touches
+func mints(event *sink.Event) []solana.PublicKey {
+	if event.Swap != nil {
+		return []solana.PublicKey{event.Swap.TokenIn.Mint, event.Swap.TokenOut.Mint}
+	}
+	return []solana.PublicKey{event.Mint()}
+}
+
+// wallets returns the wallets involved in the event
+func wallets(event *sink.Event) []solana.PublicKey {
+	if event.Swap != nil {
+		return event.Swap.Signers
+	}
+	if event.Transfer != nil {
+		return []solana.PublicKey{event.Transfer.SourceOwner, event.Transfer.DestinationOwner}
+	}
+	return []solana.PublicKey{event.Wallet()}
+}
+
+// usdValue is the USD value of a priced swap, zero for everything else
+func usdValue(event *sink.Event) float64 {
+	if event.Swap == nil || event.Swap.Price == nil {
+		return 0
+	}
+	return event.Swap.Price.VolumeUSD
+}
+
+func containsAny(set []solana.PublicKey, keys ...solana.PublicKey) bool {
+	for _, key := range keys {
+		if !key.IsZero() && solana.PublicKeySlice(set).Contains(key) {
+			return true
+		}
+	}
+	return false
+}
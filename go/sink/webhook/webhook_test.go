@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+var (
+	wallet = solana.NewWallet().PublicKey()
+	mint   = solana.NewWallet().PublicKey()
+)
+
+func swapEvent(index int, signer solana.PublicKey, volumeUSD float64) *sink.Event {
+	return &sink.Event{
+		Kind:  sink.KindSwap,
+		Index: index,
+		Swap: &tx_parser.SwapInfo{
+			Protocol: tx_parser.SwapTypeRaydium,
+			Signers:  []solana.PublicKey{signer},
+			TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID, Amount: 1_000_000_000, Decimals: 9},
+			TokenOut: tx_parser.TokenInfo{Mint: mint, Amount: 5_000, Decimals: 6},
+			Price:    &tx_parser.PriceInfo{VolumeUSD: volumeUSD},
+		},
+	}
+}
+
+// receiver records deliveries, answering with the queued statuses before succeeding
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func (r *receiver) serve(t *testing.T) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.bodies = append(r.bodies, body)
+		r.headers = append(r.headers, req.Header)
+		if len(r.statuses) > 0 {
+			w.WriteHeader(r.statuses[0])
+			r.statuses = r.statuses[1:]
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newSink(t *testing.T, deadLetter io.Writer, webhooks ...Webhook) *Sink {
+	s, err := New(Config{
+		Webhooks:    webhooks,
+		MaxAttempts: 3,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		DeadLetter:  deadLetter,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestDeliverWithRetries(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	var deadLetters bytes.Buffer
+	s := newSink(t, &deadLetters, Webhook{
+		URL:    r.serve(t),
+		Secret: "secret",
+		Filter: Filter{Wallets: []solana.PublicKey{wallet}, MinUSD: 100},
+	})
+
+	events := []*sink.Event{
+		swapEvent(0, wallet, 250),
+		swapEvent(1, wallet, 50),
+		swapEvent(2, solana.NewWallet().PublicKey(), 1_000),
+		{Kind: sink.KindTransfer, Transfer: &tx_parser.TransferInfo{DestinationOwner: wallet, Mint: mint}},
+	}
+	if err := s.Write(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	if deadLetters.Len() != 0 {
+		t.Fatalf("unexpected dead letters %s", deadLetters.String())
+	}
+	if len(r.bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(r.bodies))
+	}
+
+	body, header := r.bodies[2], r.headers[2]
+	var delivered []*sink.Event
+	if err := json.Unmarshal(body, &delivered); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 1 || delivered[0].Index != 0 || header.Get(EVENT_COUNT_HEADER) != "1" {
+		t.Errorf("unexpected delivery %s", body)
+	}
+	timestamp, err := strconv.ParseInt(header.Get(TIMESTAMP_HEADER), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify("secret", timestamp, body, header.Get(SIGNATURE_HEADER)) {
+		t.Error("invalid signature")
+	}
+	if Verify("other", timestamp, body, header.Get(SIGNATURE_HEADER)) {
+		t.Error("signature verified with the wrong secret")
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	failing := &receiver{statuses: []int{500, 500, 500, 500}}
+	rejecting := &receiver{statuses: []int{http.StatusBadRequest}}
+	var deadLetters bytes.Buffer
+	s := newSink(t, &deadLetters,
+		Webhook{URL: failing.serve(t)},
+		Webhook{URL: rejecting.serve(t), Filter: Filter{Mints: []solana.PublicKey{mint}, Kinds: []sink.Kind{sink.KindSwap}}},
+	)
+	var logs bytes.Buffer
+	s.config.Logger = slog.New(slog.NewJSONHandler(&logs, nil))
+
+	if err := s.Write(context.Background(), []*sink.Event{swapEvent(0, wallet, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(failing.bodies) != 3 || len(rejecting.bodies) != 1 {
+		t.Fatalf("expected 3 and 1 attempts, got %d and %d", len(failing.bodies), len(rejecting.bodies))
+	}
+
+	attempts := map[string]int{}
+	decoder := json.NewDecoder(&deadLetters)
+	for decoder.More() {
+		var letter DeadLetter
+		if err := decoder.Decode(&letter); err != nil {
+			t.Fatal(err)
+		}
+		if len(letter.Events) != 1 || letter.Error == "" {
+			t.Errorf("unexpected dead letter %+v", letter)
+		}
+		attempts[letter.URL] = letter.Attempts
+	}
+	if len(attempts) != 2 || attempts[s.config.Webhooks[0].URL] != 3 || attempts[s.config.Webhooks[1].URL] != 1 {
+		t.Errorf("unexpected dead letters %v", attempts)
+	}
+	if lines := bytes.Count(logs.Bytes(), []byte("webhook delivery failed")); lines != 2 {
+		t.Errorf("expected 2 logged failures, got %d", lines)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	swap := swapEvent(0, wallet, 20)
+	tests := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{"empty", Filter{}, true},
+		{"kind", Filter{Kinds: []sink.Kind{sink.KindTransfer}}, false},
+		{"either side", Filter{Mints: []solana.PublicKey{tx_parser.NATIVE_SOL_PROGRAM_ID}}, true},
+		{"other mint", Filter{Mints: []solana.PublicKey{wallet}}, false},
+		{"wallet", Filter{Wallets: []solana.PublicKey{wallet}}, true},
+		{"min usd", Filter{MinUSD: 20}, true},
+		{"above value", Filter{MinUSD: 20.01}, false},
+	}
+	for _, test := range tests {
+		if test.filter.Match(swap) != test.match {
+			t.Errorf("%s: expected match %t", test.name, test.match)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package sns
+
+import "github.com/gagliardetto/solana-go"
+
+// NAME_OFFERS_PROGRAM_ID stores the primary domain a wallet has chosen
+var NAME_OFFERS_PROGRAM_ID = solana.MustPublicKeyFromBase58("85iDfUvr3HJyLM2zcq5BXSrZCZ8iU8rXMKfb4x1YAZsL")
+
+// Name registry account layout
+const (
+	parentOffset       = 0
+	ownerOffset        = 32
+	registryHeaderSize = 96 // parent, owner and class, followed by the account's data
+)
+
+// favouriteDomainLength is a tag byte followed by the name account of the primary domain
+const favouriteDomainLength = 33
@@ -0,0 +1,265 @@
+package sns
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/cache"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// maxAccountsPerRequest is the getMultipleAccounts limit
+const maxAccountsPerRequest = 100
+
+// Resolver resolves Solana Name Service domains to their owners and wallets to their
+// primary domain, caching primary domains in a sharded LRU cache safe for concurrent use
+type Resolver struct {
+	rpcClient *rpc.Client
+	primary   *cache.Cache[solana.PublicKey, string]
+}
+
+// New creates a domain resolver
+func New(rpcClient *rpc.Client, config Config) *Resolver {
+	if config.CacheSize <= 0 {
+		config.CacheSize = 10_000
+	}
+
+	return &Resolver{
+		rpcClient: rpcClient,
+		primary:   cache.New[solana.PublicKey, string](cache.Config{Size: config.CacheSize, TTL: config.TTL, Shards: config.CacheShards}),
+	}
+}
+
+// DomainKey derives the name account of a domain such as bonfida.sol or dex.bonfida.sol
+func DomainKey(domain string) (solana.PublicKey, error) {
+	labels := strings.Split(strings.TrimSuffix(domain, ".sol"), ".")
+	if len(labels) > 2 || slices.Contains(labels, "") {
+		return solana.PublicKey{}, fmt.Errorf("invalid domain %q", domain)
+	}
+
+	key, err := tx_parser.NameAccountKey(tx_parser.HashDomainName(labels[len(labels)-1]), solana.PublicKey{}, tx_parser.SOL_TLD_AUTHORITY)
+	if err == nil && len(labels) == 2 {
+		// Subdomain names are prefixed with a zero byte
+		key, err = tx_parser.NameAccountKey(tx_parser.HashDomainName("\x00"+labels[0]), solana.PublicKey{}, key)
+	}
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive name account of %s: %w", domain, err)
+	}
+	return key, nil
+}
+
+// Resolve returns the wallet owning the domain
+func (r *Resolver) Resolve(ctx context.Context, domain string) (solana.PublicKey, error) {
+	key, err := DomainKey(domain)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+
+	accounts, err := r.getAccounts(ctx, []solana.PublicKey{key})
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	data, ok := nameRegistry(accounts[0])
+	if !ok {
+		return solana.PublicKey{}, fmt.Errorf("domain %s is not registered", domain)
+	}
+	return solana.PublicKeyFromBytes(data[ownerOffset : ownerOffset+32]), nil
+}
+
+// ReverseLookup returns the domain name of a name account, e.g. bonfida.sol
+func (r *Resolver) ReverseLookup(ctx context.Context, domainKey solana.PublicKey) (string, error) {
+	names, err := r.names(ctx, []solana.PublicKey{domainKey}, true)
+	if err != nil {
+		return "", err
+	}
+	name, ok := names[domainKey]
+	if !ok {
+		return "", fmt.Errorf("no reverse lookup found for %s", domainKey)
+	}
+	return name, nil
+}
+
+// PrimaryDomain returns the primary domain of a wallet
+func (r *Resolver) PrimaryDomain(ctx context.Context, wallet solana.PublicKey) (string, error) {
+	domains, err := r.PrimaryDomains(ctx, []solana.PublicKey{wallet})
+	if err != nil {
+		return "", err
+	}
+	domain, ok := domains[wallet]
+	if !ok {
+		return "", fmt.Errorf("no primary domain found for %s", wallet)
+	}
+	return domain, nil
+}
+
+// PrimaryDomains returns the primary domain of every wallet that has one, reading
+// uncached wallets in batched round trips. Wallets without a primary domain are left
+// out of the result.
+func (r *Resolver) PrimaryDomains(ctx context.Context, wallets []solana.PublicKey) (map[solana.PublicKey]string, error) {
+	result := make(map[solana.PublicKey]string, len(wallets))
+
+	seen := make(map[solana.PublicKey]bool, len(wallets))
+	var missing, favourites []solana.PublicKey
+	for _, wallet := range wallets {
+		if seen[wallet] {
+			continue
+		}
+		seen[wallet] = true
+
+		// Wallets without a primary domain are cached as empty
+		if domain, ok := r.primary.Get(wallet); ok {
+			if domain != "" {
+				result[wallet] = domain
+			}
+			continue
+		}
+		favourite, _, err := solana.FindProgramAddress([][]byte{[]byte("favourite_domain"), wallet[:]}, NAME_OFFERS_PROGRAM_ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive primary domain account of %s: %w", wallet, err)
+		}
+		missing = append(missing, wallet)
+		favourites = append(favourites, favourite)
+	}
+
+	accounts, err := r.getAccounts(ctx, favourites)
+	if err != nil {
+		return nil, err
+	}
+	domainKeys := make(map[solana.PublicKey]solana.PublicKey, len(missing))
+	var keys []solana.PublicKey
+	for i, account := range accounts {
+		if account == nil || !account.Owner.Equals(NAME_OFFERS_PROGRAM_ID) {
+			continue
+		}
+		data := account.Data.GetBinary()
+		if len(data) < favouriteDomainLength {
+			continue
+		}
+		domainKeys[missing[i]] = solana.PublicKeyFromBytes(data[1:favouriteDomainLength])
+		keys = append(keys, domainKeys[missing[i]])
+	}
+
+	names, err := r.names(ctx, keys, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, wallet := range missing {
+		var domain string
+		if key, ok := domainKeys[wallet]; ok {
+			domain = names[key]
+		}
+		r.primary.Put(wallet, domain)
+		if domain != "" {
+			result[wallet] = domain
+		}
+	}
+	return result, nil
+}
+
+// names reads the domain name of each name account from its reverse lookup, naming
+// subdomains after their parent domain. Parents must be top level domains.
+func (r *Resolver) names(ctx context.Context, domainKeys []solana.PublicKey, subdomains bool) (map[solana.PublicKey]string, error) {
+	// Each domain takes two keys, its name account for the parent and its reverse lookup
+	keys := make([]solana.PublicKey, 0, 2*len(domainKeys))
+	for _, domain := range domainKeys {
+		reverse, err := tx_parser.ReverseLookupKey(domain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive reverse lookup of %s: %w", domain, err)
+		}
+		keys = append(keys, domain, reverse)
+	}
+	accounts, err := r.getAccounts(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[solana.PublicKey]string, len(domainKeys))
+	labels := make(map[solana.PublicKey]string)
+	parents := make(map[solana.PublicKey]solana.PublicKey)
+	var parentKeys []solana.PublicKey
+	for i, domain := range domainKeys {
+		registry, ok := nameRegistry(accounts[2*i])
+		if !ok {
+			continue
+		}
+		label, ok := decodeReverseLookup(accounts[2*i+1])
+		if !ok {
+			continue
+		}
+
+		parent := solana.PublicKeyFromBytes(registry[parentOffset : parentOffset+32])
+		switch {
+		case parent.Equals(tx_parser.SOL_TLD_AUTHORITY):
+			names[domain] = label + ".sol"
+		case subdomains:
+			labels[domain] = strings.TrimPrefix(label, "\x00")
+			parents[domain] = parent
+			parentKeys = append(parentKeys, parent)
+		}
+	}
+	if len(parentKeys) == 0 {
+		return names, nil
+	}
+
+	parentNames, err := r.names(ctx, parentKeys, false)
+	if err != nil {
+		return nil, err
+	}
+	for domain, parent := range parents {
+		if parentName, ok := parentNames[parent]; ok {
+			names[domain] = labels[domain] + "." + parentName
+		}
+	}
+	return names, nil
+}
+
+// getAccounts reads the accounts in batches, keeping nil for missing accounts
+func (r *Resolver) getAccounts(ctx context.Context, keys []solana.PublicKey) ([]*rpc.Account, error) {
+	accounts := make([]*rpc.Account, 0, len(keys))
+	for start := 0; start < len(keys); start += maxAccountsPerRequest {
+		chunk := keys[start:min(start+maxAccountsPerRequest, len(keys))]
+		fetched, err := r.rpcClient.GetMultipleAccountsWithOpts(ctx, chunk, &rpc.GetMultipleAccountsOpts{
+			Commitment: rpc.CommitmentConfirmed,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get name accounts: %w", err)
+		}
+		if len(fetched.Value) != len(chunk) {
+			return nil, fmt.Errorf("expected %d accounts, got %d", len(chunk), len(fetched.Value))
+		}
+		accounts = append(accounts, fetched.Value...)
+	}
+	return accounts, nil
+}
+
+// nameRegistry returns the data of a name service account
+func nameRegistry(account *rpc.Account) ([]byte, bool) {
+	if account == nil || !account.Owner.Equals(tx_parser.NAME_SERVICE_PROGRAM_ID) {
+		return nil, false
+	}
+	data := account.Data.GetBinary()
+	if len(data) < registryHeaderSize {
+		return nil, false
+	}
+	return data, true
+}
+
+// decodeReverseLookup decodes the borsh string a reverse lookup account holds after its header
+func decodeReverseLookup(account *rpc.Account) (string, bool) {
+	data, ok := nameRegistry(account)
+	if !ok || len(data) < registryHeaderSize+4 {
+		return "", false
+	}
+	length := int(binary.LittleEndian.Uint32(data[registryHeaderSize:]))
+	if length == 0 || length > len(data)-registryHeaderSize-4 {
+		return "", false
+	}
+	return string(data[registryHeaderSize+4 : registryHeaderSize+4+length]), true
+}
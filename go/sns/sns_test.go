@@ -0,0 +1,149 @@
+package sns
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+type testAccount struct {
+	owner solana.PublicKey
+	data  []byte
+}
+
+func registryAccount(parent, owner solana.PublicKey, value []byte) testAccount {
+	data := append(parent[:], owner[:]...)
+	data = append(data, make([]byte, 32)...)
+	return testAccount{owner: tx_parser.NAME_SERVICE_PROGRAM_ID, data: append(data, value...)}
+}
+
+func reverseAccount(name string) testAccount {
+	value := binary.LittleEndian.AppendUint32(nil, uint32(len(name)))
+	return registryAccount(solana.PublicKey{}, solana.PublicKey{}, append(value, name...))
+}
+
+func favouriteAccount(domain solana.PublicKey) testAccount {
+	return testAccount{owner: NAME_OFFERS_PROGRAM_ID, data: append([]byte{1}, domain[:]...)}
+}
+
+func serve(t *testing.T, accounts map[solana.PublicKey]testAccount, requests *atomic.Int32) *rpc.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+		var keys []solana.PublicKey
+		json.Unmarshal(request.Params[0], &keys)
+
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			account, ok := accounts[key]
+			if !ok {
+				values[i] = "null"
+				continue
+			}
+			values[i] = fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+				account.owner, base64.StdEncoding.EncodeToString(account.data))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":[%s]}}`, strings.Join(values, ","))
+	}))
+	t.Cleanup(server.Close)
+	return rpc.New(server.URL)
+}
+
+func favouriteKey(t *testing.T, wallet solana.PublicKey) solana.PublicKey {
+	t.Helper()
+	key, _, err := solana.FindProgramAddress([][]byte{[]byte("favourite_domain"), wallet[:]}, NAME_OFFERS_PROGRAM_ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func reverseKey(t *testing.T, domain solana.PublicKey) solana.PublicKey {
+	t.Helper()
+	key, err := tx_parser.ReverseLookupKey(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestResolver(t *testing.T) {
+	owner, subOwner, bare := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	domain, err := DomainKey("bonfida.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subdomain, err := DomainKey("dex.bonfida")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	client := serve(t, map[solana.PublicKey]testAccount{
+		domain:                    registryAccount(tx_parser.SOL_TLD_AUTHORITY, owner, nil),
+		reverseKey(t, domain):     reverseAccount("bonfida"),
+		subdomain:                 registryAccount(domain, subOwner, nil),
+		reverseKey(t, subdomain):  reverseAccount("\x00dex"),
+		favouriteKey(t, owner):    favouriteAccount(domain),
+		favouriteKey(t, subOwner): favouriteAccount(subdomain),
+		favouriteKey(t, bare):     {owner: NAME_OFFERS_PROGRAM_ID, data: []byte{1}},
+	}, &requests)
+	resolver := New(client, Config{})
+
+	resolved, err := resolver.Resolve(context.Background(), "bonfida.sol")
+	if err != nil || !resolved.Equals(owner) {
+		t.Errorf("expected bonfida.sol to resolve to %s, got %s, %v", owner, resolved, err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "unregistered.sol"); err == nil {
+		t.Error("expected an unregistered domain to fail")
+	}
+	if _, err := DomainKey("a.b.c.sol"); err == nil {
+		t.Error("expected nested subdomains to be rejected")
+	}
+
+	name, err := resolver.ReverseLookup(context.Background(), subdomain)
+	if err != nil || name != "dex.bonfida.sol" {
+		t.Errorf("expected the subdomain to be named after its parent, got %q, %v", name, err)
+	}
+
+	domains, err := resolver.PrimaryDomains(context.Background(), []solana.PublicKey{owner, subOwner, bare, owner})
+	if err != nil {
+		t.Fatalf("failed to get primary domains: %v", err)
+	}
+	if len(domains) != 2 || domains[owner] != "bonfida.sol" || domains[subOwner] != "dex.bonfida.sol" {
+		t.Errorf("unexpected primary domains: %v", domains)
+	}
+
+	// Served from the cache, including the wallet without a primary domain
+	before := requests.Load()
+	if domain, err := resolver.PrimaryDomain(context.Background(), owner); err != nil || domain != "bonfida.sol" {
+		t.Errorf("unexpected primary domain: %q, %v", domain, err)
+	}
+	if _, err := resolver.PrimaryDomain(context.Background(), bare); err == nil {
+		t.Error("expected a wallet without a primary domain to fail")
+	}
+	if requests.Load() != before {
+		t.Error("expected cached wallets not to be fetched again")
+	}
+}
@@ -0,0 +1,16 @@
+package sns
+
+import "time"
+
+// Config controls the resolver
+type Config struct {
+	// CacheSize is the number of wallets whose primary domain is kept in memory,
+	// defaults to 10000
+	CacheSize int
+
+	// CacheShards is the number of independently locked cache shards, defaults to 32
+	CacheShards int
+
+	// TTL expires cached primary domains so changes are picked up, zero never expires
+	TTL time.Duration
+}
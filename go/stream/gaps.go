@@ -0,0 +1,80 @@
+package stream
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// slotTracker detects slots that may have been missed while the stream was disconnected.
+// Subscriptions filtered by program do not notify on every slot, so jumps between
+// notifications on a live connection are expected and not reported.
+type slotTracker struct {
+	mu          sync.Mutex
+	lastSlot    uint64
+	reconnected bool
+}
+
+// observe records the slot and returns the gap before it, if any
+func (t *slotTracker) observe(slot uint64) (Gap, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reconnected := t.reconnected
+	t.reconnected = false
+
+	if t.lastSlot == 0 || slot <= t.lastSlot {
+		t.lastSlot = max(t.lastSlot, slot)
+		return Gap{}, false
+	}
+
+	previous := t.lastSlot
+	t.lastSlot = slot
+	if slot == previous+1 || !reconnected {
+		return Gap{}, false
+	}
+	return Gap{FromSlot: previous + 1, ToSlot: slot - 1}, true
+}
+
+// markReconnected makes the next observed slot report everything since the last
+// slot before the disconnect as a gap
+func (t *slotTracker) markReconnected() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reconnected = true
+}
+
+// signatureSet remembers recently seen signatures, so transactions that mention several
+// subscribed programs are only delivered once
+type signatureSet struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	seen     map[solana.Signature]*list.Element
+}
+
+func newSignatureSet(capacity int) *signatureSet {
+	return &signatureSet{
+		capacity: capacity,
+		order:    list.New(),
+		seen:     make(map[solana.Signature]*list.Element),
+	}
+}
+
+// add records the signature and reports whether it was new
+func (s *signatureSet) add(signature solana.Signature) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seen[signature]; ok {
+		return false
+	}
+	s.seen[signature] = s.order.PushBack(signature)
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Front()
+		s.order.Remove(oldest)
+		delete(s.seen, oldest.Value.(solana.Signature))
+	}
+	return true
+}
@@ -0,0 +1,285 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+)
+
+// recentSignatures is the number of signatures remembered for de-duplication
+const recentSignatures = 10_000
+
+// Streamer subscribes to program activity over WebSocket and delivers parsed transactions
+type Streamer struct {
+	rpcClient *rpc.Client
+	config    Config
+	results   chan *Result
+
+	// OnGap is called when slots may have been missed, e.g. after a reconnect
+	OnGap func(gap Gap)
+
+	// OnError is called when the connection fails, the streamer keeps reconnecting
+	OnError func(err error)
+
+	slots      *slotTracker
+	signatures *signatureSet
+}
+
+// New creates a new streamer. The RPC client is used to fetch transactions in logs mode.
+func New(rpcClient *rpc.Client, config Config) *Streamer {
+	if config.Mode == "" {
+		config.Mode = ModeLogs
+	}
+	if config.Commitment == "" {
+		config.Commitment = rpc.CommitmentConfirmed
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = 1024
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = time.Second
+	}
+	if config.MaxReconnectDelay <= 0 {
+		config.MaxReconnectDelay = 30 * time.Second
+	}
+	config.Logger = logging.OrNop(config.Logger)
+	if config.ParseOptions.Logger == nil {
+		config.ParseOptions.Logger = config.Logger
+	}
+
+	return &Streamer{
+		rpcClient:  rpcClient,
+		config:     config,
+		results:    make(chan *Result, config.Buffer),
+		slots:      &slotTracker{},
+		signatures: newSignatureSet(recentSignatures),
+	}
+}
+
+// Results returns the channel parsed transactions are delivered on. It is closed when Run returns.
+func (s *Streamer) Results() <-chan *Result {
+	return s.results
+}
+
+// Run connects and streams until the context is cancelled, reconnecting with backoff
+// whenever the connection drops
+func (s *Streamer) Run(ctx context.Context) error {
+	defer close(s.results)
+
+	if s.config.WSURL == "" {
+		return fmt.Errorf("websocket URL is required")
+	}
+	if len(s.config.Programs) == 0 {
+		return fmt.Errorf("at least one program is required")
+	}
+
+	delay := s.config.ReconnectDelay
+	for {
+		started := time.Now()
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			s.config.Logger.WarnContext(ctx, "connection failed, reconnecting", logging.ERROR_KEY, err, "delay", delay)
+			if s.OnError != nil {
+				s.OnError(err)
+			}
+		}
+
+		// Reset the backoff after a connection that stayed up for a while
+		if time.Since(started) > s.config.MaxReconnectDelay {
+			delay = s.config.ReconnectDelay
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, s.config.MaxReconnectDelay)
+		s.slots.markReconnected()
+	}
+}
+
+// runOnce holds a single WebSocket connection until it fails
+func (s *Streamer) runOnce(ctx context.Context) error {
+	client, err := ws.Connect(ctx, s.config.WSURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(s.config.Programs))
+	var wg sync.WaitGroup
+	for _, program := range s.config.Programs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if s.config.Mode == ModeBlocks {
+				err = s.streamBlocks(ctx, client, program)
+			} else {
+				err = s.streamLogs(ctx, client, program)
+			}
+			errs <- err
+		}()
+	}
+
+	// The first failing subscription tears down the connection
+	err = <-errs
+	cancel()
+	wg.Wait()
+	return err
+}
+
+// streamLogs subscribes to logs mentioning the program and fetches each transaction
+func (s *Streamer) streamLogs(ctx context.Context, client *ws.Client, program solana.PublicKey) error {
+	sub, err := client.LogsSubscribeMentions(program, s.config.Commitment)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to logs of %s: %w", program, err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		notification, err := sub.Recv(ctx)
+		if err != nil {
+			return fmt.Errorf("logs subscription of %s failed: %w", program, err)
+		}
+
+		s.observeSlot(notification.Context.Slot)
+		if notification.Value.Err != nil || !s.signatures.add(notification.Value.Signature) {
+			continue
+		}
+
+		result := s.fetchAndParse(ctx, notification.Value.Signature, notification.Context.Slot)
+		if !s.deliver(ctx, result) {
+			return ctx.Err()
+		}
+	}
+}
+
+// streamBlocks subscribes to blocks mentioning the program and parses their transactions
+func (s *Streamer) streamBlocks(ctx context.Context, client *ws.Client, program solana.PublicKey) error {
+	maxVersion := uint64(0)
+	rewards := false
+	sub, err := client.BlockSubscribe(ws.NewBlockSubscribeFilterMentionsAccountOrProgram(program), &ws.BlockSubscribeOpts{
+		Commitment:                     s.config.Commitment,
+		Encoding:                       solana.EncodingBase64,
+		TransactionDetails:             rpc.TransactionDetailsFull,
+		Rewards:                        &rewards,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to blocks of %s: %w", program, err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		notification, err := sub.Recv(ctx)
+		if err != nil {
+			return fmt.Errorf("block subscription of %s failed: %w", program, err)
+		}
+		if notification.Value.Block == nil {
+			continue
+		}
+
+		s.observeSlot(notification.Value.Slot)
+		for _, result := range s.parseBlock(notification.Value.Slot, notification.Value.Block) {
+			if !s.deliver(ctx, result) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// fetchAndParse loads a transaction announced by a logs notification and parses it
+func (s *Streamer) fetchAndParse(ctx context.Context, signature solana.Signature, slot uint64) *Result {
+	result := &Result{Signature: signature, Slot: slot}
+
+	// getTransaction does not support the processed commitment
+	commitment := s.config.Commitment
+	if commitment == rpc.CommitmentProcessed {
+		commitment = rpc.CommitmentConfirmed
+	}
+
+	maxVersion := uint64(0)
+	tx, err := s.rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     commitment,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get transaction: %w", err)
+		return result
+	}
+
+	parser, err := tx_parser.NewWithOptions(tx, s.config.ParseOptions)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create parser: %w", err)
+		return result
+	}
+	result.Transaction, result.Err = parser.Parse()
+	return result
+}
+
+// parseBlock parses the successful transactions of a block that were not delivered yet
+func (s *Streamer) parseBlock(slot uint64, block *rpc.GetBlockResult) []*Result {
+	var results []*Result
+	for i := range block.Transactions {
+		ctx, err := tx_parser.NewTransactionContextFromBlock(slot, block.BlockTime, &block.Transactions[i])
+		if err != nil {
+			results = append(results, &Result{Slot: slot, Err: err})
+			continue
+		}
+		if ctx.Meta.Err != nil || len(ctx.Transaction.Signatures) == 0 {
+			continue
+		}
+
+		signature := ctx.Transaction.Signatures[0]
+		if !s.signatures.add(signature) {
+			continue
+		}
+
+		result := &Result{Signature: signature, Slot: slot}
+		result.Transaction, result.Err = tx_parser.NewFromContext(ctx, s.config.ParseOptions).Parse()
+		results = append(results, result)
+	}
+	return results
+}
+
+// observeSlot reports any gap before the slot
+func (s *Streamer) observeSlot(slot uint64) {
+	gap, ok := s.slots.observe(slot)
+	if !ok {
+		return
+	}
+	s.config.Logger.WarnContext(context.Background(), "possible gap in slots", "from_slot", gap.FromSlot, "to_slot", gap.ToSlot)
+	if s.OnGap != nil {
+		s.OnGap(gap)
+	}
+}
+
+// deliver sends the result unless the context is cancelled first
+func (s *Streamer) deliver(ctx context.Context, result *Result) bool {
+	if result.Err != nil {
+		s.config.Logger.DebugContext(ctx, "failed to parse transaction", logging.SIGNATURE_KEY, result.Signature.String(), logging.SLOT_KEY, result.Slot, logging.ERROR_KEY, result.Err)
+	}
+	select {
+	case s.results <- result:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestSlotTrackerReportsGapsAfterReconnect(t *testing.T) {
+	tracker := &slotTracker{}
+
+	for _, slot := range []uint64{100, 105, 110} {
+		if gap, ok := tracker.observe(slot); ok {
+			t.Fatalf("unexpected gap on a live connection: %+v", gap)
+		}
+	}
+
+	tracker.markReconnected()
+	gap, ok := tracker.observe(150)
+	if !ok || gap.FromSlot != 111 || gap.ToSlot != 149 {
+		t.Fatalf("expected gap 111-149, got %+v (%v)", gap, ok)
+	}
+
+	tracker.markReconnected()
+	if gap, ok := tracker.observe(151); ok {
+		t.Errorf("expected no gap for the next slot, got %+v", gap)
+	}
+}
+
+func TestObserveSlotLogsGaps(t *testing.T) {
+	var logs bytes.Buffer
+	streamer := New(nil, Config{Logger: slog.New(slog.NewJSONHandler(&logs, nil))})
+	var gaps []Gap
+	streamer.OnGap = func(gap Gap) { gaps = append(gaps, gap) }
+
+	streamer.observeSlot(10)
+	streamer.slots.markReconnected()
+	streamer.observeSlot(20)
+
+	if len(gaps) != 1 {
+		t.Fatalf("expected one gap, got %+v", gaps)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single record, got %s", logs.String())
+	}
+	if record["from_slot"] != float64(11) || record["to_slot"] != float64(19) {
+		t.Errorf("unexpected record %v", record)
+	}
+}
+
+func TestSignatureSetDeduplicates(t *testing.T) {
+	set := newSignatureSet(2)
+	a, b, c := solana.Signature{1}, solana.Signature{2}, solana.Signature{3}
+
+	if !set.add(a) || set.add(a) {
+		t.Fatal("expected the first add to succeed and the second to be a duplicate")
+	}
+	set.add(b)
+	set.add(c)
+	if !set.add(a) {
+		t.Error("expected the oldest signature to be forgotten")
+	}
+}
+
+func TestParseBlockDeliversEachTransactionOnce(t *testing.T) {
+	keys := []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.SystemProgramID}
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], 2)
+	binary.LittleEndian.PutUint64(data[4:12], 5_000)
+
+	tx := &solana.Transaction{
+		Signatures: []solana.Signature{{7}},
+		Message: solana.Message{
+			AccountKeys:  keys,
+			Header:       solana.MessageHeader{NumRequiredSignatures: 1},
+			Instructions: []solana.CompiledInstruction{{ProgramIDIndex: 2, Accounts: []uint16{0, 1}, Data: data}},
+		},
+	}
+	encoded, err := tx.ToBase64()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+
+	raw := fmt.Sprintf(`{"parentSlot":9,"transactions":[{"transaction":[%q,"base64"],"meta":{"fee":5000,"preBalances":[],"postBalances":[]}}]}`, encoded)
+	var block rpc.GetBlockResult
+	if err := json.Unmarshal([]byte(raw), &block); err != nil {
+		t.Fatalf("failed to decode block: %v", err)
+	}
+
+	streamer := New(nil, Config{WSURL: "ws://localhost", Programs: keys[2:], Mode: ModeBlocks})
+	results := streamer.parseBlock(10, &block)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("expected one parsed transaction, got %+v", results)
+	}
+	if results[0].Slot != 10 || len(results[0].Transaction.Transfers) != 1 || results[0].Transaction.Transfers[0].Amount != 5_000 {
+		t.Errorf("unexpected result: %+v", results[0].Transaction)
+	}
+
+	// A second subscription delivering the same block is ignored
+	if again := streamer.parseBlock(10, &block); len(again) != 0 {
+		t.Errorf("expected duplicate transactions to be skipped, got %d", len(again))
+	}
+}
+
+func TestRunValidatesConfig(t *testing.T) {
+	if err := New(nil, Config{Programs: []solana.PublicKey{solana.SystemProgramID}}).Run(context.Background()); err == nil {
+		t.Error("expected an error without a websocket URL")
+	}
+	if err := New(nil, Config{WSURL: "ws://localhost"}).Run(context.Background()); err == nil {
+		t.Error("expected an error without programs")
+	}
+}
@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/logging"
+)
+
+// Mode selects the WebSocket subscription used to discover transactions
+type Mode string
+
+const (
+	// ModeLogs uses logsSubscribe and fetches each transaction with getTransaction
+	ModeLogs Mode = "logs"
+
+	// ModeBlocks uses blockSubscribe, which delivers full transactions but is only
+	// enabled on some RPC providers
+	ModeBlocks Mode = "blocks"
+)
+
+// Config controls what the streamer subscribes to
+type Config struct {
+	// WSURL is the WebSocket endpoint, e.g. wss://api.mainnet-beta.solana.com
+	WSURL string
+
+	// Programs whose transactions are streamed
+	Programs []solana.PublicKey
+
+	Mode       Mode
+	Commitment rpc.CommitmentType
+
+	// ParseOptions are passed to the transaction parser
+	ParseOptions tx_parser.ParseOptions
+
+	// Buffer is the size of the results channel, defaults to 1024
+	Buffer int
+
+	// ReconnectDelay is the initial delay before reconnecting, doubled up to
+	// MaxReconnectDelay after each failed attempt. Defaults to 1s and 30s.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+
+	// Logger receives connection failures, slot gaps and transactions that could not be
+	// parsed, defaults to a no-op logger. It is also passed to the parser unless
+	// ParseOptions sets its own.
+	Logger logging.Logger
+}
+
+// Result is a parsed transaction delivered by the stream
+type Result struct {
+	Signature   solana.Signature
+	Slot        uint64
+	Transaction *tx_parser.ParsedTransaction
+	Err         error // set if the transaction could not be fetched or parsed
+}
+
+// Gap is a range of slots that may have been missed, e.g. while reconnecting.
+// Callers can backfill it with getBlocks.
+type Gap struct {
+	FromSlot uint64 // first slot that may be missing
+	ToSlot   uint64 // last slot that may be missing
+}
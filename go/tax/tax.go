@@ -0,0 +1,173 @@
+package tax
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/pnl"
+)
+
+// US tax software expects month first dates
+const usDate = "01/02/2006"
+
+// Write writes one CSV row per disposal sold in the configured period, e.g. those of
+// pnl.Tracker.Disposals
+func Write(w io.Writer, disposals []*pnl.Disposal, config Config) error {
+	config = withDefaults(config)
+
+	var header []string
+	var row func(d *pnl.Disposal) []string
+	switch config.Format {
+	case FormatForm8949:
+		header = []string{"Description of property", "Date acquired", "Date sold or disposed of", "Proceeds", "Cost or other basis", "Gain or (loss)", "Term"}
+		row = func(d *pnl.Disposal) []string {
+			acquired := "VARIOUS"
+			if !d.Acquired.IsZero() {
+				acquired = d.Acquired.In(config.Location).Format(usDate)
+			}
+			return []string{
+				description(d, config), acquired, d.Sold.In(config.Location).Format(usDate),
+				usd(d.ProceedsUSD), usd(d.CostBasisUSD), usd(d.GainUSD), string(TermOf(d)),
+			}
+		}
+	case FormatTurboTax:
+		header = []string{"Currency Name", "Purchase Date", "Cost Basis", "Date Sold", "Proceeds"}
+		row = func(d *pnl.Disposal) []string {
+			acquired := ""
+			if !d.Acquired.IsZero() {
+				acquired = d.Acquired.In(config.Location).Format(usDate)
+			}
+			return []string{
+				config.Symbol(d.Mint), acquired, usd(d.CostBasisUSD), d.Sold.In(config.Location).Format(usDate), usd(d.ProceedsUSD),
+			}
+		}
+	case FormatDetailed:
+		header = []string{
+			"signature", "mint", "symbol", "amount", "acquired", "sold", "holding_days", "term",
+			"proceeds_usd", "cost_basis_usd", "gain_usd", "proceeds_sol", "cost_basis_sol", "gain_sol",
+		}
+		row = func(d *pnl.Disposal) []string {
+			acquired, days := "", ""
+			if !d.Acquired.IsZero() {
+				acquired = d.Acquired.In(config.Location).Format(time.RFC3339)
+				days = strconv.Itoa(int(d.Sold.Sub(d.Acquired).Hours() / 24))
+			}
+			return []string{
+				d.Signature.String(), d.Mint.String(), config.Symbol(d.Mint), amount(d.Amount, d.Decimals),
+				acquired, d.Sold.In(config.Location).Format(time.RFC3339), days, string(TermOf(d)),
+				usd(d.ProceedsUSD), usd(d.CostBasisUSD), usd(d.GainUSD),
+				sol(d.ProceedsSOL), sol(d.CostBasisSOL), sol(d.GainSOL),
+			}
+		}
+	default:
+		return fmt.Errorf("unknown format %q", config.Format)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(header)
+	for _, d := range disposals {
+		if inPeriod(d, config) {
+			writer.Write(row(d))
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// Summarize totals the disposals sold in the configured period
+func Summarize(disposals []*pnl.Disposal, config Config) *Summary {
+	config = withDefaults(config)
+
+	summary := &Summary{}
+	for _, d := range disposals {
+		if !inPeriod(d, config) {
+			continue
+		}
+		summary.Disposals++
+		summary.ProceedsUSD += d.ProceedsUSD
+		summary.CostBasisUSD += d.CostBasisUSD
+		switch TermOf(d) {
+		case TermShort:
+			summary.ShortTermUSD += d.GainUSD
+		case TermLong:
+			summary.LongTermUSD += d.GainUSD
+		default:
+			summary.UnknownTermUSD += d.GainUSD
+		}
+	}
+	return summary
+}
+
+// TermOf classifies the disposal's holding period, long term when held more than a year
+func TermOf(d *pnl.Disposal) Term {
+	switch {
+	case d.Acquired.IsZero():
+		return TermUnknown
+	case d.Sold.After(d.Acquired.AddDate(1, 0, 0)):
+		return TermLong
+	}
+	return TermShort
+}
+
+// withDefaults fills in unset options
+func withDefaults(config Config) Config {
+	if config.Format == "" {
+		config.Format = FormatForm8949
+	}
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	symbol := config.Symbol
+	config.Symbol = func(mint solana.PublicKey) string {
+		if symbol != nil {
+			if name := symbol(mint); name != "" {
+				return name
+			}
+		}
+		return mint.String()
+	}
+	return config
+}
+
+// inPeriod reports whether the disposal was sold in [From, To)
+func inPeriod(d *pnl.Disposal, config Config) bool {
+	if !config.From.IsZero() && d.Sold.Before(config.From) {
+		return false
+	}
+	return config.To.IsZero() || d.Sold.Before(config.To)
+}
+
+// description describes the property sold, e.g. "1.5 BONK"
+func description(d *pnl.Disposal, config Config) string {
+	return amount(d.Amount, d.Decimals) + " " + config.Symbol(d.Mint)
+}
+
+// amount formats a raw token amount in whole tokens, exactly
+func amount(raw uint64, decimals uint8) string {
+	digits := strconv.FormatUint(raw, 10)
+	if decimals == 0 {
+		return digits
+	}
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	split := len(digits) - int(decimals)
+	return strings.TrimSuffix(strings.TrimRight(digits[:split]+"."+digits[split:], "0"), ".")
+}
+
+func usd(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func sol(value float64) string {
+	return strconv.FormatFloat(value, 'f', 9, 64)
+}
@@ -0,0 +1,101 @@
+package tax
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/pnl"
+)
+
+var bonk = solana.MustPublicKeyFromBase58("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263")
+
+func testDisposals() []*pnl.Disposal {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 15, 0, 0, 0, time.UTC)
+	}
+	return []*pnl.Disposal{
+		// held over a year
+		{Mint: bonk, Amount: 1_500_000, Decimals: 5, Acquired: day(2023, 1, 10), Sold: day(2024, 3, 1), ProceedsUSD: 300, CostBasisUSD: 100, GainUSD: 200},
+		{Mint: bonk, Amount: 500_000, Decimals: 5, Acquired: day(2024, 2, 1), Sold: day(2024, 3, 1), ProceedsUSD: 100, CostBasisUSD: 150, GainUSD: -50},
+		// sold without a known acquisition
+		{Mint: bonk, Amount: 100_000, Decimals: 5, Sold: day(2024, 12, 31), ProceedsUSD: 20, GainUSD: 20},
+		// outside the tax year
+		{Mint: bonk, Amount: 100_000, Decimals: 5, Acquired: day(2024, 12, 1), Sold: day(2025, 1, 2), ProceedsUSD: 5, GainUSD: 5},
+	}
+}
+
+func year2024() Config {
+	return Config{From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func readReport(t *testing.T, disposals []*pnl.Disposal, config Config) [][]string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, disposals, config); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	return rows
+}
+
+func TestForm8949(t *testing.T) {
+	config := year2024()
+	config.Symbol = func(mint solana.PublicKey) string { return "BONK" }
+	rows := readReport(t, testDisposals(), config)
+
+	if len(rows) != 4 {
+		t.Fatalf("expected a header and three disposals of 2024, got %d rows", len(rows))
+	}
+	expected := []string{
+		"15 BONK,01/10/2023,03/01/2024,300.00,100.00,200.00,long",
+		"5 BONK,02/01/2024,03/01/2024,100.00,150.00,-50.00,short",
+		"1 BONK,VARIOUS,12/31/2024,20.00,0.00,20.00,unknown",
+	}
+	for i, row := range rows[1:] {
+		if line := strings.Join(row, ","); line != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], line)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	config := year2024()
+	config.Format = FormatTurboTax
+	rows := readReport(t, testDisposals(), config)
+	if strings.Join(rows[0], ",") != "Currency Name,Purchase Date,Cost Basis,Date Sold,Proceeds" {
+		t.Errorf("unexpected header %v", rows[0])
+	}
+	if strings.Join(rows[1], ",") != bonk.String()+",01/10/2023,100.00,03/01/2024,300.00" {
+		t.Errorf("expected the mint named by its address, got %v", rows[1])
+	}
+
+	config.Format = FormatDetailed
+	config.Location = time.FixedZone("UTC+9", 9*3600)
+	rows = readReport(t, testDisposals(), config)
+	if rows[1][4] != "2023-01-11T00:00:00+09:00" || rows[1][6] != "416" || rows[3][6] != "" {
+		t.Errorf("unexpected detailed rows %v", rows[1:])
+	}
+
+	config.Format = "nope"
+	if err := Write(&bytes.Buffer{}, nil, config); err == nil {
+		t.Error("expected an unknown format to fail")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summary := Summarize(testDisposals(), year2024())
+	if summary.Disposals != 3 || summary.ProceedsUSD != 420 || summary.CostBasisUSD != 250 {
+		t.Errorf("unexpected totals %+v", summary)
+	}
+	if summary.LongTermUSD != 200 || summary.ShortTermUSD != -50 || summary.UnknownTermUSD != 20 {
+		t.Errorf("unexpected gains by term %+v", summary)
+	}
+}
@@ -0,0 +1,55 @@
+package tax
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Format is the CSV layout of a lot report
+type Format string
+
+const (
+	// FormatForm8949 follows IRS Form 8949 columns, importable by most US tax software
+	FormatForm8949 Format = "form8949"
+	// FormatTurboTax is the TurboTax gains and losses upload for other crypto sources
+	FormatTurboTax Format = "turbotax"
+	// FormatDetailed has every field of the disposals, with ISO dates and SOL values
+	FormatDetailed Format = "detailed"
+)
+
+// Term is the holding period class of a disposal
+type Term string
+
+const (
+	TermShort   Term = "short"
+	TermLong    Term = "long"
+	TermUnknown Term = "unknown" // acquisition not seen by the tracker
+)
+
+// Config controls lot reports
+type Config struct {
+	// Format defaults to FormatForm8949
+	Format Format
+
+	// From and To select disposals sold in [From, To), zero bounds are open, e.g. a tax year
+	From time.Time
+	To   time.Time
+
+	// Symbol names mints in descriptions, e.g. from token metadata. Defaults to the mint
+	// address, an empty result also falls back to it.
+	Symbol func(mint solana.PublicKey) string
+
+	// Location is the time zone of report dates, defaults to UTC
+	Location *time.Location
+}
+
+// Summary totals the disposals of a report by term, in USD
+type Summary struct {
+	Disposals      int     `json:"disposals"`
+	ProceedsUSD    float64 `json:"proceeds_usd"`
+	CostBasisUSD   float64 `json:"cost_basis_usd"`
+	ShortTermUSD   float64 `json:"short_term_usd"`
+	LongTermUSD    float64 `json:"long_term_usd"`
+	UnknownTermUSD float64 `json:"unknown_term_usd"` // gains on tokens without a known acquisition
+}
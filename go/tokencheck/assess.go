@@ -0,0 +1,78 @@
+package tokencheck
+
+import (
+	"fmt"
+)
+
+// levelRank orders levels by severity
+var levelRank = map[Level]int{LevelInfo: 1, LevelWarning: 2, LevelDanger: 3}
+
+// assess turns the collected facts into findings and the overall risk
+func (c *Checker) assess(report *Report, decoded *mint) {
+	add := func(check string, level Level, format string, args ...any) {
+		report.Findings = append(report.Findings, Finding{Check: check, Level: level, Message: fmt.Sprintf(format, args...)})
+		if levelRank[level] > levelRank[report.Risk] {
+			report.Risk = level
+		}
+	}
+
+	if report.MintAuthority != nil {
+		add("mint_authority", LevelDanger, "%s can mint more tokens", report.MintAuthority)
+	}
+	if report.FreezeAuthority != nil {
+		add("freeze_authority", LevelDanger, "%s can freeze token accounts", report.FreezeAuthority)
+	}
+
+	for _, ext := range decoded.extensions {
+		switch ext.kind {
+		case TRANSFER_FEE_CONFIG_EXTENSION:
+			add("transfer_fee", LevelWarning, "transfers are taxed %d bps, the fee can be changed", transferFeeBasisPoints(ext.value))
+		case PERMANENT_DELEGATE_EXTENSION:
+			if delegate := nonZeroKey(ext.value, 0); delegate != nil {
+				add("permanent_delegate", LevelDanger, "%s can transfer or burn tokens from any account", delegate)
+			}
+		case TRANSFER_HOOK_EXTENSION:
+			if program := nonZeroKey(ext.value, 32); program != nil {
+				add("transfer_hook", LevelDanger, "transfers invoke program %s, which can block them", program)
+			}
+		case NON_TRANSFERABLE_EXTENSION:
+			add("non_transferable", LevelDanger, "tokens cannot be transferred")
+		case DEFAULT_ACCOUNT_STATE_EXTENSION:
+			if len(ext.value) > 0 && ext.value[0] == frozenState {
+				add("default_frozen", LevelDanger, "new token accounts start frozen")
+			}
+		case PAUSABLE_EXTENSION:
+			add("pausable", LevelDanger, "the pause authority can halt all transfers")
+		case MINT_CLOSE_AUTHORITY_EXTENSION:
+			if authority := nonZeroKey(ext.value, 0); authority != nil {
+				add("close_authority", LevelWarning, "%s can close the mint once the supply is zero", authority)
+			}
+		}
+	}
+
+	switch {
+	case report.TopHoldersPercent >= c.config.ConcentrationDanger:
+		add("concentration", LevelDanger, "top %d holders own %.1f%% of the supply", len(report.TopHolders), report.TopHoldersPercent)
+	case report.TopHoldersPercent >= c.config.ConcentrationWarning:
+		add("concentration", LevelWarning, "top %d holders own %.1f%% of the supply", len(report.TopHolders), report.TopHoldersPercent)
+	}
+
+	if len(report.Liquidity) == 0 {
+		add("liquidity", LevelWarning, "no pools found")
+	}
+	for _, liquidity := range report.Liquidity {
+		switch liquidity.Status {
+		case LiquidityUnlocked:
+			add("liquidity", LevelDanger, "%.1f%% of the LP tokens of pool %s are neither burned nor locked",
+				100-liquidity.BurnedPercent-liquidity.LockedPercent, liquidity.Pool)
+		case LiquidityBondingCurve:
+			add("liquidity", LevelInfo, "trading on bonding curve %s", liquidity.Pool)
+		case LiquidityUnknown:
+			add("liquidity", LevelInfo, "LP status of %s pool %s is not tracked", liquidity.Protocol, liquidity.Pool)
+		}
+	}
+
+	if report.Risk == "" {
+		report.Risk = LevelInfo
+	}
+}
@@ -0,0 +1,12 @@
+package tokencheck
+
+// Token-2022 extension types that let the issuer control or tax transfers
+const (
+	TRANSFER_FEE_CONFIG_EXTENSION   = 1
+	MINT_CLOSE_AUTHORITY_EXTENSION  = 3
+	DEFAULT_ACCOUNT_STATE_EXTENSION = 6
+	NON_TRANSFERABLE_EXTENSION      = 9
+	PERMANENT_DELEGATE_EXTENSION    = 12
+	TRANSFER_HOOK_EXTENSION         = 14
+	PAUSABLE_EXTENSION              = 26
+)
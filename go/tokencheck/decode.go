@@ -0,0 +1,102 @@
+package tokencheck
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Token mint layout shared by both token programs
+const (
+	mintLength        = 82
+	accountTypeOffset = 165
+	mintAccountType   = 1
+	frozenState       = 2
+)
+
+// extension is a Token-2022 TLV entry
+type extension struct {
+	kind  uint16
+	value []byte
+}
+
+// name returns the extension's name
+func (e extension) name() string {
+	return tx_parser.TokenExtensionName(e.kind)
+}
+
+// mint is the decoded base mint and its extensions
+type mint struct {
+	mintAuthority   *solana.PublicKey
+	supply          uint64
+	decimals        uint8
+	freezeAuthority *solana.PublicKey
+	extensions      []extension
+}
+
+// decodeMint decodes a mint of either token program
+func decodeMint(address solana.PublicKey, data []byte) (*mint, error) {
+	if len(data) < mintLength {
+		return nil, fmt.Errorf("account %s is not a mint", address)
+	}
+
+	decoded := &mint{
+		mintAuthority:   optionalKey(data[0:36]),
+		supply:          binary.LittleEndian.Uint64(data[36:44]),
+		decimals:        data[44],
+		freezeAuthority: optionalKey(data[46:82]),
+	}
+	if len(data) <= accountTypeOffset || data[accountTypeOffset] != mintAccountType {
+		return decoded, nil
+	}
+
+	for offset := accountTypeOffset + 1; offset+4 <= len(data); {
+		kind := binary.LittleEndian.Uint16(data[offset:])
+		length := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		offset += 4
+		if kind == 0 {
+			break
+		}
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("extension %d of %s overruns the account", kind, address)
+		}
+		decoded.extensions = append(decoded.extensions, extension{kind: kind, value: data[offset : offset+length]})
+		offset += length
+	}
+	return decoded, nil
+}
+
+// optionalKey decodes a COption<Pubkey>, a u32 tag followed by the key
+func optionalKey(data []byte) *solana.PublicKey {
+	if binary.LittleEndian.Uint32(data[0:4]) == 0 {
+		return nil
+	}
+	key := solana.PublicKeyFromBytes(data[4:36])
+	return &key
+}
+
+// nonZeroKey decodes an OptionalNonZeroPubkey at the offset, nil when unset or out of range
+func nonZeroKey(data []byte, offset int) *solana.PublicKey {
+	if len(data) < offset+solana.PublicKeyLength {
+		return nil
+	}
+	key := solana.PublicKeyFromBytes(data[offset : offset+solana.PublicKeyLength])
+	if key.IsZero() {
+		return nil
+	}
+	return &key
+}
+
+// transferFeeBasisPoints returns the newer transfer fee of a TransferFeeConfig extension
+func transferFeeBasisPoints(value []byte) uint16 {
+	// authorities (2 x 32), withheld amount u64, older fee (epoch u64, maximum u64, bps u16),
+	// then the newer fee's epoch and maximum
+	const offset = 64 + 8 + 18 + 16
+	if len(value) < offset+2 {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(value[offset:])
+}
@@ -0,0 +1,259 @@
+package tokencheck
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/holders"
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+	"github.com/soralabs/solana-toolkit/go/pricing"
+)
+
+// Checker inspects mints for issuer controls, holder concentration and unsecured liquidity
+type Checker struct {
+	rpcClient *rpc.Client
+	reader    *pools.Reader
+	scanner   *holders.Scanner
+	config    Config
+}
+
+// New creates a token checker
+func New(rpcClient *rpc.Client, config Config) *Checker {
+	if config.Registry == nil {
+		config.Registry = pools.NewRegistry(rpcClient, pools.RegistryConfig{})
+	}
+	if len(config.QuoteMints) == 0 {
+		config.QuoteMints = []solana.PublicKey{tx_parser.NATIVE_SOL_PROGRAM_ID, pricing.USDC_MINT}
+	}
+	if config.TopHolders <= 0 {
+		config.TopHolders = 10
+	}
+	if config.ConcentrationWarning <= 0 {
+		config.ConcentrationWarning = 30
+	}
+	if config.ConcentrationDanger <= 0 {
+		config.ConcentrationDanger = 50
+	}
+	if config.SecuredLiquidity <= 0 {
+		config.SecuredLiquidity = 95
+	}
+
+	return &Checker{
+		rpcClient: rpcClient,
+		reader:    pools.New(rpcClient),
+		scanner:   holders.New(rpcClient, holders.Config{}),
+		config:    config,
+	}
+}
+
+// Check inspects the mint and returns its risk report
+func (c *Checker) Check(ctx context.Context, address solana.PublicKey) (*Report, error) {
+	accounts, err := c.accounts(ctx, []solana.PublicKey{address})
+	if err != nil {
+		return nil, err
+	}
+	account := accounts[address]
+	if account == nil {
+		return nil, fmt.Errorf("mint %s not found", address)
+	}
+	decoded, err := decodeMint(address, account.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Mint:            address,
+		Program:         account.Owner,
+		Supply:          decoded.supply,
+		Decimals:        decoded.decimals,
+		MintAuthority:   decoded.mintAuthority,
+		FreezeAuthority: decoded.freezeAuthority,
+	}
+	for _, ext := range decoded.extensions {
+		report.Extensions = append(report.Extensions, ext.name())
+	}
+
+	vaults, err := c.liquidity(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.holders(ctx, report, vaults); err != nil {
+		return nil, err
+	}
+
+	c.assess(report, decoded)
+	return report, nil
+}
+
+// liquidity fills in the LP status of every pool pairing the mint with a quote mint and
+// returns the token accounts holding pool liquidity
+func (c *Checker) liquidity(ctx context.Context, report *Report) (map[solana.PublicKey]bool, error) {
+	vaults := make(map[solana.PublicKey]bool)
+
+	var addresses []solana.PublicKey
+	for _, quote := range c.config.QuoteMints {
+		found, err := c.config.Registry.Discover(ctx, report.Mint, quote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover pools: %w", err)
+		}
+		for _, pool := range found {
+			if pool.Protocol != tx_parser.SwapTypePumpFun {
+				addresses = append(addresses, pool.Address)
+				continue
+			}
+			_, curveTokens, err := pumpfun.DeriveBondingCurveAddresses(report.Mint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to derive bonding curve of %s: %w", report.Mint, err)
+			}
+			vaults[curveTokens] = true
+			report.Liquidity = append(report.Liquidity, Liquidity{
+				Pool:      pool.Address,
+				Protocol:  pool.Protocol,
+				QuoteMint: quote,
+				Status:    LiquidityBondingCurve,
+			})
+		}
+	}
+	if len(addresses) == 0 {
+		return vaults, nil
+	}
+
+	states, err := c.reader.Load(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		mintA, mintB := state.Mints()
+		liquidity := Liquidity{
+			Pool:      state.Address(),
+			Protocol:  state.Protocol(),
+			QuoteMint: mintA,
+			Status:    LiquidityUnknown,
+		}
+		if mintA.Equals(report.Mint) {
+			liquidity.QuoteMint = mintB
+		}
+
+		switch pool := state.(type) {
+		case *pools.RaydiumAMM:
+			vaults[pool.CoinVault], vaults[pool.PCVault] = true, true
+			if err := c.lpStatus(ctx, pool, &liquidity); err != nil {
+				return nil, err
+			}
+		case *pools.ConcentratedPool:
+			vaults[pool.VaultA], vaults[pool.VaultB] = true, true
+		case *pools.DLMMPool:
+			vaults[pool.ReserveXAccount], vaults[pool.ReserveYAccount] = true, true
+		}
+		report.Liquidity = append(report.Liquidity, liquidity)
+	}
+	return vaults, nil
+}
+
+// lpStatus measures how much of a Raydium AMM pool's LP tokens were burned or are held by lockers
+func (c *Checker) lpStatus(ctx context.Context, pool *pools.RaydiumAMM, liquidity *Liquidity) error {
+	liquidity.LPMint = pool.LPMint
+	liquidity.LPIssued = pool.LPAmount
+
+	accounts, err := c.accounts(ctx, []solana.PublicKey{pool.LPMint})
+	if err != nil {
+		return err
+	}
+	if accounts[pool.LPMint] == nil {
+		return fmt.Errorf("LP mint %s not found", pool.LPMint)
+	}
+	lpMint, err := decodeMint(pool.LPMint, accounts[pool.LPMint].Data.GetBinary())
+	if err != nil {
+		return err
+	}
+	liquidity.LPSupply = lpMint.supply
+
+	if pool.LPAmount > 0 && lpMint.supply < pool.LPAmount {
+		liquidity.BurnedPercent = 100 * float64(pool.LPAmount-lpMint.supply) / float64(pool.LPAmount)
+	}
+
+	if len(c.config.Lockers) > 0 && pool.LPAmount > 0 {
+		largest, err := c.largestAccounts(ctx, pool.LPMint)
+		if err != nil {
+			return err
+		}
+		var locked uint64
+		for _, holder := range largest {
+			if slices.ContainsFunc(c.config.Lockers, holder.Owner.Equals) {
+				locked += holder.Amount
+			}
+		}
+		liquidity.LockedPercent = 100 * float64(locked) / float64(pool.LPAmount)
+	}
+
+	liquidity.Status = LiquidityUnlocked
+	if liquidity.BurnedPercent+liquidity.LockedPercent >= c.config.SecuredLiquidity {
+		liquidity.Status = LiquidityBurned
+	}
+	return nil
+}
+
+// holders fills in the largest holders, skipping pool vaults
+func (c *Checker) holders(ctx context.Context, report *Report, vaults map[solana.PublicKey]bool) error {
+	largest, err := c.largestAccounts(ctx, report.Mint)
+	if err != nil {
+		return err
+	}
+
+	for _, holder := range largest {
+		if vaults[holder.Account] || holder.Amount == 0 {
+			continue
+		}
+		if len(report.TopHolders) == c.config.TopHolders {
+			break
+		}
+		if report.Supply > 0 {
+			holder.Percent = 100 * float64(holder.Amount) / float64(report.Supply)
+		}
+		report.TopHolders = append(report.TopHolders, holder)
+		report.TopHoldersPercent += holder.Percent
+	}
+	return nil
+}
+
+// largestAccounts returns the largest token accounts of the mint with their owners
+func (c *Checker) largestAccounts(ctx context.Context, mint solana.PublicKey) ([]Holder, error) {
+	largest, err := c.scanner.Largest(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]Holder, 0, len(largest))
+	for _, holder := range largest {
+		accounts = append(accounts, Holder{Account: holder.Accounts[0], Owner: holder.Owner, Amount: holder.Amount})
+	}
+	return accounts, nil
+}
+
+// accounts reads up to 100 accounts, returning them by address. Missing accounts are absent.
+func (c *Checker) accounts(ctx context.Context, addresses []solana.PublicKey) (map[solana.PublicKey]*rpc.Account, error) {
+	accounts := make(map[solana.PublicKey]*rpc.Account, len(addresses))
+	if len(addresses) == 0 {
+		return accounts, nil
+	}
+
+	result, err := c.rpcClient.GetMultipleAccountsWithOpts(ctx, addresses, &rpc.GetMultipleAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Encoding:   solana.EncodingBase64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	for i, account := range result.Value {
+		if account != nil && i < len(addresses) {
+			accounts[addresses[i]] = account
+		}
+	}
+	return accounts, nil
+}
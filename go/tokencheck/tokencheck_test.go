@@ -0,0 +1,232 @@
+package tokencheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+type testAccount struct {
+	owner solana.PublicKey
+	data  []byte
+}
+
+func newKey() solana.PublicKey {
+	return solana.NewWallet().PublicKey()
+}
+
+func mintData(supply uint64, decimals uint8, mintAuthority, freezeAuthority *solana.PublicKey) []byte {
+	data := make([]byte, mintLength)
+	if mintAuthority != nil {
+		data[0] = 1
+		copy(data[4:36], mintAuthority[:])
+	}
+	binary.LittleEndian.PutUint64(data[36:44], supply)
+	data[44] = decimals
+	data[45] = 1
+	if freezeAuthority != nil {
+		data[46] = 1
+		copy(data[50:82], freezeAuthority[:])
+	}
+	return data
+}
+
+func appendExtension(data []byte, kind uint16, value []byte) []byte {
+	data = binary.LittleEndian.AppendUint16(data, kind)
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(value)))
+	return append(data, value...)
+}
+
+func tokenAccount(mint, owner solana.PublicKey, amount uint64) testAccount {
+	data := make([]byte, 165)
+	copy(data[0:32], mint[:])
+	copy(data[32:64], owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], amount)
+	return testAccount{owner: solana.TokenProgramID, data: data}
+}
+
+type testChain struct {
+	accounts map[solana.PublicKey]testAccount
+	largest  map[solana.PublicKey][]solana.PublicKey // mint to its largest token accounts
+}
+
+func (c *testChain) serve(t *testing.T) *rpc.Client {
+	t.Helper()
+	encode := func(account testAccount, data []byte) string {
+		return fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+			account.owner, base64.StdEncoding.EncodeToString(data))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		var result string
+		switch request.Method {
+		case "getMultipleAccounts":
+			var keys []solana.PublicKey
+			json.Unmarshal(request.Params[0], &keys)
+			values := make([]string, len(keys))
+			for i, key := range keys {
+				values[i] = "null"
+				if account, ok := c.accounts[key]; ok {
+					values[i] = encode(account, account.data)
+				}
+			}
+			result = fmt.Sprintf(`{"context":{"slot":1},"value":[%s]}`, strings.Join(values, ","))
+		case "getProgramAccounts":
+			var program solana.PublicKey
+			json.Unmarshal(request.Params[0], &program)
+			var opts rpc.GetProgramAccountsOpts
+			json.Unmarshal(request.Params[1], &opts)
+			var values []string
+			for key, account := range c.accounts {
+				matches := account.owner.Equals(program)
+				for _, filter := range opts.Filters {
+					if filter.DataSize > 0 && uint64(len(account.data)) != filter.DataSize {
+						matches = false
+					}
+					if m := filter.Memcmp; m != nil {
+						end := m.Offset + uint64(len(m.Bytes))
+						if end > uint64(len(account.data)) || !bytes.Equal(account.data[m.Offset:end], m.Bytes) {
+							matches = false
+						}
+					}
+				}
+				if matches {
+					data := account.data[*opts.DataSlice.Offset : *opts.DataSlice.Offset+*opts.DataSlice.Length]
+					values = append(values, fmt.Sprintf(`{"pubkey":%q,"account":%s}`, key, encode(account, data)))
+				}
+			}
+			result = "[" + strings.Join(values, ",") + "]"
+		case "getTokenLargestAccounts":
+			var mint solana.PublicKey
+			json.Unmarshal(request.Params[0], &mint)
+			var values []string
+			for _, key := range c.largest[mint] {
+				amount := binary.LittleEndian.Uint64(c.accounts[key].data[64:72])
+				values = append(values, fmt.Sprintf(`{"address":%q,"amount":"%d","decimals":6}`, key, amount))
+			}
+			result = fmt.Sprintf(`{"context":{"slot":1},"value":[%s]}`, strings.Join(values, ","))
+		default:
+			t.Errorf("unexpected method %s", request.Method)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, result)
+	}))
+	t.Cleanup(server.Close)
+	return rpc.New(server.URL)
+}
+
+func TestCheck(t *testing.T) {
+	mint, freezer, hookProgram := newKey(), newKey(), newKey()
+	pool, coinVault, pcVault, lpMint := newKey(), newKey(), newKey(), newKey()
+	whale, minnow, lockerOwner := newKey(), newKey(), newKey()
+	whaleAccount, minnowAccount, lockedLP, unlockedLP := newKey(), newKey(), newKey(), newKey()
+
+	// Token-2022 mint with a 1% transfer fee and a transfer hook
+	data := mintData(1_000_000, 6, nil, &freezer)
+	data = append(data, make([]byte, accountTypeOffset-len(data))...)
+	data = append(data, mintAccountType)
+	transferFee := make([]byte, 108)
+	binary.LittleEndian.PutUint16(transferFee[106:], 100)
+	data = appendExtension(data, TRANSFER_FEE_CONFIG_EXTENSION, transferFee)
+	data = appendExtension(data, TRANSFER_HOOK_EXTENSION, append(make([]byte, 32), hookProgram[:]...))
+
+	amm := make([]byte, 752)
+	binary.LittleEndian.PutUint64(amm[184:], 10_000)
+	copy(amm[336:], coinVault[:])
+	copy(amm[368:], pcVault[:])
+	copy(amm[400:], mint[:])
+	copy(amm[432:], tx_parser.NATIVE_SOL_PROGRAM_ID[:])
+	copy(amm[464:], lpMint[:])
+	binary.LittleEndian.PutUint64(amm[720:], 1_000)
+
+	chain := &testChain{
+		accounts: map[solana.PublicKey]testAccount{
+			mint:          {owner: solana.Token2022ProgramID, data: data},
+			pool:          {owner: tx_parser.RAYDIUM_V4_PROGRAM_ID, data: amm},
+			coinVault:     tokenAccount(mint, pool, 500_000),
+			pcVault:       tokenAccount(tx_parser.NATIVE_SOL_PROGRAM_ID, pool, 10_000),
+			lpMint:        {owner: solana.TokenProgramID, data: mintData(600, 9, nil, nil)},
+			whaleAccount:  tokenAccount(mint, whale, 250_000),
+			minnowAccount: tokenAccount(mint, minnow, 100_000),
+			lockedLP:      tokenAccount(lpMint, lockerOwner, 580),
+			unlockedLP:    tokenAccount(lpMint, whale, 20),
+		},
+		largest: map[solana.PublicKey][]solana.PublicKey{
+			mint:   {coinVault, whaleAccount, minnowAccount},
+			lpMint: {lockedLP, unlockedLP},
+		},
+	}
+
+	checker := New(chain.serve(t), Config{Lockers: []solana.PublicKey{lockerOwner}})
+	report, err := checker.Check(context.Background(), mint)
+	if err != nil {
+		t.Fatalf("failed to check mint: %v", err)
+	}
+
+	if report.MintAuthority != nil || report.FreezeAuthority == nil || !report.FreezeAuthority.Equals(freezer) || report.Supply != 1_000_000 {
+		t.Errorf("unexpected mint fields: %+v", report)
+	}
+	if fmt.Sprint(report.Extensions) != "[TransferFeeConfig TransferHook]" {
+		t.Errorf("unexpected extensions %v", report.Extensions)
+	}
+
+	if len(report.TopHolders) != 2 || !report.TopHolders[0].Owner.Equals(whale) || report.TopHolders[0].Percent != 25 || report.TopHoldersPercent != 35 {
+		t.Errorf("expected the pool vault to be excluded from holders, got %+v", report.TopHolders)
+	}
+
+	if len(report.Liquidity) != 1 {
+		t.Fatalf("expected one pool, got %+v", report.Liquidity)
+	}
+	lp := report.Liquidity[0]
+	if lp.Status != LiquidityBurned || lp.BurnedPercent != 40 || lp.LockedPercent != 58 || !lp.QuoteMint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		t.Errorf("unexpected liquidity: %+v", lp)
+	}
+
+	checks := make(map[string]Level)
+	for _, finding := range report.Findings {
+		checks[finding.Check] = finding.Level
+	}
+	expected := map[string]Level{
+		"freeze_authority": LevelDanger,
+		"transfer_fee":     LevelWarning,
+		"transfer_hook":    LevelDanger,
+		"concentration":    LevelWarning,
+	}
+	if fmt.Sprint(checks) != fmt.Sprint(expected) {
+		t.Errorf("unexpected findings %+v", report.Findings)
+	}
+	if report.Risk != LevelDanger {
+		t.Errorf("expected danger risk, got %s", report.Risk)
+	}
+
+	// Without lockers the remaining LP tokens count as withdrawable
+	report, err = New(chain.serve(t), Config{}).Check(context.Background(), mint)
+	if err != nil {
+		t.Fatalf("failed to check mint: %v", err)
+	}
+	if report.Liquidity[0].Status != LiquidityUnlocked {
+		t.Errorf("expected unlocked liquidity, got %+v", report.Liquidity[0])
+	}
+}
@@ -0,0 +1,95 @@
+package tokencheck
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+// Level is the severity of a finding
+type Level string
+
+const (
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelDanger  Level = "danger"
+)
+
+// Finding is a single risk detected for a mint
+type Finding struct {
+	Check   string `json:"check"`
+	Level   Level  `json:"level"`
+	Message string `json:"message"`
+}
+
+// Holder is a token account among the largest holders, pool vaults excluded
+type Holder struct {
+	Account solana.PublicKey `json:"account"`
+	Owner   solana.PublicKey `json:"owner"`
+	Amount  uint64           `json:"amount,string"`
+	Percent float64          `json:"percent"` // share of the supply, 0 to 100
+}
+
+// LiquidityStatus describes whether liquidity providers can pull a pool's liquidity
+type LiquidityStatus string
+
+const (
+	LiquidityBurned       LiquidityStatus = "burned"        // LP tokens burned or locked above the configured share
+	LiquidityUnlocked     LiquidityStatus = "unlocked"      // LP tokens held by wallets that can withdraw
+	LiquidityBondingCurve LiquidityStatus = "bonding_curve" // no LP tokens, the curve holds the liquidity
+	LiquidityUnknown      LiquidityStatus = "unknown"       // positions are NFTs and are not tracked
+)
+
+// Liquidity is the LP status of a pool trading the mint
+type Liquidity struct {
+	Pool          solana.PublicKey   `json:"pool"`
+	Protocol      tx_parser.SwapType `json:"protocol"`
+	QuoteMint     solana.PublicKey   `json:"quote_mint"`
+	Status        LiquidityStatus    `json:"status"`
+	LPMint        solana.PublicKey   `json:"lp_mint,omitzero"`
+	LPIssued      uint64             `json:"lp_issued,string"`
+	LPSupply      uint64             `json:"lp_supply,string"`
+	BurnedPercent float64            `json:"burned_percent"`
+	LockedPercent float64            `json:"locked_percent"`
+}
+
+// Report is the risk assessment of a mint
+type Report struct {
+	Mint              solana.PublicKey  `json:"mint"`
+	Program           solana.PublicKey  `json:"program"`
+	Supply            uint64            `json:"supply,string"`
+	Decimals          uint8             `json:"decimals"`
+	MintAuthority     *solana.PublicKey `json:"mint_authority"`
+	FreezeAuthority   *solana.PublicKey `json:"freeze_authority"`
+	Extensions        []string          `json:"extensions"`
+	TopHolders        []Holder          `json:"top_holders"`
+	TopHoldersPercent float64           `json:"top_holders_percent"`
+	Liquidity         []Liquidity       `json:"liquidity"`
+	Findings          []Finding         `json:"findings"`
+	Risk              Level             `json:"risk"` // highest level among the findings
+}
+
+// Config controls the checks
+type Config struct {
+	// Registry discovers the mint's pools, defaults to a new registry over the checker's client
+	Registry *pools.Registry
+
+	// QuoteMints are paired with the mint to find pools, defaults to SOL and USDC
+	QuoteMints []solana.PublicKey
+
+	// TopHolders is the number of largest holders reported, defaults to 10
+	TopHolders int
+
+	// ConcentrationWarning and ConcentrationDanger are the shares of the supply, 0 to 100,
+	// held by the top holders that raise a finding. Default to 30 and 50.
+	ConcentrationWarning float64
+	ConcentrationDanger  float64
+
+	// SecuredLiquidity is the share of LP tokens, 0 to 100, that must be burned or locked
+	// for a pool to count as burned, defaults to 95
+	SecuredLiquidity float64
+
+	// Lockers are the owners of LP token accounts treated as locked, e.g. locker programs' vaults
+	Lockers []solana.PublicKey
+}
@@ -0,0 +1,17 @@
+package tokenmeta
+
+import "github.com/gagliardetto/solana-go"
+
+// METADATA_PROGRAM_ID is the Metaplex Token Metadata program
+var METADATA_PROGRAM_ID = solana.MustPublicKeyFromBase58("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+
+const (
+	// TOKEN_2022_MINT_ACCOUNT_TYPE marks a Token-2022 account with extensions as a mint
+	TOKEN_2022_MINT_ACCOUNT_TYPE = 1
+
+	// TOKEN_2022_METADATA_EXTENSION is the TLV type of the embedded token metadata
+	TOKEN_2022_METADATA_EXTENSION = 19
+
+	// DEFAULT_IPFS_GATEWAY resolves ipfs:// URIs
+	DEFAULT_IPFS_GATEWAY = "https://ipfs.io/ipfs/"
+)
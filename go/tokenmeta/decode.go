@@ -0,0 +1,158 @@
+package tokenmeta
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Token mint layout shared by both token programs
+const (
+	mintDecimalsOffset = 44
+	mintBaseLength     = 82
+	accountTypeOffset  = 165
+)
+
+// MetadataAddress derives the Metaplex metadata account of a mint
+func MetadataAddress(mint solana.PublicKey) (solana.PublicKey, error) {
+	address, _, err := solana.FindProgramAddress([][]byte{
+		[]byte("metadata"),
+		METADATA_PROGRAM_ID[:],
+		mint[:],
+	}, METADATA_PROGRAM_ID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive metadata address of %s: %w", mint, err)
+	}
+	return address, nil
+}
+
+// reader decodes borsh values, failing once the data runs out
+type reader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.offset+n > len(r.data) {
+		r.err = fmt.Errorf("unexpected end of data at offset %d", r.offset)
+		return nil
+	}
+	value := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return value
+}
+
+func (r *reader) u8() uint8 {
+	if value := r.bytes(1); value != nil {
+		return value[0]
+	}
+	return 0
+}
+
+func (r *reader) u32() uint32 {
+	if value := r.bytes(4); value != nil {
+		return binary.LittleEndian.Uint32(value)
+	}
+	return 0
+}
+
+func (r *reader) publicKey() solana.PublicKey {
+	if value := r.bytes(solana.PublicKeyLength); value != nil {
+		return solana.PublicKeyFromBytes(value)
+	}
+	return solana.PublicKey{}
+}
+
+// string reads a length prefixed string. Metaplex pads names to a fixed size with NULs.
+func (r *reader) string() string {
+	return strings.TrimRight(string(r.bytes(int(r.u32()))), "\x00")
+}
+
+// DecodeMetaplex decodes a Metaplex metadata account
+func DecodeMetaplex(data []byte) (*Metadata, error) {
+	r := &reader{data: data}
+	r.u8() // key
+	metadata := &Metadata{
+		Source:          SourceMetaplex,
+		UpdateAuthority: r.publicKey(),
+		Mint:            r.publicKey(),
+		Name:            r.string(),
+		Symbol:          r.string(),
+		URI:             r.string(),
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", r.err)
+	}
+
+	// seller_fee_basis_points u16, creators Option<Vec<Creator>>, primary_sale_happened bool
+	r.bytes(2)
+	if r.u8() == 1 {
+		r.bytes(int(r.u32()) * (solana.PublicKeyLength + 2))
+	}
+	r.u8()
+	metadata.IsMutable = r.u8() == 1
+	if r.err != nil {
+		// Accounts written by old program versions end early, the strings are what matters
+		metadata.IsMutable = true
+	}
+	return metadata, nil
+}
+
+// DecodeToken2022 decodes the metadata embedded in a Token-2022 mint, returning nil if the
+// mint has no token metadata extension
+func DecodeToken2022(mint solana.PublicKey, data []byte) (*Metadata, error) {
+	if len(data) <= accountTypeOffset || data[accountTypeOffset] != TOKEN_2022_MINT_ACCOUNT_TYPE {
+		return nil, nil
+	}
+
+	// Extensions are type u16, length u16 and value entries after the account type
+	for offset := accountTypeOffset + 1; offset+4 <= len(data); {
+		extension := binary.LittleEndian.Uint16(data[offset:])
+		length := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		offset += 4
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("extension %d of %s overruns the account", extension, mint)
+		}
+		if extension == TOKEN_2022_METADATA_EXTENSION {
+			return decodeTokenMetadata(mint, data[offset:offset+length])
+		}
+		if extension == 0 {
+			break
+		}
+		offset += length
+	}
+	return nil, nil
+}
+
+// decodeTokenMetadata decodes the spl-token-metadata-interface TokenMetadata struct
+func decodeTokenMetadata(mint solana.PublicKey, data []byte) (*Metadata, error) {
+	r := &reader{data: data}
+	metadata := &Metadata{
+		Source:          SourceToken2022,
+		UpdateAuthority: r.publicKey(),
+	}
+	r.publicKey() // mint
+	metadata.Mint = mint
+	metadata.Name = r.string()
+	metadata.Symbol = r.string()
+	metadata.URI = r.string()
+	metadata.IsMutable = !metadata.UpdateAuthority.IsZero()
+
+	for count := r.u32(); count > 0 && r.err == nil; count-- {
+		key, value := r.string(), r.string()
+		if metadata.Additional == nil {
+			metadata.Additional = make(map[string]string)
+		}
+		metadata.Additional[key] = value
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode token metadata of %s: %w", mint, r.err)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,298 @@
+package tokenmeta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/cache"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// maxAccountsPerRequest is the getMultipleAccounts limit
+const maxAccountsPerRequest = 100
+
+// Fetcher resolves token metadata from Token-2022 metadata extensions or Metaplex
+// metadata accounts, caching the results in a sharded LRU cache safe for concurrent use
+type Fetcher struct {
+	rpcClient *rpc.Client
+	config    Config
+	cache     *cache.Cache[solana.PublicKey, *Metadata]
+
+	// OnError is called when an off-chain document cannot be fetched, the metadata is
+	// returned without it. Downloads run in parallel, so it may be called concurrently.
+	OnError func(mint solana.PublicKey, err error)
+}
+
+// New creates a metadata fetcher
+func New(rpcClient *rpc.Client, config Config) *Fetcher {
+	if config.CacheSize <= 0 {
+		config.CacheSize = 10_000
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	if config.IPFSGateway == "" {
+		config.IPFSGateway = DEFAULT_IPFS_GATEWAY
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 8
+	}
+	if config.MaxDocumentSize <= 0 {
+		config.MaxDocumentSize = 1 << 20
+	}
+
+	return &Fetcher{
+		rpcClient: rpcClient,
+		config:    config,
+		cache:     cache.New[solana.PublicKey, *Metadata](cache.Config{Size: config.CacheSize, TTL: config.TTL, Shards: config.CacheShards}),
+	}
+}
+
+// Get returns the metadata of a mint
+func (f *Fetcher) Get(ctx context.Context, mint solana.PublicKey) (*Metadata, error) {
+	fetched, err := f.GetMany(ctx, []solana.PublicKey{mint})
+	if err != nil {
+		return nil, err
+	}
+	metadata, ok := fetched[mint]
+	if !ok {
+		return nil, fmt.Errorf("no metadata found for %s", mint)
+	}
+	return metadata, nil
+}
+
+// GetMany returns the metadata of every mint that has any, reading each uncached mint
+// and its Metaplex metadata account in batched round trips. Mints without metadata are
+// left out of the result.
+func (f *Fetcher) GetMany(ctx context.Context, mints []solana.PublicKey) (map[solana.PublicKey]*Metadata, error) {
+	result := make(map[solana.PublicKey]*Metadata, len(mints))
+
+	seen := make(map[solana.PublicKey]bool, len(mints))
+	var missing []solana.PublicKey
+	for _, mint := range mints {
+		if seen[mint] {
+			continue
+		}
+		seen[mint] = true
+
+		// Mints without metadata are cached as nil
+		if metadata, ok := f.cached(mint); ok {
+			if metadata != nil {
+				result[mint] = metadata
+			}
+			continue
+		}
+		missing = append(missing, mint)
+	}
+
+	fetched, err := f.fetch(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if f.config.FetchOffChain {
+		f.fetchOffChain(ctx, fetched)
+	}
+
+	for _, mint := range missing {
+		metadata := fetched[mint]
+		f.cache.Put(mint, metadata)
+		if metadata != nil {
+			result[mint] = metadata
+		}
+		if f.config.Persistent != nil {
+			if err := f.config.Persistent.Put(mint, metadata); err != nil {
+				return nil, fmt.Errorf("failed to persist metadata: %w", err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// cached looks up the mint in memory, then in the persistent cache
+func (f *Fetcher) cached(mint solana.PublicKey) (*Metadata, bool) {
+	if metadata, ok := f.cache.Get(mint); ok {
+		return metadata, true
+	}
+	if f.config.Persistent == nil {
+		return nil, false
+	}
+
+	metadata, ok := f.config.Persistent.Get(mint)
+	if ok {
+		f.cache.Put(mint, metadata)
+	}
+	return metadata, ok
+}
+
+// ForTransaction returns the metadata of every mint the parsed transaction swaps,
+// transfers, mints, burns or creates a pool for
+func (f *Fetcher) ForTransaction(ctx context.Context, tx *tx_parser.ParsedTransaction) (map[solana.PublicKey]*Metadata, error) {
+	var mints []solana.PublicKey
+	add := func(mint solana.PublicKey) {
+		if !mint.IsZero() && !mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+			mints = append(mints, mint)
+		}
+	}
+	for _, swap := range tx.Swaps {
+		add(swap.TokenIn.Mint)
+		add(swap.TokenOut.Mint)
+	}
+	for _, transfer := range tx.Transfers {
+		add(transfer.Mint)
+	}
+	for _, event := range tx.SupplyEvents {
+		add(event.Mint)
+	}
+	for _, created := range tx.PoolCreations {
+		add(created.MintA)
+		add(created.MintB)
+	}
+	return f.GetMany(ctx, mints)
+}
+
+// fetch reads the mint and Metaplex metadata accounts, preferring Token-2022 metadata
+func (f *Fetcher) fetch(ctx context.Context, mints []solana.PublicKey) (map[solana.PublicKey]*Metadata, error) {
+	fetched := make(map[solana.PublicKey]*Metadata, len(mints))
+
+	// Each mint takes two keys, its mint account and its metadata account
+	for start := 0; start < len(mints); start += maxAccountsPerRequest / 2 {
+		chunk := mints[start:min(start+maxAccountsPerRequest/2, len(mints))]
+		keys := make([]solana.PublicKey, 0, 2*len(chunk))
+		for _, mint := range chunk {
+			metadataAddress, err := MetadataAddress(mint)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, mint, metadataAddress)
+		}
+
+		accounts, err := f.rpcClient.GetMultipleAccountsWithOpts(ctx, keys, &rpc.GetMultipleAccountsOpts{
+			Commitment: rpc.CommitmentConfirmed,
+			Encoding:   solana.EncodingBase64,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata accounts: %w", err)
+		}
+		if len(accounts.Value) != len(keys) {
+			return nil, fmt.Errorf("expected %d accounts, got %d", len(keys), len(accounts.Value))
+		}
+
+		for i, mint := range chunk {
+			metadata, err := decodeAccounts(mint, accounts.Value[2*i], accounts.Value[2*i+1])
+			if err != nil {
+				return nil, err
+			}
+			fetched[mint] = metadata
+		}
+	}
+	return fetched, nil
+}
+
+// decodeAccounts decodes the metadata of a mint from its mint and Metaplex accounts
+func decodeAccounts(mint solana.PublicKey, mintAccount, metadataAccount *rpc.Account) (*Metadata, error) {
+	var metadata *Metadata
+	var decimals uint8
+	if mintAccount != nil {
+		data := mintAccount.Data.GetBinary()
+		if len(data) >= mintBaseLength {
+			decimals = data[mintDecimalsOffset]
+		}
+		if mintAccount.Owner.Equals(solana.Token2022ProgramID) {
+			decoded, err := DecodeToken2022(mint, data)
+			if err != nil {
+				return nil, err
+			}
+			metadata = decoded
+		}
+	}
+
+	if metadata == nil && metadataAccount != nil && metadataAccount.Owner.Equals(METADATA_PROGRAM_ID) {
+		decoded, err := DecodeMetaplex(metadataAccount.Data.GetBinary())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode metadata of %s: %w", mint, err)
+		}
+		metadata = decoded
+	}
+
+	if metadata != nil {
+		metadata.Decimals = decimals
+	}
+	return metadata, nil
+}
+
+// fetchOffChain downloads the off-chain documents of the metadata in parallel
+func (f *Fetcher) fetchOffChain(ctx context.Context, fetched map[solana.PublicKey]*Metadata) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, f.config.Concurrency)
+	for mint, metadata := range fetched {
+		if metadata == nil || metadata.URI == "" {
+			continue
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			offChain, err := f.offChain(ctx, metadata.URI)
+			if err != nil {
+				if f.OnError != nil {
+					f.OnError(mint, err)
+				}
+				return
+			}
+			metadata.OffChain = offChain
+		}()
+	}
+	wg.Wait()
+}
+
+// offChain downloads and decodes an off-chain metadata document
+func (f *Fetcher) offChain(ctx context.Context, uri string) (*OffChain, error) {
+	if rest, ok := strings.CutPrefix(uri, "ipfs://"); ok {
+		uri = f.config.IPFSGateway + strings.TrimPrefix(rest, "ipfs/")
+	}
+	if !strings.HasPrefix(uri, "https://") && !strings.HasPrefix(uri, "http://") {
+		return nil, fmt.Errorf("unsupported metadata uri %q", uri)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	response, err := f.config.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", uri, response.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(response.Body, f.config.MaxDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", uri, err)
+	}
+	if int64(len(raw)) > f.config.MaxDocumentSize {
+		return nil, fmt.Errorf("metadata document %s exceeds %d bytes", uri, f.config.MaxDocumentSize)
+	}
+
+	offChain := &OffChain{Raw: raw}
+	if err := json.Unmarshal(raw, offChain); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", uri, err)
+	}
+	offChain.Raw = raw
+	return offChain, nil
+}
@@ -0,0 +1,233 @@
+package tokenmeta
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+type testAccount struct {
+	owner solana.PublicKey
+	data  []byte
+}
+
+func appendString(data []byte, value string) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(value)))
+	return append(data, value...)
+}
+
+func metaplexAccount(mint solana.PublicKey, name, symbol, uri string) testAccount {
+	data := []byte{4}
+	data = append(data, make([]byte, 32)...)
+	data = append(data, mint[:]...)
+	data = appendString(data, name+strings.Repeat("\x00", 32-len(name)))
+	data = appendString(data, symbol+strings.Repeat("\x00", 10-len(symbol)))
+	data = appendString(data, uri)
+	data = append(data, 0, 0) // seller fee
+	data = append(data, 1)    // creators
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = append(data, make([]byte, 34)...)
+	data = append(data, 1, 0) // primary sale happened, immutable
+	return testAccount{owner: METADATA_PROGRAM_ID, data: data}
+}
+
+func mintAccount(owner solana.PublicKey, decimals uint8) testAccount {
+	data := make([]byte, mintBaseLength)
+	data[mintDecimalsOffset] = decimals
+	return testAccount{owner: owner, data: data}
+}
+
+func token2022Mint(mint, authority solana.PublicKey) testAccount {
+	data := make([]byte, accountTypeOffset)
+	data[mintDecimalsOffset] = 9
+	data = append(data, TOKEN_2022_MINT_ACCOUNT_TYPE)
+
+	// A metadata pointer extension first, then the metadata
+	data = binary.LittleEndian.AppendUint16(data, 18)
+	data = binary.LittleEndian.AppendUint16(data, 64)
+	data = append(data, make([]byte, 64)...)
+
+	var value []byte
+	value = append(value, authority[:]...)
+	value = append(value, mint[:]...)
+	value = appendString(value, "Token Two")
+	value = appendString(value, "TWO")
+	value = appendString(value, "https://example.com/two.json")
+	value = binary.LittleEndian.AppendUint32(value, 1)
+	value = appendString(value, "website")
+	value = appendString(value, "example.com")
+
+	data = binary.LittleEndian.AppendUint16(data, TOKEN_2022_METADATA_EXTENSION)
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(value)))
+	data = append(data, value...)
+	return testAccount{owner: solana.Token2022ProgramID, data: data}
+}
+
+func serve(t *testing.T, accounts map[solana.PublicKey]testAccount, requests *atomic.Int32) *rpc.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+		var keys []solana.PublicKey
+		json.Unmarshal(request.Params[0], &keys)
+
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			account, ok := accounts[key]
+			if !ok {
+				values[i] = "null"
+				continue
+			}
+			values[i] = fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+				account.owner, base64.StdEncoding.EncodeToString(account.data))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":[%s]}}`, strings.Join(values, ","))
+	}))
+	t.Cleanup(server.Close)
+	return rpc.New(server.URL)
+}
+
+func TestFetcher(t *testing.T) {
+	documents := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ipfs/one":
+			fmt.Fprint(w, `{"name":"Token One","symbol":"ONE","image":"https://example.com/one.png","extra":true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer documents.Close()
+
+	classic, modern, bare := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	authority := solana.NewWallet().PublicKey()
+	classicMetadata, _ := MetadataAddress(classic)
+
+	var requests atomic.Int32
+	client := serve(t, map[solana.PublicKey]testAccount{
+		classic:         mintAccount(solana.TokenProgramID, 6),
+		classicMetadata: metaplexAccount(classic, "Token One", "ONE", "ipfs://one"),
+		modern:          token2022Mint(modern, authority),
+		bare:            mintAccount(solana.TokenProgramID, 0),
+	}, &requests)
+
+	var failed []solana.PublicKey
+	fetcher := New(client, Config{FetchOffChain: true, IPFSGateway: documents.URL + "/ipfs/"})
+	fetcher.OnError = func(mint solana.PublicKey, err error) { failed = append(failed, mint) }
+
+	fetched, err := fetcher.GetMany(context.Background(), []solana.PublicKey{classic, modern, bare, classic})
+	if err != nil {
+		t.Fatalf("failed to fetch metadata: %v", err)
+	}
+	if len(fetched) != 2 {
+		t.Fatalf("expected metadata for 2 mints, got %d", len(fetched))
+	}
+
+	one := fetched[classic]
+	if one.Source != SourceMetaplex || one.Name != "Token One" || one.Symbol != "ONE" || one.URI != "ipfs://one" || one.Decimals != 6 || one.IsMutable {
+		t.Errorf("unexpected Metaplex metadata: %+v", one)
+	}
+	if one.OffChain == nil || one.OffChain.Image != "https://example.com/one.png" || !strings.Contains(string(one.OffChain.Raw), "extra") {
+		t.Errorf("unexpected off-chain document: %+v", one.OffChain)
+	}
+
+	two := fetched[modern]
+	if two.Source != SourceToken2022 || two.Name != "Token Two" || two.Symbol != "TWO" || two.Decimals != 9 || !two.UpdateAuthority.Equals(authority) || !two.IsMutable {
+		t.Errorf("unexpected Token-2022 metadata: %+v", two)
+	}
+	if two.Additional["website"] != "example.com" || two.OffChain != nil {
+		t.Errorf("unexpected Token-2022 extras: %+v", two)
+	}
+	if len(failed) != 1 || !failed[0].Equals(modern) {
+		t.Errorf("expected the missing document of the Token-2022 mint to be reported, got %v", failed)
+	}
+
+	// Served from the cache, including the mint without metadata
+	before := requests.Load()
+	tx := &tx_parser.ParsedTransaction{Swaps: []*tx_parser.SwapInfo{{
+		TokenIn:  tx_parser.TokenInfo{Mint: tx_parser.NATIVE_SOL_PROGRAM_ID},
+		TokenOut: tx_parser.TokenInfo{Mint: modern},
+	}}, Transfers: []*tx_parser.TransferInfo{{Mint: bare}}}
+	fetched, err = fetcher.ForTransaction(context.Background(), tx)
+	if err != nil || len(fetched) != 1 || fetched[modern] == nil {
+		t.Errorf("unexpected transaction metadata: %v, %v", fetched, err)
+	}
+	if requests.Load() != before {
+		t.Error("expected cached mints not to be fetched again")
+	}
+
+	if _, err := fetcher.Get(context.Background(), bare); err == nil {
+		t.Error("expected a mint without metadata to fail")
+	}
+}
+
+func TestDecodeToken2022WithoutMetadata(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	if metadata, err := DecodeToken2022(mint, mintAccount(solana.Token2022ProgramID, 6).data); metadata != nil || err != nil {
+		t.Errorf("expected no metadata for a mint without extensions, got %+v, %v", metadata, err)
+	}
+
+	account := token2022Mint(mint, solana.PublicKey{})
+	metadata, err := DecodeToken2022(mint, account.data)
+	if err != nil || metadata.IsMutable {
+		t.Errorf("expected immutable metadata without an update authority, got %+v, %v", metadata, err)
+	}
+
+	if _, err := DecodeToken2022(mint, account.data[:len(account.data)-3]); err == nil {
+		t.Error("expected a truncated extension to fail")
+	}
+}
+
+// mapCache is an in-memory PersistentCache
+type mapCache map[solana.PublicKey]*Metadata
+
+func (c mapCache) Get(mint solana.PublicKey) (*Metadata, bool) {
+	metadata, ok := c[mint]
+	return metadata, ok
+}
+
+func (c mapCache) Put(mint solana.PublicKey, metadata *Metadata) error {
+	c[mint] = metadata
+	return nil
+}
+
+func TestFetcherPersistentCache(t *testing.T) {
+	shared, bare := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	var requests atomic.Int32
+	client := serve(t, map[solana.PublicKey]testAccount{bare: mintAccount(solana.TokenProgramID, 0)}, &requests)
+
+	persistent := mapCache{shared: {Mint: shared, Name: "Shared"}}
+	fetcher := New(client, Config{Persistent: persistent})
+	fetched, err := fetcher.GetMany(context.Background(), []solana.PublicKey{shared, bare})
+	if err != nil {
+		t.Fatalf("failed to fetch metadata: %v", err)
+	}
+	if len(fetched) != 1 || fetched[shared].Name != "Shared" {
+		t.Errorf("expected the metadata from the persistent cache, got %v", fetched)
+	}
+	if metadata, ok := persistent[bare]; !ok || metadata != nil {
+		t.Error("expected the mint without metadata persisted as nil")
+	}
+	if _, err := New(client, Config{Persistent: persistent}).GetMany(context.Background(), []solana.PublicKey{shared, bare}); err != nil || requests.Load() != 1 {
+		t.Errorf("expected a new fetcher served from the persistent cache, got %d requests: %v", requests.Load(), err)
+	}
+}
@@ -0,0 +1,78 @@
+package tokenmeta
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Source is where the on-chain metadata was read from
+type Source string
+
+const (
+	SourceMetaplex  Source = "metaplex"
+	SourceToken2022 Source = "token2022"
+)
+
+// Metadata is the name, symbol and URI of a mint, with the off-chain JSON if fetched
+type Metadata struct {
+	Mint            solana.PublicKey  `json:"mint"`
+	Source          Source            `json:"source"`
+	Name            string            `json:"name"`
+	Symbol          string            `json:"symbol"`
+	URI             string            `json:"uri"`
+	Decimals        uint8             `json:"decimals"`
+	UpdateAuthority solana.PublicKey  `json:"update_authority"`
+	IsMutable       bool              `json:"is_mutable"`           // always true for Token-2022 metadata with an update authority
+	Additional      map[string]string `json:"additional,omitempty"` // Token-2022 additional metadata fields
+	OffChain        *OffChain         `json:"off_chain,omitempty"`
+}
+
+// OffChain is the JSON document the metadata URI points to
+type OffChain struct {
+	Name        string          `json:"name"`
+	Symbol      string          `json:"symbol"`
+	Description string          `json:"description"`
+	Image       string          `json:"image"`
+	Raw         json.RawMessage `json:"raw"`
+}
+
+// PersistentCache stores fetched metadata outside the process. Mints without metadata
+// are stored as nil, so they are not fetched again either.
+type PersistentCache interface {
+	Get(mint solana.PublicKey) (*Metadata, bool)
+	Put(mint solana.PublicKey, metadata *Metadata) error
+}
+
+// Config controls the metadata fetcher
+type Config struct {
+	// CacheSize is the number of mints kept in memory, defaults to 10000
+	CacheSize int
+
+	// CacheShards is the number of independently locked cache shards, defaults to 32
+	CacheShards int
+
+	// TTL expires cached metadata so mutable metadata is read again, zero never expires
+	TTL time.Duration
+
+	// Persistent is an optional cache shared across processes or restarts, consulted after
+	// the in-memory cache and written after each fetch
+	Persistent PersistentCache
+
+	// FetchOffChain downloads the JSON document of each URI
+	FetchOffChain bool
+
+	// HTTPClient fetches off-chain JSON, defaults to a client with a 5s timeout
+	HTTPClient *http.Client
+
+	// IPFSGateway replaces the ipfs:// scheme, defaults to DEFAULT_IPFS_GATEWAY
+	IPFSGateway string
+
+	// Concurrency bounds parallel off-chain downloads, defaults to 8
+	Concurrency int
+
+	// MaxDocumentSize bounds an off-chain document, defaults to 1 MiB
+	MaxDocumentSize int64
+}
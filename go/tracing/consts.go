@@ -0,0 +1,19 @@
+package tracing
+
+import "go.opentelemetry.io/otel/attribute"
+
+// TRACER_NAME is the instrumentation scope of every span
+const TRACER_NAME = "github.com/soralabs/solana-toolkit/go"
+
+var (
+	ATTR_RPC_METHOD        = attribute.Key("rpc.method")
+	ATTR_RPC_BATCH_SIZE    = attribute.Key("rpc.batch_size")
+	ATTR_SIGNATURE         = attribute.Key("solana.signature")
+	ATTR_SLOT              = attribute.Key("solana.slot")
+	ATTR_PROTOCOL          = attribute.Key("solana.protocol")
+	ATTR_INSTRUCTION_INDEX = attribute.Key("solana.instruction_index")
+	ATTR_INNER_INDEX       = attribute.Key("solana.inner_index")
+	ATTR_SWAPS             = attribute.Key("solana.swaps")
+	ATTR_SINK              = attribute.Key("sink.name")
+	ATTR_EVENTS            = attribute.Key("sink.events")
+)
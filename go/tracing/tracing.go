@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// Tracer records OpenTelemetry spans around RPC calls, protocol parsers and sink writes
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// New creates a tracer
+func New(config Config) *Tracer {
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: config.TracerProvider.Tracer(TRACER_NAME)}
+}
+
+// NewTracerProvider creates a provider batching spans to the exporter that samples the
+// given ratio of new traces, between 0 and 1, and follows the sampling decision of
+// remote parents
+func NewTracerProvider(exporter sdktrace.SpanExporter, sampleRatio float64, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	}, opts...)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// RPCClient returns a Solana RPC client recording a span per call to the endpoint
+func (t *Tracer) RPCClient(endpoint string) *rpc.Client {
+	return rpc.NewWithCustomRPCClient(t.WrapRPC(jsonrpc.NewClient(endpoint)))
+}
+
+// WrapRPC wraps a JSON-RPC client, such as an rpcpool.Pool, to record a span per call
+func (t *Tracer) WrapRPC(client rpc.JSONRPCClient) rpc.JSONRPCClient {
+	return &tracedRPC{client: client, tracer: t.tracer}
+}
+
+// ParseTransaction parses the transaction under a span, with a child span for every
+// protocol handler that claims one of its instructions
+func (t *Tracer) ParseTransaction(ctx context.Context, txCtx *tx_parser.TransactionContext, opts tx_parser.ParseOptions) (*tx_parser.ParsedTransaction, error) {
+	ctx, span := t.tracer.Start(ctx, "parse transaction", trace.WithAttributes(ATTR_SLOT.Int64(int64(txCtx.Slot))))
+	defer span.End()
+	if len(txCtx.Transaction.Signatures) > 0 {
+		span.SetAttributes(ATTR_SIGNATURE.String(txCtx.Transaction.Signatures[0].String()))
+	}
+
+	opts.Hook = &parseHook{ctx: ctx, tracer: t.tracer, next: opts.Hook}
+	parsed, err := tx_parser.NewFromContext(txCtx, opts).Parse()
+	if err != nil {
+		return nil, record(span, err)
+	}
+	span.SetAttributes(ATTR_SWAPS.Int(len(parsed.Swaps)))
+	return parsed, nil
+}
+
+// InstrumentSink wraps a sink to record a span per write
+func (t *Tracer) InstrumentSink(name string, s sink.Sink) sink.Sink {
+	return &tracedSink{Sink: s, name: name, tracer: t.tracer}
+}
+
+// tracedRPC records a span per JSON-RPC call
+type tracedRPC struct {
+	client rpc.JSONRPCClient
+	tracer trace.Tracer
+}
+
+// CallForInto calls the wrapped client under a span
+func (r *tracedRPC) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	ctx, span := r.start(ctx, method)
+	defer span.End()
+	return record(span, r.client.CallForInto(ctx, out, method, params))
+}
+
+// CallWithCallback calls the wrapped client under a span
+func (r *tracedRPC) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	ctx, span := r.start(ctx, method)
+	defer span.End()
+	return record(span, r.client.CallWithCallback(ctx, method, params, callback))
+}
+
+// CallBatch calls the wrapped client under a span named after the first method
+func (r *tracedRPC) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	method := "batch"
+	if len(requests) > 0 {
+		method = requests[0].Method
+	}
+	ctx, span := r.start(ctx, method)
+	defer span.End()
+	span.SetAttributes(ATTR_RPC_BATCH_SIZE.Int(len(requests)))
+
+	responses, err := r.client.CallBatch(ctx, requests)
+	return responses, record(span, err)
+}
+
+// start starts the client span of an RPC call
+func (r *tracedRPC) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, "rpc "+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(ATTR_RPC_METHOD.String(method)))
+}
+
+// parseHook starts a span per protocol handler call under the transaction's span
+type parseHook struct {
+	ctx    context.Context
+	tracer trace.Tracer
+	next   tx_parser.ParseHook
+}
+
+// HandlerStarted starts the span of a handler call
+func (h *parseHook) HandlerStarted(protocol tx_parser.SwapType, instructionIndex, innerIndex int) func([]*tx_parser.SwapInfo, error) {
+	_, span := h.tracer.Start(h.ctx, "parse "+string(protocol), trace.WithAttributes(
+		ATTR_PROTOCOL.String(string(protocol)),
+		ATTR_INSTRUCTION_INDEX.Int(instructionIndex),
+		ATTR_INNER_INDEX.Int(innerIndex),
+	))
+	var next func([]*tx_parser.SwapInfo, error)
+	if h.next != nil {
+		next = h.next.HandlerStarted(protocol, instructionIndex, innerIndex)
+	}
+
+	return func(swaps []*tx_parser.SwapInfo, err error) {
+		span.SetAttributes(ATTR_SWAPS.Int(len(swaps)))
+		record(span, err)
+		span.End()
+		if next != nil {
+			next(swaps, err)
+		}
+	}
+}
+
+// tracedSink records a span per write of the sink it wraps
+type tracedSink struct {
+	sink.Sink
+	name   string
+	tracer trace.Tracer
+}
+
+// Write writes to the wrapped sink under a span
+func (s *tracedSink) Write(ctx context.Context, events []*sink.Event) error {
+	ctx, span := s.tracer.Start(ctx, "sink write", trace.WithAttributes(ATTR_SINK.String(s.name), ATTR_EVENTS.Int(len(events))))
+	defer span.End()
+	return record(span, s.Sink.Write(ctx, events))
+}
+
+// record marks the span as failed when err is set and returns err
+func record(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
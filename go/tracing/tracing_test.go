@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/sink"
+)
+
+// failingSink fails every write
+type failingSink struct{}
+
+func (failingSink) Write(ctx context.Context, events []*sink.Event) error {
+	return errors.New("write failed")
+}
+
+func (failingSink) Close() error { return nil }
+
+func newTracer() (*Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return New(Config{TracerProvider: provider}), recorder
+}
+
+// spanNames lists the ended spans as "name status"
+func spanNames(recorder *tracetest.SpanRecorder) []string {
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name()+" "+span.Status().Code.String())
+	}
+	return names
+}
+
+func TestRPCClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":42}`)
+	}))
+	defer server.Close()
+	tracer, recorder := newTracer()
+
+	slot, err := tracer.RPCClient(server.URL).GetSlot(context.Background(), rpc.CommitmentConfirmed)
+	if err != nil || slot != 42 {
+		t.Fatalf("unexpected slot %d: %v", slot, err)
+	}
+	server.Close()
+	if _, err := tracer.RPCClient(server.URL).GetSlot(context.Background(), rpc.CommitmentConfirmed); err == nil {
+		t.Fatal("expected the closed server to fail")
+	}
+
+	if names := fmt.Sprint(spanNames(recorder)); names != "[rpc getSlot Unset rpc getSlot Error]" {
+		t.Errorf("unexpected spans %s", names)
+	}
+}
+
+func TestParseTransaction(t *testing.T) {
+	tracer, recorder := newTracer()
+	tx := &solana.Transaction{
+		Signatures: []solana.Signature{{1}},
+		Message: solana.Message{
+			AccountKeys:  []solana.PublicKey{solana.NewWallet().PublicKey(), tx_parser.ORCA_PROGRAM_ID},
+			Header:       solana.MessageHeader{NumRequiredSignatures: 1},
+			Instructions: []solana.CompiledInstruction{{ProgramIDIndex: 1, Accounts: []uint16{0}, Data: []byte{1}}},
+		},
+	}
+	txCtx, err := tx_parser.NewTransactionContextFromDecoded(7, nil, tx, &rpc.TransactionMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := tracer.ParseTransaction(context.Background(), txCtx, tx_parser.ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Errors) != 1 {
+		t.Fatalf("expected the Orca instruction to fail, got %+v", parsed.Errors)
+	}
+
+	spans := recorder.Ended()
+	if names := fmt.Sprint(spanNames(recorder)); names != "[parse Orca Error parse transaction Unset]" {
+		t.Fatalf("unexpected spans %s", names)
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Error("expected the handler span to be a child of the transaction span")
+	}
+}
+
+func TestInstrumentSink(t *testing.T) {
+	tracer, recorder := newTracer()
+
+	err := tracer.InstrumentSink("kafka", failingSink{}).Write(context.Background(), []*sink.Event{{Kind: sink.KindSwap}})
+	if err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error || len(spans[0].Events()) != 1 {
+		t.Errorf("expected one failed sink span, got %v", spanNames(recorder))
+	}
+}
+
+func TestSampling(t *testing.T) {
+	for _, ratio := range []float64{0, 1} {
+		exporter := tracetest.NewInMemoryExporter()
+		provider := NewTracerProvider(exporter, ratio)
+		tracer := New(Config{TracerProvider: provider})
+
+		tracer.InstrumentSink("json", failingSink{}).Write(context.Background(), nil)
+		provider.ForceFlush(context.Background())
+		if got := len(exporter.GetSpans()); got != int(ratio) {
+			t.Errorf("ratio %v: expected %d spans, got %d", ratio, int(ratio), got)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package tracing
+
+import "go.opentelemetry.io/otel/trace"
+
+// Config controls the tracer
+type Config struct {
+	// TracerProvider creates the spans, defaults to the global provider. Use
+	// NewTracerProvider for a provider with ratio based sampling.
+	TracerProvider trace.TracerProvider
+}
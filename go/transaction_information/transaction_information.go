@@ -10,6 +10,7 @@ import (
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 
+	"github.com/soralabs/solana-toolkit/go/internal/decimals"
 	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
 	toolkit "github.com/soralabs/toolkit/go"
 )
@@ -20,11 +21,13 @@ type TransactionInformationTool struct {
 	mu sync.Mutex
 
 	rpcClient *rpc.Client
+	decimals  *decimals.Resolver
 }
 
 func NewTransactionInformationTool(rpcClient *rpc.Client) *TransactionInformationTool {
 	return &TransactionInformationTool{
 		rpcClient: rpcClient,
+		decimals:  decimals.NewResolver(rpcClient, decimals.Options{}),
 	}
 }
 
@@ -74,7 +77,10 @@ func (t *TransactionInformationTool) Execute(ctx context.Context, params json.Ra
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
-	parser, err := tx_parser.New(tx)
+	parser, err := tx_parser.NewWithOptions(tx, tx_parser.ParseOptions{
+		DecimalsResolver:        t.decimals,
+		TokenExtensionsResolver: t.decimals,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create parser: %w", err)
 	}
@@ -84,8 +90,26 @@ func (t *TransactionInformationTool) Execute(ctx context.Context, params json.Ra
 		return nil, fmt.Errorf("failed to parse transaction: %w", err)
 	}
 
+	transfers, err := parser.ParseTransfers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transfers: %w", err)
+	}
+
+	computeBudget, err := parser.ParseComputeBudget()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse compute budget: %w", err)
+	}
+
+	memos, err := parser.ParseMemos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse memos: %w", err)
+	}
+
 	return json.Marshal(TransactionInformationOutput{
-		Hash:  input.Hash,
-		Swaps: swaps,
+		Hash:          input.Hash,
+		Swaps:         swaps,
+		Transfers:     transfers,
+		ComputeBudget: computeBudget,
+		Memos:         memos,
 	})
 }
@@ -7,6 +7,9 @@ type TransactionInformationInput struct {
 }
 
 type TransactionInformationOutput struct {
-	Hash  string                `json:"hash"`
-	Swaps []*tx_parser.SwapInfo `json:"swaps"`
+	Hash          string                    `json:"hash"`
+	Swaps         []*tx_parser.SwapInfo     `json:"swaps"`
+	Transfers     []*tx_parser.TransferInfo `json:"transfers"`
+	ComputeBudget *tx_parser.ComputeBudget  `json:"compute_budget,omitempty"`
+	Memos         []*tx_parser.MemoInfo     `json:"memos,omitempty"`
 }
@@ -0,0 +1,61 @@
+package txbuilder
+
+import "github.com/gagliardetto/solana-go"
+
+var (
+	// RAYDIUM_AMM_AUTHORITY is the vault authority of every Raydium AMM v4 pool
+	RAYDIUM_AMM_AUTHORITY = solana.MustPublicKeyFromBase58("5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1")
+
+	// PUMP_SWAP_PROGRAM_ID is the PumpSwap AMM that graduated pump.fun tokens migrate to
+	PUMP_SWAP_PROGRAM_ID = solana.MustPublicKeyFromBase58("pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA")
+
+	// ADDRESS_LOOKUP_TABLE_PROGRAM_ID owns address lookup tables
+	ADDRESS_LOOKUP_TABLE_PROGRAM_ID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+)
+
+const (
+	// RAYDIUM_AMM_SWAP_BASE_IN is the instruction tag of an exact input AMM v4 swap
+	RAYDIUM_AMM_SWAP_BASE_IN = 9
+
+	// CLMM_TICK_ARRAY_SIZE is the number of ticks in a Raydium CLMM tick array
+	CLMM_TICK_ARRAY_SIZE = 60
+
+	// CLMM_TICK_ARRAYS is the number of initialized tick arrays passed to a CLMM swap
+	CLMM_TICK_ARRAYS = 3
+
+	// CLMM_TICK_ARRAY_SEARCH is how many tick arrays in the swap direction are checked
+	// for initialized ones
+	CLMM_TICK_ARRAY_SEARCH = 10
+
+	// OPENBOOK_MARKET_LENGTH is the size of an OpenBook market account
+	OPENBOOK_MARKET_LENGTH = 388
+
+	// PUMP_SWAP_POOL_LENGTH is the size of the PumpSwap pool fields the builder reads
+	PUMP_SWAP_POOL_LENGTH = 211
+
+	// PUMP_SWAP_CONFIG_LENGTH is the size of the PumpSwap global config fields the builder reads
+	PUMP_SWAP_CONFIG_LENGTH = 313
+
+	// COMPUTE_BUDGET_SET_COMPUTE_UNIT_LIMIT is the compute budget instruction that sets the limit
+	COMPUTE_BUDGET_SET_COMPUTE_UNIT_LIMIT = 2
+
+	// MAX_COMPUTE_UNIT_LIMIT is the most compute units a transaction may request
+	MAX_COMPUTE_UNIT_LIMIT = 1_400_000
+
+	// NONCE_ACCOUNT_LENGTH is the size of a durable nonce account
+	NONCE_ACCOUNT_LENGTH = 80
+
+	// MAX_TRANSACTION_SIZE is the largest serialized transaction the network accepts
+	MAX_TRANSACTION_SIZE = 1232
+
+	// LOOKUP_TABLE_EXTEND_CHUNK is how many addresses one extend transaction adds
+	LOOKUP_TABLE_EXTEND_CHUNK = 20
+)
+
+// Address lookup table program instructions
+const (
+	lookupTableCreate     = 0
+	lookupTableExtend     = 2
+	lookupTableDeactivate = 3
+	lookupTableClose      = 4
+)
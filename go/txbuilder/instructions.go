@@ -0,0 +1,159 @@
+package txbuilder
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+// clmmSwapV2Discriminator is the Anchor discriminator of the CLMM swap_v2 instruction
+var clmmSwapV2Discriminator = func() []byte {
+	sum := sha256.Sum256([]byte("global:swap_v2"))
+	return sum[:8]
+}()
+
+// DecodeMarket decodes the accounts of an OpenBook market
+func DecodeMarket(address, program solana.PublicKey, data []byte) (*Market, error) {
+	if len(data) != OPENBOOK_MARKET_LENGTH {
+		return nil, fmt.Errorf("account %s is not an OpenBook market", address)
+	}
+
+	nonce := make([]byte, 8)
+	copy(nonce, data[45:53])
+	vaultSigner, err := solana.CreateProgramAddress([][]byte{address[:], nonce}, program)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive vault signer of market %s: %w", address, err)
+	}
+
+	key := func(offset int) solana.PublicKey { return solana.PublicKeyFromBytes(data[offset : offset+32]) }
+	return &Market{
+		Address:     address,
+		Program:     program,
+		BaseVault:   key(117),
+		QuoteVault:  key(165),
+		EventQueue:  key(253),
+		Bids:        key(285),
+		Asks:        key(317),
+		VaultSigner: vaultSigner,
+	}, nil
+}
+
+// RaydiumAMMSwapInstruction builds an exact input swap_base_in against an AMM v4 pool.
+// Source and destination are the owner's token accounts of the input and output mints.
+func RaydiumAMMSwapInstruction(pool *pools.RaydiumAMM, market *Market, source, destination, owner solana.PublicKey, amountIn, minAmountOut uint64) solana.Instruction {
+	data := make([]byte, 17)
+	data[0] = RAYDIUM_AMM_SWAP_BASE_IN
+	binary.LittleEndian.PutUint64(data[1:], amountIn)
+	binary.LittleEndian.PutUint64(data[9:], minAmountOut)
+
+	return solana.NewInstruction(tx_parser.RAYDIUM_V4_PROGRAM_ID, solana.AccountMetaSlice{
+		solana.Meta(solana.TokenProgramID),
+		solana.Meta(pool.Pool).WRITE(),
+		solana.Meta(RAYDIUM_AMM_AUTHORITY),
+		solana.Meta(pool.OpenOrders).WRITE(),
+		solana.Meta(pool.TargetOrders).WRITE(),
+		solana.Meta(pool.CoinVault).WRITE(),
+		solana.Meta(pool.PCVault).WRITE(),
+		solana.Meta(market.Program),
+		solana.Meta(market.Address).WRITE(),
+		solana.Meta(market.Bids).WRITE(),
+		solana.Meta(market.Asks).WRITE(),
+		solana.Meta(market.EventQueue).WRITE(),
+		solana.Meta(market.BaseVault).WRITE(),
+		solana.Meta(market.QuoteVault).WRITE(),
+		solana.Meta(market.VaultSigner),
+		solana.Meta(source).WRITE(),
+		solana.Meta(destination).WRITE(),
+		solana.Meta(owner).SIGNER(),
+	}, data)
+}
+
+// RaydiumCLMMSwapInstruction builds an exact input swap_v2 against a CLMM pool. Tick arrays
+// are the initialized arrays in swap direction, starting with the one holding the current tick.
+func RaydiumCLMMSwapInstruction(pool *pools.ConcentratedPool, mintIn, source, destination, owner solana.PublicKey, tickArrays []solana.PublicKey, amountIn, minAmountOut uint64) (solana.Instruction, error) {
+	inputVault, outputVault, mintOut := pool.VaultA, pool.VaultB, pool.MintB
+	if mintIn.Equals(pool.MintB) {
+		inputVault, outputVault, mintOut = pool.VaultB, pool.VaultA, pool.MintA
+	} else if !mintIn.Equals(pool.MintA) {
+		return nil, fmt.Errorf("mint %s is not in pool %s", mintIn, pool.Pool)
+	}
+
+	bitmapExtension, _, err := solana.FindProgramAddress([][]byte{[]byte("pool_tick_array_bitmap_extension"), pool.Pool[:]}, tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive tick array bitmap extension: %w", err)
+	}
+
+	// amount, other_amount_threshold, sqrt_price_limit_x64 (0 for no limit), is_base_input
+	data := make([]byte, 8+8+8+16+1)
+	copy(data, clmmSwapV2Discriminator)
+	binary.LittleEndian.PutUint64(data[8:], amountIn)
+	binary.LittleEndian.PutUint64(data[16:], minAmountOut)
+	data[40] = 1
+
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(owner).SIGNER(),
+		solana.Meta(pool.AMMConfig),
+		solana.Meta(pool.Pool).WRITE(),
+		solana.Meta(source).WRITE(),
+		solana.Meta(destination).WRITE(),
+		solana.Meta(inputVault).WRITE(),
+		solana.Meta(outputVault).WRITE(),
+		solana.Meta(pool.Observation).WRITE(),
+		solana.Meta(solana.TokenProgramID),
+		solana.Meta(solana.Token2022ProgramID),
+		solana.Meta(tx_parser.MEMO_PROGRAM_ID),
+		solana.Meta(mintIn),
+		solana.Meta(mintOut),
+		solana.Meta(bitmapExtension),
+	}
+	for _, tickArray := range tickArrays {
+		accounts = append(accounts, solana.Meta(tickArray).WRITE())
+	}
+	return solana.NewInstruction(tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, accounts, data), nil
+}
+
+// TickArrayStartIndex returns the first tick of the CLMM tick array holding the tick
+func TickArrayStartIndex(tick int32, tickSpacing uint16) int32 {
+	ticks := int32(tickSpacing) * CLMM_TICK_ARRAY_SIZE
+	start := tick / ticks
+	if tick < 0 && tick%ticks != 0 {
+		start--
+	}
+	return start * ticks
+}
+
+// TickArrayAddress derives the CLMM tick array account starting at startIndex
+func TickArrayAddress(pool solana.PublicKey, startIndex int32) (solana.PublicKey, error) {
+	index := make([]byte, 4)
+	binary.BigEndian.PutUint32(index, uint32(startIndex))
+	address, _, err := solana.FindProgramAddress([][]byte{[]byte("tick_array"), pool[:], index}, tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID)
+	return address, err
+}
+
+// AssociatedTokenAddress derives the owner's associated token account under the token program
+func AssociatedTokenAddress(owner, mint, tokenProgram solana.PublicKey) (solana.PublicKey, error) {
+	address, _, err := solana.FindProgramAddress([][]byte{owner[:], tokenProgram[:], mint[:]}, solana.SPLAssociatedTokenAccountProgramID)
+	return address, err
+}
+
+// CreateAssociatedTokenAccountInstruction builds a CreateIdempotent instruction, which
+// succeeds when the account already exists
+func CreateAssociatedTokenAccountInstruction(payer, owner, mint, tokenProgram solana.PublicKey) (solana.Instruction, error) {
+	account, err := AssociatedTokenAddress(owner, mint, tokenProgram)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive token account: %w", err)
+	}
+	return solana.NewInstruction(solana.SPLAssociatedTokenAccountProgramID, solana.AccountMetaSlice{
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(account).WRITE(),
+		solana.Meta(owner),
+		solana.Meta(mint),
+		solana.Meta(solana.SystemProgramID),
+		solana.Meta(tokenProgram),
+	}, []byte{1}), nil
+}
@@ -0,0 +1,220 @@
+package txbuilder
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// LookupTableAddress derives the lookup table an authority creates at a recent slot
+func LookupTableAddress(authority solana.PublicKey, recentSlot uint64) (solana.PublicKey, uint8, error) {
+	slot := binary.LittleEndian.AppendUint64(nil, recentSlot)
+	return solana.FindProgramAddress([][]byte{authority[:], slot}, ADDRESS_LOOKUP_TABLE_PROGRAM_ID)
+}
+
+// CreateLookupTableInstruction creates the authority's lookup table for a recent slot
+func CreateLookupTableInstruction(authority, payer solana.PublicKey, recentSlot uint64) (solana.Instruction, solana.PublicKey, error) {
+	table, bump, err := LookupTableAddress(authority, recentSlot)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("failed to derive lookup table: %w", err)
+	}
+	data := binary.LittleEndian.AppendUint32(nil, lookupTableCreate)
+	data = binary.LittleEndian.AppendUint64(data, recentSlot)
+	data = append(data, bump)
+	return solana.NewInstruction(ADDRESS_LOOKUP_TABLE_PROGRAM_ID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(solana.SystemProgramID),
+	}, data), table, nil
+}
+
+// ExtendLookupTableInstruction appends addresses to a lookup table, the payer funds the
+// extra rent
+func ExtendLookupTableInstruction(table, authority, payer solana.PublicKey, addresses []solana.PublicKey) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, lookupTableExtend)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(addresses)))
+	for _, address := range addresses {
+		data = append(data, address[:]...)
+	}
+	return solana.NewInstruction(ADDRESS_LOOKUP_TABLE_PROGRAM_ID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(solana.SystemProgramID),
+	}, data)
+}
+
+// DeactivateLookupTableInstruction starts the cool down after which a table can be closed
+func DeactivateLookupTableInstruction(table, authority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(ADDRESS_LOOKUP_TABLE_PROGRAM_ID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+	}, binary.LittleEndian.AppendUint32(nil, lookupTableDeactivate))
+}
+
+// CloseLookupTableInstruction closes a deactivated table and returns its rent to the recipient
+func CloseLookupTableInstruction(table, authority, recipient solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(ADDRESS_LOOKUP_TABLE_PROGRAM_ID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(recipient).WRITE(),
+	}, binary.LittleEndian.AppendUint32(nil, lookupTableClose))
+}
+
+// CreateLookupTable builds a transaction creating a lookup table at the latest finalized
+// slot and returns the table address
+func (b *Builder) CreateLookupTable(ctx context.Context, authority, payer solana.PublicKey) (*solana.Transaction, solana.PublicKey, error) {
+	slot, err := b.rpcClient.GetSlot(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("failed to get slot: %w", err)
+	}
+	create, table, err := CreateLookupTableInstruction(authority, payer, slot)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+	tx, err := b.unsignedTransaction(ctx, payer, create)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+	return tx, table, nil
+}
+
+// ExtendLookupTable builds the transactions adding addresses to a table, LOOKUP_TABLE_EXTEND_CHUNK
+// at a time. Addresses already in the table are skipped.
+func (b *Builder) ExtendLookupTable(ctx context.Context, table, authority, payer solana.PublicKey, addresses []solana.PublicKey) ([]*solana.Transaction, error) {
+	tables, err := b.LoadLookupTables(ctx, []solana.PublicKey{table})
+	if err != nil {
+		return nil, err
+	}
+	existing := tables[table]
+
+	var missing []solana.PublicKey
+	for _, address := range addresses {
+		if !existing.Contains(address) && !solana.PublicKeySlice(missing).Contains(address) {
+			missing = append(missing, address)
+		}
+	}
+	if len(existing)+len(missing) > addresslookuptable.LOOKUP_TABLE_MAX_ADDRESSES {
+		return nil, fmt.Errorf("lookup table %s would exceed %d addresses", table, addresslookuptable.LOOKUP_TABLE_MAX_ADDRESSES)
+	}
+
+	var txs []*solana.Transaction
+	for start := 0; start < len(missing); start += LOOKUP_TABLE_EXTEND_CHUNK {
+		chunk := missing[start:min(start+LOOKUP_TABLE_EXTEND_CHUNK, len(missing))]
+		tx, err := b.unsignedTransaction(ctx, payer, ExtendLookupTableInstruction(table, authority, payer, chunk))
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// DeactivateLookupTable builds a transaction deactivating a table
+func (b *Builder) DeactivateLookupTable(ctx context.Context, table, authority solana.PublicKey) (*solana.Transaction, error) {
+	return b.unsignedTransaction(ctx, authority, DeactivateLookupTableInstruction(table, authority))
+}
+
+// CloseLookupTable builds a transaction closing a deactivated table
+func (b *Builder) CloseLookupTable(ctx context.Context, table, authority, recipient solana.PublicKey) (*solana.Transaction, error) {
+	return b.unsignedTransaction(ctx, authority, CloseLookupTableInstruction(table, authority, recipient))
+}
+
+// LoadLookupTables reads the addresses of lookup tables
+func (b *Builder) LoadLookupTables(ctx context.Context, tables []solana.PublicKey) (map[solana.PublicKey]solana.PublicKeySlice, error) {
+	return LoadLookupTables(ctx, b.rpcClient, tables)
+}
+
+// LoadLookupTables reads the addresses of lookup tables
+func LoadLookupTables(ctx context.Context, rpcClient *rpc.Client, tables []solana.PublicKey) (map[solana.PublicKey]solana.PublicKeySlice, error) {
+	result, err := rpcClient.GetMultipleAccountsWithOpts(ctx, tables, &rpc.GetMultipleAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Encoding:   solana.EncodingBase64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lookup tables: %w", err)
+	}
+
+	addresses := make(map[solana.PublicKey]solana.PublicKeySlice, len(tables))
+	for i, table := range tables {
+		if i >= len(result.Value) || result.Value[i] == nil {
+			return nil, fmt.Errorf("lookup table %s not found", table)
+		}
+		state, err := addresslookuptable.DecodeAddressLookupTableState(result.Value[i].Data.GetBinary())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode lookup table %s: %w", table, err)
+		}
+		addresses[table] = state.Addresses
+	}
+	return addresses, nil
+}
+
+// ResolveLookupTables loads the lookup tables a decoded version 0 transaction references
+// and resolves its account keys to include the looked up addresses
+func ResolveLookupTables(ctx context.Context, rpcClient *rpc.Client, tx *solana.Transaction) error {
+	if tx.Message.IsResolved() || tx.Message.NumLookups() == 0 {
+		return nil
+	}
+	var tables []solana.PublicKey
+	for _, lookup := range tx.Message.AddressTableLookups {
+		tables = append(tables, lookup.AccountKey)
+	}
+	addresses, err := LoadLookupTables(ctx, rpcClient, tables)
+	if err != nil {
+		return err
+	}
+	if err := tx.Message.SetAddressTables(addresses); err != nil {
+		return fmt.Errorf("failed to set lookup tables: %w", err)
+	}
+	if err := tx.Message.ResolveLookups(); err != nil {
+		return fmt.Errorf("failed to resolve lookup tables: %w", err)
+	}
+	return nil
+}
+
+// compress rebuilds a transaction too large for a legacy message as a version 0 message
+// over the configured lookup tables
+func (b *Builder) compress(ctx context.Context, tx *solana.Transaction, payer solana.PublicKey, blockhash solana.Hash, instructions []solana.Instruction) (*solana.Transaction, error) {
+	size, err := transactionSize(tx)
+	if err != nil {
+		return nil, err
+	}
+	if size <= MAX_TRANSACTION_SIZE {
+		return tx, nil
+	}
+	if len(b.config.LookupTables) == 0 {
+		return nil, fmt.Errorf("transaction of %d bytes exceeds %d, configure lookup tables to compress it", size, MAX_TRANSACTION_SIZE)
+	}
+
+	tables, err := b.LoadLookupTables(ctx, b.config.LookupTables)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := solana.NewTransaction(instructions, blockhash, solana.TransactionPayer(payer), solana.TransactionAddressTables(tables))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	if size, err = transactionSize(compressed); err != nil {
+		return nil, err
+	}
+	if size > MAX_TRANSACTION_SIZE {
+		return nil, fmt.Errorf("transaction of %d bytes exceeds %d even with lookup tables", size, MAX_TRANSACTION_SIZE)
+	}
+	return compressed, nil
+}
+
+// transactionSize is the serialized size of the transaction once every signer signed
+func transactionSize(tx *solana.Transaction) (int, error) {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	signatures := int(tx.Message.Header.NumRequiredSignatures)
+	// compact-u16 signature count, one byte below 128 signatures
+	return 1 + signatures*64 + len(message), nil
+}
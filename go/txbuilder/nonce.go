@@ -0,0 +1,91 @@
+package txbuilder
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// CreateNonceAccount builds a transaction creating and initializing a durable nonce
+// account funded for rent exemption. Both the payer and the new nonce account sign it.
+func (b *Builder) CreateNonceAccount(ctx context.Context, payer, nonce, authority solana.PublicKey) (*solana.Transaction, error) {
+	rent, err := b.rpcClient.GetMinimumBalanceForRentExemption(ctx, NONCE_ACCOUNT_LENGTH, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce account rent: %w", err)
+	}
+	return b.unsignedTransaction(ctx, payer, CreateNonceAccountInstructions(payer, nonce, authority, rent)...)
+}
+
+// CreateNonceAccountInstructions creates the nonce account with the given lamports and
+// initializes it under the authority
+func CreateNonceAccountInstructions(payer, nonce, authority solana.PublicKey, lamports uint64) []solana.Instruction {
+	return []solana.Instruction{
+		system.NewCreateAccountInstruction(lamports, NONCE_ACCOUNT_LENGTH, solana.SystemProgramID, payer, nonce).Build(),
+		system.NewInitializeNonceAccountInstruction(authority, nonce, solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey).Build(),
+	}
+}
+
+// AdvanceNonceInstruction advances the nonce, it must be the first instruction of a
+// transaction using the nonce
+func AdvanceNonceInstruction(nonce, authority solana.PublicKey) solana.Instruction {
+	return system.NewAdvanceNonceAccountInstruction(nonce, solana.SysVarRecentBlockHashesPubkey, authority).Build()
+}
+
+// Nonce reads the current state of a durable nonce account
+func (b *Builder) Nonce(ctx context.Context, address solana.PublicKey) (*NonceAccount, error) {
+	accounts, err := b.fetch(ctx, []solana.PublicKey{address})
+	if err != nil {
+		return nil, err
+	}
+	account := accounts[address]
+	if account == nil {
+		return nil, fmt.Errorf("nonce account %s not found", address)
+	}
+	if !account.Owner.Equals(solana.SystemProgramID) {
+		return nil, fmt.Errorf("account %s is not a nonce account", address)
+	}
+	return DecodeNonceAccount(address, account.Data.GetBinary())
+}
+
+// DecodeNonceAccount decodes an initialized durable nonce account
+func DecodeNonceAccount(address solana.PublicKey, data []byte) (*NonceAccount, error) {
+	if len(data) != NONCE_ACCOUNT_LENGTH {
+		return nil, fmt.Errorf("account %s is not a nonce account", address)
+	}
+	// version, then state where 1 is initialized
+	if binary.LittleEndian.Uint32(data[4:]) != 1 {
+		return nil, fmt.Errorf("nonce account %s is not initialized", address)
+	}
+	return &NonceAccount{
+		Address:              address,
+		Authority:            solana.PublicKeyFromBytes(data[8:40]),
+		Nonce:                solana.HashFromBytes(data[40:72]),
+		LamportsPerSignature: binary.LittleEndian.Uint64(data[72:]),
+	}, nil
+}
+
+// blockhash returns the blockhash for a new transaction. With a nonce account configured
+// it is the stored nonce and the instructions are prefixed with the nonce advance.
+func (b *Builder) blockhash(ctx context.Context, instructions []solana.Instruction) (solana.Hash, []solana.Instruction, error) {
+	if b.config.NonceAccount.IsZero() {
+		recent, err := b.rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			return solana.Hash{}, nil, fmt.Errorf("failed to get recent blockhash: %w", err)
+		}
+		return recent.Value.Blockhash, instructions, nil
+	}
+
+	nonce, err := b.Nonce(ctx, b.config.NonceAccount)
+	if err != nil {
+		return solana.Hash{}, nil, err
+	}
+	if !nonce.Authority.Equals(b.config.NonceAuthority) {
+		return solana.Hash{}, nil, fmt.Errorf("nonce account %s is controlled by %s, not %s", nonce.Address, nonce.Authority, b.config.NonceAuthority)
+	}
+	advance := AdvanceNonceInstruction(nonce.Address, nonce.Authority)
+	return nonce.Nonce, append([]solana.Instruction{advance}, instructions...), nil
+}
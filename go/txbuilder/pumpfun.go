@@ -0,0 +1,328 @@
+package txbuilder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+// Anchor discriminators of the PumpSwap buy and sell instructions
+var (
+	pumpSwapBuyDiscriminator  = anchorDiscriminator("buy")
+	pumpSwapSellDiscriminator = anchorDiscriminator("sell")
+)
+
+func anchorDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("global:" + name))
+	return sum[:8]
+}
+
+// BuildPumpFunSwap buys or sells a pump.fun token on its bonding curve, or on its canonical
+// PumpSwap pool once the curve is complete. Curve buys fix the token amount from the quote
+// and bound the SOL spent by the slippage, sells bound the SOL received. A missing token
+// account is created for buys.
+func (b *Builder) BuildPumpFunSwap(ctx context.Context, request PumpFunRequest) (*Swap, error) {
+	if request.Amount == 0 {
+		return nil, fmt.Errorf("amount is required")
+	}
+
+	curves, err := b.pools.LoadBondingCurves(ctx, []solana.PublicKey{request.Mint})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bonding curve: %w", err)
+	}
+	curve := curves[0]
+	if curve.Complete {
+		return b.buildPumpSwap(ctx, request)
+	}
+
+	swap := &Swap{MintIn: request.Mint, MintOut: tx_parser.NATIVE_SOL_PROGRAM_ID, AmountIn: request.Amount}
+	if request.Buy {
+		swap.MintIn, swap.MintOut = swap.MintOut, swap.MintIn
+	}
+	if swap.ExpectedOut, err = curve.Quote(swap.MintIn, request.Amount); err != nil {
+		return nil, fmt.Errorf("failed to quote swap: %w", err)
+	}
+	slippage := b.slippage(request.SlippageBps)
+
+	in, out, err := b.tokenLegs(ctx, request.Owner, swap.MintIn, swap.MintOut)
+	if err != nil {
+		return nil, err
+	}
+	tokenLeg := in
+	if request.Buy {
+		tokenLeg = out
+	}
+
+	_, associatedCurve, err := pumpfun.DeriveBondingCurveAddresses(request.Mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive bonding curve: %w", err)
+	}
+
+	// the curve trades native SOL, so only the token account is needed
+	instructions := b.budget()
+	if request.Buy {
+		swap.MinAmountOut = swap.ExpectedOut
+		swap.MaxAmountIn = request.Amount + ceilBps(request.Amount, slippage)
+		if !tokenLeg.exists {
+			create, err := CreateAssociatedTokenAccountInstruction(request.Owner, request.Owner, request.Mint, tokenLeg.program)
+			if err != nil {
+				return nil, err
+			}
+			instructions = append(instructions, create)
+		}
+		instructions = append(instructions, pump.NewBuyInstruction(
+			swap.ExpectedOut,
+			swap.MaxAmountIn,
+			pumpfun.GlobalPumpFunAddress,
+			pumpfun.PumpFunFeeRecipient,
+			request.Mint,
+			curve.Curve,
+			associatedCurve,
+			tokenLeg.account,
+			request.Owner,
+			solana.SystemProgramID,
+			tokenLeg.program,
+			solana.SysVarRentPubkey,
+			pumpfun.EventAuthority,
+			pumpfun.ProgramID,
+		).Build())
+	} else {
+		swap.MinAmountOut = minAmountOut(swap.ExpectedOut, slippage)
+		instructions = append(instructions, pump.NewSellInstruction(
+			request.Amount,
+			swap.MinAmountOut,
+			pumpfun.GlobalPumpFunAddress,
+			pumpfun.PumpFunFeeRecipient,
+			request.Mint,
+			curve.Curve,
+			associatedCurve,
+			tokenLeg.account,
+			request.Owner,
+			solana.SystemProgramID,
+			solana.SPLAssociatedTokenAccountProgramID,
+			tokenLeg.program,
+			pumpfun.EventAuthority,
+			pumpfun.ProgramID,
+		).Build())
+	}
+
+	if swap.Transaction, err = b.transaction(ctx, request.Owner, instructions); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// buildPumpSwap trades a graduated token on its canonical PumpSwap pool against wSOL
+func (b *Builder) buildPumpSwap(ctx context.Context, request PumpFunRequest) (*Swap, error) {
+	address, err := PumpSwapPoolAddress(request.Mint)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := b.pumpSwapPool(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	swap := &Swap{MintIn: pool.BaseMint, MintOut: pool.QuoteMint, AmountIn: request.Amount}
+	if request.Buy {
+		swap.MintIn, swap.MintOut = swap.MintOut, swap.MintIn
+	}
+	slippage := b.slippage(request.SlippageBps)
+
+	in, out, err := b.tokenLegs(ctx, request.Owner, swap.MintIn, swap.MintOut)
+	if err != nil {
+		return nil, err
+	}
+
+	var swapInstruction solana.Instruction
+	wrapAmount := request.Amount
+	if request.Buy {
+		swap.ExpectedOut = pool.QuoteBuy(request.Amount)
+		swap.MinAmountOut = swap.ExpectedOut
+		swap.MaxAmountIn = request.Amount + ceilBps(request.Amount, slippage)
+		wrapAmount = swap.MaxAmountIn
+		swapInstruction, err = PumpSwapInstruction(pool, true, request.Owner, out.account, in.account, out.program, swap.ExpectedOut, swap.MaxAmountIn)
+	} else {
+		swap.ExpectedOut = pool.QuoteSell(request.Amount)
+		swap.MinAmountOut = minAmountOut(swap.ExpectedOut, slippage)
+		swapInstruction, err = PumpSwapInstruction(pool, false, request.Owner, in.account, out.account, in.program, request.Amount, swap.MinAmountOut)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := b.wrap(request.Owner, in, out, wrapAmount, swapInstruction)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Transaction, err = b.transaction(ctx, request.Owner, instructions); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// pumpSwapPool loads a PumpSwap pool with its fees and vault balances
+func (b *Builder) pumpSwapPool(ctx context.Context, address solana.PublicKey) (*PumpSwapPool, error) {
+	config, _, err := solana.FindProgramAddress([][]byte{[]byte("global_config")}, PUMP_SWAP_PROGRAM_ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive PumpSwap config: %w", err)
+	}
+
+	accounts, err := b.fetch(ctx, []solana.PublicKey{address, config})
+	if err != nil {
+		return nil, err
+	}
+	if accounts[address] == nil {
+		return nil, fmt.Errorf("PumpSwap pool %s not found", address)
+	}
+	if accounts[config] == nil {
+		return nil, fmt.Errorf("PumpSwap config %s not found", config)
+	}
+	pool, err := DecodePumpSwapPool(address, accounts[address].Data.GetBinary(), accounts[config].Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+
+	vaults, err := b.fetch(ctx, []solana.PublicKey{pool.BaseVault, pool.QuoteVault})
+	if err != nil {
+		return nil, err
+	}
+	for _, vault := range []struct {
+		address solana.PublicKey
+		reserve *uint64
+	}{{pool.BaseVault, &pool.BaseReserve}, {pool.QuoteVault, &pool.QuoteReserve}} {
+		account := vaults[vault.address]
+		if account == nil || len(account.Data.GetBinary()) < 72 {
+			return nil, fmt.Errorf("vault %s of pool %s not found", vault.address, address)
+		}
+		*vault.reserve = binary.LittleEndian.Uint64(account.Data.GetBinary()[64:])
+	}
+	return pool, nil
+}
+
+// PumpSwapPoolAddress derives the canonical PumpSwap pool a pump.fun token migrates to
+func PumpSwapPoolAddress(mint solana.PublicKey) (solana.PublicKey, error) {
+	authority, _, err := solana.FindProgramAddress([][]byte{[]byte("pool-authority"), mint[:]}, pumpfun.ProgramID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive pool authority: %w", err)
+	}
+	index := make([]byte, 2)
+	address, _, err := solana.FindProgramAddress([][]byte{[]byte("pool"), index, authority[:], mint[:], tx_parser.NATIVE_SOL_PROGRAM_ID[:]}, PUMP_SWAP_PROGRAM_ID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive PumpSwap pool: %w", err)
+	}
+	return address, nil
+}
+
+// DecodePumpSwapPool decodes a PumpSwap pool and the fees of the global config. Reserves
+// are left for the caller to read from the vaults.
+func DecodePumpSwapPool(address solana.PublicKey, data, config []byte) (*PumpSwapPool, error) {
+	if len(data) < PUMP_SWAP_POOL_LENGTH {
+		return nil, fmt.Errorf("account %s is not a PumpSwap pool", address)
+	}
+	if len(config) < PUMP_SWAP_CONFIG_LENGTH {
+		return nil, fmt.Errorf("account is not a PumpSwap global config")
+	}
+
+	key := func(data []byte, offset int) solana.PublicKey {
+		return solana.PublicKeyFromBytes(data[offset : offset+32])
+	}
+	pool := &PumpSwapPool{
+		Pool:           address,
+		BaseMint:       key(data, 43),
+		QuoteMint:      key(data, 75),
+		BaseVault:      key(data, 139),
+		QuoteVault:     key(data, 171),
+		LPFeeBps:       binary.LittleEndian.Uint64(config[40:]),
+		ProtocolFeeBps: binary.LittleEndian.Uint64(config[48:]),
+	}
+	for offset := 57; offset < PUMP_SWAP_CONFIG_LENGTH; offset += 32 {
+		if recipient := key(config, offset); !recipient.IsZero() {
+			pool.ProtocolFeeRecipient = recipient
+			break
+		}
+	}
+	if pool.ProtocolFeeRecipient.IsZero() {
+		return nil, fmt.Errorf("PumpSwap config has no protocol fee recipient")
+	}
+	return pool, nil
+}
+
+// QuoteBuy returns the base tokens bought with quoteIn, fees included
+func (p *PumpSwapPool) QuoteBuy(quoteIn uint64) uint64 {
+	net := mulDiv(quoteIn, 10_000, 10_000+p.LPFeeBps+p.ProtocolFeeBps)
+	return mulDiv(p.BaseReserve, net, p.QuoteReserve+net)
+}
+
+// QuoteSell returns the quote tokens received for baseIn after fees
+func (p *PumpSwapPool) QuoteSell(baseIn uint64) uint64 {
+	gross := mulDiv(p.QuoteReserve, baseIn, p.BaseReserve+baseIn)
+	fees := ceilBps(gross, p.LPFeeBps) + ceilBps(gross, p.ProtocolFeeBps)
+	if fees >= gross {
+		return 0
+	}
+	return gross - fees
+}
+
+// PumpSwapInstruction builds a PumpSwap buy of exactly baseAmount for at most quoteLimit,
+// or a sell of baseAmount for at least quoteLimit. The base and quote accounts are the
+// owner's token accounts, the base program is the token program of the base mint.
+func PumpSwapInstruction(pool *PumpSwapPool, buy bool, owner, baseAccount, quoteAccount, baseProgram solana.PublicKey, baseAmount, quoteLimit uint64) (solana.Instruction, error) {
+	config, _, err := solana.FindProgramAddress([][]byte{[]byte("global_config")}, PUMP_SWAP_PROGRAM_ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive PumpSwap config: %w", err)
+	}
+	eventAuthority, _, err := solana.FindProgramAddress([][]byte{[]byte("__event_authority")}, PUMP_SWAP_PROGRAM_ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive PumpSwap event authority: %w", err)
+	}
+	feeAccount, err := AssociatedTokenAddress(pool.ProtocolFeeRecipient, pool.QuoteMint, solana.TokenProgramID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive protocol fee account: %w", err)
+	}
+
+	data := make([]byte, 24)
+	copy(data, pumpSwapSellDiscriminator)
+	if buy {
+		copy(data, pumpSwapBuyDiscriminator)
+	}
+	binary.LittleEndian.PutUint64(data[8:], baseAmount)
+	binary.LittleEndian.PutUint64(data[16:], quoteLimit)
+
+	return solana.NewInstruction(PUMP_SWAP_PROGRAM_ID, solana.AccountMetaSlice{
+		solana.Meta(pool.Pool),
+		solana.Meta(owner).WRITE().SIGNER(),
+		solana.Meta(config),
+		solana.Meta(pool.BaseMint),
+		solana.Meta(pool.QuoteMint),
+		solana.Meta(baseAccount).WRITE(),
+		solana.Meta(quoteAccount).WRITE(),
+		solana.Meta(pool.BaseVault).WRITE(),
+		solana.Meta(pool.QuoteVault).WRITE(),
+		solana.Meta(pool.ProtocolFeeRecipient),
+		solana.Meta(feeAccount).WRITE(),
+		solana.Meta(baseProgram),
+		solana.Meta(solana.TokenProgramID),
+		solana.Meta(solana.SystemProgramID),
+		solana.Meta(solana.SPLAssociatedTokenAccountProgramID),
+		solana.Meta(eventAuthority),
+		solana.Meta(PUMP_SWAP_PROGRAM_ID),
+	}, data), nil
+}
+
+// mulDiv returns a*b/c rounded down
+func mulDiv(a, b, c uint64) uint64 {
+	if c == 0 {
+		return 0
+	}
+	product := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	return product.Quo(product, new(big.Int).SetUint64(c)).Uint64()
+}
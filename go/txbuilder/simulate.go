@@ -0,0 +1,127 @@
+package txbuilder
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Simulate runs the transaction against the latest state without verifying signatures,
+// returning the consumed compute units, the logs split per top-level instruction and the
+// post-simulation state of the given accounts
+func Simulate(ctx context.Context, rpcClient *rpc.Client, tx *solana.Transaction, accounts ...solana.PublicKey) (*Simulation, error) {
+	// unsigned transactions still need a signature slot per signer to serialize
+	unsigned := *tx
+	if len(unsigned.Signatures) == 0 {
+		unsigned.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+	}
+
+	opts := &rpc.SimulateTransactionOpts{
+		Commitment:             rpc.CommitmentConfirmed,
+		ReplaceRecentBlockhash: true,
+	}
+	if len(accounts) > 0 {
+		opts.Accounts = &rpc.SimulateTransactionAccountsOpts{Encoding: solana.EncodingBase64, Addresses: accounts}
+	}
+	result, err := rpcClient.SimulateTransactionWithOpts(ctx, &unsigned, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate transaction: %w", err)
+	}
+
+	simulation := &Simulation{
+		Err:          result.Value.Err,
+		Logs:         result.Value.Logs,
+		Instructions: ProfileLogs(result.Value.Logs),
+		Accounts:     result.Value.Accounts,
+	}
+	if result.Value.UnitsConsumed != nil {
+		simulation.UnitsConsumed = *result.Value.UnitsConsumed
+	}
+	return simulation, nil
+}
+
+// Simulate runs the transaction through the builder's RPC client
+func (b *Builder) Simulate(ctx context.Context, tx *solana.Transaction, accounts ...solana.PublicKey) (*Simulation, error) {
+	return Simulate(ctx, b.rpcClient, tx, accounts...)
+}
+
+// ProfileLogs splits program logs into top-level instructions, attributing inner calls to
+// the instruction that made them
+func ProfileLogs(logs []string) []InstructionProfile {
+	var profiles []InstructionProfile
+	depth := 0
+	for _, line := range logs {
+		fields := strings.Fields(line)
+		if depth == 0 && len(fields) == 4 && fields[0] == "Program" && fields[2] == "invoke" && fields[3] == "[1]" {
+			program, err := solana.PublicKeyFromBase58(fields[1])
+			if err != nil {
+				continue
+			}
+			profiles = append(profiles, InstructionProfile{Index: len(profiles), Program: program})
+		}
+		if len(profiles) == 0 {
+			continue
+		}
+
+		profile := &profiles[len(profiles)-1]
+		if depth == 0 && !strings.HasSuffix(line, "invoke [1]") {
+			continue // logs between instructions
+		}
+		profile.Logs = append(profile.Logs, line)
+
+		if len(fields) < 3 || fields[0] != "Program" {
+			continue
+		}
+		switch {
+		case fields[2] == "invoke" && len(fields) == 4:
+			depth, _ = strconv.Atoi(strings.Trim(fields[3], "[]"))
+		case fields[2] == "consumed" && depth == 1 && len(fields) >= 4:
+			profile.UnitsConsumed, _ = strconv.ParseUint(fields[3], 10, 64)
+		case fields[2] == "success" || strings.HasPrefix(fields[2], "failed"):
+			if depth == 1 && fields[2] != "success" {
+				profile.Failed = true
+			}
+			depth--
+		}
+	}
+	return profiles
+}
+
+// SetComputeUnitLimit replaces the compute unit limit of an unsigned transaction
+func SetComputeUnitLimit(tx *solana.Transaction, units uint32) error {
+	program := slices.Index(tx.Message.AccountKeys, solana.ComputeBudget)
+	for i, instruction := range tx.Message.Instructions {
+		if program >= 0 && int(instruction.ProgramIDIndex) == program && len(instruction.Data) > 0 && instruction.Data[0] == COMPUTE_BUDGET_SET_COMPUTE_UNIT_LIMIT {
+			data := make([]byte, 5)
+			data[0] = COMPUTE_BUDGET_SET_COMPUTE_UNIT_LIMIT
+			binary.LittleEndian.PutUint32(data[1:], units)
+			tx.Message.Instructions[i].Data = data
+			tx.Signatures = nil
+			return nil
+		}
+	}
+	return fmt.Errorf("transaction has no compute unit limit instruction")
+}
+
+// fitComputeUnitLimit simulates the transaction and sets its limit to the consumed units
+// plus the configured margin
+func (b *Builder) fitComputeUnitLimit(ctx context.Context, tx *solana.Transaction) error {
+	simulation, err := b.Simulate(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if simulation.Err != nil {
+		return fmt.Errorf("transaction simulation failed: %v", simulation.Err)
+	}
+	if simulation.UnitsConsumed == 0 {
+		return nil
+	}
+	units := min(simulation.UnitsConsumed+ceilBps(simulation.UnitsConsumed, b.config.ComputeUnitMarginBps), MAX_COMPUTE_UNIT_LIMIT)
+	return SetComputeUnitLimit(tx, uint32(units))
+}
@@ -0,0 +1,316 @@
+package txbuilder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+// Builder constructs ready-to-sign swap transactions from live pool state
+type Builder struct {
+	rpcClient *rpc.Client
+	pools     *pools.Reader
+	config    Config
+}
+
+// tokenLeg is one side of a swap and the owner's token account for it
+type tokenLeg struct {
+	mint    solana.PublicKey
+	program solana.PublicKey
+	account solana.PublicKey
+	exists  bool
+}
+
+// New creates a transaction builder
+func New(rpcClient *rpc.Client, config Config) *Builder {
+	if config.ComputeUnitLimit == 0 {
+		config.ComputeUnitLimit = 300_000
+	}
+	if config.SlippageBps == 0 {
+		config.SlippageBps = 100
+	}
+	if config.ComputeUnitMarginBps == 0 {
+		config.ComputeUnitMarginBps = 1_000
+	}
+	return &Builder{
+		rpcClient: rpcClient,
+		pools:     pools.New(rpcClient),
+		config:    config,
+	}
+}
+
+// BuildSwap builds an exact input swap against a Raydium AMM v4 or CLMM pool. SOL is
+// wrapped into the owner's wSOL account and unwrapped afterwards, which also unwraps any
+// wSOL the account held before. A missing output token account is created.
+func (b *Builder) BuildSwap(ctx context.Context, request SwapRequest) (*Swap, error) {
+	if request.AmountIn == 0 {
+		return nil, fmt.Errorf("amount in is required")
+	}
+
+	states, err := b.pools.Load(ctx, []solana.PublicKey{request.Pool})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pool: %w", err)
+	}
+	state := states[0]
+
+	mintA, mintB := state.Mints()
+	var mintOut solana.PublicKey
+	switch {
+	case request.MintIn.Equals(mintA):
+		mintOut = mintB
+	case request.MintIn.Equals(mintB):
+		mintOut = mintA
+	default:
+		return nil, fmt.Errorf("mint %s is not in pool %s", request.MintIn, request.Pool)
+	}
+
+	swap := &Swap{MintIn: request.MintIn, MintOut: mintOut, AmountIn: request.AmountIn, MinAmountOut: request.MinAmountOut}
+	if swap.MinAmountOut == 0 {
+		if swap.ExpectedOut, err = state.Quote(request.MintIn, request.AmountIn); err != nil {
+			return nil, fmt.Errorf("failed to quote swap: %w", err)
+		}
+		swap.MinAmountOut = minAmountOut(swap.ExpectedOut, b.slippage(request.SlippageBps))
+	}
+
+	in, out, err := b.tokenLegs(ctx, request.Owner, request.MintIn, mintOut)
+	if err != nil {
+		return nil, err
+	}
+
+	var swapInstruction solana.Instruction
+	switch pool := state.(type) {
+	case *pools.RaydiumAMM:
+		if !in.program.Equals(solana.TokenProgramID) || !out.program.Equals(solana.TokenProgramID) {
+			return nil, fmt.Errorf("raydium AMM pools only trade SPL tokens")
+		}
+		market, err := b.market(ctx, pool)
+		if err != nil {
+			return nil, err
+		}
+		swapInstruction = RaydiumAMMSwapInstruction(pool, market, in.account, out.account, request.Owner, request.AmountIn, swap.MinAmountOut)
+	case *pools.ConcentratedPool:
+		if pool.SwapType != tx_parser.SwapTypeRaydium {
+			return nil, fmt.Errorf("%s pools are not supported", pool.SwapType)
+		}
+		tickArrays, err := b.tickArrays(ctx, pool, request.MintIn.Equals(pool.MintA))
+		if err != nil {
+			return nil, err
+		}
+		if swapInstruction, err = RaydiumCLMMSwapInstruction(pool, request.MintIn, in.account, out.account, request.Owner, tickArrays, request.AmountIn, swap.MinAmountOut); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s pools are not supported", state.Protocol())
+	}
+
+	instructions, err := b.wrap(request.Owner, in, out, request.AmountIn, swapInstruction)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Transaction, err = b.transaction(ctx, request.Owner, instructions); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// tokenLegs resolves the token programs of both mints and the owner's token accounts
+func (b *Builder) tokenLegs(ctx context.Context, owner, mintIn, mintOut solana.PublicKey) (*tokenLeg, *tokenLeg, error) {
+	mints, err := b.fetch(ctx, []solana.PublicKey{mintIn, mintOut})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	legs := []*tokenLeg{{mint: mintIn}, {mint: mintOut}}
+	accounts := make([]solana.PublicKey, len(legs))
+	for i, leg := range legs {
+		mint := mints[leg.mint]
+		if mint == nil {
+			return nil, nil, fmt.Errorf("mint %s not found", leg.mint)
+		}
+		leg.program = mint.Owner
+		if leg.account, err = AssociatedTokenAddress(owner, leg.mint, leg.program); err != nil {
+			return nil, nil, fmt.Errorf("failed to derive token account of %s: %w", leg.mint, err)
+		}
+		accounts[i] = leg.account
+	}
+
+	existing, err := b.fetch(ctx, accounts)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, leg := range legs {
+		leg.exists = existing[leg.account] != nil
+	}
+	if !legs[0].exists && !legs[0].mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		return nil, nil, fmt.Errorf("owner %s has no token account for %s", owner, mintIn)
+	}
+	return legs[0], legs[1], nil
+}
+
+// wrap surrounds the swap with the compute budget, the token account setup and the wSOL unwrap
+func (b *Builder) wrap(owner solana.PublicKey, in, out *tokenLeg, amountIn uint64, swapInstruction solana.Instruction) ([]solana.Instruction, error) {
+	instructions := b.budget()
+	var after []solana.Instruction
+	for _, leg := range []*tokenLeg{in, out} {
+		if !leg.exists {
+			create, err := CreateAssociatedTokenAccountInstruction(owner, owner, leg.mint, leg.program)
+			if err != nil {
+				return nil, err
+			}
+			instructions = append(instructions, create)
+		}
+		if leg.mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+			after = append(after, token.NewCloseAccountInstruction(leg.account, owner, owner, nil).Build())
+		}
+	}
+	if in.mint.Equals(tx_parser.NATIVE_SOL_PROGRAM_ID) {
+		instructions = append(instructions,
+			system.NewTransferInstruction(amountIn, owner, in.account).Build(),
+			token.NewSyncNativeInstruction(in.account).Build(),
+		)
+	}
+
+	instructions = append(instructions, swapInstruction)
+	return append(instructions, after...), nil
+}
+
+// budget returns the compute budget instructions
+func (b *Builder) budget() []solana.Instruction {
+	instructions := []solana.Instruction{computebudget.NewSetComputeUnitLimitInstruction(b.config.ComputeUnitLimit).Build()}
+	if b.config.ComputeUnitPrice > 0 {
+		instructions = append(instructions, computebudget.NewSetComputeUnitPriceInstruction(b.config.ComputeUnitPrice).Build())
+	}
+	return instructions
+}
+
+// slippage returns the requested slippage or the configured default
+func (b *Builder) slippage(requested uint64) uint64 {
+	if requested == 0 {
+		return b.config.SlippageBps
+	}
+	return requested
+}
+
+// market loads the OpenBook market of an AMM v4 pool
+func (b *Builder) market(ctx context.Context, pool *pools.RaydiumAMM) (*Market, error) {
+	accounts, err := b.fetch(ctx, []solana.PublicKey{pool.Market})
+	if err != nil {
+		return nil, err
+	}
+	account := accounts[pool.Market]
+	if account == nil {
+		return nil, fmt.Errorf("market %s of pool %s not found", pool.Market, pool.Pool)
+	}
+	return DecodeMarket(pool.Market, pool.MarketProgram, account.Data.GetBinary())
+}
+
+// tickArrays returns the first initialized tick arrays from the current tick in swap
+// direction. Swapping token A for B moves the price, and the tick, down.
+func (b *Builder) tickArrays(ctx context.Context, pool *pools.ConcentratedPool, zeroForOne bool) ([]solana.PublicKey, error) {
+	step := int32(pool.TickSpacing) * CLMM_TICK_ARRAY_SIZE
+	if zeroForOne {
+		step = -step
+	}
+
+	start := TickArrayStartIndex(pool.TickCurrent, pool.TickSpacing)
+	candidates := make([]solana.PublicKey, CLMM_TICK_ARRAY_SEARCH)
+	for i := range candidates {
+		address, err := TickArrayAddress(pool.Pool, start+int32(i)*step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive tick array: %w", err)
+		}
+		candidates[i] = address
+	}
+
+	accounts, err := b.fetch(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	var tickArrays []solana.PublicKey
+	for _, address := range candidates {
+		if accounts[address] != nil && len(tickArrays) < CLMM_TICK_ARRAYS {
+			tickArrays = append(tickArrays, address)
+		}
+	}
+	if len(tickArrays) == 0 {
+		return nil, fmt.Errorf("pool %s has no initialized tick arrays near tick %d", pool.Pool, pool.TickCurrent)
+	}
+	return tickArrays, nil
+}
+
+// transaction assembles the instructions with a recent blockhash or the configured nonce,
+// paid by the owner
+func (b *Builder) transaction(ctx context.Context, payer solana.PublicKey, instructions []solana.Instruction) (*solana.Transaction, error) {
+	blockhash, instructions, err := b.blockhash(ctx, instructions)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := solana.NewTransaction(instructions, blockhash, solana.TransactionPayer(payer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	if tx, err = b.compress(ctx, tx, payer, blockhash, instructions); err != nil {
+		return nil, err
+	}
+	if b.config.AutoComputeUnitLimit {
+		if err := b.fitComputeUnitLimit(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// unsignedTransaction assembles instructions with a recent blockhash and nothing else
+func (b *Builder) unsignedTransaction(ctx context.Context, payer solana.PublicKey, instructions ...solana.Instruction) (*solana.Transaction, error) {
+	recent, err := b.rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent blockhash: %w", err)
+	}
+	tx, err := solana.NewTransaction(instructions, recent.Value.Blockhash, solana.TransactionPayer(payer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// fetch reads accounts by address, missing accounts are absent
+func (b *Builder) fetch(ctx context.Context, addresses []solana.PublicKey) (map[solana.PublicKey]*rpc.Account, error) {
+	result, err := b.rpcClient.GetMultipleAccountsWithOpts(ctx, addresses, &rpc.GetMultipleAccountsOpts{
+		Commitment: rpc.CommitmentConfirmed,
+		Encoding:   solana.EncodingBase64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	accounts := make(map[solana.PublicKey]*rpc.Account, len(addresses))
+	for i, account := range result.Value {
+		if account != nil && i < len(addresses) {
+			accounts[addresses[i]] = account
+		}
+	}
+	return accounts, nil
+}
+
+// minAmountOut applies the slippage tolerance to the expected output
+func minAmountOut(expected, slippageBps uint64) uint64 {
+	if slippageBps >= 10_000 {
+		return 0
+	}
+	return expected - ceilBps(expected, slippageBps)
+}
+
+// ceilBps returns amount*bps/10000 rounded up
+func ceilBps(amount, bps uint64) uint64 {
+	product := new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(bps))
+	product.Add(product, big.NewInt(9_999))
+	return product.Quo(product, big.NewInt(10_000)).Uint64()
+}
@@ -0,0 +1,573 @@
+package txbuilder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/internal/pumpfun"
+	pump "github.com/soralabs/solana-toolkit/go/internal/pumpfun_anchor"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/pools"
+)
+
+var (
+	sol       = tx_parser.NATIVE_SOL_PROGRAM_ID
+	tokenMint = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+	owner     = solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+)
+
+var simulationLogs = []string{
+	"Program ComputeBudget111111111111111111111111111111 invoke [1]",
+	"Program ComputeBudget111111111111111111111111111111 success",
+	"Program 675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8 invoke [1]",
+	"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+	"Program log: Instruction: Transfer",
+	"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 4645 of 180000 compute units",
+	"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+	"Program 675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8 consumed 31200 of 199850 compute units",
+	"Program 675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8 success",
+	"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [1]",
+	"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 2915 of 168650 compute units",
+	"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA failed: custom program error: 0x1",
+}
+
+type testAccount struct {
+	owner solana.PublicKey
+	data  []byte
+}
+
+func newKey() solana.PublicKey {
+	return solana.NewWallet().PublicKey()
+}
+
+func putKey(data []byte, offset int, key solana.PublicKey) {
+	copy(data[offset:], key[:])
+}
+
+func tokenAccount(amount uint64) testAccount {
+	data := make([]byte, 165)
+	binary.LittleEndian.PutUint64(data[64:], amount)
+	return testAccount{owner: solana.TokenProgramID, data: data}
+}
+
+func mint(program solana.PublicKey) testAccount {
+	return testAccount{owner: program, data: make([]byte, 82)}
+}
+
+func serve(t *testing.T, accounts map[solana.PublicKey]testAccount) *rpc.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		switch request.Method {
+		case "getLatestBlockhash":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":{"blockhash":%q,"lastValidBlockHeight":100}}}`, solana.Hash{7})
+			return
+		case "getMinimumBalanceForRentExemption":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":1447680}`)
+			return
+		case "simulateTransaction":
+			logs, _ := json.Marshal(simulationLogs)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":{"err":null,"logs":%s,"accounts":null,"unitsConsumed":52150}}}`, logs)
+			return
+		}
+
+		var keys []solana.PublicKey
+		json.Unmarshal(request.Params[0], &keys)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			account, ok := accounts[key]
+			if !ok {
+				values[i] = "null"
+				continue
+			}
+			values[i] = fmt.Sprintf(`{"lamports":1,"owner":%q,"data":[%q,"base64"],"executable":false,"rentEpoch":0}`,
+				account.owner, base64.StdEncoding.EncodeToString(account.data))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":[%s]}}`, strings.Join(values, ","))
+	}))
+	t.Cleanup(server.Close)
+	return rpc.New(server.URL)
+}
+
+// instructionPrograms returns the program of each compiled instruction
+func instructionPrograms(t *testing.T, tx *solana.Transaction) []solana.PublicKey {
+	t.Helper()
+	var programs []solana.PublicKey
+	for _, instruction := range tx.Message.Instructions {
+		program, err := tx.Message.Program(instruction.ProgramIDIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		programs = append(programs, program)
+	}
+	return programs
+}
+
+// openBookMarket returns a market account with a nonce that derives a valid vault signer
+func openBookMarket(t *testing.T, address, program solana.PublicKey) testAccount {
+	t.Helper()
+	data := make([]byte, OPENBOOK_MARKET_LENGTH)
+	for nonce := range uint64(256) {
+		binary.LittleEndian.PutUint64(data[45:], nonce)
+		if _, err := solana.CreateProgramAddress([][]byte{address[:], data[45:53]}, program); err == nil {
+			return testAccount{owner: program, data: data}
+		}
+	}
+	t.Fatal("no valid vault signer nonce")
+	return testAccount{}
+}
+
+func TestBuildAMMSwap(t *testing.T) {
+	pool, coinVault, pcVault := newKey(), newKey(), newKey()
+	market, marketProgram := newKey(), newKey()
+
+	data := make([]byte, pools.RAYDIUM_AMM_ACCOUNT_LENGTH)
+	binary.LittleEndian.PutUint64(data[32:], 6)
+	binary.LittleEndian.PutUint64(data[40:], 9)
+	binary.LittleEndian.PutUint64(data[176:], 25)
+	binary.LittleEndian.PutUint64(data[184:], 10_000)
+	putKey(data, 336, coinVault)
+	putKey(data, 368, pcVault)
+	putKey(data, 400, tokenMint)
+	putKey(data, 432, sol)
+	putKey(data, 528, market)
+	putKey(data, 560, marketProgram)
+
+	client := serve(t, map[solana.PublicKey]testAccount{
+		pool:      {owner: tx_parser.RAYDIUM_V4_PROGRAM_ID, data: data},
+		coinVault: tokenAccount(1_000_000_000),
+		pcVault:   tokenAccount(2_000_000_000),
+		market:    openBookMarket(t, market, marketProgram),
+		tokenMint: mint(solana.TokenProgramID),
+		sol:       mint(solana.TokenProgramID),
+	})
+
+	builder := New(client, Config{ComputeUnitPrice: 1_000})
+	swap, err := builder.BuildSwap(context.Background(), SwapRequest{Owner: owner, Pool: pool, MintIn: sol, AmountIn: 100_000_000})
+	if err != nil {
+		t.Fatalf("failed to build swap: %v", err)
+	}
+
+	// 1e8 lamports less the 0.25% fee into a 2e9/1e9 pool
+	if swap.ExpectedOut != 47_505_655 || swap.MinAmountOut != 47_030_598 || !swap.MintOut.Equals(tokenMint) {
+		t.Errorf("unexpected amounts %+v", swap)
+	}
+	if swap.Transaction.Message.RecentBlockhash != (solana.Hash{7}) || !swap.Transaction.Message.AccountKeys[0].Equals(owner) {
+		t.Error("expected the owner to pay with the latest blockhash")
+	}
+
+	// budget limit and price, wSOL and token account creation, wrap, swap, unwrap
+	programs := instructionPrograms(t, swap.Transaction)
+	want := []solana.PublicKey{
+		solana.ComputeBudget, solana.ComputeBudget,
+		solana.SPLAssociatedTokenAccountProgramID, solana.SPLAssociatedTokenAccountProgramID,
+		solana.SystemProgramID, solana.TokenProgramID,
+		tx_parser.RAYDIUM_V4_PROGRAM_ID, solana.TokenProgramID,
+	}
+	if len(programs) != len(want) {
+		t.Fatalf("expected %d instructions, got %v", len(want), programs)
+	}
+	for i := range want {
+		if !programs[i].Equals(want[i]) {
+			t.Errorf("instruction %d: expected %s, got %s", i, want[i], programs[i])
+		}
+	}
+
+	swapInstruction := swap.Transaction.Message.Instructions[6]
+	if swapInstruction.Data[0] != RAYDIUM_AMM_SWAP_BASE_IN || binary.LittleEndian.Uint64(swapInstruction.Data[9:]) != swap.MinAmountOut {
+		t.Errorf("unexpected swap data %v", swapInstruction.Data)
+	}
+	accounts, _ := swapInstruction.ResolveInstructionAccounts(&swap.Transaction.Message)
+	source, _ := AssociatedTokenAddress(owner, sol, solana.TokenProgramID)
+	if len(accounts) != 18 || !accounts[8].PublicKey.Equals(market) || !accounts[15].PublicKey.Equals(source) {
+		t.Errorf("unexpected swap accounts %v", accounts)
+	}
+}
+
+func TestBuildCLMMSwap(t *testing.T) {
+	pool, config, vaultA, vaultB, observation := newKey(), newKey(), newKey(), newKey(), newKey()
+
+	discriminator := func(name string) []byte {
+		sum := sha256.Sum256([]byte("account:" + name))
+		return sum[:8]
+	}
+	data := make([]byte, 1544)
+	copy(data, discriminator("PoolState"))
+	putKey(data, 9, config)
+	putKey(data, 73, tokenMint)
+	putKey(data, 105, sol)
+	putKey(data, 137, vaultA)
+	putKey(data, 169, vaultB)
+	putKey(data, 201, observation)
+	binary.LittleEndian.PutUint16(data[235:], 10)
+	liquidity := big.NewInt(1_000_000_000_000).FillBytes(make([]byte, 16))
+	sqrtPrice := new(big.Int).Lsh(big.NewInt(2), 64).FillBytes(make([]byte, 16))
+	for i := range 16 {
+		data[237+i] = liquidity[15-i]
+		data[253+i] = sqrtPrice[15-i]
+	}
+	binary.LittleEndian.PutUint32(data[269:], uint32(13_863))
+
+	configData := make([]byte, 117)
+	copy(configData, discriminator("AmmConfig"))
+	binary.LittleEndian.PutUint32(configData[47:], 2_500)
+
+	// the current array, 13800, is not initialized, so the swap starts at the next one
+	tickArray1, _ := TickArrayAddress(pool, 13_200)
+	tickArray2, _ := TickArrayAddress(pool, 12_000)
+	input, _ := AssociatedTokenAddress(owner, tokenMint, solana.Token2022ProgramID)
+
+	client := serve(t, map[solana.PublicKey]testAccount{
+		pool:       {owner: tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, data: data},
+		config:     {owner: tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, data: configData},
+		vaultA:     tokenAccount(1_000_000),
+		vaultB:     tokenAccount(1_000_000),
+		tickArray1: {owner: tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID},
+		tickArray2: {owner: tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID},
+		tokenMint:  mint(solana.Token2022ProgramID),
+		sol:        mint(solana.TokenProgramID),
+		input:      tokenAccount(5_000),
+	})
+
+	swap, err := New(client, Config{}).BuildSwap(context.Background(), SwapRequest{Owner: owner, Pool: pool, MintIn: tokenMint, AmountIn: 1_000, MinAmountOut: 3_000})
+	if err != nil {
+		t.Fatalf("failed to build swap: %v", err)
+	}
+	if swap.ExpectedOut != 0 || swap.MinAmountOut != 3_000 {
+		t.Errorf("expected the requested minimum without a quote, got %+v", swap)
+	}
+
+	// budget limit, wSOL account creation, swap, unwrap
+	programs := instructionPrograms(t, swap.Transaction)
+	if len(programs) != 4 || !programs[2].Equals(tx_parser.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID) {
+		t.Fatalf("unexpected instructions %v", programs)
+	}
+	swapInstruction := swap.Transaction.Message.Instructions[2]
+	accounts, _ := swapInstruction.ResolveInstructionAccounts(&swap.Transaction.Message)
+	if len(accounts) != 16 || !accounts[3].PublicKey.Equals(input) || !accounts[7].PublicKey.Equals(observation) {
+		t.Fatalf("unexpected swap accounts %v", accounts)
+	}
+	if !accounts[14].PublicKey.Equals(tickArray1) || !accounts[15].PublicKey.Equals(tickArray2) {
+		t.Errorf("expected the initialized tick arrays below the current tick, got %v", accounts[14:])
+	}
+	if swapInstruction.Data[40] != 1 || binary.LittleEndian.Uint64(swapInstruction.Data[16:]) != 3_000 {
+		t.Errorf("unexpected swap data %v", swapInstruction.Data)
+	}
+}
+
+func TestBuildSwapRequiresInputAccount(t *testing.T) {
+	pool, coinVault, pcVault := newKey(), newKey(), newKey()
+	data := make([]byte, pools.RAYDIUM_AMM_ACCOUNT_LENGTH)
+	binary.LittleEndian.PutUint64(data[184:], 10_000)
+	putKey(data, 336, coinVault)
+	putKey(data, 368, pcVault)
+	putKey(data, 400, tokenMint)
+	putKey(data, 432, sol)
+
+	client := serve(t, map[solana.PublicKey]testAccount{
+		pool:      {owner: tx_parser.RAYDIUM_V4_PROGRAM_ID, data: data},
+		coinVault: tokenAccount(1_000),
+		pcVault:   tokenAccount(1_000),
+		tokenMint: mint(solana.TokenProgramID),
+		sol:       mint(solana.TokenProgramID),
+	})
+
+	_, err := New(client, Config{}).BuildSwap(context.Background(), SwapRequest{Owner: owner, Pool: pool, MintIn: tokenMint, AmountIn: 10})
+	if err == nil || !strings.Contains(err.Error(), "no token account") {
+		t.Errorf("expected a missing token account error, got %v", err)
+	}
+}
+
+func TestTickArrayStartIndex(t *testing.T) {
+	for _, tc := range []struct {
+		tick    int32
+		spacing uint16
+		want    int32
+	}{
+		{13_863, 10, 13_800},
+		{0, 60, 0},
+		{-1, 10, -600},
+		{-600, 10, -600},
+		{-601, 10, -1_200},
+	} {
+		if got := TickArrayStartIndex(tc.tick, tc.spacing); got != tc.want {
+			t.Errorf("tick %d spacing %d: expected %d, got %d", tc.tick, tc.spacing, tc.want, got)
+		}
+	}
+}
+
+// bondingCurve encodes a pump.fun bonding curve account
+func bondingCurve(virtualTokens, virtualSol, realTokens uint64, complete bool) testAccount {
+	data := append([]byte{}, pump.BondingCurveDiscriminator[:]...)
+	for _, v := range []uint64{virtualTokens, virtualSol, realTokens, 0, 1_000_000_000_000_000} {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	if complete {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	return testAccount{owner: tx_parser.PUMP_FUN_PROGRAM_ID, data: data}
+}
+
+func TestBuildPumpFunBuy(t *testing.T) {
+	curve, _, _ := pumpfun.DeriveBondingCurveAddresses(tokenMint)
+	client := serve(t, map[solana.PublicKey]testAccount{
+		curve:     bondingCurve(1_073_000_000_000_000, 30_000_000_000, 793_100_000_000_000, false),
+		tokenMint: mint(solana.TokenProgramID),
+		sol:       mint(solana.TokenProgramID),
+	})
+
+	swap, err := New(client, Config{}).BuildPumpFunSwap(context.Background(), PumpFunRequest{Owner: owner, Mint: tokenMint, Buy: true, Amount: 1_000_000_000})
+	if err != nil {
+		t.Fatalf("failed to build buy: %v", err)
+	}
+
+	// 0.99 SOL after the 1% fee into the initial curve, paying at most 1% more SOL
+	if swap.ExpectedOut != 34_277_831_558_567 || swap.MinAmountOut != swap.ExpectedOut || swap.MaxAmountIn != 1_010_000_000 {
+		t.Errorf("unexpected amounts %+v", swap)
+	}
+
+	// budget, token account creation, buy, and no wSOL wrapping
+	programs := instructionPrograms(t, swap.Transaction)
+	if len(programs) != 3 || !programs[1].Equals(solana.SPLAssociatedTokenAccountProgramID) || !programs[2].Equals(pumpfun.ProgramID) {
+		t.Fatalf("unexpected instructions %v", programs)
+	}
+	data := swap.Transaction.Message.Instructions[2].Data
+	if binary.LittleEndian.Uint64(data[8:]) != swap.ExpectedOut || binary.LittleEndian.Uint64(data[16:]) != swap.MaxAmountIn {
+		t.Errorf("unexpected buy data %v", data)
+	}
+}
+
+func TestBuildPumpSwapSell(t *testing.T) {
+	curve, _, _ := pumpfun.DeriveBondingCurveAddresses(tokenMint)
+	pool, _ := PumpSwapPoolAddress(tokenMint)
+	config, _, _ := solana.FindProgramAddress([][]byte{[]byte("global_config")}, PUMP_SWAP_PROGRAM_ID)
+	baseVault, quoteVault, recipient := newKey(), newKey(), newKey()
+
+	poolData := make([]byte, PUMP_SWAP_POOL_LENGTH)
+	putKey(poolData, 43, tokenMint)
+	putKey(poolData, 75, sol)
+	putKey(poolData, 139, baseVault)
+	putKey(poolData, 171, quoteVault)
+	configData := make([]byte, PUMP_SWAP_CONFIG_LENGTH)
+	binary.LittleEndian.PutUint64(configData[40:], 20)
+	binary.LittleEndian.PutUint64(configData[48:], 5)
+	putKey(configData, 57, recipient)
+	input, _ := AssociatedTokenAddress(owner, tokenMint, solana.TokenProgramID)
+
+	client := serve(t, map[solana.PublicKey]testAccount{
+		curve:      bondingCurve(0, 0, 0, true),
+		pool:       {owner: PUMP_SWAP_PROGRAM_ID, data: poolData},
+		config:     {owner: PUMP_SWAP_PROGRAM_ID, data: configData},
+		baseVault:  tokenAccount(1_000_000_000_000),
+		quoteVault: tokenAccount(100_000_000_000),
+		tokenMint:  mint(solana.TokenProgramID),
+		sol:        mint(solana.TokenProgramID),
+		input:      tokenAccount(5_000_000_000),
+	})
+
+	swap, err := New(client, Config{}).BuildPumpFunSwap(context.Background(), PumpFunRequest{Owner: owner, Mint: tokenMint, Amount: 1_000_000_000})
+	if err != nil {
+		t.Fatalf("failed to build sell: %v", err)
+	}
+
+	// 99_900_099 lamports out of the pool less the 0.2% LP and 0.05% protocol fees
+	if swap.ExpectedOut != 99_650_347 || swap.MinAmountOut != 98_653_843 || !swap.MintOut.Equals(sol) {
+		t.Errorf("unexpected amounts %+v", swap)
+	}
+
+	// budget, wSOL account creation, sell, unwrap
+	programs := instructionPrograms(t, swap.Transaction)
+	if len(programs) != 4 || !programs[2].Equals(PUMP_SWAP_PROGRAM_ID) {
+		t.Fatalf("unexpected instructions %v", programs)
+	}
+	sell := swap.Transaction.Message.Instructions[2]
+	accounts, _ := sell.ResolveInstructionAccounts(&swap.Transaction.Message)
+	if len(accounts) != 17 || !accounts[0].PublicKey.Equals(pool) || !accounts[5].PublicKey.Equals(input) || !accounts[9].PublicKey.Equals(recipient) {
+		t.Errorf("unexpected sell accounts %v", accounts)
+	}
+	if binary.LittleEndian.Uint64(sell.Data[16:]) != swap.MinAmountOut {
+		t.Errorf("unexpected sell data %v", sell.Data)
+	}
+}
+
+func TestProfileLogs(t *testing.T) {
+	profiles := ProfileLogs(simulationLogs)
+	if len(profiles) != 3 {
+		t.Fatalf("expected three instructions, got %+v", profiles)
+	}
+	swap := profiles[1]
+	if !swap.Program.Equals(tx_parser.RAYDIUM_V4_PROGRAM_ID) || swap.UnitsConsumed != 31_200 || len(swap.Logs) != 7 || swap.Failed {
+		t.Errorf("unexpected swap profile %+v", swap)
+	}
+	if profiles[0].UnitsConsumed != 0 || profiles[2].UnitsConsumed != 2_915 || !profiles[2].Failed {
+		t.Errorf("unexpected profiles %+v", profiles)
+	}
+}
+
+func TestAutoComputeUnitLimit(t *testing.T) {
+	curve, _, _ := pumpfun.DeriveBondingCurveAddresses(tokenMint)
+	input, _ := AssociatedTokenAddress(owner, tokenMint, solana.TokenProgramID)
+	client := serve(t, map[solana.PublicKey]testAccount{
+		curve:     bondingCurve(1_073_000_000_000_000, 30_000_000_000, 793_100_000_000_000, false),
+		tokenMint: mint(solana.TokenProgramID),
+		sol:       mint(solana.TokenProgramID),
+		input:     tokenAccount(1_000_000),
+	})
+
+	swap, err := New(client, Config{AutoComputeUnitLimit: true}).BuildPumpFunSwap(context.Background(), PumpFunRequest{Owner: owner, Mint: tokenMint, Amount: 1_000_000})
+	if err != nil {
+		t.Fatalf("failed to build sell: %v", err)
+	}
+
+	// 52150 simulated units plus the 10% margin
+	limit := swap.Transaction.Message.Instructions[0].Data
+	if limit[0] != COMPUTE_BUDGET_SET_COMPUTE_UNIT_LIMIT || binary.LittleEndian.Uint32(limit[1:]) != 57_365 {
+		t.Errorf("unexpected compute unit limit %v", limit)
+	}
+}
+
+func TestNonceTransactions(t *testing.T) {
+	nonce, authority := newKey(), newKey()
+	data := make([]byte, NONCE_ACCOUNT_LENGTH)
+	binary.LittleEndian.PutUint32(data[4:], 1)
+	putKey(data, 8, authority)
+	data[40] = 3
+
+	curve, _, _ := pumpfun.DeriveBondingCurveAddresses(tokenMint)
+	client := serve(t, map[solana.PublicKey]testAccount{
+		nonce:     {owner: solana.SystemProgramID, data: data},
+		curve:     bondingCurve(1_073_000_000_000_000, 30_000_000_000, 793_100_000_000_000, false),
+		tokenMint: mint(solana.TokenProgramID),
+		sol:       mint(solana.TokenProgramID),
+	})
+
+	swap, err := New(client, Config{NonceAccount: nonce, NonceAuthority: authority}).BuildPumpFunSwap(context.Background(), PumpFunRequest{Owner: owner, Mint: tokenMint, Buy: true, Amount: 1_000_000})
+	if err != nil {
+		t.Fatalf("failed to build buy: %v", err)
+	}
+	tx := swap.Transaction
+	if tx.Message.RecentBlockhash != (solana.Hash{3}) {
+		t.Errorf("expected the durable nonce as blockhash, got %s", tx.Message.RecentBlockhash)
+	}
+	advance := tx.Message.Instructions[0]
+	accounts, _ := advance.ResolveInstructionAccounts(&tx.Message)
+	if program, _ := tx.Message.Program(advance.ProgramIDIndex); !program.Equals(solana.SystemProgramID) || !accounts[0].PublicKey.Equals(nonce) || !accounts[2].IsSigner {
+		t.Errorf("expected the nonce advance signed by the authority first, got %v", accounts)
+	}
+
+	if _, err := New(client, Config{NonceAccount: nonce, NonceAuthority: owner}).BuildPumpFunSwap(context.Background(), PumpFunRequest{Owner: owner, Mint: tokenMint, Buy: true, Amount: 1_000_000}); err == nil {
+		t.Error("expected a nonce under another authority to fail")
+	}
+
+	create, err := New(client, Config{}).CreateNonceAccount(context.Background(), owner, nonce, authority)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(create.Message.Instructions) != 2 || create.Message.Header.NumRequiredSignatures != 2 || binary.LittleEndian.Uint64(create.Message.Instructions[0].Data[4:]) != 1_447_680 {
+		t.Errorf("unexpected nonce account creation %+v", create.Message)
+	}
+}
+
+func lookupTable(authority solana.PublicKey, addresses []solana.PublicKey) testAccount {
+	data := make([]byte, 56, 56+32*len(addresses))
+	binary.LittleEndian.PutUint32(data, 1)
+	binary.LittleEndian.PutUint64(data[4:], ^uint64(0))
+	data[21] = 1
+	putKey(data, 22, authority)
+	for _, address := range addresses {
+		data = append(data, address[:]...)
+	}
+	return testAccount{owner: ADDRESS_LOOKUP_TABLE_PROGRAM_ID, data: data}
+}
+
+func TestLookupTableInstructions(t *testing.T) {
+	authority := newKey()
+	create, table, err := CreateLookupTableInstruction(authority, owner, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, bump, _ := LookupTableAddress(authority, 42)
+	data, _ := create.Data()
+	if !table.Equals(expected) || len(data) != 13 || binary.LittleEndian.Uint64(data[4:]) != 42 || data[12] != bump {
+		t.Errorf("unexpected create instruction %v for %s", data, table)
+	}
+
+	existing := []solana.PublicKey{newKey(), newKey()}
+	addresses := append([]solana.PublicKey{existing[0]}, make([]solana.PublicKey, 25)...)
+	for i := 1; i < len(addresses); i++ {
+		addresses[i] = newKey()
+	}
+	client := serve(t, map[solana.PublicKey]testAccount{table: lookupTable(authority, existing)})
+	txs, err := New(client, Config{}).ExtendLookupTable(context.Background(), table, authority, owner, addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 25 new addresses in chunks of 20
+	if len(txs) != 2 || binary.LittleEndian.Uint64(txs[0].Message.Instructions[0].Data[4:]) != 20 || binary.LittleEndian.Uint64(txs[1].Message.Instructions[0].Data[4:]) != 5 {
+		t.Errorf("unexpected extend transactions %d", len(txs))
+	}
+}
+
+func TestCompressWithLookupTables(t *testing.T) {
+	var instructions []solana.Instruction
+	var recipients []solana.PublicKey
+	for range 40 {
+		recipient := newKey()
+		recipients = append(recipients, recipient)
+		instructions = append(instructions, system.NewTransferInstruction(1, owner, recipient).Build())
+	}
+	table := newKey()
+	client := serve(t, map[solana.PublicKey]testAccount{table: lookupTable(owner, recipients)})
+
+	if _, err := New(client, Config{}).transaction(context.Background(), owner, instructions); err == nil {
+		t.Error("expected an oversized transaction without lookup tables to fail")
+	}
+
+	tx, err := New(client, Config{LookupTables: []solana.PublicKey{table}}).transaction(context.Background(), owner, instructions)
+	if err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if !tx.Message.IsVersioned() || len(tx.Message.AddressTableLookups) != 1 {
+		t.Fatalf("expected a version 0 message over the table, got %+v", tx.Message)
+	}
+
+	// decoding loses the looked up keys until the tables are resolved
+	tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+	encoded, _ := tx.MarshalBinary()
+	decoded, err := solana.TransactionFromBytes(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ResolveLookupTables(context.Background(), client, decoded); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := decoded.Message.Instructions[39].ResolveInstructionAccounts(&decoded.Message)
+	if err != nil || !accounts[1].PublicKey.Equals(recipients[39]) {
+		t.Errorf("expected the resolved recipient, got %v %v", accounts, err)
+	}
+}
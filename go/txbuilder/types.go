@@ -0,0 +1,133 @@
+package txbuilder
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Config controls the compute budget and slippage of built transactions
+type Config struct {
+	// ComputeUnitLimit is the compute unit limit of the transaction, defaults to 300_000
+	ComputeUnitLimit uint32
+
+	// ComputeUnitPrice is the priority fee in micro-lamports per compute unit, no price
+	// is set when zero
+	ComputeUnitPrice uint64
+
+	// SlippageBps is the tolerated shortfall from the quoted output, defaults to 100 (1%)
+	SlippageBps uint64
+
+	// AutoComputeUnitLimit simulates built transactions and lowers the compute unit limit
+	// to the consumed units plus ComputeUnitMarginBps
+	AutoComputeUnitLimit bool
+
+	// ComputeUnitMarginBps is the headroom added to simulated units, defaults to 1000 (10%)
+	ComputeUnitMarginBps uint64
+
+	// NonceAccount makes built transactions use its durable nonce instead of a recent
+	// blockhash, so they can be signed offline and submitted later. NonceAuthority must
+	// sign them.
+	NonceAccount   solana.PublicKey
+	NonceAuthority solana.PublicKey
+
+	// LookupTables compress built transactions into version 0 messages when they exceed
+	// MAX_TRANSACTION_SIZE as legacy transactions
+	LookupTables []solana.PublicKey
+}
+
+// SwapRequest describes an exact input swap against a single pool
+type SwapRequest struct {
+	// Owner signs the transaction, pays its fees and holds the token accounts
+	Owner solana.PublicKey
+
+	// Pool is a Raydium AMM v4 or CLMM pool
+	Pool solana.PublicKey
+
+	MintIn   solana.PublicKey
+	AmountIn uint64
+
+	// SlippageBps overrides the configured slippage when non-zero
+	SlippageBps uint64
+
+	// MinAmountOut skips the quote and sets the minimum output directly when non-zero
+	MinAmountOut uint64
+}
+
+// Swap is a built, unsigned swap transaction
+type Swap struct {
+	Transaction  *solana.Transaction
+	MintIn       solana.PublicKey
+	MintOut      solana.PublicKey
+	AmountIn     uint64
+	ExpectedOut  uint64 // quoted output, 0 when MinAmountOut was requested
+	MinAmountOut uint64
+	MaxAmountIn  uint64 // most the input may cost, set by pump.fun buys of an exact output
+}
+
+// Market holds the OpenBook accounts a Raydium AMM v4 swap passes along
+type Market struct {
+	Address     solana.PublicKey
+	Program     solana.PublicKey
+	Bids        solana.PublicKey
+	Asks        solana.PublicKey
+	EventQueue  solana.PublicKey
+	BaseVault   solana.PublicKey
+	QuoteVault  solana.PublicKey
+	VaultSigner solana.PublicKey
+}
+
+// PumpFunRequest describes a trade of a pump.fun token against SOL, on its bonding curve
+// or on PumpSwap once the token graduated
+type PumpFunRequest struct {
+	// Owner signs the transaction, pays its fees and holds the token accounts
+	Owner solana.PublicKey
+
+	Mint solana.PublicKey
+
+	// Buy spends Amount lamports on the token, otherwise Amount raw tokens are sold
+	Buy    bool
+	Amount uint64
+
+	// SlippageBps overrides the configured slippage when non-zero
+	SlippageBps uint64
+}
+
+// PumpSwapPool is a PumpSwap pool of a graduated token, quoted in wSOL
+type PumpSwapPool struct {
+	Pool                 solana.PublicKey
+	BaseMint             solana.PublicKey
+	QuoteMint            solana.PublicKey
+	BaseVault            solana.PublicKey
+	QuoteVault           solana.PublicKey
+	BaseReserve          uint64
+	QuoteReserve         uint64
+	LPFeeBps             uint64
+	ProtocolFeeBps       uint64
+	ProtocolFeeRecipient solana.PublicKey
+}
+
+// Simulation is the outcome of simulating a transaction
+type Simulation struct {
+	Err           any // execution error, nil when the simulation succeeded
+	Logs          []string
+	UnitsConsumed uint64
+	Instructions  []InstructionProfile
+	Accounts      []*rpc.Account // post-simulation state of the requested accounts
+}
+
+// InstructionProfile is the compute usage and logs of one top-level instruction
+type InstructionProfile struct {
+	Index         int
+	Program       solana.PublicKey
+	UnitsConsumed uint64
+	Logs          []string // log lines of the instruction and its inner calls
+	Failed        bool
+}
+
+// NonceAccount is the state of an initialized durable nonce account
+type NonceAccount struct {
+	Address              solana.PublicKey
+	Authority            solana.PublicKey
+	Nonce                solana.Hash // the blockhash transactions using the nonce carry
+	LamportsPerSignature uint64
+}
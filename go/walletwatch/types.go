@@ -0,0 +1,77 @@
+package walletwatch
+
+import (
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/geyser"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/stream"
+)
+
+// Kind classifies a wallet's activity
+type Kind string
+
+const (
+	KindSwap      Kind = "swap"
+	KindTransfer  Kind = "transfer"
+	KindLiquidity Kind = "liquidity" // pools created and seeded by the wallet
+	KindStake     Kind = "stake"
+)
+
+// Direction is the side of a transfer the wallet is on
+type Direction string
+
+const (
+	DirectionIn  Direction = "in"
+	DirectionOut Direction = "out"
+)
+
+// Source selects the transport transactions are streamed over
+type Source string
+
+const (
+	SourceWebSocket Source = "websocket"
+	SourceGeyser    Source = "geyser"
+)
+
+// Config controls the watched wallets and the stream they are discovered on
+type Config struct {
+	// Wallets whose activity is reported
+	Wallets []solana.PublicKey
+
+	// Source is the transport, defaults to WebSocket
+	Source Source
+
+	// Stream configures the WebSocket source, its Programs are replaced by the wallets
+	Stream stream.Config
+
+	// Geyser configures the Geyser source, its Programs are replaced by the wallets and
+	// account filters are cleared
+	Geyser geyser.Config
+
+	// Kinds limits the notifications to the given kinds, all kinds if empty
+	Kinds []Kind
+
+	// Buffer is the size of the notifications channel, defaults to 1024
+	Buffer int
+
+	// Dedupe is the number of recent signatures remembered to drop transactions delivered
+	// more than once, as each watched wallet has its own subscription, defaults to 10000
+	Dedupe int
+}
+
+// Notification is a single action of a watched wallet. Exactly one of Swap, Transfer,
+// PoolCreation and Stake is set, matching Kind.
+type Notification struct {
+	Wallet       solana.PublicKey             `json:"wallet"`
+	Kind         Kind                         `json:"kind"`
+	Direction    Direction                    `json:"direction,omitempty"` // set for transfers
+	Signature    solana.Signature             `json:"signature"`
+	Slot         uint64                       `json:"slot"`
+	BlockTime    *solana.UnixTimeSeconds      `json:"block_time"`
+	Swap         *tx_parser.SwapInfo          `json:"swap,omitempty"`
+	Transfer     *tx_parser.TransferInfo      `json:"transfer,omitempty"`
+	PoolCreation *tx_parser.PoolCreatedEvent  `json:"pool_creation,omitempty"`
+	Stake        *tx_parser.StakeEvent        `json:"stake,omitempty"`
+	Transaction  *tx_parser.ParsedTransaction `json:"-"`
+}
@@ -0,0 +1,218 @@
+package walletwatch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/soralabs/solana-toolkit/go/geyser"
+	"github.com/soralabs/solana-toolkit/go/internal/cache"
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+	"github.com/soralabs/solana-toolkit/go/stream"
+)
+
+// Watcher streams the transactions of a set of wallets and emits a notification for each
+// swap, transfer, pool creation and stake action they take part in
+type Watcher struct {
+	rpcClient     *rpc.Client
+	config        Config
+	wallets       map[solana.PublicKey]bool
+	kinds         map[Kind]bool
+	notifications chan *Notification
+
+	// seen holds the signatures of recently handled transactions, which every
+	// subscription of a wallet they touch delivers
+	seenMu sync.Mutex
+	seen   *cache.Cache[solana.Signature, struct{}]
+
+	// OnError is called when the stream fails or a transaction cannot be parsed, the
+	// watcher keeps running
+	OnError func(err error)
+}
+
+// New creates a watcher. The RPC client is used by the WebSocket source to fetch transactions.
+func New(rpcClient *rpc.Client, config Config) *Watcher {
+	if config.Source == "" {
+		config.Source = SourceWebSocket
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = 1024
+	}
+	if config.Dedupe <= 0 {
+		config.Dedupe = 10_000
+	}
+
+	wallets := make(map[solana.PublicKey]bool, len(config.Wallets))
+	for _, wallet := range config.Wallets {
+		wallets[wallet] = true
+	}
+	kinds := make(map[Kind]bool, len(config.Kinds))
+	for _, kind := range config.Kinds {
+		kinds[kind] = true
+	}
+
+	return &Watcher{
+		rpcClient:     rpcClient,
+		config:        config,
+		wallets:       wallets,
+		kinds:         kinds,
+		notifications: make(chan *Notification, config.Buffer),
+		seen:          cache.New[solana.Signature, struct{}](cache.Config{Size: config.Dedupe}),
+	}
+}
+
+// Notifications returns the channel notifications are delivered on. It is closed when Run returns.
+func (w *Watcher) Notifications() <-chan *Notification {
+	return w.notifications
+}
+
+// Run streams the wallets' transactions until the context is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.notifications)
+
+	if len(w.config.Wallets) == 0 {
+		return fmt.Errorf("at least one wallet is required")
+	}
+
+	errs := make(chan error, 1)
+	switch w.config.Source {
+	case SourceWebSocket:
+		config := w.config.Stream
+		config.Programs = w.config.Wallets
+		streamer := stream.New(w.rpcClient, config)
+		streamer.OnError = w.onError
+
+		go func() { errs <- streamer.Run(ctx) }()
+		for result := range streamer.Results() {
+			w.handle(ctx, result.Transaction, result.Err)
+		}
+	case SourceGeyser:
+		config := w.config.Geyser
+		config.Programs = w.config.Wallets
+		config.Accounts, config.Owners = nil, nil
+		client := geyser.New(config)
+		client.OnError = w.onError
+
+		go func() { errs <- client.Run(ctx) }()
+		for result := range client.Results() {
+			w.handle(ctx, result.Transaction, result.Err)
+		}
+	default:
+		return fmt.Errorf("unknown source %q", w.config.Source)
+	}
+	return <-errs
+}
+
+// Process classifies a transaction obtained elsewhere, e.g. from a backfill, and
+// delivers its notifications
+func (w *Watcher) Process(ctx context.Context, tx *tx_parser.ParsedTransaction) {
+	w.handle(ctx, tx, nil)
+}
+
+// Classify returns the notifications of the watched wallets in the transaction
+func (w *Watcher) Classify(tx *tx_parser.ParsedTransaction) []*Notification {
+	var notifications []*Notification
+	emit := func(wallet solana.PublicKey, kind Kind, fill func(*Notification)) {
+		if len(w.kinds) > 0 && !w.kinds[kind] {
+			return
+		}
+		notification := &Notification{
+			Wallet:      wallet,
+			Kind:        kind,
+			Signature:   tx.Signature,
+			Slot:        tx.Slot,
+			BlockTime:   tx.BlockTime,
+			Transaction: tx,
+		}
+		fill(notification)
+		notifications = append(notifications, notification)
+	}
+
+	// the transfers of a swap or pool creation are its legs, not separate activity
+	trading := make(map[solana.PublicKey]bool)
+
+	for _, swap := range tx.Swaps {
+		for _, wallet := range w.watched(swap.Signers...) {
+			trading[wallet] = true
+			emit(wallet, KindSwap, func(n *Notification) { n.Swap = swap })
+		}
+	}
+	for _, creation := range tx.PoolCreations {
+		for _, wallet := range w.watched(creation.Creator) {
+			trading[wallet] = true
+			emit(wallet, KindLiquidity, func(n *Notification) { n.PoolCreation = creation })
+		}
+	}
+	for _, event := range tx.StakeEvents {
+		for _, wallet := range w.watched(event.Authority, event.Recipient) {
+			emit(wallet, KindStake, func(n *Notification) { n.Stake = event })
+		}
+	}
+
+	for _, transfer := range tx.Transfers {
+		from, to := transfer.Source, transfer.Destination
+		if transfer.Type == tx_parser.TransferTypeToken {
+			from, to = transfer.SourceOwner, transfer.DestinationOwner
+		}
+		if from.Equals(to) {
+			continue
+		}
+		if w.wallets[from] && !trading[from] {
+			emit(from, KindTransfer, func(n *Notification) { n.Transfer, n.Direction = transfer, DirectionOut })
+		}
+		if w.wallets[to] && !trading[to] {
+			emit(to, KindTransfer, func(n *Notification) { n.Transfer, n.Direction = transfer, DirectionIn })
+		}
+	}
+	return notifications
+}
+
+// handle delivers the notifications of a streamed transaction, once per signature
+func (w *Watcher) handle(ctx context.Context, tx *tx_parser.ParsedTransaction, err error) {
+	if err != nil {
+		w.onError(err)
+		return
+	}
+	if tx == nil || !w.firstSeen(tx.Signature) {
+		return
+	}
+	for _, notification := range w.Classify(tx) {
+		select {
+		case w.notifications <- notification:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// firstSeen records the signature, reporting whether it was not handled recently
+func (w *Watcher) firstSeen(signature solana.Signature) bool {
+	w.seenMu.Lock()
+	defer w.seenMu.Unlock()
+	if _, ok := w.seen.Get(signature); ok {
+		return false
+	}
+	w.seen.Put(signature, struct{}{})
+	return true
+}
+
+// watched returns the distinct watched wallets among the accounts
+func (w *Watcher) watched(accounts ...solana.PublicKey) []solana.PublicKey {
+	var wallets []solana.PublicKey
+	for _, account := range accounts {
+		if w.wallets[account] && !slices.Contains(wallets, account) {
+			wallets = append(wallets, account)
+		}
+	}
+	return wallets
+}
+
+func (w *Watcher) onError(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
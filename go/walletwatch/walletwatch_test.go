@@ -0,0 +1,121 @@
+package walletwatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/soralabs/solana-toolkit/go/internal/tx_parser"
+)
+
+var (
+	alice = solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	bob   = solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	carol = solana.MustPublicKeyFromBase58("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263")
+	token = solana.MustPublicKeyFromBase58("4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R")
+)
+
+func TestClassify(t *testing.T) {
+	watcher := New(nil, Config{Wallets: []solana.PublicKey{alice, bob}})
+
+	tx := &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{1},
+		Slot:      42,
+		Swaps: []*tx_parser.SwapInfo{
+			{Protocol: tx_parser.SwapTypeRaydium, Signers: []solana.PublicKey{alice}},
+		},
+		Transfers: []*tx_parser.TransferInfo{
+			// alice's swap leg is not reported as a transfer
+			{Type: tx_parser.TransferTypeToken, Mint: token, SourceOwner: carol, DestinationOwner: alice, Amount: 5},
+			{Type: tx_parser.TransferTypeToken, Mint: token, SourceOwner: carol, DestinationOwner: bob, Amount: 7},
+			{Type: tx_parser.TransferTypeSOL, Source: bob, Destination: carol, Amount: 9},
+		},
+		StakeEvents: []*tx_parser.StakeEvent{
+			{Type: tx_parser.StakeEventDelegate, Pool: tx_parser.StakePoolNative, Authority: bob, Recipient: bob},
+		},
+		PoolCreations: []*tx_parser.PoolCreatedEvent{
+			{Protocol: tx_parser.SwapTypePumpFun, Creator: carol, MintA: token},
+		},
+	}
+
+	notifications := watcher.Classify(tx)
+	if len(notifications) != 4 {
+		t.Fatalf("expected 4 notifications, got %d: %+v", len(notifications), notifications)
+	}
+
+	swap := notifications[0]
+	if swap.Kind != KindSwap || !swap.Wallet.Equals(alice) || swap.Swap != tx.Swaps[0] || swap.Slot != 42 || swap.Transaction != tx {
+		t.Errorf("unexpected swap notification %+v", swap)
+	}
+	stake := notifications[1]
+	if stake.Kind != KindStake || !stake.Wallet.Equals(bob) || stake.Stake != tx.StakeEvents[0] {
+		t.Errorf("expected bob's stake reported once, got %+v", stake)
+	}
+	in, out := notifications[2], notifications[3]
+	if in.Kind != KindTransfer || in.Direction != DirectionIn || in.Transfer.Amount != 7 {
+		t.Errorf("unexpected incoming transfer %+v", in)
+	}
+	if out.Direction != DirectionOut || !out.Wallet.Equals(bob) || out.Transfer.Amount != 9 {
+		t.Errorf("unexpected outgoing transfer %+v", out)
+	}
+}
+
+func TestClassifyPoolCreationAndKinds(t *testing.T) {
+	watcher := New(nil, Config{Wallets: []solana.PublicKey{carol}, Kinds: []Kind{KindLiquidity}})
+
+	tx := &tx_parser.ParsedTransaction{
+		PoolCreations: []*tx_parser.PoolCreatedEvent{{Protocol: tx_parser.SwapTypePumpFun, Creator: carol, MintA: token}},
+		Transfers:     []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeSOL, Source: alice, Destination: carol, Amount: 1}},
+	}
+	notifications := watcher.Classify(tx)
+	if len(notifications) != 1 || notifications[0].Kind != KindLiquidity || notifications[0].PoolCreation != tx.PoolCreations[0] {
+		t.Errorf("expected only the pool creation, got %+v", notifications)
+	}
+}
+
+func TestProcessDelivers(t *testing.T) {
+	watcher := New(nil, Config{Wallets: []solana.PublicKey{alice}})
+	watcher.Process(context.Background(), &tx_parser.ParsedTransaction{
+		Transfers: []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeSOL, Source: alice, Destination: bob, Amount: 1}},
+	})
+
+	select {
+	case notification := <-watcher.Notifications():
+		if notification.Direction != DirectionOut {
+			t.Errorf("unexpected notification %+v", notification)
+		}
+	default:
+		t.Fatal("expected a notification")
+	}
+}
+
+func TestProcessDedupesSignatures(t *testing.T) {
+	watcher := New(nil, Config{Wallets: []solana.PublicKey{alice, bob}})
+	tx := &tx_parser.ParsedTransaction{
+		Signature: solana.Signature{2},
+		Transfers: []*tx_parser.TransferInfo{{Type: tx_parser.TransferTypeSOL, Source: alice, Destination: bob, Amount: 1}},
+	}
+
+	// delivered by the subscriptions of both wallets
+	watcher.Process(context.Background(), tx)
+	watcher.Process(context.Background(), tx)
+
+	if count := len(watcher.Notifications()); count != 2 {
+		t.Fatalf("expected one notification per wallet, got %d", count)
+	}
+	out, in := <-watcher.Notifications(), <-watcher.Notifications()
+	if !out.Wallet.Equals(alice) || out.Direction != DirectionOut || !in.Wallet.Equals(bob) || in.Direction != DirectionIn {
+		t.Errorf("unexpected notifications %+v and %+v", out, in)
+	}
+}
+
+func TestRunRequiresWallets(t *testing.T) {
+	watcher := New(nil, Config{})
+	if err := watcher.Run(context.Background()); err == nil {
+		t.Error("expected an error without wallets")
+	}
+	if _, ok := <-watcher.Notifications(); ok {
+		t.Error("expected the notifications channel to be closed")
+	}
+}